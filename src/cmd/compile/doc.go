@@ -43,25 +43,79 @@ Flags:
 	-V
 		Print compiler version and exit.
 	-asmhdr file
-		Write assembly header to file.
+		Write assembly header to file. The header defines the
+		package's constants, the field offsets of its structs, and the
+		argument frame layout of functions declared without a body.
+	-bench file
+		Write the time spent in each phase of compiling each function,
+		longest first, to file. Useful in reports of slow compiles.
+	-c n
+		Run the SSA optimization passes on n goroutines, each
+		optimizing one function at a time. Only these passes run
+		in parallel: walking functions, building their SSA form
+		and generating their code remain serial, so -c does not
+		compile functions concurrently. The output does not depend
+		on n, but may differ from the output with -c=1 in the
+		names of compiler temporaries. The default is 1.
 	-complete
 		Assume package has no non-Go components.
+	-compressexport
+		Compress the export data in the object file. Importers detect
+		compressed export data automatically.
 	-cpuprofile file
 		Write a CPU profile for the compilation to file.
+	-dwarf level
+		Set how much of the package's functions is described in DWARF:
+		0 leaves them out, 1 emits the functions and their line tables,
+		and 2, the default, also describes their local variables and
+		the lexical blocks that declare them. Lower levels make the
+		compiler and linker faster and the binary smaller.
+	-dwarfdecls decls
+		Describe the package's types and integer constants in DWARF
+		even if they are unused: exported ones with decls=exported, or
+		all of them with decls=all. Cannot be used with -dwarf=0.
 	-dynlink
 		Allow references to Go symbols in shared libraries (experimental).
 	-e
 		Remove the limit on the number of errors reported (default limit is 10).
+	-embedsrc what
+		Record each source file in the object for debuggers, as a symbol
+		go.srcfile.name, where name is the file's name in the line table.
+		The symbol holds the SHA-256 hash of the file's contents and, with
+		what=content, the contents themselves, compressed with zlib.
+		The linker keeps these symbols unless run with -w.
+	-fncache dir
+		Cache the generated code of each function in dir, and reuse
+		it for functions whose source, flags, and dependencies have
+		not changed (experimental). Disabled by the back end's
+		debugging flags. Objects built from the cache may differ in
+		the names of compiler temporaries.
 	-h
 		Halt with a stack trace at the first error detected.
+	-importcfg file
+		Read import configuration from file. In the file, lines of the form
+		"packagefile path=file" give the file to load for the package with
+		import path path, and "importmap old=new" lines are like -importmap.
+		With -importcfg, imports are resolved using the file only, and the
+		compiler does not search any directories.
 	-importmap old=new
 		Interpret import "old" as import "new" during compilation.
 		The option may be repeated to add multiple mappings.
+		The mappings applied to the package's imports are recorded
+		in the object file as "importmap "old"="new"" lines after
+		the object header.
 	-installsuffix suffix
 		Look for packages in $GOROOT/pkg/$GOOS_$GOARCH_suffix
 		instead of $GOROOT/pkg/$GOOS_$GOARCH.
 	-largemodel
 		Generated code that assumes a large memory model.
+	-linkobj file
+		Write linker-specific object to file and compiler-specific
+		object to usual output file (as specified by -o).
+		Without this flag, the -o output is a combination of both
+		linker and compiler input. The compiler-specific object is
+		written before code generation, so importing packages can be
+		compiled while it runs.
 	-memprofile file
 		Write memory profile for the compilation to file.
 	-memprofilerate rate
@@ -72,6 +126,11 @@ Flags:
 		Disallow local (relative) imports.
 	-o file
 		Write object to file (default file.o or, with -pack, file.a).
+	-objindex
+		Write an index of the defined symbols to the object file, so that
+		the linker can skip duplicate definitions without decoding them.
+		The linker and tools such as go tool nm read objects written
+		with and without the index.
 	-p path
 		Set expected package import path for the code being compiled,
 		and diagnose imports that would cause a circular dependency.
@@ -121,6 +180,19 @@ not include a stack overflow check. This is most commonly used by low-level
 runtime sources invoked at times when it is unsafe for the calling goroutine to be
 preempted.
 
+	//go:norace
+	//go:nomsan
+	//go:noasan
+
+The //go:norace directive specifies that the next function declared in the file
+must not be instrumented when compiling with -race, -msan or -asan, so that accesses
+made by the function are invisible to the sanitizer in use. The //go:nomsan and
+//go:noasan directives exclude the function from -msan and -asan instrumentation
+only, and leave it instrumented with -race. A function excluded from the
+instrumentation in use is not inlined. These directives are for functions that are
+intentionally racy, such as sampling counters, or that run where calls into the
+sanitizer are unsafe.
+
 	//go:linkname localname importpath.name
 
 The //go:linkname directive instructs the compiler to use ``importpath.name'' as the
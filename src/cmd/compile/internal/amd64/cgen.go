@@ -6,26 +6,27 @@ package amd64
 
 import (
 	"cmd/compile/internal/gc"
+	"cmd/compile/internal/ir"
 	"cmd/internal/obj"
 	"cmd/internal/obj/x86"
 )
 
-func blockcopy(n, ns *gc.Node, osrc, odst, w int64) {
-	var noddi gc.Node
-	gc.Nodreg(&noddi, gc.Types[gc.Tptr], x86.REG_DI)
-	var nodsi gc.Node
-	gc.Nodreg(&nodsi, gc.Types[gc.Tptr], x86.REG_SI)
+func blockcopy(n, ns *ir.Node, osrc, odst, w int64) {
+	var noddi ir.Node
+	gc.Nodreg(&noddi, ir.Types[gc.Tptr], x86.REG_DI)
+	var nodsi ir.Node
+	gc.Nodreg(&nodsi, ir.Types[gc.Tptr], x86.REG_SI)
 
-	var nodl gc.Node
-	var nodr gc.Node
+	var nodl ir.Node
+	var nodr ir.Node
 	if n.Ullman >= ns.Ullman {
 		gc.Agenr(n, &nodr, &nodsi)
-		if ns.Op == gc.ONAME {
+		if ns.Op == ir.ONAME {
 			gc.Gvardef(ns)
 		}
 		gc.Agenr(ns, &nodl, &noddi)
 	} else {
-		if ns.Op == gc.ONAME {
+		if ns.Op == ir.ONAME {
 			gc.Gvardef(ns)
 		}
 		gc.Agenr(ns, &nodl, &noddi)
@@ -44,9 +45,9 @@ func blockcopy(n, ns *gc.Node, osrc, odst, w int64) {
 	c := w % 8 // bytes
 	q := w / 8 // quads
 
-	var oldcx gc.Node
-	var cx gc.Node
-	savex(x86.REG_CX, &cx, &oldcx, nil, gc.Types[gc.TINT64])
+	var oldcx ir.Node
+	var cx ir.Node
+	savex(x86.REG_CX, &cx, &oldcx, nil, ir.Types[ir.TINT64])
 
 	// if we are copying forward on the stack and
 	// the src and dst overlap, then reverse direction
@@ -80,14 +81,15 @@ func blockcopy(n, ns *gc.Node, osrc, odst, w int64) {
 		gins(x86.ACLD, nil, nil)
 	} else {
 		// normal direction
-		if q > 128 || (gc.Nacl && q >= 4) || (obj.Getgoos() == "plan9" && q >= 4) {
+		duff := gc.Thearch.Duffcopy
+		if q > duff.Max || (gc.Nacl && q >= duff.Min) || (obj.Getgoos() == "plan9" && q >= duff.Min) {
 			gconreg(movptr, q, x86.REG_CX)
 			gins(x86.AREP, nil, nil)   // repeat
 			gins(x86.AMOVSQ, nil, nil) // MOVQ *(SI)+,*(DI)+
-		} else if q >= 4 {
-			var oldx0 gc.Node
-			var x0 gc.Node
-			savex(x86.REG_X0, &x0, &oldx0, nil, gc.Types[gc.TFLOAT64])
+		} else if q >= duff.Min {
+			var oldx0 ir.Node
+			var x0 ir.Node
+			savex(x86.REG_X0, &x0, &oldx0, nil, ir.Types[ir.TFLOAT64])
 
 			p := gins(obj.ADUFFCOPY, nil, nil)
 			p.To.Type = obj.TYPE_ADDR
@@ -104,9 +106,9 @@ func blockcopy(n, ns *gc.Node, osrc, odst, w int64) {
 		} else if !gc.Nacl && c == 0 {
 			// We don't need the MOVSQ side-effect of updating SI and DI,
 			// and issuing a sequence of MOVQs directly is faster.
-			nodsi.Op = gc.OINDREG
+			nodsi.Op = ir.OINDREG
 
-			noddi.Op = gc.OINDREG
+			noddi.Op = ir.OINDREG
 			for q > 0 {
 				gmove(&nodsi, &cx) // MOVQ x+(SI),CX
 				gmove(&cx, &noddi) // MOVQ CX,x+(DI)
@@ -128,11 +130,11 @@ func blockcopy(n, ns *gc.Node, osrc, odst, w int64) {
 				c--
 			}
 		} else if w < 8 || c <= 4 {
-			nodsi.Op = gc.OINDREG
-			noddi.Op = gc.OINDREG
-			cx.Type = gc.Types[gc.TINT32]
-			nodsi.Type = gc.Types[gc.TINT32]
-			noddi.Type = gc.Types[gc.TINT32]
+			nodsi.Op = ir.OINDREG
+			noddi.Op = ir.OINDREG
+			cx.Type = ir.Types[ir.TINT32]
+			nodsi.Type = ir.Types[ir.TINT32]
+			noddi.Type = ir.Types[ir.TINT32]
 			if c > 4 {
 				nodsi.Xoffset = 0
 				noddi.Xoffset = 0
@@ -145,11 +147,11 @@ func blockcopy(n, ns *gc.Node, osrc, odst, w int64) {
 			gmove(&nodsi, &cx)
 			gmove(&cx, &noddi)
 		} else {
-			nodsi.Op = gc.OINDREG
-			noddi.Op = gc.OINDREG
-			cx.Type = gc.Types[gc.TINT64]
-			nodsi.Type = gc.Types[gc.TINT64]
-			noddi.Type = gc.Types[gc.TINT64]
+			nodsi.Op = ir.OINDREG
+			noddi.Op = ir.OINDREG
+			cx.Type = ir.Types[ir.TINT64]
+			nodsi.Type = ir.Types[ir.TINT64]
+			noddi.Type = ir.Types[ir.TINT64]
 			nodsi.Xoffset = c - 8
 			noddi.Xoffset = c - 8
 			gmove(&nodsi, &cx)
@@ -17,7 +17,15 @@ var (
 	cmpptr = x86.ACMPQ
 )
 
-func betypeinit() {
+func init() {
+	gc.RegisterBackend("amd64", backend{})
+	gc.RegisterBackend("amd64p32", backend{})
+}
+
+// backend is the amd64 back end; see gc.Backend.
+type backend struct{}
+
+func (backend) TypeInit() {
 	gc.Widthptr = 8
 	gc.Widthint = 8
 	gc.Widthreg = 8
@@ -35,72 +43,74 @@ func betypeinit() {
 	}
 }
 
-func Main() {
+func (backend) Init(arch *gc.Arch) {
 	if obj.Getgoos() == "nacl" {
 		resvd = append(resvd, x86.REG_BP, x86.REG_R15)
 	} else if obj.Framepointer_enabled != 0 {
 		resvd = append(resvd, x86.REG_BP)
 	}
 
-	gc.Thearch.Thechar = '6'
-	gc.Thearch.Thestring = "amd64"
-	gc.Thearch.Thelinkarch = &x86.Linkamd64
+	arch.Thechar = '6'
+	arch.Thestring = "amd64"
+	arch.Thelinkarch = &x86.Linkamd64
 	if obj.Getgoarch() == "amd64p32" {
-		gc.Thearch.Thestring = "amd64p32"
-		gc.Thearch.Thelinkarch = &x86.Linkamd64p32
+		arch.Thestring = "amd64p32"
+		arch.Thelinkarch = &x86.Linkamd64p32
 	}
-	gc.Thearch.REGSP = x86.REGSP
-	gc.Thearch.REGCTXT = x86.REGCTXT
-	gc.Thearch.REGCALLX = x86.REG_BX
-	gc.Thearch.REGCALLX2 = x86.REG_AX
-	gc.Thearch.REGRETURN = x86.REG_AX
-	gc.Thearch.REGMIN = x86.REG_AX
-	gc.Thearch.REGMAX = x86.REG_R15
-	gc.Thearch.FREGMIN = x86.REG_X0
-	gc.Thearch.FREGMAX = x86.REG_X15
-	gc.Thearch.MAXWIDTH = 1 << 50
-	gc.Thearch.ReservedRegs = resvd
+	arch.REGSP = x86.REGSP
+	arch.REGCTXT = x86.REGCTXT
+	arch.REGCALLX = x86.REG_BX
+	arch.REGCALLX2 = x86.REG_AX
+	arch.REGRETURN = x86.REG_AX
+	arch.REGMIN = x86.REG_AX
+	arch.REGMAX = x86.REG_R15
+	arch.FREGMIN = x86.REG_X0
+	arch.FREGMAX = x86.REG_X15
+	arch.MAXWIDTH = 1 << 50
+	arch.ReservedRegs = resvd
+	arch.Duffzero = gc.DuffRange{Min: 8, Max: 128}
+	arch.Duffcopy = gc.DuffRange{Min: 16, Max: 128}
 
-	gc.Thearch.AddIndex = addindex
-	gc.Thearch.Betypeinit = betypeinit
-	gc.Thearch.Cgen_bmul = cgen_bmul
-	gc.Thearch.Cgen_hmul = cgen_hmul
-	gc.Thearch.Cgen_shift = cgen_shift
-	gc.Thearch.Clearfat = clearfat
-	gc.Thearch.Defframe = defframe
-	gc.Thearch.Dodiv = dodiv
-	gc.Thearch.Excise = excise
-	gc.Thearch.Expandchecks = expandchecks
-	gc.Thearch.Getg = getg
-	gc.Thearch.Gins = gins
-	gc.Thearch.Ginsboolval = ginsboolval
-	gc.Thearch.Ginscmp = ginscmp
-	gc.Thearch.Ginscon = ginscon
-	gc.Thearch.Ginsnop = ginsnop
-	gc.Thearch.Gmove = gmove
-	gc.Thearch.Peep = peep
-	gc.Thearch.Proginfo = proginfo
-	gc.Thearch.Regtyp = regtyp
-	gc.Thearch.Sameaddr = sameaddr
-	gc.Thearch.Smallindir = smallindir
-	gc.Thearch.Stackaddr = stackaddr
-	gc.Thearch.Blockcopy = blockcopy
-	gc.Thearch.Sudoaddable = sudoaddable
-	gc.Thearch.Sudoclean = sudoclean
-	gc.Thearch.Excludedregs = excludedregs
-	gc.Thearch.RtoB = RtoB
-	gc.Thearch.FtoB = FtoB
-	gc.Thearch.BtoR = BtoR
-	gc.Thearch.BtoF = BtoF
-	gc.Thearch.Optoas = optoas
-	gc.Thearch.Doregbits = doregbits
-	gc.Thearch.Regnames = regnames
+	arch.AddIndex = addindex
+	arch.Cgen_bmul = cgen_bmul
+	arch.Cgen_hmul = cgen_hmul
+	arch.Cgen_shift = cgen_shift
+	arch.Copyas = copyas
+	arch.Copyu = copyu
+	arch.Clearfat = clearfat
+	arch.Dodiv = dodiv
+	arch.Expandchecks = expandchecks
+	arch.Getg = getg
+	arch.Gins = gins
+	arch.Ginsboolval = ginsboolval
+	arch.Ginscmp = ginscmp
+	arch.Ginscon = ginscon
+	arch.Ginsnop = ginsnop
+	arch.Gmove = gmove
+	arch.Proginfo = proginfo
+	arch.Regtyp = regtyp
+	arch.Sameaddr = sameaddr
+	arch.Smallindir = smallindir
+	arch.Stackaddr = stackaddr
+	arch.Blockcopy = blockcopy
+	arch.Sudoaddable = sudoaddable
+	arch.Sudoclean = sudoclean
+	arch.Excludedregs = excludedregs
+	arch.RtoB = RtoB
+	arch.FtoB = FtoB
+	arch.BtoR = BtoR
+	arch.BtoF = BtoF
+	arch.Optoas = optoas
+	arch.Doregbits = doregbits
+	arch.Regnames = regnames
+	arch.ZeroRange = zerorange
 
-	gc.Thearch.SSARegToReg = ssaRegToReg
-	gc.Thearch.SSAMarkMoves = ssaMarkMoves
-	gc.Thearch.SSAGenValue = ssaGenValue
-	gc.Thearch.SSAGenBlock = ssaGenBlock
+	arch.SSARegToReg = ssaRegToReg
+	arch.SSAMarkMoves = ssaMarkMoves
+	arch.SSAGenValue = ssaGenValue
+	arch.SSAGenBlock = ssaGenBlock
+}
 
-	gc.Main()
-	gc.Exit(0)
+func (backend) Peep(firstp *obj.Prog) {
+	peep(firstp)
 }
@@ -6,6 +6,7 @@ package amd64
 
 import (
 	"cmd/compile/internal/gc"
+	"cmd/compile/internal/ir"
 	"cmd/internal/obj"
 	"cmd/internal/obj/x86"
 )
@@ -13,56 +14,6 @@ import (
 // no floating point in note handlers on Plan 9
 var isPlan9 = obj.Getgoos() == "plan9"
 
-func defframe(ptxt *obj.Prog) {
-	// fill in argument size, stack size
-	ptxt.To.Type = obj.TYPE_TEXTSIZE
-
-	ptxt.To.Val = int32(gc.Rnd(gc.Curfn.Type.Argwid, int64(gc.Widthptr)))
-	frame := uint32(gc.Rnd(gc.Stksize+gc.Maxarg, int64(gc.Widthreg)))
-	ptxt.To.Offset = int64(frame)
-
-	// insert code to zero ambiguously live variables
-	// so that the garbage collector only sees initialized values
-	// when it looks for pointers.
-	p := ptxt
-
-	hi := int64(0)
-	lo := hi
-	ax := uint32(0)
-	x0 := uint32(0)
-
-	// iterate through declarations - they are sorted in decreasing xoffset order.
-	for _, n := range gc.Curfn.Func.Dcl {
-		if !n.Name.Needzero {
-			continue
-		}
-		if n.Class != gc.PAUTO {
-			gc.Fatalf("needzero class %d", n.Class)
-		}
-		if n.Type.Width%int64(gc.Widthptr) != 0 || n.Xoffset%int64(gc.Widthptr) != 0 || n.Type.Width == 0 {
-			gc.Fatalf("var %v has size %d offset %d", gc.Nconv(n, gc.FmtLong), int(n.Type.Width), int(n.Xoffset))
-		}
-
-		if lo != hi && n.Xoffset+n.Type.Width >= lo-int64(2*gc.Widthreg) {
-			// merge with range we already have
-			lo = n.Xoffset
-
-			continue
-		}
-
-		// zero old range
-		p = zerorange(p, int64(frame), lo, hi, &ax, &x0)
-
-		// set new range
-		hi = n.Xoffset + n.Type.Width
-
-		lo = n.Xoffset
-	}
-
-	// zero final range
-	zerorange(p, int64(frame), lo, hi, &ax, &x0)
-}
-
 // DUFFZERO consists of repeated blocks of 4 MOVUPSs + ADD,
 // See runtime/mkduff.go.
 const (
@@ -100,7 +51,13 @@ func dzDI(b int64) int64 {
 	return -dzClearStep * (dzBlockLen - tailSteps)
 }
 
-func zerorange(p *obj.Prog, frame int64, lo int64, hi int64, ax *uint32, x0 *uint32) *obj.Prog {
+// Bits in the zerorange state word.
+const (
+	zeroAX = 1 << iota // AX holds zero
+	zeroX0             // X0 holds zero
+)
+
+func zerorange(p *obj.Prog, frame int64, lo int64, hi int64, state *uint32) *obj.Prog {
 	cnt := hi - lo
 	if cnt == 0 {
 		return p
@@ -111,78 +68,62 @@ func zerorange(p *obj.Prog, frame int64, lo int64, hi int64, ax *uint32, x0 *uin
 		if cnt%int64(gc.Widthptr) != 0 {
 			gc.Fatalf("zerorange count not a multiple of widthptr %d", cnt)
 		}
-		if *ax == 0 {
-			p = appendpp(p, x86.AMOVQ, obj.TYPE_CONST, 0, 0, obj.TYPE_REG, x86.REG_AX, 0)
-			*ax = 1
+		if *state&zeroAX == 0 {
+			p = gc.Appendpp(p, x86.AMOVQ, obj.TYPE_CONST, 0, 0, obj.TYPE_REG, x86.REG_AX, 0)
+			*state |= zeroAX
 		}
-		p = appendpp(p, x86.AMOVL, obj.TYPE_REG, x86.REG_AX, 0, obj.TYPE_MEM, x86.REG_SP, frame+lo)
+		p = gc.Appendpp(p, x86.AMOVL, obj.TYPE_REG, x86.REG_AX, 0, obj.TYPE_MEM, x86.REG_SP, frame+lo)
 		lo += int64(gc.Widthptr)
 		cnt -= int64(gc.Widthptr)
 	}
 
 	if cnt == 8 {
-		if *ax == 0 {
-			p = appendpp(p, x86.AMOVQ, obj.TYPE_CONST, 0, 0, obj.TYPE_REG, x86.REG_AX, 0)
-			*ax = 1
+		if *state&zeroAX == 0 {
+			p = gc.Appendpp(p, x86.AMOVQ, obj.TYPE_CONST, 0, 0, obj.TYPE_REG, x86.REG_AX, 0)
+			*state |= zeroAX
 		}
-		p = appendpp(p, x86.AMOVQ, obj.TYPE_REG, x86.REG_AX, 0, obj.TYPE_MEM, x86.REG_SP, frame+lo)
+		p = gc.Appendpp(p, x86.AMOVQ, obj.TYPE_REG, x86.REG_AX, 0, obj.TYPE_MEM, x86.REG_SP, frame+lo)
 	} else if !isPlan9 && cnt <= int64(8*gc.Widthreg) {
-		if *x0 == 0 {
-			p = appendpp(p, x86.AXORPS, obj.TYPE_REG, x86.REG_X0, 0, obj.TYPE_REG, x86.REG_X0, 0)
-			*x0 = 1
+		if *state&zeroX0 == 0 {
+			p = gc.Appendpp(p, x86.AXORPS, obj.TYPE_REG, x86.REG_X0, 0, obj.TYPE_REG, x86.REG_X0, 0)
+			*state |= zeroX0
 		}
 
 		for i := int64(0); i < cnt/16; i++ {
-			p = appendpp(p, x86.AMOVUPS, obj.TYPE_REG, x86.REG_X0, 0, obj.TYPE_MEM, x86.REG_SP, frame+lo+i*16)
+			p = gc.Appendpp(p, x86.AMOVUPS, obj.TYPE_REG, x86.REG_X0, 0, obj.TYPE_MEM, x86.REG_SP, frame+lo+i*16)
 		}
 
 		if cnt%16 != 0 {
-			p = appendpp(p, x86.AMOVUPS, obj.TYPE_REG, x86.REG_X0, 0, obj.TYPE_MEM, x86.REG_SP, frame+lo+cnt-int64(16))
+			p = gc.Appendpp(p, x86.AMOVUPS, obj.TYPE_REG, x86.REG_X0, 0, obj.TYPE_MEM, x86.REG_SP, frame+lo+cnt-int64(16))
 		}
 	} else if !gc.Nacl && !isPlan9 && (cnt <= int64(128*gc.Widthreg)) {
-		if *x0 == 0 {
-			p = appendpp(p, x86.AXORPS, obj.TYPE_REG, x86.REG_X0, 0, obj.TYPE_REG, x86.REG_X0, 0)
-			*x0 = 1
+		if *state&zeroX0 == 0 {
+			p = gc.Appendpp(p, x86.AXORPS, obj.TYPE_REG, x86.REG_X0, 0, obj.TYPE_REG, x86.REG_X0, 0)
+			*state |= zeroX0
 		}
-		p = appendpp(p, leaptr, obj.TYPE_MEM, x86.REG_SP, frame+lo+dzDI(cnt), obj.TYPE_REG, x86.REG_DI, 0)
-		p = appendpp(p, obj.ADUFFZERO, obj.TYPE_NONE, 0, 0, obj.TYPE_ADDR, 0, dzOff(cnt))
+		p = gc.Appendpp(p, leaptr, obj.TYPE_MEM, x86.REG_SP, frame+lo+dzDI(cnt), obj.TYPE_REG, x86.REG_DI, 0)
+		p = gc.Appendpp(p, obj.ADUFFZERO, obj.TYPE_NONE, 0, 0, obj.TYPE_ADDR, 0, dzOff(cnt))
 		p.To.Sym = gc.Linksym(gc.Pkglookup("duffzero", gc.Runtimepkg))
 
 		if cnt%16 != 0 {
-			p = appendpp(p, x86.AMOVUPS, obj.TYPE_REG, x86.REG_X0, 0, obj.TYPE_MEM, x86.REG_DI, -int64(8))
+			p = gc.Appendpp(p, x86.AMOVUPS, obj.TYPE_REG, x86.REG_X0, 0, obj.TYPE_MEM, x86.REG_DI, -int64(8))
 		}
 	} else {
-		if *ax == 0 {
-			p = appendpp(p, x86.AMOVQ, obj.TYPE_CONST, 0, 0, obj.TYPE_REG, x86.REG_AX, 0)
-			*ax = 1
+		if *state&zeroAX == 0 {
+			p = gc.Appendpp(p, x86.AMOVQ, obj.TYPE_CONST, 0, 0, obj.TYPE_REG, x86.REG_AX, 0)
+			*state |= zeroAX
 		}
 
-		p = appendpp(p, x86.AMOVQ, obj.TYPE_CONST, 0, cnt/int64(gc.Widthreg), obj.TYPE_REG, x86.REG_CX, 0)
-		p = appendpp(p, leaptr, obj.TYPE_MEM, x86.REG_SP, frame+lo, obj.TYPE_REG, x86.REG_DI, 0)
-		p = appendpp(p, x86.AREP, obj.TYPE_NONE, 0, 0, obj.TYPE_NONE, 0, 0)
-		p = appendpp(p, x86.ASTOSQ, obj.TYPE_NONE, 0, 0, obj.TYPE_NONE, 0, 0)
+		p = gc.Appendpp(p, x86.AMOVQ, obj.TYPE_CONST, 0, cnt/int64(gc.Widthreg), obj.TYPE_REG, x86.REG_CX, 0)
+		p = gc.Appendpp(p, leaptr, obj.TYPE_MEM, x86.REG_SP, frame+lo, obj.TYPE_REG, x86.REG_DI, 0)
+		p = gc.Appendpp(p, x86.AREP, obj.TYPE_NONE, 0, 0, obj.TYPE_NONE, 0, 0)
+		p = gc.Appendpp(p, x86.ASTOSQ, obj.TYPE_NONE, 0, 0, obj.TYPE_NONE, 0, 0)
 	}
 
 	return p
 }
 
-func appendpp(p *obj.Prog, as obj.As, ftype obj.AddrType, freg int, foffset int64, ttype obj.AddrType, treg int, toffset int64) *obj.Prog {
-	q := gc.Ctxt.NewProg()
-	gc.Clearp(q)
-	q.As = as
-	q.Lineno = p.Lineno
-	q.From.Type = ftype
-	q.From.Reg = int16(freg)
-	q.From.Offset = foffset
-	q.To.Type = ttype
-	q.To.Reg = int16(treg)
-	q.To.Offset = toffset
-	q.Link = p.Link
-	p.Link = q
-	return q
-}
-
-var panicdiv *gc.Node
+var panicdiv *ir.Node
 
 /*
  * generate division.
@@ -191,7 +132,7 @@ var panicdiv *gc.Node
  *	res = nl % nr
  * according to op.
  */
-func dodiv(op gc.Op, nl *gc.Node, nr *gc.Node, res *gc.Node) {
+func dodiv(op ir.Op, nl *ir.Node, nr *ir.Node, res *ir.Node) {
 	// Have to be careful about handling
 	// most negative int divided by -1 correctly.
 	// The hardware will trap.
@@ -206,28 +147,28 @@ func dodiv(op gc.Op, nl *gc.Node, nr *gc.Node, res *gc.Node) {
 	check := false
 	if gc.Issigned[t.Etype] {
 		check = true
-		if gc.Isconst(nl, gc.CTINT) && nl.Int() != -(1<<uint64(t.Width*8-1)) {
+		if ir.Isconst(nl, ir.CTINT) && nl.Int() != -(1<<uint64(t.Width*8-1)) {
 			check = false
-		} else if gc.Isconst(nr, gc.CTINT) && nr.Int() != -1 {
+		} else if ir.Isconst(nr, ir.CTINT) && nr.Int() != -1 {
 			check = false
 		}
 	}
 
 	if t.Width < 4 {
 		if gc.Issigned[t.Etype] {
-			t = gc.Types[gc.TINT32]
+			t = ir.Types[ir.TINT32]
 		} else {
-			t = gc.Types[gc.TUINT32]
+			t = ir.Types[ir.TUINT32]
 		}
 		check = false
 	}
 
 	a := optoas(op, t)
 
-	var n3 gc.Node
+	var n3 ir.Node
 	gc.Regalloc(&n3, t0, nil)
-	var ax gc.Node
-	var oldax gc.Node
+	var ax ir.Node
+	var oldax ir.Node
 	if nl.Ullman >= nr.Ullman {
 		savex(x86.REG_AX, &ax, &oldax, res, t0)
 		gc.Cgen(nl, &ax)
@@ -251,15 +192,15 @@ func dodiv(op gc.Op, nl *gc.Node, nr *gc.Node, res *gc.Node) {
 		gmove(&n31, &n3)
 	}
 
-	var n4 gc.Node
+	var n4 ir.Node
 	if gc.Nacl {
 		// Native Client does not relay the divide-by-zero trap
 		// to the executing program, so we must insert a check
 		// for ourselves.
 		gc.Nodconst(&n4, t, 0)
 
-		gins(optoas(gc.OCMP, t), &n3, &n4)
-		p1 := gc.Gbranch(optoas(gc.ONE, t), nil, +1)
+		gins(optoas(ir.OCMP, t), &n3, &n4)
+		p1 := gc.Gbranch(optoas(ir.ONE, t), nil, +1)
 		if panicdiv == nil {
 			panicdiv = gc.Sysfunc("panicdivide")
 		}
@@ -270,11 +211,11 @@ func dodiv(op gc.Op, nl *gc.Node, nr *gc.Node, res *gc.Node) {
 	var p2 *obj.Prog
 	if check {
 		gc.Nodconst(&n4, t, -1)
-		gins(optoas(gc.OCMP, t), &n3, &n4)
-		p1 := gc.Gbranch(optoas(gc.ONE, t), nil, +1)
-		if op == gc.ODIV {
+		gins(optoas(ir.OCMP, t), &n3, &n4)
+		p1 := gc.Gbranch(optoas(ir.ONE, t), nil, +1)
+		if op == ir.ODIV {
 			// a / (-1) is -a.
-			gins(optoas(gc.OMINUS, t), nil, &ax)
+			gins(optoas(ir.OMINUS, t), nil, &ax)
 
 			gmove(&ax, res)
 		} else {
@@ -288,18 +229,18 @@ func dodiv(op gc.Op, nl *gc.Node, nr *gc.Node, res *gc.Node) {
 		gc.Patch(p1, gc.Pc)
 	}
 
-	var olddx gc.Node
-	var dx gc.Node
+	var olddx ir.Node
+	var dx ir.Node
 	savex(x86.REG_DX, &dx, &olddx, res, t)
 	if !gc.Issigned[t.Etype] {
 		gc.Nodconst(&n4, t, 0)
 		gmove(&n4, &dx)
 	} else {
-		gins(optoas(gc.OEXTEND, t), nil, nil)
+		gins(optoas(ir.OEXTEND, t), nil, nil)
 	}
 	gins(a, &n3, nil)
 	gc.Regfree(&n3)
-	if op == gc.ODIV {
+	if op == ir.ODIV {
 		gmove(&ax, res)
 	} else {
 		gmove(&dx, res)
@@ -321,28 +262,28 @@ func dodiv(op gc.Op, nl *gc.Node, nr *gc.Node, res *gc.Node) {
  * the move is not necessary if dr == res, because res is
  * known to be dead.
  */
-func savex(dr int, x *gc.Node, oldx *gc.Node, res *gc.Node, t *gc.Type) {
+func savex(dr int, x *ir.Node, oldx *ir.Node, res *ir.Node, t *ir.Type) {
 	r := uint8(gc.GetReg(dr))
 
 	// save current ax and dx if they are live
 	// and not the destination
-	*oldx = gc.Node{}
+	*oldx = ir.Node{}
 
 	gc.Nodreg(x, t, dr)
 	if r > 1 && !gc.Samereg(x, res) {
-		gc.Regalloc(oldx, gc.Types[gc.TINT64], nil)
-		x.Type = gc.Types[gc.TINT64]
+		gc.Regalloc(oldx, ir.Types[ir.TINT64], nil)
+		x.Type = ir.Types[ir.TINT64]
 		gmove(x, oldx)
 		x.Type = t
 		// TODO(marvin): Fix Node.EType type union.
-		oldx.Etype = gc.EType(r) // squirrel away old r value
+		oldx.Etype = ir.EType(r) // squirrel away old r value
 		gc.SetReg(dr, 1)
 	}
 }
 
-func restx(x *gc.Node, oldx *gc.Node) {
+func restx(x *ir.Node, oldx *ir.Node) {
 	if oldx.Op != 0 {
-		x.Type = gc.Types[gc.TINT64]
+		x.Type = ir.Types[ir.TINT64]
 		gc.SetReg(int(x.Reg), int(oldx.Etype))
 		gmove(oldx, x)
 		gc.Regfree(oldx)
@@ -353,20 +294,20 @@ func restx(x *gc.Node, oldx *gc.Node) {
  * generate high multiply:
  *   res = (nl*nr) >> width
  */
-func cgen_hmul(nl *gc.Node, nr *gc.Node, res *gc.Node) {
+func cgen_hmul(nl *ir.Node, nr *ir.Node, res *ir.Node) {
 	t := nl.Type
-	a := optoas(gc.OHMUL, t)
+	a := optoas(ir.OHMUL, t)
 	if nl.Ullman < nr.Ullman {
 		nl, nr = nr, nl
 	}
 
-	var n1 gc.Node
+	var n1 ir.Node
 	gc.Cgenr(nl, &n1, res)
-	var n2 gc.Node
+	var n2 ir.Node
 	gc.Cgenr(nr, &n2, nil)
-	var ax, oldax, dx, olddx gc.Node
-	savex(x86.REG_AX, &ax, &oldax, res, gc.Types[gc.TUINT64])
-	savex(x86.REG_DX, &dx, &olddx, res, gc.Types[gc.TUINT64])
+	var ax, oldax, dx, olddx ir.Node
+	savex(x86.REG_AX, &ax, &oldax, res, ir.Types[ir.TUINT64])
+	savex(x86.REG_DX, &dx, &olddx, res, ir.Types[ir.TUINT64])
 	gmove(&n1, &ax)
 	gins(a, &n2, nil)
 	gc.Regfree(&n2)
@@ -374,7 +315,7 @@ func cgen_hmul(nl *gc.Node, nr *gc.Node, res *gc.Node) {
 
 	if t.Width == 1 {
 		// byte multiply behaves differently.
-		var byteAH, byteDX gc.Node
+		var byteAH, byteDX ir.Node
 		gc.Nodreg(&byteAH, t, x86.REG_AH)
 		gc.Nodreg(&byteDX, t, x86.REG_DX)
 		gmove(&byteAH, &byteDX)
@@ -390,18 +331,18 @@ func cgen_hmul(nl *gc.Node, nr *gc.Node, res *gc.Node) {
  *	res = nl << nr
  *	res = nl >> nr
  */
-func cgen_shift(op gc.Op, bounded bool, nl *gc.Node, nr *gc.Node, res *gc.Node) {
+func cgen_shift(op ir.Op, bounded bool, nl *ir.Node, nr *ir.Node, res *ir.Node) {
 	a := optoas(op, nl.Type)
 
-	if nr.Op == gc.OLITERAL {
-		var n1 gc.Node
+	if nr.Op == ir.OLITERAL {
+		var n1 ir.Node
 		gc.Regalloc(&n1, nl.Type, res)
 		gc.Cgen(nl, &n1)
 		sc := uint64(nr.Int())
 		if sc >= uint64(nl.Type.Width*8) {
 			// large shift gets 2 shifts by width-1
-			var n3 gc.Node
-			gc.Nodconst(&n3, gc.Types[gc.TUINT32], nl.Type.Width*8-1)
+			var n3 ir.Node
+			gc.Nodconst(&n3, ir.Types[ir.TUINT32], nl.Type.Width*8-1)
 
 			gins(a, &n3, &n1)
 			gins(a, &n3, &n1)
@@ -414,48 +355,48 @@ func cgen_shift(op gc.Op, bounded bool, nl *gc.Node, nr *gc.Node, res *gc.Node)
 	}
 
 	if nl.Ullman >= gc.UINF {
-		var n4 gc.Node
+		var n4 ir.Node
 		gc.Tempname(&n4, nl.Type)
 		gc.Cgen(nl, &n4)
 		nl = &n4
 	}
 
 	if nr.Ullman >= gc.UINF {
-		var n5 gc.Node
+		var n5 ir.Node
 		gc.Tempname(&n5, nr.Type)
 		gc.Cgen(nr, &n5)
 		nr = &n5
 	}
 
 	rcx := gc.GetReg(x86.REG_CX)
-	var n1 gc.Node
-	gc.Nodreg(&n1, gc.Types[gc.TUINT32], x86.REG_CX)
+	var n1 ir.Node
+	gc.Nodreg(&n1, ir.Types[ir.TUINT32], x86.REG_CX)
 
 	// Allow either uint32 or uint64 as shift type,
 	// to avoid unnecessary conversion from uint32 to uint64
 	// just to do the comparison.
-	tcount := gc.Types[gc.Simtype[nr.Type.Etype]]
+	tcount := ir.Types[gc.Simtype[nr.Type.Etype]]
 
-	if tcount.Etype < gc.TUINT32 {
-		tcount = gc.Types[gc.TUINT32]
+	if tcount.Etype < ir.TUINT32 {
+		tcount = ir.Types[ir.TUINT32]
 	}
 
 	gc.Regalloc(&n1, nr.Type, &n1) // to hold the shift type in CX
-	var n3 gc.Node
+	var n3 ir.Node
 	gc.Regalloc(&n3, tcount, &n1) // to clear high bits of CX
 
-	var cx gc.Node
-	gc.Nodreg(&cx, gc.Types[gc.TUINT64], x86.REG_CX)
+	var cx ir.Node
+	gc.Nodreg(&cx, ir.Types[ir.TUINT64], x86.REG_CX)
 
-	var oldcx gc.Node
+	var oldcx ir.Node
 	if rcx > 0 && !gc.Samereg(&cx, res) {
-		gc.Regalloc(&oldcx, gc.Types[gc.TUINT64], nil)
+		gc.Regalloc(&oldcx, ir.Types[ir.TUINT64], nil)
 		gmove(&cx, &oldcx)
 	}
 
 	cx.Type = tcount
 
-	var n2 gc.Node
+	var n2 ir.Node
 	if gc.Samereg(&cx, res) {
 		gc.Regalloc(&n2, nl.Type, nil)
 	} else {
@@ -476,10 +417,10 @@ func cgen_shift(op gc.Op, bounded bool, nl *gc.Node, nr *gc.Node, res *gc.Node)
 	// test and fix up large shifts
 	if !bounded {
 		gc.Nodconst(&n3, tcount, nl.Type.Width*8)
-		gins(optoas(gc.OCMP, tcount), &n1, &n3)
-		p1 := gc.Gbranch(optoas(gc.OLT, tcount), nil, +1)
-		if op == gc.ORSH && gc.Issigned[nl.Type.Etype] {
-			gc.Nodconst(&n3, gc.Types[gc.TUINT32], nl.Type.Width*8-1)
+		gins(optoas(ir.OCMP, tcount), &n1, &n3)
+		p1 := gc.Gbranch(optoas(ir.OLT, tcount), nil, +1)
+		if op == ir.ORSH && gc.Issigned[nl.Type.Etype] {
+			gc.Nodconst(&n3, ir.Types[ir.TUINT32], nl.Type.Width*8-1)
 			gins(a, &n3, &n2)
 		} else {
 			gc.Nodconst(&n3, nl.Type, 0)
@@ -492,7 +433,7 @@ func cgen_shift(op gc.Op, bounded bool, nl *gc.Node, nr *gc.Node, res *gc.Node)
 	gins(a, &n1, &n2)
 
 	if oldcx.Op != 0 {
-		cx.Type = gc.Types[gc.TUINT64]
+		cx.Type = ir.Types[ir.TUINT64]
 		gmove(&oldcx, &cx)
 		gc.Regfree(&oldcx)
 	}
@@ -509,7 +450,7 @@ func cgen_shift(op gc.Op, bounded bool, nl *gc.Node, nr *gc.Node, res *gc.Node)
  * there is no 2-operand byte multiply instruction so
  * we do a full-width multiplication and truncate afterwards.
  */
-func cgen_bmul(op gc.Op, nl *gc.Node, nr *gc.Node, res *gc.Node) bool {
+func cgen_bmul(op ir.Op, nl *ir.Node, nr *ir.Node, res *ir.Node) bool {
 	if optoas(op, nl.Type) != x86.AIMULB {
 		return false
 	}
@@ -520,23 +461,23 @@ func cgen_bmul(op gc.Op, nl *gc.Node, nr *gc.Node, res *gc.Node) bool {
 	}
 
 	// generate operands in "8-bit" registers.
-	var n1b gc.Node
+	var n1b ir.Node
 	gc.Regalloc(&n1b, nl.Type, res)
 
 	gc.Cgen(nl, &n1b)
-	var n2b gc.Node
+	var n2b ir.Node
 	gc.Regalloc(&n2b, nr.Type, nil)
 	gc.Cgen(nr, &n2b)
 
 	// perform full-width multiplication.
-	t := gc.Types[gc.TUINT64]
+	t := ir.Types[ir.TUINT64]
 
 	if gc.Issigned[nl.Type.Etype] {
-		t = gc.Types[gc.TINT64]
+		t = ir.Types[ir.TINT64]
 	}
-	var n1 gc.Node
+	var n1 ir.Node
 	gc.Nodreg(&n1, t, int(n1b.Reg))
-	var n2 gc.Node
+	var n2 ir.Node
 	gc.Nodreg(&n2, t, int(n2b.Reg))
 	a := optoas(op, t)
 	gins(a, &n2, &n1)
@@ -549,7 +490,7 @@ func cgen_bmul(op gc.Op, nl *gc.Node, nr *gc.Node, res *gc.Node) bool {
 	return true
 }
 
-func clearfat(nl *gc.Node) {
+func clearfat(nl *ir.Node) {
 	/* clear a fat object */
 	if gc.Debug['g'] != 0 {
 		gc.Dump("\nclearfat", nl)
@@ -561,16 +502,18 @@ func clearfat(nl *gc.Node) {
 	}
 
 	w := nl.Type.Width
+	q := w / 8 // quads
+	duff := gc.Thearch.Duffzero
 
-	if w > 1024 || (w >= 64 && (gc.Nacl || isPlan9)) {
-		var oldn1 gc.Node
-		var n1 gc.Node
-		savex(x86.REG_DI, &n1, &oldn1, nil, gc.Types[gc.Tptr])
+	if q > duff.Max || (q >= duff.Min && (gc.Nacl || isPlan9)) {
+		var oldn1 ir.Node
+		var n1 ir.Node
+		savex(x86.REG_DI, &n1, &oldn1, nil, ir.Types[gc.Tptr])
 		gc.Agen(nl, &n1)
 
-		var ax gc.Node
-		var oldax gc.Node
-		savex(x86.REG_AX, &ax, &oldax, nil, gc.Types[gc.Tptr])
+		var ax ir.Node
+		var oldax ir.Node
+		savex(x86.REG_AX, &ax, &oldax, nil, ir.Types[gc.Tptr])
 		gconreg(x86.AMOVL, 0, x86.REG_AX)
 		gconreg(movptr, w/8, x86.REG_CX)
 
@@ -578,7 +521,7 @@ func clearfat(nl *gc.Node) {
 		gins(x86.ASTOSQ, nil, nil) // STOQ AL,*(DI)+
 
 		if w%8 != 0 {
-			n1.Op = gc.OINDREG
+			n1.Op = ir.OINDREG
 			clearfat_tail(&n1, w%8)
 		}
 
@@ -587,15 +530,16 @@ func clearfat(nl *gc.Node) {
 		return
 	}
 
-	if w >= 64 {
-		var oldn1 gc.Node
-		var n1 gc.Node
-		savex(x86.REG_DI, &n1, &oldn1, nil, gc.Types[gc.Tptr])
+	// DUFFZERO clears 16 bytes at a time.
+	if q >= duff.Min && w >= dzClearStep {
+		var oldn1 ir.Node
+		var n1 ir.Node
+		savex(x86.REG_DI, &n1, &oldn1, nil, ir.Types[gc.Tptr])
 		gc.Agen(nl, &n1)
 
-		var vec_zero gc.Node
-		var old_x0 gc.Node
-		savex(x86.REG_X0, &vec_zero, &old_x0, nil, gc.Types[gc.TFLOAT64])
+		var vec_zero ir.Node
+		var old_x0 ir.Node
+		savex(x86.REG_X0, &vec_zero, &old_x0, nil, ir.Types[ir.TFLOAT64])
 		gins(x86.AXORPS, &vec_zero, &vec_zero)
 
 		if di := dzDI(w); di != 0 {
@@ -607,7 +551,7 @@ func clearfat(nl *gc.Node) {
 		p.To.Offset = dzOff(w)
 
 		if w%16 != 0 {
-			n1.Op = gc.OINDREG
+			n1.Op = ir.OINDREG
 			n1.Xoffset -= 16 - w%16
 			gins(x86.AMOVUPS, &vec_zero, &n1)
 		}
@@ -619,19 +563,19 @@ func clearfat(nl *gc.Node) {
 
 	// NOTE: Must use agen, not igen, so that optimizer sees address
 	// being taken. We are not writing on field boundaries.
-	var n1 gc.Node
+	var n1 ir.Node
 	gc.Agenr(nl, &n1, nil)
-	n1.Op = gc.OINDREG
+	n1.Op = ir.OINDREG
 
 	clearfat_tail(&n1, w)
 
 	gc.Regfree(&n1)
 }
 
-func clearfat_tail(n1 *gc.Node, b int64) {
+func clearfat_tail(n1 *ir.Node, b int64) {
 	if b >= 16 && isPlan9 {
-		var z gc.Node
-		gc.Nodconst(&z, gc.Types[gc.TUINT64], 0)
+		var z ir.Node
+		gc.Nodconst(&z, ir.Types[ir.TUINT64], 0)
 		q := b / 8
 		for ; q > 0; q-- {
 			n1.Type = z.Type
@@ -646,8 +590,8 @@ func clearfat_tail(n1 *gc.Node, b int64) {
 		return
 	}
 	if b >= 16 {
-		var vec_zero gc.Node
-		gc.Regalloc(&vec_zero, gc.Types[gc.TFLOAT64], nil)
+		var vec_zero ir.Node
+		gc.Regalloc(&vec_zero, ir.Types[ir.TFLOAT64], nil)
 		gins(x86.AXORPS, &vec_zero, &vec_zero)
 
 		for b >= 16 {
@@ -670,8 +614,8 @@ func clearfat_tail(n1 *gc.Node, b int64) {
 	// The hope is that although the code will be slightly longer,
 	// the MOVs will have no dependencies and pipeline better
 	// than the unrolled STOSQ loop.
-	var z gc.Node
-	gc.Nodconst(&z, gc.Types[gc.TUINT64], 0)
+	var z ir.Node
+	gc.Nodconst(&z, ir.Types[ir.TUINT64], 0)
 	if b >= 8 {
 		n1.Type = z.Type
 		gins(x86.AMOVQ, &z, n1)
@@ -686,7 +630,7 @@ func clearfat_tail(n1 *gc.Node, b int64) {
 	}
 
 	if b >= 4 {
-		gc.Nodconst(&z, gc.Types[gc.TUINT32], 0)
+		gc.Nodconst(&z, ir.Types[ir.TUINT32], 0)
 		n1.Type = z.Type
 		gins(x86.AMOVL, &z, n1)
 		n1.Xoffset += 4
@@ -700,14 +644,14 @@ func clearfat_tail(n1 *gc.Node, b int64) {
 	}
 
 	if b >= 2 {
-		gc.Nodconst(&z, gc.Types[gc.TUINT16], 0)
+		gc.Nodconst(&z, ir.Types[ir.TUINT16], 0)
 		n1.Type = z.Type
 		gins(x86.AMOVW, &z, n1)
 		n1.Xoffset += 2
 		b -= 2
 	}
 
-	gc.Nodconst(&z, gc.Types[gc.TUINT8], 0)
+	gc.Nodconst(&z, ir.Types[ir.TUINT8], 0)
 	for b > 0 {
 		n1.Type = z.Type
 		gins(x86.AMOVB, &z, n1)
@@ -776,7 +720,7 @@ func expandchecks(firstp *obj.Prog) {
 }
 
 // addr += index*width if possible.
-func addindex(index *gc.Node, width int64, addr *gc.Node) bool {
+func addindex(index *ir.Node, width int64, addr *ir.Node) bool {
 	switch width {
 	case 1, 2, 4, 8:
 		p1 := gins(x86.ALEAQ, index, addr)
@@ -790,10 +734,10 @@ func addindex(index *gc.Node, width int64, addr *gc.Node) bool {
 }
 
 // res = runtime.getg()
-func getg(res *gc.Node) {
-	var n1 gc.Node
+func getg(res *ir.Node) {
+	var n1 ir.Node
 	gc.Regalloc(&n1, res.Type, res)
-	mov := optoas(gc.OAS, gc.Types[gc.Tptr])
+	mov := optoas(ir.OAS, ir.Types[gc.Tptr])
 	p := gins(mov, nil, &n1)
 	p.From.Type = obj.TYPE_REG
 	p.From.Reg = x86.REG_TLS
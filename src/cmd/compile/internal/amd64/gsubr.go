@@ -33,6 +33,7 @@ package amd64
 import (
 	"cmd/compile/internal/big"
 	"cmd/compile/internal/gc"
+	"cmd/compile/internal/ir"
 	"cmd/internal/obj"
 	"cmd/internal/obj/x86"
 	"fmt"
@@ -53,16 +54,16 @@ var resvd = []int{
  *	as $c, reg
  */
 func gconreg(as obj.As, c int64, reg int) {
-	var nr gc.Node
+	var nr ir.Node
 
 	switch as {
 	case x86.AADDL,
 		x86.AMOVL,
 		x86.ALEAL:
-		gc.Nodreg(&nr, gc.Types[gc.TINT32], reg)
+		gc.Nodreg(&nr, ir.Types[ir.TINT32], reg)
 
 	default:
-		gc.Nodreg(&nr, gc.Types[gc.TINT64], reg)
+		gc.Nodreg(&nr, ir.Types[ir.TINT64], reg)
 	}
 
 	ginscon(as, c, &nr)
@@ -72,24 +73,24 @@ func gconreg(as obj.As, c int64, reg int) {
  * generate
  *	as $c, n
  */
-func ginscon(as obj.As, c int64, n2 *gc.Node) {
-	var n1 gc.Node
+func ginscon(as obj.As, c int64, n2 *ir.Node) {
+	var n1 ir.Node
 
 	switch as {
 	case x86.AADDL,
 		x86.AMOVL,
 		x86.ALEAL:
-		gc.Nodconst(&n1, gc.Types[gc.TINT32], c)
+		gc.Nodconst(&n1, ir.Types[ir.TINT32], c)
 
 	default:
-		gc.Nodconst(&n1, gc.Types[gc.TINT64], c)
+		gc.Nodconst(&n1, ir.Types[ir.TINT64], c)
 	}
 
 	if as != x86.AMOVQ && (c < -(1<<31) || c >= 1<<31) {
 		// cannot have 64-bit immediate in ADD, etc.
 		// instead, MOV into register first.
-		var ntmp gc.Node
-		gc.Regalloc(&ntmp, gc.Types[gc.TINT64], nil)
+		var ntmp ir.Node
+		gc.Regalloc(&ntmp, ir.Types[ir.TINT64], nil)
 
 		gins(x86.AMOVQ, &n1, &ntmp)
 		gins(as, &ntmp, n2)
@@ -100,23 +101,23 @@ func ginscon(as obj.As, c int64, n2 *gc.Node) {
 	gins(as, &n1, n2)
 }
 
-func ginscmp(op gc.Op, t *gc.Type, n1, n2 *gc.Node, likely int) *obj.Prog {
-	if gc.Isint[t.Etype] && n1.Op == gc.OLITERAL && gc.Smallintconst(n1) && n2.Op != gc.OLITERAL {
+func ginscmp(op ir.Op, t *ir.Type, n1, n2 *ir.Node, likely int) *obj.Prog {
+	if gc.Isint[t.Etype] && n1.Op == ir.OLITERAL && gc.Smallintconst(n1) && n2.Op != ir.OLITERAL {
 		// Reverse comparison to place constant last.
 		op = gc.Brrev(op)
 		n1, n2 = n2, n1
 	}
 	// General case.
-	var r1, r2, g1, g2 gc.Node
+	var r1, r2, g1, g2 ir.Node
 
 	// A special case to make write barriers more efficient.
 	// Comparing the first field of a named struct can be done directly.
 	base := n1
-	if n1.Op == gc.ODOT && n1.Left.Type.Etype == gc.TSTRUCT && n1.Left.Type.Field(0).Sym == n1.Sym {
+	if n1.Op == ir.ODOT && n1.Left.Type.Etype == ir.TSTRUCT && n1.Left.Type.Field(0).Sym == n1.Sym {
 		base = n1.Left
 	}
 
-	if base.Op == gc.ONAME && base.Class&gc.PHEAP == 0 || n1.Op == gc.OINDREG {
+	if base.Op == ir.ONAME && base.Class&ir.PHEAP == 0 || n1.Op == ir.OINDREG {
 		r1 = *n1
 	} else {
 		gc.Regalloc(&r1, t, n1)
@@ -124,7 +125,7 @@ func ginscmp(op gc.Op, t *gc.Type, n1, n2 *gc.Node, likely int) *obj.Prog {
 		gc.Cgen(n1, &g1)
 		gmove(&g1, &r1)
 	}
-	if n2.Op == gc.OLITERAL && gc.Isint[t.Etype] && gc.Smallintconst(n2) {
+	if n2.Op == ir.OLITERAL && gc.Isint[t.Etype] && gc.Smallintconst(n2) {
 		r2 = *n2
 	} else {
 		gc.Regalloc(&r2, t, n2)
@@ -132,26 +133,26 @@ func ginscmp(op gc.Op, t *gc.Type, n1, n2 *gc.Node, likely int) *obj.Prog {
 		gc.Cgen(n2, &g2)
 		gmove(&g2, &r2)
 	}
-	gins(optoas(gc.OCMP, t), &r1, &r2)
-	if r1.Op == gc.OREGISTER {
+	gins(optoas(ir.OCMP, t), &r1, &r2)
+	if r1.Op == ir.OREGISTER {
 		gc.Regfree(&g1)
 		gc.Regfree(&r1)
 	}
-	if r2.Op == gc.OREGISTER {
+	if r2.Op == ir.OREGISTER {
 		gc.Regfree(&g2)
 		gc.Regfree(&r2)
 	}
 	return gc.Gbranch(optoas(op, t), nil, likely)
 }
 
-func ginsboolval(a obj.As, n *gc.Node) {
+func ginsboolval(a obj.As, n *ir.Node) {
 	gins(jmptoset(a), nil, n)
 }
 
 // set up nodes representing 2^63
 var (
-	bigi         gc.Node
-	bigf         gc.Node
+	bigi         ir.Node
+	bigf         ir.Node
 	bignodes_did bool
 )
 
@@ -165,10 +166,10 @@ func bignodes() {
 	i.SetInt64(1)
 	i.Lsh(&i, 63)
 
-	gc.Nodconst(&bigi, gc.Types[gc.TUINT64], 0)
+	gc.Nodconst(&bigi, ir.Types[ir.TUINT64], 0)
 	bigi.SetBigInt(&i)
 
-	bigi.Convconst(&bigf, gc.Types[gc.TFLOAT64])
+	gc.Convconst(&bigi, &bigf, ir.Types[ir.TFLOAT64])
 }
 
 /*
@@ -176,9 +177,9 @@ func bignodes() {
  *	t = f
  * hard part is conversions.
  */
-func gmove(f *gc.Node, t *gc.Node) {
+func gmove(f *ir.Node, t *ir.Node) {
 	if gc.Debug['M'] != 0 {
-		fmt.Printf("gmove %v -> %v\n", gc.Nconv(f, gc.FmtLong), gc.Nconv(t, gc.FmtLong))
+		fmt.Printf("gmove %v -> %v\n", gc.Nconv(f, ir.FmtLong), gc.Nconv(t, ir.FmtLong))
 	}
 
 	ft := gc.Simsimtype(f.Type)
@@ -197,9 +198,9 @@ func gmove(f *gc.Node, t *gc.Node) {
 	}
 
 	// convert constant to desired type
-	if f.Op == gc.OLITERAL {
-		var con gc.Node
-		f.Convconst(&con, t.Type)
+	if f.Op == ir.OLITERAL {
+		var con ir.Node
+		gc.Convconst(f, &con, t.Type)
 		f = &con
 		ft = tt // so big switch will choose a simple mov
 
@@ -229,122 +230,122 @@ func gmove(f *gc.Node, t *gc.Node) {
 
 	switch uint32(ft)<<16 | uint32(tt) {
 	default:
-		gc.Fatalf("gmove %v -> %v", gc.Tconv(f.Type, gc.FmtLong), gc.Tconv(t.Type, gc.FmtLong))
+		gc.Fatalf("gmove %v -> %v", gc.Tconv(f.Type, ir.FmtLong), gc.Tconv(t.Type, ir.FmtLong))
 
 		/*
 		 * integer copy and truncate
 		 */
-	case gc.TINT8<<16 | gc.TINT8, // same size
-		gc.TINT8<<16 | gc.TUINT8,
-		gc.TUINT8<<16 | gc.TINT8,
-		gc.TUINT8<<16 | gc.TUINT8,
-		gc.TINT16<<16 | gc.TINT8,
+	case ir.TINT8<<16 | ir.TINT8, // same size
+		ir.TINT8<<16 | ir.TUINT8,
+		ir.TUINT8<<16 | ir.TINT8,
+		ir.TUINT8<<16 | ir.TUINT8,
+		ir.TINT16<<16 | ir.TINT8,
 		// truncate
-		gc.TUINT16<<16 | gc.TINT8,
-		gc.TINT32<<16 | gc.TINT8,
-		gc.TUINT32<<16 | gc.TINT8,
-		gc.TINT64<<16 | gc.TINT8,
-		gc.TUINT64<<16 | gc.TINT8,
-		gc.TINT16<<16 | gc.TUINT8,
-		gc.TUINT16<<16 | gc.TUINT8,
-		gc.TINT32<<16 | gc.TUINT8,
-		gc.TUINT32<<16 | gc.TUINT8,
-		gc.TINT64<<16 | gc.TUINT8,
-		gc.TUINT64<<16 | gc.TUINT8:
+		ir.TUINT16<<16 | ir.TINT8,
+		ir.TINT32<<16 | ir.TINT8,
+		ir.TUINT32<<16 | ir.TINT8,
+		ir.TINT64<<16 | ir.TINT8,
+		ir.TUINT64<<16 | ir.TINT8,
+		ir.TINT16<<16 | ir.TUINT8,
+		ir.TUINT16<<16 | ir.TUINT8,
+		ir.TINT32<<16 | ir.TUINT8,
+		ir.TUINT32<<16 | ir.TUINT8,
+		ir.TINT64<<16 | ir.TUINT8,
+		ir.TUINT64<<16 | ir.TUINT8:
 		a = x86.AMOVB
 
-	case gc.TINT16<<16 | gc.TINT16, // same size
-		gc.TINT16<<16 | gc.TUINT16,
-		gc.TUINT16<<16 | gc.TINT16,
-		gc.TUINT16<<16 | gc.TUINT16,
-		gc.TINT32<<16 | gc.TINT16,
+	case ir.TINT16<<16 | ir.TINT16, // same size
+		ir.TINT16<<16 | ir.TUINT16,
+		ir.TUINT16<<16 | ir.TINT16,
+		ir.TUINT16<<16 | ir.TUINT16,
+		ir.TINT32<<16 | ir.TINT16,
 		// truncate
-		gc.TUINT32<<16 | gc.TINT16,
-		gc.TINT64<<16 | gc.TINT16,
-		gc.TUINT64<<16 | gc.TINT16,
-		gc.TINT32<<16 | gc.TUINT16,
-		gc.TUINT32<<16 | gc.TUINT16,
-		gc.TINT64<<16 | gc.TUINT16,
-		gc.TUINT64<<16 | gc.TUINT16:
+		ir.TUINT32<<16 | ir.TINT16,
+		ir.TINT64<<16 | ir.TINT16,
+		ir.TUINT64<<16 | ir.TINT16,
+		ir.TINT32<<16 | ir.TUINT16,
+		ir.TUINT32<<16 | ir.TUINT16,
+		ir.TINT64<<16 | ir.TUINT16,
+		ir.TUINT64<<16 | ir.TUINT16:
 		a = x86.AMOVW
 
-	case gc.TINT32<<16 | gc.TINT32, // same size
-		gc.TINT32<<16 | gc.TUINT32,
-		gc.TUINT32<<16 | gc.TINT32,
-		gc.TUINT32<<16 | gc.TUINT32:
+	case ir.TINT32<<16 | ir.TINT32, // same size
+		ir.TINT32<<16 | ir.TUINT32,
+		ir.TUINT32<<16 | ir.TINT32,
+		ir.TUINT32<<16 | ir.TUINT32:
 		a = x86.AMOVL
 
-	case gc.TINT64<<16 | gc.TINT32, // truncate
-		gc.TUINT64<<16 | gc.TINT32,
-		gc.TINT64<<16 | gc.TUINT32,
-		gc.TUINT64<<16 | gc.TUINT32:
+	case ir.TINT64<<16 | ir.TINT32, // truncate
+		ir.TUINT64<<16 | ir.TINT32,
+		ir.TINT64<<16 | ir.TUINT32,
+		ir.TUINT64<<16 | ir.TUINT32:
 		a = x86.AMOVQL
 
-	case gc.TINT64<<16 | gc.TINT64, // same size
-		gc.TINT64<<16 | gc.TUINT64,
-		gc.TUINT64<<16 | gc.TINT64,
-		gc.TUINT64<<16 | gc.TUINT64:
+	case ir.TINT64<<16 | ir.TINT64, // same size
+		ir.TINT64<<16 | ir.TUINT64,
+		ir.TUINT64<<16 | ir.TINT64,
+		ir.TUINT64<<16 | ir.TUINT64:
 		a = x86.AMOVQ
 
 		/*
 		 * integer up-conversions
 		 */
-	case gc.TINT8<<16 | gc.TINT16, // sign extend int8
-		gc.TINT8<<16 | gc.TUINT16:
+	case ir.TINT8<<16 | ir.TINT16, // sign extend int8
+		ir.TINT8<<16 | ir.TUINT16:
 		a = x86.AMOVBWSX
 
 		goto rdst
 
-	case gc.TINT8<<16 | gc.TINT32,
-		gc.TINT8<<16 | gc.TUINT32:
+	case ir.TINT8<<16 | ir.TINT32,
+		ir.TINT8<<16 | ir.TUINT32:
 		a = x86.AMOVBLSX
 		goto rdst
 
-	case gc.TINT8<<16 | gc.TINT64,
-		gc.TINT8<<16 | gc.TUINT64:
+	case ir.TINT8<<16 | ir.TINT64,
+		ir.TINT8<<16 | ir.TUINT64:
 		a = x86.AMOVBQSX
 		goto rdst
 
-	case gc.TUINT8<<16 | gc.TINT16, // zero extend uint8
-		gc.TUINT8<<16 | gc.TUINT16:
+	case ir.TUINT8<<16 | ir.TINT16, // zero extend uint8
+		ir.TUINT8<<16 | ir.TUINT16:
 		a = x86.AMOVBWZX
 
 		goto rdst
 
-	case gc.TUINT8<<16 | gc.TINT32,
-		gc.TUINT8<<16 | gc.TUINT32:
+	case ir.TUINT8<<16 | ir.TINT32,
+		ir.TUINT8<<16 | ir.TUINT32:
 		a = x86.AMOVBLZX
 		goto rdst
 
-	case gc.TUINT8<<16 | gc.TINT64,
-		gc.TUINT8<<16 | gc.TUINT64:
+	case ir.TUINT8<<16 | ir.TINT64,
+		ir.TUINT8<<16 | ir.TUINT64:
 		a = x86.AMOVBQZX
 		goto rdst
 
-	case gc.TINT16<<16 | gc.TINT32, // sign extend int16
-		gc.TINT16<<16 | gc.TUINT32:
+	case ir.TINT16<<16 | ir.TINT32, // sign extend int16
+		ir.TINT16<<16 | ir.TUINT32:
 		a = x86.AMOVWLSX
 
 		goto rdst
 
-	case gc.TINT16<<16 | gc.TINT64,
-		gc.TINT16<<16 | gc.TUINT64:
+	case ir.TINT16<<16 | ir.TINT64,
+		ir.TINT16<<16 | ir.TUINT64:
 		a = x86.AMOVWQSX
 		goto rdst
 
-	case gc.TUINT16<<16 | gc.TINT32, // zero extend uint16
-		gc.TUINT16<<16 | gc.TUINT32:
+	case ir.TUINT16<<16 | ir.TINT32, // zero extend uint16
+		ir.TUINT16<<16 | ir.TUINT32:
 		a = x86.AMOVWLZX
 
 		goto rdst
 
-	case gc.TUINT16<<16 | gc.TINT64,
-		gc.TUINT16<<16 | gc.TUINT64:
+	case ir.TUINT16<<16 | ir.TINT64,
+		ir.TUINT16<<16 | ir.TUINT64:
 		a = x86.AMOVWQZX
 		goto rdst
 
-	case gc.TINT32<<16 | gc.TINT64, // sign extend int32
-		gc.TINT32<<16 | gc.TUINT64:
+	case ir.TINT32<<16 | ir.TINT64, // sign extend int32
+		ir.TINT32<<16 | ir.TUINT64:
 		a = x86.AMOVLQSX
 
 		goto rdst
@@ -352,8 +353,8 @@ func gmove(f *gc.Node, t *gc.Node) {
 		// AMOVL into a register zeros the top of the register,
 	// so this is not always necessary, but if we rely on AMOVL
 	// the optimizer is almost certain to screw with us.
-	case gc.TUINT32<<16 | gc.TINT64, // zero extend uint32
-		gc.TUINT32<<16 | gc.TUINT64:
+	case ir.TUINT32<<16 | ir.TINT64, // zero extend uint32
+		ir.TUINT32<<16 | ir.TUINT64:
 		a = x86.AMOVLQZX
 
 		goto rdst
@@ -361,70 +362,70 @@ func gmove(f *gc.Node, t *gc.Node) {
 		/*
 		* float to integer
 		 */
-	case gc.TFLOAT32<<16 | gc.TINT32:
+	case ir.TFLOAT32<<16 | ir.TINT32:
 		a = x86.ACVTTSS2SL
 
 		goto rdst
 
-	case gc.TFLOAT64<<16 | gc.TINT32:
+	case ir.TFLOAT64<<16 | ir.TINT32:
 		a = x86.ACVTTSD2SL
 		goto rdst
 
-	case gc.TFLOAT32<<16 | gc.TINT64:
+	case ir.TFLOAT32<<16 | ir.TINT64:
 		a = x86.ACVTTSS2SQ
 		goto rdst
 
-	case gc.TFLOAT64<<16 | gc.TINT64:
+	case ir.TFLOAT64<<16 | ir.TINT64:
 		a = x86.ACVTTSD2SQ
 		goto rdst
 
 		// convert via int32.
-	case gc.TFLOAT32<<16 | gc.TINT16,
-		gc.TFLOAT32<<16 | gc.TINT8,
-		gc.TFLOAT32<<16 | gc.TUINT16,
-		gc.TFLOAT32<<16 | gc.TUINT8,
-		gc.TFLOAT64<<16 | gc.TINT16,
-		gc.TFLOAT64<<16 | gc.TINT8,
-		gc.TFLOAT64<<16 | gc.TUINT16,
-		gc.TFLOAT64<<16 | gc.TUINT8:
-		cvt = gc.Types[gc.TINT32]
+	case ir.TFLOAT32<<16 | ir.TINT16,
+		ir.TFLOAT32<<16 | ir.TINT8,
+		ir.TFLOAT32<<16 | ir.TUINT16,
+		ir.TFLOAT32<<16 | ir.TUINT8,
+		ir.TFLOAT64<<16 | ir.TINT16,
+		ir.TFLOAT64<<16 | ir.TINT8,
+		ir.TFLOAT64<<16 | ir.TUINT16,
+		ir.TFLOAT64<<16 | ir.TUINT8:
+		cvt = ir.Types[ir.TINT32]
 
 		goto hard
 
 		// convert via int64.
-	case gc.TFLOAT32<<16 | gc.TUINT32,
-		gc.TFLOAT64<<16 | gc.TUINT32:
-		cvt = gc.Types[gc.TINT64]
+	case ir.TFLOAT32<<16 | ir.TUINT32,
+		ir.TFLOAT64<<16 | ir.TUINT32:
+		cvt = ir.Types[ir.TINT64]
 
 		goto hard
 
 		// algorithm is:
 	//	if small enough, use native float64 -> int64 conversion.
 	//	otherwise, subtract 2^63, convert, and add it back.
-	case gc.TFLOAT32<<16 | gc.TUINT64,
-		gc.TFLOAT64<<16 | gc.TUINT64:
+	case ir.TFLOAT32<<16 | ir.TUINT64,
+		ir.TFLOAT64<<16 | ir.TUINT64:
 		a := x86.ACVTTSS2SQ
 
-		if ft == gc.TFLOAT64 {
+		if ft == ir.TFLOAT64 {
 			a = x86.ACVTTSD2SQ
 		}
 		bignodes()
-		var r1 gc.Node
-		gc.Regalloc(&r1, gc.Types[ft], nil)
-		var r2 gc.Node
-		gc.Regalloc(&r2, gc.Types[tt], t)
-		var r3 gc.Node
-		gc.Regalloc(&r3, gc.Types[ft], nil)
-		var r4 gc.Node
-		gc.Regalloc(&r4, gc.Types[tt], nil)
-		gins(optoas(gc.OAS, f.Type), f, &r1)
-		gins(optoas(gc.OCMP, f.Type), &bigf, &r1)
-		p1 := gc.Gbranch(optoas(gc.OLE, f.Type), nil, +1)
+		var r1 ir.Node
+		gc.Regalloc(&r1, ir.Types[ft], nil)
+		var r2 ir.Node
+		gc.Regalloc(&r2, ir.Types[tt], t)
+		var r3 ir.Node
+		gc.Regalloc(&r3, ir.Types[ft], nil)
+		var r4 ir.Node
+		gc.Regalloc(&r4, ir.Types[tt], nil)
+		gins(optoas(ir.OAS, f.Type), f, &r1)
+		gins(optoas(ir.OCMP, f.Type), &bigf, &r1)
+		p1 := gc.Gbranch(optoas(ir.OLE, f.Type), nil, +1)
 		gins(a, &r1, &r2)
 		p2 := gc.Gbranch(obj.AJMP, nil, 0)
 		gc.Patch(p1, gc.Pc)
-		gins(optoas(gc.OAS, f.Type), &bigf, &r3)
-		gins(optoas(gc.OSUB, f.Type), &r3, &r1)
+		gins(optoas(ir.OAS, f.Type), &bigf, &r3)
+		gins(optoas(ir.OSUB, f.Type), &r3, &r1)
 		gins(a, &r1, &r2)
 		gins(x86.AMOVQ, &bigi, &r4)
 		gins(x86.AXORQ, &r4, &r2)
@@ -439,64 +440,64 @@ func gmove(f *gc.Node, t *gc.Node) {
 		/*
 		 * integer to float
 		 */
-	case gc.TINT32<<16 | gc.TFLOAT32:
+	case ir.TINT32<<16 | ir.TFLOAT32:
 		a = x86.ACVTSL2SS
 
 		goto rdst
 
-	case gc.TINT32<<16 | gc.TFLOAT64:
+	case ir.TINT32<<16 | ir.TFLOAT64:
 		a = x86.ACVTSL2SD
 		goto rdst
 
-	case gc.TINT64<<16 | gc.TFLOAT32:
+	case ir.TINT64<<16 | ir.TFLOAT32:
 		a = x86.ACVTSQ2SS
 		goto rdst
 
-	case gc.TINT64<<16 | gc.TFLOAT64:
+	case ir.TINT64<<16 | ir.TFLOAT64:
 		a = x86.ACVTSQ2SD
 		goto rdst
 
 		// convert via int32
-	case gc.TINT16<<16 | gc.TFLOAT32,
-		gc.TINT16<<16 | gc.TFLOAT64,
-		gc.TINT8<<16 | gc.TFLOAT32,
-		gc.TINT8<<16 | gc.TFLOAT64,
-		gc.TUINT16<<16 | gc.TFLOAT32,
-		gc.TUINT16<<16 | gc.TFLOAT64,
-		gc.TUINT8<<16 | gc.TFLOAT32,
-		gc.TUINT8<<16 | gc.TFLOAT64:
-		cvt = gc.Types[gc.TINT32]
+	case ir.TINT16<<16 | ir.TFLOAT32,
+		ir.TINT16<<16 | ir.TFLOAT64,
+		ir.TINT8<<16 | ir.TFLOAT32,
+		ir.TINT8<<16 | ir.TFLOAT64,
+		ir.TUINT16<<16 | ir.TFLOAT32,
+		ir.TUINT16<<16 | ir.TFLOAT64,
+		ir.TUINT8<<16 | ir.TFLOAT32,
+		ir.TUINT8<<16 | ir.TFLOAT64:
+		cvt = ir.Types[ir.TINT32]
 
 		goto hard
 
 		// convert via int64.
-	case gc.TUINT32<<16 | gc.TFLOAT32,
-		gc.TUINT32<<16 | gc.TFLOAT64:
-		cvt = gc.Types[gc.TINT64]
+	case ir.TUINT32<<16 | ir.TFLOAT32,
+		ir.TUINT32<<16 | ir.TFLOAT64:
+		cvt = ir.Types[ir.TINT64]
 
 		goto hard
 
 		// algorithm is:
 	//	if small enough, use native int64 -> uint64 conversion.
 	//	otherwise, halve (rounding to odd?), convert, and double.
-	case gc.TUINT64<<16 | gc.TFLOAT32,
-		gc.TUINT64<<16 | gc.TFLOAT64:
+	case ir.TUINT64<<16 | ir.TFLOAT32,
+		ir.TUINT64<<16 | ir.TFLOAT64:
 		a := x86.ACVTSQ2SS
 
-		if tt == gc.TFLOAT64 {
+		if tt == ir.TFLOAT64 {
 			a = x86.ACVTSQ2SD
 		}
-		var zero gc.Node
-		gc.Nodconst(&zero, gc.Types[gc.TUINT64], 0)
-		var one gc.Node
-		gc.Nodconst(&one, gc.Types[gc.TUINT64], 1)
-		var r1 gc.Node
+		var zero ir.Node
+		gc.Nodconst(&zero, ir.Types[ir.TUINT64], 0)
+		var one ir.Node
+		gc.Nodconst(&one, ir.Types[ir.TUINT64], 1)
+		var r1 ir.Node
 		gc.Regalloc(&r1, f.Type, f)
-		var r2 gc.Node
+		var r2 ir.Node
 		gc.Regalloc(&r2, t.Type, t)
-		var r3 gc.Node
+		var r3 ir.Node
 		gc.Regalloc(&r3, f.Type, nil)
-		var r4 gc.Node
+		var r4 ir.Node
 		gc.Regalloc(&r4, f.Type, nil)
 		gmove(f, &r1)
 		gins(x86.ACMPQ, &r1, &zero)
@@ -510,7 +511,7 @@ func gmove(f *gc.Node, t *gc.Node) {
 		gins(x86.AANDL, &one, &r4)
 		gins(x86.AORQ, &r4, &r3)
 		gins(a, &r3, &r2)
-		gins(optoas(gc.OADD, t.Type), &r2, &r2)
+		gins(optoas(ir.OADD, t.Type), &r2, &r2)
 		gc.Patch(p2, gc.Pc)
 		gmove(&r2, t)
 		gc.Regfree(&r4)
@@ -522,17 +523,17 @@ func gmove(f *gc.Node, t *gc.Node) {
 		/*
 		 * float to float
 		 */
-	case gc.TFLOAT32<<16 | gc.TFLOAT32:
+	case ir.TFLOAT32<<16 | ir.TFLOAT32:
 		a = x86.AMOVSS
 
-	case gc.TFLOAT64<<16 | gc.TFLOAT64:
+	case ir.TFLOAT64<<16 | ir.TFLOAT64:
 		a = x86.AMOVSD
 
-	case gc.TFLOAT32<<16 | gc.TFLOAT64:
+	case ir.TFLOAT32<<16 | ir.TFLOAT64:
 		a = x86.ACVTSS2SD
 		goto rdst
 
-	case gc.TFLOAT64<<16 | gc.TFLOAT32:
+	case ir.TFLOAT64<<16 | ir.TFLOAT32:
 		a = x86.ACVTSD2SS
 		goto rdst
 	}
@@ -543,7 +544,7 @@ func gmove(f *gc.Node, t *gc.Node) {
 	// requires register destination
 rdst:
 	{
-		var r1 gc.Node
+		var r1 ir.Node
 		gc.Regalloc(&r1, t.Type, t)
 
 		gins(a, f, &r1)
@@ -554,7 +555,7 @@ rdst:
 
 	// requires register intermediate
 hard:
-	var r1 gc.Node
+	var r1 ir.Node
 	gc.Regalloc(&r1, cvt, t)
 
 	gmove(f, &r1)
@@ -563,13 +564,13 @@ hard:
 	return
 }
 
-func samaddr(f *gc.Node, t *gc.Node) bool {
+func samaddr(f *ir.Node, t *ir.Node) bool {
 	if f.Op != t.Op {
 		return false
 	}
 
 	switch f.Op {
-	case gc.OREGISTER:
+	case ir.OREGISTER:
 		if f.Reg != t.Reg {
 			break
 		}
@@ -583,7 +584,7 @@ func samaddr(f *gc.Node, t *gc.Node) bool {
  * generate one instruction:
  *	as f, t
  */
-func gins(as obj.As, f *gc.Node, t *gc.Node) *obj.Prog {
+func gins(as obj.As, f *ir.Node, t *ir.Node) *obj.Prog {
 	//	Node nod;
 
 	//	if(f != N && f->op == OINDEX) {
@@ -603,7 +604,7 @@ func gins(as obj.As, f *gc.Node, t *gc.Node) *obj.Prog {
 	//		gc.Regfree(&nod);
 	//	}
 
-	if f != nil && f.Op == gc.OADDR && (as == x86.AMOVL || as == x86.AMOVQ) {
+	if f != nil && f.Op == ir.OADDR && (as == x86.AMOVL || as == x86.AMOVQ) {
 		// Turn MOVL $xxx into LEAL xxx.
 		// These should be equivalent but most of the backend
 		// only expects to see LEAL, because that's what we had
@@ -628,7 +629,7 @@ func gins(as obj.As, f *gc.Node, t *gc.Node) *obj.Prog {
 		}
 
 	case x86.ALEAQ:
-		if f != nil && gc.Isconst(f, gc.CTNIL) {
+		if f != nil && ir.Isconst(f, ir.CTNIL) {
 			gc.Fatalf("gins LEAQ nil %v", f.Type)
 		}
 	}
@@ -673,50 +674,50 @@ func ginsnop() {
 	// This is actually not the x86 NOP anymore,
 	// but at the point where it gets used, AX is dead
 	// so it's okay if we lose the high bits.
-	var reg gc.Node
-	gc.Nodreg(&reg, gc.Types[gc.TINT], x86.REG_AX)
+	var reg ir.Node
+	gc.Nodreg(&reg, ir.Types[ir.TINT], x86.REG_AX)
 	gins(x86.AXCHGL, &reg, &reg)
 }
 
 /*
  * return Axxx for Oxxx on type t.
  */
-func optoas(op gc.Op, t *gc.Type) obj.As {
+func optoas(op ir.Op, t *ir.Type) obj.As {
 	if t == nil {
 		gc.Fatalf("optoas: t is nil")
 	}
 
 	// avoid constant conversions in switches below
 	const (
-		OMINUS_  = uint32(gc.OMINUS) << 16
-		OLSH_    = uint32(gc.OLSH) << 16
-		ORSH_    = uint32(gc.ORSH) << 16
-		OADD_    = uint32(gc.OADD) << 16
-		OSUB_    = uint32(gc.OSUB) << 16
-		OMUL_    = uint32(gc.OMUL) << 16
-		ODIV_    = uint32(gc.ODIV) << 16
-		OMOD_    = uint32(gc.OMOD) << 16
-		OOR_     = uint32(gc.OOR) << 16
-		OAND_    = uint32(gc.OAND) << 16
-		OXOR_    = uint32(gc.OXOR) << 16
-		OEQ_     = uint32(gc.OEQ) << 16
-		ONE_     = uint32(gc.ONE) << 16
-		OLT_     = uint32(gc.OLT) << 16
-		OLE_     = uint32(gc.OLE) << 16
-		OGE_     = uint32(gc.OGE) << 16
-		OGT_     = uint32(gc.OGT) << 16
-		OCMP_    = uint32(gc.OCMP) << 16
-		OPS_     = uint32(gc.OPS) << 16
-		OPC_     = uint32(gc.OPC) << 16
-		OAS_     = uint32(gc.OAS) << 16
-		OHMUL_   = uint32(gc.OHMUL) << 16
-		OSQRT_   = uint32(gc.OSQRT) << 16
-		OADDR_   = uint32(gc.OADDR) << 16
-		OINC_    = uint32(gc.OINC) << 16
-		ODEC_    = uint32(gc.ODEC) << 16
-		OLROT_   = uint32(gc.OLROT) << 16
-		ORROTC_  = uint32(gc.ORROTC) << 16
-		OEXTEND_ = uint32(gc.OEXTEND) << 16
+		OMINUS_  = uint32(ir.OMINUS) << 16
+		OLSH_    = uint32(ir.OLSH) << 16
+		ORSH_    = uint32(ir.ORSH) << 16
+		OADD_    = uint32(ir.OADD) << 16
+		OSUB_    = uint32(ir.OSUB) << 16
+		OMUL_    = uint32(ir.OMUL) << 16
+		ODIV_    = uint32(ir.ODIV) << 16
+		OMOD_    = uint32(ir.OMOD) << 16
+		OOR_     = uint32(ir.OOR) << 16
+		OAND_    = uint32(ir.OAND) << 16
+		OXOR_    = uint32(ir.OXOR) << 16
+		OEQ_     = uint32(ir.OEQ) << 16
+		ONE_     = uint32(ir.ONE) << 16
+		OLT_     = uint32(ir.OLT) << 16
+		OLE_     = uint32(ir.OLE) << 16
+		OGE_     = uint32(ir.OGE) << 16
+		OGT_     = uint32(ir.OGT) << 16
+		OCMP_    = uint32(ir.OCMP) << 16
+		OPS_     = uint32(ir.OPS) << 16
+		OPC_     = uint32(ir.OPC) << 16
+		OAS_     = uint32(ir.OAS) << 16
+		OHMUL_   = uint32(ir.OHMUL) << 16
+		OSQRT_   = uint32(ir.OSQRT) << 16
+		OADDR_   = uint32(ir.OADDR) << 16
+		OINC_    = uint32(ir.OINC) << 16
+		ODEC_    = uint32(ir.ODEC) << 16
+		OLROT_   = uint32(ir.OLROT) << 16
+		ORROTC_  = uint32(ir.ORROTC) << 16
+		OEXTEND_ = uint32(ir.OEXTEND) << 16
 	)
 
 	a := obj.AXXX
@@ -724,504 +725,504 @@ func optoas(op gc.Op, t *gc.Type) obj.As {
 	default:
 		gc.Fatalf("optoas: no entry %v-%v", gc.Oconv(op, 0), t)
 
-	case OADDR_ | gc.TPTR32:
+	case OADDR_ | ir.TPTR32:
 		a = x86.ALEAL
 
-	case OADDR_ | gc.TPTR64:
+	case OADDR_ | ir.TPTR64:
 		a = x86.ALEAQ
 
-	case OEQ_ | gc.TBOOL,
-		OEQ_ | gc.TINT8,
-		OEQ_ | gc.TUINT8,
-		OEQ_ | gc.TINT16,
-		OEQ_ | gc.TUINT16,
-		OEQ_ | gc.TINT32,
-		OEQ_ | gc.TUINT32,
-		OEQ_ | gc.TINT64,
-		OEQ_ | gc.TUINT64,
-		OEQ_ | gc.TPTR32,
-		OEQ_ | gc.TPTR64,
-		OEQ_ | gc.TFLOAT32,
-		OEQ_ | gc.TFLOAT64:
+	case OEQ_ | ir.TBOOL,
+		OEQ_ | ir.TINT8,
+		OEQ_ | ir.TUINT8,
+		OEQ_ | ir.TINT16,
+		OEQ_ | ir.TUINT16,
+		OEQ_ | ir.TINT32,
+		OEQ_ | ir.TUINT32,
+		OEQ_ | ir.TINT64,
+		OEQ_ | ir.TUINT64,
+		OEQ_ | ir.TPTR32,
+		OEQ_ | ir.TPTR64,
+		OEQ_ | ir.TFLOAT32,
+		OEQ_ | ir.TFLOAT64:
 		a = x86.AJEQ
 
-	case ONE_ | gc.TBOOL,
-		ONE_ | gc.TINT8,
-		ONE_ | gc.TUINT8,
-		ONE_ | gc.TINT16,
-		ONE_ | gc.TUINT16,
-		ONE_ | gc.TINT32,
-		ONE_ | gc.TUINT32,
-		ONE_ | gc.TINT64,
-		ONE_ | gc.TUINT64,
-		ONE_ | gc.TPTR32,
-		ONE_ | gc.TPTR64,
-		ONE_ | gc.TFLOAT32,
-		ONE_ | gc.TFLOAT64:
+	case ONE_ | ir.TBOOL,
+		ONE_ | ir.TINT8,
+		ONE_ | ir.TUINT8,
+		ONE_ | ir.TINT16,
+		ONE_ | ir.TUINT16,
+		ONE_ | ir.TINT32,
+		ONE_ | ir.TUINT32,
+		ONE_ | ir.TINT64,
+		ONE_ | ir.TUINT64,
+		ONE_ | ir.TPTR32,
+		ONE_ | ir.TPTR64,
+		ONE_ | ir.TFLOAT32,
+		ONE_ | ir.TFLOAT64:
 		a = x86.AJNE
 
-	case OPS_ | gc.TBOOL,
-		OPS_ | gc.TINT8,
-		OPS_ | gc.TUINT8,
-		OPS_ | gc.TINT16,
-		OPS_ | gc.TUINT16,
-		OPS_ | gc.TINT32,
-		OPS_ | gc.TUINT32,
-		OPS_ | gc.TINT64,
-		OPS_ | gc.TUINT64,
-		OPS_ | gc.TPTR32,
-		OPS_ | gc.TPTR64,
-		OPS_ | gc.TFLOAT32,
-		OPS_ | gc.TFLOAT64:
+	case OPS_ | ir.TBOOL,
+		OPS_ | ir.TINT8,
+		OPS_ | ir.TUINT8,
+		OPS_ | ir.TINT16,
+		OPS_ | ir.TUINT16,
+		OPS_ | ir.TINT32,
+		OPS_ | ir.TUINT32,
+		OPS_ | ir.TINT64,
+		OPS_ | ir.TUINT64,
+		OPS_ | ir.TPTR32,
+		OPS_ | ir.TPTR64,
+		OPS_ | ir.TFLOAT32,
+		OPS_ | ir.TFLOAT64:
 		a = x86.AJPS
 
-	case OPC_ | gc.TBOOL,
-		OPC_ | gc.TINT8,
-		OPC_ | gc.TUINT8,
-		OPC_ | gc.TINT16,
-		OPC_ | gc.TUINT16,
-		OPC_ | gc.TINT32,
-		OPC_ | gc.TUINT32,
-		OPC_ | gc.TINT64,
-		OPC_ | gc.TUINT64,
-		OPC_ | gc.TPTR32,
-		OPC_ | gc.TPTR64,
-		OPC_ | gc.TFLOAT32,
-		OPC_ | gc.TFLOAT64:
+	case OPC_ | ir.TBOOL,
+		OPC_ | ir.TINT8,
+		OPC_ | ir.TUINT8,
+		OPC_ | ir.TINT16,
+		OPC_ | ir.TUINT16,
+		OPC_ | ir.TINT32,
+		OPC_ | ir.TUINT32,
+		OPC_ | ir.TINT64,
+		OPC_ | ir.TUINT64,
+		OPC_ | ir.TPTR32,
+		OPC_ | ir.TPTR64,
+		OPC_ | ir.TFLOAT32,
+		OPC_ | ir.TFLOAT64:
 		a = x86.AJPC
 
-	case OLT_ | gc.TINT8,
-		OLT_ | gc.TINT16,
-		OLT_ | gc.TINT32,
-		OLT_ | gc.TINT64:
+	case OLT_ | ir.TINT8,
+		OLT_ | ir.TINT16,
+		OLT_ | ir.TINT32,
+		OLT_ | ir.TINT64:
 		a = x86.AJLT
 
-	case OLT_ | gc.TUINT8,
-		OLT_ | gc.TUINT16,
-		OLT_ | gc.TUINT32,
-		OLT_ | gc.TUINT64:
+	case OLT_ | ir.TUINT8,
+		OLT_ | ir.TUINT16,
+		OLT_ | ir.TUINT32,
+		OLT_ | ir.TUINT64:
 		a = x86.AJCS
 
-	case OLE_ | gc.TINT8,
-		OLE_ | gc.TINT16,
-		OLE_ | gc.TINT32,
-		OLE_ | gc.TINT64:
+	case OLE_ | ir.TINT8,
+		OLE_ | ir.TINT16,
+		OLE_ | ir.TINT32,
+		OLE_ | ir.TINT64:
 		a = x86.AJLE
 
-	case OLE_ | gc.TUINT8,
-		OLE_ | gc.TUINT16,
-		OLE_ | gc.TUINT32,
-		OLE_ | gc.TUINT64:
+	case OLE_ | ir.TUINT8,
+		OLE_ | ir.TUINT16,
+		OLE_ | ir.TUINT32,
+		OLE_ | ir.TUINT64:
 		a = x86.AJLS
 
-	case OGT_ | gc.TINT8,
-		OGT_ | gc.TINT16,
-		OGT_ | gc.TINT32,
-		OGT_ | gc.TINT64:
+	case OGT_ | ir.TINT8,
+		OGT_ | ir.TINT16,
+		OGT_ | ir.TINT32,
+		OGT_ | ir.TINT64:
 		a = x86.AJGT
 
-	case OGT_ | gc.TUINT8,
-		OGT_ | gc.TUINT16,
-		OGT_ | gc.TUINT32,
-		OGT_ | gc.TUINT64,
-		OLT_ | gc.TFLOAT32,
-		OLT_ | gc.TFLOAT64:
+	case OGT_ | ir.TUINT8,
+		OGT_ | ir.TUINT16,
+		OGT_ | ir.TUINT32,
+		OGT_ | ir.TUINT64,
+		OLT_ | ir.TFLOAT32,
+		OLT_ | ir.TFLOAT64:
 		a = x86.AJHI
 
-	case OGE_ | gc.TINT8,
-		OGE_ | gc.TINT16,
-		OGE_ | gc.TINT32,
-		OGE_ | gc.TINT64:
+	case OGE_ | ir.TINT8,
+		OGE_ | ir.TINT16,
+		OGE_ | ir.TINT32,
+		OGE_ | ir.TINT64:
 		a = x86.AJGE
 
-	case OGE_ | gc.TUINT8,
-		OGE_ | gc.TUINT16,
-		OGE_ | gc.TUINT32,
-		OGE_ | gc.TUINT64,
-		OLE_ | gc.TFLOAT32,
-		OLE_ | gc.TFLOAT64:
+	case OGE_ | ir.TUINT8,
+		OGE_ | ir.TUINT16,
+		OGE_ | ir.TUINT32,
+		OGE_ | ir.TUINT64,
+		OLE_ | ir.TFLOAT32,
+		OLE_ | ir.TFLOAT64:
 		a = x86.AJCC
 
-	case OCMP_ | gc.TBOOL,
-		OCMP_ | gc.TINT8,
-		OCMP_ | gc.TUINT8:
+	case OCMP_ | ir.TBOOL,
+		OCMP_ | ir.TINT8,
+		OCMP_ | ir.TUINT8:
 		a = x86.ACMPB
 
-	case OCMP_ | gc.TINT16,
-		OCMP_ | gc.TUINT16:
+	case OCMP_ | ir.TINT16,
+		OCMP_ | ir.TUINT16:
 		a = x86.ACMPW
 
-	case OCMP_ | gc.TINT32,
-		OCMP_ | gc.TUINT32,
-		OCMP_ | gc.TPTR32:
+	case OCMP_ | ir.TINT32,
+		OCMP_ | ir.TUINT32,
+		OCMP_ | ir.TPTR32:
 		a = x86.ACMPL
 
-	case OCMP_ | gc.TINT64,
-		OCMP_ | gc.TUINT64,
-		OCMP_ | gc.TPTR64:
+	case OCMP_ | ir.TINT64,
+		OCMP_ | ir.TUINT64,
+		OCMP_ | ir.TPTR64:
 		a = x86.ACMPQ
 
-	case OCMP_ | gc.TFLOAT32:
+	case OCMP_ | ir.TFLOAT32:
 		a = x86.AUCOMISS
 
-	case OCMP_ | gc.TFLOAT64:
+	case OCMP_ | ir.TFLOAT64:
 		a = x86.AUCOMISD
 
-	case OAS_ | gc.TBOOL,
-		OAS_ | gc.TINT8,
-		OAS_ | gc.TUINT8:
+	case OAS_ | ir.TBOOL,
+		OAS_ | ir.TINT8,
+		OAS_ | ir.TUINT8:
 		a = x86.AMOVB
 
-	case OAS_ | gc.TINT16,
-		OAS_ | gc.TUINT16:
+	case OAS_ | ir.TINT16,
+		OAS_ | ir.TUINT16:
 		a = x86.AMOVW
 
-	case OAS_ | gc.TINT32,
-		OAS_ | gc.TUINT32,
-		OAS_ | gc.TPTR32:
+	case OAS_ | ir.TINT32,
+		OAS_ | ir.TUINT32,
+		OAS_ | ir.TPTR32:
 		a = x86.AMOVL
 
-	case OAS_ | gc.TINT64,
-		OAS_ | gc.TUINT64,
-		OAS_ | gc.TPTR64:
+	case OAS_ | ir.TINT64,
+		OAS_ | ir.TUINT64,
+		OAS_ | ir.TPTR64:
 		a = x86.AMOVQ
 
-	case OAS_ | gc.TFLOAT32:
+	case OAS_ | ir.TFLOAT32:
 		a = x86.AMOVSS
 
-	case OAS_ | gc.TFLOAT64:
+	case OAS_ | ir.TFLOAT64:
 		a = x86.AMOVSD
 
-	case OADD_ | gc.TINT8,
-		OADD_ | gc.TUINT8:
+	case OADD_ | ir.TINT8,
+		OADD_ | ir.TUINT8:
 		a = x86.AADDB
 
-	case OADD_ | gc.TINT16,
-		OADD_ | gc.TUINT16:
+	case OADD_ | ir.TINT16,
+		OADD_ | ir.TUINT16:
 		a = x86.AADDW
 
-	case OADD_ | gc.TINT32,
-		OADD_ | gc.TUINT32,
-		OADD_ | gc.TPTR32:
+	case OADD_ | ir.TINT32,
+		OADD_ | ir.TUINT32,
+		OADD_ | ir.TPTR32:
 		a = x86.AADDL
 
-	case OADD_ | gc.TINT64,
-		OADD_ | gc.TUINT64,
-		OADD_ | gc.TPTR64:
+	case OADD_ | ir.TINT64,
+		OADD_ | ir.TUINT64,
+		OADD_ | ir.TPTR64:
 		a = x86.AADDQ
 
-	case OADD_ | gc.TFLOAT32:
+	case OADD_ | ir.TFLOAT32:
 		a = x86.AADDSS
 
-	case OADD_ | gc.TFLOAT64:
+	case OADD_ | ir.TFLOAT64:
 		a = x86.AADDSD
 
-	case OSUB_ | gc.TINT8,
-		OSUB_ | gc.TUINT8:
+	case OSUB_ | ir.TINT8,
+		OSUB_ | ir.TUINT8:
 		a = x86.ASUBB
 
-	case OSUB_ | gc.TINT16,
-		OSUB_ | gc.TUINT16:
+	case OSUB_ | ir.TINT16,
+		OSUB_ | ir.TUINT16:
 		a = x86.ASUBW
 
-	case OSUB_ | gc.TINT32,
-		OSUB_ | gc.TUINT32,
-		OSUB_ | gc.TPTR32:
+	case OSUB_ | ir.TINT32,
+		OSUB_ | ir.TUINT32,
+		OSUB_ | ir.TPTR32:
 		a = x86.ASUBL
 
-	case OSUB_ | gc.TINT64,
-		OSUB_ | gc.TUINT64,
-		OSUB_ | gc.TPTR64:
+	case OSUB_ | ir.TINT64,
+		OSUB_ | ir.TUINT64,
+		OSUB_ | ir.TPTR64:
 		a = x86.ASUBQ
 
-	case OSUB_ | gc.TFLOAT32:
+	case OSUB_ | ir.TFLOAT32:
 		a = x86.ASUBSS
 
-	case OSUB_ | gc.TFLOAT64:
+	case OSUB_ | ir.TFLOAT64:
 		a = x86.ASUBSD
 
-	case OINC_ | gc.TINT8,
-		OINC_ | gc.TUINT8:
+	case OINC_ | ir.TINT8,
+		OINC_ | ir.TUINT8:
 		a = x86.AINCB
 
-	case OINC_ | gc.TINT16,
-		OINC_ | gc.TUINT16:
+	case OINC_ | ir.TINT16,
+		OINC_ | ir.TUINT16:
 		a = x86.AINCW
 
-	case OINC_ | gc.TINT32,
-		OINC_ | gc.TUINT32,
-		OINC_ | gc.TPTR32:
+	case OINC_ | ir.TINT32,
+		OINC_ | ir.TUINT32,
+		OINC_ | ir.TPTR32:
 		a = x86.AINCL
 
-	case OINC_ | gc.TINT64,
-		OINC_ | gc.TUINT64,
-		OINC_ | gc.TPTR64:
+	case OINC_ | ir.TINT64,
+		OINC_ | ir.TUINT64,
+		OINC_ | ir.TPTR64:
 		a = x86.AINCQ
 
-	case ODEC_ | gc.TINT8,
-		ODEC_ | gc.TUINT8:
+	case ODEC_ | ir.TINT8,
+		ODEC_ | ir.TUINT8:
 		a = x86.ADECB
 
-	case ODEC_ | gc.TINT16,
-		ODEC_ | gc.TUINT16:
+	case ODEC_ | ir.TINT16,
+		ODEC_ | ir.TUINT16:
 		a = x86.ADECW
 
-	case ODEC_ | gc.TINT32,
-		ODEC_ | gc.TUINT32,
-		ODEC_ | gc.TPTR32:
+	case ODEC_ | ir.TINT32,
+		ODEC_ | ir.TUINT32,
+		ODEC_ | ir.TPTR32:
 		a = x86.ADECL
 
-	case ODEC_ | gc.TINT64,
-		ODEC_ | gc.TUINT64,
-		ODEC_ | gc.TPTR64:
+	case ODEC_ | ir.TINT64,
+		ODEC_ | ir.TUINT64,
+		ODEC_ | ir.TPTR64:
 		a = x86.ADECQ
 
-	case OMINUS_ | gc.TINT8,
-		OMINUS_ | gc.TUINT8:
+	case OMINUS_ | ir.TINT8,
+		OMINUS_ | ir.TUINT8:
 		a = x86.ANEGB
 
-	case OMINUS_ | gc.TINT16,
-		OMINUS_ | gc.TUINT16:
+	case OMINUS_ | ir.TINT16,
+		OMINUS_ | ir.TUINT16:
 		a = x86.ANEGW
 
-	case OMINUS_ | gc.TINT32,
-		OMINUS_ | gc.TUINT32,
-		OMINUS_ | gc.TPTR32:
+	case OMINUS_ | ir.TINT32,
+		OMINUS_ | ir.TUINT32,
+		OMINUS_ | ir.TPTR32:
 		a = x86.ANEGL
 
-	case OMINUS_ | gc.TINT64,
-		OMINUS_ | gc.TUINT64,
-		OMINUS_ | gc.TPTR64:
+	case OMINUS_ | ir.TINT64,
+		OMINUS_ | ir.TUINT64,
+		OMINUS_ | ir.TPTR64:
 		a = x86.ANEGQ
 
-	case OAND_ | gc.TBOOL,
-		OAND_ | gc.TINT8,
-		OAND_ | gc.TUINT8:
+	case OAND_ | ir.TBOOL,
+		OAND_ | ir.TINT8,
+		OAND_ | ir.TUINT8:
 		a = x86.AANDB
 
-	case OAND_ | gc.TINT16,
-		OAND_ | gc.TUINT16:
+	case OAND_ | ir.TINT16,
+		OAND_ | ir.TUINT16:
 		a = x86.AANDW
 
-	case OAND_ | gc.TINT32,
-		OAND_ | gc.TUINT32,
-		OAND_ | gc.TPTR32:
+	case OAND_ | ir.TINT32,
+		OAND_ | ir.TUINT32,
+		OAND_ | ir.TPTR32:
 		a = x86.AANDL
 
-	case OAND_ | gc.TINT64,
-		OAND_ | gc.TUINT64,
-		OAND_ | gc.TPTR64:
+	case OAND_ | ir.TINT64,
+		OAND_ | ir.TUINT64,
+		OAND_ | ir.TPTR64:
 		a = x86.AANDQ
 
-	case OOR_ | gc.TBOOL,
-		OOR_ | gc.TINT8,
-		OOR_ | gc.TUINT8:
+	case OOR_ | ir.TBOOL,
+		OOR_ | ir.TINT8,
+		OOR_ | ir.TUINT8:
 		a = x86.AORB
 
-	case OOR_ | gc.TINT16,
-		OOR_ | gc.TUINT16:
+	case OOR_ | ir.TINT16,
+		OOR_ | ir.TUINT16:
 		a = x86.AORW
 
-	case OOR_ | gc.TINT32,
-		OOR_ | gc.TUINT32,
-		OOR_ | gc.TPTR32:
+	case OOR_ | ir.TINT32,
+		OOR_ | ir.TUINT32,
+		OOR_ | ir.TPTR32:
 		a = x86.AORL
 
-	case OOR_ | gc.TINT64,
-		OOR_ | gc.TUINT64,
-		OOR_ | gc.TPTR64:
+	case OOR_ | ir.TINT64,
+		OOR_ | ir.TUINT64,
+		OOR_ | ir.TPTR64:
 		a = x86.AORQ
 
-	case OXOR_ | gc.TINT8,
-		OXOR_ | gc.TUINT8:
+	case OXOR_ | ir.TINT8,
+		OXOR_ | ir.TUINT8:
 		a = x86.AXORB
 
-	case OXOR_ | gc.TINT16,
-		OXOR_ | gc.TUINT16:
+	case OXOR_ | ir.TINT16,
+		OXOR_ | ir.TUINT16:
 		a = x86.AXORW
 
-	case OXOR_ | gc.TINT32,
-		OXOR_ | gc.TUINT32,
-		OXOR_ | gc.TPTR32:
+	case OXOR_ | ir.TINT32,
+		OXOR_ | ir.TUINT32,
+		OXOR_ | ir.TPTR32:
 		a = x86.AXORL
 
-	case OXOR_ | gc.TINT64,
-		OXOR_ | gc.TUINT64,
-		OXOR_ | gc.TPTR64:
+	case OXOR_ | ir.TINT64,
+		OXOR_ | ir.TUINT64,
+		OXOR_ | ir.TPTR64:
 		a = x86.AXORQ
 
-	case OLROT_ | gc.TINT8,
-		OLROT_ | gc.TUINT8:
+	case OLROT_ | ir.TINT8,
+		OLROT_ | ir.TUINT8:
 		a = x86.AROLB
 
-	case OLROT_ | gc.TINT16,
-		OLROT_ | gc.TUINT16:
+	case OLROT_ | ir.TINT16,
+		OLROT_ | ir.TUINT16:
 		a = x86.AROLW
 
-	case OLROT_ | gc.TINT32,
-		OLROT_ | gc.TUINT32,
-		OLROT_ | gc.TPTR32:
+	case OLROT_ | ir.TINT32,
+		OLROT_ | ir.TUINT32,
+		OLROT_ | ir.TPTR32:
 		a = x86.AROLL
 
-	case OLROT_ | gc.TINT64,
-		OLROT_ | gc.TUINT64,
-		OLROT_ | gc.TPTR64:
+	case OLROT_ | ir.TINT64,
+		OLROT_ | ir.TUINT64,
+		OLROT_ | ir.TPTR64:
 		a = x86.AROLQ
 
-	case OLSH_ | gc.TINT8,
-		OLSH_ | gc.TUINT8:
+	case OLSH_ | ir.TINT8,
+		OLSH_ | ir.TUINT8:
 		a = x86.ASHLB
 
-	case OLSH_ | gc.TINT16,
-		OLSH_ | gc.TUINT16:
+	case OLSH_ | ir.TINT16,
+		OLSH_ | ir.TUINT16:
 		a = x86.ASHLW
 
-	case OLSH_ | gc.TINT32,
-		OLSH_ | gc.TUINT32,
-		OLSH_ | gc.TPTR32:
+	case OLSH_ | ir.TINT32,
+		OLSH_ | ir.TUINT32,
+		OLSH_ | ir.TPTR32:
 		a = x86.ASHLL
 
-	case OLSH_ | gc.TINT64,
-		OLSH_ | gc.TUINT64,
-		OLSH_ | gc.TPTR64:
+	case OLSH_ | ir.TINT64,
+		OLSH_ | ir.TUINT64,
+		OLSH_ | ir.TPTR64:
 		a = x86.ASHLQ
 
-	case ORSH_ | gc.TUINT8:
+	case ORSH_ | ir.TUINT8:
 		a = x86.ASHRB
 
-	case ORSH_ | gc.TUINT16:
+	case ORSH_ | ir.TUINT16:
 		a = x86.ASHRW
 
-	case ORSH_ | gc.TUINT32,
-		ORSH_ | gc.TPTR32:
+	case ORSH_ | ir.TUINT32,
+		ORSH_ | ir.TPTR32:
 		a = x86.ASHRL
 
-	case ORSH_ | gc.TUINT64,
-		ORSH_ | gc.TPTR64:
+	case ORSH_ | ir.TUINT64,
+		ORSH_ | ir.TPTR64:
 		a = x86.ASHRQ
 
-	case ORSH_ | gc.TINT8:
+	case ORSH_ | ir.TINT8:
 		a = x86.ASARB
 
-	case ORSH_ | gc.TINT16:
+	case ORSH_ | ir.TINT16:
 		a = x86.ASARW
 
-	case ORSH_ | gc.TINT32:
+	case ORSH_ | ir.TINT32:
 		a = x86.ASARL
 
-	case ORSH_ | gc.TINT64:
+	case ORSH_ | ir.TINT64:
 		a = x86.ASARQ
 
-	case ORROTC_ | gc.TINT8,
-		ORROTC_ | gc.TUINT8:
+	case ORROTC_ | ir.TINT8,
+		ORROTC_ | ir.TUINT8:
 		a = x86.ARCRB
 
-	case ORROTC_ | gc.TINT16,
-		ORROTC_ | gc.TUINT16:
+	case ORROTC_ | ir.TINT16,
+		ORROTC_ | ir.TUINT16:
 		a = x86.ARCRW
 
-	case ORROTC_ | gc.TINT32,
-		ORROTC_ | gc.TUINT32:
+	case ORROTC_ | ir.TINT32,
+		ORROTC_ | ir.TUINT32:
 		a = x86.ARCRL
 
-	case ORROTC_ | gc.TINT64,
-		ORROTC_ | gc.TUINT64:
+	case ORROTC_ | ir.TINT64,
+		ORROTC_ | ir.TUINT64:
 		a = x86.ARCRQ
 
-	case OHMUL_ | gc.TINT8,
-		OMUL_ | gc.TINT8,
-		OMUL_ | gc.TUINT8:
+	case OHMUL_ | ir.TINT8,
+		OMUL_ | ir.TINT8,
+		OMUL_ | ir.TUINT8:
 		a = x86.AIMULB
 
-	case OHMUL_ | gc.TINT16,
-		OMUL_ | gc.TINT16,
-		OMUL_ | gc.TUINT16:
+	case OHMUL_ | ir.TINT16,
+		OMUL_ | ir.TINT16,
+		OMUL_ | ir.TUINT16:
 		a = x86.AIMULW
 
-	case OHMUL_ | gc.TINT32,
-		OMUL_ | gc.TINT32,
-		OMUL_ | gc.TUINT32,
-		OMUL_ | gc.TPTR32:
+	case OHMUL_ | ir.TINT32,
+		OMUL_ | ir.TINT32,
+		OMUL_ | ir.TUINT32,
+		OMUL_ | ir.TPTR32:
 		a = x86.AIMULL
 
-	case OHMUL_ | gc.TINT64,
-		OMUL_ | gc.TINT64,
-		OMUL_ | gc.TUINT64,
-		OMUL_ | gc.TPTR64:
+	case OHMUL_ | ir.TINT64,
+		OMUL_ | ir.TINT64,
+		OMUL_ | ir.TUINT64,
+		OMUL_ | ir.TPTR64:
 		a = x86.AIMULQ
 
-	case OHMUL_ | gc.TUINT8:
+	case OHMUL_ | ir.TUINT8:
 		a = x86.AMULB
 
-	case OHMUL_ | gc.TUINT16:
+	case OHMUL_ | ir.TUINT16:
 		a = x86.AMULW
 
-	case OHMUL_ | gc.TUINT32,
-		OHMUL_ | gc.TPTR32:
+	case OHMUL_ | ir.TUINT32,
+		OHMUL_ | ir.TPTR32:
 		a = x86.AMULL
 
-	case OHMUL_ | gc.TUINT64,
-		OHMUL_ | gc.TPTR64:
+	case OHMUL_ | ir.TUINT64,
+		OHMUL_ | ir.TPTR64:
 		a = x86.AMULQ
 
-	case OMUL_ | gc.TFLOAT32:
+	case OMUL_ | ir.TFLOAT32:
 		a = x86.AMULSS
 
-	case OMUL_ | gc.TFLOAT64:
+	case OMUL_ | ir.TFLOAT64:
 		a = x86.AMULSD
 
-	case ODIV_ | gc.TINT8,
-		OMOD_ | gc.TINT8:
+	case ODIV_ | ir.TINT8,
+		OMOD_ | ir.TINT8:
 		a = x86.AIDIVB
 
-	case ODIV_ | gc.TUINT8,
-		OMOD_ | gc.TUINT8:
+	case ODIV_ | ir.TUINT8,
+		OMOD_ | ir.TUINT8:
 		a = x86.ADIVB
 
-	case ODIV_ | gc.TINT16,
-		OMOD_ | gc.TINT16:
+	case ODIV_ | ir.TINT16,
+		OMOD_ | ir.TINT16:
 		a = x86.AIDIVW
 
-	case ODIV_ | gc.TUINT16,
-		OMOD_ | gc.TUINT16:
+	case ODIV_ | ir.TUINT16,
+		OMOD_ | ir.TUINT16:
 		a = x86.ADIVW
 
-	case ODIV_ | gc.TINT32,
-		OMOD_ | gc.TINT32:
+	case ODIV_ | ir.TINT32,
+		OMOD_ | ir.TINT32:
 		a = x86.AIDIVL
 
-	case ODIV_ | gc.TUINT32,
-		ODIV_ | gc.TPTR32,
-		OMOD_ | gc.TUINT32,
-		OMOD_ | gc.TPTR32:
+	case ODIV_ | ir.TUINT32,
+		ODIV_ | ir.TPTR32,
+		OMOD_ | ir.TUINT32,
+		OMOD_ | ir.TPTR32:
 		a = x86.ADIVL
 
-	case ODIV_ | gc.TINT64,
-		OMOD_ | gc.TINT64:
+	case ODIV_ | ir.TINT64,
+		OMOD_ | ir.TINT64:
 		a = x86.AIDIVQ
 
-	case ODIV_ | gc.TUINT64,
-		ODIV_ | gc.TPTR64,
-		OMOD_ | gc.TUINT64,
-		OMOD_ | gc.TPTR64:
+	case ODIV_ | ir.TUINT64,
+		ODIV_ | ir.TPTR64,
+		OMOD_ | ir.TUINT64,
+		OMOD_ | ir.TPTR64:
 		a = x86.ADIVQ
 
-	case OEXTEND_ | gc.TINT16:
+	case OEXTEND_ | ir.TINT16:
 		a = x86.ACWD
 
-	case OEXTEND_ | gc.TINT32:
+	case OEXTEND_ | ir.TINT32:
 		a = x86.ACDQ
 
-	case OEXTEND_ | gc.TINT64:
+	case OEXTEND_ | ir.TINT64:
 		a = x86.ACQO
 
-	case ODIV_ | gc.TFLOAT32:
+	case ODIV_ | ir.TFLOAT32:
 		a = x86.ADIVSS
 
-	case ODIV_ | gc.TFLOAT64:
+	case ODIV_ | ir.TFLOAT64:
 		a = x86.ADIVSD
 
-	case OSQRT_ | gc.TFLOAT64:
+	case OSQRT_ | ir.TFLOAT64:
 		a = x86.ASQRTSD
 	}
 
@@ -1273,15 +1274,15 @@ const (
 	OAddable = 1 << 1
 )
 
-var clean [20]gc.Node
+var clean [20]ir.Node
 
 var cleani int = 0
 
 func sudoclean() {
-	if clean[cleani-1].Op != gc.OEMPTY {
+	if clean[cleani-1].Op != ir.OEMPTY {
 		gc.Regfree(&clean[cleani-1])
 	}
-	if clean[cleani-2].Op != gc.OEMPTY {
+	if clean[cleani-2].Op != ir.OEMPTY {
 		gc.Regfree(&clean[cleani-2])
 	}
 	cleani -= 2
@@ -1298,7 +1299,7 @@ func sudoclean() {
  * after successful sudoaddable,
  * to release the register used for a.
  */
-func sudoaddable(as obj.As, n *gc.Node, a *obj.Addr) bool {
+func sudoaddable(as obj.As, n *ir.Node, a *obj.Addr) bool {
 	if n.Type == nil {
 		return false
 	}
@@ -1306,8 +1307,8 @@ func sudoaddable(as obj.As, n *gc.Node, a *obj.Addr) bool {
 	*a = obj.Addr{}
 
 	switch n.Op {
-	case gc.OLITERAL:
-		if !gc.Isconst(n, gc.CTINT) {
+	case ir.OLITERAL:
+		if !ir.Isconst(n, ir.CTINT) {
 			break
 		}
 		v := n.Int()
@@ -1356,19 +1357,19 @@ func sudoaddable(as obj.As, n *gc.Node, a *obj.Addr) bool {
 		cleani += 2
 		reg := &clean[cleani-1]
 		reg1 := &clean[cleani-2]
-		reg.Op = gc.OEMPTY
-		reg1.Op = gc.OEMPTY
+		reg.Op = ir.OEMPTY
+		reg1.Op = ir.OEMPTY
 		gc.Naddr(a, n)
 		return true
 
-	case gc.ODOT,
-		gc.ODOTPTR:
+	case ir.ODOT,
+		ir.ODOTPTR:
 		cleani += 2
 		reg := &clean[cleani-1]
 		reg1 := &clean[cleani-2]
-		reg.Op = gc.OEMPTY
-		reg1.Op = gc.OEMPTY
-		var nn *gc.Node
+		reg.Op = ir.OEMPTY
+		reg1.Op = ir.OEMPTY
+		var nn *ir.Node
 		var oary [10]int64
 		o := gc.Dotoffset(n, oary[:], &nn)
 		if nn == nil {
@@ -1386,9 +1387,9 @@ func sudoaddable(as obj.As, n *gc.Node, a *obj.Addr) bool {
 			return true
 		}
 
-		gc.Regalloc(reg, gc.Types[gc.Tptr], nil)
+		gc.Regalloc(reg, ir.Types[gc.Tptr], nil)
 		n1 := *reg
-		n1.Op = gc.OINDREG
+		n1.Op = ir.OINDREG
 		if oary[0] >= 0 {
 			gc.Agen(nn, reg)
 			n1.Xoffset = oary[0]
@@ -1413,7 +1414,7 @@ func sudoaddable(as obj.As, n *gc.Node, a *obj.Addr) bool {
 		gc.Naddr(a, &n1)
 		return true
 
-	case gc.OINDEX:
+	case ir.OINDEX:
 		return false
 	}
 
@@ -5,6 +5,7 @@
 package amd64
 
 import (
+	"cmd/compile/internal/ir"
 	"fmt"
 	"math"
 
@@ -828,7 +829,7 @@ func ssaGenValue(s *gc.SSAGenState, v *ssa.Value) {
 		p.From.Node = n
 		p.From.Sym = gc.Linksym(n.Sym)
 		p.From.Offset = off
-		if n.Class == gc.PPARAM || n.Class == gc.PPARAMOUT {
+		if n.Class == ir.PPARAM || n.Class == ir.PPARAMOUT {
 			p.From.Name = obj.NAME_PARAM
 			p.From.Offset += n.Xoffset
 		} else {
@@ -850,7 +851,7 @@ func ssaGenValue(s *gc.SSAGenState, v *ssa.Value) {
 		p.To.Node = n
 		p.To.Sym = gc.Linksym(n.Sym)
 		p.To.Offset = off
-		if n.Class == gc.PPARAM || n.Class == gc.PPARAMOUT {
+		if n.Class == ir.PPARAM || n.Class == ir.PPARAMOUT {
 			p.To.Name = obj.NAME_PARAM
 			p.To.Offset += n.Xoffset
 		} else {
@@ -906,7 +907,7 @@ func ssaGenValue(s *gc.SSAGenState, v *ssa.Value) {
 			q.To.Reg = r
 		}
 	case ssa.OpAMD64CALLstatic:
-		if v.Aux.(*gc.Sym) == gc.Deferreturn.Sym {
+		if v.Aux.(*ir.Sym) == gc.Deferreturn.Sym {
 			// Deferred calls will appear to be returning to
 			// the CALL deferreturn(SB) that we are about to emit.
 			// However, the stack trace code will show the line
@@ -920,7 +921,7 @@ func ssaGenValue(s *gc.SSAGenState, v *ssa.Value) {
 		p := gc.Prog(obj.ACALL)
 		p.To.Type = obj.TYPE_MEM
 		p.To.Name = obj.NAME_EXTERN
-		p.To.Sym = gc.Linksym(v.Aux.(*gc.Sym))
+		p.To.Sym = gc.Linksym(v.Aux.(*ir.Sym))
 		if gc.Maxarg < v.AuxInt {
 			gc.Maxarg = v.AuxInt
 		}
@@ -935,7 +936,7 @@ func ssaGenValue(s *gc.SSAGenState, v *ssa.Value) {
 		p := gc.Prog(obj.ACALL)
 		p.To.Type = obj.TYPE_MEM
 		p.To.Name = obj.NAME_EXTERN
-		p.To.Sym = gc.Linksym(gc.Deferproc.Sym)
+		p.To.Sym = gc.Linksym(v.Aux.(*ir.Sym))
 		if gc.Maxarg < v.AuxInt {
 			gc.Maxarg = v.AuxInt
 		}
@@ -1018,11 +1019,11 @@ func ssaGenValue(s *gc.SSAGenState, v *ssa.Value) {
 		gc.Prog(x86.AREP)
 		gc.Prog(x86.AMOVSQ)
 	case ssa.OpVarDef:
-		gc.Gvardef(v.Aux.(*gc.Node))
+		gc.Gvardef(v.Aux.(*ir.Node))
 	case ssa.OpVarKill:
-		gc.Gvarkill(v.Aux.(*gc.Node))
+		gc.Gvarkill(v.Aux.(*ir.Node))
 	case ssa.OpVarLive:
-		gc.Gvarlive(v.Aux.(*gc.Node))
+		gc.Gvarlive(v.Aux.(*ir.Node))
 	case ssa.OpAMD64LoweredNilCheck:
 		// Optimization - if the subsequent block has a load or store
 		// at the same address, we don't need to issue this instruction.
@@ -1153,7 +1154,7 @@ func ssaGenBlock(s *gc.SSAGenState, b, next *ssa.Block) {
 		p := gc.Prog(obj.AJMP)
 		p.To.Type = obj.TYPE_MEM
 		p.To.Name = obj.NAME_EXTERN
-		p.To.Sym = gc.Linksym(b.Aux.(*gc.Sym))
+		p.To.Sym = gc.Linksym(b.Aux.(*ir.Sym))
 
 	case ssa.BlockAMD64EQF:
 		gc.SSAGenFPJump(s, b, next, &eqfJumps)
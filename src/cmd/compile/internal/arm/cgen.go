@@ -6,6 +6,7 @@ package arm
 
 import (
 	"cmd/compile/internal/gc"
+	"cmd/compile/internal/ir"
 	"cmd/internal/obj"
 	"cmd/internal/obj/arm"
 )
@@ -15,17 +16,17 @@ import (
  * n might be any size; res is 32-bit.
  * returns Prog* to patch to panic call.
  */
-func cgenindex(n *gc.Node, res *gc.Node, bounded bool) *obj.Prog {
+func cgenindex(n *ir.Node, res *ir.Node, bounded bool) *obj.Prog {
 	if !gc.Is64(n.Type) {
 		gc.Cgen(n, res)
 		return nil
 	}
 
-	var tmp gc.Node
-	gc.Tempname(&tmp, gc.Types[gc.TINT64])
+	var tmp ir.Node
+	gc.Tempname(&tmp, ir.Types[ir.TINT64])
 	gc.Cgen(n, &tmp)
-	var lo gc.Node
-	var hi gc.Node
+	var lo ir.Node
+	var hi ir.Node
 	split64(&tmp, &lo, &hi)
 	gmove(&lo, res)
 	if bounded {
@@ -33,12 +34,12 @@ func cgenindex(n *gc.Node, res *gc.Node, bounded bool) *obj.Prog {
 		return nil
 	}
 
-	var n1 gc.Node
-	gc.Regalloc(&n1, gc.Types[gc.TINT32], nil)
-	var n2 gc.Node
-	gc.Regalloc(&n2, gc.Types[gc.TINT32], nil)
-	var zero gc.Node
-	gc.Nodconst(&zero, gc.Types[gc.TINT32], 0)
+	var n1 ir.Node
+	gc.Regalloc(&n1, ir.Types[ir.TINT32], nil)
+	var n2 ir.Node
+	gc.Regalloc(&n2, ir.Types[ir.TINT32], nil)
+	var zero ir.Node
+	gc.Nodconst(&zero, ir.Types[ir.TINT32], 0)
 	gmove(&hi, &n1)
 	gmove(&zero, &n2)
 	gins(arm.ACMP, &n1, &n2)
@@ -48,12 +49,12 @@ func cgenindex(n *gc.Node, res *gc.Node, bounded bool) *obj.Prog {
 	return gc.Gbranch(arm.ABNE, nil, -1)
 }
 
-func igenindex(n *gc.Node, res *gc.Node, bounded bool) *obj.Prog {
+func igenindex(n *ir.Node, res *ir.Node, bounded bool) *obj.Prog {
 	gc.Tempname(res, n.Type)
 	return cgenindex(n, res, bounded)
 }
 
-func blockcopy(n, res *gc.Node, osrc, odst, w int64) {
+func blockcopy(n, res *ir.Node, osrc, odst, w int64) {
 	// determine alignment.
 	// want to avoid unaligned access, so have to use
 	// smaller operations for less aligned types.
@@ -91,40 +92,40 @@ func blockcopy(n, res *gc.Node, osrc, odst, w int64) {
 		dir = -dir
 	}
 
-	if op == arm.AMOVW && !gc.Nacl && dir > 0 && c >= 4 && c <= 128 {
-		var r0 gc.Node
-		r0.Op = gc.OREGISTER
+	if op == arm.AMOVW && !gc.Nacl && dir > 0 && c >= int32(gc.Thearch.Duffcopy.Min) && c <= int32(gc.Thearch.Duffcopy.Max) {
+		var r0 ir.Node
+		r0.Op = ir.OREGISTER
 		r0.Reg = arm.REG_R0
-		var r1 gc.Node
-		r1.Op = gc.OREGISTER
+		var r1 ir.Node
+		r1.Op = ir.OREGISTER
 		r1.Reg = arm.REG_R0 + 1
-		var r2 gc.Node
-		r2.Op = gc.OREGISTER
+		var r2 ir.Node
+		r2.Op = ir.OREGISTER
 		r2.Reg = arm.REG_R0 + 2
 
-		var src gc.Node
-		gc.Regalloc(&src, gc.Types[gc.Tptr], &r1)
-		var dst gc.Node
-		gc.Regalloc(&dst, gc.Types[gc.Tptr], &r2)
+		var src ir.Node
+		gc.Regalloc(&src, ir.Types[gc.Tptr], &r1)
+		var dst ir.Node
+		gc.Regalloc(&dst, ir.Types[gc.Tptr], &r2)
 		if n.Ullman >= res.Ullman {
 			// eval n first
 			gc.Agen(n, &src)
 
-			if res.Op == gc.ONAME {
+			if res.Op == ir.ONAME {
 				gc.Gvardef(res)
 			}
 			gc.Agen(res, &dst)
 		} else {
 			// eval res first
-			if res.Op == gc.ONAME {
+			if res.Op == ir.ONAME {
 				gc.Gvardef(res)
 			}
 			gc.Agen(res, &dst)
 			gc.Agen(n, &src)
 		}
 
-		var tmp gc.Node
-		gc.Regalloc(&tmp, gc.Types[gc.Tptr], &r0)
+		var tmp ir.Node
+		gc.Regalloc(&tmp, ir.Types[gc.Tptr], &r0)
 		f := gc.Sysfunc("duffcopy")
 		p := gins(obj.ADUFFCOPY, nil, f)
 		gc.Afunclit(&p.To, f)
@@ -138,32 +139,32 @@ func blockcopy(n, res *gc.Node, osrc, odst, w int64) {
 		return
 	}
 
-	var dst gc.Node
-	var src gc.Node
+	var dst ir.Node
+	var src ir.Node
 	if n.Ullman >= res.Ullman {
 		gc.Agenr(n, &dst, res) // temporarily use dst
-		gc.Regalloc(&src, gc.Types[gc.Tptr], nil)
+		gc.Regalloc(&src, ir.Types[gc.Tptr], nil)
 		gins(arm.AMOVW, &dst, &src)
-		if res.Op == gc.ONAME {
+		if res.Op == ir.ONAME {
 			gc.Gvardef(res)
 		}
 		gc.Agen(res, &dst)
 	} else {
-		if res.Op == gc.ONAME {
+		if res.Op == ir.ONAME {
 			gc.Gvardef(res)
 		}
 		gc.Agenr(res, &dst, res)
 		gc.Agenr(n, &src, nil)
 	}
 
-	var tmp gc.Node
-	gc.Regalloc(&tmp, gc.Types[gc.TUINT32], nil)
+	var tmp ir.Node
+	gc.Regalloc(&tmp, ir.Types[ir.TUINT32], nil)
 
 	// set up end marker
-	var nend gc.Node
+	var nend ir.Node
 
 	if c >= 4 {
-		gc.Regalloc(&nend, gc.Types[gc.TUINT32], nil)
+		gc.Regalloc(&nend, ir.Types[ir.TUINT32], nil)
 
 		p := gins(arm.AMOVW, &src, &nend)
 		p.From.Type = obj.TYPE_ADDR
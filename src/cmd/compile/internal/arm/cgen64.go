@@ -6,6 +6,7 @@ package arm
 
 import (
 	"cmd/compile/internal/gc"
+	"cmd/compile/internal/ir"
 	"cmd/internal/obj"
 	"cmd/internal/obj/arm"
 )
@@ -15,37 +16,37 @@ import (
  *	res = n
  * return 1 on success, 0 if op not handled.
  */
-func cgen64(n *gc.Node, res *gc.Node) {
-	if res.Op != gc.OINDREG && res.Op != gc.ONAME {
+func cgen64(n *ir.Node, res *ir.Node) {
+	if res.Op != ir.OINDREG && res.Op != ir.ONAME {
 		gc.Dump("n", n)
 		gc.Dump("res", res)
 		gc.Fatalf("cgen64 %v of %v", gc.Oconv(n.Op, 0), gc.Oconv(res.Op, 0))
 	}
 
 	l := n.Left
-	var t1 gc.Node
+	var t1 ir.Node
 	if !l.Addable {
 		gc.Tempname(&t1, l.Type)
 		gc.Cgen(l, &t1)
 		l = &t1
 	}
 
-	var hi1 gc.Node
-	var lo1 gc.Node
+	var hi1 ir.Node
+	var lo1 ir.Node
 	split64(l, &lo1, &hi1)
 	switch n.Op {
 	default:
 		gc.Fatalf("cgen64 %v", gc.Oconv(n.Op, 0))
 
-	case gc.OMINUS:
-		var lo2 gc.Node
-		var hi2 gc.Node
+	case ir.OMINUS:
+		var lo2 ir.Node
+		var hi2 ir.Node
 		split64(res, &lo2, &hi2)
 
 		gc.Regalloc(&t1, lo1.Type, nil)
-		var al gc.Node
+		var al ir.Node
 		gc.Regalloc(&al, lo1.Type, nil)
-		var ah gc.Node
+		var ah ir.Node
 		gc.Regalloc(&ah, hi1.Type, nil)
 
 		gins(arm.AMOVW, &lo1, &al)
@@ -67,14 +68,14 @@ func cgen64(n *gc.Node, res *gc.Node) {
 		splitclean()
 		return
 
-	case gc.OCOM:
+	case ir.OCOM:
 		gc.Regalloc(&t1, lo1.Type, nil)
 		gmove(ncon(^uint32(0)), &t1)
 
-		var lo2 gc.Node
-		var hi2 gc.Node
+		var lo2 ir.Node
+		var hi2 ir.Node
 		split64(res, &lo2, &hi2)
-		var n1 gc.Node
+		var n1 ir.Node
 		gc.Regalloc(&n1, lo1.Type, nil)
 
 		gins(arm.AMOVW, &lo1, &n1)
@@ -93,15 +94,15 @@ func cgen64(n *gc.Node, res *gc.Node) {
 
 		// binary operators.
 	// common setup below.
-	case gc.OADD,
-		gc.OSUB,
-		gc.OMUL,
-		gc.OLSH,
-		gc.ORSH,
-		gc.OAND,
-		gc.OOR,
-		gc.OXOR,
-		gc.OLROT:
+	case ir.OADD,
+		ir.OSUB,
+		ir.OMUL,
+		ir.OLSH,
+		ir.ORSH,
+		ir.OAND,
+		ir.OOR,
+		ir.OXOR,
+		ir.OLROT:
 		break
 	}
 
@@ -109,21 +110,21 @@ func cgen64(n *gc.Node, res *gc.Node) {
 	r := n.Right
 
 	if r != nil && !r.Addable {
-		var t2 gc.Node
+		var t2 ir.Node
 		gc.Tempname(&t2, r.Type)
 		gc.Cgen(r, &t2)
 		r = &t2
 	}
 
-	var hi2 gc.Node
-	var lo2 gc.Node
+	var hi2 ir.Node
+	var lo2 ir.Node
 	if gc.Is64(r.Type) {
 		split64(r, &lo2, &hi2)
 	}
 
-	var al gc.Node
+	var al ir.Node
 	gc.Regalloc(&al, lo1.Type, nil)
-	var ah gc.Node
+	var ah ir.Node
 	gc.Regalloc(&ah, hi1.Type, nil)
 
 	// Do op. Leave result in ah:al.
@@ -132,12 +133,12 @@ func cgen64(n *gc.Node, res *gc.Node) {
 		gc.Fatalf("cgen64: not implemented: %v\n", n)
 
 		// TODO: Constants
-	case gc.OADD:
-		var bl gc.Node
-		gc.Regalloc(&bl, gc.Types[gc.TPTR32], nil)
+	case ir.OADD:
+		var bl ir.Node
+		gc.Regalloc(&bl, ir.Types[ir.TPTR32], nil)
 
-		var bh gc.Node
-		gc.Regalloc(&bh, gc.Types[gc.TPTR32], nil)
+		var bh ir.Node
+		gc.Regalloc(&bh, ir.Types[ir.TPTR32], nil)
 		gins(arm.AMOVW, &hi1, &ah)
 		gins(arm.AMOVW, &lo1, &al)
 		gins(arm.AMOVW, &hi2, &bh)
@@ -149,12 +150,12 @@ func cgen64(n *gc.Node, res *gc.Node) {
 		gc.Regfree(&bh)
 
 		// TODO: Constants.
-	case gc.OSUB:
-		var bl gc.Node
-		gc.Regalloc(&bl, gc.Types[gc.TPTR32], nil)
+	case ir.OSUB:
+		var bl ir.Node
+		gc.Regalloc(&bl, ir.Types[ir.TPTR32], nil)
 
-		var bh gc.Node
-		gc.Regalloc(&bh, gc.Types[gc.TPTR32], nil)
+		var bh ir.Node
+		gc.Regalloc(&bh, ir.Types[ir.TPTR32], nil)
 		gins(arm.AMOVW, &lo1, &al)
 		gins(arm.AMOVW, &hi1, &ah)
 		gins(arm.AMOVW, &lo2, &bl)
@@ -166,16 +167,16 @@ func cgen64(n *gc.Node, res *gc.Node) {
 		gc.Regfree(&bh)
 
 		// TODO(kaib): this can be done with 4 regs and does not need 6
-	case gc.OMUL:
-		var bl gc.Node
-		gc.Regalloc(&bl, gc.Types[gc.TPTR32], nil)
+	case ir.OMUL:
+		var bl ir.Node
+		gc.Regalloc(&bl, ir.Types[ir.TPTR32], nil)
 
-		var bh gc.Node
-		gc.Regalloc(&bh, gc.Types[gc.TPTR32], nil)
-		var cl gc.Node
-		gc.Regalloc(&cl, gc.Types[gc.TPTR32], nil)
-		var ch gc.Node
-		gc.Regalloc(&ch, gc.Types[gc.TPTR32], nil)
+		var bh ir.Node
+		gc.Regalloc(&bh, ir.Types[ir.TPTR32], nil)
+		var cl ir.Node
+		gc.Regalloc(&cl, ir.Types[ir.TPTR32], nil)
+		var ch ir.Node
+		gc.Regalloc(&ch, ir.Types[ir.TPTR32], nil)
 
 		// load args into bh:bl and bh:bl.
 		gins(arm.AMOVW, &hi1, &bh)
@@ -236,12 +237,12 @@ func cgen64(n *gc.Node, res *gc.Node) {
 	//	t = hi
 	//	shld hi:lo, c
 	//	shld lo:t, c
-	case gc.OLROT:
+	case ir.OLROT:
 		v := uint64(r.Int())
 
-		var bl gc.Node
+		var bl ir.Node
 		gc.Regalloc(&bl, lo1.Type, nil)
-		var bh gc.Node
+		var bh ir.Node
 		gc.Regalloc(&bh, hi1.Type, nil)
 		if v >= 32 {
 			// reverse during load to do the first 32 bits of rotate
@@ -273,24 +274,24 @@ func cgen64(n *gc.Node, res *gc.Node) {
 		gc.Regfree(&bl)
 		gc.Regfree(&bh)
 
-	case gc.OLSH:
-		var bl gc.Node
+	case ir.OLSH:
+		var bl ir.Node
 		gc.Regalloc(&bl, lo1.Type, nil)
-		var bh gc.Node
+		var bh ir.Node
 		gc.Regalloc(&bh, hi1.Type, nil)
 		gins(arm.AMOVW, &hi1, &bh)
 		gins(arm.AMOVW, &lo1, &bl)
 
 		var p6 *obj.Prog
-		var s gc.Node
-		var n1 gc.Node
-		var creg gc.Node
+		var s ir.Node
+		var n1 ir.Node
+		var creg ir.Node
 		var p1 *obj.Prog
 		var p2 *obj.Prog
 		var p3 *obj.Prog
 		var p4 *obj.Prog
 		var p5 *obj.Prog
-		if r.Op == gc.OLITERAL {
+		if r.Op == ir.OLITERAL {
 			v := uint64(r.Int())
 			if v >= 64 {
 				// TODO(kaib): replace with gins(AMOVW, nodintconst(0), &al)
@@ -323,12 +324,12 @@ func cgen64(n *gc.Node, res *gc.Node) {
 			goto olsh_break
 		}
 
-		gc.Regalloc(&s, gc.Types[gc.TUINT32], nil)
-		gc.Regalloc(&creg, gc.Types[gc.TUINT32], nil)
+		gc.Regalloc(&s, ir.Types[ir.TUINT32], nil)
+		gc.Regalloc(&creg, ir.Types[ir.TUINT32], nil)
 		if gc.Is64(r.Type) {
 			// shift is >= 1<<32
-			var cl gc.Node
-			var ch gc.Node
+			var cl ir.Node
+			var ch ir.Node
 			split64(r, &cl, &ch)
 
 			gmove(&ch, &s)
@@ -352,7 +353,7 @@ func cgen64(n *gc.Node, res *gc.Node) {
 		p2 = gc.Gbranch(arm.ABEQ, nil, 0)
 
 		// shift is < 32
-		gc.Nodconst(&n1, gc.Types[gc.TUINT32], 32)
+		gc.Nodconst(&n1, ir.Types[ir.TUINT32], 32)
 
 		gmove(&n1, &creg)
 		gins(arm.ACMP, &s, &creg)
@@ -389,7 +390,7 @@ func cgen64(n *gc.Node, res *gc.Node) {
 		p4 = gc.Gbranch(arm.ABEQ, nil, 0)
 
 		// shift is < 64
-		gc.Nodconst(&n1, gc.Types[gc.TUINT32], 64)
+		gc.Nodconst(&n1, ir.Types[ir.TUINT32], 64)
 
 		gmove(&n1, &creg)
 		gins(arm.ACMP, &s, &creg)
@@ -434,27 +435,27 @@ func cgen64(n *gc.Node, res *gc.Node) {
 		gc.Regfree(&bl)
 		gc.Regfree(&bh)
 
-	case gc.ORSH:
-		var bl gc.Node
+	case ir.ORSH:
+		var bl ir.Node
 		gc.Regalloc(&bl, lo1.Type, nil)
-		var bh gc.Node
+		var bh ir.Node
 		gc.Regalloc(&bh, hi1.Type, nil)
 		gins(arm.AMOVW, &hi1, &bh)
 		gins(arm.AMOVW, &lo1, &bl)
 
 		var p4 *obj.Prog
 		var p5 *obj.Prog
-		var n1 gc.Node
+		var n1 ir.Node
 		var p6 *obj.Prog
-		var s gc.Node
+		var s ir.Node
 		var p1 *obj.Prog
 		var p2 *obj.Prog
-		var creg gc.Node
+		var creg ir.Node
 		var p3 *obj.Prog
-		if r.Op == gc.OLITERAL {
+		if r.Op == ir.OLITERAL {
 			v := uint64(r.Int())
 			if v >= 64 {
-				if bh.Type.Etype == gc.TINT32 {
+				if bh.Type.Etype == ir.TINT32 {
 					//	MOVW	bh->31, al
 					gshift(arm.AMOVW, &bh, arm.SHIFT_AR, 31, &al)
 
@@ -465,7 +466,7 @@ func cgen64(n *gc.Node, res *gc.Node) {
 					gins(arm.AEOR, &ah, &ah)
 				}
 			} else if v > 32 {
-				if bh.Type.Etype == gc.TINT32 {
+				if bh.Type.Etype == ir.TINT32 {
 					//	MOVW	bh->(v-32), al
 					gshift(arm.AMOVW, &bh, arm.SHIFT_AR, int32(v-32), &al)
 
@@ -479,7 +480,7 @@ func cgen64(n *gc.Node, res *gc.Node) {
 				}
 			} else if v == 32 {
 				gins(arm.AMOVW, &bh, &al)
-				if bh.Type.Etype == gc.TINT32 {
+				if bh.Type.Etype == ir.TINT32 {
 					//	MOVW	bh->31, ah
 					gshift(arm.AMOVW, &bh, arm.SHIFT_AR, 31, &ah)
 				} else {
@@ -492,7 +493,7 @@ func cgen64(n *gc.Node, res *gc.Node) {
 				//	OR		bh<<(32-v), al
 				gshift(arm.AORR, &bh, arm.SHIFT_LL, int32(32-v), &al)
 
-				if bh.Type.Etype == gc.TINT32 {
+				if bh.Type.Etype == ir.TINT32 {
 					//	MOVW	bh->v, ah
 					gshift(arm.AMOVW, &bh, arm.SHIFT_AR, int32(v), &ah)
 				} else {
@@ -507,18 +508,18 @@ func cgen64(n *gc.Node, res *gc.Node) {
 			goto orsh_break
 		}
 
-		gc.Regalloc(&s, gc.Types[gc.TUINT32], nil)
-		gc.Regalloc(&creg, gc.Types[gc.TUINT32], nil)
+		gc.Regalloc(&s, ir.Types[ir.TUINT32], nil)
+		gc.Regalloc(&creg, ir.Types[ir.TUINT32], nil)
 		if gc.Is64(r.Type) {
 			// shift is >= 1<<32
-			var ch gc.Node
-			var cl gc.Node
+			var ch ir.Node
+			var cl ir.Node
 			split64(r, &cl, &ch)
 
 			gmove(&ch, &s)
 			gins(arm.ATST, &s, nil)
 			var p1 *obj.Prog
-			if bh.Type.Etype == gc.TINT32 {
+			if bh.Type.Etype == ir.TINT32 {
 				p1 = gshift(arm.AMOVW, &bh, arm.SHIFT_AR, 31, &ah)
 			} else {
 				p1 = gins(arm.AEOR, &ah, &ah)
@@ -543,7 +544,7 @@ func cgen64(n *gc.Node, res *gc.Node) {
 		p2 = gc.Gbranch(arm.ABEQ, nil, 0)
 
 		// check if shift is < 32
-		gc.Nodconst(&n1, gc.Types[gc.TUINT32], 32)
+		gc.Nodconst(&n1, ir.Types[ir.TUINT32], 32)
 
 		gmove(&n1, &creg)
 		gins(arm.ACMP, &s, &creg)
@@ -563,7 +564,7 @@ func cgen64(n *gc.Node, res *gc.Node) {
 
 		p1.Scond = arm.C_SCOND_LO
 
-		if bh.Type.Etype == gc.TINT32 {
+		if bh.Type.Etype == ir.TINT32 {
 			//	MOVW	bh->s, ah
 			p1 = gregshift(arm.AMOVW, &bh, arm.SHIFT_AR, &s, &ah)
 		} else {
@@ -580,7 +581,7 @@ func cgen64(n *gc.Node, res *gc.Node) {
 		p1 = gins(arm.AMOVW, &bh, &al)
 
 		p1.Scond = arm.C_SCOND_EQ
-		if bh.Type.Etype == gc.TINT32 {
+		if bh.Type.Etype == ir.TINT32 {
 			gshift(arm.AMOVW, &bh, arm.SHIFT_AR, 31, &ah)
 		} else {
 			gins(arm.AEOR, &ah, &ah)
@@ -588,7 +589,7 @@ func cgen64(n *gc.Node, res *gc.Node) {
 		p4 = gc.Gbranch(arm.ABEQ, nil, 0)
 
 		// check if shift is < 64
-		gc.Nodconst(&n1, gc.Types[gc.TUINT32], 64)
+		gc.Nodconst(&n1, ir.Types[ir.TUINT32], 64)
 
 		gmove(&n1, &creg)
 		gins(arm.ACMP, &s, &creg)
@@ -603,7 +604,7 @@ func cgen64(n *gc.Node, res *gc.Node) {
 
 		p1.Scond = arm.C_SCOND_LO
 
-		if bh.Type.Etype == gc.TINT32 {
+		if bh.Type.Etype == ir.TINT32 {
 			//	MOVW	bh->(s-32), al
 			p1 := gregshift(arm.AMOVW, &bh, arm.SHIFT_AR, &s, &al)
 
@@ -622,7 +623,7 @@ func cgen64(n *gc.Node, res *gc.Node) {
 		if p6 != nil {
 			gc.Patch(p6, gc.Pc)
 		}
-		if bh.Type.Etype == gc.TINT32 {
+		if bh.Type.Etype == ir.TINT32 {
 			//	MOVW	bh->31, al
 			gshift(arm.AMOVW, &bh, arm.SHIFT_AR, 31, &al)
 		} else {
@@ -732,10 +733,10 @@ func cgen64(n *gc.Node, res *gc.Node) {
 	//			splitclean();
 	//			goto out;
 	//		}
-	case gc.OXOR,
-		gc.OAND,
-		gc.OOR:
-		var n1 gc.Node
+	case ir.OXOR,
+		ir.OAND,
+		ir.OOR:
+		var n1 ir.Node
 		gc.Regalloc(&n1, lo1.Type, nil)
 
 		gins(arm.AMOVW, &lo1, &al)
@@ -767,13 +768,13 @@ func cgen64(n *gc.Node, res *gc.Node) {
  * generate comparison of nl, nr, both 64-bit.
  * nl is memory; nr is constant or memory.
  */
-func cmp64(nl *gc.Node, nr *gc.Node, op gc.Op, likely int, to *obj.Prog) {
-	var lo1 gc.Node
-	var hi1 gc.Node
-	var lo2 gc.Node
-	var hi2 gc.Node
-	var r1 gc.Node
-	var r2 gc.Node
+func cmp64(nl *ir.Node, nr *ir.Node, op ir.Op, likely int, to *obj.Prog) {
+	var lo1 ir.Node
+	var hi1 ir.Node
+	var lo2 ir.Node
+	var hi2 ir.Node
+	var r1 ir.Node
+	var r2 ir.Node
 
 	split64(nl, &lo1, &hi1)
 	split64(nr, &lo2, &hi2)
@@ -782,8 +783,8 @@ func cmp64(nl *gc.Node, nr *gc.Node, op gc.Op, likely int, to *obj.Prog) {
 	// if they differ, we're done.
 	t := hi1.Type
 
-	gc.Regalloc(&r1, gc.Types[gc.TINT32], nil)
-	gc.Regalloc(&r2, gc.Types[gc.TINT32], nil)
+	gc.Regalloc(&r1, ir.Types[ir.TINT32], nil)
+	gc.Regalloc(&r2, ir.Types[ir.TINT32], nil)
 	gins(arm.AMOVW, &hi1, &r1)
 	gins(arm.AMOVW, &hi2, &r2)
 	gins(arm.ACMP, &r1, &r2)
@@ -800,14 +801,14 @@ func cmp64(nl *gc.Node, nr *gc.Node, op gc.Op, likely int, to *obj.Prog) {
 	// cmp lo
 	// beq to
 	// L:
-	case gc.OEQ:
+	case ir.OEQ:
 		br = gc.Gbranch(arm.ABNE, nil, -likely)
 
 		// cmp hi
 	// bne to
 	// cmp lo
 	// bne to
-	case gc.ONE:
+	case ir.ONE:
 		gc.Patch(gc.Gbranch(arm.ABNE, nil, likely), to)
 
 		// cmp hi
@@ -816,11 +817,11 @@ func cmp64(nl *gc.Node, nr *gc.Node, op gc.Op, likely int, to *obj.Prog) {
 	// cmp lo
 	// bge to (or bgt to)
 	// L:
-	case gc.OGE,
-		gc.OGT:
-		gc.Patch(gc.Gbranch(optoas(gc.OGT, t), nil, likely), to)
+	case ir.OGE,
+		ir.OGT:
+		gc.Patch(gc.Gbranch(optoas(ir.OGT, t), nil, likely), to)
 
-		br = gc.Gbranch(optoas(gc.OLT, t), nil, -likely)
+		br = gc.Gbranch(optoas(ir.OLT, t), nil, -likely)
 
 		// cmp hi
 	// blt to
@@ -828,18 +829,18 @@ func cmp64(nl *gc.Node, nr *gc.Node, op gc.Op, likely int, to *obj.Prog) {
 	// cmp lo
 	// ble to (or jlt to)
 	// L:
-	case gc.OLE,
-		gc.OLT:
-		gc.Patch(gc.Gbranch(optoas(gc.OLT, t), nil, likely), to)
+	case ir.OLE,
+		ir.OLT:
+		gc.Patch(gc.Gbranch(optoas(ir.OLT, t), nil, likely), to)
 
-		br = gc.Gbranch(optoas(gc.OGT, t), nil, -likely)
+		br = gc.Gbranch(optoas(ir.OGT, t), nil, -likely)
 	}
 
 	// compare least significant word
 	t = lo1.Type
 
-	gc.Regalloc(&r1, gc.Types[gc.TINT32], nil)
-	gc.Regalloc(&r2, gc.Types[gc.TINT32], nil)
+	gc.Regalloc(&r1, ir.Types[ir.TINT32], nil)
+	gc.Regalloc(&r2, ir.Types[ir.TINT32], nil)
 	gins(arm.AMOVW, &lo1, &r1)
 	gins(arm.AMOVW, &lo2, &r2)
 	gins(arm.ACMP, &r1, &r2)
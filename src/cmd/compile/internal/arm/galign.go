@@ -7,70 +7,80 @@ package arm
 import (
 	"cmd/compile/internal/gc"
 	"cmd/compile/internal/ssa"
+	"cmd/internal/obj"
 	"cmd/internal/obj/arm"
 )
 
-func betypeinit() {
+func init() {
+	gc.RegisterBackend("arm", backend{})
+}
+
+// backend is the arm back end; see gc.Backend.
+type backend struct{}
+
+func (backend) TypeInit() {
 	gc.Widthptr = 4
 	gc.Widthint = 4
 	gc.Widthreg = 4
 }
 
-func Main() {
-	gc.Thearch.Thechar = '5'
-	gc.Thearch.Thestring = "arm"
-	gc.Thearch.Thelinkarch = &arm.Linkarm
-	gc.Thearch.REGSP = arm.REGSP
-	gc.Thearch.REGCTXT = arm.REGCTXT
-	gc.Thearch.REGCALLX = arm.REG_R1
-	gc.Thearch.REGCALLX2 = arm.REG_R2
-	gc.Thearch.REGRETURN = arm.REG_R0
-	gc.Thearch.REGMIN = arm.REG_R0
-	gc.Thearch.REGMAX = arm.REGEXT
-	gc.Thearch.FREGMIN = arm.REG_F0
-	gc.Thearch.FREGMAX = arm.FREGEXT
-	gc.Thearch.MAXWIDTH = (1 << 32) - 1
-	gc.Thearch.ReservedRegs = resvd
+func (backend) Init(arch *gc.Arch) {
+	arch.Thechar = '5'
+	arch.Thestring = "arm"
+	arch.Thelinkarch = &arm.Linkarm
+	arch.REGSP = arm.REGSP
+	arch.REGCTXT = arm.REGCTXT
+	arch.REGCALLX = arm.REG_R1
+	arch.REGCALLX2 = arm.REG_R2
+	arch.REGRETURN = arm.REG_R0
+	arch.REGMIN = arm.REG_R0
+	arch.REGMAX = arm.REGEXT
+	arch.FREGMIN = arm.REG_F0
+	arch.FREGMAX = arm.FREGEXT
+	arch.MAXWIDTH = (1 << 32) - 1
+	arch.ReservedRegs = resvd
+	arch.Duffzero = gc.DuffRange{Min: 4, Max: 128}
+	arch.Duffcopy = gc.DuffRange{Min: 4, Max: 128}
 
-	gc.Thearch.Betypeinit = betypeinit
-	gc.Thearch.Cgen64 = cgen64
-	gc.Thearch.Cgen_hmul = cgen_hmul
-	gc.Thearch.Cgen_shift = cgen_shift
-	gc.Thearch.Clearfat = clearfat
-	gc.Thearch.Cmp64 = cmp64
-	gc.Thearch.Defframe = defframe
-	gc.Thearch.Excise = excise
-	gc.Thearch.Expandchecks = expandchecks
-	gc.Thearch.Getg = getg
-	gc.Thearch.Gins = gins
-	gc.Thearch.Ginscmp = ginscmp
-	gc.Thearch.Ginscon = ginscon
-	gc.Thearch.Ginsnop = ginsnop
-	gc.Thearch.Gmove = gmove
-	gc.Thearch.Cgenindex = cgenindex
-	gc.Thearch.Peep = peep
-	gc.Thearch.Proginfo = proginfo
-	gc.Thearch.Regtyp = regtyp
-	gc.Thearch.Sameaddr = sameaddr
-	gc.Thearch.Smallindir = smallindir
-	gc.Thearch.Stackaddr = stackaddr
-	gc.Thearch.Blockcopy = blockcopy
-	gc.Thearch.Sudoaddable = sudoaddable
-	gc.Thearch.Sudoclean = sudoclean
-	gc.Thearch.Excludedregs = excludedregs
-	gc.Thearch.RtoB = RtoB
-	gc.Thearch.FtoB = RtoB
-	gc.Thearch.BtoR = BtoR
-	gc.Thearch.BtoF = BtoF
-	gc.Thearch.Optoas = optoas
-	gc.Thearch.Doregbits = doregbits
-	gc.Thearch.Regnames = regnames
+	arch.Cgen64 = cgen64
+	arch.Cgen_hmul = cgen_hmul
+	arch.Cgen_shift = cgen_shift
+	arch.Copyas = copyas
+	arch.Copyu = copyu
+	arch.Clearfat = clearfat
+	arch.Cmp64 = cmp64
+	arch.Expandchecks = expandchecks
+	arch.Getg = getg
+	arch.Gins = gins
+	arch.Ginscmp = ginscmp
+	arch.Ginscon = ginscon
+	arch.Ginsnop = ginsnop
+	arch.Gmove = gmove
+	arch.Cgenindex = cgenindex
+	arch.Proginfo = proginfo
+	arch.Regtyp = regtyp
+	arch.Sameaddr = sameaddr
+	arch.Smallindir = smallindir
+	arch.Stackaddr = stackaddr
+	arch.Blockcopy = blockcopy
+	arch.Sudoaddable = sudoaddable
+	arch.Sudoclean = sudoclean
+	arch.Excludedregs = excludedregs
+	arch.RtoB = RtoB
+	arch.FtoB = RtoB
+	arch.BtoR = BtoR
+	arch.BtoF = BtoF
+	arch.Optoas = optoas
+	arch.Doregbits = doregbits
+	arch.Regnames = regnames
+	arch.ZeroRange = zerorange
 
-	gc.Thearch.SSARegToReg = ssaRegToReg
-	gc.Thearch.SSAMarkMoves = func(s *gc.SSAGenState, b *ssa.Block) {}
-	gc.Thearch.SSAGenValue = ssaGenValue
-	gc.Thearch.SSAGenBlock = ssaGenBlock
+	arch.SSARegToReg = ssaRegToReg
+	arch.SSAMarkMoves = func(s *gc.SSAGenState, b *ssa.Block) {}
+	arch.SSAGenValue = ssaGenValue
+	arch.SSAGenBlock = ssaGenBlock
+}
 
-	gc.Main()
-	gc.Exit(0)
+func (backend) Peep(firstp *obj.Prog) {
+	peep(firstp)
 }
@@ -32,6 +32,7 @@ package arm
 
 import (
 	"cmd/compile/internal/gc"
+	"cmd/compile/internal/ir"
 	"cmd/internal/obj"
 	"cmd/internal/obj/arm"
 	"fmt"
@@ -47,24 +48,24 @@ var resvd = []int{
  * overwritten by next call, but useful in calls to gins.
  */
 
-var ncon_n gc.Node
+var ncon_n ir.Node
 
-func ncon(i uint32) *gc.Node {
+func ncon(i uint32) *ir.Node {
 	if ncon_n.Type == nil {
-		gc.Nodconst(&ncon_n, gc.Types[gc.TUINT32], 0)
+		gc.Nodconst(&ncon_n, ir.Types[ir.TUINT32], 0)
 	}
 	ncon_n.SetInt(int64(i))
 	return &ncon_n
 }
 
-var sclean [10]gc.Node
+var sclean [10]ir.Node
 
 var nsclean int
 
 /*
  * n is a 64-bit value.  fill in lo and hi to refer to its 32-bit halves.
  */
-func split64(n *gc.Node, lo *gc.Node, hi *gc.Node) {
+func split64(n *ir.Node, lo *ir.Node, hi *ir.Node) {
 	if !gc.Is64(n.Type) {
 		gc.Fatalf("split64 %v", n.Type)
 	}
@@ -72,13 +73,13 @@ func split64(n *gc.Node, lo *gc.Node, hi *gc.Node) {
 	if nsclean >= len(sclean) {
 		gc.Fatalf("split64 clean")
 	}
-	sclean[nsclean].Op = gc.OEMPTY
+	sclean[nsclean].Op = ir.OEMPTY
 	nsclean++
 	switch n.Op {
 	default:
 		switch n.Op {
 		default:
-			var n1 gc.Node
+			var n1 ir.Node
 			if !dotaddable(n, &n1) {
 				gc.Igen(n, &n1, nil)
 				sclean[nsclean-1] = n1
@@ -86,39 +87,39 @@ func split64(n *gc.Node, lo *gc.Node, hi *gc.Node) {
 
 			n = &n1
 
-		case gc.ONAME:
-			if n.Class == gc.PPARAMREF {
-				var n1 gc.Node
+		case ir.ONAME:
+			if n.Class == ir.PPARAMREF {
+				var n1 ir.Node
 				gc.Cgen(n.Name.Heapaddr, &n1)
 				sclean[nsclean-1] = n1
 				n = &n1
 			}
 
 			// nothing
-		case gc.OINDREG:
+		case ir.OINDREG:
 			break
 		}
 
 		*lo = *n
 		*hi = *n
-		lo.Type = gc.Types[gc.TUINT32]
-		if n.Type.Etype == gc.TINT64 {
-			hi.Type = gc.Types[gc.TINT32]
+		lo.Type = ir.Types[ir.TUINT32]
+		if n.Type.Etype == ir.TINT64 {
+			hi.Type = ir.Types[ir.TINT32]
 		} else {
-			hi.Type = gc.Types[gc.TUINT32]
+			hi.Type = ir.Types[ir.TUINT32]
 		}
 		hi.Xoffset += 4
 
-	case gc.OLITERAL:
-		var n1 gc.Node
-		n.Convconst(&n1, n.Type)
+	case ir.OLITERAL:
+		var n1 ir.Node
+		gc.Convconst(n, &n1, n.Type)
 		i := n1.Int()
-		gc.Nodconst(lo, gc.Types[gc.TUINT32], int64(uint32(i)))
+		gc.Nodconst(lo, ir.Types[ir.TUINT32], int64(uint32(i)))
 		i >>= 32
-		if n.Type.Etype == gc.TINT64 {
-			gc.Nodconst(hi, gc.Types[gc.TINT32], int64(int32(i)))
+		if n.Type.Etype == ir.TINT64 {
+			gc.Nodconst(hi, ir.Types[ir.TINT32], int64(int32(i)))
 		} else {
-			gc.Nodconst(hi, gc.Types[gc.TUINT32], int64(uint32(i)))
+			gc.Nodconst(hi, ir.Types[ir.TUINT32], int64(uint32(i)))
 		}
 	}
 }
@@ -128,12 +129,12 @@ func splitclean() {
 		gc.Fatalf("splitclean")
 	}
 	nsclean--
-	if sclean[nsclean].Op != gc.OEMPTY {
+	if sclean[nsclean].Op != ir.OEMPTY {
 		gc.Regfree(&sclean[nsclean])
 	}
 }
 
-func gmove(f *gc.Node, t *gc.Node) {
+func gmove(f *ir.Node, t *ir.Node) {
 	if gc.Debug['M'] != 0 {
 		fmt.Printf("gmove %v -> %v\n", f, t)
 	}
@@ -150,34 +151,34 @@ func gmove(f *gc.Node, t *gc.Node) {
 	// cannot have two memory operands;
 	// except 64-bit, which always copies via registers anyway.
 	var a obj.As
-	var r1 gc.Node
+	var r1 ir.Node
 	if !gc.Is64(f.Type) && !gc.Is64(t.Type) && gc.Ismem(f) && gc.Ismem(t) {
 		goto hard
 	}
 
 	// convert constant to desired type
-	if f.Op == gc.OLITERAL {
-		var con gc.Node
+	if f.Op == ir.OLITERAL {
+		var con ir.Node
 		switch tt {
 		default:
-			f.Convconst(&con, t.Type)
+			gc.Convconst(f, &con, t.Type)
 
-		case gc.TINT16,
-			gc.TINT8:
-			var con gc.Node
-			f.Convconst(&con, gc.Types[gc.TINT32])
-			var r1 gc.Node
+		case ir.TINT16,
+			ir.TINT8:
+			var con ir.Node
+			gc.Convconst(f, &con, ir.Types[ir.TINT32])
+			var r1 ir.Node
 			gc.Regalloc(&r1, con.Type, t)
 			gins(arm.AMOVW, &con, &r1)
 			gmove(&r1, t)
 			gc.Regfree(&r1)
 			return
 
-		case gc.TUINT16,
-			gc.TUINT8:
-			var con gc.Node
-			f.Convconst(&con, gc.Types[gc.TUINT32])
-			var r1 gc.Node
+		case ir.TUINT16,
+			ir.TUINT8:
+			var con ir.Node
+			gc.Convconst(f, &con, ir.Types[ir.TUINT32])
+			var r1 ir.Node
 			gc.Regalloc(&r1, con.Type, t)
 			gins(arm.AMOVW, &con, &r1)
 			gmove(&r1, t)
@@ -210,95 +211,95 @@ func gmove(f *gc.Node, t *gc.Node) {
 		/*
 		 * integer copy and truncate
 		 */
-	case gc.TINT8<<16 | gc.TINT8: // same size
+	case ir.TINT8<<16 | ir.TINT8: // same size
 		if !gc.Ismem(f) {
 			a = arm.AMOVB
 			break
 		}
 		fallthrough
 
-	case gc.TUINT8<<16 | gc.TINT8,
-		gc.TINT16<<16 | gc.TINT8, // truncate
-		gc.TUINT16<<16 | gc.TINT8,
-		gc.TINT32<<16 | gc.TINT8,
-		gc.TUINT32<<16 | gc.TINT8:
+	case ir.TUINT8<<16 | ir.TINT8,
+		ir.TINT16<<16 | ir.TINT8, // truncate
+		ir.TUINT16<<16 | ir.TINT8,
+		ir.TINT32<<16 | ir.TINT8,
+		ir.TUINT32<<16 | ir.TINT8:
 		a = arm.AMOVBS
 
-	case gc.TUINT8<<16 | gc.TUINT8:
+	case ir.TUINT8<<16 | ir.TUINT8:
 		if !gc.Ismem(f) {
 			a = arm.AMOVB
 			break
 		}
 		fallthrough
 
-	case gc.TINT8<<16 | gc.TUINT8,
-		gc.TINT16<<16 | gc.TUINT8,
-		gc.TUINT16<<16 | gc.TUINT8,
-		gc.TINT32<<16 | gc.TUINT8,
-		gc.TUINT32<<16 | gc.TUINT8:
+	case ir.TINT8<<16 | ir.TUINT8,
+		ir.TINT16<<16 | ir.TUINT8,
+		ir.TUINT16<<16 | ir.TUINT8,
+		ir.TINT32<<16 | ir.TUINT8,
+		ir.TUINT32<<16 | ir.TUINT8:
 		a = arm.AMOVBU
 
-	case gc.TINT64<<16 | gc.TINT8, // truncate low word
-		gc.TUINT64<<16 | gc.TINT8:
+	case ir.TINT64<<16 | ir.TINT8, // truncate low word
+		ir.TUINT64<<16 | ir.TINT8:
 		a = arm.AMOVBS
 
 		goto trunc64
 
-	case gc.TINT64<<16 | gc.TUINT8,
-		gc.TUINT64<<16 | gc.TUINT8:
+	case ir.TINT64<<16 | ir.TUINT8,
+		ir.TUINT64<<16 | ir.TUINT8:
 		a = arm.AMOVBU
 		goto trunc64
 
-	case gc.TINT16<<16 | gc.TINT16: // same size
+	case ir.TINT16<<16 | ir.TINT16: // same size
 		if !gc.Ismem(f) {
 			a = arm.AMOVH
 			break
 		}
 		fallthrough
 
-	case gc.TUINT16<<16 | gc.TINT16,
-		gc.TINT32<<16 | gc.TINT16, // truncate
-		gc.TUINT32<<16 | gc.TINT16:
+	case ir.TUINT16<<16 | ir.TINT16,
+		ir.TINT32<<16 | ir.TINT16, // truncate
+		ir.TUINT32<<16 | ir.TINT16:
 		a = arm.AMOVHS
 
-	case gc.TUINT16<<16 | gc.TUINT16:
+	case ir.TUINT16<<16 | ir.TUINT16:
 		if !gc.Ismem(f) {
 			a = arm.AMOVH
 			break
 		}
 		fallthrough
 
-	case gc.TINT16<<16 | gc.TUINT16,
-		gc.TINT32<<16 | gc.TUINT16,
-		gc.TUINT32<<16 | gc.TUINT16:
+	case ir.TINT16<<16 | ir.TUINT16,
+		ir.TINT32<<16 | ir.TUINT16,
+		ir.TUINT32<<16 | ir.TUINT16:
 		a = arm.AMOVHU
 
-	case gc.TINT64<<16 | gc.TINT16, // truncate low word
-		gc.TUINT64<<16 | gc.TINT16:
+	case ir.TINT64<<16 | ir.TINT16, // truncate low word
+		ir.TUINT64<<16 | ir.TINT16:
 		a = arm.AMOVHS
 
 		goto trunc64
 
-	case gc.TINT64<<16 | gc.TUINT16,
-		gc.TUINT64<<16 | gc.TUINT16:
+	case ir.TINT64<<16 | ir.TUINT16,
+		ir.TUINT64<<16 | ir.TUINT16:
 		a = arm.AMOVHU
 		goto trunc64
 
-	case gc.TINT32<<16 | gc.TINT32, // same size
-		gc.TINT32<<16 | gc.TUINT32,
-		gc.TUINT32<<16 | gc.TINT32,
-		gc.TUINT32<<16 | gc.TUINT32:
+	case ir.TINT32<<16 | ir.TINT32, // same size
+		ir.TINT32<<16 | ir.TUINT32,
+		ir.TUINT32<<16 | ir.TINT32,
+		ir.TUINT32<<16 | ir.TUINT32:
 		a = arm.AMOVW
 
-	case gc.TINT64<<16 | gc.TINT32, // truncate
-		gc.TUINT64<<16 | gc.TINT32,
-		gc.TINT64<<16 | gc.TUINT32,
-		gc.TUINT64<<16 | gc.TUINT32:
-		var flo gc.Node
-		var fhi gc.Node
+	case ir.TINT64<<16 | ir.TINT32, // truncate
+		ir.TUINT64<<16 | ir.TINT32,
+		ir.TINT64<<16 | ir.TUINT32,
+		ir.TUINT64<<16 | ir.TUINT32:
+		var flo ir.Node
+		var fhi ir.Node
 		split64(f, &flo, &fhi)
 
-		var r1 gc.Node
+		var r1 ir.Node
 		gc.Regalloc(&r1, t.Type, nil)
 		gins(arm.AMOVW, &flo, &r1)
 		gins(arm.AMOVW, &r1, t)
@@ -306,20 +307,20 @@ func gmove(f *gc.Node, t *gc.Node) {
 		splitclean()
 		return
 
-	case gc.TINT64<<16 | gc.TINT64, // same size
-		gc.TINT64<<16 | gc.TUINT64,
-		gc.TUINT64<<16 | gc.TINT64,
-		gc.TUINT64<<16 | gc.TUINT64:
-		var fhi gc.Node
-		var flo gc.Node
+	case ir.TINT64<<16 | ir.TINT64, // same size
+		ir.TINT64<<16 | ir.TUINT64,
+		ir.TUINT64<<16 | ir.TINT64,
+		ir.TUINT64<<16 | ir.TUINT64:
+		var fhi ir.Node
+		var flo ir.Node
 		split64(f, &flo, &fhi)
 
-		var tlo gc.Node
-		var thi gc.Node
+		var tlo ir.Node
+		var thi ir.Node
 		split64(t, &tlo, &thi)
-		var r1 gc.Node
+		var r1 ir.Node
 		gc.Regalloc(&r1, flo.Type, nil)
-		var r2 gc.Node
+		var r2 ir.Node
 		gc.Regalloc(&r2, fhi.Type, nil)
 		gins(arm.AMOVW, &flo, &r1)
 		gins(arm.AMOVW, &fhi, &r2)
@@ -334,67 +335,67 @@ func gmove(f *gc.Node, t *gc.Node) {
 		/*
 		 * integer up-conversions
 		 */
-	case gc.TINT8<<16 | gc.TINT16, // sign extend int8
-		gc.TINT8<<16 | gc.TUINT16,
-		gc.TINT8<<16 | gc.TINT32,
-		gc.TINT8<<16 | gc.TUINT32:
+	case ir.TINT8<<16 | ir.TINT16, // sign extend int8
+		ir.TINT8<<16 | ir.TUINT16,
+		ir.TINT8<<16 | ir.TINT32,
+		ir.TINT8<<16 | ir.TUINT32:
 		a = arm.AMOVBS
 
 		goto rdst
 
-	case gc.TINT8<<16 | gc.TINT64, // convert via int32
-		gc.TINT8<<16 | gc.TUINT64:
-		cvt = gc.Types[gc.TINT32]
+	case ir.TINT8<<16 | ir.TINT64, // convert via int32
+		ir.TINT8<<16 | ir.TUINT64:
+		cvt = ir.Types[ir.TINT32]
 
 		goto hard
 
-	case gc.TUINT8<<16 | gc.TINT16, // zero extend uint8
-		gc.TUINT8<<16 | gc.TUINT16,
-		gc.TUINT8<<16 | gc.TINT32,
-		gc.TUINT8<<16 | gc.TUINT32:
+	case ir.TUINT8<<16 | ir.TINT16, // zero extend uint8
+		ir.TUINT8<<16 | ir.TUINT16,
+		ir.TUINT8<<16 | ir.TINT32,
+		ir.TUINT8<<16 | ir.TUINT32:
 		a = arm.AMOVBU
 
 		goto rdst
 
-	case gc.TUINT8<<16 | gc.TINT64, // convert via uint32
-		gc.TUINT8<<16 | gc.TUINT64:
-		cvt = gc.Types[gc.TUINT32]
+	case ir.TUINT8<<16 | ir.TINT64, // convert via uint32
+		ir.TUINT8<<16 | ir.TUINT64:
+		cvt = ir.Types[ir.TUINT32]
 
 		goto hard
 
-	case gc.TINT16<<16 | gc.TINT32, // sign extend int16
-		gc.TINT16<<16 | gc.TUINT32:
+	case ir.TINT16<<16 | ir.TINT32, // sign extend int16
+		ir.TINT16<<16 | ir.TUINT32:
 		a = arm.AMOVHS
 
 		goto rdst
 
-	case gc.TINT16<<16 | gc.TINT64, // convert via int32
-		gc.TINT16<<16 | gc.TUINT64:
-		cvt = gc.Types[gc.TINT32]
+	case ir.TINT16<<16 | ir.TINT64, // convert via int32
+		ir.TINT16<<16 | ir.TUINT64:
+		cvt = ir.Types[ir.TINT32]
 
 		goto hard
 
-	case gc.TUINT16<<16 | gc.TINT32, // zero extend uint16
-		gc.TUINT16<<16 | gc.TUINT32:
+	case ir.TUINT16<<16 | ir.TINT32, // zero extend uint16
+		ir.TUINT16<<16 | ir.TUINT32:
 		a = arm.AMOVHU
 
 		goto rdst
 
-	case gc.TUINT16<<16 | gc.TINT64, // convert via uint32
-		gc.TUINT16<<16 | gc.TUINT64:
-		cvt = gc.Types[gc.TUINT32]
+	case ir.TUINT16<<16 | ir.TINT64, // convert via uint32
+		ir.TUINT16<<16 | ir.TUINT64:
+		cvt = ir.Types[ir.TUINT32]
 
 		goto hard
 
-	case gc.TINT32<<16 | gc.TINT64, // sign extend int32
-		gc.TINT32<<16 | gc.TUINT64:
-		var tlo gc.Node
-		var thi gc.Node
+	case ir.TINT32<<16 | ir.TINT64, // sign extend int32
+		ir.TINT32<<16 | ir.TUINT64:
+		var tlo ir.Node
+		var thi ir.Node
 		split64(t, &tlo, &thi)
 
-		var r1 gc.Node
+		var r1 ir.Node
 		gc.Regalloc(&r1, tlo.Type, nil)
-		var r2 gc.Node
+		var r2 ir.Node
 		gc.Regalloc(&r2, thi.Type, nil)
 		gmove(f, &r1)
 		p1 := gins(arm.AMOVW, &r1, &r2)
@@ -411,14 +412,14 @@ func gmove(f *gc.Node, t *gc.Node) {
 		splitclean()
 		return
 
-	case gc.TUINT32<<16 | gc.TINT64, // zero extend uint32
-		gc.TUINT32<<16 | gc.TUINT64:
-		var thi gc.Node
-		var tlo gc.Node
+	case ir.TUINT32<<16 | ir.TINT64, // zero extend uint32
+		ir.TUINT32<<16 | ir.TUINT64:
+		var thi ir.Node
+		var tlo ir.Node
 		split64(t, &tlo, &thi)
 
 		gmove(f, &tlo)
-		var r1 gc.Node
+		var r1 ir.Node
 		gc.Regalloc(&r1, thi.Type, nil)
 		gins(arm.AMOVW, ncon(0), &r1)
 		gins(arm.AMOVW, &r1, &thi)
@@ -430,54 +431,54 @@ func gmove(f *gc.Node, t *gc.Node) {
 	/*
 	* float to integer
 	 */
-	case gc.TFLOAT32<<16 | gc.TINT8,
-		gc.TFLOAT32<<16 | gc.TUINT8,
-		gc.TFLOAT32<<16 | gc.TINT16,
-		gc.TFLOAT32<<16 | gc.TUINT16,
-		gc.TFLOAT32<<16 | gc.TINT32,
-		gc.TFLOAT32<<16 | gc.TUINT32,
+	case ir.TFLOAT32<<16 | ir.TINT8,
+		ir.TFLOAT32<<16 | ir.TUINT8,
+		ir.TFLOAT32<<16 | ir.TINT16,
+		ir.TFLOAT32<<16 | ir.TUINT16,
+		ir.TFLOAT32<<16 | ir.TINT32,
+		ir.TFLOAT32<<16 | ir.TUINT32,
 
 		//	case CASE(TFLOAT32, TUINT64):
 
-		gc.TFLOAT64<<16 | gc.TINT8,
-		gc.TFLOAT64<<16 | gc.TUINT8,
-		gc.TFLOAT64<<16 | gc.TINT16,
-		gc.TFLOAT64<<16 | gc.TUINT16,
-		gc.TFLOAT64<<16 | gc.TINT32,
-		gc.TFLOAT64<<16 | gc.TUINT32:
+		ir.TFLOAT64<<16 | ir.TINT8,
+		ir.TFLOAT64<<16 | ir.TUINT8,
+		ir.TFLOAT64<<16 | ir.TINT16,
+		ir.TFLOAT64<<16 | ir.TUINT16,
+		ir.TFLOAT64<<16 | ir.TINT32,
+		ir.TFLOAT64<<16 | ir.TUINT32:
 		fa := arm.AMOVF
 
 		a := arm.AMOVFW
-		if ft == gc.TFLOAT64 {
+		if ft == ir.TFLOAT64 {
 			fa = arm.AMOVD
 			a = arm.AMOVDW
 		}
 
 		ta := arm.AMOVW
 		switch tt {
-		case gc.TINT8:
+		case ir.TINT8:
 			ta = arm.AMOVBS
 
-		case gc.TUINT8:
+		case ir.TUINT8:
 			ta = arm.AMOVBU
 
-		case gc.TINT16:
+		case ir.TINT16:
 			ta = arm.AMOVHS
 
-		case gc.TUINT16:
+		case ir.TUINT16:
 			ta = arm.AMOVHU
 		}
 
-		var r1 gc.Node
-		gc.Regalloc(&r1, gc.Types[ft], f)
-		var r2 gc.Node
-		gc.Regalloc(&r2, gc.Types[tt], t)
+		var r1 ir.Node
+		gc.Regalloc(&r1, ir.Types[ft], f)
+		var r2 ir.Node
+		gc.Regalloc(&r2, ir.Types[tt], t)
 		gins(fa, f, &r1)        // load to fpu
 		p1 := gins(a, &r1, &r1) // convert to w
 		switch tt {
-		case gc.TUINT8,
-			gc.TUINT16,
-			gc.TUINT32:
+		case ir.TUINT8,
+			ir.TUINT16,
+			ir.TUINT32:
 			p1.Scond |= arm.C_UBIT
 		}
 
@@ -490,52 +491,52 @@ func gmove(f *gc.Node, t *gc.Node) {
 		/*
 		 * integer to float
 		 */
-	case gc.TINT8<<16 | gc.TFLOAT32,
-		gc.TUINT8<<16 | gc.TFLOAT32,
-		gc.TINT16<<16 | gc.TFLOAT32,
-		gc.TUINT16<<16 | gc.TFLOAT32,
-		gc.TINT32<<16 | gc.TFLOAT32,
-		gc.TUINT32<<16 | gc.TFLOAT32,
-		gc.TINT8<<16 | gc.TFLOAT64,
-		gc.TUINT8<<16 | gc.TFLOAT64,
-		gc.TINT16<<16 | gc.TFLOAT64,
-		gc.TUINT16<<16 | gc.TFLOAT64,
-		gc.TINT32<<16 | gc.TFLOAT64,
-		gc.TUINT32<<16 | gc.TFLOAT64:
+	case ir.TINT8<<16 | ir.TFLOAT32,
+		ir.TUINT8<<16 | ir.TFLOAT32,
+		ir.TINT16<<16 | ir.TFLOAT32,
+		ir.TUINT16<<16 | ir.TFLOAT32,
+		ir.TINT32<<16 | ir.TFLOAT32,
+		ir.TUINT32<<16 | ir.TFLOAT32,
+		ir.TINT8<<16 | ir.TFLOAT64,
+		ir.TUINT8<<16 | ir.TFLOAT64,
+		ir.TINT16<<16 | ir.TFLOAT64,
+		ir.TUINT16<<16 | ir.TFLOAT64,
+		ir.TINT32<<16 | ir.TFLOAT64,
+		ir.TUINT32<<16 | ir.TFLOAT64:
 		fa := arm.AMOVW
 
 		switch ft {
-		case gc.TINT8:
+		case ir.TINT8:
 			fa = arm.AMOVBS
 
-		case gc.TUINT8:
+		case ir.TUINT8:
 			fa = arm.AMOVBU
 
-		case gc.TINT16:
+		case ir.TINT16:
 			fa = arm.AMOVHS
 
-		case gc.TUINT16:
+		case ir.TUINT16:
 			fa = arm.AMOVHU
 		}
 
 		a := arm.AMOVWF
 		ta := arm.AMOVF
-		if tt == gc.TFLOAT64 {
+		if tt == ir.TFLOAT64 {
 			a = arm.AMOVWD
 			ta = arm.AMOVD
 		}
 
-		var r1 gc.Node
-		gc.Regalloc(&r1, gc.Types[ft], f)
-		var r2 gc.Node
-		gc.Regalloc(&r2, gc.Types[tt], t)
+		var r1 ir.Node
+		gc.Regalloc(&r1, ir.Types[ft], f)
+		var r2 ir.Node
+		gc.Regalloc(&r2, ir.Types[tt], t)
 		gins(fa, f, &r1)          // load to cpu
 		gins(arm.AMOVW, &r1, &r2) // copy to fpu
 		p1 := gins(a, &r2, &r2)   // convert
 		switch ft {
-		case gc.TUINT8,
-			gc.TUINT16,
-			gc.TUINT32:
+		case ir.TUINT8,
+			ir.TUINT16,
+			ir.TUINT32:
 			p1.Scond |= arm.C_UBIT
 		}
 
@@ -544,32 +545,32 @@ func gmove(f *gc.Node, t *gc.Node) {
 		gc.Regfree(&r2)
 		return
 
-	case gc.TUINT64<<16 | gc.TFLOAT32,
-		gc.TUINT64<<16 | gc.TFLOAT64:
+	case ir.TUINT64<<16 | ir.TFLOAT32,
+		ir.TUINT64<<16 | ir.TFLOAT64:
 		gc.Fatalf("gmove UINT64, TFLOAT not implemented")
 		return
 
 		/*
 		 * float to float
 		 */
-	case gc.TFLOAT32<<16 | gc.TFLOAT32:
+	case ir.TFLOAT32<<16 | ir.TFLOAT32:
 		a = arm.AMOVF
 
-	case gc.TFLOAT64<<16 | gc.TFLOAT64:
+	case ir.TFLOAT64<<16 | ir.TFLOAT64:
 		a = arm.AMOVD
 
-	case gc.TFLOAT32<<16 | gc.TFLOAT64:
-		var r1 gc.Node
-		gc.Regalloc(&r1, gc.Types[gc.TFLOAT64], t)
+	case ir.TFLOAT32<<16 | ir.TFLOAT64:
+		var r1 ir.Node
+		gc.Regalloc(&r1, ir.Types[ir.TFLOAT64], t)
 		gins(arm.AMOVF, f, &r1)
 		gins(arm.AMOVFD, &r1, &r1)
 		gins(arm.AMOVD, &r1, t)
 		gc.Regfree(&r1)
 		return
 
-	case gc.TFLOAT64<<16 | gc.TFLOAT32:
-		var r1 gc.Node
-		gc.Regalloc(&r1, gc.Types[gc.TFLOAT64], t)
+	case ir.TFLOAT64<<16 | ir.TFLOAT32:
+		var r1 ir.Node
+		gc.Regalloc(&r1, ir.Types[ir.TFLOAT64], t)
 		gins(arm.AMOVD, f, &r1)
 		gins(arm.AMOVDF, &r1, &r1)
 		gins(arm.AMOVF, &r1, t)
@@ -604,8 +605,8 @@ hard:
 
 	// truncate 64 bit integer
 trunc64:
-	var fhi gc.Node
-	var flo gc.Node
+	var fhi ir.Node
+	var flo ir.Node
 	split64(f, &flo, &fhi)
 
 	gc.Regalloc(&r1, t.Type, nil)
@@ -616,13 +617,13 @@ trunc64:
 	return
 }
 
-func samaddr(f *gc.Node, t *gc.Node) bool {
+func samaddr(f *ir.Node, t *ir.Node) bool {
 	if f.Op != t.Op {
 		return false
 	}
 
 	switch f.Op {
-	case gc.OREGISTER:
+	case ir.OREGISTER:
 		if f.Reg != t.Reg {
 			break
 		}
@@ -636,11 +637,11 @@ func samaddr(f *gc.Node, t *gc.Node) bool {
  * generate one instruction:
  *	as f, t
  */
-func gins(as obj.As, f *gc.Node, t *gc.Node) *obj.Prog {
+func gins(as obj.As, f *ir.Node, t *ir.Node) *obj.Prog {
 	//	Node nod;
 	//	int32 v;
 
-	if f != nil && f.Op == gc.OINDEX {
+	if f != nil && f.Op == ir.OINDEX {
 		gc.Fatalf("gins OINDEX not implemented")
 	}
 
@@ -650,7 +651,7 @@ func gins(as obj.As, f *gc.Node, t *gc.Node) *obj.Prog {
 	//		constnode.vconst = v;
 	//		idx.reg = nod.reg;
 	//		gc.Regfree(&nod);
-	if t != nil && t.Op == gc.OINDEX {
+	if t != nil && t.Op == ir.OINDEX {
 		gc.Fatalf("gins OINDEX not implemented")
 	}
 
@@ -673,7 +674,7 @@ func gins(as obj.As, f *gc.Node, t *gc.Node) *obj.Prog {
 
 	case arm.ACMP, arm.ACMPF, arm.ACMPD:
 		if t != nil {
-			if f.Op != gc.OREGISTER {
+			if f.Op != ir.OREGISTER {
 				/* generate a comparison
 				TODO(kaib): one of the args can actually be a small constant. relax the constraint and fix call sites.
 				*/
@@ -685,7 +686,7 @@ func gins(as obj.As, f *gc.Node, t *gc.Node) *obj.Prog {
 		}
 
 	case arm.AMULU:
-		if f != nil && f.Op != gc.OREGISTER {
+		if f != nil && f.Op != ir.OREGISTER {
 			gc.Fatalf("bad operands to mul")
 		}
 
@@ -714,7 +715,7 @@ func gins(as obj.As, f *gc.Node, t *gc.Node) *obj.Prog {
 /*
  * insert n into reg slot of p
  */
-func raddr(n *gc.Node, p *obj.Prog) {
+func raddr(n *ir.Node, p *obj.Prog) {
 	var a obj.Addr
 	gc.Naddr(&a, n)
 	if a.Type != obj.TYPE_REG {
@@ -732,7 +733,7 @@ func raddr(n *gc.Node, p *obj.Prog) {
 /* generate a constant shift
  * arm encodes a shift by 32 as 0, thus asking for 0 shift is illegal.
  */
-func gshift(as obj.As, lhs *gc.Node, stype int32, sval int32, rhs *gc.Node) *obj.Prog {
+func gshift(as obj.As, lhs *ir.Node, stype int32, sval int32, rhs *ir.Node) *obj.Prog {
 	if sval <= 0 || sval > 32 {
 		gc.Fatalf("bad shift value: %d", sval)
 	}
@@ -747,7 +748,7 @@ func gshift(as obj.As, lhs *gc.Node, stype int32, sval int32, rhs *gc.Node) *obj
 
 /* generate a register shift
  */
-func gregshift(as obj.As, lhs *gc.Node, stype int32, reg *gc.Node, rhs *gc.Node) *obj.Prog {
+func gregshift(as obj.As, lhs *ir.Node, stype int32, reg *ir.Node, rhs *ir.Node) *obj.Prog {
 	p := gins(as, nil, rhs)
 	p.From.Type = obj.TYPE_SHIFT
 	p.From.Offset = int64(stype) | (int64(reg.Reg)&15)<<8 | 1<<4 | int64(lhs.Reg)&15
@@ -757,40 +758,40 @@ func gregshift(as obj.As, lhs *gc.Node, stype int32, reg *gc.Node, rhs *gc.Node)
 /*
  * return Axxx for Oxxx on type t.
  */
-func optoas(op gc.Op, t *gc.Type) obj.As {
+func optoas(op ir.Op, t *ir.Type) obj.As {
 	if t == nil {
 		gc.Fatalf("optoas: t is nil")
 	}
 
 	// avoid constant conversions in switches below
 	const (
-		OMINUS_ = uint32(gc.OMINUS) << 16
-		OLSH_   = uint32(gc.OLSH) << 16
-		ORSH_   = uint32(gc.ORSH) << 16
-		OADD_   = uint32(gc.OADD) << 16
-		OSUB_   = uint32(gc.OSUB) << 16
-		OMUL_   = uint32(gc.OMUL) << 16
-		ODIV_   = uint32(gc.ODIV) << 16
-		OMOD_   = uint32(gc.OMOD) << 16
-		OOR_    = uint32(gc.OOR) << 16
-		OAND_   = uint32(gc.OAND) << 16
-		OXOR_   = uint32(gc.OXOR) << 16
-		OEQ_    = uint32(gc.OEQ) << 16
-		ONE_    = uint32(gc.ONE) << 16
-		OLT_    = uint32(gc.OLT) << 16
-		OLE_    = uint32(gc.OLE) << 16
-		OGE_    = uint32(gc.OGE) << 16
-		OGT_    = uint32(gc.OGT) << 16
-		OCMP_   = uint32(gc.OCMP) << 16
-		OPS_    = uint32(gc.OPS) << 16
-		OAS_    = uint32(gc.OAS) << 16
-		OSQRT_  = uint32(gc.OSQRT) << 16
+		OMINUS_ = uint32(ir.OMINUS) << 16
+		OLSH_   = uint32(ir.OLSH) << 16
+		ORSH_   = uint32(ir.ORSH) << 16
+		OADD_   = uint32(ir.OADD) << 16
+		OSUB_   = uint32(ir.OSUB) << 16
+		OMUL_   = uint32(ir.OMUL) << 16
+		ODIV_   = uint32(ir.ODIV) << 16
+		OMOD_   = uint32(ir.OMOD) << 16
+		OOR_    = uint32(ir.OOR) << 16
+		OAND_   = uint32(ir.OAND) << 16
+		OXOR_   = uint32(ir.OXOR) << 16
+		OEQ_    = uint32(ir.OEQ) << 16
+		ONE_    = uint32(ir.ONE) << 16
+		OLT_    = uint32(ir.OLT) << 16
+		OLE_    = uint32(ir.OLE) << 16
+		OGE_    = uint32(ir.OGE) << 16
+		OGT_    = uint32(ir.OGT) << 16
+		OCMP_   = uint32(ir.OCMP) << 16
+		OPS_    = uint32(ir.OPS) << 16
+		OAS_    = uint32(ir.OAS) << 16
+		OSQRT_  = uint32(ir.OSQRT) << 16
 	)
 
 	a := obj.AXXX
 	switch uint32(op)<<16 | uint32(gc.Simtype[t.Etype]) {
 	default:
-		gc.Fatalf("optoas: no entry %v-%v etype %v simtype %v", gc.Oconv(op, 0), t, gc.Types[t.Etype], gc.Types[gc.Simtype[t.Etype]])
+		gc.Fatalf("optoas: no entry %v-%v etype %v simtype %v", gc.Oconv(op, 0), t, ir.Types[t.Etype], ir.Types[gc.Simtype[t.Etype]])
 
 		/*	case CASE(OADDR, TPTR32):
 				a = ALEAL;
@@ -801,261 +802,261 @@ func optoas(op gc.Op, t *gc.Type) obj.As {
 				break;
 		*/
 	// TODO(kaib): make sure the conditional branches work on all edge cases
-	case OEQ_ | gc.TBOOL,
-		OEQ_ | gc.TINT8,
-		OEQ_ | gc.TUINT8,
-		OEQ_ | gc.TINT16,
-		OEQ_ | gc.TUINT16,
-		OEQ_ | gc.TINT32,
-		OEQ_ | gc.TUINT32,
-		OEQ_ | gc.TINT64,
-		OEQ_ | gc.TUINT64,
-		OEQ_ | gc.TPTR32,
-		OEQ_ | gc.TPTR64,
-		OEQ_ | gc.TFLOAT32,
-		OEQ_ | gc.TFLOAT64:
+	case OEQ_ | ir.TBOOL,
+		OEQ_ | ir.TINT8,
+		OEQ_ | ir.TUINT8,
+		OEQ_ | ir.TINT16,
+		OEQ_ | ir.TUINT16,
+		OEQ_ | ir.TINT32,
+		OEQ_ | ir.TUINT32,
+		OEQ_ | ir.TINT64,
+		OEQ_ | ir.TUINT64,
+		OEQ_ | ir.TPTR32,
+		OEQ_ | ir.TPTR64,
+		OEQ_ | ir.TFLOAT32,
+		OEQ_ | ir.TFLOAT64:
 		a = arm.ABEQ
 
-	case ONE_ | gc.TBOOL,
-		ONE_ | gc.TINT8,
-		ONE_ | gc.TUINT8,
-		ONE_ | gc.TINT16,
-		ONE_ | gc.TUINT16,
-		ONE_ | gc.TINT32,
-		ONE_ | gc.TUINT32,
-		ONE_ | gc.TINT64,
-		ONE_ | gc.TUINT64,
-		ONE_ | gc.TPTR32,
-		ONE_ | gc.TPTR64,
-		ONE_ | gc.TFLOAT32,
-		ONE_ | gc.TFLOAT64:
+	case ONE_ | ir.TBOOL,
+		ONE_ | ir.TINT8,
+		ONE_ | ir.TUINT8,
+		ONE_ | ir.TINT16,
+		ONE_ | ir.TUINT16,
+		ONE_ | ir.TINT32,
+		ONE_ | ir.TUINT32,
+		ONE_ | ir.TINT64,
+		ONE_ | ir.TUINT64,
+		ONE_ | ir.TPTR32,
+		ONE_ | ir.TPTR64,
+		ONE_ | ir.TFLOAT32,
+		ONE_ | ir.TFLOAT64:
 		a = arm.ABNE
 
-	case OLT_ | gc.TINT8,
-		OLT_ | gc.TINT16,
-		OLT_ | gc.TINT32,
-		OLT_ | gc.TINT64,
-		OLT_ | gc.TFLOAT32,
-		OLT_ | gc.TFLOAT64:
+	case OLT_ | ir.TINT8,
+		OLT_ | ir.TINT16,
+		OLT_ | ir.TINT32,
+		OLT_ | ir.TINT64,
+		OLT_ | ir.TFLOAT32,
+		OLT_ | ir.TFLOAT64:
 		a = arm.ABLT
 
-	case OLT_ | gc.TUINT8,
-		OLT_ | gc.TUINT16,
-		OLT_ | gc.TUINT32,
-		OLT_ | gc.TUINT64:
+	case OLT_ | ir.TUINT8,
+		OLT_ | ir.TUINT16,
+		OLT_ | ir.TUINT32,
+		OLT_ | ir.TUINT64:
 		a = arm.ABLO
 
-	case OLE_ | gc.TINT8,
-		OLE_ | gc.TINT16,
-		OLE_ | gc.TINT32,
-		OLE_ | gc.TINT64,
-		OLE_ | gc.TFLOAT32,
-		OLE_ | gc.TFLOAT64:
+	case OLE_ | ir.TINT8,
+		OLE_ | ir.TINT16,
+		OLE_ | ir.TINT32,
+		OLE_ | ir.TINT64,
+		OLE_ | ir.TFLOAT32,
+		OLE_ | ir.TFLOAT64:
 		a = arm.ABLE
 
-	case OLE_ | gc.TUINT8,
-		OLE_ | gc.TUINT16,
-		OLE_ | gc.TUINT32,
-		OLE_ | gc.TUINT64:
+	case OLE_ | ir.TUINT8,
+		OLE_ | ir.TUINT16,
+		OLE_ | ir.TUINT32,
+		OLE_ | ir.TUINT64:
 		a = arm.ABLS
 
-	case OGT_ | gc.TINT8,
-		OGT_ | gc.TINT16,
-		OGT_ | gc.TINT32,
-		OGT_ | gc.TINT64,
-		OGT_ | gc.TFLOAT32,
-		OGT_ | gc.TFLOAT64:
+	case OGT_ | ir.TINT8,
+		OGT_ | ir.TINT16,
+		OGT_ | ir.TINT32,
+		OGT_ | ir.TINT64,
+		OGT_ | ir.TFLOAT32,
+		OGT_ | ir.TFLOAT64:
 		a = arm.ABGT
 
-	case OGT_ | gc.TUINT8,
-		OGT_ | gc.TUINT16,
-		OGT_ | gc.TUINT32,
-		OGT_ | gc.TUINT64:
+	case OGT_ | ir.TUINT8,
+		OGT_ | ir.TUINT16,
+		OGT_ | ir.TUINT32,
+		OGT_ | ir.TUINT64:
 		a = arm.ABHI
 
-	case OGE_ | gc.TINT8,
-		OGE_ | gc.TINT16,
-		OGE_ | gc.TINT32,
-		OGE_ | gc.TINT64,
-		OGE_ | gc.TFLOAT32,
-		OGE_ | gc.TFLOAT64:
+	case OGE_ | ir.TINT8,
+		OGE_ | ir.TINT16,
+		OGE_ | ir.TINT32,
+		OGE_ | ir.TINT64,
+		OGE_ | ir.TFLOAT32,
+		OGE_ | ir.TFLOAT64:
 		a = arm.ABGE
 
-	case OGE_ | gc.TUINT8,
-		OGE_ | gc.TUINT16,
-		OGE_ | gc.TUINT32,
-		OGE_ | gc.TUINT64:
+	case OGE_ | ir.TUINT8,
+		OGE_ | ir.TUINT16,
+		OGE_ | ir.TUINT32,
+		OGE_ | ir.TUINT64:
 		a = arm.ABHS
 
-	case OCMP_ | gc.TBOOL,
-		OCMP_ | gc.TINT8,
-		OCMP_ | gc.TUINT8,
-		OCMP_ | gc.TINT16,
-		OCMP_ | gc.TUINT16,
-		OCMP_ | gc.TINT32,
-		OCMP_ | gc.TUINT32,
-		OCMP_ | gc.TPTR32:
+	case OCMP_ | ir.TBOOL,
+		OCMP_ | ir.TINT8,
+		OCMP_ | ir.TUINT8,
+		OCMP_ | ir.TINT16,
+		OCMP_ | ir.TUINT16,
+		OCMP_ | ir.TINT32,
+		OCMP_ | ir.TUINT32,
+		OCMP_ | ir.TPTR32:
 		a = arm.ACMP
 
-	case OCMP_ | gc.TFLOAT32:
+	case OCMP_ | ir.TFLOAT32:
 		a = arm.ACMPF
 
-	case OCMP_ | gc.TFLOAT64:
+	case OCMP_ | ir.TFLOAT64:
 		a = arm.ACMPD
 
-	case OPS_ | gc.TFLOAT32,
-		OPS_ | gc.TFLOAT64:
+	case OPS_ | ir.TFLOAT32,
+		OPS_ | ir.TFLOAT64:
 		a = arm.ABVS
 
-	case OAS_ | gc.TBOOL:
+	case OAS_ | ir.TBOOL:
 		a = arm.AMOVB
 
-	case OAS_ | gc.TINT8:
+	case OAS_ | ir.TINT8:
 		a = arm.AMOVBS
 
-	case OAS_ | gc.TUINT8:
+	case OAS_ | ir.TUINT8:
 		a = arm.AMOVBU
 
-	case OAS_ | gc.TINT16:
+	case OAS_ | ir.TINT16:
 		a = arm.AMOVHS
 
-	case OAS_ | gc.TUINT16:
+	case OAS_ | ir.TUINT16:
 		a = arm.AMOVHU
 
-	case OAS_ | gc.TINT32,
-		OAS_ | gc.TUINT32,
-		OAS_ | gc.TPTR32:
+	case OAS_ | ir.TINT32,
+		OAS_ | ir.TUINT32,
+		OAS_ | ir.TPTR32:
 		a = arm.AMOVW
 
-	case OAS_ | gc.TFLOAT32:
+	case OAS_ | ir.TFLOAT32:
 		a = arm.AMOVF
 
-	case OAS_ | gc.TFLOAT64:
+	case OAS_ | ir.TFLOAT64:
 		a = arm.AMOVD
 
-	case OADD_ | gc.TINT8,
-		OADD_ | gc.TUINT8,
-		OADD_ | gc.TINT16,
-		OADD_ | gc.TUINT16,
-		OADD_ | gc.TINT32,
-		OADD_ | gc.TUINT32,
-		OADD_ | gc.TPTR32:
+	case OADD_ | ir.TINT8,
+		OADD_ | ir.TUINT8,
+		OADD_ | ir.TINT16,
+		OADD_ | ir.TUINT16,
+		OADD_ | ir.TINT32,
+		OADD_ | ir.TUINT32,
+		OADD_ | ir.TPTR32:
 		a = arm.AADD
 
-	case OADD_ | gc.TFLOAT32:
+	case OADD_ | ir.TFLOAT32:
 		a = arm.AADDF
 
-	case OADD_ | gc.TFLOAT64:
+	case OADD_ | ir.TFLOAT64:
 		a = arm.AADDD
 
-	case OSUB_ | gc.TINT8,
-		OSUB_ | gc.TUINT8,
-		OSUB_ | gc.TINT16,
-		OSUB_ | gc.TUINT16,
-		OSUB_ | gc.TINT32,
-		OSUB_ | gc.TUINT32,
-		OSUB_ | gc.TPTR32:
+	case OSUB_ | ir.TINT8,
+		OSUB_ | ir.TUINT8,
+		OSUB_ | ir.TINT16,
+		OSUB_ | ir.TUINT16,
+		OSUB_ | ir.TINT32,
+		OSUB_ | ir.TUINT32,
+		OSUB_ | ir.TPTR32:
 		a = arm.ASUB
 
-	case OSUB_ | gc.TFLOAT32:
+	case OSUB_ | ir.TFLOAT32:
 		a = arm.ASUBF
 
-	case OSUB_ | gc.TFLOAT64:
+	case OSUB_ | ir.TFLOAT64:
 		a = arm.ASUBD
 
-	case OMINUS_ | gc.TINT8,
-		OMINUS_ | gc.TUINT8,
-		OMINUS_ | gc.TINT16,
-		OMINUS_ | gc.TUINT16,
-		OMINUS_ | gc.TINT32,
-		OMINUS_ | gc.TUINT32,
-		OMINUS_ | gc.TPTR32:
+	case OMINUS_ | ir.TINT8,
+		OMINUS_ | ir.TUINT8,
+		OMINUS_ | ir.TINT16,
+		OMINUS_ | ir.TUINT16,
+		OMINUS_ | ir.TINT32,
+		OMINUS_ | ir.TUINT32,
+		OMINUS_ | ir.TPTR32:
 		a = arm.ARSB
 
-	case OAND_ | gc.TINT8,
-		OAND_ | gc.TUINT8,
-		OAND_ | gc.TINT16,
-		OAND_ | gc.TUINT16,
-		OAND_ | gc.TINT32,
-		OAND_ | gc.TUINT32,
-		OAND_ | gc.TPTR32:
+	case OAND_ | ir.TINT8,
+		OAND_ | ir.TUINT8,
+		OAND_ | ir.TINT16,
+		OAND_ | ir.TUINT16,
+		OAND_ | ir.TINT32,
+		OAND_ | ir.TUINT32,
+		OAND_ | ir.TPTR32:
 		a = arm.AAND
 
-	case OOR_ | gc.TINT8,
-		OOR_ | gc.TUINT8,
-		OOR_ | gc.TINT16,
-		OOR_ | gc.TUINT16,
-		OOR_ | gc.TINT32,
-		OOR_ | gc.TUINT32,
-		OOR_ | gc.TPTR32:
+	case OOR_ | ir.TINT8,
+		OOR_ | ir.TUINT8,
+		OOR_ | ir.TINT16,
+		OOR_ | ir.TUINT16,
+		OOR_ | ir.TINT32,
+		OOR_ | ir.TUINT32,
+		OOR_ | ir.TPTR32:
 		a = arm.AORR
 
-	case OXOR_ | gc.TINT8,
-		OXOR_ | gc.TUINT8,
-		OXOR_ | gc.TINT16,
-		OXOR_ | gc.TUINT16,
-		OXOR_ | gc.TINT32,
-		OXOR_ | gc.TUINT32,
-		OXOR_ | gc.TPTR32:
+	case OXOR_ | ir.TINT8,
+		OXOR_ | ir.TUINT8,
+		OXOR_ | ir.TINT16,
+		OXOR_ | ir.TUINT16,
+		OXOR_ | ir.TINT32,
+		OXOR_ | ir.TUINT32,
+		OXOR_ | ir.TPTR32:
 		a = arm.AEOR
 
-	case OLSH_ | gc.TINT8,
-		OLSH_ | gc.TUINT8,
-		OLSH_ | gc.TINT16,
-		OLSH_ | gc.TUINT16,
-		OLSH_ | gc.TINT32,
-		OLSH_ | gc.TUINT32,
-		OLSH_ | gc.TPTR32:
+	case OLSH_ | ir.TINT8,
+		OLSH_ | ir.TUINT8,
+		OLSH_ | ir.TINT16,
+		OLSH_ | ir.TUINT16,
+		OLSH_ | ir.TINT32,
+		OLSH_ | ir.TUINT32,
+		OLSH_ | ir.TPTR32:
 		a = arm.ASLL
 
-	case ORSH_ | gc.TUINT8,
-		ORSH_ | gc.TUINT16,
-		ORSH_ | gc.TUINT32,
-		ORSH_ | gc.TPTR32:
+	case ORSH_ | ir.TUINT8,
+		ORSH_ | ir.TUINT16,
+		ORSH_ | ir.TUINT32,
+		ORSH_ | ir.TPTR32:
 		a = arm.ASRL
 
-	case ORSH_ | gc.TINT8,
-		ORSH_ | gc.TINT16,
-		ORSH_ | gc.TINT32:
+	case ORSH_ | ir.TINT8,
+		ORSH_ | ir.TINT16,
+		ORSH_ | ir.TINT32:
 		a = arm.ASRA
 
-	case OMUL_ | gc.TUINT8,
-		OMUL_ | gc.TUINT16,
-		OMUL_ | gc.TUINT32,
-		OMUL_ | gc.TPTR32:
+	case OMUL_ | ir.TUINT8,
+		OMUL_ | ir.TUINT16,
+		OMUL_ | ir.TUINT32,
+		OMUL_ | ir.TPTR32:
 		a = arm.AMULU
 
-	case OMUL_ | gc.TINT8,
-		OMUL_ | gc.TINT16,
-		OMUL_ | gc.TINT32:
+	case OMUL_ | ir.TINT8,
+		OMUL_ | ir.TINT16,
+		OMUL_ | ir.TINT32:
 		a = arm.AMUL
 
-	case OMUL_ | gc.TFLOAT32:
+	case OMUL_ | ir.TFLOAT32:
 		a = arm.AMULF
 
-	case OMUL_ | gc.TFLOAT64:
+	case OMUL_ | ir.TFLOAT64:
 		a = arm.AMULD
 
-	case ODIV_ | gc.TUINT8,
-		ODIV_ | gc.TUINT16,
-		ODIV_ | gc.TUINT32,
-		ODIV_ | gc.TPTR32:
+	case ODIV_ | ir.TUINT8,
+		ODIV_ | ir.TUINT16,
+		ODIV_ | ir.TUINT32,
+		ODIV_ | ir.TPTR32:
 		a = arm.ADIVU
 
-	case ODIV_ | gc.TINT8,
-		ODIV_ | gc.TINT16,
-		ODIV_ | gc.TINT32:
+	case ODIV_ | ir.TINT8,
+		ODIV_ | ir.TINT16,
+		ODIV_ | ir.TINT32:
 		a = arm.ADIV
 
-	case OMOD_ | gc.TUINT8,
-		OMOD_ | gc.TUINT16,
-		OMOD_ | gc.TUINT32,
-		OMOD_ | gc.TPTR32:
+	case OMOD_ | ir.TUINT8,
+		OMOD_ | ir.TUINT16,
+		OMOD_ | ir.TUINT32,
+		OMOD_ | ir.TPTR32:
 		a = arm.AMODU
 
-	case OMOD_ | gc.TINT8,
-		OMOD_ | gc.TINT16,
-		OMOD_ | gc.TINT32:
+	case OMOD_ | ir.TINT8,
+		OMOD_ | ir.TINT16,
+		OMOD_ | ir.TINT32:
 		a = arm.AMOD
 
 		//	case CASE(OEXTEND, TINT16):
@@ -1070,13 +1071,13 @@ func optoas(op gc.Op, t *gc.Type) obj.As {
 	//		a = ACQO;
 	//		break;
 
-	case ODIV_ | gc.TFLOAT32:
+	case ODIV_ | ir.TFLOAT32:
 		a = arm.ADIVF
 
-	case ODIV_ | gc.TFLOAT64:
+	case ODIV_ | ir.TFLOAT64:
 		a = arm.ADIVD
 
-	case OSQRT_ | gc.TFLOAT64:
+	case OSQRT_ | ir.TFLOAT64:
 		a = arm.ASQRTD
 	}
 
@@ -1088,27 +1089,27 @@ const (
 	OPtrto = 1 << 1
 )
 
-var clean [20]gc.Node
+var clean [20]ir.Node
 
 var cleani int = 0
 
 func sudoclean() {
-	if clean[cleani-1].Op != gc.OEMPTY {
+	if clean[cleani-1].Op != ir.OEMPTY {
 		gc.Regfree(&clean[cleani-1])
 	}
-	if clean[cleani-2].Op != gc.OEMPTY {
+	if clean[cleani-2].Op != ir.OEMPTY {
 		gc.Regfree(&clean[cleani-2])
 	}
 	cleani -= 2
 }
 
-func dotaddable(n *gc.Node, n1 *gc.Node) bool {
-	if n.Op != gc.ODOT {
+func dotaddable(n *ir.Node, n1 *ir.Node) bool {
+	if n.Op != ir.ODOT {
 		return false
 	}
 
 	var oary [10]int64
-	var nn *gc.Node
+	var nn *ir.Node
 	o := gc.Dotoffset(n, oary[:], &nn)
 	if nn != nil && nn.Addable && o == 1 && oary[0] >= 0 {
 		*n1 = *nn
@@ -1131,7 +1132,7 @@ func dotaddable(n *gc.Node, n1 *gc.Node) bool {
  * after successful sudoaddable,
  * to release the register used for a.
  */
-func sudoaddable(as obj.As, n *gc.Node, a *obj.Addr) bool {
+func sudoaddable(as obj.As, n *ir.Node, a *obj.Addr) bool {
 	if n.Type == nil {
 		return false
 	}
@@ -1139,8 +1140,8 @@ func sudoaddable(as obj.As, n *gc.Node, a *obj.Addr) bool {
 	*a = obj.Addr{}
 
 	switch n.Op {
-	case gc.OLITERAL:
-		if !gc.Isconst(n, gc.CTINT) {
+	case ir.OLITERAL:
+		if !ir.Isconst(n, ir.CTINT) {
 			break
 		}
 		v := n.Int()
@@ -1169,19 +1170,19 @@ func sudoaddable(as obj.As, n *gc.Node, a *obj.Addr) bool {
 		cleani += 2
 		reg := &clean[cleani-1]
 		reg1 := &clean[cleani-2]
-		reg.Op = gc.OEMPTY
-		reg1.Op = gc.OEMPTY
+		reg.Op = ir.OEMPTY
+		reg1.Op = ir.OEMPTY
 		gc.Naddr(a, n)
 		return true
 
-	case gc.ODOT,
-		gc.ODOTPTR:
+	case ir.ODOT,
+		ir.ODOTPTR:
 		cleani += 2
 		reg := &clean[cleani-1]
 		reg1 := &clean[cleani-2]
-		reg.Op = gc.OEMPTY
-		reg1.Op = gc.OEMPTY
-		var nn *gc.Node
+		reg.Op = ir.OEMPTY
+		reg1.Op = ir.OEMPTY
+		var nn *ir.Node
 		var oary [10]int64
 		o := gc.Dotoffset(n, oary[:], &nn)
 		if nn == nil {
@@ -1199,9 +1200,9 @@ func sudoaddable(as obj.As, n *gc.Node, a *obj.Addr) bool {
 			return true
 		}
 
-		gc.Regalloc(reg, gc.Types[gc.Tptr], nil)
+		gc.Regalloc(reg, ir.Types[gc.Tptr], nil)
 		n1 := *reg
-		n1.Op = gc.OINDREG
+		n1.Op = ir.OINDREG
 		if oary[0] >= 0 {
 			gc.Agen(nn, reg)
 			n1.Xoffset = oary[0]
@@ -1226,7 +1227,7 @@ func sudoaddable(as obj.As, n *gc.Node, a *obj.Addr) bool {
 		gc.Naddr(a, &n1)
 		return true
 
-	case gc.OINDEX:
+	case ir.OINDEX:
 		return false
 	}
 
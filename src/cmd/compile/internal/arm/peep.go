@@ -37,15 +37,12 @@ import (
 	"fmt"
 )
 
-var gactive uint32
-
 // UNUSED
 func peep(firstp *obj.Prog) {
 	g := gc.Flowstart(firstp, nil)
 	if g == nil {
 		return
 	}
-	gactive = 0
 
 	var p *obj.Prog
 	var t int
@@ -62,7 +59,7 @@ loop1:
 		 * elide shift into TYPE_SHIFT operand of subsequent instruction
 		 */
 		//			if(shiftprop(r)) {
-		//				excise(r);
+		//				gc.Excise(r);
 		//				t++;
 		//				break;
 		//			}
@@ -79,14 +76,14 @@ loop1:
 			if regtyp(&p.From) {
 				if p.From.Type == p.To.Type && isfloatreg(&p.From) == isfloatreg(&p.To) {
 					if p.Scond == arm.C_SCOND_NONE {
-						if copyprop(g, r) {
-							excise(r)
+						if gc.Copyprop(r) {
+							gc.Excise(r)
 							t++
 							break
 						}
 
-						if subprop(r) && copyprop(g, r) {
-							excise(r)
+						if subprop(r) && gc.Copyprop(r) {
+							gc.Excise(r)
 							t++
 							break
 						}
@@ -220,7 +217,7 @@ loop1:
 	//			}
 	//			p1->scond |= C_SBIT;
 	//			r2->prog->as = t;
-	//			excise(r);
+	//			gc.Excise(r);
 	//			continue;
 
 	//	predicate(g);
@@ -328,120 +325,6 @@ func subprop(r0 *gc.Flow) bool {
 	return false
 }
 
-/*
- * The idea is to remove redundant copies.
- *	v1->v2	F=0
- *	(use v2	s/v2/v1/)*
- *	set v1	F=1
- *	use v2	return fail
- *	-----------------
- *	v1->v2	F=0
- *	(use v2	s/v2/v1/)*
- *	set v1	F=1
- *	set v2	return success
- */
-func copyprop(g *gc.Graph, r0 *gc.Flow) bool {
-	p := r0.Prog
-	v1 := &p.From
-	v2 := &p.To
-	if copyas(v1, v2) {
-		return true
-	}
-	gactive++
-	return copy1(v1, v2, r0.S1, false)
-}
-
-func copy1(v1 *obj.Addr, v2 *obj.Addr, r *gc.Flow, f bool) bool {
-	if uint32(r.Active) == gactive {
-		if gc.Debug['P'] != 0 {
-			fmt.Printf("act set; return 1\n")
-		}
-		return true
-	}
-
-	r.Active = int32(gactive)
-	if gc.Debug['P'] != 0 {
-		fmt.Printf("copy %v->%v f=%v\n", gc.Ctxt.Dconv(v1), gc.Ctxt.Dconv(v2), f)
-	}
-	for ; r != nil; r = r.S1 {
-		p := r.Prog
-		if gc.Debug['P'] != 0 {
-			fmt.Printf("%v", p)
-		}
-		if !f && gc.Uniqp(r) == nil {
-			f = true
-			if gc.Debug['P'] != 0 {
-				fmt.Printf("; merge; f=%v", f)
-			}
-		}
-
-		switch t := copyu(p, v2, nil); t {
-		case 2: /* rar, can't split */
-			if gc.Debug['P'] != 0 {
-				fmt.Printf("; %vrar; return 0\n", gc.Ctxt.Dconv(v2))
-			}
-			return false
-
-		case 3: /* set */
-			if gc.Debug['P'] != 0 {
-				fmt.Printf("; %vset; return 1\n", gc.Ctxt.Dconv(v2))
-			}
-			return true
-
-		case 1, /* used, substitute */
-			4: /* use and set */
-			if f {
-				if gc.Debug['P'] == 0 {
-					return false
-				}
-				if t == 4 {
-					fmt.Printf("; %vused+set and f=%v; return 0\n", gc.Ctxt.Dconv(v2), f)
-				} else {
-					fmt.Printf("; %vused and f=%v; return 0\n", gc.Ctxt.Dconv(v2), f)
-				}
-				return false
-			}
-
-			if copyu(p, v2, v1) != 0 {
-				if gc.Debug['P'] != 0 {
-					fmt.Printf("; sub fail; return 0\n")
-				}
-				return false
-			}
-
-			if gc.Debug['P'] != 0 {
-				fmt.Printf("; sub%v/%v", gc.Ctxt.Dconv(v2), gc.Ctxt.Dconv(v1))
-			}
-			if t == 4 {
-				if gc.Debug['P'] != 0 {
-					fmt.Printf("; %vused+set; return 1\n", gc.Ctxt.Dconv(v2))
-				}
-				return true
-			}
-		}
-
-		if !f {
-			t := copyu(p, v1, nil)
-			if t == 2 || t == 3 || t == 4 {
-				f = true
-				if gc.Debug['P'] != 0 {
-					fmt.Printf("; %vset and !f; f=%v", gc.Ctxt.Dconv(v1), f)
-				}
-			}
-		}
-
-		if gc.Debug['P'] != 0 {
-			fmt.Printf("\n")
-		}
-		if r.S2 != nil {
-			if !copy1(v1, v2, r.S2, f) {
-				return false
-			}
-		}
-	}
-	return true
-}
-
 // UNUSED
 /*
  * The idea is to remove redundant constants.
@@ -978,7 +861,7 @@ func xtramodes(g *gc.Graph, r *gc.Flow, a *obj.Addr) bool {
 						if p1.Reg != 0 {
 							a.Reg = p1.Reg
 						}
-						excise(r1)
+						gc.Excise(r1)
 						return true
 					}
 				}
@@ -998,9 +881,9 @@ func xtramodes(g *gc.Graph, r *gc.Flow, a *obj.Addr) bool {
 							a.Offset = p1.From.Offset
 							p.Scond |= arm.C_PBIT
 							if !finduse(g, r, &r1.Prog.To) {
-								excise(r1)
+								gc.Excise(r1)
 							}
-							excise(r2)
+							gc.Excise(r2)
 							return true
 						}
 					}
@@ -1017,7 +900,7 @@ func xtramodes(g *gc.Graph, r *gc.Flow, a *obj.Addr) bool {
 
 			a.Offset = p1.From.Offset
 			p.Scond |= arm.C_PBIT
-			excise(r1)
+			gc.Excise(r1)
 			return true
 		}
 	}
@@ -1659,7 +1542,7 @@ func applypred(rstart *gc.Flow, j *Joininfo, cond int, branch int) {
 	for r := j.start; ; r = successor(r) {
 		if r.Prog.As == arm.AB {
 			if r != j.last || branch == Delbranch {
-				excise(r)
+				gc.Excise(r)
 			} else {
 				if cond == Truecond {
 					r.Prog.As = predinfo[rstart.Prog.As-arm.ABEQ].opcode
@@ -1698,14 +1581,14 @@ func predicate(g *gc.Graph) {
 				if (t1 == Branch && (t2 == Join || t2 == Setcond)) || (t2 == Join && (t1 == Join || t1 == Setcond)) {
 					applypred(r, &j1, Falsecond, Delbranch)
 					applypred(r, &j2, Truecond, Delbranch)
-					excise(r)
+					gc.Excise(r)
 					continue
 				}
 			}
 
 			if t1 == End || t1 == Branch {
 				applypred(r, &j1, Falsecond, Keepbranch)
-				excise(r)
+				gc.Excise(r)
 				continue
 			}
 		}
@@ -1727,8 +1610,3 @@ func stackaddr(a *obj.Addr) bool {
 func smallindir(a *obj.Addr, reg *obj.Addr) bool {
 	return reg.Type == obj.TYPE_REG && a.Type == obj.TYPE_MEM && a.Reg == reg.Reg && 0 <= a.Offset && a.Offset < 4096
 }
-
-func excise(r *gc.Flow) {
-	p := r.Prog
-	obj.Nopout(p)
-}
@@ -6,6 +6,7 @@ package arm
 
 import (
 	"cmd/compile/internal/gc"
+	"cmd/compile/internal/ir"
 	"cmd/compile/internal/ssa"
 	"cmd/internal/obj"
 	"cmd/internal/obj/arm"
@@ -37,7 +38,7 @@ func ssaGenValue(s *gc.SSAGenState, v *ssa.Value) {
 		p.From.Node = n
 		p.From.Sym = gc.Linksym(n.Sym)
 		p.From.Offset = off
-		if n.Class == gc.PPARAM || n.Class == gc.PPARAMOUT {
+		if n.Class == ir.PPARAM || n.Class == ir.PPARAMOUT {
 			p.From.Name = obj.NAME_PARAM
 			p.From.Offset += n.Xoffset
 		} else {
@@ -56,7 +57,7 @@ func ssaGenValue(s *gc.SSAGenState, v *ssa.Value) {
 		p.To.Node = n
 		p.To.Sym = gc.Linksym(n.Sym)
 		p.To.Offset = off
-		if n.Class == gc.PPARAM || n.Class == gc.PPARAMOUT {
+		if n.Class == ir.PPARAM || n.Class == ir.PPARAMOUT {
 			p.To.Name = obj.NAME_PARAM
 			p.To.Offset += n.Xoffset
 		} else {
@@ -112,16 +113,16 @@ func ssaGenValue(s *gc.SSAGenState, v *ssa.Value) {
 		p := gc.Prog(obj.ACALL)
 		p.To.Type = obj.TYPE_MEM
 		p.To.Name = obj.NAME_EXTERN
-		p.To.Sym = gc.Linksym(v.Aux.(*gc.Sym))
+		p.To.Sym = gc.Linksym(v.Aux.(*ir.Sym))
 		if gc.Maxarg < v.AuxInt {
 			gc.Maxarg = v.AuxInt
 		}
 	case ssa.OpVarDef:
-		gc.Gvardef(v.Aux.(*gc.Node))
+		gc.Gvardef(v.Aux.(*ir.Node))
 	case ssa.OpVarKill:
-		gc.Gvarkill(v.Aux.(*gc.Node))
+		gc.Gvarkill(v.Aux.(*ir.Node))
 	case ssa.OpVarLive:
-		gc.Gvarlive(v.Aux.(*gc.Node))
+		gc.Gvarlive(v.Aux.(*ir.Node))
 	case ssa.OpARMLessThan:
 		v.Fatalf("pseudo-op made it to output: %s", v.LongString())
 	default:
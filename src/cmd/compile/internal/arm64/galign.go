@@ -6,64 +6,74 @@ package arm64
 
 import (
 	"cmd/compile/internal/gc"
+	"cmd/internal/obj"
 	"cmd/internal/obj/arm64"
 )
 
-func betypeinit() {
+func init() {
+	gc.RegisterBackend("arm64", backend{})
+}
+
+// backend is the arm64 back end; see gc.Backend.
+type backend struct{}
+
+func (backend) TypeInit() {
 	gc.Widthptr = 8
 	gc.Widthint = 8
 	gc.Widthreg = 8
 }
 
-func Main() {
-	gc.Thearch.Thechar = '7'
-	gc.Thearch.Thestring = "arm64"
-	gc.Thearch.Thelinkarch = &arm64.Linkarm64
-	gc.Thearch.REGSP = arm64.REGSP
-	gc.Thearch.REGCTXT = arm64.REGCTXT
-	gc.Thearch.REGCALLX = arm64.REGRT1
-	gc.Thearch.REGCALLX2 = arm64.REGRT2
-	gc.Thearch.REGRETURN = arm64.REG_R0
-	gc.Thearch.REGMIN = arm64.REG_R0
-	gc.Thearch.REGMAX = arm64.REG_R31
-	gc.Thearch.REGZERO = arm64.REGZERO
-	gc.Thearch.FREGMIN = arm64.REG_F0
-	gc.Thearch.FREGMAX = arm64.REG_F31
-	gc.Thearch.MAXWIDTH = 1 << 50
-	gc.Thearch.ReservedRegs = resvd
+func (backend) Init(arch *gc.Arch) {
+	arch.Thechar = '7'
+	arch.Thestring = "arm64"
+	arch.Thelinkarch = &arm64.Linkarm64
+	arch.REGSP = arm64.REGSP
+	arch.REGCTXT = arm64.REGCTXT
+	arch.REGCALLX = arm64.REGRT1
+	arch.REGCALLX2 = arm64.REGRT2
+	arch.REGRETURN = arm64.REG_R0
+	arch.REGMIN = arm64.REG_R0
+	arch.REGMAX = arm64.REG_R31
+	arch.REGZERO = arm64.REGZERO
+	arch.FREGMIN = arm64.REG_F0
+	arch.FREGMAX = arm64.REG_F31
+	arch.MAXWIDTH = 1 << 50
+	arch.ReservedRegs = resvd
+	arch.Duffzero = gc.DuffRange{Min: 4, Max: 128}
 
-	gc.Thearch.Betypeinit = betypeinit
-	gc.Thearch.Cgen_hmul = cgen_hmul
-	gc.Thearch.Cgen_shift = cgen_shift
-	gc.Thearch.Clearfat = clearfat
-	gc.Thearch.Defframe = defframe
-	gc.Thearch.Dodiv = dodiv
-	gc.Thearch.Excise = excise
-	gc.Thearch.Expandchecks = expandchecks
-	gc.Thearch.Getg = getg
-	gc.Thearch.Gins = gins
-	gc.Thearch.Ginscmp = ginscmp
-	gc.Thearch.Ginscon = ginscon
-	gc.Thearch.Ginsnop = ginsnop
-	gc.Thearch.Gmove = gmove
-	gc.Thearch.Peep = peep
-	gc.Thearch.Proginfo = proginfo
-	gc.Thearch.Regtyp = regtyp
-	gc.Thearch.Sameaddr = sameaddr
-	gc.Thearch.Smallindir = smallindir
-	gc.Thearch.Stackaddr = stackaddr
-	gc.Thearch.Blockcopy = blockcopy
-	gc.Thearch.Sudoaddable = sudoaddable
-	gc.Thearch.Sudoclean = sudoclean
-	gc.Thearch.Excludedregs = excludedregs
-	gc.Thearch.RtoB = RtoB
-	gc.Thearch.FtoB = RtoB
-	gc.Thearch.BtoR = BtoR
-	gc.Thearch.BtoF = BtoF
-	gc.Thearch.Optoas = optoas
-	gc.Thearch.Doregbits = doregbits
-	gc.Thearch.Regnames = regnames
+	arch.Cgen_hmul = cgen_hmul
+	arch.Cgen_shift = cgen_shift
+	arch.Copyas = copyas
+	arch.Copyu = copyu
+	arch.Clearfat = clearfat
+	arch.Dodiv = dodiv
+	arch.Expandchecks = expandchecks
+	arch.Getg = getg
+	arch.Gins = gins
+	arch.Ginscmp = ginscmp
+	arch.Ginscon = ginscon
+	arch.Ginsnop = ginsnop
+	arch.Gmove = gmove
+	arch.Proginfo = proginfo
+	arch.Regtyp = regtyp
+	arch.Sameaddr = sameaddr
+	arch.Smallindir = smallindir
+	arch.Stackaddr = stackaddr
+	arch.Blockcopy = blockcopy
+	arch.Sudoaddable = sudoaddable
+	arch.Sudoclean = sudoclean
+	arch.Excludedregs = excludedregs
+	arch.RtoB = RtoB
+	arch.FtoB = RtoB
+	arch.BtoR = BtoR
+	arch.BtoF = BtoF
+	arch.Optoas = optoas
+	arch.Doregbits = doregbits
+	arch.Regnames = regnames
+	arch.PadFrame = padframe
+	arch.ZeroRange = zerorange
+}
 
-	gc.Main()
-	gc.Exit(0)
+func (backend) Peep(firstp *obj.Prog) {
+	peep(firstp)
 }
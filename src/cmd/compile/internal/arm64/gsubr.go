@@ -32,6 +32,7 @@ package arm64
 
 import (
 	"cmd/compile/internal/gc"
+	"cmd/compile/internal/ir"
 	"cmd/internal/obj"
 	"cmd/internal/obj/arm64"
 	"fmt"
@@ -53,16 +54,16 @@ var resvd = []int{
  * generate
  *	as $c, n
  */
-func ginscon(as obj.As, c int64, n2 *gc.Node) {
-	var n1 gc.Node
+func ginscon(as obj.As, c int64, n2 *ir.Node) {
+	var n1 ir.Node
 
-	gc.Nodconst(&n1, gc.Types[gc.TINT64], c)
+	gc.Nodconst(&n1, ir.Types[ir.TINT64], c)
 
-	if as != arm64.AMOVD && (c < -arm64.BIG || c > arm64.BIG) || as == arm64.AMUL || n2 != nil && n2.Op != gc.OREGISTER {
+	if as != arm64.AMOVD && (c < -arm64.BIG || c > arm64.BIG) || as == arm64.AMUL || n2 != nil && n2.Op != ir.OREGISTER {
 		// cannot have more than 16-bit of immediate in ADD, etc.
 		// instead, MOV into register first.
-		var ntmp gc.Node
-		gc.Regalloc(&ntmp, gc.Types[gc.TINT64], nil)
+		var ntmp ir.Node
+		gc.Regalloc(&ntmp, ir.Types[ir.TINT64], nil)
 
 		gins(arm64.AMOVD, &n1, &ntmp)
 		gins(as, &ntmp, n2)
@@ -77,10 +78,10 @@ func ginscon(as obj.As, c int64, n2 *gc.Node) {
  * generate
  *	as n, $c (CMP)
  */
-func ginscon2(as obj.As, n2 *gc.Node, c int64) {
-	var n1 gc.Node
+func ginscon2(as obj.As, n2 *ir.Node, c int64) {
+	var n1 ir.Node
 
-	gc.Nodconst(&n1, gc.Types[gc.TINT64], c)
+	gc.Nodconst(&n1, ir.Types[ir.TINT64], c)
 
 	switch as {
 	default:
@@ -94,34 +95,34 @@ func ginscon2(as obj.As, n2 *gc.Node, c int64) {
 	}
 
 	// MOV n1 into register first
-	var ntmp gc.Node
-	gc.Regalloc(&ntmp, gc.Types[gc.TINT64], nil)
+	var ntmp ir.Node
+	gc.Regalloc(&ntmp, ir.Types[ir.TINT64], nil)
 
 	rawgins(arm64.AMOVD, &n1, &ntmp)
 	gcmp(as, n2, &ntmp)
 	gc.Regfree(&ntmp)
 }
 
-func ginscmp(op gc.Op, t *gc.Type, n1, n2 *gc.Node, likely int) *obj.Prog {
-	if gc.Isint[t.Etype] && n1.Op == gc.OLITERAL && n2.Op != gc.OLITERAL {
+func ginscmp(op ir.Op, t *ir.Type, n1, n2 *ir.Node, likely int) *obj.Prog {
+	if gc.Isint[t.Etype] && n1.Op == ir.OLITERAL && n2.Op != ir.OLITERAL {
 		// Reverse comparison to place constant last.
 		op = gc.Brrev(op)
 		n1, n2 = n2, n1
 	}
 
-	var r1, r2, g1, g2 gc.Node
+	var r1, r2, g1, g2 ir.Node
 	gc.Regalloc(&r1, t, n1)
 	gc.Regalloc(&g1, n1.Type, &r1)
 	gc.Cgen(n1, &g1)
 	gmove(&g1, &r1)
-	if gc.Isint[t.Etype] && gc.Isconst(n2, gc.CTINT) {
-		ginscon2(optoas(gc.OCMP, t), &r1, n2.Int())
+	if gc.Isint[t.Etype] && ir.Isconst(n2, ir.CTINT) {
+		ginscon2(optoas(ir.OCMP, t), &r1, n2.Int())
 	} else {
 		gc.Regalloc(&r2, t, n2)
 		gc.Regalloc(&g2, n1.Type, &r2)
 		gc.Cgen(n2, &g2)
 		gmove(&g2, &r2)
-		gcmp(optoas(gc.OCMP, t), &r1, &r2)
+		gcmp(optoas(ir.OCMP, t), &r1, &r2)
 		gc.Regfree(&g2)
 		gc.Regfree(&r2)
 	}
@@ -135,9 +136,9 @@ func ginscmp(op gc.Op, t *gc.Type, n1, n2 *gc.Node, likely int) *obj.Prog {
  *	t = f
  * hard part is conversions.
  */
-func gmove(f *gc.Node, t *gc.Node) {
+func gmove(f *ir.Node, t *ir.Node) {
 	if gc.Debug['M'] != 0 {
-		fmt.Printf("gmove %v -> %v\n", gc.Nconv(f, gc.FmtLong), gc.Nconv(t, gc.FmtLong))
+		fmt.Printf("gmove %v -> %v\n", gc.Nconv(f, ir.FmtLong), gc.Nconv(t, ir.FmtLong))
 	}
 
 	ft := int(gc.Simsimtype(f.Type))
@@ -150,37 +151,37 @@ func gmove(f *gc.Node, t *gc.Node) {
 	}
 
 	// cannot have two memory operands
-	var r1 gc.Node
+	var r1 ir.Node
 	var a obj.As
 	if gc.Ismem(f) && gc.Ismem(t) {
 		goto hard
 	}
 
 	// convert constant to desired type
-	if f.Op == gc.OLITERAL {
-		var con gc.Node
+	if f.Op == ir.OLITERAL {
+		var con ir.Node
 		switch tt {
 		default:
-			f.Convconst(&con, t.Type)
-
-		case gc.TINT32,
-			gc.TINT16,
-			gc.TINT8:
-			var con gc.Node
-			f.Convconst(&con, gc.Types[gc.TINT64])
-			var r1 gc.Node
+			gc.Convconst(f, &con, t.Type)
+
+		case ir.TINT32,
+			ir.TINT16,
+			ir.TINT8:
+			var con ir.Node
+			gc.Convconst(f, &con, ir.Types[ir.TINT64])
+			var r1 ir.Node
 			gc.Regalloc(&r1, con.Type, t)
 			gins(arm64.AMOVD, &con, &r1)
 			gmove(&r1, t)
 			gc.Regfree(&r1)
 			return
 
-		case gc.TUINT32,
-			gc.TUINT16,
-			gc.TUINT8:
-			var con gc.Node
-			f.Convconst(&con, gc.Types[gc.TUINT64])
-			var r1 gc.Node
+		case ir.TUINT32,
+			ir.TUINT16,
+			ir.TUINT8:
+			var con ir.Node
+			gc.Convconst(f, &con, ir.Types[ir.TUINT64])
+			var r1 ir.Node
 			gc.Regalloc(&r1, con.Type, t)
 			gins(arm64.AMOVD, &con, &r1)
 			gmove(&r1, t)
@@ -201,7 +202,7 @@ func gmove(f *gc.Node, t *gc.Node) {
 	// any floating point operand requires register
 	// src, so goto hard to copy to register first.
 	if gc.Ismem(f) && ft != tt && (gc.Isfloat[ft] || gc.Isfloat[tt]) {
-		cvt = gc.Types[ft]
+		cvt = ir.Types[ft]
 		goto hard
 	}
 
@@ -214,117 +215,117 @@ func gmove(f *gc.Node, t *gc.Node) {
 
 	switch uint32(ft)<<16 | uint32(tt) {
 	default:
-		gc.Fatalf("gmove %v -> %v", gc.Tconv(f.Type, gc.FmtLong), gc.Tconv(t.Type, gc.FmtLong))
+		gc.Fatalf("gmove %v -> %v", gc.Tconv(f.Type, ir.FmtLong), gc.Tconv(t.Type, ir.FmtLong))
 
 		/*
 		 * integer copy and truncate
 		 */
-	case gc.TINT8<<16 | gc.TINT8, // same size
-		gc.TUINT8<<16 | gc.TINT8,
-		gc.TINT16<<16 | gc.TINT8,
+	case ir.TINT8<<16 | ir.TINT8, // same size
+		ir.TUINT8<<16 | ir.TINT8,
+		ir.TINT16<<16 | ir.TINT8,
 		// truncate
-		gc.TUINT16<<16 | gc.TINT8,
-		gc.TINT32<<16 | gc.TINT8,
-		gc.TUINT32<<16 | gc.TINT8,
-		gc.TINT64<<16 | gc.TINT8,
-		gc.TUINT64<<16 | gc.TINT8:
+		ir.TUINT16<<16 | ir.TINT8,
+		ir.TINT32<<16 | ir.TINT8,
+		ir.TUINT32<<16 | ir.TINT8,
+		ir.TINT64<<16 | ir.TINT8,
+		ir.TUINT64<<16 | ir.TINT8:
 		a = arm64.AMOVB
 
-	case gc.TINT8<<16 | gc.TUINT8, // same size
-		gc.TUINT8<<16 | gc.TUINT8,
-		gc.TINT16<<16 | gc.TUINT8,
+	case ir.TINT8<<16 | ir.TUINT8, // same size
+		ir.TUINT8<<16 | ir.TUINT8,
+		ir.TINT16<<16 | ir.TUINT8,
 		// truncate
-		gc.TUINT16<<16 | gc.TUINT8,
-		gc.TINT32<<16 | gc.TUINT8,
-		gc.TUINT32<<16 | gc.TUINT8,
-		gc.TINT64<<16 | gc.TUINT8,
-		gc.TUINT64<<16 | gc.TUINT8:
+		ir.TUINT16<<16 | ir.TUINT8,
+		ir.TINT32<<16 | ir.TUINT8,
+		ir.TUINT32<<16 | ir.TUINT8,
+		ir.TINT64<<16 | ir.TUINT8,
+		ir.TUINT64<<16 | ir.TUINT8:
 		a = arm64.AMOVBU
 
-	case gc.TINT16<<16 | gc.TINT16, // same size
-		gc.TUINT16<<16 | gc.TINT16,
-		gc.TINT32<<16 | gc.TINT16,
+	case ir.TINT16<<16 | ir.TINT16, // same size
+		ir.TUINT16<<16 | ir.TINT16,
+		ir.TINT32<<16 | ir.TINT16,
 		// truncate
-		gc.TUINT32<<16 | gc.TINT16,
-		gc.TINT64<<16 | gc.TINT16,
-		gc.TUINT64<<16 | gc.TINT16:
+		ir.TUINT32<<16 | ir.TINT16,
+		ir.TINT64<<16 | ir.TINT16,
+		ir.TUINT64<<16 | ir.TINT16:
 		a = arm64.AMOVH
 
-	case gc.TINT16<<16 | gc.TUINT16, // same size
-		gc.TUINT16<<16 | gc.TUINT16,
-		gc.TINT32<<16 | gc.TUINT16,
+	case ir.TINT16<<16 | ir.TUINT16, // same size
+		ir.TUINT16<<16 | ir.TUINT16,
+		ir.TINT32<<16 | ir.TUINT16,
 		// truncate
-		gc.TUINT32<<16 | gc.TUINT16,
-		gc.TINT64<<16 | gc.TUINT16,
-		gc.TUINT64<<16 | gc.TUINT16:
+		ir.TUINT32<<16 | ir.TUINT16,
+		ir.TINT64<<16 | ir.TUINT16,
+		ir.TUINT64<<16 | ir.TUINT16:
 		a = arm64.AMOVHU
 
-	case gc.TINT32<<16 | gc.TINT32, // same size
-		gc.TUINT32<<16 | gc.TINT32,
-		gc.TINT64<<16 | gc.TINT32,
+	case ir.TINT32<<16 | ir.TINT32, // same size
+		ir.TUINT32<<16 | ir.TINT32,
+		ir.TINT64<<16 | ir.TINT32,
 		// truncate
-		gc.TUINT64<<16 | gc.TINT32:
+		ir.TUINT64<<16 | ir.TINT32:
 		a = arm64.AMOVW
 
-	case gc.TINT32<<16 | gc.TUINT32, // same size
-		gc.TUINT32<<16 | gc.TUINT32,
-		gc.TINT64<<16 | gc.TUINT32,
-		gc.TUINT64<<16 | gc.TUINT32:
+	case ir.TINT32<<16 | ir.TUINT32, // same size
+		ir.TUINT32<<16 | ir.TUINT32,
+		ir.TINT64<<16 | ir.TUINT32,
+		ir.TUINT64<<16 | ir.TUINT32:
 		a = arm64.AMOVWU
 
-	case gc.TINT64<<16 | gc.TINT64, // same size
-		gc.TINT64<<16 | gc.TUINT64,
-		gc.TUINT64<<16 | gc.TINT64,
-		gc.TUINT64<<16 | gc.TUINT64:
+	case ir.TINT64<<16 | ir.TINT64, // same size
+		ir.TINT64<<16 | ir.TUINT64,
+		ir.TUINT64<<16 | ir.TINT64,
+		ir.TUINT64<<16 | ir.TUINT64:
 		a = arm64.AMOVD
 
 		/*
 		 * integer up-conversions
 		 */
-	case gc.TINT8<<16 | gc.TINT16, // sign extend int8
-		gc.TINT8<<16 | gc.TUINT16,
-		gc.TINT8<<16 | gc.TINT32,
-		gc.TINT8<<16 | gc.TUINT32,
-		gc.TINT8<<16 | gc.TINT64,
-		gc.TINT8<<16 | gc.TUINT64:
+	case ir.TINT8<<16 | ir.TINT16, // sign extend int8
+		ir.TINT8<<16 | ir.TUINT16,
+		ir.TINT8<<16 | ir.TINT32,
+		ir.TINT8<<16 | ir.TUINT32,
+		ir.TINT8<<16 | ir.TINT64,
+		ir.TINT8<<16 | ir.TUINT64:
 		a = arm64.AMOVB
 
 		goto rdst
 
-	case gc.TUINT8<<16 | gc.TINT16, // zero extend uint8
-		gc.TUINT8<<16 | gc.TUINT16,
-		gc.TUINT8<<16 | gc.TINT32,
-		gc.TUINT8<<16 | gc.TUINT32,
-		gc.TUINT8<<16 | gc.TINT64,
-		gc.TUINT8<<16 | gc.TUINT64:
+	case ir.TUINT8<<16 | ir.TINT16, // zero extend uint8
+		ir.TUINT8<<16 | ir.TUINT16,
+		ir.TUINT8<<16 | ir.TINT32,
+		ir.TUINT8<<16 | ir.TUINT32,
+		ir.TUINT8<<16 | ir.TINT64,
+		ir.TUINT8<<16 | ir.TUINT64:
 		a = arm64.AMOVBU
 
 		goto rdst
 
-	case gc.TINT16<<16 | gc.TINT32, // sign extend int16
-		gc.TINT16<<16 | gc.TUINT32,
-		gc.TINT16<<16 | gc.TINT64,
-		gc.TINT16<<16 | gc.TUINT64:
+	case ir.TINT16<<16 | ir.TINT32, // sign extend int16
+		ir.TINT16<<16 | ir.TUINT32,
+		ir.TINT16<<16 | ir.TINT64,
+		ir.TINT16<<16 | ir.TUINT64:
 		a = arm64.AMOVH
 
 		goto rdst
 
-	case gc.TUINT16<<16 | gc.TINT32, // zero extend uint16
-		gc.TUINT16<<16 | gc.TUINT32,
-		gc.TUINT16<<16 | gc.TINT64,
-		gc.TUINT16<<16 | gc.TUINT64:
+	case ir.TUINT16<<16 | ir.TINT32, // zero extend uint16
+		ir.TUINT16<<16 | ir.TUINT32,
+		ir.TUINT16<<16 | ir.TINT64,
+		ir.TUINT16<<16 | ir.TUINT64:
 		a = arm64.AMOVHU
 
 		goto rdst
 
-	case gc.TINT32<<16 | gc.TINT64, // sign extend int32
-		gc.TINT32<<16 | gc.TUINT64:
+	case ir.TINT32<<16 | ir.TINT64, // sign extend int32
+		ir.TINT32<<16 | ir.TUINT64:
 		a = arm64.AMOVW
 
 		goto rdst
 
-	case gc.TUINT32<<16 | gc.TINT64, // zero extend uint32
-		gc.TUINT32<<16 | gc.TUINT64:
+	case ir.TUINT32<<16 | ir.TINT64, // zero extend uint32
+		ir.TUINT32<<16 | ir.TUINT64:
 		a = arm64.AMOVWU
 
 		goto rdst
@@ -332,115 +333,115 @@ func gmove(f *gc.Node, t *gc.Node) {
 	/*
 	* float to integer
 	 */
-	case gc.TFLOAT32<<16 | gc.TINT32:
+	case ir.TFLOAT32<<16 | ir.TINT32:
 		a = arm64.AFCVTZSSW
 		goto rdst
 
-	case gc.TFLOAT64<<16 | gc.TINT32:
+	case ir.TFLOAT64<<16 | ir.TINT32:
 		a = arm64.AFCVTZSDW
 		goto rdst
 
-	case gc.TFLOAT32<<16 | gc.TINT64:
+	case ir.TFLOAT32<<16 | ir.TINT64:
 		a = arm64.AFCVTZSS
 		goto rdst
 
-	case gc.TFLOAT64<<16 | gc.TINT64:
+	case ir.TFLOAT64<<16 | ir.TINT64:
 		a = arm64.AFCVTZSD
 		goto rdst
 
-	case gc.TFLOAT32<<16 | gc.TUINT32:
+	case ir.TFLOAT32<<16 | ir.TUINT32:
 		a = arm64.AFCVTZUSW
 		goto rdst
 
-	case gc.TFLOAT64<<16 | gc.TUINT32:
+	case ir.TFLOAT64<<16 | ir.TUINT32:
 		a = arm64.AFCVTZUDW
 		goto rdst
 
-	case gc.TFLOAT32<<16 | gc.TUINT64:
+	case ir.TFLOAT32<<16 | ir.TUINT64:
 		a = arm64.AFCVTZUS
 		goto rdst
 
-	case gc.TFLOAT64<<16 | gc.TUINT64:
+	case ir.TFLOAT64<<16 | ir.TUINT64:
 		a = arm64.AFCVTZUD
 		goto rdst
 
-	case gc.TFLOAT32<<16 | gc.TINT16,
-		gc.TFLOAT32<<16 | gc.TINT8,
-		gc.TFLOAT64<<16 | gc.TINT16,
-		gc.TFLOAT64<<16 | gc.TINT8:
-		cvt = gc.Types[gc.TINT32]
+	case ir.TFLOAT32<<16 | ir.TINT16,
+		ir.TFLOAT32<<16 | ir.TINT8,
+		ir.TFLOAT64<<16 | ir.TINT16,
+		ir.TFLOAT64<<16 | ir.TINT8:
+		cvt = ir.Types[ir.TINT32]
 
 		goto hard
 
-	case gc.TFLOAT32<<16 | gc.TUINT16,
-		gc.TFLOAT32<<16 | gc.TUINT8,
-		gc.TFLOAT64<<16 | gc.TUINT16,
-		gc.TFLOAT64<<16 | gc.TUINT8:
-		cvt = gc.Types[gc.TUINT32]
+	case ir.TFLOAT32<<16 | ir.TUINT16,
+		ir.TFLOAT32<<16 | ir.TUINT8,
+		ir.TFLOAT64<<16 | ir.TUINT16,
+		ir.TFLOAT64<<16 | ir.TUINT8:
+		cvt = ir.Types[ir.TUINT32]
 
 		goto hard
 
 	/*
 	 * integer to float
 	 */
-	case gc.TINT8<<16 | gc.TFLOAT32,
-		gc.TINT16<<16 | gc.TFLOAT32,
-		gc.TINT32<<16 | gc.TFLOAT32:
+	case ir.TINT8<<16 | ir.TFLOAT32,
+		ir.TINT16<<16 | ir.TFLOAT32,
+		ir.TINT32<<16 | ir.TFLOAT32:
 		a = arm64.ASCVTFWS
 
 		goto rdst
 
-	case gc.TINT8<<16 | gc.TFLOAT64,
-		gc.TINT16<<16 | gc.TFLOAT64,
-		gc.TINT32<<16 | gc.TFLOAT64:
+	case ir.TINT8<<16 | ir.TFLOAT64,
+		ir.TINT16<<16 | ir.TFLOAT64,
+		ir.TINT32<<16 | ir.TFLOAT64:
 		a = arm64.ASCVTFWD
 
 		goto rdst
 
-	case gc.TINT64<<16 | gc.TFLOAT32:
+	case ir.TINT64<<16 | ir.TFLOAT32:
 		a = arm64.ASCVTFS
 		goto rdst
 
-	case gc.TINT64<<16 | gc.TFLOAT64:
+	case ir.TINT64<<16 | ir.TFLOAT64:
 		a = arm64.ASCVTFD
 		goto rdst
 
-	case gc.TUINT8<<16 | gc.TFLOAT32,
-		gc.TUINT16<<16 | gc.TFLOAT32,
-		gc.TUINT32<<16 | gc.TFLOAT32:
+	case ir.TUINT8<<16 | ir.TFLOAT32,
+		ir.TUINT16<<16 | ir.TFLOAT32,
+		ir.TUINT32<<16 | ir.TFLOAT32:
 		a = arm64.AUCVTFWS
 
 		goto rdst
 
-	case gc.TUINT8<<16 | gc.TFLOAT64,
-		gc.TUINT16<<16 | gc.TFLOAT64,
-		gc.TUINT32<<16 | gc.TFLOAT64:
+	case ir.TUINT8<<16 | ir.TFLOAT64,
+		ir.TUINT16<<16 | ir.TFLOAT64,
+		ir.TUINT32<<16 | ir.TFLOAT64:
 		a = arm64.AUCVTFWD
 
 		goto rdst
 
-	case gc.TUINT64<<16 | gc.TFLOAT32:
+	case ir.TUINT64<<16 | ir.TFLOAT32:
 		a = arm64.AUCVTFS
 		goto rdst
 
-	case gc.TUINT64<<16 | gc.TFLOAT64:
+	case ir.TUINT64<<16 | ir.TFLOAT64:
 		a = arm64.AUCVTFD
 		goto rdst
 
 		/*
 		 * float to float
 		 */
-	case gc.TFLOAT32<<16 | gc.TFLOAT32:
+	case ir.TFLOAT32<<16 | ir.TFLOAT32:
 		a = arm64.AFMOVS
 
-	case gc.TFLOAT64<<16 | gc.TFLOAT64:
+	case ir.TFLOAT64<<16 | ir.TFLOAT64:
 		a = arm64.AFMOVD
 
-	case gc.TFLOAT32<<16 | gc.TFLOAT64:
+	case ir.TFLOAT32<<16 | ir.TFLOAT64:
 		a = arm64.AFCVTSD
 		goto rdst
 
-	case gc.TFLOAT64<<16 | gc.TFLOAT32:
+	case ir.TFLOAT64<<16 | ir.TFLOAT32:
 		a = arm64.AFCVTDS
 		goto rdst
 	}
@@ -470,7 +471,7 @@ hard:
 // gins is called by the front end.
 // It synthesizes some multiple-instruction sequences
 // so the front end can stay simpler.
-func gins(as obj.As, f, t *gc.Node) *obj.Prog {
+func gins(as obj.As, f, t *ir.Node) *obj.Prog {
 	if as >= obj.A_ARCHSPECIFIC {
 		if x, ok := f.IntLiteral(); ok {
 			ginscon(as, x, t)
@@ -490,7 +491,7 @@ func gins(as obj.As, f, t *gc.Node) *obj.Prog {
  * generate one instruction:
  *	as f, t
  */
-func rawgins(as obj.As, f *gc.Node, t *gc.Node) *obj.Prog {
+func rawgins(as obj.As, f *ir.Node, t *ir.Node) *obj.Prog {
 	// TODO(austin): Add self-move test like in 6g (but be careful
 	// of truncation moves)
 
@@ -501,7 +502,7 @@ func rawgins(as obj.As, f *gc.Node, t *gc.Node) *obj.Prog {
 	switch as {
 	case arm64.ACMP, arm64.AFCMPS, arm64.AFCMPD:
 		if t != nil {
-			if f.Op != gc.OREGISTER {
+			if f.Op != ir.OREGISTER {
 				gc.Fatalf("bad operands to gcmp")
 			}
 			p.From = p.To
@@ -561,7 +562,7 @@ func rawgins(as obj.As, f *gc.Node, t *gc.Node) *obj.Prog {
 /*
  * insert n into reg slot of p
  */
-func raddr(n *gc.Node, p *obj.Prog) {
+func raddr(n *ir.Node, p *obj.Prog) {
 	var a obj.Addr
 
 	gc.Naddr(&a, n)
@@ -577,8 +578,8 @@ func raddr(n *gc.Node, p *obj.Prog) {
 	}
 }
 
-func gcmp(as obj.As, lhs *gc.Node, rhs *gc.Node) *obj.Prog {
-	if lhs.Op != gc.OREGISTER {
+func gcmp(as obj.As, lhs *ir.Node, rhs *ir.Node) *obj.Prog {
+	if lhs.Op != ir.OREGISTER {
 		gc.Fatalf("bad operands to gcmp: %v %v", gc.Oconv(lhs.Op, 0), gc.Oconv(rhs.Op, 0))
 	}
 
@@ -590,33 +591,33 @@ func gcmp(as obj.As, lhs *gc.Node, rhs *gc.Node) *obj.Prog {
 /*
  * return Axxx for Oxxx on type t.
  */
-func optoas(op gc.Op, t *gc.Type) obj.As {
+func optoas(op ir.Op, t *ir.Type) obj.As {
 	if t == nil {
 		gc.Fatalf("optoas: t is nil")
 	}
 
 	// avoid constant conversions in switches below
 	const (
-		OMINUS_ = uint32(gc.OMINUS) << 16
-		OLSH_   = uint32(gc.OLSH) << 16
-		ORSH_   = uint32(gc.ORSH) << 16
-		OADD_   = uint32(gc.OADD) << 16
-		OSUB_   = uint32(gc.OSUB) << 16
-		OMUL_   = uint32(gc.OMUL) << 16
-		ODIV_   = uint32(gc.ODIV) << 16
-		OOR_    = uint32(gc.OOR) << 16
-		OAND_   = uint32(gc.OAND) << 16
-		OXOR_   = uint32(gc.OXOR) << 16
-		OEQ_    = uint32(gc.OEQ) << 16
-		ONE_    = uint32(gc.ONE) << 16
-		OLT_    = uint32(gc.OLT) << 16
-		OLE_    = uint32(gc.OLE) << 16
-		OGE_    = uint32(gc.OGE) << 16
-		OGT_    = uint32(gc.OGT) << 16
-		OCMP_   = uint32(gc.OCMP) << 16
-		OAS_    = uint32(gc.OAS) << 16
-		OHMUL_  = uint32(gc.OHMUL) << 16
-		OSQRT_  = uint32(gc.OSQRT) << 16
+		OMINUS_ = uint32(ir.OMINUS) << 16
+		OLSH_   = uint32(ir.OLSH) << 16
+		ORSH_   = uint32(ir.ORSH) << 16
+		OADD_   = uint32(ir.OADD) << 16
+		OSUB_   = uint32(ir.OSUB) << 16
+		OMUL_   = uint32(ir.OMUL) << 16
+		ODIV_   = uint32(ir.ODIV) << 16
+		OOR_    = uint32(ir.OOR) << 16
+		OAND_   = uint32(ir.OAND) << 16
+		OXOR_   = uint32(ir.OXOR) << 16
+		OEQ_    = uint32(ir.OEQ) << 16
+		ONE_    = uint32(ir.ONE) << 16
+		OLT_    = uint32(ir.OLT) << 16
+		OLE_    = uint32(ir.OLE) << 16
+		OGE_    = uint32(ir.OGE) << 16
+		OGT_    = uint32(ir.OGT) << 16
+		OCMP_   = uint32(ir.OCMP) << 16
+		OAS_    = uint32(ir.OAS) << 16
+		OHMUL_  = uint32(ir.OHMUL) << 16
+		OSQRT_  = uint32(ir.OSQRT) << 16
 	)
 
 	a := obj.AXXX
@@ -624,230 +625,230 @@ func optoas(op gc.Op, t *gc.Type) obj.As {
 	default:
 		gc.Fatalf("optoas: no entry for op=%v type=%v", gc.Oconv(op, 0), t)
 
-	case OEQ_ | gc.TBOOL,
-		OEQ_ | gc.TINT8,
-		OEQ_ | gc.TUINT8,
-		OEQ_ | gc.TINT16,
-		OEQ_ | gc.TUINT16,
-		OEQ_ | gc.TINT32,
-		OEQ_ | gc.TUINT32,
-		OEQ_ | gc.TINT64,
-		OEQ_ | gc.TUINT64,
-		OEQ_ | gc.TPTR32,
-		OEQ_ | gc.TPTR64,
-		OEQ_ | gc.TFLOAT32,
-		OEQ_ | gc.TFLOAT64:
+	case OEQ_ | ir.TBOOL,
+		OEQ_ | ir.TINT8,
+		OEQ_ | ir.TUINT8,
+		OEQ_ | ir.TINT16,
+		OEQ_ | ir.TUINT16,
+		OEQ_ | ir.TINT32,
+		OEQ_ | ir.TUINT32,
+		OEQ_ | ir.TINT64,
+		OEQ_ | ir.TUINT64,
+		OEQ_ | ir.TPTR32,
+		OEQ_ | ir.TPTR64,
+		OEQ_ | ir.TFLOAT32,
+		OEQ_ | ir.TFLOAT64:
 		a = arm64.ABEQ
 
-	case ONE_ | gc.TBOOL,
-		ONE_ | gc.TINT8,
-		ONE_ | gc.TUINT8,
-		ONE_ | gc.TINT16,
-		ONE_ | gc.TUINT16,
-		ONE_ | gc.TINT32,
-		ONE_ | gc.TUINT32,
-		ONE_ | gc.TINT64,
-		ONE_ | gc.TUINT64,
-		ONE_ | gc.TPTR32,
-		ONE_ | gc.TPTR64,
-		ONE_ | gc.TFLOAT32,
-		ONE_ | gc.TFLOAT64:
+	case ONE_ | ir.TBOOL,
+		ONE_ | ir.TINT8,
+		ONE_ | ir.TUINT8,
+		ONE_ | ir.TINT16,
+		ONE_ | ir.TUINT16,
+		ONE_ | ir.TINT32,
+		ONE_ | ir.TUINT32,
+		ONE_ | ir.TINT64,
+		ONE_ | ir.TUINT64,
+		ONE_ | ir.TPTR32,
+		ONE_ | ir.TPTR64,
+		ONE_ | ir.TFLOAT32,
+		ONE_ | ir.TFLOAT64:
 		a = arm64.ABNE
 
-	case OLT_ | gc.TINT8,
-		OLT_ | gc.TINT16,
-		OLT_ | gc.TINT32,
-		OLT_ | gc.TINT64:
+	case OLT_ | ir.TINT8,
+		OLT_ | ir.TINT16,
+		OLT_ | ir.TINT32,
+		OLT_ | ir.TINT64:
 		a = arm64.ABLT
 
-	case OLT_ | gc.TUINT8,
-		OLT_ | gc.TUINT16,
-		OLT_ | gc.TUINT32,
-		OLT_ | gc.TUINT64,
-		OLT_ | gc.TFLOAT32,
-		OLT_ | gc.TFLOAT64:
+	case OLT_ | ir.TUINT8,
+		OLT_ | ir.TUINT16,
+		OLT_ | ir.TUINT32,
+		OLT_ | ir.TUINT64,
+		OLT_ | ir.TFLOAT32,
+		OLT_ | ir.TFLOAT64:
 		a = arm64.ABLO
 
-	case OLE_ | gc.TINT8,
-		OLE_ | gc.TINT16,
-		OLE_ | gc.TINT32,
-		OLE_ | gc.TINT64:
+	case OLE_ | ir.TINT8,
+		OLE_ | ir.TINT16,
+		OLE_ | ir.TINT32,
+		OLE_ | ir.TINT64:
 		a = arm64.ABLE
 
-	case OLE_ | gc.TUINT8,
-		OLE_ | gc.TUINT16,
-		OLE_ | gc.TUINT32,
-		OLE_ | gc.TUINT64,
-		OLE_ | gc.TFLOAT32,
-		OLE_ | gc.TFLOAT64:
+	case OLE_ | ir.TUINT8,
+		OLE_ | ir.TUINT16,
+		OLE_ | ir.TUINT32,
+		OLE_ | ir.TUINT64,
+		OLE_ | ir.TFLOAT32,
+		OLE_ | ir.TFLOAT64:
 		a = arm64.ABLS
 
-	case OGT_ | gc.TINT8,
-		OGT_ | gc.TINT16,
-		OGT_ | gc.TINT32,
-		OGT_ | gc.TINT64,
-		OGT_ | gc.TFLOAT32,
-		OGT_ | gc.TFLOAT64:
+	case OGT_ | ir.TINT8,
+		OGT_ | ir.TINT16,
+		OGT_ | ir.TINT32,
+		OGT_ | ir.TINT64,
+		OGT_ | ir.TFLOAT32,
+		OGT_ | ir.TFLOAT64:
 		a = arm64.ABGT
 
-	case OGT_ | gc.TUINT8,
-		OGT_ | gc.TUINT16,
-		OGT_ | gc.TUINT32,
-		OGT_ | gc.TUINT64:
+	case OGT_ | ir.TUINT8,
+		OGT_ | ir.TUINT16,
+		OGT_ | ir.TUINT32,
+		OGT_ | ir.TUINT64:
 		a = arm64.ABHI
 
-	case OGE_ | gc.TINT8,
-		OGE_ | gc.TINT16,
-		OGE_ | gc.TINT32,
-		OGE_ | gc.TINT64,
-		OGE_ | gc.TFLOAT32,
-		OGE_ | gc.TFLOAT64:
+	case OGE_ | ir.TINT8,
+		OGE_ | ir.TINT16,
+		OGE_ | ir.TINT32,
+		OGE_ | ir.TINT64,
+		OGE_ | ir.TFLOAT32,
+		OGE_ | ir.TFLOAT64:
 		a = arm64.ABGE
 
-	case OGE_ | gc.TUINT8,
-		OGE_ | gc.TUINT16,
-		OGE_ | gc.TUINT32,
-		OGE_ | gc.TUINT64:
+	case OGE_ | ir.TUINT8,
+		OGE_ | ir.TUINT16,
+		OGE_ | ir.TUINT32,
+		OGE_ | ir.TUINT64:
 		a = arm64.ABHS
 
-	case OCMP_ | gc.TBOOL,
-		OCMP_ | gc.TINT8,
-		OCMP_ | gc.TINT16,
-		OCMP_ | gc.TINT32,
-		OCMP_ | gc.TPTR32,
-		OCMP_ | gc.TINT64,
-		OCMP_ | gc.TUINT8,
-		OCMP_ | gc.TUINT16,
-		OCMP_ | gc.TUINT32,
-		OCMP_ | gc.TUINT64,
-		OCMP_ | gc.TPTR64:
+	case OCMP_ | ir.TBOOL,
+		OCMP_ | ir.TINT8,
+		OCMP_ | ir.TINT16,
+		OCMP_ | ir.TINT32,
+		OCMP_ | ir.TPTR32,
+		OCMP_ | ir.TINT64,
+		OCMP_ | ir.TUINT8,
+		OCMP_ | ir.TUINT16,
+		OCMP_ | ir.TUINT32,
+		OCMP_ | ir.TUINT64,
+		OCMP_ | ir.TPTR64:
 		a = arm64.ACMP
 
-	case OCMP_ | gc.TFLOAT32:
+	case OCMP_ | ir.TFLOAT32:
 		a = arm64.AFCMPS
 
-	case OCMP_ | gc.TFLOAT64:
+	case OCMP_ | ir.TFLOAT64:
 		a = arm64.AFCMPD
 
-	case OAS_ | gc.TBOOL,
-		OAS_ | gc.TINT8:
+	case OAS_ | ir.TBOOL,
+		OAS_ | ir.TINT8:
 		a = arm64.AMOVB
 
-	case OAS_ | gc.TUINT8:
+	case OAS_ | ir.TUINT8:
 		a = arm64.AMOVBU
 
-	case OAS_ | gc.TINT16:
+	case OAS_ | ir.TINT16:
 		a = arm64.AMOVH
 
-	case OAS_ | gc.TUINT16:
+	case OAS_ | ir.TUINT16:
 		a = arm64.AMOVHU
 
-	case OAS_ | gc.TINT32:
+	case OAS_ | ir.TINT32:
 		a = arm64.AMOVW
 
-	case OAS_ | gc.TUINT32,
-		OAS_ | gc.TPTR32:
+	case OAS_ | ir.TUINT32,
+		OAS_ | ir.TPTR32:
 		a = arm64.AMOVWU
 
-	case OAS_ | gc.TINT64,
-		OAS_ | gc.TUINT64,
-		OAS_ | gc.TPTR64:
+	case OAS_ | ir.TINT64,
+		OAS_ | ir.TUINT64,
+		OAS_ | ir.TPTR64:
 		a = arm64.AMOVD
 
-	case OAS_ | gc.TFLOAT32:
+	case OAS_ | ir.TFLOAT32:
 		a = arm64.AFMOVS
 
-	case OAS_ | gc.TFLOAT64:
+	case OAS_ | ir.TFLOAT64:
 		a = arm64.AFMOVD
 
-	case OADD_ | gc.TINT8,
-		OADD_ | gc.TUINT8,
-		OADD_ | gc.TINT16,
-		OADD_ | gc.TUINT16,
-		OADD_ | gc.TINT32,
-		OADD_ | gc.TUINT32,
-		OADD_ | gc.TPTR32,
-		OADD_ | gc.TINT64,
-		OADD_ | gc.TUINT64,
-		OADD_ | gc.TPTR64:
+	case OADD_ | ir.TINT8,
+		OADD_ | ir.TUINT8,
+		OADD_ | ir.TINT16,
+		OADD_ | ir.TUINT16,
+		OADD_ | ir.TINT32,
+		OADD_ | ir.TUINT32,
+		OADD_ | ir.TPTR32,
+		OADD_ | ir.TINT64,
+		OADD_ | ir.TUINT64,
+		OADD_ | ir.TPTR64:
 		a = arm64.AADD
 
-	case OADD_ | gc.TFLOAT32:
+	case OADD_ | ir.TFLOAT32:
 		a = arm64.AFADDS
 
-	case OADD_ | gc.TFLOAT64:
+	case OADD_ | ir.TFLOAT64:
 		a = arm64.AFADDD
 
-	case OSUB_ | gc.TINT8,
-		OSUB_ | gc.TUINT8,
-		OSUB_ | gc.TINT16,
-		OSUB_ | gc.TUINT16,
-		OSUB_ | gc.TINT32,
-		OSUB_ | gc.TUINT32,
-		OSUB_ | gc.TPTR32,
-		OSUB_ | gc.TINT64,
-		OSUB_ | gc.TUINT64,
-		OSUB_ | gc.TPTR64:
+	case OSUB_ | ir.TINT8,
+		OSUB_ | ir.TUINT8,
+		OSUB_ | ir.TINT16,
+		OSUB_ | ir.TUINT16,
+		OSUB_ | ir.TINT32,
+		OSUB_ | ir.TUINT32,
+		OSUB_ | ir.TPTR32,
+		OSUB_ | ir.TINT64,
+		OSUB_ | ir.TUINT64,
+		OSUB_ | ir.TPTR64:
 		a = arm64.ASUB
 
-	case OSUB_ | gc.TFLOAT32:
+	case OSUB_ | ir.TFLOAT32:
 		a = arm64.AFSUBS
 
-	case OSUB_ | gc.TFLOAT64:
+	case OSUB_ | ir.TFLOAT64:
 		a = arm64.AFSUBD
 
-	case OMINUS_ | gc.TINT8,
-		OMINUS_ | gc.TUINT8,
-		OMINUS_ | gc.TINT16,
-		OMINUS_ | gc.TUINT16,
-		OMINUS_ | gc.TINT32,
-		OMINUS_ | gc.TUINT32,
-		OMINUS_ | gc.TPTR32,
-		OMINUS_ | gc.TINT64,
-		OMINUS_ | gc.TUINT64,
-		OMINUS_ | gc.TPTR64:
+	case OMINUS_ | ir.TINT8,
+		OMINUS_ | ir.TUINT8,
+		OMINUS_ | ir.TINT16,
+		OMINUS_ | ir.TUINT16,
+		OMINUS_ | ir.TINT32,
+		OMINUS_ | ir.TUINT32,
+		OMINUS_ | ir.TPTR32,
+		OMINUS_ | ir.TINT64,
+		OMINUS_ | ir.TUINT64,
+		OMINUS_ | ir.TPTR64:
 		a = arm64.ANEG
 
-	case OMINUS_ | gc.TFLOAT32:
+	case OMINUS_ | ir.TFLOAT32:
 		a = arm64.AFNEGS
 
-	case OMINUS_ | gc.TFLOAT64:
+	case OMINUS_ | ir.TFLOAT64:
 		a = arm64.AFNEGD
 
-	case OAND_ | gc.TINT8,
-		OAND_ | gc.TUINT8,
-		OAND_ | gc.TINT16,
-		OAND_ | gc.TUINT16,
-		OAND_ | gc.TINT32,
-		OAND_ | gc.TUINT32,
-		OAND_ | gc.TPTR32,
-		OAND_ | gc.TINT64,
-		OAND_ | gc.TUINT64,
-		OAND_ | gc.TPTR64:
+	case OAND_ | ir.TINT8,
+		OAND_ | ir.TUINT8,
+		OAND_ | ir.TINT16,
+		OAND_ | ir.TUINT16,
+		OAND_ | ir.TINT32,
+		OAND_ | ir.TUINT32,
+		OAND_ | ir.TPTR32,
+		OAND_ | ir.TINT64,
+		OAND_ | ir.TUINT64,
+		OAND_ | ir.TPTR64:
 		a = arm64.AAND
 
-	case OOR_ | gc.TINT8,
-		OOR_ | gc.TUINT8,
-		OOR_ | gc.TINT16,
-		OOR_ | gc.TUINT16,
-		OOR_ | gc.TINT32,
-		OOR_ | gc.TUINT32,
-		OOR_ | gc.TPTR32,
-		OOR_ | gc.TINT64,
-		OOR_ | gc.TUINT64,
-		OOR_ | gc.TPTR64:
+	case OOR_ | ir.TINT8,
+		OOR_ | ir.TUINT8,
+		OOR_ | ir.TINT16,
+		OOR_ | ir.TUINT16,
+		OOR_ | ir.TINT32,
+		OOR_ | ir.TUINT32,
+		OOR_ | ir.TPTR32,
+		OOR_ | ir.TINT64,
+		OOR_ | ir.TUINT64,
+		OOR_ | ir.TPTR64:
 		a = arm64.AORR
 
-	case OXOR_ | gc.TINT8,
-		OXOR_ | gc.TUINT8,
-		OXOR_ | gc.TINT16,
-		OXOR_ | gc.TUINT16,
-		OXOR_ | gc.TINT32,
-		OXOR_ | gc.TUINT32,
-		OXOR_ | gc.TPTR32,
-		OXOR_ | gc.TINT64,
-		OXOR_ | gc.TUINT64,
-		OXOR_ | gc.TPTR64:
+	case OXOR_ | ir.TINT8,
+		OXOR_ | ir.TUINT8,
+		OXOR_ | ir.TINT16,
+		OXOR_ | ir.TUINT16,
+		OXOR_ | ir.TINT32,
+		OXOR_ | ir.TUINT32,
+		OXOR_ | ir.TPTR32,
+		OXOR_ | ir.TINT64,
+		OXOR_ | ir.TUINT64,
+		OXOR_ | ir.TPTR64:
 		a = arm64.AEOR
 
 		// TODO(minux): handle rotates
@@ -864,30 +865,30 @@ func optoas(op gc.Op, t *gc.Type) obj.As {
 	//	a = 0//???; RLDC?
 	//	break;
 
-	case OLSH_ | gc.TINT8,
-		OLSH_ | gc.TUINT8,
-		OLSH_ | gc.TINT16,
-		OLSH_ | gc.TUINT16,
-		OLSH_ | gc.TINT32,
-		OLSH_ | gc.TUINT32,
-		OLSH_ | gc.TPTR32,
-		OLSH_ | gc.TINT64,
-		OLSH_ | gc.TUINT64,
-		OLSH_ | gc.TPTR64:
+	case OLSH_ | ir.TINT8,
+		OLSH_ | ir.TUINT8,
+		OLSH_ | ir.TINT16,
+		OLSH_ | ir.TUINT16,
+		OLSH_ | ir.TINT32,
+		OLSH_ | ir.TUINT32,
+		OLSH_ | ir.TPTR32,
+		OLSH_ | ir.TINT64,
+		OLSH_ | ir.TUINT64,
+		OLSH_ | ir.TPTR64:
 		a = arm64.ALSL
 
-	case ORSH_ | gc.TUINT8,
-		ORSH_ | gc.TUINT16,
-		ORSH_ | gc.TUINT32,
-		ORSH_ | gc.TPTR32,
-		ORSH_ | gc.TUINT64,
-		ORSH_ | gc.TPTR64:
+	case ORSH_ | ir.TUINT8,
+		ORSH_ | ir.TUINT16,
+		ORSH_ | ir.TUINT32,
+		ORSH_ | ir.TPTR32,
+		ORSH_ | ir.TUINT64,
+		ORSH_ | ir.TPTR64:
 		a = arm64.ALSR
 
-	case ORSH_ | gc.TINT8,
-		ORSH_ | gc.TINT16,
-		ORSH_ | gc.TINT32,
-		ORSH_ | gc.TINT64:
+	case ORSH_ | ir.TINT8,
+		ORSH_ | ir.TINT16,
+		ORSH_ | ir.TINT32,
+		ORSH_ | ir.TINT64:
 		a = arm64.AASR
 
 		// TODO(minux): handle rotates
@@ -902,59 +903,59 @@ func optoas(op gc.Op, t *gc.Type) obj.As {
 	//	a = 0//??? RLDC??
 	//	break;
 
-	case OHMUL_ | gc.TINT64:
+	case OHMUL_ | ir.TINT64:
 		a = arm64.ASMULH
 
-	case OHMUL_ | gc.TUINT64,
-		OHMUL_ | gc.TPTR64:
+	case OHMUL_ | ir.TUINT64,
+		OHMUL_ | ir.TPTR64:
 		a = arm64.AUMULH
 
-	case OMUL_ | gc.TINT8,
-		OMUL_ | gc.TINT16,
-		OMUL_ | gc.TINT32:
+	case OMUL_ | ir.TINT8,
+		OMUL_ | ir.TINT16,
+		OMUL_ | ir.TINT32:
 		a = arm64.ASMULL
 
-	case OMUL_ | gc.TINT64:
+	case OMUL_ | ir.TINT64:
 		a = arm64.AMUL
 
-	case OMUL_ | gc.TUINT8,
-		OMUL_ | gc.TUINT16,
-		OMUL_ | gc.TUINT32,
-		OMUL_ | gc.TPTR32:
+	case OMUL_ | ir.TUINT8,
+		OMUL_ | ir.TUINT16,
+		OMUL_ | ir.TUINT32,
+		OMUL_ | ir.TPTR32:
 		// don't use word multiply, the high 32-bit are undefined.
 		a = arm64.AUMULL
 
-	case OMUL_ | gc.TUINT64,
-		OMUL_ | gc.TPTR64:
+	case OMUL_ | ir.TUINT64,
+		OMUL_ | ir.TPTR64:
 		a = arm64.AMUL // for 64-bit multiplies, signedness doesn't matter.
 
-	case OMUL_ | gc.TFLOAT32:
+	case OMUL_ | ir.TFLOAT32:
 		a = arm64.AFMULS
 
-	case OMUL_ | gc.TFLOAT64:
+	case OMUL_ | ir.TFLOAT64:
 		a = arm64.AFMULD
 
-	case ODIV_ | gc.TINT8,
-		ODIV_ | gc.TINT16,
-		ODIV_ | gc.TINT32,
-		ODIV_ | gc.TINT64:
+	case ODIV_ | ir.TINT8,
+		ODIV_ | ir.TINT16,
+		ODIV_ | ir.TINT32,
+		ODIV_ | ir.TINT64:
 		a = arm64.ASDIV
 
-	case ODIV_ | gc.TUINT8,
-		ODIV_ | gc.TUINT16,
-		ODIV_ | gc.TUINT32,
-		ODIV_ | gc.TPTR32,
-		ODIV_ | gc.TUINT64,
-		ODIV_ | gc.TPTR64:
+	case ODIV_ | ir.TUINT8,
+		ODIV_ | ir.TUINT16,
+		ODIV_ | ir.TUINT32,
+		ODIV_ | ir.TPTR32,
+		ODIV_ | ir.TUINT64,
+		ODIV_ | ir.TPTR64:
 		a = arm64.AUDIV
 
-	case ODIV_ | gc.TFLOAT32:
+	case ODIV_ | ir.TFLOAT32:
 		a = arm64.AFDIVS
 
-	case ODIV_ | gc.TFLOAT64:
+	case ODIV_ | ir.TFLOAT64:
 		a = arm64.AFDIVD
 
-	case OSQRT_ | gc.TFLOAT64:
+	case OSQRT_ | ir.TFLOAT64:
 		a = arm64.AFSQRTD
 	}
 
@@ -966,7 +967,7 @@ const (
 	OAddable = 1 << 1
 )
 
-func xgen(n *gc.Node, a *gc.Node, o int) bool {
+func xgen(n *ir.Node, a *ir.Node, o int) bool {
 	// TODO(minux)
 
 	return -1 != 0 /*TypeKind(100016)*/
@@ -987,7 +988,7 @@ func sudoclean() {
  * after successful sudoaddable,
  * to release the register used for a.
  */
-func sudoaddable(as obj.As, n *gc.Node, a *obj.Addr) bool {
+func sudoaddable(as obj.As, n *ir.Node, a *obj.Addr) bool {
 	// TODO(minux)
 
 	*a = obj.Addr{}
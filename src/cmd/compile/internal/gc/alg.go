@@ -4,6 +4,7 @@
 
 package gc
 
+import "cmd/compile/internal/ir"
 import "fmt"
 
 const (
@@ -25,7 +26,7 @@ const (
 	AMEM = 100
 )
 
-func algtype(t *Type) int {
+func algtype(t *ir.Type) int {
 	a := algtype1(t, nil)
 	if a == AMEM {
 		switch t.Width {
@@ -47,7 +48,7 @@ func algtype(t *Type) int {
 	return a
 }
 
-func algtype1(t *Type, bad **Type) int {
+func algtype1(t *ir.Type, bad **ir.Type) int {
 	if bad != nil {
 		*bad = nil
 	}
@@ -59,46 +60,46 @@ func algtype1(t *Type, bad **Type) int {
 	}
 
 	switch t.Etype {
-	case TANY, TFORW:
+	case ir.TANY, ir.TFORW:
 		// will be defined later.
 		*bad = t
 		return -1
 
-	case TINT8, TUINT8, TINT16, TUINT16,
-		TINT32, TUINT32, TINT64, TUINT64,
-		TINT, TUINT, TUINTPTR,
-		TBOOL, TPTR32, TPTR64,
-		TCHAN, TUNSAFEPTR:
+	case ir.TINT8, ir.TUINT8, ir.TINT16, ir.TUINT16,
+		ir.TINT32, ir.TUINT32, ir.TINT64, ir.TUINT64,
+		ir.TINT, ir.TUINT, ir.TUINTPTR,
+		ir.TBOOL, ir.TPTR32, ir.TPTR64,
+		ir.TCHAN, ir.TUNSAFEPTR:
 		return AMEM
 
-	case TFUNC, TMAP:
+	case ir.TFUNC, ir.TMAP:
 		if bad != nil {
 			*bad = t
 		}
 		return ANOEQ
 
-	case TFLOAT32:
+	case ir.TFLOAT32:
 		return AFLOAT32
 
-	case TFLOAT64:
+	case ir.TFLOAT64:
 		return AFLOAT64
 
-	case TCOMPLEX64:
+	case ir.TCOMPLEX64:
 		return ACPLX64
 
-	case TCOMPLEX128:
+	case ir.TCOMPLEX128:
 		return ACPLX128
 
-	case TSTRING:
+	case ir.TSTRING:
 		return ASTRING
 
-	case TINTER:
+	case ir.TINTER:
 		if isnilinter(t) {
 			return ANILINTER
 		}
 		return AINTER
 
-	case TARRAY:
+	case ir.TARRAY:
 		if Isslice(t) {
 			if bad != nil {
 				*bad = t
@@ -128,7 +129,7 @@ func algtype1(t *Type, bad **Type) int {
 
 		return -1 // needs special compare
 
-	case TSTRUCT:
+	case ir.TSTRUCT:
 		fields := t.FieldSlice()
 
 		// One-field struct is same as that one field alone.
@@ -159,30 +160,30 @@ func algtype1(t *Type, bad **Type) int {
 }
 
 // Generate a helper function to compute the hash of a value of type t.
-func genhash(sym *Sym, t *Type) {
+func genhash(sym *ir.Sym, t *ir.Type) {
 	if Debug['r'] != 0 {
 		fmt.Printf("genhash %v %v\n", sym, t)
 	}
 
 	lineno = 1 // less confusing than end of input
-	dclcontext = PEXTERN
+	dclcontext = ir.PEXTERN
 	markdcl()
 
 	// func sym(p *T, h uintptr) uintptr
-	fn := Nod(ODCLFUNC, nil, nil)
+	fn := Nod(ir.ODCLFUNC, nil, nil)
 
 	fn.Func.Nname = newname(sym)
-	fn.Func.Nname.Class = PFUNC
-	tfn := Nod(OTFUNC, nil, nil)
+	fn.Func.Nname.Class = ir.PFUNC
+	tfn := Nod(ir.OTFUNC, nil, nil)
 	fn.Func.Nname.Name.Param.Ntype = tfn
 
-	n := Nod(ODCLFIELD, newname(Lookup("p")), typenod(Ptrto(t)))
+	n := Nod(ir.ODCLFIELD, newname(Lookup("p")), typenod(Ptrto(t)))
 	tfn.List.Append(n)
 	np := n.Left
-	n = Nod(ODCLFIELD, newname(Lookup("h")), typenod(Types[TUINTPTR]))
+	n = Nod(ir.ODCLFIELD, newname(Lookup("h")), typenod(ir.Types[ir.TUINTPTR]))
 	tfn.List.Append(n)
 	nh := n.Left
-	n = Nod(ODCLFIELD, nil, typenod(Types[TUINTPTR])) // return value
+	n = Nod(ir.ODCLFIELD, nil, typenod(ir.Types[ir.TUINTPTR])) // return value
 	tfn.Rlist.Append(n)
 
 	funchdr(fn)
@@ -195,7 +196,7 @@ func genhash(sym *Sym, t *Type) {
 	default:
 		Fatalf("genhash %v", t)
 
-	case TARRAY:
+	case ir.TARRAY:
 		if Isslice(t) {
 			Fatalf("genhash %v", t)
 		}
@@ -205,28 +206,28 @@ func genhash(sym *Sym, t *Type) {
 		// pure memory.
 		hashel := hashfor(t.Type)
 
-		n := Nod(ORANGE, nil, Nod(OIND, np, nil))
+		n := Nod(ir.ORANGE, nil, Nod(ir.OIND, np, nil))
 		ni := newname(Lookup("i"))
-		ni.Type = Types[TINT]
+		ni.Type = ir.Types[ir.TINT]
 		n.List.Set1(ni)
 		n.Colas = true
 		colasdefn(n.List, n)
 		ni = n.List.First()
 
 		// h = hashel(&p[i], h)
-		call := Nod(OCALL, hashel, nil)
+		call := Nod(ir.OCALL, hashel, nil)
 
-		nx := Nod(OINDEX, np, ni)
+		nx := Nod(ir.OINDEX, np, ni)
 		nx.Bounded = true
-		na := Nod(OADDR, nx, nil)
+		na := Nod(ir.OADDR, nx, nil)
 		na.Etype = 1 // no escape to heap
 		call.List.Append(na)
 		call.List.Append(nh)
-		n.Nbody.Append(Nod(OAS, nh, call))
+		n.Nbody.Append(Nod(ir.OAS, nh, call))
 
 		fn.Nbody.Append(n)
 
-	case TSTRUCT:
+	case ir.TSTRUCT:
 		// Walk the struct using memhash for runs of AMEM
 		// and calling specific hash functions for the others.
 		for i, fields := 0, t.FieldSlice(); i < len(fields); {
@@ -241,13 +242,13 @@ func genhash(sym *Sym, t *Type) {
 			// Hash non-memory fields with appropriate hash function.
 			if algtype1(f.Type, nil) != AMEM {
 				hashel := hashfor(f.Type)
-				call := Nod(OCALL, hashel, nil)
-				nx := NodSym(OXDOT, np, f.Sym) // TODO: fields from other packages?
-				na := Nod(OADDR, nx, nil)
+				call := Nod(ir.OCALL, hashel, nil)
+				nx := NodSym(ir.OXDOT, np, f.Sym) // TODO: fields from other packages?
+				na := Nod(ir.OADDR, nx, nil)
 				na.Etype = 1 // no escape to heap
 				call.List.Append(na)
 				call.List.Append(nh)
-				fn.Nbody.Append(Nod(OAS, nh, call))
+				fn.Nbody.Append(Nod(ir.OAS, nh, call))
 				i++
 				continue
 			}
@@ -257,20 +258,20 @@ func genhash(sym *Sym, t *Type) {
 
 			// h = hashel(&p.first, size, h)
 			hashel := hashmem(f.Type)
-			call := Nod(OCALL, hashel, nil)
-			nx := NodSym(OXDOT, np, f.Sym) // TODO: fields from other packages?
-			na := Nod(OADDR, nx, nil)
+			call := Nod(ir.OCALL, hashel, nil)
+			nx := NodSym(ir.OXDOT, np, f.Sym) // TODO: fields from other packages?
+			na := Nod(ir.OADDR, nx, nil)
 			na.Etype = 1 // no escape to heap
 			call.List.Append(na)
 			call.List.Append(nh)
 			call.List.Append(Nodintconst(size))
-			fn.Nbody.Append(Nod(OAS, nh, call))
+			fn.Nbody.Append(Nod(ir.OAS, nh, call))
 
 			i = next
 		}
 	}
 
-	r := Nod(ORETURN, nil, nil)
+	r := Nod(ir.ORETURN, nil, nil)
 	r.List.Append(nh)
 	fn.Nbody.Append(r)
 
@@ -301,8 +302,8 @@ func genhash(sym *Sym, t *Type) {
 	safemode = old_safemode
 }
 
-func hashfor(t *Type) *Node {
-	var sym *Sym
+func hashfor(t *ir.Type) *ir.Node {
+	var sym *ir.Sym
 
 	switch algtype1(t, nil) {
 	case AMEM:
@@ -326,11 +327,11 @@ func hashfor(t *Type) *Node {
 	}
 
 	n := newname(sym)
-	n.Class = PFUNC
-	tfn := Nod(OTFUNC, nil, nil)
-	tfn.List.Append(Nod(ODCLFIELD, nil, typenod(Ptrto(t))))
-	tfn.List.Append(Nod(ODCLFIELD, nil, typenod(Types[TUINTPTR])))
-	tfn.Rlist.Append(Nod(ODCLFIELD, nil, typenod(Types[TUINTPTR])))
+	n.Class = ir.PFUNC
+	tfn := Nod(ir.OTFUNC, nil, nil)
+	tfn.List.Append(Nod(ir.ODCLFIELD, nil, typenod(Ptrto(t))))
+	tfn.List.Append(Nod(ir.ODCLFIELD, nil, typenod(ir.Types[ir.TUINTPTR])))
+	tfn.Rlist.Append(Nod(ir.ODCLFIELD, nil, typenod(ir.Types[ir.TUINTPTR])))
 	tfn = typecheck(tfn, Etype)
 	n.Type = tfn.Type
 	return n
@@ -338,30 +339,30 @@ func hashfor(t *Type) *Node {
 
 // geneq generates a helper function to
 // check equality of two values of type t.
-func geneq(sym *Sym, t *Type) {
+func geneq(sym *ir.Sym, t *ir.Type) {
 	if Debug['r'] != 0 {
 		fmt.Printf("geneq %v %v\n", sym, t)
 	}
 
 	lineno = 1 // less confusing than end of input
-	dclcontext = PEXTERN
+	dclcontext = ir.PEXTERN
 	markdcl()
 
 	// func sym(p, q *T) bool
-	fn := Nod(ODCLFUNC, nil, nil)
+	fn := Nod(ir.ODCLFUNC, nil, nil)
 
 	fn.Func.Nname = newname(sym)
-	fn.Func.Nname.Class = PFUNC
-	tfn := Nod(OTFUNC, nil, nil)
+	fn.Func.Nname.Class = ir.PFUNC
+	tfn := Nod(ir.OTFUNC, nil, nil)
 	fn.Func.Nname.Name.Param.Ntype = tfn
 
-	n := Nod(ODCLFIELD, newname(Lookup("p")), typenod(Ptrto(t)))
+	n := Nod(ir.ODCLFIELD, newname(Lookup("p")), typenod(Ptrto(t)))
 	tfn.List.Append(n)
 	np := n.Left
-	n = Nod(ODCLFIELD, newname(Lookup("q")), typenod(Ptrto(t)))
+	n = Nod(ir.ODCLFIELD, newname(Lookup("q")), typenod(Ptrto(t)))
 	tfn.List.Append(n)
 	nq := n.Left
-	n = Nod(ODCLFIELD, nil, typenod(Types[TBOOL]))
+	n = Nod(ir.ODCLFIELD, nil, typenod(ir.Types[ir.TBOOL]))
 	tfn.Rlist.Append(n)
 
 	funchdr(fn)
@@ -374,7 +375,7 @@ func geneq(sym *Sym, t *Type) {
 	default:
 		Fatalf("geneq %v", t)
 
-	case TARRAY:
+	case ir.TARRAY:
 		if Isslice(t) {
 			Fatalf("geneq %v", t)
 		}
@@ -384,43 +385,43 @@ func geneq(sym *Sym, t *Type) {
 		// pure memory. Even if we unrolled the range loop,
 		// each iteration would be a function call, so don't bother
 		// unrolling.
-		nrange := Nod(ORANGE, nil, Nod(OIND, np, nil))
+		nrange := Nod(ir.ORANGE, nil, Nod(ir.OIND, np, nil))
 
 		ni := newname(Lookup("i"))
-		ni.Type = Types[TINT]
+		ni.Type = ir.Types[ir.TINT]
 		nrange.List.Set1(ni)
 		nrange.Colas = true
 		colasdefn(nrange.List, nrange)
 		ni = nrange.List.First()
 
 		// if p[i] != q[i] { return false }
-		nx := Nod(OINDEX, np, ni)
+		nx := Nod(ir.OINDEX, np, ni)
 
 		nx.Bounded = true
-		ny := Nod(OINDEX, nq, ni)
+		ny := Nod(ir.OINDEX, nq, ni)
 		ny.Bounded = true
 
-		nif := Nod(OIF, nil, nil)
-		nif.Left = Nod(ONE, nx, ny)
-		r := Nod(ORETURN, nil, nil)
+		nif := Nod(ir.OIF, nil, nil)
+		nif.Left = Nod(ir.ONE, nx, ny)
+		r := Nod(ir.ORETURN, nil, nil)
 		r.List.Append(Nodbool(false))
 		nif.Nbody.Append(r)
 		nrange.Nbody.Append(nif)
 		fn.Nbody.Append(nrange)
 
 		// return true
-		ret := Nod(ORETURN, nil, nil)
+		ret := Nod(ir.ORETURN, nil, nil)
 		ret.List.Append(Nodbool(true))
 		fn.Nbody.Append(ret)
 
-	case TSTRUCT:
-		var cond *Node
-		and := func(n *Node) {
+	case ir.TSTRUCT:
+		var cond *ir.Node
+		and := func(n *ir.Node) {
 			if cond == nil {
 				cond = n
 				return
 			}
-			cond = Nod(OANDAND, cond, n)
+			cond = Nod(ir.OANDAND, cond, n)
 		}
 
 		// Walk the struct using memequal for runs of AMEM
@@ -462,7 +463,7 @@ func geneq(sym *Sym, t *Type) {
 			cond = Nodbool(true)
 		}
 
-		ret := Nod(ORETURN, nil, nil)
+		ret := Nod(ir.ORETURN, nil, nil)
 		ret.List.Append(cond)
 		fn.Nbody.Append(ret)
 	}
@@ -502,25 +503,25 @@ func geneq(sym *Sym, t *Type) {
 
 // eqfield returns the node
 // 	p.field == q.field
-func eqfield(p *Node, q *Node, field *Sym) *Node {
-	nx := NodSym(OXDOT, p, field)
-	ny := NodSym(OXDOT, q, field)
-	ne := Nod(OEQ, nx, ny)
+func eqfield(p *ir.Node, q *ir.Node, field *ir.Sym) *ir.Node {
+	nx := NodSym(ir.OXDOT, p, field)
+	ny := NodSym(ir.OXDOT, q, field)
+	ne := Nod(ir.OEQ, nx, ny)
 	return ne
 }
 
 // eqmem returns the node
 // 	memequal(&p.field, &q.field [, size])
-func eqmem(p *Node, q *Node, field *Sym, size int64) *Node {
-	nx := Nod(OADDR, NodSym(OXDOT, p, field), nil)
+func eqmem(p *ir.Node, q *ir.Node, field *ir.Sym, size int64) *ir.Node {
+	nx := Nod(ir.OADDR, NodSym(ir.OXDOT, p, field), nil)
 	nx.Etype = 1 // does not escape
-	ny := Nod(OADDR, NodSym(OXDOT, q, field), nil)
+	ny := Nod(ir.OADDR, NodSym(ir.OXDOT, q, field), nil)
 	ny.Etype = 1 // does not escape
 	nx = typecheck(nx, Erv)
 	ny = typecheck(ny, Erv)
 
 	fn, needsize := eqmemfunc(size, nx.Type.Type)
-	call := Nod(OCALL, fn, nil)
+	call := Nod(ir.OCALL, fn, nil)
 	call.List.Append(nx)
 	call.List.Append(ny)
 	if needsize {
@@ -530,7 +531,7 @@ func eqmem(p *Node, q *Node, field *Sym, size int64) *Node {
 	return call
 }
 
-func eqmemfunc(size int64, t *Type) (fn *Node, needsize bool) {
+func eqmemfunc(size int64, t *ir.Type) (fn *ir.Node, needsize bool) {
 	switch size {
 	default:
 		fn = syslook("memequal")
@@ -550,7 +551,7 @@ func eqmemfunc(size int64, t *Type) (fn *Node, needsize bool) {
 // size is the length in bytes of the memory included in the run.
 // next is the index just after the end of the memory run.
 // TODO(mdempsky): Eliminate fields parameter once struct fields are kept in slices.
-func memrun(t *Type, fields []*Field, start int) (size int64, next int) {
+func memrun(t *ir.Type, fields []*ir.Field, start int) (size int64, next int) {
 	next = start
 	for {
 		next++
@@ -573,8 +574,8 @@ func memrun(t *Type, fields []*Field, start int) (size int64, next int) {
 // ispaddedfield reports whether the i'th field of struct type t is followed
 // by padding. The caller is responsible for providing t.FieldSlice() as fields.
 // TODO(mdempsky): Eliminate fields parameter once struct fields are kept in slices.
-func ispaddedfield(t *Type, fields []*Field, i int) bool {
-	if t.Etype != TSTRUCT {
+func ispaddedfield(t *ir.Type, fields []*ir.Field, i int) bool {
+	if t.Etype != ir.TSTRUCT {
 		Fatalf("ispaddedfield called non-struct %v", t)
 	}
 	end := t.Width
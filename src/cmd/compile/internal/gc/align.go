@@ -4,6 +4,8 @@
 
 package gc
 
+import "cmd/compile/internal/ir"
+
 // machine size and rounding alignment is dictated around
 // the size of a pointer, set in betypeinit (see ../amd64/galign.go).
 var defercalc int
@@ -15,7 +17,7 @@ func Rnd(o int64, r int64) int64 {
 	return (o + r - 1) &^ (r - 1)
 }
 
-func offmod(t *Type) {
+func offmod(t *ir.Type) {
 	o := int32(0)
 	for _, f := range t.Fields().Slice() {
 		f.Width = int64(o)
@@ -27,7 +29,7 @@ func offmod(t *Type) {
 	}
 }
 
-func widstruct(errtype *Type, t *Type, o int64, flag int) int64 {
+func widstruct(errtype *ir.Type, t *ir.Type, o int64, flag int) int64 {
 	starto := o
 	maxalign := int32(flag)
 	if maxalign < 1 {
@@ -72,7 +74,7 @@ func widstruct(errtype *Type, t *Type, o int64, flag int) int64 {
 		}
 		o += w
 		if o >= Thearch.MAXWIDTH {
-			Yyerror("type %v too large", Tconv(errtype, FmtLong))
+			Yyerror("type %v too large", Tconv(errtype, ir.FmtLong))
 			o = 8 // small but nonzero
 		}
 	}
@@ -97,7 +99,7 @@ func widstruct(errtype *Type, t *Type, o int64, flag int) int64 {
 	return o
 }
 
-func dowidth(t *Type) {
+func dowidth(t *ir.Type) {
 	if Widthptr == 0 {
 		Fatalf("dowidth without betypeinit")
 	}
@@ -114,6 +116,13 @@ func dowidth(t *Type) {
 		return
 	}
 
+	// A computed zero-size type has a nonzero alignment.
+	// Don't compute it again: computed widths must not
+	// change while the SSA passes run concurrently (see -c).
+	if t.Width == 0 && t.Align > 0 {
+		return
+	}
+
 	if t.Width == -2 {
 		if !t.Broke {
 			t.Broke = true
@@ -140,7 +149,7 @@ func dowidth(t *Type) {
 
 	et := t.Etype
 	switch et {
-	case TFUNC, TCHAN, TMAP, TSTRING:
+	case ir.TFUNC, ir.TCHAN, ir.TMAP, ir.TSTRING:
 		break
 
 	// simtype == 0 during bootstrap
@@ -156,54 +165,54 @@ func dowidth(t *Type) {
 		Fatalf("dowidth: unknown type: %v", t)
 
 	// compiler-specific stuff
-	case TINT8, TUINT8, TBOOL:
+	case ir.TINT8, ir.TUINT8, ir.TBOOL:
 		// bool is int8
 		w = 1
 
-	case TINT16, TUINT16:
+	case ir.TINT16, ir.TUINT16:
 		w = 2
 
-	case TINT32, TUINT32, TFLOAT32:
+	case ir.TINT32, ir.TUINT32, ir.TFLOAT32:
 		w = 4
 
-	case TINT64, TUINT64, TFLOAT64, TCOMPLEX64:
+	case ir.TINT64, ir.TUINT64, ir.TFLOAT64, ir.TCOMPLEX64:
 		w = 8
 		t.Align = uint8(Widthreg)
 
-	case TCOMPLEX128:
+	case ir.TCOMPLEX128:
 		w = 16
 		t.Align = uint8(Widthreg)
 
-	case TPTR32:
+	case ir.TPTR32:
 		w = 4
 		checkwidth(t.Type)
 
-	case TPTR64:
+	case ir.TPTR64:
 		w = 8
 		checkwidth(t.Type)
 
-	case TUNSAFEPTR:
+	case ir.TUNSAFEPTR:
 		w = int64(Widthptr)
 
-	case TINTER: // implemented as 2 pointers
+	case ir.TINTER: // implemented as 2 pointers
 		w = 2 * int64(Widthptr)
 
 		t.Align = uint8(Widthptr)
 		offmod(t)
 
-	case TCHAN: // implemented as pointer
+	case ir.TCHAN: // implemented as pointer
 		w = int64(Widthptr)
 
 		checkwidth(t.Type)
 
 		// make fake type to check later to
 		// trigger channel argument check.
-		t1 := typ(TCHANARGS)
+		t1 := ir.Typ(ir.TCHANARGS)
 
 		t1.Type = t
 		checkwidth(t1)
 
-	case TCHANARGS:
+	case ir.TCHANARGS:
 		t1 := t.Type
 		dowidth(t.Type) // just in case
 		if t1.Type.Width >= 1<<16 {
@@ -211,33 +220,33 @@ func dowidth(t *Type) {
 		}
 		t.Width = 1
 
-	case TMAP: // implemented as pointer
+	case ir.TMAP: // implemented as pointer
 		w = int64(Widthptr)
 
 		checkwidth(t.Type)
 		checkwidth(t.Key())
 
-	case TFORW: // should have been filled in
+	case ir.TFORW: // should have been filled in
 		if !t.Broke {
 			Yyerror("invalid recursive type %v", t)
 		}
 		w = 1 // anything will do
 
 	// dummy type; should be replaced before use.
-	case TANY:
+	case ir.TANY:
 		if Debug['A'] == 0 {
 			Fatalf("dowidth any")
 		}
 		w = 1 // anything will do
 
-	case TSTRING:
+	case ir.TSTRING:
 		if sizeof_String == 0 {
 			Fatalf("early dowidth string")
 		}
 		w = int64(sizeof_String)
 		t.Align = uint8(Widthptr)
 
-	case TARRAY:
+	case ir.TARRAY:
 		if t.Type == nil {
 			break
 		}
@@ -246,7 +255,7 @@ func dowidth(t *Type) {
 			if t.Type.Width != 0 {
 				cap := (uint64(Thearch.MAXWIDTH) - 1) / uint64(t.Type.Width)
 				if uint64(t.Bound) > cap {
-					Yyerror("type %v larger than address space", Tconv(t, FmtLong))
+					Yyerror("type %v larger than address space", Tconv(t, ir.FmtLong))
 				}
 			}
 
@@ -265,7 +274,7 @@ func dowidth(t *Type) {
 			Fatalf("dowidth %v", t) // probably [...]T
 		}
 
-	case TSTRUCT:
+	case ir.TSTRUCT:
 		if t.Funarg {
 			Fatalf("dowidth fn struct %v", t)
 		}
@@ -273,8 +282,8 @@ func dowidth(t *Type) {
 
 	// make fake type to check later to
 	// trigger function argument computation.
-	case TFUNC:
-		t1 := typ(TFUNCARGS)
+	case ir.TFUNC:
+		t1 := ir.Typ(ir.TFUNCARGS)
 
 		t1.Type = t
 		checkwidth(t1)
@@ -284,7 +293,7 @@ func dowidth(t *Type) {
 
 	// function is 3 cated structures;
 	// compute their widths as side-effect.
-	case TFUNCARGS:
+	case ir.TFUNCARGS:
 		t1 := t.Type
 
 		w = widstruct(t.Type, t1.Recvs(), 0, 0)
@@ -318,6 +327,18 @@ func dowidth(t *Type) {
 	}
 }
 
+// backendDowidth is dowidth for use by the SSA back end, which may
+// optimize several functions at once (see -c). Widths that are already
+// computed are read without locking, since they don't change.
+func backendDowidth(t *ir.Type) {
+	if t.Width > 0 || t.Width == 0 && t.Align > 0 {
+		return
+	}
+	backendMu.Lock()
+	dowidth(t)
+	backendMu.Unlock()
+}
+
 // when a type's width should be known, we call checkwidth
 // to compute it.  during a declaration like
 //
@@ -334,9 +355,9 @@ func dowidth(t *Type) {
 // is needed immediately.  checkwidth makes sure the
 // size is evaluated eventually.
 
-var deferredTypeStack []*Type
+var deferredTypeStack []*ir.Type
 
-func checkwidth(t *Type) {
+func checkwidth(t *ir.Type) {
 	if t == nil {
 		return
 	}
@@ -383,10 +404,10 @@ func resumecheckwidth() {
 }
 
 // compute total size of f's in/out arguments.
-func Argsize(t *Type) int {
+func Argsize(t *ir.Type) int {
 	var w int64
 
-	for _, p := range recvsParamsResults {
+	for _, p := range ir.RecvsParamsResults {
 		for _, f := range p(t).Fields().Slice() {
 			if x := f.Width + f.Type.Width; x > w {
 				w = x
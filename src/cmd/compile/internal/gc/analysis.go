@@ -0,0 +1,31 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gc
+
+// An analysis is a read-only pass over the IR of each function that a
+// tool built into the compiler registers with RegisterAnalysis, to see
+// the code as the compiler does without maintaining a fork. Such a
+// tool adds a file to this package, or to a package that a file of
+// package main imports, guarded like the registry by
+//
+//	// +build compiler_analysis
+//
+// and registers its passes in an init function. It then builds the
+// compiler with -tags compiler_analysis. Without the tag, the registry
+// is not compiled in and running the passes costs nothing.
+
+// An AnalysisPhase says when an analysis runs.
+type AnalysisPhase int
+
+const (
+	// AfterTypecheck is after type checking, once the package has
+	// no errors. Closures are still inside the functions that
+	// contain them.
+	AfterTypecheck AnalysisPhase = iota
+
+	// AfterEscape is after inlining and escape analysis, so calls
+	// may be inlined and variables that escape are marked.
+	AfterEscape
+)
@@ -0,0 +1,11 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build !compiler_analysis
+
+package gc
+
+// runAnalyses does nothing: without the compiler_analysis build tag,
+// no analyses can be registered.
+func runAnalyses(phase AnalysisPhase) {}
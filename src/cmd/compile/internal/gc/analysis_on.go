@@ -0,0 +1,48 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build compiler_analysis
+
+package gc
+
+import "cmd/compile/internal/ir"
+
+type analysis struct {
+	name  string
+	phase AnalysisPhase
+	run   func(fn *ir.Node)
+}
+
+var analyses []analysis
+
+// RegisterAnalysis registers run to be called with each function of
+// the package after phase, in the order of the declarations, with
+// Curfn and lineno set to the function. The analyses of a phase run
+// in the order they were registered, one function after another.
+// run must not modify the IR; it may report problems with Warnl or
+// Yyerror. name identifies the analysis in the -d timings output.
+func RegisterAnalysis(name string, phase AnalysisPhase, run func(fn *ir.Node)) {
+	analyses = append(analyses, analysis{name, phase, run})
+}
+
+// runAnalyses runs the analyses registered for phase.
+func runAnalyses(phase AnalysisPhase) {
+	for _, a := range analyses {
+		if a.phase != phase {
+			continue
+		}
+		for _, n := range xtop {
+			if n.Op != ir.ODCLFUNC {
+				continue
+			}
+			t := timestart()
+			Curfn = n
+			lno := setlineno(n)
+			a.run(n)
+			lineno = lno
+			timeend(a.name, n, t)
+		}
+	}
+	Curfn = nil
+}
@@ -0,0 +1,60 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build compiler_analysis
+
+package gc
+
+import (
+	"cmd/compile/internal/ir"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRegisterAnalysis(t *testing.T) {
+	defer func(saved []analysis) { analyses = saved }(analyses)
+	analyses = nil
+
+	// heap records, after each phase, whether x in f is on the heap.
+	var seen []string
+	heap := make(map[AnalysisPhase]bool)
+	for _, phase := range []AnalysisPhase{AfterTypecheck, AfterEscape} {
+		phase := phase
+		RegisterAnalysis("test", phase, func(fn *ir.Node) {
+			if Curfn != fn {
+				t.Errorf("Curfn = %v, want %v", Curfn, fn)
+			}
+			seen = append(seen, fn.Func.Nname.Sym.Name)
+			for _, n := range fn.Func.Dcl {
+				if n.Sym != nil && n.Sym.Name == "x" {
+					heap[phase] = n.Class&ir.PHEAP != 0
+				}
+			}
+		})
+	}
+
+	_, _, err := CompilePackage(`package p
+func f() *int { x := 1; return &x }
+func g() {}
+`, "escape", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"f", "g", "f", "g"}; !reflect.DeepEqual(seen, want) {
+		t.Errorf("analyses saw %v, want %v", seen, want)
+	}
+	if heap[AfterTypecheck] || !heap[AfterEscape] {
+		t.Errorf("x on the heap after type checking, escape analysis: %v, %v, want false, true", heap[AfterTypecheck], heap[AfterEscape])
+	}
+
+	analyses = nil
+	RegisterAnalysis("test", AfterTypecheck, func(fn *ir.Node) {
+		Yyerror("analysis of %v failed", fn.Func.Nname.Sym)
+	})
+	_, _, err = CompilePackage("package p\nfunc f() {}\n", "typecheck", "")
+	if err == nil || !strings.Contains(err.Error(), "analysis of f failed") {
+		t.Errorf("CompilePackage with a failing analysis: err = %v", err)
+	}
+}
@@ -0,0 +1,52 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gc
+
+import (
+	"io/ioutil"
+	"strings"
+	"testing"
+)
+
+// Make sure -asmhdr describes the arguments of assembly functions.
+func TestAsmhdrArgs(t *testing.T) {
+	d := newTestDir(t, "TestAsmhdrArgs")
+	defer d.remove()
+
+	hdr := d.path("go_asm.h")
+	d.env = []string{"GOARCH=amd64"}
+	d.compile("x", `
+package x
+
+func add(x int32, _ int64, s string) (sum int64, ok bool)
+
+func noasm(x int) int { return x }
+`, "-asmhdr", hdr)
+	data, err := ioutil.ReadFile(hdr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := string(data)
+
+	for _, want := range []string{
+		"#define add__args 48\n",
+		"#define add_arg_x 0\n",
+		"#define add_arg_x__size 4\n",
+		"#define add_arg1 8\n",
+		"#define add_arg1__size 8\n",
+		"#define add_arg_s 16\n",
+		"#define add_arg_s__size 16\n",
+		"#define add_ret_sum 32\n",
+		"#define add_ret_ok 40\n",
+		"#define add_ret_ok__size 1\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("missing %q in header:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "noasm") {
+		t.Errorf("header describes function with Go body:\n%s", out)
+	}
+}
@@ -0,0 +1,96 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gc
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// Make sure -S=json lists each function with its instructions,
+// their sizes and positions, and their relocations.
+func TestAsmJSON(t *testing.T) {
+	d := newTestDir(t, "TestAsmJSON")
+	defer d.remove()
+
+	src := d.write("x.go", `package x
+
+func f(x int) int {
+	return g(x) + 1
+}
+
+func g(x int) int {
+	return x * x
+}
+`)
+	cmd := exec.Command("go", "tool", "compile", "-S=json", "-l", "-o", d.path("x.o"), src)
+	cmd.Env = append(os.Environ(), "GOARCH=amd64")
+	out, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("compile failed: %v\n%s", err, out)
+	}
+
+	type inst struct {
+		Pc     int64
+		Size   int64
+		File   string
+		Line   int
+		Text   string
+		Relocs []struct{ Sym string }
+	}
+	type fn struct {
+		Name         string
+		Size         int64
+		Instructions []inst
+		Totals       struct {
+			Instructions int
+			Bytes        int64
+			Relocs       int
+		}
+	}
+	funcs := make(map[string]fn)
+	s := bufio.NewScanner(bytes.NewReader(out))
+	for s.Scan() {
+		var f fn
+		if err := json.Unmarshal(s.Bytes(), &f); err != nil {
+			t.Fatalf("%v in line %q", err, s.Text())
+		}
+		funcs[f.Name] = f
+	}
+
+	f, ok := funcs[`"".f`]
+	if !ok || len(funcs) != 2 {
+		t.Fatalf("-S=json listed %d functions, want f and g:\n%s", len(funcs), out)
+	}
+	var size int64
+	n, relocs, callg := 0, 0, false
+	for _, in := range f.Instructions {
+		if filepath.Base(in.File) != "x.go" || in.Line < 3 || in.Line > 5 {
+			t.Errorf("%s at %s:%d, want x.go:3-5", in.Text, in.File, in.Line)
+		}
+		if in.Size > 0 {
+			n++
+		}
+		size += in.Size
+		relocs += len(in.Relocs)
+		if strings.HasPrefix(in.Text, "CALL") {
+			for _, r := range in.Relocs {
+				callg = callg || r.Sym == `"".g`
+			}
+		}
+	}
+	if size != f.Size || f.Totals.Bytes != f.Size || f.Totals.Instructions != n || f.Totals.Relocs != relocs {
+		t.Errorf("f has size %d, %d instructions and %d relocations in %d bytes; totals are %+v", f.Size, n, relocs, size, f.Totals)
+	}
+	if !callg {
+		t.Errorf("no CALL in f has a relocation to g:\n%s", out)
+	}
+}
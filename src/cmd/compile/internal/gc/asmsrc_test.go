@@ -0,0 +1,50 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gc
+
+import (
+	"strings"
+	"testing"
+)
+
+// Make sure -S shows the source line of each group of instructions.
+func TestAsmSource(t *testing.T) {
+	d := newTestDir(t, "TestAsmSource")
+	defer d.remove()
+
+	src := d.write("x.go", `package x
+
+func f(x int) int {
+	y := g(x)
+	return y * 3
+}
+
+func g(x int) int
+`)
+	d.env = []string{"GOARCH=amd64"}
+	out := d.run("go", "tool", "compile", "-S", "-o", d.path("x.o"), src)
+
+	// Each source line comes right before the first instruction
+	// from that line.
+	lines := strings.Split(out, "\n")
+	for _, want := range []string{
+		"\t// x.go:3\tfunc f(x int) int {",
+		"\t// x.go:4\ty := g(x)",
+		"\t// x.go:5\treturn y * 3",
+	} {
+		i := 0
+		for i < len(lines) && lines[i] != want {
+			i++
+		}
+		if i+1 >= len(lines) {
+			t.Errorf("-S output has no line %q:\n%s", want, out)
+			continue
+		}
+		pos := "(" + src + ":" + want[len("\t// x.go:"):strings.LastIndex(want, "\t")] + ")"
+		if next := lines[i+1]; !strings.Contains(next, pos) {
+			t.Errorf("%q is followed by %q, want an instruction at %s", want, next, pos)
+		}
+	}
+}
@@ -0,0 +1,53 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gc
+
+import "cmd/internal/obj"
+
+// A Backend generates code for one architecture. Main calls its
+// methods and the hooks it sets in Thearch in this order:
+//
+//	Init      once, before the command line is parsed, to describe
+//	          the target and set the code generation hooks in arch.
+//	TypeInit  once, after the command line is parsed, to set
+//	          Widthptr, Widthint and Widthreg and adjust Thearch to
+//	          the flags (for example, registers reserved by -dynlink).
+//
+// Then, for each function, the portable code generates Progs through
+// the hooks in Thearch: Gins, Gmove, Optoas and friends in cgen.go and
+// gsubr.go, or SSAGenValue and SSAGenBlock for functions compiled by
+// the SSA back end. For functions generated by the old back end, the
+// optimizer in reg.go then calls
+//
+//	Peep      to run the back end's peephole optimizer on the
+//	          function's Progs, starting at firstp.
+//
+// Finally defframe calls PadFrame and ZeroRange to finish the function
+// prologue, and the Progs are flushed to the assembler for
+// Thearch.Thelinkarch by obj.Flushplist.
+type Backend interface {
+	Init(arch *Arch)
+	TypeInit()
+	Peep(firstp *obj.Prog)
+}
+
+// backends maps each GOARCH to its back end.
+var backends = make(map[string]Backend)
+
+// RegisterBackend makes b the back end for the architecture goarch.
+// The back ends in this tree register themselves from init functions.
+// An experimental back end, which must also live under cmd/compile to
+// import this package, can register itself from an init function in a
+// file added to cmd/compile's main package. Such a function runs after
+// those of the built-in back ends, so it can also replace one of them.
+func RegisterBackend(goarch string, b Backend) {
+	if b == nil {
+		panic("gc: RegisterBackend of nil Backend for " + goarch)
+	}
+	backends[goarch] = b
+}
+
+// thebackend is the back end for $GOARCH, selected by Main.
+var thebackend Backend
@@ -6,28 +6,36 @@
 // Based loosely on x/tools/go/importer.
 // (see fmt.go, parser.go as "documentation" for how to use/setup data structures)
 //
-// Use "-newexport" flag to enable.
+// This is the default export format. The textual format written by
+// export.go remains available with -newexport=0 for one release.
 
 /*
 Export data encoding:
 
 The export data is a serialized description of the graph of exported
-objects: constants, types, variables, and functions. Only types can
-be re-exported and so we need to know which package they are coming
-from. Therefore, packages are also part of the export graph.
-
-The roots of the graph are the list of constants, variables, functions,
-and eventually types. Types are written last because most of them will
-be written as part of other objects which will reduce the number of
-types that need to be written separately.
-
-The graph is serialized in in-order fashion, starting with the roots.
-Each object in the graph is serialized by writing its fields sequentially.
-If the field is a pointer to another object, that object is serialized,
-recursively. Otherwise the field is written. Non-pointer fields are all
-encoded as either an integer or string value.
-
-Only packages and types may be referred to more than once. When getting
+objects: constants, types, variables, and functions. Objects of other
+packages may be re-exported and so we need to know which package they
+are coming from. Therefore, packages are also part of the export graph.
+
+Each object is declared separately: its declaration is serialized as
+a chunk of bytes that can be decoded on its own, and the chunks make
+up the data section of the export data. A declaration refers to named
+types by their qualified name only; each of them is declared in a
+chunk of its own. An index maps the qualified name of each declared
+object to the offset of its declaration in the data section, so that
+an importer can decode a declaration when the object is first used
+rather than when the package is imported. Likewise, the inlined body
+of a function is a chunk of its own, which the function's declaration
+refers to by its offset.
+
+Within a chunk, the graph is serialized in in-order fashion, starting
+with the declared object. Each object in the graph is serialized by
+writing its fields sequentially. If the field is a pointer to another
+object, that object is serialized, recursively. Otherwise the field is
+written. Non-pointer fields are all encoded as either an integer or
+string value.
+
+Only packages, types, and strings may be referred to more than once. When getting
 to a package or type that was not serialized before, an integer _index_
 is assigned to it, starting at 0. In this case, the encoding starts
 with an integer _tag_ < 0. The tag value indicates the kind of object
@@ -37,7 +45,8 @@ starts with the respective package or type index >= 0. An importer can
 trivially determine if a package or type needs to be read in for the first
 time (tag < 0) and entered into the respective package or type table, or
 if the package or type was seen already (index >= 0), in which case the
-index is used to look up the object in a table.
+index is used to look up the object in a table. The tables are local to
+a chunk (and to the header and index, see below).
 
 Before exporting or importing, the type tables are populated with the
 predeclared types (int, string, error, unsafe.Pointer, etc.). This way
@@ -47,25 +56,48 @@ Encoding format:
 
 The export data starts with a single byte indicating the encoding format
 (compact, or with debugging information), followed by a version string
-(so we can evolve the encoding if need be), the name of the imported
-package, and a string containing platform-specific information for that
-package.
-
-After this header, the lists of objects follow. After the objects, platform-
-specific data may be found which is not used strictly for type checking.
-
-The encoding of objects is straight-forward: Constants, variables, and
-functions start with their name, type, and possibly a value. Named types
-record their name and package so that they can be canonicalized: If the
-same type was imported before via another import, the importer must use
-the previously imported type pointer so that we have exactly one version
-(i.e., one pointer) for each named type (and read but discard the current
-type encoding). Unnamed types simply encode their respective fields.
+(so we can evolve the encoding if need be), the version of the compiler
+that wrote it, the name of the imported package, and a string containing
+platform-specific information for that package. An importer checks the
+versions first, so that it can report export data written by an
+incompatible compiler rather than fail to decode it.
+
+Export data written with -compressexport instead starts with the byte 'z',
+followed by the DEFLATE-compressed (RFC 1951) bytes of the uncompressed
+export data, including its encoding format byte. The compressed bytes
+are escaped in the same way as all export data (see escape).
+
+After this header follow the length and the bytes of the data section,
+and the index. The data section starts with the encoding format byte,
+too, so that each chunk can be decoded given just the data section.
+The index lists the packages whose objects are declared, each followed
+by the names of those objects and the offsets of their declarations.
+The package with the empty path is the package the export data belongs
+to, both in the index and in the chunks.
+
+A declaration starts with a tag for the kind of object. Constants are
+followed by their type and value, variables by their type, functions by
+their parameter lists and the offset of their inlined body, or -1, and
+named types by their underlying type and, unless that is an interface,
+their methods, which are encoded like functions. Unnamed types simply
+encode their respective fields.
+
+An inlined body starts with the names of the receiver, parameters, and
+results of its function, followed by the statements of the body. Each
+statement and expression starts with its operator, followed by its
+operands; the encoding mirrors the syntax the body was parsed from,
+so that an importer can build the nodes the parser would have built.
 
 In the encoding, any list (of objects, struct fields, methods, parameter
 names, but also the bytes of a string, etc.) starts with the list length.
 This permits an importer to allocate the right amount of memory for the
-list upfront, without the need to grow it later.
+list upfront, without the need to grow it later. Lists of statements
+and expressions in inlined bodies end with the operator OEND instead.
+
+Strings are deduplicated like packages and types, except that the tag
+for a string seen for the first time is its negated length, followed by
+its bytes. A string seen before is written as its index >= 0. Index 0 is
+reserved for the empty string, which is never written out.
 
 All integer values use variable-length encoding for compact representation.
 
@@ -89,7 +121,9 @@ package gc
 import (
 	"bytes"
 	"cmd/compile/internal/big"
+	"cmd/compile/internal/ir"
 	"cmd/internal/obj"
+	"compress/flate"
 	"encoding/binary"
 	"fmt"
 	"sort"
@@ -104,25 +138,107 @@ const (
 // TODO(gri) remove eventually
 const forceNewExport = false // force new export format - do not submit with this flag set
 
-const exportVersion = "v0"
+const exportVersion = "v4"
 
 // Export writes the export data for localpkg to out and returns the number of bytes written.
 func Export(out *obj.Biobuf, trace bool) int {
-	p := exporter{
-		out:      out,
-		pkgIndex: make(map[*Pkg]int),
-		typIndex: make(map[*Type]int),
-		trace:    trace,
+	x := &exportIndex{
+		offsets: make(map[*ir.Sym]int),
+		types:   make(map[*ir.Sym]*ir.Type),
+		bodies:  make(map[*ir.Node]bool),
+		trace:   trace,
 	}
 
-	// write low-level encoding format
+	// determine low-level encoding format
 	var format byte = 'c' // compact
 	if debugFormat {
 		format = 'd'
 	}
-	p.byte(format)
+	x.data.WriteByte(format)
+
+	// determine the inlined function bodies to export
+	// and the objects they depend on
+	x.reexportInlined()
+
+	// collect objects to export
+	var syms []*ir.Sym
+	var types []*ir.Type
+	for _, n := range exportlist {
+		sym := n.Sym
+		// TODO(gri) Closures appear marked as exported.
+		// Investigate and determine if we need this.
+		if sym.Flags&ir.SymExported != 0 {
+			continue
+		}
+		sym.Flags |= ir.SymExported
+
+		// TODO(gri) Closures have dots in their names;
+		// e.g., TestFloatZeroValue.func1 in math/big tests.
+		// We may not need this eventually. See also comment
+		// on sym.Flags&SymExported test above.
+		if strings.Contains(sym.Name, ".") {
+			Fatalf("exporter: unexpected symbol: %v", sym)
+		}
+
+		if sym.Def == nil {
+			Fatalf("exporter: unknown export symbol: %v", sym)
+		}
+		switch n := sym.Def; n.Op {
+		case ir.OLITERAL:
+			// constant
+			n = typecheck(n, Erv)
+			if n == nil || n.Op != ir.OLITERAL {
+				Fatalf("exporter: dumpexportconst: oconst nil: %v", sym)
+			}
+			if sym.Pkg != localpkg {
+				// inlined bodies refer to constants by value
+				break
+			}
+			syms = append(syms, sym)
+
+		case ir.ONAME:
+			// variable or function
+			n = typecheck(n, Erv|Ecall)
+			if n == nil || n.Type == nil {
+				Fatalf("exporter: variable/function exported but not defined: %v", sym)
+			}
+			syms = append(syms, sym)
+
+		case ir.OTYPE:
+			// named type
+			t := n.Type
+			if t.Etype == ir.TFORW {
+				Fatalf("exporter: export of incomplete type %v", sym)
+			}
+			types = append(types, t)
+
+		default:
+			Fatalf("exporter: unexpected export symbol: %v %v", Oconv(n.Op, 0), sym)
+		}
+	}
+	exportlist = nil // match export.go use of exportlist
+
+	// for reproducible output
+	sort.Stable(symByName(syms))
+	sort.Stable(typByName(types))
+	for _, sym := range syms {
+		x.declare(sym)
+	}
+	for _, t := range types {
+		x.declareType(t)
+	}
+
+	// write the declarations, including those of the
+	// named types they refer to
+	// (x.todo grows during iteration - cannot use range)
+	for i := 0; i < len(x.todo); i++ {
+		x.decl(x.todo[i])
+	}
+
+	// --- header ---
 
-	// --- generic export data ---
+	p := x.newExporter()
+	p.byte(format)
 
 	if p.trace {
 		p.tracef("\n--- package ---\n")
@@ -139,13 +255,13 @@ func Export(out *obj.Biobuf, trace bool) int {
 		p.tracef("\n")
 	}
 
-	// populate type map with predeclared "known" types
-	predecl := predeclared()
-	for index, typ := range predecl {
-		p.typIndex[typ] = index
+	// write compiler version
+	if p.trace {
+		p.tracef("compiler = ")
 	}
-	if len(p.typIndex) != len(predecl) {
-		Fatalf("exporter: duplicate entries in type map?")
+	p.string(obj.Getgoversion())
+	if p.trace {
+		p.tracef("\n")
 	}
 
 	// write package data
@@ -166,226 +282,236 @@ func Export(out *obj.Biobuf, trace bool) int {
 		p.tracef("\n")
 	}
 
-	// collect objects to export
-	var consts, vars, funcs []*Sym
-	var types []*Type
-	for _, n := range exportlist {
-		sym := n.Sym
-		// TODO(gri) Closures appear marked as exported.
-		// Investigate and determine if we need this.
-		if sym.Flags&SymExported != 0 {
-			continue
-		}
-		sym.Flags |= SymExported
+	// write data section
+	if p.trace {
+		p.tracef("\n--- data section ---\n[ ")
+	}
+	p.int(x.data.Len())
+	if p.trace {
+		p.tracef("]\n")
+	}
+	p.out.Write(x.data.Bytes())
 
-		// TODO(gri) Closures have dots in their names;
-		// e.g., TestFloatZeroValue.func1 in math/big tests.
-		// We may not need this eventually. See also comment
-		// on sym.Flags&SymExported test above.
-		if strings.Contains(sym.Name, ".") {
-			Fatalf("exporter: unexpected symbol: %v", sym)
-		}
+	x.writeIndex(p)
 
-		if sym.Flags&SymExport != 0 {
-			if sym.Def == nil {
-				Fatalf("exporter: unknown export symbol: %v", sym)
-			}
-			switch n := sym.Def; n.Op {
-			case OLITERAL:
-				// constant
-				n = typecheck(n, Erv)
-				if n == nil || n.Op != OLITERAL {
-					Fatalf("exporter: dumpexportconst: oconst nil: %v", sym)
-				}
-				consts = append(consts, sym)
+	if p.trace {
+		p.tracef("\n--- end ---\n")
+	}
 
-			case ONAME:
-				// variable or function
-				n = typecheck(n, Erv|Ecall)
-				if n == nil || n.Type == nil {
-					Fatalf("exporter: variable/function exported but not defined: %v", sym)
-				}
-				if n.Type.Etype == TFUNC && n.Class == PFUNC {
-					funcs = append(funcs, sym)
-				} else {
-					vars = append(vars, sym)
-				}
+	// --- end of export data ---
 
-			case OTYPE:
-				// named type
-				t := n.Type
-				if t.Etype == TFORW {
-					Fatalf("exporter: export of incomplete type %v", sym)
-				}
-				types = append(types, t)
+	data := escape(nil, p.out.Bytes())
+	out.Write(data)
+	return len(data)
+}
 
-			default:
-				Fatalf("exporter: unexpected export symbol: %v %v", Oconv(n.Op, 0), sym)
-			}
-		}
+func unidealType(typ *ir.Type, val ir.Val) *ir.Type {
+	// Untyped (ideal) constants get their own type. This decouples
+	// the constant type from the encoding of the constant value.
+	if typ == nil || isideal(typ) {
+		typ = untype(val.Ctype())
 	}
-	exportlist = nil // match export.go use of exportlist
+	return typ
+}
 
-	// for reproducible output
-	sort.Sort(symByName(consts))
-	sort.Sort(symByName(vars))
-	sort.Sort(symByName(funcs))
-	// sort types later when we have fewer types left
+// An exportIndex collects the declarations to export and writes each
+// of them, and each exported inlined body, as a chunk of the data
+// section, recording the offsets of the declarations for the index.
+type exportIndex struct {
+	data    bytes.Buffer         // data section
+	offsets map[*ir.Sym]int      // offset of each declaration, or -1 until it is written
+	types   map[*ir.Sym]*ir.Type // the named type declared by a sym, if any
+	todo    []*ir.Sym            // declarations to write, in order
+	bodies  map[*ir.Node]bool    // functions whose inlined bodies are exported
+	trace   bool
+}
 
-	// write consts
-	if p.trace {
-		p.tracef("\n--- consts ---\n[ ")
+// newExporter returns an exporter for a chunk of the data section,
+// or for the header and index, with its tables populated with the
+// predeclared types.
+func (x *exportIndex) newExporter() *exporter {
+	p := &exporter{
+		x:        x,
+		strIndex: map[string]int{"": 0}, // empty string is mapped to 0
+		pkgIndex: make(map[*ir.Pkg]int),
+		typIndex: make(map[*ir.Type]int),
+		trace:    x.trace,
 	}
-	p.int(len(consts))
-	if p.trace {
-		p.tracef("]\n")
+
+	// populate type map with predeclared "known" types
+	predecl := predeclared()
+	for index, typ := range predecl {
+		p.typIndex[typ] = index
 	}
-	for _, sym := range consts {
-		p.string(sym.Name)
-		n := sym.Def
-		p.typ(unidealType(n.Type, n.Val()))
-		p.value(n.Val())
-		if p.trace {
-			p.tracef("\n")
-		}
+	if len(p.typIndex) != len(predecl) {
+		Fatalf("exporter: duplicate entries in type map?")
 	}
 
-	// write vars
-	if p.trace {
-		p.tracef("\n--- vars ---\n[ ")
-	}
-	p.int(len(vars))
-	if p.trace {
-		p.tracef("]\n")
+	return p
+}
+
+// declare schedules the declaration of sym to be written,
+// unless it was scheduled before.
+func (x *exportIndex) declare(sym *ir.Sym) {
+	if _, ok := x.offsets[sym]; ok {
+		return
 	}
-	for _, sym := range vars {
-		p.string(sym.Name)
-		p.typ(sym.Def.Type)
-		if p.trace {
-			p.tracef("\n")
+	x.offsets[sym] = -1
+	x.todo = append(x.todo, sym)
+}
+
+// declareType is like declare for the name of the named type t.
+func (x *exportIndex) declareType(t *ir.Type) {
+	if t0 := x.types[t.Sym]; t0 != nil {
+		if t0 != t {
+			Fatalf("exporter: different types named %v", t.Sym)
 		}
+		return
 	}
+	x.types[t.Sym] = t
+	x.declare(t.Sym)
+}
 
-	// write funcs
+// decl writes the declaration of sym.
+func (x *exportIndex) decl(sym *ir.Sym) {
+	p := x.newExporter()
 	if p.trace {
-		p.tracef("\n--- funcs ---\n[ ")
+		p.tracef("\n--- %v ---\n", sym)
+	}
+
+	if t := x.types[sym]; t != nil {
+		p.tag(typeTag)
+		p.typeDecl(t)
+	} else {
+		switch n := sym.Def; n.Op {
+		case ir.OLITERAL:
+			p.tag(constTag)
+			p.typ(unidealType(n.Type, n.Val()))
+			p.value(n.Val())
+
+		case ir.ONAME:
+			if n.Type.Etype == ir.TFUNC && n.Class == ir.PFUNC {
+				p.tag(funcTag)
+				p.funcDecl(n)
+			} else {
+				p.tag(varTag)
+				p.typ(n.Type)
+			}
+
+		default:
+			Fatalf("exporter: unexpected declaration: %v %v", Oconv(n.Op, 0), sym)
+		}
 	}
-	p.int(len(funcs))
 	if p.trace {
-		p.tracef("]\n")
-	}
-	for _, sym := range funcs {
-		p.string(sym.Name)
-		sig := sym.Def.Type
-		inlineable := p.isInlineable(sym.Def)
-		p.paramList(sig.Params(), inlineable)
-		p.paramList(sig.Results(), inlineable)
-		index := -1
-		if inlineable {
-			index = len(p.inlined)
-			p.inlined = append(p.inlined, sym.Def.Func)
-		}
-		p.int(index)
-		if p.trace {
-			p.tracef("\n")
-		}
+		p.tracef("\n")
 	}
 
-	// determine which types are still left to write and sort them
-	i := 0
-	for _, t := range types {
-		if _, ok := p.typIndex[t]; !ok {
-			types[i] = t
-			i++
-		}
-	}
-	types = types[:i]
-	sort.Sort(typByName(types))
+	x.offsets[sym] = x.write(p)
+}
 
-	// write types
-	if p.trace {
-		p.tracef("\n--- types ---\n[ ")
+// write appends the chunk written by p to the data section
+// and returns its offset.
+func (x *exportIndex) write(p *exporter) int {
+	off := x.data.Len()
+	x.data.Write(p.out.Bytes())
+	return off
+}
+
+// writeIndex writes the index of the declarations to p.
+func (x *exportIndex) writeIndex(p *exporter) {
+	bypkg := make(map[*ir.Pkg][]*ir.Sym)
+	for sym := range x.offsets {
+		bypkg[sym.Pkg] = append(bypkg[sym.Pkg], sym)
 	}
-	p.int(len(types))
-	if p.trace {
-		p.tracef("]\n")
+	var pkgs []*ir.Pkg
+	for pkg := range bypkg {
+		pkgs = append(pkgs, pkg)
 	}
-	for _, t := range types {
-		// Writing a type may further reduce the number of types
-		// that are left to be written, but at this point we don't
-		// care.
-		p.typ(t)
-		if p.trace {
-			p.tracef("\n")
-		}
-	}
-
-	// --- compiler-specific export data ---
+	sort.Sort(pkgByPath(pkgs))
 
 	if p.trace {
-		p.tracef("\n--- inlined function bodies ---\n[ ")
-		if p.indent != 0 {
-			Fatalf("exporter: incorrect indentation")
-		}
+		p.tracef("\n--- index ---\n[ ")
 	}
-
-	// write inlined function bodies
-	p.int(len(p.inlined))
+	p.int(len(pkgs))
 	if p.trace {
 		p.tracef("]\n")
 	}
-	for _, f := range p.inlined {
-		if p.trace {
-			p.tracef("{ %s }\n", Hconv(f.Inl, FmtSharp))
+	for _, pkg := range pkgs {
+		syms := bypkg[pkg]
+		sort.Sort(symByName(syms))
+		p.pkg(pkg)
+		p.int(len(syms))
+		for _, sym := range syms {
+			off := x.offsets[sym]
+			if off < 0 {
+				Fatalf("exporter: declaration of %v not written", sym)
+			}
+			p.string(sym.Name)
+			p.int(off)
 		}
-		p.nodeList(f.Inl)
 		if p.trace {
 			p.tracef("\n")
 		}
 	}
+}
 
-	if p.trace {
-		p.tracef("\n--- end ---\n")
-	}
-
-	// --- end of export data ---
-
-	return p.written
+// funcDecl writes the signature of the function n and the offset
+// of its inlined body, or -1.
+func (p *exporter) funcDecl(n *ir.Node) {
+	sig := n.Type
+	p.walk(func() {
+		p.paramList(sig.Params())
+		p.paramList(sig.Results())
+	})
+	p.int(p.x.body(n))
 }
 
-func unidealType(typ *Type, val Val) *Type {
-	// Untyped (ideal) constants get their own type. This decouples
-	// the constant type from the encoding of the constant value.
-	if typ == nil || isideal(typ) {
-		typ = untype(val.Ctype())
+// typeDecl writes the underlying type of the named type t
+// and the methods associated with it.
+func (p *exporter) typeDecl(t *ir.Type) {
+	p.typ(t.Orig)
+
+	// interfaces don't have associated methods
+	if t.Orig.Etype == ir.TINTER {
+		return
 	}
-	return typ
+	p.associatedMethods(t)
 }
 
-type symByName []*Sym
+type symByName []*ir.Sym
 
 func (a symByName) Len() int           { return len(a) }
 func (a symByName) Less(i, j int) bool { return a[i].Name < a[j].Name }
 func (a symByName) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
 
-type typByName []*Type
+type typByName []*ir.Type
 
 func (a typByName) Len() int           { return len(a) }
 func (a typByName) Less(i, j int) bool { return a[i].Sym.Name < a[j].Sym.Name }
 func (a typByName) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
 
-type exporter struct {
-	out      *obj.Biobuf
-	pkgIndex map[*Pkg]int
-	typIndex map[*Type]int
-	inlined  []*Func
+type pkgByPath []*ir.Pkg
 
-	written int // bytes written
-	indent  int // for p.trace
-	trace   bool
+func (a pkgByPath) Len() int           { return len(a) }
+func (a pkgByPath) Less(i, j int) bool { return a[i].Path < a[j].Path }
+func (a pkgByPath) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
+
+// An exporter writes the header and index of the export data,
+// or a chunk of its data section.
+type exporter struct {
+	out      bytes.Buffer
+	x        *exportIndex
+	strIndex map[string]int
+	pkgIndex map[*ir.Pkg]int
+	typIndex map[*ir.Type]int
+	work     []func() // steps of the type walk to run, last first
+	next     []func() // steps scheduled by the current step
+
+	indent int // for p.trace
+	trace  bool
 }
 
-func (p *exporter) pkg(pkg *Pkg) {
+func (p *exporter) pkg(pkg *ir.Pkg) {
 	if pkg == nil {
 		Fatalf("exporter: unexpected nil pkg")
 	}
@@ -408,7 +534,53 @@ func (p *exporter) pkg(pkg *Pkg) {
 	p.string(pkg.Path)
 }
 
-func (p *exporter) typ(t *Type) {
+// Types in machine-generated code may be nested arbitrarily deeply.
+// Rather than recursing for each component of a type, the type
+// encoders below write the parts of a type they can write right away
+// and schedule the remaining ones (component types and everything
+// that follows them) with p.later. p.walk runs the scheduled steps
+// from an explicit work list, in the order in which a recursive walk
+// would write them, so the encoding does not depend on the walk.
+
+// typ writes the type t.
+func (p *exporter) typ(t *ir.Type) {
+	p.walk(func() { p.typ1(t) })
+}
+
+// walk calls f and then runs the steps scheduled by f and,
+// transitively, by those steps, each step's scheduled steps
+// before any steps scheduled earlier.
+func (p *exporter) walk(f func()) {
+	saved := p.next
+	p.next = nil
+	base := len(p.work)
+	p.work = append(p.work, f)
+	for len(p.work) > base {
+		f := p.work[len(p.work)-1]
+		p.work = p.work[:len(p.work)-1]
+		f()
+		// push the steps scheduled by f in reverse order
+		// so that they are run next, in the order scheduled
+		for i := len(p.next) - 1; i >= 0; i-- {
+			p.work = append(p.work, p.next[i])
+		}
+		p.next = p.next[:0]
+	}
+	p.next = saved
+}
+
+// later schedules f to run after the current step of p.walk
+// and after the steps it scheduled before.
+func (p *exporter) later(f func()) {
+	p.next = append(p.next, f)
+}
+
+// laterTyp schedules the type t to be written.
+func (p *exporter) laterTyp(t *ir.Type) {
+	p.later(func() { p.typ1(t) })
+}
+
+func (p *exporter) typ1(t *ir.Type) {
 	if t == nil {
 		Fatalf("exporter: nil type")
 	}
@@ -431,7 +603,7 @@ func (p *exporter) typ(t *Type) {
 	// otherwise, remember the type, write the type tag (< 0) and type data
 	if p.trace {
 		p.tracef("T%d = {>\n", len(p.typIndex))
-		defer p.tracef("<\n} ")
+		defer p.later(func() { p.tracef("<\n} ") })
 	}
 	p.typIndex[t] = len(p.typIndex)
 
@@ -453,56 +625,14 @@ func (p *exporter) typ(t *Type) {
 		p.tag(namedTag)
 		p.qualifiedName(sym)
 
-		// write underlying type
-		p.typ(t.Orig)
-
-		// interfaces don't have associated methods
-		if t.Orig.Etype == TINTER {
-			return
-		}
-
-		// sort methods for reproducible export format
-		// TODO(gri) Determine if they are already sorted
-		// in which case we can drop this step.
-		var methods []*Field
-		for _, m := range t.Methods().Slice() {
-			methods = append(methods, m)
-		}
-		sort.Sort(methodbyname(methods))
-		p.int(len(methods))
-
-		if p.trace && len(methods) > 0 {
-			p.tracef("associated methods {>")
-		}
-
-		for _, m := range methods {
-			if p.trace {
-				p.tracef("\n")
-			}
-			p.string(m.Sym.Name)
-			sig := m.Type
-			inlineable := p.isInlineable(sig.Nname)
-			p.paramList(sig.Recvs(), inlineable)
-			p.paramList(sig.Params(), inlineable)
-			p.paramList(sig.Results(), inlineable)
-			index := -1
-			if inlineable {
-				index = len(p.inlined)
-				p.inlined = append(p.inlined, sig.Nname.Func)
-			}
-			p.int(index)
-		}
-
-		if p.trace && len(methods) > 0 {
-			p.tracef("<\n} ")
-		}
-
+		// the underlying type and methods are part of the declaration
+		p.x.declareType(t)
 		return
 	}
 
 	// otherwise we have a type literal
 	switch t.Etype {
-	case TARRAY:
+	case ir.TARRAY:
 		// TODO(gri) define named constant for the -100
 		if t.Bound >= 0 || t.Bound == -100 {
 			p.tag(arrayTag)
@@ -510,27 +640,27 @@ func (p *exporter) typ(t *Type) {
 		} else {
 			p.tag(sliceTag)
 		}
-		p.typ(t.Type)
+		p.laterTyp(t.Type)
 
-	case T_old_DARRAY:
+	case ir.T_old_DARRAY:
 		// see p.param use of T_old_DARRAY
 		p.tag(dddTag)
-		p.typ(t.Type)
+		p.laterTyp(t.Type)
 
-	case TSTRUCT:
+	case ir.TSTRUCT:
 		p.tag(structTag)
 		p.fieldList(t)
 
-	case TPTR32, TPTR64: // could use Tptr but these are constants
+	case ir.TPTR32, ir.TPTR64: // could use Tptr but these are constants
 		p.tag(pointerTag)
-		p.typ(t.Type)
+		p.laterTyp(t.Type)
 
-	case TFUNC:
+	case ir.TFUNC:
 		p.tag(signatureTag)
-		p.paramList(t.Params(), false)
-		p.paramList(t.Results(), false)
+		p.paramList(t.Params())
+		p.paramList(t.Results())
 
-	case TINTER:
+	case ir.TINTER:
 		p.tag(interfaceTag)
 
 		// gc doesn't separate between embedded interfaces
@@ -538,45 +668,81 @@ func (p *exporter) typ(t *Type) {
 		p.int(0) // no embedded interfaces
 		p.methodList(t)
 
-	case TMAP:
+	case ir.TMAP:
 		p.tag(mapTag)
-		p.typ(t.Key()) // key
-		p.typ(t.Type)  // val
+		p.laterTyp(t.Key()) // key
+		p.laterTyp(t.Type)  // val
 
-	case TCHAN:
+	case ir.TCHAN:
 		p.tag(chanTag)
 		p.int(int(t.Chan))
-		p.typ(t.Type)
+		p.laterTyp(t.Type)
 
 	default:
 		Fatalf("exporter: unexpected type: %s (Etype = %d)", Tconv(t, 0), t.Etype)
 	}
 }
 
-func (p *exporter) qualifiedName(sym *Sym) {
-	p.string(sym.Name)
-	p.pkg(sym.Pkg)
-}
+// associatedMethods writes the methods of the named type t.
+func (p *exporter) associatedMethods(t *ir.Type) {
+	// sort methods for reproducible export format
+	// TODO(gri) Determine if they are already sorted
+	// in which case we can drop this step.
+	var methods []*ir.Field
+	for _, m := range t.Methods().Slice() {
+		methods = append(methods, m)
+	}
+	sort.Sort(methodbyname(methods))
+	p.int(len(methods))
 
-func (p *exporter) fieldList(t *Type) {
-	if p.trace && t.NumFields() > 0 {
-		p.tracef("fields {>")
+	if p.trace && len(methods) > 0 {
+		p.tracef("associated methods {>")
 		defer p.tracef("<\n} ")
 	}
 
-	p.int(t.NumFields())
-	for _, f := range t.Fields().Slice() {
+	for _, m := range methods {
 		if p.trace {
 			p.tracef("\n")
 		}
-		p.field(f)
+		p.string(m.Sym.Name)
+		sig := m.Type
+		p.walk(func() {
+			p.paramList(sig.Recvs())
+			p.paramList(sig.Params())
+			p.paramList(sig.Results())
+		})
+		p.int(p.x.body(sig.Nname))
 	}
 }
 
-func (p *exporter) field(f *Field) {
-	p.fieldName(f)
-	p.typ(f.Type)
-	p.note(f.Note)
+func (p *exporter) qualifiedName(sym *ir.Sym) {
+	p.string(sym.Name)
+	p.pkg(sym.Pkg)
+}
+
+func (p *exporter) fieldList(t *ir.Type) {
+	p.later(func() {
+		if p.trace && t.NumFields() > 0 {
+			p.tracef("fields {>")
+			defer p.later(func() { p.tracef("<\n} ") })
+		}
+
+		p.int(t.NumFields())
+		for _, f := range t.Fields().Slice() {
+			p.field(f)
+		}
+	})
+}
+
+func (p *exporter) field(f *ir.Field) {
+	p.later(func() {
+		if p.trace {
+			p.tracef("\n")
+		}
+		p.fieldName(f)
+		p.laterTyp(f.Type)
+		p.later(func() { p.note(f.Note) })
+	})
 }
 
 func (p *exporter) note(n *string) {
@@ -587,33 +753,37 @@ func (p *exporter) note(n *string) {
 	p.string(s)
 }
 
-func (p *exporter) methodList(t *Type) {
-	if p.trace && t.NumFields() > 0 {
-		p.tracef("methods {>")
-		defer p.tracef("<\n} ")
-	}
+func (p *exporter) methodList(t *ir.Type) {
+	p.later(func() {
+		if p.trace && t.NumFields() > 0 {
+			p.tracef("methods {>")
+			defer p.later(func() { p.tracef("<\n} ") })
+		}
 
-	p.int(t.NumFields())
-	for _, m := range t.Fields().Slice() {
-		if p.trace {
-			p.tracef("\n")
+		p.int(t.NumFields())
+		for _, m := range t.Fields().Slice() {
+			p.method(m)
 		}
-		p.method(m)
-	}
+	})
 }
 
-func (p *exporter) method(m *Field) {
-	p.fieldName(m)
-	// TODO(gri) For functions signatures, we use p.typ() to export
-	// so we could share the same type with multiple functions. Do
-	// the same here, or never try to do this for functions.
-	p.paramList(m.Type.Params(), false)
-	p.paramList(m.Type.Results(), false)
+func (p *exporter) method(m *ir.Field) {
+	p.later(func() {
+		if p.trace {
+			p.tracef("\n")
+		}
+		p.fieldName(m)
+		// TODO(gri) For functions signatures, we use p.typ() to export
+		// so we could share the same type with multiple functions. Do
+		// the same here, or never try to do this for functions.
+		p.paramList(m.Type.Params())
+		p.paramList(m.Type.Results())
+	})
 }
 
 // fieldName is like qualifiedName but it doesn't record the package
 // for blank (_) or exported names.
-func (p *exporter) fieldName(t *Field) {
+func (p *exporter) fieldName(t *ir.Field) {
 	sym := t.Sym
 
 	var name string
@@ -631,7 +801,7 @@ func (p *exporter) fieldName(t *Field) {
 	}
 }
 
-func basetypeName(t *Type) string {
+func basetypeName(t *ir.Type) string {
 	s := t.Sym
 	if s == nil && Isptr[t.Etype] {
 		s = t.Type.Sym // deref
@@ -642,46 +812,50 @@ func basetypeName(t *Type) string {
 	return ""
 }
 
-func (p *exporter) paramList(params *Type, numbered bool) {
-	if params.Etype != TSTRUCT || !params.Funarg {
+func (p *exporter) paramList(params *ir.Type) {
+	if params.Etype != ir.TSTRUCT || !params.Funarg {
 		Fatalf("exporter: parameter list expected")
 	}
 
-	// use negative length to indicate unnamed parameters
-	// (look at the first parameter only since either all
-	// names are present or all are absent)
-	n := params.NumFields()
-	if n > 0 && parName(params.Field(0), numbered) == "" {
-		n = -n
-	}
-	p.int(n)
-	for _, q := range params.Fields().Slice() {
-		p.param(q, n, numbered)
-	}
+	p.later(func() {
+		// use negative length to indicate unnamed parameters
+		// (look at the first parameter only since either all
+		// names are present or all are absent)
+		n := params.NumFields()
+		if n > 0 && parName(params.Field(0)) == "" {
+			n = -n
+		}
+		p.int(n)
+		for _, q := range params.Fields().Slice() {
+			p.param(q, n)
+		}
+	})
 }
 
-func (p *exporter) param(q *Field, n int, numbered bool) {
+func (p *exporter) param(q *ir.Field, n int) {
 	t := q.Type
 	if q.Isddd {
 		// create a fake type to encode ... just for the p.typ call
 		// (T_old_DARRAY is not used anywhere else in the compiler,
 		// we use it here to communicate between p.param and p.typ.)
-		t = &Type{Etype: T_old_DARRAY, Type: t.Type}
-	}
-	p.typ(t)
-	if n > 0 {
-		p.string(parName(q, numbered))
-	}
-	// TODO(gri) This is compiler-specific (escape info).
-	// Move into compiler-specific section eventually?
-	// (Not having escape info causes tests to fail, e.g. runtime GCInfoTest)
-	//
-	// TODO(gri) The q.Note is much more verbose that necessary and
-	// adds significantly to export data size. FIX THIS.
-	p.note(q.Note)
+		t = &ir.Type{Etype: ir.T_old_DARRAY, Type: t.Type}
+	}
+	p.laterTyp(t)
+	p.later(func() {
+		if n > 0 {
+			p.string(parName(q))
+		}
+		// TODO(gri) This is compiler-specific (escape info).
+		// Move into compiler-specific section eventually?
+		// (Not having escape info causes tests to fail, e.g. runtime GCInfoTest)
+		//
+		// TODO(gri) The q.Note is much more verbose that necessary and
+		// adds significantly to export data size. FIX THIS.
+		p.note(q.Note)
+	})
 }
 
-func parName(q *Field, numbered bool) string {
+func parName(q *ir.Field) string {
 	if q.Sym == nil {
 		return ""
 	}
@@ -698,16 +872,14 @@ func parName(q *Field, numbered bool) string {
 			Fatalf("exporter: unexpected parameter name: %s", name)
 		}
 	}
-	// undo gc-internal name specialization unless required
-	if !numbered {
-		if i := strings.Index(name, "·"); i > 0 {
-			name = name[:i] // cut off numbering
-		}
+	// undo gc-internal name specialization
+	if i := strings.Index(name, "·"); i > 0 {
+		name = name[:i] // cut off numbering
 	}
 	return name
 }
 
-func (p *exporter) value(x Val) {
+func (p *exporter) value(x ir.Val) {
 	if p.trace {
 		p.tracef("= ")
 	}
@@ -720,8 +892,8 @@ func (p *exporter) value(x Val) {
 		}
 		p.tag(tag)
 
-	case *Mpint:
-		if Minintval[TINT64].Cmp(x) <= 0 && x.Cmp(Maxintval[TINT64]) <= 0 {
+	case *ir.Mpint:
+		if Minintval[ir.TINT64].Cmp(x) <= 0 && x.Cmp(Maxintval[ir.TINT64]) <= 0 {
 			// common case: x fits into an int64 - use compact encoding
 			p.tag(int64Tag)
 			p.int64(x.Int64())
@@ -729,16 +901,16 @@ func (p *exporter) value(x Val) {
 		}
 		// uncommon case: large x - use float encoding
 		// (powers of 2 will be encoded efficiently with exponent)
-		f := newMpflt()
+		f := ir.NewMpflt()
 		f.SetInt(x)
 		p.tag(floatTag)
 		p.float(f)
 
-	case *Mpflt:
+	case *ir.Mpflt:
 		p.tag(floatTag)
 		p.float(x)
 
-	case *Mpcplx:
+	case *ir.Mpcplx:
 		p.tag(complexTag)
 		p.float(&x.Real)
 		p.float(&x.Imag)
@@ -747,7 +919,7 @@ func (p *exporter) value(x Val) {
 		p.tag(stringTag)
 		p.string(x)
 
-	case *NilVal:
+	case *ir.NilVal:
 		// not a constant but used in exported function bodies
 		p.tag(nilTag)
 
@@ -756,7 +928,7 @@ func (p *exporter) value(x Val) {
 	}
 }
 
-func (p *exporter) float(x *Mpflt) {
+func (p *exporter) float(x *ir.Mpflt) {
 	// extract sign (there is no -0)
 	f := &x.Val
 	sign := f.Sign()
@@ -788,252 +960,436 @@ func (p *exporter) float(x *Mpflt) {
 // ----------------------------------------------------------------------------
 // Inlined function bodies
 
-func (p *exporter) isInlineable(n *Node) bool {
-	if n != nil && n.Func != nil && len(n.Func.Inl.Slice()) != 0 {
-		// when lazily typechecking inlined bodies, some re-exported ones may not have been typechecked yet.
-		// currently that can leave unresolved ONONAMEs in import-dot-ed packages in the wrong package
-		if Debug['l'] < 2 {
-			typecheckinl(n)
+// reexportInlined determines the inlineable functions and methods
+// reachable from exportlist whose bodies are exported, and appends
+// the objects those bodies refer to to exportlist so that importers
+// can typecheck them, as the textual exporter does (see reexportdep).
+func (x *exportIndex) reexportInlined() {
+	seen := make(map[*ir.Type]bool)
+	// exportlist grows during iteration - cannot use range
+	for i := 0; i < len(exportlist); i++ {
+		n := exportlist[i].Sym.Def
+		if n == nil {
+			continue // reported when collecting objects
+		}
+		switch n.Op {
+		case ir.ONAME:
+			n = typecheck(n, Erv|Ecall)
+			if n == nil || n.Type == nil {
+				continue // reported when collecting objects
+			}
+			x.reexportType(n.Type, seen)
+			if n.Class == ir.PFUNC {
+				x.reexportBody(n)
+			}
+		case ir.OTYPE:
+			x.reexportType(n.Type, seen)
+		}
+	}
+}
+
+// reexportType finds the inlineable methods of t and of the types
+// it is composed of (export.go:dumpexporttype). Like p.typ, it uses
+// a work list rather than recursion to handle deeply nested types.
+func (x *exportIndex) reexportType(t *ir.Type, seen map[*ir.Type]bool) {
+	work := []*ir.Type{t}
+	for len(work) > 0 {
+		t := work[len(work)-1]
+		work = work[:len(work)-1]
+		if t == nil || seen[t] || t == ir.Types[t.Etype] || t == ir.Bytetype || t == ir.Runetype || t == ir.Errortype {
+			continue
+		}
+		seen[t] = true
+
+		switch t.Etype {
+		case ir.TSTRUCT, ir.TINTER:
+			for _, f := range t.Fields().Slice() {
+				work = append(work, f.Type)
+			}
+		case ir.TFUNC:
+			work = append(work, t.Recvs(), t.Results(), t.Params())
+		case ir.TMAP:
+			work = append(work, t.Type, t.Down) // t.Down is the key
+		case ir.TARRAY, ir.TCHAN, ir.TPTR32, ir.TPTR64:
+			work = append(work, t.Type)
+		}
+
+		if t.Sym == nil {
+			continue
+		}
+		for _, f := range t.Methods().Slice() {
+			work = append(work, f.Type)
+			x.reexportBody(f.Type.Nname) // nname was set by caninl
 		}
-		return true
 	}
-	return false
 }
 
-func (p *exporter) nodeList(list Nodes) {
+// reexportBody marks the body of fn, if any, for export
+// and appends the objects it refers to to exportlist.
+func (x *exportIndex) reexportBody(fn *ir.Node) {
+	if fn == nil || x.bodies[fn] || !hasinl(fn) {
+		return
+	}
+	// when lazily typechecking inlined bodies, some re-exported ones may not have been typechecked yet.
+	// currently that can leave unresolved ONONAMEs in import-dot-ed packages in the wrong package
+	if Debug['l'] < 2 {
+		typecheckinl(fn)
+	}
+	x.bodies[fn] = true
+	reexportdeplist(fn.Func.Inl)
+}
+
+// body writes the inlined body of fn and returns its offset,
+// or -1 if fn has no exported body.
+func (x *exportIndex) body(fn *ir.Node) int {
+	if fn == nil || !x.bodies[fn] {
+		return -1
+	}
+	p := x.newExporter()
 	if p.trace {
-		p.tracef("[ ")
+		p.tracef("\n--- body of %v ---\n", fn)
 	}
-	p.int(list.Len())
+	p.funcBody(fn)
 	if p.trace {
-		if list.Len() == 0 {
-			p.tracef("] {}")
-		} else {
-			p.tracef("] {>")
-			defer p.tracef("<\n}")
+		p.tracef("\n")
+	}
+	return x.write(p)
+}
+
+// funcBody writes the names of the receiver, parameters, and
+// results of fn, followed by the statements of its inlined body.
+// The body has been typechecked; the encoding undoes the changes
+// typecheck made to the nodes the parser built, where needed.
+func (p *exporter) funcBody(fn *ir.Node) {
+	for _, f := range ir.RecvsParamsResults {
+		for _, q := range f(fn.Type).Fields().Slice() {
+			p.name(q.Nname)
 		}
 	}
-	for _, n := range list.Slice() {
-		if p.trace {
-			p.tracef("\n")
+	p.stmtList(fn.Func.Inl)
+}
+
+// name writes the name of the local or package-level object n.
+// Locals are numbered as in the textual export format, so that
+// their names are unique within the body.
+func (p *exporter) name(n *ir.Node) {
+	var name string
+	switch {
+	case n == nil || n.Sym == nil || strings.HasPrefix(n.Sym.Name, "~r"):
+		name = "" // unnamed result
+	case isblank(n) || strings.HasPrefix(n.Sym.Name, "~b"):
+		name = "_"
+	case n.Name != nil && n.Name.Vargen > 0:
+		name = fmt.Sprintf("%s·%d", n.Sym.Name, n.Name.Vargen)
+	default:
+		name = n.Sym.Name
+	}
+	p.string(name)
+	if name != "" && name != "_" {
+		p.pkg(n.Sym.Pkg)
+	}
+}
+
+// fieldSym writes the field or method name sym. If method is set,
+// sym was made by methodsym, and only the method name is written.
+func (p *exporter) fieldSym(sym *ir.Sym, method bool) {
+	name := sym.Name
+	if method {
+		if i := strings.LastIndex(name, "."); i >= 0 {
+			name = name[i+1:]
 		}
-		p.node(n)
+	}
+	p.string(name)
+	if name != "_" && !exportname(name) {
+		p.pkg(sym.Pkg)
 	}
 }
 
-func (p *exporter) node(n *Node) {
-	p.op(n.Op)
+func (p *exporter) stmtList(list ir.Nodes) {
+	for _, n := range list.Slice() {
+		p.stmt(n)
+	}
+	p.op(ir.OEND)
+}
 
-	switch n.Op {
-	// names
-	case ONAME, OPACK, ONONAME:
-		p.sym(n.Sym)
+func (p *exporter) stmt(n *ir.Node) {
+	// statements other than if and switch can't have an init:
+	// write the init statements before them
+	if n.Ninit.Len() > 0 && !stmtwithinit(n.Op) {
+		for _, n := range n.Ninit.Slice() {
+			p.stmt(n)
+		}
+	}
 
-	case OTYPE:
-		if p.bool(n.Type == nil) {
-			p.sym(n.Sym)
-		} else {
-			p.typ(n.Type)
+	switch op := n.Op; op {
+	case ir.ODCL:
+		if isblank(n.Left) {
+			break
 		}
+		p.op(ir.ODCL)
+		p.name(n.Left)
+		p.typ(n.Left.Type)
 
-	case OLITERAL:
-		p.typ(unidealType(n.Type, n.Val()))
-		p.value(n.Val())
+	case ir.OAS, ir.OASWB:
+		// a declaration without an initialization
+		// (var x T) is written as ODCL only
+		if n.Right == nil {
+			break
+		}
+		p.op(ir.OAS)
+		p.expr(n.Left)
+		p.expr(n.Right)
 
-	// expressions
-	case OMAKEMAP, OMAKECHAN, OMAKESLICE:
-		if p.bool(n.List.Len() != 0) {
-			p.nodeList(n.List) // TODO(gri) do we still need to export this?
+	case ir.OASOP:
+		p.op(ir.OASOP)
+		p.int(int(n.Etype))
+		p.expr(n.Left)
+		if p.bool(!n.Implicit) { // x op= y rather than x++ or x--
+			p.expr(n.Right)
 		}
-		p.nodesOrNil(n.Left, n.Right)
-		p.typ(n.Type)
 
-	case OPLUS, OMINUS, OADDR, OCOM, OIND, ONOT, ORECV:
-		p.node(n.Left)
+	case ir.OAS2, ir.OAS2DOTTYPE, ir.OAS2FUNC, ir.OAS2MAPR, ir.OAS2RECV:
+		p.op(ir.OAS2)
+		p.exprList(n.List)
+		p.exprList(n.Rlist)
 
-	case OADD, OAND, OANDAND, OANDNOT, ODIV, OEQ, OGE, OGT, OLE, OLT,
-		OLSH, OMOD, OMUL, ONE, OOR, OOROR, ORSH, OSEND,
-		OSUB, OXOR:
-		p.node(n.Left)
-		p.node(n.Right)
+	case ir.ORETURN:
+		p.op(ir.ORETURN)
+		p.exprList(n.List)
 
-	case OADDSTR:
-		p.nodeList(n.List)
+	case ir.OIF:
+		p.op(ir.OIF)
+		p.stmtList(n.Ninit)
+		p.expr(n.Left)
+		p.stmtList(n.Nbody)
+		p.stmtList(n.Rlist)
 
-	case OPTRLIT:
-		p.node(n.Left)
+	case ir.OSWITCH:
+		p.op(ir.OSWITCH)
+		p.stmtList(n.Ninit)
+		p.exprsOrNil(n.Left, nil)
+		p.stmtList(n.List)
 
-	case OSTRUCTLIT:
-		p.typ(n.Type)
-		p.nodeList(n.List)
-		p.bool(n.Implicit)
+	case ir.OCASE, ir.OXCASE:
+		p.op(ir.OXCASE)
+		p.exprList(n.List)
+		p.stmtList(n.Nbody)
 
-	case OARRAYLIT, OMAPLIT:
-		p.typ(n.Type)
-		p.nodeList(n.List)
-		p.bool(n.Implicit)
+	case ir.OFALL, ir.OXFALL:
+		p.op(ir.OXFALL)
 
-	case OKEY:
-		p.nodesOrNil(n.Left, n.Right)
+	case ir.OGOTO, ir.OLABEL:
+		p.op(op)
+		p.name(n.Left)
 
-	case OCOPY, OCOMPLEX:
-		p.node(n.Left)
-		p.node(n.Right)
+	case ir.OBLOCK:
+		p.op(ir.OBLOCK)
+		p.stmtList(n.List)
 
-	case OCONV, OCONVIFACE, OCONVNOP, OARRAYBYTESTR, OARRAYRUNESTR, OSTRARRAYBYTE, OSTRARRAYRUNE, ORUNESTR:
-		p.typ(n.Type)
-		if p.bool(n.Left != nil) {
-			p.node(n.Left)
-		} else {
-			p.nodeList(n.List)
-		}
+	case ir.OEMPTY, ir.ODCLCONST:
+		// nothing to write; references to local
+		// constants have been replaced by their values
 
-	case ODOT, ODOTPTR, ODOTMETH, ODOTINTER, OXDOT:
-		p.node(n.Left)
-		p.sym(n.Sym)
+	default:
+		p.expr(n)
+	}
+}
 
-	case ODOTTYPE, ODOTTYPE2:
-		p.node(n.Left)
-		if p.bool(n.Right != nil) {
-			p.node(n.Right)
-		} else {
-			p.typ(n.Type)
+func (p *exporter) exprList(list ir.Nodes) {
+	for _, n := range list.Slice() {
+		p.expr(n)
+	}
+	p.op(ir.OEND)
+}
+
+// exprsOrNil writes a and b, either of which may be nil.
+func (p *exporter) exprsOrNil(a, b *ir.Node) {
+	ab := 0
+	if a != nil {
+		ab |= 1
+	}
+	if b != nil {
+		ab |= 2
+	}
+	p.int(ab)
+	if ab&1 != 0 {
+		p.expr(a)
+	}
+	if ab&2 != 0 {
+		p.expr(b)
+	}
+}
+
+func (p *exporter) expr(n *ir.Node) {
+	// the parser doesn't build implicit dereferences
+	// and address operations - typecheck adds them
+	for n.Implicit && (n.Op == ir.OIND || n.Op == ir.OADDR) {
+		n = n.Left
+	}
+
+	switch op := n.Op; op {
+	// names and types
+
+	case ir.ONAME:
+		if n.Left != nil && n.Left.Op == ir.OTYPE && n.Right != nil {
+			// method expression T.m
+			p.op(ir.OXDOT)
+			p.expr(n.Left)
+			p.fieldSym(n.Right.Sym, true)
+			break
 		}
+		p.op(ir.ONAME)
+		p.name(n)
 
-	case OINDEX, OINDEXMAP, OSLICE, OSLICESTR, OSLICEARR, OSLICE3, OSLICE3ARR:
-		p.node(n.Left)
-		p.node(n.Right)
+	case ir.ONONAME, ir.OPACK:
+		p.op(ir.ONAME)
+		p.name(n)
 
-	case OREAL, OIMAG, OAPPEND, OCAP, OCLOSE, ODELETE, OLEN, OMAKE, ONEW, OPANIC,
-		ORECOVER, OPRINT, OPRINTN:
-		p.nodesOrNil(n.Left, nil)
-		p.nodeList(n.List)
-		p.bool(n.Isddd)
+	case ir.OTYPE:
+		p.op(ir.OTYPE)
+		p.typ(n.Type)
 
-	case OCALL, OCALLFUNC, OCALLMETH, OCALLINTER, OGETG:
-		p.node(n.Left)
-		p.nodeList(n.List)
-		p.bool(n.Isddd)
+	case ir.OLITERAL:
+		p.op(ir.OLITERAL)
+		p.typ(unidealType(n.Type, n.Val()))
+		p.value(n.Val())
 
-	case OCMPSTR, OCMPIFACE:
-		p.node(n.Left)
-		p.node(n.Right)
-		p.int(int(n.Etype))
+	case ir.OPAREN:
+		p.expr(n.Left)
 
-	case OPAREN:
-		p.node(n.Left)
+	// composite literals
 
-	// statements
-	case ODCL:
-		p.node(n.Left) // TODO(gri) compare with fmt code
-		p.typ(n.Left.Type)
+	case ir.OPTRLIT:
+		p.op(ir.OPTRLIT)
+		p.expr(n.Left)
 
-	case OAS, OASWB:
-		p.nodesOrNil(n.Left, n.Right) // n.Right might be nil
-		p.bool(n.Colas)
+	case ir.OSTRUCTLIT:
+		p.op(ir.OSTRUCTLIT)
+		p.typ(n.Type)
+		p.int(n.List.Len())
+		for _, kv := range n.List.Slice() {
+			p.fieldSym(kv.Left.Sym, false)
+			p.expr(kv.Right)
+		}
 
-	case OASOP:
-		p.node(n.Left)
-		// n.Implicit indicates ++ or --, n.Right is 1 in those cases
-		p.node(n.Right)
-		p.int(int(n.Etype))
+	case ir.OARRAYLIT, ir.OMAPLIT:
+		p.op(ir.OCOMPLIT)
+		p.typ(n.Type)
+		p.exprList(n.List)
 
-	case OAS2:
-		p.nodeList(n.List)
-		p.nodeList(n.Rlist)
+	case ir.OKEY:
+		p.op(ir.OKEY)
+		p.exprsOrNil(n.Left, n.Right)
 
-	case OAS2DOTTYPE, OAS2FUNC, OAS2MAPR, OAS2RECV:
-		p.nodeList(n.List)
-		p.nodeList(n.Rlist)
+	// selectors, indexing, and slicing
 
-	case ORETURN:
-		p.nodeList(n.List)
+	case ir.OXDOT, ir.ODOT, ir.ODOTPTR, ir.ODOTMETH, ir.ODOTINTER:
+		p.op(ir.OXDOT)
+		p.expr(n.Left)
+		p.fieldSym(n.Sym, op == ir.ODOTMETH || op == ir.ODOTINTER)
 
-	case OPROC, ODEFER:
-		p.node(n.Left)
+	case ir.ODOTTYPE, ir.ODOTTYPE2:
+		p.op(ir.ODOTTYPE)
+		p.expr(n.Left)
+		p.typ(n.Type)
 
-	case OIF:
-		p.nodeList(n.Ninit)
-		p.node(n.Left)
-		p.nodeList(n.Nbody)
-		p.nodeList(n.Rlist)
+	case ir.OINDEX, ir.OINDEXMAP:
+		p.op(ir.OINDEX)
+		p.expr(n.Left)
+		p.expr(n.Right)
 
-	case OFOR:
-		p.nodeList(n.Ninit)
-		p.nodesOrNil(n.Left, n.Right)
-		p.nodeList(n.Nbody)
+	case ir.OSLICE, ir.OSLICESTR, ir.OSLICEARR:
+		p.op(ir.OSLICE)
+		p.expr(n.Left)
+		p.exprsOrNil(n.Right.Left, n.Right.Right)
 
-	case ORANGE:
-		if p.bool(n.List.Len() != 0) {
-			p.nodeList(n.List)
-		}
-		p.node(n.Right)
-		p.nodeList(n.Nbody)
+	case ir.OSLICE3, ir.OSLICE3ARR:
+		p.op(ir.OSLICE3)
+		p.expr(n.Left)
+		p.exprsOrNil(n.Right.Left, n.Right.Right.Left)
+		p.expr(n.Right.Right.Right)
 
-	case OSELECT, OSWITCH:
-		p.nodeList(n.Ninit)
-		p.nodesOrNil(n.Left, nil)
-		p.nodeList(n.List)
+	// conversions and calls
 
-	case OCASE, OXCASE:
-		if p.bool(n.List.Len() != 0) {
-			p.nodeList(n.List)
+	case ir.OCONV, ir.OCONVIFACE, ir.OCONVNOP, ir.OARRAYBYTESTR, ir.OARRAYRUNESTR, ir.OSTRARRAYBYTE, ir.OSTRARRAYRUNE, ir.ORUNESTR:
+		p.op(ir.OCONV)
+		p.typ(n.Type)
+		p.args(n)
+
+	case ir.OCOPY, ir.OCOMPLEX:
+		p.op(op)
+		p.expr(n.Left)
+		p.expr(n.Right)
+		p.op(ir.OEND)
+
+	case ir.OREAL, ir.OIMAG, ir.OAPPEND, ir.OCAP, ir.OCLOSE, ir.ODELETE, ir.OLEN, ir.ONEW, ir.OPANIC, ir.ORECOVER, ir.OPRINT, ir.OPRINTN:
+		p.op(op)
+		p.args(n)
+		if op == ir.OAPPEND {
+			p.bool(n.Isddd)
 		}
-		p.nodeList(n.Nbody)
 
-	case OBREAK, OCONTINUE, OGOTO, OFALL, OXFALL:
-		p.nodesOrNil(n.Left, nil)
+	case ir.OMAKEMAP, ir.OMAKECHAN, ir.OMAKESLICE:
+		p.op(ir.OMAKE)
+		p.typ(n.Type)
+		switch {
+		case n.Right != nil:
+			p.expr(n.Left)
+			p.expr(n.Right)
+		case n.Left != nil && (op == ir.OMAKESLICE || !isideal(n.Left.Type)):
+			// typecheck supplies a size of 0 for maps and channels
+			p.expr(n.Left)
+		}
+		p.op(ir.OEND)
 
-	case OEMPTY, ODCLCONST:
-		// nothing to do
+	case ir.OCALL, ir.OCALLFUNC, ir.OCALLMETH, ir.OCALLINTER, ir.OGETG:
+		p.op(ir.OCALL)
+		p.expr(n.Left)
+		p.exprList(n.List)
+		p.bool(n.Isddd)
 
-	case OLABEL:
-		p.node(n.Left)
+	// operators
 
-	default:
-		Fatalf("exporter: CANNOT EXPORT: %s\nPlease notify gri@\n", opnames[n.Op])
-	}
-}
+	case ir.OPLUS, ir.OMINUS, ir.OADDR, ir.OCOM, ir.OIND, ir.ONOT, ir.ORECV:
+		p.op(op)
+		p.expr(n.Left)
 
-func (p *exporter) nodesOrNil(a, b *Node) {
-	ab := 0
-	if a != nil {
-		ab |= 1
-	}
-	if b != nil {
-		ab |= 2
-	}
-	p.int(ab)
-	if ab&1 != 0 {
-		p.node(a)
-	}
-	if ab&2 != 0 {
-		p.node(b)
-	}
-}
+	case ir.OADD, ir.OAND, ir.OANDAND, ir.OANDNOT, ir.ODIV, ir.OEQ, ir.OGE, ir.OGT, ir.OLE, ir.OLT,
+		ir.OLSH, ir.OMOD, ir.OMUL, ir.ONE, ir.OOR, ir.OOROR, ir.ORSH, ir.OSEND, ir.OSUB, ir.OXOR:
+		p.op(op)
+		p.expr(n.Left)
+		p.expr(n.Right)
 
-func (p *exporter) sym(s *Sym) {
-	name := s.Name
-	p.string(name)
-	if name == "?" || name != "_" && name != "" && !exportname(name) {
-		p.pkg(s.Pkg)
+	case ir.OADDSTR:
+		p.op(ir.OADDSTR)
+		p.exprList(n.List)
+
+	case ir.OCMPSTR, ir.OCMPIFACE:
+		// the comparison operator is in n.Etype
+		p.op(ir.Op(n.Etype))
+		p.expr(n.Left)
+		p.expr(n.Right)
+
+	default:
+		Fatalf("exporter: cannot export %v (%d) node in inlined body of %v", Oconv(op, 0), op, n)
 	}
 }
 
-func (p *exporter) bool(b bool) bool {
-	x := 0
-	if b {
-		x = 1
+// args writes the arguments of the builtin or conversion n.
+func (p *exporter) args(n *ir.Node) {
+	if n.Left != nil {
+		p.expr(n.Left)
+		p.op(ir.OEND)
+		return
 	}
-	p.int(x)
-	return b
+	p.exprList(n.List)
 }
 
-func (p *exporter) op(op Op) {
-	p.int(int(op))
+func (p *exporter) op(op ir.Op) {
 	if p.trace {
-		p.tracef("%s ", opnames[op])
+		p.tracef("%s ", Oconv(op, 0))
 	}
+	p.int(int(op))
 }
 
 // ----------------------------------------------------------------------------
@@ -1086,10 +1442,25 @@ func (p *exporter) string(s string) {
 	if p.trace {
 		p.tracef("%q ", s)
 	}
-	p.rawInt64(int64(len(s)))
-	for i := 0; i < len(s); i++ {
-		p.byte(s[i])
+	// if we saw the string before, write its index (>= 0)
+	// (the empty string is mapped to 0)
+	if i, ok := p.strIndex[s]; ok {
+		p.rawInt64(int64(i))
+		return
 	}
+	// otherwise, remember string and write its negative length and bytes
+	p.strIndex[s] = len(p.strIndex)
+	p.rawInt64(-int64(len(s)))
+	p.out.WriteString(s)
+}
+
+func (p *exporter) bool(b bool) bool {
+	x := 0
+	if b {
+		x = 1
+	}
+	p.int(x)
+	return b
 }
 
 // marker emits a marker byte and position information which makes
@@ -1097,40 +1468,60 @@ func (p *exporter) string(s string) {
 // debugFormat format only.
 func (p *exporter) marker(m byte) {
 	p.byte(m)
-	p.rawInt64(int64(p.written))
+	p.rawInt64(int64(p.out.Len()))
 }
 
 // rawInt64 should only be used by low-level encoders
 func (p *exporter) rawInt64(x int64) {
 	var tmp [binary.MaxVarintLen64]byte
 	n := binary.PutVarint(tmp[:], x)
-	for i := 0; i < n; i++ {
-		p.byte(tmp[i])
-	}
+	p.out.Write(tmp[:n])
 }
 
 // byte is the bottleneck interface to write to p.out.
-// byte escapes b as follows (any encoding does that
-// hides '$'):
+// The bytes are escaped once the export data is complete
+// (see escape).
+func (p *exporter) byte(b byte) {
+	p.out.WriteByte(b)
+}
+
+// escape appends data to dst, escaped as follows (any encoding
+// does that hides '$'):
 //
 //	'$'  => '|' 'S'
 //	'|'  => '|' '|'
 //
 // Necessary so other tools can find the end of the
 // export data by searching for "$$".
-func (p *exporter) byte(b byte) {
-	switch b {
-	case '$':
-		// write '$' as '|' 'S'
-		b = 'S'
-		fallthrough
-	case '|':
-		// write '|' as '|' '|'
-		obj.Bputc(p.out, '|')
-		p.written++
-	}
-	obj.Bputc(p.out, b)
-	p.written++
+func escape(dst, data []byte) []byte {
+	for _, b := range data {
+		switch b {
+		case '$':
+			dst = append(dst, '|', 'S')
+		case '|':
+			dst = append(dst, '|', '|')
+		default:
+			dst = append(dst, b)
+		}
+	}
+	return dst
+}
+
+// compress returns the compressed form of the export data data.
+func compress(data []byte) []byte {
+	var buf bytes.Buffer
+	w, err := flate.NewWriter(&buf, flate.BestSpeed)
+	if err != nil {
+		Fatalf("exporter: %v", err)
+	}
+	w.Write(data)
+	if err := w.Close(); err != nil {
+		Fatalf("exporter: compressing export data: %v", err)
+	}
+
+	out := make([]byte, 0, 1+buf.Len()+buf.Len()/64)
+	out = append(out, 'z')
+	return escape(out, buf.Bytes())
 }
 
 // tracef is like fmt.Printf but it rewrites the format string
@@ -1169,6 +1560,12 @@ const (
 	// Packages
 	packageTag = -(iota + 1)
 
+	// Declarations
+	constTag
+	typeTag
+	varTag
+	funcTag
+
 	// Types
 	namedTag
 	arrayTag
@@ -1199,6 +1596,12 @@ var tagString = [...]string{
 	// Packages:
 	-packageTag: "package",
 
+	// Declarations:
+	-constTag: "const",
+	-typeTag:  "type",
+	-varTag:   "var",
+	-funcTag:  "func",
+
 	// Types:
 	-namedTag:     "named type",
 	-arrayTag:     "array",
@@ -1226,77 +1629,77 @@ var tagString = [...]string{
 // untype returns the "pseudo" untyped type for a Ctype (import/export use only).
 // (we can't use an pre-initialized array because we must be sure all types are
 // set up)
-func untype(ctype Ctype) *Type {
+func untype(ctype ir.Ctype) *ir.Type {
 	switch ctype {
-	case CTINT:
-		return idealint
-	case CTRUNE:
-		return idealrune
-	case CTFLT:
-		return idealfloat
-	case CTCPLX:
-		return idealcomplex
-	case CTSTR:
-		return idealstring
-	case CTBOOL:
-		return idealbool
-	case CTNIL:
-		return Types[TNIL]
+	case ir.CTINT:
+		return ir.Idealint
+	case ir.CTRUNE:
+		return ir.Idealrune
+	case ir.CTFLT:
+		return ir.Idealfloat
+	case ir.CTCPLX:
+		return ir.Idealcomplex
+	case ir.CTSTR:
+		return ir.Idealstring
+	case ir.CTBOOL:
+		return ir.Idealbool
+	case ir.CTNIL:
+		return ir.Types[ir.TNIL]
 	}
 	Fatalf("exporter: unknown Ctype")
 	return nil
 }
 
-var predecl []*Type // initialized lazily
+var predecl []*ir.Type // initialized lazily
 
-func predeclared() []*Type {
+func predeclared() []*ir.Type {
 	if predecl == nil {
 		// initialize lazily to be sure that all
 		// elements have been initialized before
-		predecl = []*Type{
+		predecl = []*ir.Type{
 			// basic types
-			Types[TBOOL],
-			Types[TINT],
-			Types[TINT8],
-			Types[TINT16],
-			Types[TINT32],
-			Types[TINT64],
-			Types[TUINT],
-			Types[TUINT8],
-			Types[TUINT16],
-			Types[TUINT32],
-			Types[TUINT64],
-			Types[TUINTPTR],
-			Types[TFLOAT32],
-			Types[TFLOAT64],
-			Types[TCOMPLEX64],
-			Types[TCOMPLEX128],
-			Types[TSTRING],
+			ir.Types[ir.TBOOL],
+			ir.Types[ir.TINT],
+			ir.Types[ir.TINT8],
+			ir.Types[ir.TINT16],
+			ir.Types[ir.TINT32],
+			ir.Types[ir.TINT64],
+			ir.Types[ir.TUINT],
+			ir.Types[ir.TUINT8],
+			ir.Types[ir.TUINT16],
+			ir.Types[ir.TUINT32],
+			ir.Types[ir.TUINT64],
+			ir.Types[ir.TUINTPTR],
+			ir.Types[ir.TFLOAT32],
+			ir.Types[ir.TFLOAT64],
+			ir.Types[ir.TCOMPLEX64],
+			ir.Types[ir.TCOMPLEX128],
+			ir.Types[ir.TSTRING],
 
 			// aliases
-			bytetype,
-			runetype,
+			ir.Bytetype,
+			ir.Runetype,
 
 			// error
-			errortype,
+			ir.Errortype,
 
 			// untyped types
-			untype(CTBOOL),
-			untype(CTINT),
-			untype(CTRUNE),
-			untype(CTFLT),
-			untype(CTCPLX),
-			untype(CTSTR),
-			untype(CTNIL),
+			untype(ir.CTBOOL),
+			untype(ir.CTINT),
+			untype(ir.CTRUNE),
+			untype(ir.CTFLT),
+			untype(ir.CTCPLX),
+			untype(ir.CTSTR),
+			untype(ir.CTNIL),
 
 			// package unsafe
-			Types[TUNSAFEPTR],
+			ir.Types[ir.TUNSAFEPTR],
 
 			// invalid type (package contains errors)
-			Types[Txxx],
+			ir.Types[ir.Txxx],
 
 			// any type, for builtin export data
-			Types[TANY],
+			ir.Types[ir.TANY],
 		}
 	}
 	return predecl
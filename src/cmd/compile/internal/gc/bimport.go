@@ -9,9 +9,15 @@ package gc
 
 import (
 	"bufio"
+	"bytes"
 	"cmd/compile/internal/big"
+	"cmd/compile/internal/ir"
+	"cmd/internal/obj"
+	"compress/flate"
 	"encoding/binary"
 	"fmt"
+	"io"
+	"sort"
 )
 
 // The overall structure of Import is symmetric to Export: For each
@@ -19,13 +25,29 @@ import (
 // in bimport.go. Changing the export format requires making symmetric
 // changes to bimport.go and bexport.go.
 
-// Import populates importpkg from the serialized package data.
+// Import reads the export data of importpkg. It only records where
+// the declarations of the objects are, including those of the objects
+// of other packages that importpkg refers to; each of them is decoded
+// when the object is first referred to (see expandDecls).
 func Import(in *bufio.Reader) {
-	p := importer{in: in}
+	p := importer{
+		in:      unescaper{in},
+		self:    importpkg,
+		strList: []string{""}, // empty string is mapped to 0
+		strHash: []uint32{ir.StrhashString("")},
+	}
 	p.buf = p.bufarray[:]
 
 	// read low-level encoding format
-	switch format := p.byte(); format {
+	format := p.byte()
+	if format == 'z' {
+		// compressed - read the uncompressed export data,
+		// starting with its own encoding format
+		p.in = unescaper{bufio.NewReader(flate.NewReader(unescaper{in}))}
+		p.read = 0
+		format = p.byte()
+	}
+	switch format {
 	case 'c':
 		// compact format - nothing to do
 	case 'd':
@@ -34,10 +56,14 @@ func Import(in *bufio.Reader) {
 		Fatalf("importer: invalid encoding format in export data: got %q; want 'c' or 'd'", format)
 	}
 
-	// --- generic export data ---
+	// --- header ---
 
+	want := obj.Getgoversion() + ", export data " + exportVersion
 	if v := p.string(); v != exportVersion {
-		Fatalf("importer: unknown export data version: %s", v)
+		incompatibleimport(importpkg.Path, "export data "+v, want)
+	}
+	if v := p.string(); v != obj.Getgoversion() {
+		incompatibleimport(importpkg.Path, v+", export data "+exportVersion, want)
 	}
 
 	// populate typList with predeclared "known" types
@@ -52,105 +78,270 @@ func Import(in *bufio.Reader) {
 	// read compiler-specific flags
 	importpkg.Safe = p.string() == "safe"
 
+	// read data section
+	data := make([]byte, p.int())
+	for i := range data {
+		data[i] = p.byte()
+	}
+	if len(data) == 0 || data[0] != format {
+		Fatalf("importer: invalid data section in export data")
+	}
+
+	// read index
+	for i := p.int(); i > 0; i-- {
+		pkg := p.pkg()
+		for i := p.int(); i > 0; i-- {
+			sym := pkg.LookupHash(p.stringHash())
+			off := p.int()
+			if sym.Def != nil {
+				continue // declared by another import
+			}
+			if _, ok := declSrc[sym]; ok && pkg != importpkg {
+				continue // the export data of pkg takes precedence
+			}
+			declSrc[sym] = declRef{pkg: importpkg, data: data, off: off}
+		}
+	}
+
+	// --- end of export data ---
+
+	// the init function is called by fninit rather than referred to
+	expandDecls(importpkg.Lookup("init"))
+}
+
+// Declarations are decoded from the data section of the export data
+// that contains them when they are needed. Until then, the Def of the
+// object's Sym is nil, and declSrc records where its declaration is;
+// restrictlookup and importdot, which resolve the names of imported
+// objects, decode the declarations of the objects they resolve.
+var (
+	declSrc    = make(map[*ir.Sym]declRef)
+	declQueue  []*ir.Sym // declarations to decode, in order
+	declFixups []func()  // to run once the declarations in declQueue are decoded
+	expanding  bool      // expandDecls is decoding declarations
+)
+
+// A declRef locates the declaration of an object in export data.
+type declRef struct {
+	pkg  *ir.Pkg // package whose export data contains the declaration
+	data []byte  // data section of that export data
+	off  int     // offset of the declaration in data
+}
+
+// expandDecls decodes the declarations of syms, if they were not
+// decoded before, and those of the named types they refer to. The
+// declarations may refer to each other, so if expandDecls is called
+// while decoding, it only queues the declarations of syms.
+func expandDecls(syms ...*ir.Sym) {
+	for _, sym := range syms {
+		if _, ok := declSrc[sym]; ok {
+			declQueue = append(declQueue, sym)
+		}
+	}
+	if expanding || len(declQueue) == 0 {
+		return
+	}
+	expanding = true
+
+	// declare the objects at package scope, even if we are in the
+	// middle of a function, such as when decoding an inlined body
+	savepkg, savefn := importpkg, Curfn
+	savectxt, savedepth := dclcontext, Funcdepth
+	savestack, saveblock := dclstack, block
+	Curfn = nil
+	dclcontext, Funcdepth = ir.PEXTERN, 0
+	dclstack, block = nil, 1
+
 	// defer some type-checking until all types are read in completely
 	// (parser.go:import_package)
 	tcok := typecheckok
 	typecheckok = true
-	defercheckwidth()
+	deferwidth := defercalc == 0
+	if deferwidth {
+		defercheckwidth()
+	}
 
-	// read consts
-	for i := p.int(); i > 0; i-- {
-		sym := p.localname()
-		typ := p.typ()
-		val := p.value(typ)
-		importconst(sym, idealType(typ), nodlit(val))
+	// declQueue grows during iteration - cannot use range
+	for i := 0; i < len(declQueue); i++ {
+		sym := declQueue[i]
+		ref, ok := declSrc[sym]
+		if !ok {
+			continue // decoded before
+		}
+		delete(declSrc, sym)
+		importpkg = ref.pkg
+		chunk(ref.pkg, ref.data, ref.off).decl(sym)
 	}
+	declQueue = declQueue[:0]
 
-	// read vars
-	for i := p.int(); i > 0; i-- {
-		sym := p.localname()
-		typ := p.typ()
-		importvar(sym, typ)
+	for i := 0; i < len(declFixups); i++ {
+		declFixups[i]()
 	}
+	declFixups = declFixups[:0]
 
-	// read funcs
-	for i := p.int(); i > 0; i-- {
-		// parser.go:hidden_fndcl
-		sym := p.localname()
-		params := p.paramList()
-		result := p.paramList()
-		inl := p.int()
-
-		sig := functype(nil, params, result)
-		importsym(sym, ONAME)
-		if sym.Def != nil && sym.Def.Op == ONAME && !Eqtype(sig, sym.Def.Type) {
-			Fatalf("importer: inconsistent definition for func %v during import\n\t%v\n\t%v", sym, sym.Def.Type, sig)
-		}
+	typecheckok = tcok
+	if deferwidth {
+		resumecheckwidth()
+	}
 
-		n := newfuncname(sym)
-		n.Type = sig
-		declare(n, PFUNC)
-		funchdr(n)
+	importpkg, Curfn = savepkg, savefn
+	dclcontext, Funcdepth = savectxt, savedepth
+	dclstack, block = savestack, saveblock
+	expanding = false
+}
 
-		// parser.go:hidden_import
-		n.Func.Inl.Set(nil)
-		if inl >= 0 {
-			if inl != len(p.inlined) {
-				panic(fmt.Sprintf("inlined body list inconsistent: %d != %d", inl, len(p.inlined)))
-			}
-			p.inlined = append(p.inlined, n.Func)
+// expandPkg decodes the declarations of all the objects of pkg
+// not decoded yet.
+func expandPkg(pkg *ir.Pkg) {
+	var syms []*ir.Sym
+	for _, s := range pkg.Syms() {
+		if _, ok := declSrc[s]; ok {
+			syms = append(syms, s)
 		}
-		funcbody(n)
-		importlist = append(importlist, n) // TODO(gri) do this only if body is inlineable?
 	}
+	sort.Sort(symByName(syms)) // for a reproducible order
+	expandDecls(syms...)
+}
 
-	// read types
-	for i := p.int(); i > 0; i-- {
-		// name is parsed as part of named type
-		p.typ()
+// chunk returns an importer for the chunk at offset off
+// of the data section of the export data of pkg.
+func chunk(pkg *ir.Pkg, data []byte, off int) *importer {
+	p := &importer{
+		in:          bytes.NewReader(data[off:]),
+		self:        pkg,
+		data:        data,
+		strList:     []string{""}, // empty string is mapped to 0
+		strHash:     []uint32{ir.StrhashString("")},
+		debugFormat: data[0] == 'd',
 	}
+	p.buf = p.bufarray[:]
 
-	// --- compiler-specific export data ---
+	// populate typList with predeclared "known" types
+	p.typList = append(p.typList, predeclared()...)
 
-	// read inlined functions bodies
-	n := p.int()
-	for i := 0; i < n; i++ {
-		body := p.block()
-		const hookup = false // TODO(gri) enable and remove this condition
-		if hookup {
-			p.inlined[i].Inl.Set(body)
+	return p
+}
+
+// fixup schedules f to run once the declarations being
+// decoded are complete.
+func (p *importer) fixup(f func()) {
+	pkg := p.self
+	declFixups = append(declFixups, func() {
+		importpkg = pkg
+		f()
+	})
+}
+
+// decl reads the declaration of sym.
+func (p *importer) decl(sym *ir.Sym) {
+	switch tag := p.tagOrIndex(); tag {
+	case constTag:
+		typ := p.typ()
+		val := p.value(typ)
+		// typ may be a named type not declared yet; convert val later.
+		p.fixup(func() { importconst(sym, idealType(typ), nodlit(val)) })
+
+	case typeTag:
+		p.typeDecl(sym)
+
+	case varTag:
+		importvar(sym, p.typ())
+
+	case funcTag:
+		p.funcDecl(sym)
+
+	default:
+		Fatalf("importer: unexpected declaration of %v (tag = %d)", sym, tag)
+	}
+}
+
+// funcDecl reads and declares the function sym, unless it was
+// imported before from the export data of another package.
+func (p *importer) funcDecl(sym *ir.Sym) {
+	// parser.go:hidden_fndcl
+	var params, result []*ir.Field
+	p.walk(func() {
+		p.paramList(func(fs []*ir.Field) { params = fs })
+		p.paramList(func(fs []*ir.Field) { result = fs })
+	})
+	body := p.int()
+
+	sig := sharetype(functypefield(nil, params, result))
+	importsym(sym, ir.ONAME)
+	if sym.Def != nil && sym.Def.Op == ir.ONAME {
+		if !Eqtype(sig, sym.Def.Type) {
+			Fatalf("importer: inconsistent definition for func %v during import\n\t%v\n\t%v", sym, sym.Def.Type, sig)
 		}
+		return
 	}
 
-	// --- end of export data ---
+	n := newfuncname(sym)
+	n.Type = sig
+	declare(n, ir.PFUNC)
 
-	typecheckok = tcok
-	resumecheckwidth()
+	// parser.go:hidden_import
+	p.setInlsrc(n, body)
+	importlist = append(importlist, n) // TODO(gri) do this only if body is inlineable?
+}
 
-	testdclstack() // debugging only
+// typeDecl reads the underlying type and the methods
+// of the named type with the name tsym.
+func (p *importer) typeDecl(tsym *ir.Sym) {
+	// parser.go:hidden_pkgtype
+	t := pkgtype(tsym)
+	importsym(tsym, ir.OTYPE)
+
+	// read underlying type
+	// parser.go:hidden_type
+	t0 := p.typ()
+	if t.Etype == ir.TFORW {
+		importtype(t, t0) // parser.go:hidden_import
+	} else {
+		// t0 may refer to types not declared yet; compare it later.
+		p.fixup(func() { importtype(t, t0) })
+	}
+
+	// interfaces don't have associated methods
+	if t0.Etype != ir.TINTER {
+		p.associatedMethods(tsym)
+	}
 }
 
-func idealType(typ *Type) *Type {
+// setInlsrc records that the inlined body of the function n,
+// if any, is at offset off of the data section.
+func (p *importer) setInlsrc(n *ir.Node, off int) {
+	if off >= 0 {
+		n.Func.Inlsrc = &ir.Inlsrc{Pkg: p.self, Data: p.data, Off: off}
+	}
+}
+
+func idealType(typ *ir.Type) *ir.Type {
 	if isideal(typ) {
 		// canonicalize ideal types
-		typ = Types[TIDEAL]
+		typ = ir.Types[ir.TIDEAL]
 	}
 	return typ
 }
 
 type importer struct {
-	in       *bufio.Reader
+	in       io.ByteReader
+	self     *ir.Pkg // package whose export data is read
+	data     []byte  // data section, if reading a chunk of it
+	strList  []string
+	strHash  []uint32 // hashes of strList, for LookupHash
 	buf      []byte   // for reading strings
 	bufarray [64]byte // initial underlying array for buf, large enough to avoid allocation when compiling std lib
-	pkgList  []*Pkg
-	typList  []*Type
-	inlined  []*Func
+	pkgList  []*ir.Pkg
+	typList  []*ir.Type
+
+	work []func() // steps of the type walk to run, last first
+	next []func() // steps scheduled by the current step
 
 	debugFormat bool
 	read        int // bytes read
 }
 
-func (p *importer) pkg() *Pkg {
+func (p *importer) pkg() *ir.Pkg {
 	// if the package was seen before, i is its index (>= 0)
 	i := p.tagOrIndex()
 	if i >= 0 {
@@ -176,8 +367,8 @@ func (p *importer) pkg() *Pkg {
 		Fatalf("importer: bad path in import: %q", path)
 	}
 
-	// an empty path denotes the package we are currently importing
-	pkg := importpkg
+	// an empty path denotes the package whose export data we are reading
+	pkg := p.self
 	if path != "" {
 		pkg = mkpkg(path)
 	}
@@ -191,30 +382,69 @@ func (p *importer) pkg() *Pkg {
 	return pkg
 }
 
-func (p *importer) localname() *Sym {
-	// parser.go:hidden_importsym
-	name := p.string()
-	if name == "" {
-		Fatalf("importer: unexpected anonymous name")
-	}
-	return importpkg.Lookup(name)
+func (p *importer) newtyp(etype ir.EType) *ir.Type {
+	t := ir.Typ(etype)
+	p.typList = append(p.typList, t)
+	return t
 }
 
-func (p *importer) newtyp(etype EType) *Type {
-	t := typ(etype)
-	p.typList = append(p.typList, t)
+// The type decoders below mirror the type encoders in bexport.go:
+// they read the parts of a type that come first and schedule the
+// remaining ones with p.later, so that deeply nested types are read
+// from an explicit work list rather than by recursion. A decoder
+// passes what it read to its set function once it is complete.
+
+// typ reads a type.
+func (p *importer) typ() *ir.Type {
+	var t *ir.Type
+	p.walk(func() { p.typ1(func(t1 *ir.Type) { t = t1 }) })
 	return t
 }
 
-func (p *importer) typ() *Type {
+// walk calls f and then runs the steps scheduled by f and,
+// transitively, by those steps, each step's scheduled steps
+// before any steps scheduled earlier.
+func (p *importer) walk(f func()) {
+	saved := p.next
+	p.next = nil
+	base := len(p.work)
+	p.work = append(p.work, f)
+	for len(p.work) > base {
+		f := p.work[len(p.work)-1]
+		p.work = p.work[:len(p.work)-1]
+		f()
+		// push the steps scheduled by f in reverse order
+		// so that they are run next, in the order scheduled
+		for i := len(p.next) - 1; i >= 0; i-- {
+			p.work = append(p.work, p.next[i])
+		}
+		p.next = p.next[:0]
+	}
+	p.next = saved
+}
+
+// later schedules f to run after the current step of p.walk
+// and after the steps it scheduled before.
+func (p *importer) later(f func()) {
+	p.next = append(p.next, f)
+}
+
+// laterTyp schedules a type to be read.
+func (p *importer) laterTyp(set func(*ir.Type)) {
+	p.later(func() { p.typ1(set) })
+}
+
+func (p *importer) typ1(set func(*ir.Type)) {
 	// if the type was seen before, i is its index (>= 0)
 	i := p.tagOrIndex()
 	if i >= 0 {
-		return p.typList[i]
+		set(p.typList[i])
+		return
 	}
 
 	// otherwise, i is the type tag (< 0)
-	var t *Type
+	var t *ir.Type
+	n := len(p.typList) // index of an unnamed t; see newtyp
 	switch i {
 	case namedTag:
 		// parser.go:hidden_importsym
@@ -222,102 +452,55 @@ func (p *importer) typ() *Type {
 
 		// parser.go:hidden_pkgtype
 		t = pkgtype(tsym)
-		importsym(tsym, OTYPE)
 		p.typList = append(p.typList, t)
 
-		// read underlying type
-		// parser.go:hidden_type
-		t0 := p.typ()
-		importtype(t, t0) // parser.go:hidden_import
-
-		// interfaces don't have associated methods
-		if t0.Etype == TINTER {
-			break
-		}
-
-		// read associated methods
-		for i := p.int(); i > 0; i-- {
-			// parser.go:hidden_fndcl
-			name := p.string()
-			recv := p.paramList() // TODO(gri) do we need a full param list for the receiver?
-			params := p.paramList()
-			result := p.paramList()
-			inl := p.int()
-
-			pkg := localpkg
-			if !exportname(name) {
-				pkg = tsym.Pkg
-			}
-			sym := pkg.Lookup(name)
-
-			n := methodname1(newname(sym), recv[0].Right)
-			n.Type = functype(recv[0], params, result)
-			checkwidth(n.Type)
-			addmethod(sym, n.Type, tsym.Pkg, false, false)
-			funchdr(n)
-
-			// (comment from parser.go)
-			// inl.C's inlnode in on a dotmeth node expects to find the inlineable body as
-			// (dotmeth's type).Nname.Inl, and dotmeth's type has been pulled
-			// out by typecheck's lookdot as this $$.ttype. So by providing
-			// this back link here we avoid special casing there.
-			n.Type.Nname = n
-
-			// parser.go:hidden_import
-			n.Func.Inl.Set(nil)
-			if inl >= 0 {
-				if inl != len(p.inlined) {
-					panic(fmt.Sprintf("inlined body list inconsistent: %d != %d", inl, len(p.inlined)))
-				}
-				p.inlined = append(p.inlined, n.Func)
-			}
-			funcbody(n)
-			importlist = append(importlist, n) // TODO(gri) do this only if body is inlineable?
-		}
+		// the underlying type and methods are part of the declaration
+		expandDecls(tsym)
 
 	case arrayTag, sliceTag:
-		t = p.newtyp(TARRAY)
+		t = p.newtyp(ir.TARRAY)
 		t.Bound = -1
 		if i == arrayTag {
 			t.Bound = p.int64()
 		}
-		t.Type = p.typ()
+		p.laterTyp(func(elem *ir.Type) { t.Type = elem })
 
 	case dddTag:
-		t = p.newtyp(T_old_DARRAY)
+		t = p.newtyp(ir.T_old_DARRAY)
 		t.Bound = -1
-		t.Type = p.typ()
+		p.laterTyp(func(elem *ir.Type) { t.Type = elem })
 
 	case structTag:
-		t = p.newtyp(TSTRUCT)
-		tostruct0(t, p.fieldList())
+		t = p.newtyp(ir.TSTRUCT)
+		p.fieldList(func(fields []*ir.Node) { tostruct0(t, fields) })
 
 	case pointerTag:
 		t = p.newtyp(Tptr)
-		t.Type = p.typ()
+		p.laterTyp(func(elem *ir.Type) { t.Type = elem })
 
 	case signatureTag:
-		t = p.newtyp(TFUNC)
-		params := p.paramList()
-		result := p.paramList()
-		functype0(t, nil, params, result)
+		t = p.newtyp(ir.TFUNC)
+		var params, result []*ir.Field
+		p.paramList(func(fs []*ir.Field) { params = fs })
+		p.paramList(func(fs []*ir.Field) { result = fs })
+		p.later(func() { functypefield0(t, nil, params, result) })
 
 	case interfaceTag:
-		t = p.newtyp(TINTER)
+		t = p.newtyp(ir.TINTER)
 		if p.int() != 0 {
 			Fatalf("importer: unexpected embedded interface")
 		}
-		tointerface0(t, p.methodList())
+		p.methodList(func(methods []*ir.Node) { tointerface0(t, methods) })
 
 	case mapTag:
-		t = p.newtyp(TMAP)
-		t.Down = p.typ() // key
-		t.Type = p.typ() // val
+		t = p.newtyp(ir.TMAP)
+		p.laterTyp(func(key *ir.Type) { t.Down = key })
+		p.laterTyp(func(val *ir.Type) { t.Type = val })
 
 	case chanTag:
-		t = p.newtyp(TCHAN)
+		t = p.newtyp(ir.TCHAN)
 		t.Chan = uint8(p.int())
-		t.Type = p.typ()
+		p.laterTyp(func(elem *ir.Type) { t.Type = elem })
 
 	default:
 		Fatalf("importer: unexpected type (tag = %d)", i)
@@ -327,56 +510,111 @@ func (p *importer) typ() *Type {
 		Fatalf("importer: nil type (type tag = %d)", i)
 	}
 
-	return t
+	p.later(func() {
+		if s := sharetype(t); s != t {
+			p.typList[n] = s
+			t = s
+		}
+		set(t)
+	})
 }
 
-func (p *importer) qualifiedName() *Sym {
-	name := p.string()
+// associatedMethods reads the methods of the named type
+// with the name tsym.
+func (p *importer) associatedMethods(tsym *ir.Sym) {
+	for i := p.int(); i > 0; i-- {
+		// parser.go:hidden_fndcl
+		name, h := p.stringHash()
+		var recv, params, result []*ir.Field
+		p.walk(func() {
+			p.paramList(func(fs []*ir.Field) { recv = fs }) // TODO(gri) do we need a full param list for the receiver?
+			p.paramList(func(fs []*ir.Field) { params = fs })
+			p.paramList(func(fs []*ir.Field) { result = fs })
+		})
+		body := p.int()
+
+		pkg := localpkg
+		if !exportname(name) {
+			pkg = tsym.Pkg
+		}
+		sym := pkg.LookupHash(name, h)
+
+		n := methodname1(newname(sym), typenod(recv[0].Type))
+		n.Type = functypefield(recv[0], params, result)
+		p.fixup(func() {
+			checkwidth(n.Type)
+			addmethod(sym, n.Type, tsym.Pkg, false, false)
+		})
+
+		// (comment from parser.go)
+		// inl.C's inlnode in on a dotmeth node expects to find the inlineable body as
+		// (dotmeth's type).Nname.Inl, and dotmeth's type has been pulled
+		// out by typecheck's lookdot as this $$.ttype. So by providing
+		// this back link here we avoid special casing there.
+		n.Type.Nname = n
+
+		// parser.go:hidden_import
+		p.setInlsrc(n, body)
+		importlist = append(importlist, n) // TODO(gri) do this only if body is inlineable?
+	}
+}
+
+func (p *importer) qualifiedName() *ir.Sym {
+	name, h := p.stringHash()
 	pkg := p.pkg()
-	return pkg.Lookup(name)
+	return pkg.LookupHash(name, h)
 }
 
 // parser.go:hidden_structdcl_list
-func (p *importer) fieldList() []*Node {
-	i := p.int()
-	if i == 0 {
-		return nil
-	}
-	n := make([]*Node, i)
-	for i := range n {
-		n[i] = p.field()
-	}
-	return n
+func (p *importer) fieldList(set func([]*ir.Node)) {
+	p.later(func() {
+		i := p.int()
+		if i == 0 {
+			set(nil)
+			return
+		}
+		n := make([]*ir.Node, i)
+		for i := range n {
+			i := i
+			p.field(func(f *ir.Node) { n[i] = f })
+		}
+		p.later(func() { set(n) })
+	})
 }
 
 // parser.go:hidden_structdcl
-func (p *importer) field() *Node {
-	sym := p.fieldName()
-	typ := p.typ()
-	note := p.note()
-
-	var n *Node
-	if sym.Name != "" {
-		n = Nod(ODCLFIELD, newname(sym), typenod(typ))
-	} else {
-		// anonymous field - typ must be T or *T and T must be a type name
-		s := typ.Sym
-		if s == nil && Isptr[typ.Etype] {
-			s = typ.Type.Sym // deref
-		}
-		pkg := importpkg
-		if sym != nil {
-			pkg = sym.Pkg
-		}
-		n = embedded(s, pkg)
-		n.Right = typenod(typ)
-	}
-	n.SetVal(note)
+func (p *importer) field(set func(*ir.Node)) {
+	p.later(func() {
+		sym := p.fieldName()
+		var typ *ir.Type
+		p.laterTyp(func(t *ir.Type) { typ = t })
+		p.later(func() {
+			note := p.note()
+
+			var n *ir.Node
+			if sym.Name != "" {
+				n = Nod(ir.ODCLFIELD, newname(sym), typenod(typ))
+			} else {
+				// anonymous field - typ must be T or *T and T must be a type name
+				s := typ.Sym
+				if s == nil && Isptr[typ.Etype] {
+					s = typ.Type.Sym // deref
+				}
+				pkg := p.self
+				if sym != nil {
+					pkg = sym.Pkg
+				}
+				n = embedded(s, pkg)
+				n.Right = typenod(typ)
+			}
+			n.SetVal(note)
 
-	return n
+			set(n)
+		})
+	})
 }
 
-func (p *importer) note() (v Val) {
+func (p *importer) note() (v ir.Val) {
 	if s := p.string(); s != "" {
 		v.U = s
 	}
@@ -384,29 +622,38 @@ func (p *importer) note() (v Val) {
 }
 
 // parser.go:hidden_interfacedcl_list
-func (p *importer) methodList() []*Node {
-	i := p.int()
-	if i == 0 {
-		return nil
-	}
-	n := make([]*Node, i)
-	for i := range n {
-		n[i] = p.method()
-	}
-	return n
+func (p *importer) methodList(set func([]*ir.Node)) {
+	p.later(func() {
+		i := p.int()
+		if i == 0 {
+			set(nil)
+			return
+		}
+		n := make([]*ir.Node, i)
+		for i := range n {
+			i := i
+			p.method(func(m *ir.Node) { n[i] = m })
+		}
+		p.later(func() { set(n) })
+	})
 }
 
 // parser.go:hidden_interfacedcl
-func (p *importer) method() *Node {
-	sym := p.fieldName()
-	params := p.paramList()
-	result := p.paramList()
-	return Nod(ODCLFIELD, newname(sym), typenod(functype(fakethis(), params, result)))
+func (p *importer) method(set func(*ir.Node)) {
+	p.later(func() {
+		sym := p.fieldName()
+		var params, result []*ir.Field
+		p.paramList(func(fs []*ir.Field) { params = fs })
+		p.paramList(func(fs []*ir.Field) { result = fs })
+		p.later(func() {
+			set(Nod(ir.ODCLFIELD, newname(sym), typenod(functypefield(fakethisfield(), params, result))))
+		})
+	})
 }
 
 // parser.go:sym,hidden_importsym
-func (p *importer) fieldName() *Sym {
-	name := p.string()
+func (p *importer) fieldName() *ir.Sym {
+	name, h := p.stringHash()
 	pkg := localpkg
 	if name == "_" {
 		// During imports, unqualified non-exported identifiers are from builtinpkg
@@ -415,66 +662,76 @@ func (p *importer) fieldName() *Sym {
 		pkg = builtinpkg
 	} else if name == "?" || name != "" && !exportname(name) {
 		if name == "?" {
-			name = ""
+			name, h = "", p.strHash[0]
 		}
 		pkg = p.pkg()
 	}
-	return pkg.Lookup(name)
+	return pkg.LookupHash(name, h)
 }
 
 // parser.go:ohidden_funarg_list
-func (p *importer) paramList() []*Node {
-	i := p.int()
-	if i == 0 {
-		return nil
-	}
-	// negative length indicates unnamed parameters
-	named := true
-	if i < 0 {
-		i = -i
-		named = false
-	}
-	// i > 0
-	n := make([]*Node, i)
-	for i := range n {
-		n[i] = p.param(named)
-	}
-	return n
+//
+// The parameters are fields without Nnames: the function they belong
+// to is only declared, and loadinl declares the parameters with the
+// inlined body, if the body is used.
+func (p *importer) paramList(set func([]*ir.Field)) {
+	p.later(func() {
+		i := p.int()
+		if i == 0 {
+			set(nil)
+			return
+		}
+		// negative length indicates unnamed parameters
+		named := true
+		if i < 0 {
+			i = -i
+			named = false
+		}
+		// i > 0
+		fs := make([]*ir.Field, i)
+		for i := range fs {
+			fs[i] = p.param(named)
+		}
+		p.later(func() { set(fs) })
+	})
 }
 
 // parser.go:hidden_funarg
-func (p *importer) param(named bool) *Node {
-	typ := p.typ()
-
-	isddd := false
-	if typ.Etype == T_old_DARRAY {
-		// T_old_DARRAY indicates ... type
-		// TODO(mdempsky): Fix Type rekinding.
-		typ.Etype = TARRAY
-		isddd = true
-	}
-
-	n := Nod(ODCLFIELD, nil, typenod(typ))
-	n.Isddd = isddd
-
-	if named {
-		name := p.string()
-		if name == "" {
-			Fatalf("importer: expected named parameter")
+//
+// param returns the parameter, which is complete once
+// the steps it schedules have run.
+func (p *importer) param(named bool) *ir.Field {
+	f := ir.NewField()
+	p.laterTyp(func(typ *ir.Type) {
+		if typ.Etype == ir.T_old_DARRAY {
+			// T_old_DARRAY indicates ... type
+			// TODO(mdempsky): Fix Type rekinding.
+			typ.Etype = ir.TARRAY
+			f.Isddd = true
+		}
+		f.Type = typ
+	})
+	p.later(func() {
+		if named {
+			name, h := p.stringHash()
+			if name == "" {
+				Fatalf("importer: expected named parameter")
+			}
+			// The parameter package doesn't matter; it's never consulted.
+			// We use the builtinpkg per parser.go:sym (line 1181).
+			f.Sym = builtinpkg.LookupHash(name, h)
 		}
-		// The parameter package doesn't matter; it's never consulted.
-		// We use the builtinpkg per parser.go:sym (line 1181).
-		n.Left = newname(builtinpkg.Lookup(name))
-	}
-
-	// TODO(gri) This is compiler-specific (escape info).
-	// Move into compiler-specific section eventually?
-	n.SetVal(p.note())
 
-	return n
+		// TODO(gri) This is compiler-specific (escape info).
+		// Move into compiler-specific section eventually?
+		if s := p.string(); s != "" {
+			f.Note = &s
+		}
+	})
+	return f
 }
 
-func (p *importer) value(typ *Type) (x Val) {
+func (p *importer) value(typ *ir.Type) (x ir.Val) {
 	switch tag := p.tagOrIndex(); tag {
 	case falseTag:
 		x.U = false
@@ -483,17 +740,17 @@ func (p *importer) value(typ *Type) (x Val) {
 		x.U = true
 
 	case int64Tag:
-		u := new(Mpint)
+		u := new(ir.Mpint)
 		u.SetInt64(p.int64())
-		u.Rune = typ == idealrune
+		u.Rune = typ == ir.Idealrune
 		x.U = u
 
 	case floatTag:
-		f := newMpflt()
+		f := ir.NewMpflt()
 		p.float(f)
-		if typ == idealint || Isint[typ.Etype] {
+		if typ == ir.Idealint || Isint[typ.Etype] {
 			// uncommon case: large int encoded as float
-			u := new(Mpint)
+			u := new(ir.Mpint)
 			u.SetFloat(f)
 			x.U = u
 			break
@@ -501,7 +758,7 @@ func (p *importer) value(typ *Type) (x Val) {
 		x.U = f
 
 	case complexTag:
-		u := new(Mpcplx)
+		u := new(ir.Mpcplx)
 		p.float(&u.Real)
 		p.float(&u.Imag)
 		x.U = u
@@ -513,7 +770,7 @@ func (p *importer) value(typ *Type) (x Val) {
 		Fatalf("importer: unknown constant (importing package with errors)")
 
 	case nilTag:
-		x.U = new(NilVal)
+		x.U = new(ir.NilVal)
 
 	default:
 		Fatalf("importer: unexpected value tag %d", tag)
@@ -527,7 +784,7 @@ func (p *importer) value(typ *Type) (x Val) {
 	return
 }
 
-func (p *importer) float(x *Mpflt) {
+func (p *importer) float(x *ir.Mpflt) {
 	sign := p.int()
 	if sign == 0 {
 		x.SetFloat64(0)
@@ -547,244 +804,339 @@ func (p *importer) float(x *Mpflt) {
 // ----------------------------------------------------------------------------
 // Inlined function bodies
 
-func (p *importer) block() []*Node {
-	markdcl()
-	// TODO(gri) populate "scope" with function parameters so they can be found
-	//           inside the function body
-	list := p.nodeList()
-	popdcl()
-	return list
-}
-
-// parser.go:stmt_list
-func (p *importer) nodeList() []*Node {
-	c := p.int()
-	s := make([]*Node, c)
-	for i := range s {
-		s[i] = p.node()
+// loadinl decodes the imported inlined body of fn into fn.Func.Inl,
+// unless it was decoded before, and declares the receiver, parameters,
+// and results of fn with the names the body refers to them by.
+func loadinl(fn *ir.Node) {
+	src := fn.Func.Inlsrc
+	if src == nil {
+		return
 	}
-	return s
-}
+	fn.Func.Inlsrc = nil
 
-func (p *importer) node() *Node {
-	// TODO(gri) eventually we may need to allocate in each branch
-	n := Nod(p.op(), nil, nil)
+	savepkg, savefn := importpkg, Curfn
+	savectxt, savedepth := dclcontext, Funcdepth
+	importpkg = src.Pkg
 
-	switch n.Op {
-	// names
-	case ONAME, OPACK, ONONAME:
-		name := mkname(p.sym())
-		// TODO(gri) decide what to do here (this code throws away n)
-		/*
-			if name.Op != n.Op {
-				Fatalf("importer: got node op = %s; want %s", opnames[name.Op], opnames[n.Op])
+	p := chunk(src.Pkg, src.Data, src.Off)
+	if fn.Type.Shared {
+		// The parameters are fn's own; see sharetype.
+		fn.Type = unsharefunctype(fn.Type)
+	}
+	for _, f := range ir.RecvsParamsResults {
+		for _, q := range f(fn.Type).Fields().Slice() {
+			q.Nname = nil
+			if sym := p.name(); sym != nil {
+				q.Nname = newname(sym)
 			}
-		*/
-		n = name
-
-	case OTYPE:
-		if p.bool() {
-			n.Sym = p.sym()
-		} else {
-			n.Type = p.typ()
-		}
-
-	case OLITERAL:
-		typ := p.typ()
-		n.Type = idealType(typ)
-		n.SetVal(p.value(typ))
-
-	// expressions
-	case OMAKEMAP, OMAKECHAN, OMAKESLICE:
-		if p.bool() {
-			n.List.Set(p.nodeList())
-		}
-		n.Left, n.Right = p.nodesOrNil()
-		n.Type = p.typ()
-
-	case OPLUS, OMINUS, OADDR, OCOM, OIND, ONOT, ORECV:
-		n.Left = p.node()
-
-	case OADD, OAND, OANDAND, OANDNOT, ODIV, OEQ, OGE, OGT, OLE, OLT,
-		OLSH, OMOD, OMUL, ONE, OOR, OOROR, ORSH, OSEND,
-		OSUB, OXOR:
-		n.Left = p.node()
-		n.Right = p.node()
-
-	case OADDSTR:
-		n.List.Set(p.nodeList())
-
-	case OPTRLIT:
-		n.Left = p.node()
-
-	case OSTRUCTLIT:
-		n.Type = p.typ()
-		n.List.Set(p.nodeList())
-		n.Implicit = p.bool()
-
-	case OARRAYLIT, OMAPLIT:
-		n.Type = p.typ()
-		n.List.Set(p.nodeList())
-		n.Implicit = p.bool()
-
-	case OKEY:
-		n.Left, n.Right = p.nodesOrNil()
-
-	case OCOPY, OCOMPLEX:
-		n.Left = p.node()
-		n.Right = p.node()
-
-	case OCONV, OCONVIFACE, OCONVNOP, OARRAYBYTESTR, OARRAYRUNESTR, OSTRARRAYBYTE, OSTRARRAYRUNE, ORUNESTR:
-		// n.Type = p.typ()
-		// if p.bool() {
-		// 	n.Left = p.node()
-		// } else {
-		// 	n.List.Set(p.nodeList())
-		// }
-		x := Nod(OCALL, p.typ().Nod, nil)
-		if p.bool() {
-			x.List.Set1(p.node())
-		} else {
-			x.List.Set(p.nodeList())
-		}
-		return x
-
-	case ODOT, ODOTPTR, ODOTMETH, ODOTINTER, OXDOT:
-		// see parser.new_dotname
-		obj := p.node()
-		sel := p.sym()
-		if obj.Op == OPACK {
-			s := restrictlookup(sel.Name, obj.Name.Pkg)
-			obj.Used = true
-			return oldname(s)
-		}
-		return NodSym(OXDOT, obj, sel)
-
-	case ODOTTYPE, ODOTTYPE2:
-		n.Left = p.node()
-		if p.bool() {
-			n.Right = p.node()
-		} else {
-			n.Type = p.typ()
 		}
+	}
 
-	case OINDEX, OINDEXMAP, OSLICE, OSLICESTR, OSLICEARR, OSLICE3, OSLICE3ARR:
-		n.Left = p.node()
-		n.Right = p.node()
+	// funchdr, without the changes to fn
+	dclcontext = ir.PAUTO
+	markdcl()
+	Funcdepth = 1
+	Curfn = fn
+	fn.Func.Dcl = nil
+	funcargs2(fn.Type)
 
-	case OREAL, OIMAG, OAPPEND, OCAP, OCLOSE, ODELETE, OLEN, OMAKE, ONEW, OPANIC,
-		ORECOVER, OPRINT, OPRINTN:
-		n.Left, _ = p.nodesOrNil()
-		n.List.Set(p.nodeList())
-		n.Isddd = p.bool()
+	body := p.stmtList()
+	popdcl()
+	fn.Func.Inl.Set(body)
 
-	case OCALL, OCALLFUNC, OCALLMETH, OCALLINTER, OGETG:
-		n.Left = p.node()
-		n.List.Set(p.nodeList())
-		n.Isddd = p.bool()
+	importpkg, Curfn = savepkg, savefn
+	dclcontext, Funcdepth = savectxt, savedepth
+}
 
-	case OCMPSTR, OCMPIFACE:
-		n.Left = p.node()
-		n.Right = p.node()
-		n.Etype = EType(p.int())
+// The body decoders below build the nodes the parser would have built
+// for the source of the body, in the declaration scopes the parser
+// would have used, so that the body can be typechecked like a parsed
+// one.
+
+// name reads the name of a local or package-level object,
+// or returns nil for an unnamed result.
+func (p *importer) name() *ir.Sym {
+	name, h := p.stringHash()
+	switch name {
+	case "":
+		return nil
+	case "_":
+		return builtinpkg.LookupHash(name, h)
+	}
+	return p.pkg().LookupHash(name, h)
+}
 
-	case OPAREN:
-		n.Left = p.node()
+func (p *importer) stmtList() []*ir.Node {
+	var list []*ir.Node
+	for {
+		op := p.op()
+		switch op {
+		case ir.OEND:
+			return list
+		case ir.ODCL:
+			// parser.go:vardcl
+			lhs := dclname(p.name())
+			typ := typenod(p.typ())
+			list = append(list, variter([]*ir.Node{lhs}, typ, nil)...)
+		default:
+			list = append(list, p.node(op))
+		}
+	}
+}
 
-	// statements
-	case ODCL:
-		n.Left = p.node() // TODO(gri) compare with fmt code
-		n.Left.Type = p.typ()
+// block reads a statement list in a scope of its own.
+func (p *importer) block() []*ir.Node {
+	markdcl()
+	list := p.stmtList()
+	popdcl()
+	return list
+}
 
-	case OAS:
-		n.Left, n.Right = p.nodesOrNil()
-		n.Colas = p.bool() // TODO(gri) what about complexinit?
+func (p *importer) exprList() []*ir.Node {
+	var list []*ir.Node
+	for {
+		n := p.expr()
+		if n == nil {
+			return list
+		}
+		list = append(list, n)
+	}
+}
 
-	case OASOP:
-		n.Left = p.node()
-		n.Right = p.node()
-		n.Etype = EType(p.int())
+// exprsOrNil reads two expressions, either of which may be nil.
+func (p *importer) exprsOrNil() (a, b *ir.Node) {
+	ab := p.int()
+	if ab&1 != 0 {
+		a = p.expr()
+	}
+	if ab&2 != 0 {
+		b = p.expr()
+	}
+	return
+}
 
-	case OAS2, OASWB:
-		n.List.Set(p.nodeList())
-		n.Rlist.Set(p.nodeList())
+// expr reads an expression, or returns nil at the end of a list.
+func (p *importer) expr() *ir.Node {
+	op := p.op()
+	if op == ir.OEND {
+		return nil
+	}
+	return p.node(op)
+}
 
-	case OAS2DOTTYPE, OAS2FUNC, OAS2MAPR, OAS2RECV:
-		n.List.Set(p.nodeList())
-		n.Rlist.Set(p.nodeList())
+// node reads the statement or expression with the operator op.
+func (p *importer) node(op ir.Op) *ir.Node {
+	switch op {
+	// names and types
 
-	case ORETURN:
-		n.List.Set(p.nodeList())
+	case ir.ONAME:
+		sym := p.name()
+		expandDecls(sym)
+		return mkname(sym)
 
-	case OPROC, ODEFER:
-		n.Left = p.node()
+	case ir.OTYPE:
+		return typenod(p.typ())
 
-	case OIF:
-		n.Ninit.Set(p.nodeList())
-		n.Left = p.node()
-		n.Nbody.Set(p.nodeList())
-		n.Rlist.Set(p.nodeList())
+	case ir.OLITERAL:
+		typ := p.typ()
+		n := nodlit(p.value(typ))
+		if !isideal(typ) {
+			// typed constant, as typecheck left it; T(nil)
+			// could not be compared to nil
+			n.Type = typ
+		}
+		return n
 
-	case OFOR:
-		n.Ninit.Set(p.nodeList())
-		n.Left, n.Right = p.nodesOrNil()
-		n.Nbody.Set(p.nodeList())
+	// composite literals
 
-	case ORANGE:
-		if p.bool() {
-			n.List.Set(p.nodeList())
+	case ir.OPTRLIT:
+		n := p.expr()
+		if n.Op == ir.OCOMPLIT {
+			// parser.go:uexpr
+			n.Right = Nod(ir.OIND, n.Right, nil)
+			n.Right.Implicit = true
+			return n
+		}
+		return Nod(ir.OADDR, n, nil)
+
+	case ir.OSTRUCTLIT:
+		n := Nod(ir.OCOMPLIT, nil, typenod(p.typ()))
+		list := make([]*ir.Node, p.int())
+		for i := range list {
+			sym := p.fieldName()
+			list[i] = Nod(ir.OKEY, newname(sym), p.expr())
+		}
+		n.List.Set(list)
+		return n
+
+	case ir.OCOMPLIT:
+		n := Nod(ir.OCOMPLIT, nil, typenod(p.typ()))
+		n.List.Set(p.exprList())
+		return n
+
+	case ir.OKEY:
+		left, right := p.exprsOrNil()
+		return Nod(ir.OKEY, left, right)
+
+	// selectors, indexing, and slicing
+
+	case ir.OXDOT:
+		x := p.expr()
+		return NodSym(ir.OXDOT, x, p.fieldName())
+
+	case ir.ODOTTYPE:
+		x := p.expr()
+		return Nod(ir.ODOTTYPE, x, typenod(p.typ()))
+
+	case ir.OINDEX:
+		x := p.expr()
+		return Nod(ir.OINDEX, x, p.expr())
+
+	case ir.OSLICE:
+		x := p.expr()
+		low, high := p.exprsOrNil()
+		return Nod(ir.OSLICE, x, Nod(ir.OKEY, low, high))
+
+	case ir.OSLICE3:
+		x := p.expr()
+		low, high := p.exprsOrNil()
+		max := p.expr()
+		return Nod(ir.OSLICE3, x, Nod(ir.OKEY, low, Nod(ir.OKEY, high, max)))
+
+	// conversions and calls
+
+	case ir.OCONV:
+		n := Nod(ir.OCALL, typenod(p.typ()), nil)
+		n.List.Set(p.exprList())
+		return n
+
+	case ir.OCOPY, ir.OCOMPLEX, ir.OREAL, ir.OIMAG, ir.OAPPEND, ir.OCAP, ir.OCLOSE, ir.ODELETE, ir.OLEN, ir.ONEW, ir.OPANIC, ir.ORECOVER, ir.OPRINT, ir.OPRINTN:
+		n := builtinCall(op)
+		n.List.Set(p.exprList())
+		if op == ir.OAPPEND {
+			n.Isddd = p.bool()
 		}
-		n.Right = p.node()
-		n.Nbody.Set(p.nodeList())
+		return n
 
-	case OSELECT, OSWITCH:
-		n.Ninit.Set(p.nodeList())
-		n.Left, _ = p.nodesOrNil()
-		n.List.Set(p.nodeList())
+	case ir.OMAKE:
+		n := builtinCall(ir.OMAKE)
+		n.List.Append(typenod(p.typ()))
+		n.List.Append(p.exprList()...)
+		return n
 
-	case OCASE, OXCASE:
-		if p.bool() {
-			n.List.Set(p.nodeList())
-		}
-		n.Nbody.Set(p.nodeList())
+	case ir.OCALL:
+		n := Nod(ir.OCALL, p.expr(), nil)
+		n.List.Set(p.exprList())
+		n.Isddd = p.bool()
+		return n
 
-	case OBREAK, OCONTINUE, OGOTO, OFALL, OXFALL:
-		n.Left, _ = p.nodesOrNil()
+	// operators
 
-	case OEMPTY, ODCLCONST:
-		// nothing to do
+	case ir.OPLUS, ir.OMINUS, ir.OADDR, ir.OCOM, ir.OIND, ir.ONOT, ir.ORECV:
+		return Nod(op, p.expr(), nil)
 
-	case OLABEL:
-		n.Left = p.node()
+	case ir.OADD, ir.OAND, ir.OANDAND, ir.OANDNOT, ir.ODIV, ir.OEQ, ir.OGE, ir.OGT, ir.OLE, ir.OLT,
+		ir.OLSH, ir.OMOD, ir.OMUL, ir.ONE, ir.OOR, ir.OOROR, ir.ORSH, ir.OSEND, ir.OSUB, ir.OXOR:
+		x := p.expr()
+		return Nod(op, x, p.expr())
 
-	default:
-		panic(fmt.Sprintf("importer: %s (%d) node not yet supported", opnames[n.Op], n.Op))
-	}
+	case ir.OADDSTR:
+		list := p.exprList()
+		x := list[0]
+		for _, y := range list[1:] {
+			x = Nod(ir.OADD, x, y)
+		}
+		return x
 
-	return n
-}
+	// statements
 
-func (p *importer) nodesOrNil() (a, b *Node) {
-	ab := p.int()
-	if ab&1 != 0 {
-		a = p.node()
-	}
-	if ab&2 != 0 {
-		b = p.node()
+	case ir.OAS:
+		x := p.expr()
+		return Nod(ir.OAS, x, p.expr())
+
+	case ir.OASOP:
+		n := Nod(ir.OASOP, nil, nil)
+		n.Etype = ir.EType(p.int())
+		n.Left = p.expr()
+		if !p.bool() {
+			// x++ or x--
+			n.Right = Nodintconst(1)
+			n.Implicit = true
+		} else {
+			n.Right = p.expr()
+		}
+		return n
+
+	case ir.OAS2:
+		n := Nod(ir.OAS2, nil, nil)
+		n.List.Set(p.exprList())
+		n.Rlist.Set(p.exprList())
+		return n
+
+	case ir.ORETURN:
+		n := Nod(ir.ORETURN, nil, nil)
+		n.List.Set(p.exprList())
+		return n
+
+	case ir.OIF:
+		// parser.go:if_stmt
+		markdcl()
+		n := Nod(ir.OIF, nil, nil)
+		n.Ninit.Set(p.stmtList())
+		n.Left = p.expr()
+		n.Nbody.Set(p.block())
+		n.Rlist.Set(p.block())
+		popdcl()
+		return n
+
+	case ir.OSWITCH:
+		// parser.go:switch_stmt
+		markdcl()
+		n := Nod(ir.OSWITCH, nil, nil)
+		n.Ninit.Set(p.stmtList())
+		n.Left, _ = p.exprsOrNil()
+		n.List.Set(p.stmtList())
+		popdcl()
+		return n
+
+	case ir.OXCASE:
+		// parser.go:caseblock
+		markdcl()
+		n := Nod(ir.OXCASE, nil, nil)
+		n.List.Set(p.exprList())
+		n.Xoffset = int64(block)
+		n.Nbody.Set(p.stmtList())
+		popdcl()
+		return n
+
+	case ir.OXFALL:
+		n := Nod(ir.OXFALL, nil, nil)
+		n.Xoffset = int64(block)
+		return n
+
+	case ir.OGOTO, ir.OLABEL:
+		n := Nod(op, newname(p.name()), nil)
+		n.Sym = dclstack // context, for goto restrictions
+		return n
+
+	case ir.OBLOCK:
+		n := Nod(ir.OBLOCK, nil, nil)
+		n.List.Set(p.block())
+		return n
 	}
-	return
-}
 
-func (p *importer) sym() *Sym {
-	return p.fieldName()
+	Fatalf("importer: unexpected node %v (%d) in inlined body", Oconv(op, 0), op)
+	return nil
 }
 
-func (p *importer) bool() bool {
-	return p.int() != 0
+// builtinCall returns a call of the builtin function for op,
+// without arguments.
+func builtinCall(op ir.Op) *ir.Node {
+	return Nod(ir.OCALL, mkname(builtinpkg.Lookup(goopnames[op])), nil)
 }
 
-func (p *importer) op() Op {
-	return Op(p.int())
+func (p *importer) op() ir.Op {
+	return ir.Op(p.int())
 }
 
 // ----------------------------------------------------------------------------
@@ -814,24 +1166,48 @@ func (p *importer) int64() int64 {
 	return p.rawInt64()
 }
 
+func (p *importer) bool() bool {
+	return p.int() != 0
+}
+
 func (p *importer) string() string {
+	s, _ := p.stringHash()
+	return s
+}
+
+// stringHash reads a string and returns it with its hash, which is
+// computed only when the string is first read. The same names are
+// looked up again and again, often in the same package (parameter,
+// field and method names), and LookupHash doesn't hash them again.
+func (p *importer) stringHash() (string, uint32) {
 	if p.debugFormat {
 		p.marker('s')
 	}
 
-	if n := int(p.rawInt64()); n > 0 {
-		if cap(p.buf) < n {
-			p.buf = make([]byte, n)
-		} else {
-			p.buf = p.buf[:n]
-		}
-		for i := range p.buf {
-			p.buf[i] = p.byte()
+	// if the string was seen before, i is its index (>= 0)
+	// (the empty string is at index 0)
+	i := p.rawInt64()
+	if i >= 0 {
+		if i >= int64(len(p.strList)) {
+			Fatalf("importer: invalid string index %d", i)
 		}
-		return string(p.buf)
+		return p.strList[i], p.strHash[i]
 	}
 
-	return ""
+	// otherwise, i is the negative string length (< 0)
+	if n := int(-i); n <= cap(p.buf) {
+		p.buf = p.buf[:n]
+	} else {
+		p.buf = make([]byte, n)
+	}
+	for i := range p.buf {
+		p.buf[i] = p.byte()
+	}
+	h := ir.Strhash(p.buf)
+	s := ir.InternStringHash(p.buf, h)
+	p.strList = append(p.strList, s)
+	p.strHash = append(p.strHash, h)
+	return s, h
 }
 
 func (p *importer) marker(want byte) {
@@ -859,28 +1235,49 @@ func (p *importer) ReadByte() (byte, error) {
 	return p.byte(), nil
 }
 
+// unescaper reads the bytes escaped by escape from in,
+// without reading beyond them.
+type unescaper struct {
+	in io.ByteReader
+}
+
+func (u unescaper) ReadByte() (byte, error) {
+	c, err := u.in.ReadByte()
+	if c != '|' || err != nil {
+		return c, err
+	}
+	c, err = u.in.ReadByte()
+	if err != nil {
+		return c, err
+	}
+	switch c {
+	case 'S':
+		c = '$'
+	case '|':
+		// nothing to do
+	default:
+		return c, fmt.Errorf("unexpected escape sequence |%c in export data", c)
+	}
+	return c, nil
+}
+
+func (u unescaper) Read(b []byte) (int, error) {
+	for i := range b {
+		c, err := u.ReadByte()
+		if err != nil {
+			return i, err
+		}
+		b[i] = c
+	}
+	return len(b), nil
+}
+
 // byte is the bottleneck interface for reading from p.in.
-// It unescapes '|' 'S' to '$' and '|' '|' to '|'.
 func (p *importer) byte() byte {
 	c, err := p.in.ReadByte()
 	p.read++
 	if err != nil {
 		Fatalf("importer: read error: %v", err)
 	}
-	if c == '|' {
-		c, err = p.in.ReadByte()
-		p.read++
-		if err != nil {
-			Fatalf("importer: read error: %v", err)
-		}
-		switch c {
-		case 'S':
-			c = '$'
-		case '|':
-			// nothing to do
-		default:
-			Fatalf("importer: unexpected escape sequence in export data")
-		}
-	}
 	return c
 }
@@ -0,0 +1,142 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gc
+
+import (
+	"internal/testenv"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// A testDir is a scratch directory in which a test writes,
+// compiles, links and runs small Go programs.
+type testDir struct {
+	t   *testing.T
+	dir string
+	env []string // added to the environment of commands
+}
+
+// newTestDir creates a scratch directory for t, or skips t
+// if it cannot run the go command. The caller must call
+// remove when done with the directory.
+func newTestDir(t *testing.T, prefix string) *testDir {
+	testenv.MustHaveGoBuild(t)
+	dir, err := ioutil.TempDir("", prefix)
+	if err != nil {
+		t.Fatalf("could not create directory: %v", err)
+	}
+	return &testDir{t: t, dir: dir}
+}
+
+// remove removes the directory and everything in it.
+func (d *testDir) remove() {
+	os.RemoveAll(d.dir)
+}
+
+// path returns the name of file name in the directory.
+func (d *testDir) path(name string) string {
+	return filepath.Join(d.dir, name)
+}
+
+// write writes src to file name and returns its path.
+func (d *testDir) write(name, src string) string {
+	file := d.path(name)
+	if err := os.MkdirAll(filepath.Dir(file), 0777); err != nil {
+		d.t.Fatalf("could not create directory: %v", err)
+	}
+	if err := ioutil.WriteFile(file, []byte(src), 0644); err != nil {
+		d.t.Fatalf("could not write source file: %v", err)
+	}
+	return file
+}
+
+// try runs the command args in the directory
+// and returns its combined output.
+func (d *testDir) try(args ...string) (string, error) {
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Dir = d.dir
+	if d.env != nil {
+		cmd.Env = append(os.Environ(), d.env...)
+	}
+	out, err := cmd.CombinedOutput()
+	return string(out), err
+}
+
+// run is like try, but fails the test if the command fails.
+func (d *testDir) run(args ...string) string {
+	out, err := d.try(args...)
+	if err != nil {
+		d.t.Fatalf("%v: %v\n%s", args, err, out)
+	}
+	return out
+}
+
+// compile writes src to name.go and compiles it, with the
+// directory in the import path, to name.o, which it returns.
+func (d *testDir) compile(name, src string, flags ...string) string {
+	return d.compileTo(name+".o", name, src, flags...)
+}
+
+// pack is like compile, but writes the package archive name.a.
+func (d *testDir) pack(name, src string, flags ...string) string {
+	return d.compileTo(name+".a", name, src, append([]string{"-pack"}, flags...)...)
+}
+
+// compileTo is like compile, but writes the object file out.
+func (d *testDir) compileTo(out, name, src string, flags ...string) string {
+	file := d.write(name+".go", src)
+	out = d.path(out)
+	args := append([]string{"go", "tool", "compile", "-I", d.dir, "-o", out}, flags...)
+	d.run(append(args, file)...)
+	return out
+}
+
+// link links the main package in object file obj, looking for
+// imported packages in the directory, and returns the binary.
+func (d *testDir) link(obj string) string {
+	exe := strings.TrimSuffix(obj, filepath.Ext(obj)) + ".exe"
+	d.run("go", "tool", "link", "-L", d.dir, "-o", exe, obj)
+	return exe
+}
+
+// build writes src to name.go and builds it with go build,
+// passing the compiler gcflags, and returns the binary.
+func (d *testDir) build(name, src string, gcflags ...string) string {
+	file := d.write(name+".go", src)
+	exe := d.path(name + ".exe")
+	args := []string{"go", "build"}
+	if len(gcflags) > 0 {
+		args = append(args, "-gcflags", strings.Join(gcflags, " "))
+	}
+	d.run(append(args, "-o", exe, file)...)
+	return exe
+}
+
+// compileS compiles src with the given flags
+// and returns the assembly listing.
+func compileS(t *testing.T, src string, flags ...string) string {
+	d := newTestDir(t, "compileS")
+	defer d.remove()
+	file := d.write("x.go", src)
+	args := append([]string{"go", "tool", "compile", "-S", "-o", d.path("x.o")}, flags...)
+	return d.run(append(args, file)...)
+}
+
+// funcText returns the part of the assembly listing out for function fn.
+func funcText(out, fn string) string {
+	i := strings.Index(out, "\"\"."+fn+" t=")
+	if i < 0 {
+		return ""
+	}
+	out = out[i:]
+	if j := strings.Index(out[1:], "\n\"\"."); j >= 0 {
+		out = out[:j+1]
+	}
+	return out
+}
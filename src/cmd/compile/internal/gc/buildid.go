@@ -0,0 +1,113 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gc
+
+import (
+	"bufio"
+	"bytes"
+	"cmd/internal/obj"
+	"crypto/sha256"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+)
+
+// The content id of an object file is a hash of the inputs to the
+// compilation other than the source files: the compiler version, the
+// flags that affect the output, and the export data of the imported
+// packages. It is recorded in the export metadata, after the build id,
+// as
+//
+//	content id "hash"
+//
+// Build tools can compare content ids rather than file modification
+// times to decide whether a package must be recompiled, and can detect
+// packages in a build that were compiled with different flags.
+
+// importfiles maps the import paths of the packages imported by the
+// package being compiled to the files they were loaded from.
+var importfiles = map[string]string{}
+
+// contentid returns the content id of the package being compiled.
+func contentid() string {
+	h := sha256.New()
+	fmt.Fprintf(h, "go object %s %s %s %s\n", goos, goarch, obj.Getgoversion(), obj.Expstring())
+	switch Thearch.Thechar {
+	case '5':
+		fmt.Fprintf(h, "GOARM=%d\n", obj.Getgoarm())
+	case '8':
+		fmt.Fprintf(h, "GO386=%s\n", obj.Getgo386())
+	}
+
+	flag.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "I", "importcfg", "o", "linkobj", "asmhdr", "buildid", "fncache", "bench", "cpuprofile", "memprofile", "memprofilerate":
+			// These name the files the compiler reads or writes
+			// or only affect profiling and timing; the imported packages
+			// are accounted for by their export data below.
+			return
+		case "trimpath":
+			// This only changes the recorded paths of the source
+			// files, which are left out too, and go build passes
+			// a new temporary directory on every build.
+			return
+		}
+		fmt.Fprintf(h, "flag -%s=%s\n", f.Name, f.Value)
+	})
+
+	var paths []string
+	for path := range importfiles {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		data, err := readexportdata(importfiles[path])
+		if err != nil {
+			Fatalf("computing content id: %v", err)
+		}
+		fmt.Fprintf(h, "import %q %d\n", path, len(data))
+		h.Write(data)
+	}
+
+	return fmt.Sprintf("%x", h.Sum(nil))
+}
+
+// readexportdata returns the contents of the package file name up to
+// and including the $$ marker that ends the export data, or all of it
+// if it has no export data.
+func readexportdata(name string) ([]byte, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	r := bufio.NewReader(f)
+	var buf bytes.Buffer
+	for markers := 0; markers < 2; {
+		s, err := r.ReadBytes('$')
+		buf.Write(s)
+		if err == nil {
+			var c byte
+			c, err = r.ReadByte()
+			if err == nil {
+				buf.WriteByte(c)
+				if c == '$' {
+					markers++
+				}
+			}
+		}
+		if err == io.EOF {
+			// no export data (empty archive)
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return buf.Bytes(), nil
+}
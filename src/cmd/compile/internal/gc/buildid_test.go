@@ -0,0 +1,52 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gc
+
+import (
+	"io/ioutil"
+	"regexp"
+	"testing"
+)
+
+// Make sure the content id changes with the flags and the export data
+// of the imported packages, and only with those.
+func TestContentID(t *testing.T) {
+	d := newTestDir(t, "TestContentID")
+	defer d.remove()
+
+	idRE := regexp.MustCompile(`\ncontent id "([0-9a-f]+)"\n`)
+	compile := func(name, src, out string, flags ...string) string {
+		obj, err := ioutil.ReadFile(d.compileTo(out, name, src, flags...))
+		if err != nil {
+			t.Fatalf("could not read object file: %v", err)
+		}
+		m := idRE.FindSubmatch(obj)
+		if m == nil {
+			t.Fatalf("no content id in %s", out)
+		}
+		return string(m[1])
+	}
+
+	compile("p", "package p\nfunc F() int { return 1 }\n", "p.o")
+	main := "package main\nimport \"p\"\nfunc main() { p.F() }\n"
+	id := compile("main", main, "main.o")
+
+	if got := compile("main", main, "other.o"); got != id {
+		t.Errorf("content id changed with output file name")
+	}
+	if got := compile("main", "package main\nimport \"p\"\nfunc main() { p.F(); p.F() }\n", "main.o"); got != id {
+		t.Errorf("content id changed with source file")
+	}
+	if got := compile("main", main, "main.o", "-trimpath", d.dir); got != id {
+		t.Errorf("content id changed with -trimpath")
+	}
+	if got := compile("main", main, "main.o", "-N"); got == id {
+		t.Errorf("content id did not change with -N")
+	}
+	compile("p", "package p\nfunc F() int { return 2 }\n", "p.o")
+	if got := compile("main", main, "main.o"); got == id {
+		t.Errorf("content id did not change with export data of import")
+	}
+}
@@ -151,8 +151,28 @@ const runtimeimport = "" +
 	"func @\"\".racewrite (? uintptr \"unsafe-uintptr\")\n" +
 	"func @\"\".racereadrange (@\"\".addr·1 uintptr \"unsafe-uintptr\", @\"\".size·2 uintptr \"unsafe-uintptr\")\n" +
 	"func @\"\".racewriterange (@\"\".addr·1 uintptr \"unsafe-uintptr\", @\"\".size·2 uintptr \"unsafe-uintptr\")\n" +
+	"func @\"\".checkptrAlignment (@\"\".p·1 *byte, @\"\".typ·2 *byte, @\"\".n·3 uintptr \"unsafe-uintptr\")\n" +
+	"func @\"\".checkptrArithmetic (@\"\".p·1 uintptr \"unsafe-uintptr\", @\"\".originals·2 []*byte)\n" +
+	"func @\"\".cgoCheckPointer (@\"\".ptr·2 interface {}, @\"\".args·3 ...interface {}) (? interface {})\n" +
+	"func @\"\".checkfailed (@\"\".msg·1 string)\n" +
+	"func @\"\".checkwarn (@\"\".msg·1 string)\n" +
 	"func @\"\".msanread (@\"\".addr·1 uintptr \"unsafe-uintptr\", @\"\".size·2 uintptr \"unsafe-uintptr\")\n" +
 	"func @\"\".msanwrite (@\"\".addr·1 uintptr \"unsafe-uintptr\", @\"\".size·2 uintptr \"unsafe-uintptr\")\n" +
+	"func @\"\".asanread (@\"\".addr·1 uintptr \"unsafe-uintptr\", @\"\".size·2 uintptr \"unsafe-uintptr\")\n" +
+	"func @\"\".asanwrite (@\"\".addr·1 uintptr \"unsafe-uintptr\", @\"\".size·2 uintptr \"unsafe-uintptr\")\n" +
+	"func @\"\".asanpoison (@\"\".addr·1 uintptr \"unsafe-uintptr\", @\"\".size·2 uintptr \"unsafe-uintptr\")\n" +
+	"func @\"\".asanunpoison (@\"\".addr·1 uintptr \"unsafe-uintptr\", @\"\".size·2 uintptr \"unsafe-uintptr\")\n" +
+	"func @\"\".coverRegister (@\"\".counters·1 *uint32, @\"\".n·2 uintptr \"unsafe-uintptr\", @\"\".blocks·3 *uint8, @\"\".size·4 uintptr \"unsafe-uintptr\")\n" +
+	"func @\"\".libfuzzerInitCounters (@\"\".start·1 *uint8, @\"\".n·2 uintptr \"unsafe-uintptr\")\n" +
+	"func @\"\".libfuzzerTraceCmp1 (? uint8, ? uint8)\n" +
+	"func @\"\".libfuzzerTraceCmp2 (? uint16, ? uint16)\n" +
+	"func @\"\".libfuzzerTraceCmp4 (? uint32, ? uint32)\n" +
+	"func @\"\".libfuzzerTraceCmp8 (? uint64, ? uint64)\n" +
+	"func @\"\".libfuzzerTraceConstCmp1 (? uint8, ? uint8)\n" +
+	"func @\"\".libfuzzerTraceConstCmp2 (? uint16, ? uint16)\n" +
+	"func @\"\".libfuzzerTraceConstCmp4 (? uint32, ? uint32)\n" +
+	"func @\"\".libfuzzerTraceConstCmp8 (? uint64, ? uint64)\n" +
+	"func @\"\".libfuzzerHookStrCmp (? string, ? string)\n" +
 	"\n" +
 	"$$\n"
 
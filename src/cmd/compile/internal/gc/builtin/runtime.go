@@ -195,6 +195,38 @@ func racewrite(uintptr)
 func racereadrange(addr, size uintptr)
 func racewriterange(addr, size uintptr)
 
+// unsafe pointer checks
+func checkptrAlignment(p *byte, typ *byte, n uintptr)
+func checkptrArithmetic(p uintptr, originals []*byte)
+
+// cgo pointer checks
+func cgoCheckPointer(ptr interface{}, args ...interface{}) interface{}
+
+// compiler-inserted assertions
+func checkfailed(msg string)
+func checkwarn(msg string)
+
 // memory sanitizer
 func msanread(addr, size uintptr)
 func msanwrite(addr, size uintptr)
+
+// address sanitizer
+func asanread(addr, size uintptr)
+func asanwrite(addr, size uintptr)
+func asanpoison(addr, size uintptr)
+func asanunpoison(addr, size uintptr)
+
+// native coverage
+func coverRegister(counters *uint32, n uintptr, blocks *uint8, size uintptr)
+
+// libFuzzer instrumentation
+func libfuzzerInitCounters(start *uint8, n uintptr)
+func libfuzzerTraceCmp1(uint8, uint8)
+func libfuzzerTraceCmp2(uint16, uint16)
+func libfuzzerTraceCmp4(uint32, uint32)
+func libfuzzerTraceCmp8(uint64, uint64)
+func libfuzzerTraceConstCmp1(uint8, uint8)
+func libfuzzerTraceConstCmp2(uint16, uint16)
+func libfuzzerTraceConstCmp4(uint32, uint32)
+func libfuzzerTraceConstCmp8(uint64, uint64)
+func libfuzzerHookStrCmp(string, string)
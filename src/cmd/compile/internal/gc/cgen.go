@@ -5,6 +5,7 @@
 package gc
 
 import (
+	"cmd/compile/internal/ir"
 	"cmd/internal/obj"
 	"cmd/internal/obj/ppc64"
 	"fmt"
@@ -14,11 +15,11 @@ import (
 //	res = n;
 // simplifies and calls Thearch.Gmove.
 // if wb is true, need to emit write barriers.
-func Cgen(n, res *Node) {
+func Cgen(n, res *ir.Node) {
 	cgen_wb(n, res, false)
 }
 
-func cgen_wb(n, res *Node, wb bool) {
+func cgen_wb(n, res *ir.Node, wb bool) {
 	if Debug['g'] != 0 {
 		op := "cgen"
 		if wb {
@@ -36,18 +37,18 @@ func cgen_wb(n, res *Node, wb bool) {
 		Fatalf("cgen: res nil")
 	}
 
-	for n.Op == OCONVNOP {
+	for n.Op == ir.OCONVNOP {
 		n = n.Left
 	}
 
 	switch n.Op {
-	case OSLICE, OSLICEARR, OSLICESTR, OSLICE3, OSLICE3ARR:
+	case ir.OSLICE, ir.OSLICEARR, ir.OSLICESTR, ir.OSLICE3, ir.OSLICE3ARR:
 		cgen_slice(n, res, wb)
 		return
 
-	case OEFACE:
-		if res.Op != ONAME || !res.Addable || wb {
-			var n1 Node
+	case ir.OEFACE:
+		if res.Op != ir.ONAME || !res.Addable || wb {
+			var n1 ir.Node
 			Tempname(&n1, n.Type)
 			Cgen_eface(n, &n1)
 			cgen_wb(&n1, res, wb)
@@ -56,21 +57,21 @@ func cgen_wb(n, res *Node, wb bool) {
 		}
 		return
 
-	case ODOTTYPE:
+	case ir.ODOTTYPE:
 		cgen_dottype(n, res, nil, wb)
 		return
 
-	case OAPPEND:
+	case ir.OAPPEND:
 		cgen_append(n, res)
 		return
 	}
 
 	if n.Ullman >= UINF {
-		if n.Op == OINDREG {
+		if n.Op == ir.OINDREG {
 			Fatalf("cgen: this is going to miscompile")
 		}
 		if res.Ullman >= UINF {
-			var n1 Node
+			var n1 ir.Node
 			Tempname(&n1, n.Type)
 			Cgen(n, &n1)
 			cgen_wb(&n1, res, wb)
@@ -89,14 +90,14 @@ func cgen_wb(n, res *Node, wb bool) {
 	if !res.Addable {
 		if n.Ullman > res.Ullman {
 			if Ctxt.Arch.Regsize == 4 && Is64(n.Type) {
-				var n1 Node
+				var n1 ir.Node
 				Tempname(&n1, n.Type)
 				Cgen(n, &n1)
 				cgen_wb(&n1, res, wb)
 				return
 			}
 
-			var n1 Node
+			var n1 ir.Node
 			Regalloc(&n1, n.Type, res)
 			Cgen(n, &n1)
 			if n1.Ullman > res.Ullman {
@@ -118,22 +119,22 @@ func cgen_wb(n, res *Node, wb bool) {
 
 			f := true // gen thru register
 			switch n.Op {
-			case OLITERAL:
+			case ir.OLITERAL:
 				if Smallintconst(n) {
 					f = false
 				}
 
-			case OREGISTER:
+			case ir.OREGISTER:
 				f = false
 			}
 
 			if !Iscomplex[n.Type.Etype] && Ctxt.Arch.Regsize == 8 && !wb {
-				a := Thearch.Optoas(OAS, res.Type)
+				a := Thearch.Optoas(ir.OAS, res.Type)
 				var addr obj.Addr
 				if Thearch.Sudoaddable(a, res, &addr) {
 					var p1 *obj.Prog
 					if f {
-						var n2 Node
+						var n2 ir.Node
 						Regalloc(&n2, res.Type, nil)
 						Cgen(n, &n2)
 						p1 = Thearch.Gins(a, &n2, nil)
@@ -153,7 +154,7 @@ func cgen_wb(n, res *Node, wb bool) {
 
 		if Ctxt.Arch.Thechar == '8' {
 			// no registers to speak of
-			var n1, n2 Node
+			var n1, n2 ir.Node
 			Tempname(&n1, n.Type)
 			Cgen(n, &n1)
 			Igen(res, &n2, nil)
@@ -162,7 +163,7 @@ func cgen_wb(n, res *Node, wb bool) {
 			return
 		}
 
-		var n1 Node
+		var n1 ir.Node
 		Igen(res, &n1, nil)
 		cgen_wb(n, &n1, wb)
 		Regfree(&n1)
@@ -173,17 +174,17 @@ func cgen_wb(n, res *Node, wb bool) {
 	// can't do in walk because n->left->addable
 	// changes if n->left is an escaping local variable.
 	switch n.Op {
-	case OSPTR, OLEN:
-		if Isslice(n.Left.Type) || Istype(n.Left.Type, TSTRING) {
+	case ir.OSPTR, ir.OLEN:
+		if Isslice(n.Left.Type) || Istype(n.Left.Type, ir.TSTRING) {
 			n.Addable = n.Left.Addable
 		}
 
-	case OCAP:
+	case ir.OCAP:
 		if Isslice(n.Left.Type) {
 			n.Addable = n.Left.Addable
 		}
 
-	case OITAB:
+	case ir.OITAB:
 		n.Addable = n.Left.Addable
 	}
 
@@ -192,7 +193,7 @@ func cgen_wb(n, res *Node, wb bool) {
 			Fatalf("cgen_wb of type %v", res.Type)
 		}
 		if n.Ullman >= UINF {
-			var n1 Node
+			var n1 ir.Node
 			Tempname(&n1, n.Type)
 			Cgen(n, &n1)
 			n = &n1
@@ -206,10 +207,10 @@ func cgen_wb(n, res *Node, wb bool) {
 	if Ctxt.Arch.Thechar == '5' { // TODO(rsc): Maybe more often?
 		// if both are addressable, move
 		if n.Addable && res.Addable {
-			if Is64(n.Type) || Is64(res.Type) || n.Op == OREGISTER || res.Op == OREGISTER || Iscomplex[n.Type.Etype] || Iscomplex[res.Type.Etype] {
+			if Is64(n.Type) || Is64(res.Type) || n.Op == ir.OREGISTER || res.Op == ir.OREGISTER || Iscomplex[n.Type.Etype] || Iscomplex[res.Type.Etype] {
 				Thearch.Gmove(n, res)
 			} else {
-				var n1 Node
+				var n1 ir.Node
 				Regalloc(&n1, n.Type, nil)
 				Thearch.Gmove(n, &n1)
 				Cgen(&n1, res)
@@ -223,7 +224,7 @@ func cgen_wb(n, res *Node, wb bool) {
 		if !n.Addable && !res.Addable {
 			// could use regalloc here sometimes,
 			// but have to check for ullman >= UINF.
-			var n1 Node
+			var n1 ir.Node
 			Tempname(&n1, n.Type)
 			Cgen(n, &n1)
 			Cgen(&n1, res)
@@ -233,7 +234,7 @@ func cgen_wb(n, res *Node, wb bool) {
 		// if result is not addressable directly but n is,
 		// compute its address and then store via the address.
 		if !res.Addable {
-			var n1 Node
+			var n1 ir.Node
 			Igen(res, &n1, nil)
 			Cgen(n, &n1)
 			Regfree(&n1)
@@ -254,10 +255,10 @@ func cgen_wb(n, res *Node, wb bool) {
 	if Ctxt.Arch.Thechar == '0' || Ctxt.Arch.Thechar == '7' || Ctxt.Arch.Thechar == '9' {
 		// if both are addressable, move
 		if n.Addable {
-			if n.Op == OREGISTER || res.Op == OREGISTER {
+			if n.Op == ir.OREGISTER || res.Op == ir.OREGISTER {
 				Thearch.Gmove(n, res)
 			} else {
-				var n1 Node
+				var n1 ir.Node
 				Regalloc(&n1, n.Type, nil)
 				Thearch.Gmove(n, &n1)
 				Cgen(&n1, res)
@@ -269,11 +270,11 @@ func cgen_wb(n, res *Node, wb bool) {
 
 	// if n is sudoaddable generate addr and move
 	if Ctxt.Arch.Thechar == '5' && !Is64(n.Type) && !Is64(res.Type) && !Iscomplex[n.Type.Etype] && !Iscomplex[res.Type.Etype] {
-		a := Thearch.Optoas(OAS, n.Type)
+		a := Thearch.Optoas(ir.OAS, n.Type)
 		var addr obj.Addr
 		if Thearch.Sudoaddable(a, n, &addr) {
-			if res.Op != OREGISTER {
-				var n2 Node
+			if res.Op != ir.OREGISTER {
+				var n2 ir.Node
 				Regalloc(&n2, res.Type, nil)
 				p1 := Thearch.Gins(a, nil, &n2)
 				p1.From = addr
@@ -299,7 +300,7 @@ func cgen_wb(n, res *Node, wb bool) {
 
 	if nl != nil && nl.Ullman >= UINF {
 		if nr != nil && nr.Ullman >= UINF {
-			var n1 Node
+			var n1 ir.Node
 			Tempname(&n1, nl.Type)
 			Cgen(nl, &n1)
 			n2 := *n
@@ -313,17 +314,17 @@ func cgen_wb(n, res *Node, wb bool) {
 	if Ctxt.Arch.Regsize == 4 && (Is64(n.Type) || Is64(res.Type) || n.Left != nil && Is64(n.Left.Type)) {
 		switch n.Op {
 		// math goes to cgen64.
-		case OMINUS,
-			OCOM,
-			OADD,
-			OSUB,
-			OMUL,
-			OLROT,
-			OLSH,
-			ORSH,
-			OAND,
-			OOR,
-			OXOR:
+		case ir.OMINUS,
+			ir.OCOM,
+			ir.OADD,
+			ir.OSUB,
+			ir.OMUL,
+			ir.OLROT,
+			ir.OLSH,
+			ir.ORSH,
+			ir.OAND,
+			ir.OOR,
+			ir.OXOR:
 			Thearch.Cgen64(n, res)
 			return
 		}
@@ -335,14 +336,14 @@ func cgen_wb(n, res *Node, wb bool) {
 	}
 
 	if !Iscomplex[n.Type.Etype] && Ctxt.Arch.Regsize == 8 {
-		a := Thearch.Optoas(OAS, n.Type)
+		a := Thearch.Optoas(ir.OAS, n.Type)
 		var addr obj.Addr
 		if Thearch.Sudoaddable(a, n, &addr) {
-			if res.Op == OREGISTER {
+			if res.Op == ir.OREGISTER {
 				p1 := Thearch.Gins(a, nil, res)
 				p1.From = addr
 			} else {
-				var n2 Node
+				var n2 ir.Node
 				Regalloc(&n2, n.Type, nil)
 				p1 := Thearch.Gins(a, nil, &n2)
 				p1.From = addr
@@ -360,49 +361,49 @@ func cgen_wb(n, res *Node, wb bool) {
 	default:
 		Dump("cgen", n)
 		Dump("cgen-res", res)
-		Fatalf("cgen: unknown op %v", Nconv(n, FmtShort|FmtSign))
+		Fatalf("cgen: unknown op %v", Nconv(n, ir.FmtShort|ir.FmtSign))
 
-	case OOROR, OANDAND,
-		OEQ, ONE,
-		OLT, OLE,
-		OGE, OGT,
-		ONOT:
+	case ir.OOROR, ir.OANDAND,
+		ir.OEQ, ir.ONE,
+		ir.OLT, ir.OLE,
+		ir.OGE, ir.OGT,
+		ir.ONOT:
 		Bvgen(n, res, true)
 		return
 
-	case OPLUS:
+	case ir.OPLUS:
 		Cgen(nl, res)
 		return
 
 		// unary
-	case OCOM:
-		a := Thearch.Optoas(OXOR, nl.Type)
+	case ir.OCOM:
+		a := Thearch.Optoas(ir.OXOR, nl.Type)
 
-		var n1 Node
+		var n1 ir.Node
 		Regalloc(&n1, nl.Type, nil)
 		Cgen(nl, &n1)
-		var n2 Node
+		var n2 ir.Node
 		Nodconst(&n2, nl.Type, -1)
 		Thearch.Gins(a, &n2, &n1)
 		cgen_norm(n, &n1, res)
 		return
 
-	case OMINUS:
+	case ir.OMINUS:
 		if Isfloat[nl.Type.Etype] {
 			nr = Nodintconst(-1)
 			nr = convlit(nr, n.Type)
-			a = Thearch.Optoas(OMUL, nl.Type)
+			a = Thearch.Optoas(ir.OMUL, nl.Type)
 			goto sbop
 		}
 
 		a := Thearch.Optoas(n.Op, nl.Type)
 		// unary
-		var n1 Node
+		var n1 ir.Node
 		Regalloc(&n1, nl.Type, res)
 
 		Cgen(nl, &n1)
 		if Ctxt.Arch.Thechar == '5' {
-			var n2 Node
+			var n2 ir.Node
 			Nodconst(&n2, nl.Type, 0)
 			Thearch.Gins(a, &n2, &n1)
 		} else if Ctxt.Arch.Thechar == '7' {
@@ -413,48 +414,48 @@ func cgen_wb(n, res *Node, wb bool) {
 		cgen_norm(n, &n1, res)
 		return
 
-	case OSQRT:
-		var n1 Node
+	case ir.OSQRT:
+		var n1 ir.Node
 		Regalloc(&n1, nl.Type, res)
 		Cgen(n.Left, &n1)
-		Thearch.Gins(Thearch.Optoas(OSQRT, nl.Type), &n1, &n1)
+		Thearch.Gins(Thearch.Optoas(ir.OSQRT, nl.Type), &n1, &n1)
 		Thearch.Gmove(&n1, res)
 		Regfree(&n1)
 		return
 
-	case OGETG:
+	case ir.OGETG:
 		Thearch.Getg(res)
 		return
 
 		// symmetric binary
-	case OAND,
-		OOR,
-		OXOR,
-		OADD,
-		OMUL:
-		if n.Op == OMUL && Thearch.Cgen_bmul != nil && Thearch.Cgen_bmul(n.Op, nl, nr, res) {
+	case ir.OAND,
+		ir.OOR,
+		ir.OXOR,
+		ir.OADD,
+		ir.OMUL:
+		if n.Op == ir.OMUL && Thearch.Cgen_bmul != nil && Thearch.Cgen_bmul(n.Op, nl, nr, res) {
 			break
 		}
 		a = Thearch.Optoas(n.Op, nl.Type)
 		goto sbop
 
 		// asymmetric binary
-	case OSUB:
+	case ir.OSUB:
 		a = Thearch.Optoas(n.Op, nl.Type)
 		goto abop
 
-	case OHMUL:
+	case ir.OHMUL:
 		Thearch.Cgen_hmul(nl, nr, res)
 
-	case OCONV:
+	case ir.OCONV:
 		if Eqtype(n.Type, nl.Type) || Noconv(n.Type, nl.Type) {
 			Cgen(nl, res)
 			return
 		}
 
 		if Ctxt.Arch.Thechar == '8' {
-			var n1 Node
-			var n2 Node
+			var n1 ir.Node
+			var n2 ir.Node
 			Tempname(&n2, n.Type)
 			Mgen(nl, &n1, res)
 			Thearch.Gmove(&n1, &n2)
@@ -463,8 +464,8 @@ func cgen_wb(n, res *Node, wb bool) {
 			break
 		}
 
-		var n1 Node
-		var n2 Node
+		var n1 ir.Node
+		var n2 ir.Node
 		if Ctxt.Arch.Thechar == '5' {
 			if nl.Addable && !Is64(nl.Type) {
 				Regalloc(&n1, nl.Type, res)
@@ -487,7 +488,7 @@ func cgen_wb(n, res *Node, wb bool) {
 				// If loading from memory, do conversion during load,
 				// so as to avoid use of 8-bit register in, say, int(*byteptr).
 				switch nl.Op {
-				case ODOT, ODOTPTR, OINDEX, OIND, ONAME:
+				case ir.ODOT, ir.ODOTPTR, ir.OINDEX, ir.OIND, ir.ONAME:
 					Igen(nl, &n1, res)
 					Regalloc(&n2, n.Type, res)
 					Thearch.Gmove(&n1, &n2)
@@ -507,39 +508,39 @@ func cgen_wb(n, res *Node, wb bool) {
 		// have to allocate its own register.
 		Thearch.Gmove(&n1, &n2)
 		Thearch.Gmove(&n2, res)
-		if n2.Op == OREGISTER {
+		if n2.Op == ir.OREGISTER {
 			Regfree(&n2)
 		}
-		if n1.Op == OREGISTER {
+		if n1.Op == ir.OREGISTER {
 			Regfree(&n1)
 		}
 
-	case ODOT,
-		ODOTPTR,
-		OINDEX,
-		OIND,
-		ONAME: // PHEAP or PPARAMREF var
-		var n1 Node
+	case ir.ODOT,
+		ir.ODOTPTR,
+		ir.OINDEX,
+		ir.OIND,
+		ir.ONAME: // PHEAP or PPARAMREF var
+		var n1 ir.Node
 		Igen(n, &n1, res)
 
 		Thearch.Gmove(&n1, res)
 		Regfree(&n1)
 
 		// interface table is first word of interface value
-	case OITAB:
-		var n1 Node
+	case ir.OITAB:
+		var n1 ir.Node
 		Igen(nl, &n1, res)
 
 		n1.Type = n.Type
 		Thearch.Gmove(&n1, res)
 		Regfree(&n1)
 
-	case OSPTR:
+	case ir.OSPTR:
 		// pointer is the first word of string or slice.
-		if Isconst(nl, CTSTR) {
-			var n1 Node
-			Regalloc(&n1, Types[Tptr], res)
-			p1 := Thearch.Gins(Thearch.Optoas(OAS, n1.Type), nil, &n1)
+		if ir.Isconst(nl, ir.CTSTR) {
+			var n1 ir.Node
+			Regalloc(&n1, ir.Types[Tptr], res)
+			p1 := Thearch.Gins(Thearch.Optoas(ir.OAS, n1.Type), nil, &n1)
 			Datastring(nl.Val().U.(string), &p1.From)
 			p1.From.Type = obj.TYPE_ADDR
 			Thearch.Gmove(&n1, res)
@@ -547,28 +548,28 @@ func cgen_wb(n, res *Node, wb bool) {
 			break
 		}
 
-		var n1 Node
+		var n1 ir.Node
 		Igen(nl, &n1, res)
 		n1.Type = n.Type
 		Thearch.Gmove(&n1, res)
 		Regfree(&n1)
 
-	case OLEN:
-		if Istype(nl.Type, TMAP) || Istype(nl.Type, TCHAN) {
+	case ir.OLEN:
+		if Istype(nl.Type, ir.TMAP) || Istype(nl.Type, ir.TCHAN) {
 			// map and chan have len in the first int-sized word.
 			// a zero pointer means zero length
-			var n1 Node
-			Regalloc(&n1, Types[Tptr], res)
+			var n1 ir.Node
+			Regalloc(&n1, ir.Types[Tptr], res)
 
 			Cgen(nl, &n1)
 
-			var n2 Node
-			Nodconst(&n2, Types[Tptr], 0)
-			p1 := Thearch.Ginscmp(OEQ, Types[Tptr], &n1, &n2, 0)
+			var n2 ir.Node
+			Nodconst(&n2, ir.Types[Tptr], 0)
+			p1 := Thearch.Ginscmp(ir.OEQ, ir.Types[Tptr], &n1, &n2, 0)
 
 			n2 = n1
-			n2.Op = OINDREG
-			n2.Type = Types[Simtype[TINT]]
+			n2.Op = ir.OINDREG
+			n2.Type = ir.Types[Simtype[ir.TINT]]
 			Thearch.Gmove(&n2, &n1)
 
 			Patch(p1, Pc)
@@ -578,38 +579,38 @@ func cgen_wb(n, res *Node, wb bool) {
 			break
 		}
 
-		if Istype(nl.Type, TSTRING) || Isslice(nl.Type) {
+		if Istype(nl.Type, ir.TSTRING) || Isslice(nl.Type) {
 			// both slice and string have len one pointer into the struct.
 			// a zero pointer means zero length
-			var n1 Node
+			var n1 ir.Node
 			Igen(nl, &n1, res)
 
-			n1.Type = Types[Simtype[TUINT]]
+			n1.Type = ir.Types[Simtype[ir.TUINT]]
 			n1.Xoffset += int64(Array_nel)
 			Thearch.Gmove(&n1, res)
 			Regfree(&n1)
 			break
 		}
 
-		Fatalf("cgen: OLEN: unknown type %v", Tconv(nl.Type, FmtLong))
+		Fatalf("cgen: OLEN: unknown type %v", Tconv(nl.Type, ir.FmtLong))
 
-	case OCAP:
-		if Istype(nl.Type, TCHAN) {
+	case ir.OCAP:
+		if Istype(nl.Type, ir.TCHAN) {
 			// chan has cap in the second int-sized word.
 			// a zero pointer means zero length
-			var n1 Node
-			Regalloc(&n1, Types[Tptr], res)
+			var n1 ir.Node
+			Regalloc(&n1, ir.Types[Tptr], res)
 
 			Cgen(nl, &n1)
 
-			var n2 Node
-			Nodconst(&n2, Types[Tptr], 0)
-			p1 := Thearch.Ginscmp(OEQ, Types[Tptr], &n1, &n2, 0)
+			var n2 ir.Node
+			Nodconst(&n2, ir.Types[Tptr], 0)
+			p1 := Thearch.Ginscmp(ir.OEQ, ir.Types[Tptr], &n1, &n2, 0)
 
 			n2 = n1
-			n2.Op = OINDREG
+			n2.Op = ir.OINDREG
 			n2.Xoffset = int64(Widthint)
-			n2.Type = Types[Simtype[TINT]]
+			n2.Type = ir.Types[Simtype[ir.TINT]]
 			Thearch.Gmove(&n2, &n1)
 
 			Patch(p1, Pc)
@@ -620,18 +621,18 @@ func cgen_wb(n, res *Node, wb bool) {
 		}
 
 		if Isslice(nl.Type) {
-			var n1 Node
+			var n1 ir.Node
 			Igen(nl, &n1, res)
-			n1.Type = Types[Simtype[TUINT]]
+			n1.Type = ir.Types[Simtype[ir.TUINT]]
 			n1.Xoffset += int64(Array_cap)
 			Thearch.Gmove(&n1, res)
 			Regfree(&n1)
 			break
 		}
 
-		Fatalf("cgen: OCAP: unknown type %v", Tconv(nl.Type, FmtLong))
+		Fatalf("cgen: OCAP: unknown type %v", Tconv(nl.Type, ir.FmtLong))
 
-	case OADDR:
+	case ir.OADDR:
 		if n.Bounded { // let race detector avoid nil checks
 			Disable_checknil++
 		}
@@ -640,32 +641,32 @@ func cgen_wb(n, res *Node, wb bool) {
 			Disable_checknil--
 		}
 
-	case OCALLMETH:
+	case ir.OCALLMETH:
 		cgen_callmeth(n, 0)
 		cgen_callret(n, res)
 
-	case OCALLINTER:
+	case ir.OCALLINTER:
 		cgen_callinter(n, res, 0)
 		cgen_callret(n, res)
 
-	case OCALLFUNC:
+	case ir.OCALLFUNC:
 		cgen_call(n, 0)
 		cgen_callret(n, res)
 
-	case OMOD, ODIV:
+	case ir.OMOD, ir.ODIV:
 		if Isfloat[n.Type.Etype] || Thearch.Dodiv == nil {
 			a = Thearch.Optoas(n.Op, nl.Type)
 			goto abop
 		}
 
 		if nl.Ullman >= nr.Ullman {
-			var n1 Node
+			var n1 ir.Node
 			Regalloc(&n1, nl.Type, res)
 			Cgen(nl, &n1)
 			cgen_div(n.Op, &n1, nr, res)
 			Regfree(&n1)
 		} else {
-			var n2 Node
+			var n2 ir.Node
 			if !Smallintconst(nr) {
 				Regalloc(&n2, nr.Type, res)
 				Cgen(nr, &n2)
@@ -674,12 +675,12 @@ func cgen_wb(n, res *Node, wb bool) {
 			}
 
 			cgen_div(n.Op, nl, &n2, res)
-			if n2.Op != OLITERAL {
+			if n2.Op != ir.OLITERAL {
 				Regfree(&n2)
 			}
 		}
 
-	case OLSH, ORSH, OLROT:
+	case ir.OLSH, ir.ORSH, ir.OLROT:
 		Thearch.Cgen_shift(n.Op, n.Bounded, nl, nr, res)
 	}
 
@@ -700,19 +701,19 @@ func cgen_wb(n, res *Node, wb bool) {
 	// might as well go on the left, so we can reuse that
 	// register for the computation.
 sbop: // symmetric binary
-	if nl.Ullman < nr.Ullman || (nl.Ullman == nr.Ullman && (Smallintconst(nl) || (nr.Op == OLITERAL && !Smallintconst(nr)))) {
+	if nl.Ullman < nr.Ullman || (nl.Ullman == nr.Ullman && (Smallintconst(nl) || (nr.Op == ir.OLITERAL && !Smallintconst(nr)))) {
 		nl, nr = nr, nl
 	}
 
 abop: // asymmetric binary
-	var n1 Node
-	var n2 Node
+	var n1 ir.Node
+	var n2 ir.Node
 	if Ctxt.Arch.Thechar == '8' {
 		// no registers, sigh
 		if Smallintconst(nr) {
-			var n1 Node
+			var n1 ir.Node
 			Mgen(nl, &n1, res)
-			var n2 Node
+			var n2 ir.Node
 			Regalloc(&n2, nl.Type, &n1)
 			Thearch.Gmove(&n1, &n2)
 			Thearch.Gins(a, nr, &n2)
@@ -720,12 +721,12 @@ abop: // asymmetric binary
 			Regfree(&n2)
 			Mfree(&n1)
 		} else if nl.Ullman >= nr.Ullman {
-			var nt Node
+			var nt ir.Node
 			Tempname(&nt, nl.Type)
 			Cgen(nl, &nt)
-			var n2 Node
+			var n2 ir.Node
 			Mgen(nr, &n2, nil)
-			var n1 Node
+			var n1 ir.Node
 			Regalloc(&n1, nl.Type, res)
 			Thearch.Gmove(&nt, &n1)
 			Thearch.Gins(a, &n2, &n1)
@@ -733,10 +734,10 @@ abop: // asymmetric binary
 			Regfree(&n1)
 			Mfree(&n2)
 		} else {
-			var n2 Node
+			var n2 ir.Node
 			Regalloc(&n2, nr.Type, res)
 			Cgen(nr, &n2)
-			var n1 Node
+			var n1 ir.Node
 			Regalloc(&n1, nl.Type, nil)
 			Cgen(nl, &n1)
 			Thearch.Gins(a, &n2, &n1)
@@ -770,17 +771,26 @@ abop: // asymmetric binary
 	}
 
 	Thearch.Gins(a, &n2, &n1)
-	if n2.Op != OLITERAL {
+	if n2.Op != ir.OLITERAL {
 		Regfree(&n2)
 	}
 	cgen_norm(n, &n1, res)
 }
 
-var sys_wbptr *Node
+var sys_wbptr *ir.Node
 
-func cgen_wbptr(n, res *Node) {
+// genwb records, for the assignment being generated by the legacy back
+// end, whether it has a write barrier, and whether its pointers were
+// left out because they would store the pointer already there; see
+// checkwbgen.
+var genwb struct {
+	wb, skipped bool
+}
+
+func cgen_wbptr(n, res *ir.Node) {
+	genwb.wb = true
 	if Curfn != nil {
-		if Curfn.Func.Pragma&Nowritebarrier != 0 {
+		if Curfn.Func.Pragma&ir.Nowritebarrier != 0 {
 			Yyerror("write barrier prohibited")
 		}
 		if Curfn.Func.WBLineno == 0 {
@@ -791,9 +801,9 @@ func cgen_wbptr(n, res *Node) {
 		Warn("write barrier")
 	}
 
-	var dst, src Node
+	var dst, src ir.Node
 	Igen(res, &dst, nil)
-	if n.Op == OREGISTER {
+	if n.Op == ir.OREGISTER {
 		src = *n
 		Regrealloc(&src)
 	} else {
@@ -801,25 +811,25 @@ func cgen_wbptr(n, res *Node) {
 	}
 
 	wbVar := syslook("writeBarrier")
-	wbEnabled := NodSym(ODOT, wbVar, wbVar.Type.Field(0).Sym)
+	wbEnabled := NodSym(ir.ODOT, wbVar, wbVar.Type.Field(0).Sym)
 	wbEnabled = typecheck(wbEnabled, Erv)
-	pbr := Thearch.Ginscmp(ONE, Types[TUINT8], wbEnabled, Nodintconst(0), -1)
-	Thearch.Gins(Thearch.Optoas(OAS, Types[Tptr]), &src, &dst)
+	pbr := Thearch.Ginscmp(ir.ONE, ir.Types[ir.TUINT8], wbEnabled, Nodintconst(0), -1)
+	Thearch.Gins(Thearch.Optoas(ir.OAS, ir.Types[Tptr]), &src, &dst)
 	pjmp := Gbranch(obj.AJMP, nil, 0)
 	Patch(pbr, Pc)
-	var adst Node
+	var adst ir.Node
 	Agenr(&dst, &adst, &dst)
-	p := Thearch.Gins(Thearch.Optoas(OAS, Types[Tptr]), &adst, nil)
+	p := Thearch.Gins(Thearch.Optoas(ir.OAS, ir.Types[Tptr]), &adst, nil)
 	a := &p.To
 	a.Type = obj.TYPE_MEM
 	a.Reg = int16(Thearch.REGSP)
 	a.Offset = Ctxt.FixedFrameSize()
-	p2 := Thearch.Gins(Thearch.Optoas(OAS, Types[Tptr]), &src, nil)
+	p2 := Thearch.Gins(Thearch.Optoas(ir.OAS, ir.Types[Tptr]), &src, nil)
 	p2.To = p.To
 	p2.To.Offset += int64(Widthptr)
 	Regfree(&adst)
 	if sys_wbptr == nil {
-		sys_wbptr = writebarrierfn("writebarrierptr", Types[Tptr], Types[Tptr])
+		sys_wbptr = writebarrierfn("writebarrierptr", ir.Types[Tptr], ir.Types[Tptr])
 	}
 	Ginscall(sys_wbptr, 0)
 	Patch(pjmp, Pc)
@@ -828,9 +838,10 @@ func cgen_wbptr(n, res *Node) {
 	Regfree(&src)
 }
 
-func cgen_wbfat(n, res *Node) {
+func cgen_wbfat(n, res *ir.Node) {
+	genwb.wb = true
 	if Curfn != nil {
-		if Curfn.Func.Pragma&Nowritebarrier != 0 {
+		if Curfn.Func.Pragma&ir.Nowritebarrier != 0 {
 			Yyerror("write barrier prohibited")
 		}
 		if Curfn.Func.WBLineno == 0 {
@@ -842,7 +853,7 @@ func cgen_wbfat(n, res *Node) {
 	}
 	needType := true
 	funcName := "typedmemmove"
-	var dst, src Node
+	var dst, src ir.Node
 	if n.Ullman >= res.Ullman {
 		Agenr(n, &src, nil)
 		Agenr(res, &dst, nil)
@@ -850,7 +861,7 @@ func cgen_wbfat(n, res *Node) {
 		Agenr(res, &dst, nil)
 		Agenr(n, &src, nil)
 	}
-	p := Thearch.Gins(Thearch.Optoas(OAS, Types[Tptr]), &dst, nil)
+	p := Thearch.Gins(Thearch.Optoas(ir.OAS, ir.Types[Tptr]), &dst, nil)
 	a := &p.To
 	a.Type = obj.TYPE_MEM
 	a.Reg = int16(Thearch.REGSP)
@@ -858,32 +869,32 @@ func cgen_wbfat(n, res *Node) {
 	if needType {
 		a.Offset += int64(Widthptr)
 	}
-	p2 := Thearch.Gins(Thearch.Optoas(OAS, Types[Tptr]), &src, nil)
+	p2 := Thearch.Gins(Thearch.Optoas(ir.OAS, ir.Types[Tptr]), &src, nil)
 	p2.To = p.To
 	p2.To.Offset += int64(Widthptr)
 	Regfree(&dst)
 	if needType {
-		src.Type = Types[Tptr]
-		Thearch.Gins(Thearch.Optoas(OAS, Types[Tptr]), typename(n.Type), &src)
-		p3 := Thearch.Gins(Thearch.Optoas(OAS, Types[Tptr]), &src, nil)
+		src.Type = ir.Types[Tptr]
+		Thearch.Gins(Thearch.Optoas(ir.OAS, ir.Types[Tptr]), typename(n.Type), &src)
+		p3 := Thearch.Gins(Thearch.Optoas(ir.OAS, ir.Types[Tptr]), &src, nil)
 		p3.To = p2.To
 		p3.To.Offset -= 2 * int64(Widthptr)
 	}
 	Regfree(&src)
-	Ginscall(writebarrierfn(funcName, Types[Tptr], Types[Tptr]), 0)
+	Ginscall(writebarrierfn(funcName, ir.Types[Tptr], ir.Types[Tptr]), 0)
 }
 
 // cgen_norm moves n1 to res, truncating to expected type if necessary.
 // n1 is a register, and cgen_norm frees it.
-func cgen_norm(n, n1, res *Node) {
+func cgen_norm(n, n1, res *ir.Node) {
 	switch Ctxt.Arch.Thechar {
 	case '6', '8':
 		// We use sized math, so the result is already truncated.
 	default:
 		switch n.Op {
-		case OADD, OSUB, OMUL, ODIV, OCOM, OMINUS:
+		case ir.OADD, ir.OSUB, ir.OMUL, ir.ODIV, ir.OCOM, ir.OMINUS:
 			// TODO(rsc): What about left shift?
-			Thearch.Gins(Thearch.Optoas(OAS, n.Type), n1, n1)
+			Thearch.Gins(Thearch.Optoas(ir.OAS, n.Type), n1, n1)
 		}
 	}
 
@@ -891,12 +902,12 @@ func cgen_norm(n, n1, res *Node) {
 	Regfree(n1)
 }
 
-func Mgen(n *Node, n1 *Node, rg *Node) {
-	n1.Op = OEMPTY
+func Mgen(n *ir.Node, n1 *ir.Node, rg *ir.Node) {
+	n1.Op = ir.OEMPTY
 
 	if n.Addable {
 		*n1 = *n
-		if n1.Op == OREGISTER || n1.Op == OINDREG {
+		if n1.Op == ir.OREGISTER || n1.Op == ir.OINDREG {
 			reg[n.Reg-int16(Thearch.REGMIN)]++
 		}
 		return
@@ -911,8 +922,8 @@ func Mgen(n *Node, n1 *Node, rg *Node) {
 	}
 }
 
-func Mfree(n *Node) {
-	if n.Op == OREGISTER {
+func Mfree(n *ir.Node) {
+	if n.Op == ir.OREGISTER {
 		Regfree(n)
 	}
 }
@@ -920,7 +931,7 @@ func Mfree(n *Node) {
 // allocate a register (reusing res if possible) and generate
 //	a = n
 // The caller must call Regfree(a).
-func Cgenr(n *Node, a *Node, res *Node) {
+func Cgenr(n *ir.Node, a *ir.Node, res *ir.Node) {
 	if Debug['g'] != 0 {
 		Dump("cgenr-n", n)
 	}
@@ -936,16 +947,16 @@ func Cgenr(n *Node, a *Node, res *Node) {
 	}
 
 	switch n.Op {
-	case ONAME,
-		ODOT,
-		ODOTPTR,
-		OINDEX,
-		OCALLFUNC,
-		OCALLMETH,
-		OCALLINTER:
-		var n1 Node
+	case ir.ONAME,
+		ir.ODOT,
+		ir.ODOTPTR,
+		ir.OINDEX,
+		ir.OCALLFUNC,
+		ir.OCALLMETH,
+		ir.OCALLINTER:
+		var n1 ir.Node
 		Igen(n, &n1, res)
-		Regalloc(a, Types[Tptr], &n1)
+		Regalloc(a, ir.Types[Tptr], &n1)
 		Thearch.Gmove(&n1, a)
 		Regfree(&n1)
 
@@ -959,7 +970,7 @@ func Cgenr(n *Node, a *Node, res *Node) {
 //	a = &n
 // The caller must call Regfree(a).
 // The generated code checks that the result is not nil.
-func Agenr(n *Node, a *Node, res *Node) {
+func Agenr(n *ir.Node, a *ir.Node, res *ir.Node) {
 	if Debug['g'] != 0 {
 		Dump("\nagenr-n", n)
 	}
@@ -968,59 +979,59 @@ func Agenr(n *Node, a *Node, res *Node) {
 	nr := n.Right
 
 	switch n.Op {
-	case ODOT, ODOTPTR, OCALLFUNC, OCALLMETH, OCALLINTER:
-		var n1 Node
+	case ir.ODOT, ir.ODOTPTR, ir.OCALLFUNC, ir.OCALLMETH, ir.OCALLINTER:
+		var n1 ir.Node
 		Igen(n, &n1, res)
-		Regalloc(a, Types[Tptr], &n1)
+		Regalloc(a, ir.Types[Tptr], &n1)
 		Agen(&n1, a)
 		Regfree(&n1)
 
-	case OIND:
+	case ir.OIND:
 		Cgenr(n.Left, a, res)
 		Cgen_checknil(a)
 
-	case OINDEX:
+	case ir.OINDEX:
 		if Ctxt.Arch.Thechar == '5' {
 			var p2 *obj.Prog // to be patched to panicindex.
 			w := uint32(n.Type.Width)
 			bounded := Debug['B'] != 0 || n.Bounded
-			var n1 Node
-			var n3 Node
+			var n1 ir.Node
+			var n3 ir.Node
 			if nr.Addable {
-				var tmp Node
-				if !Isconst(nr, CTINT) {
-					Tempname(&tmp, Types[TINT32])
+				var tmp ir.Node
+				if !ir.Isconst(nr, ir.CTINT) {
+					Tempname(&tmp, ir.Types[ir.TINT32])
 				}
-				if !Isconst(nl, CTSTR) {
+				if !ir.Isconst(nl, ir.CTSTR) {
 					Agenr(nl, &n3, res)
 				}
-				if !Isconst(nr, CTINT) {
+				if !ir.Isconst(nr, ir.CTINT) {
 					p2 = Thearch.Cgenindex(nr, &tmp, bounded)
 					Regalloc(&n1, tmp.Type, nil)
 					Thearch.Gmove(&tmp, &n1)
 				}
 			} else if nl.Addable {
-				if !Isconst(nr, CTINT) {
-					var tmp Node
-					Tempname(&tmp, Types[TINT32])
+				if !ir.Isconst(nr, ir.CTINT) {
+					var tmp ir.Node
+					Tempname(&tmp, ir.Types[ir.TINT32])
 					p2 = Thearch.Cgenindex(nr, &tmp, bounded)
 					Regalloc(&n1, tmp.Type, nil)
 					Thearch.Gmove(&tmp, &n1)
 				}
 
-				if !Isconst(nl, CTSTR) {
+				if !ir.Isconst(nl, ir.CTSTR) {
 					Agenr(nl, &n3, res)
 				}
 			} else {
-				var tmp Node
-				Tempname(&tmp, Types[TINT32])
+				var tmp ir.Node
+				Tempname(&tmp, ir.Types[ir.TINT32])
 				p2 = Thearch.Cgenindex(nr, &tmp, bounded)
 				nr = &tmp
-				if !Isconst(nl, CTSTR) {
+				if !ir.Isconst(nl, ir.CTSTR) {
 					Agenr(nl, &n3, res)
 				}
 				Regalloc(&n1, tmp.Type, nil)
-				Thearch.Gins(Thearch.Optoas(OAS, tmp.Type), &tmp, &n1)
+				Thearch.Gins(Thearch.Optoas(ir.OAS, tmp.Type), &tmp, &n1)
 			}
 
 			// &a is in &n3 (allocated in res)
@@ -1028,59 +1039,59 @@ func Agenr(n *Node, a *Node, res *Node) {
 			// w is width
 
 			// constant index
-			if Isconst(nr, CTINT) {
-				if Isconst(nl, CTSTR) {
+			if ir.Isconst(nr, ir.CTINT) {
+				if ir.Isconst(nl, ir.CTSTR) {
 					Fatalf("constant string constant index")
 				}
-				v := uint64(nr.Val().U.(*Mpint).Int64())
-				var n2 Node
-				if Isslice(nl.Type) || nl.Type.Etype == TSTRING {
+				v := uint64(nr.Val().U.(*ir.Mpint).Int64())
+				var n2 ir.Node
+				if Isslice(nl.Type) || nl.Type.Etype == ir.TSTRING {
 					if Debug['B'] == 0 && !n.Bounded {
 						n1 = n3
-						n1.Op = OINDREG
-						n1.Type = Types[Tptr]
+						n1.Op = ir.OINDREG
+						n1.Type = ir.Types[Tptr]
 						n1.Xoffset = int64(Array_nel)
-						Nodconst(&n2, Types[TUINT32], int64(v))
-						p1 := Thearch.Ginscmp(OGT, Types[TUINT32], &n1, &n2, +1)
+						Nodconst(&n2, ir.Types[ir.TUINT32], int64(v))
+						p1 := Thearch.Ginscmp(ir.OGT, ir.Types[ir.TUINT32], &n1, &n2, +1)
 						Ginscall(Panicindex, -1)
 						Patch(p1, Pc)
 					}
 
 					n1 = n3
-					n1.Op = OINDREG
-					n1.Type = Types[Tptr]
+					n1.Op = ir.OINDREG
+					n1.Type = ir.Types[Tptr]
 					n1.Xoffset = int64(Array_array)
 					Thearch.Gmove(&n1, &n3)
 				}
 
-				Nodconst(&n2, Types[Tptr], int64(v*uint64(w)))
-				Thearch.Gins(Thearch.Optoas(OADD, Types[Tptr]), &n2, &n3)
+				Nodconst(&n2, ir.Types[Tptr], int64(v*uint64(w)))
+				Thearch.Gins(Thearch.Optoas(ir.OADD, ir.Types[Tptr]), &n2, &n3)
 				*a = n3
 				break
 			}
 
-			var n2 Node
-			Regalloc(&n2, Types[TINT32], &n1) // i
+			var n2 ir.Node
+			Regalloc(&n2, ir.Types[ir.TINT32], &n1) // i
 			Thearch.Gmove(&n1, &n2)
 			Regfree(&n1)
 
-			var n4 Node
+			var n4 ir.Node
 			if Debug['B'] == 0 && !n.Bounded {
 				// check bounds
-				if Isconst(nl, CTSTR) {
-					Nodconst(&n4, Types[TUINT32], int64(len(nl.Val().U.(string))))
-				} else if Isslice(nl.Type) || nl.Type.Etype == TSTRING {
+				if ir.Isconst(nl, ir.CTSTR) {
+					Nodconst(&n4, ir.Types[ir.TUINT32], int64(len(nl.Val().U.(string))))
+				} else if Isslice(nl.Type) || nl.Type.Etype == ir.TSTRING {
 					n1 = n3
-					n1.Op = OINDREG
-					n1.Type = Types[Tptr]
+					n1.Op = ir.OINDREG
+					n1.Type = ir.Types[Tptr]
 					n1.Xoffset = int64(Array_nel)
-					Regalloc(&n4, Types[TUINT32], nil)
+					Regalloc(&n4, ir.Types[ir.TUINT32], nil)
 					Thearch.Gmove(&n1, &n4)
 				} else {
-					Nodconst(&n4, Types[TUINT32], nl.Type.Bound)
+					Nodconst(&n4, ir.Types[ir.TUINT32], nl.Type.Bound)
 				}
-				p1 := Thearch.Ginscmp(OLT, Types[TUINT32], &n2, &n4, +1)
-				if n4.Op == OREGISTER {
+				p1 := Thearch.Ginscmp(ir.OLT, ir.Types[ir.TUINT32], &n2, &n4, +1)
+				if n4.Op == ir.OREGISTER {
 					Regfree(&n4)
 				}
 				if p2 != nil {
@@ -1090,15 +1101,15 @@ func Agenr(n *Node, a *Node, res *Node) {
 				Patch(p1, Pc)
 			}
 
-			if Isconst(nl, CTSTR) {
-				Regalloc(&n3, Types[Tptr], res)
-				p1 := Thearch.Gins(Thearch.Optoas(OAS, Types[Tptr]), nil, &n3)
+			if ir.Isconst(nl, ir.CTSTR) {
+				Regalloc(&n3, ir.Types[Tptr], res)
+				p1 := Thearch.Gins(Thearch.Optoas(ir.OAS, ir.Types[Tptr]), nil, &n3)
 				Datastring(nl.Val().U.(string), &p1.From)
 				p1.From.Type = obj.TYPE_ADDR
-			} else if Isslice(nl.Type) || nl.Type.Etype == TSTRING {
+			} else if Isslice(nl.Type) || nl.Type.Etype == ir.TSTRING {
 				n1 = n3
-				n1.Op = OINDREG
-				n1.Type = Types[Tptr]
+				n1.Op = ir.OINDREG
+				n1.Type = ir.Types[Tptr]
 				n1.Xoffset = int64(Array_array)
 				Thearch.Gmove(&n1, &n3)
 			}
@@ -1108,20 +1119,20 @@ func Agenr(n *Node, a *Node, res *Node) {
 			} else if Thearch.AddIndex != nil && Thearch.AddIndex(&n2, int64(w), &n3) {
 				// done by back end
 			} else if w == 1 {
-				Thearch.Gins(Thearch.Optoas(OADD, Types[Tptr]), &n2, &n3)
+				Thearch.Gins(Thearch.Optoas(ir.OADD, ir.Types[Tptr]), &n2, &n3)
 			} else {
 				if w&(w-1) == 0 {
 					// Power of 2.  Use shift.
-					Thearch.Ginscon(Thearch.Optoas(OLSH, Types[TUINT32]), int64(log2(uint64(w))), &n2)
+					Thearch.Ginscon(Thearch.Optoas(ir.OLSH, ir.Types[ir.TUINT32]), int64(log2(uint64(w))), &n2)
 				} else {
 					// Not a power of 2.  Use multiply.
-					Regalloc(&n4, Types[TUINT32], nil)
-					Nodconst(&n1, Types[TUINT32], int64(w))
+					Regalloc(&n4, ir.Types[ir.TUINT32], nil)
+					Nodconst(&n1, ir.Types[ir.TUINT32], int64(w))
 					Thearch.Gmove(&n1, &n4)
-					Thearch.Gins(Thearch.Optoas(OMUL, Types[TUINT32]), &n4, &n2)
+					Thearch.Gins(Thearch.Optoas(ir.OMUL, ir.Types[ir.TUINT32]), &n4, &n2)
 					Regfree(&n4)
 				}
-				Thearch.Gins(Thearch.Optoas(OADD, Types[Tptr]), &n2, &n3)
+				Thearch.Gins(Thearch.Optoas(ir.OADD, ir.Types[Tptr]), &n2, &n3)
 			}
 			*a = n3
 			Regfree(&n2)
@@ -1131,44 +1142,44 @@ func Agenr(n *Node, a *Node, res *Node) {
 			var p2 *obj.Prog // to be patched to panicindex.
 			w := uint32(n.Type.Width)
 			bounded := Debug['B'] != 0 || n.Bounded
-			var n3 Node
-			var tmp Node
-			var n1 Node
+			var n3 ir.Node
+			var tmp ir.Node
+			var n1 ir.Node
 			if nr.Addable {
 				// Generate &nl first, and move nr into register.
-				if !Isconst(nl, CTSTR) {
+				if !ir.Isconst(nl, ir.CTSTR) {
 					Igen(nl, &n3, res)
 				}
-				if !Isconst(nr, CTINT) {
+				if !ir.Isconst(nr, ir.CTINT) {
 					p2 = Thearch.Igenindex(nr, &tmp, bounded)
 					Regalloc(&n1, tmp.Type, nil)
 					Thearch.Gmove(&tmp, &n1)
 				}
 			} else if nl.Addable {
 				// Generate nr first, and move &nl into register.
-				if !Isconst(nr, CTINT) {
+				if !ir.Isconst(nr, ir.CTINT) {
 					p2 = Thearch.Igenindex(nr, &tmp, bounded)
 					Regalloc(&n1, tmp.Type, nil)
 					Thearch.Gmove(&tmp, &n1)
 				}
 
-				if !Isconst(nl, CTSTR) {
+				if !ir.Isconst(nl, ir.CTSTR) {
 					Igen(nl, &n3, res)
 				}
 			} else {
 				p2 = Thearch.Igenindex(nr, &tmp, bounded)
 				nr = &tmp
-				if !Isconst(nl, CTSTR) {
+				if !ir.Isconst(nl, ir.CTSTR) {
 					Igen(nl, &n3, res)
 				}
 				Regalloc(&n1, tmp.Type, nil)
-				Thearch.Gins(Thearch.Optoas(OAS, tmp.Type), &tmp, &n1)
+				Thearch.Gins(Thearch.Optoas(ir.OAS, tmp.Type), &tmp, &n1)
 			}
 
 			// For fixed array we really want the pointer in n3.
-			var n2 Node
+			var n2 ir.Node
 			if Isfixedarray(nl.Type) {
-				Regalloc(&n2, Types[Tptr], &n3)
+				Regalloc(&n2, ir.Types[Tptr], &n3)
 				Agen(&n3, &n2)
 				Regfree(&n3)
 				n3 = n2
@@ -1180,43 +1191,43 @@ func Agenr(n *Node, a *Node, res *Node) {
 			// w is width
 
 			// constant index
-			if Isconst(nr, CTINT) {
-				if Isconst(nl, CTSTR) {
+			if ir.Isconst(nr, ir.CTINT) {
+				if ir.Isconst(nl, ir.CTSTR) {
 					Fatalf("constant string constant index") // front end should handle
 				}
-				v := uint64(nr.Val().U.(*Mpint).Int64())
-				if Isslice(nl.Type) || nl.Type.Etype == TSTRING {
+				v := uint64(nr.Val().U.(*ir.Mpint).Int64())
+				if Isslice(nl.Type) || nl.Type.Etype == ir.TSTRING {
 					if Debug['B'] == 0 && !n.Bounded {
 						nlen := n3
-						nlen.Type = Types[TUINT32]
+						nlen.Type = ir.Types[ir.TUINT32]
 						nlen.Xoffset += int64(Array_nel)
-						Nodconst(&n2, Types[TUINT32], int64(v))
-						p1 := Thearch.Ginscmp(OGT, Types[TUINT32], &nlen, &n2, +1)
+						Nodconst(&n2, ir.Types[ir.TUINT32], int64(v))
+						p1 := Thearch.Ginscmp(ir.OGT, ir.Types[ir.TUINT32], &nlen, &n2, +1)
 						Ginscall(Panicindex, -1)
 						Patch(p1, Pc)
 					}
 				}
 
 				// Load base pointer in n2 = n3.
-				Regalloc(&n2, Types[Tptr], &n3)
+				Regalloc(&n2, ir.Types[Tptr], &n3)
 
-				n3.Type = Types[Tptr]
+				n3.Type = ir.Types[Tptr]
 				n3.Xoffset += int64(Array_array)
 				Thearch.Gmove(&n3, &n2)
 				Regfree(&n3)
 				if v*uint64(w) != 0 {
-					Nodconst(&n1, Types[Tptr], int64(v*uint64(w)))
-					Thearch.Gins(Thearch.Optoas(OADD, Types[Tptr]), &n1, &n2)
+					Nodconst(&n1, ir.Types[Tptr], int64(v*uint64(w)))
+					Thearch.Gins(Thearch.Optoas(ir.OADD, ir.Types[Tptr]), &n1, &n2)
 				}
 				*a = n2
 				break
 			}
 
 			// i is in register n1, extend to 32 bits.
-			t := Types[TUINT32]
+			t := ir.Types[ir.TUINT32]
 
 			if Issigned[n1.Type.Etype] {
-				t = Types[TINT32]
+				t = ir.Types[ir.TINT32]
 			}
 
 			Regalloc(&n2, t, &n1) // i
@@ -1225,12 +1236,12 @@ func Agenr(n *Node, a *Node, res *Node) {
 
 			if Debug['B'] == 0 && !n.Bounded {
 				// check bounds
-				t := Types[TUINT32]
+				t := ir.Types[ir.TUINT32]
 
-				var nlen Node
-				if Isconst(nl, CTSTR) {
+				var nlen ir.Node
+				if ir.Isconst(nl, ir.CTSTR) {
 					Nodconst(&nlen, t, int64(len(nl.Val().U.(string))))
-				} else if Isslice(nl.Type) || nl.Type.Etype == TSTRING {
+				} else if Isslice(nl.Type) || nl.Type.Etype == ir.TSTRING {
 					nlen = n3
 					nlen.Type = t
 					nlen.Xoffset += int64(Array_nel)
@@ -1238,7 +1249,7 @@ func Agenr(n *Node, a *Node, res *Node) {
 					Nodconst(&nlen, t, nl.Type.Bound)
 				}
 
-				p1 := Thearch.Ginscmp(OLT, t, &n2, &nlen, +1)
+				p1 := Thearch.Ginscmp(ir.OLT, t, &n2, &nlen, +1)
 				if p2 != nil {
 					Patch(p2, Pc)
 				}
@@ -1246,20 +1257,20 @@ func Agenr(n *Node, a *Node, res *Node) {
 				Patch(p1, Pc)
 			}
 
-			if Isconst(nl, CTSTR) {
-				Regalloc(&n3, Types[Tptr], res)
-				p1 := Thearch.Gins(Thearch.Optoas(OAS, Types[Tptr]), nil, &n3)
+			if ir.Isconst(nl, ir.CTSTR) {
+				Regalloc(&n3, ir.Types[Tptr], res)
+				p1 := Thearch.Gins(Thearch.Optoas(ir.OAS, ir.Types[Tptr]), nil, &n3)
 				Datastring(nl.Val().U.(string), &p1.From)
 				p1.From.Type = obj.TYPE_ADDR
-				Thearch.Gins(Thearch.Optoas(OADD, n3.Type), &n2, &n3)
+				Thearch.Gins(Thearch.Optoas(ir.OADD, n3.Type), &n2, &n3)
 				goto indexdone1
 			}
 
 			// Load base pointer in n3.
-			Regalloc(&tmp, Types[Tptr], &n3)
+			Regalloc(&tmp, ir.Types[Tptr], &n3)
 
-			if Isslice(nl.Type) || nl.Type.Etype == TSTRING {
-				n3.Type = Types[Tptr]
+			if Isslice(nl.Type) || nl.Type.Etype == ir.TSTRING {
+				n3.Type = ir.Types[Tptr]
 				n3.Xoffset += int64(Array_array)
 				Thearch.Gmove(&n3, &tmp)
 			}
@@ -1272,16 +1283,16 @@ func Agenr(n *Node, a *Node, res *Node) {
 			} else if Thearch.AddIndex != nil && Thearch.AddIndex(&n2, int64(w), &n3) {
 				// done by back end
 			} else if w == 1 {
-				Thearch.Gins(Thearch.Optoas(OADD, Types[Tptr]), &n2, &n3)
+				Thearch.Gins(Thearch.Optoas(ir.OADD, ir.Types[Tptr]), &n2, &n3)
 			} else {
 				if w&(w-1) == 0 {
 					// Power of 2.  Use shift.
-					Thearch.Ginscon(Thearch.Optoas(OLSH, Types[TUINT32]), int64(log2(uint64(w))), &n2)
+					Thearch.Ginscon(Thearch.Optoas(ir.OLSH, ir.Types[ir.TUINT32]), int64(log2(uint64(w))), &n2)
 				} else {
 					// Not a power of 2.  Use multiply.
-					Thearch.Ginscon(Thearch.Optoas(OMUL, Types[TUINT32]), int64(w), &n2)
+					Thearch.Ginscon(Thearch.Optoas(ir.OMUL, ir.Types[ir.TUINT32]), int64(w), &n2)
 				}
-				Thearch.Gins(Thearch.Optoas(OADD, Types[Tptr]), &n2, &n3)
+				Thearch.Gins(Thearch.Optoas(ir.OADD, ir.Types[Tptr]), &n2, &n3)
 			}
 
 		indexdone1:
@@ -1294,26 +1305,26 @@ func Agenr(n *Node, a *Node, res *Node) {
 		w := uint64(n.Type.Width)
 
 		// Generate the non-addressable child first.
-		var n3 Node
-		var nlen Node
-		var tmp Node
-		var n1 Node
+		var n3 ir.Node
+		var nlen ir.Node
+		var tmp ir.Node
+		var n1 ir.Node
 		if nr.Addable {
 			goto irad
 		}
 		if nl.Addable {
 			Cgenr(nr, &n1, nil)
-			if !Isconst(nl, CTSTR) {
+			if !ir.Isconst(nl, ir.CTSTR) {
 				if Isfixedarray(nl.Type) {
 					Agenr(nl, &n3, res)
 				} else {
 					Igen(nl, &nlen, res)
 					freelen = 1
-					nlen.Type = Types[Tptr]
+					nlen.Type = ir.Types[Tptr]
 					nlen.Xoffset += int64(Array_array)
-					Regalloc(&n3, Types[Tptr], res)
+					Regalloc(&n3, ir.Types[Tptr], res)
 					Thearch.Gmove(&nlen, &n3)
-					nlen.Type = Types[Simtype[TUINT]]
+					nlen.Type = ir.Types[Simtype[ir.TUINT]]
 					nlen.Xoffset += int64(Array_nel) - int64(Array_array)
 				}
 			}
@@ -1326,38 +1337,38 @@ func Agenr(n *Node, a *Node, res *Node) {
 		nr = &tmp
 
 	irad:
-		if !Isconst(nl, CTSTR) {
+		if !ir.Isconst(nl, ir.CTSTR) {
 			if Isfixedarray(nl.Type) {
 				Agenr(nl, &n3, res)
 			} else {
 				if !nl.Addable {
-					if res != nil && res.Op == OREGISTER { // give up res, which we don't need yet.
+					if res != nil && res.Op == ir.OREGISTER { // give up res, which we don't need yet.
 						Regfree(res)
 					}
 
 					// igen will need an addressable node.
-					var tmp2 Node
+					var tmp2 ir.Node
 					Tempname(&tmp2, nl.Type)
 					Cgen(nl, &tmp2)
 					nl = &tmp2
 
-					if res != nil && res.Op == OREGISTER { // reacquire res
+					if res != nil && res.Op == ir.OREGISTER { // reacquire res
 						Regrealloc(res)
 					}
 				}
 
 				Igen(nl, &nlen, res)
 				freelen = 1
-				nlen.Type = Types[Tptr]
+				nlen.Type = ir.Types[Tptr]
 				nlen.Xoffset += int64(Array_array)
-				Regalloc(&n3, Types[Tptr], res)
+				Regalloc(&n3, ir.Types[Tptr], res)
 				Thearch.Gmove(&nlen, &n3)
-				nlen.Type = Types[Simtype[TUINT]]
+				nlen.Type = ir.Types[Simtype[ir.TUINT]]
 				nlen.Xoffset += int64(Array_nel) - int64(Array_array)
 			}
 		}
 
-		if !Isconst(nr, CTINT) {
+		if !ir.Isconst(nr, ir.CTINT) {
 			Cgenr(nr, &n1, nil)
 		}
 
@@ -1370,14 +1381,14 @@ func Agenr(n *Node, a *Node, res *Node) {
 
 		// constant index
 	index:
-		if Isconst(nr, CTINT) {
-			if Isconst(nl, CTSTR) {
+		if ir.Isconst(nr, ir.CTINT) {
+			if ir.Isconst(nl, ir.CTSTR) {
 				Fatalf("constant string constant index") // front end should handle
 			}
-			v := uint64(nr.Val().U.(*Mpint).Int64())
-			if Isslice(nl.Type) || nl.Type.Etype == TSTRING {
+			v := uint64(nr.Val().U.(*ir.Mpint).Int64())
+			if Isslice(nl.Type) || nl.Type.Etype == ir.TSTRING {
 				if Debug['B'] == 0 && !n.Bounded {
-					p1 := Thearch.Ginscmp(OGT, Types[Simtype[TUINT]], &nlen, Nodintconst(int64(v)), +1)
+					p1 := Thearch.Ginscmp(ir.OGT, ir.Types[Simtype[ir.TUINT]], &nlen, Nodintconst(int64(v)), +1)
 					Ginscall(Panicindex, -1)
 					Patch(p1, Pc)
 				}
@@ -1386,50 +1397,50 @@ func Agenr(n *Node, a *Node, res *Node) {
 			}
 
 			if v*w != 0 {
-				Thearch.Ginscon(Thearch.Optoas(OADD, Types[Tptr]), int64(v*w), &n3)
+				Thearch.Ginscon(Thearch.Optoas(ir.OADD, ir.Types[Tptr]), int64(v*w), &n3)
 			}
 			*a = n3
 			break
 		}
 
 		// type of the index
-		t := Types[TUINT64]
+		t := ir.Types[ir.TUINT64]
 
 		if Issigned[n1.Type.Etype] {
-			t = Types[TINT64]
+			t = ir.Types[ir.TINT64]
 		}
 
-		var n2 Node
+		var n2 ir.Node
 		Regalloc(&n2, t, &n1) // i
 		Thearch.Gmove(&n1, &n2)
 		Regfree(&n1)
 
 		if Debug['B'] == 0 && !n.Bounded {
 			// check bounds
-			t = Types[Simtype[TUINT]]
+			t = ir.Types[Simtype[ir.TUINT]]
 
 			if Is64(nr.Type) {
-				t = Types[TUINT64]
+				t = ir.Types[ir.TUINT64]
 			}
-			if Isconst(nl, CTSTR) {
+			if ir.Isconst(nl, ir.CTSTR) {
 				Nodconst(&nlen, t, int64(len(nl.Val().U.(string))))
-			} else if Isslice(nl.Type) || nl.Type.Etype == TSTRING {
+			} else if Isslice(nl.Type) || nl.Type.Etype == ir.TSTRING {
 				// nlen already initialized
 			} else {
 				Nodconst(&nlen, t, nl.Type.Bound)
 			}
 
-			p1 := Thearch.Ginscmp(OLT, t, &n2, &nlen, +1)
+			p1 := Thearch.Ginscmp(ir.OLT, t, &n2, &nlen, +1)
 			Ginscall(Panicindex, -1)
 			Patch(p1, Pc)
 		}
 
-		if Isconst(nl, CTSTR) {
-			Regalloc(&n3, Types[Tptr], res)
-			p1 := Thearch.Gins(Thearch.Optoas(OAS, n3.Type), nil, &n3) // XXX was LEAQ!
+		if ir.Isconst(nl, ir.CTSTR) {
+			Regalloc(&n3, ir.Types[Tptr], res)
+			p1 := Thearch.Gins(Thearch.Optoas(ir.OAS, n3.Type), nil, &n3) // XXX was LEAQ!
 			Datastring(nl.Val().U.(string), &p1.From)
 			p1.From.Type = obj.TYPE_ADDR
-			Thearch.Gins(Thearch.Optoas(OADD, n3.Type), &n2, &n3)
+			Thearch.Gins(Thearch.Optoas(ir.OADD, n3.Type), &n2, &n3)
 			goto indexdone
 		}
 
@@ -1438,16 +1449,16 @@ func Agenr(n *Node, a *Node, res *Node) {
 		} else if Thearch.AddIndex != nil && Thearch.AddIndex(&n2, int64(w), &n3) {
 			// done by back end
 		} else if w == 1 {
-			Thearch.Gins(Thearch.Optoas(OADD, Types[Tptr]), &n2, &n3)
+			Thearch.Gins(Thearch.Optoas(ir.OADD, ir.Types[Tptr]), &n2, &n3)
 		} else {
 			if w&(w-1) == 0 {
 				// Power of 2.  Use shift.
-				Thearch.Ginscon(Thearch.Optoas(OLSH, t), int64(log2(w)), &n2)
+				Thearch.Ginscon(Thearch.Optoas(ir.OLSH, t), int64(log2(w)), &n2)
 			} else {
 				// Not a power of 2.  Use multiply.
-				Thearch.Ginscon(Thearch.Optoas(OMUL, t), int64(w), &n2)
+				Thearch.Ginscon(Thearch.Optoas(ir.OMUL, t), int64(w), &n2)
 			}
-			Thearch.Gins(Thearch.Optoas(OADD, Types[Tptr]), &n2, &n3)
+			Thearch.Gins(Thearch.Optoas(ir.OADD, ir.Types[Tptr]), &n2, &n3)
 		}
 
 	indexdone:
@@ -1458,7 +1469,7 @@ func Agenr(n *Node, a *Node, res *Node) {
 		}
 
 	default:
-		Regalloc(a, Types[Tptr], res)
+		Regalloc(a, ir.Types[Tptr], res)
 		Agen(n, a)
 	}
 }
@@ -1475,7 +1486,7 @@ func log2(n uint64) int {
 // generate:
 //	res = &n;
 // The generated code checks that the result is not nil.
-func Agen(n *Node, res *Node) {
+func Agen(n *ir.Node, res *ir.Node) {
 	if Debug['g'] != 0 {
 		Dump("\nagen-res", res)
 		Dump("agen-r", n)
@@ -1485,52 +1496,52 @@ func Agen(n *Node, res *Node) {
 		return
 	}
 
-	for n.Op == OCONVNOP {
+	for n.Op == ir.OCONVNOP {
 		n = n.Left
 	}
 
-	if Isconst(n, CTNIL) && n.Type.Width > int64(Widthptr) {
+	if ir.Isconst(n, ir.CTNIL) && n.Type.Width > int64(Widthptr) {
 		// Use of a nil interface or nil slice.
 		// Create a temporary we can take the address of and read.
 		// The generated code is just going to panic, so it need not
 		// be terribly efficient. See issue 3670.
-		var n1 Node
+		var n1 ir.Node
 		Tempname(&n1, n.Type)
 
 		Gvardef(&n1)
 		Thearch.Clearfat(&n1)
-		var n2 Node
-		Regalloc(&n2, Types[Tptr], res)
-		var n3 Node
-		n3.Op = OADDR
+		var n2 ir.Node
+		Regalloc(&n2, ir.Types[Tptr], res)
+		var n3 ir.Node
+		n3.Op = ir.OADDR
 		n3.Left = &n1
-		Thearch.Gins(Thearch.Optoas(OAS, Types[Tptr]), &n3, &n2)
+		Thearch.Gins(Thearch.Optoas(ir.OAS, ir.Types[Tptr]), &n3, &n2)
 		Thearch.Gmove(&n2, res)
 		Regfree(&n2)
 		return
 	}
 
-	if n.Op == OINDREG && n.Xoffset == 0 {
+	if n.Op == ir.OINDREG && n.Xoffset == 0 {
 		// Generate MOVW R0, R1 instead of MOVW $0(R0), R1.
 		// This allows better move propagation in the back ends
 		// (and maybe it helps the processor).
 		n1 := *n
-		n1.Op = OREGISTER
+		n1.Op = ir.OREGISTER
 		n1.Type = res.Type
 		Thearch.Gmove(&n1, res)
 		return
 	}
 
 	if n.Addable {
-		if n.Op == OREGISTER {
+		if n.Op == ir.OREGISTER {
 			Fatalf("agen OREGISTER")
 		}
-		var n1 Node
-		n1.Op = OADDR
+		var n1 ir.Node
+		n1.Op = ir.OADDR
 		n1.Left = n
-		var n2 Node
-		Regalloc(&n2, Types[Tptr], res)
-		Thearch.Gins(Thearch.Optoas(OAS, Types[Tptr]), &n1, &n2)
+		var n2 ir.Node
+		Regalloc(&n2, ir.Types[Tptr], res)
+		Thearch.Gins(Thearch.Optoas(ir.OAS, ir.Types[Tptr]), &n1, &n2)
 		Thearch.Gmove(&n2, res)
 		Regfree(&n2)
 		return
@@ -1540,33 +1551,33 @@ func Agen(n *Node, res *Node) {
 
 	switch n.Op {
 	default:
-		Fatalf("agen: unknown op %v", Nconv(n, FmtShort|FmtSign))
+		Fatalf("agen: unknown op %v", Nconv(n, ir.FmtShort|ir.FmtSign))
 
-	case OCALLMETH:
+	case ir.OCALLMETH:
 		cgen_callmeth(n, 0)
 		cgen_aret(n, res)
 
-	case OCALLINTER:
+	case ir.OCALLINTER:
 		cgen_callinter(n, res, 0)
 		cgen_aret(n, res)
 
-	case OCALLFUNC:
+	case ir.OCALLFUNC:
 		cgen_call(n, 0)
 		cgen_aret(n, res)
 
-	case OEFACE, ODOTTYPE, OSLICE, OSLICEARR, OSLICESTR, OSLICE3, OSLICE3ARR:
-		var n1 Node
+	case ir.OEFACE, ir.ODOTTYPE, ir.OSLICE, ir.OSLICEARR, ir.OSLICESTR, ir.OSLICE3, ir.OSLICE3ARR:
+		var n1 ir.Node
 		Tempname(&n1, n.Type)
 		Cgen(n, &n1)
 		Agen(&n1, res)
 
-	case OINDEX:
-		var n1 Node
+	case ir.OINDEX:
+		var n1 ir.Node
 		Agenr(n, &n1, res)
 		Thearch.Gmove(&n1, res)
 		Regfree(&n1)
 
-	case ONAME:
+	case ir.ONAME:
 		// should only get here with names in this func.
 		if n.Name.Funcdepth > 0 && n.Name.Funcdepth != Funcdepth {
 			Dump("bad agen", n)
@@ -1574,7 +1585,7 @@ func Agen(n *Node, res *Node) {
 		}
 
 		// should only get here for heap vars or paramref
-		if n.Class&PHEAP == 0 && n.Class != PPARAMREF {
+		if n.Class&ir.PHEAP == 0 && n.Class != ir.PPARAMREF {
 			Dump("bad agen", n)
 			Fatalf("agen: bad ONAME class %#x", n.Class)
 		}
@@ -1584,17 +1595,17 @@ func Agen(n *Node, res *Node) {
 			addOffset(res, n.Xoffset)
 		}
 
-	case OIND:
+	case ir.OIND:
 		Cgen(nl, res)
 		Cgen_checknil(res)
 
-	case ODOT:
+	case ir.ODOT:
 		Agen(nl, res)
 		if n.Xoffset != 0 {
 			addOffset(res, n.Xoffset)
 		}
 
-	case ODOTPTR:
+	case ir.ODOTPTR:
 		Cgen(nl, res)
 		Cgen_checknil(res)
 		if n.Xoffset != 0 {
@@ -1603,18 +1614,18 @@ func Agen(n *Node, res *Node) {
 	}
 }
 
-func addOffset(res *Node, offset int64) {
+func addOffset(res *ir.Node, offset int64) {
 	if Ctxt.Arch.Thechar == '6' || Ctxt.Arch.Thechar == '8' {
-		Thearch.Gins(Thearch.Optoas(OADD, Types[Tptr]), Nodintconst(offset), res)
+		Thearch.Gins(Thearch.Optoas(ir.OADD, ir.Types[Tptr]), Nodintconst(offset), res)
 		return
 	}
 
-	var n1, n2 Node
-	Regalloc(&n1, Types[Tptr], nil)
+	var n1, n2 ir.Node
+	Regalloc(&n1, ir.Types[Tptr], nil)
 	Thearch.Gmove(res, &n1)
-	Regalloc(&n2, Types[Tptr], nil)
-	Thearch.Gins(Thearch.Optoas(OAS, Types[Tptr]), Nodintconst(offset), &n2)
-	Thearch.Gins(Thearch.Optoas(OADD, Types[Tptr]), &n2, &n1)
+	Regalloc(&n2, ir.Types[Tptr], nil)
+	Thearch.Gins(Thearch.Optoas(ir.OAS, ir.Types[Tptr]), Nodintconst(offset), &n2)
+	Thearch.Gins(Thearch.Optoas(ir.OADD, ir.Types[Tptr]), &n2, &n1)
 	Thearch.Gmove(&n1, res)
 	Regfree(&n1)
 	Regfree(&n2)
@@ -1626,20 +1637,20 @@ func addOffset(res *Node, offset int64) {
 // be a newly allocated register. The caller must call Regfree(a)
 // to free r when the address is no longer needed.
 // The generated code ensures that &n is not nil.
-func Igen(n *Node, a *Node, res *Node) {
+func Igen(n *ir.Node, a *ir.Node, res *ir.Node) {
 	if Debug['g'] != 0 {
 		Dump("\nigen-n", n)
 	}
 
 	switch n.Op {
-	case ONAME:
-		if (n.Class&PHEAP != 0) || n.Class == PPARAMREF {
+	case ir.ONAME:
+		if (n.Class&ir.PHEAP != 0) || n.Class == ir.PPARAMREF {
 			break
 		}
 		*a = *n
 		return
 
-	case OINDREG:
+	case ir.OINDREG:
 		// Increase the refcount of the register so that igen's caller
 		// has to call Regfree.
 		if n.Reg != int16(Thearch.REGSP) {
@@ -1648,37 +1659,37 @@ func Igen(n *Node, a *Node, res *Node) {
 		*a = *n
 		return
 
-	case ODOT:
+	case ir.ODOT:
 		Igen(n.Left, a, res)
 		a.Xoffset += n.Xoffset
 		a.Type = n.Type
 		Fixlargeoffset(a)
 		return
 
-	case ODOTPTR:
+	case ir.ODOTPTR:
 		Cgenr(n.Left, a, res)
 		Cgen_checknil(a)
-		a.Op = OINDREG
+		a.Op = ir.OINDREG
 		a.Xoffset += n.Xoffset
 		a.Type = n.Type
 		Fixlargeoffset(a)
 		return
 
-	case OCALLFUNC, OCALLMETH, OCALLINTER:
+	case ir.OCALLFUNC, ir.OCALLMETH, ir.OCALLINTER:
 		switch n.Op {
-		case OCALLFUNC:
+		case ir.OCALLFUNC:
 			cgen_call(n, 0)
 
-		case OCALLMETH:
+		case ir.OCALLMETH:
 			cgen_callmeth(n, 0)
 
-		case OCALLINTER:
+		case ir.OCALLINTER:
 			cgen_callinter(n, nil, 0)
 		}
 
 		fp := n.Left.Type.Results().Field(0)
-		*a = Node{}
-		a.Op = OINDREG
+		*a = ir.Node{}
+		a.Op = ir.OINDREG
 		a.Reg = int16(Thearch.REGSP)
 		a.Addable = true
 		a.Xoffset = fp.Width + Ctxt.FixedFrameSize()
@@ -1689,26 +1700,26 @@ func Igen(n *Node, a *Node, res *Node) {
 	// put the offset in the addressing.
 	// Could do the same for slice except that we need
 	// to use the real index for the bounds checking.
-	case OINDEX:
+	case ir.OINDEX:
 		if Isfixedarray(n.Left.Type) || (Isptr[n.Left.Type.Etype] && Isfixedarray(n.Left.Left.Type)) {
-			if Isconst(n.Right, CTINT) {
+			if ir.Isconst(n.Right, ir.CTINT) {
 				// Compute &a.
 				if !Isptr[n.Left.Type.Etype] {
 					Igen(n.Left, a, res)
 				} else {
-					var n1 Node
+					var n1 ir.Node
 					Igen(n.Left, &n1, res)
 					Cgen_checknil(&n1)
-					Regalloc(a, Types[Tptr], res)
+					Regalloc(a, ir.Types[Tptr], res)
 					Thearch.Gmove(&n1, a)
 					Regfree(&n1)
-					a.Op = OINDREG
+					a.Op = ir.OINDREG
 				}
 
 				// Compute &a[i] as &a + i*width.
 				a.Type = n.Type
 
-				a.Xoffset += n.Right.Val().U.(*Mpint).Int64() * n.Type.Width
+				a.Xoffset += n.Right.Val().U.(*ir.Mpint).Int64() * n.Type.Width
 				Fixlargeoffset(a)
 				return
 			}
@@ -1716,7 +1727,7 @@ func Igen(n *Node, a *Node, res *Node) {
 	}
 
 	Agenr(n, a, res)
-	a.Op = OINDREG
+	a.Op = ir.OINDREG
 	a.Type = n.Type
 }
 
@@ -1725,13 +1736,13 @@ func Igen(n *Node, a *Node, res *Node) {
 // 	if n == wantTrue {
 // 		goto to
 // 	}
-func Bgen(n *Node, wantTrue bool, likely int, to *obj.Prog) {
+func Bgen(n *ir.Node, wantTrue bool, likely int, to *obj.Prog) {
 	bgenx(n, nil, wantTrue, likely, to)
 }
 
 // Bvgen generates code for calculating boolean values:
 // 	res = n == wantTrue
-func Bvgen(n, res *Node, wantTrue bool) {
+func Bvgen(n, res *ir.Node, wantTrue bool) {
 	if Thearch.Ginsboolval == nil {
 		// Direct value generation not implemented for this architecture.
 		// Implement using jumps.
@@ -1748,7 +1759,7 @@ func Bvgen(n, res *Node, wantTrue bool) {
 // 		res = 0
 // 	}
 // geninit controls whether n's Ninit is generated.
-func bvgenjump(n, res *Node, wantTrue, geninit bool) {
+func bvgenjump(n, res *ir.Node, wantTrue, geninit bool) {
 	init := n.Ninit
 	if !geninit {
 		n.Ninit.Set(nil)
@@ -1767,7 +1778,7 @@ func bvgenjump(n, res *Node, wantTrue, geninit bool) {
 // bgenx is the backend for Bgen and Bvgen.
 // If res is nil, it generates a branch.
 // Otherwise, it generates a boolean value.
-func bgenx(n, res *Node, wantTrue bool, likely int, to *obj.Prog) {
+func bgenx(n, res *ir.Node, wantTrue bool, likely int, to *obj.Prog) {
 	if Debug['g'] != 0 {
 		fmt.Printf("\nbgenx wantTrue=%t likely=%d to=%v\n", wantTrue, likely, to)
 		Dump("n", n)
@@ -1783,17 +1794,17 @@ func bgenx(n, res *Node, wantTrue bool, likely int, to *obj.Prog) {
 	Genlist(n.Ninit)
 
 	if n.Type == nil {
-		n = convlit(n, Types[TBOOL])
+		n = convlit(n, ir.Types[ir.TBOOL])
 		if n.Type == nil {
 			return
 		}
 	}
 
-	if n.Type.Etype != TBOOL {
+	if n.Type.Etype != ir.TBOOL {
 		Fatalf("bgen: bad type %v for %v", n.Type, Oconv(n.Op, 0))
 	}
 
-	for n.Op == OCONVNOP {
+	for n.Op == ir.OCONVNOP {
 		n = n.Left
 		Genlist(n.Ninit)
 	}
@@ -1812,19 +1823,19 @@ func bgenx(n, res *Node, wantTrue bool, likely int, to *obj.Prog) {
 		if genval {
 			Cgen(n, res)
 			if !wantTrue {
-				Thearch.Gins(Thearch.Optoas(OXOR, Types[TUINT8]), Nodintconst(1), res)
+				Thearch.Gins(Thearch.Optoas(ir.OXOR, ir.Types[ir.TUINT8]), Nodintconst(1), res)
 			}
 			return
 		}
 
-		var tmp Node
+		var tmp ir.Node
 		Regalloc(&tmp, n.Type, nil)
 		Cgen(n, &tmp)
 		bgenNonZero(&tmp, nil, wantTrue, likely, to)
 		Regfree(&tmp)
 		return
 
-	case ONAME:
+	case ir.ONAME:
 		if genval {
 			// 5g, 7g, and 9g might need a temporary or other help here,
 			// but they don't support direct generation of a bool value yet.
@@ -1835,7 +1846,7 @@ func bgenx(n, res *Node, wantTrue bool, likely int, to *obj.Prog) {
 			}
 			Cgen(n, res)
 			if !wantTrue {
-				Thearch.Gins(Thearch.Optoas(OXOR, Types[TUINT8]), Nodintconst(1), res)
+				Thearch.Gins(Thearch.Optoas(ir.OXOR, ir.Types[ir.TUINT8]), Nodintconst(1), res)
 			}
 			return
 		}
@@ -1845,17 +1856,17 @@ func bgenx(n, res *Node, wantTrue bool, likely int, to *obj.Prog) {
 			bgenNonZero(n, nil, wantTrue, likely, to)
 			return
 		}
-		var tmp Node
+		var tmp ir.Node
 		Regalloc(&tmp, n.Type, nil)
 		Cgen(n, &tmp)
 		bgenNonZero(&tmp, nil, wantTrue, likely, to)
 		Regfree(&tmp)
 		return
 
-	case OLITERAL:
+	case ir.OLITERAL:
 		// n is a constant.
-		if !Isconst(n, CTBOOL) {
-			Fatalf("bgen: non-bool const %v\n", Nconv(n, FmtLong))
+		if !ir.Isconst(n, ir.CTBOOL) {
+			Fatalf("bgen: non-bool const %v\n", Nconv(n, ir.FmtLong))
 		}
 		if genval {
 			Cgen(Nodbool(wantTrue == n.Val().U.(bool)), res)
@@ -1867,8 +1878,8 @@ func bgenx(n, res *Node, wantTrue bool, likely int, to *obj.Prog) {
 		}
 		return
 
-	case OANDAND, OOROR:
-		and := (n.Op == OANDAND) == wantTrue
+	case ir.OANDAND, ir.OOROR:
+		and := (n.Op == ir.OANDAND) == wantTrue
 		if genval {
 			p1 := Gbranch(obj.AJMP, nil, 0)
 			p2 := Gbranch(obj.AJMP, nil, 0)
@@ -1897,14 +1908,14 @@ func bgenx(n, res *Node, wantTrue bool, likely int, to *obj.Prog) {
 		}
 		return
 
-	case ONOT: // unary
+	case ir.ONOT: // unary
 		if n.Left == nil || n.Left.Type == nil {
 			return
 		}
 		bgenx(n.Left, res, !wantTrue, likely, to)
 		return
 
-	case OEQ, ONE, OLT, OGT, OLE, OGE:
+	case ir.OEQ, ir.ONE, ir.OLT, ir.OGT, ir.OLE, ir.OGE:
 		if n.Left == nil || n.Left.Type == nil || n.Right == nil || n.Right.Type == nil {
 			return
 		}
@@ -1922,7 +1933,7 @@ func bgenx(n, res *Node, wantTrue bool, likely int, to *obj.Prog) {
 			n.Ninit.Set(nil)
 			if genval {
 				bgenx(n, res, true, likely, to)
-				Thearch.Gins(Thearch.Optoas(OXOR, Types[TUINT8]), Nodintconst(1), res) // res = !res
+				Thearch.Gins(Thearch.Optoas(ir.OXOR, ir.Types[ir.TUINT8]), Nodintconst(1), res) // res = !res
 				n.Ninit.Set(ll.Slice())
 				return
 			}
@@ -1941,14 +1952,14 @@ func bgenx(n, res *Node, wantTrue bool, likely int, to *obj.Prog) {
 	wantTrue = true
 
 	// make simplest on right
-	if nl.Op == OLITERAL || (nl.Ullman < nr.Ullman && nl.Ullman < UINF) {
+	if nl.Op == ir.OLITERAL || (nl.Ullman < nr.Ullman && nl.Ullman < UINF) {
 		op = Brrev(op)
 		nl, nr = nr, nl
 	}
 
 	if Isslice(nl.Type) || Isinter(nl.Type) {
 		// front end should only leave cmp to literal nil
-		if (op != OEQ && op != ONE) || nr.Op != OLITERAL {
+		if (op != ir.OEQ && op != ir.ONE) || nr.Op != ir.OLITERAL {
 			if Isslice(nl.Type) {
 				Yyerror("illegal slice comparison")
 			} else {
@@ -1957,17 +1968,17 @@ func bgenx(n, res *Node, wantTrue bool, likely int, to *obj.Prog) {
 			return
 		}
 
-		var ptr Node
+		var ptr ir.Node
 		Igen(nl, &ptr, nil)
 		if Isslice(nl.Type) {
 			ptr.Xoffset += int64(Array_array)
 		}
-		ptr.Type = Types[Tptr]
-		var tmp Node
+		ptr.Type = ir.Types[Tptr]
+		var tmp ir.Node
 		Regalloc(&tmp, ptr.Type, &ptr)
 		Cgen(&ptr, &tmp)
 		Regfree(&ptr)
-		bgenNonZero(&tmp, res, op == OEQ != wantTrue, likely, to)
+		bgenNonZero(&tmp, res, op == ir.OEQ != wantTrue, likely, to)
 		Regfree(&tmp)
 		return
 	}
@@ -1983,7 +1994,7 @@ func bgenx(n, res *Node, wantTrue bool, likely int, to *obj.Prog) {
 			bvgenjump(n, res, wantTrue, false)
 			return
 		}
-		if !nl.Addable || Isconst(nl, CTINT) {
+		if !nl.Addable || ir.Isconst(nl, ir.CTINT) {
 			nl = CgenTemp(nl)
 		}
 		if !nr.Addable {
@@ -1994,17 +2005,17 @@ func bgenx(n, res *Node, wantTrue bool, likely int, to *obj.Prog) {
 	}
 
 	if nr.Ullman >= UINF {
-		var n1 Node
+		var n1 ir.Node
 		Regalloc(&n1, nl.Type, nil)
 		Cgen(nl, &n1)
 		nl = &n1
 
-		var tmp Node
+		var tmp ir.Node
 		Tempname(&tmp, nl.Type)
 		Thearch.Gmove(&n1, &tmp)
 		Regfree(&n1)
 
-		var n2 Node
+		var n2 ir.Node
 		Regalloc(&n2, nr.Type, nil)
 		Cgen(nr, &n2)
 		nr = &n2
@@ -2014,7 +2025,7 @@ func bgenx(n, res *Node, wantTrue bool, likely int, to *obj.Prog) {
 		Regfree(&n1)
 		Regfree(&n2)
 	} else {
-		var n1 Node
+		var n1 ir.Node
 		if !nl.Addable && Ctxt.Arch.Thechar == '8' {
 			Tempname(&n1, nl.Type)
 		} else {
@@ -2025,7 +2036,7 @@ func bgenx(n, res *Node, wantTrue bool, likely int, to *obj.Prog) {
 		nl = &n1
 
 		if Smallintconst(nr) && Ctxt.Arch.Thechar != '0' && Ctxt.Arch.Thechar != '9' {
-			Thearch.Gins(Thearch.Optoas(OCMP, nr.Type), nl, nr)
+			Thearch.Gins(Thearch.Optoas(ir.OCMP, nr.Type), nl, nr)
 			bins(nr.Type, res, op, likely, to)
 			return
 		}
@@ -2034,7 +2045,7 @@ func bgenx(n, res *Node, wantTrue bool, likely int, to *obj.Prog) {
 			nr = CgenTemp(nr)
 		}
 
-		var n2 Node
+		var n2 ir.Node
 		Regalloc(&n2, nr.Type, nil)
 		Cgen(nr, &n2)
 		nr = &n2
@@ -2044,7 +2055,7 @@ func bgenx(n, res *Node, wantTrue bool, likely int, to *obj.Prog) {
 	l, r := nl, nr
 
 	// On x86, only < and <= work right with NaN; reverse if needed
-	if Ctxt.Arch.Thechar == '6' && Isfloat[nl.Type.Etype] && (op == OGT || op == OGE) {
+	if Ctxt.Arch.Thechar == '6' && Isfloat[nl.Type.Etype] && (op == ir.OGT || op == ir.OGE) {
 		l, r = r, l
 		op = Brrev(op)
 	}
@@ -2057,7 +2068,7 @@ func bgenx(n, res *Node, wantTrue bool, likely int, to *obj.Prog) {
 	}
 
 	// Do the comparison.
-	Thearch.Gins(Thearch.Optoas(OCMP, nr.Type), l, r)
+	Thearch.Gins(Thearch.Optoas(ir.OCMP, nr.Type), l, r)
 
 	// Handle floating point special cases.
 	// Note that 8g has Bgen_float and is handled above.
@@ -2068,46 +2079,46 @@ func bgenx(n, res *Node, wantTrue bool, likely int, to *obj.Prog) {
 				Fatalf("genval 5g Isfloat special cases not implemented")
 			}
 			switch n.Op {
-			case ONE:
-				Patch(Gbranch(Thearch.Optoas(OPS, nr.Type), nr.Type, likely), to)
+			case ir.ONE:
+				Patch(Gbranch(Thearch.Optoas(ir.OPS, nr.Type), nr.Type, likely), to)
 				Patch(Gbranch(Thearch.Optoas(op, nr.Type), nr.Type, likely), to)
 			default:
-				p := Gbranch(Thearch.Optoas(OPS, nr.Type), nr.Type, -likely)
+				p := Gbranch(Thearch.Optoas(ir.OPS, nr.Type), nr.Type, -likely)
 				Patch(Gbranch(Thearch.Optoas(op, nr.Type), nr.Type, likely), to)
 				Patch(p, Pc)
 			}
 			return
 		case '6':
 			switch n.Op {
-			case OEQ:
+			case ir.OEQ:
 				// neither NE nor P
 				if genval {
-					var reg Node
-					Regalloc(&reg, Types[TBOOL], nil)
-					Thearch.Ginsboolval(Thearch.Optoas(OEQ, nr.Type), &reg)
-					Thearch.Ginsboolval(Thearch.Optoas(OPC, nr.Type), res)
-					Thearch.Gins(Thearch.Optoas(OAND, Types[TBOOL]), &reg, res)
+					var reg ir.Node
+					Regalloc(&reg, ir.Types[ir.TBOOL], nil)
+					Thearch.Ginsboolval(Thearch.Optoas(ir.OEQ, nr.Type), &reg)
+					Thearch.Ginsboolval(Thearch.Optoas(ir.OPC, nr.Type), res)
+					Thearch.Gins(Thearch.Optoas(ir.OAND, ir.Types[ir.TBOOL]), &reg, res)
 					Regfree(&reg)
 				} else {
-					p1 := Gbranch(Thearch.Optoas(ONE, nr.Type), nil, -likely)
-					p2 := Gbranch(Thearch.Optoas(OPS, nr.Type), nil, -likely)
+					p1 := Gbranch(Thearch.Optoas(ir.ONE, nr.Type), nil, -likely)
+					p2 := Gbranch(Thearch.Optoas(ir.OPS, nr.Type), nil, -likely)
 					Patch(Gbranch(obj.AJMP, nil, 0), to)
 					Patch(p1, Pc)
 					Patch(p2, Pc)
 				}
 				return
-			case ONE:
+			case ir.ONE:
 				// either NE or P
 				if genval {
-					var reg Node
-					Regalloc(&reg, Types[TBOOL], nil)
-					Thearch.Ginsboolval(Thearch.Optoas(ONE, nr.Type), &reg)
-					Thearch.Ginsboolval(Thearch.Optoas(OPS, nr.Type), res)
-					Thearch.Gins(Thearch.Optoas(OOR, Types[TBOOL]), &reg, res)
+					var reg ir.Node
+					Regalloc(&reg, ir.Types[ir.TBOOL], nil)
+					Thearch.Ginsboolval(Thearch.Optoas(ir.ONE, nr.Type), &reg)
+					Thearch.Ginsboolval(Thearch.Optoas(ir.OPS, nr.Type), res)
+					Thearch.Gins(Thearch.Optoas(ir.OOR, ir.Types[ir.TBOOL]), &reg, res)
 					Regfree(&reg)
 				} else {
-					Patch(Gbranch(Thearch.Optoas(ONE, nr.Type), nil, likely), to)
-					Patch(Gbranch(Thearch.Optoas(OPS, nr.Type), nil, likely), to)
+					Patch(Gbranch(Thearch.Optoas(ir.ONE, nr.Type), nil, likely), to)
+					Patch(Gbranch(Thearch.Optoas(ir.OPS, nr.Type), nil, likely), to)
 				}
 				return
 			}
@@ -2118,14 +2129,14 @@ func bgenx(n, res *Node, wantTrue bool, likely int, to *obj.Prog) {
 			switch n.Op {
 			// On arm64 and ppc64, <= and >= mishandle NaN. Must decompose into < or > and =.
 			// TODO(josh): Convert a <= b to b > a instead?
-			case OLE, OGE:
-				if op == OLE {
-					op = OLT
+			case ir.OLE, ir.OGE:
+				if op == ir.OLE {
+					op = ir.OLT
 				} else {
-					op = OGT
+					op = ir.OGT
 				}
 				Patch(Gbranch(Thearch.Optoas(op, nr.Type), nr.Type, likely), to)
-				Patch(Gbranch(Thearch.Optoas(OEQ, nr.Type), nr.Type, likely), to)
+				Patch(Gbranch(Thearch.Optoas(ir.OEQ, nr.Type), nr.Type, likely), to)
 				return
 			}
 		}
@@ -2135,11 +2146,11 @@ func bgenx(n, res *Node, wantTrue bool, likely int, to *obj.Prog) {
 	bins(nr.Type, res, op, likely, to)
 }
 
-func bgenNonZero(n, res *Node, wantTrue bool, likely int, to *obj.Prog) {
+func bgenNonZero(n, res *ir.Node, wantTrue bool, likely int, to *obj.Prog) {
 	// TODO: Optimize on systems that can compare to zero easily.
-	var op Op = ONE
+	var op ir.Op = ir.ONE
 	if !wantTrue {
-		op = OEQ
+		op = ir.OEQ
 	}
 
 	// MIPS does not have CMP instruction
@@ -2150,16 +2161,16 @@ func bgenNonZero(n, res *Node, wantTrue bool, likely int, to *obj.Prog) {
 		return
 	}
 
-	var zero Node
+	var zero ir.Node
 	Nodconst(&zero, n.Type, 0)
-	Thearch.Gins(Thearch.Optoas(OCMP, n.Type), n, &zero)
+	Thearch.Gins(Thearch.Optoas(ir.OCMP, n.Type), n, &zero)
 	bins(n.Type, res, op, likely, to)
 }
 
 // bins inserts an instruction to handle the result of a compare.
 // If res is non-nil, it inserts appropriate value generation instructions.
 // If res is nil, it inserts a branch to to.
-func bins(typ *Type, res *Node, op Op, likely int, to *obj.Prog) {
+func bins(typ *ir.Type, res *ir.Node, op ir.Op, likely int, to *obj.Prog) {
 	a := Thearch.Optoas(op, typ)
 	if res != nil {
 		// value gen
@@ -2185,15 +2196,15 @@ func bins(typ *Type, res *Node, op Op, likely int, to *obj.Prog) {
 // becomes much simpler and also more trustworthy.
 // The fact that it works at all today is probably due to the fact
 // that ODOT and OINDEX are irrelevant.
-func stkof(n *Node) int64 {
+func stkof(n *ir.Node) int64 {
 	switch n.Op {
-	case OINDREG:
+	case ir.OINDREG:
 		if n.Reg != int16(Thearch.REGSP) {
 			return -1000 // not on stack
 		}
 		return n.Xoffset
 
-	case ODOT:
+	case ir.ODOT:
 		t := n.Left.Type
 		if Isptr[t.Etype] {
 			break
@@ -2204,7 +2215,7 @@ func stkof(n *Node) int64 {
 		}
 		return off + n.Xoffset
 
-	case OINDEX:
+	case ir.OINDEX:
 		t := n.Left.Type
 		if !Isfixedarray(t) {
 			break
@@ -2213,12 +2224,12 @@ func stkof(n *Node) int64 {
 		if off == -1000 || off == +1000 {
 			return off
 		}
-		if Isconst(n.Right, CTINT) {
-			return off + t.Type.Width*n.Right.Val().U.(*Mpint).Int64()
+		if ir.Isconst(n.Right, ir.CTINT) {
+			return off + t.Type.Width*n.Right.Val().U.(*ir.Mpint).Int64()
 		}
 		return +1000 // on stack but not sure exactly where
 
-	case OCALLMETH, OCALLINTER, OCALLFUNC:
+	case ir.OCALLMETH, ir.OCALLINTER, ir.OCALLFUNC:
 		t := n.Left.Type
 		if Isptr[t.Etype] {
 			t = t.Type
@@ -2238,7 +2249,7 @@ func stkof(n *Node) int64 {
 // block copy:
 //	memmove(&ns, &n, w);
 // if wb is true, needs write barrier.
-func sgen_wb(n *Node, ns *Node, w int64, wb bool) {
+func sgen_wb(n *ir.Node, ns *ir.Node, w int64, wb bool) {
 	if Debug['g'] != 0 {
 		op := "sgen"
 		if wb {
@@ -2259,9 +2270,9 @@ func sgen_wb(n *Node, ns *Node, w int64, wb bool) {
 
 	// If copying .args, that's all the results, so record definition sites
 	// for them for the liveness analysis.
-	if ns.Op == ONAME && ns.Sym.Name == ".args" {
+	if ns.Op == ir.ONAME && ns.Sym.Name == ".args" {
 		for _, ln := range Curfn.Func.Dcl {
-			if ln.Class == PPARAMOUT {
+			if ln.Class == ir.PPARAMOUT {
 				Gvardef(ln)
 			}
 		}
@@ -2274,8 +2285,8 @@ func sgen_wb(n *Node, ns *Node, w int64, wb bool) {
 
 	if w == 0 {
 		// evaluate side effects only
-		var nodr Node
-		Regalloc(&nodr, Types[Tptr], nil)
+		var nodr ir.Node
+		Regalloc(&nodr, ir.Types[Tptr], nil)
 		Agen(ns, &nodr)
 		Agen(n, &nodr)
 		Regfree(&nodr)
@@ -2300,7 +2311,7 @@ func sgen_wb(n *Node, ns *Node, w int64, wb bool) {
 		// OR: write barrier needed and source is on stack.
 		// Invoking the write barrier will use the stack to prepare its call.
 		// Copy to temporary.
-		var tmp Node
+		var tmp ir.Node
 		Tempname(&tmp, n.Type)
 		sgen_wb(n, &tmp, w, false)
 		sgen_wb(&tmp, ns, w, wb)
@@ -2322,7 +2333,7 @@ func sgen_wb(n *Node, ns *Node, w int64, wb bool) {
 //	proc=1	goroutine run in new proc
 //	proc=2	defer call save away stack
 //	proc=3	normal call to C pointer (not Go func value)
-func Ginscall(f *Node, proc int) {
+func Ginscall(f *ir.Node, proc int) {
 	if f.Type != nil {
 		extra := int32(0)
 		if proc == 1 || proc == 2 {
@@ -2337,7 +2348,7 @@ func Ginscall(f *Node, proc int) {
 
 	case 0, // normal call
 		-1: // normal call but no return
-		if f.Op == ONAME && f.Class == PFUNC {
+		if f.Op == ir.ONAME && f.Class == ir.PFUNC {
 			if f == Deferreturn {
 				// Deferred calls will appear to be returning to the CALL
 				// deferreturn(SB) that we are about to emit. However, the
@@ -2382,14 +2393,14 @@ func Ginscall(f *Node, proc int) {
 			break
 		}
 
-		var reg Node
-		Nodreg(&reg, Types[Tptr], Thearch.REGCTXT)
-		var r1 Node
-		Nodreg(&r1, Types[Tptr], Thearch.REGCALLX)
+		var reg ir.Node
+		Nodreg(&reg, ir.Types[Tptr], Thearch.REGCTXT)
+		var r1 ir.Node
+		Nodreg(&r1, ir.Types[Tptr], Thearch.REGCALLX)
 		Thearch.Gmove(f, &reg)
-		reg.Op = OINDREG
+		reg.Op = ir.OINDREG
 		Thearch.Gmove(&reg, &r1)
-		reg.Op = OREGISTER
+		reg.Op = ir.OREGISTER
 		Thearch.Gins(obj.ACALL, &reg, &r1)
 
 	case 3: // normal call of c function pointer
@@ -2397,21 +2408,25 @@ func Ginscall(f *Node, proc int) {
 
 	case 1, // call in new proc (go)
 		2: // deferred call (defer)
-		var stk Node
-
-		// size of arguments at 0(SP)
-		stk.Op = OINDREG
+		var stk ir.Node
+		stk.Op = ir.OINDREG
 		stk.Reg = int16(Thearch.REGSP)
 		stk.Xoffset = Ctxt.FixedFrameSize()
-		Thearch.Ginscon(Thearch.Optoas(OAS, Types[TINT32]), int64(Argsize(f.Type)), &stk)
 
-		// FuncVal* at 8(SP)
-		stk.Xoffset = int64(Widthptr) + Ctxt.FixedFrameSize()
+		// A deferred call without arguments needs only the FuncVal*, at 0(SP).
+		niladic := proc == 2 && Argsize(f.Type) == 0
+		if !niladic {
+			// size of arguments at 0(SP)
+			Thearch.Ginscon(Thearch.Optoas(ir.OAS, ir.Types[ir.TINT32]), int64(Argsize(f.Type)), &stk)
+
+			// FuncVal* at 8(SP)
+			stk.Xoffset += int64(Widthptr)
+		}
 
-		var reg Node
-		Nodreg(&reg, Types[Tptr], Thearch.REGCALLX2)
+		var reg ir.Node
+		Nodreg(&reg, ir.Types[Tptr], Thearch.REGCALLX2)
 		Thearch.Gmove(f, &reg)
-		Thearch.Gins(Thearch.Optoas(OAS, Types[Tptr]), &reg, &stk)
+		Thearch.Gins(Thearch.Optoas(ir.OAS, ir.Types[Tptr]), &reg, &stk)
 
 		if proc == 1 {
 			Ginscall(Newproc, 0)
@@ -2419,12 +2434,16 @@ func Ginscall(f *Node, proc int) {
 			if !hasdefer {
 				Fatalf("hasdefer=0 but has defer")
 			}
-			Ginscall(Deferproc, 0)
+			if niladic {
+				Ginscall(Deferproc0, 0)
+			} else {
+				Ginscall(Deferproc, 0)
+			}
 		}
 
 		if proc == 2 {
-			Nodreg(&reg, Types[TINT32], Thearch.REGRETURN)
-			p := Thearch.Ginscmp(OEQ, Types[TINT32], &reg, Nodintconst(0), +1)
+			Nodreg(&reg, ir.Types[ir.TINT32], Thearch.REGRETURN)
+			p := Thearch.Ginscmp(ir.OEQ, ir.Types[ir.TINT32], &reg, Nodintconst(0), +1)
 			cgen_ret(nil)
 			Patch(p, Pc)
 		}
@@ -2433,16 +2452,16 @@ func Ginscall(f *Node, proc int) {
 
 // n is call to interface method.
 // generate res = n.
-func cgen_callinter(n *Node, res *Node, proc int) {
+func cgen_callinter(n *ir.Node, res *ir.Node, proc int) {
 	i := n.Left
-	if i.Op != ODOTINTER {
+	if i.Op != ir.ODOTINTER {
 		Fatalf("cgen_callinter: not ODOTINTER %v", Oconv(i.Op, 0))
 	}
 
 	i = i.Left // interface
 
 	if !i.Addable {
-		var tmpi Node
+		var tmpi ir.Node
 		Tempname(&tmpi, i.Type)
 		Cgen(i, &tmpi)
 		i = &tmpi
@@ -2452,34 +2471,34 @@ func cgen_callinter(n *Node, res *Node, proc int) {
 
 	// i is now addable, prepare an indirected
 	// register to hold its address.
-	var nodi Node
+	var nodi ir.Node
 	Igen(i, &nodi, res) // REG = &inter
 
-	var nodsp Node
-	Nodindreg(&nodsp, Types[Tptr], Thearch.REGSP)
+	var nodsp ir.Node
+	Nodindreg(&nodsp, ir.Types[Tptr], Thearch.REGSP)
 	nodsp.Xoffset = Ctxt.FixedFrameSize()
 	if proc != 0 {
 		nodsp.Xoffset += 2 * int64(Widthptr) // leave room for size & fn
 	}
-	nodi.Type = Types[Tptr]
+	nodi.Type = ir.Types[Tptr]
 	nodi.Xoffset += int64(Widthptr)
 	Cgen(&nodi, &nodsp) // {0, 8(nacl), or 16}(SP) = 8(REG) -- i.data
 
-	var nodo Node
-	Regalloc(&nodo, Types[Tptr], res)
+	var nodo ir.Node
+	Regalloc(&nodo, ir.Types[Tptr], res)
 
-	nodi.Type = Types[Tptr]
+	nodi.Type = ir.Types[Tptr]
 	nodi.Xoffset -= int64(Widthptr)
 	Cgen(&nodi, &nodo) // REG = 0(REG) -- i.tab
 	Regfree(&nodi)
 
-	var nodr Node
-	Regalloc(&nodr, Types[Tptr], &nodo)
-	if n.Left.Xoffset == BADWIDTH {
+	var nodr ir.Node
+	Regalloc(&nodr, ir.Types[Tptr], &nodo)
+	if n.Left.Xoffset == ir.BADWIDTH {
 		Fatalf("cgen_callinter: badwidth")
 	}
 	Cgen_checknil(&nodo) // in case offset is huge
-	nodo.Op = OINDREG
+	nodo.Op = ir.OINDREG
 	nodo.Xoffset = n.Left.Xoffset + 3*int64(Widthptr) + 8
 	if proc == 0 {
 		// plain call: use direct c function pointer - more efficient
@@ -2501,16 +2520,16 @@ func cgen_callinter(n *Node, res *Node, proc int) {
 //	proc=0	normal call
 //	proc=1	goroutine run in new proc
 //	proc=2	defer call save away stack
-func cgen_call(n *Node, proc int) {
+func cgen_call(n *ir.Node, proc int) {
 	if n == nil {
 		return
 	}
 
-	var afun Node
+	var afun ir.Node
 	if n.Left.Ullman >= UINF {
 		// if name involves a fn call
 		// precompute the address of the fn
-		Tempname(&afun, Types[Tptr])
+		Tempname(&afun, ir.Types[Tptr])
 
 		Cgen(n.Left, &afun)
 	}
@@ -2520,8 +2539,8 @@ func cgen_call(n *Node, proc int) {
 
 	// call tempname pointer
 	if n.Left.Ullman >= UINF {
-		var nod Node
-		Regalloc(&nod, Types[Tptr], nil)
+		var nod ir.Node
+		Regalloc(&nod, ir.Types[Tptr], nil)
 		Cgen_as(&nod, &afun)
 		nod.Type = t
 		Ginscall(&nod, proc)
@@ -2530,9 +2549,9 @@ func cgen_call(n *Node, proc int) {
 	}
 
 	// call pointer
-	if n.Left.Op != ONAME || n.Left.Class != PFUNC {
-		var nod Node
-		Regalloc(&nod, Types[Tptr], nil)
+	if n.Left.Op != ir.ONAME || n.Left.Class != ir.PFUNC {
+		var nod ir.Node
+		Regalloc(&nod, ir.Types[Tptr], nil)
 		Cgen_as(&nod, n.Left)
 		nod.Type = t
 		Ginscall(&nod, proc)
@@ -2549,9 +2568,9 @@ func cgen_call(n *Node, proc int) {
 // call to n has already been generated.
 // generate:
 //	res = return value from call.
-func cgen_callret(n *Node, res *Node) {
+func cgen_callret(n *ir.Node, res *ir.Node) {
 	t := n.Left.Type
-	if t.Etype == TPTR32 || t.Etype == TPTR64 {
+	if t.Etype == ir.TPTR32 || t.Etype == ir.TPTR64 {
 		t = t.Type
 	}
 
@@ -2560,8 +2579,8 @@ func cgen_callret(n *Node, res *Node) {
 		Fatalf("cgen_callret: nil")
 	}
 
-	var nod Node
-	nod.Op = OINDREG
+	var nod ir.Node
+	nod.Op = ir.OINDREG
 	nod.Reg = int16(Thearch.REGSP)
 	nod.Addable = true
 
@@ -2573,7 +2592,7 @@ func cgen_callret(n *Node, res *Node) {
 // call to n has already been generated.
 // generate:
 //	res = &return value from call.
-func cgen_aret(n *Node, res *Node) {
+func cgen_aret(n *ir.Node, res *ir.Node) {
 	t := n.Left.Type
 	if Isptr[t.Etype] {
 		t = t.Type
@@ -2584,18 +2603,18 @@ func cgen_aret(n *Node, res *Node) {
 		Fatalf("cgen_aret: nil")
 	}
 
-	var nod1 Node
-	nod1.Op = OINDREG
+	var nod1 ir.Node
+	nod1.Op = ir.OINDREG
 	nod1.Reg = int16(Thearch.REGSP)
 	nod1.Addable = true
 	nod1.Xoffset = fp.Width + Ctxt.FixedFrameSize()
 	nod1.Type = fp.Type
 
-	if res.Op != OREGISTER {
-		var nod2 Node
-		Regalloc(&nod2, Types[Tptr], res)
+	if res.Op != ir.OREGISTER {
+		var nod2 ir.Node
+		Regalloc(&nod2, ir.Types[Tptr], res)
 		Agen(&nod1, &nod2)
-		Thearch.Gins(Thearch.Optoas(OAS, Types[Tptr]), &nod2, res)
+		Thearch.Gins(Thearch.Optoas(ir.OAS, ir.Types[Tptr]), &nod2, res)
 		Regfree(&nod2)
 	} else {
 		Agen(&nod1, res)
@@ -2604,7 +2623,7 @@ func cgen_aret(n *Node, res *Node) {
 
 // generate return.
 // n->left is assignments to return values.
-func cgen_ret(n *Node) {
+func cgen_ret(n *ir.Node) {
 	if n != nil {
 		Genlist(n.List) // copy out args
 	}
@@ -2613,7 +2632,7 @@ func cgen_ret(n *Node) {
 	}
 	Genlist(Curfn.Func.Exit)
 	p := Thearch.Gins(obj.ARET, nil, nil)
-	if n != nil && n.Op == ORETJMP {
+	if n != nil && n.Op == ir.ORETJMP {
 		p.To.Type = obj.TYPE_MEM
 		p.To.Name = obj.NAME_EXTERN
 		p.To.Sym = Linksym(n.Left.Sym)
@@ -2623,14 +2642,15 @@ func cgen_ret(n *Node) {
 // generate division according to op, one of:
 //	res = nl / nr
 //	res = nl % nr
-func cgen_div(op Op, nl *Node, nr *Node, res *Node) {
+// Cgen only calls cgen_div if the back end has a Dodiv.
+func cgen_div(op ir.Op, nl *ir.Node, nr *ir.Node, res *ir.Node) {
 	var w int
 
 	// TODO(rsc): arm64 needs to support the relevant instructions
 	// in peep and optoas in order to enable this.
 	// TODO(rsc): ppc64 needs to support the relevant instructions
 	// in peep and optoas in order to enable this.
-	if nr.Op != OLITERAL || Ctxt.Arch.Thechar == '0' || Ctxt.Arch.Thechar == '7' || Ctxt.Arch.Thechar == '9' {
+	if nr.Op != ir.OLITERAL || Ctxt.Arch.Thechar == '0' || Ctxt.Arch.Thechar == '7' || Ctxt.Arch.Thechar == '9' {
 		goto longdiv
 	}
 	w = int(nl.Type.Width * 8)
@@ -2642,37 +2662,37 @@ func cgen_div(op Op, nl *Node, nr *Node, res *Node) {
 	default:
 		goto longdiv
 
-	case TUINT64:
+	case ir.TUINT64:
 		var m Magic
 		m.W = w
-		m.Ud = uint64(nr.Val().U.(*Mpint).Int64())
+		m.Ud = uint64(nr.Val().U.(*ir.Mpint).Int64())
 		Umagic(&m)
 		if m.Bad != 0 {
 			break
 		}
-		if op == OMOD {
+		if op == ir.OMOD {
 			goto longmod
 		}
 
-		var n1 Node
+		var n1 ir.Node
 		Cgenr(nl, &n1, nil)
-		var n2 Node
+		var n2 ir.Node
 		Nodconst(&n2, nl.Type, int64(m.Um))
-		var n3 Node
+		var n3 ir.Node
 		Regalloc(&n3, nl.Type, res)
 		Thearch.Cgen_hmul(&n1, &n2, &n3)
 
 		if m.Ua != 0 {
 			// need to add numerator accounting for overflow
-			Thearch.Gins(Thearch.Optoas(OADD, nl.Type), &n1, &n3)
+			Thearch.Gins(Thearch.Optoas(ir.OADD, nl.Type), &n1, &n3)
 
 			Nodconst(&n2, nl.Type, 1)
-			Thearch.Gins(Thearch.Optoas(ORROTC, nl.Type), &n2, &n3)
+			Thearch.Gins(Thearch.Optoas(ir.ORROTC, nl.Type), &n2, &n3)
 			Nodconst(&n2, nl.Type, int64(m.S)-1)
-			Thearch.Gins(Thearch.Optoas(ORSH, nl.Type), &n2, &n3)
+			Thearch.Gins(Thearch.Optoas(ir.ORSH, nl.Type), &n2, &n3)
 		} else {
 			Nodconst(&n2, nl.Type, int64(m.S))
-			Thearch.Gins(Thearch.Optoas(ORSH, nl.Type), &n2, &n3) // shift dx
+			Thearch.Gins(Thearch.Optoas(ir.ORSH, nl.Type), &n2, &n3) // shift dx
 		}
 
 		Thearch.Gmove(&n3, res)
@@ -2680,43 +2700,43 @@ func cgen_div(op Op, nl *Node, nr *Node, res *Node) {
 		Regfree(&n3)
 		return
 
-	case TINT64:
+	case ir.TINT64:
 		var m Magic
 		m.W = w
-		m.Sd = nr.Val().U.(*Mpint).Int64()
+		m.Sd = nr.Val().U.(*ir.Mpint).Int64()
 		Smagic(&m)
 		if m.Bad != 0 {
 			break
 		}
-		if op == OMOD {
+		if op == ir.OMOD {
 			goto longmod
 		}
 
-		var n1 Node
+		var n1 ir.Node
 		Cgenr(nl, &n1, res)
-		var n2 Node
+		var n2 ir.Node
 		Nodconst(&n2, nl.Type, m.Sm)
-		var n3 Node
+		var n3 ir.Node
 		Regalloc(&n3, nl.Type, nil)
 		Thearch.Cgen_hmul(&n1, &n2, &n3)
 
 		if m.Sm < 0 {
 			// need to add numerator
-			Thearch.Gins(Thearch.Optoas(OADD, nl.Type), &n1, &n3)
+			Thearch.Gins(Thearch.Optoas(ir.OADD, nl.Type), &n1, &n3)
 		}
 
 		Nodconst(&n2, nl.Type, int64(m.S))
-		Thearch.Gins(Thearch.Optoas(ORSH, nl.Type), &n2, &n3) // shift n3
+		Thearch.Gins(Thearch.Optoas(ir.ORSH, nl.Type), &n2, &n3) // shift n3
 
 		Nodconst(&n2, nl.Type, int64(w)-1)
 
-		Thearch.Gins(Thearch.Optoas(ORSH, nl.Type), &n2, &n1) // -1 iff num is neg
-		Thearch.Gins(Thearch.Optoas(OSUB, nl.Type), &n1, &n3) // added
+		Thearch.Gins(Thearch.Optoas(ir.ORSH, nl.Type), &n2, &n1) // -1 iff num is neg
+		Thearch.Gins(Thearch.Optoas(ir.OSUB, nl.Type), &n1, &n3) // added
 
 		if m.Sd < 0 {
 			// this could probably be removed
 			// by factoring it into the multiplier
-			Thearch.Gins(Thearch.Optoas(OMINUS, nl.Type), nil, &n3)
+			Thearch.Gins(Thearch.Optoas(ir.OMINUS, nl.Type), nil, &n3)
 		}
 
 		Thearch.Gmove(&n3, res)
@@ -2729,6 +2749,9 @@ func cgen_div(op Op, nl *Node, nr *Node, res *Node) {
 
 	// division and mod using (slow) hardware instruction
 longdiv:
+	if Thearch.Dodiv == nil {
+		Fatalf("cgen_div: no Dodiv for %v", op)
+	}
 	Thearch.Dodiv(op, nl, nr, res)
 
 	return
@@ -2736,22 +2759,22 @@ longdiv:
 	// mod using formula A%B = A-(A/B*B) but
 	// we know that there is a fast algorithm for A/B
 longmod:
-	var n1 Node
+	var n1 ir.Node
 	Regalloc(&n1, nl.Type, res)
 
 	Cgen(nl, &n1)
-	var n2 Node
+	var n2 ir.Node
 	Regalloc(&n2, nl.Type, nil)
-	cgen_div(ODIV, &n1, nr, &n2)
-	a := Thearch.Optoas(OMUL, nl.Type)
+	cgen_div(ir.ODIV, &n1, nr, &n2)
+	a := Thearch.Optoas(ir.OMUL, nl.Type)
 	if w == 8 {
 		// use 2-operand 16-bit multiply
 		// because there is no 2-operand 8-bit multiply
-		a = Thearch.Optoas(OMUL, Types[TINT16]) // XXX was IMULW
+		a = Thearch.Optoas(ir.OMUL, ir.Types[ir.TINT16]) // XXX was IMULW
 	}
 
 	if !Smallintconst(nr) {
-		var n3 Node
+		var n3 ir.Node
 		Regalloc(&n3, nl.Type, nil)
 		Cgen(nr, &n3)
 		Thearch.Gins(a, &n3, &n2)
@@ -2759,17 +2782,17 @@ longmod:
 	} else {
 		Thearch.Gins(a, nr, &n2)
 	}
-	Thearch.Gins(Thearch.Optoas(OSUB, nl.Type), &n2, &n1)
+	Thearch.Gins(Thearch.Optoas(ir.OSUB, nl.Type), &n2, &n1)
 	Thearch.Gmove(&n1, res)
 	Regfree(&n1)
 	Regfree(&n2)
 }
 
-func Fixlargeoffset(n *Node) {
+func Fixlargeoffset(n *ir.Node) {
 	if n == nil {
 		return
 	}
-	if n.Op != OINDREG {
+	if n.Op != ir.OINDREG {
 		return
 	}
 	if n.Reg == int16(Thearch.REGSP) { // stack offset cannot be large
@@ -2779,21 +2802,21 @@ func Fixlargeoffset(n *Node) {
 		// offset too large, add to register instead.
 		a := *n
 
-		a.Op = OREGISTER
-		a.Type = Types[Tptr]
+		a.Op = ir.OREGISTER
+		a.Type = ir.Types[Tptr]
 		a.Xoffset = 0
 		Cgen_checknil(&a)
-		Thearch.Ginscon(Thearch.Optoas(OADD, Types[Tptr]), n.Xoffset, &a)
+		Thearch.Ginscon(Thearch.Optoas(ir.OADD, ir.Types[Tptr]), n.Xoffset, &a)
 		n.Xoffset = 0
 	}
 }
 
-func cgen_append(n, res *Node) {
+func cgen_append(n, res *ir.Node) {
 	if Debug['g'] != 0 {
 		Dump("cgen_append-n", n)
 		Dump("cgen_append-res", res)
 	}
-	if res.Op != ONAME && !samesafeexpr(res, n.List.First()) {
+	if res.Op != ir.ONAME && !samesafeexpr(res, n.List.First()) {
 		Dump("cgen_append-n", n)
 		Dump("cgen_append-res", res)
 		Fatalf("append not lowered")
@@ -2811,26 +2834,26 @@ func cgen_append(n, res *Node) {
 	needFullUpdate := !samesafeexpr(res, n.List.First())
 
 	// Copy src triple into base, len, cap.
-	base := temp(Types[Tptr])
-	len := temp(Types[TUINT])
-	cap := temp(Types[TUINT])
+	base := temp(ir.Types[Tptr])
+	len := temp(ir.Types[ir.TUINT])
+	cap := temp(ir.Types[ir.TUINT])
 
-	var src Node
+	var src ir.Node
 	Igen(n.List.First(), &src, nil)
-	src.Type = Types[Tptr]
+	src.Type = ir.Types[Tptr]
 	Thearch.Gmove(&src, base)
-	src.Type = Types[TUINT]
+	src.Type = ir.Types[ir.TUINT]
 	src.Xoffset += int64(Widthptr)
 	Thearch.Gmove(&src, len)
 	src.Xoffset += int64(Widthptr)
 	Thearch.Gmove(&src, cap)
 
 	// if len+argc <= cap goto L1
-	var rlen Node
-	Regalloc(&rlen, Types[TUINT], nil)
+	var rlen ir.Node
+	Regalloc(&rlen, ir.Types[ir.TUINT], nil)
 	Thearch.Gmove(len, &rlen)
-	Thearch.Ginscon(Thearch.Optoas(OADD, Types[TUINT]), int64(n.List.Len()-1), &rlen)
-	p := Thearch.Ginscmp(OLE, Types[TUINT], &rlen, cap, +1)
+	Thearch.Ginscon(Thearch.Optoas(ir.OADD, ir.Types[ir.TUINT]), int64(n.List.Len()-1), &rlen)
+	p := Thearch.Ginscmp(ir.OLE, ir.Types[ir.TUINT], &rlen, cap, +1)
 	// Note: rlen and src are Regrealloc'ed below at the target of the
 	// branch we just emitted; do not reuse these Go variables for
 	// other purposes. They need to still describe the same things
@@ -2838,28 +2861,28 @@ func cgen_append(n, res *Node) {
 	Regfree(&src)
 
 	// base, len, cap = growslice(type, base, len, cap, newlen)
-	var arg Node
-	arg.Op = OINDREG
+	var arg ir.Node
+	arg.Op = ir.OINDREG
 	arg.Reg = int16(Thearch.REGSP)
 	arg.Addable = true
 	arg.Xoffset = Ctxt.FixedFrameSize()
-	arg.Type = Ptrto(Types[TUINT8])
+	arg.Type = Ptrto(ir.Types[ir.TUINT8])
 	Cgen(typename(res.Type), &arg)
 	arg.Xoffset += int64(Widthptr)
 
-	arg.Type = Types[Tptr]
+	arg.Type = ir.Types[Tptr]
 	Cgen(base, &arg)
 	arg.Xoffset += int64(Widthptr)
 
-	arg.Type = Types[TUINT]
+	arg.Type = ir.Types[ir.TUINT]
 	Cgen(len, &arg)
 	arg.Xoffset += int64(Widthptr)
 
-	arg.Type = Types[TUINT]
+	arg.Type = ir.Types[ir.TUINT]
 	Cgen(cap, &arg)
 	arg.Xoffset += int64(Widthptr)
 
-	arg.Type = Types[TUINT]
+	arg.Type = ir.Types[ir.TUINT]
 	Cgen(&rlen, &arg)
 	arg.Xoffset += int64(Widthptr)
 	Regfree(&rlen)
@@ -2872,15 +2895,15 @@ func cgen_append(n, res *Node) {
 		arg.Xoffset += 4
 	}
 
-	arg.Type = Types[Tptr]
+	arg.Type = ir.Types[Tptr]
 	Cgen(&arg, base)
 	arg.Xoffset += int64(Widthptr)
 
-	arg.Type = Types[TUINT]
+	arg.Type = ir.Types[ir.TUINT]
 	Cgen(&arg, len)
 	arg.Xoffset += int64(Widthptr)
 
-	arg.Type = Types[TUINT]
+	arg.Type = ir.Types[ir.TUINT]
 	Cgen(&arg, cap)
 
 	// Update res with base, len+argc, cap.
@@ -2890,21 +2913,21 @@ func cgen_append(n, res *Node) {
 		}
 		Patch(p, Pc)
 	}
-	if res.Op == ONAME {
+	if res.Op == ir.ONAME {
 		Gvardef(res)
 	}
-	var dst, r1 Node
+	var dst, r1 ir.Node
 	Igen(res, &dst, nil)
-	dst.Type = Types[TUINT]
+	dst.Type = ir.Types[ir.TUINT]
 	dst.Xoffset += int64(Widthptr)
-	Regalloc(&r1, Types[TUINT], nil)
+	Regalloc(&r1, ir.Types[ir.TUINT], nil)
 	Thearch.Gmove(len, &r1)
-	Thearch.Ginscon(Thearch.Optoas(OADD, Types[TUINT]), int64(n.List.Len()-1), &r1)
+	Thearch.Ginscon(Thearch.Optoas(ir.OADD, ir.Types[ir.TUINT]), int64(n.List.Len()-1), &r1)
 	Thearch.Gmove(&r1, &dst)
 	Regfree(&r1)
 	dst.Xoffset += int64(Widthptr)
 	Thearch.Gmove(cap, &dst)
-	dst.Type = Types[Tptr]
+	dst.Type = ir.Types[Tptr]
 	dst.Xoffset -= 2 * int64(Widthptr)
 	cgen_wb(base, &dst, needwritebarrier(&dst, base))
 	Regfree(&dst)
@@ -2920,7 +2943,7 @@ func cgen_append(n, res *Node) {
 		q := Gbranch(obj.AJMP, nil, 0)
 		Patch(p, Pc)
 		// At the goto above, src refers to cap and rlen holds the new len
-		if src.Op == OREGISTER || src.Op == OINDREG {
+		if src.Op == ir.OREGISTER || src.Op == ir.OINDREG {
 			Regrealloc(&src)
 		}
 		Regrealloc(&rlen)
@@ -2936,27 +2959,27 @@ func cgen_append(n, res *Node) {
 	// Could avoid reloading registers on each iteration if we know the cgen_wb
 	// is not going to use a write barrier.
 	i := 0
-	var r2 Node
+	var r2 ir.Node
 	for _, n2 := range n.List.Slice()[1:] {
-		Regalloc(&r1, Types[Tptr], nil)
+		Regalloc(&r1, ir.Types[Tptr], nil)
 		Thearch.Gmove(base, &r1)
-		Regalloc(&r2, Types[TUINT], nil)
+		Regalloc(&r2, ir.Types[ir.TUINT], nil)
 		Thearch.Gmove(len, &r2)
 		if i > 0 {
-			Thearch.Gins(Thearch.Optoas(OADD, Types[TUINT]), Nodintconst(int64(i)), &r2)
+			Thearch.Gins(Thearch.Optoas(ir.OADD, ir.Types[ir.TUINT]), Nodintconst(int64(i)), &r2)
 		}
 		w := res.Type.Type.Width
 		if Thearch.AddIndex != nil && Thearch.AddIndex(&r2, w, &r1) {
 			// r1 updated by back end
 		} else if w == 1 {
-			Thearch.Gins(Thearch.Optoas(OADD, Types[Tptr]), &r2, &r1)
+			Thearch.Gins(Thearch.Optoas(ir.OADD, ir.Types[Tptr]), &r2, &r1)
 		} else {
-			Thearch.Ginscon(Thearch.Optoas(OMUL, Types[TUINT]), w, &r2)
-			Thearch.Gins(Thearch.Optoas(OADD, Types[Tptr]), &r2, &r1)
+			Thearch.Ginscon(Thearch.Optoas(ir.OMUL, ir.Types[ir.TUINT]), w, &r2)
+			Thearch.Gins(Thearch.Optoas(ir.OADD, ir.Types[Tptr]), &r2, &r1)
 		}
 		Regfree(&r2)
 
-		r1.Op = OINDREG
+		r1.Op = ir.OINDREG
 		r1.Type = res.Type.Type
 		cgen_wb(n2, &r1, needwritebarrier(&r1, n2))
 		Regfree(&r1)
@@ -2967,7 +2990,7 @@ func cgen_append(n, res *Node) {
 // Generate res = n, where n is x[i:j] or x[i:j:k].
 // If wb is true, need write barrier updating res's base pointer.
 // On systems with 32-bit ints, i, j, k are guaranteed to be 32-bit values.
-func cgen_slice(n, res *Node, wb bool) {
+func cgen_slice(n, res *ir.Node, wb bool) {
 	if Debug['g'] != 0 {
 		Dump("cgen_slice-n", n)
 		Dump("cgen_slice-res", res)
@@ -2979,14 +3002,14 @@ func cgen_slice(n, res *Node, wb bool) {
 	// and i, j, k are cheap. On a system with registers (anything but 386)
 	// we can evaluate x first and then know we have enough registers
 	// for i, j, k as well.
-	var x, xbase, xlen, xcap, i, j, k Node
-	if n.Op != OSLICEARR && n.Op != OSLICE3ARR {
+	var x, xbase, xlen, xcap, i, j, k ir.Node
+	if n.Op != ir.OSLICEARR && n.Op != ir.OSLICE3ARR {
 		Igen(n.Left, &x, nil)
 	}
 
-	indexRegType := Types[TUINT]
+	indexRegType := ir.Types[ir.TUINT]
 	if Widthreg > Widthptr { // amd64p32
-		indexRegType = Types[TUINT64]
+		indexRegType = ir.Types[ir.TUINT64]
 	}
 
 	// On most systems, we use registers.
@@ -2996,19 +3019,19 @@ func cgen_slice(n, res *Node, wb bool) {
 	ginscon := Thearch.Ginscon
 	gins := Thearch.Gins
 	if Thearch.Thechar == '8' {
-		regalloc = func(n *Node, t *Type, reuse *Node) {
+		regalloc = func(n *ir.Node, t *ir.Type, reuse *ir.Node) {
 			Tempname(n, t)
 		}
-		ginscon = func(as obj.As, c int64, n *Node) {
-			var n1 Node
+		ginscon = func(as obj.As, c int64, n *ir.Node) {
+			var n1 ir.Node
 			Regalloc(&n1, n.Type, n)
 			Thearch.Gmove(n, &n1)
 			Thearch.Ginscon(as, c, &n1)
 			Thearch.Gmove(&n1, n)
 			Regfree(&n1)
 		}
-		gins = func(as obj.As, f, t *Node) *obj.Prog {
-			var n1 Node
+		gins = func(as obj.As, f, t *ir.Node) *obj.Prog {
+			var n1 ir.Node
 			Regalloc(&n1, t.Type, t)
 			Thearch.Gmove(t, &n1)
 			Thearch.Gins(as, f, &n1)
@@ -3024,17 +3047,17 @@ func cgen_slice(n, res *Node, wb bool) {
 		if xlen.Op != 0 {
 			return
 		}
-		if n.Op == OSLICEARR || n.Op == OSLICE3ARR {
+		if n.Op == ir.OSLICEARR || n.Op == ir.OSLICE3ARR {
 			Nodconst(&xlen, indexRegType, n.Left.Type.Type.Bound)
 			return
 		}
-		if n.Op == OSLICESTR && Isconst(n.Left, CTSTR) {
+		if n.Op == ir.OSLICESTR && ir.Isconst(n.Left, ir.CTSTR) {
 			Nodconst(&xlen, indexRegType, int64(len(n.Left.Val().U.(string))))
 			return
 		}
 		regalloc(&xlen, indexRegType, nil)
 		x.Xoffset += int64(Widthptr)
-		x.Type = Types[TUINT]
+		x.Type = ir.Types[ir.TUINT]
 		Thearch.Gmove(&x, &xlen)
 		x.Xoffset -= int64(Widthptr)
 	}
@@ -3043,27 +3066,27 @@ func cgen_slice(n, res *Node, wb bool) {
 		if xcap.Op != 0 {
 			return
 		}
-		if n.Op == OSLICEARR || n.Op == OSLICE3ARR || n.Op == OSLICESTR {
+		if n.Op == ir.OSLICEARR || n.Op == ir.OSLICE3ARR || n.Op == ir.OSLICESTR {
 			loadlen()
 			xcap = xlen
-			if xcap.Op == OREGISTER {
+			if xcap.Op == ir.OREGISTER {
 				Regrealloc(&xcap)
 			}
 			return
 		}
 		regalloc(&xcap, indexRegType, nil)
 		x.Xoffset += 2 * int64(Widthptr)
-		x.Type = Types[TUINT]
+		x.Type = ir.Types[ir.TUINT]
 		Thearch.Gmove(&x, &xcap)
 		x.Xoffset -= 2 * int64(Widthptr)
 	}
 
-	var x1, x2, x3 *Node // unevaluated index arguments
+	var x1, x2, x3 *ir.Node // unevaluated index arguments
 	x1 = n.Right.Left
 	switch n.Op {
 	default:
 		x2 = n.Right.Right
-	case OSLICE3, OSLICE3ARR:
+	case ir.OSLICE3, ir.OSLICE3ARR:
 		x2 = n.Right.Right.Left
 		x3 = n.Right.Right.Right
 	}
@@ -3073,15 +3096,15 @@ func cgen_slice(n, res *Node, wb bool) {
 	// If targ.Op == OREGISTER on return, it must be Regfreed,
 	// but it should not be modified without first checking whether it is
 	// xlen or xcap's register.
-	load := func(src, targ *Node) {
+	load := func(src, targ *ir.Node) {
 		if src == nil {
 			return
 		}
 		switch src.Op {
-		case OLITERAL:
+		case ir.OLITERAL:
 			*targ = *src
 			return
-		case OLEN:
+		case ir.OLEN:
 			// NOTE(rsc): This doesn't actually trigger, because order.go
 			// has pulled all the len and cap calls into separate assignments
 			// to temporaries. There are tests in test/sliceopt.go that could
@@ -3092,12 +3115,12 @@ func cgen_slice(n, res *Node, wb bool) {
 				}
 				loadlen()
 				*targ = xlen
-				if targ.Op == OREGISTER {
+				if targ.Op == ir.OREGISTER {
 					Regrealloc(targ)
 				}
 				return
 			}
-		case OCAP:
+		case ir.OCAP:
 			// NOTE(rsc): This doesn't actually trigger; see note in case OLEN above.
 			if samesafeexpr(n.Left, src.Left) {
 				if Debug_slice > 0 {
@@ -3105,7 +3128,7 @@ func cgen_slice(n, res *Node, wb bool) {
 				}
 				loadcap()
 				*targ = xcap
-				if targ.Op == OREGISTER {
+				if targ.Op == ir.OREGISTER {
 					Regrealloc(targ)
 				}
 				return
@@ -3116,7 +3139,7 @@ func cgen_slice(n, res *Node, wb bool) {
 				Warn("slice: reuse 1st index")
 			}
 			*targ = i
-			if targ.Op == OREGISTER {
+			if targ.Op == ir.OREGISTER {
 				Regrealloc(targ)
 			}
 			return
@@ -3126,7 +3149,7 @@ func cgen_slice(n, res *Node, wb bool) {
 				Warn("slice: reuse 2nd index")
 			}
 			*targ = j
-			if targ.Op == OREGISTER {
+			if targ.Op == ir.OREGISTER {
 				Regrealloc(targ)
 			}
 			return
@@ -3144,7 +3167,7 @@ func cgen_slice(n, res *Node, wb bool) {
 			}
 		} else {
 			regalloc(targ, indexRegType, nil)
-			var tmp Node
+			var tmp ir.Node
 			Cgenr(src, &tmp, targ)
 			Thearch.Gmove(&tmp, targ)
 			Regfree(&tmp)
@@ -3164,17 +3187,17 @@ func cgen_slice(n, res *Node, wb bool) {
 	if j.Op == 0 {
 		loadlen()
 		j = xlen
-		if j.Op == OREGISTER {
+		if j.Op == ir.OREGISTER {
 			Regrealloc(&j)
 		}
 	}
 
 	// k defaults to cap(x)
 	// Only need to load it if we're recalculating cap or doing a full update.
-	if k.Op == 0 && n.Op != OSLICESTR && (!iszero(&i) || needFullUpdate) {
+	if k.Op == 0 && n.Op != ir.OSLICESTR && (!iszero(&i) || needFullUpdate) {
 		loadcap()
 		k = xcap
-		if k.Op == OREGISTER {
+		if k.Op == ir.OREGISTER {
 			Regrealloc(&k)
 		}
 	}
@@ -3182,37 +3205,37 @@ func cgen_slice(n, res *Node, wb bool) {
 	// Check constant indexes for negative values, and against constant length if known.
 	// The func obvious below checks for out-of-order constant indexes.
 	var bound int64 = -1
-	if n.Op == OSLICEARR || n.Op == OSLICE3ARR {
+	if n.Op == ir.OSLICEARR || n.Op == ir.OSLICE3ARR {
 		bound = n.Left.Type.Type.Bound
-	} else if n.Op == OSLICESTR && Isconst(n.Left, CTSTR) {
+	} else if n.Op == ir.OSLICESTR && ir.Isconst(n.Left, ir.CTSTR) {
 		bound = int64(len(n.Left.Val().U.(string)))
 	}
-	if Isconst(&i, CTINT) {
-		if i.Val().U.(*Mpint).CmpInt64(0) < 0 || bound >= 0 && i.Val().U.(*Mpint).CmpInt64(bound) > 0 {
+	if ir.Isconst(&i, ir.CTINT) {
+		if i.Val().U.(*ir.Mpint).CmpInt64(0) < 0 || bound >= 0 && i.Val().U.(*ir.Mpint).CmpInt64(bound) > 0 {
 			Yyerror("slice index out of bounds")
 		}
 	}
-	if Isconst(&j, CTINT) {
-		if j.Val().U.(*Mpint).CmpInt64(0) < 0 || bound >= 0 && j.Val().U.(*Mpint).CmpInt64(bound) > 0 {
+	if ir.Isconst(&j, ir.CTINT) {
+		if j.Val().U.(*ir.Mpint).CmpInt64(0) < 0 || bound >= 0 && j.Val().U.(*ir.Mpint).CmpInt64(bound) > 0 {
 			Yyerror("slice index out of bounds")
 		}
 	}
-	if Isconst(&k, CTINT) {
-		if k.Val().U.(*Mpint).CmpInt64(0) < 0 || bound >= 0 && k.Val().U.(*Mpint).CmpInt64(bound) > 0 {
+	if ir.Isconst(&k, ir.CTINT) {
+		if k.Val().U.(*ir.Mpint).CmpInt64(0) < 0 || bound >= 0 && k.Val().U.(*ir.Mpint).CmpInt64(bound) > 0 {
 			Yyerror("slice index out of bounds")
 		}
 	}
 
 	// same reports whether n1 and n2 are the same register or constant.
-	same := func(n1, n2 *Node) bool {
-		return n1.Op == OREGISTER && n2.Op == OREGISTER && n1.Reg == n2.Reg ||
-			n1.Op == ONAME && n2.Op == ONAME && n1.Orig == n2.Orig && n1.Type == n2.Type && n1.Xoffset == n2.Xoffset ||
-			n1.Op == OLITERAL && n2.Op == OLITERAL && n1.Val().U.(*Mpint).Cmp(n2.Val().U.(*Mpint)) == 0
+	same := func(n1, n2 *ir.Node) bool {
+		return n1.Op == ir.OREGISTER && n2.Op == ir.OREGISTER && n1.Reg == n2.Reg ||
+			n1.Op == ir.ONAME && n2.Op == ir.ONAME && n1.Orig == n2.Orig && n1.Type == n2.Type && n1.Xoffset == n2.Xoffset ||
+			n1.Op == ir.OLITERAL && n2.Op == ir.OLITERAL && n1.Val().U.(*ir.Mpint).Cmp(n2.Val().U.(*ir.Mpint)) == 0
 	}
 
 	// obvious reports whether n1 <= n2 is obviously true,
 	// and it calls Yyerror if n1 <= n2 is obviously false.
-	obvious := func(n1, n2 *Node) bool {
+	obvious := func(n1, n2 *ir.Node) bool {
 		if Debug['B'] != 0 { // -B disables bounds checks
 			return true
 		}
@@ -3225,8 +3248,8 @@ func cgen_slice(n, res *Node, wb bool) {
 		if xlen.Op != 0 && same(n1, &xlen) && xcap.Op != 0 && same(n2, &xcap) {
 			return true // len(x) <= cap(x) always true
 		}
-		if Isconst(n1, CTINT) && Isconst(n2, CTINT) {
-			if n1.Val().U.(*Mpint).Cmp(n2.Val().U.(*Mpint)) <= 0 {
+		if ir.Isconst(n1, ir.CTINT) && ir.Isconst(n2, ir.CTINT) {
+			if n1.Val().U.(*ir.Mpint).Cmp(n2.Val().U.(*ir.Mpint)) <= 0 {
 				return true // n1, n2 constants such that n1 <= n2
 			}
 			Yyerror("slice index out of bounds")
@@ -3235,24 +3258,24 @@ func cgen_slice(n, res *Node, wb bool) {
 		return false
 	}
 
-	compare := func(n1, n2 *Node) {
+	compare := func(n1, n2 *ir.Node) {
 		// n1 might be a 64-bit constant, even on 32-bit architectures,
 		// but it will be represented in 32 bits.
 		if Ctxt.Arch.Regsize == 4 && Is64(n1.Type) {
-			if n1.Val().U.(*Mpint).CmpInt64(1<<31) >= 0 {
+			if n1.Val().U.(*ir.Mpint).CmpInt64(1<<31) >= 0 {
 				Fatalf("missed slice out of bounds check")
 			}
-			var tmp Node
-			Nodconst(&tmp, indexRegType, n1.Val().U.(*Mpint).Int64())
+			var tmp ir.Node
+			Nodconst(&tmp, indexRegType, n1.Val().U.(*ir.Mpint).Int64())
 			n1 = &tmp
 		}
-		p := Thearch.Ginscmp(OGT, indexRegType, n1, n2, -1)
+		p := Thearch.Ginscmp(ir.OGT, indexRegType, n1, n2, -1)
 		panics = append(panics, p)
 	}
 
 	loadcap()
 	max := &xcap
-	if k.Op != 0 && (n.Op == OSLICE3 || n.Op == OSLICE3ARR) {
+	if k.Op != 0 && (n.Op == ir.OSLICE3 || n.Op == ir.OSLICE3ARR) {
 		if obvious(&k, max) {
 			if Debug_slice > 0 {
 				Warn("slice: omit check for 3rd index")
@@ -3303,10 +3326,10 @@ func cgen_slice(n, res *Node, wb bool) {
 
 	// Done with xlen and xcap.
 	// Now safe to modify j and k even if they alias xlen, xcap.
-	if xlen.Op == OREGISTER {
+	if xlen.Op == ir.OREGISTER {
 		Regfree(&xlen)
 	}
-	if xcap.Op == OREGISTER {
+	if xcap.Op == ir.OREGISTER {
 		Regfree(&xcap)
 	}
 
@@ -3319,37 +3342,37 @@ func cgen_slice(n, res *Node, wb bool) {
 			if Debug_slice > 0 {
 				Warn("slice: result len == 0")
 			}
-			if j.Op == OREGISTER {
+			if j.Op == ir.OREGISTER {
 				Regfree(&j)
 			}
 			Nodconst(&j, indexRegType, 0)
 		} else {
 			switch j.Op {
-			case OLITERAL:
-				if Isconst(&i, CTINT) {
-					Nodconst(&j, indexRegType, j.Val().U.(*Mpint).Int64()-i.Val().U.(*Mpint).Int64())
+			case ir.OLITERAL:
+				if ir.Isconst(&i, ir.CTINT) {
+					Nodconst(&j, indexRegType, j.Val().U.(*ir.Mpint).Int64()-i.Val().U.(*ir.Mpint).Int64())
 					if Debug_slice > 0 {
-						Warn("slice: result len == %d", j.Val().U.(*Mpint).Int64())
+						Warn("slice: result len == %d", j.Val().U.(*ir.Mpint).Int64())
 					}
 					break
 				}
 				fallthrough
-			case ONAME:
+			case ir.ONAME:
 				if !istemp(&j) {
-					var r Node
+					var r ir.Node
 					regalloc(&r, indexRegType, nil)
 					Thearch.Gmove(&j, &r)
 					j = r
 				}
 				fallthrough
-			case OREGISTER:
-				if i.Op == OLITERAL {
-					v := i.Val().U.(*Mpint).Int64()
+			case ir.OREGISTER:
+				if i.Op == ir.OLITERAL {
+					v := i.Val().U.(*ir.Mpint).Int64()
 					if v != 0 {
-						ginscon(Thearch.Optoas(OSUB, indexRegType), v, &j)
+						ginscon(Thearch.Optoas(ir.OSUB, indexRegType), v, &j)
 					}
 				} else {
-					gins(Thearch.Optoas(OSUB, indexRegType), &i, &j)
+					gins(Thearch.Optoas(ir.OSUB, indexRegType), &i, &j)
 				}
 			}
 		}
@@ -3359,12 +3382,12 @@ func cgen_slice(n, res *Node, wb bool) {
 		// we replace j with a constant 0 instead of doing a subtraction,
 		// leaving i unmodified.)
 		if k.Op == 0 {
-			if Debug_slice > 0 && n.Op != OSLICESTR {
+			if Debug_slice > 0 && n.Op != ir.OSLICESTR {
 				Warn("slice: result cap not computed")
 			}
 			// no need
 		} else if same(&i, &k) {
-			if k.Op == OREGISTER {
+			if k.Op == ir.OREGISTER {
 				Regfree(&k)
 			}
 			Nodconst(&k, indexRegType, 0)
@@ -3376,46 +3399,46 @@ func cgen_slice(n, res *Node, wb bool) {
 				Warn("slice: result cap == result len")
 			}
 			// k and j were the same value; make k-i the same as j-i.
-			if k.Op == OREGISTER {
+			if k.Op == ir.OREGISTER {
 				Regfree(&k)
 			}
 			k = j
-			if k.Op == OREGISTER {
+			if k.Op == ir.OREGISTER {
 				Regrealloc(&k)
 			}
 		} else {
 			switch k.Op {
-			case OLITERAL:
-				if Isconst(&i, CTINT) {
-					Nodconst(&k, indexRegType, k.Val().U.(*Mpint).Int64()-i.Val().U.(*Mpint).Int64())
+			case ir.OLITERAL:
+				if ir.Isconst(&i, ir.CTINT) {
+					Nodconst(&k, indexRegType, k.Val().U.(*ir.Mpint).Int64()-i.Val().U.(*ir.Mpint).Int64())
 					if Debug_slice > 0 {
-						Warn("slice: result cap == %d", k.Val().U.(*Mpint).Int64())
+						Warn("slice: result cap == %d", k.Val().U.(*ir.Mpint).Int64())
 					}
 					break
 				}
 				fallthrough
-			case ONAME:
+			case ir.ONAME:
 				if !istemp(&k) {
-					var r Node
+					var r ir.Node
 					regalloc(&r, indexRegType, nil)
 					Thearch.Gmove(&k, &r)
 					k = r
 				}
 				fallthrough
-			case OREGISTER:
+			case ir.OREGISTER:
 				if same(&i, &k) {
 					Regfree(&k)
 					Nodconst(&k, indexRegType, 0)
 					if Debug_slice > 0 {
 						Warn("slice: result cap == 0")
 					}
-				} else if i.Op == OLITERAL {
-					v := i.Val().U.(*Mpint).Int64()
+				} else if i.Op == ir.OLITERAL {
+					v := i.Val().U.(*ir.Mpint).Int64()
 					if v != 0 {
-						ginscon(Thearch.Optoas(OSUB, indexRegType), v, &k)
+						ginscon(Thearch.Optoas(ir.OSUB, indexRegType), v, &k)
 					}
 				} else {
-					gins(Thearch.Optoas(OSUB, indexRegType), &i, &k)
+					gins(Thearch.Optoas(ir.OSUB, indexRegType), &i, &k)
 				}
 			}
 		}
@@ -3429,7 +3452,7 @@ func cgen_slice(n, res *Node, wb bool) {
 		adjustBase = false
 	} else if k.Op != 0 && iszero(&k) || k.Op == 0 && iszero(&j) {
 		if Debug_slice > 0 {
-			if n.Op == OSLICESTR {
+			if n.Op == ir.OSLICESTR {
 				Warn("slice: skip base adjustment for string len == 0")
 			} else {
 				Warn("slice: skip base adjustment for cap == 0")
@@ -3439,6 +3462,7 @@ func cgen_slice(n, res *Node, wb bool) {
 	}
 
 	if !adjustBase && !needFullUpdate {
+		genwb.skipped = true
 		if Debug_slice > 0 {
 			if k.Op != 0 {
 				Warn("slice: len/cap-only update")
@@ -3446,24 +3470,24 @@ func cgen_slice(n, res *Node, wb bool) {
 				Warn("slice: len-only update")
 			}
 		}
-		if i.Op == OREGISTER {
+		if i.Op == ir.OREGISTER {
 			Regfree(&i)
 		}
 		// Write len (and cap if needed) back to x.
 		x.Xoffset += int64(Widthptr)
-		x.Type = Types[TUINT]
+		x.Type = ir.Types[ir.TUINT]
 		Thearch.Gmove(&j, &x)
 		x.Xoffset -= int64(Widthptr)
 		if k.Op != 0 {
 			x.Xoffset += 2 * int64(Widthptr)
-			x.Type = Types[TUINT]
+			x.Type = ir.Types[ir.TUINT]
 			Thearch.Gmove(&k, &x)
 			x.Xoffset -= 2 * int64(Widthptr)
 		}
 		Regfree(&x)
 	} else {
 		// Compute new base. May smash i.
-		if n.Op == OSLICEARR || n.Op == OSLICE3ARR {
+		if n.Op == ir.OSLICEARR || n.Op == ir.OSLICE3ARR {
 			Cgenr(n.Left, &xbase, nil)
 			Cgen_checknil(&xbase)
 		} else {
@@ -3479,50 +3503,50 @@ func cgen_slice(n, res *Node, wb bool) {
 			if k.Op == 0 {
 				size = &j
 			}
-			if Isconst(size, CTINT) {
+			if ir.Isconst(size, ir.CTINT) {
 				// zero was checked above, must be non-zero.
 			} else {
-				var tmp Node
+				var tmp ir.Node
 				Nodconst(&tmp, indexRegType, 0)
-				p = Thearch.Ginscmp(OEQ, indexRegType, size, &tmp, -1)
+				p = Thearch.Ginscmp(ir.OEQ, indexRegType, size, &tmp, -1)
 			}
 			var w int64
-			if n.Op == OSLICESTR {
+			if n.Op == ir.OSLICESTR {
 				w = 1 // res is string, elem size is 1 (byte)
 			} else {
 				w = res.Type.Type.Width // res is []T, elem size is T.width
 			}
-			if Isconst(&i, CTINT) {
-				ginscon(Thearch.Optoas(OADD, xbase.Type), i.Val().U.(*Mpint).Int64()*w, &xbase)
+			if ir.Isconst(&i, ir.CTINT) {
+				ginscon(Thearch.Optoas(ir.OADD, xbase.Type), i.Val().U.(*ir.Mpint).Int64()*w, &xbase)
 			} else if Thearch.AddIndex != nil && Thearch.AddIndex(&i, w, &xbase) {
 				// done by back end
 			} else if w == 1 {
-				gins(Thearch.Optoas(OADD, xbase.Type), &i, &xbase)
+				gins(Thearch.Optoas(ir.OADD, xbase.Type), &i, &xbase)
 			} else {
-				if i.Op == ONAME && !istemp(&i) {
-					var tmp Node
+				if i.Op == ir.ONAME && !istemp(&i) {
+					var tmp ir.Node
 					Tempname(&tmp, i.Type)
 					Thearch.Gmove(&i, &tmp)
 					i = tmp
 				}
-				ginscon(Thearch.Optoas(OMUL, i.Type), w, &i)
-				gins(Thearch.Optoas(OADD, xbase.Type), &i, &xbase)
+				ginscon(Thearch.Optoas(ir.OMUL, i.Type), w, &i)
+				gins(Thearch.Optoas(ir.OADD, xbase.Type), &i, &xbase)
 			}
 			if p != nil {
 				Patch(p, Pc)
 			}
 		}
-		if i.Op == OREGISTER {
+		if i.Op == ir.OREGISTER {
 			Regfree(&i)
 		}
 
 		// Write len, cap, base to result.
-		if res.Op == ONAME {
+		if res.Op == ir.ONAME {
 			Gvardef(res)
 		}
 		Igen(res, &x, nil)
 		x.Xoffset += int64(Widthptr)
-		x.Type = Types[TUINT]
+		x.Type = ir.Types[ir.TUINT]
 		Thearch.Gmove(&j, &x)
 		x.Xoffset -= int64(Widthptr)
 		if k.Op != 0 {
@@ -3536,10 +3560,10 @@ func cgen_slice(n, res *Node, wb bool) {
 		Regfree(&x)
 	}
 
-	if j.Op == OREGISTER {
+	if j.Op == ir.OREGISTER {
 		Regfree(&j)
 	}
-	if k.Op == OREGISTER {
+	if k.Op == ir.OREGISTER {
 		Regfree(&k)
 	}
 }
@@ -0,0 +1,94 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gc
+
+import (
+	"cmd/compile/internal/ir"
+	"strings"
+)
+
+// Checks of the cgo pointer passing rules.
+//
+// Go code may pass a Go pointer to C only if the memory it points to
+// contains no Go pointers. cmd/cgo enforces this by wrapping pointer
+// arguments of calls to C functions in calls to runtime.cgoCheckPointer,
+// but it works from the syntax alone and cannot see every call.
+// The compiler knows the types, so when ordering a call of a function
+// _Cfunc_f generated by cmd/cgo, it wraps every argument that might
+// point to a Go pointer, and that cmd/cgo has not already checked, in
+// the same call. As in cmd/cgo, a check of &x.f examines only x.f
+// rather than all of x.
+
+// iscgocall reports whether n calls a C function through its cmd/cgo
+// wrapper.
+func iscgocall(n *ir.Node) bool {
+	return n.Op == ir.OCALLFUNC && n.Left.Op == ir.ONAME && n.Left.Class == ir.PFUNC &&
+		n.Left.Sym.Pkg == localpkg && strings.HasPrefix(n.Left.Sym.Name, "_Cfunc_")
+}
+
+// cgocheckargs wraps each argument x of the C call n that needs a
+// pointer check in runtime.cgoCheckPointer(x).(T), as cmd/cgo does.
+// It must be called before the arguments are ordered, so that they
+// are still evaluated in their original order.
+func cgocheckargs(n *ir.Node) {
+	if compiling_runtime != 0 || !iscgocall(n) || n.Isddd {
+		return
+	}
+	if n.List.Len() == 1 && n.List.First().Type.Etype == ir.TSTRUCT && n.List.First().Type.Funarg {
+		// f(g()) with multiple results.
+		return
+	}
+	t, it := ir.IterFields(n.Left.Type.Params())
+	for i, arg := range n.List.Slice() {
+		if t == nil {
+			break
+		}
+		if cgoneedscheck(t.Type) && !cgochecked(arg) && !isnil(arg) {
+			call := Nod(ir.OCALL, syslook("cgoCheckPointer"), nil)
+			call.List.Set1(arg)
+			if isfieldaddr(arg) {
+				call.List.Append(Nodbool(true))
+			}
+			x := Nod(ir.ODOTTYPE, call, typenod(arg.Type))
+			n.List.SetIndex(i, typecheck(x, Erv))
+		}
+		t = it.Next()
+	}
+}
+
+// cgoneedscheck reports whether an argument of type t might
+// point to memory containing a Go pointer, as in cmd/cgo.
+func cgoneedscheck(t *ir.Type) bool {
+	switch t.Etype {
+	case ir.TUNSAFEPTR:
+		return true
+	case ir.TPTR32, ir.TPTR64:
+		return haspointers(t.Type)
+	}
+	return false
+}
+
+// cgochecked reports whether the argument n has already been
+// passed through one of the cgoCheckPointer wrappers written by cmd/cgo.
+func cgochecked(n *ir.Node) bool {
+	for {
+		switch n.Op {
+		case ir.OCONV, ir.OCONVNOP, ir.ODOTTYPE:
+			n = n.Left
+			continue
+		case ir.OCALLFUNC:
+			return n.Left.Op == ir.ONAME && strings.HasPrefix(n.Left.Sym.Name, "_cgoCheckPointer")
+		}
+		return false
+	}
+}
+
+// isfieldaddr reports whether n, ignoring conversions, is &x.f.
+func isfieldaddr(n *ir.Node) bool {
+	for n.Op == ir.OCONV || n.Op == ir.OCONVNOP {
+		n = n.Left
+	}
+	return n.Op == ir.OADDR && (n.Left.Op == ir.ODOT || n.Left.Op == ir.ODOTPTR)
+}
@@ -0,0 +1,49 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gc
+
+import (
+	"internal/testenv"
+	"strings"
+	"testing"
+)
+
+// Make sure pointer arguments of C calls are checked
+// unless cmd/cgo has already checked them.
+func TestCgoCheck(t *testing.T) {
+	testenv.MustHaveGoBuild(t)
+
+	src := `package p
+
+type T struct{ p *int }
+
+type S struct {
+	n int
+	t T
+}
+
+//go:noinline
+func _Cfunc_f(p *T, q *int) {}
+
+func _cgoCheckPointer(interface{}, ...interface{}) interface{} { return nil }
+
+func unchecked(s *S, q *int) {
+	_Cfunc_f(&s.t, q)
+}
+
+func checked(s *S, q *int) {
+	_Cfunc_f(_cgoCheckPointer(&s.t, true).(*T), q)
+}
+`
+	out := compileS(t, src)
+	text := funcText(out, "unchecked")
+	if n := strings.Count(text, "CALL\truntime.cgoCheckPointer(SB)"); n != 1 {
+		t.Errorf("unchecked: got %d cgoCheckPointer calls, want 1:\n%s", n, text)
+	}
+	text = funcText(out, "checked")
+	if text == "" || strings.Contains(text, "runtime.cgoCheckPointer") {
+		t.Errorf("checked: argument checked twice:\n%s", text)
+	}
+}
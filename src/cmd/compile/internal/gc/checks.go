@@ -0,0 +1,146 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gc
+
+import "cmd/compile/internal/ir"
+
+// Compiler-inserted runtime assertions.
+//
+// With -d=checks=N, the compiler adds cheap checks of properties it
+// expects to hold at run time. A failed check calls runtime.checkfailed,
+// which throws, or, for properties that correct programs may violate,
+// runtime.checkwarn, which prints a warning and returns. The checks are
+// meant for soak-testing the compiler and for tracking down memory
+// corruption in user programs.
+//
+// At level 1 the compiler checks that
+//
+//	- the result of slicing a slice or array has 0 <= len <= cap;
+//	- the result of slicing a string has len >= 0;
+//	- the lengths and capacities passed to make are not negative.
+//
+// The bounds checks on the slice indexes already ensure the slice
+// properties, except under -B or if the compiler is wrong.
+//
+// Level 2 adds a warning, once per loop, if a map ranged over directly
+// by name is grown by its own loop body, when the body assigns to
+// elements of the map. Adding entries during iteration is legal, so
+// this is not an error, but it makes it unpredictable whether the loop
+// visits them.
+
+// checkstmt returns the typechecked statement
+//	if cond { checkfailed(msg) }
+func checkstmt(cond *ir.Node, msg string) *ir.Node {
+	return checkstmtcall(cond, "checkfailed", msg)
+}
+
+// checkstmtcall returns the typechecked statement
+//	if cond { fn(msg); extra... }
+func checkstmtcall(cond *ir.Node, fn, msg string, extra ...*ir.Node) *ir.Node {
+	call := Nod(ir.OCALL, syslook(fn), nil)
+	call.List.Set1(nodlit(ir.Val{U: msg}))
+	n := Nod(ir.OIF, cond, nil)
+	n.Nbody.Set(append([]*ir.Node{call}, extra...))
+	n.Likely = -1
+	return typecheck(n, Etop)
+}
+
+// walkcheckslice returns the already walked slice expression n,
+// evaluated into a temporary, after appending to init a check that
+// the result has 0 <= len <= cap, or, for a string, len >= 0.
+func walkcheckslice(n *ir.Node, init *ir.Nodes) *ir.Node {
+	if Debug_checks == 0 || compiling_runtime != 0 || init == nil {
+		return n
+	}
+	switch n.Op {
+	case ir.OSLICE, ir.OSLICEARR, ir.OSLICE3, ir.OSLICE3ARR, ir.OSLICESTR:
+	default:
+		return n
+	}
+
+	// Not copyexpr: walking the assignment would walk n again.
+	tmp := temp(n.Type)
+	a := Nod(ir.OAS, tmp, n)
+	a.Typecheck = 1
+	init.Append(a)
+
+	bad := Nod(ir.OLT, Nod(ir.OLEN, tmp, nil), Nodintconst(0))
+	msg := "checks: negative string length"
+	if n.Op != ir.OSLICESTR {
+		// cap < 0 implies len < 0 or len > cap.
+		bad = Nod(ir.OOROR, bad, Nod(ir.OGT, Nod(ir.OLEN, tmp, nil), Nod(ir.OCAP, tmp, nil)))
+		msg = "checks: slice length exceeds capacity or is negative"
+	}
+	init.Append(walkstmt(checkstmt(bad, msg)))
+	return tmp
+}
+
+// walkcheckmake returns the length or capacity n of a make call,
+// evaluated only once, after appending to init a check that it is
+// not negative.
+func walkcheckmake(n *ir.Node, init *ir.Nodes) *ir.Node {
+	if Debug_checks == 0 || compiling_runtime != 0 || init == nil {
+		return n
+	}
+	if n == nil || ir.Isconst(n, ir.CTINT) || !Issigned[n.Type.Etype] {
+		return n
+	}
+	n = walkexpr(n, init)
+	n = cheapexpr(n, init)
+	a := checkstmt(Nod(ir.OLT, n, Nodintconst(0)), "checks: negative length or capacity passed to make")
+	init.Append(walkstmt(a))
+	return n
+}
+
+// ordercheckrange adds the level 2 map growth warning to the range
+// statement n over the map m, whose ordered copy is h. It must be
+// called before the loop body is ordered.
+func ordercheckrange(n, m, h *ir.Node, order *Order) {
+	if Debug_checks < 2 || compiling_runtime != 0 || m.Op != ir.ONAME {
+		return
+	}
+	if !writesmap(n.Nbody.Slice(), m) {
+		return
+	}
+
+	// hn := len(h)
+	// for ... range h {
+	//	if len(h) > hn { checkwarn(...); hn = maxint }
+	//	...
+	// }
+	//
+	// Setting hn to maxint warns only once per loop.
+	hn := ordertemp(ir.Types[ir.TINT], order, false)
+	a := Nod(ir.OAS, hn, Nod(ir.OLEN, h, nil))
+	order.out = append(order.out, typecheck(a, Etop))
+	max := new(ir.Mpint)
+	max.Set(Maxintval[ir.TINT])
+	a = checkstmtcall(Nod(ir.OGT, Nod(ir.OLEN, h, nil), hn), "checkwarn", "checks: map grown during its own range loop",
+		Nod(ir.OAS, hn, nodlit(ir.Val{U: max})))
+	n.Nbody.Set(append([]*ir.Node{a}, n.Nbody.Slice()...))
+}
+
+// writesmap reports whether the statements in l assign to an element
+// of the map variable m. Closure bodies are not examined.
+func writesmap(l []*ir.Node, m *ir.Node) bool {
+	for _, n := range l {
+		if writesmapnode(n, m) {
+			return true
+		}
+	}
+	return false
+}
+
+func writesmapnode(n *ir.Node, m *ir.Node) bool {
+	if n == nil || n.Op == ir.OCLOSURE {
+		return false
+	}
+	if n.Op == ir.OINDEXMAP && n.Etype == 1 && n.Left == m {
+		return true
+	}
+	return writesmapnode(n.Left, m) || writesmapnode(n.Right, m) ||
+		writesmap(n.Ninit.Slice(), m) || writesmap(n.Nbody.Slice(), m) ||
+		writesmap(n.List.Slice(), m) || writesmap(n.Rlist.Slice(), m)
+}
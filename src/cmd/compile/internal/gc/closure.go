@@ -5,15 +5,16 @@
 package gc
 
 import (
+	"cmd/compile/internal/ir"
 	"fmt"
 )
 
 // function literals aka closures
-func closurehdr(ntype *Node) {
-	var name *Node
-	var a *Node
+func closurehdr(ntype *ir.Node) {
+	var name *ir.Node
+	var a *ir.Node
 
-	n := Nod(OCLOSURE, nil, nil)
+	n := Nod(ir.OCLOSURE, nil, nil)
 	n.Func.Ntype = ntype
 	n.Func.Depth = Funcdepth
 	n.Func.Outerfunc = Curfn
@@ -35,7 +36,7 @@ func closurehdr(ntype *Node) {
 		if name != nil {
 			name = newname(name.Sym)
 		}
-		a = Nod(ODCLFIELD, name, n1.Right)
+		a = Nod(ir.ODCLFIELD, name, n1.Right)
 		a.Isddd = n1.Isddd
 		if name != nil {
 			name.Isddd = a.Isddd
@@ -47,13 +48,13 @@ func closurehdr(ntype *Node) {
 		if name != nil {
 			name = newname(name.Sym)
 		}
-		ntype.Rlist.Append(Nod(ODCLFIELD, name, n2.Right))
+		ntype.Rlist.Append(Nod(ir.ODCLFIELD, name, n2.Right))
 	}
 }
 
-func closurebody(body []*Node) *Node {
+func closurebody(body []*ir.Node) *ir.Node {
 	if len(body) == 0 {
-		body = []*Node{Nod(OEMPTY, nil, nil)}
+		body = []*ir.Node{Nod(ir.OEMPTY, nil, nil)}
 	}
 
 	func_ := Curfn
@@ -73,25 +74,28 @@ func closurebody(body []*Node) *Node {
 	return func_
 }
 
-func typecheckclosure(func_ *Node, top int) {
+func typecheckclosure(func_ *ir.Node, top int) {
 	for _, ln := range func_.Func.Cvars.Slice() {
 		n := ln.Name.Param.Closure
 		if !n.Name.Captured {
 			n.Name.Captured = true
 			if n.Name.Decldepth == 0 {
-				Fatalf("typecheckclosure: var %v does not have decldepth assigned", Nconv(n, FmtShort))
+				Fatalf("typecheckclosure: var %v does not have decldepth assigned", Nconv(n, ir.FmtShort))
 			}
 
-			// Ignore assignments to the variable in straightline code
-			// preceding the first capturing by a closure.
-			if n.Name.Decldepth == decldepth {
+			// Ignore assignments to the variable that cannot happen
+			// after the first capturing by a closure: those in
+			// straightline code preceding it, and those preceding
+			// the outermost loop (or label) that encloses the capture
+			// but not the declaration.
+			if n.Name.Decldepth == decldepth || n.Name.Decldepth < decldepth && n.Name.Param.Assigngen < loopstart[n.Name.Decldepth+1] {
 				n.Assigned = false
 			}
 		}
 	}
 
 	for _, ln := range func_.Func.Dcl {
-		if ln.Op == ONAME && (ln.Class == PPARAM || ln.Class == PPARAMOUT) {
+		if ln.Op == ir.ONAME && (ln.Class == ir.PPARAM || ln.Class == ir.PPARAMOUT) {
 			ln.Name.Decldepth = 1
 		}
 	}
@@ -100,6 +104,7 @@ func typecheckclosure(func_ *Node, top int) {
 	func_.Func.Ntype = typecheck(func_.Func.Ntype, Etype)
 	func_.Type = func_.Func.Ntype.Type
 	func_.Func.Top = top
+	func_.Func.Iota = curiota() // for the body, if Main checks it later
 
 	// Type check the body now, but only if we're inside a function.
 	// At top level (in a variable initialization: curfn==nil) we're not
@@ -108,9 +113,12 @@ func typecheckclosure(func_ *Node, top int) {
 	if Curfn != nil && func_.Type != nil {
 		Curfn = func_
 		olddd := decldepth
+		oldls := loopstart
 		decldepth = 1
+		loopstart = nil
 		typecheckslice(func_.Nbody.Slice(), Etop)
 		decldepth = olddd
+		loopstart = oldls
 		Curfn = oldfn
 	}
 
@@ -126,7 +134,7 @@ func typecheckclosure(func_ *Node, top int) {
 
 var closurename_closgen int
 
-func closurename(n *Node) *Sym {
+func closurename(n *ir.Node) *ir.Sym {
 	if n.Sym != nil {
 		return n.Sym
 	}
@@ -140,7 +148,7 @@ func closurename(n *Node) *Sym {
 		prefix = "func"
 		closurename_closgen++
 		gen = closurename_closgen
-	} else if n.Func.Outerfunc.Op == ODCLFUNC {
+	} else if n.Func.Outerfunc.Op == ir.ODCLFUNC {
 		// The outermost closure inside of a named function.
 		outer = n.Func.Outerfunc.Func.Nname.Sym.Name
 
@@ -156,7 +164,7 @@ func closurename(n *Node) *Sym {
 			closurename_closgen++
 			gen = closurename_closgen
 		}
-	} else if n.Func.Outerfunc.Op == OCLOSURE {
+	} else if n.Func.Outerfunc.Op == ir.OCLOSURE {
 		// Nested closure, recurse.
 		outer = closurename(n.Func.Outerfunc).Name
 
@@ -164,31 +172,32 @@ func closurename(n *Node) *Sym {
 		n.Func.Outerfunc.Func.Closgen++
 		gen = n.Func.Outerfunc.Func.Closgen
 	} else {
-		Fatalf("closurename called for %v", Nconv(n, FmtShort))
+		Fatalf("closurename called for %v", Nconv(n, ir.FmtShort))
 	}
 	n.Sym = Lookupf("%s.%s%d", outer, prefix, gen)
 	return n.Sym
 }
 
-func makeclosure(func_ *Node) *Node {
+func makeclosure(func_ *ir.Node) *ir.Node {
 	// wrap body in external function
 	// that begins by reading closure parameters.
-	xtype := Nod(OTFUNC, nil, nil)
+	xtype := Nod(ir.OTFUNC, nil, nil)
 
 	xtype.List.Set(func_.List.Slice())
 	xtype.Rlist.Set(func_.Rlist.Slice())
 
 	// create the function
-	xfunc := Nod(ODCLFUNC, nil, nil)
+	xfunc := Nod(ir.ODCLFUNC, nil, nil)
 
 	xfunc.Func.Nname = newfuncname(closurename(func_))
-	xfunc.Func.Nname.Sym.Flags |= SymExported // disable export
+	xfunc.Func.Nname.Sym.Flags |= ir.SymExported // disable export
 	xfunc.Func.Nname.Name.Param.Ntype = xtype
 	xfunc.Func.Nname.Name.Defn = xfunc
-	declare(xfunc.Func.Nname, PFUNC)
+	declare(xfunc.Func.Nname, ir.PFUNC)
 	xfunc.Func.Nname.Name.Funcdepth = func_.Func.Depth
 	xfunc.Func.Depth = func_.Func.Depth
 	xfunc.Func.Endlineno = func_.Func.Endlineno
+	xfunc.Func.Block = func_.Func.Block
 	makefuncsym(xfunc.Func.Nname.Sym)
 
 	xfunc.Nbody.Set(func_.Nbody.Slice())
@@ -214,8 +223,8 @@ func makeclosure(func_ *Node) *Node {
 // by value or by reference.
 // We use value capturing for values <= 128 bytes that are never reassigned
 // after capturing (effectively constant).
-func capturevars(xfunc *Node) {
-	var outer *Node
+func capturevars(xfunc *ir.Node) {
+	var outer *ir.Node
 
 	lno := lineno
 	lineno = xfunc.Lineno
@@ -230,7 +239,7 @@ func capturevars(xfunc *Node) {
 			// the a, b, c gets parsed as references to older
 			// a, b, c before the parser figures out this is a
 			// declaration.
-			v.Op = OXXX
+			v.Op = ir.OXXX
 
 			continue
 		}
@@ -243,15 +252,19 @@ func capturevars(xfunc *Node) {
 		v.Name.Param.Outerexpr = nil
 
 		// out parameters will be assigned to implicitly upon return.
-		if outer.Class != PPARAMOUT && !v.Name.Param.Closure.Addrtaken && !v.Name.Param.Closure.Assigned && v.Type.Width <= 128 {
+		if outer.Class != ir.PPARAMOUT && !v.Name.Param.Closure.Addrtaken && !v.Name.Param.Closure.Assigned && v.Type.Width <= 128 {
 			v.Name.Byval = true
 		} else {
 			v.Name.Param.Closure.Addrtaken = true
-			outer = Nod(OADDR, outer, nil)
+			outer = Nod(ir.OADDR, outer, nil)
+		}
+
+		if Debug_capture != 0 {
+			Warnl(v.Lineno, "%v captured by %s", v.Sym, capturewhy(v))
 		}
 
 		if Debug['m'] > 1 {
-			var name *Sym
+			var name *ir.Sym
 			if v.Name.Curfn != nil && v.Name.Curfn.Func.Nname != nil {
 				name = v.Name.Curfn.Func.Nname.Sym
 			}
@@ -269,9 +282,156 @@ func capturevars(xfunc *Node) {
 	lineno = lno
 }
 
+// capturewhy describes how the closure variable v is captured
+// and, for capture by reference, why.
+func capturewhy(v *ir.Node) string {
+	if v.Name.Byval {
+		return "value"
+	}
+	c := v.Name.Param.Closure
+	switch {
+	case c.Class == ir.PPARAMOUT:
+		return "ref (result parameter)"
+	case v.Type.Width > 128:
+		return fmt.Sprintf("ref (%d bytes is too large to copy)", v.Type.Width)
+	case c.Addrtaken && c.Name.Param.Addrline != 0:
+		return fmt.Sprintf("ref (address taken at %v)", linestr(c.Name.Param.Addrline))
+	case c.Assigned && c.Name.Param.Assignline != 0:
+		line := c.Name.Param.Assignline
+		if line < v.Name.Curfn.Lineno {
+			// In a loop: the assignment follows the capture
+			// made by the previous iteration.
+			return fmt.Sprintf("ref (assigned at %v)", linestr(line))
+		}
+		return fmt.Sprintf("ref (assigned after capture at %v)", linestr(line))
+	}
+	return "ref"
+}
+
+// directclosures finds the closures in fn that are assigned to a local
+// variable, do not escape, and are only ever called through that
+// variable, as in
+//	f := func(x int) { ... }
+//	f(1)
+//	f(2)
+// It rewrites the calls to call the closure directly, as in
+//	func(x int) { ... }(1)
+// so that transformclosure passes the captured variables
+// as hidden arguments instead of allocating a closure.
+// It must run after escape analysis and before transformclosure.
+func directclosures(fn *ir.Node) {
+	var vars []*ir.Node                 // candidate variables, in order
+	defs := make(map[*ir.Node]*ir.Node) // variable -> defining OAS
+	calls := make(map[*ir.Node][]*ir.Node)
+	bad := make(map[*ir.Node]bool)
+
+	var finddefs func(n *ir.Node)
+	finddefs = func(n *ir.Node) {
+		if n == nil || n.Op == ir.OCLOSURE {
+			return
+		}
+		if n.Op == ir.OAS && n.Right != nil && n.Right.Op == ir.OCLOSURE && n.Right.Esc == EscNone {
+			v := n.Left
+			if v.Op == ir.ONAME && v.Class == ir.PAUTO && v.Name.Defn == n && !v.Assigned && !v.Addrtaken && !v.Name.Captured {
+				vars = append(vars, v)
+				defs[v] = n
+			}
+		}
+		for _, l := range []ir.Nodes{n.Ninit, n.List, n.Nbody, n.Rlist} {
+			for _, n1 := range l.Slice() {
+				finddefs(n1)
+			}
+		}
+		finddefs(n.Left)
+		finddefs(n.Right)
+	}
+	for _, n := range fn.Nbody.Slice() {
+		finddefs(n)
+	}
+	if len(vars) == 0 {
+		return
+	}
+
+	// Any use of a variable other than as the function
+	// in a call disqualifies it.
+	var finduses func(n *ir.Node)
+	finduses = func(n *ir.Node) {
+		if n == nil || n.Op == ir.OCLOSURE {
+			return
+		}
+		left := n.Left
+		switch n.Op {
+		case ir.ONAME:
+			if defs[n] != nil {
+				bad[n] = true
+			}
+			return
+
+		case ir.ODCL:
+			return
+
+		case ir.OAS:
+			if defs[n.Left] == n {
+				left = nil
+			}
+
+		case ir.OCALLFUNC:
+			if defs[n.Left] != nil {
+				calls[n.Left] = append(calls[n.Left], n)
+				left = nil
+			}
+
+		case ir.OPROC, ir.ODEFER:
+			// The call would be evaluated later, possibly
+			// after the captured variables are gone.
+			if n.Left.Op == ir.OCALLFUNC && defs[n.Left.Left] != nil {
+				bad[n.Left.Left] = true
+			}
+		}
+		for _, l := range []ir.Nodes{n.Ninit, n.List, n.Nbody, n.Rlist} {
+			for _, n1 := range l.Slice() {
+				finduses(n1)
+			}
+		}
+		finduses(left)
+		finduses(n.Right)
+	}
+	for _, n := range fn.Nbody.Slice() {
+		finduses(n)
+	}
+
+	for _, v := range vars {
+		if bad[v] || len(calls[v]) == 0 {
+			continue
+		}
+		as := defs[v]
+		clo := as.Right
+		// A direct call reads the variables captured by value
+		// at the call instead of at the closure, which is only
+		// the same if nothing can assign them in between.
+		// capturevars captures by value only variables it knows
+		// are not assigned after their first capture.
+		for _, cv := range clo.Func.Cvars.Slice() {
+			if cv.Name.Byval && cv.Name.Param.Closure.Assigned {
+				Fatalf("directclosures: %v captured by value but assigned", Nconv(cv, ir.FmtShort))
+			}
+		}
+		if Debug['m'] > 1 {
+			Warnl(clo.Lineno, "calling %v directly", Nconv(clo, ir.FmtShort))
+		}
+		clo.Func.Top |= Ecall
+		for _, call := range calls[v] {
+			call.Left = clo
+		}
+		as.Op = ir.OEMPTY
+		as.Left = nil
+		as.Right = nil
+	}
+}
+
 // transformclosure is called in a separate phase after escape analysis.
 // It transform closure bodies to properly reference captured variables.
-func transformclosure(xfunc *Node) {
+func transformclosure(xfunc *ir.Node) {
 	lno := lineno
 	lineno = xfunc.Lineno
 	func_ := xfunc.Func.Closure
@@ -296,17 +456,17 @@ func transformclosure(xfunc *Node) {
 		f := xfunc.Func.Nname
 
 		// We are going to insert captured variables before input args.
-		var params []*Field
-		var decls []*Node
+		var params []*ir.Field
+		var decls []*ir.Node
 		for _, v := range func_.Func.Cvars.Slice() {
-			if v.Op == OXXX {
+			if v.Op == ir.OXXX {
 				continue
 			}
-			fld := newField()
+			fld := ir.NewField()
 			fld.Funarg = true
 			if v.Name.Byval {
 				// If v is captured by value, we merely downgrade it to PPARAM.
-				v.Class = PPARAM
+				v.Class = ir.PPARAM
 
 				v.Ullman = 1
 				fld.Nname = v
@@ -317,7 +477,7 @@ func transformclosure(xfunc *Node) {
 				// (accesses will implicitly deref &v).
 				addr := newname(Lookupf("&%s", v.Sym.Name))
 				addr.Type = Ptrto(v.Type)
-				addr.Class = PPARAM
+				addr.Class = ir.PPARAM
 				v.Name.Heapaddr = addr
 				fld.Nname = addr
 			}
@@ -343,17 +503,17 @@ func transformclosure(xfunc *Node) {
 		xfunc.Type = f.Type // update type of ODCLFUNC
 	} else {
 		// The closure is not called, so it is going to stay as closure.
-		var body []*Node
+		var body []*ir.Node
 		offset := int64(Widthptr)
-		var addr *Node
-		var cv *Node
+		var addr *ir.Node
+		var cv *ir.Node
 		for _, v := range func_.Func.Cvars.Slice() {
-			if v.Op == OXXX {
+			if v.Op == ir.OXXX {
 				continue
 			}
 
 			// cv refers to the field inside of closure OSTRUCTLIT.
-			cv = Nod(OCLOSUREVAR, nil, nil)
+			cv = Nod(ir.OCLOSUREVAR, nil, nil)
 
 			cv.Type = v.Type
 			if !v.Name.Byval {
@@ -365,24 +525,24 @@ func transformclosure(xfunc *Node) {
 
 			if v.Name.Byval && v.Type.Width <= int64(2*Widthptr) {
 				// If it is a small variable captured by value, downgrade it to PAUTO.
-				v.Class = PAUTO
+				v.Class = ir.PAUTO
 				v.Ullman = 1
 				xfunc.Func.Dcl = append(xfunc.Func.Dcl, v)
-				body = append(body, Nod(OAS, v, cv))
+				body = append(body, Nod(ir.OAS, v, cv))
 			} else {
 				// Declare variable holding addresses taken from closure
 				// and initialize in entry prologue.
 				addr = newname(Lookupf("&%s", v.Sym.Name))
-				addr.Name.Param.Ntype = Nod(OIND, typenod(v.Type), nil)
-				addr.Class = PAUTO
+				addr.Name.Param.Ntype = Nod(ir.OIND, typenod(v.Type), nil)
+				addr.Class = ir.PAUTO
 				addr.Used = true
 				addr.Name.Curfn = xfunc
 				xfunc.Func.Dcl = append(xfunc.Func.Dcl, addr)
 				v.Name.Heapaddr = addr
 				if v.Name.Byval {
-					cv = Nod(OADDR, cv, nil)
+					cv = Nod(ir.OADDR, cv, nil)
 				}
-				body = append(body, Nod(OAS, addr, cv))
+				body = append(body, Nod(ir.OAS, addr, cv))
 			}
 		}
 
@@ -397,7 +557,7 @@ func transformclosure(xfunc *Node) {
 	lineno = lno
 }
 
-func walkclosure(func_ *Node, init *Nodes) *Node {
+func walkclosure(func_ *ir.Node, init *ir.Nodes) *ir.Node {
 	// If no closure vars, don't bother wrapping.
 	if len(func_.Func.Cvars.Slice()) == 0 {
 		return func_.Func.Closure.Func.Nname
@@ -417,28 +577,28 @@ func walkclosure(func_ *Node, init *Nodes) *Node {
 	// the struct is unnamed so that closures in multiple packages with the
 	// same struct type can share the descriptor.
 
-	typ := Nod(OTSTRUCT, nil, nil)
+	typ := Nod(ir.OTSTRUCT, nil, nil)
 
-	typ.List.Set1(Nod(ODCLFIELD, newname(Lookup(".F")), typenod(Types[TUINTPTR])))
-	var typ1 *Node
+	typ.List.Set1(Nod(ir.ODCLFIELD, newname(Lookup(".F")), typenod(ir.Types[ir.TUINTPTR])))
+	var typ1 *ir.Node
 	for _, v := range func_.Func.Cvars.Slice() {
-		if v.Op == OXXX {
+		if v.Op == ir.OXXX {
 			continue
 		}
 		typ1 = typenod(v.Type)
 		if !v.Name.Byval {
-			typ1 = Nod(OIND, typ1, nil)
+			typ1 = Nod(ir.OIND, typ1, nil)
 		}
-		typ.List.Append(Nod(ODCLFIELD, newname(v.Sym), typ1))
+		typ.List.Append(Nod(ir.ODCLFIELD, newname(v.Sym), typ1))
 	}
 
-	clos := Nod(OCOMPLIT, nil, Nod(OIND, typ, nil))
+	clos := Nod(ir.OCOMPLIT, nil, Nod(ir.OIND, typ, nil))
 	clos.Esc = func_.Esc
 	clos.Right.Implicit = true
-	clos.List.Set(append([]*Node{Nod(OCFUNC, func_.Func.Closure.Func.Nname, nil)}, func_.Func.Enter.Slice()...))
+	clos.List.Set(append([]*ir.Node{Nod(ir.OCFUNC, func_.Func.Closure.Func.Nname, nil)}, func_.Func.Enter.Slice()...))
 
 	// Force type conversion from *struct to the func type.
-	clos = Nod(OCONVNOP, clos, nil)
+	clos = Nod(ir.OCONVNOP, clos, nil)
 
 	clos.Type = func_.Type
 
@@ -460,9 +620,9 @@ func walkclosure(func_ *Node, init *Nodes) *Node {
 	return walkexpr(clos, init)
 }
 
-func typecheckpartialcall(fn *Node, sym *Sym) {
+func typecheckpartialcall(fn *ir.Node, sym *ir.Sym) {
 	switch fn.Op {
-	case ODOTINTER, ODOTMETH:
+	case ir.ODOTINTER, ir.ODOTMETH:
 		break
 
 	default:
@@ -473,30 +633,30 @@ func typecheckpartialcall(fn *Node, sym *Sym) {
 	xfunc := makepartialcall(fn, fn.Type, sym)
 	fn.Func = xfunc.Func
 	fn.Right = newname(sym)
-	fn.Op = OCALLPART
+	fn.Op = ir.OCALLPART
 	fn.Type = xfunc.Type
 }
 
-var makepartialcall_gopkg *Pkg
+var makepartialcall_gopkg *ir.Pkg
 
-func makepartialcall(fn *Node, t0 *Type, meth *Sym) *Node {
+func makepartialcall(fn *ir.Node, t0 *ir.Type, meth *ir.Sym) *ir.Node {
 	var p string
 
 	rcvrtype := fn.Left.Type
 	if exportname(meth.Name) {
-		p = fmt.Sprintf("(%v).%s-fm", Tconv(rcvrtype, FmtLeft|FmtShort), meth.Name)
+		p = fmt.Sprintf("(%v).%s-fm", Tconv(rcvrtype, ir.FmtLeft|ir.FmtShort), meth.Name)
 	} else {
-		p = fmt.Sprintf("(%v).(%v)-fm", Tconv(rcvrtype, FmtLeft|FmtShort), Sconv(meth, FmtLeft))
+		p = fmt.Sprintf("(%v).(%v)-fm", Tconv(rcvrtype, ir.FmtLeft|ir.FmtShort), Sconv(meth, ir.FmtLeft))
 	}
 	basetype := rcvrtype
 	if Isptr[rcvrtype.Etype] {
 		basetype = basetype.Type
 	}
-	if basetype.Etype != TINTER && basetype.Sym == nil {
+	if basetype.Etype != ir.TINTER && basetype.Sym == nil {
 		Fatalf("missing base type for %v", rcvrtype)
 	}
 
-	var spkg *Pkg
+	var spkg *ir.Pkg
 	if basetype.Sym != nil {
 		spkg = basetype.Sym.Pkg
 	}
@@ -509,30 +669,30 @@ func makepartialcall(fn *Node, t0 *Type, meth *Sym) *Node {
 
 	sym := Pkglookup(p, spkg)
 
-	if sym.Flags&SymUniq != 0 {
+	if sym.Flags&ir.SymUniq != 0 {
 		return sym.Def
 	}
-	sym.Flags |= SymUniq
+	sym.Flags |= ir.SymUniq
 
 	savecurfn := Curfn
 	Curfn = nil
 
-	xtype := Nod(OTFUNC, nil, nil)
+	xtype := Nod(ir.OTFUNC, nil, nil)
 	i := 0
-	var l []*Node
-	var callargs []*Node
+	var l []*ir.Node
+	var callargs []*ir.Node
 	ddd := false
-	xfunc := Nod(ODCLFUNC, nil, nil)
+	xfunc := Nod(ir.ODCLFUNC, nil, nil)
 	Curfn = xfunc
-	var fld *Node
-	var n *Node
+	var fld *ir.Node
+	var n *ir.Node
 	for _, t := range t0.Params().Fields().Slice() {
 		n = newname(LookupN("a", i))
 		i++
-		n.Class = PPARAM
+		n.Class = ir.PPARAM
 		xfunc.Func.Dcl = append(xfunc.Func.Dcl, n)
 		callargs = append(callargs, n)
-		fld = Nod(ODCLFIELD, n, typenod(t.Type))
+		fld = Nod(ir.ODCLFIELD, n, typenod(t.Type))
 		if t.Isddd {
 			fld.Isddd = true
 			ddd = true
@@ -544,63 +704,63 @@ func makepartialcall(fn *Node, t0 *Type, meth *Sym) *Node {
 	xtype.List.Set(l)
 	i = 0
 	l = nil
-	var retargs []*Node
+	var retargs []*ir.Node
 	for _, t := range t0.Results().Fields().Slice() {
 		n = newname(LookupN("r", i))
 		i++
-		n.Class = PPARAMOUT
+		n.Class = ir.PPARAMOUT
 		xfunc.Func.Dcl = append(xfunc.Func.Dcl, n)
 		retargs = append(retargs, n)
-		l = append(l, Nod(ODCLFIELD, n, typenod(t.Type)))
+		l = append(l, Nod(ir.ODCLFIELD, n, typenod(t.Type)))
 	}
 
 	xtype.Rlist.Set(l)
 
 	xfunc.Func.Dupok = true
 	xfunc.Func.Nname = newfuncname(sym)
-	xfunc.Func.Nname.Sym.Flags |= SymExported // disable export
+	xfunc.Func.Nname.Sym.Flags |= ir.SymExported // disable export
 	xfunc.Func.Nname.Name.Param.Ntype = xtype
 	xfunc.Func.Nname.Name.Defn = xfunc
-	declare(xfunc.Func.Nname, PFUNC)
+	declare(xfunc.Func.Nname, ir.PFUNC)
 
 	// Declare and initialize variable holding receiver.
 
 	xfunc.Func.Needctxt = true
-	cv := Nod(OCLOSUREVAR, nil, nil)
+	cv := Nod(ir.OCLOSUREVAR, nil, nil)
 	cv.Xoffset = int64(Widthptr)
 	cv.Type = rcvrtype
 	if int(cv.Type.Align) > Widthptr {
 		cv.Xoffset = int64(cv.Type.Align)
 	}
-	ptr := Nod(ONAME, nil, nil)
+	ptr := Nod(ir.ONAME, nil, nil)
 	ptr.Sym = Lookup("rcvr")
-	ptr.Class = PAUTO
+	ptr.Class = ir.PAUTO
 	ptr.Addable = true
 	ptr.Ullman = 1
 	ptr.Used = true
 	ptr.Name.Curfn = xfunc
 	ptr.Xoffset = 0
 	xfunc.Func.Dcl = append(xfunc.Func.Dcl, ptr)
-	var body []*Node
+	var body []*ir.Node
 	if Isptr[rcvrtype.Etype] || Isinter(rcvrtype) {
 		ptr.Name.Param.Ntype = typenod(rcvrtype)
-		body = append(body, Nod(OAS, ptr, cv))
+		body = append(body, Nod(ir.OAS, ptr, cv))
 	} else {
 		ptr.Name.Param.Ntype = typenod(Ptrto(rcvrtype))
-		body = append(body, Nod(OAS, ptr, Nod(OADDR, cv, nil)))
+		body = append(body, Nod(ir.OAS, ptr, Nod(ir.OADDR, cv, nil)))
 	}
 
-	call := Nod(OCALL, NodSym(OXDOT, ptr, meth), nil)
+	call := Nod(ir.OCALL, NodSym(ir.OXDOT, ptr, meth), nil)
 	call.List.Set(callargs)
 	call.Isddd = ddd
 	if t0.Results().NumFields() == 0 {
 		body = append(body, call)
 	} else {
-		n := Nod(OAS2, nil, nil)
+		n := Nod(ir.OAS2, nil, nil)
 		n.List.Set(retargs)
 		n.Rlist.Set1(call)
 		body = append(body, n)
-		n = Nod(ORETURN, nil, nil)
+		n = Nod(ir.ORETURN, nil, nil)
 		body = append(body, n)
 	}
 
@@ -614,7 +774,26 @@ func makepartialcall(fn *Node, t0 *Type, meth *Sym) *Node {
 	return xfunc
 }
 
-func walkpartialcall(n *Node, init *Nodes) *Node {
+// staticpartialcall reports whether the closure for the method
+// value n can be static data: its receiver is either the address
+// of a global variable or a zero-width value with no side effects.
+func staticpartialcall(n *ir.Node) bool {
+	x := n.Left
+	switch {
+	case x.Op == ir.OADDR:
+		return x.Left.Op == ir.ONAME && x.Left.Class == ir.PEXTERN
+	case x.Type.Width == 0:
+		switch x.Op {
+		case ir.ONAME:
+			return true
+		case ir.OSTRUCTLIT, ir.OARRAYLIT:
+			return x.List.Len() == 0
+		}
+	}
+	return false
+}
+
+func walkpartialcall(n *ir.Node, init *ir.Nodes) *ir.Node {
 	// Create closure in the form of a composite literal.
 	// For x.M with receiver (x) type T, the generated code looks like:
 	//
@@ -630,18 +809,38 @@ func walkpartialcall(n *Node, init *Nodes) *Node {
 		checknil(n.Left, init)
 	}
 
-	typ := Nod(OTSTRUCT, nil, nil)
-	typ.List.Set1(Nod(ODCLFIELD, newname(Lookup("F")), typenod(Types[TUINTPTR])))
-	typ.List.Append(Nod(ODCLFIELD, newname(Lookup("R")), typenod(n.Left.Type)))
+	typ := Nod(ir.OTSTRUCT, nil, nil)
+	typ.List.Set1(Nod(ir.ODCLFIELD, newname(Lookup("F")), typenod(ir.Types[ir.TUINTPTR])))
+	typ.List.Append(Nod(ir.ODCLFIELD, newname(Lookup("R")), typenod(n.Left.Type)))
+
+	if staticpartialcall(n) {
+		// The closure is the same every time x.M is evaluated,
+		// so use static data instead of allocating it:
+		//
+		//	clos = &statictmp
+		delete(prealloc, n)
+		typ = typecheck(typ, Etype)
+		v := staticname(typ.Type, 0)
+		s := Linksym(v.Sym)
+		s.WriteAddr(Ctxt, 0, Widthptr, Linksym(n.Func.Nname.Sym), 0)
+		if n.Left.Op == ir.OADDR {
+			x := n.Left.Left
+			s.WriteAddr(Ctxt, int64(Widthptr), Widthptr, Linksym(x.Sym), x.Xoffset)
+		}
+		clos := Nod(ir.OCONVNOP, Nod(ir.OADDR, v, nil), nil)
+		clos.Type = n.Type
+		clos = typecheck(clos, Erv)
+		return walkexpr(clos, init)
+	}
 
-	clos := Nod(OCOMPLIT, nil, Nod(OIND, typ, nil))
+	clos := Nod(ir.OCOMPLIT, nil, Nod(ir.OIND, typ, nil))
 	clos.Esc = n.Esc
 	clos.Right.Implicit = true
-	clos.List.Set1(Nod(OCFUNC, n.Func.Nname, nil))
+	clos.List.Set1(Nod(ir.OCFUNC, n.Func.Nname, nil))
 	clos.List.Append(n.Left)
 
 	// Force type conversion from *struct to the func type.
-	clos = Nod(OCONVNOP, clos, nil)
+	clos = Nod(ir.OCONVNOP, clos, nil)
 
 	clos.Type = n.Type
 
@@ -0,0 +1,297 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gc
+
+import (
+	"bufio"
+	"cmd/compile/internal/ir"
+	"cmd/internal/obj"
+	"encoding/binary"
+	"fmt"
+	"os"
+	"runtime/debug"
+	"strings"
+)
+
+// CompileFunc and the functions it uses let the compiler's tests run
+// the front end on a snippet in process, to check the IR a phase
+// produces without building a binary and parsing -m output.
+
+// compileFuncPhases are the phases CompileFunc can stop after,
+// in the order they run.
+var compileFuncPhases = []string{"parse", "typecheck", "inline", "escape", "closure", "walk"}
+
+// compileFuncDiags collects the diagnostics while CompileFunc runs.
+// When it is not nil, Flusherrors and Fatalf append to it instead of
+// printing, and errorexit unwinds CompileFunc instead of exiting.
+var compileFuncDiags *[]string
+
+// compileFuncExit is the panic value errorexit uses to unwind CompileFunc.
+type compileFuncExit struct{}
+
+// An InternalError is the error CompileFunc returns when the compiler
+// crashes or reports an internal compiler error instead of accepting
+// or rejecting its input.
+type InternalError struct {
+	Msg   string // the Fatalf message or the panic value
+	Stack []byte // the stack of the goroutine that failed
+}
+
+func (e *InternalError) Error() string {
+	return "internal compiler error: " + e.Msg
+}
+
+// CompileFunc compiles src, the text of a Go source file, in process
+// up to and including phase, and returns the function called name in
+// it. The phases are "parse", "typecheck", "inline", "escape",
+// "closure" (the rewriting of closures that follows escape analysis)
+// and "walk".
+//
+// flags holds single-letter flags to set as if given on the command
+// line, such as "m" for -m or "l" for -l. The errors and warnings the
+// compiler reports, such as those of escape analysis under -m, are
+// returned in diags, one per message. If the compiler reports an
+// error, CompileFunc returns a nil fn and an error holding diags. If
+// the compiler crashes, or calls Fatalf, the error is an
+// *InternalError. As in Main, a crash after an ordinary error is
+// reported as that error unless -d panic is set.
+//
+// CompileFunc is for the compiler's tests only. Each call compiles a
+// new package, but state such as the imported packages carries over
+// from one call to the next. Without a back end in Thearch, it
+// compiles for a 64-bit machine.
+func CompileFunc(src, name, phase, flags string) (fn *ir.Node, diags []string, err error) {
+	fns, diags, err := CompilePackage(src, phase, flags)
+	if err != nil {
+		return nil, diags, err
+	}
+	for _, n := range fns {
+		if n.Func.Nname != nil && n.Func.Nname.Sym.Name == name {
+			return n, diags, nil
+		}
+	}
+	return nil, diags, fmt.Errorf("CompileFunc: no function %s", name)
+}
+
+// CompilePackage is like CompileFunc, but returns all the functions
+// in src, in the order the compiler compiles them. After the "closure"
+// phase, they include the functions the closures in src become.
+func CompilePackage(src, phase, flags string) (fns []*ir.Node, diags []string, err error) {
+	last := -1
+	for i, p := range compileFuncPhases {
+		if p == phase {
+			last = i
+		}
+	}
+	if last < 0 {
+		return nil, nil, fmt.Errorf("CompileFunc: unknown phase %q", phase)
+	}
+	runs := func(phase string) bool {
+		for i := 0; i <= last; i++ {
+			if compileFuncPhases[i] == phase {
+				return true
+			}
+		}
+		return false
+	}
+
+	savedDebug := Debug
+	for _, c := range flags {
+		Debug[c]++
+	}
+	if Debug['l'] <= 1 {
+		Debug['l'] = 1 - Debug['l']
+	}
+	compileFuncInit()
+	compileFuncReset()
+
+	compileFuncDiags = &diags
+	defer func() {
+		if e := recover(); e != nil {
+			fns = nil
+			switch e := e.(type) {
+			case compileFuncExit:
+				err = fmt.Errorf("%s", strings.Join(diags, "\n"))
+			case *InternalError:
+				err = e
+			default:
+				// Like hidePanic, treat a crash after an error
+				// as a consequence of the error.
+				if Debug_panic == 0 && nsavederrors+nerrors > 0 {
+					Flusherrors()
+					err = fmt.Errorf("%s", strings.Join(diags, "\n"))
+				} else {
+					err = &InternalError{Msg: fmt.Sprint(e), Stack: debug.Stack()}
+				}
+			}
+		}
+		compileFuncDiags = nil
+		Debug = savedDebug
+		Curfn = nil
+		dclcontext = ir.PEXTERN
+	}()
+
+	linehistpush("CompileFunc.go")
+	parse_file(bufio.NewReader(strings.NewReader(src)))
+	lexlineno++
+	linehistpop()
+	if nsyntaxerrors != 0 {
+		errorexit()
+	}
+
+	testdclstack()
+	mkpackage(localpkg.Name)
+	finishUniverse()
+
+	if runs("typecheck") {
+		typecheckxtop()
+		if nsavederrors+nerrors != 0 {
+			errorexit()
+		}
+		runAnalyses(AfterTypecheck)
+	}
+	if runs("inline") {
+		inlinextop()
+	}
+	if runs("escape") {
+		escapextop()
+		runAnalyses(AfterEscape)
+	}
+	if runs("closure") {
+		transformclosures()
+	}
+
+	for _, n := range xtop {
+		if n.Op == ir.ODCLFUNC {
+			fns = append(fns, n)
+		}
+	}
+	if runs("walk") {
+		for _, fn := range fns {
+			if funcenter(fn) {
+				lno := setlineno(fn)
+				buildfunc(fn, nil)
+				lineno = lno
+				Curfn = nil
+			}
+		}
+	}
+	if nsavederrors+nerrors != 0 {
+		errorexit()
+	}
+	Flusherrors()
+	return fns, diags, nil
+}
+
+// compileFuncInit sets up the state that Main sets up before it reads
+// the source files, unless Main or an earlier call already has.
+func compileFuncInit() {
+	if Ctxt != nil {
+		return
+	}
+	if Thearch.Thelinkarch == nil {
+		Thearch.Thechar = '6'
+		Thearch.Thestring = "amd64"
+		Thearch.Thelinkarch = &obj.LinkArch{
+			ByteOrder: binary.LittleEndian,
+			Name:      "amd64",
+			Thechar:   '6',
+			Minlc:     1,
+			Ptrsize:   8,
+			Regsize:   8,
+		}
+		Thearch.MAXWIDTH = 1 << 50
+		thebackend = compileFuncBackend{}
+	}
+
+	Ctxt = obj.Linknew(Thearch.Thelinkarch)
+	Ctxt.DiagFunc = Yyerror
+	Ctxt.Bso = &bstdout
+	bstdout = *obj.Binitw(os.Stdout)
+	initPackages()
+
+	thebackend.TypeInit()
+	initUniverse()
+	blockgen = 1
+	dclcontext = ir.PEXTERN
+	lexlineno = 1
+	loadsys()
+}
+
+// compileFuncReset discards the package compiled by the previous call
+// of CompileFunc, including the state an error may have left behind.
+func compileFuncReset() {
+	localpkg = new(ir.Pkg)
+	localpkg.Prefix = "\"\""
+	pkgMap[""] = localpkg
+	for i, p := range pkgs {
+		if p.Path == "" {
+			pkgs[i] = localpkg
+		}
+	}
+
+	// Give the package its own blank identifier, as lexinit does.
+	// Walk changes the node, which the old package's functions share.
+	s := Lookup("_")
+	s.Block = -100
+	s.Def = Nod(ir.ONAME, nil, nil)
+	s.Def.Lineno = 0
+	s.Def.Sym = s
+	s.Def.Type = ir.Types[ir.TBLANK]
+	nblank = s.Def
+
+	xtop = nil
+	externdcl = nil
+	exportlist = nil
+	importlist = nil
+	funcsyms = nil
+
+	errors = errors[:0]
+	nerrors = 0
+	nsavederrors = 0
+	nsyntaxerrors = 0
+
+	Curfn = nil
+	dclstack = nil
+	dclcontext = ir.PEXTERN
+	typecheckok = false
+	typecheckdefstack = nil
+	iotastack = nil
+	defercalc = 0
+	Funcdepth = 0
+	decldepth = 0
+	block = 1
+	iota_ = -1000000
+	imported_unsafe = false
+	declQueue, declFixups = nil, nil
+	expanding = false
+
+	// Number the generated names from the start, as a new process would,
+	// so that the IR of a snippet does not depend on the calls before.
+	closurename_closgen = 0
+	declare_typegen = 0
+	inlgen = 0
+	loopgen = 0
+	renameinit_initgen = 0
+	slicebytes_gen = 0
+	statictmpgen = 0
+	statuniqgen = 0
+	walkprintfunc_prgen = 0
+}
+
+// compileFuncBackend is the back end CompileFunc uses when Main has not
+// selected one. CompileFunc stops before code generation, so it only
+// needs to describe amd64's types.
+type compileFuncBackend struct{}
+
+func (compileFuncBackend) Init(arch *Arch) {}
+
+func (compileFuncBackend) TypeInit() {
+	Widthptr = 8
+	Widthint = 8
+	Widthreg = 8
+}
+
+func (compileFuncBackend) Peep(firstp *obj.Prog) {}
@@ -0,0 +1,158 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gc
+
+import (
+	"cmd/compile/internal/ir"
+	"strings"
+	"testing"
+)
+
+// findNode returns the first node in the body of fn for which match
+// returns true, or nil if there is none.
+func findNode(fn *ir.Node, match func(*ir.Node) bool) *ir.Node {
+	var find func(n *ir.Node) *ir.Node
+	findList := func(l ir.Nodes) *ir.Node {
+		for _, n := range l.Slice() {
+			if m := find(n); m != nil {
+				return m
+			}
+		}
+		return nil
+	}
+	find = func(n *ir.Node) *ir.Node {
+		if n == nil {
+			return nil
+		}
+		if match(n) {
+			return n
+		}
+		for _, m := range []*ir.Node{find(n.Left), find(n.Right), findList(n.Ninit), findList(n.Nbody), findList(n.List), findList(n.Rlist)} {
+			if m != nil {
+				return m
+			}
+		}
+		return nil
+	}
+	return findList(fn.Nbody)
+}
+
+func TestCompileFuncTypecheck(t *testing.T) {
+	fn, _, err := CompileFunc(`package p
+func f(a, b int) int { return a + b }
+`, "f", "typecheck", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	add := findNode(fn, func(n *ir.Node) bool { return n.Op == ir.OADD })
+	if add == nil || add.Type != ir.Types[ir.TINT] {
+		t.Errorf("a + b = %+v, want an OADD of type int", add)
+	}
+
+	_, _, err = CompileFunc(`package p
+func f() int { return "x" }
+`, "f", "typecheck", "")
+	if err == nil || !strings.Contains(err.Error(), "cannot use \"x\"") {
+		t.Errorf("CompileFunc with a type error: err = %v", err)
+	}
+
+	_, _, err = CompileFunc(`package p
+func f( {
+`, "f", "typecheck", "")
+	if err == nil || !strings.Contains(err.Error(), "syntax error") {
+		t.Errorf("CompileFunc with a syntax error: err = %v", err)
+	}
+}
+
+func TestCompileFuncInline(t *testing.T) {
+	src := `package p
+func g(x int) int { return x * 2 }
+func f(y int) int { return g(y) }
+`
+	isCallG := func(n *ir.Node) bool {
+		return n.Op == ir.OCALLFUNC && n.Left.Sym != nil && n.Left.Sym.Name == "g"
+	}
+
+	fn, _, err := CompileFunc(src, "f", "inline", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if findNode(fn, isCallG) != nil {
+		t.Errorf("the call of g in f was not inlined")
+	}
+
+	fn, _, err = CompileFunc(src, "f", "inline", "l")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if findNode(fn, isCallG) == nil {
+		t.Errorf("the call of g in f was inlined with -l")
+	}
+}
+
+func TestCompileFuncEscape(t *testing.T) {
+	fn, diags, err := CompileFunc(`package p
+func f() (*int, int) {
+	x, y := 1, 2
+	p := &y
+	return &x, *p
+}
+`, "f", "escape", "m")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := strings.Join(diags, "\n"); !strings.Contains(d, "&x escapes to heap") || !strings.Contains(d, "&y does not escape") {
+		t.Errorf("-m diagnostics %q do not report the escape of &x and &y", diags)
+	}
+	for _, n := range fn.Func.Dcl {
+		if n.Sym == nil {
+			continue
+		}
+		switch n.Sym.Name {
+		case "x":
+			if n.Class&ir.PHEAP == 0 {
+				t.Errorf("x is not moved to the heap")
+			}
+		case "y":
+			if n.Class&ir.PHEAP != 0 {
+				t.Errorf("y is moved to the heap")
+			}
+		}
+	}
+}
+
+func TestCompileFuncWalk(t *testing.T) {
+	src := `package p
+func f(a, b string) string { return a + b }
+`
+	isConcat := func(n *ir.Node) bool {
+		return n.Op == ir.OCALLFUNC && n.Left.Sym != nil && n.Left.Sym.Name == "concatstring2"
+	}
+
+	fn, _, err := CompileFunc(src, "f", "closure", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if findNode(fn, isConcat) != nil {
+		t.Errorf("a + b is a call of concatstring2 before walk")
+	}
+
+	fn, _, err = CompileFunc(src, "f", "walk", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if findNode(fn, isConcat) == nil {
+		t.Errorf("a + b is not a call of concatstring2 after walk")
+	}
+}
+
+func TestCompileFuncErrors(t *testing.T) {
+	if _, _, err := CompileFunc("package p\n", "f", "ssa", ""); err == nil {
+		t.Errorf("CompileFunc with an unknown phase succeeded")
+	}
+	if _, _, err := CompileFunc("package p\nfunc g() {}\n", "f", "typecheck", ""); err == nil {
+		t.Errorf("CompileFunc of a missing function succeeded")
+	}
+}
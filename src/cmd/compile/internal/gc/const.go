@@ -5,82 +5,34 @@
 package gc
 
 import (
-	"cmd/compile/internal/big"
+	"cmd/compile/internal/ir"
 	"cmd/internal/obj"
 	"strings"
 )
 
-// IntLiteral returns the Node's literal value as an interger.
-func (n *Node) IntLiteral() (x int64, ok bool) {
-	switch {
-	case n == nil:
-		return
-	case Isconst(n, CTINT):
-		return n.Int(), true
-	case Isconst(n, CTBOOL):
-		return int64(obj.Bool2int(n.Bool())), true
-	}
-	return
-}
-
-// Int returns n as an int.
-// n must be an integer constant.
-func (n *Node) Int() int64 {
-	if !Isconst(n, CTINT) {
-		Fatalf("Int(%v)", n)
-	}
-	return n.Val().U.(*Mpint).Int64()
-}
-
-// SetInt sets n's value to i.
-// n must be an integer constant.
-func (n *Node) SetInt(i int64) {
-	if !Isconst(n, CTINT) {
-		Fatalf("SetInt(%v)", n)
-	}
-	n.Val().U.(*Mpint).SetInt64(i)
-}
-
-// SetBigInt sets n's value to x.
-// n must be an integer constant.
-func (n *Node) SetBigInt(x *big.Int) {
-	if !Isconst(n, CTINT) {
-		Fatalf("SetBigInt(%v)", n)
-	}
-	n.Val().U.(*Mpint).Val.Set(x)
-}
-
-// Bool returns n as an bool.
-// n must be an boolean constant.
-func (n *Node) Bool() bool {
-	if !Isconst(n, CTBOOL) {
-		Fatalf("Int(%v)", n)
-	}
-	return n.Val().U.(bool)
-}
-
 // truncate float literal fv to 32-bit or 64-bit precision
 // according to type; return truncated value.
-func truncfltlit(oldv *Mpflt, t *Type) *Mpflt {
+// context, if not nil, describes where t came from, for errors.
+func truncfltlit(oldv *ir.Mpflt, t *ir.Type, context func() string) *ir.Mpflt {
 	if t == nil {
 		return oldv
 	}
 
-	var v Val
+	var v ir.Val
 	v.U = oldv
-	overflow(v, t)
+	overflow(v, t, context)
 
-	fv := newMpflt()
+	fv := ir.NewMpflt()
 	fv.Set(oldv)
 
 	// convert large precision literal floating
 	// into limited precision (float64 or float32)
 	switch t.Etype {
-	case TFLOAT64:
+	case ir.TFLOAT64:
 		d := fv.Float64()
 		fv.SetFloat64(d)
 
-	case TFLOAT32:
+	case ir.TFLOAT32:
 		d := fv.Float32()
 		fv.SetFloat64(d)
 	}
@@ -89,7 +41,7 @@ func truncfltlit(oldv *Mpflt, t *Type) *Mpflt {
 }
 
 // NegOne returns a Node of type t with value -1.
-func NegOne(t *Type) *Node {
+func NegOne(t *ir.Type) *ir.Node {
 	n := Nodintconst(-1)
 	n = convlit(n, t)
 	return n
@@ -99,16 +51,18 @@ func NegOne(t *Type) *Node {
 // implicit conversion.
 // The result of convlit MUST be assigned back to n, e.g.
 // 	n.Left = convlit(n.Left, t)
-func convlit(n *Node, t *Type) *Node {
-	return convlit1(n, t, false)
+func convlit(n *ir.Node, t *ir.Type) *ir.Node {
+	return convlit1(n, t, false, nil)
 }
 
 // convert n, if literal, to type t.
 // return a new node if necessary
 // (if n is a named constant, can't edit n->type directly).
+// context, if not nil, describes where t came from,
+// as for assignconvfn; it is used in overflow errors.
 // The result of convlit1 MUST be assigned back to n, e.g.
-// 	n.Left = convlit1(n.Left, t, explicit)
-func convlit1(n *Node, t *Type, explicit bool) *Node {
+// 	n.Left = convlit1(n.Left, t, explicit, context)
+func convlit1(n *ir.Node, t *ir.Type, explicit bool, context func() string) *ir.Node {
 	if n == nil || t == nil || n.Type == nil || isideal(t) || n.Type == t {
 		return n
 	}
@@ -116,39 +70,39 @@ func convlit1(n *Node, t *Type, explicit bool) *Node {
 		return n
 	}
 
-	if n.Op == OLITERAL {
+	if n.Op == ir.OLITERAL {
 		nn := *n
 		n = &nn
 	}
 
 	switch n.Op {
 	default:
-		if n.Type == idealbool {
-			if t.Etype == TBOOL {
+		if n.Type == ir.Idealbool {
+			if t.Etype == ir.TBOOL {
 				n.Type = t
 			} else {
-				n.Type = Types[TBOOL]
+				n.Type = ir.Types[ir.TBOOL]
 			}
 		}
 
-		if n.Type.Etype == TIDEAL {
-			n.Left = convlit(n.Left, t)
-			n.Right = convlit(n.Right, t)
+		if n.Type.Etype == ir.TIDEAL {
+			n.Left = convlit1(n.Left, t, false, context)
+			n.Right = convlit1(n.Right, t, false, context)
 			n.Type = t
 		}
 
 		return n
 
 		// target is invalid type for a constant?  leave alone.
-	case OLITERAL:
-		if !okforconst[t.Etype] && n.Type.Etype != TNIL {
-			return defaultlit(n, nil)
+	case ir.OLITERAL:
+		if !okforconst[t.Etype] && n.Type.Etype != ir.TNIL {
+			return defaultlitfn(n, nil, context)
 		}
 
-	case OLSH, ORSH:
-		n.Left = convlit1(n.Left, t, explicit && isideal(n.Left.Type))
+	case ir.OLSH, ir.ORSH:
+		n.Left = convlit1(n.Left, t, explicit && isideal(n.Left.Type), context)
 		t = n.Left.Type
-		if t != nil && t.Etype == TIDEAL && n.Val().Ctype() != CTINT {
+		if t != nil && t.Etype == ir.TIDEAL && n.Val().Ctype() != ir.CTINT {
 			n.SetVal(toint(n.Val()))
 		}
 		if t != nil && !Isint[t.Etype] {
@@ -159,26 +113,26 @@ func convlit1(n *Node, t *Type, explicit bool) *Node {
 		n.Type = t
 		return n
 
-	case OCOMPLEX:
-		if n.Type.Etype == TIDEAL {
+	case ir.OCOMPLEX:
+		if n.Type.Etype == ir.TIDEAL {
 			switch t.Etype {
 			// If trying to convert to non-complex type,
 			// leave as complex128 and let typechecker complain.
 			default:
-				t = Types[TCOMPLEX128]
+				t = ir.Types[ir.TCOMPLEX128]
 				fallthrough
 
 				//fallthrough
-			case TCOMPLEX128:
+			case ir.TCOMPLEX128:
 				n.Type = t
 
-				n.Left = convlit(n.Left, Types[TFLOAT64])
-				n.Right = convlit(n.Right, Types[TFLOAT64])
+				n.Left = convlit(n.Left, ir.Types[ir.TFLOAT64])
+				n.Right = convlit(n.Right, ir.Types[ir.TFLOAT64])
 
-			case TCOMPLEX64:
+			case ir.TCOMPLEX64:
 				n.Type = t
-				n.Left = convlit(n.Left, Types[TFLOAT32])
-				n.Right = convlit(n.Right, Types[TFLOAT32])
+				n.Left = convlit(n.Left, ir.Types[ir.TFLOAT32])
+				n.Right = convlit(n.Right, ir.Types[ir.TFLOAT32])
 			}
 		}
 
@@ -190,67 +144,67 @@ func convlit1(n *Node, t *Type, explicit bool) *Node {
 		return n
 	}
 
-	ct := consttype(n)
-	var et EType
+	ct := ir.Consttype(n)
+	var et ir.EType
 	if ct < 0 {
 		goto bad
 	}
 
 	et = t.Etype
-	if et == TINTER {
-		if ct == CTNIL && n.Type == Types[TNIL] {
+	if et == ir.TINTER {
+		if ct == ir.CTNIL && n.Type == ir.Types[ir.TNIL] {
 			n.Type = t
 			return n
 		}
-		return defaultlit(n, nil)
+		return defaultlitfn(n, nil, context)
 	}
 
 	switch ct {
 	default:
 		goto bad
 
-	case CTNIL:
+	case ir.CTNIL:
 		switch et {
 		default:
 			n.Type = nil
 			goto bad
 
 			// let normal conversion code handle it
-		case TSTRING:
+		case ir.TSTRING:
 			return n
 
-		case TARRAY:
+		case ir.TARRAY:
 			if !Isslice(t) {
 				goto bad
 			}
 
-		case TPTR32,
-			TPTR64,
-			TINTER,
-			TMAP,
-			TCHAN,
-			TFUNC,
-			TUNSAFEPTR:
+		case ir.TPTR32,
+			ir.TPTR64,
+			ir.TINTER,
+			ir.TMAP,
+			ir.TCHAN,
+			ir.TFUNC,
+			ir.TUNSAFEPTR:
 			break
 
 			// A nil literal may be converted to uintptr
 		// if it is an unsafe.Pointer
-		case TUINTPTR:
-			if n.Type.Etype == TUNSAFEPTR {
-				n.SetVal(Val{new(Mpint)})
-				n.Val().U.(*Mpint).SetInt64(0)
+		case ir.TUINTPTR:
+			if n.Type.Etype == ir.TUNSAFEPTR {
+				n.SetVal(ir.Val{U: new(ir.Mpint)})
+				n.Val().U.(*ir.Mpint).SetInt64(0)
 			} else {
 				goto bad
 			}
 		}
 
-	case CTSTR, CTBOOL:
+	case ir.CTSTR, ir.CTBOOL:
 		if et != n.Type.Etype {
 			goto bad
 		}
 
-	case CTINT, CTRUNE, CTFLT, CTCPLX:
-		if n.Type.Etype == TUNSAFEPTR && t.Etype != TUINTPTR {
+	case ir.CTINT, ir.CTRUNE, ir.CTFLT, ir.CTCPLX:
+		if n.Type.Etype == ir.TUNSAFEPTR && t.Etype != ir.TUINTPTR {
 			goto bad
 		}
 		ct := n.Val().Ctype()
@@ -259,38 +213,38 @@ func convlit1(n *Node, t *Type, explicit bool) *Node {
 			default:
 				goto bad
 
-			case CTCPLX, CTFLT, CTRUNE:
+			case ir.CTCPLX, ir.CTFLT, ir.CTRUNE:
 				n.SetVal(toint(n.Val()))
 				fallthrough
 
-			case CTINT:
-				overflow(n.Val(), t)
+			case ir.CTINT:
+				overflow(n.Val(), t, context)
 			}
 		} else if Isfloat[et] {
 			switch ct {
 			default:
 				goto bad
 
-			case CTCPLX, CTINT, CTRUNE:
+			case ir.CTCPLX, ir.CTINT, ir.CTRUNE:
 				n.SetVal(toflt(n.Val()))
 				fallthrough
 
-			case CTFLT:
-				n.SetVal(Val{truncfltlit(n.Val().U.(*Mpflt), t)})
+			case ir.CTFLT:
+				n.SetVal(ir.Val{U: truncfltlit(n.Val().U.(*ir.Mpflt), t, context)})
 			}
 		} else if Iscomplex[et] {
 			switch ct {
 			default:
 				goto bad
 
-			case CTFLT, CTINT, CTRUNE:
+			case ir.CTFLT, ir.CTINT, ir.CTRUNE:
 				n.SetVal(tocplx(n.Val()))
 				fallthrough
 
-			case CTCPLX:
-				overflow(n.Val(), t)
+			case ir.CTCPLX:
+				overflow(n.Val(), t, context)
 			}
-		} else if et == TSTRING && (ct == CTINT || ct == CTRUNE) && explicit {
+		} else if et == ir.TSTRING && (ct == ir.CTINT || ct == ir.CTRUNE) && explicit {
 			n.SetVal(tostr(n.Val()))
 		} else {
 			goto bad
@@ -309,45 +263,45 @@ bad:
 	}
 
 	if isideal(n.Type) {
-		n = defaultlit(n, nil)
+		n = defaultlitfn(n, nil, context)
 	}
 	return n
 }
 
-func copyval(v Val) Val {
+func copyval(v ir.Val) ir.Val {
 	switch v.Ctype() {
-	case CTINT, CTRUNE:
-		i := new(Mpint)
-		i.Set(v.U.(*Mpint))
-		i.Rune = v.U.(*Mpint).Rune
+	case ir.CTINT, ir.CTRUNE:
+		i := new(ir.Mpint)
+		i.Set(v.U.(*ir.Mpint))
+		i.Rune = v.U.(*ir.Mpint).Rune
 		v.U = i
 
-	case CTFLT:
-		f := newMpflt()
-		f.Set(v.U.(*Mpflt))
+	case ir.CTFLT:
+		f := ir.NewMpflt()
+		f.Set(v.U.(*ir.Mpflt))
 		v.U = f
 
-	case CTCPLX:
-		c := new(Mpcplx)
-		c.Real.Set(&v.U.(*Mpcplx).Real)
-		c.Imag.Set(&v.U.(*Mpcplx).Imag)
+	case ir.CTCPLX:
+		c := new(ir.Mpcplx)
+		c.Real.Set(&v.U.(*ir.Mpcplx).Real)
+		c.Imag.Set(&v.U.(*ir.Mpcplx).Imag)
 		v.U = c
 	}
 
 	return v
 }
 
-func tocplx(v Val) Val {
+func tocplx(v ir.Val) ir.Val {
 	switch v.Ctype() {
-	case CTINT, CTRUNE:
-		c := new(Mpcplx)
-		c.Real.SetInt(v.U.(*Mpint))
+	case ir.CTINT, ir.CTRUNE:
+		c := new(ir.Mpcplx)
+		c.Real.SetInt(v.U.(*ir.Mpint))
 		c.Imag.SetFloat64(0.0)
 		v.U = c
 
-	case CTFLT:
-		c := new(Mpcplx)
-		c.Real.Set(v.U.(*Mpflt))
+	case ir.CTFLT:
+		c := new(ir.Mpcplx)
+		c.Real.Set(v.U.(*ir.Mpflt))
 		c.Imag.SetFloat64(0.0)
 		v.U = c
 	}
@@ -355,18 +309,18 @@ func tocplx(v Val) Val {
 	return v
 }
 
-func toflt(v Val) Val {
+func toflt(v ir.Val) ir.Val {
 	switch v.Ctype() {
-	case CTINT, CTRUNE:
-		f := newMpflt()
-		f.SetInt(v.U.(*Mpint))
+	case ir.CTINT, ir.CTRUNE:
+		f := ir.NewMpflt()
+		f.SetInt(v.U.(*ir.Mpint))
 		v.U = f
 
-	case CTCPLX:
-		f := newMpflt()
-		f.Set(&v.U.(*Mpcplx).Real)
-		if v.U.(*Mpcplx).Imag.CmpFloat64(0) != 0 {
-			Yyerror("constant %v%vi truncated to real", Fconv(&v.U.(*Mpcplx).Real, FmtSharp), Fconv(&v.U.(*Mpcplx).Imag, FmtSharp|FmtSign))
+	case ir.CTCPLX:
+		f := ir.NewMpflt()
+		f.Set(&v.U.(*ir.Mpcplx).Real)
+		if v.U.(*ir.Mpcplx).Imag.CmpFloat64(0) != 0 {
+			Yyerror("constant %v%vi truncated to real", ir.Fconv(&v.U.(*ir.Mpcplx).Real, ir.FmtSharp), ir.Fconv(&v.U.(*ir.Mpcplx).Imag, ir.FmtSharp|ir.FmtSign))
 		}
 		v.U = f
 	}
@@ -374,32 +328,32 @@ func toflt(v Val) Val {
 	return v
 }
 
-func toint(v Val) Val {
+func toint(v ir.Val) ir.Val {
 	switch v.Ctype() {
-	case CTRUNE:
-		i := new(Mpint)
-		i.Set(v.U.(*Mpint))
+	case ir.CTRUNE:
+		i := new(ir.Mpint)
+		i.Set(v.U.(*ir.Mpint))
 		v.U = i
 
-	case CTFLT:
-		i := new(Mpint)
-		if f := v.U.(*Mpflt); i.SetFloat(f) < 0 {
+	case ir.CTFLT:
+		i := new(ir.Mpint)
+		if f := v.U.(*ir.Mpflt); i.SetFloat(f) < 0 {
 			msg := "constant %v truncated to integer"
 			// provide better error message if SetFloat failed because f was too large
 			if f.Val.IsInt() {
 				msg = "constant %v overflows integer"
 			}
-			Yyerror(msg, Fconv(f, FmtSharp))
+			Yyerror(msg, ir.Fconv(f, ir.FmtSharp))
 		}
 		v.U = i
 
-	case CTCPLX:
-		i := new(Mpint)
-		if i.SetFloat(&v.U.(*Mpcplx).Real) < 0 {
-			Yyerror("constant %v%vi truncated to integer", Fconv(&v.U.(*Mpcplx).Real, FmtSharp), Fconv(&v.U.(*Mpcplx).Imag, FmtSharp|FmtSign))
+	case ir.CTCPLX:
+		i := new(ir.Mpint)
+		if i.SetFloat(&v.U.(*ir.Mpcplx).Real) < 0 {
+			Yyerror("constant %v%vi truncated to integer", ir.Fconv(&v.U.(*ir.Mpcplx).Real, ir.FmtSharp), ir.Fconv(&v.U.(*ir.Mpcplx).Imag, ir.FmtSharp|ir.FmtSign))
 		}
-		if v.U.(*Mpcplx).Imag.CmpFloat64(0) != 0 {
-			Yyerror("constant %v%vi truncated to real", Fconv(&v.U.(*Mpcplx).Real, FmtSharp), Fconv(&v.U.(*Mpcplx).Imag, FmtSharp|FmtSign))
+		if v.U.(*ir.Mpcplx).Imag.CmpFloat64(0) != 0 {
+			Yyerror("constant %v%vi truncated to real", ir.Fconv(&v.U.(*ir.Mpcplx).Real, ir.FmtSharp), ir.Fconv(&v.U.(*ir.Mpcplx).Imag, ir.FmtSharp|ir.FmtSign))
 		}
 		v.U = i
 	}
@@ -407,29 +361,29 @@ func toint(v Val) Val {
 	return v
 }
 
-func doesoverflow(v Val, t *Type) bool {
+func doesoverflow(v ir.Val, t *ir.Type) bool {
 	switch v.Ctype() {
-	case CTINT, CTRUNE:
+	case ir.CTINT, ir.CTRUNE:
 		if !Isint[t.Etype] {
 			Fatalf("overflow: %v integer constant", t)
 		}
-		if v.U.(*Mpint).Cmp(Minintval[t.Etype]) < 0 || v.U.(*Mpint).Cmp(Maxintval[t.Etype]) > 0 {
+		if v.U.(*ir.Mpint).Cmp(Minintval[t.Etype]) < 0 || v.U.(*ir.Mpint).Cmp(Maxintval[t.Etype]) > 0 {
 			return true
 		}
 
-	case CTFLT:
+	case ir.CTFLT:
 		if !Isfloat[t.Etype] {
 			Fatalf("overflow: %v floating-point constant", t)
 		}
-		if v.U.(*Mpflt).Cmp(minfltval[t.Etype]) <= 0 || v.U.(*Mpflt).Cmp(maxfltval[t.Etype]) >= 0 {
+		if v.U.(*ir.Mpflt).Cmp(minfltval[t.Etype]) <= 0 || v.U.(*ir.Mpflt).Cmp(maxfltval[t.Etype]) >= 0 {
 			return true
 		}
 
-	case CTCPLX:
+	case ir.CTCPLX:
 		if !Iscomplex[t.Etype] {
 			Fatalf("overflow: %v complex constant", t)
 		}
-		if v.U.(*Mpcplx).Real.Cmp(minfltval[t.Etype]) <= 0 || v.U.(*Mpcplx).Real.Cmp(maxfltval[t.Etype]) >= 0 || v.U.(*Mpcplx).Imag.Cmp(minfltval[t.Etype]) <= 0 || v.U.(*Mpcplx).Imag.Cmp(maxfltval[t.Etype]) >= 0 {
+		if v.U.(*ir.Mpcplx).Real.Cmp(minfltval[t.Etype]) <= 0 || v.U.(*ir.Mpcplx).Real.Cmp(maxfltval[t.Etype]) >= 0 || v.U.(*ir.Mpcplx).Imag.Cmp(minfltval[t.Etype]) <= 0 || v.U.(*ir.Mpcplx).Imag.Cmp(maxfltval[t.Etype]) >= 0 {
 			return true
 		}
 	}
@@ -437,64 +391,55 @@ func doesoverflow(v Val, t *Type) bool {
 	return false
 }
 
-func overflow(v Val, t *Type) {
+// overflow reports an error if v does not fit in t.
+// context, if not nil, describes where t came from.
+func overflow(v ir.Val, t *ir.Type, context func() string) {
 	// v has already been converted
 	// to appropriate form for t.
-	if t == nil || t.Etype == TIDEAL {
+	if t == nil || t.Etype == ir.TIDEAL {
 		return
 	}
 
 	// Only uintptrs may be converted to unsafe.Pointer, which cannot overflow.
-	if t.Etype == TUNSAFEPTR {
+	if t.Etype == ir.TUNSAFEPTR {
 		return
 	}
 
 	if doesoverflow(v, t) {
-		Yyerror("constant %s overflows %v", Vconv(v, 0), t)
+		if context != nil {
+			Yyerror("constant %s overflows %v in %s", Vconv(v, 0), t, context())
+		} else {
+			Yyerror("constant %s overflows %v", Vconv(v, 0), t)
+		}
 	}
 }
 
-func tostr(v Val) Val {
+func tostr(v ir.Val) ir.Val {
 	switch v.Ctype() {
-	case CTINT, CTRUNE:
-		if v.U.(*Mpint).Cmp(Minintval[TINT]) < 0 || v.U.(*Mpint).Cmp(Maxintval[TINT]) > 0 {
+	case ir.CTINT, ir.CTRUNE:
+		if v.U.(*ir.Mpint).Cmp(Minintval[ir.TINT]) < 0 || v.U.(*ir.Mpint).Cmp(Maxintval[ir.TINT]) > 0 {
 			Yyerror("overflow in int -> string")
 		}
-		r := uint(v.U.(*Mpint).Int64())
-		v = Val{}
+		r := uint(v.U.(*ir.Mpint).Int64())
+		v = ir.Val{}
 		v.U = string(r)
 
-	case CTFLT:
+	case ir.CTFLT:
 		Yyerror("no float -> string")
 		fallthrough
 
-	case CTNIL:
-		v = Val{}
+	case ir.CTNIL:
+		v = ir.Val{}
 		v.U = ""
 	}
 
 	return v
 }
 
-func consttype(n *Node) Ctype {
-	if n == nil || n.Op != OLITERAL {
-		return -1
-	}
-	return n.Val().Ctype()
-}
-
-func Isconst(n *Node, ct Ctype) bool {
-	t := consttype(n)
-
-	// If the caller is asking for CTINT, allow CTRUNE too.
-	// Makes life easier for back ends.
-	return t == ct || (ct == CTINT && t == CTRUNE)
-}
-
-func saveorig(n *Node) *Node {
+func saveorig(n *ir.Node) *ir.Node {
 	if n == n.Orig {
 		// duplicate node for n->orig.
-		n1 := Nod(OLITERAL, nil, nil)
+		n1 := Nod(ir.OLITERAL, nil, nil)
 
 		n.Orig = n1
 		*n1 = *n
@@ -503,71 +448,80 @@ func saveorig(n *Node) *Node {
 	return n.Orig
 }
 
+// mpopname names the integer constant operations
+// whose results can overflow, for errors.
+var mpopname = map[ir.Op]string{
+	ir.OADD: "addition",
+	ir.OSUB: "subtraction",
+	ir.OMUL: "multiplication",
+	ir.OLSH: "shift",
+}
+
 // if n is constant, rewrite as OLITERAL node.
-func evconst(n *Node) {
+func evconst(n *ir.Node) {
 	// pick off just the opcodes that can be
 	// constant evaluated.
 	switch n.Op {
 	default:
 		return
 
-	case OADD,
-		OAND,
-		OANDAND,
-		OANDNOT,
-		OARRAYBYTESTR,
-		OCOM,
-		ODIV,
-		OEQ,
-		OGE,
-		OGT,
-		OLE,
-		OLSH,
-		OLT,
-		OMINUS,
-		OMOD,
-		OMUL,
-		ONE,
-		ONOT,
-		OOR,
-		OOROR,
-		OPLUS,
-		ORSH,
-		OSUB,
-		OXOR:
+	case ir.OADD,
+		ir.OAND,
+		ir.OANDAND,
+		ir.OANDNOT,
+		ir.OARRAYBYTESTR,
+		ir.OCOM,
+		ir.ODIV,
+		ir.OEQ,
+		ir.OGE,
+		ir.OGT,
+		ir.OLE,
+		ir.OLSH,
+		ir.OLT,
+		ir.OMINUS,
+		ir.OMOD,
+		ir.OMUL,
+		ir.ONE,
+		ir.ONOT,
+		ir.OOR,
+		ir.OOROR,
+		ir.OPLUS,
+		ir.ORSH,
+		ir.OSUB,
+		ir.OXOR:
 		break
 
-	case OCONV:
+	case ir.OCONV:
 		if n.Type == nil {
 			return
 		}
-		if !okforconst[n.Type.Etype] && n.Type.Etype != TNIL {
+		if !okforconst[n.Type.Etype] && n.Type.Etype != ir.TNIL {
 			return
 		}
 
 		// merge adjacent constants in the argument list.
-	case OADDSTR:
+	case ir.OADDSTR:
 		s := n.List.Slice()
 		for i1 := 0; i1 < len(s); i1++ {
-			if Isconst(s[i1], CTSTR) && i1+1 < len(s) && Isconst(s[i1+1], CTSTR) {
+			if ir.Isconst(s[i1], ir.CTSTR) && i1+1 < len(s) && ir.Isconst(s[i1+1], ir.CTSTR) {
 				// merge from i1 up to but not including i2
 				var strs []string
 				i2 := i1
-				for i2 < len(s) && Isconst(s[i2], CTSTR) {
+				for i2 < len(s) && ir.Isconst(s[i2], ir.CTSTR) {
 					strs = append(strs, s[i2].Val().U.(string))
 					i2++
 				}
 
 				nl := *s[i1]
 				nl.Orig = &nl
-				nl.SetVal(Val{strings.Join(strs, "")})
+				nl.SetVal(ir.Val{U: strings.Join(strs, "")})
 				s[i1] = &nl
 				s = append(s[:i1+1], s[i2:]...)
 			}
 		}
 
-		if len(s) == 1 && Isconst(s[0], CTSTR) {
-			n.Op = OLITERAL
+		if len(s) == 1 && ir.Isconst(s[0], ir.CTSTR) {
+			n.Op = ir.OLITERAL
 			n.SetVal(s[0].Val())
 		} else {
 			n.List.Set(s)
@@ -580,63 +534,64 @@ func evconst(n *Node) {
 	if nl == nil || nl.Type == nil {
 		return
 	}
-	if consttype(nl) < 0 {
+	if ir.Consttype(nl) < 0 {
 		return
 	}
 	wl := nl.Type.Etype
 	if Isint[wl] || Isfloat[wl] || Iscomplex[wl] {
-		wl = TIDEAL
+		wl = ir.TIDEAL
 	}
 
 	// avoid constant conversions in switches below
 	const (
-		CTINT_         = uint32(CTINT)
-		CTRUNE_        = uint32(CTRUNE)
-		CTFLT_         = uint32(CTFLT)
-		CTCPLX_        = uint32(CTCPLX)
-		CTSTR_         = uint32(CTSTR)
-		CTBOOL_        = uint32(CTBOOL)
-		CTNIL_         = uint32(CTNIL)
-		OCONV_         = uint32(OCONV) << 16
-		OARRAYBYTESTR_ = uint32(OARRAYBYTESTR) << 16
-		OPLUS_         = uint32(OPLUS) << 16
-		OMINUS_        = uint32(OMINUS) << 16
-		OCOM_          = uint32(OCOM) << 16
-		ONOT_          = uint32(ONOT) << 16
-		OLSH_          = uint32(OLSH) << 16
-		ORSH_          = uint32(ORSH) << 16
-		OADD_          = uint32(OADD) << 16
-		OSUB_          = uint32(OSUB) << 16
-		OMUL_          = uint32(OMUL) << 16
-		ODIV_          = uint32(ODIV) << 16
-		OMOD_          = uint32(OMOD) << 16
-		OOR_           = uint32(OOR) << 16
-		OAND_          = uint32(OAND) << 16
-		OANDNOT_       = uint32(OANDNOT) << 16
-		OXOR_          = uint32(OXOR) << 16
-		OEQ_           = uint32(OEQ) << 16
-		ONE_           = uint32(ONE) << 16
-		OLT_           = uint32(OLT) << 16
-		OLE_           = uint32(OLE) << 16
-		OGE_           = uint32(OGE) << 16
-		OGT_           = uint32(OGT) << 16
-		OOROR_         = uint32(OOROR) << 16
-		OANDAND_       = uint32(OANDAND) << 16
+		CTINT_         = uint32(ir.CTINT)
+		CTRUNE_        = uint32(ir.CTRUNE)
+		CTFLT_         = uint32(ir.CTFLT)
+		CTCPLX_        = uint32(ir.CTCPLX)
+		CTSTR_         = uint32(ir.CTSTR)
+		CTBOOL_        = uint32(ir.CTBOOL)
+		CTNIL_         = uint32(ir.CTNIL)
+		OCONV_         = uint32(ir.OCONV) << 16
+		OARRAYBYTESTR_ = uint32(ir.OARRAYBYTESTR) << 16
+		OPLUS_         = uint32(ir.OPLUS) << 16
+		OMINUS_        = uint32(ir.OMINUS) << 16
+		OCOM_          = uint32(ir.OCOM) << 16
+		ONOT_          = uint32(ir.ONOT) << 16
+		OLSH_          = uint32(ir.OLSH) << 16
+		ORSH_          = uint32(ir.ORSH) << 16
+		OADD_          = uint32(ir.OADD) << 16
+		OSUB_          = uint32(ir.OSUB) << 16
+		OMUL_          = uint32(ir.OMUL) << 16
+		ODIV_          = uint32(ir.ODIV) << 16
+		OMOD_          = uint32(ir.OMOD) << 16
+		OOR_           = uint32(ir.OOR) << 16
+		OAND_          = uint32(ir.OAND) << 16
+		OANDNOT_       = uint32(ir.OANDNOT) << 16
+		OXOR_          = uint32(ir.OXOR) << 16
+		OEQ_           = uint32(ir.OEQ) << 16
+		ONE_           = uint32(ir.ONE) << 16
+		OLT_           = uint32(ir.OLT) << 16
+		OLE_           = uint32(ir.OLE) << 16
+		OGE_           = uint32(ir.OGE) << 16
+		OGT_           = uint32(ir.OGT) << 16
+		OOROR_         = uint32(ir.OOROR) << 16
+		OANDAND_       = uint32(ir.OANDAND) << 16
 	)
 
 	nr := n.Right
-	var rv Val
+	var rv ir.Val
 	var lno int32
-	var wr EType
-	var v Val
-	var norig *Node
-	var nn *Node
+	var wr ir.EType
+	var v ir.Val
+	var norig *ir.Node
+	var nn *ir.Node
+	var diag uint8
 	if nr == nil {
 		// copy numeric value to avoid modifying
 		// nl, in case someone still refers to it (e.g. iota).
 		v = nl.Val()
 
-		if wl == TIDEAL {
+		if wl == ir.TIDEAL {
 			v = copyval(v)
 		}
 
@@ -650,7 +605,7 @@ func evconst(n *Node) {
 
 		case OCONV_ | CTNIL_,
 			OARRAYBYTESTR_ | CTNIL_:
-			if n.Type.Etype == TSTRING {
+			if n.Type.Etype == ir.TSTRING {
 				v = tostr(v)
 				nl.Type = n.Type
 				break
@@ -663,7 +618,7 @@ func evconst(n *Node) {
 			OCONV_ | CTFLT_,
 			OCONV_ | CTSTR_,
 			OCONV_ | CTBOOL_:
-			nl = convlit1(nl, n.Type, true)
+			nl = convlit1(nl, n.Type, true, nil)
 
 			v = nl.Val()
 
@@ -673,47 +628,47 @@ func evconst(n *Node) {
 
 		case OMINUS_ | CTINT_,
 			OMINUS_ | CTRUNE_:
-			v.U.(*Mpint).Neg()
+			v.U.(*ir.Mpint).Neg()
 
 		case OCOM_ | CTINT_,
 			OCOM_ | CTRUNE_:
-			var et EType = Txxx
+			var et ir.EType = ir.Txxx
 			if nl.Type != nil {
 				et = nl.Type.Etype
 			}
 
 			// calculate the mask in b
 			// result will be (a ^ mask)
-			var b Mpint
+			var b ir.Mpint
 			switch et {
 			// signed guys change sign
 			default:
 				b.SetInt64(-1)
 
 				// unsigned guys invert their bits
-			case TUINT8,
-				TUINT16,
-				TUINT32,
-				TUINT64,
-				TUINT,
-				TUINTPTR:
+			case ir.TUINT8,
+				ir.TUINT16,
+				ir.TUINT32,
+				ir.TUINT64,
+				ir.TUINT,
+				ir.TUINTPTR:
 				b.Set(Maxintval[et])
 			}
 
-			v.U.(*Mpint).Xor(&b)
+			v.U.(*ir.Mpint).Xor(&b)
 
 		case OPLUS_ | CTFLT_:
 			break
 
 		case OMINUS_ | CTFLT_:
-			v.U.(*Mpflt).Neg()
+			v.U.(*ir.Mpflt).Neg()
 
 		case OPLUS_ | CTCPLX_:
 			break
 
 		case OMINUS_ | CTCPLX_:
-			v.U.(*Mpcplx).Real.Neg()
-			v.U.(*Mpcplx).Imag.Neg()
+			v.U.(*ir.Mpcplx).Real.Neg()
+			v.U.(*ir.Mpcplx).Imag.Neg()
 
 		case ONOT_ | CTBOOL_:
 			if !v.U.(bool) {
@@ -726,12 +681,12 @@ func evconst(n *Node) {
 	if nr.Type == nil {
 		return
 	}
-	if consttype(nr) < 0 {
+	if ir.Consttype(nr) < 0 {
 		return
 	}
 	wr = nr.Type.Etype
 	if Isint[wr] || Isfloat[wr] || Iscomplex[wr] {
-		wr = TIDEAL
+		wr = ir.TIDEAL
 	}
 
 	// check for compatible general types (numeric, string, etc)
@@ -743,12 +698,12 @@ func evconst(n *Node) {
 	switch n.Op {
 	// ideal const mixes with anything but otherwise must match.
 	default:
-		if nl.Type.Etype != TIDEAL {
+		if nl.Type.Etype != ir.TIDEAL {
 			nr = defaultlit(nr, nl.Type)
 			n.Right = nr
 		}
 
-		if nr.Type.Etype != TIDEAL {
+		if nr.Type.Etype != ir.TIDEAL {
 			nl = defaultlit(nl, nr.Type)
 			n.Left = nl
 		}
@@ -759,14 +714,14 @@ func evconst(n *Node) {
 
 		// right must be unsigned.
 	// left can be ideal.
-	case OLSH, ORSH:
-		nr = defaultlit(nr, Types[TUINT])
+	case ir.OLSH, ir.ORSH:
+		nr = defaultlit(nr, ir.Types[ir.TUINT])
 
 		n.Right = nr
 		if nr.Type != nil && (Issigned[nr.Type.Etype] || !Isint[nr.Type.Etype]) {
 			goto illegal
 		}
-		if nl.Val().Ctype() != CTRUNE {
+		if nl.Val().Ctype() != ir.CTRUNE {
 			nl.SetVal(toint(nl.Val()))
 		}
 		nr.SetVal(toint(nr.Val()))
@@ -776,38 +731,38 @@ func evconst(n *Node) {
 	// n->left, in case someone still refers to it (e.g. iota).
 	v = nl.Val()
 
-	if wl == TIDEAL {
+	if wl == ir.TIDEAL {
 		v = copyval(v)
 	}
 
 	rv = nr.Val()
 
 	// convert to common ideal
-	if v.Ctype() == CTCPLX || rv.Ctype() == CTCPLX {
+	if v.Ctype() == ir.CTCPLX || rv.Ctype() == ir.CTCPLX {
 		v = tocplx(v)
 		rv = tocplx(rv)
 	}
 
-	if v.Ctype() == CTFLT || rv.Ctype() == CTFLT {
+	if v.Ctype() == ir.CTFLT || rv.Ctype() == ir.CTFLT {
 		v = toflt(v)
 		rv = toflt(rv)
 	}
 
 	// Rune and int turns into rune.
-	if v.Ctype() == CTRUNE && rv.Ctype() == CTINT {
-		i := new(Mpint)
-		i.Set(rv.U.(*Mpint))
+	if v.Ctype() == ir.CTRUNE && rv.Ctype() == ir.CTINT {
+		i := new(ir.Mpint)
+		i.Set(rv.U.(*ir.Mpint))
 		i.Rune = true
 		rv.U = i
 	}
-	if v.Ctype() == CTINT && rv.Ctype() == CTRUNE {
-		if n.Op == OLSH || n.Op == ORSH {
-			i := new(Mpint)
-			i.Set(rv.U.(*Mpint))
+	if v.Ctype() == ir.CTINT && rv.Ctype() == ir.CTRUNE {
+		if n.Op == ir.OLSH || n.Op == ir.ORSH {
+			i := new(ir.Mpint)
+			i.Set(rv.U.(*ir.Mpint))
 			rv.U = i
 		} else {
-			i := new(Mpint)
-			i.Set(v.U.(*Mpint))
+			i := new(ir.Mpint)
+			i.Set(v.U.(*ir.Mpint))
 			i.Rune = true
 			v.U = i
 		}
@@ -828,77 +783,80 @@ func evconst(n *Node) {
 
 	case OADD_ | CTINT_,
 		OADD_ | CTRUNE_:
-		v.U.(*Mpint).Add(rv.U.(*Mpint))
+		v.U.(*ir.Mpint).Add(rv.U.(*ir.Mpint))
 
 	case OSUB_ | CTINT_,
 		OSUB_ | CTRUNE_:
-		v.U.(*Mpint).Sub(rv.U.(*Mpint))
+		v.U.(*ir.Mpint).Sub(rv.U.(*ir.Mpint))
 
 	case OMUL_ | CTINT_,
 		OMUL_ | CTRUNE_:
-		v.U.(*Mpint).Mul(rv.U.(*Mpint))
+		v.U.(*ir.Mpint).Mul(rv.U.(*ir.Mpint))
 
 	case ODIV_ | CTINT_,
 		ODIV_ | CTRUNE_:
-		if rv.U.(*Mpint).CmpInt64(0) == 0 {
-			Yyerror("division by zero")
-			v.U.(*Mpint).SetOverflow()
+		if rv.U.(*ir.Mpint).CmpInt64(0) == 0 {
+			Yyerror("division by zero in %v", n)
+			diag = 1
+			v.U.(*ir.Mpint).SetOverflow()
 			break
 		}
 
-		v.U.(*Mpint).Quo(rv.U.(*Mpint))
+		v.U.(*ir.Mpint).Quo(rv.U.(*ir.Mpint))
 
 	case OMOD_ | CTINT_,
 		OMOD_ | CTRUNE_:
-		if rv.U.(*Mpint).CmpInt64(0) == 0 {
-			Yyerror("division by zero")
-			v.U.(*Mpint).SetOverflow()
+		if rv.U.(*ir.Mpint).CmpInt64(0) == 0 {
+			Yyerror("division by zero in %v", n)
+			diag = 1
+			v.U.(*ir.Mpint).SetOverflow()
 			break
 		}
 
-		v.U.(*Mpint).Rem(rv.U.(*Mpint))
+		v.U.(*ir.Mpint).Rem(rv.U.(*ir.Mpint))
 
 	case OLSH_ | CTINT_,
 		OLSH_ | CTRUNE_:
-		v.U.(*Mpint).Lsh(rv.U.(*Mpint))
+		v.U.(*ir.Mpint).Lsh(rv.U.(*ir.Mpint))
 
 	case ORSH_ | CTINT_,
 		ORSH_ | CTRUNE_:
-		v.U.(*Mpint).Rsh(rv.U.(*Mpint))
+		v.U.(*ir.Mpint).Rsh(rv.U.(*ir.Mpint))
 
 	case OOR_ | CTINT_,
 		OOR_ | CTRUNE_:
-		v.U.(*Mpint).Or(rv.U.(*Mpint))
+		v.U.(*ir.Mpint).Or(rv.U.(*ir.Mpint))
 
 	case OAND_ | CTINT_,
 		OAND_ | CTRUNE_:
-		v.U.(*Mpint).And(rv.U.(*Mpint))
+		v.U.(*ir.Mpint).And(rv.U.(*ir.Mpint))
 
 	case OANDNOT_ | CTINT_,
 		OANDNOT_ | CTRUNE_:
-		v.U.(*Mpint).AndNot(rv.U.(*Mpint))
+		v.U.(*ir.Mpint).AndNot(rv.U.(*ir.Mpint))
 
 	case OXOR_ | CTINT_,
 		OXOR_ | CTRUNE_:
-		v.U.(*Mpint).Xor(rv.U.(*Mpint))
+		v.U.(*ir.Mpint).Xor(rv.U.(*ir.Mpint))
 
 	case OADD_ | CTFLT_:
-		v.U.(*Mpflt).Add(rv.U.(*Mpflt))
+		v.U.(*ir.Mpflt).Add(rv.U.(*ir.Mpflt))
 
 	case OSUB_ | CTFLT_:
-		v.U.(*Mpflt).Sub(rv.U.(*Mpflt))
+		v.U.(*ir.Mpflt).Sub(rv.U.(*ir.Mpflt))
 
 	case OMUL_ | CTFLT_:
-		v.U.(*Mpflt).Mul(rv.U.(*Mpflt))
+		v.U.(*ir.Mpflt).Mul(rv.U.(*ir.Mpflt))
 
 	case ODIV_ | CTFLT_:
-		if rv.U.(*Mpflt).CmpFloat64(0) == 0 {
-			Yyerror("division by zero")
-			v.U.(*Mpflt).SetFloat64(1.0)
+		if rv.U.(*ir.Mpflt).CmpFloat64(0) == 0 {
+			Yyerror("division by zero in %v", n)
+			diag = 1
+			v.U.(*ir.Mpflt).SetFloat64(1.0)
 			break
 		}
 
-		v.U.(*Mpflt).Quo(rv.U.(*Mpflt))
+		v.U.(*ir.Mpflt).Quo(rv.U.(*ir.Mpflt))
 
 		// The default case above would print 'ideal % ideal',
 	// which is not quite an ideal error.
@@ -911,25 +869,26 @@ func evconst(n *Node) {
 		return
 
 	case OADD_ | CTCPLX_:
-		v.U.(*Mpcplx).Real.Add(&rv.U.(*Mpcplx).Real)
-		v.U.(*Mpcplx).Imag.Add(&rv.U.(*Mpcplx).Imag)
+		v.U.(*ir.Mpcplx).Real.Add(&rv.U.(*ir.Mpcplx).Real)
+		v.U.(*ir.Mpcplx).Imag.Add(&rv.U.(*ir.Mpcplx).Imag)
 
 	case OSUB_ | CTCPLX_:
-		v.U.(*Mpcplx).Real.Sub(&rv.U.(*Mpcplx).Real)
-		v.U.(*Mpcplx).Imag.Sub(&rv.U.(*Mpcplx).Imag)
+		v.U.(*ir.Mpcplx).Real.Sub(&rv.U.(*ir.Mpcplx).Real)
+		v.U.(*ir.Mpcplx).Imag.Sub(&rv.U.(*ir.Mpcplx).Imag)
 
 	case OMUL_ | CTCPLX_:
-		cmplxmpy(v.U.(*Mpcplx), rv.U.(*Mpcplx))
+		cmplxmpy(v.U.(*ir.Mpcplx), rv.U.(*ir.Mpcplx))
 
 	case ODIV_ | CTCPLX_:
-		if rv.U.(*Mpcplx).Real.CmpFloat64(0) == 0 && rv.U.(*Mpcplx).Imag.CmpFloat64(0) == 0 {
-			Yyerror("complex division by zero")
-			rv.U.(*Mpcplx).Real.SetFloat64(1.0)
-			rv.U.(*Mpcplx).Imag.SetFloat64(0.0)
+		if rv.U.(*ir.Mpcplx).Real.CmpFloat64(0) == 0 && rv.U.(*ir.Mpcplx).Imag.CmpFloat64(0) == 0 {
+			Yyerror("complex division by zero in %v", n)
+			diag = 1
+			rv.U.(*ir.Mpcplx).Real.SetFloat64(1.0)
+			rv.U.(*ir.Mpcplx).Imag.SetFloat64(0.0)
 			break
 		}
 
-		cmplxdiv(v.U.(*Mpcplx), rv.U.(*Mpcplx))
+		cmplxdiv(v.U.(*ir.Mpcplx), rv.U.(*ir.Mpcplx))
 
 	case OEQ_ | CTNIL_:
 		goto settrue
@@ -939,90 +898,90 @@ func evconst(n *Node) {
 
 	case OEQ_ | CTINT_,
 		OEQ_ | CTRUNE_:
-		if v.U.(*Mpint).Cmp(rv.U.(*Mpint)) == 0 {
+		if v.U.(*ir.Mpint).Cmp(rv.U.(*ir.Mpint)) == 0 {
 			goto settrue
 		}
 		goto setfalse
 
 	case ONE_ | CTINT_,
 		ONE_ | CTRUNE_:
-		if v.U.(*Mpint).Cmp(rv.U.(*Mpint)) != 0 {
+		if v.U.(*ir.Mpint).Cmp(rv.U.(*ir.Mpint)) != 0 {
 			goto settrue
 		}
 		goto setfalse
 
 	case OLT_ | CTINT_,
 		OLT_ | CTRUNE_:
-		if v.U.(*Mpint).Cmp(rv.U.(*Mpint)) < 0 {
+		if v.U.(*ir.Mpint).Cmp(rv.U.(*ir.Mpint)) < 0 {
 			goto settrue
 		}
 		goto setfalse
 
 	case OLE_ | CTINT_,
 		OLE_ | CTRUNE_:
-		if v.U.(*Mpint).Cmp(rv.U.(*Mpint)) <= 0 {
+		if v.U.(*ir.Mpint).Cmp(rv.U.(*ir.Mpint)) <= 0 {
 			goto settrue
 		}
 		goto setfalse
 
 	case OGE_ | CTINT_,
 		OGE_ | CTRUNE_:
-		if v.U.(*Mpint).Cmp(rv.U.(*Mpint)) >= 0 {
+		if v.U.(*ir.Mpint).Cmp(rv.U.(*ir.Mpint)) >= 0 {
 			goto settrue
 		}
 		goto setfalse
 
 	case OGT_ | CTINT_,
 		OGT_ | CTRUNE_:
-		if v.U.(*Mpint).Cmp(rv.U.(*Mpint)) > 0 {
+		if v.U.(*ir.Mpint).Cmp(rv.U.(*ir.Mpint)) > 0 {
 			goto settrue
 		}
 		goto setfalse
 
 	case OEQ_ | CTFLT_:
-		if v.U.(*Mpflt).Cmp(rv.U.(*Mpflt)) == 0 {
+		if v.U.(*ir.Mpflt).Cmp(rv.U.(*ir.Mpflt)) == 0 {
 			goto settrue
 		}
 		goto setfalse
 
 	case ONE_ | CTFLT_:
-		if v.U.(*Mpflt).Cmp(rv.U.(*Mpflt)) != 0 {
+		if v.U.(*ir.Mpflt).Cmp(rv.U.(*ir.Mpflt)) != 0 {
 			goto settrue
 		}
 		goto setfalse
 
 	case OLT_ | CTFLT_:
-		if v.U.(*Mpflt).Cmp(rv.U.(*Mpflt)) < 0 {
+		if v.U.(*ir.Mpflt).Cmp(rv.U.(*ir.Mpflt)) < 0 {
 			goto settrue
 		}
 		goto setfalse
 
 	case OLE_ | CTFLT_:
-		if v.U.(*Mpflt).Cmp(rv.U.(*Mpflt)) <= 0 {
+		if v.U.(*ir.Mpflt).Cmp(rv.U.(*ir.Mpflt)) <= 0 {
 			goto settrue
 		}
 		goto setfalse
 
 	case OGE_ | CTFLT_:
-		if v.U.(*Mpflt).Cmp(rv.U.(*Mpflt)) >= 0 {
+		if v.U.(*ir.Mpflt).Cmp(rv.U.(*ir.Mpflt)) >= 0 {
 			goto settrue
 		}
 		goto setfalse
 
 	case OGT_ | CTFLT_:
-		if v.U.(*Mpflt).Cmp(rv.U.(*Mpflt)) > 0 {
+		if v.U.(*ir.Mpflt).Cmp(rv.U.(*ir.Mpflt)) > 0 {
 			goto settrue
 		}
 		goto setfalse
 
 	case OEQ_ | CTCPLX_:
-		if v.U.(*Mpcplx).Real.Cmp(&rv.U.(*Mpcplx).Real) == 0 && v.U.(*Mpcplx).Imag.Cmp(&rv.U.(*Mpcplx).Imag) == 0 {
+		if v.U.(*ir.Mpcplx).Real.Cmp(&rv.U.(*ir.Mpcplx).Real) == 0 && v.U.(*ir.Mpcplx).Imag.Cmp(&rv.U.(*ir.Mpcplx).Imag) == 0 {
 			goto settrue
 		}
 		goto setfalse
 
 	case ONE_ | CTCPLX_:
-		if v.U.(*Mpcplx).Real.Cmp(&rv.U.(*Mpcplx).Real) != 0 || v.U.(*Mpcplx).Imag.Cmp(&rv.U.(*Mpcplx).Imag) != 0 {
+		if v.U.(*ir.Mpcplx).Real.Cmp(&rv.U.(*ir.Mpcplx).Real) != 0 || v.U.(*ir.Mpcplx).Imag.Cmp(&rv.U.(*ir.Mpcplx).Imag) != 0 {
 			goto settrue
 		}
 		goto setfalse
@@ -1088,6 +1047,13 @@ func evconst(n *Node) {
 		goto setfalse
 	}
 
+	if x, ok := v.U.(*ir.Mpint); ok && x.OvfBits != 0 {
+		// Report the operation whose result crossed the limit,
+		// not the enclosing expression.
+		Yyerror("constant %s overflow: %v needs %d bits, limit is %d", mpopname[n.Op], n, x.OvfBits, ir.Mpintprec)
+		x.OvfBits = 0
+	}
+
 	goto ret
 
 ret:
@@ -1096,17 +1062,20 @@ ret:
 
 	// restore value of n->orig.
 	n.Orig = norig
+	if diag != 0 {
+		n.Diag = 1 // error already reported; see typecheck OTARRAY
+	}
 
 	n.SetVal(v)
 
 	// check range.
 	lno = setlineno(n)
-	overflow(v, n.Type)
+	overflow(v, n.Type, nil)
 	lineno = lno
 
 	// truncate precision for non-ideal float.
-	if v.Ctype() == CTFLT && n.Type.Etype != TIDEAL {
-		n.SetVal(Val{truncfltlit(v.U.(*Mpflt), n.Type)})
+	if v.Ctype() == ir.CTFLT && n.Type.Etype != ir.TIDEAL {
+		n.SetVal(ir.Val{U: truncfltlit(v.U.(*ir.Mpflt), n.Type, nil)})
 	}
 	return
 
@@ -1135,74 +1104,74 @@ illegal:
 	}
 }
 
-func nodlit(v Val) *Node {
-	n := Nod(OLITERAL, nil, nil)
+func nodlit(v ir.Val) *ir.Node {
+	n := Nod(ir.OLITERAL, nil, nil)
 	n.SetVal(v)
 	switch v.Ctype() {
 	default:
 		Fatalf("nodlit ctype %d", v.Ctype())
 
-	case CTSTR:
-		n.Type = idealstring
+	case ir.CTSTR:
+		n.Type = ir.Idealstring
 
-	case CTBOOL:
-		n.Type = idealbool
+	case ir.CTBOOL:
+		n.Type = ir.Idealbool
 
-	case CTINT, CTRUNE, CTFLT, CTCPLX:
-		n.Type = Types[TIDEAL]
+	case ir.CTINT, ir.CTRUNE, ir.CTFLT, ir.CTCPLX:
+		n.Type = ir.Types[ir.TIDEAL]
 
-	case CTNIL:
-		n.Type = Types[TNIL]
+	case ir.CTNIL:
+		n.Type = ir.Types[ir.TNIL]
 	}
 
 	return n
 }
 
-func nodcplxlit(r Val, i Val) *Node {
+func nodcplxlit(r ir.Val, i ir.Val) *ir.Node {
 	r = toflt(r)
 	i = toflt(i)
 
-	c := new(Mpcplx)
-	n := Nod(OLITERAL, nil, nil)
-	n.Type = Types[TIDEAL]
-	n.SetVal(Val{c})
+	c := new(ir.Mpcplx)
+	n := Nod(ir.OLITERAL, nil, nil)
+	n.Type = ir.Types[ir.TIDEAL]
+	n.SetVal(ir.Val{U: c})
 
-	if r.Ctype() != CTFLT || i.Ctype() != CTFLT {
+	if r.Ctype() != ir.CTFLT || i.Ctype() != ir.CTFLT {
 		Fatalf("nodcplxlit ctype %d/%d", r.Ctype(), i.Ctype())
 	}
 
-	c.Real.Set(r.U.(*Mpflt))
-	c.Imag.Set(i.U.(*Mpflt))
+	c.Real.Set(r.U.(*ir.Mpflt))
+	c.Imag.Set(i.U.(*ir.Mpflt))
 	return n
 }
 
 // idealkind returns a constant kind like consttype
 // but for an arbitrary "ideal" (untyped constant) expression.
-func idealkind(n *Node) Ctype {
+func idealkind(n *ir.Node) ir.Ctype {
 	if n == nil || !isideal(n.Type) {
-		return CTxxx
+		return ir.CTxxx
 	}
 
 	switch n.Op {
 	default:
-		return CTxxx
+		return ir.CTxxx
 
-	case OLITERAL:
+	case ir.OLITERAL:
 		return n.Val().Ctype()
 
 		// numeric kinds.
-	case OADD,
-		OAND,
-		OANDNOT,
-		OCOM,
-		ODIV,
-		OMINUS,
-		OMOD,
-		OMUL,
-		OSUB,
-		OXOR,
-		OOR,
-		OPLUS:
+	case ir.OADD,
+		ir.OAND,
+		ir.OANDNOT,
+		ir.OCOM,
+		ir.ODIV,
+		ir.OMINUS,
+		ir.OMOD,
+		ir.OMUL,
+		ir.OSUB,
+		ir.OXOR,
+		ir.OOR,
+		ir.OPLUS:
 		k1 := idealkind(n.Left)
 
 		k2 := idealkind(n.Right)
@@ -1212,56 +1181,64 @@ func idealkind(n *Node) Ctype {
 			return k2
 		}
 
-	case OREAL, OIMAG:
-		return CTFLT
+	case ir.OREAL, ir.OIMAG:
+		return ir.CTFLT
 
-	case OCOMPLEX:
-		return CTCPLX
+	case ir.OCOMPLEX:
+		return ir.CTCPLX
 
-	case OADDSTR:
-		return CTSTR
+	case ir.OADDSTR:
+		return ir.CTSTR
 
-	case OANDAND,
-		OEQ,
-		OGE,
-		OGT,
-		OLE,
-		OLT,
-		ONE,
-		ONOT,
-		OOROR,
-		OCMPSTR,
-		OCMPIFACE:
-		return CTBOOL
+	case ir.OANDAND,
+		ir.OEQ,
+		ir.OGE,
+		ir.OGT,
+		ir.OLE,
+		ir.OLT,
+		ir.ONE,
+		ir.ONOT,
+		ir.OOROR,
+		ir.OCMPSTR,
+		ir.OCMPIFACE:
+		return ir.CTBOOL
 
 		// shifts (beware!).
-	case OLSH, ORSH:
+	case ir.OLSH, ir.ORSH:
 		return idealkind(n.Left)
 	}
 }
 
 // The result of defaultlit MUST be assigned back to n, e.g.
 // 	n.Left = defaultlit(n.Left, t)
-func defaultlit(n *Node, t *Type) *Node {
+func defaultlit(n *ir.Node, t *ir.Type) *ir.Node {
+	return defaultlitfn(n, t, nil)
+}
+
+// defaultlitfn is like defaultlit, but context, if not nil,
+// describes where t came from, for overflow errors.
+// The result of defaultlitfn MUST be assigned back to n, e.g.
+// 	n.Left = defaultlitfn(n.Left, t, context)
+func defaultlitfn(n *ir.Node, t *ir.Type, context func() string) *ir.Node {
 	if n == nil || !isideal(n.Type) {
 		return n
 	}
 
-	if n.Op == OLITERAL {
+	if n.Op == ir.OLITERAL {
 		nn := *n
 		n = &nn
 	}
 
 	lno := setlineno(n)
 	ctype := idealkind(n)
-	var t1 *Type
+	var t1 *ir.Type
 	switch ctype {
 	default:
 		if t != nil {
-			return convlit(n, t)
+			return convlit1(n, t, false, context)
 		}
 
-		if n.Val().Ctype() == CTNIL {
+		if n.Val().Ctype() == ir.CTNIL {
 			lineno = lno
 			if n.Diag == 0 {
 				Yyerror("use of untyped nil")
@@ -1272,38 +1249,38 @@ func defaultlit(n *Node, t *Type) *Node {
 			break
 		}
 
-		if n.Val().Ctype() == CTSTR {
-			t1 := Types[TSTRING]
+		if n.Val().Ctype() == ir.CTSTR {
+			t1 := ir.Types[ir.TSTRING]
 			n = convlit(n, t1)
 			break
 		}
 
 		Yyerror("defaultlit: unknown literal: %v", n)
 
-	case CTxxx:
-		Fatalf("defaultlit: idealkind is CTxxx: %v", Nconv(n, FmtSign))
+	case ir.CTxxx:
+		Fatalf("defaultlit: idealkind is CTxxx: %v", Nconv(n, ir.FmtSign))
 
-	case CTBOOL:
-		t1 := Types[TBOOL]
-		if t != nil && t.Etype == TBOOL {
+	case ir.CTBOOL:
+		t1 := ir.Types[ir.TBOOL]
+		if t != nil && t.Etype == ir.TBOOL {
 			t1 = t
 		}
 		n = convlit(n, t1)
 
-	case CTINT:
-		t1 = Types[TINT]
+	case ir.CTINT:
+		t1 = ir.Types[ir.TINT]
 		goto num
 
-	case CTRUNE:
-		t1 = runetype
+	case ir.CTRUNE:
+		t1 = ir.Runetype
 		goto num
 
-	case CTFLT:
-		t1 = Types[TFLOAT64]
+	case ir.CTFLT:
+		t1 = ir.Types[ir.TFLOAT64]
 		goto num
 
-	case CTCPLX:
-		t1 = Types[TCOMPLEX128]
+	case ir.CTCPLX:
+		t1 = ir.Types[ir.TCOMPLEX128]
 		goto num
 	}
 
@@ -1325,15 +1302,19 @@ num:
 			t1 = t
 			v1 = tocplx(n.Val())
 		}
-		if n.Val().Ctype() != CTxxx {
+		if n.Val().Ctype() != ir.CTxxx {
 			n.SetVal(v1)
 		}
 	}
 
-	if n.Val().Ctype() != CTxxx {
-		overflow(n.Val(), t1)
+	if t1 != t {
+		// t1 is the default type, not the one context describes.
+		context = nil
+	}
+	if n.Val().Ctype() != ir.CTxxx {
+		overflow(n.Val(), t1, context)
 	}
-	n = convlit(n, t1)
+	n = convlit1(n, t1, false, context)
 	lineno = lno
 	return n
 }
@@ -1344,7 +1325,7 @@ num:
 // force means must assign concrete (non-ideal) type.
 // The results of defaultlit2 MUST be assigned back to l and r, e.g.
 // 	n.Left, n.Right = defaultlit2(n.Left, n.Right, force)
-func defaultlit2(l *Node, r *Node, force bool) (*Node, *Node) {
+func defaultlit2(l *ir.Node, r *ir.Node, force bool) (*ir.Node, *ir.Node) {
 	if l.Type == nil || r.Type == nil {
 		return l, r
 	}
@@ -1362,57 +1343,57 @@ func defaultlit2(l *Node, r *Node, force bool) (*Node, *Node) {
 		return l, r
 	}
 
-	if l.Type.Etype == TBOOL {
-		l = convlit(l, Types[TBOOL])
-		r = convlit(r, Types[TBOOL])
+	if l.Type.Etype == ir.TBOOL {
+		l = convlit(l, ir.Types[ir.TBOOL])
+		r = convlit(r, ir.Types[ir.TBOOL])
 	}
 
 	lkind := idealkind(l)
 	rkind := idealkind(r)
-	if lkind == CTCPLX || rkind == CTCPLX {
-		l = convlit(l, Types[TCOMPLEX128])
-		r = convlit(r, Types[TCOMPLEX128])
+	if lkind == ir.CTCPLX || rkind == ir.CTCPLX {
+		l = convlit(l, ir.Types[ir.TCOMPLEX128])
+		r = convlit(r, ir.Types[ir.TCOMPLEX128])
 		return l, r
 	}
 
-	if lkind == CTFLT || rkind == CTFLT {
-		l = convlit(l, Types[TFLOAT64])
-		r = convlit(r, Types[TFLOAT64])
+	if lkind == ir.CTFLT || rkind == ir.CTFLT {
+		l = convlit(l, ir.Types[ir.TFLOAT64])
+		r = convlit(r, ir.Types[ir.TFLOAT64])
 		return l, r
 	}
 
-	if lkind == CTRUNE || rkind == CTRUNE {
-		l = convlit(l, runetype)
-		r = convlit(r, runetype)
+	if lkind == ir.CTRUNE || rkind == ir.CTRUNE {
+		l = convlit(l, ir.Runetype)
+		r = convlit(r, ir.Runetype)
 		return l, r
 	}
 
-	l = convlit(l, Types[TINT])
-	r = convlit(r, Types[TINT])
+	l = convlit(l, ir.Types[ir.TINT])
+	r = convlit(r, ir.Types[ir.TINT])
 
 	return l, r
 }
 
 // strlit returns the value of a literal string Node as a string.
-func strlit(n *Node) string {
+func strlit(n *ir.Node) string {
 	return n.Val().U.(string)
 }
 
-func Smallintconst(n *Node) bool {
-	if n.Op == OLITERAL && Isconst(n, CTINT) && n.Type != nil {
+func Smallintconst(n *ir.Node) bool {
+	if n.Op == ir.OLITERAL && ir.Isconst(n, ir.CTINT) && n.Type != nil {
 		switch Simtype[n.Type.Etype] {
-		case TINT8,
-			TUINT8,
-			TINT16,
-			TUINT16,
-			TINT32,
-			TUINT32,
-			TBOOL,
-			TPTR32:
+		case ir.TINT8,
+			ir.TUINT8,
+			ir.TINT16,
+			ir.TUINT16,
+			ir.TINT32,
+			ir.TUINT32,
+			ir.TBOOL,
+			ir.TPTR32:
 			return true
 
-		case TIDEAL, TINT64, TUINT64, TPTR64:
-			if n.Val().U.(*Mpint).Cmp(Minintval[TINT32]) < 0 || n.Val().U.(*Mpint).Cmp(Maxintval[TINT32]) > 0 {
+		case ir.TIDEAL, ir.TINT64, ir.TUINT64, ir.TPTR64:
+			if n.Val().U.(*ir.Mpint).Cmp(Minintval[ir.TINT32]) < 0 || n.Val().U.(*ir.Mpint).Cmp(Maxintval[ir.TINT32]) > 0 {
 				break
 			}
 			return true
@@ -1422,23 +1403,23 @@ func Smallintconst(n *Node) bool {
 	return false
 }
 
-func nonnegconst(n *Node) int {
-	if n.Op == OLITERAL && n.Type != nil {
+func nonnegconst(n *ir.Node) int {
+	if n.Op == ir.OLITERAL && n.Type != nil {
 		switch Simtype[n.Type.Etype] {
 		// check negative and 2^31
-		case TINT8,
-			TUINT8,
-			TINT16,
-			TUINT16,
-			TINT32,
-			TUINT32,
-			TINT64,
-			TUINT64,
-			TIDEAL:
-			if n.Val().U.(*Mpint).Cmp(Minintval[TUINT32]) < 0 || n.Val().U.(*Mpint).Cmp(Maxintval[TINT32]) > 0 {
+		case ir.TINT8,
+			ir.TUINT8,
+			ir.TINT16,
+			ir.TUINT16,
+			ir.TINT32,
+			ir.TUINT32,
+			ir.TINT64,
+			ir.TUINT64,
+			ir.TIDEAL:
+			if n.Val().U.(*ir.Mpint).Cmp(Minintval[ir.TUINT32]) < 0 || n.Val().U.(*ir.Mpint).Cmp(Maxintval[ir.TINT32]) > 0 {
 				break
 			}
-			return int(n.Val().U.(*Mpint).Int64())
+			return int(n.Val().U.(*ir.Mpint).Int64())
 		}
 	}
 
@@ -1447,27 +1428,27 @@ func nonnegconst(n *Node) int {
 
 // convert x to type et and back to int64
 // for sign extension and truncation.
-func iconv(x int64, et EType) int64 {
+func iconv(x int64, et ir.EType) int64 {
 	switch et {
-	case TINT8:
+	case ir.TINT8:
 		x = int64(int8(x))
 
-	case TUINT8:
+	case ir.TUINT8:
 		x = int64(uint8(x))
 
-	case TINT16:
+	case ir.TINT16:
 		x = int64(int16(x))
 
-	case TUINT16:
+	case ir.TUINT16:
 		x = int64(uint64(x))
 
-	case TINT32:
+	case ir.TINT32:
 		x = int64(int32(x))
 
-	case TUINT32:
+	case ir.TUINT32:
 		x = int64(uint32(x))
 
-	case TINT64, TUINT64:
+	case ir.TINT64, ir.TUINT64:
 		break
 	}
 
@@ -1476,67 +1457,67 @@ func iconv(x int64, et EType) int64 {
 
 // Convconst converts constant node n to type t and
 // places the result in con.
-func (n *Node) Convconst(con *Node, t *Type) {
+func Convconst(n, con *ir.Node, t *ir.Type) {
 	tt := Simsimtype(t)
 
 	// copy the constant for conversion
-	Nodconst(con, Types[TINT8], 0)
+	Nodconst(con, ir.Types[ir.TINT8], 0)
 
 	con.Type = t
 	con.SetVal(n.Val())
 
 	if Isint[tt] {
-		con.SetVal(Val{new(Mpint)})
+		con.SetVal(ir.Val{U: new(ir.Mpint)})
 		var i int64
 		switch n.Val().Ctype() {
 		default:
-			Fatalf("convconst ctype=%d %v", n.Val().Ctype(), Tconv(t, FmtLong))
+			Fatalf("convconst ctype=%d %v", n.Val().Ctype(), Tconv(t, ir.FmtLong))
 
-		case CTINT, CTRUNE:
-			i = n.Val().U.(*Mpint).Int64()
+		case ir.CTINT, ir.CTRUNE:
+			i = n.Val().U.(*ir.Mpint).Int64()
 
-		case CTBOOL:
+		case ir.CTBOOL:
 			i = int64(obj.Bool2int(n.Val().U.(bool)))
 
-		case CTNIL:
+		case ir.CTNIL:
 			i = 0
 		}
 
 		i = iconv(i, tt)
-		con.Val().U.(*Mpint).SetInt64(i)
+		con.Val().U.(*ir.Mpint).SetInt64(i)
 		return
 	}
 
 	if Isfloat[tt] {
 		con.SetVal(toflt(con.Val()))
-		if con.Val().Ctype() != CTFLT {
+		if con.Val().Ctype() != ir.CTFLT {
 			Fatalf("convconst ctype=%d %v", con.Val().Ctype(), t)
 		}
-		if tt == TFLOAT32 {
-			con.SetVal(Val{truncfltlit(con.Val().U.(*Mpflt), t)})
+		if tt == ir.TFLOAT32 {
+			con.SetVal(ir.Val{U: truncfltlit(con.Val().U.(*ir.Mpflt), t, nil)})
 		}
 		return
 	}
 
 	if Iscomplex[tt] {
 		con.SetVal(tocplx(con.Val()))
-		if tt == TCOMPLEX64 {
-			con.Val().U.(*Mpcplx).Real = *truncfltlit(&con.Val().U.(*Mpcplx).Real, Types[TFLOAT32])
-			con.Val().U.(*Mpcplx).Imag = *truncfltlit(&con.Val().U.(*Mpcplx).Imag, Types[TFLOAT32])
+		if tt == ir.TCOMPLEX64 {
+			con.Val().U.(*ir.Mpcplx).Real = *truncfltlit(&con.Val().U.(*ir.Mpcplx).Real, ir.Types[ir.TFLOAT32], nil)
+			con.Val().U.(*ir.Mpcplx).Imag = *truncfltlit(&con.Val().U.(*ir.Mpcplx).Imag, ir.Types[ir.TFLOAT32], nil)
 		}
 		return
 	}
 
-	Fatalf("convconst %v constant", Tconv(t, FmtLong))
+	Fatalf("convconst %v constant", Tconv(t, ir.FmtLong))
 }
 
 // complex multiply v *= rv
 //	(a, b) * (c, d) = (a*c - b*d, b*c + a*d)
-func cmplxmpy(v *Mpcplx, rv *Mpcplx) {
-	var ac Mpflt
-	var bd Mpflt
-	var bc Mpflt
-	var ad Mpflt
+func cmplxmpy(v *ir.Mpcplx, rv *ir.Mpcplx) {
+	var ac ir.Mpflt
+	var bd ir.Mpflt
+	var bc ir.Mpflt
+	var ad ir.Mpflt
 
 	ac.Set(&v.Real)
 	ac.Mul(&rv.Real) // ac
@@ -1564,12 +1545,12 @@ func cmplxmpy(v *Mpcplx, rv *Mpcplx) {
 
 // complex divide v /= rv
 //	(a, b) / (c, d) = ((a*c + b*d), (b*c - a*d))/(c*c + d*d)
-func cmplxdiv(v *Mpcplx, rv *Mpcplx) {
-	var ac Mpflt
-	var bd Mpflt
-	var bc Mpflt
-	var ad Mpflt
-	var cc_plus_dd Mpflt
+func cmplxdiv(v *ir.Mpcplx, rv *ir.Mpcplx) {
+	var ac ir.Mpflt
+	var bd ir.Mpflt
+	var bc ir.Mpflt
+	var ad ir.Mpflt
+	var cc_plus_dd ir.Mpflt
 
 	cc_plus_dd.Set(&rv.Real)
 	cc_plus_dd.Mul(&rv.Real) // cc
@@ -1612,50 +1593,50 @@ func cmplxdiv(v *Mpcplx, rv *Mpcplx) {
 // may be known at compile time, are not Go language constants.
 // Only called for expressions known to evaluated to compile-time
 // constants.
-func isgoconst(n *Node) bool {
+func isgoconst(n *ir.Node) bool {
 	if n.Orig != nil {
 		n = n.Orig
 	}
 
 	switch n.Op {
-	case OADD,
-		OADDSTR,
-		OAND,
-		OANDAND,
-		OANDNOT,
-		OCOM,
-		ODIV,
-		OEQ,
-		OGE,
-		OGT,
-		OLE,
-		OLSH,
-		OLT,
-		OMINUS,
-		OMOD,
-		OMUL,
-		ONE,
-		ONOT,
-		OOR,
-		OOROR,
-		OPLUS,
-		ORSH,
-		OSUB,
-		OXOR,
-		OIOTA,
-		OCOMPLEX,
-		OREAL,
-		OIMAG:
+	case ir.OADD,
+		ir.OADDSTR,
+		ir.OAND,
+		ir.OANDAND,
+		ir.OANDNOT,
+		ir.OCOM,
+		ir.ODIV,
+		ir.OEQ,
+		ir.OGE,
+		ir.OGT,
+		ir.OLE,
+		ir.OLSH,
+		ir.OLT,
+		ir.OMINUS,
+		ir.OMOD,
+		ir.OMUL,
+		ir.ONE,
+		ir.ONOT,
+		ir.OOR,
+		ir.OOROR,
+		ir.OPLUS,
+		ir.ORSH,
+		ir.OSUB,
+		ir.OXOR,
+		ir.OIOTA,
+		ir.OCOMPLEX,
+		ir.OREAL,
+		ir.OIMAG:
 		if isgoconst(n.Left) && (n.Right == nil || isgoconst(n.Right)) {
 			return true
 		}
 
-	case OCONV:
+	case ir.OCONV:
 		if okforconst[n.Type.Etype] && isgoconst(n.Left) {
 			return true
 		}
 
-	case OLEN, OCAP:
+	case ir.OLEN, ir.OCAP:
 		l := n.Left
 		if isgoconst(l) {
 			return true
@@ -1673,30 +1654,30 @@ func isgoconst(n *Node) bool {
 			return true
 		}
 
-	case OLITERAL:
-		if n.Val().Ctype() != CTNIL {
+	case ir.OLITERAL:
+		if n.Val().Ctype() != ir.CTNIL {
 			return true
 		}
 
-	case ONAME:
+	case ir.ONAME:
 		l := n.Sym.Def
-		if l != nil && l.Op == OLITERAL && n.Val().Ctype() != CTNIL {
+		if l != nil && l.Op == ir.OLITERAL && n.Val().Ctype() != ir.CTNIL {
 			return true
 		}
 
-	case ONONAME:
-		if n.Sym.Def != nil && n.Sym.Def.Op == OIOTA {
+	case ir.ONONAME:
+		if n.Sym.Def != nil && n.Sym.Def.Op == ir.OIOTA {
 			return true
 		}
 
 		// Only constant calls are unsafe.Alignof, Offsetof, and Sizeof.
-	case OCALL:
+	case ir.OCALL:
 		l := n.Left
 
-		for l.Op == OPAREN {
+		for l.Op == ir.OPAREN {
 			l = l.Left
 		}
-		if l.Op != ONAME || l.Sym.Pkg != unsafepkg {
+		if l.Op != ir.ONAME || l.Sym.Pkg != unsafepkg {
 			break
 		}
 		if l.Sym.Name == "Alignof" || l.Sym.Name == "Offsetof" || l.Sym.Name == "Sizeof" {
@@ -1708,31 +1689,31 @@ func isgoconst(n *Node) bool {
 	return false
 }
 
-func hascallchan(n *Node) bool {
+func hascallchan(n *ir.Node) bool {
 	if n == nil {
 		return false
 	}
 	switch n.Op {
-	case OAPPEND,
-		OCALL,
-		OCALLFUNC,
-		OCALLINTER,
-		OCALLMETH,
-		OCAP,
-		OCLOSE,
-		OCOMPLEX,
-		OCOPY,
-		ODELETE,
-		OIMAG,
-		OLEN,
-		OMAKE,
-		ONEW,
-		OPANIC,
-		OPRINT,
-		OPRINTN,
-		OREAL,
-		ORECOVER,
-		ORECV:
+	case ir.OAPPEND,
+		ir.OCALL,
+		ir.OCALLFUNC,
+		ir.OCALLINTER,
+		ir.OCALLMETH,
+		ir.OCAP,
+		ir.OCLOSE,
+		ir.OCOMPLEX,
+		ir.OCOPY,
+		ir.ODELETE,
+		ir.OIMAG,
+		ir.OLEN,
+		ir.OMAKE,
+		ir.ONEW,
+		ir.OPANIC,
+		ir.OPRINT,
+		ir.OPRINTN,
+		ir.OREAL,
+		ir.ORECOVER,
+		ir.ORECV:
 		return true
 	}
 
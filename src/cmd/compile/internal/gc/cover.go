@@ -0,0 +1,252 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gc
+
+import (
+	"bytes"
+	"cmd/compile/internal/ir"
+	"cmd/internal/obj"
+	"fmt"
+	"sort"
+)
+
+// Coverage instrumentation.
+//
+// With -cover, the compiler counts how often each basic block of
+// the package's functions runs, without the source rewriting done by
+// cmd/cover. Every block starts with an increment of its own element
+// of the package variable cover·counters. The inserted code takes its
+// position from the statement it precedes, so line numbers in the
+// compiled code are those of the original source.
+//
+// The package also gets a read-only symbol cover·blocks describing
+// the blocks, one line per counter in the form
+//
+//	file:startline.startcol,endline.endcol numstmt
+//
+// as in a cmd/cover profile. The compiler does not track columns,
+// so each block covers its lines in full. The blocks are numbered file
+// by file, so the counters of each file form one contiguous run.
+//
+// The package's init function passes both tables to the runtime by
+// calling
+//
+//	coverRegister(&cover·counters[0], len(cover·counters), &cover·blocks[0], len(cover·blocks))
+//
+// The runtime hands them on to package testing, which reports them
+// in "count" mode when the test binary was not built by cmd/cover;
+// see runtime/cover.go and testing/cover.go. The linker keeps
+// cover·blocks in the binary whenever it keeps cover·counters.
+//
+// Instrumentation runs before inlining, so a function inlined into
+// its caller still counts its blocks.
+//
+// With -d=libfuzzer, the counters are 8 bits wide, as libFuzzer
+// expects, and there is no cover·blocks. The package's init function
+// registers the counters with libFuzzer instead; see libfuzzer.go.
+
+var flag_cover int
+
+// coverblocks is the cover·blocks symbol, if the package has one,
+// and coverdata its contents. covercounters is the matching array
+// of counters.
+var (
+	coverblocks   *ir.Sym
+	coverdata     string
+	covercounters *ir.Node
+)
+
+type coverBlock struct {
+	start, end int32 // lines of first and last statement
+	nstmt      int
+}
+
+type coverState struct {
+	counters *ir.Node
+	blocks   []coverBlock
+	incs     []*ir.Node
+}
+
+// cover instruments the functions in top for coverage.
+func cover(top []*ir.Node) {
+	if compiling_runtime != 0 {
+		// Don't instrument the runtime, which registers
+		// the counters and runs before they are registered.
+		return
+	}
+	c := &coverState{counters: newname(Lookup("cover·counters"))}
+	for _, fn := range top {
+		if fn.Op != ir.ODCLFUNC || fn.Nbody.Len() == 0 {
+			continue
+		}
+		Curfn = fn
+		c.list(&fn.Nbody, fn.Lineno)
+	}
+	Curfn = nil
+
+	if len(c.blocks) == 0 {
+		return
+	}
+
+	t := ir.Typ(ir.TARRAY)
+	t.Type = ir.Types[ir.TUINT32]
+	if Debug_libfuzzer != 0 {
+		t.Type = ir.Types[ir.TUINT8]
+	}
+	t.Bound = int64(len(c.blocks))
+	addvar(c.counters, t, ir.PEXTERN)
+
+	// Renumber the blocks by file. Closures are compiled
+	// after all top-level functions, so without this the
+	// blocks of one file may be spread out.
+	files := make([]string, len(c.blocks))
+	order := make([]int, len(c.blocks))
+	for k, b := range c.blocks {
+		files[k], _ = Ctxt.LineHist.AbsFileLine(int(b.start))
+		order[k] = k
+	}
+	sort.Stable(byFile{order, files})
+	blocks := make([]coverBlock, len(c.blocks))
+	for k, old := range order {
+		blocks[k] = c.blocks[old]
+		c.incs[old].Left.Right = Nodintconst(int64(k))
+	}
+	c.blocks = blocks
+
+	for _, n := range c.incs {
+		if typecheck(n, Etop) != n {
+			Fatalf("cover: typecheck replaced %v", n)
+		}
+	}
+
+	if Debug_libfuzzer != 0 {
+		fuzzcounters = c.counters
+		return
+	}
+
+	var buf bytes.Buffer
+	for _, b := range c.blocks {
+		file, start := Ctxt.LineHist.AbsFileLine(int(b.start))
+		_, end := Ctxt.LineHist.AbsFileLine(int(b.end))
+		fmt.Fprintf(&buf, "%s:%d.1,%d.1 %d\n", file, start, end+1, b.nstmt)
+	}
+	coverblocks = Lookup("cover·blocks")
+	coverdata = buf.String()
+	covercounters = c.counters
+}
+
+// byFile sorts block numbers by the file the block is in.
+type byFile struct {
+	order []int
+	files []string
+}
+
+func (x byFile) Len() int           { return len(x.order) }
+func (x byFile) Swap(i, j int)      { x.order[i], x.order[j] = x.order[j], x.order[i] }
+func (x byFile) Less(i, j int) bool { return x.files[x.order[i]] < x.files[x.order[j]] }
+
+// coverinit returns a call registering the package's
+// counters and block table with the runtime.
+func coverinit() *ir.Node {
+	t := ir.Typ(ir.TARRAY)
+	t.Type = ir.Types[ir.TUINT8]
+	t.Bound = int64(len(coverdata))
+	blocks := newname(coverblocks)
+	blocks.Class = ir.PEXTERN
+	blocks.Type = t
+
+	n := Nod(ir.OCALL, syslook("coverRegister"), nil)
+	n.List.Set([]*ir.Node{
+		Nod(ir.OADDR, Nod(ir.OINDEX, covercounters, Nodintconst(0)), nil),
+		Nodintconst(covercounters.Type.Bound),
+		Nod(ir.OADDR, Nod(ir.OINDEX, blocks, Nodintconst(0)), nil),
+		Nodintconst(int64(len(coverdata))),
+	})
+	return n
+}
+
+// dumpcover writes the cover·blocks symbol.
+func dumpcover() {
+	if coverblocks == nil {
+		return
+	}
+	off := dsname(coverblocks, 0, coverdata)
+	ggloblsym(coverblocks, int32(off), obj.RODATA)
+}
+
+// list instruments the statements in l, starting a new block
+// at the beginning of l and after each statement that transfers
+// control elsewhere or is the target of a jump. An empty l gets
+// a block of its own at line lno.
+func (c *coverState) list(l *ir.Nodes, lno int32) {
+	var out []*ir.Node
+	need := true
+	for _, n := range l.Slice() {
+		if n.Op == ir.OLABEL {
+			// Count the block after the label, so that
+			// jumps to the label count it too.
+			out = append(out, n)
+			need = true
+			continue
+		}
+		if need {
+			out = append(out, c.inc(n.Lineno))
+			need = false
+		}
+		b := &c.blocks[len(c.blocks)-1]
+		b.end = n.Lineno
+		b.nstmt++
+		out = append(out, n)
+
+		c.stmt(n)
+		switch n.Op {
+		case ir.OIF, ir.OFOR, ir.ORANGE, ir.OSWITCH, ir.OSELECT, ir.OBLOCK,
+			ir.OGOTO, ir.OBREAK, ir.OCONTINUE, ir.ORETURN, ir.ORETJMP, ir.OFALL, ir.OXFALL, ir.OPANIC:
+			need = true
+		}
+	}
+	if len(out) == 0 {
+		out = append(out, c.inc(lno))
+	}
+	l.Set(out)
+}
+
+// stmt instruments the statement lists nested in n.
+// Closures are separate functions and are instrumented on their own.
+func (c *coverState) stmt(n *ir.Node) {
+	switch n.Op {
+	case ir.OIF:
+		c.list(&n.Nbody, n.Lineno)
+		if n.Rlist.Len() != 0 {
+			c.list(&n.Rlist, n.Lineno)
+		}
+
+	case ir.OFOR, ir.ORANGE:
+		c.list(&n.Nbody, n.Lineno)
+
+	case ir.OSWITCH, ir.OSELECT:
+		for _, cas := range n.List.Slice() {
+			c.list(&cas.Nbody, cas.Lineno)
+		}
+
+	case ir.OBLOCK:
+		c.list(&n.List, n.Lineno)
+	}
+}
+
+// inc returns the statement that counts a new block
+// starting with a statement at line lno.
+func (c *coverState) inc(lno int32) *ir.Node {
+	k := len(c.blocks)
+	c.blocks = append(c.blocks, coverBlock{start: lno, end: lno})
+
+	lno, lineno = lineno, lno
+	n := Nod(ir.OASOP, Nod(ir.OINDEX, c.counters, Nodintconst(int64(k))), Nodintconst(1))
+	n.Etype = ir.EType(ir.OADD)
+	n.Implicit = true
+	lineno = lno
+	c.incs = append(c.incs, n)
+	return n
+}
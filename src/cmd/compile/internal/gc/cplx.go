@@ -4,17 +4,18 @@
 
 package gc
 
+import "cmd/compile/internal/ir"
 import "cmd/internal/obj"
 
-func overlap_cplx(f *Node, t *Node) bool {
+func overlap_cplx(f *ir.Node, t *ir.Node) bool {
 	// check whether f and t could be overlapping stack references.
 	// not exact, because it's hard to check for the stack register
 	// in portable code.  close enough: worst case we will allocate
 	// an extra temporary and the registerizer will clean it up.
-	return f.Op == OINDREG && t.Op == OINDREG && f.Xoffset+f.Type.Width >= t.Xoffset && t.Xoffset+t.Type.Width >= f.Xoffset
+	return f.Op == ir.OINDREG && t.Op == ir.OINDREG && f.Xoffset+f.Type.Width >= t.Xoffset && t.Xoffset+t.Type.Width >= f.Xoffset
 }
 
-func complexbool(op Op, nl, nr, res *Node, wantTrue bool, likely int, to *obj.Prog) {
+func complexbool(op ir.Op, nl, nr, res *ir.Node, wantTrue bool, likely int, to *obj.Prog) {
 	// make both sides addable in ullman order
 	if nr != nil {
 		if nl.Ullman > nr.Ullman && !nl.Addable {
@@ -30,7 +31,7 @@ func complexbool(op Op, nl, nr, res *Node, wantTrue bool, likely int, to *obj.Pr
 	}
 
 	// Break nl and nr into real and imaginary components.
-	var lreal, limag, rreal, rimag Node
+	var lreal, limag, rreal, rimag ir.Node
 	subnode(&lreal, &limag, nl)
 	subnode(&rreal, &rimag, nr)
 
@@ -39,39 +40,39 @@ func complexbool(op Op, nl, nr, res *Node, wantTrue bool, likely int, to *obj.Pr
 	// 	real(l) == real(r) && imag(l) == imag(r)
 	// if generating a value, use a branch-free version:
 	// 	real(l) == real(r) & imag(l) == imag(r)
-	realeq := Node{
-		Op:    OEQ,
+	realeq := ir.Node{
+		Op:    ir.OEQ,
 		Left:  &lreal,
 		Right: &rreal,
-		Type:  Types[TBOOL],
+		Type:  ir.Types[ir.TBOOL],
 	}
-	imageq := Node{
-		Op:    OEQ,
+	imageq := ir.Node{
+		Op:    ir.OEQ,
 		Left:  &limag,
 		Right: &rimag,
-		Type:  Types[TBOOL],
+		Type:  ir.Types[ir.TBOOL],
 	}
-	and := Node{
-		Op:    OANDAND,
+	and := ir.Node{
+		Op:    ir.OANDAND,
 		Left:  &realeq,
 		Right: &imageq,
-		Type:  Types[TBOOL],
+		Type:  ir.Types[ir.TBOOL],
 	}
 
 	if res != nil {
 		// generating a value
-		and.Op = OAND
-		if op == ONE {
-			and.Op = OOR
-			realeq.Op = ONE
-			imageq.Op = ONE
+		and.Op = ir.OAND
+		if op == ir.ONE {
+			and.Op = ir.OOR
+			realeq.Op = ir.ONE
+			imageq.Op = ir.ONE
 		}
 		Bvgen(&and, res, true)
 		return
 	}
 
 	// generating a branch
-	if op == ONE {
+	if op == ir.ONE {
 		wantTrue = !wantTrue
 	}
 
@@ -79,18 +80,18 @@ func complexbool(op Op, nl, nr, res *Node, wantTrue bool, likely int, to *obj.Pr
 }
 
 // break addable nc-complex into nr-real and ni-imaginary
-func subnode(nr *Node, ni *Node, nc *Node) {
+func subnode(nr *ir.Node, ni *ir.Node, nc *ir.Node) {
 	if !nc.Addable {
 		Fatalf("subnode not addable")
 	}
 
 	tc := Simsimtype(nc.Type)
 	tc = cplxsubtype(tc)
-	t := Types[tc]
+	t := ir.Types[tc]
 
-	if nc.Op == OLITERAL {
-		nodfconst(nr, t, &nc.Val().U.(*Mpcplx).Real)
-		nodfconst(ni, t, &nc.Val().U.(*Mpcplx).Imag)
+	if nc.Op == ir.OLITERAL {
+		nodfconst(nr, t, &nc.Val().U.(*ir.Mpcplx).Real)
+		nodfconst(ni, t, &nc.Val().U.(*ir.Mpcplx).Imag)
 		return
 	}
 
@@ -103,9 +104,9 @@ func subnode(nr *Node, ni *Node, nc *Node) {
 }
 
 // generate code res = -nl
-func minus(nl *Node, res *Node) {
-	var ra Node
-	ra.Op = OMINUS
+func minus(nl *ir.Node, res *ir.Node) {
+	var ra ir.Node
+	ra.Op = ir.OMINUS
 	ra.Left = nl
 	ra.Type = nl.Type
 	Cgen(&ra, res)
@@ -114,11 +115,11 @@ func minus(nl *Node, res *Node) {
 // build and execute tree
 //	real(res) = -real(nl)
 //	imag(res) = -imag(nl)
-func complexminus(nl *Node, res *Node) {
-	var n1 Node
-	var n2 Node
-	var n5 Node
-	var n6 Node
+func complexminus(nl *ir.Node, res *ir.Node) {
+	var n1 ir.Node
+	var n2 ir.Node
+	var n5 ir.Node
+	var n6 ir.Node
 
 	subnode(&n1, &n2, nl)
 	subnode(&n5, &n6, res)
@@ -130,26 +131,26 @@ func complexminus(nl *Node, res *Node) {
 // build and execute tree
 //	real(res) = real(nl) op real(nr)
 //	imag(res) = imag(nl) op imag(nr)
-func complexadd(op Op, nl *Node, nr *Node, res *Node) {
-	var n1 Node
-	var n2 Node
-	var n3 Node
-	var n4 Node
-	var n5 Node
-	var n6 Node
+func complexadd(op ir.Op, nl *ir.Node, nr *ir.Node, res *ir.Node) {
+	var n1 ir.Node
+	var n2 ir.Node
+	var n3 ir.Node
+	var n4 ir.Node
+	var n5 ir.Node
+	var n6 ir.Node
 
 	subnode(&n1, &n2, nl)
 	subnode(&n3, &n4, nr)
 	subnode(&n5, &n6, res)
 
-	var ra Node
+	var ra ir.Node
 	ra.Op = op
 	ra.Left = &n1
 	ra.Right = &n3
 	ra.Type = n1.Type
 	Cgen(&ra, &n5)
 
-	ra = Node{}
+	ra = ir.Node{}
 	ra.Op = op
 	ra.Left = &n2
 	ra.Right = &n4
@@ -161,14 +162,14 @@ func complexadd(op Op, nl *Node, nr *Node, res *Node) {
 //	tmp       = real(nl)*real(nr) - imag(nl)*imag(nr)
 //	imag(res) = real(nl)*imag(nr) + imag(nl)*real(nr)
 //	real(res) = tmp
-func complexmul(nl *Node, nr *Node, res *Node) {
-	var n1 Node
-	var n2 Node
-	var n3 Node
-	var n4 Node
-	var n5 Node
-	var n6 Node
-	var tmp Node
+func complexmul(nl *ir.Node, nr *ir.Node, res *ir.Node) {
+	var n1 ir.Node
+	var n2 ir.Node
+	var n3 ir.Node
+	var n4 ir.Node
+	var n5 ir.Node
+	var n6 ir.Node
+	var tmp ir.Node
 
 	subnode(&n1, &n2, nl)
 	subnode(&n3, &n4, nr)
@@ -176,42 +177,42 @@ func complexmul(nl *Node, nr *Node, res *Node) {
 	Tempname(&tmp, n5.Type)
 
 	// real part -> tmp
-	var rm1 Node
+	var rm1 ir.Node
 
-	rm1.Op = OMUL
+	rm1.Op = ir.OMUL
 	rm1.Left = &n1
 	rm1.Right = &n3
 	rm1.Type = n1.Type
 
-	var rm2 Node
-	rm2.Op = OMUL
+	var rm2 ir.Node
+	rm2.Op = ir.OMUL
 	rm2.Left = &n2
 	rm2.Right = &n4
 	rm2.Type = n2.Type
 
-	var ra Node
-	ra.Op = OSUB
+	var ra ir.Node
+	ra.Op = ir.OSUB
 	ra.Left = &rm1
 	ra.Right = &rm2
 	ra.Type = rm1.Type
 	Cgen(&ra, &tmp)
 
 	// imag part
-	rm1 = Node{}
+	rm1 = ir.Node{}
 
-	rm1.Op = OMUL
+	rm1.Op = ir.OMUL
 	rm1.Left = &n1
 	rm1.Right = &n4
 	rm1.Type = n1.Type
 
-	rm2 = Node{}
-	rm2.Op = OMUL
+	rm2 = ir.Node{}
+	rm2.Op = ir.OMUL
 	rm2.Left = &n2
 	rm2.Right = &n3
 	rm2.Type = n2.Type
 
-	ra = Node{}
-	ra.Op = OADD
+	ra = ir.Node{}
+	ra.Op = ir.OADD
 	ra.Left = &rm1
 	ra.Right = &rm2
 	ra.Type = rm1.Type
@@ -221,12 +222,12 @@ func complexmul(nl *Node, nr *Node, res *Node) {
 	Cgen(&tmp, &n5)
 }
 
-func nodfconst(n *Node, t *Type, fval *Mpflt) {
-	*n = Node{}
-	n.Op = OLITERAL
+func nodfconst(n *ir.Node, t *ir.Type, fval *ir.Mpflt) {
+	*n = ir.Node{}
+	n.Op = ir.OLITERAL
 	n.Addable = true
 	ullmancalc(n)
-	n.SetVal(Val{fval})
+	n.SetVal(ir.Val{U: fval})
 	n.Type = t
 
 	if !Isfloat[t.Etype] {
@@ -234,7 +235,7 @@ func nodfconst(n *Node, t *Type, fval *Mpflt) {
 	}
 }
 
-func Complexop(n *Node, res *Node) bool {
+func Complexop(n *ir.Node, res *ir.Node) bool {
 	if n != nil && n.Type != nil {
 		if Iscomplex[n.Type.Etype] {
 			goto maybe
@@ -247,7 +248,7 @@ func Complexop(n *Node, res *Node) bool {
 		}
 	}
 
-	if n.Op == OREAL || n.Op == OIMAG {
+	if n.Op == ir.OREAL || n.Op == ir.OIMAG {
 		//dump("\ncomplex-yes", n);
 		return true
 	}
@@ -257,22 +258,22 @@ func Complexop(n *Node, res *Node) bool {
 
 maybe:
 	switch n.Op {
-	case OCONV, // implemented ops
-		OADD,
-		OSUB,
-		OMUL,
-		OMINUS,
-		OCOMPLEX,
-		OREAL,
-		OIMAG:
+	case ir.OCONV, // implemented ops
+		ir.OADD,
+		ir.OSUB,
+		ir.OMUL,
+		ir.OMINUS,
+		ir.OCOMPLEX,
+		ir.OREAL,
+		ir.OIMAG:
 		//dump("\ncomplex-yes", n);
 		return true
 
-	case ODOT,
-		ODOTPTR,
-		OINDEX,
-		OIND,
-		ONAME:
+	case ir.ODOT,
+		ir.ODOTPTR,
+		ir.OINDEX,
+		ir.OIND,
+		ir.ONAME:
 		//dump("\ncomplex-yes", n);
 		return true
 	}
@@ -281,7 +282,7 @@ maybe:
 	return false
 }
 
-func Complexmove(f *Node, t *Node) {
+func Complexmove(f *ir.Node, t *ir.Node) {
 	if Debug['g'] != 0 {
 		Dump("\ncomplexmove-f", f)
 		Dump("complexmove-t", t)
@@ -296,19 +297,19 @@ func Complexmove(f *Node, t *Node) {
 	// complex to complex move/convert.
 	// make f addable.
 	// also use temporary if possible stack overlap.
-	if (ft == TCOMPLEX64 || ft == TCOMPLEX128) && (tt == TCOMPLEX64 || tt == TCOMPLEX128) {
+	if (ft == ir.TCOMPLEX64 || ft == ir.TCOMPLEX128) && (tt == ir.TCOMPLEX64 || tt == ir.TCOMPLEX128) {
 		if !f.Addable || overlap_cplx(f, t) {
-			var tmp Node
+			var tmp ir.Node
 			Tempname(&tmp, f.Type)
 			Complexmove(f, &tmp)
 			f = &tmp
 		}
 
-		var n1 Node
-		var n2 Node
+		var n1 ir.Node
+		var n2 ir.Node
 		subnode(&n1, &n2, f)
-		var n4 Node
-		var n3 Node
+		var n4 ir.Node
+		var n3 ir.Node
 		subnode(&n3, &n4, t)
 
 		Cgen(&n1, &n3)
@@ -318,24 +319,24 @@ func Complexmove(f *Node, t *Node) {
 	}
 }
 
-func Complexgen(n *Node, res *Node) {
+func Complexgen(n *ir.Node, res *ir.Node) {
 	if Debug['g'] != 0 {
 		Dump("\ncomplexgen-n", n)
 		Dump("complexgen-res", res)
 	}
 
-	for n.Op == OCONVNOP {
+	for n.Op == ir.OCONVNOP {
 		n = n.Left
 	}
 
 	// pick off float/complex opcodes
 	switch n.Op {
-	case OCOMPLEX:
+	case ir.OCOMPLEX:
 		if res.Addable {
-			var n1 Node
-			var n2 Node
+			var n1 ir.Node
+			var n2 ir.Node
 			subnode(&n1, &n2, res)
-			var tmp Node
+			var tmp ir.Node
 			Tempname(&tmp, n1.Type)
 			Cgen(n.Left, &tmp)
 			Cgen(n.Right, &n2)
@@ -343,19 +344,19 @@ func Complexgen(n *Node, res *Node) {
 			return
 		}
 
-	case OREAL, OIMAG:
+	case ir.OREAL, ir.OIMAG:
 		nl := n.Left
 		if !nl.Addable {
-			var tmp Node
+			var tmp ir.Node
 			Tempname(&tmp, nl.Type)
 			Complexgen(nl, &tmp)
 			nl = &tmp
 		}
 
-		var n1 Node
-		var n2 Node
+		var n1 ir.Node
+		var n2 ir.Node
 		subnode(&n1, &n2, nl)
-		if n.Op == OREAL {
+		if n.Op == ir.OREAL {
 			Cgen(&n1, res)
 			return
 		}
@@ -372,7 +373,7 @@ func Complexgen(n *Node, res *Node) {
 	tr = cplxsubtype(tr)
 	if tl != tr {
 		if !n.Addable {
-			var n1 Node
+			var n1 ir.Node
 			Tempname(&n1, n.Type)
 			Complexmove(n, &n1)
 			n = &n1
@@ -383,7 +384,7 @@ func Complexgen(n *Node, res *Node) {
 	}
 
 	if !res.Addable {
-		var n1 Node
+		var n1 ir.Node
 		Igen(res, &n1, nil)
 		Cgen(n, &n1)
 		Regfree(&n1)
@@ -400,29 +401,29 @@ func Complexgen(n *Node, res *Node) {
 		Dump("complexgen: unknown op", n)
 		Fatalf("complexgen: unknown op %v", Oconv(n.Op, 0))
 
-	case ODOT,
-		ODOTPTR,
-		OINDEX,
-		OIND,
-		ONAME, // PHEAP or PPARAMREF var
-		OCALLFUNC,
-		OCALLMETH,
-		OCALLINTER:
-		var n1 Node
+	case ir.ODOT,
+		ir.ODOTPTR,
+		ir.OINDEX,
+		ir.OIND,
+		ir.ONAME, // PHEAP or PPARAMREF var
+		ir.OCALLFUNC,
+		ir.OCALLMETH,
+		ir.OCALLINTER:
+		var n1 ir.Node
 		Igen(n, &n1, res)
 
 		Complexmove(&n1, res)
 		Regfree(&n1)
 		return
 
-	case OCONV,
-		OADD,
-		OSUB,
-		OMUL,
-		OMINUS,
-		OCOMPLEX,
-		OREAL,
-		OIMAG:
+	case ir.OCONV,
+		ir.OADD,
+		ir.OSUB,
+		ir.OMUL,
+		ir.OMINUS,
+		ir.OCOMPLEX,
+		ir.OREAL,
+		ir.OIMAG:
 		break
 	}
 
@@ -433,7 +434,7 @@ func Complexgen(n *Node, res *Node) {
 	nr := n.Right
 
 	// make both sides addable in ullman order
-	var tnl Node
+	var tnl ir.Node
 	if nr != nil {
 		if nl.Ullman > nr.Ullman && !nl.Addable {
 			Tempname(&tnl, nl.Type)
@@ -442,7 +443,7 @@ func Complexgen(n *Node, res *Node) {
 		}
 
 		if !nr.Addable {
-			var tnr Node
+			var tnr ir.Node
 			Tempname(&tnr, nr.Type)
 			Cgen(nr, &tnr)
 			nr = &tnr
@@ -459,16 +460,16 @@ func Complexgen(n *Node, res *Node) {
 	default:
 		Fatalf("complexgen: unknown op %v", Oconv(n.Op, 0))
 
-	case OCONV:
+	case ir.OCONV:
 		Complexmove(nl, res)
 
-	case OMINUS:
+	case ir.OMINUS:
 		complexminus(nl, res)
 
-	case OADD, OSUB:
+	case ir.OADD, ir.OSUB:
 		complexadd(n.Op, nl, nr, res)
 
-	case OMUL:
+	case ir.OMUL:
 		complexmul(nl, nr, res)
 	}
 }
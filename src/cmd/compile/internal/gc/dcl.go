@@ -5,6 +5,7 @@
 package gc
 
 import (
+	"cmd/compile/internal/ir"
 	"cmd/internal/obj"
 	"fmt"
 	"sort"
@@ -25,7 +26,7 @@ func dflag() bool {
 }
 
 // declaration stack & operations
-func dcopy(a *Sym, b *Sym) {
+func dcopy(a *ir.Sym, b *ir.Sym) {
 	a.Pkg = b.Pkg
 	a.Name = b.Name
 	a.Def = b.Def
@@ -33,15 +34,15 @@ func dcopy(a *Sym, b *Sym) {
 	a.Lastlineno = b.Lastlineno
 }
 
-func push() *Sym {
-	d := new(Sym)
+func push() *ir.Sym {
+	d := new(ir.Sym)
 	d.Lastlineno = lineno
 	d.Link = dclstack
 	dclstack = d
 	return d
 }
 
-func pushdcl(s *Sym) *Sym {
+func pushdcl(s *ir.Sym) *ir.Sym {
 	d := push()
 	dcopy(d, s)
 	if dflag() {
@@ -67,6 +68,7 @@ func popdcl() {
 	}
 
 	dclstack = d.Link // pop mark
+	blocks[block].end = lineno
 	block = d.Block
 }
 
@@ -77,6 +79,10 @@ func markdcl() {
 
 	blockgen++
 	block = blockgen
+	for int32(len(blocks)) <= block {
+		blocks = append(blocks, blockinfo{})
+	}
+	blocks[block] = blockinfo{parent: d.Block, start: lineno}
 }
 
 func dumpdcl(st string) {
@@ -105,7 +111,7 @@ func testdclstack() {
 	}
 }
 
-func redeclare(s *Sym, where string) {
+func redeclare(s *ir.Sym, where string) {
 	if s.Lastlineno == 0 {
 		var tmp string
 		if s.Origpkg != nil {
@@ -138,8 +144,8 @@ var vargen int
 
 var declare_typegen int
 
-func declare(n *Node, ctxt Class) {
-	if ctxt == PDISCARD {
+func declare(n *ir.Node, ctxt ir.Class) {
+	if ctxt == ir.PDISCARD {
 		return
 	}
 
@@ -149,7 +155,7 @@ func declare(n *Node, ctxt Class) {
 
 	if n.Name == nil {
 		// named OLITERAL needs Name; most OLITERALs don't.
-		n.Name = new(Name)
+		n.Name = new(ir.Name)
 	}
 	n.Lineno = lineno
 	s := n.Sym
@@ -159,27 +165,27 @@ func declare(n *Node, ctxt Class) {
 		Yyerror("cannot declare name %v", s)
 	}
 
-	if ctxt == PEXTERN && s.Name == "init" {
+	if ctxt == ir.PEXTERN && s.Name == "init" {
 		Yyerror("cannot declare init - must be func")
 	}
 
 	gen := 0
-	if ctxt == PEXTERN {
+	if ctxt == ir.PEXTERN {
 		externdcl = append(externdcl, n)
 		if dflag() {
 			fmt.Printf("\t%v global decl %v %p\n", linestr(lineno), s, n)
 		}
 	} else {
-		if Curfn == nil && ctxt == PAUTO {
+		if Curfn == nil && ctxt == ir.PAUTO {
 			Fatalf("automatic outside function")
 		}
 		if Curfn != nil {
 			Curfn.Func.Dcl = append(Curfn.Func.Dcl, n)
 		}
-		if n.Op == OTYPE {
+		if n.Op == ir.OTYPE {
 			declare_typegen++
 			gen = declare_typegen
-		} else if n.Op == ONAME && ctxt == PAUTO && !strings.Contains(s.Name, "·") {
+		} else if n.Op == ir.ONAME && ctxt == ir.PAUTO && !strings.Contains(s.Name, "·") {
 			vargen++
 			gen = vargen
 		}
@@ -187,14 +193,14 @@ func declare(n *Node, ctxt Class) {
 		n.Name.Curfn = Curfn
 	}
 
-	if ctxt == PAUTO {
+	if ctxt == ir.PAUTO {
 		n.Xoffset = 0
 	}
 
 	if s.Block == block {
 		// functype will print errors about duplicate function arguments.
 		// Don't repeat the error here.
-		if ctxt != PPARAM && ctxt != PPARAMOUT {
+		if ctxt != ir.PPARAM && ctxt != ir.PPARAMOUT {
 			redeclare(s, "in this block")
 		}
 	}
@@ -202,6 +208,7 @@ func declare(n *Node, ctxt Class) {
 	s.Block = block
 	s.Lastlineno = lineno
 	s.Def = n
+	n.Name.Block = block
 	n.Name.Vargen = int32(gen)
 	n.Name.Funcdepth = Funcdepth
 	n.Class = ctxt
@@ -209,34 +216,34 @@ func declare(n *Node, ctxt Class) {
 	autoexport(n, ctxt)
 }
 
-func addvar(n *Node, t *Type, ctxt Class) {
-	if n == nil || n.Sym == nil || (n.Op != ONAME && n.Op != ONONAME) || t == nil {
+func addvar(n *ir.Node, t *ir.Type, ctxt ir.Class) {
+	if n == nil || n.Sym == nil || (n.Op != ir.ONAME && n.Op != ir.ONONAME) || t == nil {
 		Fatalf("addvar: n=%v t=%v nil", n, t)
 	}
 
-	n.Op = ONAME
+	n.Op = ir.ONAME
 	declare(n, ctxt)
 	n.Type = t
 }
 
 // declare variables from grammar
 // new_name_list (type | [type] = expr_list)
-func variter(vl []*Node, t *Node, el []*Node) []*Node {
-	var init []*Node
+func variter(vl []*ir.Node, t *ir.Node, el []*ir.Node) []*ir.Node {
+	var init []*ir.Node
 	doexpr := len(el) > 0
 
 	if len(el) == 1 && len(vl) > 1 {
 		e := el[0]
-		as2 := Nod(OAS2, nil, nil)
+		as2 := Nod(ir.OAS2, nil, nil)
 		as2.List.Set(vl)
 		as2.Rlist.Set1(e)
 		for _, v := range vl {
-			v.Op = ONAME
+			v.Op = ir.ONAME
 			declare(v, dclcontext)
 			v.Name.Param.Ntype = t
 			v.Name.Defn = as2
 			if Funcdepth > 0 {
-				init = append(init, Nod(ODCL, v, nil))
+				init = append(init, Nod(ir.ODCL, v, nil))
 			}
 		}
 
@@ -244,7 +251,7 @@ func variter(vl []*Node, t *Node, el []*Node) []*Node {
 	}
 
 	for _, v := range vl {
-		var e *Node
+		var e *ir.Node
 		if doexpr {
 			if len(el) == 0 {
 				Yyerror("missing expression in var declaration")
@@ -257,15 +264,15 @@ func variter(vl []*Node, t *Node, el []*Node) []*Node {
 			e = nil
 		}
 
-		v.Op = ONAME
+		v.Op = ir.ONAME
 		declare(v, dclcontext)
 		v.Name.Param.Ntype = t
 
 		if e != nil || Funcdepth > 0 || isblank(v) {
 			if Funcdepth > 0 {
-				init = append(init, Nod(ODCL, v, nil))
+				init = append(init, Nod(ir.ODCL, v, nil))
 			}
-			e = Nod(OAS, v, e)
+			e = Nod(ir.OAS, v, e)
 			init = append(init, e)
 			if e.Right != nil {
 				v.Name.Defn = e
@@ -281,7 +288,7 @@ func variter(vl []*Node, t *Node, el []*Node) []*Node {
 
 // declare constants from grammar
 // new_name_list [[type] = expr_list]
-func constiter(vl []*Node, t *Node, cl []*Node) []*Node {
+func constiter(vl []*ir.Node, t *ir.Node, cl []*ir.Node) []*ir.Node {
 	lno := int32(0) // default is to leave line number alone in listtreecopy
 	if len(cl) == 0 {
 		if t != nil {
@@ -296,8 +303,8 @@ func constiter(vl []*Node, t *Node, cl []*Node) []*Node {
 	}
 	clcopy := listtreecopy(cl, lno)
 
-	var c *Node
-	var vv []*Node
+	var c *ir.Node
+	var vv []*ir.Node
 	for _, v := range vl {
 		if len(clcopy) == 0 {
 			Yyerror("missing value in const declaration")
@@ -307,13 +314,14 @@ func constiter(vl []*Node, t *Node, cl []*Node) []*Node {
 		c = clcopy[0]
 		clcopy = clcopy[1:]
 
-		v.Op = OLITERAL
+		v.Op = ir.OLITERAL
 		declare(v, dclcontext)
+		v.Name.Iota = iota_
 
 		v.Name.Param.Ntype = t
 		v.Name.Defn = c
 
-		vv = append(vv, Nod(ODCLCONST, v, nil))
+		vv = append(vv, Nod(ir.ODCLCONST, v, nil))
 	}
 
 	if len(clcopy) != 0 {
@@ -323,14 +331,36 @@ func constiter(vl []*Node, t *Node, cl []*Node) []*Node {
 	return vv
 }
 
+// hasclosure reports whether the expression n contains a function literal.
+func hasclosure(n *ir.Node) bool {
+	if n == nil {
+		return false
+	}
+	if n.Op == ir.OCLOSURE || hasclosure(n.Left) || hasclosure(n.Right) {
+		return true
+	}
+	return listhasclosure(n.List.Slice())
+}
+
+// listhasclosure reports whether any of the expressions l
+// contains a function literal.
+func listhasclosure(l []*ir.Node) bool {
+	for _, n := range l {
+		if hasclosure(n) {
+			return true
+		}
+	}
+	return false
+}
+
 // this generates a new name node,
 // typically for labels or other one-off names.
-func newname(s *Sym) *Node {
+func newname(s *ir.Sym) *ir.Node {
 	if s == nil {
 		Fatalf("newname nil")
 	}
 
-	n := Nod(ONAME, nil, nil)
+	n := Nod(ir.ONAME, nil, nil)
 	n.Sym = s
 	n.Type = nil
 	n.Addable = true
@@ -341,27 +371,27 @@ func newname(s *Sym) *Node {
 
 // newfuncname generates a new name node for a function or method.
 // TODO(rsc): Use an ODCLFUNC node instead. See comment in CL 7360.
-func newfuncname(s *Sym) *Node {
+func newfuncname(s *ir.Sym) *ir.Node {
 	n := newname(s)
-	n.Func = new(Func)
+	n.Func = new(ir.Func)
 	n.Func.FCurfn = Curfn
 	return n
 }
 
 // this generates a new name node for a name
 // being declared.
-func dclname(s *Sym) *Node {
+func dclname(s *ir.Sym) *ir.Node {
 	n := newname(s)
-	n.Op = ONONAME // caller will correct it
+	n.Op = ir.ONONAME // caller will correct it
 	return n
 }
 
-func typenod(t *Type) *Node {
+func typenod(t *ir.Type) *ir.Node {
 	// if we copied another type with *t = *u
 	// then t->nod might be out of date, so
 	// check t->nod->type too
 	if t.Nod == nil || t.Nod.Type != t {
-		t.Nod = Nod(OTYPE, nil, nil)
+		t.Nod = Nod(ir.OTYPE, nil, nil)
 		t.Nod.Type = t
 		t.Nod.Sym = t.Sym
 	}
@@ -373,7 +403,7 @@ func typenod(t *Type) *Node {
 // that has already been pushed on the
 // declaration list. a diagnostic is
 // generated if no name has been defined.
-func oldname(s *Sym) *Node {
+func oldname(s *ir.Sym) *ir.Node {
 	n := s.Def
 	if n == nil {
 		// maybe a top-level name will come along
@@ -381,12 +411,12 @@ func oldname(s *Sym) *Node {
 		// walkdef will check s->def again once
 		// all the input source has been processed.
 		n = newname(s)
-		n.Op = ONONAME
-		n.Name.Iota = iota_ // save current iota value in const declarations
+		n.Op = ir.ONONAME
+		n.Name.Iota = iota_ // note whether iota is in a const declaration
 		return n
 	}
 
-	if Curfn != nil && n.Op == ONAME && n.Name.Funcdepth > 0 && n.Name.Funcdepth != Funcdepth {
+	if Curfn != nil && n.Op == ir.ONAME && n.Name.Funcdepth > 0 && n.Name.Funcdepth != Funcdepth {
 		// inner func is referring to var in outer func.
 		//
 		// TODO(rsc): If there is an outer variable x and we
@@ -395,10 +425,10 @@ func oldname(s *Sym) *Node {
 		// make x a closure variable unnecessarily.
 		if n.Name.Param.Closure == nil || n.Name.Param.Closure.Name.Funcdepth != Funcdepth {
 			// create new closure var.
-			c := Nod(ONAME, nil, nil)
+			c := Nod(ir.ONAME, nil, nil)
 
 			c.Sym = s
-			c.Class = PPARAMREF
+			c.Class = ir.PPARAMREF
 			c.Isddd = n.Isddd
 			c.Name.Defn = n
 			c.Addable = false
@@ -419,29 +449,29 @@ func oldname(s *Sym) *Node {
 }
 
 // := declarations
-func colasname(n *Node) bool {
+func colasname(n *ir.Node) bool {
 	switch n.Op {
-	case ONAME,
-		ONONAME,
-		OPACK,
-		OTYPE,
-		OLITERAL:
+	case ir.ONAME,
+		ir.ONONAME,
+		ir.OPACK,
+		ir.OTYPE,
+		ir.OLITERAL:
 		return n.Sym != nil
 	}
 
 	return false
 }
 
-func colasdefn(left Nodes, defn *Node) {
+func colasdefn(left ir.Nodes, defn *ir.Node) {
 	for _, n1 := range left.Slice() {
 		if n1.Sym != nil {
-			n1.Sym.Flags |= SymUniq
+			n1.Sym.Flags |= ir.SymUniq
 		}
 	}
 
 	nnew := 0
 	nerr := 0
-	var n *Node
+	var n *ir.Node
 	for i2, n2 := range left.Slice() {
 		n = n2
 		if isblank(n) {
@@ -453,14 +483,14 @@ func colasdefn(left Nodes, defn *Node) {
 			continue
 		}
 
-		if n.Sym.Flags&SymUniq == 0 {
+		if n.Sym.Flags&ir.SymUniq == 0 {
 			yyerrorl(defn.Lineno, "%v repeated on left side of :=", n.Sym)
 			n.Diag++
 			nerr++
 			continue
 		}
 
-		n.Sym.Flags &^= SymUniq
+		n.Sym.Flags &^= ir.SymUniq
 		if n.Sym.Block == block {
 			continue
 		}
@@ -469,7 +499,7 @@ func colasdefn(left Nodes, defn *Node) {
 		n = newname(n.Sym)
 		declare(n, dclcontext)
 		n.Name.Defn = defn
-		defn.Ninit.Append(Nod(ODCL, n, nil))
+		defn.Ninit.Append(Nod(ir.ODCL, n, nil))
 		left.SetIndex(i2, n)
 	}
 
@@ -478,8 +508,8 @@ func colasdefn(left Nodes, defn *Node) {
 	}
 }
 
-func colas(left []*Node, right []*Node, lno int32) *Node {
-	as := Nod(OAS2, nil, nil)
+func colas(left []*ir.Node, right []*ir.Node, lno int32) *ir.Node {
+	as := Nod(ir.OAS2, nil, nil)
 	as.List.Set(left)
 	as.Rlist.Set(right)
 	as.Colas = true
@@ -492,7 +522,7 @@ func colas(left []*Node, right []*Node, lno int32) *Node {
 		as.Right = as.Rlist.First()
 		as.List.Set(nil)
 		as.Rlist.Set(nil)
-		as.Op = OAS
+		as.Op = ir.OAS
 	}
 
 	return as
@@ -500,8 +530,8 @@ func colas(left []*Node, right []*Node, lno int32) *Node {
 
 // declare the arguments in an
 // interface field declaration.
-func ifacedcl(n *Node) {
-	if n.Op != ODCLFIELD || n.Right == nil {
+func ifacedcl(n *ir.Node) {
+	if n.Op != ir.ODCLFIELD || n.Right == nil {
 		Fatalf("ifacedcl")
 	}
 
@@ -509,9 +539,9 @@ func ifacedcl(n *Node) {
 		Yyerror("methods must have a unique non-blank name")
 	}
 
-	n.Func = new(Func)
+	n.Func = new(ir.Func)
 	n.Func.FCurfn = Curfn
-	dclcontext = PPARAM
+	dclcontext = ir.PPARAM
 	markdcl()
 	Funcdepth++
 	n.Func.Outer = Curfn
@@ -522,7 +552,7 @@ func ifacedcl(n *Node) {
 	// seen the body of a function but since an interface
 	// field declaration does not have a body, we must
 	// call it now to pop the current declaration context.
-	dclcontext = PAUTO
+	dclcontext = ir.PAUTO
 
 	funcbody(n)
 }
@@ -531,9 +561,9 @@ func ifacedcl(n *Node) {
 // and declare the arguments.
 // called in extern-declaration context
 // returns in auto-declaration context.
-func funchdr(n *Node) {
+func funchdr(n *ir.Node) {
 	// change the declaration context from extern to auto
-	if Funcdepth == 0 && dclcontext != PEXTERN {
+	if Funcdepth == 0 && dclcontext != ir.PEXTERN {
 		Fatalf("funchdr: dclcontext")
 	}
 
@@ -541,9 +571,10 @@ func funchdr(n *Node) {
 		makefuncsym(n.Func.Nname.Sym)
 	}
 
-	dclcontext = PAUTO
+	dclcontext = ir.PAUTO
 	markdcl()
 	Funcdepth++
+	n.Func.Block = block
 
 	n.Func.Outer = Curfn
 	Curfn = n
@@ -557,8 +588,8 @@ func funchdr(n *Node) {
 	}
 }
 
-func funcargs(nt *Node) {
-	if nt.Op != OTFUNC {
+func funcargs(nt *ir.Node) {
+	if nt.Op != ir.OTFUNC {
 		Fatalf("funcargs %v", Oconv(nt.Op, 0))
 	}
 
@@ -572,30 +603,30 @@ func funcargs(nt *Node) {
 	// will not fill in the types until later
 	if nt.Left != nil {
 		n := nt.Left
-		if n.Op != ODCLFIELD {
+		if n.Op != ir.ODCLFIELD {
 			Fatalf("funcargs receiver %v", Oconv(n.Op, 0))
 		}
 		if n.Left != nil {
-			n.Left.Op = ONAME
+			n.Left.Op = ir.ONAME
 			n.Left.Name.Param.Ntype = n.Right
-			declare(n.Left, PPARAM)
-			if dclcontext == PAUTO {
+			declare(n.Left, ir.PPARAM)
+			if dclcontext == ir.PAUTO {
 				vargen++
 				n.Left.Name.Vargen = int32(vargen)
 			}
 		}
 	}
 
-	var n *Node
+	var n *ir.Node
 	for _, n = range nt.List.Slice() {
-		if n.Op != ODCLFIELD {
+		if n.Op != ir.ODCLFIELD {
 			Fatalf("funcargs in %v", Oconv(n.Op, 0))
 		}
 		if n.Left != nil {
-			n.Left.Op = ONAME
+			n.Left.Op = ir.ONAME
 			n.Left.Name.Param.Ntype = n.Right
-			declare(n.Left, PPARAM)
-			if dclcontext == PAUTO {
+			declare(n.Left, ir.PPARAM)
+			if dclcontext == ir.PAUTO {
 				vargen++
 				n.Left.Name.Vargen = int32(vargen)
 			}
@@ -606,7 +637,7 @@ func funcargs(nt *Node) {
 	gen := nt.List.Len()
 	var i int = 0
 	for _, n = range nt.Rlist.Slice() {
-		if n.Op != ODCLFIELD {
+		if n.Op != ir.ODCLFIELD {
 			Fatalf("funcargs out %v", Oconv(n.Op, 0))
 		}
 
@@ -617,7 +648,7 @@ func funcargs(nt *Node) {
 		}
 
 		// TODO: n->left->missing = 1;
-		n.Left.Op = ONAME
+		n.Left.Op = ir.ONAME
 
 		if isblank(n.Left) {
 			// Give it a name so we can assign to it during return. ~b stands for 'blank'.
@@ -636,8 +667,8 @@ func funcargs(nt *Node) {
 		}
 
 		n.Left.Name.Param.Ntype = n.Right
-		declare(n.Left, PPARAMOUT)
-		if dclcontext == PAUTO {
+		declare(n.Left, ir.PPARAMOUT)
+		if dclcontext == ir.PAUTO {
 			i++
 			n.Left.Name.Vargen = int32(i)
 		}
@@ -647,8 +678,8 @@ func funcargs(nt *Node) {
 // Same as funcargs, except run over an already constructed TFUNC.
 // This happens during import, where the hidden_fndcl rule has
 // used functype directly to parse the function's type.
-func funcargs2(t *Type) {
-	if t.Etype != TFUNC {
+func funcargs2(t *ir.Type) {
+	if t.Etype != ir.TFUNC {
 		Fatalf("funcargs2 %v", t)
 	}
 
@@ -658,7 +689,7 @@ func funcargs2(t *Type) {
 		}
 		n := ft.Nname // no need for newname(ft->nname->sym)
 		n.Type = ft.Type
-		declare(n, PPARAM)
+		declare(n, ir.PPARAM)
 	}
 
 	for _, ft := range t.Params().Fields().Slice() {
@@ -667,7 +698,7 @@ func funcargs2(t *Type) {
 		}
 		n := ft.Nname
 		n.Type = ft.Type
-		declare(n, PPARAM)
+		declare(n, ir.PPARAM)
 	}
 
 	for _, ft := range t.Results().Fields().Slice() {
@@ -676,16 +707,16 @@ func funcargs2(t *Type) {
 		}
 		n := ft.Nname
 		n.Type = ft.Type
-		declare(n, PPARAMOUT)
+		declare(n, ir.PPARAMOUT)
 	}
 }
 
 // finish the body.
 // called in auto-declaration context.
 // returns in extern-declaration context.
-func funcbody(n *Node) {
+func funcbody(n *ir.Node) {
 	// change the declaration context from auto to extern
-	if dclcontext != PAUTO {
+	if dclcontext != ir.PAUTO {
 		Fatalf("funcbody: dclcontext")
 	}
 	popdcl()
@@ -693,14 +724,14 @@ func funcbody(n *Node) {
 	Curfn = n.Func.Outer
 	n.Func.Outer = nil
 	if Funcdepth == 0 {
-		dclcontext = PEXTERN
+		dclcontext = ir.PEXTERN
 	}
 }
 
 // new type being defined with name s.
-func typedcl0(s *Sym) *Node {
+func typedcl0(s *ir.Sym) *ir.Node {
 	n := newname(s)
-	n.Op = OTYPE
+	n.Op = ir.OTYPE
 	declare(n, dclcontext)
 	return n
 }
@@ -708,42 +739,42 @@ func typedcl0(s *Sym) *Node {
 // node n, which was returned by typedcl0
 // is being declared to have uncompiled type t.
 // return the ODCLTYPE node to use.
-func typedcl1(n *Node, t *Node, local bool) *Node {
+func typedcl1(n *ir.Node, t *ir.Node, local bool) *ir.Node {
 	n.Name.Param.Ntype = t
 	n.Local = local
-	return Nod(ODCLTYPE, n, nil)
+	return Nod(ir.ODCLTYPE, n, nil)
 }
 
 // structs, functions, and methods.
 // they don't belong here, but where do they belong?
-func checkembeddedtype(t *Type) {
+func checkembeddedtype(t *ir.Type) {
 	if t == nil {
 		return
 	}
 
 	if t.Sym == nil && Isptr[t.Etype] {
 		t = t.Type
-		if t.Etype == TINTER {
+		if t.Etype == ir.TINTER {
 			Yyerror("embedded type cannot be a pointer to interface")
 		}
 	}
 
 	if Isptr[t.Etype] {
 		Yyerror("embedded type cannot be a pointer")
-	} else if t.Etype == TFORW && t.Embedlineno == 0 {
+	} else if t.Etype == ir.TFORW && t.Embedlineno == 0 {
 		t.Embedlineno = lineno
 	}
 }
 
-func structfield(n *Node) *Field {
+func structfield(n *ir.Node) *ir.Field {
 	lno := lineno
 	lineno = n.Lineno
 
-	if n.Op != ODCLFIELD {
+	if n.Op != ir.ODCLFIELD {
 		Fatalf("structfield: oops %v\n", n)
 	}
 
-	f := newField()
+	f := ir.NewField()
 	f.Isddd = n.Isddd
 
 	if n.Right != nil {
@@ -765,7 +796,7 @@ func structfield(n *Node) *Field {
 	}
 
 	switch n.Val().Ctype() {
-	case CTSTR:
+	case ir.CTSTR:
 		f.Note = new(string)
 		*f.Note = n.Val().U.(string)
 
@@ -773,11 +804,11 @@ func structfield(n *Node) *Field {
 		Yyerror("field annotation must be string")
 		fallthrough
 
-	case CTxxx:
+	case ir.CTxxx:
 		f.Note = nil
 	}
 
-	if n.Left != nil && n.Left.Op == ONAME {
+	if n.Left != nil && n.Left.Op == ir.ONAME {
 		f.Nname = n.Left
 		f.Embedded = n.Embedded
 		f.Sym = f.Nname.Sym
@@ -789,10 +820,10 @@ func structfield(n *Node) *Field {
 
 // checkdupfields emits errors for duplicately named fields or methods in
 // a list of struct or interface types.
-func checkdupfields(what string, ts ...*Type) {
+func checkdupfields(what string, ts ...*ir.Type) {
 	lno := lineno
 
-	seen := make(map[*Sym]bool)
+	seen := make(map[*ir.Sym]bool)
 	for _, t := range ts {
 		for _, f := range t.Fields().Slice() {
 			if f.Sym == nil || f.Nname == nil || isblank(f.Nname) {
@@ -812,24 +843,24 @@ func checkdupfields(what string, ts ...*Type) {
 
 // convert a parsed id/type list into
 // a type for struct/interface/arglist
-func tostruct(l []*Node) *Type {
-	t := typ(TSTRUCT)
+func tostruct(l []*ir.Node) *ir.Type {
+	t := ir.Typ(ir.TSTRUCT)
 	tostruct0(t, l)
 	return t
 }
 
-func tostruct0(t *Type, l []*Node) {
-	if t == nil || t.Etype != TSTRUCT {
+func tostruct0(t *ir.Type, l []*ir.Node) {
+	if t == nil || t.Etype != ir.TSTRUCT {
 		Fatalf("struct expected")
 	}
 
-	var fields []*Field
+	var fields []*ir.Field
 	for _, n := range l {
 		fields = append(fields, structfield(n))
 	}
 	t.SetFields(fields)
 
-	for f, it := IterFields(t); f != nil && !t.Broke; f = it.Next() {
+	for f, it := ir.IterFields(t); f != nil && !t.Broke; f = it.Next() {
 		if f.Broke {
 			t.Broke = true
 		}
@@ -842,17 +873,17 @@ func tostruct0(t *Type, l []*Node) {
 	}
 }
 
-func tofunargs(l []*Node) *Type {
-	t := typ(TSTRUCT)
+func tofunargs(l []*ir.Node) *ir.Type {
+	t := ir.Typ(ir.TSTRUCT)
 	t.Funarg = true
 
-	var fields []*Field
+	var fields []*ir.Field
 	for _, n := range l {
 		f := structfield(n)
 		f.Funarg = true
 
 		// esc.go needs to find f given a PPARAM to add the tag.
-		if n.Left != nil && n.Left.Class == PPARAM {
+		if n.Left != nil && n.Left.Class == ir.PPARAM {
 			n.Left.Name.Param.Field = f
 		}
 
@@ -860,7 +891,7 @@ func tofunargs(l []*Node) *Type {
 	}
 	t.SetFields(fields)
 
-	for f, it := IterFields(t); f != nil && !t.Broke; f = it.Next() {
+	for f, it := ir.IterFields(t); f != nil && !t.Broke; f = it.Next() {
 		if f.Broke {
 			t.Broke = true
 		}
@@ -869,19 +900,38 @@ func tofunargs(l []*Node) *Type {
 	return t
 }
 
-func interfacefield(n *Node) *Field {
+// tofunargsfield is like tofunargs, but for fields rather than the
+// ODCLFIELDs of a declaration.
+func tofunargsfield(fields []*ir.Field) *ir.Type {
+	t := ir.Typ(ir.TSTRUCT)
+	t.Funarg = true
+
+	for _, f := range fields {
+		f.Funarg = true
+		if f.Type == nil {
+			f.Broke = true
+		}
+		if f.Broke {
+			t.Broke = true
+		}
+	}
+	t.SetFields(fields)
+	return t
+}
+
+func interfacefield(n *ir.Node) *ir.Field {
 	lno := lineno
 	lineno = n.Lineno
 
-	if n.Op != ODCLFIELD {
+	if n.Op != ir.ODCLFIELD {
 		Fatalf("interfacefield: oops %v\n", n)
 	}
 
-	if n.Val().Ctype() != CTxxx {
+	if n.Val().Ctype() != ir.CTxxx {
 		Yyerror("interface method cannot have annotation")
 	}
 
-	f := newField()
+	f := ir.NewField()
 	f.Isddd = n.Isddd
 
 	if n.Right != nil {
@@ -889,13 +939,13 @@ func interfacefield(n *Node) *Field {
 			// queue resolution of method type for later.
 			// right now all we need is the name list.
 			// avoids cycles for recursive interface types.
-			n.Type = typ(TINTERMETH)
+			n.Type = ir.Typ(ir.TINTERMETH)
 
 			n.Type.Nname = n.Right
 			n.Left.Type = n.Type
 			queuemethod(n)
 
-			if n.Left.Op == ONAME {
+			if n.Left.Op == ir.ONAME {
 				f.Nname = n.Left
 				f.Embedded = n.Embedded
 				f.Sym = f.Nname.Sym
@@ -910,10 +960,10 @@ func interfacefield(n *Node) *Field {
 
 			if n.Type != nil {
 				switch n.Type.Etype {
-				case TINTER:
+				case ir.TINTER:
 					break
 
-				case TFORW:
+				case ir.TFORW:
 					Yyerror("interface type loop involving %v", n.Type)
 					f.Broke = true
 
@@ -936,25 +986,25 @@ func interfacefield(n *Node) *Field {
 	return f
 }
 
-func tointerface(l []*Node) *Type {
-	t := typ(TINTER)
+func tointerface(l []*ir.Node) *ir.Type {
+	t := ir.Typ(ir.TINTER)
 	tointerface0(t, l)
 	return t
 }
 
-func tointerface0(t *Type, l []*Node) *Type {
-	if t == nil || t.Etype != TINTER {
+func tointerface0(t *ir.Type, l []*ir.Node) *ir.Type {
+	if t == nil || t.Etype != ir.TINTER {
 		Fatalf("interface expected")
 	}
 
-	var fields []*Field
+	var fields []*ir.Field
 	for _, n := range l {
 		f := interfacefield(n)
 
-		if n.Left == nil && f.Type.Etype == TINTER {
+		if n.Left == nil && f.Type.Etype == ir.TINTER {
 			// embedded interface, inline methods
 			for _, t1 := range f.Type.Fields().Slice() {
-				f = newField()
+				f = ir.NewField()
 				f.Type = t1.Type
 				f.Broke = t1.Broke
 				f.Sym = t1.Sym
@@ -970,7 +1020,7 @@ func tointerface0(t *Type, l []*Node) *Type {
 	sort.Sort(methcmp(fields))
 	t.SetFields(fields)
 
-	for f, it := IterFields(t); f != nil && !t.Broke; f = it.Next() {
+	for f, it := ir.IterFields(t); f != nil && !t.Broke; f = it.Next() {
 		if f.Broke {
 			t.Broke = true
 		}
@@ -982,7 +1032,7 @@ func tointerface0(t *Type, l []*Node) *Type {
 	return t
 }
 
-func embedded(s *Sym, pkg *Pkg) *Node {
+func embedded(s *ir.Sym, pkg *ir.Pkg) *ir.Node {
 	const (
 		CenterDot = 0xB7
 	)
@@ -995,7 +1045,7 @@ func embedded(s *Sym, pkg *Pkg) *Node {
 		name = s.Name[:i]
 	}
 
-	var n *Node
+	var n *ir.Node
 	if exportname(name) {
 		n = newname(Lookup(name))
 	} else if s.Pkg == builtinpkg {
@@ -1004,20 +1054,27 @@ func embedded(s *Sym, pkg *Pkg) *Node {
 	} else {
 		n = newname(Pkglookup(name, s.Pkg))
 	}
-	n = Nod(ODCLFIELD, n, oldname(s))
+	n = Nod(ir.ODCLFIELD, n, oldname(s))
 	n.Embedded = 1
 	return n
 }
 
-func fakethis() *Node {
-	n := Nod(ODCLFIELD, nil, typenod(Ptrto(typ(TSTRUCT))))
+func fakethis() *ir.Node {
+	n := Nod(ir.ODCLFIELD, nil, typenod(Ptrto(ir.Typ(ir.TSTRUCT))))
 	return n
 }
 
+// fakethisfield is like fakethis, for functypefield.
+func fakethisfield() *ir.Field {
+	f := ir.NewField()
+	f.Type = Ptrto(ir.Typ(ir.TSTRUCT))
+	return f
+}
+
 // Is this field a method on an interface?
 // Those methods have an anonymous *struct{} as the receiver.
 // (See fakethis above.)
-func isifacemethod(f *Type) bool {
+func isifacemethod(f *ir.Type) bool {
 	rcvr := f.Recv()
 	if rcvr.Sym != nil {
 		return false
@@ -1027,27 +1084,27 @@ func isifacemethod(f *Type) bool {
 		return false
 	}
 	t = t.Type
-	if t.Sym != nil || t.Etype != TSTRUCT || t.NumFields() != 0 {
+	if t.Sym != nil || t.Etype != ir.TSTRUCT || t.NumFields() != 0 {
 		return false
 	}
 	return true
 }
 
 // turn a parsed function declaration into a type
-func functype(this *Node, in, out []*Node) *Type {
-	t := typ(TFUNC)
+func functype(this *ir.Node, in, out []*ir.Node) *ir.Type {
+	t := ir.Typ(ir.TFUNC)
 	functype0(t, this, in, out)
 	return t
 }
 
-func functype0(t *Type, this *Node, in, out []*Node) {
-	if t == nil || t.Etype != TFUNC {
+func functype0(t *ir.Type, this *ir.Node, in, out []*ir.Node) {
+	if t == nil || t.Etype != ir.TFUNC {
 		Fatalf("function type expected")
 	}
 
-	var rcvr []*Node
+	var rcvr []*ir.Node
 	if this != nil {
-		rcvr = []*Node{this}
+		rcvr = []*ir.Node{this}
 	}
 	*t.RecvsP() = tofunargs(rcvr)
 	*t.ResultsP() = tofunargs(out)
@@ -1068,13 +1125,40 @@ func functype0(t *Type, this *Node, in, out []*Node) {
 	}
 }
 
-var methodsym_toppkg *Pkg
+// functypefield is like functype, but for fields rather than the
+// ODCLFIELDs of a declaration. The fields have no Nname.
+func functypefield(this *ir.Field, in, out []*ir.Field) *ir.Type {
+	t := ir.Typ(ir.TFUNC)
+	functypefield0(t, this, in, out)
+	return t
+}
+
+func functypefield0(t *ir.Type, this *ir.Field, in, out []*ir.Field) {
+	var rcvr []*ir.Field
+	if this != nil {
+		rcvr = []*ir.Field{this}
+	}
+	*t.RecvsP() = tofunargsfield(rcvr)
+	*t.ResultsP() = tofunargsfield(out)
+	*t.ParamsP() = tofunargsfield(in)
+
+	if t.Recvs().Broke || t.Results().Broke || t.Params().Broke {
+		t.Broke = true
+	}
+
+	t.Outnamed = false
+	if len(out) > 0 && out[0].Sym != nil && (out[0].Sym.Name[0] != '~' || out[0].Sym.Name[1] != 'r') {
+		t.Outnamed = true
+	}
+}
+
+var methodsym_toppkg *ir.Pkg
 
-func methodsym(nsym *Sym, t0 *Type, iface int) *Sym {
-	var s *Sym
+func methodsym(nsym *ir.Sym, t0 *ir.Type, iface int) *ir.Sym {
+	var s *ir.Sym
 	var p string
 	var suffix string
-	var spkg *Pkg
+	var spkg *ir.Pkg
 
 	t := t0
 	if t == nil {
@@ -1103,22 +1187,22 @@ func methodsym(nsym *Sym, t0 *Type, iface int) *Sym {
 	suffix = ""
 	if iface != 0 {
 		dowidth(t0)
-		if t0.Width < Types[Tptr].Width {
+		if t0.Width < ir.Types[Tptr].Width {
 			suffix = "·i"
 		}
 	}
 
 	if (spkg == nil || nsym.Pkg != spkg) && !exportname(nsym.Name) {
 		if t0.Sym == nil && Isptr[t0.Etype] {
-			p = fmt.Sprintf("(%v).%s.%s%s", Tconv(t0, FmtLeft|FmtShort), nsym.Pkg.Prefix, nsym.Name, suffix)
+			p = fmt.Sprintf("(%v).%s.%s%s", Tconv(t0, ir.FmtLeft|ir.FmtShort), nsym.Pkg.Prefix, nsym.Name, suffix)
 		} else {
-			p = fmt.Sprintf("%v.%s.%s%s", Tconv(t0, FmtLeft|FmtShort), nsym.Pkg.Prefix, nsym.Name, suffix)
+			p = fmt.Sprintf("%v.%s.%s%s", Tconv(t0, ir.FmtLeft|ir.FmtShort), nsym.Pkg.Prefix, nsym.Name, suffix)
 		}
 	} else {
 		if t0.Sym == nil && Isptr[t0.Etype] {
-			p = fmt.Sprintf("(%v).%s%s", Tconv(t0, FmtLeft|FmtShort), nsym.Name, suffix)
+			p = fmt.Sprintf("(%v).%s%s", Tconv(t0, ir.FmtLeft|ir.FmtShort), nsym.Name, suffix)
 		} else {
-			p = fmt.Sprintf("%v.%s%s", Tconv(t0, FmtLeft|FmtShort), nsym.Name, suffix)
+			p = fmt.Sprintf("%v.%s%s", Tconv(t0, ir.FmtLeft|ir.FmtShort), nsym.Name, suffix)
 		}
 	}
 
@@ -1138,7 +1222,7 @@ bad:
 	return nil
 }
 
-func methodname(n *Node, t *Type) *Node {
+func methodname(n *ir.Node, t *ir.Type) *ir.Node {
 	s := methodsym(n.Sym, t, 0)
 	if s == nil {
 		return n
@@ -1146,9 +1230,9 @@ func methodname(n *Node, t *Type) *Node {
 	return newname(s)
 }
 
-func methodname1(n *Node, t *Node) *Node {
+func methodname1(n *ir.Node, t *ir.Node) *ir.Node {
 	star := ""
-	if t.Op == OIND {
+	if t.Op == ir.OIND {
 		star = "*"
 		t = t.Left
 	}
@@ -1177,7 +1261,7 @@ func methodname1(n *Node, t *Node) *Node {
 // - msym is the method symbol
 // - t is function type (with receiver)
 // - tpkg is the package of the type declaring the method during import, or nil (ignored) --- for verification only
-func addmethod(msym *Sym, t *Type, tpkg *Pkg, local, nointerface bool) {
+func addmethod(msym *ir.Sym, t *ir.Type, tpkg *ir.Pkg, local, nointerface bool) {
 	// get field sym
 	if msym == nil {
 		Fatalf("no method symbol")
@@ -1220,7 +1304,7 @@ func addmethod(msym *Sym, t *Type, tpkg *Pkg, local, nointerface bool) {
 				return
 			}
 
-			if t.Etype == TINTER {
+			if t.Etype == ir.TINTER {
 				Yyerror("invalid receiver type %v (%v is an interface type)", pa, t)
 				return
 			}
@@ -1228,7 +1312,7 @@ func addmethod(msym *Sym, t *Type, tpkg *Pkg, local, nointerface bool) {
 
 		// Should have picked off all the reasons above,
 		// but just in case, fall back to generic error.
-		Yyerror("invalid receiver type %v (%v / %v)", pa, Tconv(pa, FmtLong), Tconv(t, FmtLong))
+		Yyerror("invalid receiver type %v (%v / %v)", pa, Tconv(pa, ir.FmtLong), Tconv(t, ir.FmtLong))
 
 		return
 	}
@@ -1243,7 +1327,7 @@ func addmethod(msym *Sym, t *Type, tpkg *Pkg, local, nointerface bool) {
 		return
 	}
 
-	if pa.Etype == TSTRUCT {
+	if pa.Etype == ir.TSTRUCT {
 		for _, f := range pa.Fields().Slice() {
 			if f.Sym == msym {
 				Yyerror("type %v has both field and method named %v", pa, msym)
@@ -1252,7 +1336,7 @@ func addmethod(msym *Sym, t *Type, tpkg *Pkg, local, nointerface bool) {
 		}
 	}
 
-	n := Nod(ODCLFIELD, newname(msym), nil)
+	n := Nod(ir.ODCLFIELD, newname(msym), nil)
 	n.Type = t
 
 	for _, f := range pa.Methods().Slice() {
@@ -1272,21 +1356,33 @@ func addmethod(msym *Sym, t *Type, tpkg *Pkg, local, nointerface bool) {
 
 	// during import unexported method names should be in the type's package
 	if tpkg != nil && f.Sym != nil && !exportname(f.Sym.Name) && f.Sym.Pkg != tpkg {
-		Fatalf("imported method name %v in wrong package %s\n", Sconv(f.Sym, FmtSign), tpkg.Name)
+		Fatalf("imported method name %v in wrong package %s\n", Sconv(f.Sym, ir.FmtSign), tpkg.Name)
 	}
 
 	pa.Methods().Append(f)
 }
 
-func funccompile(n *Node) {
-	Stksize = BADWIDTH
+func funccompile(n *ir.Node) {
+	if !funcenter(n) {
+		return
+	}
+	compile(n)
+	funcexit()
+	fncachestore(n)
+	funcdone(n)
+}
+
+// funcenter sets up the global state for compiling n.
+// It reports whether n can be compiled.
+func funcenter(n *ir.Node) bool {
+	Stksize = ir.BADWIDTH
 	Maxarg = 0
 
 	if n.Type == nil {
 		if nerrors == 0 {
 			Fatalf("funccompile missing type")
 		}
-		return
+		return false
 	}
 
 	// assign parameter offsets
@@ -1297,24 +1393,69 @@ func funccompile(n *Node) {
 	}
 
 	Stksize = 0
-	dclcontext = PAUTO
+	dclcontext = ir.PAUTO
 	Funcdepth = n.Func.Depth + 1
-	compile(n)
+	return true
+}
+
+// funcexit resets the global state after compiling a function
+// and assembles the generated code.
+func funcexit() {
+	fn := Curfn
+	if fn != nil && nerrors == 0 {
+		// Report assembler errors, such as a branch too far for its
+		// instruction encoding, at the function being assembled.
+		defer func(lno int32) {
+			lineno = lno
+		}(setlineno(fn))
+	}
 	Curfn = nil
 	Pc = nil
 	continpc = nil
 	breakpc = nil
 	Funcdepth = 0
-	dclcontext = PEXTERN
+	dclcontext = ir.PEXTERN
 	if nerrors != 0 {
 		// If we have compile errors, ignore any assembler/linker errors.
 		Ctxt.DiagFunc = func(string, ...interface{}) {}
 	}
+	t := timestart()
 	flushdata()
 	obj.Flushplist(Ctxt) // convert from Prog list to machine code
+	if fn != nil {
+		timeend("assemble", fn, t)
+	}
+}
+
+// funcdone releases what fn no longer needs once its code has been
+// generated: its walked body and its declarations, including the
+// temporaries, whose symbols would otherwise keep them and fn alive.
+// The copy of the body for inlining stays with fn.Func.Nname, since
+// the exporter writes it and method wrappers generated later inline it.
+func funcdone(fn *ir.Node) {
+	if compiling_runtime != 0 {
+		// checknowritebarrierrec walks the bodies after compilation.
+		return
+	}
+	if fn.Nbody.Len() == 0 {
+		return
+	}
+	for _, n := range fn.Func.Dcl {
+		if n.Sym != nil && n.Sym.Def == n {
+			n.Sym.Def = nil
+		}
+	}
+	fn.Nbody.Set(nil)
+	fn.Func.Enter.Set(nil)
+	fn.Func.Exit.Set(nil)
+	fn.Func.Dcl = nil
+	fn.Func.FieldTrack = nil
+	fn.Func.ReflectMethods = nil
+	fn.Func.WBReasons = nil
+	fn.Func.Released = true
 }
 
-func funcsym(s *Sym) *Sym {
+func funcsym(s *ir.Sym) *ir.Sym {
 	if s.Fsym != nil {
 		return s.Fsym
 	}
@@ -1324,7 +1465,7 @@ func funcsym(s *Sym) *Sym {
 	return s1
 }
 
-func makefuncsym(s *Sym) {
+func makefuncsym(s *ir.Sym) {
 	if isblanksym(s) {
 		return
 	}
@@ -1340,26 +1481,26 @@ func makefuncsym(s *Sym) {
 }
 
 type nowritebarrierrecChecker struct {
-	curfn  *Node
+	curfn  *ir.Node
 	stable bool
 
 	// best maps from the ODCLFUNC of each visited function that
 	// recursively invokes a write barrier to the called function
 	// on the shortest path to a write barrier.
-	best map[*Node]nowritebarrierrecCall
+	best map[*ir.Node]nowritebarrierrecCall
 }
 
 type nowritebarrierrecCall struct {
-	target *Node
+	target *ir.Node
 	depth  int
 	lineno int32
 }
 
 func checknowritebarrierrec() {
 	c := nowritebarrierrecChecker{
-		best: make(map[*Node]nowritebarrierrecCall),
+		best: make(map[*ir.Node]nowritebarrierrecCall),
 	}
-	visitBottomUp(xtop, func(list []*Node, recursive bool) {
+	visitBottomUp(xtop, func(list []*ir.Node, recursive bool) {
 		// Functions with write barriers have depth 0.
 		for _, n := range list {
 			if n.Func.WBLineno != 0 {
@@ -1386,7 +1527,7 @@ func checknowritebarrierrec() {
 
 		// Check nowritebarrierrec functions.
 		for _, n := range list {
-			if n.Func.Pragma&Nowritebarrierrec == 0 {
+			if n.Func.Pragma&ir.Nowritebarrierrec == 0 {
 				continue
 			}
 			call, hasWB := c.best[n]
@@ -1407,18 +1548,18 @@ func checknowritebarrierrec() {
 	})
 }
 
-func (c *nowritebarrierrecChecker) visitcodelist(l Nodes) {
+func (c *nowritebarrierrecChecker) visitcodelist(l ir.Nodes) {
 	for _, n := range l.Slice() {
 		c.visitcode(n)
 	}
 }
 
-func (c *nowritebarrierrecChecker) visitcode(n *Node) {
+func (c *nowritebarrierrecChecker) visitcode(n *ir.Node) {
 	if n == nil {
 		return
 	}
 
-	if n.Op == OCALLFUNC || n.Op == OCALLMETH {
+	if n.Op == ir.OCALLFUNC || n.Op == ir.OCALLMETH {
 		c.visitcall(n)
 	}
 
@@ -1430,12 +1571,12 @@ func (c *nowritebarrierrecChecker) visitcode(n *Node) {
 	c.visitcodelist(n.Rlist)
 }
 
-func (c *nowritebarrierrecChecker) visitcall(n *Node) {
+func (c *nowritebarrierrecChecker) visitcall(n *ir.Node) {
 	fn := n.Left
-	if n.Op == OCALLMETH {
+	if n.Op == ir.OCALLMETH {
 		fn = n.Left.Sym.Def
 	}
-	if fn == nil || fn.Op != ONAME || fn.Class != PFUNC || fn.Name.Defn == nil {
+	if fn == nil || fn.Op != ir.ONAME || fn.Class != ir.PFUNC || fn.Name.Defn == nil {
 		return
 	}
 	if (compiling_runtime != 0 || fn.Sym.Pkg == Runtimepkg) && fn.Sym.Name == "allocm" {
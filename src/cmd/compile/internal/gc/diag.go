@@ -0,0 +1,126 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gc
+
+import (
+	"encoding/json"
+	"go/scanner"
+	gotoken "go/token"
+	"io/ioutil"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Machine-readable diagnostics.
+//
+// With -json, errors and warnings are printed one per line as JSON
+// objects instead of as "file:line: message" text:
+//
+//	{"File":"/abs/x.go","Line":3,"Message":"imported and not used: \"os\"","Remove":{"Start":19,"End":24}}
+//
+// File and Line give the position of the diagnostic. Remove, if
+// present, is a byte range of File whose removal fixes the problem.
+// Only unused imports have one: it covers the import spec, or its
+// whole line, including the newline, if nothing else is on the line.
+
+var flag_json int
+
+// A jsonError is the JSON form of an Error.
+type jsonError struct {
+	File    string
+	Line    int
+	Message string
+	Remove  *byteRange `json:",omitempty"`
+}
+
+// A byteRange is the range [Start, End) of byte offsets in a file.
+type byteRange struct {
+	Start, End int
+}
+
+// printjson prints err as a JSON object.
+func printjson(err Error) {
+	file, line := Ctxt.LineHist.AbsFileLine(int(err.lineno))
+	b, _ := json.Marshal(jsonError{file, line, err.text, err.remove})
+	os.Stdout.Write(append(b, '\n'))
+}
+
+// importSource describes the import spec for path at lineno,
+// for the "imported and not used" error. It returns the byte
+// range to remove in the -json output, and whether the source
+// file mentions name.X only in comments.
+// It returns nil and false if it cannot read the file.
+func importSource(lineno int32, path, name string) (r *byteRange, inComments bool) {
+	file, line := Ctxt.LineHist.AbsFileLine(int(lineno))
+	src, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, false
+	}
+
+	var mention *regexp.Regexp
+	if name != "" && name != "." {
+		mention = regexp.MustCompile(`\b` + regexp.QuoteMeta(name) + `\.\w`)
+	}
+
+	fset := gotoken.NewFileSet()
+	f := fset.AddFile(file, -1, len(src))
+	var s scanner.Scanner
+	s.Init(f, src, nil, scanner.ScanComments)
+	var prev gotoken.Token
+	start := -1 // offset of the package name before the path, if any
+	for {
+		pos, tok, lit := s.Scan()
+		if tok == gotoken.EOF {
+			break
+		}
+		off := f.Offset(pos)
+		switch {
+		case tok == gotoken.COMMENT:
+			if mention != nil && mention.MatchString(lit) {
+				inComments = true
+			}
+			continue
+		case tok == gotoken.IDENT || tok == gotoken.PERIOD:
+			if prev == gotoken.IMPORT || prev == gotoken.LPAREN || prev == gotoken.SEMICOLON {
+				start = off
+			}
+		case tok == gotoken.STRING && r == nil && f.Line(pos) == line:
+			if p, err := strconv.Unquote(lit); err == nil && p == path {
+				if start < 0 || prev != gotoken.IDENT && prev != gotoken.PERIOD {
+					start = off
+				}
+				r = specRange(src, start, off+len(lit))
+			}
+			start = -1
+		default:
+			start = -1
+		}
+		prev = tok
+	}
+	return r, inComments
+}
+
+// specRange returns the range to remove for the import spec
+// src[start:end]: the whole line if the spec, possibly with
+// the import keyword and a trailing comment, is all there is
+// on it, and otherwise the spec and the semicolon after it.
+func specRange(src []byte, start, end int) *byteRange {
+	bol := strings.LastIndex(string(src[:start]), "\n") + 1
+	eol := len(src)
+	if i := strings.Index(string(src[end:]), "\n"); i >= 0 {
+		eol = end + i + 1
+	}
+	if rest := strings.TrimLeft(string(src[end:eol]), " \t"); strings.HasPrefix(rest, ";") {
+		end = eol - len(strings.TrimLeft(rest[1:], " \t"))
+	}
+	before := strings.TrimSpace(string(src[bol:start]))
+	after := strings.TrimSpace(string(src[end:eol]))
+	if (before == "" || before == "import") && (after == "" || strings.HasPrefix(after, "//")) {
+		return &byteRange{bol, eol}
+	}
+	return &byteRange{start, end}
+}
@@ -0,0 +1,124 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gc
+
+import (
+	"bufio"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+const unusedImportSrc = `package p
+
+import (
+	"fmt"
+	x "os"; "strings"
+)
+
+import "bytes" // not used either
+
+// For example:
+//	fmt.Println(strings.ToUpper("hi"))
+func F() string { return strings.ToUpper("hi") }
+`
+
+// Make sure -json reports unused imports with byte ranges
+// whose removal leaves a package that compiles.
+func TestUnusedImportJSON(t *testing.T) {
+	d := newTestDir(t, "TestUnusedImportJSON")
+	defer d.remove()
+
+	src := d.write("p.go", unusedImportSrc)
+	out, err := d.try("go", "tool", "compile", "-json", "-o", d.path("p.o"), src)
+	if err == nil {
+		t.Fatalf("compile succeeded; want unused imports")
+	}
+
+	type diag struct {
+		File    string
+		Line    int
+		Message string
+		Remove  *struct{ Start, End int }
+	}
+	var diags []diag
+	s := bufio.NewScanner(strings.NewReader(out))
+	for s.Scan() {
+		var g diag
+		if err := json.Unmarshal(s.Bytes(), &g); err != nil {
+			t.Fatalf("%v in line %q", err, s.Text())
+		}
+		diags = append(diags, g)
+	}
+	want := []struct {
+		line   int
+		msg    string
+		remove string
+	}{
+		{4, `imported and not used: "fmt" (used only in comments; to import it for its side effects only, use import _ "fmt")`, "\t\"fmt\"\n"},
+		{5, `imported and not used: "os" as x`, `x "os"; `},
+		{8, `imported and not used: "bytes"`, "import \"bytes\" // not used either\n"},
+	}
+	if len(diags) != len(want) {
+		t.Fatalf("got %d diagnostics, want %d:\n%s", len(diags), len(want), out)
+	}
+	fixed := unusedImportSrc
+	for i := len(want) - 1; i >= 0; i-- {
+		g, w := diags[i], want[i]
+		if g.File != src || g.Line != w.line || g.Message != w.msg {
+			t.Errorf("got %s:%d: %s\nwant %s:%d: %s", g.File, g.Line, g.Message, src, w.line, w.msg)
+		}
+		if g.Remove == nil {
+			t.Errorf("line %d: no range to remove", w.line)
+			continue
+		}
+		if got := unusedImportSrc[g.Remove.Start:g.Remove.End]; got != w.remove {
+			t.Errorf("line %d: range to remove is %q, want %q", w.line, got, w.remove)
+			continue
+		}
+		fixed = fixed[:g.Remove.Start] + fixed[g.Remove.End:]
+	}
+
+	d.write("p.go", fixed)
+	if out, err := d.try("go", "tool", "compile", "-json", "-o", d.path("p.o"), src); err != nil {
+		t.Errorf("compiling with the imports removed: %v\n%s\n%s", err, out, fixed)
+	}
+}
+
+// Make sure -json reports the range to remove for the unused import
+// that takes the compiler over the error limit.
+func TestUnusedImportJSONTooMany(t *testing.T) {
+	d := newTestDir(t, "TestUnusedImportJSONTooMany")
+	defer d.remove()
+
+	imports := []string{"bufio", "bytes", "errors", "fmt", "io", "os", "sort", "strconv", "strings", "unicode"}
+	src := d.write("p.go", "package p\n\nimport (\n\t\""+strings.Join(imports, "\"\n\t\"")+"\"\n)\n")
+	out, err := d.try("go", "tool", "compile", "-json", "-o", d.path("p.o"), src)
+	if err == nil {
+		t.Fatalf("compile succeeded; want unused imports")
+	}
+
+	var n int
+	s := bufio.NewScanner(strings.NewReader(out))
+	for s.Scan() {
+		var g struct {
+			Message string
+			Remove  *struct{ Start, End int }
+		}
+		if err := json.Unmarshal(s.Bytes(), &g); err != nil {
+			t.Fatalf("%v in line %q", err, s.Text())
+		}
+		if g.Message == "too many errors" {
+			continue
+		}
+		n++
+		if g.Remove == nil {
+			t.Errorf("no range to remove for %s", g.Message)
+		}
+	}
+	if n != len(imports) {
+		t.Errorf("got %d unused imports, want %d:\n%s", n, len(imports), out)
+	}
+}
@@ -0,0 +1,47 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gc
+
+import (
+	"internal/testenv"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// Make sure the -d duff* flags move the thresholds at which amd64 uses
+// duffzero and duffcopy, in both back ends.
+func TestDuffFlags(t *testing.T) {
+	testenv.MustHaveGoBuild(t)
+	if runtime.GOARCH != "amd64" {
+		t.Skipf("skipping on %s", runtime.GOARCH)
+	}
+
+	src := `package p
+
+func zero(p *[10]uint64) { *p = [10]uint64{} }
+
+func copy(d, s *[16]uint64) { *d = *s }
+`
+	for _, ssa := range []string{"-ssa=1", "-ssa=0"} {
+		for _, tt := range []struct {
+			flags      []string
+			zero, copy bool
+		}{
+			{nil, true, true},
+			{[]string{"-d", "duffzeromin=11"}, false, true},
+			{[]string{"-d", "duffcopymin=17"}, true, false},
+			{[]string{"-d", "duffzeromin=1,duffzeromax=4,duffcopymin=1,duffcopymax=4"}, false, false},
+		} {
+			out := compileS(t, src, append([]string{ssa}, tt.flags...)...)
+			if got := strings.Contains(funcText(out, "zero"), "DUFFZERO"); got != tt.zero {
+				t.Errorf("%s %v: DUFFZERO in zero is %v, want %v", ssa, tt.flags, got, tt.zero)
+			}
+			if got := strings.Contains(funcText(out, "copy"), "DUFFCOPY"); got != tt.copy {
+				t.Errorf("%s %v: DUFFCOPY in copy is %v, want %v", ssa, tt.flags, got, tt.copy)
+			}
+		}
+	}
+}
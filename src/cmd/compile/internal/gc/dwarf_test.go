@@ -0,0 +1,99 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gc
+
+import (
+	"debug/dwarf"
+	"debug/elf"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// mustHaveELF skips t on systems whose binaries are not ELF files.
+func mustHaveELF(t *testing.T) {
+	if runtime.GOOS == "darwin" || runtime.GOOS == "windows" || runtime.GOOS == "plan9" {
+		t.Skipf("skipping on %s: test reads ELF", runtime.GOOS)
+	}
+}
+
+// readDWARF opens the binary exe and reads its DWARF data.
+// The caller must close the returned file.
+func readDWARF(t *testing.T, exe string) (*elf.File, *dwarf.Data) {
+	f, err := elf.Open(exe)
+	if err != nil {
+		t.Fatalf("could not open target: %v", err)
+	}
+	d, err := f.DWARF()
+	if err != nil {
+		f.Close()
+		t.Fatalf("could not read DWARF: %v", err)
+	}
+	return f, d
+}
+
+// Make sure -dwarf=0 leaves functions out of DWARF and
+// -dwarf=1 describes them without their local variables.
+func TestDwarfLevels(t *testing.T) {
+	mustHaveELF(t)
+	d := newTestDir(t, "TestDwarfLevels")
+	defer d.remove()
+
+	src := `
+package main
+
+//go:noinline
+func g(p *int) { *p++ }
+
+func f() int {
+	x := 1
+	g(&x)
+	return x
+}
+
+func main() { f() }
+`
+	tests := []struct {
+		level, want string
+	}{
+		{"0", ""},
+		{"1", "main.f"},
+		{"2", "main.f x"},
+	}
+	for _, tt := range tests {
+		exe := d.build("test", src, "-dwarf="+tt.level)
+		f, dw := readDWARF(t, exe)
+
+		// List main.f and its variables.
+		var names []string
+		infunc := false
+		r := dw.Reader()
+		for {
+			e, err := r.Next()
+			if err != nil {
+				t.Fatalf("reading DWARF: %v", err)
+			}
+			if e == nil {
+				break
+			}
+			name, _ := e.Val(dwarf.AttrName).(string)
+			switch e.Tag {
+			case dwarf.TagSubprogram:
+				infunc = name == "main.f"
+				if infunc {
+					names = append(names, name)
+				}
+			case dwarf.TagVariable:
+				if infunc {
+					names = append(names, name)
+				}
+			}
+		}
+		f.Close()
+		if got := strings.Join(names, " "); got != tt.want {
+			t.Errorf("-dwarf=%s: got %q, want %q", tt.level, got, tt.want)
+		}
+	}
+}
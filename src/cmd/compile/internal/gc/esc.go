@@ -5,6 +5,7 @@
 package gc
 
 import (
+	"cmd/compile/internal/ir"
 	"fmt"
 	"strconv"
 	"strings"
@@ -34,10 +35,10 @@ import (
 // when analyzing a set of mutually recursive functions.
 
 type bottomUpVisitor struct {
-	analyze  func([]*Node, bool)
+	analyze  func([]*ir.Node, bool)
 	visitgen uint32
-	nodeID   map[*Node]uint32
-	stack    []*Node
+	nodeID   map[*ir.Node]uint32
+	stack    []*ir.Node
 }
 
 // visitBottomUp invokes analyze on the ODCLFUNC nodes listed in list.
@@ -53,18 +54,18 @@ type bottomUpVisitor struct {
 // If recursive is false, the list consists of only a single function and its closures.
 // If recursive is true, the list may still contain only a single function,
 // if that function is itself recursive.
-func visitBottomUp(list []*Node, analyze func(list []*Node, recursive bool)) {
+func visitBottomUp(list []*ir.Node, analyze func(list []*ir.Node, recursive bool)) {
 	var v bottomUpVisitor
 	v.analyze = analyze
-	v.nodeID = make(map[*Node]uint32)
+	v.nodeID = make(map[*ir.Node]uint32)
 	for _, n := range list {
-		if n.Op == ODCLFUNC && n.Func.FCurfn == nil {
+		if n.Op == ir.ODCLFUNC && n.Func.FCurfn == nil {
 			v.visit(n)
 		}
 	}
 }
 
-func (v *bottomUpVisitor) visit(n *Node) uint32 {
+func (v *bottomUpVisitor) visit(n *ir.Node) uint32 {
 	if id := v.nodeID[n]; id > 0 {
 		// already visited
 		return id
@@ -109,14 +110,14 @@ func (v *bottomUpVisitor) visit(n *Node) uint32 {
 	return min
 }
 
-func (v *bottomUpVisitor) visitcodelist(l Nodes, min uint32) uint32 {
+func (v *bottomUpVisitor) visitcodelist(l ir.Nodes, min uint32) uint32 {
 	for _, n := range l.Slice() {
 		min = v.visitcode(n, min)
 	}
 	return min
 }
 
-func (v *bottomUpVisitor) visitcode(n *Node, min uint32) uint32 {
+func (v *bottomUpVisitor) visitcode(n *ir.Node, min uint32) uint32 {
 	if n == nil {
 		return min
 	}
@@ -128,12 +129,12 @@ func (v *bottomUpVisitor) visitcode(n *Node, min uint32) uint32 {
 	min = v.visitcodelist(n.Nbody, min)
 	min = v.visitcodelist(n.Rlist, min)
 
-	if n.Op == OCALLFUNC || n.Op == OCALLMETH {
+	if n.Op == ir.OCALLFUNC || n.Op == ir.OCALLMETH {
 		fn := n.Left
-		if n.Op == OCALLMETH {
+		if n.Op == ir.OCALLMETH {
 			fn = n.Left.Sym.Def
 		}
-		if fn != nil && fn.Op == ONAME && fn.Class == PFUNC && fn.Name.Defn != nil {
+		if fn != nil && fn.Op == ir.ONAME && fn.Class == ir.PFUNC && fn.Name.Defn != nil {
 			m := v.visit(fn.Name.Defn)
 			if m < min {
 				min = m
@@ -141,7 +142,7 @@ func (v *bottomUpVisitor) visitcode(n *Node, min uint32) uint32 {
 		}
 	}
 
-	if n.Op == OCLOSURE {
+	if n.Op == ir.OCLOSURE {
 		m := v.visit(n.Func.Closure)
 		if m < min {
 			min = m
@@ -182,7 +183,7 @@ func (v *bottomUpVisitor) visitcode(n *Node, min uint32) uint32 {
 // needs to be moved to the heap, and new(T) and slice
 // literals are always real allocations.
 
-func escapes(all []*Node) {
+func escapes(all []*ir.Node) {
 	visitBottomUp(all, escAnalyze)
 }
 
@@ -301,23 +302,23 @@ func (l Level) guaranteedDereference() int {
 // that is heap allocated to the (alleged) reason for the
 // heap allocation.
 type EscStep struct {
-	src, dst *Node    // the endpoints of this edge in the escape-to-heap chain.
+	src, dst *ir.Node // the endpoints of this edge in the escape-to-heap chain.
 	parent   *EscStep // used in flood to record path
 	why      string   // explanation for this step in the escape-to-heap chain
 	busy     bool     // used in prevent to snip cycles.
 }
 
 type NodeEscState struct {
-	Curfn             *Node
+	Curfn             *ir.Node
 	Escflowsrc        []EscStep // flow(this, src)
-	Escretval         Nodes     // on OCALLxxx, list of dummy return values
+	Escretval         ir.Nodes  // on OCALLxxx, list of dummy return values
 	Escloopdepth      int32     // -1: global, 0: return variables, 1:function top level, increased inside function for every loop or label to mark scopes
 	Esclevel          Level
 	Walkgen           uint32
 	Maxextraloopdepth int32
 }
 
-func (e *EscState) nodeEscState(n *Node) *NodeEscState {
+func (e *EscState) nodeEscState(n *ir.Node) *NodeEscState {
 	if nE, ok := n.Opt().(*NodeEscState); ok {
 		return nE
 	}
@@ -331,7 +332,7 @@ func (e *EscState) nodeEscState(n *Node) *NodeEscState {
 	return nE
 }
 
-func (e *EscState) track(n *Node) {
+func (e *EscState) track(n *ir.Node) {
 	if Curfn == nil {
 		Fatalf("EscState.track: Curfn nil")
 	}
@@ -396,20 +397,20 @@ type EscState struct {
 	//   - parameters on imported functions not marked 'safe'
 	//   - assignments to global variables
 	// flow to.
-	theSink Node
-
-	dsts      []*Node // all dst nodes
-	loopdepth int32   // for detecting nested loop scopes
-	pdepth    int     // for debug printing in recursions.
-	dstcount  int     // diagnostic
-	edgecount int     // diagnostic
-	noesc     []*Node // list of possible non-escaping nodes, for printing
-	recursive bool    // recursive function or group of mutually recursive functions.
-	opts      []*Node // nodes with .Opt initialized
+	theSink ir.Node
+
+	dsts      []*ir.Node // all dst nodes
+	loopdepth int32      // for detecting nested loop scopes
+	pdepth    int        // for debug printing in recursions.
+	dstcount  int        // diagnostic
+	edgecount int        // diagnostic
+	noesc     []*ir.Node // list of possible non-escaping nodes, for printing
+	recursive bool       // recursive function or group of mutually recursive functions.
+	opts      []*ir.Node // nodes with .Opt initialized
 	walkgen   uint32
 }
 
-func (e *EscState) stepWalk(dst, src *Node, why string, parent *EscStep) *EscStep {
+func (e *EscState) stepWalk(dst, src *ir.Node, why string, parent *EscStep) *EscStep {
 	// TODO: keep a cache of these, mark entry/exit in escwalk to avoid allocation
 	// Or perhaps never mind, since it is disabled unless printing is on.
 	// We may want to revisit this, since the EscStep nodes would make
@@ -421,7 +422,7 @@ func (e *EscState) stepWalk(dst, src *Node, why string, parent *EscStep) *EscSte
 	return &EscStep{src: src, dst: dst, why: why, parent: parent}
 }
 
-func (e *EscState) stepAssign(step *EscStep, dst, src *Node, why string) *EscStep {
+func (e *EscState) stepAssign(step *EscStep, dst, src *ir.Node, why string) *EscStep {
 	if Debug['m'] == 0 {
 		return nil
 	}
@@ -432,7 +433,7 @@ func (e *EscState) stepAssign(step *EscStep, dst, src *Node, why string) *EscSte
 }
 
 // funcSym returns fn.Func.Nname.Sym if no nils are encountered along the way.
-func funcSym(fn *Node) *Sym {
+func funcSym(fn *ir.Node) *ir.Sym {
 	if fn == nil || fn.Func.Nname == nil {
 		return nil
 	}
@@ -440,30 +441,30 @@ func funcSym(fn *Node) *Sym {
 }
 
 // curfnSym returns n.Curfn.Nname.Sym if no nils are encountered along the way.
-func (e *EscState) curfnSym(n *Node) *Sym {
+func (e *EscState) curfnSym(n *ir.Node) *ir.Sym {
 	nE := e.nodeEscState(n)
 	return funcSym(nE.Curfn)
 }
 
-func escAnalyze(all []*Node, recursive bool) {
+func escAnalyze(all []*ir.Node, recursive bool) {
 	var es EscState
 	e := &es
-	e.theSink.Op = ONAME
+	e.theSink.Op = ir.ONAME
 	e.theSink.Orig = &e.theSink
-	e.theSink.Class = PEXTERN
+	e.theSink.Class = ir.PEXTERN
 	e.theSink.Sym = Lookup(".sink")
 	e.nodeEscState(&e.theSink).Escloopdepth = -1
 	e.recursive = recursive
 
 	for _, n := range all {
-		if n.Op == ODCLFUNC {
+		if n.Op == ir.ODCLFUNC {
 			n.Esc = EscFuncPlanned
 		}
 	}
 
 	// flow-analyze functions
 	for _, n := range all {
-		if n.Op == ODCLFUNC {
+		if n.Op == ir.ODCLFUNC {
 			escfunc(e, n)
 		}
 	}
@@ -478,7 +479,7 @@ func escAnalyze(all []*Node, recursive bool) {
 
 	// for all top level functions, tag the typenodes corresponding to the param nodes
 	for _, n := range all {
-		if n.Op == ODCLFUNC {
+		if n.Op == ir.ODCLFUNC {
 			esctag(e, n)
 		}
 	}
@@ -486,7 +487,7 @@ func escAnalyze(all []*Node, recursive bool) {
 	if Debug['m'] != 0 {
 		for _, n := range e.noesc {
 			if n.Esc == EscNone {
-				Warnl(n.Lineno, "%v %v does not escape", e.curfnSym(n), Nconv(n, FmtShort))
+				Warnl(n.Lineno, "%v %v does not escape", e.curfnSym(n), Nconv(n, ir.FmtShort))
 			}
 		}
 	}
@@ -495,7 +496,7 @@ func escAnalyze(all []*Node, recursive bool) {
 	}
 }
 
-func escfunc(e *EscState, func_ *Node) {
+func escfunc(e *EscState, func_ *ir.Node) {
 	//	print("escfunc %N %s\n", func->nname, e->recursive?"(recursive)":"");
 	if func_.Esc != 1 {
 		Fatalf("repeat escfunc %v", func_.Func.Nname)
@@ -508,16 +509,16 @@ func escfunc(e *EscState, func_ *Node) {
 	Curfn = func_
 
 	for _, ln := range Curfn.Func.Dcl {
-		if ln.Op != ONAME {
+		if ln.Op != ir.ONAME {
 			continue
 		}
 		llNE := e.nodeEscState(ln)
 		switch ln.Class {
 		// out params are in a loopdepth between the sink and all local variables
-		case PPARAMOUT:
+		case ir.PPARAMOUT:
 			llNE.Escloopdepth = 0
 
-		case PPARAM:
+		case ir.PPARAM:
 			llNE.Escloopdepth = 1
 			if ln.Type != nil && !haspointers(ln.Type) {
 				break
@@ -534,7 +535,7 @@ func escfunc(e *EscState, func_ *Node) {
 	// in a mutually recursive group we lose track of the return values
 	if e.recursive {
 		for _, ln := range Curfn.Func.Dcl {
-			if ln.Op == ONAME && ln.Class == PPARAMOUT {
+			if ln.Op == ir.ONAME && ln.Class == ir.PPARAMOUT {
 				escflows(e, &e.theSink, ln, e.stepAssign(nil, ln, ln, "returned from recursive function"))
 			}
 		}
@@ -549,17 +550,17 @@ func escfunc(e *EscState, func_ *Node) {
 // Mark labels that have no backjumps to them as not increasing e->loopdepth.
 // Walk hasn't generated (goto|label)->left->sym->label yet, so we'll cheat
 // and set it to one of the following two. Then in esc we'll clear it again.
-var looping Label
+var looping ir.Label
 
-var nonlooping Label
+var nonlooping ir.Label
 
-func escloopdepthlist(e *EscState, l Nodes) {
+func escloopdepthlist(e *EscState, l ir.Nodes) {
 	for _, n := range l.Slice() {
 		escloopdepth(e, n)
 	}
 }
 
-func escloopdepth(e *EscState, n *Node) {
+func escloopdepth(e *EscState, n *ir.Node) {
 	if n == nil {
 		return
 	}
@@ -567,9 +568,9 @@ func escloopdepth(e *EscState, n *Node) {
 	escloopdepthlist(e, n.Ninit)
 
 	switch n.Op {
-	case OLABEL:
+	case ir.OLABEL:
 		if n.Left == nil || n.Left.Sym == nil {
-			Fatalf("esc:label without label: %v", Nconv(n, FmtSign))
+			Fatalf("esc:label without label: %v", Nconv(n, ir.FmtSign))
 		}
 
 		// Walk will complain about this label being already defined, but that's not until
@@ -578,9 +579,9 @@ func escloopdepth(e *EscState, n *Node) {
 		//	fatal("escape analysis messed up analyzing label: %+N", n);
 		n.Left.Sym.Label = &nonlooping
 
-	case OGOTO:
+	case ir.OGOTO:
 		if n.Left == nil || n.Left.Sym == nil {
-			Fatalf("esc:goto without label: %v", Nconv(n, FmtSign))
+			Fatalf("esc:goto without label: %v", Nconv(n, ir.FmtSign))
 		}
 
 		// If we come past one that's uninitialized, this must be a (harmless) forward jump
@@ -597,13 +598,13 @@ func escloopdepth(e *EscState, n *Node) {
 	escloopdepthlist(e, n.Rlist)
 }
 
-func esclist(e *EscState, l Nodes, up *Node) {
+func esclist(e *EscState, l ir.Nodes, up *ir.Node) {
 	for _, n := range l.Slice() {
 		esc(e, n, up)
 	}
 }
 
-func esc(e *EscState, n *Node, up *Node) {
+func esc(e *EscState, n *ir.Node, up *ir.Node) {
 	if n == nil {
 		return
 	}
@@ -619,7 +620,7 @@ func esc(e *EscState, n *Node, up *Node) {
 	// ninit logically runs at a different loopdepth than the rest of the for loop.
 	esclist(e, n.Ninit, n)
 
-	if n.Op == OFOR || n.Op == ORANGE {
+	if n.Op == ir.OFOR || n.Op == ir.ORANGE {
 		e.loopdepth++
 	}
 
@@ -627,7 +628,7 @@ func esc(e *EscState, n *Node, up *Node) {
 	// process type switch as declaration.
 	// must happen before processing of switch body,
 	// so before recursion.
-	if n.Op == OSWITCH && n.Left != nil && n.Left.Op == OTYPESW {
+	if n.Op == ir.OSWITCH && n.Left != nil && n.Left.Op == ir.OTYPESW {
 		for _, n1 := range n.List.Slice() { // cases
 			// it.N().Rlist is the variable per case
 			if n1.Rlist.Len() != 0 {
@@ -640,8 +641,8 @@ func esc(e *EscState, n *Node, up *Node) {
 	// "Big" conditions that were scattered around in walk have been gathered here
 	if n.Esc != EscHeap && n.Type != nil &&
 		(n.Type.Width > MaxStackVarSize ||
-			n.Op == ONEW && n.Type.Type.Width >= 1<<16 ||
-			n.Op == OMAKESLICE && !isSmallMakeSlice(n)) {
+			n.Op == ir.ONEW && n.Type.Type.Width >= 1<<16 ||
+			n.Op == ir.OMAKESLICE && !isSmallMakeSlice(n)) {
 		if Debug['m'] > 2 {
 			Warnl(n.Lineno, "%v is too large for stack", n)
 		}
@@ -656,7 +657,7 @@ func esc(e *EscState, n *Node, up *Node) {
 	esclist(e, n.List, n)
 	esclist(e, n.Rlist, n)
 
-	if n.Op == OFOR || n.Op == ORANGE {
+	if n.Op == ir.OFOR || n.Op == ir.ORANGE {
 		e.loopdepth--
 	}
 
@@ -666,12 +667,12 @@ func esc(e *EscState, n *Node, up *Node) {
 
 	switch n.Op {
 	// Record loop depth at declaration.
-	case ODCL:
+	case ir.ODCL:
 		if n.Left != nil {
 			e.nodeEscState(n.Left).Escloopdepth = e.loopdepth
 		}
 
-	case OLABEL:
+	case ir.OLABEL:
 		if n.Left.Sym.Label == &nonlooping {
 			if Debug['m'] > 2 {
 				fmt.Printf("%v:%v non-looping label\n", linestr(lineno), n)
@@ -689,7 +690,7 @@ func esc(e *EscState, n *Node, up *Node) {
 
 		n.Left.Sym.Label = nil
 
-	case ORANGE:
+	case ir.ORANGE:
 		if n.List.Len() >= 2 {
 			// Everything but fixed array is a dereference.
 
@@ -704,8 +705,8 @@ func esc(e *EscState, n *Node, up *Node) {
 			}
 		}
 
-	case OSWITCH:
-		if n.Left != nil && n.Left.Op == OTYPESW {
+	case ir.OSWITCH:
+		if n.Left != nil && n.Left.Op == ir.OTYPESW {
 			for _, n2 := range n.List.Slice() {
 				// cases
 				// n.Left.Right is the argument of the .(type),
@@ -726,10 +727,10 @@ func esc(e *EscState, n *Node, up *Node) {
 	// This assignment is a no-op for escape analysis,
 	// it does not store any new pointers into b that were not already there.
 	// However, without this special case b will escape, because we assign to OIND/ODOTPTR.
-	case OAS, OASOP, OASWB:
-		if (n.Left.Op == OIND || n.Left.Op == ODOTPTR) && n.Left.Left.Op == ONAME && // dst is ONAME dereference
-			(n.Right.Op == OSLICE || n.Right.Op == OSLICE3 || n.Right.Op == OSLICESTR) && // src is slice operation
-			(n.Right.Left.Op == OIND || n.Right.Left.Op == ODOTPTR) && n.Right.Left.Left.Op == ONAME && // slice is applied to ONAME dereference
+	case ir.OAS, ir.OASOP, ir.OASWB:
+		if (n.Left.Op == ir.OIND || n.Left.Op == ir.ODOTPTR) && n.Left.Left.Op == ir.ONAME && // dst is ONAME dereference
+			(n.Right.Op == ir.OSLICE || n.Right.Op == ir.OSLICE3 || n.Right.Op == ir.OSLICESTR) && // src is slice operation
+			(n.Right.Left.Op == ir.OIND || n.Right.Left.Op == ir.ODOTPTR) && n.Right.Left.Left.Op == ir.ONAME && // slice is applied to ONAME dereference
 			n.Left.Left == n.Right.Left.Left { // dst and src reference the same base ONAME
 
 			// Here we also assume that the statement will not contain calls,
@@ -743,7 +744,7 @@ func esc(e *EscState, n *Node, up *Node) {
 			// b escapes as well. If we ignore such OSLICEARR, we will conclude
 			// that b does not escape when b contents do.
 			if Debug['m'] != 0 {
-				Warnl(n.Lineno, "%v ignoring self-assignment to %v", e.curfnSym(n), Nconv(n.Left, FmtShort))
+				Warnl(n.Lineno, "%v ignoring self-assignment to %v", e.curfnSym(n), Nconv(n.Left, ir.FmtShort))
 			}
 
 			break
@@ -751,7 +752,7 @@ func esc(e *EscState, n *Node, up *Node) {
 
 		escassign(e, n.Left, n.Right, nil)
 
-	case OAS2: // x,y = a,b
+	case ir.OAS2: // x,y = a,b
 		if n.List.Len() == n.Rlist.Len() {
 			rs := n.Rlist.Slice()
 			for i, n := range n.List.Slice() {
@@ -759,17 +760,17 @@ func esc(e *EscState, n *Node, up *Node) {
 			}
 		}
 
-	case OAS2RECV: // v, ok = <-ch
+	case ir.OAS2RECV: // v, ok = <-ch
 		escassignNilWhy(e, n.List.First(), n.Rlist.First(), "assign-pair-receive")
-	case OAS2MAPR: // v, ok = m[k]
+	case ir.OAS2MAPR: // v, ok = m[k]
 		escassignNilWhy(e, n.List.First(), n.Rlist.First(), "assign-pair-mapr")
-	case OAS2DOTTYPE: // v, ok = x.(type)
+	case ir.OAS2DOTTYPE: // v, ok = x.(type)
 		escassignNilWhy(e, n.List.First(), n.Rlist.First(), "assign-pair-dot-type")
 
-	case OSEND: // ch <- x
+	case ir.OSEND: // ch <- x
 		escassignSinkNilWhy(e, n, n.Right, "send")
 
-	case ODEFER:
+	case ir.ODEFER:
 		if e.loopdepth == 1 { // top level
 			break
 		}
@@ -783,7 +784,7 @@ func esc(e *EscState, n *Node, up *Node) {
 			escassignSinkNilWhy(e, n, n4, "defer func arg")
 		}
 
-	case OPROC:
+	case ir.OPROC:
 		// go f(x) - f and x escape
 		escassignSinkNilWhy(e, n, n.Left.Left, "go func")
 
@@ -792,11 +793,11 @@ func esc(e *EscState, n *Node, up *Node) {
 			escassignSinkNilWhy(e, n, n4, "go func arg")
 		}
 
-	case OCALLMETH, OCALLFUNC, OCALLINTER:
+	case ir.OCALLMETH, ir.OCALLFUNC, ir.OCALLINTER:
 		esccall(e, n, up)
 
 		// esccall already done on n->rlist->n. tie it's escretval to n->list
-	case OAS2FUNC: // x,y = f()
+	case ir.OAS2FUNC: // x,y = f()
 		rs := e.nodeEscState(n.Rlist.First()).Escretval.Slice()
 		for i, n := range n.List.Slice() {
 			if i >= len(rs) {
@@ -808,7 +809,7 @@ func esc(e *EscState, n *Node, up *Node) {
 			Fatalf("esc oas2func")
 		}
 
-	case ORETURN:
+	case ir.ORETURN:
 		ll := n.List
 		if n.List.Len() == 1 && Curfn.Type.Results().NumFields() > 1 {
 			// OAS2FUNC in disguise
@@ -822,7 +823,7 @@ func esc(e *EscState, n *Node, up *Node) {
 			if i >= ll.Len() {
 				break
 			}
-			if lrn.Op != ONAME || lrn.Class != PPARAMOUT {
+			if lrn.Op != ir.ONAME || lrn.Class != ir.PPARAMOUT {
 				continue
 			}
 			escassignNilWhy(e, lrn, ll.Index(i), "return")
@@ -834,10 +835,10 @@ func esc(e *EscState, n *Node, up *Node) {
 		}
 
 		// Argument could leak through recover.
-	case OPANIC:
+	case ir.OPANIC:
 		escassignSinkNilWhy(e, n, n.Left, "panic")
 
-	case OAPPEND:
+	case ir.OAPPEND:
 		if !n.Isddd {
 			for _, nn := range n.List.Slice()[1:] {
 				escassignSinkNilWhy(e, n, nn, "appended to slice") // lose track of assign to dereference
@@ -847,22 +848,22 @@ func esc(e *EscState, n *Node, up *Node) {
 			slice2 := n.List.Second()
 			escassignDereference(e, &e.theSink, slice2, e.stepAssign(nil, n, slice2, "appended slice...")) // lose track of assign of dereference
 			if Debug['m'] > 3 {
-				Warnl(n.Lineno, "%v special treatment of append(slice1, slice2...) %v", e.curfnSym(n), Nconv(n, FmtShort))
+				Warnl(n.Lineno, "%v special treatment of append(slice1, slice2...) %v", e.curfnSym(n), Nconv(n, ir.FmtShort))
 			}
 		}
 		escassignDereference(e, &e.theSink, n.List.First(), e.stepAssign(nil, n, n.List.First(), "appendee slice")) // The original elements are now leaked, too
 
-	case OCOPY:
+	case ir.OCOPY:
 		escassignDereference(e, &e.theSink, n.Right, e.stepAssign(nil, n, n.Right, "copied slice")) // lose track of assign of dereference
 
-	case OCONV, OCONVNOP:
+	case ir.OCONV, ir.OCONVNOP:
 		escassignNilWhy(e, n, n.Left, "converted")
 
-	case OCONVIFACE:
+	case ir.OCONVIFACE:
 		e.track(n)
 		escassignNilWhy(e, n, n.Left, "interface-converted")
 
-	case OARRAYLIT:
+	case ir.OARRAYLIT:
 		why := "array literal element"
 		if Isslice(n.Type) {
 			// Slice itself is not leaked until proven otherwise
@@ -875,24 +876,24 @@ func esc(e *EscState, n *Node, up *Node) {
 		}
 
 		// Link values to struct.
-	case OSTRUCTLIT:
+	case ir.OSTRUCTLIT:
 		for _, n6 := range n.List.Slice() {
 			escassignNilWhy(e, n, n6.Right, "struct literal element")
 		}
 
-	case OPTRLIT:
+	case ir.OPTRLIT:
 		e.track(n)
 
 		// Link OSTRUCTLIT to OPTRLIT; if OPTRLIT escapes, OSTRUCTLIT elements do too.
 		escassignNilWhy(e, n, n.Left, "pointer literal [assign]")
 
-	case OCALLPART:
+	case ir.OCALLPART:
 		e.track(n)
 
 		// Contents make it to memory, lose track.
 		escassignSinkNilWhy(e, n, n.Left, "call part")
 
-	case OMAPLIT:
+	case ir.OMAPLIT:
 		e.track(n)
 		// Keys and values make it to memory, lose track.
 		for _, n7 := range n.List.Slice() {
@@ -901,14 +902,14 @@ func esc(e *EscState, n *Node, up *Node) {
 		}
 
 		// Link addresses of captured variables to closure.
-	case OCLOSURE:
+	case ir.OCLOSURE:
 		for _, v := range n.Func.Cvars.Slice() {
-			if v.Op == OXXX { // unnamed out argument; see dcl.go:/^funcargs
+			if v.Op == ir.OXXX { // unnamed out argument; see dcl.go:/^funcargs
 				continue
 			}
 			a := v.Name.Param.Closure
 			if !v.Name.Byval {
-				a = Nod(OADDR, a, nil)
+				a = Nod(ir.OADDR, a, nil)
 				a.Lineno = v.Lineno
 				e.nodeEscState(a).Escloopdepth = e.loopdepth
 				a = typecheck(a, Erv)
@@ -918,22 +919,22 @@ func esc(e *EscState, n *Node, up *Node) {
 		}
 		fallthrough
 
-	case OMAKECHAN,
-		OMAKEMAP,
-		OMAKESLICE,
-		ONEW,
-		OARRAYRUNESTR,
-		OARRAYBYTESTR,
-		OSTRARRAYRUNE,
-		OSTRARRAYBYTE,
-		ORUNESTR:
+	case ir.OMAKECHAN,
+		ir.OMAKEMAP,
+		ir.OMAKESLICE,
+		ir.ONEW,
+		ir.OARRAYRUNESTR,
+		ir.OARRAYBYTESTR,
+		ir.OSTRARRAYRUNE,
+		ir.OSTRARRAYBYTE,
+		ir.ORUNESTR:
 		e.track(n)
 
-	case OADDSTR:
+	case ir.OADDSTR:
 		e.track(n)
 		// Arguments of OADDSTR do not escape.
 
-	case OADDR:
+	case ir.OADDR:
 		// current loop depth is an upper bound on actual loop depth
 		// of addressed value.
 		e.track(n)
@@ -941,9 +942,9 @@ func esc(e *EscState, n *Node, up *Node) {
 		// for &x, use loop depth of x if known.
 		// it should always be known, but if not, be conservative
 		// and keep the current loop depth.
-		if n.Left.Op == ONAME {
+		if n.Left.Op == ir.ONAME {
 			switch n.Left.Class {
-			case PAUTO:
+			case ir.PAUTO:
 				nE := e.nodeEscState(n)
 				leftE := e.nodeEscState(n.Left)
 				if leftE.Escloopdepth != 0 {
@@ -956,7 +957,7 @@ func esc(e *EscState, n *Node, up *Node) {
 			// so that writing the address of one result
 			// to another (or the same) result makes the
 			// first result move to the heap.
-			case PPARAM, PPARAMOUT:
+			case ir.PPARAM, ir.PPARAMOUT:
 				nE := e.nodeEscState(n)
 				nE.Escloopdepth = 1
 			}
@@ -968,7 +969,7 @@ func esc(e *EscState, n *Node, up *Node) {
 
 // escassignNilWhy bundles a common case of
 // escassign(e, dst, src, e.stepAssign(nil, dst, src, reason))
-func escassignNilWhy(e *EscState, dst, src *Node, reason string) {
+func escassignNilWhy(e *EscState, dst, src *ir.Node, reason string) {
 	var step *EscStep
 	if Debug['m'] != 0 {
 		step = e.stepAssign(nil, dst, src, reason)
@@ -978,7 +979,7 @@ func escassignNilWhy(e *EscState, dst, src *Node, reason string) {
 
 // escassignSinkNilWhy bundles a common case of
 // escassign(e, &e.theSink, src, e.stepAssign(nil, dst, src, reason))
-func escassignSinkNilWhy(e *EscState, dst, src *Node, reason string) {
+func escassignSinkNilWhy(e *EscState, dst, src *ir.Node, reason string) {
 	var step *EscStep
 	if Debug['m'] != 0 {
 		step = e.stepAssign(nil, dst, src, reason)
@@ -990,16 +991,16 @@ func escassignSinkNilWhy(e *EscState, dst, src *Node, reason string) {
 // dst==nil, any name node expr still must be marked as being
 // evaluated in curfn.	For expr==nil, dst must still be examined for
 // evaluations inside it (e.g *f(x) = y)
-func escassign(e *EscState, dst, src *Node, step *EscStep) {
-	if isblank(dst) || dst == nil || src == nil || src.Op == ONONAME || src.Op == OXXX {
+func escassign(e *EscState, dst, src *ir.Node, step *EscStep) {
+	if isblank(dst) || dst == nil || src == nil || src.Op == ir.ONONAME || src.Op == ir.OXXX {
 		return
 	}
 
 	if Debug['m'] > 2 {
 		fmt.Printf("%v:[%d] %v escassign: %v(%v)[%v] = %v(%v)[%v]\n",
 			linestr(lineno), e.loopdepth, funcSym(Curfn),
-			Nconv(dst, FmtShort), Jconv(dst, FmtShort), Oconv(dst.Op, 0),
-			Nconv(src, FmtShort), Jconv(src, FmtShort), Oconv(src.Op, 0))
+			Nconv(dst, ir.FmtShort), Jconv(dst, ir.FmtShort), Oconv(dst.Op, 0),
+			Nconv(src, ir.FmtShort), Jconv(src, ir.FmtShort), Oconv(src.Op, 0))
 	}
 
 	setlineno(dst)
@@ -1014,29 +1015,29 @@ func escassign(e *EscState, dst, src *Node, step *EscStep) {
 		Dump("dst", dst)
 		Fatalf("escassign: unexpected dst")
 
-	case OARRAYLIT,
-		OCLOSURE,
-		OCONV,
-		OCONVIFACE,
-		OCONVNOP,
-		OMAPLIT,
-		OSTRUCTLIT,
-		OPTRLIT,
-		ODDDARG,
-		OCALLPART:
+	case ir.OARRAYLIT,
+		ir.OCLOSURE,
+		ir.OCONV,
+		ir.OCONVIFACE,
+		ir.OCONVNOP,
+		ir.OMAPLIT,
+		ir.OSTRUCTLIT,
+		ir.OPTRLIT,
+		ir.ODDDARG,
+		ir.OCALLPART:
 		break
 
-	case ONAME:
-		if dst.Class == PEXTERN {
+	case ir.ONAME:
+		if dst.Class == ir.PEXTERN {
 			dstwhy = "assigned to top level variable"
 			dst = &e.theSink
 		}
 
-	case ODOT: // treat "dst.x = src" as "dst = src"
+	case ir.ODOT: // treat "dst.x = src" as "dst = src"
 		escassign(e, dst.Left, src, e.stepAssign(step, originalDst, src, "dot-equals"))
 		return
 
-	case OINDEX:
+	case ir.OINDEX:
 		if Isfixedarray(dst.Left.Type) {
 			escassign(e, dst.Left, src, e.stepAssign(step, originalDst, src, "array-element-equals"))
 			return
@@ -1045,16 +1046,16 @@ func escassign(e *EscState, dst, src *Node, step *EscStep) {
 		dstwhy = "slice-element-equals"
 		dst = &e.theSink // lose track of dereference
 
-	case OIND:
+	case ir.OIND:
 		dstwhy = "star-equals"
 		dst = &e.theSink // lose track of dereference
 
-	case ODOTPTR:
+	case ir.ODOTPTR:
 		dstwhy = "star-dot-equals"
 		dst = &e.theSink // lose track of dereference
 
 		// lose track of key and value
-	case OINDEXMAP:
+	case ir.OINDEXMAP:
 		escassign(e, &e.theSink, dst.Right, e.stepAssign(nil, originalDst, src, "key of map put"))
 		dstwhy = "value of map put"
 		dst = &e.theSink
@@ -1064,34 +1065,34 @@ func escassign(e *EscState, dst, src *Node, step *EscStep) {
 	e.pdepth++
 
 	switch src.Op {
-	case OADDR, // dst = &x
-		OIND,    // dst = *x
-		ODOTPTR, // dst = (*x).f
-		ONAME,
-		OPARAM,
-		ODDDARG,
-		OPTRLIT,
-		OARRAYLIT,
-		OMAPLIT,
-		OSTRUCTLIT,
-		OMAKECHAN,
-		OMAKEMAP,
-		OMAKESLICE,
-		OARRAYRUNESTR,
-		OARRAYBYTESTR,
-		OSTRARRAYRUNE,
-		OSTRARRAYBYTE,
-		OADDSTR,
-		ONEW,
-		OCALLPART,
-		ORUNESTR,
-		OCONVIFACE:
+	case ir.OADDR, // dst = &x
+		ir.OIND,    // dst = *x
+		ir.ODOTPTR, // dst = (*x).f
+		ir.ONAME,
+		ir.OPARAM,
+		ir.ODDDARG,
+		ir.OPTRLIT,
+		ir.OARRAYLIT,
+		ir.OMAPLIT,
+		ir.OSTRUCTLIT,
+		ir.OMAKECHAN,
+		ir.OMAKEMAP,
+		ir.OMAKESLICE,
+		ir.OARRAYRUNESTR,
+		ir.OARRAYBYTESTR,
+		ir.OSTRARRAYRUNE,
+		ir.OSTRARRAYBYTE,
+		ir.OADDSTR,
+		ir.ONEW,
+		ir.OCALLPART,
+		ir.ORUNESTR,
+		ir.OCONVIFACE:
 		escflows(e, dst, src, e.stepAssign(step, originalDst, src, dstwhy))
 
-	case OCLOSURE:
+	case ir.OCLOSURE:
 		// OCLOSURE is lowered to OPTRLIT,
 		// insert OADDR to account for the additional indirection.
-		a := Nod(OADDR, src, nil)
+		a := Nod(ir.OADDR, src, nil)
 		a.Lineno = src.Lineno
 		e.nodeEscState(a).Escloopdepth = e.nodeEscState(src).Escloopdepth
 		a.Type = Ptrto(src.Type)
@@ -1099,45 +1100,45 @@ func escassign(e *EscState, dst, src *Node, step *EscStep) {
 
 	// Flowing multiple returns to a single dst happens when
 	// analyzing "go f(g())": here g() flows to sink (issue 4529).
-	case OCALLMETH, OCALLFUNC, OCALLINTER:
+	case ir.OCALLMETH, ir.OCALLFUNC, ir.OCALLINTER:
 		for _, n := range e.nodeEscState(src).Escretval.Slice() {
 			escflows(e, dst, n, e.stepAssign(nil, originalDst, n, dstwhy))
 		}
 
 		// A non-pointer escaping from a struct does not concern us.
-	case ODOT:
+	case ir.ODOT:
 		if src.Type != nil && !haspointers(src.Type) {
 			break
 		}
 		fallthrough
 
 		// Conversions, field access, slice all preserve the input value.
-	case OCONV,
-		OCONVNOP,
-		ODOTMETH,
+	case ir.OCONV,
+		ir.OCONVNOP,
+		ir.ODOTMETH,
 		// treat recv.meth as a value with recv in it, only happens in ODEFER and OPROC
 		// iface.method already leaks iface in esccall, no need to put in extra ODOTINTER edge here
-		ODOTTYPE2,
-		OSLICE,
-		OSLICE3,
-		OSLICEARR,
-		OSLICE3ARR,
-		OSLICESTR:
+		ir.ODOTTYPE2,
+		ir.OSLICE,
+		ir.OSLICE3,
+		ir.OSLICEARR,
+		ir.OSLICE3ARR,
+		ir.OSLICESTR:
 		// Conversions, field access, slice all preserve the input value.
 		escassign(e, dst, src.Left, e.stepAssign(step, originalDst, src, dstwhy))
 
-	case ODOTTYPE:
+	case ir.ODOTTYPE:
 		if src.Type != nil && !haspointers(src.Type) {
 			break
 		}
 		escassign(e, dst, src.Left, e.stepAssign(step, originalDst, src, dstwhy))
 
-	case OAPPEND:
+	case ir.OAPPEND:
 		// Append returns first argument.
 		// Subsequent arguments are already leaked because they are operands to append.
 		escassign(e, dst, src.List.First(), e.stepAssign(step, dst, src.List.First(), dstwhy))
 
-	case OINDEX:
+	case ir.OINDEX:
 		// Index of array preserves input value.
 		if Isfixedarray(src.Left.Type) {
 			escassign(e, dst, src.Left, e.stepAssign(step, originalDst, src, dstwhy))
@@ -1148,20 +1149,20 @@ func escassign(e *EscState, dst, src *Node, step *EscStep) {
 		// Might be pointer arithmetic, in which case
 	// the operands flow into the result.
 	// TODO(rsc): Decide what the story is here. This is unsettling.
-	case OADD,
-		OSUB,
-		OOR,
-		OXOR,
-		OMUL,
-		ODIV,
-		OMOD,
-		OLSH,
-		ORSH,
-		OAND,
-		OANDNOT,
-		OPLUS,
-		OMINUS,
-		OCOM:
+	case ir.OADD,
+		ir.OSUB,
+		ir.OOR,
+		ir.OXOR,
+		ir.OMUL,
+		ir.ODIV,
+		ir.OMOD,
+		ir.OLSH,
+		ir.ORSH,
+		ir.OAND,
+		ir.OANDNOT,
+		ir.OPLUS,
+		ir.OMINUS,
+		ir.OCOM:
 		escassign(e, dst, src.Left, e.stepAssign(step, originalDst, src, dstwhy))
 
 		escassign(e, dst, src.Right, e.stepAssign(step, originalDst, src, dstwhy))
@@ -1268,15 +1269,15 @@ func describeEscape(em uint16) string {
 
 // escassignfromtag models the input-to-output assignment flow of one of a function
 // calls arguments, where the flow is encoded in "note".
-func escassignfromtag(e *EscState, note *string, dsts Nodes, src *Node) uint16 {
+func escassignfromtag(e *EscState, note *string, dsts ir.Nodes, src *ir.Node) uint16 {
 	em := parsetag(note)
-	if src.Op == OLITERAL {
+	if src.Op == ir.OLITERAL {
 		return em
 	}
 
 	if Debug['m'] > 3 {
 		fmt.Printf("%v::assignfromtag:: src=%v, em=%s\n",
-			linestr(lineno), Nconv(src, FmtShort), describeEscape(em))
+			linestr(lineno), Nconv(src, ir.FmtShort), describeEscape(em))
 	}
 
 	if em == EscUnknown {
@@ -1322,8 +1323,8 @@ func escassignfromtag(e *EscState, note *string, dsts Nodes, src *Node) uint16 {
 	return em0
 }
 
-func escassignDereference(e *EscState, dst *Node, src *Node, step *EscStep) {
-	if src.Op == OLITERAL {
+func escassignDereference(e *EscState, dst *ir.Node, src *ir.Node, step *EscStep) {
+	if src.Op == ir.OLITERAL {
 		return
 	}
 	escassign(e, dst, e.addDereference(src), step)
@@ -1332,8 +1333,8 @@ func escassignDereference(e *EscState, dst *Node, src *Node, step *EscStep) {
 // addDereference constructs a suitable OIND note applied to src.
 // Because this is for purposes of escape accounting, not execution,
 // some semantically dubious node combinations are (currently) possible.
-func (e *EscState) addDereference(n *Node) *Node {
-	ind := Nod(OIND, n, nil)
+func (e *EscState) addDereference(n *ir.Node) *ir.Node {
+	ind := Nod(ir.OIND, n, nil)
 	e.nodeEscState(ind).Escloopdepth = e.nodeEscState(n).Escloopdepth
 	ind.Lineno = n.Lineno
 	t := n.Type
@@ -1381,17 +1382,17 @@ func escNoteOutputParamFlow(e uint16, vargen int32, level Level) uint16 {
 	return (e &^ (bitsMaskForTag << shift)) | encodedFlow
 }
 
-func initEscretval(e *EscState, n *Node, fntype *Type) {
+func initEscretval(e *EscState, n *ir.Node, fntype *ir.Type) {
 	i := 0
 	nE := e.nodeEscState(n)
 	nE.Escretval.Set(nil) // Suspect this is not nil for indirect calls.
 	for _, t := range fntype.Results().Fields().Slice() {
-		src := Nod(ONAME, nil, nil)
+		src := Nod(ir.ONAME, nil, nil)
 		buf := fmt.Sprintf(".out%d", i)
 		i++
 		src.Sym = Lookup(buf)
 		src.Type = t.Type
-		src.Class = PAUTO
+		src.Class = ir.PAUTO
 		src.Name.Curfn = Curfn
 		e.nodeEscState(src).Escloopdepth = e.loopdepth
 		src.Used = true
@@ -1406,20 +1407,20 @@ func initEscretval(e *EscState, n *Node, fntype *Type) {
 // nodes, which may be marked "noescape". Navigating the ast is slightly
 // different for methods vs plain functions and for imported vs
 // this-package
-func esccall(e *EscState, n *Node, up *Node) {
-	var fntype *Type
+func esccall(e *EscState, n *ir.Node, up *ir.Node) {
+	var fntype *ir.Type
 	var indirect bool
-	var fn *Node
+	var fn *ir.Node
 	switch n.Op {
 	default:
 		Fatalf("esccall")
 
-	case OCALLFUNC:
+	case ir.OCALLFUNC:
 		fn = n.Left
 		fntype = fn.Type
-		indirect = fn.Op != ONAME || fn.Class != PFUNC
+		indirect = fn.Op != ir.ONAME || fn.Class != ir.PFUNC
 
-	case OCALLMETH:
+	case ir.OCALLMETH:
 		fn = n.Left.Sym.Def
 		if fn != nil {
 			fntype = fn.Type
@@ -1427,7 +1428,7 @@ func esccall(e *EscState, n *Node, up *Node) {
 			fntype = n.Left.Type
 		}
 
-	case OCALLINTER:
+	case ir.OCALLINTER:
 		fntype = n.Left.Type
 		indirect = true
 	}
@@ -1435,7 +1436,7 @@ func esccall(e *EscState, n *Node, up *Node) {
 	ll := n.List
 	if n.List.Len() == 1 {
 		a := n.List.First()
-		if a.Type.Etype == TSTRUCT && a.Type.Funarg { // f(g()).
+		if a.Type.Etype == ir.TSTRUCT && a.Type.Funarg { // f(g()).
 			ll = e.nodeEscState(a).Escretval
 		}
 	}
@@ -1446,13 +1447,13 @@ func esccall(e *EscState, n *Node, up *Node) {
 		for _, n1 := range ll.Slice() {
 			escassignSinkNilWhy(e, n, n1, "parameter to indirect call")
 			if Debug['m'] > 3 {
-				fmt.Printf("%v::esccall:: indirect call <- %v, untracked\n", linestr(lineno), Nconv(n1, FmtShort))
+				fmt.Printf("%v::esccall:: indirect call <- %v, untracked\n", linestr(lineno), Nconv(n1, ir.FmtShort))
 			}
 		}
 		// Set up bogus outputs
 		initEscretval(e, n, fntype)
 		// If there is a receiver, it also leaks to heap.
-		if n.Op != OCALLFUNC {
+		if n.Op != ir.OCALLFUNC {
 			t := fntype.Recv()
 			src := n.Left.Left
 			if haspointers(t.Type) {
@@ -1463,10 +1464,10 @@ func esccall(e *EscState, n *Node, up *Node) {
 	}
 
 	nE := e.nodeEscState(n)
-	if fn != nil && fn.Op == ONAME && fn.Class == PFUNC &&
+	if fn != nil && fn.Op == ir.ONAME && fn.Class == ir.PFUNC &&
 		fn.Name.Defn != nil && len(fn.Name.Defn.Nbody.Slice()) != 0 && fn.Name.Param.Ntype != nil && fn.Name.Defn.Esc < EscFuncTagged {
 		if Debug['m'] > 3 {
-			fmt.Printf("%v::esccall:: %v in recursive group\n", linestr(lineno), Nconv(n, FmtShort))
+			fmt.Printf("%v::esccall:: %v in recursive group\n", linestr(lineno), Nconv(n, ir.FmtShort))
 		}
 
 		// function in same mutually recursive group. Incorporate into flow graph.
@@ -1480,11 +1481,11 @@ func esccall(e *EscState, n *Node, up *Node) {
 		}
 
 		// Receiver.
-		if n.Op != OCALLFUNC {
+		if n.Op != ir.OCALLFUNC {
 			escassignNilWhy(e, fn.Name.Param.Ntype.Left.Left, n.Left.Left, "call receiver")
 		}
 
-		var src *Node
+		var src *ir.Node
 		lls := ll.Slice()
 		lrs := fn.Name.Param.Ntype.List.Slice()
 		i := 0
@@ -1492,8 +1493,8 @@ func esccall(e *EscState, n *Node, up *Node) {
 			src = lls[i]
 			if lrs[i].Isddd && !n.Isddd {
 				// Introduce ODDDARG node to represent ... allocation.
-				src = Nod(ODDDARG, nil, nil)
-				src.Type = typ(TARRAY)
+				src = Nod(ir.ODDDARG, nil, nil)
+				src.Type = ir.Typ(ir.TARRAY)
 				src.Type.Type = lrs[i].Type.Type
 				src.Type.Bound = int64(len(lls) - i)
 				src.Type = Ptrto(src.Type) // make pointer so it will be tracked
@@ -1513,7 +1514,7 @@ func esccall(e *EscState, n *Node, up *Node) {
 		// "..." arguments are untracked
 		for ; i < len(lls); i++ {
 			if Debug['m'] > 3 {
-				fmt.Printf("%v::esccall:: ... <- %v, untracked\n", linestr(lineno), Nconv(lls[i], FmtShort))
+				fmt.Printf("%v::esccall:: ... <- %v, untracked\n", linestr(lineno), Nconv(lls[i], ir.FmtShort))
 			}
 			escassignSinkNilWhy(e, src, lls[i], "... arg to recursive call")
 		}
@@ -1523,11 +1524,11 @@ func esccall(e *EscState, n *Node, up *Node) {
 
 	// Imported or completely analyzed function. Use the escape tags.
 	if nE.Escretval.Len() != 0 {
-		Fatalf("esc already decorated call %v\n", Nconv(n, FmtSign))
+		Fatalf("esc already decorated call %v\n", Nconv(n, ir.FmtSign))
 	}
 
 	if Debug['m'] > 3 {
-		fmt.Printf("%v::esccall:: %v not recursive\n", linestr(lineno), Nconv(n, FmtShort))
+		fmt.Printf("%v::esccall:: %v not recursive\n", linestr(lineno), Nconv(n, ir.FmtShort))
 	}
 
 	// set up out list on this call node with dummy auto ONAMES in the current (calling) function.
@@ -1536,7 +1537,7 @@ func esccall(e *EscState, n *Node, up *Node) {
 	//	print("esc analyzed fn: %#N (%+T) returning (%+H)\n", fn, fntype, n->escretval);
 
 	// Receiver.
-	if n.Op != OCALLFUNC {
+	if n.Op != ir.OCALLFUNC {
 		t := fntype.Recv()
 		src := n.Left.Left
 		if haspointers(t.Type) {
@@ -1544,16 +1545,16 @@ func esccall(e *EscState, n *Node, up *Node) {
 		}
 	}
 
-	var src *Node
+	var src *ir.Node
 	i := 0
 	lls := ll.Slice()
-	for t, it := IterFields(fntype.Params()); i < len(lls); i++ {
+	for t, it := ir.IterFields(fntype.Params()); i < len(lls); i++ {
 		src = lls[i]
 		if t.Isddd && !n.Isddd {
 			// Introduce ODDDARG node to represent ... allocation.
-			src = Nod(ODDDARG, nil, nil)
+			src = Nod(ir.ODDDARG, nil, nil)
 			src.Lineno = n.Lineno
-			src.Type = typ(TARRAY)
+			src.Type = ir.Typ(ir.TARRAY)
 			src.Type.Type = t.Type.Type
 			src.Type.Bound = int64(len(lls) - i)
 			src.Type = Ptrto(src.Type) // make pointer so it will be tracked
@@ -1562,9 +1563,9 @@ func esccall(e *EscState, n *Node, up *Node) {
 		}
 
 		if haspointers(t.Type) {
-			if escassignfromtag(e, t.Note, nE.Escretval, src) == EscNone && up.Op != ODEFER && up.Op != OPROC {
+			if escassignfromtag(e, t.Note, nE.Escretval, src) == EscNone && up.Op != ir.ODEFER && up.Op != ir.OPROC {
 				a := src
-				for a.Op == OCONVNOP {
+				for a.Op == ir.OCONVNOP {
 					a = a.Left
 				}
 				switch a.Op {
@@ -1577,12 +1578,12 @@ func esccall(e *EscState, n *Node, up *Node) {
 				// src->esc == EscNone means that src does not escape the current function.
 				// src->noescape = 1 here means that src does not escape this statement
 				// in the current function.
-				case OCALLPART,
-					OCLOSURE,
-					ODDDARG,
-					OARRAYLIT,
-					OPTRLIT,
-					OSTRUCTLIT:
+				case ir.OCALLPART,
+					ir.OCLOSURE,
+					ir.ODDDARG,
+					ir.OARRAYLIT,
+					ir.OPTRLIT,
+					ir.OSTRUCTLIT:
 					a.Noescape = true
 				}
 			}
@@ -1597,7 +1598,7 @@ func esccall(e *EscState, n *Node, up *Node) {
 
 	for ; i < len(lls); i++ {
 		if Debug['m'] > 3 {
-			fmt.Printf("%v::esccall:: ... <- %v\n", linestr(lineno), Nconv(lls[i], FmtShort))
+			fmt.Printf("%v::esccall:: ... <- %v\n", linestr(lineno), Nconv(lls[i], ir.FmtShort))
 		}
 		escassignNilWhy(e, src, lls[i], "arg to ...") // args to slice
 	}
@@ -1605,7 +1606,7 @@ func esccall(e *EscState, n *Node, up *Node) {
 
 // escflows records the link src->dst in dst, throwing out some quick wins,
 // and also ensuring that dst is noted as a flow destination.
-func escflows(e *EscState, dst, src *Node, why *EscStep) {
+func escflows(e *EscState, dst, src *ir.Node, why *EscStep) {
 	if dst == nil || src == nil || dst == src {
 		return
 	}
@@ -1616,7 +1617,7 @@ func escflows(e *EscState, dst, src *Node, why *EscStep) {
 	}
 
 	if Debug['m'] > 3 {
-		fmt.Printf("%v::flows:: %v <- %v\n", linestr(lineno), Nconv(dst, FmtShort), Nconv(src, FmtShort))
+		fmt.Printf("%v::flows:: %v <- %v\n", linestr(lineno), Nconv(dst, ir.FmtShort), Nconv(src, ir.FmtShort))
 	}
 
 	dstE := e.nodeEscState(dst)
@@ -1645,9 +1646,9 @@ func escflows(e *EscState, dst, src *Node, why *EscStep) {
 // its currfn/e->loopdepth are different from the flood's root.
 // Once an object has been moved to the heap, all of its upstream should be considered
 // escaping to the global scope.
-func escflood(e *EscState, dst *Node) {
+func escflood(e *EscState, dst *ir.Node) {
 	switch dst.Op {
-	case ONAME, OCLOSURE:
+	case ir.ONAME, ir.OCLOSURE:
 		break
 
 	default:
@@ -1656,7 +1657,7 @@ func escflood(e *EscState, dst *Node) {
 
 	dstE := e.nodeEscState(dst)
 	if Debug['m'] > 2 {
-		fmt.Printf("\nescflood:%d: dst %v scope:%v[%d]\n", e.walkgen, Nconv(dst, FmtShort), e.curfnSym(dst), dstE.Escloopdepth)
+		fmt.Printf("\nescflood:%d: dst %v scope:%v[%d]\n", e.walkgen, Nconv(dst, ir.FmtShort), e.curfnSym(dst), dstE.Escloopdepth)
 	}
 
 	for i, l := range dstE.Escflowsrc {
@@ -1667,13 +1668,13 @@ func escflood(e *EscState, dst *Node) {
 }
 
 // funcOutputAndInput reports whether dst and src correspond to output and input parameters of the same function.
-func funcOutputAndInput(dst, src *Node) bool {
+func funcOutputAndInput(dst, src *ir.Node) bool {
 	// Note if dst is marked as escaping, then "returned" is too weak.
-	return dst.Op == ONAME && dst.Class == PPARAMOUT &&
-		src.Op == ONAME && src.Class == PPARAM && src.Name.Curfn == dst.Name.Curfn
+	return dst.Op == ir.ONAME && dst.Class == ir.PPARAMOUT &&
+		src.Op == ir.ONAME && src.Class == ir.PPARAM && src.Name.Curfn == dst.Name.Curfn
 }
 
-func (es *EscStep) describe(src *Node) {
+func (es *EscStep) describe(src *ir.Node) {
 	if Debug['m'] < 2 {
 		return
 	}
@@ -1699,12 +1700,12 @@ func (es *EscStep) describe(src *Node) {
 
 const NOTALOOPDEPTH = -1
 
-func escwalk(e *EscState, level Level, dst *Node, src *Node, step *EscStep) {
+func escwalk(e *EscState, level Level, dst *ir.Node, src *ir.Node, step *EscStep) {
 	escwalkBody(e, level, dst, src, step, NOTALOOPDEPTH)
 }
 
-func escwalkBody(e *EscState, level Level, dst *Node, src *Node, step *EscStep, extraloopdepth int32) {
-	if src.Op == OLITERAL {
+func escwalkBody(e *EscState, level Level, dst *ir.Node, src *ir.Node, step *EscStep, extraloopdepth int32) {
+	if src.Op == ir.OLITERAL {
 		return
 	}
 	srcE := e.nodeEscState(src)
@@ -1736,7 +1737,7 @@ func escwalkBody(e *EscState, level Level, dst *Node, src *Node, step *EscStep,
 
 	if Debug['m'] > 2 {
 		fmt.Printf("escwalk: level:%d depth:%d %.*s op=%v %v(%v) scope:%v[%d] extraloopdepth=%v\n",
-			level, e.pdepth, e.pdepth, "\t\t\t\t\t\t\t\t\t\t", Oconv(src.Op, 0), Nconv(src, FmtShort), Jconv(src, FmtShort), e.curfnSym(src), srcE.Escloopdepth, extraloopdepth)
+			level, e.pdepth, e.pdepth, "\t\t\t\t\t\t\t\t\t\t", Oconv(src.Op, 0), Nconv(src, ir.FmtShort), Jconv(src, ir.FmtShort), e.curfnSym(src), srcE.Escloopdepth, extraloopdepth)
 	}
 
 	e.pdepth++
@@ -1754,10 +1755,10 @@ func escwalkBody(e *EscState, level Level, dst *Node, src *Node, step *EscStep,
 		// 4. return *in
 		if Debug['m'] != 0 {
 			if Debug['m'] <= 2 {
-				Warnl(src.Lineno, "leaking param: %v to result %v level=%v", Nconv(src, FmtShort), dst.Sym, level.int())
+				Warnl(src.Lineno, "leaking param: %v to result %v level=%v", Nconv(src, ir.FmtShort), dst.Sym, level.int())
 				step.describe(src)
 			} else {
-				Warnl(src.Lineno, "leaking param: %v to result %v level=%v", Nconv(src, FmtShort), dst.Sym, level)
+				Warnl(src.Lineno, "leaking param: %v to result %v level=%v", Nconv(src, ir.FmtShort), dst.Sym, level)
 			}
 		}
 		if src.Esc&EscMask != EscReturn {
@@ -1770,11 +1771,11 @@ func escwalkBody(e *EscState, level Level, dst *Node, src *Node, step *EscStep,
 	// If parameter content escapes to heap, set EscContentEscapes
 	// Note minor confusion around escape from pointer-to-struct vs escape from struct
 	if dst.Esc == EscHeap &&
-		src.Op == ONAME && src.Class == PPARAM && src.Esc&EscMask < EscScope &&
+		src.Op == ir.ONAME && src.Class == ir.PPARAM && src.Esc&EscMask < EscScope &&
 		level.int() > 0 {
 		src.Esc = escMax(EscContentEscapes|src.Esc, EscNone)
 		if Debug['m'] != 0 {
-			Warnl(src.Lineno, "mark escaped content: %v", Nconv(src, FmtShort))
+			Warnl(src.Lineno, "mark escaped content: %v", Nconv(src, ir.FmtShort))
 			step.describe(src)
 		}
 	}
@@ -1783,19 +1784,19 @@ func escwalkBody(e *EscState, level Level, dst *Node, src *Node, step *EscStep,
 
 	osrcesc = src.Esc
 	switch src.Op {
-	case ONAME:
-		if src.Class == PPARAM && (leaks || dstE.Escloopdepth < 0) && src.Esc&EscMask < EscScope {
+	case ir.ONAME:
+		if src.Class == ir.PPARAM && (leaks || dstE.Escloopdepth < 0) && src.Esc&EscMask < EscScope {
 			if level.guaranteedDereference() > 0 {
 				src.Esc = escMax(EscContentEscapes|src.Esc, EscNone)
 				if Debug['m'] != 0 {
 					if Debug['m'] <= 2 {
 						if osrcesc != src.Esc {
-							Warnl(src.Lineno, "leaking param content: %v", Nconv(src, FmtShort))
+							Warnl(src.Lineno, "leaking param content: %v", Nconv(src, ir.FmtShort))
 							step.describe(src)
 						}
 					} else {
 						Warnl(src.Lineno, "leaking param content: %v level=%v dst.eld=%v src.eld=%v dst=%v",
-							Nconv(src, FmtShort), level, dstE.Escloopdepth, modSrcLoopdepth, Nconv(dst, FmtShort))
+							Nconv(src, ir.FmtShort), level, dstE.Escloopdepth, modSrcLoopdepth, Nconv(dst, ir.FmtShort))
 					}
 				}
 			} else {
@@ -1803,11 +1804,11 @@ func escwalkBody(e *EscState, level Level, dst *Node, src *Node, step *EscStep,
 				if Debug['m'] != 0 {
 
 					if Debug['m'] <= 2 {
-						Warnl(src.Lineno, "leaking param: %v", Nconv(src, FmtShort))
+						Warnl(src.Lineno, "leaking param: %v", Nconv(src, ir.FmtShort))
 						step.describe(src)
 					} else {
 						Warnl(src.Lineno, "leaking param: %v level=%v dst.eld=%v src.eld=%v dst=%v",
-							Nconv(src, FmtShort), level, dstE.Escloopdepth, modSrcLoopdepth, Nconv(dst, FmtShort))
+							Nconv(src, ir.FmtShort), level, dstE.Escloopdepth, modSrcLoopdepth, Nconv(dst, ir.FmtShort))
 					}
 				}
 			}
@@ -1815,17 +1816,17 @@ func escwalkBody(e *EscState, level Level, dst *Node, src *Node, step *EscStep,
 
 		// Treat a PPARAMREF closure variable as equivalent to the
 		// original variable.
-		if src.Class == PPARAMREF {
+		if src.Class == ir.PPARAMREF {
 			if leaks && Debug['m'] != 0 {
-				Warnl(src.Lineno, "leaking closure reference %v", Nconv(src, FmtShort))
+				Warnl(src.Lineno, "leaking closure reference %v", Nconv(src, ir.FmtShort))
 				step.describe(src)
 			}
 			escwalk(e, level, dst, src.Name.Param.Closure, e.stepWalk(dst, src.Name.Param.Closure, "closure-var", step))
 		}
 
-	case OPTRLIT, OADDR:
+	case ir.OPTRLIT, ir.OADDR:
 		why := "pointer literal"
-		if src.Op == OADDR {
+		if src.Op == ir.OADDR {
 			why = "address-of"
 		}
 		if leaks {
@@ -1833,14 +1834,14 @@ func escwalkBody(e *EscState, level Level, dst *Node, src *Node, step *EscStep,
 			addrescapes(src.Left)
 			if Debug['m'] != 0 && osrcesc != src.Esc {
 				p := src
-				if p.Left.Op == OCLOSURE {
+				if p.Left.Op == ir.OCLOSURE {
 					p = p.Left // merely to satisfy error messages in tests
 				}
 				if Debug['m'] > 2 {
 					Warnl(src.Lineno, "%v escapes to heap, level=%v, dst.eld=%v, src.eld=%v",
-						Nconv(p, FmtShort), level, dstE.Escloopdepth, modSrcLoopdepth)
+						Nconv(p, ir.FmtShort), level, dstE.Escloopdepth, modSrcLoopdepth)
 				} else {
-					Warnl(src.Lineno, "%v escapes to heap", Nconv(p, FmtShort))
+					Warnl(src.Lineno, "%v escapes to heap", Nconv(p, ir.FmtShort))
 					step.describe(src)
 				}
 			}
@@ -1850,14 +1851,14 @@ func escwalkBody(e *EscState, level Level, dst *Node, src *Node, step *EscStep,
 			escwalk(e, level.dec(), dst, src.Left, e.stepWalk(dst, src.Left, why, step))
 		}
 
-	case OAPPEND:
+	case ir.OAPPEND:
 		escwalk(e, level, dst, src.List.First(), e.stepWalk(dst, src.List.First(), "append-first-arg", step))
 
-	case ODDDARG:
+	case ir.ODDDARG:
 		if leaks {
 			src.Esc = EscHeap
 			if Debug['m'] != 0 && osrcesc != src.Esc {
-				Warnl(src.Lineno, "%v escapes to heap", Nconv(src, FmtShort))
+				Warnl(src.Lineno, "%v escapes to heap", Nconv(src, ir.FmtShort))
 				step.describe(src)
 			}
 			extraloopdepth = modSrcLoopdepth
@@ -1865,7 +1866,7 @@ func escwalkBody(e *EscState, level Level, dst *Node, src *Node, step *EscStep,
 		// similar to a slice arraylit and its args.
 		level = level.dec()
 
-	case OARRAYLIT:
+	case ir.OARRAYLIT:
 		if Isfixedarray(src.Type) {
 			break
 		}
@@ -1875,53 +1876,53 @@ func escwalkBody(e *EscState, level Level, dst *Node, src *Node, step *EscStep,
 
 		fallthrough
 
-	case OMAKECHAN,
-		OMAKEMAP,
-		OMAKESLICE,
-		OARRAYRUNESTR,
-		OARRAYBYTESTR,
-		OSTRARRAYRUNE,
-		OSTRARRAYBYTE,
-		OADDSTR,
-		OMAPLIT,
-		ONEW,
-		OCLOSURE,
-		OCALLPART,
-		ORUNESTR,
-		OCONVIFACE:
+	case ir.OMAKECHAN,
+		ir.OMAKEMAP,
+		ir.OMAKESLICE,
+		ir.OARRAYRUNESTR,
+		ir.OARRAYBYTESTR,
+		ir.OSTRARRAYRUNE,
+		ir.OSTRARRAYBYTE,
+		ir.OADDSTR,
+		ir.OMAPLIT,
+		ir.ONEW,
+		ir.OCLOSURE,
+		ir.OCALLPART,
+		ir.ORUNESTR,
+		ir.OCONVIFACE:
 		if leaks {
 			src.Esc = EscHeap
 			if Debug['m'] != 0 && osrcesc != src.Esc {
-				Warnl(src.Lineno, "%v escapes to heap", Nconv(src, FmtShort))
+				Warnl(src.Lineno, "%v escapes to heap", Nconv(src, ir.FmtShort))
 				step.describe(src)
 			}
 			extraloopdepth = modSrcLoopdepth
 		}
 
-	case ODOT,
-		ODOTTYPE:
+	case ir.ODOT,
+		ir.ODOTTYPE:
 		escwalk(e, level, dst, src.Left, e.stepWalk(dst, src.Left, "dot", step))
 
 	case
-		OSLICE,
-		OSLICEARR,
-		OSLICE3,
-		OSLICE3ARR,
-		OSLICESTR:
+		ir.OSLICE,
+		ir.OSLICEARR,
+		ir.OSLICE3,
+		ir.OSLICE3ARR,
+		ir.OSLICESTR:
 		escwalk(e, level, dst, src.Left, e.stepWalk(dst, src.Left, "slice", step))
 
-	case OINDEX:
+	case ir.OINDEX:
 		if Isfixedarray(src.Left.Type) {
 			escwalk(e, level, dst, src.Left, e.stepWalk(dst, src.Left, "fixed-array-index-of", step))
 			break
 		}
 		fallthrough
 
-	case ODOTPTR:
+	case ir.ODOTPTR:
 		escwalk(e, level.inc(), dst, src.Left, e.stepWalk(dst, src.Left, "dot of pointer", step))
-	case OINDEXMAP:
+	case ir.OINDEXMAP:
 		escwalk(e, level.inc(), dst, src.Left, e.stepWalk(dst, src.Left, "map index", step))
-	case OIND:
+	case ir.OIND:
 		escwalk(e, level.inc(), dst, src.Left, e.stepWalk(dst, src.Left, "indirection", step))
 
 	// In this case a link went directly to a call, but should really go
@@ -1929,12 +1930,12 @@ func escwalkBody(e *EscState, level Level, dst *Node, src *Node, step *EscStep,
 	// themselves link to the inputs with levels adjusted.
 	// See e.g. #10466
 	// This can only happen with functions returning a single result.
-	case OCALLMETH, OCALLFUNC, OCALLINTER:
+	case ir.OCALLMETH, ir.OCALLFUNC, ir.OCALLINTER:
 		if srcE.Escretval.Len() != 0 {
 			if Debug['m'] > 2 {
 				fmt.Printf("%v:[%d] dst %v escwalk replace src: %v with %v\n",
 					linestr(lineno), e.loopdepth,
-					Nconv(dst, FmtShort), Nconv(src, FmtShort), Nconv(srcE.Escretval.First(), FmtShort))
+					Nconv(dst, ir.FmtShort), Nconv(src, ir.FmtShort), Nconv(srcE.Escretval.First(), ir.FmtShort))
 			}
 			src = srcE.Escretval.First()
 			srcE = e.nodeEscState(src)
@@ -1959,7 +1960,7 @@ recurse:
 // lets us take the address below to get a *string.
 var unsafeUintptrTag = "unsafe-uintptr"
 
-func esctag(e *EscState, func_ *Node) {
+func esctag(e *EscState, func_ *ir.Node) {
 	func_.Esc = EscFuncTagged
 
 	// External functions are assumed unsafe,
@@ -1982,7 +1983,7 @@ func esctag(e *EscState, func_ *Node) {
 		narg := 0
 		for _, t := range func_.Type.Params().Fields().Slice() {
 			narg++
-			if t.Type.Etype == TUINTPTR {
+			if t.Type.Etype == ir.TUINTPTR {
 				if Debug['m'] != 0 {
 					var name string
 					if t.Sym != nil {
@@ -2003,7 +2004,7 @@ func esctag(e *EscState, func_ *Node) {
 	Curfn = func_
 
 	for _, ln := range Curfn.Func.Dcl {
-		if ln.Op != ONAME {
+		if ln.Op != ir.ONAME {
 			continue
 		}
 
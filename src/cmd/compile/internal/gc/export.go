@@ -7,17 +7,20 @@ package gc
 import (
 	"bufio"
 	"bytes"
+	"cmd/compile/internal/ir"
 	"cmd/internal/obj"
 	"fmt"
 	"sort"
+	"strings"
 	"unicode"
 	"unicode/utf8"
 )
 
 var (
-	newexport    int // if set, use new export format
-	Debug_export int // if set, print debugging information about export data
-	exportsize   int
+	newexport      = 1 // if set, use new export format; -newexport=0 selects the old textual format
+	compressexport int // if set, compress binary export data
+	Debug_export   int // if set, print debugging information about export data
+	exportsize     int
 )
 
 func exportf(format string, args ...interface{}) {
@@ -28,21 +31,21 @@ func exportf(format string, args ...interface{}) {
 	}
 }
 
-var asmlist []*Node
+var asmlist []*ir.Node
 
 // Mark n's symbol as exported
-func exportsym(n *Node) {
+func exportsym(n *ir.Node) {
 	if n == nil || n.Sym == nil {
 		return
 	}
-	if n.Sym.Flags&(SymExport|SymPackage) != 0 {
-		if n.Sym.Flags&SymPackage != 0 {
+	if n.Sym.Flags&(ir.SymExport|ir.SymPackage) != 0 {
+		if n.Sym.Flags&ir.SymPackage != 0 {
 			Yyerror("export/package mismatch: %v", n.Sym)
 		}
 		return
 	}
 
-	n.Sym.Flags |= SymExport
+	n.Sym.Flags |= ir.SymExport
 
 	if Debug['E'] != 0 {
 		fmt.Printf("export symbol %v\n", n.Sym)
@@ -64,7 +67,7 @@ func initname(s string) bool {
 
 // exportedsym reports whether a symbol will be visible
 // to files that import our package.
-func exportedsym(sym *Sym) bool {
+func exportedsym(sym *ir.Sym) bool {
 	// Builtins are visible everywhere.
 	if sym.Pkg == builtinpkg || sym.Origpkg == builtinpkg {
 		return true
@@ -73,14 +76,14 @@ func exportedsym(sym *Sym) bool {
 	return sym.Pkg == localpkg && exportname(sym.Name)
 }
 
-func autoexport(n *Node, ctxt Class) {
+func autoexport(n *ir.Node, ctxt ir.Class) {
 	if n == nil || n.Sym == nil {
 		return
 	}
-	if (ctxt != PEXTERN && ctxt != PFUNC) || dclcontext != PEXTERN {
+	if (ctxt != ir.PEXTERN && ctxt != ir.PFUNC) || dclcontext != ir.PEXTERN {
 		return
 	}
-	if n.Name.Param != nil && n.Name.Param.Ntype != nil && n.Name.Param.Ntype.Op == OTFUNC && n.Name.Param.Ntype.Left != nil { // method
+	if n.Name.Param != nil && n.Name.Param.Ntype != nil && n.Name.Param.Ntype.Op == ir.OTFUNC && n.Name.Param.Ntype.Left != nil { // method
 		return
 	}
 
@@ -88,13 +91,13 @@ func autoexport(n *Node, ctxt Class) {
 	if Debug['A'] != 0 || exportname(n.Sym.Name) || initname(n.Sym.Name) {
 		exportsym(n)
 	}
-	if asmhdr != "" && n.Sym.Pkg == localpkg && n.Sym.Flags&SymAsm == 0 {
-		n.Sym.Flags |= SymAsm
+	if asmhdr != "" && n.Sym.Pkg == localpkg && n.Sym.Flags&ir.SymAsm == 0 {
+		n.Sym.Flags |= ir.SymAsm
 		asmlist = append(asmlist, n)
 	}
 }
 
-func dumppkg(p *Pkg) {
+func dumppkg(p *ir.Pkg) {
 	if p == nil || p == localpkg || p.Exported || p == builtinpkg {
 		return
 	}
@@ -107,25 +110,25 @@ func dumppkg(p *Pkg) {
 }
 
 // Look for anything we need for the inline body
-func reexportdeplist(ll Nodes) {
+func reexportdeplist(ll ir.Nodes) {
 	for _, n := range ll.Slice() {
 		reexportdep(n)
 	}
 }
 
-func reexportdep(n *Node) {
+func reexportdep(n *ir.Node) {
 	if n == nil {
 		return
 	}
 
 	//print("reexportdep %+hN\n", n);
 	switch n.Op {
-	case ONAME:
-		switch n.Class &^ PHEAP {
+	case ir.ONAME:
+		switch n.Class &^ ir.PHEAP {
 		// methods will be printed along with their type
 		// nodes for T.Method expressions
-		case PFUNC:
-			if n.Left != nil && n.Left.Op == OTYPE {
+		case ir.PFUNC:
+			if n.Left != nil && n.Left.Op == ir.OTYPE {
 				break
 			}
 
@@ -135,7 +138,7 @@ func reexportdep(n *Node) {
 			}
 			fallthrough
 
-		case PEXTERN:
+		case ir.PEXTERN:
 			if n.Sym != nil && !exportedsym(n.Sym) {
 				if Debug['E'] != 0 {
 					fmt.Printf("reexport name %v\n", n.Sym)
@@ -145,10 +148,10 @@ func reexportdep(n *Node) {
 		}
 
 	// Local variables in the bodies need their type.
-	case ODCL:
+	case ir.ODCL:
 		t := n.Left.Type
 
-		if t != Types[t.Etype] && t != idealbool && t != idealstring {
+		if t != ir.Types[t.Etype] && t != ir.Idealbool && t != ir.Idealstring {
 			if Isptr[t.Etype] {
 				t = t.Type
 			}
@@ -160,9 +163,9 @@ func reexportdep(n *Node) {
 			}
 		}
 
-	case OLITERAL:
+	case ir.OLITERAL:
 		t := n.Type
-		if t != Types[n.Type.Etype] && t != idealbool && t != idealstring {
+		if t != ir.Types[n.Type.Etype] && t != ir.Idealbool && t != ir.Idealstring {
 			if Isptr[t.Etype] {
 				t = t.Type
 			}
@@ -175,7 +178,7 @@ func reexportdep(n *Node) {
 		}
 		fallthrough
 
-	case OTYPE:
+	case ir.OTYPE:
 		if n.Sym != nil && n.Sym.Def != nil && !exportedsym(n.Sym) {
 			if Debug['E'] != 0 {
 				fmt.Printf("reexport literal/type %v\n", n.Sym)
@@ -184,26 +187,26 @@ func reexportdep(n *Node) {
 		}
 
 	// for operations that need a type when rendered, put the type on the export list.
-	case OCONV,
-		OCONVIFACE,
-		OCONVNOP,
-		ORUNESTR,
-		OARRAYBYTESTR,
-		OARRAYRUNESTR,
-		OSTRARRAYBYTE,
-		OSTRARRAYRUNE,
-		ODOTTYPE,
-		ODOTTYPE2,
-		OSTRUCTLIT,
-		OARRAYLIT,
-		OPTRLIT,
-		OMAKEMAP,
-		OMAKESLICE,
-		OMAKECHAN:
+	case ir.OCONV,
+		ir.OCONVIFACE,
+		ir.OCONVNOP,
+		ir.ORUNESTR,
+		ir.OARRAYBYTESTR,
+		ir.OARRAYRUNESTR,
+		ir.OSTRARRAYBYTE,
+		ir.OSTRARRAYRUNE,
+		ir.ODOTTYPE,
+		ir.ODOTTYPE2,
+		ir.OSTRUCTLIT,
+		ir.OARRAYLIT,
+		ir.OPTRLIT,
+		ir.OMAKEMAP,
+		ir.OMAKESLICE,
+		ir.OMAKECHAN:
 		t := n.Type
 
 		switch t.Etype {
-		case TARRAY, TCHAN, TPTR32, TPTR64:
+		case ir.TARRAY, ir.TCHAN, ir.TPTR32, ir.TPTR64:
 			if t.Sym == nil {
 				t = t.Type
 			}
@@ -224,9 +227,9 @@ func reexportdep(n *Node) {
 	reexportdeplist(n.Nbody)
 }
 
-func dumpexportconst(s *Sym) {
+func dumpexportconst(s *ir.Sym) {
 	n := typecheck(s.Def, Erv)
-	if n == nil || n.Op != OLITERAL {
+	if n == nil || n.Op != ir.OLITERAL {
 		Fatalf("dumpexportconst: oconst nil: %v", s)
 	}
 
@@ -234,13 +237,13 @@ func dumpexportconst(s *Sym) {
 	dumpexporttype(t)
 
 	if t != nil && !isideal(t) {
-		exportf("\tconst %v %v = %v\n", Sconv(s, FmtSharp), Tconv(t, FmtSharp), Vconv(n.Val(), FmtSharp))
+		exportf("\tconst %v %v = %v\n", Sconv(s, ir.FmtSharp), Tconv(t, ir.FmtSharp), Vconv(n.Val(), ir.FmtSharp))
 	} else {
-		exportf("\tconst %v = %v\n", Sconv(s, FmtSharp), Vconv(n.Val(), FmtSharp))
+		exportf("\tconst %v = %v\n", Sconv(s, ir.FmtSharp), Vconv(n.Val(), ir.FmtSharp))
 	}
 }
 
-func dumpexportvar(s *Sym) {
+func dumpexportvar(s *ir.Sym) {
 	n := s.Def
 	n = typecheck(n, Erv|Ecall)
 	if n == nil || n.Type == nil {
@@ -251,8 +254,12 @@ func dumpexportvar(s *Sym) {
 	t := n.Type
 	dumpexporttype(t)
 
-	if t.Etype == TFUNC && n.Class == PFUNC {
-		if n.Func != nil && len(n.Func.Inl.Slice()) != 0 {
+	if t.Etype == ir.TFUNC && n.Class == ir.PFUNC {
+		if hasinl(n) {
+			// loadinl may have given n a copy of its shared type,
+			// with the parameters named as in the inlined body.
+			t = n.Type
+
 			// when lazily typechecking inlined bodies, some re-exported ones may not have been typechecked yet.
 			// currently that can leave unresolved ONONAMEs in import-dot-ed packages in the wrong package
 			if Debug['l'] < 2 {
@@ -260,29 +267,29 @@ func dumpexportvar(s *Sym) {
 			}
 
 			// NOTE: The space after %#S here is necessary for ld's export data parser.
-			exportf("\tfunc %v %v { %v }\n", Sconv(s, FmtSharp), Tconv(t, FmtShort|FmtSharp), Hconv(n.Func.Inl, FmtSharp|FmtBody))
+			exportf("\tfunc %v %v { %v }\n", Sconv(s, ir.FmtSharp), Tconv(t, ir.FmtShort|ir.FmtSharp), Hconv(n.Func.Inl, ir.FmtSharp|ir.FmtBody))
 
 			reexportdeplist(n.Func.Inl)
 		} else {
-			exportf("\tfunc %v %v\n", Sconv(s, FmtSharp), Tconv(t, FmtShort|FmtSharp))
+			exportf("\tfunc %v %v\n", Sconv(s, ir.FmtSharp), Tconv(t, ir.FmtShort|ir.FmtSharp))
 		}
 	} else {
-		exportf("\tvar %v %v\n", Sconv(s, FmtSharp), Tconv(t, FmtSharp))
+		exportf("\tvar %v %v\n", Sconv(s, ir.FmtSharp), Tconv(t, ir.FmtSharp))
 	}
 }
 
 // methodbyname sorts types by symbol name.
-type methodbyname []*Field
+type methodbyname []*ir.Field
 
 func (x methodbyname) Len() int           { return len(x) }
 func (x methodbyname) Swap(i, j int)      { x[i], x[j] = x[j], x[i] }
 func (x methodbyname) Less(i, j int) bool { return x[i].Sym.Name < x[j].Sym.Name }
 
-func dumpexporttype(t *Type) {
+func dumpexporttype(t *ir.Type) {
 	if t == nil {
 		return
 	}
-	if t.Printed || t == Types[t.Etype] || t == bytetype || t == runetype || t == errortype {
+	if t.Printed || t == ir.Types[t.Etype] || t == ir.Bytetype || t == ir.Runetype || t == ir.Errortype {
 		return
 	}
 	t.Printed = true
@@ -292,18 +299,18 @@ func dumpexporttype(t *Type) {
 	}
 
 	switch t.Etype {
-	case TSTRUCT, TINTER:
+	case ir.TSTRUCT, ir.TINTER:
 		for _, f := range t.Fields().Slice() {
 			dumpexporttype(f.Type)
 		}
-	case TFUNC:
+	case ir.TFUNC:
 		dumpexporttype(t.Recvs())
 		dumpexporttype(t.Results())
 		dumpexporttype(t.Params())
-	case TMAP:
+	case ir.TMAP:
 		dumpexporttype(t.Type)
 		dumpexporttype(t.Down) // key
-	case TARRAY, TCHAN, TPTR32, TPTR64:
+	case ir.TARRAY, ir.TCHAN, ir.TPTR32, ir.TPTR64:
 		dumpexporttype(t.Type)
 	}
 
@@ -311,38 +318,39 @@ func dumpexporttype(t *Type) {
 		return
 	}
 
-	var m []*Field
+	var m []*ir.Field
 	for _, f := range t.Methods().Slice() {
 		dumpexporttype(f.Type)
 		m = append(m, f)
 	}
 	sort.Sort(methodbyname(m))
 
-	exportf("\ttype %v %v\n", Sconv(t.Sym, FmtSharp), Tconv(t, FmtSharp|FmtLong))
+	exportf("\ttype %v %v\n", Sconv(t.Sym, ir.FmtSharp), Tconv(t, ir.FmtSharp|ir.FmtLong))
 	for _, f := range m {
 		if f.Nointerface {
 			exportf("\t//go:nointerface\n")
 		}
-		if f.Type.Nname != nil && len(f.Type.Nname.Func.Inl.Slice()) != 0 { // nname was set by caninl
+		if hasinl(f.Type.Nname) { // nname was set by caninl
 
 			// when lazily typechecking inlined bodies, some re-exported ones may not have been typechecked yet.
 			// currently that can leave unresolved ONONAMEs in import-dot-ed packages in the wrong package
 			if Debug['l'] < 2 {
 				typecheckinl(f.Type.Nname)
 			}
-			exportf("\tfunc %v %v %v { %v }\n", Tconv(f.Type.Recvs(), FmtSharp), Sconv(f.Sym, FmtShort|FmtByte|FmtSharp), Tconv(f.Type, FmtShort|FmtSharp), Hconv(f.Type.Nname.Func.Inl, FmtSharp|FmtBody))
+			ft := f.Type.Nname.Type // see dumpexportvar
+			exportf("\tfunc %v %v %v { %v }\n", Tconv(ft.Recvs(), ir.FmtSharp), Sconv(f.Sym, ir.FmtShort|ir.FmtByte|ir.FmtSharp), Tconv(ft, ir.FmtShort|ir.FmtSharp), Hconv(f.Type.Nname.Func.Inl, ir.FmtSharp|ir.FmtBody))
 			reexportdeplist(f.Type.Nname.Func.Inl)
 		} else {
-			exportf("\tfunc %v %v %v\n", Tconv(f.Type.Recvs(), FmtSharp), Sconv(f.Sym, FmtShort|FmtByte|FmtSharp), Tconv(f.Type, FmtShort|FmtSharp))
+			exportf("\tfunc %v %v %v\n", Tconv(f.Type.Recvs(), ir.FmtSharp), Sconv(f.Sym, ir.FmtShort|ir.FmtByte|ir.FmtSharp), Tconv(f.Type, ir.FmtShort|ir.FmtSharp))
 		}
 	}
 }
 
-func dumpsym(s *Sym) {
-	if s.Flags&SymExported != 0 {
+func dumpsym(s *ir.Sym) {
+	if s.Flags&ir.SymExported != 0 {
 		return
 	}
-	s.Flags |= SymExported
+	s.Flags |= ir.SymExported
 
 	if s.Def == nil {
 		Yyerror("unknown export symbol: %v", s)
@@ -356,17 +364,17 @@ func dumpsym(s *Sym) {
 	default:
 		Yyerror("unexpected export symbol: %v %v", Oconv(s.Def.Op, 0), s)
 
-	case OLITERAL:
+	case ir.OLITERAL:
 		dumpexportconst(s)
 
-	case OTYPE:
-		if s.Def.Type.Etype == TFORW {
+	case ir.OTYPE:
+		if s.Def.Type.Etype == ir.TFORW {
 			Yyerror("export of incomplete type %v", s)
 		} else {
 			dumpexporttype(s.Def.Type)
 		}
 
-	case ONAME:
+	case ir.ONAME:
 		dumpexportvar(s)
 	}
 }
@@ -375,6 +383,18 @@ func dumpexport() {
 	if buildid != "" {
 		exportf("build id %q\n", buildid)
 	}
+	exportf("content id %q\n", contentid())
+
+	// record the import paths rewritten by -importmap so that tools
+	// can relate the imports in the source to the packages used
+	var sources []string
+	for source := range importMapped {
+		sources = append(sources, source)
+	}
+	sort.Strings(sources)
+	for _, source := range sources {
+		exportf("importmap %q=%q\n", source, importMapped[source])
+	}
 
 	size := 0 // size of export section without enclosing markers
 	if forceNewExport || newexport != 0 {
@@ -382,33 +402,39 @@ func dumpexport() {
 		// The linker also looks for the $$ marker - use char after $$ to distinguish format.
 		exportf("\n$$B\n")        // indicate binary format
 		const verifyExport = true // enable to check format changes
+		var copy bytes.Buffer
+		bcopy := obj.Binitw(&copy)
+		size = Export(bcopy, Debug_export != 0)
+		bcopy.Flush() // flushing to bytes.Buffer cannot fail
+		data := copy.Bytes()
+		if compressexport != 0 {
+			data = compress(data)
+			size = len(data)
+		}
+		if n, err := bout.Write(data); n != size || err != nil {
+			Fatalf("error writing export data: got %d bytes, want %d bytes, err = %v", n, size, err)
+		}
 		if verifyExport {
-			// save a copy of the export data
-			var copy bytes.Buffer
-			bcopy := obj.Binitw(&copy)
-			size = Export(bcopy, Debug_export != 0)
-			bcopy.Flush() // flushing to bytes.Buffer cannot fail
-			if n, err := bout.Write(copy.Bytes()); n != size || err != nil {
-				Fatalf("error writing export data: got %d bytes, want %d bytes, err = %v", n, size, err)
-			}
 			// export data must contain no '$' so that we can find the end by searching for "$$"
-			if bytes.IndexByte(copy.Bytes(), '$') >= 0 {
+			if bytes.IndexByte(data, '$') >= 0 {
 				Fatalf("export data contains $")
 			}
 
-			// verify that we can read the copied export data back in
+			// verify that we can read the export data back in
 			// (use empty package map to avoid collisions)
 			savedPkgMap := pkgMap
 			savedPkgs := pkgs
-			pkgMap = make(map[string]*Pkg)
+			savedDeclSrc := declSrc
+			pkgMap = make(map[string]*ir.Pkg)
 			pkgs = nil
+			declSrc = make(map[*ir.Sym]declRef)
 			importpkg = mkpkg("")
-			Import(bufio.NewReader(&copy)) // must not die
+			Import(bufio.NewReader(bytes.NewReader(data))) // must not die
+			expandPkg(importpkg)
 			importpkg = nil
 			pkgs = savedPkgs
 			pkgMap = savedPkgMap
-		} else {
-			size = Export(bout, Debug_export != 0)
+			declSrc = savedDeclSrc
 		}
 		exportf("\n$$\n")
 	} else {
@@ -450,7 +476,7 @@ func dumpexport() {
 // import
 
 // return the sym for ss, which should match lexical
-func importsym(s *Sym, op Op) *Sym {
+func importsym(s *ir.Sym, op ir.Op) *ir.Sym {
 	if s.Def != nil && s.Def.Op != op {
 		pkgstr := fmt.Sprintf("during import %q", importpkg.Path)
 		redeclare(s, pkgstr)
@@ -459,9 +485,9 @@ func importsym(s *Sym, op Op) *Sym {
 	// mark the symbol so it is not reexported
 	if s.Def == nil {
 		if Debug['A'] != 0 || exportname(s.Name) || initname(s.Name) {
-			s.Flags |= SymExport
+			s.Flags |= ir.SymExport
 		} else {
-			s.Flags |= SymPackage // package scope
+			s.Flags |= ir.SymPackage // package scope
 		}
 	}
 
@@ -469,13 +495,13 @@ func importsym(s *Sym, op Op) *Sym {
 }
 
 // return the type pkg.name, forward declaring if needed
-func pkgtype(s *Sym) *Type {
-	importsym(s, OTYPE)
-	if s.Def == nil || s.Def.Op != OTYPE {
-		t := typ(TFORW)
+func pkgtype(s *ir.Sym) *ir.Type {
+	importsym(s, ir.OTYPE)
+	if s.Def == nil || s.Def.Op != ir.OTYPE {
+		t := ir.Typ(ir.TFORW)
 		t.Sym = s
 		s.Def = typenod(t)
-		s.Def.Name = new(Name)
+		s.Def.Name = new(ir.Name)
 	}
 
 	if s.Def.Type == nil {
@@ -486,7 +512,7 @@ func pkgtype(s *Sym) *Type {
 
 var numImport = make(map[string]int)
 
-func importimport(s *Sym, path string) {
+func importimport(s *ir.Sym, path string) {
 	// Informational: record package name
 	// associated with import path, for use in
 	// human-readable messages.
@@ -508,15 +534,15 @@ func importimport(s *Sym, path string) {
 	}
 }
 
-func importconst(s *Sym, t *Type, n *Node) {
-	importsym(s, OLITERAL)
+func importconst(s *ir.Sym, t *ir.Type, n *ir.Node) {
+	importsym(s, ir.OLITERAL)
 	n = convlit(n, t)
 
 	if s.Def != nil { // TODO: check if already the same.
 		return
 	}
 
-	if n.Op != OLITERAL {
+	if n.Op != ir.OLITERAL {
 		Yyerror("expression must be a constant")
 		return
 	}
@@ -528,16 +554,16 @@ func importconst(s *Sym, t *Type, n *Node) {
 
 	n.Orig = newname(s)
 	n.Sym = s
-	declare(n, PEXTERN)
+	declare(n, ir.PEXTERN)
 
 	if Debug['E'] != 0 {
 		fmt.Printf("import const %v\n", s)
 	}
 }
 
-func importvar(s *Sym, t *Type) {
-	importsym(s, ONAME)
-	if s.Def != nil && s.Def.Op == ONAME {
+func importvar(s *ir.Sym, t *ir.Type) {
+	importsym(s, ir.ONAME)
+	if s.Def != nil && s.Def.Op == ir.ONAME {
 		if Eqtype(t, s.Def.Type) {
 			return
 		}
@@ -547,35 +573,35 @@ func importvar(s *Sym, t *Type) {
 	n := newname(s)
 	s.Importdef = importpkg
 	n.Type = t
-	declare(n, PEXTERN)
+	declare(n, ir.PEXTERN)
 
 	if Debug['E'] != 0 {
-		fmt.Printf("import var %v %v\n", s, Tconv(t, FmtLong))
+		fmt.Printf("import var %v %v\n", s, Tconv(t, ir.FmtLong))
 	}
 }
 
-func importtype(pt *Type, t *Type) {
+func importtype(pt *ir.Type, t *ir.Type) {
 	// override declaration in unsafe.go for Pointer.
 	// there is no way in Go code to define unsafe.Pointer
 	// so we have to supply it.
 	if incannedimport != 0 && importpkg.Name == "unsafe" && pt.Nod.Sym.Name == "Pointer" {
-		t = Types[TUNSAFEPTR]
+		t = ir.Types[ir.TUNSAFEPTR]
 	}
 
-	if pt.Etype == TFORW {
+	if pt.Etype == ir.TFORW {
 		n := pt.Nod
 		copytype(pt.Nod, t)
 		pt.Nod = n // unzero nod
 		pt.Sym.Importdef = importpkg
 		pt.Sym.Lastlineno = lineno
-		declare(n, PEXTERN)
+		declare(n, ir.PEXTERN)
 		checkwidth(pt)
 	} else if !Eqtype(pt.Orig, t) {
-		Yyerror("inconsistent definition for type %v during import\n\t%v (in %q)\n\t%v (in %q)", pt.Sym, Tconv(pt, FmtLong), pt.Sym.Importdef.Path, Tconv(t, FmtLong), importpkg.Path)
+		Yyerror("inconsistent definition for type %v during import\n\t%v (in %q)\n\t%v (in %q)", pt.Sym, Tconv(pt, ir.FmtLong), pt.Sym.Importdef.Path, Tconv(t, ir.FmtLong), importpkg.Path)
 	}
 
 	if Debug['E'] != 0 {
-		fmt.Printf("import type %v %v\n", pt, Tconv(t, FmtLong))
+		fmt.Printf("import type %v %v\n", pt, Tconv(t, ir.FmtLong))
 	}
 }
 
@@ -590,12 +616,12 @@ func dumpasmhdr() {
 			continue
 		}
 		switch n.Op {
-		case OLITERAL:
-			fmt.Fprintf(b, "#define const_%s %v\n", n.Sym.Name, Vconv(n.Val(), FmtSharp))
+		case ir.OLITERAL:
+			fmt.Fprintf(b, "#define const_%s %v\n", n.Sym.Name, Vconv(n.Val(), ir.FmtSharp))
 
-		case OTYPE:
+		case ir.OTYPE:
 			t := n.Type
-			if t.Etype != TSTRUCT || t.Map != nil || t.Funarg {
+			if t.Etype != ir.TSTRUCT || t.Map != nil || t.Funarg {
 				break
 			}
 			fmt.Fprintf(b, "#define %s__size %d\n", t.Sym.Name, int(t.Width))
@@ -604,8 +630,35 @@ func dumpasmhdr() {
 					fmt.Fprintf(b, "#define %s_%s %d\n", n.Sym.Name, t.Sym.Name, int(t.Width))
 				}
 			}
+
+		case ir.ONAME:
+			// Describe the argument frame of functions implemented
+			// in assembly, so that the assembly can be checked
+			// against the Go declaration.
+			if n.Class != ir.PFUNC || n.Name.Defn == nil || n.Name.Defn.Nbody.Len() != 0 || n.Name.Defn.Func.Released {
+				break
+			}
+			t := n.Type
+			dowidth(t)
+			fmt.Fprintf(b, "#define %s__args %d\n", n.Sym.Name, int(t.Argwid))
+			asmhdrargs(b, n.Sym.Name, "arg", t.Params())
+			asmhdrargs(b, n.Sym.Name, "ret", t.Results())
 		}
 	}
 
 	obj.Bterm(b)
 }
+
+// asmhdrargs writes the frame offset and size of each field of the
+// parameter or result list t of function fn. Fields without a usable
+// name are numbered by position.
+func asmhdrargs(b *obj.Biobuf, fn, kind string, t *ir.Type) {
+	for i, f := range t.Fields().Slice() {
+		name := fmt.Sprintf("%s%d", kind, i)
+		if f.Sym != nil && !isblanksym(f.Sym) && !strings.HasPrefix(f.Sym.Name, "~") {
+			name = kind + "_" + f.Sym.Name
+		}
+		fmt.Fprintf(b, "#define %s_%s %d\n", fn, name, int(f.Width))
+		fmt.Fprintf(b, "#define %s_%s__size %d\n", fn, name, int(f.Type.Width))
+	}
+}
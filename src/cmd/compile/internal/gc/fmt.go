@@ -6,6 +6,7 @@ package gc
 
 import (
 	"bytes"
+	"cmd/compile/internal/ir"
 	"cmd/internal/obj"
 	"fmt"
 	"strconv"
@@ -13,24 +14,6 @@ import (
 	"unicode/utf8"
 )
 
-// A FmtFlag value is a set of flags (or 0).
-// They control how the Xconv functions format their values.
-// See the respective function's documentation for details.
-type FmtFlag int
-
-const (
-	FmtWidth    FmtFlag = 1 << iota
-	FmtLeft             // "-"
-	FmtSharp            // "#"
-	FmtSign             // "+"
-	FmtUnsigned         // "u"
-	FmtShort            // "h"
-	FmtLong             // "l"
-	FmtComma            // ","
-	FmtByte             // "hh"
-	FmtBody             // for printing export bodies
-)
-
 //
 // Format conversions
 //	%L int		Line numbers
@@ -107,93 +90,93 @@ var fmtbody bool
 //	%-uT		type identifiers with package name instead of prefix (typesym, dcommontype, typehash)
 //
 
-func setfmode(flags *FmtFlag) (fm int, fb bool) {
+func setfmode(flags *ir.FmtFlag) (fm int, fb bool) {
 	fm = fmtmode
 	fb = fmtbody
-	if *flags&FmtSign != 0 {
+	if *flags&ir.FmtSign != 0 {
 		fmtmode = FDbg
-	} else if *flags&FmtSharp != 0 {
+	} else if *flags&ir.FmtSharp != 0 {
 		fmtmode = FExp
-	} else if *flags&FmtLeft != 0 {
+	} else if *flags&ir.FmtLeft != 0 {
 		fmtmode = FTypeId
 	}
 
-	if *flags&FmtBody != 0 {
+	if *flags&ir.FmtBody != 0 {
 		fmtbody = true
 	}
 
-	*flags &^= (FmtSharp | FmtLeft | FmtSign | FmtBody)
+	*flags &^= (ir.FmtSharp | ir.FmtLeft | ir.FmtSign | ir.FmtBody)
 	return
 }
 
 // Fmt "%L": Linenumbers
 
 var goopnames = []string{
-	OADDR:     "&",
-	OADD:      "+",
-	OADDSTR:   "+",
-	OANDAND:   "&&",
-	OANDNOT:   "&^",
-	OAND:      "&",
-	OAPPEND:   "append",
-	OAS:       "=",
-	OAS2:      "=",
-	OBREAK:    "break",
-	OCALL:     "function call", // not actual syntax
-	OCAP:      "cap",
-	OCASE:     "case",
-	OCLOSE:    "close",
-	OCOMPLEX:  "complex",
-	OCOM:      "^",
-	OCONTINUE: "continue",
-	OCOPY:     "copy",
-	ODEC:      "--",
-	ODELETE:   "delete",
-	ODEFER:    "defer",
-	ODIV:      "/",
-	OEQ:       "==",
-	OFALL:     "fallthrough",
-	OFOR:      "for",
-	OGE:       ">=",
-	OGOTO:     "goto",
-	OGT:       ">",
-	OIF:       "if",
-	OIMAG:     "imag",
-	OINC:      "++",
-	OIND:      "*",
-	OLEN:      "len",
-	OLE:       "<=",
-	OLSH:      "<<",
-	OLT:       "<",
-	OMAKE:     "make",
-	OMINUS:    "-",
-	OMOD:      "%",
-	OMUL:      "*",
-	ONEW:      "new",
-	ONE:       "!=",
-	ONOT:      "!",
-	OOROR:     "||",
-	OOR:       "|",
-	OPANIC:    "panic",
-	OPLUS:     "+",
-	OPRINTN:   "println",
-	OPRINT:    "print",
-	ORANGE:    "range",
-	OREAL:     "real",
-	ORECV:     "<-",
-	ORECOVER:  "recover",
-	ORETURN:   "return",
-	ORSH:      ">>",
-	OSELECT:   "select",
-	OSEND:     "<-",
-	OSUB:      "-",
-	OSWITCH:   "switch",
-	OXOR:      "^",
+	ir.OADDR:     "&",
+	ir.OADD:      "+",
+	ir.OADDSTR:   "+",
+	ir.OANDAND:   "&&",
+	ir.OANDNOT:   "&^",
+	ir.OAND:      "&",
+	ir.OAPPEND:   "append",
+	ir.OAS:       "=",
+	ir.OAS2:      "=",
+	ir.OBREAK:    "break",
+	ir.OCALL:     "function call", // not actual syntax
+	ir.OCAP:      "cap",
+	ir.OCASE:     "case",
+	ir.OCLOSE:    "close",
+	ir.OCOMPLEX:  "complex",
+	ir.OCOM:      "^",
+	ir.OCONTINUE: "continue",
+	ir.OCOPY:     "copy",
+	ir.ODEC:      "--",
+	ir.ODELETE:   "delete",
+	ir.ODEFER:    "defer",
+	ir.ODIV:      "/",
+	ir.OEQ:       "==",
+	ir.OFALL:     "fallthrough",
+	ir.OFOR:      "for",
+	ir.OGE:       ">=",
+	ir.OGOTO:     "goto",
+	ir.OGT:       ">",
+	ir.OIF:       "if",
+	ir.OIMAG:     "imag",
+	ir.OINC:      "++",
+	ir.OIND:      "*",
+	ir.OLEN:      "len",
+	ir.OLE:       "<=",
+	ir.OLSH:      "<<",
+	ir.OLT:       "<",
+	ir.OMAKE:     "make",
+	ir.OMINUS:    "-",
+	ir.OMOD:      "%",
+	ir.OMUL:      "*",
+	ir.ONEW:      "new",
+	ir.ONE:       "!=",
+	ir.ONOT:      "!",
+	ir.OOROR:     "||",
+	ir.OOR:       "|",
+	ir.OPANIC:    "panic",
+	ir.OPLUS:     "+",
+	ir.OPRINTN:   "println",
+	ir.OPRINT:    "print",
+	ir.ORANGE:    "range",
+	ir.OREAL:     "real",
+	ir.ORECV:     "<-",
+	ir.ORECOVER:  "recover",
+	ir.ORETURN:   "return",
+	ir.ORSH:      ">>",
+	ir.OSELECT:   "select",
+	ir.OSEND:     "<-",
+	ir.OSUB:      "-",
+	ir.OSWITCH:   "switch",
+	ir.OXOR:      "^",
 }
 
 // Fmt "%O":  Node opcodes
-func Oconv(o Op, flag FmtFlag) string {
-	if (flag&FmtSharp != 0) || fmtmode != FDbg {
+func Oconv(o ir.Op, flag ir.FmtFlag) string {
+	if (flag&ir.FmtSharp != 0) || fmtmode != FDbg {
 		if o >= 0 && int(o) < len(goopnames) && goopnames[o] != "" {
 			return goopnames[o]
 		}
@@ -217,10 +200,10 @@ var classnames = []string{
 }
 
 // Fmt "%J": Node details.
-func Jconv(n *Node, flag FmtFlag) string {
+func Jconv(n *ir.Node, flag ir.FmtFlag) string {
 	var buf bytes.Buffer
 
-	c := flag & FmtShort
+	c := flag & ir.FmtShort
 
 	if c == 0 && n.Ullman != 0 {
 		fmt.Fprintf(&buf, " u(%d)", n.Ullman)
@@ -238,19 +221,19 @@ func Jconv(n *Node, flag FmtFlag) string {
 		fmt.Fprintf(&buf, " l(%d)", n.Lineno)
 	}
 
-	if c == 0 && n.Xoffset != BADWIDTH {
+	if c == 0 && n.Xoffset != ir.BADWIDTH {
 		fmt.Fprintf(&buf, " x(%d%+d)", n.Xoffset, stkdelta[n])
 	}
 
 	if n.Class != 0 {
 		s := ""
-		if n.Class&PHEAP != 0 {
+		if n.Class&ir.PHEAP != 0 {
 			s = ",heap"
 		}
-		if int(n.Class&^PHEAP) < len(classnames) {
-			fmt.Fprintf(&buf, " class(%s%s)", classnames[n.Class&^PHEAP], s)
+		if int(n.Class&^ir.PHEAP) < len(classnames) {
+			fmt.Fprintf(&buf, " class(%s%s)", classnames[n.Class&^ir.PHEAP], s)
 		} else {
-			fmt.Fprintf(&buf, " class(%d?%s)", n.Class&^PHEAP, s)
+			fmt.Fprintf(&buf, " class(%d?%s)", n.Class&^ir.PHEAP, s)
 		}
 	}
 
@@ -326,16 +309,16 @@ func Jconv(n *Node, flag FmtFlag) string {
 }
 
 // Fmt "%V": Values
-func Vconv(v Val, flag FmtFlag) string {
+func Vconv(v ir.Val, flag ir.FmtFlag) string {
 	switch v.Ctype() {
-	case CTINT:
-		if (flag&FmtSharp != 0) || fmtmode == FExp {
-			return Bconv(v.U.(*Mpint), FmtSharp)
+	case ir.CTINT:
+		if (flag&ir.FmtSharp != 0) || fmtmode == FExp {
+			return ir.Bconv(v.U.(*ir.Mpint), ir.FmtSharp)
 		}
-		return Bconv(v.U.(*Mpint), 0)
+		return ir.Bconv(v.U.(*ir.Mpint), 0)
 
-	case CTRUNE:
-		x := v.U.(*Mpint).Int64()
+	case ir.CTRUNE:
+		x := v.U.(*ir.Mpint).Int64()
 		if ' ' <= x && x < 0x80 && x != '\\' && x != '\'' {
 			return fmt.Sprintf("'%c'", int(x))
 		}
@@ -345,96 +328,48 @@ func Vconv(v Val, flag FmtFlag) string {
 		if 0 <= x && x <= utf8.MaxRune {
 			return fmt.Sprintf("'\\U%08x'", uint64(x))
 		}
-		return fmt.Sprintf("('\\x00' + %v)", v.U.(*Mpint))
+		return fmt.Sprintf("('\\x00' + %v)", v.U.(*ir.Mpint))
 
-	case CTFLT:
-		if (flag&FmtSharp != 0) || fmtmode == FExp {
-			return Fconv(v.U.(*Mpflt), 0)
+	case ir.CTFLT:
+		if (flag&ir.FmtSharp != 0) || fmtmode == FExp {
+			return ir.Fconv(v.U.(*ir.Mpflt), 0)
 		}
-		return Fconv(v.U.(*Mpflt), FmtSharp)
+		return ir.Fconv(v.U.(*ir.Mpflt), ir.FmtSharp)
 
-	case CTCPLX:
-		if (flag&FmtSharp != 0) || fmtmode == FExp {
-			return fmt.Sprintf("(%v+%vi)", &v.U.(*Mpcplx).Real, &v.U.(*Mpcplx).Imag)
+	case ir.CTCPLX:
+		if (flag&ir.FmtSharp != 0) || fmtmode == FExp {
+			return fmt.Sprintf("(%v+%vi)", &v.U.(*ir.Mpcplx).Real, &v.U.(*ir.Mpcplx).Imag)
 		}
-		if v.U.(*Mpcplx).Real.CmpFloat64(0) == 0 {
-			return fmt.Sprintf("%vi", Fconv(&v.U.(*Mpcplx).Imag, FmtSharp))
+		if v.U.(*ir.Mpcplx).Real.CmpFloat64(0) == 0 {
+			return fmt.Sprintf("%vi", ir.Fconv(&v.U.(*ir.Mpcplx).Imag, ir.FmtSharp))
 		}
-		if v.U.(*Mpcplx).Imag.CmpFloat64(0) == 0 {
-			return Fconv(&v.U.(*Mpcplx).Real, FmtSharp)
+		if v.U.(*ir.Mpcplx).Imag.CmpFloat64(0) == 0 {
+			return ir.Fconv(&v.U.(*ir.Mpcplx).Real, ir.FmtSharp)
 		}
-		if v.U.(*Mpcplx).Imag.CmpFloat64(0) < 0 {
-			return fmt.Sprintf("(%v%vi)", Fconv(&v.U.(*Mpcplx).Real, FmtSharp), Fconv(&v.U.(*Mpcplx).Imag, FmtSharp))
+		if v.U.(*ir.Mpcplx).Imag.CmpFloat64(0) < 0 {
+			return fmt.Sprintf("(%v%vi)", ir.Fconv(&v.U.(*ir.Mpcplx).Real, ir.FmtSharp), ir.Fconv(&v.U.(*ir.Mpcplx).Imag, ir.FmtSharp))
 		}
-		return fmt.Sprintf("(%v+%vi)", Fconv(&v.U.(*Mpcplx).Real, FmtSharp), Fconv(&v.U.(*Mpcplx).Imag, FmtSharp))
+		return fmt.Sprintf("(%v+%vi)", ir.Fconv(&v.U.(*ir.Mpcplx).Real, ir.FmtSharp), ir.Fconv(&v.U.(*ir.Mpcplx).Imag, ir.FmtSharp))
 
-	case CTSTR:
+	case ir.CTSTR:
 		return strconv.Quote(v.U.(string))
 
-	case CTBOOL:
+	case ir.CTBOOL:
 		if v.U.(bool) {
 			return "true"
 		}
 		return "false"
 
-	case CTNIL:
+	case ir.CTNIL:
 		return "nil"
 	}
 
 	return fmt.Sprintf("<ctype=%d>", v.Ctype())
 }
 
-/*
-s%,%,\n%g
-s%\n+%\n%g
-s%^[	]*T%%g
-s%,.*%%g
-s%.+%	[T&]		= "&",%g
-s%^	........*\]%&~%g
-s%~	%%g
-*/
-var etnames = []string{
-	TINT:        "INT",
-	TUINT:       "UINT",
-	TINT8:       "INT8",
-	TUINT8:      "UINT8",
-	TINT16:      "INT16",
-	TUINT16:     "UINT16",
-	TINT32:      "INT32",
-	TUINT32:     "UINT32",
-	TINT64:      "INT64",
-	TUINT64:     "UINT64",
-	TUINTPTR:    "UINTPTR",
-	TFLOAT32:    "FLOAT32",
-	TFLOAT64:    "FLOAT64",
-	TCOMPLEX64:  "COMPLEX64",
-	TCOMPLEX128: "COMPLEX128",
-	TBOOL:       "BOOL",
-	TPTR32:      "PTR32",
-	TPTR64:      "PTR64",
-	TFUNC:       "FUNC",
-	TARRAY:      "ARRAY",
-	TSTRUCT:     "STRUCT",
-	TCHAN:       "CHAN",
-	TMAP:        "MAP",
-	TINTER:      "INTER",
-	TFORW:       "FORW",
-	TSTRING:     "STRING",
-	TUNSAFEPTR:  "TUNSAFEPTR",
-	TANY:        "ANY",
-}
-
-// Fmt "%E": etype
-func Econv(et EType) string {
-	if int(et) < len(etnames) && etnames[et] != "" {
-		return etnames[et]
-	}
-	return fmt.Sprintf("E-%d", et)
-}
-
 // Fmt "%S": syms
-func symfmt(s *Sym, flag FmtFlag) string {
-	if s.Pkg != nil && flag&FmtShort == 0 {
+func symfmt(s *ir.Sym, flag ir.FmtFlag) string {
+	if s.Pkg != nil && flag&ir.FmtShort == 0 {
 		switch fmtmode {
 		case FErr: // This is for the user
 			if s.Pkg == builtinpkg || s.Pkg == localpkg {
@@ -451,7 +386,7 @@ func symfmt(s *Sym, flag FmtFlag) string {
 			return s.Pkg.Name + "." + s.Name
 
 		case FTypeId:
-			if flag&FmtUnsigned != 0 {
+			if flag&ir.FmtUnsigned != 0 {
 				return s.Pkg.Name + "." + s.Name // dcommontype, typehash
 			}
 			return s.Pkg.Prefix + "." + s.Name // (methodsym), typesym, weaksym
@@ -466,7 +401,7 @@ func symfmt(s *Sym, flag FmtFlag) string {
 		}
 	}
 
-	if flag&FmtByte != 0 {
+	if flag&ir.FmtByte != 0 {
 		// FmtByte (hh) implies FmtShort (h)
 		// skip leading "type." in method name
 		p := s.Name
@@ -486,59 +421,59 @@ func symfmt(s *Sym, flag FmtFlag) string {
 }
 
 var basicnames = []string{
-	TINT:        "int",
-	TUINT:       "uint",
-	TINT8:       "int8",
-	TUINT8:      "uint8",
-	TINT16:      "int16",
-	TUINT16:     "uint16",
-	TINT32:      "int32",
-	TUINT32:     "uint32",
-	TINT64:      "int64",
-	TUINT64:     "uint64",
-	TUINTPTR:    "uintptr",
-	TFLOAT32:    "float32",
-	TFLOAT64:    "float64",
-	TCOMPLEX64:  "complex64",
-	TCOMPLEX128: "complex128",
-	TBOOL:       "bool",
-	TANY:        "any",
-	TSTRING:     "string",
-	TNIL:        "nil",
-	TIDEAL:      "untyped number",
-	TBLANK:      "blank",
+	ir.TINT:        "int",
+	ir.TUINT:       "uint",
+	ir.TINT8:       "int8",
+	ir.TUINT8:      "uint8",
+	ir.TINT16:      "int16",
+	ir.TUINT16:     "uint16",
+	ir.TINT32:      "int32",
+	ir.TUINT32:     "uint32",
+	ir.TINT64:      "int64",
+	ir.TUINT64:     "uint64",
+	ir.TUINTPTR:    "uintptr",
+	ir.TFLOAT32:    "float32",
+	ir.TFLOAT64:    "float64",
+	ir.TCOMPLEX64:  "complex64",
+	ir.TCOMPLEX128: "complex128",
+	ir.TBOOL:       "bool",
+	ir.TANY:        "any",
+	ir.TSTRING:     "string",
+	ir.TNIL:        "nil",
+	ir.TIDEAL:      "untyped number",
+	ir.TBLANK:      "blank",
 }
 
-func typefmt(t *Type, flag FmtFlag) string {
+func typefmt(t *ir.Type, flag ir.FmtFlag) string {
 	if t == nil {
 		return "<T>"
 	}
 
-	if t == bytetype || t == runetype {
+	if t == ir.Bytetype || t == ir.Runetype {
 		// in %-T mode collapse rune and byte with their originals.
 		if fmtmode != FTypeId {
-			return Sconv(t.Sym, FmtShort)
+			return Sconv(t.Sym, ir.FmtShort)
 		}
-		t = Types[t.Etype]
+		t = ir.Types[t.Etype]
 	}
 
-	if t == errortype {
+	if t == ir.Errortype {
 		return "error"
 	}
 
 	// Unless the 'l' flag was specified, if the type has a name, just print that name.
-	if flag&FmtLong == 0 && t.Sym != nil && t != Types[t.Etype] {
+	if flag&ir.FmtLong == 0 && t.Sym != nil && t != ir.Types[t.Etype] {
 		switch fmtmode {
 		case FTypeId:
-			if flag&FmtShort != 0 {
+			if flag&ir.FmtShort != 0 {
 				if t.Vargen != 0 {
-					return fmt.Sprintf("%v·%d", Sconv(t.Sym, FmtShort), t.Vargen)
+					return fmt.Sprintf("%v·%d", Sconv(t.Sym, ir.FmtShort), t.Vargen)
 				}
-				return Sconv(t.Sym, FmtShort)
+				return Sconv(t.Sym, ir.FmtShort)
 			}
 
-			if flag&FmtUnsigned != 0 {
-				return Sconv(t.Sym, FmtUnsigned)
+			if flag&ir.FmtUnsigned != 0 {
+				return Sconv(t.Sym, ir.FmtUnsigned)
 			}
 			fallthrough
 
@@ -553,7 +488,7 @@ func typefmt(t *Type, flag FmtFlag) string {
 
 	if int(t.Etype) < len(basicnames) && basicnames[t.Etype] != "" {
 		prefix := ""
-		if fmtmode == FErr && (t == idealbool || t == idealstring) {
+		if fmtmode == FErr && (t == ir.Idealbool || t == ir.Idealstring) {
 			prefix = "untyped "
 		}
 		return prefix + basicnames[t.Etype]
@@ -561,19 +496,19 @@ func typefmt(t *Type, flag FmtFlag) string {
 
 	if fmtmode == FDbg {
 		fmtmode = 0
-		str := Econv(t.Etype) + "-" + typefmt(t, flag)
+		str := ir.Econv(t.Etype) + "-" + typefmt(t, flag)
 		fmtmode = FDbg
 		return str
 	}
 
 	switch t.Etype {
-	case TPTR32, TPTR64:
-		if fmtmode == FTypeId && (flag&FmtShort != 0) {
-			return "*" + Tconv(t.Type, FmtShort)
+	case ir.TPTR32, ir.TPTR64:
+		if fmtmode == FTypeId && (flag&ir.FmtShort != 0) {
+			return "*" + Tconv(t.Type, ir.FmtShort)
 		}
 		return "*" + t.Type.String()
 
-	case TARRAY:
+	case ir.TARRAY:
 		if t.Bound >= 0 {
 			return fmt.Sprintf("[%d]%v", t.Bound, t.Type)
 		}
@@ -582,7 +517,7 @@ func typefmt(t *Type, flag FmtFlag) string {
 		}
 		return "[]" + t.Type.String()
 
-	case TCHAN:
+	case ir.TCHAN:
 		switch t.Chan {
 		case Crecv:
 			return "<-chan " + t.Type.String()
@@ -591,15 +526,15 @@ func typefmt(t *Type, flag FmtFlag) string {
 			return "chan<- " + t.Type.String()
 		}
 
-		if t.Type != nil && t.Type.Etype == TCHAN && t.Type.Sym == nil && t.Type.Chan == Crecv {
+		if t.Type != nil && t.Type.Etype == ir.TCHAN && t.Type.Sym == nil && t.Type.Chan == Crecv {
 			return "chan (" + t.Type.String() + ")"
 		}
 		return "chan " + t.Type.String()
 
-	case TMAP:
+	case ir.TMAP:
 		return "map[" + t.Key().String() + "]" + t.Type.String()
 
-	case TINTER:
+	case ir.TINTER:
 		var buf bytes.Buffer
 		buf.WriteString("interface {")
 		for i, f := range t.Fields().Slice() {
@@ -613,11 +548,11 @@ func typefmt(t *Type, flag FmtFlag) string {
 				// Wrong interface definitions may have types lacking a symbol.
 				break
 			case exportname(f.Sym.Name):
-				buf.WriteString(Sconv(f.Sym, FmtShort))
+				buf.WriteString(Sconv(f.Sym, ir.FmtShort))
 			default:
-				buf.WriteString(Sconv(f.Sym, FmtUnsigned))
+				buf.WriteString(Sconv(f.Sym, ir.FmtUnsigned))
 			}
-			buf.WriteString(Tconv(f.Type, FmtShort))
+			buf.WriteString(Tconv(f.Type, ir.FmtShort))
 		}
 		if t.NumFields() != 0 {
 			buf.WriteString(" ")
@@ -625,9 +560,9 @@ func typefmt(t *Type, flag FmtFlag) string {
 		buf.WriteString("}")
 		return buf.String()
 
-	case TFUNC:
+	case ir.TFUNC:
 		var buf bytes.Buffer
-		if flag&FmtShort != 0 {
+		if flag&ir.FmtShort != 0 {
 			// no leading func
 		} else {
 			if t.Recv() != nil {
@@ -657,7 +592,7 @@ func typefmt(t *Type, flag FmtFlag) string {
 		}
 		return buf.String()
 
-	case TSTRUCT:
+	case ir.TSTRUCT:
 		if t.Map != nil {
 			// Format the bucket struct for map[x]y as map.bucket[x]y.
 			// This avoids a recursive print that generates very long names.
@@ -679,9 +614,9 @@ func typefmt(t *Type, flag FmtFlag) string {
 		var buf bytes.Buffer
 		if t.Funarg {
 			buf.WriteString("(")
-			var flag1 FmtFlag
+			var flag1 ir.FmtFlag
 			if fmtmode == FTypeId || fmtmode == FErr { // no argument names on function signature, and no "noescape"/"nosplit" tags
-				flag1 = FmtShort
+				flag1 = ir.FmtShort
 			}
 			for i, f := range t.Fields().Slice() {
 				if i != 0 {
@@ -697,7 +632,7 @@ func typefmt(t *Type, flag FmtFlag) string {
 					buf.WriteString(";")
 				}
 				buf.WriteString(" ")
-				buf.WriteString(Fldconv(f, FmtLong))
+				buf.WriteString(Fldconv(f, ir.FmtLong))
 			}
 			if t.NumFields() != 0 {
 				buf.WriteString(" ")
@@ -706,13 +641,13 @@ func typefmt(t *Type, flag FmtFlag) string {
 		}
 		return buf.String()
 
-	case TFORW:
+	case ir.TFORW:
 		if t.Sym != nil {
 			return "undefined " + t.Sym.String()
 		}
 		return "undefined"
 
-	case TUNSAFEPTR:
+	case ir.TUNSAFEPTR:
 		if fmtmode == FExp {
 			return "@\"unsafe\".Pointer"
 		}
@@ -720,24 +655,24 @@ func typefmt(t *Type, flag FmtFlag) string {
 	}
 
 	if fmtmode == FExp {
-		Fatalf("missing %v case during export", Econv(t.Etype))
+		Fatalf("missing %v case during export", ir.Econv(t.Etype))
 	}
 
 	// Don't know how to handle - fall back to detailed prints.
-	return fmt.Sprintf("%v <%v> %v", Econv(t.Etype), t.Sym, t.Type)
+	return fmt.Sprintf("%v <%v> %v", ir.Econv(t.Etype), t.Sym, t.Type)
 }
 
 // Statements which may be rendered with a simplestmt as init.
-func stmtwithinit(op Op) bool {
+func stmtwithinit(op ir.Op) bool {
 	switch op {
-	case OIF, OFOR, OSWITCH:
+	case ir.OIF, ir.OFOR, ir.OSWITCH:
 		return true
 	}
 
 	return false
 }
 
-func stmtfmt(n *Node) string {
+func stmtfmt(n *ir.Node) string {
 	var f string
 
 	// some statements allow for an init, but at most one,
@@ -763,10 +698,10 @@ func stmtfmt(n *Node) string {
 	}
 
 	switch n.Op {
-	case ODCL:
+	case ir.ODCL:
 		if fmtmode == FExp {
-			switch n.Left.Class &^ PHEAP {
-			case PPARAM, PPARAMOUT, PAUTO:
+			switch n.Left.Class &^ ir.PHEAP {
+			case ir.PPARAM, ir.PPARAMOUT, ir.PAUTO:
 				f += fmt.Sprintf("var %v %v", n.Left, n.Left.Type)
 				goto ret
 			}
@@ -774,7 +709,7 @@ func stmtfmt(n *Node) string {
 
 		f += fmt.Sprintf("var %v %v", n.Left.Sym, n.Left.Type)
 
-	case ODCLFIELD:
+	case ir.ODCLFIELD:
 		if n.Left != nil {
 			f += fmt.Sprintf("%v %v", n.Left, n.Right)
 		} else {
@@ -784,7 +719,7 @@ func stmtfmt(n *Node) string {
 	// Don't export "v = <N>" initializing statements, hope they're always
 	// preceded by the DCL which will be re-parsed and typecheck to reproduce
 	// the "v = <N>" again.
-	case OAS, OASWB:
+	case ir.OAS, ir.OASWB:
 		if fmtmode == FExp && n.Right == nil {
 			break
 		}
@@ -795,9 +730,9 @@ func stmtfmt(n *Node) string {
 			f += fmt.Sprintf("%v = %v", n.Left, n.Right)
 		}
 
-	case OASOP:
+	case ir.OASOP:
 		if n.Implicit {
-			if Op(n.Etype) == OADD {
+			if ir.Op(n.Etype) == ir.OADD {
 				f += fmt.Sprintf("%v++", n.Left)
 			} else {
 				f += fmt.Sprintf("%v--", n.Left)
@@ -805,31 +740,31 @@ func stmtfmt(n *Node) string {
 			break
 		}
 
-		f += fmt.Sprintf("%v %v= %v", n.Left, Oconv(Op(n.Etype), FmtSharp), n.Right)
+		f += fmt.Sprintf("%v %v= %v", n.Left, Oconv(ir.Op(n.Etype), ir.FmtSharp), n.Right)
 
-	case OAS2:
+	case ir.OAS2:
 		if n.Colas && !complexinit {
-			f += fmt.Sprintf("%v := %v", Hconv(n.List, FmtComma), Hconv(n.Rlist, FmtComma))
+			f += fmt.Sprintf("%v := %v", Hconv(n.List, ir.FmtComma), Hconv(n.Rlist, ir.FmtComma))
 			break
 		}
 		fallthrough
 
-	case OAS2DOTTYPE, OAS2FUNC, OAS2MAPR, OAS2RECV:
-		f += fmt.Sprintf("%v = %v", Hconv(n.List, FmtComma), Hconv(n.Rlist, FmtComma))
+	case ir.OAS2DOTTYPE, ir.OAS2FUNC, ir.OAS2MAPR, ir.OAS2RECV:
+		f += fmt.Sprintf("%v = %v", Hconv(n.List, ir.FmtComma), Hconv(n.Rlist, ir.FmtComma))
 
-	case ORETURN:
-		f += fmt.Sprintf("return %v", Hconv(n.List, FmtComma))
+	case ir.ORETURN:
+		f += fmt.Sprintf("return %v", Hconv(n.List, ir.FmtComma))
 
-	case ORETJMP:
+	case ir.ORETJMP:
 		f += fmt.Sprintf("retjmp %v", n.Sym)
 
-	case OPROC:
+	case ir.OPROC:
 		f += fmt.Sprintf("go %v", n.Left)
 
-	case ODEFER:
+	case ir.ODEFER:
 		f += fmt.Sprintf("defer %v", n.Left)
 
-	case OIF:
+	case ir.OIF:
 		if simpleinit {
 			f += fmt.Sprintf("if %v; %v { %v }", n.Ninit.First(), n.Left, n.Nbody)
 		} else {
@@ -839,7 +774,7 @@ func stmtfmt(n *Node) string {
 			f += fmt.Sprintf(" else { %v }", n.Rlist)
 		}
 
-	case OFOR:
+	case ir.OFOR:
 		if fmtmode == FErr { // TODO maybe only if FmtShort, same below
 			f += "for loop"
 			break
@@ -864,7 +799,7 @@ func stmtfmt(n *Node) string {
 
 		f += fmt.Sprintf(" { %v }", n.Nbody)
 
-	case ORANGE:
+	case ir.ORANGE:
 		if fmtmode == FErr {
 			f += "for loop"
 			break
@@ -875,15 +810,15 @@ func stmtfmt(n *Node) string {
 			break
 		}
 
-		f += fmt.Sprintf("for %v = range %v { %v }", Hconv(n.List, FmtComma), n.Right, n.Nbody)
+		f += fmt.Sprintf("for %v = range %v { %v }", Hconv(n.List, ir.FmtComma), n.Right, n.Nbody)
 
-	case OSELECT, OSWITCH:
+	case ir.OSELECT, ir.OSWITCH:
 		if fmtmode == FErr {
 			f += fmt.Sprintf("%v statement", Oconv(n.Op, 0))
 			break
 		}
 
-		f += Oconv(n.Op, FmtSharp)
+		f += Oconv(n.Op, ir.FmtSharp)
 		if simpleinit {
 			f += fmt.Sprintf(" %v;", n.Ninit.First())
 		}
@@ -893,28 +828,28 @@ func stmtfmt(n *Node) string {
 
 		f += fmt.Sprintf(" { %v }", n.List)
 
-	case OCASE, OXCASE:
+	case ir.OCASE, ir.OXCASE:
 		if n.List.Len() != 0 {
-			f += fmt.Sprintf("case %v: %v", Hconv(n.List, FmtComma), n.Nbody)
+			f += fmt.Sprintf("case %v: %v", Hconv(n.List, ir.FmtComma), n.Nbody)
 		} else {
 			f += fmt.Sprintf("default: %v", n.Nbody)
 		}
 
-	case OBREAK,
-		OCONTINUE,
-		OGOTO,
-		OFALL,
-		OXFALL:
+	case ir.OBREAK,
+		ir.OCONTINUE,
+		ir.OGOTO,
+		ir.OFALL,
+		ir.OXFALL:
 		if n.Left != nil {
-			f += fmt.Sprintf("%v %v", Oconv(n.Op, FmtSharp), n.Left)
+			f += fmt.Sprintf("%v %v", Oconv(n.Op, ir.FmtSharp), n.Left)
 		} else {
-			f += Oconv(n.Op, FmtSharp)
+			f += Oconv(n.Op, ir.FmtSharp)
 		}
 
-	case OEMPTY:
+	case ir.OEMPTY:
 		break
 
-	case OLABEL:
+	case ir.OLABEL:
 		f += fmt.Sprintf("%v: ", n.Left)
 	}
 
@@ -927,121 +862,121 @@ ret:
 }
 
 var opprec = []int{
-	OAPPEND:       8,
-	OARRAYBYTESTR: 8,
-	OARRAYLIT:     8,
-	OARRAYRUNESTR: 8,
-	OCALLFUNC:     8,
-	OCALLINTER:    8,
-	OCALLMETH:     8,
-	OCALL:         8,
-	OCAP:          8,
-	OCLOSE:        8,
-	OCONVIFACE:    8,
-	OCONVNOP:      8,
-	OCONV:         8,
-	OCOPY:         8,
-	ODELETE:       8,
-	OGETG:         8,
-	OLEN:          8,
-	OLITERAL:      8,
-	OMAKESLICE:    8,
-	OMAKE:         8,
-	OMAPLIT:       8,
-	ONAME:         8,
-	ONEW:          8,
-	ONONAME:       8,
-	OPACK:         8,
-	OPANIC:        8,
-	OPAREN:        8,
-	OPRINTN:       8,
-	OPRINT:        8,
-	ORUNESTR:      8,
-	OSTRARRAYBYTE: 8,
-	OSTRARRAYRUNE: 8,
-	OSTRUCTLIT:    8,
-	OTARRAY:       8,
-	OTCHAN:        8,
-	OTFUNC:        8,
-	OTINTER:       8,
-	OTMAP:         8,
-	OTSTRUCT:      8,
-	OINDEXMAP:     8,
-	OINDEX:        8,
-	OSLICE:        8,
-	OSLICESTR:     8,
-	OSLICEARR:     8,
-	OSLICE3:       8,
-	OSLICE3ARR:    8,
-	ODOTINTER:     8,
-	ODOTMETH:      8,
-	ODOTPTR:       8,
-	ODOTTYPE2:     8,
-	ODOTTYPE:      8,
-	ODOT:          8,
-	OXDOT:         8,
-	OCALLPART:     8,
-	OPLUS:         7,
-	ONOT:          7,
-	OCOM:          7,
-	OMINUS:        7,
-	OADDR:         7,
-	OIND:          7,
-	ORECV:         7,
-	OMUL:          6,
-	ODIV:          6,
-	OMOD:          6,
-	OLSH:          6,
-	ORSH:          6,
-	OAND:          6,
-	OANDNOT:       6,
-	OADD:          5,
-	OSUB:          5,
-	OOR:           5,
-	OXOR:          5,
-	OEQ:           4,
-	OLT:           4,
-	OLE:           4,
-	OGE:           4,
-	OGT:           4,
-	ONE:           4,
-	OCMPSTR:       4,
-	OCMPIFACE:     4,
-	OSEND:         3,
-	OANDAND:       2,
-	OOROR:         1,
+	ir.OAPPEND:       8,
+	ir.OARRAYBYTESTR: 8,
+	ir.OARRAYLIT:     8,
+	ir.OARRAYRUNESTR: 8,
+	ir.OCALLFUNC:     8,
+	ir.OCALLINTER:    8,
+	ir.OCALLMETH:     8,
+	ir.OCALL:         8,
+	ir.OCAP:          8,
+	ir.OCLOSE:        8,
+	ir.OCONVIFACE:    8,
+	ir.OCONVNOP:      8,
+	ir.OCONV:         8,
+	ir.OCOPY:         8,
+	ir.ODELETE:       8,
+	ir.OGETG:         8,
+	ir.OLEN:          8,
+	ir.OLITERAL:      8,
+	ir.OMAKESLICE:    8,
+	ir.OMAKE:         8,
+	ir.OMAPLIT:       8,
+	ir.ONAME:         8,
+	ir.ONEW:          8,
+	ir.ONONAME:       8,
+	ir.OPACK:         8,
+	ir.OPANIC:        8,
+	ir.OPAREN:        8,
+	ir.OPRINTN:       8,
+	ir.OPRINT:        8,
+	ir.ORUNESTR:      8,
+	ir.OSTRARRAYBYTE: 8,
+	ir.OSTRARRAYRUNE: 8,
+	ir.OSTRUCTLIT:    8,
+	ir.OTARRAY:       8,
+	ir.OTCHAN:        8,
+	ir.OTFUNC:        8,
+	ir.OTINTER:       8,
+	ir.OTMAP:         8,
+	ir.OTSTRUCT:      8,
+	ir.OINDEXMAP:     8,
+	ir.OINDEX:        8,
+	ir.OSLICE:        8,
+	ir.OSLICESTR:     8,
+	ir.OSLICEARR:     8,
+	ir.OSLICE3:       8,
+	ir.OSLICE3ARR:    8,
+	ir.ODOTINTER:     8,
+	ir.ODOTMETH:      8,
+	ir.ODOTPTR:       8,
+	ir.ODOTTYPE2:     8,
+	ir.ODOTTYPE:      8,
+	ir.ODOT:          8,
+	ir.OXDOT:         8,
+	ir.OCALLPART:     8,
+	ir.OPLUS:         7,
+	ir.ONOT:          7,
+	ir.OCOM:          7,
+	ir.OMINUS:        7,
+	ir.OADDR:         7,
+	ir.OIND:          7,
+	ir.ORECV:         7,
+	ir.OMUL:          6,
+	ir.ODIV:          6,
+	ir.OMOD:          6,
+	ir.OLSH:          6,
+	ir.ORSH:          6,
+	ir.OAND:          6,
+	ir.OANDNOT:       6,
+	ir.OADD:          5,
+	ir.OSUB:          5,
+	ir.OOR:           5,
+	ir.OXOR:          5,
+	ir.OEQ:           4,
+	ir.OLT:           4,
+	ir.OLE:           4,
+	ir.OGE:           4,
+	ir.OGT:           4,
+	ir.ONE:           4,
+	ir.OCMPSTR:       4,
+	ir.OCMPIFACE:     4,
+	ir.OSEND:         3,
+	ir.OANDAND:       2,
+	ir.OOROR:         1,
 	// Statements handled by stmtfmt
-	OAS:         -1,
-	OAS2:        -1,
-	OAS2DOTTYPE: -1,
-	OAS2FUNC:    -1,
-	OAS2MAPR:    -1,
-	OAS2RECV:    -1,
-	OASOP:       -1,
-	OBREAK:      -1,
-	OCASE:       -1,
-	OCONTINUE:   -1,
-	ODCL:        -1,
-	ODCLFIELD:   -1,
-	ODEFER:      -1,
-	OEMPTY:      -1,
-	OFALL:       -1,
-	OFOR:        -1,
-	OGOTO:       -1,
-	OIF:         -1,
-	OLABEL:      -1,
-	OPROC:       -1,
-	ORANGE:      -1,
-	ORETURN:     -1,
-	OSELECT:     -1,
-	OSWITCH:     -1,
-	OXCASE:      -1,
-	OXFALL:      -1,
-	OEND:        0,
+	ir.OAS:         -1,
+	ir.OAS2:        -1,
+	ir.OAS2DOTTYPE: -1,
+	ir.OAS2FUNC:    -1,
+	ir.OAS2MAPR:    -1,
+	ir.OAS2RECV:    -1,
+	ir.OASOP:       -1,
+	ir.OBREAK:      -1,
+	ir.OCASE:       -1,
+	ir.OCONTINUE:   -1,
+	ir.ODCL:        -1,
+	ir.ODCLFIELD:   -1,
+	ir.ODEFER:      -1,
+	ir.OEMPTY:      -1,
+	ir.OFALL:       -1,
+	ir.OFOR:        -1,
+	ir.OGOTO:       -1,
+	ir.OIF:         -1,
+	ir.OLABEL:      -1,
+	ir.OPROC:       -1,
+	ir.ORANGE:      -1,
+	ir.ORETURN:     -1,
+	ir.OSELECT:     -1,
+	ir.OSWITCH:     -1,
+	ir.OXCASE:      -1,
+	ir.OXFALL:      -1,
+	ir.OEND:        0,
 }
 
-func exprfmt(n *Node, prec int) string {
-	for n != nil && n.Implicit && (n.Op == OIND || n.Op == OADDR) {
+func exprfmt(n *ir.Node, prec int) string {
+	for n != nil && n.Implicit && (n.Op == ir.OIND || n.Op == ir.OADDR) {
 		n = n.Left
 	}
 
@@ -1050,7 +985,7 @@ func exprfmt(n *Node, prec int) string {
 	}
 
 	nprec := opprec[n.Op]
-	if n.Op == OTYPE && n.Sym != nil {
+	if n.Op == ir.OTYPE && n.Sym != nil {
 		nprec = 8
 	}
 
@@ -1059,16 +994,16 @@ func exprfmt(n *Node, prec int) string {
 	}
 
 	switch n.Op {
-	case OPAREN:
+	case ir.OPAREN:
 		return fmt.Sprintf("(%v)", n.Left)
 
-	case ODDDARG:
+	case ir.ODDDARG:
 		return "... argument"
 
-	case OREGISTER:
+	case ir.OREGISTER:
 		return obj.Rconv(int(n.Reg))
 
-	case OLITERAL: // this is a bit of a mess
+	case ir.OLITERAL: // this is a bit of a mess
 		if fmtmode == FErr {
 			if n.Orig != nil && n.Orig != n {
 				return exprfmt(n.Orig, prec)
@@ -1077,13 +1012,13 @@ func exprfmt(n *Node, prec int) string {
 				return Sconv(n.Sym, 0)
 			}
 		}
-		if n.Val().Ctype() == CTNIL && n.Orig != nil && n.Orig != n {
+		if n.Val().Ctype() == ir.CTNIL && n.Orig != nil && n.Orig != n {
 			return exprfmt(n.Orig, prec)
 		}
-		if n.Type != nil && n.Type.Etype != TIDEAL && n.Type.Etype != TNIL && n.Type != idealbool && n.Type != idealstring {
+		if n.Type != nil && n.Type.Etype != ir.TIDEAL && n.Type.Etype != ir.TNIL && n.Type != ir.Idealbool && n.Type != ir.Idealstring {
 			// Need parens when type begins with what might
 			// be misinterpreted as a unary operator: * or <-.
-			if Isptr[n.Type.Etype] || (n.Type.Etype == TCHAN && n.Type.Chan == Crecv) {
+			if Isptr[n.Type.Etype] || (n.Type.Etype == ir.TCHAN && n.Type.Chan == Crecv) {
 				return fmt.Sprintf("(%v)(%v)", n.Type, Vconv(n.Val(), 0))
 			} else {
 				return fmt.Sprintf("%v(%v)", n.Type, Vconv(n.Val(), 0))
@@ -1094,7 +1029,7 @@ func exprfmt(n *Node, prec int) string {
 
 	// Special case: name used as local variable in export.
 	// _ becomes ~b%d internally; print as _ for export
-	case ONAME:
+	case ir.ONAME:
 		if (fmtmode == FExp || fmtmode == FErr) && n.Sym != nil && n.Sym.Name[0] == '~' && n.Sym.Name[1] == 'b' {
 			return "_"
 		}
@@ -1105,25 +1040,25 @@ func exprfmt(n *Node, prec int) string {
 		// Special case: explicit name of func (*T) method(...) is turned into pkg.(*T).method,
 		// but for export, this should be rendered as (*pkg.T).meth.
 		// These nodes have the special property that they are names with a left OTYPE and a right ONAME.
-		if fmtmode == FExp && n.Left != nil && n.Left.Op == OTYPE && n.Right != nil && n.Right.Op == ONAME {
+		if fmtmode == FExp && n.Left != nil && n.Left.Op == ir.OTYPE && n.Right != nil && n.Right.Op == ir.ONAME {
 			if Isptr[n.Left.Type.Etype] {
-				return fmt.Sprintf("(%v).%v", n.Left.Type, Sconv(n.Right.Sym, FmtShort|FmtByte))
+				return fmt.Sprintf("(%v).%v", n.Left.Type, Sconv(n.Right.Sym, ir.FmtShort|ir.FmtByte))
 			} else {
-				return fmt.Sprintf("%v.%v", n.Left.Type, Sconv(n.Right.Sym, FmtShort|FmtByte))
+				return fmt.Sprintf("%v.%v", n.Left.Type, Sconv(n.Right.Sym, ir.FmtShort|ir.FmtByte))
 			}
 		}
 		fallthrough
 
-	case OPACK, ONONAME:
+	case ir.OPACK, ir.ONONAME:
 		return Sconv(n.Sym, 0)
 
-	case OTYPE:
+	case ir.OTYPE:
 		if n.Type == nil && n.Sym != nil {
 			return Sconv(n.Sym, 0)
 		}
 		return Tconv(n.Type, 0)
 
-	case OTARRAY:
+	case ir.OTARRAY:
 		if n.Left != nil {
 			return fmt.Sprintf("[]%v", n.Left)
 		}
@@ -1131,10 +1066,10 @@ func exprfmt(n *Node, prec int) string {
 		f += fmt.Sprintf("[]%v", n.Right)
 		return f // happens before typecheck
 
-	case OTMAP:
+	case ir.OTMAP:
 		return fmt.Sprintf("map[%v]%v", n.Left, n.Right)
 
-	case OTCHAN:
+	case ir.OTCHAN:
 		switch n.Etype {
 		case Crecv:
 			return fmt.Sprintf("<-chan %v", n.Left)
@@ -1143,23 +1078,23 @@ func exprfmt(n *Node, prec int) string {
 			return fmt.Sprintf("chan<- %v", n.Left)
 
 		default:
-			if n.Left != nil && n.Left.Op == OTCHAN && n.Left.Sym == nil && n.Left.Etype == Crecv {
+			if n.Left != nil && n.Left.Op == ir.OTCHAN && n.Left.Sym == nil && n.Left.Etype == Crecv {
 				return fmt.Sprintf("chan (%v)", n.Left)
 			} else {
 				return fmt.Sprintf("chan %v", n.Left)
 			}
 		}
 
-	case OTSTRUCT:
+	case ir.OTSTRUCT:
 		return "<struct>"
 
-	case OTINTER:
+	case ir.OTINTER:
 		return "<inter>"
 
-	case OTFUNC:
+	case ir.OTFUNC:
 		return "<func>"
 
-	case OCLOSURE:
+	case ir.OCLOSURE:
 		if fmtmode == FErr {
 			return "func literal"
 		}
@@ -1168,7 +1103,7 @@ func exprfmt(n *Node, prec int) string {
 		}
 		return fmt.Sprintf("%v { %v }", n.Type, n.Name.Param.Closure.Nbody)
 
-	case OCOMPLIT:
+	case ir.OCOMPLIT:
 		ptrlit := n.Right != nil && n.Right.Implicit && n.Right.Type != nil && Isptr[n.Right.Type.Etype]
 		if fmtmode == FErr {
 			if n.Right != nil && n.Right.Type != nil && !n.Implicit {
@@ -1184,18 +1119,18 @@ func exprfmt(n *Node, prec int) string {
 
 		if fmtmode == FExp && ptrlit {
 			// typecheck has overwritten OIND by OTYPE with pointer type.
-			return fmt.Sprintf("(&%v{ %v })", n.Right.Type.Type, Hconv(n.List, FmtComma))
+			return fmt.Sprintf("(&%v{ %v })", n.Right.Type.Type, Hconv(n.List, ir.FmtComma))
 		}
 
-		return fmt.Sprintf("(%v{ %v })", n.Right, Hconv(n.List, FmtComma))
+		return fmt.Sprintf("(%v{ %v })", n.Right, Hconv(n.List, ir.FmtComma))
 
-	case OPTRLIT:
+	case ir.OPTRLIT:
 		if fmtmode == FExp && n.Left.Implicit {
 			return Nconv(n.Left, 0)
 		}
 		return fmt.Sprintf("&%v", n.Left)
 
-	case OSTRUCTLIT:
+	case ir.OSTRUCTLIT:
 		if fmtmode == FExp { // requires special handling of field names
 			var f string
 			if n.Implicit {
@@ -1204,7 +1139,7 @@ func exprfmt(n *Node, prec int) string {
 				f += fmt.Sprintf("(%v{", n.Type)
 			}
 			for i1, n1 := range n.List.Slice() {
-				f += fmt.Sprintf(" %v:%v", Sconv(n1.Left.Sym, FmtShort|FmtByte), n1.Right)
+				f += fmt.Sprintf(" %v:%v", Sconv(n1.Left.Sym, ir.FmtShort|ir.FmtByte), n1.Right)
 
 				if i1+1 < n.List.Len() {
 					f += ","
@@ -1222,20 +1157,20 @@ func exprfmt(n *Node, prec int) string {
 		}
 		fallthrough
 
-	case OARRAYLIT, OMAPLIT:
+	case ir.OARRAYLIT, ir.OMAPLIT:
 		if fmtmode == FErr {
 			return fmt.Sprintf("%v literal", n.Type)
 		}
 		if fmtmode == FExp && n.Implicit {
-			return fmt.Sprintf("{ %v }", Hconv(n.List, FmtComma))
+			return fmt.Sprintf("{ %v }", Hconv(n.List, ir.FmtComma))
 		}
-		return fmt.Sprintf("(%v{ %v })", n.Type, Hconv(n.List, FmtComma))
+		return fmt.Sprintf("(%v{ %v })", n.Type, Hconv(n.List, ir.FmtComma))
 
-	case OKEY:
+	case ir.OKEY:
 		if n.Left != nil && n.Right != nil {
 			if fmtmode == FExp && n.Left.Type == structkey {
 				// requires special handling of field names
-				return fmt.Sprintf("%v:%v", Sconv(n.Left.Sym, FmtShort|FmtByte), n.Right)
+				return fmt.Sprintf("%v:%v", Sconv(n.Left.Sym, ir.FmtShort|ir.FmtByte), n.Right)
 			} else {
 				return fmt.Sprintf("%v:%v", n.Left, n.Right)
 			}
@@ -1249,27 +1184,27 @@ func exprfmt(n *Node, prec int) string {
 		}
 		return ":"
 
-	case OCALLPART:
+	case ir.OCALLPART:
 		var f string
 		f += exprfmt(n.Left, nprec)
 		if n.Right == nil || n.Right.Sym == nil {
 			f += ".<nil>"
 			return f
 		}
-		f += fmt.Sprintf(".%v", Sconv(n.Right.Sym, FmtShort|FmtByte))
+		f += fmt.Sprintf(".%v", Sconv(n.Right.Sym, ir.FmtShort|ir.FmtByte))
 		return f
 
-	case OXDOT, ODOT, ODOTPTR, ODOTINTER, ODOTMETH:
+	case ir.OXDOT, ir.ODOT, ir.ODOTPTR, ir.ODOTINTER, ir.ODOTMETH:
 		var f string
 		f += exprfmt(n.Left, nprec)
 		if n.Sym == nil {
 			f += ".<nil>"
 			return f
 		}
-		f += fmt.Sprintf(".%v", Sconv(n.Sym, FmtShort|FmtByte))
+		f += fmt.Sprintf(".%v", Sconv(n.Sym, ir.FmtShort|ir.FmtByte))
 		return f
 
-	case ODOTTYPE, ODOTTYPE2:
+	case ir.ODOTTYPE, ir.ODOTTYPE2:
 		var f string
 		f += exprfmt(n.Left, nprec)
 		if n.Right != nil {
@@ -1279,126 +1214,126 @@ func exprfmt(n *Node, prec int) string {
 		f += fmt.Sprintf(".(%v)", n.Type)
 		return f
 
-	case OINDEX,
-		OINDEXMAP,
-		OSLICE,
-		OSLICESTR,
-		OSLICEARR,
-		OSLICE3,
-		OSLICE3ARR:
+	case ir.OINDEX,
+		ir.OINDEXMAP,
+		ir.OSLICE,
+		ir.OSLICESTR,
+		ir.OSLICEARR,
+		ir.OSLICE3,
+		ir.OSLICE3ARR:
 		var f string
 		f += exprfmt(n.Left, nprec)
 		f += fmt.Sprintf("[%v]", n.Right)
 		return f
 
-	case OCOPY, OCOMPLEX:
-		return fmt.Sprintf("%v(%v, %v)", Oconv(n.Op, FmtSharp), n.Left, n.Right)
-
-	case OCONV,
-		OCONVIFACE,
-		OCONVNOP,
-		OARRAYBYTESTR,
-		OARRAYRUNESTR,
-		OSTRARRAYBYTE,
-		OSTRARRAYRUNE,
-		ORUNESTR:
+	case ir.OCOPY, ir.OCOMPLEX:
+		return fmt.Sprintf("%v(%v, %v)", Oconv(n.Op, ir.FmtSharp), n.Left, n.Right)
+
+	case ir.OCONV,
+		ir.OCONVIFACE,
+		ir.OCONVNOP,
+		ir.OARRAYBYTESTR,
+		ir.OARRAYRUNESTR,
+		ir.OSTRARRAYBYTE,
+		ir.OSTRARRAYRUNE,
+		ir.ORUNESTR:
 		if n.Type == nil || n.Type.Sym == nil {
 			return fmt.Sprintf("(%v)(%v)", n.Type, n.Left)
 		}
 		if n.Left != nil {
 			return fmt.Sprintf("%v(%v)", n.Type, n.Left)
 		}
-		return fmt.Sprintf("%v(%v)", n.Type, Hconv(n.List, FmtComma))
-
-	case OREAL,
-		OIMAG,
-		OAPPEND,
-		OCAP,
-		OCLOSE,
-		ODELETE,
-		OLEN,
-		OMAKE,
-		ONEW,
-		OPANIC,
-		ORECOVER,
-		OPRINT,
-		OPRINTN:
+		return fmt.Sprintf("%v(%v)", n.Type, Hconv(n.List, ir.FmtComma))
+
+	case ir.OREAL,
+		ir.OIMAG,
+		ir.OAPPEND,
+		ir.OCAP,
+		ir.OCLOSE,
+		ir.ODELETE,
+		ir.OLEN,
+		ir.OMAKE,
+		ir.ONEW,
+		ir.OPANIC,
+		ir.ORECOVER,
+		ir.OPRINT,
+		ir.OPRINTN:
 		if n.Left != nil {
-			return fmt.Sprintf("%v(%v)", Oconv(n.Op, FmtSharp), n.Left)
+			return fmt.Sprintf("%v(%v)", Oconv(n.Op, ir.FmtSharp), n.Left)
 		}
 		if n.Isddd {
-			return fmt.Sprintf("%v(%v...)", Oconv(n.Op, FmtSharp), Hconv(n.List, FmtComma))
+			return fmt.Sprintf("%v(%v...)", Oconv(n.Op, ir.FmtSharp), Hconv(n.List, ir.FmtComma))
 		}
-		return fmt.Sprintf("%v(%v)", Oconv(n.Op, FmtSharp), Hconv(n.List, FmtComma))
+		return fmt.Sprintf("%v(%v)", Oconv(n.Op, ir.FmtSharp), Hconv(n.List, ir.FmtComma))
 
-	case OCALL, OCALLFUNC, OCALLINTER, OCALLMETH, OGETG:
+	case ir.OCALL, ir.OCALLFUNC, ir.OCALLINTER, ir.OCALLMETH, ir.OGETG:
 		var f string
 		f += exprfmt(n.Left, nprec)
 		if n.Isddd {
-			f += fmt.Sprintf("(%v...)", Hconv(n.List, FmtComma))
+			f += fmt.Sprintf("(%v...)", Hconv(n.List, ir.FmtComma))
 			return f
 		}
-		f += fmt.Sprintf("(%v)", Hconv(n.List, FmtComma))
+		f += fmt.Sprintf("(%v)", Hconv(n.List, ir.FmtComma))
 		return f
 
-	case OMAKEMAP, OMAKECHAN, OMAKESLICE:
+	case ir.OMAKEMAP, ir.OMAKECHAN, ir.OMAKESLICE:
 		if n.List.Len() != 0 { // pre-typecheck
-			return fmt.Sprintf("make(%v, %v)", n.Type, Hconv(n.List, FmtComma))
+			return fmt.Sprintf("make(%v, %v)", n.Type, Hconv(n.List, ir.FmtComma))
 		}
 		if n.Right != nil {
 			return fmt.Sprintf("make(%v, %v, %v)", n.Type, n.Left, n.Right)
 		}
-		if n.Left != nil && (n.Op == OMAKESLICE || !isideal(n.Left.Type)) {
+		if n.Left != nil && (n.Op == ir.OMAKESLICE || !isideal(n.Left.Type)) {
 			return fmt.Sprintf("make(%v, %v)", n.Type, n.Left)
 		}
 		return fmt.Sprintf("make(%v)", n.Type)
 
 		// Unary
-	case OPLUS,
-		OMINUS,
-		OADDR,
-		OCOM,
-		OIND,
-		ONOT,
-		ORECV:
+	case ir.OPLUS,
+		ir.OMINUS,
+		ir.OADDR,
+		ir.OCOM,
+		ir.OIND,
+		ir.ONOT,
+		ir.ORECV:
 		var f string
 		if n.Left.Op == n.Op {
-			f += fmt.Sprintf("%v ", Oconv(n.Op, FmtSharp))
+			f += fmt.Sprintf("%v ", Oconv(n.Op, ir.FmtSharp))
 		} else {
-			f += Oconv(n.Op, FmtSharp)
+			f += Oconv(n.Op, ir.FmtSharp)
 		}
 		f += exprfmt(n.Left, nprec+1)
 		return f
 
 		// Binary
-	case OADD,
-		OAND,
-		OANDAND,
-		OANDNOT,
-		ODIV,
-		OEQ,
-		OGE,
-		OGT,
-		OLE,
-		OLT,
-		OLSH,
-		OMOD,
-		OMUL,
-		ONE,
-		OOR,
-		OOROR,
-		ORSH,
-		OSEND,
-		OSUB,
-		OXOR:
+	case ir.OADD,
+		ir.OAND,
+		ir.OANDAND,
+		ir.OANDNOT,
+		ir.ODIV,
+		ir.OEQ,
+		ir.OGE,
+		ir.OGT,
+		ir.OLE,
+		ir.OLT,
+		ir.OLSH,
+		ir.OMOD,
+		ir.OMUL,
+		ir.ONE,
+		ir.OOR,
+		ir.OOROR,
+		ir.ORSH,
+		ir.OSEND,
+		ir.OSUB,
+		ir.OXOR:
 		var f string
 		f += exprfmt(n.Left, nprec)
 
-		f += fmt.Sprintf(" %v ", Oconv(n.Op, FmtSharp))
+		f += fmt.Sprintf(" %v ", Oconv(n.Op, ir.FmtSharp))
 		f += exprfmt(n.Right, nprec+1)
 		return f
 
-	case OADDSTR:
+	case ir.OADDSTR:
 		var f string
 		i := 0
 		for _, n1 := range n.List.Slice() {
@@ -1411,15 +1346,15 @@ func exprfmt(n *Node, prec int) string {
 
 		return f
 
-	case OCMPSTR, OCMPIFACE:
+	case ir.OCMPSTR, ir.OCMPIFACE:
 		var f string
 		f += exprfmt(n.Left, nprec)
 		// TODO(marvin): Fix Node.EType type union.
-		f += fmt.Sprintf(" %v ", Oconv(Op(n.Etype), FmtSharp))
+		f += fmt.Sprintf(" %v ", Oconv(ir.Op(n.Etype), ir.FmtSharp))
 		f += exprfmt(n.Right, nprec+1)
 		return f
 
-	case ODCLCONST:
+	case ir.ODCLCONST:
 		// if exporting, DCLCONST should just be removed as its usage
 		// has already been replaced with literals
 		if fmtbody {
@@ -1430,18 +1365,18 @@ func exprfmt(n *Node, prec int) string {
 	return fmt.Sprintf("<node %v>", Oconv(n.Op, 0))
 }
 
-func nodefmt(n *Node, flag FmtFlag) string {
+func nodefmt(n *ir.Node, flag ir.FmtFlag) string {
 	t := n.Type
 
 	// we almost always want the original, except in export mode for literals
 	// this saves the importer some work, and avoids us having to redo some
 	// special casing for package unsafe
-	if (fmtmode != FExp || n.Op != OLITERAL) && n.Orig != nil {
+	if (fmtmode != FExp || n.Op != ir.OLITERAL) && n.Orig != nil {
 		n = n.Orig
 	}
 
-	if flag&FmtLong != 0 && t != nil {
-		if t.Etype == TNIL {
+	if flag&ir.FmtLong != 0 && t != nil {
+		if t.Etype == ir.TNIL {
 			return "nil"
 		} else {
 			return fmt.Sprintf("%v (type %v)", n, t)
@@ -1466,12 +1401,12 @@ func indent(buf *bytes.Buffer) {
 	}
 }
 
-func nodedump(n *Node, flag FmtFlag) string {
+func nodedump(n *ir.Node, flag ir.FmtFlag) string {
 	if n == nil {
 		return ""
 	}
 
-	recur := flag&FmtShort == 0
+	recur := flag&ir.FmtShort == 0
 
 	var buf bytes.Buffer
 	if recur {
@@ -1491,13 +1426,13 @@ func nodedump(n *Node, flag FmtFlag) string {
 	default:
 		fmt.Fprintf(&buf, "%v%v", Oconv(n.Op, 0), Jconv(n, 0))
 
-	case OREGISTER, OINDREG:
+	case ir.OREGISTER, ir.OINDREG:
 		fmt.Fprintf(&buf, "%v-%v%v", Oconv(n.Op, 0), obj.Rconv(int(n.Reg)), Jconv(n, 0))
 
-	case OLITERAL:
+	case ir.OLITERAL:
 		fmt.Fprintf(&buf, "%v-%v%v", Oconv(n.Op, 0), Vconv(n.Val(), 0), Jconv(n, 0))
 
-	case ONAME, ONONAME:
+	case ir.ONAME, ir.ONONAME:
 		if n.Sym != nil {
 			fmt.Fprintf(&buf, "%v-%v%v", Oconv(n.Op, 0), n.Sym, Jconv(n, 0))
 		} else {
@@ -1508,10 +1443,10 @@ func nodedump(n *Node, flag FmtFlag) string {
 			fmt.Fprintf(&buf, "%v-ntype%v", Oconv(n.Op, 0), n.Name.Param.Ntype)
 		}
 
-	case OASOP:
-		fmt.Fprintf(&buf, "%v-%v%v", Oconv(n.Op, 0), Oconv(Op(n.Etype), 0), Jconv(n, 0))
+	case ir.OASOP:
+		fmt.Fprintf(&buf, "%v-%v%v", Oconv(n.Op, 0), Oconv(ir.Op(n.Etype), 0), Jconv(n, 0))
 
-	case OTYPE:
+	case ir.OTYPE:
 		fmt.Fprintf(&buf, "%v %v%v type=%v", Oconv(n.Op, 0), n.Sym, Jconv(n, 0), n.Type)
 		if recur && n.Type == nil && n.Name.Param.Ntype != nil {
 			indent(&buf)
@@ -1519,7 +1454,7 @@ func nodedump(n *Node, flag FmtFlag) string {
 		}
 	}
 
-	if n.Sym != nil && n.Op != ONAME {
+	if n.Sym != nil && n.Op != ir.ONAME {
 		fmt.Fprintf(&buf, " %v", n.Sym)
 	}
 
@@ -1553,14 +1488,10 @@ func nodedump(n *Node, flag FmtFlag) string {
 	return buf.String()
 }
 
-func (s *Sym) String() string {
-	return Sconv(s, 0)
-}
-
 // Fmt "%S": syms
 // Flags:  "%hS" suppresses qualifying with package
-func Sconv(s *Sym, flag FmtFlag) string {
-	if flag&FmtLong != 0 {
+func Sconv(s *ir.Sym, flag ir.FmtFlag) string {
+	if flag&ir.FmtLong != 0 {
 		panic("linksymfmt")
 	}
 
@@ -1581,11 +1512,7 @@ func Sconv(s *Sym, flag FmtFlag) string {
 	return str
 }
 
-func (t *Type) String() string {
-	return Tconv(t, 0)
-}
-
-func Fldconv(f *Field, flag FmtFlag) string {
+func Fldconv(f *ir.Field, flag ir.FmtFlag) string {
 	if f == nil {
 		return "<T>"
 	}
@@ -1593,39 +1520,43 @@ func Fldconv(f *Field, flag FmtFlag) string {
 	sf := flag
 	sm, sb := setfmode(&flag)
 
-	if fmtmode == FTypeId && (sf&FmtUnsigned != 0) {
+	if fmtmode == FTypeId && (sf&ir.FmtUnsigned != 0) {
 		fmtpkgpfx++
 	}
 	if fmtpkgpfx != 0 {
-		flag |= FmtUnsigned
+		flag |= ir.FmtUnsigned
 	}
 
 	var name string
-	if flag&FmtShort == 0 {
+	if flag&ir.FmtShort == 0 {
 		s := f.Sym
 
 		// Take the name from the original, lest we substituted it with ~r%d or ~b%d.
 		// ~r%d is a (formerly) unnamed result.
-		if (fmtmode == FErr || fmtmode == FExp) && f.Nname != nil {
-			if f.Nname.Orig != nil {
-				s = f.Nname.Orig.Sym
-				if s != nil && s.Name[0] == '~' {
-					if s.Name[1] == 'r' { // originally an unnamed result
-						s = nil
-					} else if s.Name[1] == 'b' { // originally the blank identifier _
-						s = Lookup("_")
-					}
-				}
-			} else {
+		// The parameters of imported functions have no Nname.
+		if fmtmode == FErr || fmtmode == FExp {
+			if f.Nname != nil {
 				s = nil
+				if f.Nname.Orig != nil {
+					s = f.Nname.Orig.Sym
+				}
+			}
+			if s != nil && s.Name != "" && s.Name[0] == '~' {
+				if s.Name[1] == 'r' { // originally an unnamed result
+					s = nil
+				} else if s.Name[1] == 'b' { // originally the blank identifier _
+					s = Lookup("_")
+				}
 			}
 		}
 
 		if s != nil && f.Embedded == 0 {
-			if f.Funarg {
+			if f.Funarg && f.Nname != nil {
 				name = Nconv(f.Nname, 0)
-			} else if flag&FmtLong != 0 {
-				name = Sconv(s, FmtShort|FmtByte) // qualify non-exported names (used on structs, not on funarg)
+			} else if f.Funarg {
+				name = Sconv(s, 0)
+			} else if flag&ir.FmtLong != 0 {
+				name = Sconv(s, ir.FmtShort|ir.FmtByte) // qualify non-exported names (used on structs, not on funarg)
 			} else {
 				name = Sconv(s, 0)
 			}
@@ -1655,11 +1586,11 @@ func Fldconv(f *Field, flag FmtFlag) string {
 	// (The escape analysis tags do not apply to func vars.)
 	// But it must not suppress struct field tags.
 	// See golang.org/issue/13777 and golang.org/issue/14331.
-	if flag&FmtShort == 0 && (!fmtbody || !f.Funarg) && f.Note != nil {
+	if flag&ir.FmtShort == 0 && (!fmtbody || !f.Funarg) && f.Note != nil {
 		str += " " + strconv.Quote(*f.Note)
 	}
 
-	if fmtmode == FTypeId && (sf&FmtUnsigned != 0) {
+	if fmtmode == FTypeId && (sf&ir.FmtUnsigned != 0) {
 		fmtpkgpfx--
 	}
 
@@ -1673,7 +1604,7 @@ func Fldconv(f *Field, flag FmtFlag) string {
 // Flags: 'l' print definition, not name
 //	  'h' omit 'func' and receiver from function types, short type names
 //	  'u' package name, not prefix (FTypeId mode, sticky)
-func Tconv(t *Type, flag FmtFlag) string {
+func Tconv(t *ir.Type, flag ir.FmtFlag) string {
 	if t == nil {
 		return "<T>"
 	}
@@ -1686,16 +1617,16 @@ func Tconv(t *Type, flag FmtFlag) string {
 	sf := flag
 	sm, sb := setfmode(&flag)
 
-	if fmtmode == FTypeId && (sf&FmtUnsigned != 0) {
+	if fmtmode == FTypeId && (sf&ir.FmtUnsigned != 0) {
 		fmtpkgpfx++
 	}
 	if fmtpkgpfx != 0 {
-		flag |= FmtUnsigned
+		flag |= ir.FmtUnsigned
 	}
 
 	str := typefmt(t, flag)
 
-	if fmtmode == FTypeId && (sf&FmtUnsigned != 0) {
+	if fmtmode == FTypeId && (sf&ir.FmtUnsigned != 0) {
 		fmtpkgpfx--
 	}
 
@@ -1706,14 +1637,10 @@ func Tconv(t *Type, flag FmtFlag) string {
 	return str
 }
 
-func (n *Node) String() string {
-	return Nconv(n, 0)
-}
-
 // Fmt '%N': Nodes.
 // Flags: 'l' suffix with "(type %T)" where possible
 //	  '+h' in debug mode, don't recurse, no multiline output
-func Nconv(n *Node, flag FmtFlag) string {
+func Nconv(n *ir.Node, flag ir.FmtFlag) string {
 	if n == nil {
 		return "<N>"
 	}
@@ -1740,13 +1667,9 @@ func Nconv(n *Node, flag FmtFlag) string {
 	return str
 }
 
-func (n Nodes) String() string {
-	return Hconv(n, 0)
-}
-
 // Fmt '%H': Nodes.
 // Flags: all those of %N plus ',': separate with comma's instead of semicolons.
-func Hconv(l Nodes, flag FmtFlag) string {
+func Hconv(l ir.Nodes, flag ir.FmtFlag) string {
 	if l.Len() == 0 && fmtmode == FDbg {
 		return "<nil>"
 	}
@@ -1756,7 +1679,7 @@ func Hconv(l Nodes, flag FmtFlag) string {
 	sep := "; "
 	if fmtmode == FDbg {
 		sep = "\n"
-	} else if flag&FmtComma != 0 {
+	} else if flag&ir.FmtComma != 0 {
 		sep = ", "
 	}
 
@@ -1774,10 +1697,10 @@ func Hconv(l Nodes, flag FmtFlag) string {
 	return buf.String()
 }
 
-func dumplist(s string, l Nodes) {
-	fmt.Printf("%s%v\n", s, Hconv(l, FmtSign))
+func dumplist(s string, l ir.Nodes) {
+	fmt.Printf("%s%v\n", s, Hconv(l, ir.FmtSign))
 }
 
-func Dump(s string, n *Node) {
-	fmt.Printf("%s [%p]%v\n", s, n, Nconv(n, FmtSign))
+func Dump(s string, n *ir.Node) {
+	fmt.Printf("%s [%p]%v\n", s, n, Nconv(n, ir.FmtSign))
 }
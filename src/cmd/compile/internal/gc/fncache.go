@@ -0,0 +1,603 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gc
+
+import (
+	"bufio"
+	"bytes"
+	"cmd/compile/internal/ir"
+	"cmd/compile/internal/ssa"
+	"cmd/internal/obj"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/gob"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// The function cache (-fncache dir) saves the machine code generated
+// for each function compiled by the SSA back end, keyed by a hash of
+// the function's IR after walk, and reuses it when a later compilation
+// produces the same IR. On a hit, the compiler still builds the SSA
+// form of the function, for its effects on the rest of the package,
+// but skips optimizing it, generating its code, and assembling it.
+//
+// The key covers the content id of the compilation (see buildid.go),
+// so a change to the compiler, the flags, or the export data of an
+// imported package invalidates the whole cache. It also covers the
+// source positions in the function, so functions below a line that
+// was added or removed are recompiled too.
+//
+// The names of autotmps are not part of the key: they are numbered
+// throughout the package, and only appear in the debugging
+// information. A function reused from the cache keeps the names it
+// was compiled with, so the object file can differ from one compiled
+// without the cache in those names.
+
+// fncachedir is the directory holding the function cache, if any.
+var fncachedir string
+
+// fncachekeys holds the keys of the functions being compiled that
+// were not found in the cache, to save them once they are assembled.
+var fncachekeys = map[*ir.Node]string{}
+
+// fncacheid is the part of the keys common to all functions.
+var fncacheid string
+
+// A fncacheEntry is the code and metadata saved for a function.
+// Symbols are referred to by an index in Syms; 0 means nil.
+type fncacheEntry struct {
+	Syms []fncacheSym
+
+	Sym           int
+	Dupok         uint8
+	Cfunc         uint8
+	Nosplit       uint8
+	Leaf          uint8
+	ReflectMethod bool
+	NoDWARF       bool
+	Local         bool
+	Args          int32
+	Locals        int32
+	Size          int64
+	Gotype        int
+	P             []byte
+	R             []fncacheReloc
+	Autom         []fncacheAuto
+	Scopes        []fncacheScope
+
+	Pcsp     []byte
+	Pcfile   []byte
+	Pcline   []byte
+	Pcstmt   []byte
+	Pcdata   [][]byte
+	Funcdata []fncacheFuncdata
+	File     []int
+
+	// Strings holds the contents of the string data symbols the
+	// code refers to, which the back end creates as needed.
+	Strings []string
+}
+
+type fncacheSym struct {
+	Name    string
+	Version int16
+}
+
+type fncacheReloc struct {
+	Off  int32
+	Siz  uint8
+	Type int32
+	Add  int64
+	Sym  int
+}
+
+type fncacheAuto struct {
+	Asym    int
+	Aoffset int32
+	Name    int16
+	Gotype  int
+	Scope   int32
+}
+
+type fncacheScope struct {
+	Parent int32
+	Ranges []obj.ScopeRange
+}
+
+type fncacheFuncdata struct {
+	Sym int
+	Off int64
+
+	// Data holds the contents of a pointer map symbol, which is
+	// defined along with the function (see gcsymdup).
+	Data []byte
+}
+
+// fncacheload looks for the code of fn, whose SSA form is ssafn, in the
+// function cache. If it is found, fncacheload defines the function's
+// symbol and the data it refers to, and reports true.
+func fncacheload(fn *ir.Node, ssafn *ssa.Func) bool {
+	if fncachedir == "" || isblank(fn.Func.Nname) {
+		return false
+	}
+	key := fncachekey(fn)
+	data, err := ioutil.ReadFile(fncachefile(key))
+	if err != nil {
+		fncachekeys[fn] = key
+		return false
+	}
+	var e fncacheEntry
+	err = gob.NewDecoder(bytes.NewReader(data)).Decode(&e)
+	if err != nil || e.Sym <= 0 || e.Sym >= len(e.Syms) || e.Syms[e.Sym].Name != Linksym(fn.Func.Nname.Sym).Name {
+		// A corrupt entry is as good as none.
+		fncachekeys[fn] = key
+		return false
+	}
+
+	// Emit the type descriptors of the variables, as emitfunc does.
+	// The code may refer to others only if the SSA form did.
+	for _, n := range fn.Func.Dcl {
+		if n.Op == ir.ONAME && (n.Class == ir.PAUTO || n.Class == ir.PPARAM || n.Class == ir.PPARAMOUT) {
+			ngotype(n)
+		}
+	}
+	for _, r := range e.R {
+		if !typesymEmitted(e.Syms[r.Sym].Name) {
+			fncachekeys[fn] = key
+			return false
+		}
+	}
+
+	ssafn.Free()
+	e.restore()
+	if Debug_fncache != 0 {
+		Warnl(fn.Lineno, "function cache hit for %v", fn.Func.Nname.Sym)
+	}
+	return true
+}
+
+// fncachestore saves the code of fn in the function cache, if it
+// was not found there.
+func fncachestore(fn *ir.Node) {
+	key, ok := fncachekeys[fn]
+	if !ok {
+		return
+	}
+	delete(fncachekeys, fn)
+	s := Linksym(fn.Func.Nname.Sym)
+	if nerrors != 0 || s.Type != obj.STEXT || s.Pcln == nil {
+		return
+	}
+
+	// The cache is only an optimization: don't fail the compilation
+	// if an entry can't be encoded or written. Write entries
+	// atomically, for the sake of concurrent compilations using the
+	// same cache.
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(newFncacheEntry(s)); err != nil {
+		return
+	}
+	file := fncachefile(key)
+	if err := os.MkdirAll(filepath.Dir(file), 0777); err != nil {
+		return
+	}
+	f, err := ioutil.TempFile(filepath.Dir(file), "tmp")
+	if err != nil {
+		return
+	}
+	_, err = f.Write(buf.Bytes())
+	if err1 := f.Close(); err == nil {
+		err = err1
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), file)
+	}
+	if err != nil {
+		os.Remove(f.Name())
+	}
+}
+
+func fncachefile(key string) string {
+	return filepath.Join(fncachedir, key[:2], key)
+}
+
+// newFncacheEntry returns the cache entry for the assembled function s.
+func newFncacheEntry(s *obj.LSym) *fncacheEntry {
+	e := &fncacheEntry{Syms: []fncacheSym{{}}}
+	refs := map[*obj.LSym]int{}
+	ref := func(s *obj.LSym) int {
+		if s == nil {
+			return 0
+		}
+		if i, ok := refs[s]; ok {
+			return i
+		}
+		refs[s] = len(e.Syms)
+		e.Syms = append(e.Syms, fncacheSym{s.Name, s.Version})
+		return refs[s]
+	}
+
+	e.Sym = ref(s)
+	e.Dupok = s.Dupok
+	e.Cfunc = s.Cfunc
+	e.Nosplit = s.Nosplit
+	e.Leaf = s.Leaf
+	e.ReflectMethod = s.ReflectMethod
+	e.NoDWARF = s.NoDWARF
+	e.Local = s.Local
+	e.Args = s.Args
+	e.Locals = s.Locals
+	e.Size = s.Size
+	e.Gotype = ref(s.Gotype)
+	e.P = s.P
+
+	strs := map[string]bool{}
+	for _, r := range s.R {
+		e.R = append(e.R, fncacheReloc{r.Off, r.Siz, r.Type, r.Add, ref(r.Sym)})
+		if r.Sym == nil {
+			continue
+		}
+		switch name := r.Sym.Name; {
+		case strings.HasPrefix(name, "go.string.hdr."):
+			strs[string(r.Sym.R[0].Sym.P)] = true
+		case strings.HasPrefix(name, "go.string."):
+			strs[string(r.Sym.P)] = true
+		}
+	}
+	for str := range strs {
+		e.Strings = append(e.Strings, str)
+	}
+	sort.Strings(e.Strings)
+
+	for a := s.Autom; a != nil; a = a.Link {
+		e.Autom = append(e.Autom, fncacheAuto{ref(a.Asym), a.Aoffset, a.Name, ref(a.Gotype), a.Scope})
+	}
+	for _, sc := range s.Scopes {
+		e.Scopes = append(e.Scopes, fncacheScope{sc.Parent, sc.Ranges})
+	}
+
+	pc := s.Pcln
+	e.Pcsp = pc.Pcsp.P
+	e.Pcfile = pc.Pcfile.P
+	e.Pcline = pc.Pcline.P
+	e.Pcstmt = pc.Pcstmt.P
+	for _, d := range pc.Pcdata {
+		e.Pcdata = append(e.Pcdata, d.P)
+	}
+	for i, fs := range pc.Funcdata {
+		d := fncacheFuncdata{Sym: ref(fs), Off: pc.Funcdataoff[i]}
+		if fs != nil && strings.HasPrefix(fs.Name, "gclocals·") {
+			d.Data = fs.P
+		}
+		e.Funcdata = append(e.Funcdata, d)
+	}
+	for _, f := range pc.File {
+		e.File = append(e.File, ref(f))
+	}
+	return e
+}
+
+// restore defines the function saved in e, and the data it refers to.
+func (e *fncacheEntry) restore() {
+	syms := make([]*obj.LSym, len(e.Syms))
+	for i, s := range e.Syms[1:] {
+		syms[i+1] = obj.Linklookup(Ctxt, s.Name, int(s.Version))
+	}
+
+	for _, str := range e.Strings {
+		stringsym(str)
+	}
+
+	s := syms[e.Sym]
+	s.Type = obj.STEXT
+	s.Onlist = 1
+	s.Dupok = e.Dupok
+	s.Cfunc = e.Cfunc
+	s.Nosplit = e.Nosplit
+	s.Leaf = e.Leaf
+	s.ReflectMethod = e.ReflectMethod
+	s.NoDWARF = e.NoDWARF
+	s.Local = e.Local
+	s.Args = e.Args
+	s.Locals = e.Locals
+	s.Size = e.Size
+	s.Gotype = syms[e.Gotype]
+	s.P = e.P
+	for _, r := range e.R {
+		s.R = append(s.R, obj.Reloc{Off: r.Off, Siz: r.Siz, Type: r.Type, Add: r.Add, Sym: syms[r.Sym]})
+	}
+	for i := len(e.Autom) - 1; i >= 0; i-- {
+		a := e.Autom[i]
+		gotype := syms[a.Gotype]
+		if gotype != nil && !typesymEmitted(gotype.Name) {
+			// An autotmp allocated by the back end,
+			// whose type may be used nowhere else.
+			gotype = nil
+		}
+		s.Autom = &obj.Auto{Asym: syms[a.Asym], Link: s.Autom, Aoffset: a.Aoffset, Name: a.Name, Gotype: gotype, Scope: a.Scope}
+	}
+	for _, sc := range e.Scopes {
+		s.Scopes = append(s.Scopes, obj.Scope{Parent: sc.Parent, Ranges: sc.Ranges})
+	}
+
+	pc := new(obj.Pcln)
+	pc.Pcsp.P = e.Pcsp
+	pc.Pcfile.P = e.Pcfile
+	pc.Pcline.P = e.Pcline
+	pc.Pcstmt.P = e.Pcstmt
+	for _, d := range e.Pcdata {
+		pc.Pcdata = append(pc.Pcdata, obj.Pcdata{P: d})
+	}
+	for _, d := range e.Funcdata {
+		fs := syms[d.Sym]
+		if d.Data != nil {
+			// Each function has its own pointer map symbols,
+			// deduplicated by the linker.
+			fs = &obj.LSym{Name: fs.Name, Dupok: 1, P: d.Data}
+			ggloblLSym(fs, int32(len(d.Data)), obj.RODATA)
+		}
+		pc.Funcdata = append(pc.Funcdata, fs)
+		pc.Funcdataoff = append(pc.Funcdataoff, d.Off)
+	}
+	for _, f := range e.File {
+		pc.File = append(pc.File, syms[f])
+	}
+	s.Pcln = pc
+
+	Ctxt.Text = append(Ctxt.Text, s)
+}
+
+// typesymEmitted reports whether the symbol name is not that of a type
+// descriptor, or is that of one that will be emitted (see typenamesym).
+func typesymEmitted(name string) bool {
+	if !strings.HasPrefix(name, "type.") || strings.HasPrefix(name, "type..") {
+		return true
+	}
+	s := typepkg.Find(name[len("type."):])
+	return s != nil && s.Def != nil
+}
+
+// fncachekey returns the function cache key for fn.
+func fncachekey(fn *ir.Node) string {
+	if fncacheid == "" {
+		fncacheid = contentid()
+	}
+	sha := sha256.New()
+	w := bufio.NewWriter(sha)
+	fmt.Fprintf(w, "fncache %s %q %q %q\n", fncacheid, myimportpath, Ctxt.Pathname, obj.Getgoroot())
+	h := irhasher{w: w, nodes: map[*ir.Node]int{}, types: map[*ir.Type]int{}}
+	h.fn(fn)
+	w.Flush()
+	return fmt.Sprintf("%x", sha.Sum(nil))
+}
+
+// An irhasher writes the IR of a function to w, for hashing.
+// Nodes and types are written once, and referred to by number after.
+type irhasher struct {
+	w     io.Writer
+	buf   [binary.MaxVarintLen64]byte
+	nodes map[*ir.Node]int
+	types map[*ir.Type]int
+
+	line    int32
+	linestr string
+}
+
+func (h *irhasher) int(x int64) {
+	n := binary.PutVarint(h.buf[:], x)
+	h.w.Write(h.buf[:n])
+}
+
+func (h *irhasher) bool(b bool) {
+	if b {
+		h.int(1)
+	} else {
+		h.int(0)
+	}
+}
+
+func (h *irhasher) string(s string) {
+	h.int(int64(len(s)))
+	io.WriteString(h.w, s)
+}
+
+func (h *irhasher) pos(line int32) {
+	if line != h.line || h.linestr == "" {
+		h.line = line
+		h.linestr = linestr(line)
+	}
+	h.string(h.linestr)
+}
+
+func (h *irhasher) sym(s *ir.Sym) {
+	if s == nil {
+		h.string("")
+		return
+	}
+	if s.Pkg != nil {
+		h.string(s.Pkg.Path)
+	}
+	h.string(s.Name)
+}
+
+// syms writes the names of the symbols in m, in order.
+func (h *irhasher) syms(m map[*ir.Sym]struct{}) {
+	var names []string
+	for s := range m {
+		names = append(names, s.Pkg.Path+"."+s.Name)
+	}
+	sort.Strings(names)
+	h.int(int64(len(names)))
+	for _, name := range names {
+		h.string(name)
+	}
+}
+
+func (h *irhasher) fn(fn *ir.Node) {
+	f := fn.Func
+	h.node(fn)
+	h.node(f.Nname)
+	h.list(f.Enter)
+	h.list(f.Exit)
+	h.list(f.Cvars)
+	h.int(int64(len(f.Dcl)))
+	for _, n := range f.Dcl {
+		h.node(n)
+	}
+	h.int(int64(f.Pragma))
+	h.bool(f.Dupok)
+	h.bool(f.Wrapper)
+	h.bool(f.Needctxt)
+	h.bool(f.ReflectMethod)
+	h.pos(f.Endlineno)
+	h.syms(f.FieldTrack)
+	h.syms(f.ReflectMethods)
+}
+
+func (h *irhasher) list(l ir.Nodes) {
+	h.int(int64(l.Len()))
+	for _, n := range l.Slice() {
+		h.node(n)
+	}
+}
+
+func (h *irhasher) node(n *ir.Node) {
+	if n == nil {
+		h.int(0)
+		return
+	}
+	if i, ok := h.nodes[n]; ok {
+		h.int(int64(i))
+		return
+	}
+	h.nodes[n] = len(h.nodes) + 1
+	h.int(-1)
+
+	h.int(int64(n.Op))
+	h.int(int64(n.Etype))
+	h.int(int64(n.Class))
+	h.int(int64(n.Esc))
+	h.int(int64(n.Reg))
+	h.int(n.Xoffset)
+	h.int(int64(n.Likely))
+	h.int(int64(n.Embedded))
+	// Globals are shared by the whole package. They are positioned
+	// where first used, and marked Used by the back end, both of
+	// which depend on what the cache hit.
+	global := n.Op == ir.ONAME && (n.Class == ir.PEXTERN || n.Class == ir.PFUNC)
+	for _, b := range [...]bool{n.Nointerface, n.Addable, n.Bounded, n.Colas, n.Noescape, n.Local, n.Used && !global, n.Isddd, n.Implicit, n.Addrtaken, n.Assigned, n.Hasbreak} {
+		h.bool(b)
+	}
+	if global {
+		h.int(0)
+	} else {
+		h.pos(n.Lineno)
+	}
+	if n.Op == ir.ONAME && n.Sym != nil && strings.HasPrefix(n.Sym.Name, "autotmp_") {
+		// Numbered throughout the package; see above.
+		h.string("autotmp")
+	} else if global && n.Sym != nil {
+		// The code refers to the linker symbol, which a
+		// //go:linkname directive elsewhere can rename.
+		h.string(Linksym(n.Sym).Name)
+	} else {
+		h.sym(n.Sym)
+	}
+	if n.HasVal() {
+		h.val(n.Val())
+	} else {
+		h.int(0)
+	}
+	h.typ(n.Type)
+
+	if n.Name != nil {
+		h.int(int64(n.Name.Vargen))
+		h.int(int64(n.Name.Funcdepth))
+		for _, b := range [...]bool{n.Name.Method, n.Name.Readonly, n.Name.Captured, n.Name.Byval, n.Name.Needzero, n.Name.Keepalive} {
+			h.bool(b)
+		}
+		h.node(n.Name.Heapaddr)
+		if n.Name.Param != nil {
+			h.node(n.Name.Param.Stackparam)
+		}
+	}
+
+	h.node(n.Left)
+	h.node(n.Right)
+	h.list(n.Ninit)
+	h.list(n.Nbody)
+	h.list(n.List)
+	h.list(n.Rlist)
+}
+
+func (h *irhasher) val(v ir.Val) {
+	switch u := v.U.(type) {
+	case *ir.Mpint:
+		h.string("int")
+		h.string(u.Val.String())
+		h.bool(u.Rune)
+	case *ir.Mpflt:
+		h.string("float")
+		h.string(u.Val.Text('p', 0))
+	case *ir.Mpcplx:
+		h.string("complex")
+		h.string(u.Real.Val.Text('p', 0))
+		h.string(u.Imag.Val.Text('p', 0))
+	case string:
+		h.string("string")
+		h.string(u)
+	case bool:
+		h.string("bool")
+		h.bool(u)
+	case *ir.NilVal:
+		h.string("nil")
+	default:
+		h.string("none")
+	}
+}
+
+func (h *irhasher) typ(t *ir.Type) {
+	if t == nil {
+		h.int(0)
+		return
+	}
+	if i, ok := h.types[t]; ok {
+		h.int(int64(i))
+		return
+	}
+	h.types[t] = len(h.types) + 1
+	h.int(-1)
+
+	h.int(int64(t.Etype))
+	h.sym(t.Sym)
+	h.int(int64(t.Vargen))
+	h.int(t.Width)
+	h.int(int64(t.Align))
+	h.int(t.Bound)
+	h.int(int64(t.Chan))
+	for _, b := range [...]bool{t.Noalg, t.Funarg, t.Outnamed} {
+		h.bool(b)
+	}
+	h.typ(t.Type)
+	h.typ(t.Down)
+	if t.Etype == ir.TSTRUCT || t.Etype == ir.TINTER {
+		h.int(int64(t.NumFields()))
+		for _, f := range t.FieldSlice() {
+			h.sym(f.Sym)
+			h.typ(f.Type)
+			h.int(f.Width)
+			h.int(int64(f.Embedded))
+			for _, b := range [...]bool{f.Nointerface, f.Funarg, f.Isddd} {
+				h.bool(b)
+			}
+		}
+	}
+}
@@ -0,0 +1,93 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gc
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// Make sure -fncache reuses the code of unchanged functions, and only
+// of those, and that the program built from the cache runs.
+func TestFuncCache(t *testing.T) {
+	d := newTestDir(t, "TestFuncCache")
+	defer d.remove()
+
+	const prog = `package main
+
+var s = "ab"
+
+func f(x int) int {
+	return x + %s
+}
+
+func g(x int) string {
+	return s[x:] + "g"
+}
+
+func main() {
+	println(f(1), g(1))
+}
+`
+	src := d.path("main.go")
+	obj := d.path("main.o")
+	cache := d.path("cache")
+	compile := func(f string) (hits []string) {
+		d.write("main.go", fmt.Sprintf(prog, f))
+		out := d.run("go", "tool", "compile", "-fncache", cache, "-d", "fncache", "-o", obj, src)
+		for _, line := range strings.Split(out, "\n") {
+			if i := strings.Index(line, "function cache hit for "); i >= 0 {
+				hits = append(hits, line[i+len("function cache hit for "):])
+			}
+		}
+		sort.Strings(hits)
+		return hits
+	}
+
+	if hits := compile("1"); len(hits) != 0 {
+		t.Fatalf("first compile hit %v in an empty cache", hits)
+	}
+	all := []string{"f", "g", "init", "main"}
+	if hits := compile("1"); !reflect.DeepEqual(hits, all) {
+		t.Fatalf("second compile hit %v, want %v", hits, all)
+	}
+	// Changing the body of f must miss for f and for main, which
+	// inlines f, but not for the other functions.
+	if hits, want := compile("2"), []string{"g", "init"}; !reflect.DeepEqual(hits, want) {
+		t.Fatalf("compile after editing f hit %v, want %v", hits, want)
+	}
+
+	if out := d.run(d.link(obj)); out != "3 bg\n" {
+		t.Errorf("got output %q, want %q", out, "3 bg\n")
+	}
+}
+
+// Make sure -fncache does not reuse the code of a function after a
+// //go:linkname directive in another file renames a global it uses.
+func TestFuncCacheLinkname(t *testing.T) {
+	d := newTestDir(t, "TestFuncCacheLinkname")
+	defer d.remove()
+
+	a := d.write("a.go", "package p\n\nvar x int\n\nfunc F() int {\n\treturn x\n}\n")
+	b := d.path("b.go")
+	obj := d.path("p.o")
+	cache := d.path("cache")
+	compile := func(src string) bool {
+		d.write("b.go", src)
+		out := d.run("go", "tool", "compile", "-fncache", cache, "-d", "fncache", "-o", obj, a, b)
+		return strings.Contains(out, "function cache hit for F")
+	}
+
+	compile("package p\n")
+	if !compile("package p\n") {
+		t.Fatalf("second compile did not hit for F")
+	}
+	if compile("package p\n\nimport _ \"unsafe\"\n\n//go:linkname x q.x\n") {
+		t.Errorf("compile after renaming x hit for F")
+	}
+}
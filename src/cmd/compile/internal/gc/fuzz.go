@@ -0,0 +1,38 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gc
+
+// FuzzParse and FuzzTypecheck are entry points for go-fuzz
+// (github.com/dvyukov/go-fuzz), built with, for example,
+//
+//	go-fuzz-build -func FuzzTypecheck cmd/compile/internal/gc
+//
+// Each compiles data in process as the text of a source file and
+// panics if the compiler crashes or reports an internal compiler
+// error. Whatever its input, the compiler must either accept it or
+// reject it with ordinary diagnostics.
+
+// FuzzParse parses data. It returns 1 if data parses and 0 if not,
+// which tells go-fuzz to prefer inputs that parse.
+func FuzzParse(data []byte) int {
+	return fuzz(data, "parse")
+}
+
+// FuzzTypecheck parses and type checks data. It returns 1 if data
+// type checks and 0 if not.
+func FuzzTypecheck(data []byte) int {
+	return fuzz(data, "typecheck")
+}
+
+func fuzz(data []byte, phase string) int {
+	_, _, err := CompilePackage(string(data), phase, "")
+	if ice, ok := err.(*InternalError); ok {
+		panic(ice.Error() + "\n" + string(ice.Stack))
+	}
+	if err != nil {
+		return 0
+	}
+	return 1
+}
@@ -0,0 +1,94 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gc
+
+import "testing"
+
+// fuzzSeeds are the inputs TestFuzzSeeds checks, together with every
+// prefix of each and every copy of each with one byte deleted. They
+// also make a starting corpus for go-fuzz.
+var fuzzSeeds = []string{
+	`package p
+
+import "unsafe"
+
+type T struct {
+	a, b int
+	s    []string
+	m    map[string]*T
+}
+
+func (t *T) f(x ...int) (r int, err error) {
+	for i, v := range x {
+		r += i * v
+	}
+	defer func() { recover() }()
+	switch {
+	case t.a > 0:
+		r <<= uint(t.b)
+		fallthrough
+	default:
+		r = len(t.s) + int(unsafe.Sizeof(*t))
+	}
+	return
+}
+`,
+	`package p
+
+type I interface {
+	M(int) string
+}
+
+const (
+	c0 = iota * 1.5
+	c1
+	c2 = "s" + "t"
+)
+
+var v = [...]interface{}{c0, c1, c2, 'x', 1 + 2i}
+
+func g(ch chan<- I, i I) {
+	select {
+	case ch <- i:
+	default:
+		if s, ok := i.(interface{ M(int) string }); ok {
+			go s.M(len(v))
+		}
+	}
+L:
+	for x := range make(chan int) {
+		switch x := interface{}(x).(type) {
+		case int:
+			break L
+		case nil, string:
+			goto L
+		}
+	}
+}
+`,
+}
+
+func TestFuzzSeeds(t *testing.T) {
+	for _, seed := range fuzzSeeds {
+		if _, _, err := CompilePackage(seed, "typecheck", ""); err != nil {
+			t.Errorf("seed does not type check: %v\n%s", err, seed)
+		}
+		var inputs []string
+		for i := 0; i <= len(seed); i++ {
+			inputs = append(inputs, seed[:i])
+			if i < len(seed) {
+				inputs = append(inputs, seed[:i]+seed[i+1:])
+			}
+		}
+		for _, in := range inputs {
+			for _, phase := range []string{"parse", "typecheck"} {
+				_, _, err := CompilePackage(in, phase, "")
+				if ice, ok := err.(*InternalError); ok {
+					t.Fatalf("%s of %q: %v\n%s", phase, in, ice, ice.Stack)
+				}
+			}
+		}
+	}
+}
@@ -7,16 +7,17 @@
 package gc
 
 import (
+	"cmd/compile/internal/ir"
 	"cmd/internal/obj"
 	"fmt"
 )
 
 // TODO: labellist should become part of a "compilation state" for functions.
-var labellist []*Label
+var labellist []*ir.Label
 
-func Sysfunc(name string) *Node {
+func Sysfunc(name string) *ir.Node {
 	n := newname(Pkglookup(name, Runtimepkg))
-	n.Class = PFUNC
+	n.Class = ir.PFUNC
 	return n
 }
 
@@ -24,26 +25,26 @@ func Sysfunc(name string) *Node {
 // by "increasing" the "value" of n.Esc to EscHeap.
 // Storage is allocated as necessary to allow the address
 // to be taken.
-func addrescapes(n *Node) {
+func addrescapes(n *ir.Node) {
 	switch n.Op {
 	// probably a type error already.
 	// dump("addrescapes", n);
 	default:
 		break
 
-	case ONAME:
+	case ir.ONAME:
 		if n == nodfp {
 			break
 		}
 
 		// if this is a tmpname (PAUTO), it was tagged by tmpname as not escaping.
 		// on PPARAM it means something different.
-		if n.Class == PAUTO && n.Esc == EscNever {
+		if n.Class == ir.PAUTO && n.Esc == EscNever {
 			break
 		}
 
 		switch n.Class {
-		case PPARAMREF:
+		case ir.PPARAMREF:
 			addrescapes(n.Name.Defn)
 
 		// if func param, need separate temporary
@@ -53,19 +54,19 @@ func addrescapes(n *Node) {
 		// so the param already has a valid xoffset.
 
 		// expression to refer to stack copy
-		case PPARAM, PPARAMOUT:
-			n.Name.Param.Stackparam = Nod(OPARAM, n, nil)
+		case ir.PPARAM, ir.PPARAMOUT:
+			n.Name.Param.Stackparam = Nod(ir.OPARAM, n, nil)
 
 			n.Name.Param.Stackparam.Type = n.Type
 			n.Name.Param.Stackparam.Addable = true
-			if n.Xoffset == BADWIDTH {
+			if n.Xoffset == ir.BADWIDTH {
 				Fatalf("addrescapes before param assignment")
 			}
 			n.Name.Param.Stackparam.Xoffset = n.Xoffset
 			fallthrough
 
-		case PAUTO:
-			n.Class |= PHEAP
+		case ir.PAUTO:
+			n.Class |= ir.PHEAP
 
 			n.Addable = false
 			n.Ullman = 2
@@ -75,13 +76,14 @@ func addrescapes(n *Node) {
 			oldfn := Curfn
 
 			Curfn = n.Name.Curfn
-			if Curfn.Func.Closure != nil && Curfn.Op == OCLOSURE {
+			if Curfn.Func.Closure != nil && Curfn.Op == ir.OCLOSURE {
 				Curfn = Curfn.Func.Closure
 			}
 			n.Name.Heapaddr = temp(Ptrto(n.Type))
 			buf := fmt.Sprintf("&%v", n.Sym)
 			n.Name.Heapaddr.Sym = Lookup(buf)
 			n.Name.Heapaddr.Orig.Sym = n.Name.Heapaddr.Sym
+			n.Name.Heapaddr.Name.Block = n.Name.Block
 			n.Esc = EscHeap
 			if Debug['m'] != 0 {
 				fmt.Printf("%v: moved to heap: %v\n", n.Line(), n)
@@ -89,7 +91,7 @@ func addrescapes(n *Node) {
 			Curfn = oldfn
 		}
 
-	case OIND, ODOTPTR:
+	case ir.OIND, ir.ODOTPTR:
 		break
 
 	// ODOTPTR has already been introduced,
@@ -97,7 +99,7 @@ func addrescapes(n *Node) {
 	// In &x[0], if x is a slice, then x does not
 	// escape--the pointer inside x does, but that
 	// is always a heap pointer anyway.
-	case ODOT, OINDEX, OPAREN, OCONVNOP:
+	case ir.ODOT, ir.OINDEX, ir.OPAREN, ir.OCONVNOP:
 		if !Isslice(n.Left.Type) {
 			addrescapes(n.Left)
 		}
@@ -111,17 +113,17 @@ func clearlabels() {
 	labellist = labellist[:0]
 }
 
-func newlab(n *Node) *Label {
+func newlab(n *ir.Node) *ir.Label {
 	s := n.Left.Sym
 	lab := s.Label
 	if lab == nil {
-		lab = new(Label)
+		lab = new(ir.Label)
 		lab.Sym = s
 		s.Label = lab
 		labellist = append(labellist, lab)
 	}
 
-	if n.Op == OLABEL {
+	if n.Op == ir.OLABEL {
 		if lab.Def != nil {
 			Yyerror("label %v already defined at %v", s, lab.Def.Line())
 		} else {
@@ -136,7 +138,7 @@ func newlab(n *Node) *Label {
 
 // There is a copy of checkgoto in the new SSA backend.
 // Please keep them in sync.
-func checkgoto(from *Node, to *Node) {
+func checkgoto(from *ir.Node, to *ir.Node) {
 	if from.Sym == to.Sym {
 		return
 	}
@@ -160,9 +162,9 @@ func checkgoto(from *Node, to *Node) {
 		// decide what to complain about.
 		// prefer to complain about 'into block' over declarations,
 		// so scan backward to find most recent block or else dcl.
-		var block *Sym
+		var block *ir.Sym
 
-		var dcl *Sym
+		var dcl *ir.Sym
 		ts := to.Sym
 		for ; nt > nf; nt-- {
 			if ts.Pkg == nil {
@@ -184,15 +186,15 @@ func checkgoto(from *Node, to *Node) {
 		}
 
 		if block != nil {
-			Yyerror("goto %v jumps into block starting at %v", from.Left.Sym, linestr(block.Lastlineno))
+			Yyerror("goto %v jumps into block starting at %v\n\tlabel %v defined at %v", from.Left.Sym, linestr(block.Lastlineno), to.Left.Sym, to.Line())
 		} else {
-			Yyerror("goto %v jumps over declaration of %v at %v", from.Left.Sym, dcl, linestr(dcl.Lastlineno))
+			Yyerror("goto %v jumps over declaration of %v at %v\n\tlabel %v defined at %v", from.Left.Sym, dcl, linestr(dcl.Lastlineno), to.Left.Sym, to.Line())
 		}
 		lineno = lno
 	}
 }
 
-func stmtlabel(n *Node) *Label {
+func stmtlabel(n *ir.Node) *ir.Label {
 	if n.Sym != nil {
 		lab := n.Sym.Label
 		if lab != nil {
@@ -207,25 +209,25 @@ func stmtlabel(n *Node) *Label {
 }
 
 // compile statements
-func Genlist(l Nodes) {
+func Genlist(l ir.Nodes) {
 	for _, n := range l.Slice() {
 		gen(n)
 	}
 }
 
 // generate code to start new proc running call n.
-func cgen_proc(n *Node, proc int) {
+func cgen_proc(n *ir.Node, proc int) {
 	switch n.Left.Op {
 	default:
 		Fatalf("cgen_proc: unknown call %v", Oconv(n.Left.Op, 0))
 
-	case OCALLMETH:
+	case ir.OCALLMETH:
 		cgen_callmeth(n.Left, proc)
 
-	case OCALLINTER:
+	case ir.OCALLINTER:
 		cgen_callinter(n.Left, nil, proc)
 
-	case OCALLFUNC:
+	case ir.OCALLFUNC:
 		cgen_call(n.Left, proc)
 	}
 }
@@ -233,16 +235,16 @@ func cgen_proc(n *Node, proc int) {
 // generate declaration.
 // have to allocate heap copy
 // for escaped variables.
-func cgen_dcl(n *Node) {
+func cgen_dcl(n *ir.Node) {
 	if Debug['g'] != 0 {
 		Dump("\ncgen-dcl", n)
 	}
-	if n.Op != ONAME {
+	if n.Op != ir.ONAME {
 		Dump("cgen_dcl", n)
 		Fatalf("cgen_dcl")
 	}
 
-	if n.Class&PHEAP == 0 {
+	if n.Class&ir.PHEAP == 0 {
 		return
 	}
 	if compiling_runtime != 0 {
@@ -255,53 +257,53 @@ func cgen_dcl(n *Node) {
 }
 
 // generate discard of value
-func cgen_discard(nr *Node) {
+func cgen_discard(nr *ir.Node) {
 	if nr == nil {
 		return
 	}
 
 	switch nr.Op {
-	case ONAME:
-		if nr.Class&PHEAP == 0 && nr.Class != PEXTERN && nr.Class != PFUNC && nr.Class != PPARAMREF {
+	case ir.ONAME:
+		if nr.Class&ir.PHEAP == 0 && nr.Class != ir.PEXTERN && nr.Class != ir.PFUNC && nr.Class != ir.PPARAMREF {
 			gused(nr)
 		}
 
 		// unary
-	case OADD,
-		OAND,
-		ODIV,
-		OEQ,
-		OGE,
-		OGT,
-		OLE,
-		OLSH,
-		OLT,
-		OMOD,
-		OMUL,
-		ONE,
-		OOR,
-		ORSH,
-		OSUB,
-		OXOR:
+	case ir.OADD,
+		ir.OAND,
+		ir.ODIV,
+		ir.OEQ,
+		ir.OGE,
+		ir.OGT,
+		ir.OLE,
+		ir.OLSH,
+		ir.OLT,
+		ir.OMOD,
+		ir.OMUL,
+		ir.ONE,
+		ir.OOR,
+		ir.ORSH,
+		ir.OSUB,
+		ir.OXOR:
 		cgen_discard(nr.Left)
 
 		cgen_discard(nr.Right)
 
 		// binary
-	case OCAP,
-		OCOM,
-		OLEN,
-		OMINUS,
-		ONOT,
-		OPLUS:
+	case ir.OCAP,
+		ir.OCOM,
+		ir.OLEN,
+		ir.OMINUS,
+		ir.ONOT,
+		ir.OPLUS:
 		cgen_discard(nr.Left)
 
-	case OIND:
+	case ir.OIND:
 		Cgen_checknil(nr.Left)
 
 		// special enough to just evaluate
 	default:
-		var tmp Node
+		var tmp ir.Node
 		Tempname(&tmp, nr.Type)
 
 		Cgen_as(&tmp, nr)
@@ -310,39 +312,39 @@ func cgen_discard(nr *Node) {
 }
 
 // clearslim generates code to zero a slim node.
-func Clearslim(n *Node) {
-	var z Node
-	z.Op = OLITERAL
+func Clearslim(n *ir.Node) {
+	var z ir.Node
+	z.Op = ir.OLITERAL
 	z.Type = n.Type
 	z.Addable = true
 
 	switch Simtype[n.Type.Etype] {
-	case TCOMPLEX64, TCOMPLEX128:
-		z.SetVal(Val{new(Mpcplx)})
-		z.Val().U.(*Mpcplx).Real.SetFloat64(0.0)
-		z.Val().U.(*Mpcplx).Imag.SetFloat64(0.0)
+	case ir.TCOMPLEX64, ir.TCOMPLEX128:
+		z.SetVal(ir.Val{U: new(ir.Mpcplx)})
+		z.Val().U.(*ir.Mpcplx).Real.SetFloat64(0.0)
+		z.Val().U.(*ir.Mpcplx).Imag.SetFloat64(0.0)
 
-	case TFLOAT32, TFLOAT64:
-		var zero Mpflt
+	case ir.TFLOAT32, ir.TFLOAT64:
+		var zero ir.Mpflt
 		zero.SetFloat64(0.0)
-		z.SetVal(Val{&zero})
+		z.SetVal(ir.Val{U: &zero})
 
-	case TPTR32, TPTR64, TCHAN, TMAP:
-		z.SetVal(Val{new(NilVal)})
+	case ir.TPTR32, ir.TPTR64, ir.TCHAN, ir.TMAP:
+		z.SetVal(ir.Val{U: new(ir.NilVal)})
 
-	case TBOOL:
-		z.SetVal(Val{false})
+	case ir.TBOOL:
+		z.SetVal(ir.Val{U: false})
 
-	case TINT8,
-		TINT16,
-		TINT32,
-		TINT64,
-		TUINT8,
-		TUINT16,
-		TUINT32,
-		TUINT64:
-		z.SetVal(Val{new(Mpint)})
-		z.Val().U.(*Mpint).SetInt64(0)
+	case ir.TINT8,
+		ir.TINT16,
+		ir.TINT32,
+		ir.TINT64,
+		ir.TUINT8,
+		ir.TUINT16,
+		ir.TUINT32,
+		ir.TUINT64:
+		z.SetVal(ir.Val{U: new(ir.Mpint)})
+		z.Val().U.(*ir.Mpint).SetInt64(0)
 
 	default:
 		Fatalf("clearslim called on type %v", n.Type)
@@ -356,17 +358,17 @@ func Clearslim(n *Node) {
 //	res = iface{typ, data}
 // n->left is typ
 // n->right is data
-func Cgen_eface(n *Node, res *Node) {
+func Cgen_eface(n *ir.Node, res *ir.Node) {
 	// the right node of an eface may contain function calls that uses res as an argument,
 	// so it's important that it is done first
 
-	tmp := temp(Types[Tptr])
+	tmp := temp(ir.Types[Tptr])
 	Cgen(n.Right, tmp)
 
 	Gvardef(res)
 
 	dst := *res
-	dst.Type = Types[Tptr]
+	dst.Type = ir.Types[Tptr]
 	dst.Xoffset += int64(Widthptr)
 	Cgen(tmp, &dst)
 
@@ -379,7 +381,7 @@ func Cgen_eface(n *Node, res *Node) {
 //	res = x.(T) (when resok == nil)
 // n.Left is x
 // n.Type is T
-func cgen_dottype(n *Node, res, resok *Node, wb bool) {
+func cgen_dottype(n *ir.Node, res, resok *ir.Node, wb bool) {
 	if Debug_typeassert > 0 {
 		Warn("type assertion inlined")
 	}
@@ -396,25 +398,25 @@ func cgen_dottype(n *Node, res, resok *Node, wb bool) {
 	//		resok = false // (when resok != nil)
 	//	}
 	//
-	var iface Node
+	var iface ir.Node
 	Igen(n.Left, &iface, res)
-	var r1, r2 Node
-	byteptr := Ptrto(Types[TUINT8]) // type used in runtime prototypes for runtime type (*byte)
+	var r1, r2 ir.Node
+	byteptr := Ptrto(ir.Types[ir.TUINT8]) // type used in runtime prototypes for runtime type (*byte)
 	Regalloc(&r1, byteptr, nil)
 	iface.Type = byteptr
 	Cgen(&iface, &r1)
 	if !isnilinter(n.Left.Type) {
 		// Holding itab, want concrete type in second word.
-		p := Thearch.Ginscmp(OEQ, byteptr, &r1, Nodintconst(0), -1)
+		p := Thearch.Ginscmp(ir.OEQ, byteptr, &r1, Nodintconst(0), -1)
 		r2 = r1
-		r2.Op = OINDREG
+		r2.Op = ir.OINDREG
 		r2.Xoffset = int64(Widthptr)
 		Cgen(&r2, &r1)
 		Patch(p, Pc)
 	}
 	Regalloc(&r2, byteptr, nil)
 	Cgen(typename(n.Type), &r2)
-	p := Thearch.Ginscmp(ONE, byteptr, &r1, &r2, -1)
+	p := Thearch.Ginscmp(ir.ONE, byteptr, &r1, &r2, -1)
 	Regfree(&r2) // not needed for success path; reclaimed on one failure path
 	iface.Xoffset += int64(Widthptr)
 	Cgen(&iface, &r1)
@@ -428,11 +430,11 @@ func cgen_dottype(n *Node, res, resok *Node, wb bool) {
 		Regrealloc(&r2) // reclaim from above, for this failure path
 		fn := syslook("panicdottype")
 		dowidth(fn.Type)
-		call := Nod(OCALLFUNC, fn, nil)
+		call := Nod(ir.OCALLFUNC, fn, nil)
 		r1.Type = byteptr
 		r2.Type = byteptr
-		call.List.Set([]*Node{&r1, &r2, typename(n.Left.Type)})
-		call.List.Set(ascompatte(OCALLFUNC, call, false, fn.Type.Params(), call.List.Slice(), 0, nil))
+		call.List.Set([]*ir.Node{&r1, &r2, typename(n.Left.Type)})
+		call.List.Set(ascompatte(ir.OCALLFUNC, call, false, fn.Type.Params(), call.List.Slice(), 0, nil))
 		gen(call)
 		Regfree(&r1)
 		Regfree(&r2)
@@ -468,7 +470,7 @@ func cgen_dottype(n *Node, res, resok *Node, wb bool) {
 //	res, resok = x.(T)
 // n.Left is x
 // n.Type is T
-func Cgen_As2dottype(n, res, resok *Node) {
+func Cgen_As2dottype(n, res, resok *ir.Node) {
 	if Debug_typeassert > 0 {
 		Warn("type assertion inlined")
 	}
@@ -485,25 +487,25 @@ func Cgen_As2dottype(n, res, resok *Node) {
 	//		resok = false
 	//	}
 	//
-	var iface Node
+	var iface ir.Node
 	Igen(n.Left, &iface, nil)
-	var r1, r2 Node
-	byteptr := Ptrto(Types[TUINT8]) // type used in runtime prototypes for runtime type (*byte)
+	var r1, r2 ir.Node
+	byteptr := Ptrto(ir.Types[ir.TUINT8]) // type used in runtime prototypes for runtime type (*byte)
 	Regalloc(&r1, byteptr, res)
 	iface.Type = byteptr
 	Cgen(&iface, &r1)
 	if !isnilinter(n.Left.Type) {
 		// Holding itab, want concrete type in second word.
-		p := Thearch.Ginscmp(OEQ, byteptr, &r1, Nodintconst(0), -1)
+		p := Thearch.Ginscmp(ir.OEQ, byteptr, &r1, Nodintconst(0), -1)
 		r2 = r1
-		r2.Op = OINDREG
+		r2.Op = ir.OINDREG
 		r2.Xoffset = int64(Widthptr)
 		Cgen(&r2, &r1)
 		Patch(p, Pc)
 	}
 	Regalloc(&r2, byteptr, nil)
 	Cgen(typename(n.Type), &r2)
-	p := Thearch.Ginscmp(ONE, byteptr, &r1, &r2, -1)
+	p := Thearch.Ginscmp(ir.ONE, byteptr, &r1, &r2, -1)
 	iface.Type = n.Type
 	iface.Xoffset += int64(Widthptr)
 	Cgen(&iface, &r1)
@@ -516,9 +518,9 @@ func Cgen_As2dottype(n, res, resok *Node) {
 
 	fn := syslook("panicdottype")
 	dowidth(fn.Type)
-	call := Nod(OCALLFUNC, fn, nil)
-	call.List.Set([]*Node{&r1, &r2, typename(n.Left.Type)})
-	call.List.Set(ascompatte(OCALLFUNC, call, false, fn.Type.Params(), call.List.Slice(), 0, nil))
+	call := Nod(ir.OCALLFUNC, fn, nil)
+	call.List.Set([]*ir.Node{&r1, &r2, typename(n.Left.Type)})
+	call.List.Set(ascompatte(ir.OCALLFUNC, call, false, fn.Type.Params(), call.List.Slice(), 0, nil))
 	gen(call)
 	Regfree(&r1)
 	Regfree(&r2)
@@ -529,12 +531,12 @@ func Cgen_As2dottype(n, res, resok *Node) {
 // gather series of offsets
 // >=0 is direct addressed field
 // <0 is pointer to next field (+1)
-func Dotoffset(n *Node, oary []int64, nn **Node) int {
+func Dotoffset(n *ir.Node, oary []int64, nn **ir.Node) int {
 	var i int
 
 	switch n.Op {
-	case ODOT:
-		if n.Xoffset == BADWIDTH {
+	case ir.ODOT:
+		if n.Xoffset == ir.BADWIDTH {
 			Dump("bad width in dotoffset", n)
 			Fatalf("bad width in dotoffset")
 		}
@@ -554,8 +556,8 @@ func Dotoffset(n *Node, oary []int64, nn **Node) int {
 			i++
 		}
 
-	case ODOTPTR:
-		if n.Xoffset == BADWIDTH {
+	case ir.ODOTPTR:
+		if n.Xoffset == ir.BADWIDTH {
 			Dump("bad width in dotoffset", n)
 			Fatalf("bad width in dotoffset")
 		}
@@ -578,29 +580,29 @@ func Dotoffset(n *Node, oary []int64, nn **Node) int {
 }
 
 // make a new off the books
-func Tempname(nn *Node, t *Type) {
+func Tempname(nn *ir.Node, t *ir.Type) {
 	if Curfn == nil {
 		Fatalf("no curfn for tempname")
 	}
-	if Curfn.Func.Closure != nil && Curfn.Op == OCLOSURE {
+	if Curfn.Func.Closure != nil && Curfn.Op == ir.OCLOSURE {
 		Dump("Tempname", Curfn)
 		Fatalf("adding tempname to wrong closure function")
 	}
 
 	if t == nil {
 		Yyerror("tempname called with nil type")
-		t = Types[TINT32]
+		t = ir.Types[ir.TINT32]
 	}
 
 	// give each tmp a different name so that there
 	// a chance to registerizer them
 	s := LookupN("autotmp_", statuniqgen)
 	statuniqgen++
-	n := Nod(ONAME, nil, nil)
+	n := Nod(ir.ONAME, nil, nil)
 	n.Sym = s
 	s.Def = n
 	n.Type = t
-	n.Class = PAUTO
+	n.Class = ir.PAUTO
 	n.Addable = true
 	n.Ullman = 1
 	n.Esc = EscNever
@@ -612,14 +614,14 @@ func Tempname(nn *Node, t *Type) {
 	*nn = *n
 }
 
-func temp(t *Type) *Node {
-	var n Node
+func temp(t *ir.Type) *ir.Node {
+	var n ir.Node
 	Tempname(&n, t)
 	n.Sym.Def.Used = true
 	return n.Orig
 }
 
-func gen(n *Node) {
+func gen(n *ir.Node) {
 	//dump("gen", n);
 
 	lno := setlineno(n)
@@ -638,24 +640,24 @@ func gen(n *Node) {
 
 	switch n.Op {
 	default:
-		Fatalf("gen: unknown op %v", Nconv(n, FmtShort|FmtSign))
-
-	case OCASE,
-		OFALL,
-		OXCASE,
-		OXFALL,
-		ODCLCONST,
-		ODCLFUNC,
-		ODCLTYPE:
+		Fatalf("gen: unknown op %v", Nconv(n, ir.FmtShort|ir.FmtSign))
+
+	case ir.OCASE,
+		ir.OFALL,
+		ir.OXCASE,
+		ir.OXFALL,
+		ir.ODCLCONST,
+		ir.ODCLFUNC,
+		ir.ODCLTYPE:
 		break
 
-	case OEMPTY:
+	case ir.OEMPTY:
 		break
 
-	case OBLOCK:
+	case ir.OBLOCK:
 		Genlist(n.List)
 
-	case OLABEL:
+	case ir.OLABEL:
 		if isblanksym(n.Left.Sym) {
 			break
 		}
@@ -677,7 +679,7 @@ func gen(n *Node) {
 		if n.Name.Defn != nil {
 			switch n.Name.Defn.Op {
 			// so stmtlabel can find the label
-			case OFOR, OSWITCH, OSELECT:
+			case ir.OFOR, ir.OSWITCH, ir.OSELECT:
 				n.Name.Defn.Sym = lab.Sym
 			}
 		}
@@ -689,7 +691,7 @@ func gen(n *Node) {
 	// valid code, since they're just going to another goto
 	// to the same label.  we'll unwind it when we learn the pc
 	// of the label in the OLABEL case above.)
-	case OGOTO:
+	case ir.OGOTO:
 		lab := newlab(n)
 
 		if lab.Labelpc != nil {
@@ -698,7 +700,7 @@ func gen(n *Node) {
 			lab.Gotopc = gjmp(lab.Gotopc)
 		}
 
-	case OBREAK:
+	case ir.OBREAK:
 		if n.Left != nil {
 			lab := n.Left.Sym.Label
 			if lab == nil {
@@ -723,7 +725,7 @@ func gen(n *Node) {
 
 		gjmp(breakpc)
 
-	case OCONTINUE:
+	case ir.OCONTINUE:
 		if n.Left != nil {
 			lab := n.Left.Sym.Label
 			if lab == nil {
@@ -748,7 +750,7 @@ func gen(n *Node) {
 
 		gjmp(continpc)
 
-	case OFOR:
+	case ir.OFOR:
 		sbreak := breakpc
 		p1 := gjmp(nil)     //		goto test
 		breakpc = gjmp(nil) // break:	goto done
@@ -775,7 +777,7 @@ func gen(n *Node) {
 			lab.Continpc = nil
 		}
 
-	case OIF:
+	case ir.OIF:
 		p1 := gjmp(nil)                         //		goto test
 		p2 := gjmp(nil)                         // p2:		goto else
 		Patch(p1, Pc)                           // test:
@@ -786,7 +788,7 @@ func gen(n *Node) {
 		Genlist(n.Rlist)                        //		else
 		Patch(p3, Pc)                           // done:
 
-	case OSWITCH:
+	case ir.OSWITCH:
 		sbreak := breakpc
 		p1 := gjmp(nil)     //		goto test
 		breakpc = gjmp(nil) // break:	goto done
@@ -805,7 +807,7 @@ func gen(n *Node) {
 			lab.Breakpc = nil
 		}
 
-	case OSELECT:
+	case ir.OSELECT:
 		sbreak := breakpc
 		p1 := gjmp(nil)     //		goto test
 		breakpc = gjmp(nil) // break:	goto done
@@ -824,53 +826,61 @@ func gen(n *Node) {
 			lab.Breakpc = nil
 		}
 
-	case ODCL:
+	case ir.ODCL:
 		cgen_dcl(n.Left)
 
-	case OAS:
+	case ir.OAS:
 		if gen_as_init(n, false) {
 			break
 		}
+		outer := genwb
+		genwb.wb, genwb.skipped = false, false
 		Cgen_as(n.Left, n.Right)
+		checkwbgen(n, genwb.wb, genwb.skipped)
+		genwb = outer
 
-	case OASWB:
+	case ir.OASWB:
+		outer := genwb
+		genwb.wb, genwb.skipped = false, false
 		Cgen_as_wb(n.Left, n.Right, true)
+		checkwbgen(n, genwb.wb, genwb.skipped)
+		genwb = outer
 
-	case OAS2DOTTYPE:
+	case ir.OAS2DOTTYPE:
 		cgen_dottype(n.Rlist.First(), n.List.First(), n.List.Second(), needwritebarrier(n.List.First(), n.Rlist.First()))
 
-	case OCALLMETH:
+	case ir.OCALLMETH:
 		cgen_callmeth(n, 0)
 
-	case OCALLINTER:
+	case ir.OCALLINTER:
 		cgen_callinter(n, nil, 0)
 
-	case OCALLFUNC:
+	case ir.OCALLFUNC:
 		cgen_call(n, 0)
 
-	case OPROC:
+	case ir.OPROC:
 		cgen_proc(n, 1)
 
-	case ODEFER:
+	case ir.ODEFER:
 		cgen_proc(n, 2)
 
-	case ORETURN, ORETJMP:
+	case ir.ORETURN, ir.ORETJMP:
 		cgen_ret(n)
 
 	// Function calls turned into compiler intrinsics.
 	// At top level, can just ignore the call and make sure to preserve side effects in the argument, if any.
-	case OGETG:
+	case ir.OGETG:
 		// nothing
-	case OSQRT:
+	case ir.OSQRT:
 		cgen_discard(n.Left)
 
-	case OCHECKNIL:
+	case ir.OCHECKNIL:
 		Cgen_checknil(n.Left)
 
-	case OVARKILL:
+	case ir.OVARKILL:
 		Gvarkill(n.Left)
 
-	case OVARLIVE:
+	case ir.OVARLIVE:
 		Gvarlive(n.Left)
 	}
 
@@ -883,11 +893,11 @@ ret:
 	lineno = lno
 }
 
-func Cgen_as(nl, nr *Node) {
+func Cgen_as(nl, nr *ir.Node) {
 	Cgen_as_wb(nl, nr, false)
 }
 
-func Cgen_as_wb(nl, nr *Node, wb bool) {
+func Cgen_as_wb(nl, nr *ir.Node, wb bool) {
 	if Debug['g'] != 0 {
 		op := "cgen_as"
 		if wb {
@@ -897,7 +907,7 @@ func Cgen_as_wb(nl, nr *Node, wb bool) {
 		Dump(op+" = ", nr)
 	}
 
-	for nr != nil && nr.Op == OCONVNOP {
+	for nr != nil && nr.Op == ir.OCONVNOP {
 		nr = nr.Left
 	}
 
@@ -908,7 +918,7 @@ func Cgen_as_wb(nl, nr *Node, wb bool) {
 
 	if nr == nil || iszero(nr) {
 		// heaps should already be clear
-		if nr == nil && (nl.Class&PHEAP != 0) {
+		if nr == nil && (nl.Class&ir.PHEAP != 0) {
 			return
 		}
 
@@ -917,7 +927,7 @@ func Cgen_as_wb(nl, nr *Node, wb bool) {
 			return
 		}
 		if Isfat(tl) {
-			if nl.Op == ONAME {
+			if nl.Op == ir.ONAME {
 				Gvardef(nl)
 			}
 			Thearch.Clearfat(nl)
@@ -936,30 +946,30 @@ func Cgen_as_wb(nl, nr *Node, wb bool) {
 	cgen_wb(nr, nl, wb)
 }
 
-func cgen_callmeth(n *Node, proc int) {
+func cgen_callmeth(n *ir.Node, proc int) {
 	// generate a rewrite in n2 for the method call
 	// (p.f)(...) goes to (f)(p,...)
 
 	l := n.Left
 
-	if l.Op != ODOTMETH {
+	if l.Op != ir.ODOTMETH {
 		Fatalf("cgen_callmeth: not dotmethod: %v", l)
 	}
 
 	n2 := *n
-	n2.Op = OCALLFUNC
+	n2.Op = ir.OCALLFUNC
 	n2.Left = newname(l.Sym)
 	n2.Left.Type = l.Type
 
-	if n2.Left.Op == ONAME {
-		n2.Left.Class = PFUNC
+	if n2.Left.Op == ir.ONAME {
+		n2.Left.Class = ir.PFUNC
 	}
 	cgen_call(&n2, proc)
 }
 
 // CgenTemp creates a temporary node, assigns n to it, and returns it.
-func CgenTemp(n *Node) *Node {
-	var tmp Node
+func CgenTemp(n *ir.Node) *ir.Node {
+	var tmp ir.Node
 	Tempname(&tmp, n.Type)
 	Cgen(n, &tmp)
 	return &tmp
@@ -992,19 +1002,19 @@ func checklabels() {
 // Slices, strings and interfaces are supported. Small structs or arrays with
 // elements of basic type are also supported.
 // nr is nil when assigning a zero value.
-func Componentgen(nr, nl *Node) bool {
+func Componentgen(nr, nl *ir.Node) bool {
 	return componentgen_wb(nr, nl, false)
 }
 
 // componentgen_wb is like componentgen but if wb==true emits write barriers for pointer updates.
-func componentgen_wb(nr, nl *Node, wb bool) bool {
+func componentgen_wb(nr, nl *ir.Node, wb bool) bool {
 	// Don't generate any code for complete copy of a variable into itself.
 	// It's useless, and the VARDEF will incorrectly mark the old value as dead.
 	// (This check assumes that the arguments passed to componentgen did not
 	// themselves come from Igen, or else we could have Op==ONAME but
 	// with a Type and Xoffset describing an individual field, not the entire
 	// variable.)
-	if nl.Op == ONAME && nl == nr {
+	if nl.Op == ir.ONAME && nl == nr {
 		return true
 	}
 
@@ -1014,7 +1024,7 @@ func componentgen_wb(nr, nl *Node, wb bool) bool {
 	const maxMoves = 8
 	n := 0
 	numPtr := 0
-	visitComponents(nl.Type, 0, func(t *Type, offset int64) bool {
+	visitComponents(nl.Type, 0, func(t *ir.Type, offset int64) bool {
 		n++
 		if Simtype[t.Etype] == Tptr && t != itable {
 			numPtr++
@@ -1028,21 +1038,21 @@ func componentgen_wb(nr, nl *Node, wb bool) bool {
 	// Must call emitVardef after evaluating rhs but before writing to lhs.
 	emitVardef := func() {
 		// Emit vardef if needed.
-		if nl.Op == ONAME {
+		if nl.Op == ir.ONAME {
 			switch nl.Type.Etype {
-			case TARRAY, TSTRING, TINTER, TSTRUCT:
+			case ir.TARRAY, ir.TSTRING, ir.TINTER, ir.TSTRUCT:
 				Gvardef(nl)
 			}
 		}
 	}
 
-	isConstString := Isconst(nr, CTSTR)
+	isConstString := ir.Isconst(nr, ir.CTSTR)
 
 	if !cadable(nl) && nr != nil && !cadable(nr) && !isConstString {
 		return false
 	}
 
-	var nodl Node
+	var nodl ir.Node
 	if cadable(nl) {
 		nodl = *nl
 	} else {
@@ -1057,24 +1067,24 @@ func componentgen_wb(nr, nl *Node, wb bool) bool {
 	lbase := nodl.Xoffset
 
 	// Special case: zeroing.
-	var nodr Node
+	var nodr ir.Node
 	if nr == nil {
 		// When zeroing, prepare a register containing zero.
 		// TODO(rsc): Check that this is actually generating the best code.
 		if Thearch.REGZERO != 0 {
 			// cpu has a dedicated zero register
-			Nodreg(&nodr, Types[TUINT], Thearch.REGZERO)
+			Nodreg(&nodr, ir.Types[ir.TUINT], Thearch.REGZERO)
 		} else {
 			// no dedicated zero register
-			var zero Node
+			var zero ir.Node
 			Nodconst(&zero, nl.Type, 0)
-			Regalloc(&nodr, Types[TUINT], nil)
+			Regalloc(&nodr, ir.Types[ir.TUINT], nil)
 			Thearch.Gmove(&zero, &nodr)
 			defer Regfree(&nodr)
 		}
 
 		emitVardef()
-		visitComponents(nl.Type, 0, func(t *Type, offset int64) bool {
+		visitComponents(nl.Type, 0, func(t *ir.Type, offset int64) bool {
 			nodl.Type = t
 			nodl.Xoffset = lbase + offset
 			nodr.Type = t
@@ -1094,16 +1104,16 @@ func componentgen_wb(nr, nl *Node, wb bool) bool {
 		emitVardef()
 
 		// base
-		nodl.Type = Ptrto(Types[TUINT8])
-		Regalloc(&nodr, Types[Tptr], nil)
-		p := Thearch.Gins(Thearch.Optoas(OAS, Types[Tptr]), nil, &nodr)
+		nodl.Type = Ptrto(ir.Types[ir.TUINT8])
+		Regalloc(&nodr, ir.Types[Tptr], nil)
+		p := Thearch.Gins(Thearch.Optoas(ir.OAS, ir.Types[Tptr]), nil, &nodr)
 		Datastring(nr.Val().U.(string), &p.From)
 		p.From.Type = obj.TYPE_ADDR
 		Thearch.Gmove(&nodr, &nodl)
 		Regfree(&nodr)
 
 		// length
-		nodl.Type = Types[Simtype[TUINT]]
+		nodl.Type = ir.Types[Simtype[ir.TUINT]]
 		nodl.Xoffset += int64(Array_nel) - int64(Array_array)
 		Nodconst(&nodr, nodl.Type, int64(len(nr.Val().U.(string))))
 		Thearch.Gmove(&nodr, &nodl)
@@ -1113,7 +1123,7 @@ func componentgen_wb(nr, nl *Node, wb bool) bool {
 	// General case: copy nl = nr.
 	nodr = *nr
 	if !cadable(nr) {
-		if nr.Ullman >= UINF && nodl.Op == OINDREG {
+		if nr.Ullman >= UINF && nodl.Op == ir.OINDREG {
 			Fatalf("miscompile")
 		}
 		Igen(nr, &nodr, nil)
@@ -1129,10 +1139,10 @@ func componentgen_wb(nr, nl *Node, wb bool) bool {
 
 	emitVardef()
 	var (
-		ptrType   *Type
+		ptrType   *ir.Type
 		ptrOffset int64
 	)
-	visitComponents(nl.Type, 0, func(t *Type, offset int64) bool {
+	visitComponents(nl.Type, 0, func(t *ir.Type, offset int64) bool {
 		if wb && Simtype[t.Etype] == Tptr && t != itable {
 			if ptrType != nil {
 				Fatalf("componentgen_wb %v", Tconv(nl.Type, 0))
@@ -1166,9 +1176,9 @@ func componentgen_wb(nr, nl *Node, wb bool) bool {
 // type and memory offset, assuming t starts at startOffset.
 // If f ever returns false, visitComponents returns false without any more
 // calls to f. Otherwise visitComponents returns true.
-func visitComponents(t *Type, startOffset int64, f func(elem *Type, elemOffset int64) bool) bool {
+func visitComponents(t *ir.Type, startOffset int64, f func(elem *ir.Type, elemOffset int64) bool) bool {
 	switch t.Etype {
-	case TINT64:
+	case ir.TINT64:
 		if Widthreg == 8 {
 			break
 		}
@@ -1177,37 +1187,37 @@ func visitComponents(t *Type, startOffset int64, f func(elem *Type, elemOffset i
 		if Thearch.Thechar != '5' && Thearch.Thechar != '8' {
 			Fatalf("unknown 32-bit architecture")
 		}
-		return f(Types[TUINT32], startOffset) &&
-			f(Types[TINT32], startOffset+4)
+		return f(ir.Types[ir.TUINT32], startOffset) &&
+			f(ir.Types[ir.TINT32], startOffset+4)
 
-	case TUINT64:
+	case ir.TUINT64:
 		if Widthreg == 8 {
 			break
 		}
-		return f(Types[TUINT32], startOffset) &&
-			f(Types[TUINT32], startOffset+4)
+		return f(ir.Types[ir.TUINT32], startOffset) &&
+			f(ir.Types[ir.TUINT32], startOffset+4)
 
-	case TCOMPLEX64:
-		return f(Types[TFLOAT32], startOffset) &&
-			f(Types[TFLOAT32], startOffset+4)
+	case ir.TCOMPLEX64:
+		return f(ir.Types[ir.TFLOAT32], startOffset) &&
+			f(ir.Types[ir.TFLOAT32], startOffset+4)
 
-	case TCOMPLEX128:
-		return f(Types[TFLOAT64], startOffset) &&
-			f(Types[TFLOAT64], startOffset+8)
+	case ir.TCOMPLEX128:
+		return f(ir.Types[ir.TFLOAT64], startOffset) &&
+			f(ir.Types[ir.TFLOAT64], startOffset+8)
 
-	case TINTER:
+	case ir.TINTER:
 		return f(itable, startOffset) &&
-			f(Ptrto(Types[TUINT8]), startOffset+int64(Widthptr))
+			f(Ptrto(ir.Types[ir.TUINT8]), startOffset+int64(Widthptr))
 
-	case TSTRING:
-		return f(Ptrto(Types[TUINT8]), startOffset) &&
-			f(Types[Simtype[TUINT]], startOffset+int64(Widthptr))
+	case ir.TSTRING:
+		return f(Ptrto(ir.Types[ir.TUINT8]), startOffset) &&
+			f(ir.Types[Simtype[ir.TUINT]], startOffset+int64(Widthptr))
 
-	case TARRAY:
+	case ir.TARRAY:
 		if Isslice(t) {
 			return f(Ptrto(t.Type), startOffset+int64(Array_array)) &&
-				f(Types[Simtype[TUINT]], startOffset+int64(Array_nel)) &&
-				f(Types[Simtype[TUINT]], startOffset+int64(Array_cap))
+				f(ir.Types[Simtype[ir.TUINT]], startOffset+int64(Array_nel)) &&
+				f(ir.Types[Simtype[ir.TUINT]], startOffset+int64(Array_cap))
 		}
 
 		// Short-circuit [1e6]struct{}.
@@ -1222,7 +1232,7 @@ func visitComponents(t *Type, startOffset int64, f func(elem *Type, elemOffset i
 		}
 		return true
 
-	case TSTRUCT:
+	case ir.TSTRUCT:
 		if t.Type != nil && t.Type.Width != 0 {
 			// NOTE(rsc): If this happens, the right thing to do is to say
 			//	startOffset -= t.Type.Width
@@ -1244,7 +1254,7 @@ func visitComponents(t *Type, startOffset int64, f func(elem *Type, elemOffset i
 	return f(t, startOffset)
 }
 
-func cadable(n *Node) bool {
+func cadable(n *ir.Node) bool {
 	// Note: Not sure why you can have n.Op == ONAME without n.Addable, but you can.
-	return n.Addable && n.Op == ONAME
+	return n.Addable && n.Op == ir.ONAME
 }
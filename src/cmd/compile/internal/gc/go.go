@@ -6,6 +6,7 @@ package gc
 
 import (
 	"bytes"
+	"cmd/compile/internal/ir"
 	"cmd/compile/internal/ssa"
 	"cmd/internal/obj"
 )
@@ -13,121 +14,10 @@ import (
 const (
 	UINF            = 100
 	PRIME1          = 3
-	BADWIDTH        = -1000000000
 	MaxStackVarSize = 10 * 1024 * 1024
 )
 
-type Val struct {
-	// U contains one of:
-	// bool     bool when n.ValCtype() == CTBOOL
-	// *Mpint   int when n.ValCtype() == CTINT, rune when n.ValCtype() == CTRUNE
-	// *Mpflt   float when n.ValCtype() == CTFLT
-	// *Mpcplx  pair of floats when n.ValCtype() == CTCPLX
-	// string   string when n.ValCtype() == CTSTR
-	// *Nilval  when n.ValCtype() == CTNIL
-	U interface{}
-}
-
-type NilVal struct{}
-
-func (v Val) Ctype() Ctype {
-	switch x := v.U.(type) {
-	default:
-		Fatalf("unexpected Ctype for %T", v.U)
-		panic("not reached")
-	case nil:
-		return 0
-	case *NilVal:
-		return CTNIL
-	case bool:
-		return CTBOOL
-	case *Mpint:
-		if x.Rune {
-			return CTRUNE
-		}
-		return CTINT
-	case *Mpflt:
-		return CTFLT
-	case *Mpcplx:
-		return CTCPLX
-	case string:
-		return CTSTR
-	}
-}
-
-type Pkg struct {
-	Name     string // package name
-	Path     string // string literal used in import statement
-	Pathsym  *Sym
-	Prefix   string // escaped path for use in symbol table
-	Imported bool   // export data of this package was parsed
-	Exported bool   // import line written in export data
-	Direct   bool   // imported directly
-	Safe     bool   // whether the package is marked as safe
-	Syms     map[string]*Sym
-}
-
-type Sym struct {
-	Flags     SymFlags
-	Link      *Sym
-	Importdef *Pkg   // where imported definition was found
-	Linkname  string // link name
-
-	// saved and restored by dcopy
-	Pkg        *Pkg
-	Name       string // variable name
-	Def        *Node  // definition: ONAME OTYPE OPACK or OLITERAL
-	Block      int32  // blocknumber to catch redeclaration
-	Lastlineno int32  // last declaration for diagnostic
-
-	Label   *Label // corresponding label (ephemeral)
-	Origpkg *Pkg   // original package for . import
-	Lsym    *obj.LSym
-	Fsym    *Sym // funcsym
-}
-
-type Label struct {
-	Sym *Sym
-	Def *Node
-	Use []*Node
-
-	// for use during gen
-	Gotopc   *obj.Prog // pointer to unresolved gotos
-	Labelpc  *obj.Prog // pointer to code
-	Breakpc  *obj.Prog // pointer to code
-	Continpc *obj.Prog // pointer to code
-
-	Used bool
-}
-
-type SymFlags uint8
-
-const (
-	SymExport SymFlags = 1 << iota // to be exported
-	SymPackage
-	SymExported // already written out by export
-	SymUniq
-	SymSiggen
-	SymAsm
-	SymAlgGen
-)
-
-var dclstack *Sym
-
-// Ctype describes the constant kind of an "ideal" (untyped) constant.
-type Ctype int8
-
-const (
-	CTxxx Ctype = iota
-
-	CTINT
-	CTRUNE
-	CTFLT
-	CTCPLX
-	CTSTR
-	CTBOOL
-	CTNIL
-)
+var dclstack *ir.Sym
 
 const (
 	// types of channel
@@ -137,25 +27,6 @@ const (
 	Cboth = Crecv | Csend
 )
 
-// The Class of a variable/function describes the "storage class"
-// of a variable or function. During parsing, storage classes are
-// called declaration contexts.
-type Class uint8
-
-const (
-	Pxxx      Class = iota
-	PEXTERN         // global variable
-	PAUTO           // local variables
-	PPARAM          // input arguments
-	PPARAMOUT       // output results
-	PPARAMREF       // closure variable reference
-	PFUNC           // global function
-
-	PDISCARD // discard during parse of duplicate import
-
-	PHEAP = 1 << 7 // an extra bit to identify an escaped variable
-)
-
 const (
 	Etop      = 1 << 1 // evaluated at statement level
 	Erv       = 1 << 2 // evaluated in value context
@@ -172,11 +43,11 @@ const (
 
 type Sig struct {
 	name   string
-	pkg    *Pkg
-	isym   *Sym
-	tsym   *Sym
-	type_  *Type
-	mtype  *Type
+	pkg    *ir.Pkg
+	isym   *ir.Sym
+	tsym   *ir.Sym
+	type_  *ir.Type
+	mtype  *ir.Type
 	offset int32
 }
 
@@ -220,6 +91,8 @@ var infile string
 
 var outfile string
 
+var linkobj string
+
 var bout *obj.Biobuf
 
 var nerrors int
@@ -230,6 +103,12 @@ var nsyntaxerrors int
 
 var decldepth int32
 
+// loopstart[d] is the value of loopgen when the loop or label
+// that raised decldepth to d began; see markloop.
+var loopstart []int32
+
+var loopgen int32
+
 var safemode int
 
 var nolocalimports int
@@ -245,27 +124,35 @@ var debugstr string
 var Debug_checknil int
 var Debug_typeassert int
 
-var localpkg *Pkg // package being compiled
+var localpkg *ir.Pkg // package being compiled
+
+var importpkg *ir.Pkg // package being imported
+
+var itabpkg *ir.Pkg // fake pkg for itab cache
 
-var importpkg *Pkg // package being imported
+var Runtimepkg *ir.Pkg // package runtime
 
-var itabpkg *Pkg // fake pkg for itab cache
+var racepkg *ir.Pkg // package runtime/race
 
-var Runtimepkg *Pkg // package runtime
+var msanpkg *ir.Pkg // package runtime/msan
 
-var racepkg *Pkg // package runtime/race
+var asanpkg *ir.Pkg // package runtime/asan
 
-var msanpkg *Pkg // package runtime/msan
+var typepkg *ir.Pkg // fake package for runtime type info (headers)
 
-var typepkg *Pkg // fake package for runtime type info (headers)
+var typelinkpkg *ir.Pkg // fake package for runtime type info (data)
 
-var typelinkpkg *Pkg // fake package for runtime type info (data)
+var unsafepkg *ir.Pkg // package unsafe
 
-var unsafepkg *Pkg // package unsafe
+var trackpkg *ir.Pkg // fake package for field tracking
 
-var trackpkg *Pkg // fake package for field tracking
+var reflectmethodpkg *ir.Pkg // fake package for reflect method names
 
-var Tptr EType // either TPTR32 or TPTR64
+var dwarfdeclspkg *ir.Pkg // fake package for -dwarfdecls
+
+var srcfilepkg *ir.Pkg // fake package for -embedsrc
+
+var Tptr ir.EType // either TPTR32 or TPTR64
 
 var myimportpath string
 
@@ -273,65 +160,86 @@ var localimport string
 
 var asmhdr string
 
-var Simtype [NTYPE]EType
+var dwarfdecls string
+
+var embedsrc string
+
+// dwarflevel is the -dwarf setting: how much of the package's
+// functions is described in DWARF.
+var dwarflevel int
+
+const (
+	dwarfNone  = iota // leave the functions out
+	dwarfLines        // functions and line tables
+	dwarfVars         // also local variables and their lexical blocks
+)
+
+var Simtype [ir.NTYPE]ir.EType
 
 var (
-	Isptr     [NTYPE]bool
-	isforw    [NTYPE]bool
-	Isint     [NTYPE]bool
-	Isfloat   [NTYPE]bool
-	Iscomplex [NTYPE]bool
-	Issigned  [NTYPE]bool
-	issimple  [NTYPE]bool
+	Isptr     [ir.NTYPE]bool
+	isforw    [ir.NTYPE]bool
+	Isint     [ir.NTYPE]bool
+	Isfloat   [ir.NTYPE]bool
+	Iscomplex [ir.NTYPE]bool
+	Issigned  [ir.NTYPE]bool
+	issimple  [ir.NTYPE]bool
 )
 
 var (
-	okforeq    [NTYPE]bool
-	okforadd   [NTYPE]bool
-	okforand   [NTYPE]bool
-	okfornone  [NTYPE]bool
-	okforcmp   [NTYPE]bool
-	okforbool  [NTYPE]bool
-	okforcap   [NTYPE]bool
-	okforlen   [NTYPE]bool
-	okforarith [NTYPE]bool
-	okforconst [NTYPE]bool
+	okforeq    [ir.NTYPE]bool
+	okforadd   [ir.NTYPE]bool
+	okforand   [ir.NTYPE]bool
+	okfornone  [ir.NTYPE]bool
+	okforcmp   [ir.NTYPE]bool
+	okforbool  [ir.NTYPE]bool
+	okforcap   [ir.NTYPE]bool
+	okforlen   [ir.NTYPE]bool
+	okforarith [ir.NTYPE]bool
+	okforconst [ir.NTYPE]bool
 )
 
 var (
-	okfor [OEND][]bool
-	iscmp [OEND]bool
+	okfor [ir.OEND][]bool
+	iscmp [ir.OEND]bool
 )
 
-var Minintval [NTYPE]*Mpint
+var Minintval [ir.NTYPE]*ir.Mpint
 
-var Maxintval [NTYPE]*Mpint
+var Maxintval [ir.NTYPE]*ir.Mpint
 
-var minfltval [NTYPE]*Mpflt
+var minfltval [ir.NTYPE]*ir.Mpflt
 
-var maxfltval [NTYPE]*Mpflt
+var maxfltval [ir.NTYPE]*ir.Mpflt
 
-var xtop []*Node
+var xtop []*ir.Node
 
-var externdcl []*Node
+var externdcl []*ir.Node
 
-var exportlist []*Node
+var exportlist []*ir.Node
 
-var importlist []*Node // imported functions and methods with inlinable bodies
+var importlist []*ir.Node // imported functions and methods with inlinable bodies
 
-var funcsyms []*Node
+var funcsyms []*ir.Node
 
-var dclcontext Class // PEXTERN/PAUTO
+var dclcontext ir.Class // PEXTERN/PAUTO
 
 var incannedimport int
 
-var statuniqgen int // name generator for static temps
+var statuniqgen int // name generator for autotmps
+
+// statictmpgen numbers the static temps. It is separate from statuniqgen
+// so that the names seen by later functions do not depend on how many
+// temporaries the back end allocated, which -fncache relies on.
+var statictmpgen int
+
+var rodatasyms []*ir.Sym // read-only static temps, see dedupRodata
 
 var iota_ int32
 
-var lastconst []*Node
+var lastconst []*ir.Node
 
-var lasttype *Node
+var lasttype *ir.Node
 
 var Maxarg int64
 
@@ -343,9 +251,13 @@ var blockgen int32 // max block number
 
 var block int32 // current block number
 
+// blocks describes each block number handed out by markdcl,
+// for the lexical scopes that compile records in DWARF.
+var blocks []blockinfo
+
 var hasdefer bool // flag that curfn has defer statement
 
-var Curfn *Node
+var Curfn *ir.Node
 
 var Widthptr int
 
@@ -353,7 +265,7 @@ var Widthint int
 
 var Widthreg int
 
-var nblank *Node
+var nblank *ir.Node
 
 var Funcdepth int32
 
@@ -373,6 +285,8 @@ var flag_race int
 
 var flag_msan int
 
+var flag_asan int
+
 var flag_largemodel int
 
 // Whether we are adding any sort of code instrumentation, such as
@@ -395,7 +309,7 @@ var breakpc *obj.Prog
 
 var Pc *obj.Prog
 
-var nodfp *Node
+var nodfp *ir.Node
 
 var Disable_checknil int
 
@@ -482,6 +396,14 @@ const (
 	PostInc = 1 << 29
 )
 
+// Arch describes the target of a compiler back end and holds the hooks
+// the portable code calls to generate code for it. Main has the
+// Backend for $GOARCH fill in Thearch; see Backend for the order in
+// which the hooks are used. The optimizer in popt.go and reg.go also
+// calls Proginfo and the register bitmap hooks.
+//
+// Fields marked optional may be left unset; checkArch reports any
+// other hook a back end forgot to provide.
 type Arch struct {
 	Thechar      int
 	Thestring    string
@@ -499,23 +421,22 @@ type Arch struct {
 	MAXWIDTH     int64
 	ReservedRegs []int
 
-	AddIndex     func(*Node, int64, *Node) bool // optional
-	Betypeinit   func()
-	Bgen_float   func(*Node, bool, int, *obj.Prog) // optional
-	Cgen64       func(*Node, *Node)                // only on 32-bit systems
-	Cgenindex    func(*Node, *Node, bool) *obj.Prog
-	Cgen_bmul    func(Op, *Node, *Node, *Node) bool
-	Cgen_float   func(*Node, *Node) // optional
-	Cgen_hmul    func(*Node, *Node, *Node)
-	Cgen_shift   func(Op, bool, *Node, *Node, *Node)
-	Clearfat     func(*Node)
-	Cmp64        func(*Node, *Node, Op, int, *obj.Prog) // only on 32-bit systems
-	Defframe     func(*obj.Prog)
-	Dodiv        func(Op, *Node, *Node, *Node)
-	Excise       func(*Flow)
+	AddIndex     func(*ir.Node, int64, *ir.Node) bool           // optional
+	Bgen_float   func(*ir.Node, bool, int, *obj.Prog)           // optional
+	Cgen64       func(*ir.Node, *ir.Node)                       // only on 32-bit systems
+	Cgenindex    func(*ir.Node, *ir.Node, bool) *obj.Prog       // required on arm ('5')
+	Cgen_bmul    func(ir.Op, *ir.Node, *ir.Node, *ir.Node) bool // optional
+	Cgen_float   func(*ir.Node, *ir.Node)                       // optional
+	Cgen_hmul    func(*ir.Node, *ir.Node, *ir.Node)
+	Cgen_shift   func(ir.Op, bool, *ir.Node, *ir.Node, *ir.Node)
+	Clearfat     func(*ir.Node)
+	Copyas       func(a, v *obj.Addr) bool                       // see peep.go
+	Copyu        func(p *obj.Prog, v, s *obj.Addr) int           // see peep.go
+	Cmp64        func(*ir.Node, *ir.Node, ir.Op, int, *obj.Prog) // only on 32-bit systems
+	Dodiv        func(ir.Op, *ir.Node, *ir.Node, *ir.Node)       // optional; if unset, Cgen uses Optoas(ODIV) and Optoas(OMOD)
 	Expandchecks func(*obj.Prog)
-	Getg         func(*Node)
-	Gins         func(obj.As, *Node, *Node) *obj.Prog
+	Getg         func(*ir.Node)
+	Gins         func(obj.As, *ir.Node, *ir.Node) *obj.Prog
 
 	// Ginscmp generates code comparing n1 to n2 and jumping away if op is satisfied.
 	// The returned prog should be Patch'ed with the jump target.
@@ -527,7 +448,7 @@ type Arch struct {
 	// function calls needed during the evaluation, and on 32-bit systems
 	// the values are guaranteed not to be 64-bit values, so no in-memory
 	// temporaries are necessary.
-	Ginscmp func(op Op, t *Type, n1, n2 *Node, likely int) *obj.Prog
+	Ginscmp func(op ir.Op, t *ir.Type, n1, n2 *ir.Node, likely int) *obj.Prog
 
 	// Ginsboolval inserts instructions to convert the result
 	// of a just-completed comparison to a boolean value.
@@ -535,31 +456,50 @@ type Arch struct {
 	// corresponding to the desired value.
 	// The second argument is the destination.
 	// If not present, Ginsboolval will be emulated with jumps.
-	Ginsboolval func(obj.As, *Node)
+	Ginsboolval func(obj.As, *ir.Node)
 
-	Ginscon      func(obj.As, int64, *Node)
+	Ginscon      func(obj.As, int64, *ir.Node)
 	Ginsnop      func()
-	Gmove        func(*Node, *Node)
-	Igenindex    func(*Node, *Node, bool) *obj.Prog
-	Peep         func(*obj.Prog)
-	Proginfo     func(*obj.Prog) // fills in Prog.Info
+	Gmove        func(*ir.Node, *ir.Node)
+	Igenindex    func(*ir.Node, *ir.Node, bool) *obj.Prog // required on 386 ('8')
+	Proginfo     func(*obj.Prog)                          // fills in Prog.Info
 	Regtyp       func(*obj.Addr) bool
 	Sameaddr     func(*obj.Addr, *obj.Addr) bool
 	Smallindir   func(*obj.Addr, *obj.Addr) bool
 	Stackaddr    func(*obj.Addr) bool
-	Blockcopy    func(*Node, *Node, int64, int64, int64)
-	Sudoaddable  func(obj.As, *Node, *obj.Addr) bool
+	Blockcopy    func(*ir.Node, *ir.Node, int64, int64, int64)
+	Sudoaddable  func(obj.As, *ir.Node, *obj.Addr) bool
 	Sudoclean    func()
 	Excludedregs func() uint64
 	RtoB         func(int) uint64
 	FtoB         func(int) uint64
 	BtoR         func(uint64) int
 	BtoF         func(uint64) int
-	Optoas       func(Op, *Type) obj.As
+	Optoas       func(ir.Op, *ir.Type) obj.As
 	Doregbits    func(int) uint64
 	Regnames     func(*int) []string
 	Use387       bool // should 8g use 387 FP instructions instead of sse2.
 
+	// Duffzero and Duffcopy are the block sizes for which Clearfat
+	// and Blockcopy, and the SSA back end's lowering of Zero and
+	// Move, use the runtime's Duff's device routines.
+	// They can be overridden with the -d duff* debug flags.
+	// The amd64 and 386 defaults come from benchmarks; arm, arm64
+	// and ppc64 use the cutoffs their back ends had hard-coded.
+	Duffzero DuffRange
+	Duffcopy DuffRange
+
+	// ZeroRange inserts code after p to zero the stack words
+	// [frame+lo, frame+hi) of the function's frame and returns the
+	// last instruction it added. State is zero for the first call in
+	// a function; ZeroRange may record in it which registers it has
+	// already loaded with zero.
+	ZeroRange func(p *obj.Prog, frame, lo, hi int64, state *uint32) *obj.Prog
+
+	// PadFrame, if set, returns the frame size to use for a
+	// function needing frame bytes of locals and outgoing arguments.
+	PadFrame func(frame int64) int64
+
 	// SSARegToReg maps ssa register numbers to obj register numbers.
 	SSARegToReg []int16
 
@@ -574,27 +514,42 @@ type Arch struct {
 	SSAGenBlock func(s *SSAGenState, b, next *ssa.Block)
 }
 
+// DuffRange is a range of block sizes, in words, that are zeroed or
+// copied by calling into duffzero or duffcopy. Blocks of more than Max
+// words use a loop instead; otherwise blocks of fewer than Min words
+// use unrolled moves. Max cannot exceed DuffMaxWords, the number of
+// words the runtime routines handle.
+type DuffRange struct {
+	Min int64
+	Max int64
+}
+
+const DuffMaxWords = 128
+
 var pcloc int32
 
+// Thearch is the back end for the target architecture.
 var Thearch Arch
 
-var Newproc *Node
+var Newproc *ir.Node
+
+var Deferproc *ir.Node
 
-var Deferproc *Node
+var Deferproc0 *ir.Node
 
-var Deferreturn *Node
+var Deferreturn *ir.Node
 
-var Panicindex *Node
+var Panicindex *ir.Node
 
-var panicslice *Node
+var panicslice *ir.Node
 
-var panicdivide *Node
+var panicdivide *ir.Node
 
-var throwreturn *Node
+var throwreturn *ir.Node
 
-var growslice *Node
+var growslice *ir.Node
 
-var writebarrierptr *Node
-var typedmemmove *Node
+var writebarrierptr *ir.Node
+var typedmemmove *ir.Node
 
-var panicdottype *Node
+var panicdottype *ir.Node
@@ -31,6 +31,7 @@
 package gc
 
 import (
+	"cmd/compile/internal/ir"
 	"cmd/internal/obj"
 	"fmt"
 	"runtime"
@@ -44,33 +45,33 @@ var (
 )
 
 // Is this node a memory operand?
-func Ismem(n *Node) bool {
+func Ismem(n *ir.Node) bool {
 	switch n.Op {
-	case OITAB,
-		OSPTR,
-		OLEN,
-		OCAP,
-		OINDREG,
-		ONAME,
-		OPARAM,
-		OCLOSUREVAR:
+	case ir.OITAB,
+		ir.OSPTR,
+		ir.OLEN,
+		ir.OCAP,
+		ir.OINDREG,
+		ir.ONAME,
+		ir.OPARAM,
+		ir.OCLOSUREVAR:
 		return true
 
-	case OADDR:
+	case ir.OADDR:
 		return Thearch.Thechar == '6' || Thearch.Thechar == '9' // because 6g uses PC-relative addressing; TODO(rsc): not sure why 9g too
 	}
 
 	return false
 }
 
-func Samereg(a *Node, b *Node) bool {
+func Samereg(a *ir.Node, b *ir.Node) bool {
 	if a == nil || b == nil {
 		return false
 	}
-	if a.Op != OREGISTER {
+	if a.Op != ir.OREGISTER {
 		return false
 	}
-	if b.Op != OREGISTER {
+	if b.Op != ir.OREGISTER {
 		return false
 	}
 	if a.Reg != b.Reg {
@@ -79,7 +80,7 @@ func Samereg(a *Node, b *Node) bool {
 	return true
 }
 
-func Gbranch(as obj.As, t *Type, likely int) *obj.Prog {
+func Gbranch(as obj.As, t *ir.Type, likely int) *obj.Prog {
 	p := Prog(as)
 	p.To.Type = obj.TYPE_BRANCH
 	p.To.Val = nil
@@ -99,6 +100,7 @@ func Gbranch(as obj.As, t *Type, likely int) *obj.Prog {
 
 func Prog(as obj.As) *obj.Prog {
 	var p *obj.Prog
+	counts.progs++
 
 	if as == obj.AGLOBL {
 		if ddumped {
@@ -128,25 +130,25 @@ func Prog(as obj.As) *obj.Prog {
 	return p
 }
 
-func Nodreg(n *Node, t *Type, r int) {
+func Nodreg(n *ir.Node, t *ir.Type, r int) {
 	if t == nil {
 		Fatalf("nodreg: t nil")
 	}
 
-	*n = Node{}
-	n.Op = OREGISTER
+	*n = ir.Node{}
+	n.Op = ir.OREGISTER
 	n.Addable = true
 	ullmancalc(n)
 	n.Reg = int16(r)
 	n.Type = t
 }
 
-func Nodindreg(n *Node, t *Type, r int) {
+func Nodindreg(n *ir.Node, t *ir.Type, r int) {
 	Nodreg(n, t, r)
-	n.Op = OINDREG
+	n.Op = ir.OINDREG
 }
 
-func Afunclit(a *obj.Addr, n *Node) {
+func Afunclit(a *obj.Addr, n *ir.Node) {
 	if a.Type == obj.TYPE_ADDR && a.Name == obj.NAME_EXTERN {
 		a.Type = obj.TYPE_MEM
 		a.Sym = Linksym(n.Sym)
@@ -160,6 +162,24 @@ func Clearp(p *obj.Prog) {
 	pcloc++
 }
 
+// Appendpp inserts a new instruction after p and returns it.
+// The new instruction has p's line number and the given operands.
+func Appendpp(p *obj.Prog, as obj.As, ftype obj.AddrType, freg int, foffset int64, ttype obj.AddrType, treg int, toffset int64) *obj.Prog {
+	q := Ctxt.NewProg()
+	Clearp(q)
+	q.As = as
+	q.Lineno = p.Lineno
+	q.From.Type = ftype
+	q.From.Reg = int16(freg)
+	q.From.Offset = foffset
+	q.To.Type = ttype
+	q.To.Reg = int16(treg)
+	q.To.Offset = toffset
+	q.Link = p.Link
+	p.Link = q
+	return q
+}
+
 func dumpdata() {
 	ddumped = true
 	if dfirst == nil {
@@ -190,12 +210,12 @@ func fixautoused(p *obj.Prog) {
 		if p == nil {
 			break
 		}
-		if p.As == obj.ATYPE && p.From.Node != nil && p.From.Name == obj.NAME_AUTO && !((p.From.Node).(*Node)).Used {
+		if p.As == obj.ATYPE && p.From.Node != nil && p.From.Name == obj.NAME_AUTO && !((p.From.Node).(*ir.Node)).Used {
 			*lp = p.Link
 			continue
 		}
 
-		if (p.As == obj.AVARDEF || p.As == obj.AVARKILL || p.As == obj.AVARLIVE) && p.To.Node != nil && !((p.To.Node).(*Node)).Used {
+		if (p.As == obj.AVARDEF || p.As == obj.AVARKILL || p.As == obj.AVARLIVE) && p.To.Node != nil && !((p.To.Node).(*ir.Node)).Used {
 			// Cannot remove VARDEF instruction, because - unlike TYPE handled above -
 			// VARDEFs are interspersed with other code, and a jump might be using the
 			// VARDEF as a target. Replace with a no-op instead. A later pass will remove
@@ -206,18 +226,18 @@ func fixautoused(p *obj.Prog) {
 		}
 
 		if p.From.Name == obj.NAME_AUTO && p.From.Node != nil {
-			p.From.Offset += stkdelta[p.From.Node.(*Node)]
+			p.From.Offset += stkdelta[p.From.Node.(*ir.Node)]
 		}
 
 		if p.To.Name == obj.NAME_AUTO && p.To.Node != nil {
-			p.To.Offset += stkdelta[p.To.Node.(*Node)]
+			p.To.Offset += stkdelta[p.To.Node.(*ir.Node)]
 		}
 
 		lp = &p.Link
 	}
 }
 
-func ggloblnod(nam *Node) {
+func ggloblnod(nam *ir.Node) {
 	p := Thearch.Gins(obj.AGLOBL, nam, nil)
 	p.Lineno = nam.Lineno
 	p.From.Sym.Gotype = Linksym(ngotype(nam))
@@ -233,7 +253,7 @@ func ggloblnod(nam *Node) {
 	}
 }
 
-func ggloblsym(s *Sym, width int32, flags int16) {
+func ggloblsym(s *ir.Sym, width int32, flags int16) {
 	ggloblLSym(Linksym(s), width, flags)
 }
 
@@ -260,22 +280,22 @@ func gjmp(to *obj.Prog) *obj.Prog {
 	return p
 }
 
-func gtrack(s *Sym) {
+func gtrack(s *ir.Sym) {
 	p := Thearch.Gins(obj.AUSEFIELD, nil, nil)
 	p.From.Type = obj.TYPE_MEM
 	p.From.Name = obj.NAME_EXTERN
 	p.From.Sym = Linksym(s)
 }
 
-func gused(n *Node) {
+func gused(n *ir.Node) {
 	Thearch.Gins(obj.ANOP, n, nil) // used
 }
 
-func Isfat(t *Type) bool {
+func Isfat(t *ir.Type) bool {
 	if t != nil {
 		switch t.Etype {
-		case TSTRUCT, TARRAY, TSTRING,
-			TINTER: // maybe remove later
+		case ir.TSTRUCT, ir.TARRAY, ir.TSTRING,
+			ir.TINTER: // maybe remove later
 			return true
 		}
 	}
@@ -291,23 +311,23 @@ func markautoused(p *obj.Prog) {
 		}
 
 		if p.From.Node != nil {
-			((p.From.Node).(*Node)).Used = true
+			((p.From.Node).(*ir.Node)).Used = true
 		}
 
 		if p.To.Node != nil {
-			((p.To.Node).(*Node)).Used = true
+			((p.To.Node).(*ir.Node)).Used = true
 		}
 	}
 }
 
 // Naddr rewrites a to refer to n.
 // It assumes that a is zeroed on entry.
-func Naddr(a *obj.Addr, n *Node) {
+func Naddr(a *obj.Addr, n *ir.Node) {
 	if n == nil {
 		return
 	}
 
-	if n.Type != nil && n.Type.Etype != TIDEAL {
+	if n.Type != nil && n.Type.Etype != ir.TIDEAL {
 		// TODO(rsc): This is undone by the selective clearing of width below,
 		// to match architectures that were not as aggressive in setting width
 		// during naddr. Those widths must be cleared to avoid triggering
@@ -326,7 +346,7 @@ func Naddr(a *obj.Addr, n *Node) {
 		Dump("naddr", n)
 		Fatalf("naddr: bad %v %v", Oconv(n.Op, 0), Ctxt.Dconv(a))
 
-	case OREGISTER:
+	case ir.OREGISTER:
 		a.Type = obj.TYPE_REG
 		a.Reg = n.Reg
 		a.Sym = nil
@@ -334,7 +354,7 @@ func Naddr(a *obj.Addr, n *Node) {
 			a.Width = 0
 		}
 
-	case OINDREG:
+	case ir.OINDREG:
 		a.Type = obj.TYPE_MEM
 		a.Reg = n.Reg
 		a.Sym = Linksym(n.Sym)
@@ -348,7 +368,7 @@ func Naddr(a *obj.Addr, n *Node) {
 
 		// n->left is PHEAP ONAME for stack parameter.
 	// compute address of actual parameter on stack.
-	case OPARAM:
+	case ir.OPARAM:
 		a.Etype = uint8(Simtype[n.Left.Type.Etype])
 
 		a.Width = n.Left.Type.Width
@@ -358,7 +378,7 @@ func Naddr(a *obj.Addr, n *Node) {
 		a.Name = obj.NAME_PARAM
 		a.Node = n.Left.Orig
 
-	case OCLOSUREVAR:
+	case ir.OCLOSUREVAR:
 		if !Curfn.Func.Needctxt {
 			Fatalf("closurevar without needctxt")
 		}
@@ -367,11 +387,11 @@ func Naddr(a *obj.Addr, n *Node) {
 		a.Sym = nil
 		a.Offset = n.Xoffset
 
-	case OCFUNC:
+	case ir.OCFUNC:
 		Naddr(a, n.Left)
 		a.Sym = Linksym(n.Left.Sym)
 
-	case ONAME:
+	case ir.ONAME:
 		a.Etype = 0
 		if n.Type != nil {
 			a.Etype = uint8(Simtype[n.Type.Etype])
@@ -394,16 +414,16 @@ func Naddr(a *obj.Addr, n *Node) {
 		default:
 			Fatalf("naddr: ONAME class %v %d\n", n.Sym, n.Class)
 
-		case PEXTERN:
+		case ir.PEXTERN:
 			a.Name = obj.NAME_EXTERN
 
-		case PAUTO:
+		case ir.PAUTO:
 			a.Name = obj.NAME_AUTO
 
-		case PPARAM, PPARAMOUT:
+		case ir.PPARAM, ir.PPARAMOUT:
 			a.Name = obj.NAME_PARAM
 
-		case PFUNC:
+		case ir.PFUNC:
 			a.Name = obj.NAME_EXTERN
 			a.Type = obj.TYPE_ADDR
 			a.Width = int64(Widthptr)
@@ -412,49 +432,49 @@ func Naddr(a *obj.Addr, n *Node) {
 
 		a.Sym = Linksym(s)
 
-	case ODOT:
+	case ir.ODOT:
 		// A special case to make write barriers more efficient.
 		// Taking the address of the first field of a named struct
 		// is the same as taking the address of the struct.
-		if n.Left.Type.Etype != TSTRUCT || n.Left.Type.Field(0).Sym != n.Sym {
+		if n.Left.Type.Etype != ir.TSTRUCT || n.Left.Type.Field(0).Sym != n.Sym {
 			Debug['h'] = 1
 			Dump("naddr", n)
 			Fatalf("naddr: bad %v %v", Oconv(n.Op, 0), Ctxt.Dconv(a))
 		}
 		Naddr(a, n.Left)
 
-	case OLITERAL:
+	case ir.OLITERAL:
 		if Thearch.Thechar == '8' {
 			a.Width = 0
 		}
 		switch n.Val().Ctype() {
 		default:
-			Fatalf("naddr: const %v", Tconv(n.Type, FmtLong))
+			Fatalf("naddr: const %v", Tconv(n.Type, ir.FmtLong))
 
-		case CTFLT:
+		case ir.CTFLT:
 			a.Type = obj.TYPE_FCONST
-			a.Val = n.Val().U.(*Mpflt).Float64()
+			a.Val = n.Val().U.(*ir.Mpflt).Float64()
 
-		case CTINT, CTRUNE:
+		case ir.CTINT, ir.CTRUNE:
 			a.Sym = nil
 			a.Type = obj.TYPE_CONST
-			a.Offset = n.Val().U.(*Mpint).Int64()
+			a.Offset = n.Val().U.(*ir.Mpint).Int64()
 
-		case CTSTR:
+		case ir.CTSTR:
 			datagostring(n.Val().U.(string), a)
 
-		case CTBOOL:
+		case ir.CTBOOL:
 			a.Sym = nil
 			a.Type = obj.TYPE_CONST
 			a.Offset = int64(obj.Bool2int(n.Val().U.(bool)))
 
-		case CTNIL:
+		case ir.CTNIL:
 			a.Sym = nil
 			a.Type = obj.TYPE_CONST
 			a.Offset = 0
 		}
 
-	case OADDR:
+	case ir.OADDR:
 		Naddr(a, n.Left)
 		a.Etype = uint8(Tptr)
 		if Thearch.Thechar != '0' && Thearch.Thechar != '5' && Thearch.Thechar != '7' && Thearch.Thechar != '9' { // TODO(rsc): Do this even for arm, ppc64.
@@ -467,7 +487,7 @@ func Naddr(a *obj.Addr, n *Node) {
 		a.Type = obj.TYPE_ADDR
 
 		// itable of interface value
-	case OITAB:
+	case ir.OITAB:
 		Naddr(a, n.Left)
 
 		if a.Type == obj.TYPE_CONST && a.Offset == 0 {
@@ -477,7 +497,7 @@ func Naddr(a *obj.Addr, n *Node) {
 		a.Width = int64(Widthptr)
 
 		// pointer in a string or slice
-	case OSPTR:
+	case ir.OSPTR:
 		Naddr(a, n.Left)
 
 		if a.Type == obj.TYPE_CONST && a.Offset == 0 {
@@ -488,26 +508,26 @@ func Naddr(a *obj.Addr, n *Node) {
 		a.Width = int64(Widthptr)
 
 		// len of string or slice
-	case OLEN:
+	case ir.OLEN:
 		Naddr(a, n.Left)
 
 		if a.Type == obj.TYPE_CONST && a.Offset == 0 {
 			break // len(nil)
 		}
-		a.Etype = uint8(Simtype[TUINT])
+		a.Etype = uint8(Simtype[ir.TUINT])
 		a.Offset += int64(Array_nel)
 		if Thearch.Thechar != '5' { // TODO(rsc): Do this even on arm.
 			a.Width = int64(Widthint)
 		}
 
 		// cap of string or slice
-	case OCAP:
+	case ir.OCAP:
 		Naddr(a, n.Left)
 
 		if a.Type == obj.TYPE_CONST && a.Offset == 0 {
 			break // cap(nil)
 		}
-		a.Etype = uint8(Simtype[TUINT])
+		a.Etype = uint8(Simtype[ir.TUINT])
 		a.Offset += int64(Array_cap)
 		if Thearch.Thechar != '5' { // TODO(rsc): Do this even on arm.
 			a.Width = int64(Widthint)
@@ -535,40 +555,46 @@ func newplist() *obj.Plist {
 // incorrectly) passed a 1; the behavior is exactly
 // the same as it is for 1, except that PARAMOUT is
 // generated instead of PARAM.
-func nodarg(t interface{}, fp int) *Node {
-	var n *Node
+func nodarg(t interface{}, fp int) *ir.Node {
+	var n *ir.Node
+
+	// Output args are OINDREG; don't allocate the Name of an ONAME.
+	op := ir.ONAME
+	if fp == 0 {
+		op = ir.OINDREG
+	}
 
 	switch t := t.(type) {
-	case *Type:
+	case *ir.Type:
 		// entire argument struct, not just one arg
-		if t.Etype != TSTRUCT || !t.Funarg {
+		if t.Etype != ir.TSTRUCT || !t.Funarg {
 			Fatalf("nodarg: bad type %v", t)
 		}
-		n = Nod(ONAME, nil, nil)
+		n = Nod(op, nil, nil)
 		n.Sym = Lookup(".args")
 		n.Type = t
 		first := t.Field(0)
 		if first == nil {
 			Fatalf("nodarg: bad struct")
 		}
-		if first.Width == BADWIDTH {
+		if first.Width == ir.BADWIDTH {
 			Fatalf("nodarg: offset not computed for %v", t)
 		}
 		n.Xoffset = first.Width
 		n.Addable = true
-	case *Field:
+	case *ir.Field:
 		if fp == 1 || fp == -1 {
 			for _, n := range Curfn.Func.Dcl {
-				if (n.Class == PPARAM || n.Class == PPARAMOUT) && !isblanksym(t.Sym) && n.Sym == t.Sym {
+				if (n.Class == ir.PPARAM || n.Class == ir.PPARAMOUT) && !isblanksym(t.Sym) && n.Sym == t.Sym {
 					return n
 				}
 			}
 		}
 
-		n = Nod(ONAME, nil, nil)
+		n = Nod(op, nil, nil)
 		n.Type = t.Type
 		n.Sym = t.Sym
-		if t.Width == BADWIDTH {
+		if t.Width == ir.BADWIDTH {
 			Fatalf("nodarg: offset not computed for %v", t)
 		}
 		n.Xoffset = t.Width
@@ -587,16 +613,14 @@ func nodarg(t interface{}, fp int) *Node {
 
 	switch fp {
 	case 0: // output arg
-		n.Op = OINDREG
-
 		n.Reg = int16(Thearch.REGSP)
 		n.Xoffset += Ctxt.FixedFrameSize()
 
 	case 1: // input arg
-		n.Class = PPARAM
+		n.Class = ir.PPARAM
 
 	case -1: // output arg from paramstoheap
-		n.Class = PPARAMOUT
+		n.Class = ir.PPARAMOUT
 
 	case 2: // offset output arg
 		Fatalf("shouldn't be used")
@@ -690,12 +714,12 @@ func Anyregalloc() bool {
 // allocate register of type t, leave in n.
 // if o != N, o may be reusable register.
 // caller must Regfree(n).
-func Regalloc(n *Node, t *Type, o *Node) {
+func Regalloc(n *ir.Node, t *ir.Type, o *ir.Node) {
 	if t == nil {
 		Fatalf("regalloc: t nil")
 	}
 	et := Simtype[t.Etype]
-	if Ctxt.Arch.Regsize == 4 && (et == TINT64 || et == TUINT64) {
+	if Ctxt.Arch.Regsize == 4 && (et == ir.TINT64 || et == ir.TUINT64) {
 		Fatalf("regalloc 64bit")
 	}
 
@@ -705,8 +729,8 @@ Switch:
 	default:
 		Fatalf("regalloc: unknown type %v", t)
 
-	case TINT8, TUINT8, TINT16, TUINT16, TINT32, TUINT32, TINT64, TUINT64, TPTR32, TPTR64, TBOOL:
-		if o != nil && o.Op == OREGISTER {
+	case ir.TINT8, ir.TUINT8, ir.TINT16, ir.TUINT16, ir.TINT32, ir.TUINT32, ir.TINT64, ir.TUINT64, ir.TPTR32, ir.TPTR64, ir.TBOOL:
+		if o != nil && o.Op == ir.OREGISTER {
 			i = int(o.Reg)
 			if Thearch.REGMIN <= i && i <= Thearch.REGMAX {
 				break Switch
@@ -721,12 +745,12 @@ Switch:
 		Regdump()
 		Fatalf("out of fixed registers")
 
-	case TFLOAT32, TFLOAT64:
+	case ir.TFLOAT32, ir.TFLOAT64:
 		if Thearch.Use387 {
 			i = Thearch.FREGMIN // x86.REG_F0
 			break Switch
 		}
-		if o != nil && o.Op == OREGISTER {
+		if o != nil && o.Op == ir.OREGISTER {
 			i = int(o.Reg)
 			if Thearch.FREGMIN <= i && i <= Thearch.FREGMAX {
 				break Switch
@@ -741,7 +765,7 @@ Switch:
 		Regdump()
 		Fatalf("out of floating registers")
 
-	case TCOMPLEX64, TCOMPLEX128:
+	case ir.TCOMPLEX64, ir.TCOMPLEX128:
 		Tempname(n, t)
 		return
 	}
@@ -759,11 +783,11 @@ Switch:
 	Nodreg(n, t, i)
 }
 
-func Regfree(n *Node) {
-	if n.Op == ONAME {
+func Regfree(n *ir.Node) {
+	if n.Op == ir.ONAME {
 		return
 	}
-	if n.Op != OREGISTER && n.Op != OINDREG {
+	if n.Op != ir.OREGISTER && n.Op != ir.OINDREG {
 		Fatalf("regfree: not a register")
 	}
 	i := int(n.Reg)
@@ -803,8 +827,8 @@ func Reginuse(r int) bool {
 
 // Regrealloc(n) undoes the effect of Regfree(n),
 // so that a register can be given up but then reclaimed.
-func Regrealloc(n *Node) {
-	if n.Op != OREGISTER && n.Op != OINDREG {
+func Regrealloc(n *ir.Node) {
+	if n.Op != ir.OREGISTER && n.Op != ir.OINDREG {
 		Fatalf("regrealloc: not a register")
 	}
 	i := int(n.Reg)
@@ -0,0 +1,174 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gc
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+)
+
+// Make sure importing a package compiled by an incompatible compiler,
+// as recorded in the object header or in the export data, reports
+// the two compiler versions instead of failing to read the package.
+func TestImportIncompatible(t *testing.T) {
+	d := newTestDir(t, "TestImportIncompatible")
+	defer d.remove()
+
+	obj, err := ioutil.ReadFile(d.compile("p", "package p\nfunc F() int { return 1 }\n"))
+	if err != nil {
+		t.Fatalf("could not read object file: %v", err)
+	}
+
+	// The object header is "go object GOOS GOARCH VERSION EXPERIMENTS".
+	eol := bytes.IndexByte(obj, '\n')
+	header := strings.Fields(string(obj[:eol]))
+	oldheader := strings.Join(append(header[:4:4], "go1.0", header[len(header)-1]), " ")
+
+	// The binary export data starts with the encoding format byte
+	// and the length and bytes of the export data version.
+	oldversion := bytes.Replace(obj, []byte("$$B\nc\x03v4"), []byte("$$B\nc\x03v0"), 1)
+	if bytes.Equal(oldversion, obj) {
+		t.Fatalf("export data version not found in object file")
+	}
+
+	for _, tt := range []struct {
+		name string
+		obj  []byte
+		want string
+	}{
+		{"header", append([]byte(oldheader), obj[eol:]...), "have go1.0 X:"},
+		{"export data", oldversion, "have export data v0"},
+	} {
+		d.write("p.o", string(tt.obj))
+		main := d.write("main.go", "package main\nimport \"p\"\nfunc main() { p.F() }\n")
+		out, err := d.try("go", "tool", "compile", "-I", d.dir, "-o", d.path("main.o"), main)
+		if err == nil || !strings.Contains(out, "package p was compiled with an incompatible compiler version") || !strings.Contains(out, tt.want) {
+			t.Errorf("%s: got %v, want error naming %q:\n%s", tt.name, err, tt.want, out)
+		}
+	}
+}
+
+// Make sure the export data of a machine-generated package with
+// pathologically nested types can be written and imported.
+func TestImportDeeplyNested(t *testing.T) {
+	d := newTestDir(t, "TestImportDeeplyNested")
+	defer d.remove()
+
+	typ := "int"
+	for i := 0; i < 100000; i++ {
+		switch i % 4 {
+		case 0:
+			typ = "struct{ f " + typ + " }"
+		case 1:
+			typ = "map[string]" + typ
+		case 2:
+			typ = "[]*" + typ
+		case 3:
+			typ = "func(" + typ + ") int"
+		}
+	}
+	// The run time data for a type includes its name, so the back end
+	// would write data quadratic in the nesting depth for the type.
+	// Use it only in the signature of a function without a body.
+	d.compile("p", "package p\nfunc F(x "+typ+")\n")
+	d.compile("main", "package main\nimport \"p\"\nfunc main() { p.F(nil) }\n")
+}
+
+// Make sure -importcfg resolves imports using the configuration file
+// only, without searching for package files in directories.
+func TestImportCfg(t *testing.T) {
+	d := newTestDir(t, "TestImportCfg")
+	defer d.remove()
+
+	// compile compiles without -I, so that only flags tell
+	// the compiler where to find imports.
+	compile := func(name, src string, flags ...string) (string, error) {
+		file := d.write(name+".go", src)
+		args := append([]string{"go", "tool", "compile", "-o", d.path(name + ".o")}, flags...)
+		return d.try(append(args, file)...)
+	}
+	if out, err := compile("p", "package p\nfunc F() int { return 1 }\n"); err != nil {
+		t.Fatalf("could not compile p: %v\n%s", err, out)
+	}
+	// Give the package file a name findpkg would never look for,
+	// that is shorter than the import path and whose extension
+	// is not two characters long.
+	pfile := d.path("p1.pkg")
+	if err := os.Rename(d.path("p.o"), pfile); err != nil {
+		t.Fatalf("could not rename package file: %v", err)
+	}
+	cfg := d.write("importcfg", "# import configuration\n\npackagefile example.com/pkg/p=p1.pkg\nimportmap q=example.com/pkg/p\n")
+
+	src := "package main\nimport (\n\t\"example.com/pkg/p\"\n\tq \"q\"\n)\nfunc main() { p.F(); q.F() }\n"
+	if out, err := compile("main", src, "-importcfg", cfg, "-i"); err != nil {
+		t.Errorf("could not compile main with -importcfg: %v\n%s", err, out)
+	} else if !strings.Contains(out, "pragma example.com/pkg/p.pkg ") {
+		t.Errorf("compiling main with -importcfg did not record example.com/pkg/p.pkg as an import:\n%s", out)
+	}
+
+	// Packages not listed in the configuration are not found,
+	// even if they are in an -I directory.
+	src = "package main\nimport \"p\"\nfunc main() { p.F() }\n"
+	if err := os.Rename(pfile, d.path("p.o")); err != nil {
+		t.Fatalf("could not rename package file: %v", err)
+	}
+	if out, err := compile("main", src, "-I", d.dir); err != nil {
+		t.Fatalf("could not compile main with -I: %v\n%s", err, out)
+	}
+	if out, err := compile("main", src, "-I", d.dir, "-importcfg", cfg); err == nil || !strings.Contains(out, `can't find import: "p"`) {
+		t.Errorf("compiling main with -importcfg: got %v, want error about missing import:\n%s", err, out)
+	}
+}
+
+// Make sure the -importmap entries applied to the imports of a package
+// are recorded in its export metadata, and that the package can still
+// be imported.
+func TestImportMapRecorded(t *testing.T) {
+	d := newTestDir(t, "TestImportMapRecorded")
+	defer d.remove()
+
+	d.compile("vendor/p", "package p\nfunc F() int { return 1 }\n")
+	d.compile("vendor/q", "package q\nfunc G() int { return 2 }\n")
+	r := d.compile("r", "package r\nimport (\n\t\"p\"\n\t\"q\"\n)\nfunc H() int { return p.F() + q.G() }\n",
+		"-importmap", "q=vendor/q", "-importmap", "p=vendor/p", "-importmap", "unused=vendor/unused")
+
+	obj, err := ioutil.ReadFile(r)
+	if err != nil {
+		t.Fatalf("could not read object file: %v", err)
+	}
+	want := "\nimportmap \"p\"=\"vendor/p\"\nimportmap \"q\"=\"vendor/q\"\n"
+	if i := bytes.Index(obj, []byte("$$")); !bytes.Contains(obj[:i], []byte(want)) || bytes.Contains(obj[:i], []byte("unused")) {
+		t.Errorf("object header does not record exactly the applied import map entries; want %q:\n%s", want, obj[:i])
+	}
+
+	d.compile("main", "package main\nimport \"r\"\nfunc main() { r.H() }\n")
+}
+
+// Make sure importing a package declares only the objects that the
+// importing package refers to, and the named types they refer to.
+func TestImportLazy(t *testing.T) {
+	d := newTestDir(t, "TestImportLazy")
+	defer d.remove()
+
+	d.compile("p", "package p\ntype T int\ntype U struct{ t T }\nconst C T = 1\nvar V U\nfunc F() U { return V }\n")
+	main := d.write("main.go", "package main\nimport \"p\"\nfunc main() { println(p.C) }\n")
+	out, err := d.try("go", "tool", "compile", "-E", "-I", d.dir, "-o", d.path("main.o"), main)
+	if err != nil {
+		t.Fatalf("could not compile main: %v\n%s", err, out)
+	}
+	for _, want := range []string{"import const p.C", "import type p.T "} {
+		if !strings.Contains(out, want) {
+			t.Errorf("importing p did not declare %q:\n%s", want, out)
+		}
+	}
+	for _, bad := range []string{"p.U", "p.V", "p.F"} {
+		if strings.Contains(out, bad) {
+			t.Errorf("importing p declared %s, which main does not refer to:\n%s", bad, out)
+		}
+	}
+}
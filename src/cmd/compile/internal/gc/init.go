@@ -4,6 +4,9 @@
 
 package gc
 
+import "cmd/compile/internal/ir"
+import "cmd/internal/obj"
+
 //	case OADD:
 //		if(n->right->op == OLITERAL) {
 //			v = n->right->vconst;
@@ -25,7 +28,7 @@ package gc
 
 var renameinit_initgen int
 
-func renameinit() *Sym {
+func renameinit() *ir.Sym {
 	renameinit_initgen++
 	return LookupN("init.", renameinit_initgen)
 }
@@ -39,21 +42,27 @@ func renameinit() *Sym {
 //			throw();			(4a)
 //		}
 //		initdone· = 1;				(6)
+//		libfuzzerInitCounters(...) // if -d=libfuzzer	(6a)
+//		coverRegister(...) // if -cover		(6b)
 //		// over all matching imported symbols
 //			<pkg>.init()			(7)
 //		{ <init stmts> }			(8)
+//		// for each outlined variable initializer
+//			if initvars·[i] != nil {	(8a)
+//				initvars·[i]()
+//			}
 //		init.<n>() // if any			(9)
 //		initdone· = 2;				(10)
 //		return					(11)
 //	}
-func anyinit(n []*Node) bool {
+func anyinit(n []*ir.Node) bool {
 	// are there any interesting init statements
 	for _, ln := range n {
 		switch ln.Op {
-		case ODCLFUNC, ODCLCONST, ODCLTYPE, OEMPTY:
+		case ir.ODCLFUNC, ir.ODCLCONST, ir.ODCLTYPE, ir.OEMPTY:
 			break
 
-		case OAS, OASWB:
+		case ir.OAS, ir.OASWB:
 			if isblank(ln.Left) && candiscard(ln.Right) {
 				break
 			}
@@ -65,6 +74,11 @@ func anyinit(n []*Node) bool {
 		}
 	}
 
+	// are there coverage or libFuzzer counters to register
+	if covercounters != nil || fuzzcounters != nil {
+		return true
+	}
+
 	// is this main
 	if localpkg.Name == "main" {
 		return true
@@ -78,7 +92,7 @@ func anyinit(n []*Node) bool {
 	}
 
 	// are there any imported init functions
-	for _, s := range initSyms {
+	for _, s := range ir.InitSyms {
 		if s.Def != nil {
 			return true
 		}
@@ -88,7 +102,7 @@ func anyinit(n []*Node) bool {
 	return false
 }
 
-func fninit(n []*Node) {
+func fninit(n []*ir.Node) {
 	if Debug['A'] != 0 {
 		// sys.go or unsafe.go during compiler build
 		return
@@ -99,51 +113,63 @@ func fninit(n []*Node) {
 		return
 	}
 
-	var r []*Node
+	nf = outlineinitvars(nf)
+
+	var r []*ir.Node
 
 	// (1)
 	gatevar := newname(Lookup("initdone·"))
-	addvar(gatevar, Types[TUINT8], PEXTERN)
+	addvar(gatevar, ir.Types[ir.TUINT8], ir.PEXTERN)
 
 	// (2)
 	Maxarg = 0
 
-	fn := Nod(ODCLFUNC, nil, nil)
+	fn := Nod(ir.ODCLFUNC, nil, nil)
 	initsym := Lookup("init")
 	fn.Func.Nname = newname(initsym)
 	fn.Func.Nname.Name.Defn = fn
-	fn.Func.Nname.Name.Param.Ntype = Nod(OTFUNC, nil, nil)
-	declare(fn.Func.Nname, PFUNC)
+	fn.Func.Nname.Name.Param.Ntype = Nod(ir.OTFUNC, nil, nil)
+	declare(fn.Func.Nname, ir.PFUNC)
 	funchdr(fn)
 
 	// (3)
-	a := Nod(OIF, nil, nil)
-	a.Left = Nod(OGT, gatevar, Nodintconst(1))
+	a := Nod(ir.OIF, nil, nil)
+	a.Left = Nod(ir.OGT, gatevar, Nodintconst(1))
 	a.Likely = 1
 	r = append(r, a)
 	// (3a)
-	a.Nbody.Set1(Nod(ORETURN, nil, nil))
+	a.Nbody.Set1(Nod(ir.ORETURN, nil, nil))
 
 	// (4)
-	b := Nod(OIF, nil, nil)
-	b.Left = Nod(OEQ, gatevar, Nodintconst(1))
+	b := Nod(ir.OIF, nil, nil)
+	b.Left = Nod(ir.OEQ, gatevar, Nodintconst(1))
 	// this actually isn't likely, but code layout is better
 	// like this: no JMP needed after the call.
 	b.Likely = 1
 	r = append(r, b)
 	// (4a)
-	b.Nbody.Set1(Nod(OCALL, syslook("throwinit"), nil))
+	b.Nbody.Set1(Nod(ir.OCALL, syslook("throwinit"), nil))
 
 	// (6)
-	a = Nod(OAS, gatevar, Nodintconst(1))
+	a = Nod(ir.OAS, gatevar, Nodintconst(1))
 
 	r = append(r, a)
 
+	// (6a)
+	if fuzzcounters != nil {
+		r = append(r, fuzzinitcounters())
+	}
+
+	// (6b)
+	if covercounters != nil {
+		r = append(r, coverinit())
+	}
+
 	// (7)
-	for _, s := range initSyms {
+	for _, s := range ir.InitSyms {
 		if s.Def != nil && s != initsym {
 			// could check that it is fn of no args/returns
-			a = Nod(OCALL, s.Def, nil)
+			a = Nod(ir.OCALL, s.Def, nil)
 			r = append(r, a)
 		}
 	}
@@ -158,17 +184,17 @@ func fninit(n []*Node) {
 		if s.Def == nil {
 			break
 		}
-		a = Nod(OCALL, s.Def, nil)
+		a = Nod(ir.OCALL, s.Def, nil)
 		r = append(r, a)
 	}
 
 	// (10)
-	a = Nod(OAS, gatevar, Nodintconst(2))
+	a = Nod(ir.OAS, gatevar, Nodintconst(2))
 
 	r = append(r, a)
 
 	// (11)
-	a = Nod(ORETURN, nil, nil)
+	a = Nod(ir.ORETURN, nil, nil)
 
 	r = append(r, a)
 	exportsym(fn.Func.Nname)
@@ -182,3 +208,97 @@ func fninit(n []*Node) {
 	Curfn = nil
 	funccompile(fn)
 }
+
+// outlineinitvars moves the initialization of each package-level
+// variable in nf whose initializer has no side effects into a
+// function of its own, V·init for variable V, and replaces it by
+// a call through the table
+//	var initvars·[n]func()
+// The entries of the table refer to the functions with R_INITVAR
+// relocations, which the linker resolves to nil if the variable is
+// not otherwise reachable, so that the variable, its initializer,
+// and the data it refers to can all be dropped from the binary.
+func outlineinitvars(nf []*ir.Node) []*ir.Node {
+	if compiling_runtime != 0 || Ctxt.Flag_dynlink {
+		return nf
+	}
+	// Static initialization may leave more than one
+	// statement assigning to a variable; keep those.
+	count := make(map[*ir.Sym]int)
+	for _, n := range nf {
+		if outlinable(n) {
+			count[n.Left.Sym]++
+		}
+	}
+	var out, vars []*ir.Node
+	for _, n := range nf {
+		if outlinable(n) && count[n.Left.Sym] == 1 {
+			vars = append(vars, n)
+		}
+	}
+	if len(vars) == 0 {
+		return nf
+	}
+
+	lno := lineno
+	t := ir.Typ(ir.TARRAY)
+	t.Type = functype(nil, nil, nil)
+	t.Bound = int64(len(vars))
+	table := newname(Lookup("initvars·"))
+	addvar(table, t, ir.PEXTERN)
+
+	i := 0
+	for _, n := range nf {
+		if i == len(vars) || n != vars[i] {
+			out = append(out, n)
+			continue
+		}
+		lineno = n.Lineno
+		Maxarg = 0
+		fn := Nod(ir.ODCLFUNC, nil, nil)
+		fn.Func.Nname = newname(Lookup(n.Left.Sym.Name + "·init"))
+		fn.Func.Nname.Name.Defn = fn
+		fn.Func.Nname.Name.Param.Ntype = Nod(ir.OTFUNC, nil, nil)
+		declare(fn.Func.Nname, ir.PFUNC)
+		funchdr(fn)
+		fn.Nbody.Set1(n)
+		funcbody(fn)
+		Curfn = fn
+		fn = typecheck(fn, Etop)
+		typecheckslice(fn.Nbody.Slice(), Etop)
+		Curfn = nil
+		funccompile(fn)
+
+		off := i * Widthptr
+		duintptr(table.Sym, off, 0)
+		r := obj.Addrel(Linksym(table.Sym))
+		r.Off = int32(off)
+		r.Siz = uint8(Widthptr)
+		r.Sym = Linksym(funcsym(fn.Func.Nname.Sym))
+		r.Type = obj.R_INITVAR
+
+		f := Nod(ir.OINDEX, table, Nodintconst(int64(i)))
+		a := Nod(ir.OIF, Nod(ir.ONE, f, nodnil()), nil)
+		a.Nbody.Set1(Nod(ir.OCALL, Nod(ir.OINDEX, table, Nodintconst(int64(i))), nil))
+		out = append(out, a)
+		i++
+	}
+	lineno = lno
+	return out
+}
+
+// outlinable reports whether the init statement n assigns a composite
+// literal that can be discarded to a package-level variable.
+func outlinable(n *ir.Node) bool {
+	if n.Op != ir.OAS || n.Ninit.Len() != 0 || n.Right == nil {
+		return false
+	}
+	if n.Left.Op != ir.ONAME || n.Left.Class != ir.PEXTERN || n.Left.Sym.Pkg != localpkg || isblank(n.Left) {
+		return false
+	}
+	switch n.Right.Op {
+	case ir.OMAPLIT, ir.OARRAYLIT, ir.OSTRUCTLIT, ir.OPTRLIT:
+		return candiscard(n.Right)
+	}
+	return false
+}
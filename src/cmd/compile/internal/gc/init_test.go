@@ -0,0 +1,41 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gc
+
+import (
+	"strings"
+	"testing"
+)
+
+// Make sure the linker drops package-level variables initialized by
+// composite literals, and their initializers, if nothing refers to
+// them, and keeps the ones that are used.
+func TestInitVarsDropped(t *testing.T) {
+	d := newTestDir(t, "TestInitVarsDropped")
+	defer d.remove()
+
+	d.pack("p", `package p
+
+var Unused = map[string]int{"unused": 1}
+
+var Used = map[string]int{"used": 2}
+`)
+	prog := d.link(d.pack("main", "package main\nimport \"p\"\nfunc main() { println(p.Used[\"used\"]) }\n"))
+	if out := d.run(prog); out != "2\n" {
+		t.Errorf("got output %q, want %q", out, "2\n")
+	}
+	// The linker writes · as . in the symbol table.
+	syms := d.run("go", "tool", "nm", prog)
+	for _, sym := range []string{" p.Used\n", " p.Used.init\n"} {
+		if !strings.Contains(syms, sym) {
+			t.Errorf("binary does not contain %s", strings.TrimSpace(sym))
+		}
+	}
+	for _, sym := range []string{" p.Unused\n", " p.Unused.init\n"} {
+		if strings.Contains(syms, sym) {
+			t.Errorf("binary contains %s", strings.TrimSpace(sym))
+		}
+	}
+}
@@ -28,12 +28,13 @@
 package gc
 
 import (
+	"cmd/compile/internal/ir"
 	"fmt"
 )
 
 // Get the function's package. For ordinary functions it's on the ->sym, but for imported methods
 // the ->sym can be re-used in the local package, so peel it off the receiver's type.
-func fnpkg(fn *Node) *Pkg {
+func fnpkg(fn *ir.Node) *ir.Pkg {
 	if fn.Type.Recv() != nil {
 		// method
 		rcvr := fn.Type.Recv().Type
@@ -42,7 +43,7 @@ func fnpkg(fn *Node) *Pkg {
 			rcvr = rcvr.Type
 		}
 		if rcvr.Sym == nil {
-			Fatalf("receiver with no sym: [%v] %v  (%v)", fn.Sym, Nconv(fn, FmtLong), rcvr)
+			Fatalf("receiver with no sym: [%v] %v  (%v)", fn.Sym, Nconv(fn, ir.FmtLong), rcvr)
 		}
 		return rcvr.Sym.Pkg
 	}
@@ -51,9 +52,19 @@ func fnpkg(fn *Node) *Pkg {
 	return fn.Sym.Pkg
 }
 
+// hasinl reports whether fn has an inlineable body. The body of a
+// function imported from binary export data is parsed on first use.
+func hasinl(fn *ir.Node) bool {
+	if fn == nil || fn.Func == nil {
+		return false
+	}
+	loadinl(fn)
+	return fn.Func.Inl.Len() != 0
+}
+
 // Lazy typechecking of imported bodies. For local functions, caninl will set ->typecheck
 // because they're a copy of an already checked body.
-func typecheckinl(fn *Node) {
+func typecheckinl(fn *ir.Node) {
 	lno := setlineno(fn)
 
 	// typecheckinl is only for imported functions;
@@ -67,7 +78,7 @@ func typecheckinl(fn *Node) {
 	}
 
 	if Debug['m'] > 2 {
-		fmt.Printf("typecheck import [%v] %v { %v }\n", fn.Sym, Nconv(fn, FmtLong), Hconv(fn.Func.Inl, FmtSharp))
+		fmt.Printf("typecheck import [%v] %v { %v }\n", fn.Sym, Nconv(fn, ir.FmtLong), Hconv(fn.Func.Inl, ir.FmtSharp))
 	}
 
 	save_safemode := safemode
@@ -86,16 +97,23 @@ func typecheckinl(fn *Node) {
 // Caninl determines whether fn is inlineable.
 // If so, caninl saves fn->nbody in fn->inl and substitutes it with a copy.
 // fn and ->nbody will already have been typechecked.
-func caninl(fn *Node) {
-	if fn.Op != ODCLFUNC {
+func caninl(fn *ir.Node) {
+	if fn.Op != ir.ODCLFUNC {
 		Fatalf("caninl %v", fn)
 	}
 	if fn.Func.Nname == nil {
-		Fatalf("caninl no nname %v", Nconv(fn, FmtSign))
+		Fatalf("caninl no nname %v", Nconv(fn, ir.FmtSign))
 	}
 
 	// If marked "go:noinline", don't inline
-	if fn.Func.Pragma&Noinline != 0 {
+	if fn.Func.Pragma&ir.Noinline != 0 {
+		return
+	}
+
+	// If excluded from the instrumentation in use by "go:norace",
+	// "go:nomsan" or "go:noasan", don't inline: the body would be
+	// instrumented as part of its caller.
+	if noinstrument(fn) {
 		return
 	}
 
@@ -147,7 +165,7 @@ func caninl(fn *Node) {
 	fn.Type.Nname = fn.Func.Nname
 
 	if Debug['m'] > 1 {
-		fmt.Printf("%v: can inline %v as: %v { %v }\n", fn.Line(), Nconv(fn.Func.Nname, FmtSharp), Tconv(fn.Type, FmtSharp), Hconv(fn.Func.Nname.Func.Inl, FmtSharp))
+		fmt.Printf("%v: can inline %v as: %v { %v }\n", fn.Line(), Nconv(fn.Func.Nname, ir.FmtSharp), Tconv(fn.Type, ir.FmtSharp), Hconv(fn.Func.Nname.Func.Inl, ir.FmtSharp))
 	} else if Debug['m'] != 0 {
 		fmt.Printf("%v: can inline %v\n", fn.Line(), fn.Func.Nname)
 	}
@@ -156,7 +174,7 @@ func caninl(fn *Node) {
 }
 
 // Look for anything we want to punt on.
-func ishairylist(ll Nodes, budget *int) bool {
+func ishairylist(ll ir.Nodes, budget *int) bool {
 	for _, n := range ll.Slice() {
 		if ishairy(n, budget) {
 			return true
@@ -165,20 +183,20 @@ func ishairylist(ll Nodes, budget *int) bool {
 	return false
 }
 
-func ishairy(n *Node, budget *int) bool {
+func ishairy(n *ir.Node, budget *int) bool {
 	if n == nil {
 		return false
 	}
 
 	switch n.Op {
 	// Call is okay if inlinable and we have the budget for the body.
-	case OCALLFUNC:
-		if n.Left.Func != nil && len(n.Left.Func.Inl.Slice()) != 0 {
+	case ir.OCALLFUNC:
+		if hasinl(n.Left) {
 			*budget -= int(n.Left.Func.InlCost)
 			break
 		}
-		if n.Left.Op == ONAME && n.Left.Left != nil && n.Left.Left.Op == OTYPE && n.Left.Right != nil && n.Left.Right.Op == ONAME { // methods called as functions
-			if n.Left.Sym.Def != nil && len(n.Left.Sym.Def.Func.Inl.Slice()) != 0 {
+		if n.Left.Op == ir.ONAME && n.Left.Left != nil && n.Left.Left.Op == ir.OTYPE && n.Left.Right != nil && n.Left.Right.Op == ir.ONAME { // methods called as functions
+			if hasinl(n.Left.Sym.Def) {
 				*budget -= int(n.Left.Sym.Def.Func.InlCost)
 				break
 			}
@@ -188,14 +206,14 @@ func ishairy(n *Node, budget *int) bool {
 		}
 
 	// Call is okay if inlinable and we have the budget for the body.
-	case OCALLMETH:
+	case ir.OCALLMETH:
 		if n.Left.Type == nil {
-			Fatalf("no function type for [%p] %v\n", n.Left, Nconv(n.Left, FmtSign))
+			Fatalf("no function type for [%p] %v\n", n.Left, Nconv(n.Left, ir.FmtSign))
 		}
 		if n.Left.Type.Nname == nil {
-			Fatalf("no function definition for [%p] %v\n", n.Left.Type, Tconv(n.Left.Type, FmtSign))
+			Fatalf("no function definition for [%p] %v\n", n.Left.Type, Tconv(n.Left.Type, ir.FmtSign))
 		}
-		if len(n.Left.Type.Nname.Func.Inl.Slice()) != 0 {
+		if hasinl(n.Left.Type.Nname) {
 			*budget -= int(n.Left.Type.Nname.Func.InlCost)
 			break
 		}
@@ -204,22 +222,22 @@ func ishairy(n *Node, budget *int) bool {
 		}
 
 	// Things that are too hairy, irrespective of the budget
-	case OCALL, OCALLINTER, OPANIC, ORECOVER:
+	case ir.OCALL, ir.OCALLINTER, ir.OPANIC, ir.ORECOVER:
 		if Debug['l'] < 4 {
 			return true
 		}
 
-	case OCLOSURE,
-		OCALLPART,
-		ORANGE,
-		OFOR,
-		OSELECT,
-		OTYPESW,
-		OPROC,
-		ODEFER,
-		ODCLTYPE, // can't print yet
-		OBREAK,
-		ORETJMP:
+	case ir.OCLOSURE,
+		ir.OCALLPART,
+		ir.ORANGE,
+		ir.OFOR,
+		ir.OSELECT,
+		ir.OTYPESW,
+		ir.OPROC,
+		ir.ODEFER,
+		ir.ODCLTYPE, // can't print yet
+		ir.OBREAK,
+		ir.ORETJMP:
 		return true
 	}
 
@@ -231,21 +249,21 @@ func ishairy(n *Node, budget *int) bool {
 // Inlcopy and inlcopylist recursively copy the body of a function.
 // Any name-like node of non-local class is marked for re-export by adding it to
 // the exportlist.
-func inlcopylist(ll []*Node) []*Node {
-	s := make([]*Node, 0, len(ll))
+func inlcopylist(ll []*ir.Node) []*ir.Node {
+	s := make([]*ir.Node, 0, len(ll))
 	for _, n := range ll {
 		s = append(s, inlcopy(n))
 	}
 	return s
 }
 
-func inlcopy(n *Node) *Node {
+func inlcopy(n *ir.Node) *ir.Node {
 	if n == nil {
 		return nil
 	}
 
 	switch n.Op {
-	case ONAME, OTYPE, OLITERAL:
+	case ir.ONAME, ir.OTYPE, ir.OLITERAL:
 		return n
 	}
 
@@ -265,7 +283,7 @@ func inlcopy(n *Node) *Node {
 
 // Inlcalls/nodelist/node walks fn's statements and expressions and substitutes any
 // calls made to inlineable functions. This is the external entry point.
-func inlcalls(fn *Node) {
+func inlcalls(fn *ir.Node) {
 	savefn := Curfn
 	Curfn = fn
 	fn = inlnode(fn)
@@ -276,8 +294,8 @@ func inlcalls(fn *Node) {
 }
 
 // Turn an OINLCALL into a statement.
-func inlconv2stmt(n *Node) {
-	n.Op = OBLOCK
+func inlconv2stmt(n *ir.Node) {
+	n.Op = ir.OBLOCK
 
 	// n->ninit stays
 	n.List.Set(n.Nbody.Slice())
@@ -289,7 +307,7 @@ func inlconv2stmt(n *Node) {
 // Turn an OINLCALL into a single valued expression.
 // The result of inlconv2expr MUST be assigned back to n, e.g.
 // 	n.Left = inlconv2expr(n.Left)
-func inlconv2expr(n *Node) *Node {
+func inlconv2expr(n *ir.Node) *ir.Node {
 	r := n.Rlist.First()
 	return addinit(r, append(n.Ninit.Slice(), n.Nbody.Slice()...))
 }
@@ -299,9 +317,9 @@ func inlconv2expr(n *Node) *Node {
 // containing the inlined statements on the first list element so
 // order will be preserved Used in return, oas2func and call
 // statements.
-func inlconv2list(n *Node) []*Node {
-	if n.Op != OINLCALL || n.Rlist.Len() == 0 {
-		Fatalf("inlconv2list %v\n", Nconv(n, FmtSign))
+func inlconv2list(n *ir.Node) []*ir.Node {
+	if n.Op != ir.OINLCALL || n.Rlist.Len() == 0 {
+		Fatalf("inlconv2list %v\n", Nconv(n, ir.FmtSign))
 	}
 
 	s := n.Rlist.Slice()
@@ -309,7 +327,7 @@ func inlconv2list(n *Node) []*Node {
 	return s
 }
 
-func inlnodelist(l Nodes) {
+func inlnodelist(l ir.Nodes) {
 	s := l.Slice()
 	for i := range s {
 		s[i] = inlnode(s[i])
@@ -329,24 +347,24 @@ func inlnodelist(l Nodes) {
 // shorter and less complicated.
 // The result of inlnode MUST be assigned back to n, e.g.
 // 	n.Left = inlnode(n.Left)
-func inlnode(n *Node) *Node {
+func inlnode(n *ir.Node) *ir.Node {
 	if n == nil {
 		return n
 	}
 
 	switch n.Op {
 	// inhibit inlining of their argument
-	case ODEFER, OPROC:
+	case ir.ODEFER, ir.OPROC:
 		switch n.Left.Op {
-		case OCALLFUNC, OCALLMETH:
+		case ir.OCALLFUNC, ir.OCALLMETH:
 			// TODO(marvin): Fix Node.EType type union.
-			n.Left.Etype = EType(n.Op)
+			n.Left.Etype = ir.EType(n.Op)
 		}
 		fallthrough
 
 		// TODO do them here (or earlier),
 	// so escape analysis can avoid more heapmoves.
-	case OCLOSURE:
+	case ir.OCLOSURE:
 		return n
 	}
 
@@ -354,19 +372,19 @@ func inlnode(n *Node) *Node {
 
 	inlnodelist(n.Ninit)
 	for _, n1 := range n.Ninit.Slice() {
-		if n1.Op == OINLCALL {
+		if n1.Op == ir.OINLCALL {
 			inlconv2stmt(n1)
 		}
 	}
 
 	n.Left = inlnode(n.Left)
-	if n.Left != nil && n.Left.Op == OINLCALL {
+	if n.Left != nil && n.Left.Op == ir.OINLCALL {
 		n.Left = inlconv2expr(n.Left)
 	}
 
 	n.Right = inlnode(n.Right)
-	if n.Right != nil && n.Right.Op == OINLCALL {
-		if n.Op == OFOR {
+	if n.Right != nil && n.Right.Op == ir.OINLCALL {
+		if n.Op == ir.OFOR {
 			inlconv2stmt(n.Right)
 		} else {
 			n.Right = inlconv2expr(n.Right)
@@ -375,22 +393,22 @@ func inlnode(n *Node) *Node {
 
 	inlnodelist(n.List)
 	switch n.Op {
-	case OBLOCK:
+	case ir.OBLOCK:
 		for _, n2 := range n.List.Slice() {
-			if n2.Op == OINLCALL {
+			if n2.Op == ir.OINLCALL {
 				inlconv2stmt(n2)
 			}
 		}
 
 		// if we just replaced arg in f(arg()) or return arg with an inlined call
 	// and arg returns multiple values, glue as list
-	case ORETURN,
-		OCALLFUNC,
-		OCALLMETH,
-		OCALLINTER,
-		OAPPEND,
-		OCOMPLEX:
-		if n.List.Len() == 1 && n.List.First().Op == OINLCALL && n.List.First().Rlist.Len() > 1 {
+	case ir.ORETURN,
+		ir.OCALLFUNC,
+		ir.OCALLMETH,
+		ir.OCALLINTER,
+		ir.OAPPEND,
+		ir.OCOMPLEX:
+		if n.List.Len() == 1 && n.List.First().Op == ir.OINLCALL && n.List.First().Rlist.Len() > 1 {
 			n.List.Set(inlconv2list(n.List.First()))
 			break
 		}
@@ -399,7 +417,7 @@ func inlnode(n *Node) *Node {
 	default:
 		s := n.List.Slice()
 		for i1, n1 := range s {
-			if n1.Op == OINLCALL {
+			if n1.Op == ir.OINLCALL {
 				s[i1] = inlconv2expr(s[i1])
 			}
 		}
@@ -407,10 +425,10 @@ func inlnode(n *Node) *Node {
 
 	inlnodelist(n.Rlist)
 	switch n.Op {
-	case OAS2FUNC:
-		if n.Rlist.First().Op == OINLCALL {
+	case ir.OAS2FUNC:
+		if n.Rlist.First().Op == ir.OINLCALL {
 			n.Rlist.Set(inlconv2list(n.Rlist.First()))
-			n.Op = OAS2
+			n.Op = ir.OAS2
 			n.Typecheck = 0
 			n = typecheck(n, Etop)
 			break
@@ -420,8 +438,8 @@ func inlnode(n *Node) *Node {
 	default:
 		s := n.Rlist.Slice()
 		for i1, n1 := range s {
-			if n1.Op == OINLCALL {
-				if n.Op == OIF {
+			if n1.Op == ir.OINLCALL {
+				if n.Op == ir.OIF {
 					inlconv2stmt(n1)
 				} else {
 					s[i1] = inlconv2expr(s[i1])
@@ -432,7 +450,7 @@ func inlnode(n *Node) *Node {
 
 	inlnodelist(n.Nbody)
 	for _, n := range n.Nbody.Slice() {
-		if n.Op == OINLCALL {
+		if n.Op == ir.OINLCALL {
 			inlconv2stmt(n)
 		}
 	}
@@ -441,38 +459,38 @@ func inlnode(n *Node) *Node {
 	// transmogrify this node itself unless inhibited by the
 	// switch at the top of this function.
 	switch n.Op {
-	case OCALLFUNC, OCALLMETH:
+	case ir.OCALLFUNC, ir.OCALLMETH:
 		// TODO(marvin): Fix Node.EType type union.
-		if n.Etype == EType(OPROC) || n.Etype == EType(ODEFER) {
+		if n.Etype == ir.EType(ir.OPROC) || n.Etype == ir.EType(ir.ODEFER) {
 			return n
 		}
 	}
 
 	switch n.Op {
-	case OCALLFUNC:
+	case ir.OCALLFUNC:
 		if Debug['m'] > 3 {
-			fmt.Printf("%v:call to func %v\n", n.Line(), Nconv(n.Left, FmtSign))
+			fmt.Printf("%v:call to func %v\n", n.Line(), Nconv(n.Left, ir.FmtSign))
 		}
-		if n.Left.Func != nil && len(n.Left.Func.Inl.Slice()) != 0 { // normal case
+		if hasinl(n.Left) { // normal case
 			n = mkinlcall(n, n.Left, n.Isddd)
-		} else if n.Left.Op == ONAME && n.Left.Left != nil && n.Left.Left.Op == OTYPE && n.Left.Right != nil && n.Left.Right.Op == ONAME { // methods called as functions
+		} else if n.Left.Op == ir.ONAME && n.Left.Left != nil && n.Left.Left.Op == ir.OTYPE && n.Left.Right != nil && n.Left.Right.Op == ir.ONAME { // methods called as functions
 			if n.Left.Sym.Def != nil {
 				n = mkinlcall(n, n.Left.Sym.Def, n.Isddd)
 			}
 		}
 
-	case OCALLMETH:
+	case ir.OCALLMETH:
 		if Debug['m'] > 3 {
-			fmt.Printf("%v:call to meth %v\n", n.Line(), Nconv(n.Left.Right, FmtLong))
+			fmt.Printf("%v:call to meth %v\n", n.Line(), Nconv(n.Left.Right, ir.FmtLong))
 		}
 
 		// typecheck should have resolved ODOTMETH->type, whose nname points to the actual function.
 		if n.Left.Type == nil {
-			Fatalf("no function type for [%p] %v\n", n.Left, Nconv(n.Left, FmtSign))
+			Fatalf("no function type for [%p] %v\n", n.Left, Nconv(n.Left, ir.FmtSign))
 		}
 
 		if n.Left.Type.Nname == nil {
-			Fatalf("no function definition for [%p] %v\n", n.Left.Type, Tconv(n.Left.Type, FmtSign))
+			Fatalf("no function definition for [%p] %v\n", n.Left.Type, Tconv(n.Left.Type, ir.FmtSign))
 		}
 
 		n = mkinlcall(n, n.Left.Type.Nname, n.Isddd)
@@ -484,7 +502,7 @@ func inlnode(n *Node) *Node {
 
 // The result of mkinlcall MUST be assigned back to n, e.g.
 // 	n.Left = mkinlcall(n.Left, fn, isddd)
-func mkinlcall(n *Node, fn *Node, isddd bool) *Node {
+func mkinlcall(n *ir.Node, fn *ir.Node, isddd bool) *ir.Node {
 	save_safemode := safemode
 
 	// imported functions may refer to unsafe as long as the
@@ -499,7 +517,7 @@ func mkinlcall(n *Node, fn *Node, isddd bool) *Node {
 	return n
 }
 
-func tinlvar(t *Field) *Node {
+func tinlvar(t *ir.Field) *ir.Node {
 	if t.Nname != nil && !isblank(t.Nname) {
 		if t.Nname.Name.Inlvar == nil {
 			Fatalf("missing inlvar for %v\n", t.Nname)
@@ -518,9 +536,9 @@ var inlgen int
 // parameters.
 // The result of mkinlcall1 MUST be assigned back to n, e.g.
 // 	n.Left = mkinlcall1(n.Left, fn, isddd)
-func mkinlcall1(n *Node, fn *Node, isddd bool) *Node {
+func mkinlcall1(n *ir.Node, fn *ir.Node, isddd bool) *ir.Node {
 	// For variadic fn.
-	if len(fn.Func.Inl.Slice()) == 0 {
+	if !hasinl(fn) {
 		return n
 	}
 
@@ -534,20 +552,20 @@ func mkinlcall1(n *Node, fn *Node, isddd bool) *Node {
 
 	// Bingo, we have a function node, and it has an inlineable body
 	if Debug['m'] > 1 {
-		fmt.Printf("%v: inlining call to %v %v { %v }\n", n.Line(), fn.Sym, Tconv(fn.Type, FmtSharp), Hconv(fn.Func.Inl, FmtSharp))
+		fmt.Printf("%v: inlining call to %v %v { %v }\n", n.Line(), fn.Sym, Tconv(fn.Type, ir.FmtSharp), Hconv(fn.Func.Inl, ir.FmtSharp))
 	} else if Debug['m'] != 0 {
 		fmt.Printf("%v: inlining call to %v\n", n.Line(), fn)
 	}
 
 	if Debug['m'] > 2 {
-		fmt.Printf("%v: Before inlining: %v\n", n.Line(), Nconv(n, FmtSign))
+		fmt.Printf("%v: Before inlining: %v\n", n.Line(), Nconv(n, ir.FmtSign))
 	}
 
 	ninit := n.Ninit
 
 	//dumplist("ninit pre", ninit);
 
-	var dcl []*Node
+	var dcl []*ir.Node
 	if fn.Name.Defn != nil {
 		// local function
 		dcl = fn.Func.Inldcl.Slice()
@@ -556,28 +574,28 @@ func mkinlcall1(n *Node, fn *Node, isddd bool) *Node {
 		dcl = fn.Func.Dcl
 	}
 
-	var retvars []*Node
+	var retvars []*ir.Node
 	i := 0
 
 	// Make temp names to use instead of the originals
 	for _, ln := range dcl {
-		if ln.Class == PPARAMOUT { // return values handled below.
+		if ln.Class == ir.PPARAMOUT { // return values handled below.
 			continue
 		}
-		if ln.Op == ONAME {
+		if ln.Op == ir.ONAME {
 			ln.Name.Inlvar = inlvar(ln)
 
 			// Typecheck because inlvar is not necessarily a function parameter.
 			ln.Name.Inlvar = typecheck(ln.Name.Inlvar, Erv)
 
-			if ln.Class&^PHEAP != PAUTO {
-				ninit.Append(Nod(ODCL, ln.Name.Inlvar, nil)) // otherwise gen won't emit the allocations for heapallocs
+			if ln.Class&^ir.PHEAP != ir.PAUTO {
+				ninit.Append(Nod(ir.ODCL, ln.Name.Inlvar, nil)) // otherwise gen won't emit the allocations for heapallocs
 			}
 		}
 	}
 
 	// temporaries for return values.
-	var m *Node
+	var m *ir.Node
 	for _, t := range fn.Type.Results().Fields().Slice() {
 		if t != nil && t.Nname != nil && !isblank(t.Nname) {
 			m = inlvar(t.Nname)
@@ -589,12 +607,12 @@ func mkinlcall1(n *Node, fn *Node, isddd bool) *Node {
 			i++
 		}
 
-		ninit.Append(Nod(ODCL, m, nil))
+		ninit.Append(Nod(ir.ODCL, m, nil))
 		retvars = append(retvars, m)
 	}
 
 	// assign receiver.
-	if fn.Type.Recv() != nil && n.Left.Op == ODOTMETH {
+	if fn.Type.Recv() != nil && n.Left.Op == ir.ODOTMETH {
 		// method call with a receiver.
 		t := fn.Type.Recv()
 
@@ -602,12 +620,12 @@ func mkinlcall1(n *Node, fn *Node, isddd bool) *Node {
 			Fatalf("missing inlvar for %v\n", t.Nname)
 		}
 		if n.Left.Left == nil {
-			Fatalf("method call without receiver: %v", Nconv(n, FmtSign))
+			Fatalf("method call without receiver: %v", Nconv(n, ir.FmtSign))
 		}
 		if t == nil {
-			Fatalf("method call unknown receiver type: %v", Nconv(n, FmtSign))
+			Fatalf("method call unknown receiver type: %v", Nconv(n, ir.FmtSign))
 		}
-		as := Nod(OAS, tinlvar(t), n.Left.Left)
+		as := Nod(ir.OAS, tinlvar(t), n.Left.Left)
 		if as != nil {
 			as = typecheck(as, Etop)
 			ninit.Append(as)
@@ -617,7 +635,7 @@ func mkinlcall1(n *Node, fn *Node, isddd bool) *Node {
 	// check if inlined function is variadic.
 	variadic := false
 
-	var varargtype *Type
+	var varargtype *ir.Type
 	varargcount := 0
 	for _, t := range fn.Type.Params().Fields().Slice() {
 		if t.Isddd {
@@ -636,7 +654,7 @@ func mkinlcall1(n *Node, fn *Node, isddd bool) *Node {
 
 	if n.List.Len() == 1 {
 		switch n.List.First().Op {
-		case OCALL, OCALLFUNC, OCALLINTER, OCALLMETH:
+		case ir.OCALL, ir.OCALLFUNC, ir.OCALLINTER, ir.OCALLMETH:
 			if n.List.First().Left.Type.Results().NumFields() > 1 {
 				multiret = n.List.First().Left.Type.Results().NumFields() - 1
 			}
@@ -645,23 +663,23 @@ func mkinlcall1(n *Node, fn *Node, isddd bool) *Node {
 
 	if variadic {
 		varargcount = n.List.Len() + multiret
-		if n.Left.Op != ODOTMETH {
+		if n.Left.Op != ir.ODOTMETH {
 			varargcount -= fn.Type.Recvs().NumFields()
 		}
 		varargcount -= fn.Type.Params().NumFields() - 1
 	}
 
 	// assign arguments to the parameters' temp names
-	as := Nod(OAS2, nil, nil)
+	as := Nod(ir.OAS2, nil, nil)
 
 	as.Rlist.Set(n.List.Slice())
 	li := 0
 
 	// TODO: if len(nlist) == 1 but multiple args, check that n->list->n is a call?
-	if fn.Type.Recv() != nil && n.Left.Op != ODOTMETH {
+	if fn.Type.Recv() != nil && n.Left.Op != ir.ODOTMETH {
 		// non-method call to method
 		if n.List.Len() == 0 {
-			Fatalf("non-method call to method without first arg: %v", Nconv(n, FmtSign))
+			Fatalf("non-method call to method without first arg: %v", Nconv(n, ir.FmtSign))
 		}
 
 		// append receiver inlvar to LHS.
@@ -671,7 +689,7 @@ func mkinlcall1(n *Node, fn *Node, isddd bool) *Node {
 			Fatalf("missing inlvar for %v\n", t.Nname)
 		}
 		if t == nil {
-			Fatalf("method call unknown receiver type: %v", Nconv(n, FmtSign))
+			Fatalf("method call unknown receiver type: %v", Nconv(n, ir.FmtSign))
 		}
 		as.List.Append(tinlvar(t))
 		li++
@@ -680,8 +698,8 @@ func mkinlcall1(n *Node, fn *Node, isddd bool) *Node {
 	// append ordinary arguments to LHS.
 	chkargcount := n.List.Len() > 1
 
-	var vararg *Node    // the slice argument to a variadic call
-	var varargs []*Node // the list of LHS names to put in vararg.
+	var vararg *ir.Node    // the slice argument to a variadic call
+	var varargs []*ir.Node // the list of LHS names to put in vararg.
 	if !chkargcount {
 		// 0 or 1 expression on RHS.
 		var i int
@@ -701,7 +719,7 @@ func mkinlcall1(n *Node, fn *Node, isddd bool) *Node {
 		}
 	} else {
 		// match arguments except final variadic (unless the call is dotted itself)
-		t, it := IterFields(fn.Type.Params())
+		t, it := ir.IterFields(fn.Type.Params())
 		for t != nil {
 			if li >= n.List.Len() {
 				break
@@ -731,7 +749,7 @@ func mkinlcall1(n *Node, fn *Node, isddd bool) *Node {
 		}
 
 		if li < n.List.Len() || t != nil {
-			Fatalf("arg count mismatch: %v  vs %v\n", Tconv(fn.Type.Params(), FmtSharp), Hconv(n.List, FmtComma))
+			Fatalf("arg count mismatch: %v  vs %v\n", Tconv(fn.Type.Params(), ir.FmtSharp), Hconv(n.List, ir.FmtComma))
 		}
 	}
 
@@ -742,18 +760,18 @@ func mkinlcall1(n *Node, fn *Node, isddd bool) *Node {
 
 	// turn the variadic args into a slice.
 	if variadic {
-		as = Nod(OAS, vararg, nil)
+		as = Nod(ir.OAS, vararg, nil)
 		if varargcount == 0 {
 			as.Right = nodnil()
 			as.Right.Type = varargtype
 		} else {
-			vararrtype := typ(TARRAY)
+			vararrtype := ir.Typ(ir.TARRAY)
 			vararrtype.Type = varargtype.Type
 			vararrtype.Bound = int64(varargcount)
 
-			as.Right = Nod(OCOMPLIT, nil, typenod(varargtype))
+			as.Right = Nod(ir.OCOMPLIT, nil, typenod(varargtype))
 			as.Right.List.Set(varargs)
-			as.Right = Nod(OSLICE, as.Right, Nod(OKEY, nil, nil))
+			as.Right = Nod(ir.OSLICE, as.Right, Nod(ir.OKEY, nil, nil))
 		}
 
 		as = typecheck(as, Etop)
@@ -762,7 +780,7 @@ func mkinlcall1(n *Node, fn *Node, isddd bool) *Node {
 
 	// zero the outparams
 	for _, n := range retvars {
-		as = Nod(OAS, n, nil)
+		as = Nod(ir.OAS, n, nil)
 		as = typecheck(as, Etop)
 		ninit.Append(as)
 	}
@@ -777,14 +795,14 @@ func mkinlcall1(n *Node, fn *Node, isddd bool) *Node {
 
 	body := subst.list(fn.Func.Inl)
 
-	body = append(body, Nod(OGOTO, retlabel, nil)) // avoid 'not used' when function doesn't have return
-	body = append(body, Nod(OLABEL, retlabel, nil))
+	body = append(body, Nod(ir.OGOTO, retlabel, nil)) // avoid 'not used' when function doesn't have return
+	body = append(body, Nod(ir.OLABEL, retlabel, nil))
 
 	typecheckslice(body, Etop)
 
 	//dumplist("ninit post", ninit);
 
-	call := Nod(OINLCALL, nil, nil)
+	call := Nod(ir.OINLCALL, nil, nil)
 
 	call.Ninit.Set(ninit.Slice())
 	call.Nbody.Set(body)
@@ -815,14 +833,14 @@ func mkinlcall1(n *Node, fn *Node, isddd bool) *Node {
 	fn.Func.Inl.Set(nil) // prevent infinite recursion (shouldn't happen anyway)
 	inlnodelist(call.Nbody)
 	for _, n := range call.Nbody.Slice() {
-		if n.Op == OINLCALL {
+		if n.Op == ir.OINLCALL {
 			inlconv2stmt(n)
 		}
 	}
 	fn.Func.Inl.Set(body)
 
 	if Debug['m'] > 2 {
-		fmt.Printf("%v: After inlining %v\n\n", n.Line(), Nconv(n, FmtSign))
+		fmt.Printf("%v: After inlining %v\n\n", n.Line(), Nconv(n, ir.FmtSign))
 	}
 
 	return n
@@ -831,14 +849,14 @@ func mkinlcall1(n *Node, fn *Node, isddd bool) *Node {
 // Every time we expand a function we generate a new set of tmpnames,
 // PAUTO's in the calling functions, and link them off of the
 // PPARAM's, PAUTOS and PPARAMOUTs of the called function.
-func inlvar(var_ *Node) *Node {
+func inlvar(var_ *ir.Node) *ir.Node {
 	if Debug['m'] > 3 {
-		fmt.Printf("inlvar %v\n", Nconv(var_, FmtSign))
+		fmt.Printf("inlvar %v\n", Nconv(var_, ir.FmtSign))
 	}
 
 	n := newname(var_.Sym)
 	n.Type = var_.Type
-	n.Class = PAUTO
+	n.Class = ir.PAUTO
 	n.Used = true
 	n.Name.Curfn = Curfn // the calling function, not the called one
 	n.Addrtaken = var_.Addrtaken
@@ -855,10 +873,10 @@ func inlvar(var_ *Node) *Node {
 }
 
 // Synthesize a variable to store the inlined function's results in.
-func retvar(t *Field, i int) *Node {
+func retvar(t *ir.Field, i int) *ir.Node {
 	n := newname(LookupN("~r", i))
 	n.Type = t.Type
-	n.Class = PAUTO
+	n.Class = ir.PAUTO
 	n.Used = true
 	n.Name.Curfn = Curfn // the calling function, not the called one
 	Curfn.Func.Dcl = append(Curfn.Func.Dcl, n)
@@ -867,10 +885,10 @@ func retvar(t *Field, i int) *Node {
 
 // Synthesize a variable to store the inlined function's arguments
 // when they come from a multiple return call.
-func argvar(t *Type, i int) *Node {
+func argvar(t *ir.Type, i int) *ir.Node {
 	n := newname(LookupN("~arg", i))
 	n.Type = t.Type
-	n.Class = PAUTO
+	n.Class = ir.PAUTO
 	n.Used = true
 	n.Name.Curfn = Curfn // the calling function, not the called one
 	Curfn.Func.Dcl = append(Curfn.Func.Dcl, n)
@@ -879,7 +897,7 @@ func argvar(t *Type, i int) *Node {
 
 var newlabel_inl_label int
 
-func newlabel_inl() *Node {
+func newlabel_inl() *ir.Node {
 	newlabel_inl_label++
 	n := newname(LookupN(".inlret", newlabel_inl_label))
 	n.Etype = 1 // flag 'safe' for escape analysis (no backjumps)
@@ -890,15 +908,15 @@ func newlabel_inl() *Node {
 // function call.
 type inlsubst struct {
 	// Target of the goto substituted in place of a return.
-	retlabel *Node
+	retlabel *ir.Node
 
 	// Temporary result variables.
-	retvars []*Node
+	retvars []*ir.Node
 }
 
 // list inlines a list of nodes.
-func (subst *inlsubst) list(ll Nodes) []*Node {
-	s := make([]*Node, 0, ll.Len())
+func (subst *inlsubst) list(ll ir.Nodes) []*ir.Node {
+	s := make([]*ir.Node, 0, ll.Len())
 	for _, n := range ll.Slice() {
 		s = append(s, subst.node(n))
 	}
@@ -909,38 +927,38 @@ func (subst *inlsubst) list(ll Nodes) []*Node {
 // inlined function, substituting references to input/output
 // parameters with ones to the tmpnames, and substituting returns with
 // assignments to the output.
-func (subst *inlsubst) node(n *Node) *Node {
+func (subst *inlsubst) node(n *ir.Node) *ir.Node {
 	if n == nil {
 		return nil
 	}
 
 	switch n.Op {
-	case ONAME:
+	case ir.ONAME:
 		if n.Name.Inlvar != nil { // These will be set during inlnode
 			if Debug['m'] > 2 {
-				fmt.Printf("substituting name %v  ->  %v\n", Nconv(n, FmtSign), Nconv(n.Name.Inlvar, FmtSign))
+				fmt.Printf("substituting name %v  ->  %v\n", Nconv(n, ir.FmtSign), Nconv(n.Name.Inlvar, ir.FmtSign))
 			}
 			return n.Name.Inlvar
 		}
 
 		if Debug['m'] > 2 {
-			fmt.Printf("not substituting name %v\n", Nconv(n, FmtSign))
+			fmt.Printf("not substituting name %v\n", Nconv(n, ir.FmtSign))
 		}
 		return n
 
-	case OLITERAL, OTYPE:
+	case ir.OLITERAL, ir.OTYPE:
 		return n
 
 		// Since we don't handle bodies with closures, this return is guaranteed to belong to the current inlined function.
 
 	//		dump("Return before substitution", n);
-	case ORETURN:
-		m := Nod(OGOTO, subst.retlabel, nil)
+	case ir.ORETURN:
+		m := Nod(ir.OGOTO, subst.retlabel, nil)
 
 		m.Ninit.Set(subst.list(n.Ninit))
 
 		if len(subst.retvars) != 0 && n.List.Len() != 0 {
-			as := Nod(OAS2, nil, nil)
+			as := Nod(ir.OAS2, nil, nil)
 
 			// Make a shallow copy of retvars.
 			// Otherwise OINLCALL.Rlist will be the same list,
@@ -959,8 +977,8 @@ func (subst *inlsubst) node(n *Node) *Node {
 		//		dump("Return after substitution", m);
 		return m
 
-	case OGOTO, OLABEL:
-		m := Nod(OXXX, nil, nil)
+	case ir.OGOTO, ir.OLABEL:
+		m := Nod(ir.OXXX, nil, nil)
 		*m = *n
 		m.Ninit.Set(nil)
 		p := fmt.Sprintf("%s·%d", n.Left.Sym.Name, inlgen)
@@ -968,12 +986,12 @@ func (subst *inlsubst) node(n *Node) *Node {
 
 		return m
 	default:
-		m := Nod(OXXX, nil, nil)
+		m := Nod(ir.OXXX, nil, nil)
 		*m = *n
 		m.Ninit.Set(nil)
 
-		if n.Op == OCLOSURE {
-			Fatalf("cannot inline function containing closure: %v", Nconv(n, FmtSign))
+		if n.Op == ir.OCLOSURE {
+			Fatalf("cannot inline function containing closure: %v", Nconv(n, ir.FmtSign))
 		}
 
 		m.Left = subst.node(n.Left)
@@ -988,19 +1006,19 @@ func (subst *inlsubst) node(n *Node) *Node {
 }
 
 // Plaster over linenumbers
-func setlnolist(ll Nodes, lno int32) {
+func setlnolist(ll ir.Nodes, lno int32) {
 	for _, n := range ll.Slice() {
 		setlno(n, lno)
 	}
 }
 
-func setlno(n *Node, lno int32) {
+func setlno(n *ir.Node, lno int32) {
 	if n == nil {
 		return
 	}
 
 	// don't clobber names, unless they're freshly synthesized
-	if n.Op != ONAME || n.Lineno == 0 {
+	if n.Op != ir.ONAME || n.Lineno == 0 {
 		n.Lineno = lno
 	}
 
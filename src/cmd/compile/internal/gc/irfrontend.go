@@ -0,0 +1,70 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gc
+
+import "cmd/compile/internal/ir"
+
+func init() {
+	ir.Fe = irFrontend{}
+}
+
+// irFrontend provides the IR with the services of the compiler.
+type irFrontend struct{}
+
+func (irFrontend) Fatalf(format string, args ...interface{}) {
+	Fatalf(format, args...)
+}
+
+func (irFrontend) Yyerror(format string, args ...interface{}) {
+	Yyerror(format, args...)
+}
+
+func (irFrontend) Nerrors() int {
+	return nerrors + nsavederrors
+}
+
+func (irFrontend) DumpFatal(s string, n *ir.Node) {
+	Debug['h'] = 1
+	Dump(s, n)
+	Fatalf("%s", s)
+}
+
+func (irFrontend) Lineno() int32 {
+	return lineno
+}
+
+func (irFrontend) Line(line int32) string {
+	return Ctxt.LineHist.LineString(int(line))
+}
+
+func (irFrontend) Nconv(n *ir.Node, flag ir.FmtFlag) string {
+	return Nconv(n, flag)
+}
+
+func (irFrontend) Hconv(l ir.Nodes, flag ir.FmtFlag) string {
+	return Hconv(l, flag)
+}
+
+func (irFrontend) Sconv(s *ir.Sym, flag ir.FmtFlag) string {
+	return Sconv(s, flag)
+}
+
+func (irFrontend) Tconv(t *ir.Type, flag ir.FmtFlag) string {
+	return Tconv(t, flag)
+}
+
+func (irFrontend) Eqtype(t1, t2 *ir.Type) bool {
+	return Eqtype(t1, t2)
+}
+
+func (irFrontend) Dowidth(t *ir.Type) {
+	backendDowidth(t)
+}
+
+func (irFrontend) Ptrto(t *ir.Type) *ir.Type {
+	backendMu.Lock()
+	defer backendMu.Unlock()
+	return Ptrto(t)
+}
@@ -0,0 +1,109 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gc
+
+import (
+	"bytes"
+	"cmd/compile/internal/ir"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+var updateIR = flag.Bool("update", false, "update the golden files in testdata/ir")
+
+var (
+	irPhasesRE = regexp.MustCompile(`(?m)^// phases:(.*)$`)
+	irLineRE   = regexp.MustCompile(`\bl\((\d+)\)`)
+)
+
+// TestIRGolden compiles each testdata/ir/NAME.go up to the phases
+// listed in its "// phases:" comment and compares the IR of its
+// functions after each PHASE with testdata/ir/NAME.PHASE.golden.
+// Run with -update to rewrite the golden files after a change to the
+// IR a phase produces, and review the diff.
+func TestIRGolden(t *testing.T) {
+	files, err := filepath.Glob("testdata/ir/*.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) == 0 {
+		t.Fatal("no files in testdata/ir")
+	}
+	for _, file := range files {
+		src, err := ioutil.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		m := irPhasesRE.FindSubmatch(src)
+		if m == nil {
+			t.Errorf("%s: no // phases: comment", file)
+			continue
+		}
+		for _, phase := range strings.Fields(string(m[1])) {
+			fns, _, err := CompilePackage(string(src), phase, "")
+			if err != nil {
+				t.Errorf("%s: %s: %v", file, phase, err)
+				continue
+			}
+			golden := strings.TrimSuffix(file, ".go") + "." + phase + ".golden"
+			checkGolden(t, golden, dumpIR(fns))
+		}
+	}
+}
+
+// dumpIR returns the IR of fns in the format of -W. The line numbers
+// in it are those of the source file, so that the dump does not
+// depend on what the compiler read before.
+func dumpIR(fns []*ir.Node) string {
+	var buf bytes.Buffer
+	for _, fn := range fns {
+		fmt.Fprintf(&buf, "func %v%v\n\n", fn.Func.Nname.Sym, Hconv(fn.Nbody, ir.FmtSign))
+	}
+	return irLineRE.ReplaceAllStringFunc(buf.String(), func(l string) string {
+		n, _ := strconv.Atoi(l[2 : len(l)-1])
+		pos := filepath.Base(linestr(int32(n)))
+		return "l(" + strings.TrimPrefix(pos, "CompileFunc.go:") + ")"
+	})
+}
+
+// checkGolden compares got with the contents of the file golden,
+// or rewrites the file under -update.
+func checkGolden(t *testing.T, golden, got string) {
+	if *updateIR {
+		if err := ioutil.WriteFile(golden, []byte(got), 0666); err != nil {
+			t.Error(err)
+		}
+		return
+	}
+	want, err := ioutil.ReadFile(golden)
+	if err != nil {
+		t.Errorf("%v (run with -update to create it)", err)
+		return
+	}
+	if got == string(want) {
+		return
+	}
+	gotLines := strings.Split(got, "\n")
+	wantLines := strings.Split(string(want), "\n")
+	for i := 0; ; i++ {
+		var g, w string
+		if i < len(gotLines) {
+			g = gotLines[i]
+		}
+		if i < len(wantLines) {
+			w = wantLines[i]
+		}
+		if g != w {
+			t.Errorf("%s:%d: IR differs (run with -update to accept it)\ngot:  %s\nwant: %s", golden, i+1, g, w)
+			return
+		}
+	}
+}
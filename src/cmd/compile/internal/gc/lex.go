@@ -6,6 +6,7 @@ package gc
 
 import (
 	"bufio"
+	"cmd/compile/internal/ir"
 	"cmd/internal/obj"
 	"fmt"
 	"io"
@@ -44,20 +45,6 @@ func plan9quote(s string) string {
 	return s
 }
 
-type Pragma uint16
-
-const (
-	Nointerface       Pragma = 1 << iota
-	Noescape                 // func parameters don't escape
-	Norace                   // func must not have race detector annotations
-	Nosplit                  // func should not execute on separate stack
-	Noinline                 // func should not be inlined
-	Systemstack              // func must run on system stack
-	Nowritebarrier           // emit compiler error instead of write barrier
-	Nowritebarrierrec        // error on write barrier in this or recursive callees
-	CgoUnsafeArgs            // treat a pointer to one arg as a pointer to them all
-)
-
 type lexer struct {
 	// source
 	bin        *bufio.Reader
@@ -67,14 +54,39 @@ type lexer struct {
 
 	// pragma flags
 	// accumulated by lexer; reset by parser
-	pragma Pragma
+	pragma ir.Pragma
 
 	// current token
 	tok  int32
-	sym_ *Sym   // valid if tok == LNAME
-	val  Val    // valid if tok == LLITERAL
-	op   Op     // valid if tok == LOPER, LASOP, or LINCOP, or prec > 0
-	prec OpPrec // operator precedence; 0 if not a binary operator
+	sym_ *ir.Sym // valid if tok == LNAME
+	val  ir.Val  // valid if tok == LLITERAL
+	op   ir.Op   // valid if tok == LOPER, LASOP, or LINCOP, or prec > 0
+	prec OpPrec  // operator precedence; 0 if not a binary operator
+
+	// token recording and replay, for repeating the expressions
+	// of a const spec; see parser.constdcl
+	recording bool
+	recorded  []token
+	replay    []token
+}
+
+// A token is the current token of a lexer, saved for replay.
+type token struct {
+	tok    int32
+	sym_   *ir.Sym
+	val    ir.Val
+	op     ir.Op
+	prec   OpPrec
+	lineno int32
+}
+
+func (l *lexer) token() token {
+	return token{l.tok, l.sym_, l.val, l.op, l.prec, lineno}
+}
+
+func (l *lexer) settoken(t token) {
+	l.tok, l.sym_, l.val, l.op, l.prec = t.tok, t.sym_, t.val, t.op, t.prec
+	lineno = t.lineno
 }
 
 type OpPrec int
@@ -186,6 +198,18 @@ func lexname(lex rune) string {
 }
 
 func (l *lexer) next() {
+	if len(l.replay) > 0 {
+		l.settoken(l.replay[0])
+		l.replay = l.replay[1:]
+	} else {
+		l.lex()
+	}
+	if l.recording {
+		l.recorded = append(l.recorded, l.token())
+	}
+}
+
+func (l *lexer) lex() {
 	nlsemi := l.nlsemi
 	l.nlsemi = false
 	l.prec = 0
@@ -222,7 +246,7 @@ l0:
 	// c < utf8.RuneSelf
 
 	var c1 rune
-	var op Op
+	var op ir.Op
 	var prec OpPrec
 
 	switch c {
@@ -318,7 +342,7 @@ l0:
 			}
 		}
 
-		op = ODIV
+		op = ir.ODIV
 		prec = PMUL
 		goto binop1
 
@@ -330,44 +354,44 @@ l0:
 		}
 
 	case '*':
-		op = OMUL
+		op = ir.OMUL
 		prec = PMUL
 		goto binop
 
 	case '%':
-		op = OMOD
+		op = ir.OMOD
 		prec = PMUL
 		goto binop
 
 	case '+':
-		op = OADD
+		op = ir.OADD
 		goto incop
 
 	case '-':
-		op = OSUB
+		op = ir.OSUB
 		goto incop
 
 	case '>':
 		c = LOPER
 		c1 = l.getr()
 		if c1 == '>' {
-			op = ORSH
+			op = ir.ORSH
 			prec = PMUL
 			goto binop
 		}
 
 		l.prec = PCMP
 		if c1 == '=' {
-			l.op = OGE
+			l.op = ir.OGE
 			goto lx
 		}
-		l.op = OGT
+		l.op = ir.OGT
 
 	case '<':
 		c = LOPER
 		c1 = l.getr()
 		if c1 == '<' {
-			op = OLSH
+			op = ir.OLSH
 			prec = PMUL
 			goto binop
 		}
@@ -378,23 +402,23 @@ l0:
 			// so we can give a good error message when used
 			// in an expression context.
 			l.prec = PCOMM
-			l.op = OSEND
+			l.op = ir.OSEND
 			goto lx
 		}
 
 		l.prec = PCMP
 		if c1 == '=' {
-			l.op = OLE
+			l.op = ir.OLE
 			goto lx
 		}
-		l.op = OLT
+		l.op = ir.OLT
 
 	case '=':
 		c1 = l.getr()
 		if c1 == '=' {
 			c = LOPER
 			l.prec = PCMP
-			l.op = OEQ
+			l.op = ir.OEQ
 			goto lx
 		}
 
@@ -403,7 +427,7 @@ l0:
 		if c1 == '=' {
 			c = LOPER
 			l.prec = PCMP
-			l.op = ONE
+			l.op = ir.ONE
 			goto lx
 		}
 
@@ -412,18 +436,18 @@ l0:
 		if c1 == '&' {
 			c = LOPER
 			l.prec = PANDAND
-			l.op = OANDAND
+			l.op = ir.OANDAND
 			goto lx
 		}
 
 		if c1 == '^' {
 			c = LOPER
-			op = OANDNOT
+			op = ir.OANDNOT
 			prec = PMUL
 			goto binop
 		}
 
-		op = OAND
+		op = ir.OAND
 		prec = PMUL
 		goto binop1
 
@@ -432,16 +456,16 @@ l0:
 		if c1 == '|' {
 			c = LOPER
 			l.prec = POROR
-			l.op = OOROR
+			l.op = ir.OOROR
 			goto lx
 		}
 
-		op = OOR
+		op = ir.OOR
 		prec = PADD
 		goto binop1
 
 	case '^':
-		op = OXOR
+		op = ir.OXOR
 		prec = PADD
 		goto binop
 
@@ -686,7 +710,7 @@ func (l *lexer) number(c rune) {
 		// imaginary constant
 		if c == 'i' {
 			str = lexbuf.String()
-			x := new(Mpcplx)
+			x := new(ir.Mpcplx)
 			x.Real.SetFloat64(0.0)
 			x.Imag.SetString(str)
 			if x.Imag.Val.IsInf() {
@@ -710,7 +734,7 @@ func (l *lexer) number(c rune) {
 		}
 
 		str = lexbuf.String()
-		x := new(Mpint)
+		x := new(ir.Mpint)
 		x.SetString(str)
 		if x.Ovf {
 			Yyerror("overflow in constant")
@@ -725,7 +749,7 @@ func (l *lexer) number(c rune) {
 	} else { // float
 
 		str = lexbuf.String()
-		x := newMpflt()
+		x := ir.NewMpflt()
 		x.SetString(str)
 		if x.Val.IsInf() {
 			Yyerror("overflow in float constant")
@@ -763,7 +787,7 @@ func (l *lexer) stdString() {
 		}
 	}
 
-	l.val.U = internString(cp.Bytes())
+	l.val.U = ir.InternString(cp.Bytes())
 	if Debug['x'] != 0 {
 		fmt.Printf("lex: string literal\n")
 	}
@@ -794,7 +818,7 @@ func (l *lexer) rawString() {
 		cp.WriteRune(c)
 	}
 
-	l.val.U = internString(cp.Bytes())
+	l.val.U = ir.InternString(cp.Bytes())
 	if Debug['x'] != 0 {
 		fmt.Printf("lex: string literal\n")
 	}
@@ -818,7 +842,7 @@ func (l *lexer) rune() {
 		l.ungetr()
 	}
 
-	x := new(Mpint)
+	x := new(ir.Mpint)
 	l.val.U = x
 	x.SetInt64(int64(r))
 	x.Rune = true
@@ -830,17 +854,6 @@ func (l *lexer) rune() {
 	l.tok = LLITERAL
 }
 
-var internedStrings = map[string]string{}
-
-func internString(b []byte) string {
-	s, ok := internedStrings[string(b)] // string(b) here doesn't allocate
-	if !ok {
-		s = string(b)
-		internedStrings[s] = s
-	}
-	return s
-}
-
 func more(pp *string) bool {
 	p := *pp
 	for p != "" && isSpace(rune(p[0])) {
@@ -896,33 +909,37 @@ func (l *lexer) getlinepragma() rune {
 			Lookup(f[1]).Linkname = f[2]
 		case "go:nointerface":
 			if obj.Fieldtrack_enabled != 0 {
-				l.pragma |= Nointerface
+				l.pragma |= ir.Nointerface
 			}
 		case "go:noescape":
-			l.pragma |= Noescape
+			l.pragma |= ir.Noescape
 		case "go:norace":
-			l.pragma |= Norace
+			l.pragma |= ir.Norace
+		case "go:nomsan":
+			l.pragma |= ir.Nomsan
+		case "go:noasan":
+			l.pragma |= ir.Noasan
 		case "go:nosplit":
-			l.pragma |= Nosplit
+			l.pragma |= ir.Nosplit
 		case "go:noinline":
-			l.pragma |= Noinline
+			l.pragma |= ir.Noinline
 		case "go:systemstack":
 			if compiling_runtime == 0 {
 				Yyerror("//go:systemstack only allowed in runtime")
 			}
-			l.pragma |= Systemstack
+			l.pragma |= ir.Systemstack
 		case "go:nowritebarrier":
 			if compiling_runtime == 0 {
 				Yyerror("//go:nowritebarrier only allowed in runtime")
 			}
-			l.pragma |= Nowritebarrier
+			l.pragma |= ir.Nowritebarrier
 		case "go:nowritebarrierrec":
 			if compiling_runtime == 0 {
 				Yyerror("//go:nowritebarrierrec only allowed in runtime")
 			}
-			l.pragma |= Nowritebarrierrec | Nowritebarrier // implies Nowritebarrier
+			l.pragma |= ir.Nowritebarrierrec | ir.Nowritebarrier // implies Nowritebarrier
 		case "go:cgo_unsafe_args":
-			l.pragma |= CgoUnsafeArgs
+			l.pragma |= ir.CgoUnsafeArgs
 		}
 		return c
 	}
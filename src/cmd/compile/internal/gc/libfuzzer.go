@@ -0,0 +1,93 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gc
+
+import "cmd/compile/internal/ir"
+
+// Instrumentation for libFuzzer.
+//
+// With -d=libfuzzer, the coverage pass in cover.go gives every basic
+// block an 8-bit counter, which the package's init function registers
+// with libFuzzer. In addition, walk reports the operands of integer and
+// string comparisons to libFuzzer, which uses them to guide mutation.
+// The runtime side is in runtime/libfuzzer.go, which is built only with
+// the libfuzzer build tag.
+
+// fuzzcounters is the package's array of 8-bit block counters, if any.
+var fuzzcounters *ir.Node
+
+// fuzzinitcounters returns a call registering fuzzcounters with libFuzzer.
+func fuzzinitcounters() *ir.Node {
+	n := Nod(ir.OCALL, syslook("libfuzzerInitCounters"), nil)
+	n.List.Set([]*ir.Node{
+		Nod(ir.OADDR, Nod(ir.OINDEX, fuzzcounters, Nodintconst(0)), nil),
+		Nodintconst(fuzzcounters.Type.Bound),
+	})
+	return n
+}
+
+// fuzzcmp adds to init a call reporting the operands of the
+// comparison n to libFuzzer, if n compares integers or strings.
+// It may replace the operands of n with temporaries, so that
+// they are evaluated only once.
+func fuzzcmp(n *ir.Node, init *ir.Nodes) {
+	if Debug_libfuzzer == 0 || compiling_runtime != 0 || init == nil {
+		return
+	}
+	if n.Left.Op == ir.OLITERAL && n.Right.Op == ir.OLITERAL {
+		return
+	}
+
+	t := n.Left.Type
+	var fn string
+	var argtype *ir.Type
+	swap := false
+	switch {
+	case Istype(t, ir.TSTRING):
+		fn = "libfuzzerHookStrCmp"
+		argtype = ir.Types[ir.TSTRING]
+
+	case Isint[t.Etype]:
+		switch t.Width {
+		case 1:
+			fn, argtype = "libfuzzerTraceCmp1", ir.Types[ir.TUINT8]
+		case 2:
+			fn, argtype = "libfuzzerTraceCmp2", ir.Types[ir.TUINT16]
+		case 4:
+			fn, argtype = "libfuzzerTraceCmp4", ir.Types[ir.TUINT32]
+		case 8:
+			fn, argtype = "libfuzzerTraceCmp8", ir.Types[ir.TUINT64]
+		default:
+			return
+		}
+		// libFuzzer treats comparisons against constants specially.
+		// Its hooks for them take the constant first.
+		if n.Left.Op == ir.OLITERAL || n.Right.Op == ir.OLITERAL {
+			fn = "libfuzzerTraceConstCmp" + fn[len(fn)-1:]
+			swap = n.Right.Op == ir.OLITERAL
+		}
+
+	default:
+		return
+	}
+
+	n.Left = cheapexpr(n.Left, init)
+	n.Right = cheapexpr(n.Right, init)
+	l, r := n.Left, n.Right
+	if swap {
+		l, r = r, l
+	}
+	init.Append(mkcall(fn, nil, init, fuzzarg(l, argtype, init), fuzzarg(r, argtype, init)))
+}
+
+// fuzzarg returns n converted to t, for passing to a libFuzzer hook.
+func fuzzarg(n *ir.Node, t *ir.Type, init *ir.Nodes) *ir.Node {
+	if n.Op == ir.OLITERAL && !Istype(t, ir.TSTRING) {
+		// Convert at run time: a negative constant
+		// does not convert to an unsigned type.
+		n = copyexpr(n, n.Type, init)
+	}
+	return conv(n, t)
+}
@@ -0,0 +1,46 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gc
+
+import (
+	"internal/testenv"
+	"strings"
+	"testing"
+)
+
+// Make sure -d=libfuzzer counts blocks in 8-bit counters,
+// registers them from the package's init function, and reports
+// integer and string comparisons.
+func TestLibFuzzer(t *testing.T) {
+	testenv.MustHaveGoBuild(t)
+
+	src := `package p
+
+func f(x, y int, s string) int {
+	if x < y {
+		return 1
+	}
+	if x == 7 {
+		return 2
+	}
+	if s == "fuzz" {
+		return 3
+	}
+	return 0
+}
+`
+	out := compileS(t, src, "-d=libfuzzer")
+	for _, want := range []string{
+		"MOVB\tAL, \"\".cover·counters(SB)",
+		"CALL\truntime.libfuzzerInitCounters(SB)",
+		"CALL\truntime.libfuzzerTraceCmp8(SB)",
+		"CALL\truntime.libfuzzerTraceConstCmp8(SB)",
+		"CALL\truntime.libfuzzerHookStrCmp(SB)",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("missing %q in -d=libfuzzer output:\n%s", want, out)
+		}
+	}
+}
@@ -8,14 +8,17 @@ package gc
 
 import (
 	"bufio"
+	"cmd/compile/internal/ir"
 	"cmd/compile/internal/ssa"
 	"cmd/internal/obj"
 	"flag"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"log"
 	"os"
 	"path"
+	"path/filepath"
 	"runtime"
 	"strconv"
 	"strings"
@@ -31,10 +34,28 @@ var (
 )
 
 var (
-	Debug_append int
-	Debug_panic  int
-	Debug_slice  int
-	Debug_wb     int
+	Debug_append     int
+	Debug_capture    int
+	Debug_checkptr   int
+	Debug_checks     int
+	Debug_exhaustive int
+	Debug_fncache    int
+	Debug_gcdata     int
+	Debug_libfuzzer  int
+	Debug_maxptrmask int
+	Debug_mergeautos int
+	Debug_nodealloc  int
+	Debug_panic      int
+	Debug_slice      int
+	Debug_timings    int
+	Debug_toolarge   int
+	Debug_wb         int
+	Debug_wbbreak    int
+
+	Debug_duffzeromin int
+	Debug_duffzeromax int
+	Debug_duffcopymin int
+	Debug_duffcopymax int
 )
 
 // Debug arguments.
@@ -45,15 +66,32 @@ var debugtab = []struct {
 	name string
 	val  *int
 }{
-	{"append", &Debug_append},         // print information about append compilation
-	{"disablenil", &Disable_checknil}, // disable nil checks
-	{"gcprog", &Debug_gcprog},         // print dump of GC programs
-	{"nil", &Debug_checknil},          // print information about nil checks
-	{"panic", &Debug_panic},           // do not hide any compiler panic
-	{"slice", &Debug_slice},           // print information about slice compilation
-	{"typeassert", &Debug_typeassert}, // print information about type assertion inlining
-	{"wb", &Debug_wb},                 // print information about write barriers
-	{"export", &Debug_export},         // print export data
+	{"append", &Debug_append},           // print information about append compilation
+	{"capture", &Debug_capture},         // explain how closures capture variables
+	{"checkptr", &Debug_checkptr},       // instrument unsafe pointer conversions
+	{"checks", &Debug_checks},           // insert runtime assertions; see checks.go
+	{"disablenil", &Disable_checknil},   // disable nil checks
+	{"exhaustive", &Debug_exhaustive},   // warn about switches missing constants of the switched type
+	{"fncache", &Debug_fncache},         // print hits in the function cache
+	{"gcdata", &Debug_gcdata},           // print size of GC information per type
+	{"gcprog", &Debug_gcprog},           // print dump of GC programs
+	{"libfuzzer", &Debug_libfuzzer},     // instrument basic blocks and comparisons for libFuzzer
+	{"maxptrmask", &Debug_maxptrmask},   // set largest ptrmask, in bytes, before using a GC program
+	{"mergeautos", &Debug_mergeautos},   // print information about stack slot sharing
+	{"nil", &Debug_checknil},            // print information about nil checks
+	{"nodealloc", &Debug_nodealloc},     // print the number of nodes allocated in each phase
+	{"panic", &Debug_panic},             // do not hide any compiler panic
+	{"slice", &Debug_slice},             // print information about slice compilation
+	{"timings", &Debug_timings},         // print phase times and work counts on one line at exit
+	{"toolarge", &Debug_toolarge},       // print information about functions too large to compile
+	{"typeassert", &Debug_typeassert},   // print information about type assertion inlining
+	{"wb", &Debug_wb},                   // print information about write barriers
+	{"wbbreak", &Debug_wbbreak},         // break a write barrier marking, to test -d=wb=2
+	{"export", &Debug_export},           // print export data
+	{"duffzeromin", &Debug_duffzeromin}, // set smallest block, in words, zeroed with duffzero
+	{"duffzeromax", &Debug_duffzeromax}, // set largest block, in words, zeroed with duffzero
+	{"duffcopymin", &Debug_duffcopymin}, // set smallest block, in words, copied with duffcopy
+	{"duffcopymax", &Debug_duffcopymax}, // set largest block, in words, copied with duffcopy
 }
 
 func usage() {
@@ -74,6 +112,43 @@ func hidePanic() {
 	}
 }
 
+// asmjson is whether -S=json asks for the listing in JSON.
+var asmjson bool
+
+// asmFlag is the value of -S: a count, as with the other flags that
+// set Debug, or "json".
+type asmFlag struct{}
+
+func (asmFlag) String() string {
+	if asmjson {
+		return "json"
+	}
+	return fmt.Sprint(Debug['S'])
+}
+
+func (asmFlag) Set(s string) error {
+	switch s {
+	case "true":
+		Debug['S']++
+	case "false":
+		Debug['S'] = 0
+	case "json":
+		Debug['S']++
+		asmjson = true
+	default:
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			return fmt.Errorf("invalid count %q", s)
+		}
+		Debug['S'] = n
+	}
+	return nil
+}
+
+func (asmFlag) IsBoolFlag() bool {
+	return true
+}
+
 func doversion() {
 	p := obj.Expstring()
 	if p == "X:none" {
@@ -90,56 +165,28 @@ func doversion() {
 func Main() {
 	defer hidePanic()
 
-	// Allow GOARCH=thearch.thestring or GOARCH=thearch.thestringsuffix,
-	// but not other values.
 	p := obj.Getgoarch()
+	thebackend = backends[p]
+	if thebackend == nil {
+		fmt.Fprintf(os.Stderr, "compile: unknown architecture %q\n", p)
+		os.Exit(2)
+	}
+	thebackend.Init(&Thearch)
 
+	// Allow GOARCH=thearch.thestring or GOARCH=thearch.thestringsuffix,
+	// but not other values.
 	if !strings.HasPrefix(p, Thearch.Thestring) {
 		log.Fatalf("cannot use %cg with GOARCH=%s", Thearch.Thechar, p)
 	}
 	goarch = p
+	checkArch()
 
 	Ctxt = obj.Linknew(Thearch.Thelinkarch)
 	Ctxt.DiagFunc = Yyerror
 	Ctxt.Bso = &bstdout
 	bstdout = *obj.Binitw(os.Stdout)
 
-	localpkg = mkpkg("")
-	localpkg.Prefix = "\"\""
-
-	// pseudo-package, for scoping
-	builtinpkg = mkpkg("go.builtin")
-
-	builtinpkg.Prefix = "go.builtin" // not go%2ebuiltin
-
-	// pseudo-package, accessed by import "unsafe"
-	unsafepkg = mkpkg("unsafe")
-
-	unsafepkg.Name = "unsafe"
-
-	// real package, referred to by generated runtime calls
-	Runtimepkg = mkpkg("runtime")
-
-	Runtimepkg.Name = "runtime"
-
-	// pseudo-packages used in symbol tables
-	itabpkg = mkpkg("go.itab")
-
-	itabpkg.Name = "go.itab"
-	itabpkg.Prefix = "go.itab" // not go%2eitab
-
-	typelinkpkg = mkpkg("go.typelink")
-	typelinkpkg.Name = "go.typelink"
-	typelinkpkg.Prefix = "go.typelink" // not go%2etypelink
-
-	trackpkg = mkpkg("go.track")
-
-	trackpkg.Name = "go.track"
-	trackpkg.Prefix = "go.track" // not go%2etrack
-
-	typepkg = mkpkg("type")
-
-	typepkg.Name = "type"
+	initPackages()
 
 	goroot = obj.Getgoroot()
 	goos = obj.Getgoos()
@@ -163,28 +210,41 @@ func Main() {
 	obj.Flagcount("N", "disable optimizations", &Debug['N'])
 	obj.Flagcount("P", "debug peephole optimizer", &Debug['P'])
 	obj.Flagcount("R", "debug register optimizer", &Debug['R'])
-	obj.Flagcount("S", "print assembly listing", &Debug['S'])
+	flag.Var(asmFlag{}, "S", "print assembly listing; -S=json lists the functions in JSON")
 	obj.Flagfn0("V", "print compiler version", doversion)
 	obj.Flagcount("W", "debug parse tree after type checking", &Debug['W'])
+	obj.Flagcount("asan", "build code compatible with C/C++ address sanitizer", &flag_asan)
 	obj.Flagstr("asmhdr", "write assembly header to `file`", &asmhdr)
+	obj.Flagstr("bench", "write the time of each phase for each function to `file`", &benchfile)
 	obj.Flagstr("buildid", "record `id` as the build id in the export metadata", &buildid)
+	flag.IntVar(&nBackendWorkers, "c", 1, "run the SSA optimization passes on `n` goroutines")
 	obj.Flagcount("complete", "compiling complete package (no C or assembly)", &pure_go)
+	obj.Flagcount("compressexport", "compress binary export data", &compressexport)
+	obj.Flagcount("cover", "instrument basic blocks for coverage", &flag_cover)
 	obj.Flagstr("d", "print debug information about items in `list`", &debugstr)
+	flag.IntVar(&dwarflevel, "dwarf", dwarfVars, "describe functions in DWARF at `level`: 0 none, 1 lines only, 2 local variables")
+	obj.Flagstr("dwarfdecls", "describe the package's `decls` in DWARF even if unused: exported or all", &dwarfdecls)
 	obj.Flagcount("e", "no limit on number of errors reported", &Debug['e'])
+	obj.Flagstr("embedsrc", "record the source files for debuggers: `what` is hash or content", &embedsrc)
 	obj.Flagcount("f", "debug stack frames", &Debug['f'])
+	obj.Flagstr("fncache", "reuse the code of unchanged functions from the cache in `dir` (experimental)", &fncachedir)
 	obj.Flagcount("g", "debug code generation", &Debug['g'])
 	obj.Flagcount("h", "halt on error", &Debug['h'])
 	obj.Flagcount("i", "debug line number stack", &Debug['i'])
+	obj.Flagfn1("importcfg", "read import configuration from `file`", readImportCfg)
 	obj.Flagfn1("importmap", "add `definition` of the form source=actual to import map", addImportMap)
 	obj.Flagstr("installsuffix", "set pkg directory `suffix`", &flag_installsuffix)
 	obj.Flagcount("j", "debug runtime-initialized variables", &Debug['j'])
+	obj.Flagcount("json", "print errors as JSON objects", &flag_json)
 	obj.Flagcount("l", "disable inlining", &Debug['l'])
+	obj.Flagstr("linkobj", "write linker-specific object to `file`", &linkobj)
 	obj.Flagcount("live", "debug liveness analysis", &debuglive)
 	obj.Flagcount("m", "print optimization decisions", &Debug['m'])
 	obj.Flagcount("msan", "build code compatible with C/C++ memory sanitizer", &flag_msan)
-	obj.Flagcount("newexport", "use new export format", &newexport) // TODO(gri) remove eventually (issue 13241)
+	obj.Flagcount("newexport", "use new export format (default; 0 for old textual format)", &newexport) // TODO(gri) remove eventually (issue 13241)
 	obj.Flagcount("nolocalimports", "reject local (relative) imports", &nolocalimports)
 	obj.Flagstr("o", "write output to `file`", &outfile)
+	flag.BoolVar(&Ctxt.Flag_objindex, "objindex", false, "write symbol index to object file (linker format version 2)")
 	obj.Flagstr("p", "set expected package import `path`", &myimportpath)
 	obj.Flagcount("pack", "write package file instead of object file", &writearchive)
 	obj.Flagcount("r", "debug generated wrappers", &Debug['r'])
@@ -225,6 +285,8 @@ func Main() {
 	Ctxt.Flag_optimize = Debug['N'] == 0
 
 	Ctxt.Debugasm = int32(Debug['S'])
+	Ctxt.Debugasmjson = asmjson
+	Ctxt.Debugasmsrc = true
 	Ctxt.Debugvlog = int32(Debug['v'])
 
 	if flag.NArg() < 1 {
@@ -241,13 +303,34 @@ func Main() {
 		msanpkg = mkpkg("runtime/msan")
 		msanpkg.Name = "msan"
 	}
-	if flag_race != 0 && flag_msan != 0 {
-		log.Fatal("cannot use both -race and -msan")
-	} else if flag_race != 0 || flag_msan != 0 {
+	if flag_asan != 0 {
+		asanpkg = mkpkg("runtime/asan")
+		asanpkg.Name = "asan"
+	}
+	switch dwarfdecls {
+	case "", "exported", "all":
+	default:
+		log.Fatalf("-dwarfdecls must be exported or all, not %q", dwarfdecls)
+	}
+	switch embedsrc {
+	case "", "hash", "content":
+	default:
+		log.Fatalf("-embedsrc must be hash or content, not %q", embedsrc)
+	}
+	if dwarflevel < dwarfNone || dwarflevel > dwarfVars {
+		log.Fatalf("-dwarf must be 0, 1 or 2, not %d", dwarflevel)
+	}
+	if dwarflevel == dwarfNone && dwarfdecls != "" {
+		log.Fatal("cannot use -dwarfdecls with -dwarf=0")
+	}
+	if flag_race+flag_msan+flag_asan > 1 {
+		log.Fatal("can use only one of -race, -msan and -asan")
+	} else if flag_race != 0 || flag_msan != 0 || flag_asan != 0 {
 		instrumenting = true
 	}
 
 	// parse -d argument
+	ssaDebug := false
 	if debugstr != "" {
 	Split:
 		for _, name := range strings.Split(debugstr, ",") {
@@ -286,11 +369,27 @@ func Main() {
 				if err != "" {
 					log.Fatalf(err)
 				}
+				ssaDebug = true
 				continue Split
 			}
 			log.Fatalf("unknown debug key -d %s\n", name)
 		}
 	}
+	if flag_cover != 0 && Debug_libfuzzer != 0 {
+		log.Fatal("cannot use both -cover and -d=libfuzzer")
+	}
+	if nBackendWorkers < 1 {
+		log.Fatalf("-c must be at least 1, got %d", nBackendWorkers)
+	}
+	if ssaDebug || os.Getenv("GOSSAFUNC") != "" || os.Getenv("GOSSAHASH") != "" {
+		// The SSA debugging output is per function;
+		// don't interleave it.
+		nBackendWorkers = 1
+	}
+	if ssaDebug || os.Getenv("GOSSAFUNC") != "" || Debug['S'] != 0 || debuglive != 0 || Debug_checknil != 0 || Debug_mergeautos != 0 || Debug_wb > 1 {
+		// The debugging output of the back end is not cached.
+		fncachedir = ""
+	}
 
 	// enable inlining.  for now:
 	//	default: inlining on.  (debug['l'] == 1)
@@ -300,7 +399,15 @@ func Main() {
 		Debug['l'] = 1 - Debug['l']
 	}
 
-	Thearch.Betypeinit()
+	setduff(&Thearch.Duffzero, "duffzero", Debug_duffzeromin, Debug_duffzeromax)
+	setduff(&Thearch.Duffcopy, "duffcopy", Debug_duffcopymin, Debug_duffcopymax)
+	if Debug_maxptrmask != 0 && Debug_maxptrmask < maxPtrmaskBytes {
+		log.Fatalf("invalid -d maxptrmask=%d: must be at least %d", Debug_maxptrmask, maxPtrmaskBytes)
+	}
+	startTimings()
+	startNodeCounts()
+
+	thebackend.TypeInit()
 	if Widthptr == 0 {
 		Fatalf("betypeinit failed")
 	}
@@ -308,18 +415,22 @@ func Main() {
 	initUniverse()
 
 	blockgen = 1
-	dclcontext = PEXTERN
+	dclcontext = ir.PEXTERN
 	nerrors = 0
 	lexlineno = 1
 
 	loadsys()
 
+	tparse := timestart()
 	for _, infile = range flag.Args() {
 		if trace && Debug['x'] != 0 {
 			fmt.Printf("--- %s ---\n", infile)
 		}
 
 		linehistpush(infile)
+		if embedsrc != "" {
+			recordsrcfile(infile)
+		}
 
 		f, err := os.Open(infile)
 		if err != nil {
@@ -352,10 +463,105 @@ func Main() {
 		f.Close()
 	}
 
+	timeend("parse", nil, tparse)
+
 	testdclstack()
 	mkpackage(localpkg.Name) // final import not used checks
 	finishUniverse()
 
+	// Phases 1-4: Type check, and decide how closures capture variables.
+	typecheckxtop()
+
+	if nsavederrors+nerrors != 0 {
+		errorexit()
+	}
+	runAnalyses(AfterTypecheck)
+
+	// Coverage instrumentation must run before inlining,
+	// so that inlined copies of function bodies count too.
+	if flag_cover != 0 || Debug_libfuzzer != 0 {
+		cover(xtop)
+	}
+
+	// Phase 5: Inlining
+	inlinextop()
+
+	// Phase 6: Escape analysis.
+	// Required for moving heap allocations onto stack,
+	// which in turn is required by the closure implementation,
+	// which stores the addresses of stack variables into the closure.
+	// If the closure does not escape, it needs to be on the stack
+	// or else the stack copier will not update it.
+	// Large values are also moved off stack in escape analysis;
+	// because large values may contain pointers, it must happen early.
+	escapextop()
+	runAnalyses(AfterEscape)
+
+	// Phase 7: Transform closure bodies to properly reference captured variables.
+	// This needs to happen before walk, because closures must be transformed
+	// before walk reaches a call of a closure.
+	// Closures only called through a local variable are first turned
+	// into directly called closures, using the results of escape analysis.
+	transformclosures()
+
+	// Compute the initialization order, and compile init, before the
+	// other functions: the order depends on the bodies of the functions
+	// that package-level variables refer to, and funcdone releases the
+	// bodies once they are compiled.
+	setNodePhase("compile")
+	if nsavederrors+nerrors == 0 {
+		t := timestart()
+		fninit(xtop)
+		timeend("fninit", nil, t)
+	}
+
+	// With -linkobj, the export data is complete once the package is
+	// type checked, inlined and escape analyzed and has its init
+	// function: write it before running the back end.
+	if linkobj != "" && nsavederrors+nerrors == 0 {
+		setNodePhase("dumpexport")
+		t := timestart()
+		dumpexportobj()
+		timeend("dumpexport", nil, t)
+	}
+
+	// Phase 8: Compile top level functions.
+	compileFunctions()
+
+	if compiling_runtime != 0 {
+		checknowritebarrierrec()
+	}
+
+	// Phase 9: Check external declarations.
+	for i, n := range externdcl {
+		if n.Op == ir.ONAME {
+			externdcl[i] = typecheck(externdcl[i], Erv)
+		}
+	}
+
+	if nerrors+nsavederrors != 0 {
+		errorexit()
+	}
+
+	setNodePhase("dumpobj")
+	tdump := timestart()
+	dumpobj()
+	timeend("dumpobj", nil, tdump)
+
+	if asmhdr != "" {
+		dumpasmhdr()
+	}
+
+	if nerrors+nsavederrors != 0 {
+		errorexit()
+	}
+
+	Flusherrors()
+}
+
+// typecheckxtop type checks the declarations in xtop, in phases, and
+// decides how closures capture their variables.
+func typecheckxtop() {
 	typecheckok = true
 	if Debug['f'] != 0 {
 		frame(1)
@@ -368,9 +574,11 @@ func Main() {
 	//   and methods but doesn't depend on any of it.
 	defercheckwidth()
 
+	setNodePhase("typecheck")
+	tcheck := timestart()
 	// Don't use range--typecheck can add closures to xtop.
 	for i := 0; i < len(xtop); i++ {
-		if xtop[i].Op != ODCL && xtop[i].Op != OAS && xtop[i].Op != OAS2 {
+		if xtop[i].Op != ir.ODCL && xtop[i].Op != ir.OAS && xtop[i].Op != ir.OAS2 {
 			xtop[i] = typecheck(xtop[i], Etop)
 		}
 	}
@@ -380,24 +588,43 @@ func Main() {
 
 	// Don't use range--typecheck can add closures to xtop.
 	for i := 0; i < len(xtop); i++ {
-		if xtop[i].Op == ODCL || xtop[i].Op == OAS || xtop[i].Op == OAS2 {
+		if xtop[i].Op == ir.ODCL || xtop[i].Op == ir.OAS || xtop[i].Op == ir.OAS2 {
 			xtop[i] = typecheck(xtop[i], Etop)
 		}
 	}
+
+	// Variables declared without an initializer do not appear in xtop.
+	// Check their types now, so that errors in array bounds are
+	// reported even if later phases stop early.
+	for i, n := range externdcl {
+		if n.Op == ir.ONAME && n.Sym.Pkg == localpkg {
+			externdcl[i] = typecheck(externdcl[i], Erv)
+		}
+	}
 	resumecheckwidth()
+	timeend("typecheck", nil, tcheck)
 
 	// Phase 3: Type check function bodies.
 	// Don't use range--typecheck can add closures to xtop.
 	for i := 0; i < len(xtop); i++ {
-		if xtop[i].Op == ODCLFUNC || xtop[i].Op == OCLOSURE {
+		if xtop[i].Op == ir.ODCLFUNC || xtop[i].Op == ir.OCLOSURE {
+			t := timestart()
 			Curfn = xtop[i]
 			decldepth = 1
 			saveerrors()
+			if Curfn.Func.Closure != nil {
+				// A closure in a constant declaration sees its iota.
+				iotastack = append(iotastack, Curfn.Func.Closure.Func.Iota)
+			}
 			typecheckslice(Curfn.Nbody.Slice(), Etop)
+			if Curfn.Func.Closure != nil {
+				iotastack = iotastack[:len(iotastack)-1]
+			}
 			checkreturn(Curfn)
 			if nerrors != 0 {
 				Curfn.Nbody.Set(nil) // type errors; do not compile
 			}
+			timeend("typecheck", Curfn, t)
 		}
 	}
 
@@ -405,24 +632,23 @@ func Main() {
 	// This needs to run before escape analysis,
 	// because variables captured by value do not escape.
 	for _, n := range xtop {
-		if n.Op == ODCLFUNC && n.Func.Closure != nil {
+		if n.Op == ir.ODCLFUNC && n.Func.Closure != nil {
 			Curfn = n
 			capturevars(n)
 		}
 	}
 
 	Curfn = nil
+}
 
-	if nsavederrors+nerrors != 0 {
-		errorexit()
-	}
-
-	// Phase 5: Inlining
+// inlinextop finds the functions in xtop that can be inlined and
+// inlines the calls to them.
+func inlinextop() {
 	if Debug['l'] > 1 {
 		// Typecheck imported function bodies if debug['l'] > 1,
 		// otherwise lazily when used or re-exported.
 		for _, n := range importlist {
-			if len(n.Func.Inl.Slice()) != 0 {
+			if hasinl(n) {
 				saveerrors()
 				typecheckinl(n)
 			}
@@ -433,82 +659,200 @@ func Main() {
 		}
 	}
 
+	setNodePhase("inline")
 	if Debug['l'] != 0 {
 		// Find functions that can be inlined and clone them before walk expands them.
-		visitBottomUp(xtop, func(list []*Node, recursive bool) {
+		visitBottomUp(xtop, func(list []*ir.Node, recursive bool) {
 			for _, n := range list {
-				if n.Op == ODCLFUNC {
+				if n.Op == ir.ODCLFUNC {
+					t := timestart()
 					caninl(n)
 					inlcalls(n)
+					timeend("inline", n, t)
 				}
 			}
 		})
 	}
+}
 
-	// Phase 6: Escape analysis.
-	// Required for moving heap allocations onto stack,
-	// which in turn is required by the closure implementation,
-	// which stores the addresses of stack variables into the closure.
-	// If the closure does not escape, it needs to be on the stack
-	// or else the stack copier will not update it.
-	// Large values are also moved off stack in escape analysis;
-	// because large values may contain pointers, it must happen early.
+// escapextop runs escape analysis on the functions in xtop.
+func escapextop() {
+	setNodePhase("escape")
+	tesc := timestart()
 	escapes(xtop)
+	timeend("escape", nil, tesc)
+}
 
-	// Phase 7: Transform closure bodies to properly reference captured variables.
-	// This needs to happen before walk, because closures must be transformed
-	// before walk reaches a call of a closure.
+// transformclosures rewrites the closures in xtop to reference their
+// captured variables, after turning the ones only called through a
+// local variable into direct calls.
+func transformclosures() {
+	for _, n := range xtop {
+		if n.Op == ir.ODCLFUNC {
+			Curfn = n
+			directclosures(n)
+		}
+	}
 	for _, n := range xtop {
-		if n.Op == ODCLFUNC && n.Func.Closure != nil {
+		if n.Op == ir.ODCLFUNC && n.Func.Closure != nil {
 			Curfn = n
 			transformclosure(n)
 		}
 	}
 
 	Curfn = nil
+}
 
-	// Phase 8: Compile top level functions.
-	// Don't use range--walk can add functions to xtop.
-	for i := 0; i < len(xtop); i++ {
-		if xtop[i].Op == ODCLFUNC {
-			funccompile(xtop[i])
-		}
-	}
+// initPackages creates the package being compiled and the
+// pseudo-packages the compiler puts its own symbols in.
+func initPackages() {
+	localpkg = mkpkg("")
+	localpkg.Prefix = "\"\""
 
-	if nsavederrors+nerrors == 0 {
-		fninit(xtop)
-	}
+	// pseudo-package, for scoping
+	builtinpkg = mkpkg("go.builtin")
 
-	if compiling_runtime != 0 {
-		checknowritebarrierrec()
-	}
+	builtinpkg.Prefix = "go.builtin" // not go%2ebuiltin
 
-	// Phase 9: Check external declarations.
-	for i, n := range externdcl {
-		if n.Op == ONAME {
-			externdcl[i] = typecheck(externdcl[i], Erv)
-		}
-	}
+	// pseudo-package, accessed by import "unsafe"
+	unsafepkg = mkpkg("unsafe")
 
-	if nerrors+nsavederrors != 0 {
-		errorexit()
-	}
+	unsafepkg.Name = "unsafe"
 
-	dumpobj()
+	// real package, referred to by generated runtime calls
+	Runtimepkg = mkpkg("runtime")
 
-	if asmhdr != "" {
-		dumpasmhdr()
-	}
+	Runtimepkg.Name = "runtime"
 
-	if nerrors+nsavederrors != 0 {
-		errorexit()
-	}
+	// pseudo-packages used in symbol tables
+	itabpkg = mkpkg("go.itab")
 
-	Flusherrors()
+	itabpkg.Name = "go.itab"
+	itabpkg.Prefix = "go.itab" // not go%2eitab
+
+	typelinkpkg = mkpkg("go.typelink")
+	typelinkpkg.Name = "go.typelink"
+	typelinkpkg.Prefix = "go.typelink" // not go%2etypelink
+
+	trackpkg = mkpkg("go.track")
+
+	trackpkg.Name = "go.track"
+	trackpkg.Prefix = "go.track" // not go%2etrack
+
+	reflectmethodpkg = mkpkg("go.reflectmethod")
+
+	reflectmethodpkg.Name = "go.reflectmethod"
+	reflectmethodpkg.Prefix = "go.reflectmethod" // not go%2ereflectmethod
+
+	dwarfdeclspkg = mkpkg("go.dwarfdecls")
+
+	dwarfdeclspkg.Name = "go.dwarfdecls"
+	dwarfdeclspkg.Prefix = "go.dwarfdecls" // not go%2edwarfdecls
+
+	srcfilepkg = mkpkg("go.srcfile")
+
+	srcfilepkg.Name = "go.srcfile"
+	srcfilepkg.Prefix = "go.srcfile" // not go%2esrcfile
+
+	typepkg = mkpkg("type")
+
+	typepkg.Name = "type"
+}
+
+// setduff overrides the architecture's default Duff's device range r
+// with the values of the -d name{min,max} flags, if set.
+// A zero r means the back end does not use name at all.
+func setduff(r *DuffRange, name string, min, max int) {
+	if min == 0 && max == 0 {
+		return
+	}
+	if *r == (DuffRange{}) {
+		log.Fatalf("-d %smin and %smax are not supported on %s", name, name, Thearch.Thestring)
+	}
+	if min != 0 {
+		r.Min = int64(min)
+	}
+	if max != 0 {
+		r.Max = int64(max)
+	}
+	if r.Min < 1 || r.Max > DuffMaxWords || r.Min > r.Max {
+		log.Fatalf("invalid -d %s range [%d, %d]: must be within [1, %d] with min <= max", name, r.Min, r.Max, DuffMaxWords)
+	}
 }
 
 var importMap = map[string]string{}
 
+// importMapped records the entries of importMap applied to the
+// imports of the package being compiled. dumpexport records them
+// in the export metadata.
+var importMapped = map[string]string{}
+
+// checkArch reports the required Thearch hooks that the back end's
+// Init method did not set. A back end under construction fails here
+// with a list of what is missing rather than with a nil dereference
+// somewhere in code generation.
+func checkArch() {
+	a := &Thearch
+	hooks := []struct {
+		name string
+		set  bool
+	}{
+		{"Thelinkarch", a.Thelinkarch != nil},
+		{"Blockcopy", a.Blockcopy != nil},
+		{"BtoF", a.BtoF != nil},
+		{"BtoR", a.BtoR != nil},
+		{"Cgen_hmul", a.Cgen_hmul != nil},
+		{"Cgen_shift", a.Cgen_shift != nil},
+		{"Clearfat", a.Clearfat != nil},
+		{"Copyas", a.Copyas != nil},
+		{"Copyu", a.Copyu != nil},
+		{"Doregbits", a.Doregbits != nil},
+		{"Excludedregs", a.Excludedregs != nil},
+		{"Expandchecks", a.Expandchecks != nil},
+		{"FtoB", a.FtoB != nil},
+		{"Getg", a.Getg != nil},
+		{"Gins", a.Gins != nil},
+		{"Ginscmp", a.Ginscmp != nil},
+		{"Ginscon", a.Ginscon != nil},
+		{"Ginsnop", a.Ginsnop != nil},
+		{"Gmove", a.Gmove != nil},
+		{"Optoas", a.Optoas != nil},
+		{"Proginfo", a.Proginfo != nil},
+		{"Regnames", a.Regnames != nil},
+		{"Regtyp", a.Regtyp != nil},
+		{"RtoB", a.RtoB != nil},
+		{"Sameaddr", a.Sameaddr != nil},
+		{"Smallindir", a.Smallindir != nil},
+		{"Stackaddr", a.Stackaddr != nil},
+		{"Sudoaddable", a.Sudoaddable != nil},
+		{"Sudoclean", a.Sudoclean != nil},
+		{"ZeroRange", a.ZeroRange != nil},
+	}
+	var missing []string
+	for _, h := range hooks {
+		if !h.set {
+			missing = append(missing, h.name)
+		}
+	}
+	if a.Thelinkarch != nil && a.Thelinkarch.Regsize == 4 && (a.Cgen64 == nil || a.Cmp64 == nil) {
+		missing = append(missing, "Cgen64", "Cmp64")
+	}
+	// Agenr indexes with Cgenindex on arm and Igenindex on 386.
+	if a.Thechar == '5' && a.Cgenindex == nil {
+		missing = append(missing, "Cgenindex")
+	}
+	if a.Thechar == '8' && a.Igenindex == nil {
+		missing = append(missing, "Igenindex")
+	}
+	// The SSA hooks come as a set.
+	if a.SSAGenValue != nil && (a.SSAGenBlock == nil || a.SSAMarkMoves == nil || a.SSARegToReg == nil) {
+		missing = append(missing, "SSAGenBlock/SSAMarkMoves/SSARegToReg")
+	}
+	if len(missing) > 0 {
+		log.Fatalf("incomplete back end for GOARCH=%s: missing %s", goarch, strings.Join(missing, ", "))
+	}
+}
+
 func addImportMap(s string) {
 	if strings.Count(s, "=") != 1 {
 		log.Fatal("-importmap argument must be of the form source=actual")
@@ -521,6 +865,62 @@ func addImportMap(s string) {
 	importMap[source] = actual
 }
 
+// packageFile maps import paths to the files to load for them,
+// as read from the -importcfg file. If packageFile is not nil,
+// findpkg consults only packageFile and does not search directories.
+var packageFile map[string]string
+
+// readImportCfg reads the import configuration file.
+// Each line is blank, a # comment, or one of
+//
+//	importmap source=actual
+//	packagefile path=file
+//
+// importmap is like the -importmap flag. packagefile says that
+// the package with import path path is to be loaded from file.
+func readImportCfg(file string) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		log.Fatalf("-importcfg: %v", err)
+	}
+	if packageFile == nil {
+		packageFile = map[string]string{}
+	}
+
+	for lineNum, line := range strings.Split(string(data), "\n") {
+		lineNum++ // 1-based
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		var verb, args string
+		if i := strings.Index(line, " "); i < 0 {
+			verb = line
+		} else {
+			verb, args = line[:i], strings.TrimSpace(line[i+1:])
+		}
+		var before, after string
+		if i := strings.Index(args, "="); i >= 0 {
+			before, after = args[:i], args[i+1:]
+		}
+		switch verb {
+		default:
+			log.Fatalf("%s:%d: unknown directive %q", file, lineNum, verb)
+		case "importmap":
+			if before == "" || after == "" {
+				log.Fatalf(`%s:%d: invalid importmap: syntax is "importmap source=actual"`, file, lineNum)
+			}
+			importMap[before] = after
+		case "packagefile":
+			if before == "" || after == "" {
+				log.Fatalf(`%s:%d: invalid packagefile: syntax is "packagefile path=file"`, file, lineNum)
+			}
+			packageFile[before] = after
+		}
+	}
+}
+
 func saveerrors() {
 	nsavederrors += nerrors
 	nerrors = 0
@@ -576,6 +976,11 @@ func islocalname(name string) bool {
 }
 
 func findpkg(name string) (file string, ok bool) {
+	if packageFile != nil {
+		file, ok = packageFile[name]
+		return file, ok
+	}
+
 	if islocalname(name) {
 		if safemode != 0 || nolocalimports != 0 {
 			return "", false
@@ -626,6 +1031,9 @@ func findpkg(name string) (file string, ok bool) {
 		} else if flag_msan != 0 {
 			suffixsep = "_"
 			suffix = "msan"
+		} else if flag_asan != 0 {
+			suffixsep = "_"
+			suffix = "asan"
 		}
 
 		file = fmt.Sprintf("%s/pkg/%s_%s%s%s/%s.a", goroot, goos, goarch, suffixsep, suffix, name)
@@ -663,7 +1071,7 @@ func loadsys() {
 	incannedimport = 0
 }
 
-func importfile(f *Val, indent []byte) {
+func importfile(f *ir.Val, indent []byte) {
 	if importpkg != nil {
 		Fatalf("importpkg not nil")
 	}
@@ -698,6 +1106,7 @@ func importfile(f *Val, indent []byte) {
 	}
 
 	if mapped, ok := importMap[path_]; ok {
+		importMapped[path_] = mapped
 		path_ = mapped
 	}
 
@@ -736,6 +1145,7 @@ func importfile(f *Val, indent []byte) {
 	}
 
 	importpkg = mkpkg(path_)
+	importfiles[path_] = file
 
 	if importpkg.Imported {
 		return
@@ -775,14 +1185,23 @@ func importfile(f *Val, indent []byte) {
 
 		q := fmt.Sprintf("%s %s %s %s", obj.Getgoos(), obj.Getgoarch(), obj.Getgoversion(), obj.Expstring())
 		if p[10:] != q {
-			Yyerror("import %s: object is [%s] expected [%s]", file, p[10:], q)
-			errorexit()
+			target := obj.Getgoos() + " " + obj.Getgoarch() + " "
+			if !strings.HasPrefix(p[10:], target) {
+				Yyerror("import %s: object is [%s] expected [%s]", file, p[10:], q)
+				errorexit()
+			}
+			incompatibleimport(path_, p[10+len(target):], q[len(target):])
 		}
 	}
 
 	// assume files move (get installed)
 	// so don't record the full path.
-	linehistpragma(file[len(file)-len(path_)-2:]) // acts as #pragma lib
+	if packageFile != nil {
+		// the file name is arbitrary; record the import path
+		linehistpragma(path_ + filepath.Ext(file)) // acts as #pragma lib
+	} else {
+		linehistpragma(file[len(file)-len(path_)-2:]) // acts as #pragma lib
+	}
 
 	// In the importfile, if we find:
 	// $$\n  (old format): position the input right after $$\n and return
@@ -828,6 +1247,14 @@ func importfile(f *Val, indent []byte) {
 	}
 }
 
+// incompatibleimport reports that the package with import path path
+// was compiled by a compiler whose output this compiler cannot read,
+// and exits. have and want describe the two compilers.
+func incompatibleimport(path, have, want string) {
+	Yyerror("package %s was compiled with an incompatible compiler version\n\thave %s\n\twant %s", path, have, want)
+	errorexit()
+}
+
 func pkgnotused(lineno int32, path string, name string) {
 	// If the package was imported with a name other than the final
 	// import path element, show it explicitly in the error message.
@@ -839,11 +1266,17 @@ func pkgnotused(lineno int32, path string, name string) {
 	if i := strings.LastIndex(elem, "/"); i >= 0 {
 		elem = elem[i+1:]
 	}
-	if name == "" || elem == name {
-		yyerrorl(lineno, "imported and not used: %q", path)
-	} else {
-		yyerrorl(lineno, "imported and not used: %q as %s", path, name)
+	msg := fmt.Sprintf("imported and not used: %q", path)
+	if name != "" && elem != name {
+		msg += " as " + name
+	}
+	r, inComments := importSource(lineno, path, name)
+	if inComments {
+		// The package is used, but only in comments
+		// (such as examples), which do not count.
+		msg += fmt.Sprintf(" (used only in comments; to import it for its side effects only, use import _ %q)", path)
 	}
+	yyerrorlremove(lineno, r, "%s", msg)
 }
 
 func mkpackage(pkgname string) {
@@ -856,11 +1289,11 @@ func mkpackage(pkgname string) {
 		if pkgname != localpkg.Name {
 			Yyerror("package %s; expected %s", pkgname, localpkg.Name)
 		}
-		for _, s := range localpkg.Syms {
+		for _, s := range localpkg.Syms() {
 			if s.Def == nil {
 				continue
 			}
-			if s.Def.Op == OPACK {
+			if s.Def.Op == ir.OPACK {
 				// throw away top-level package name leftover
 				// from previous file.
 				// leave s->block set to cause redeclaration
@@ -0,0 +1,154 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gc
+
+import "cmd/compile/internal/ir"
+import "cmd/internal/obj"
+
+// mergeautos finds pointer-free PAUTO variables in Curfn.Func.Dcl whose
+// lifetimes do not overlap and that can therefore share a stack slot.
+// It returns a map from each variable that should reuse another's slot
+// to the variable that owns the slot. Owners appear before the variables
+// merged into them in Curfn.Func.Dcl.
+//
+// Only variables the garbage collector does not care about are
+// considered, so the stack maps are unaffected. Variables whose address
+// is taken, or is loaded into a register, are skipped: accesses through
+// the pointer are invisible to the liveness analysis.
+// This is the common case for large arrays and structs, which are
+// copied and zeroed through registers.
+func mergeautos(ptxt *obj.Prog) map[*ir.Node]*ir.Node {
+	if Debug['N'] != 0 {
+		return nil
+	}
+
+	for _, ln := range Curfn.Func.Dcl {
+		ln.SetOpt(nil)
+	}
+	var vars []*ir.Node
+	for _, ln := range Curfn.Func.Dcl {
+		if ln.Op == ir.ONAME && ln.Class == ir.PAUTO && ln.Used && !ln.Addrtaken && ln.Type.Width > 0 && !haspointers(ln.Type) {
+			ln.Name.Curfn = Curfn // as in getvariables
+			ln.SetOpt(int32(len(vars)))
+			vars = append(vars, ln)
+		}
+	}
+	defer func() {
+		for _, ln := range vars {
+			ln.SetOpt(nil)
+		}
+	}()
+	if len(vars) < 2 {
+		return nil
+	}
+
+	cfg := newcfg(ptxt)
+	defer freecfg(cfg)
+
+	// Drop variables whose address is loaded into a register.
+	// Later accesses through that register do not mention the variable.
+	nvars := int32(len(vars))
+	skip := bvalloc(nvars)
+	for p := ptxt; p != nil; p = p.Link {
+		if p.From.Type == obj.TYPE_ADDR || p.Info.Flags&LeftAddr != 0 {
+			if i, ok := autoindex(&p.From, vars); ok {
+				bvset(skip, i)
+			}
+		}
+		if p.To.Type == obj.TYPE_ADDR || p.Info.Flags&RightAddr != 0 {
+			if i, ok := autoindex(&p.To, vars); ok {
+				bvset(skip, i)
+			}
+		}
+	}
+
+	lv := newliveness(Curfn, ptxt, cfg, vars)
+	livenessprologue(lv)
+	livenesssolve(lv)
+
+	// A variable live on entry is read before it is written on some
+	// path, so the argument below does not apply to it.
+	bvor(skip, skip, cfg[0].livein)
+
+	// Two variables interfere if one is referenced while the other is
+	// live. Walk each block backward, tracking the live set after
+	// each instruction.
+	interfere := make([]Bvec, nvars)
+	for i := range interfere {
+		interfere[i] = bvalloc(nvars)
+	}
+	live := bvalloc(nvars)
+	uevar := bvalloc(nvars)
+	varkill := bvalloc(nvars)
+	avarinit := bvalloc(nvars)
+	for _, bb := range cfg {
+		bvcopy(live, bb.liveout)
+		for p := bb.last; p != nil; p = p.Opt.(*obj.Prog) {
+			if p.As != obj.ATYPE && p.As != obj.AVARKILL {
+				if i, ok := autoindex(&p.From, vars); ok {
+					bvor(interfere[i], interfere[i], live)
+				}
+				if i, ok := autoindex(&p.To, vars); ok {
+					bvor(interfere[i], interfere[i], live)
+				}
+			}
+			progeffects(p, vars, uevar, varkill, avarinit)
+			bvandnot(live, live, varkill)
+			bvor(live, live, uevar)
+		}
+	}
+
+	// Greedily assign variables to slots in declaration order.
+	// A slot is shared only by variables of the same size and alignment.
+	type slot struct {
+		owner   *ir.Node
+		members []int32
+	}
+	var slots []*slot
+	merged := make(map[*ir.Node]*ir.Node)
+	for i := int32(0); i < nvars; i++ {
+		if bvget(skip, i) != 0 {
+			continue
+		}
+		n := vars[i]
+		var s *slot
+	search:
+		for _, t := range slots {
+			if t.owner.Type.Width != n.Type.Width || t.owner.Type.Align != n.Type.Align {
+				continue
+			}
+			for _, j := range t.members {
+				if bvget(interfere[i], j) != 0 || bvget(interfere[j], i) != 0 {
+					continue search
+				}
+			}
+			s = t
+			break
+		}
+		if s == nil {
+			slots = append(slots, &slot{owner: n, members: []int32{i}})
+			continue
+		}
+		s.members = append(s.members, i)
+		merged[n] = s.owner
+		if Debug_mergeautos != 0 {
+			Warnl(n.Lineno, "%v shares stack slot with %v", n, s.owner)
+		}
+	}
+	return merged
+}
+
+// autoindex returns the index in vars of the variable a refers to.
+func autoindex(a *obj.Addr, vars []*ir.Node) (int32, bool) {
+	if a.Node == nil || a.Name != obj.NAME_AUTO {
+		return 0, false
+	}
+	n := a.Node.(*ir.Node)
+	i, ok := n.Opt().(int32)
+	if !ok || i >= int32(len(vars)) || vars[i] != n {
+		return 0, false
+	}
+	return i, true
+}
@@ -47,7 +47,8 @@ func main() {
 
 // Compile .go file, import data from .o file, and write Go string version.
 func mkbuiltin(w io.Writer, name string) {
-	args := []string{"tool", "compile", "-A"}
+	// loadsys reads the builtin declarations in the textual format.
+	args := []string{"tool", "compile", "-A", "-newexport=0"}
 	if name == "runtime" {
 		args = append(args, "-u")
 	}
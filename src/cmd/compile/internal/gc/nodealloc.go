@@ -0,0 +1,68 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gc
+
+import "cmd/compile/internal/ir"
+import "fmt"
+
+// -d nodealloc counts the Nodes allocated in each phase of the
+// compilation, and the allocations avoided by reusing nodes, such as
+// the temporaries of order that are no longer live. It prints the
+// counts when the compiler exits.
+
+type nodePhase struct {
+	name   string
+	allocs int // Nodes allocated
+	reused int // allocations avoided
+}
+
+var (
+	nodePhases   []*nodePhase
+	curNodePhase *nodePhase
+)
+
+// setNodePhase starts counting allocations for the named phase.
+func setNodePhase(name string) {
+	if Debug_nodealloc == 0 {
+		return
+	}
+	for _, p := range nodePhases {
+		if p.name == name {
+			curNodePhase = p
+			return
+		}
+	}
+	curNodePhase = &nodePhase{name: name}
+	nodePhases = append(nodePhases, curNodePhase)
+}
+
+// nodeAllocated records the allocation of n. ssa.Compile may run
+// concurrently (see -c), but it allocates nodes only with backendMu
+// held.
+func nodeAllocated(n *ir.Node) {
+	if curNodePhase != nil {
+		curNodePhase.allocs++
+	}
+}
+
+// nodeReused records that n was reused in place of a new node.
+func nodeReused(n *ir.Node) {
+	if curNodePhase != nil {
+		curNodePhase.reused++
+	}
+}
+
+// startNodeCounts arranges for the counts to be printed at exit.
+func startNodeCounts() {
+	if Debug_nodealloc == 0 {
+		return
+	}
+	setNodePhase("parse")
+	AtExit(func() {
+		for _, p := range nodePhases {
+			fmt.Printf("%s: %d nodes allocated, %d without reuse\n", p.name, p.allocs, p.allocs+p.reused)
+		}
+	})
+}
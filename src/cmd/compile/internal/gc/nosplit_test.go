@@ -0,0 +1,58 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gc
+
+import (
+	"strings"
+	"testing"
+)
+
+// Make sure small leaf functions are compiled without a
+// stack-growth prologue and that other functions keep theirs.
+func TestLeafNosplit(t *testing.T) {
+	d := newTestDir(t, "TestLeafNosplit")
+	defer d.remove()
+
+	src := d.write("x.go", `
+package x
+
+func leaf(x []int, i int) int {
+	return x[i] + x[i+1]
+}
+
+func big(i int) int {
+	var a [64]int
+	a[i&63] = i
+	return a[(i+1)&63]
+}
+
+func g()
+
+func caller() {
+	g()
+}
+`)
+
+	for _, arch := range []string{"386", "amd64", "arm64", "ppc64le"} {
+		d.env = []string{"GOARCH=" + arch}
+		out := d.run("go", "tool", "compile", "-S", "-o", d.path("x.o"), src)
+		split := map[string]bool{}
+		var fn string
+		for _, line := range strings.Split(out, "\n") {
+			if i := strings.Index(line, "\tTEXT\t\"\"."); i >= 0 {
+				fn = line[i+len("\tTEXT\t\"\"."):]
+				fn = fn[:strings.Index(fn, "(")]
+			}
+			if fn != "" && strings.Contains(line, "runtime.morestack") {
+				split[fn] = true
+			}
+		}
+		for fn, want := range map[string]bool{"leaf": false, "big": true, "caller": true} {
+			if split[fn] != want {
+				t.Errorf("%s: %s has stack check = %v, want %v", arch, fn, split[fn], want)
+			}
+		}
+	}
+}
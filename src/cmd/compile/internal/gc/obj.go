@@ -5,11 +5,16 @@
 package gc
 
 import (
+	"bytes"
+	"cmd/compile/internal/ir"
 	"cmd/internal/obj"
+	"compress/zlib"
 	"crypto/sha256"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"strconv"
+	"strings"
 )
 
 // architecture-independent object file output
@@ -21,7 +26,29 @@ func formathdr(arhdr []byte, name string, size int64) {
 	copy(arhdr[:], fmt.Sprintf("%-16s%-12d%-6d%-6d%-8o%-10d`\n", name, 0, 0, 0, 0644, size))
 }
 
+// Object file output modes.
+const (
+	modeCompilerObj = 1 << iota // export data, for compiling importers
+	modeLinkerObj               // code and data, for the linker
+)
+
+// dumpobj writes the object file, or with -linkobj the linker object.
 func dumpobj() {
+	if linkobj == "" {
+		dumpobj1(outfile, modeCompilerObj|modeLinkerObj)
+		return
+	}
+	dumpobj1(linkobj, modeLinkerObj)
+}
+
+// dumpexportobj writes the export data to the -o file, with -linkobj.
+// It is called before the functions are compiled, so that importers
+// can be compiled while the back end runs.
+func dumpexportobj() {
+	dumpobj1(outfile, modeCompilerObj)
+}
+
+func dumpobj1(outfile string, mode int) {
 	var err error
 	bout, err = obj.Bopenw(outfile)
 	if err != nil {
@@ -40,7 +67,9 @@ func dumpobj() {
 	}
 
 	fmt.Fprintf(bout, "go object %s %s %s %s\n", obj.Getgoos(), obj.Getgoarch(), obj.Getgoversion(), obj.Expstring())
-	dumpexport()
+	if mode&modeCompilerObj != 0 {
+		dumpexport()
+	}
 
 	if writearchive != 0 {
 		bout.Flush()
@@ -52,8 +81,15 @@ func dumpobj() {
 		formathdr(arhdr[:], "__.PKGDEF", size)
 		bout.Write(arhdr[:])
 		bout.Flush()
-
 		obj.Bseek(bout, startobj+size+(size&1), 0)
+	}
+
+	if mode&modeLinkerObj == 0 {
+		obj.Bterm(bout)
+		return
+	}
+
+	if writearchive != 0 {
 		arhdr = [ArhdrSize]byte{}
 		bout.Write(arhdr[:])
 		startobj = obj.Boffset(bout)
@@ -61,7 +97,7 @@ func dumpobj() {
 	}
 
 	if pragcgobuf != "" {
-		if writearchive != 0 {
+		if writearchive != 0 || mode&modeCompilerObj == 0 {
 			// write empty export section; must be before cgo section
 			fmt.Fprintf(bout, "\n$$\n\n$$\n\n")
 		}
@@ -75,7 +111,13 @@ func dumpobj() {
 	externs := len(externdcl)
 
 	dumpglobls()
+	dumpdwarfdecls()
+	dumpsrcfiles()
 	dumptypestructs()
+	dumpcover()
+	if Debug_gcdata != 0 {
+		dumpgcdata()
+	}
 
 	// Dump extra globals.
 	tmp := externdcl
@@ -87,7 +129,9 @@ func dumpobj() {
 	externdcl = tmp
 
 	dumpdata()
-	obj.Writeobjdirect(Ctxt, bout)
+	obj.Flushplist(Ctxt)
+	dedupRodata()
+	obj.Writeobjfile(Ctxt, bout)
 
 	if writearchive != 0 {
 		bout.Flush()
@@ -103,17 +147,116 @@ func dumpobj() {
 	obj.Bterm(bout)
 }
 
+// dumpdwarfdecls emits the declarations selected by -dwarfdecls so
+// that the linker can describe them in DWARF even if nothing else
+// refers to them. The symbol go.dwarfdecls."". refers to the type
+// descriptors of the package's type declarations and to a symbol
+// go.dwarfdecls."".C for each integer constant C, holding its value.
+func dumpdwarfdecls() {
+	if dwarfdecls == "" {
+		return
+	}
+	s := Pkglookup(localpkg.Prefix+".", dwarfdeclspkg)
+	ot := 0
+	for _, n := range externdcl {
+		if n.Sym == nil || n.Sym.Pkg != localpkg || isblanksym(n.Sym) {
+			continue
+		}
+		if dwarfdecls == "exported" && !exportname(n.Sym.Name) {
+			continue
+		}
+		switch n.Op {
+		case ir.OTYPE:
+			ot = dsymptr(s, ot, typenamesym(n.Type), 0)
+		case ir.OLITERAL:
+			if c := dwarfconst(n); c != nil {
+				ot = dsymptr(s, ot, c, 0)
+			}
+		}
+	}
+	ggloblsym(s, int32(ot), obj.RODATA)
+}
+
+// dwarfconst emits the symbol describing the constant n for
+// -dwarfdecls. Only integer constants are described; untyped ones
+// are given their default type. It returns nil if n is not described.
+func dwarfconst(n *ir.Node) *ir.Sym {
+	t := n.Type
+	v := n.Val()
+	switch v.Ctype() {
+	case ir.CTINT:
+		if isideal(t) {
+			t = ir.Types[ir.TINT]
+		}
+	case ir.CTRUNE:
+		if isideal(t) {
+			t = ir.Runetype
+		}
+	default:
+		return nil
+	}
+	if !Isint[t.Etype] || doesoverflow(v, t) {
+		return nil
+	}
+	s := Pkglookup(localpkg.Prefix+"."+n.Sym.Name, dwarfdeclspkg)
+	// For unsigned types, Int64 gives the bits of the value, which
+	// the linker reads back as unsigned.
+	duintxx(s, 0, uint64(v.U.(*ir.Mpint).Int64()), 8)
+	ggloblsym(s, 8, obj.RODATA)
+	Linksym(s).Gotype = Linksym(typenamesym(t))
+	return s
+}
+
+// A srcfile is a source file recorded for -embedsrc.
+type srcfile struct {
+	name string // as recorded in the line table
+	data []byte
+}
+
+var srcfiles []srcfile
+
+// recordsrcfile reads the source file infile, which linehistpush
+// has just entered, for dumpsrcfiles.
+func recordsrcfile(infile string) {
+	data, err := ioutil.ReadFile(infile)
+	if err != nil {
+		fmt.Printf("open %s: %v\n", infile, err)
+		errorexit()
+	}
+	srcfiles = append(srcfiles, srcfile{Ctxt.LineHist.Top.AbsFile, data})
+}
+
+// dumpsrcfiles emits a symbol go.srcfile.name for each source file
+// recorded with -embedsrc, where name is the file's name in the line
+// table. The symbol holds the SHA-256 hash of the file's contents,
+// followed, for -embedsrc=content, by the contents compressed with zlib.
+func dumpsrcfiles() {
+	for _, f := range srcfiles {
+		h := sha256.Sum256(f.data)
+		b := h[:]
+		if embedsrc == "content" {
+			var buf bytes.Buffer
+			zw := zlib.NewWriter(&buf)
+			zw.Write(f.data)
+			zw.Close()
+			b = append(b, buf.Bytes()...)
+		}
+		s := Pkglookup(f.name, srcfilepkg)
+		ggloblsym(s, int32(dsname(s, 0, string(b))), obj.RODATA|obj.DUPOK)
+	}
+}
+
 func dumpglobls() {
 	// add globals
 	for _, n := range externdcl {
-		if n.Op != ONAME {
+		if n.Op != ir.ONAME {
 			continue
 		}
 
 		if n.Type == nil {
 			Fatalf("external %v nil type\n", n)
 		}
-		if n.Class == PFUNC {
+		if n.Class == ir.PFUNC {
 			continue
 		}
 		if n.Sym.Pkg != localpkg {
@@ -132,12 +275,65 @@ func dumpglobls() {
 	funcsyms = nil
 }
 
+// dedupRodata names each read-only static temporary by a hash of its
+// contents and marks it DUPOK, so that the linker keeps a single copy
+// of identical data from all packages, and writes identical data in
+// this package only once. A temporary that refers to symbols local to
+// this package, such as "".f or type."".T, other than other such
+// temporaries, keeps its name.
+func dedupRodata() {
+	ro := make(map[*obj.LSym]bool)
+	for _, s := range rodatasyms {
+		ro[Linksym(s)] = true
+	}
+	rodatasyms = nil
+
+	seen := make(map[*obj.LSym]bool)
+	var rename func(s *obj.LSym) bool
+	rename = func(s *obj.LSym) bool {
+		if seen[s] {
+			// Already renamed, or a reference cycle.
+			return s.Dupok != 0
+		}
+		seen[s] = true
+		h := sha256.New()
+		fmt.Fprintf(h, "%d %d\n", s.Size, len(s.P))
+		h.Write(s.P)
+		for i := range s.R {
+			r := &s.R[i]
+			if r.Sym == nil {
+				continue
+			}
+			if ro[r.Sym] && !rename(r.Sym) || r.Sym.Version != 0 || strings.Contains(r.Sym.Name, `"".`) {
+				return false
+			}
+			fmt.Fprintf(h, "%d %d %d %d %q\n", r.Off, r.Siz, r.Type, r.Add, r.Sym.Name)
+		}
+		s.Name = fmt.Sprintf("rodata·%x", h.Sum(nil))
+		s.Dupok = 1
+		return true
+	}
+
+	names := make(map[string]bool)
+	data := Ctxt.Data[:0]
+	for _, s := range Ctxt.Data {
+		if ro[s] && rename(s) {
+			if names[s.Name] {
+				continue
+			}
+			names[s.Name] = true
+		}
+		data = append(data, s)
+	}
+	Ctxt.Data = data
+}
+
 func Bputname(b *obj.Biobuf, s *obj.LSym) {
 	obj.Bwritestring(b, s.Name)
 	obj.Bputc(b, 0)
 }
 
-func Linksym(s *Sym) *obj.LSym {
+func Linksym(s *ir.Sym) *obj.LSym {
 	if s == nil {
 		return nil
 	}
@@ -158,7 +354,7 @@ func Linksym(s *Sym) *obj.LSym {
 	return ls
 }
 
-func duintxx(s *Sym, off int, v uint64, wid int) int {
+func duintxx(s *ir.Sym, off int, v uint64, wid int) int {
 	return duintxxLSym(Linksym(s), off, v, wid)
 }
 
@@ -171,19 +367,19 @@ func duintxxLSym(s *obj.LSym, off int, v uint64, wid int) int {
 	return int(obj.Setuintxx(Ctxt, s, int64(off), v, int64(wid)))
 }
 
-func duint8(s *Sym, off int, v uint8) int {
+func duint8(s *ir.Sym, off int, v uint8) int {
 	return duintxx(s, off, uint64(v), 1)
 }
 
-func duint16(s *Sym, off int, v uint16) int {
+func duint16(s *ir.Sym, off int, v uint16) int {
 	return duintxx(s, off, uint64(v), 2)
 }
 
-func duint32(s *Sym, off int, v uint32) int {
+func duint32(s *ir.Sym, off int, v uint32) int {
 	return duintxx(s, off, uint64(v), 4)
 }
 
-func duintptr(s *Sym, off int, v uint64) int {
+func duintptr(s *ir.Sym, off int, v uint64) int {
 	return duintxx(s, off, v, Widthptr)
 }
 
@@ -198,19 +394,28 @@ type stringConstantSyms struct {
 // contents to the pair of linker symbols for that string.
 var stringConstants = make(map[string]stringConstantSyms, 100)
 
-func stringsym(s string) (hdr, data *obj.LSym) {
-	var symname string
+// stringsymname returns the name of the string data symbol for s,
+// less the "go.string." prefix.
+func stringsymname(s string) string {
 	if len(s) > 100 {
 		// Huge strings are hashed to avoid long names in object files.
 		// Indulge in some paranoia by writing the length of s, too,
 		// as protection against length extension attacks.
 		h := sha256.New()
 		io.WriteString(h, s)
-		symname = fmt.Sprintf(".gostring.%d.%x", len(s), h.Sum(nil))
-	} else {
-		// Small strings get named directly by their contents.
-		symname = strconv.Quote(s)
+		return fmt.Sprintf(".gostring.%d.%x", len(s), h.Sum(nil))
 	}
+	// Small strings get named directly by their contents.
+	return strconv.Quote(s)
+}
+
+// stringdataname returns the name of the string data symbol for s.
+func stringdataname(s string) string {
+	return "go.string." + stringsymname(s)
+}
+
+func stringsym(s string) (hdr, data *obj.LSym) {
+	symname := stringsymname(s)
 
 	const prefix = "go.string."
 	symdataname := prefix + symname
@@ -246,7 +451,7 @@ func stringsym(s string) (hdr, data *obj.LSym) {
 
 var slicebytes_gen int
 
-func slicebytes(nam *Node, s string, len int) {
+func slicebytes(nam *ir.Node, s string, len int) {
 	slicebytes_gen++
 	symname := fmt.Sprintf(".gobytes.%d", slicebytes_gen)
 	sym := Pkglookup(symname, localpkg)
@@ -255,7 +460,7 @@ func slicebytes(nam *Node, s string, len int) {
 	off := dsname(sym, 0, s)
 	ggloblsym(sym, int32(off), obj.NOPTR|obj.LOCAL)
 
-	if nam.Op != ONAME {
+	if nam.Op != ir.ONAME {
 		Fatalf("slicebytes %v", nam)
 	}
 	off = int(nam.Xoffset)
@@ -270,7 +475,7 @@ func Datastring(s string, a *obj.Addr) {
 	a.Name = obj.NAME_EXTERN
 	a.Sym = symdata
 	a.Offset = 0
-	a.Etype = uint8(Simtype[TINT])
+	a.Etype = uint8(Simtype[ir.TINT])
 }
 
 func datagostring(sval string, a *obj.Addr) {
@@ -279,17 +484,17 @@ func datagostring(sval string, a *obj.Addr) {
 	a.Name = obj.NAME_EXTERN
 	a.Sym = symhdr
 	a.Offset = 0
-	a.Etype = uint8(TSTRING)
+	a.Etype = uint8(ir.TSTRING)
 }
 
-func dgostringptr(s *Sym, off int, str string) int {
+func dgostringptr(s *ir.Sym, off int, str string) int {
 	if str == "" {
 		return duintptr(s, off, 0)
 	}
 	return dgostrlitptr(s, off, &str)
 }
 
-func dgostrlitptr(s *Sym, off int, lit *string) int {
+func dgostrlitptr(s *ir.Sym, off int, lit *string) int {
 	if lit == nil {
 		return duintptr(s, off, 0)
 	}
@@ -300,7 +505,7 @@ func dgostrlitptr(s *Sym, off int, lit *string) int {
 	return off
 }
 
-func dsname(s *Sym, off int, t string) int {
+func dsname(s *ir.Sym, off int, t string) int {
 	return dsnameLSym(Linksym(s), off, t)
 }
 
@@ -309,7 +514,7 @@ func dsnameLSym(s *obj.LSym, off int, t string) int {
 	return off + len(t)
 }
 
-func dsymptr(s *Sym, off int, x *Sym, xoff int) int {
+func dsymptr(s *ir.Sym, off int, x *ir.Sym, xoff int) int {
 	return dsymptrLSym(Linksym(s), off, Linksym(x), xoff)
 }
 
@@ -320,8 +525,8 @@ func dsymptrLSym(s *obj.LSym, off int, x *obj.LSym, xoff int) int {
 	return off
 }
 
-func gdata(nam *Node, nr *Node, wid int) {
-	if nam.Op != ONAME {
+func gdata(nam *ir.Node, nr *ir.Node, wid int) {
+	if nam.Op != ir.ONAME {
 		Fatalf("gdata nam op %v", opnames[nam.Op])
 	}
 	if nam.Sym == nil {
@@ -329,25 +534,25 @@ func gdata(nam *Node, nr *Node, wid int) {
 	}
 
 	switch nr.Op {
-	case OLITERAL:
+	case ir.OLITERAL:
 		switch nr.Val().Ctype() {
-		case CTCPLX:
-			gdatacomplex(nam, nr.Val().U.(*Mpcplx))
+		case ir.CTCPLX:
+			gdatacomplex(nam, nr.Val().U.(*ir.Mpcplx))
 
-		case CTSTR:
+		case ir.CTSTR:
 			gdatastring(nam, nr.Val().U.(string))
 
-		case CTINT, CTRUNE, CTBOOL:
+		case ir.CTINT, ir.CTRUNE, ir.CTBOOL:
 			i, _ := nr.IntLiteral()
 			Linksym(nam.Sym).WriteInt(Ctxt, nam.Xoffset, wid, i)
 
-		case CTFLT:
+		case ir.CTFLT:
 			s := Linksym(nam.Sym)
-			f := nr.Val().U.(*Mpflt).Float64()
+			f := nr.Val().U.(*ir.Mpflt).Float64()
 			switch nam.Type.Etype {
-			case TFLOAT32:
+			case ir.TFLOAT32:
 				s.WriteFloat32(Ctxt, nam.Xoffset, float32(f))
-			case TFLOAT64:
+			case ir.TFLOAT64:
 				s.WriteFloat64(Ctxt, nam.Xoffset, f)
 			}
 
@@ -355,15 +560,15 @@ func gdata(nam *Node, nr *Node, wid int) {
 			Fatalf("gdata unhandled OLITERAL %v", nr)
 		}
 
-	case OADDR:
-		if nr.Left.Op != ONAME {
+	case ir.OADDR:
+		if nr.Left.Op != ir.ONAME {
 			Fatalf("gdata ADDR left op %s", opnames[nr.Left.Op])
 		}
 		to := nr.Left
 		Linksym(nam.Sym).WriteAddr(Ctxt, nam.Xoffset, wid, Linksym(to.Sym), to.Xoffset)
 
-	case ONAME:
-		if nr.Class != PFUNC {
+	case ir.ONAME:
+		if nr.Class != ir.PFUNC {
 			Fatalf("gdata NAME not PFUNC %d", nr.Class)
 		}
 		Linksym(nam.Sym).WriteAddr(Ctxt, nam.Xoffset, wid, Linksym(funcsym(nr.Sym)), nr.Xoffset)
@@ -373,23 +578,23 @@ func gdata(nam *Node, nr *Node, wid int) {
 	}
 }
 
-func gdatacomplex(nam *Node, cval *Mpcplx) {
-	t := Types[cplxsubtype(nam.Type.Etype)]
+func gdatacomplex(nam *ir.Node, cval *ir.Mpcplx) {
+	t := ir.Types[cplxsubtype(nam.Type.Etype)]
 	r := cval.Real.Float64()
 	i := cval.Imag.Float64()
 	s := Linksym(nam.Sym)
 
 	switch t.Etype {
-	case TFLOAT32:
+	case ir.TFLOAT32:
 		s.WriteFloat32(Ctxt, nam.Xoffset, float32(r))
 		s.WriteFloat32(Ctxt, nam.Xoffset+4, float32(i))
-	case TFLOAT64:
+	case ir.TFLOAT64:
 		s.WriteFloat64(Ctxt, nam.Xoffset, r)
 		s.WriteFloat64(Ctxt, nam.Xoffset+8, i)
 	}
 }
 
-func gdatastring(nam *Node, sval string) {
+func gdatastring(nam *ir.Node, sval string) {
 	s := Linksym(nam.Sym)
 	_, symdata := stringsym(sval)
 	s.WriteAddr(Ctxt, nam.Xoffset, Widthptr, symdata, 0)
@@ -0,0 +1,201 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gc
+
+import (
+	"bytes"
+	"cmd/compile/internal/ir"
+	"cmd/internal/obj"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// Make sure -linkobj splits the compiler output into export data,
+// used to compile importers, and code, used by the linker, and that
+// the program built from the two halves runs.
+func TestLinkObj(t *testing.T) {
+	d := newTestDir(t, "TestLinkObj")
+	defer d.remove()
+
+	for _, dir := range []string{"export", "link"} {
+		if err := os.Mkdir(d.path(dir), 0777); err != nil {
+			t.Fatalf("could not create directory: %v", err)
+		}
+	}
+
+	p := d.write("p.go", "package p\nvar X = []int{1, 2, 3}\nfunc F() int { return len(X) }\n")
+	export := filepath.Join(d.path("export"), "p.a")
+	link := filepath.Join(d.path("link"), "p.a")
+	d.run("go", "tool", "compile", "-pack", "-o", export, "-linkobj", link, p)
+
+	exportData, err := ioutil.ReadFile(export)
+	if err != nil {
+		t.Fatalf("could not read export file: %v", err)
+	}
+	linkData, err := ioutil.ReadFile(link)
+	if err != nil {
+		t.Fatalf("could not read link object: %v", err)
+	}
+	if !bytes.Contains(exportData, []byte("\n$$B\n")) || bytes.Contains(exportData, []byte("_go_.o")) {
+		t.Errorf("export file does not contain just export data")
+	}
+	if bytes.Contains(linkData, []byte("\n$$B\n")) || !bytes.Contains(linkData, []byte("_go_.o")) {
+		t.Errorf("link object does not contain just code")
+	}
+
+	// The export data is written before the back end runs; it must
+	// still be the same as in a single object file.
+	whole := d.path("p.a")
+	d.run("go", "tool", "compile", "-pack", "-o", whole, p)
+	wholeData, err := ioutil.ReadFile(whole)
+	if err != nil {
+		t.Fatalf("could not read object file: %v", err)
+	}
+	if !bytes.HasPrefix(wholeData, exportData) {
+		t.Errorf("export file differs from the export data of a single object file")
+	}
+
+	main := d.write("main.go", "package main\nimport \"p\"\nfunc main() {\n\tif p.F() != 3 {\n\t\tpanic(\"bad\")\n\t}\n}\n")
+	mainobj := d.path("main.o")
+	prog := d.path("main.exe")
+	d.run("go", "tool", "compile", "-I", d.path("export"), "-o", mainobj, main)
+	d.run("go", "tool", "link", "-L", d.path("link"), "-o", prog, mainobj)
+	d.run(prog)
+}
+
+// Make sure programs built from object files with a symbol index,
+// including duplicate definitions the linker skips, and from object
+// files without one, run, and that tools can read indexed objects.
+func TestObjIndex(t *testing.T) {
+	d := newTestDir(t, "TestObjIndex")
+	defer d.remove()
+
+	// p, q and main all define the string data for "hello"
+	// and the equality algorithm for [2]string.
+	for _, index := range []string{"-objindex", "-objindex=false"} {
+		d.pack("p", "package p\nfunc F() [2]string { return [2]string{\"hello\", \"p\"} }\n", "-objindex")
+		d.pack("q", "package q\nfunc G() [2]string { return [2]string{\"hello\", \"q\"} }\n", index)
+		main := d.pack("main", `package main
+
+import (
+	"p"
+	"q"
+)
+
+func main() {
+	x, y := p.F(), q.G()
+	if x == y || x[0] != "hello" || y[0] != "hello" {
+		panic("bad")
+	}
+	println("hello")
+}
+`, "-objindex")
+		prog := d.link(main)
+		if out := d.run(prog); out != "hello\n" {
+			t.Errorf("%s: got output %q, want %q", index, out, "hello\n")
+		}
+		if out := d.run("go", "tool", "nm", main); !strings.Contains(out, " T %22%22.main\n") {
+			t.Errorf("%s: go tool nm does not list main.main:\n%s", index, out)
+		}
+	}
+}
+
+// Make sure identical read-only data is written once per package,
+// and linked once per binary.
+func TestRodataDedup(t *testing.T) {
+	d := newTestDir(t, "TestRodataDedup")
+	defer d.remove()
+
+	// rodata returns the read-only data symbols in file, with · written
+	// as ., as the linker does in the symbol table.
+	rodata := func(file string) []string {
+		var syms []string
+		for _, line := range strings.Split(d.run("go", "tool", "nm", file), "\n") {
+			f := strings.Fields(line)
+			if len(f) == 3 && f[1] == "R" && strings.HasPrefix(f[2], "rodata") {
+				syms = append(syms, strings.Replace(f[2], "·", ".", 1))
+			}
+		}
+		return syms
+	}
+
+	p := d.pack("p", `package p
+
+func F() []int    { return []int{1, 2, 3, 4} }
+func G() []int    { return []int{1, 2, 3, 4} }
+func H() []string { return []string{"a", "b"} }
+`)
+	psyms := rodata(p)
+	if len(psyms) != 2 {
+		t.Errorf("p has read-only data symbols %v, want 2", psyms)
+	}
+	q := d.pack("q", "package q\nfunc F() []int { return []int{1, 2, 3, 4} }\n")
+	qsyms := rodata(q)
+	if len(qsyms) != 1 || qsyms[0] != psyms[0] && qsyms[0] != psyms[1] {
+		t.Errorf("q has read-only data symbols %v, want one of %v", qsyms, psyms)
+	}
+	prog := d.link(d.pack("main", `package main
+
+import (
+	"p"
+	"q"
+)
+
+func main() {
+	println(p.F()[0] + p.G()[1] + q.F()[2], p.H()[1])
+}
+`))
+	if out := d.run(prog); out != "6 b\n" {
+		t.Errorf("got output %q, want %q", out, "6 b\n")
+	}
+	// The binary may contain read-only data from other packages too.
+	n := map[string]int{}
+	for _, sym := range rodata(prog) {
+		n[sym]++
+	}
+	for _, sym := range psyms {
+		if n[sym] != 1 {
+			t.Errorf("binary has %d copies of %s, want 1", n[sym], sym)
+		}
+	}
+}
+
+// Make sure dedupRodata keeps the name of a temporary that refers to
+// a symbol local to the package, even one whose name does not start
+// with "".
+func TestRodataDedupLocal(t *testing.T) {
+	defer func(ctxt *obj.Link) { Ctxt = ctxt }(Ctxt)
+	Ctxt = obj.Linknew(&obj.LinkArch{Thechar: '6'})
+
+	// tmp returns a read-only temporary holding a pointer to sym.
+	tmp := func(name, sym string) *obj.LSym {
+		s := obj.Linklookup(Ctxt, name, 0)
+		s.Size = 8
+		s.P = make([]byte, 8)
+		s.R = []obj.Reloc{{Siz: 8, Type: obj.R_ADDR, Sym: obj.Linklookup(Ctxt, sym, 0)}}
+		Ctxt.Data = append(Ctxt.Data, s)
+		rodatasyms = append(rodatasyms, &ir.Sym{Lsym: s})
+		return s
+	}
+	local := []*obj.LSym{
+		tmp(`"".statictmp_0`, `"".f`),
+		tmp(`"".statictmp_1`, `type."".T`),
+		tmp(`"".statictmp_2`, `go.itab."".T,p.I`),
+	}
+	other := tmp(`"".statictmp_3`, `type.p.T`)
+	dedupRodata()
+
+	for _, s := range local {
+		if s.Dupok != 0 || !strings.HasPrefix(s.Name, `"".statictmp_`) {
+			t.Errorf("temporary referring to %s renamed to %s", s.R[0].Sym.Name, s.Name)
+		}
+	}
+	if other.Dupok == 0 || !strings.HasPrefix(other.Name, "rodata") {
+		t.Errorf("temporary referring to %s not renamed", other.R[0].Sym.Name)
+	}
+}
@@ -4,165 +4,167 @@
 
 package gc
 
+import "cmd/compile/internal/ir"
+
 // auto generated by go tool dist
 var opnames = []string{
-	OXXX:             "XXX",
-	ONAME:            "NAME",
-	ONONAME:          "NONAME",
-	OTYPE:            "TYPE",
-	OPACK:            "PACK",
-	OLITERAL:         "LITERAL",
-	OADD:             "ADD",
-	OSUB:             "SUB",
-	OOR:              "OR",
-	OXOR:             "XOR",
-	OADDSTR:          "ADDSTR",
-	OADDR:            "ADDR",
-	OANDAND:          "ANDAND",
-	OAPPEND:          "APPEND",
-	OARRAYBYTESTR:    "ARRAYBYTESTR",
-	OARRAYBYTESTRTMP: "ARRAYBYTESTRTMP",
-	OARRAYRUNESTR:    "ARRAYRUNESTR",
-	OSTRARRAYBYTE:    "STRARRAYBYTE",
-	OSTRARRAYBYTETMP: "STRARRAYBYTETMP",
-	OSTRARRAYRUNE:    "STRARRAYRUNE",
-	OAS:              "AS",
-	OAS2:             "AS2",
-	OAS2FUNC:         "AS2FUNC",
-	OAS2RECV:         "AS2RECV",
-	OAS2MAPR:         "AS2MAPR",
-	OAS2DOTTYPE:      "AS2DOTTYPE",
-	OASOP:            "ASOP",
-	OASWB:            "ASWB",
-	OCALL:            "CALL",
-	OCALLFUNC:        "CALLFUNC",
-	OCALLMETH:        "CALLMETH",
-	OCALLINTER:       "CALLINTER",
-	OCALLPART:        "CALLPART",
-	OCAP:             "CAP",
-	OCLOSE:           "CLOSE",
-	OCLOSURE:         "CLOSURE",
-	OCMPIFACE:        "CMPIFACE",
-	OCMPSTR:          "CMPSTR",
-	OCOMPLIT:         "COMPLIT",
-	OMAPLIT:          "MAPLIT",
-	OSTRUCTLIT:       "STRUCTLIT",
-	OARRAYLIT:        "ARRAYLIT",
-	OPTRLIT:          "PTRLIT",
-	OCONV:            "CONV",
-	OCONVIFACE:       "CONVIFACE",
-	OCONVNOP:         "CONVNOP",
-	OCOPY:            "COPY",
-	ODCL:             "DCL",
-	ODCLFUNC:         "DCLFUNC",
-	ODCLFIELD:        "DCLFIELD",
-	ODCLCONST:        "DCLCONST",
-	ODCLTYPE:         "DCLTYPE",
-	ODELETE:          "DELETE",
-	ODOT:             "DOT",
-	ODOTPTR:          "DOTPTR",
-	ODOTMETH:         "DOTMETH",
-	ODOTINTER:        "DOTINTER",
-	OXDOT:            "XDOT",
-	ODOTTYPE:         "DOTTYPE",
-	ODOTTYPE2:        "DOTTYPE2",
-	OEQ:              "EQ",
-	ONE:              "NE",
-	OLT:              "LT",
-	OLE:              "LE",
-	OGE:              "GE",
-	OGT:              "GT",
-	OIND:             "IND",
-	OINDEX:           "INDEX",
-	OINDEXMAP:        "INDEXMAP",
-	OKEY:             "KEY",
-	OPARAM:           "PARAM",
-	OLEN:             "LEN",
-	OMAKE:            "MAKE",
-	OMAKECHAN:        "MAKECHAN",
-	OMAKEMAP:         "MAKEMAP",
-	OMAKESLICE:       "MAKESLICE",
-	OMUL:             "MUL",
-	ODIV:             "DIV",
-	OMOD:             "MOD",
-	OLSH:             "LSH",
-	ORSH:             "RSH",
-	OAND:             "AND",
-	OANDNOT:          "ANDNOT",
-	ONEW:             "NEW",
-	ONOT:             "NOT",
-	OCOM:             "COM",
-	OPLUS:            "PLUS",
-	OMINUS:           "MINUS",
-	OOROR:            "OROR",
-	OPANIC:           "PANIC",
-	OPRINT:           "PRINT",
-	OPRINTN:          "PRINTN",
-	OPAREN:           "PAREN",
-	OSEND:            "SEND",
-	OSLICE:           "SLICE",
-	OSLICEARR:        "SLICEARR",
-	OSLICESTR:        "SLICESTR",
-	OSLICE3:          "SLICE3",
-	OSLICE3ARR:       "SLICE3ARR",
-	ORECOVER:         "RECOVER",
-	ORECV:            "RECV",
-	ORUNESTR:         "RUNESTR",
-	OSELRECV:         "SELRECV",
-	OSELRECV2:        "SELRECV2",
-	OIOTA:            "IOTA",
-	OREAL:            "REAL",
-	OIMAG:            "IMAG",
-	OCOMPLEX:         "COMPLEX",
-	OBLOCK:           "BLOCK",
-	OBREAK:           "BREAK",
-	OCASE:            "CASE",
-	OXCASE:           "XCASE",
-	OCONTINUE:        "CONTINUE",
-	ODEFER:           "DEFER",
-	OEMPTY:           "EMPTY",
-	OFALL:            "FALL",
-	OXFALL:           "XFALL",
-	OFOR:             "FOR",
-	OGOTO:            "GOTO",
-	OIF:              "IF",
-	OLABEL:           "LABEL",
-	OPROC:            "PROC",
-	ORANGE:           "RANGE",
-	ORETURN:          "RETURN",
-	OSELECT:          "SELECT",
-	OSWITCH:          "SWITCH",
-	OTYPESW:          "TYPESW",
-	OTCHAN:           "TCHAN",
-	OTMAP:            "TMAP",
-	OTSTRUCT:         "TSTRUCT",
-	OTINTER:          "TINTER",
-	OTFUNC:           "TFUNC",
-	OTARRAY:          "TARRAY",
-	ODDD:             "DDD",
-	ODDDARG:          "DDDARG",
-	OINLCALL:         "INLCALL",
-	OEFACE:           "EFACE",
-	OITAB:            "ITAB",
-	OSPTR:            "SPTR",
-	OCLOSUREVAR:      "CLOSUREVAR",
-	OCFUNC:           "CFUNC",
-	OCHECKNIL:        "CHECKNIL",
-	OVARKILL:         "VARKILL",
-	OVARLIVE:         "VARLIVE",
-	OREGISTER:        "REGISTER",
-	OINDREG:          "INDREG",
-	OCMP:             "CMP",
-	ODEC:             "DEC",
-	OINC:             "INC",
-	OEXTEND:          "EXTEND",
-	OHMUL:            "HMUL",
-	OLROT:            "LROT",
-	ORROTC:           "RROTC",
-	ORETJMP:          "RETJMP",
-	OPS:              "OPS",
-	OPC:              "OPC",
-	OSQRT:            "OSQRT",
-	OGETG:            "OGETG",
-	OEND:             "END",
+	ir.OXXX:             "XXX",
+	ir.ONAME:            "NAME",
+	ir.ONONAME:          "NONAME",
+	ir.OTYPE:            "TYPE",
+	ir.OPACK:            "PACK",
+	ir.OLITERAL:         "LITERAL",
+	ir.OADD:             "ADD",
+	ir.OSUB:             "SUB",
+	ir.OOR:              "OR",
+	ir.OXOR:             "XOR",
+	ir.OADDSTR:          "ADDSTR",
+	ir.OADDR:            "ADDR",
+	ir.OANDAND:          "ANDAND",
+	ir.OAPPEND:          "APPEND",
+	ir.OARRAYBYTESTR:    "ARRAYBYTESTR",
+	ir.OARRAYBYTESTRTMP: "ARRAYBYTESTRTMP",
+	ir.OARRAYRUNESTR:    "ARRAYRUNESTR",
+	ir.OSTRARRAYBYTE:    "STRARRAYBYTE",
+	ir.OSTRARRAYBYTETMP: "STRARRAYBYTETMP",
+	ir.OSTRARRAYRUNE:    "STRARRAYRUNE",
+	ir.OAS:              "AS",
+	ir.OAS2:             "AS2",
+	ir.OAS2FUNC:         "AS2FUNC",
+	ir.OAS2RECV:         "AS2RECV",
+	ir.OAS2MAPR:         "AS2MAPR",
+	ir.OAS2DOTTYPE:      "AS2DOTTYPE",
+	ir.OASOP:            "ASOP",
+	ir.OASWB:            "ASWB",
+	ir.OCALL:            "CALL",
+	ir.OCALLFUNC:        "CALLFUNC",
+	ir.OCALLMETH:        "CALLMETH",
+	ir.OCALLINTER:       "CALLINTER",
+	ir.OCALLPART:        "CALLPART",
+	ir.OCAP:             "CAP",
+	ir.OCLOSE:           "CLOSE",
+	ir.OCLOSURE:         "CLOSURE",
+	ir.OCMPIFACE:        "CMPIFACE",
+	ir.OCMPSTR:          "CMPSTR",
+	ir.OCOMPLIT:         "COMPLIT",
+	ir.OMAPLIT:          "MAPLIT",
+	ir.OSTRUCTLIT:       "STRUCTLIT",
+	ir.OARRAYLIT:        "ARRAYLIT",
+	ir.OPTRLIT:          "PTRLIT",
+	ir.OCONV:            "CONV",
+	ir.OCONVIFACE:       "CONVIFACE",
+	ir.OCONVNOP:         "CONVNOP",
+	ir.OCOPY:            "COPY",
+	ir.ODCL:             "DCL",
+	ir.ODCLFUNC:         "DCLFUNC",
+	ir.ODCLFIELD:        "DCLFIELD",
+	ir.ODCLCONST:        "DCLCONST",
+	ir.ODCLTYPE:         "DCLTYPE",
+	ir.ODELETE:          "DELETE",
+	ir.ODOT:             "DOT",
+	ir.ODOTPTR:          "DOTPTR",
+	ir.ODOTMETH:         "DOTMETH",
+	ir.ODOTINTER:        "DOTINTER",
+	ir.OXDOT:            "XDOT",
+	ir.ODOTTYPE:         "DOTTYPE",
+	ir.ODOTTYPE2:        "DOTTYPE2",
+	ir.OEQ:              "EQ",
+	ir.ONE:              "NE",
+	ir.OLT:              "LT",
+	ir.OLE:              "LE",
+	ir.OGE:              "GE",
+	ir.OGT:              "GT",
+	ir.OIND:             "IND",
+	ir.OINDEX:           "INDEX",
+	ir.OINDEXMAP:        "INDEXMAP",
+	ir.OKEY:             "KEY",
+	ir.OPARAM:           "PARAM",
+	ir.OLEN:             "LEN",
+	ir.OMAKE:            "MAKE",
+	ir.OMAKECHAN:        "MAKECHAN",
+	ir.OMAKEMAP:         "MAKEMAP",
+	ir.OMAKESLICE:       "MAKESLICE",
+	ir.OMUL:             "MUL",
+	ir.ODIV:             "DIV",
+	ir.OMOD:             "MOD",
+	ir.OLSH:             "LSH",
+	ir.ORSH:             "RSH",
+	ir.OAND:             "AND",
+	ir.OANDNOT:          "ANDNOT",
+	ir.ONEW:             "NEW",
+	ir.ONOT:             "NOT",
+	ir.OCOM:             "COM",
+	ir.OPLUS:            "PLUS",
+	ir.OMINUS:           "MINUS",
+	ir.OOROR:            "OROR",
+	ir.OPANIC:           "PANIC",
+	ir.OPRINT:           "PRINT",
+	ir.OPRINTN:          "PRINTN",
+	ir.OPAREN:           "PAREN",
+	ir.OSEND:            "SEND",
+	ir.OSLICE:           "SLICE",
+	ir.OSLICEARR:        "SLICEARR",
+	ir.OSLICESTR:        "SLICESTR",
+	ir.OSLICE3:          "SLICE3",
+	ir.OSLICE3ARR:       "SLICE3ARR",
+	ir.ORECOVER:         "RECOVER",
+	ir.ORECV:            "RECV",
+	ir.ORUNESTR:         "RUNESTR",
+	ir.OSELRECV:         "SELRECV",
+	ir.OSELRECV2:        "SELRECV2",
+	ir.OIOTA:            "IOTA",
+	ir.OREAL:            "REAL",
+	ir.OIMAG:            "IMAG",
+	ir.OCOMPLEX:         "COMPLEX",
+	ir.OBLOCK:           "BLOCK",
+	ir.OBREAK:           "BREAK",
+	ir.OCASE:            "CASE",
+	ir.OXCASE:           "XCASE",
+	ir.OCONTINUE:        "CONTINUE",
+	ir.ODEFER:           "DEFER",
+	ir.OEMPTY:           "EMPTY",
+	ir.OFALL:            "FALL",
+	ir.OXFALL:           "XFALL",
+	ir.OFOR:             "FOR",
+	ir.OGOTO:            "GOTO",
+	ir.OIF:              "IF",
+	ir.OLABEL:           "LABEL",
+	ir.OPROC:            "PROC",
+	ir.ORANGE:           "RANGE",
+	ir.ORETURN:          "RETURN",
+	ir.OSELECT:          "SELECT",
+	ir.OSWITCH:          "SWITCH",
+	ir.OTYPESW:          "TYPESW",
+	ir.OTCHAN:           "TCHAN",
+	ir.OTMAP:            "TMAP",
+	ir.OTSTRUCT:         "TSTRUCT",
+	ir.OTINTER:          "TINTER",
+	ir.OTFUNC:           "TFUNC",
+	ir.OTARRAY:          "TARRAY",
+	ir.ODDD:             "DDD",
+	ir.ODDDARG:          "DDDARG",
+	ir.OINLCALL:         "INLCALL",
+	ir.OEFACE:           "EFACE",
+	ir.OITAB:            "ITAB",
+	ir.OSPTR:            "SPTR",
+	ir.OCLOSUREVAR:      "CLOSUREVAR",
+	ir.OCFUNC:           "CFUNC",
+	ir.OCHECKNIL:        "CHECKNIL",
+	ir.OVARKILL:         "VARKILL",
+	ir.OVARLIVE:         "VARLIVE",
+	ir.OREGISTER:        "REGISTER",
+	ir.OINDREG:          "INDREG",
+	ir.OCMP:             "CMP",
+	ir.ODEC:             "DEC",
+	ir.OINC:             "INC",
+	ir.OEXTEND:          "EXTEND",
+	ir.OHMUL:            "HMUL",
+	ir.OLROT:            "LROT",
+	ir.ORROTC:           "RROTC",
+	ir.ORETJMP:          "RETJMP",
+	ir.OPS:              "OPS",
+	ir.OPC:              "OPC",
+	ir.OSQRT:            "OSQRT",
+	ir.OGETG:            "OGETG",
+	ir.OEND:             "END",
 }
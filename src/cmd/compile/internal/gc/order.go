@@ -5,6 +5,7 @@
 package gc
 
 import (
+	"cmd/compile/internal/ir"
 	"fmt"
 	"strings"
 )
@@ -41,28 +42,48 @@ import (
 
 // Order holds state during the ordering process.
 type Order struct {
-	out  []*Node // list of generated statements
-	temp []*Node // stack of temporary variables
+	out  []*ir.Node // list of generated statements
+	temp []*ir.Node // stack of temporary variables
 }
 
+// orderfree holds the temporaries of the function being ordered that
+// are no longer live, by type, for ordertemp to reuse. orderkeep
+// holds the temporaries that must not be reused; see orderprealloc.
+var (
+	orderfree map[*ir.Type][]*ir.Node
+	orderkeep map[*ir.Node]bool
+)
+
 // Order rewrites fn->nbody to apply the ordering constraints
 // described in the comment at the top of the file.
-func order(fn *Node) {
+func order(fn *ir.Node) {
 	if Debug['W'] > 1 {
 		s := fmt.Sprintf("\nbefore order %v", fn.Func.Nname.Sym)
 		dumplist(s, fn.Nbody)
 	}
 
+	orderfree = make(map[*ir.Type][]*ir.Node)
+	orderkeep = make(map[*ir.Node]bool)
 	orderblockNodes(&fn.Nbody)
+	orderfree = nil
+	orderkeep = nil
 }
 
-// Ordertemp allocates a new temporary with the given type,
-// pushes it onto the temp stack, and returns it.
-// If clear is true, ordertemp emits code to zero the temporary.
-func ordertemp(t *Type, order *Order, clear bool) *Node {
-	var_ := temp(t)
+// Ordertemp allocates a new temporary with the given type, or reuses
+// one that is no longer live, pushes it onto the temp stack, and
+// returns it. If clear is true, ordertemp emits code to zero the
+// temporary.
+func ordertemp(t *ir.Type, order *Order, clear bool) *ir.Node {
+	var var_ *ir.Node
+	if l := orderfree[t]; len(l) > 0 {
+		var_ = l[len(l)-1]
+		orderfree[t] = l[:len(l)-1]
+		nodeReused(var_)
+	} else {
+		var_ = temp(t)
+	}
 	if clear {
-		a := Nod(OAS, var_, nil)
+		a := Nod(ir.OAS, var_, nil)
 		a = typecheck(a, Etop)
 		order.out = append(order.out, a)
 	}
@@ -71,6 +92,24 @@ func ordertemp(t *Type, order *Order, clear bool) *Node {
 	return var_
 }
 
+// Orderprealloc allocates a temporary with the given type for walk
+// to use in place of a heap allocation for n, and records it in
+// prealloc. Walk may change the type of the temporary, so it is a new
+// one, and ordertemp does not reuse it.
+func orderprealloc(n *ir.Node, t *ir.Type, order *Order, clear bool) {
+	var_ := temp(t)
+	if clear {
+		a := Nod(ir.OAS, var_, nil)
+		a = typecheck(a, Etop)
+		order.out = append(order.out, a)
+	}
+	if orderkeep != nil {
+		orderkeep[var_] = true
+	}
+	order.temp = append(order.temp, var_)
+	prealloc[n] = var_
+}
+
 // Ordercopyexpr behaves like ordertemp but also emits
 // code to initialize the temporary to the value n.
 //
@@ -83,9 +122,9 @@ func ordertemp(t *Type, order *Order, clear bool) *Node {
 // (The other candidate would be map access, but map access
 // returns a pointer to the result data instead of taking a pointer
 // to be filled in.)
-func ordercopyexpr(n *Node, t *Type, order *Order, clear int) *Node {
+func ordercopyexpr(n *ir.Node, t *ir.Type, order *Order, clear int) *ir.Node {
 	var_ := ordertemp(t, order, clear != 0)
-	a := Nod(OAS, var_, n)
+	a := Nod(ir.OAS, var_, n)
 	a = typecheck(a, Etop)
 	order.out = append(order.out, a)
 	return var_
@@ -95,14 +134,14 @@ func ordercopyexpr(n *Node, t *Type, order *Order, clear int) *Node {
 // The definition of cheap is that n is a variable or constant.
 // If not, ordercheapexpr allocates a new tmp, emits tmp = n,
 // and then returns tmp.
-func ordercheapexpr(n *Node, order *Order) *Node {
+func ordercheapexpr(n *ir.Node, order *Order) *ir.Node {
 	if n == nil {
 		return nil
 	}
 	switch n.Op {
-	case ONAME, OLITERAL:
+	case ir.ONAME, ir.OLITERAL:
 		return n
-	case OLEN, OCAP:
+	case ir.OLEN, ir.OCAP:
 		l := ordercheapexpr(n.Left, order)
 		if l == n.Left {
 			return n
@@ -123,12 +162,12 @@ func ordercheapexpr(n *Node, order *Order) *Node {
 // as assigning to the original n.
 //
 // The intended use is to apply to x when rewriting x += y into x = x + y.
-func ordersafeexpr(n *Node, order *Order) *Node {
+func ordersafeexpr(n *ir.Node, order *Order) *ir.Node {
 	switch n.Op {
-	case ONAME, OLITERAL:
+	case ir.ONAME, ir.OLITERAL:
 		return n
 
-	case ODOT, OLEN, OCAP:
+	case ir.ODOT, ir.OLEN, ir.OCAP:
 		l := ordersafeexpr(n.Left, order)
 		if l == n.Left {
 			return n
@@ -138,7 +177,7 @@ func ordersafeexpr(n *Node, order *Order) *Node {
 		a.Left = l
 		return typecheck(&a, Erv)
 
-	case ODOTPTR, OIND:
+	case ir.ODOTPTR, ir.OIND:
 		l := ordercheapexpr(n.Left, order)
 		if l == n.Left {
 			return n
@@ -148,8 +187,8 @@ func ordersafeexpr(n *Node, order *Order) *Node {
 		a.Left = l
 		return typecheck(&a, Erv)
 
-	case OINDEX, OINDEXMAP:
-		var l *Node
+	case ir.OINDEX, ir.OINDEXMAP:
+		var l *ir.Node
 		if Isfixedarray(n.Left.Type) {
 			l = ordersafeexpr(n.Left, order)
 		} else {
@@ -171,8 +210,8 @@ func ordersafeexpr(n *Node, order *Order) *Node {
 }
 
 // Istemp reports whether n is a temporary variable.
-func istemp(n *Node) bool {
-	if n.Op != ONAME {
+func istemp(n *ir.Node) bool {
+	if n.Op != ir.ONAME {
 		return false
 	}
 	return strings.HasPrefix(n.Sym.Name, "autotmp_")
@@ -184,14 +223,14 @@ func istemp(n *Node) bool {
 // of ordinary stack variables, those are not 'isaddrokay'. Temporaries are okay,
 // because we emit explicit VARKILL instructions marking the end of those
 // temporaries' lifetimes.
-func isaddrokay(n *Node) bool {
-	return islvalue(n) && (n.Op != ONAME || n.Class == PEXTERN || istemp(n))
+func isaddrokay(n *ir.Node) bool {
+	return islvalue(n) && (n.Op != ir.ONAME || n.Class == ir.PEXTERN || istemp(n))
 }
 
 // Orderaddrtemp ensures that *np is okay to pass by address to runtime routines.
 // If the original argument *np is not okay, orderaddrtemp creates a tmp, emits
 // tmp = *np, and then sets *np to the tmp variable.
-func orderaddrtemp(n *Node, order *Order) *Node {
+func orderaddrtemp(n *ir.Node, order *Order) *ir.Node {
 	if isaddrokay(n) {
 		return n
 	}
@@ -206,27 +245,35 @@ func marktemp(order *Order) ordermarker {
 }
 
 // Poptemp pops temporaries off the stack until reaching the mark,
-// which must have been returned by marktemp.
+// which must have been returned by marktemp. The temporaries must be
+// dead: ordertemp may reuse them for the rest of the function.
 func poptemp(mark ordermarker, order *Order) {
+	if orderfree != nil { // not when walk orders a statement
+		for _, n := range order.temp[mark:] {
+			if !orderkeep[n] {
+				orderfree[n.Type] = append(orderfree[n.Type], n)
+			}
+		}
+	}
 	order.temp = order.temp[:mark]
 }
 
 // Cleantempnopop emits to *out VARKILL instructions for each temporary
 // above the mark on the temporary stack, but it does not pop them
 // from the stack.
-func cleantempnopop(mark ordermarker, order *Order, out *[]*Node) {
-	var kill *Node
+func cleantempnopop(mark ordermarker, order *Order, out *[]*ir.Node) {
+	var kill *ir.Node
 
 	for i := len(order.temp) - 1; i >= int(mark); i-- {
 		n := order.temp[i]
 		if n.Name.Keepalive {
 			n.Name.Keepalive = false
 			n.Addrtaken = true // ensure SSA keeps the n variable
-			kill = Nod(OVARLIVE, n, nil)
+			kill = Nod(ir.OVARLIVE, n, nil)
 			kill = typecheck(kill, Etop)
 			*out = append(*out, kill)
 		}
-		kill = Nod(OVARKILL, n, nil)
+		kill = Nod(ir.OVARKILL, n, nil)
 		kill = typecheck(kill, Etop)
 		*out = append(*out, kill)
 	}
@@ -240,7 +287,7 @@ func cleantemp(top ordermarker, order *Order) {
 }
 
 // Orderstmtlist orders each of the statements in the list.
-func orderstmtlist(l Nodes, order *Order) {
+func orderstmtlist(l ir.Nodes, order *Order) {
 	for _, n := range l.Slice() {
 		orderstmt(n, order)
 	}
@@ -248,7 +295,7 @@ func orderstmtlist(l Nodes, order *Order) {
 
 // Orderblock orders the block of statements l onto a new list,
 // and returns the ordered list.
-func orderblock(l Nodes) []*Node {
+func orderblock(l ir.Nodes) []*ir.Node {
 	var order Order
 	mark := marktemp(&order)
 	orderstmtlist(l, &order)
@@ -258,7 +305,7 @@ func orderblock(l Nodes) []*Node {
 
 // OrderblockNodes orders the block of statements in n into a new slice,
 // and then replaces the old slice in n with the new slice.
-func orderblockNodes(n *Nodes) {
+func orderblockNodes(n *ir.Nodes) {
 	var order Order
 	mark := marktemp(&order)
 	orderstmtlist(*n, &order)
@@ -270,7 +317,7 @@ func orderblockNodes(n *Nodes) {
 // leaves them as the init list of the final *np.
 // The result of orderexprinplace MUST be assigned back to n, e.g.
 // 	n.Left = orderexprinplace(n.Left, outer)
-func orderexprinplace(n *Node, outer *Order) *Node {
+func orderexprinplace(n *ir.Node, outer *Order) *ir.Node {
 	var order Order
 	n = orderexpr(n, &order, nil)
 	n = addinit(n, order.out)
@@ -285,7 +332,7 @@ func orderexprinplace(n *Node, outer *Order) *Node {
 // and replaces it with the resulting statement list.
 // The result of orderstmtinplace MUST be assigned back to n, e.g.
 // 	n.Left = orderstmtinplace(n.Left)
-func orderstmtinplace(n *Node) *Node {
+func orderstmtinplace(n *ir.Node) *ir.Node {
 	var order Order
 	mark := marktemp(&order)
 	orderstmt(n, &order)
@@ -294,14 +341,14 @@ func orderstmtinplace(n *Node) *Node {
 }
 
 // Orderinit moves n's init list to order->out.
-func orderinit(n *Node, order *Order) {
+func orderinit(n *ir.Node, order *Order) {
 	orderstmtlist(n.Ninit, order)
 	n.Ninit.Set(nil)
 }
 
 // Ismulticall reports whether the list l is f() for a multi-value function.
 // Such an f() could appear as the lone argument to a multi-arg function.
-func ismulticall(l Nodes) bool {
+func ismulticall(l ir.Nodes) bool {
 	// one arg only
 	if l.Len() != 1 {
 		return false
@@ -313,7 +360,7 @@ func ismulticall(l Nodes) bool {
 	default:
 		return false
 
-	case OCALLFUNC, OCALLMETH, OCALLINTER:
+	case ir.OCALLFUNC, ir.OCALLMETH, ir.OCALLINTER:
 		break
 	}
 
@@ -323,20 +370,20 @@ func ismulticall(l Nodes) bool {
 
 // Copyret emits t1, t2, ... = n, where n is a function call,
 // and then returns the list t1, t2, ....
-func copyret(n *Node, order *Order) []*Node {
-	if n.Type.Etype != TSTRUCT || !n.Type.Funarg {
+func copyret(n *ir.Node, order *Order) []*ir.Node {
+	if n.Type.Etype != ir.TSTRUCT || !n.Type.Funarg {
 		Fatalf("copyret %v %d", n.Type, n.Left.Type.Results().NumFields())
 	}
 
-	var l1 []*Node
-	var l2 []*Node
+	var l1 []*ir.Node
+	var l2 []*ir.Node
 	for _, t := range n.Type.Fields().Slice() {
 		tmp := temp(t.Type)
 		l1 = append(l1, tmp)
 		l2 = append(l2, tmp)
 	}
 
-	as := Nod(OAS2, nil, nil)
+	as := Nod(ir.OAS2, nil, nil)
 	as.List.Set(l1)
 	as.Rlist.Set1(n)
 	as = typecheck(as, Etop)
@@ -346,7 +393,7 @@ func copyret(n *Node, order *Order) []*Node {
 }
 
 // Ordercallargs orders the list of call arguments *l.
-func ordercallargs(l *Nodes, order *Order) {
+func ordercallargs(l *ir.Nodes, order *Order) {
 	if ismulticall(*l) {
 		// return f() where f() is multiple values.
 		l.Set(copyret(l.First(), order))
@@ -357,13 +404,14 @@ func ordercallargs(l *Nodes, order *Order) {
 
 // Ordercall orders the call expression n.
 // n->op is OCALLMETH/OCALLFUNC/OCALLINTER or a builtin like OCOPY.
-func ordercall(n *Node, order *Order) {
+func ordercall(n *ir.Node, order *Order) {
 	n.Left = orderexpr(n.Left, order, nil)
 	n.Right = orderexpr(n.Right, order, nil) // ODDDARG temp
+	cgocheckargs(n)
 	ordercallargs(&n.List, order)
 
-	if n.Op == OCALLFUNC {
-		t, it := IterFields(n.Left.Type.Params())
+	if n.Op == ir.OCALLFUNC {
+		t, it := ir.IterFields(n.Left.Type.Params())
 		for i := range n.List.Slice() {
 			// Check for "unsafe-uintptr" tag provided by escape analysis.
 			// If present and the argument is really a pointer being converted
@@ -375,7 +423,7 @@ func ordercall(n *Node, order *Order) {
 			}
 			if t.Note != nil && *t.Note == unsafeUintptrTag {
 				xp := n.List.Addr(i)
-				for (*xp).Op == OCONVNOP && !Isptr[(*xp).Type.Etype] {
+				for (*xp).Op == ir.OCONVNOP && !Isptr[(*xp).Type.Etype] {
 					xp = &(*xp).Left
 				}
 				x := *xp
@@ -413,29 +461,29 @@ func ordercall(n *Node, order *Order) {
 //
 // Ordermapassign also inserts these temporaries if needed for
 // calling writebarrierfat with a pointer to n->right.
-func ordermapassign(n *Node, order *Order) {
+func ordermapassign(n *ir.Node, order *Order) {
 	switch n.Op {
 	default:
 		Fatalf("ordermapassign %v", Oconv(n.Op, 0))
 
-	case OAS:
+	case ir.OAS:
 		order.out = append(order.out, n)
 
 		// We call writebarrierfat only for values > 4 pointers long. See walk.go.
-		if (n.Left.Op == OINDEXMAP || (needwritebarrier(n.Left, n.Right) && n.Left.Type.Width > int64(4*Widthptr))) && !isaddrokay(n.Right) {
+		if (n.Left.Op == ir.OINDEXMAP || (needwritebarrier(n.Left, n.Right) && n.Left.Type.Width > int64(4*Widthptr))) && !isaddrokay(n.Right) {
 			m := n.Left
 			n.Left = ordertemp(m.Type, order, false)
-			a := Nod(OAS, m, n.Left)
+			a := Nod(ir.OAS, m, n.Left)
 			a = typecheck(a, Etop)
 			order.out = append(order.out, a)
 		}
 
-	case OAS2, OAS2DOTTYPE, OAS2MAPR, OAS2FUNC:
-		var post []*Node
-		var m *Node
-		var a *Node
+	case ir.OAS2, ir.OAS2DOTTYPE, ir.OAS2MAPR, ir.OAS2FUNC:
+		var post []*ir.Node
+		var m *ir.Node
+		var a *ir.Node
 		for i1, n1 := range n.List.Slice() {
-			if n1.Op == OINDEXMAP {
+			if n1.Op == ir.OINDEXMAP {
 				m = n1
 				if !istemp(m.Left) {
 					m.Left = ordercopyexpr(m.Left, m.Left.Type, order, 0)
@@ -444,14 +492,14 @@ func ordermapassign(n *Node, order *Order) {
 					m.Right = ordercopyexpr(m.Right, m.Right.Type, order, 0)
 				}
 				n.List.SetIndex(i1, ordertemp(m.Type, order, false))
-				a = Nod(OAS, m, n.List.Index(i1))
+				a = Nod(ir.OAS, m, n.List.Index(i1))
 				a = typecheck(a, Etop)
 				post = append(post, a)
-			} else if instrumenting && n.Op == OAS2FUNC && !isblank(n.List.Index(i1)) {
+			} else if instrumenting && n.Op == ir.OAS2FUNC && !isblank(n.List.Index(i1)) {
 				m = n.List.Index(i1)
 				t := ordertemp(m.Type, order, false)
 				n.List.SetIndex(i1, t)
-				a = Nod(OAS, m, t)
+				a = Nod(ir.OAS, m, t)
 				a = typecheck(a, Etop)
 				post = append(post, a)
 			}
@@ -465,7 +513,7 @@ func ordermapassign(n *Node, order *Order) {
 // Orderstmt orders the statement n, appending to order->out.
 // Temporaries created during the statement are cleaned
 // up using VARKILL instructions as possible.
-func orderstmt(n *Node, order *Order) {
+func orderstmt(n *ir.Node, order *Order) {
 	if n == nil {
 		return
 	}
@@ -478,37 +526,37 @@ func orderstmt(n *Node, order *Order) {
 	default:
 		Fatalf("orderstmt %v", Oconv(n.Op, 0))
 
-	case OVARKILL, OVARLIVE:
+	case ir.OVARKILL, ir.OVARLIVE:
 		order.out = append(order.out, n)
 
-	case OAS:
+	case ir.OAS:
 		t := marktemp(order)
 		n.Left = orderexpr(n.Left, order, nil)
 		n.Right = orderexpr(n.Right, order, n.Left)
 		ordermapassign(n, order)
 		cleantemp(t, order)
 
-	case OAS2,
-		OCLOSE,
-		OCOPY,
-		OPRINT,
-		OPRINTN,
-		ORECOVER,
-		ORECV:
+	case ir.OAS2,
+		ir.OCLOSE,
+		ir.OCOPY,
+		ir.OPRINT,
+		ir.OPRINTN,
+		ir.ORECOVER,
+		ir.ORECV:
 		t := marktemp(order)
 		n.Left = orderexpr(n.Left, order, nil)
 		n.Right = orderexpr(n.Right, order, nil)
 		orderexprlist(n.List, order)
 		orderexprlist(n.Rlist, order)
 		switch n.Op {
-		case OAS2, OAS2DOTTYPE:
+		case ir.OAS2, ir.OAS2DOTTYPE:
 			ordermapassign(n, order)
 		default:
 			order.out = append(order.out, n)
 		}
 		cleantemp(t, order)
 
-	case OASOP:
+	case ir.OASOP:
 		// Special: rewrite l op= r into l = l op r.
 		// This simplifies quite a few operations;
 		// most important is that it lets us separate
@@ -519,22 +567,22 @@ func orderstmt(n *Node, order *Order) {
 		n.Left = orderexpr(n.Left, order, nil)
 		n.Left = ordersafeexpr(n.Left, order)
 		tmp1 := treecopy(n.Left, 0)
-		if tmp1.Op == OINDEXMAP {
+		if tmp1.Op == ir.OINDEXMAP {
 			tmp1.Etype = 0 // now an rvalue not an lvalue
 		}
 		tmp1 = ordercopyexpr(tmp1, n.Left.Type, order, 0)
 		// TODO(marvin): Fix Node.EType type union.
-		n.Right = Nod(Op(n.Etype), tmp1, n.Right)
+		n.Right = Nod(ir.Op(n.Etype), tmp1, n.Right)
 		n.Right = typecheck(n.Right, Erv)
 		n.Right = orderexpr(n.Right, order, nil)
 		n.Etype = 0
-		n.Op = OAS
+		n.Op = ir.OAS
 		ordermapassign(n, order)
 		cleantemp(t, order)
 
 		// Special: make sure key is addressable,
 	// and make sure OINDEXMAP is not copied out.
-	case OAS2MAPR:
+	case ir.OAS2MAPR:
 		t := marktemp(order)
 
 		orderexprlist(n.List, order)
@@ -543,15 +591,15 @@ func orderstmt(n *Node, order *Order) {
 		r.Right = orderexpr(r.Right, order, nil)
 
 		// See case OINDEXMAP below.
-		if r.Right.Op == OARRAYBYTESTR {
-			r.Right.Op = OARRAYBYTESTRTMP
+		if r.Right.Op == ir.OARRAYBYTESTR {
+			r.Right.Op = ir.OARRAYBYTESTRTMP
 		}
 		r.Right = orderaddrtemp(r.Right, order)
 		ordermapassign(n, order)
 		cleantemp(t, order)
 
 		// Special: avoid copy of func call n->rlist->n.
-	case OAS2FUNC:
+	case ir.OAS2FUNC:
 		t := marktemp(order)
 
 		orderexprlist(n.List, order)
@@ -562,7 +610,7 @@ func orderstmt(n *Node, order *Order) {
 		// Special: use temporary variables to hold result,
 	// so that assertI2Tetc can take address of temporary.
 	// No temporary for blank assignment.
-	case OAS2DOTTYPE:
+	case ir.OAS2DOTTYPE:
 		t := marktemp(order)
 
 		orderexprlist(n.List, order)
@@ -573,58 +621,58 @@ func orderstmt(n *Node, order *Order) {
 			typ := n.Rlist.First().Type
 			tmp1 := ordertemp(typ, order, haspointers(typ))
 			order.out = append(order.out, n)
-			r := Nod(OAS, n.List.First(), tmp1)
+			r := Nod(ir.OAS, n.List.First(), tmp1)
 			r = typecheck(r, Etop)
 			ordermapassign(r, order)
-			n.List.Set([]*Node{tmp1, n.List.Second()})
+			n.List.Set([]*ir.Node{tmp1, n.List.Second()})
 		}
 
 		cleantemp(t, order)
 
 		// Special: use temporary variables to hold result,
 	// so that chanrecv can take address of temporary.
-	case OAS2RECV:
+	case ir.OAS2RECV:
 		t := marktemp(order)
 
 		orderexprlist(n.List, order)
 		n.Rlist.First().Left = orderexpr(n.Rlist.First().Left, order, nil) // arg to recv
 		ch := n.Rlist.First().Left.Type
 		tmp1 := ordertemp(ch.Type, order, haspointers(ch.Type))
-		var tmp2 *Node
+		var tmp2 *ir.Node
 		if !isblank(n.List.Second()) {
 			tmp2 = ordertemp(n.List.Second().Type, order, false)
 		} else {
-			tmp2 = ordertemp(Types[TBOOL], order, false)
+			tmp2 = ordertemp(ir.Types[ir.TBOOL], order, false)
 		}
 		order.out = append(order.out, n)
-		r := Nod(OAS, n.List.First(), tmp1)
+		r := Nod(ir.OAS, n.List.First(), tmp1)
 		r = typecheck(r, Etop)
 		ordermapassign(r, order)
-		r = Nod(OAS, n.List.Second(), tmp2)
+		r = Nod(ir.OAS, n.List.Second(), tmp2)
 		r = typecheck(r, Etop)
 		ordermapassign(r, order)
-		n.List.Set([]*Node{tmp1, tmp2})
+		n.List.Set([]*ir.Node{tmp1, tmp2})
 		cleantemp(t, order)
 
 		// Special: does not save n onto out.
-	case OBLOCK, OEMPTY:
+	case ir.OBLOCK, ir.OEMPTY:
 		orderstmtlist(n.List, order)
 
 		// Special: n->left is not an expression; save as is.
-	case OBREAK,
-		OCONTINUE,
-		ODCL,
-		ODCLCONST,
-		ODCLTYPE,
-		OFALL,
-		OXFALL,
-		OGOTO,
-		OLABEL,
-		ORETJMP:
+	case ir.OBREAK,
+		ir.OCONTINUE,
+		ir.ODCL,
+		ir.ODCLCONST,
+		ir.ODCLTYPE,
+		ir.OFALL,
+		ir.OXFALL,
+		ir.OGOTO,
+		ir.OLABEL,
+		ir.ORETJMP:
 		order.out = append(order.out, n)
 
 		// Special: handle call arguments.
-	case OCALLFUNC, OCALLINTER, OCALLMETH:
+	case ir.OCALLFUNC, ir.OCALLINTER, ir.OCALLMETH:
 		t := marktemp(order)
 
 		ordercall(n, order)
@@ -632,20 +680,21 @@ func orderstmt(n *Node, order *Order) {
 		cleantemp(t, order)
 
 		// Special: order arguments to inner call but not call itself.
-	case ODEFER, OPROC:
+	case ir.ODEFER, ir.OPROC:
 		t := marktemp(order)
 
 		switch n.Left.Op {
 		// Delete will take the address of the key.
 		// Copy key into new temp and do not clean it
 		// (it persists beyond the statement).
-		case ODELETE:
+		case ir.ODELETE:
 			orderexprlist(n.Left.List, order)
 
 			t1 := marktemp(order)
 			np := n.Left.List.Addr(1) // map key
 			*np = ordercopyexpr(*np, (*np).Type, order, 0)
-			poptemp(t1, order)
+			// Not poptemp: the key must not be reused.
+			order.temp = order.temp[:t1]
 
 		default:
 			ordercall(n.Left, order)
@@ -654,7 +703,7 @@ func orderstmt(n *Node, order *Order) {
 		order.out = append(order.out, n)
 		cleantemp(t, order)
 
-	case ODELETE:
+	case ir.ODELETE:
 		t := marktemp(order)
 		n.List.SetIndex(0, orderexpr(n.List.Index(0), order, nil))
 		n.List.SetIndex(1, orderexpr(n.List.Index(1), order, nil))
@@ -664,11 +713,11 @@ func orderstmt(n *Node, order *Order) {
 
 		// Clean temporaries from condition evaluation at
 	// beginning of loop body and after for statement.
-	case OFOR:
+	case ir.OFOR:
 		t := marktemp(order)
 
 		n.Left = orderexprinplace(n.Left, order)
-		var l []*Node
+		var l []*ir.Node
 		cleantempnopop(t, order, &l)
 		n.Nbody.Set(append(l, n.Nbody.Slice()...))
 		orderblockNodes(&n.Nbody)
@@ -678,11 +727,11 @@ func orderstmt(n *Node, order *Order) {
 
 		// Clean temporaries from condition at
 	// beginning of both branches.
-	case OIF:
+	case ir.OIF:
 		t := marktemp(order)
 
 		n.Left = orderexprinplace(n.Left, order)
-		var l []*Node
+		var l []*ir.Node
 		cleantempnopop(t, order, &l)
 		n.Nbody.Set(append(l, n.Nbody.Slice()...))
 		l = nil
@@ -695,7 +744,7 @@ func orderstmt(n *Node, order *Order) {
 
 		// Special: argument will be converted to interface using convT2E
 	// so make sure it is an addressable temporary.
-	case OPANIC:
+	case ir.OPANIC:
 		t := marktemp(order)
 
 		n.Left = orderexpr(n.Left, order, nil)
@@ -713,7 +762,7 @@ func orderstmt(n *Node, order *Order) {
 	// The exception is ranging over an array value (not a slice, not a pointer to array),
 	// which must make a copy to avoid seeing updates made during
 	// the range body. Ranging over an array value is uncommon though.
-	case ORANGE:
+	case ir.ORANGE:
 		t := marktemp(order)
 
 		n.Right = orderexpr(n.Right, order, nil)
@@ -723,9 +772,9 @@ func orderstmt(n *Node, order *Order) {
 
 			// Mark []byte(str) range expression to reuse string backing storage.
 		// It is safe because the storage cannot be mutated.
-		case TARRAY:
-			if n.Right.Op == OSTRARRAYBYTE {
-				n.Right.Op = OSTRARRAYBYTETMP
+		case ir.TARRAY:
+			if n.Right.Op == ir.OSTRARRAYBYTE {
+				n.Right.Op = ir.OSTRARRAYBYTETMP
 			}
 			if n.List.Len() < 2 || isblank(n.List.Second()) {
 				// for i := range x will only use x once, to compute len(x).
@@ -737,12 +786,12 @@ func orderstmt(n *Node, order *Order) {
 			// chan, string, slice, array ranges use value multiple times.
 		// make copy.
 		// fall through
-		case TCHAN, TSTRING:
+		case ir.TCHAN, ir.TSTRING:
 			r := n.Right
 
-			if r.Type.Etype == TSTRING && r.Type != Types[TSTRING] {
-				r = Nod(OCONV, r, nil)
-				r.Type = Types[TSTRING]
+			if r.Type.Etype == ir.TSTRING && r.Type != ir.Types[ir.TSTRING] {
+				r = Nod(ir.OCONV, r, nil)
+				r.Type = ir.Types[ir.TSTRING]
 				r = typecheck(r, Erv)
 			}
 
@@ -751,13 +800,14 @@ func orderstmt(n *Node, order *Order) {
 			// copy the map value in case it is a map literal.
 		// TODO(rsc): Make tmp = literal expressions reuse tmp.
 		// For maps tmp is just one word so it hardly matters.
-		case TMAP:
+		case ir.TMAP:
 			r := n.Right
 
 			n.Right = ordercopyexpr(r, r.Type, order, 0)
+			ordercheckrange(n, r, n.Right, order)
 
 			// n->alloc is the temp for the iterator.
-			prealloc[n] = ordertemp(Types[TUINT8], order, true)
+			orderprealloc(n, ir.Types[ir.TUINT8], order, true)
 		}
 		for i := range n.List.Slice() {
 			n.List.SetIndex(i, orderexprinplace(n.List.Index(i), order))
@@ -766,7 +816,7 @@ func orderstmt(n *Node, order *Order) {
 		order.out = append(order.out, n)
 		cleantemp(t, order)
 
-	case ORETURN:
+	case ir.ORETURN:
 		ordercallargs(&n.List, order)
 		order.out = append(order.out, n)
 
@@ -779,14 +829,14 @@ func orderstmt(n *Node, order *Order) {
 	// reordered after the channel evaluation for a different
 	// case (if p were nil, then the timing of the fault would
 	// give this away).
-	case OSELECT:
+	case ir.OSELECT:
 		t := marktemp(order)
 
-		var tmp1 *Node
-		var tmp2 *Node
-		var r *Node
+		var tmp1 *ir.Node
+		var tmp2 *ir.Node
+		var r *ir.Node
 		for _, n2 := range n.List.Slice() {
-			if n2.Op != OXCASE {
+			if n2.Op != ir.OXCASE {
 				Fatalf("order select case %v", Oconv(n2.Op, 0))
 			}
 			r = n2.Left
@@ -807,13 +857,13 @@ func orderstmt(n *Node, order *Order) {
 				// the ODCL nodes to declare x and y. We want to delay that
 				// declaration (and possible allocation) until inside the case body.
 				// Delete the ODCL nodes here and recreate them inside the body below.
-				case OSELRECV, OSELRECV2:
+				case ir.OSELRECV, ir.OSELRECV2:
 					if r.Colas {
 						i := 0
-						if r.Ninit.Len() != 0 && r.Ninit.First().Op == ODCL && r.Ninit.First().Left == r.Left {
+						if r.Ninit.Len() != 0 && r.Ninit.First().Op == ir.ODCL && r.Ninit.First().Left == r.Left {
 							i++
 						}
-						if i < r.Ninit.Len() && r.Ninit.Index(i).Op == ODCL && r.List.Len() != 0 && r.Ninit.Index(i).Left == r.List.First() {
+						if i < r.Ninit.Len() && r.Ninit.Index(i).Op == ir.ODCL && r.List.Len() != 0 && r.Ninit.Index(i).Left == r.List.First() {
 							i++
 						}
 						if i >= r.Ninit.Len() {
@@ -833,7 +883,7 @@ func orderstmt(n *Node, order *Order) {
 					// c is always evaluated; x and ok are only evaluated when assigned.
 					r.Right.Left = orderexpr(r.Right.Left, order, nil)
 
-					if r.Right.Left.Op != ONAME {
+					if r.Right.Left.Op != ir.ONAME {
 						r.Right.Left = ordercopyexpr(r.Right.Left, r.Right.Left.Type, order, 0)
 					}
 
@@ -853,13 +903,13 @@ func orderstmt(n *Node, order *Order) {
 						tmp1 = r.Left
 
 						if r.Colas {
-							tmp2 = Nod(ODCL, tmp1, nil)
+							tmp2 = Nod(ir.ODCL, tmp1, nil)
 							tmp2 = typecheck(tmp2, Etop)
 							n2.Ninit.Append(tmp2)
 						}
 
 						r.Left = ordertemp(r.Right.Left.Type.Type, order, haspointers(r.Right.Left.Type.Type))
-						tmp2 = Nod(OAS, tmp1, r.Left)
+						tmp2 = Nod(ir.OAS, tmp1, r.Left)
 						tmp2 = typecheck(tmp2, Etop)
 						n2.Ninit.Append(tmp2)
 					}
@@ -870,19 +920,19 @@ func orderstmt(n *Node, order *Order) {
 					if r.List.Len() != 0 {
 						tmp1 = r.List.First()
 						if r.Colas {
-							tmp2 = Nod(ODCL, tmp1, nil)
+							tmp2 = Nod(ir.ODCL, tmp1, nil)
 							tmp2 = typecheck(tmp2, Etop)
 							n2.Ninit.Append(tmp2)
 						}
 
 						r.List.Set1(ordertemp(tmp1.Type, order, false))
-						tmp2 = Nod(OAS, tmp1, r.List.First())
+						tmp2 = Nod(ir.OAS, tmp1, r.List.First())
 						tmp2 = typecheck(tmp2, Etop)
 						n2.Ninit.Append(tmp2)
 					}
 					n2.Ninit.Set(orderblock(n2.Ninit))
 
-				case OSEND:
+				case ir.OSEND:
 					if r.Ninit.Len() != 0 {
 						Yyerror("ninit on select send")
 						dumplist("ninit", r.Ninit)
@@ -918,7 +968,7 @@ func orderstmt(n *Node, order *Order) {
 		poptemp(t, order)
 
 		// Special: value being sent is passed as a pointer; make it addressable.
-	case OSEND:
+	case ir.OSEND:
 		t := marktemp(order)
 
 		n.Left = orderexpr(n.Left, order, nil)
@@ -934,12 +984,12 @@ func orderstmt(n *Node, order *Order) {
 	// the if-else chain instead.)
 	// For now just clean all the temporaries at the end.
 	// In practice that's fine.
-	case OSWITCH:
+	case ir.OSWITCH:
 		t := marktemp(order)
 
 		n.Left = orderexpr(n.Left, order, nil)
 		for _, n4 := range n.List.Slice() {
-			if n4.Op != OXCASE {
+			if n4.Op != ir.OXCASE {
 				Fatalf("order switch case %v", Oconv(n4.Op, 0))
 			}
 			orderexprlistinplace(n4.List, order)
@@ -954,7 +1004,7 @@ func orderstmt(n *Node, order *Order) {
 }
 
 // Orderexprlist orders the expression list l into order.
-func orderexprlist(l Nodes, order *Order) {
+func orderexprlist(l ir.Nodes, order *Order) {
 	s := l.Slice()
 	for i := range s {
 		s[i] = orderexpr(s[i], order, nil)
@@ -963,7 +1013,7 @@ func orderexprlist(l Nodes, order *Order) {
 
 // Orderexprlist orders the expression list l but saves
 // the side effects on the individual expression ninit lists.
-func orderexprlistinplace(l Nodes, order *Order) {
+func orderexprlistinplace(l ir.Nodes, order *Order) {
 	s := l.Slice()
 	for i := range s {
 		s[i] = orderexprinplace(s[i], order)
@@ -971,7 +1021,7 @@ func orderexprlistinplace(l Nodes, order *Order) {
 }
 
 // prealloc[x] records the allocation to use for x.
-var prealloc = map[*Node]*Node{}
+var prealloc = map[*ir.Node]*ir.Node{}
 
 // Orderexpr orders a single expression, appending side
 // effects to order->out as needed.
@@ -980,7 +1030,7 @@ var prealloc = map[*Node]*Node{}
 // to avoid copying the result of the expression to a temporary.)
 // The result of orderexpr MUST be assigned back to n, e.g.
 // 	n.Left = orderexpr(n.Left, order, lhs)
-func orderexpr(n *Node, order *Order, lhs *Node) *Node {
+func orderexpr(n *ir.Node, order *Order, lhs *ir.Node) *ir.Node {
 	if n == nil {
 		return n
 	}
@@ -998,14 +1048,14 @@ func orderexpr(n *Node, order *Order, lhs *Node) *Node {
 		// Addition of strings turns into a function call.
 	// Allocate a temporary to hold the strings.
 	// Fewer than 5 strings use direct runtime helpers.
-	case OADDSTR:
+	case ir.OADDSTR:
 		orderexprlist(n.List, order)
 
 		if n.List.Len() > 5 {
-			t := typ(TARRAY)
+			t := ir.Typ(ir.TARRAY)
 			t.Bound = int64(n.List.Len())
-			t.Type = Types[TSTRING]
-			prealloc[n] = ordertemp(t, order, false)
+			t.Type = ir.Types[ir.TSTRING]
+			orderprealloc(n, t, order, false)
 		}
 
 		// Mark string(byteSlice) arguments to reuse byteSlice backing
@@ -1019,34 +1069,34 @@ func orderexpr(n *Node, order *Order, lhs *Node) *Node {
 
 		haslit := false
 		for _, n1 := range n.List.Slice() {
-			hasbyte = hasbyte || n1.Op == OARRAYBYTESTR
-			haslit = haslit || n1.Op == OLITERAL && len(n1.Val().U.(string)) != 0
+			hasbyte = hasbyte || n1.Op == ir.OARRAYBYTESTR
+			haslit = haslit || n1.Op == ir.OLITERAL && len(n1.Val().U.(string)) != 0
 		}
 
 		if haslit && hasbyte {
 			for _, n2 := range n.List.Slice() {
-				if n2.Op == OARRAYBYTESTR {
-					n2.Op = OARRAYBYTESTRTMP
+				if n2.Op == ir.OARRAYBYTESTR {
+					n2.Op = ir.OARRAYBYTESTRTMP
 				}
 			}
 		}
 
-	case OCMPSTR:
+	case ir.OCMPSTR:
 		n.Left = orderexpr(n.Left, order, nil)
 		n.Right = orderexpr(n.Right, order, nil)
 
 		// Mark string(byteSlice) arguments to reuse byteSlice backing
 		// buffer during conversion. String comparison does not
 		// memorize the strings for later use, so it is safe.
-		if n.Left.Op == OARRAYBYTESTR {
-			n.Left.Op = OARRAYBYTESTRTMP
+		if n.Left.Op == ir.OARRAYBYTESTR {
+			n.Left.Op = ir.OARRAYBYTESTRTMP
 		}
-		if n.Right.Op == OARRAYBYTESTR {
-			n.Right.Op = OARRAYBYTESTRTMP
+		if n.Right.Op == ir.OARRAYBYTESTR {
+			n.Right.Op = ir.OARRAYBYTESTRTMP
 		}
 
 		// key must be addressable
-	case OINDEXMAP:
+	case ir.OINDEXMAP:
 		n.Left = orderexpr(n.Left, order, nil)
 
 		n.Right = orderexpr(n.Right, order, nil)
@@ -1061,8 +1111,8 @@ func orderexpr(n *Node, order *Order, lhs *Node) *Node {
 		// the map index, because the map access is going to
 		// be forced to happen immediately following this
 		// conversion (by the ordercopyexpr a few lines below).
-		if n.Etype == 0 && n.Right.Op == OARRAYBYTESTR {
-			n.Right.Op = OARRAYBYTESTRTMP
+		if n.Etype == 0 && n.Right.Op == ir.OARRAYBYTESTR {
+			n.Right.Op = ir.OARRAYBYTESTRTMP
 		}
 
 		n.Right = orderaddrtemp(n.Right, order)
@@ -1074,65 +1124,80 @@ func orderexpr(n *Node, order *Order, lhs *Node) *Node {
 
 		// concrete type (not interface) argument must be addressable
 	// temporary to pass to runtime.
-	case OCONVIFACE:
+	case ir.OCONVIFACE:
 		n.Left = orderexpr(n.Left, order, nil)
 
 		if !Isinter(n.Left.Type) {
 			n.Left = orderaddrtemp(n.Left, order)
 		}
 
-	case OANDAND, OOROR:
+	case ir.OCONVNOP:
+		if n.Type.Etype == ir.TUNSAFEPTR && n.Left.Type.Etype == ir.TUINTPTR && (n.Left.Op == ir.OCALLFUNC || n.Left.Op == ir.OCALLINTER || n.Left.Op == ir.OCALLMETH) {
+			// When reordering unsafe.Pointer(f()) into a separate
+			// statement, the conversion and function call must stay
+			// together, so that the result is held in a pointer-typed
+			// temporary and -d=checkptr can see where it came from.
+			orderinit(n.Left, order)
+			ordercall(n.Left, order)
+			if lhs == nil || lhs.Op != ir.ONAME || instrumenting {
+				n = ordercopyexpr(n, n.Type, order, 0)
+			}
+		} else {
+			n.Left = orderexpr(n.Left, order, nil)
+		}
+
+	case ir.OANDAND, ir.OOROR:
 		mark := marktemp(order)
 		n.Left = orderexpr(n.Left, order, nil)
 
 		// Clean temporaries from first branch at beginning of second.
 		// Leave them on the stack so that they can be killed in the outer
 		// context in case the short circuit is taken.
-		var s []*Node
+		var s []*ir.Node
 
 		cleantempnopop(mark, order, &s)
 		n.Right.Ninit.Set(append(s, n.Right.Ninit.Slice()...))
 		n.Right = orderexprinplace(n.Right, order)
 
-	case OCALLFUNC,
-		OCALLINTER,
-		OCALLMETH,
-		OCAP,
-		OCOMPLEX,
-		OCOPY,
-		OIMAG,
-		OLEN,
-		OMAKECHAN,
-		OMAKEMAP,
-		OMAKESLICE,
-		ONEW,
-		OREAL,
-		ORECOVER,
-		OSTRARRAYBYTE,
-		OSTRARRAYBYTETMP,
-		OSTRARRAYRUNE:
+	case ir.OCALLFUNC,
+		ir.OCALLINTER,
+		ir.OCALLMETH,
+		ir.OCAP,
+		ir.OCOMPLEX,
+		ir.OCOPY,
+		ir.OIMAG,
+		ir.OLEN,
+		ir.OMAKECHAN,
+		ir.OMAKEMAP,
+		ir.OMAKESLICE,
+		ir.ONEW,
+		ir.OREAL,
+		ir.ORECOVER,
+		ir.OSTRARRAYBYTE,
+		ir.OSTRARRAYBYTETMP,
+		ir.OSTRARRAYRUNE:
 		ordercall(n, order)
-		if lhs == nil || lhs.Op != ONAME || instrumenting {
+		if lhs == nil || lhs.Op != ir.ONAME || instrumenting {
 			n = ordercopyexpr(n, n.Type, order, 0)
 		}
 
-	case OAPPEND:
+	case ir.OAPPEND:
 		ordercallargs(&n.List, order)
-		if lhs == nil || lhs.Op != ONAME && !samesafeexpr(lhs, n.List.First()) {
+		if lhs == nil || lhs.Op != ir.ONAME && !samesafeexpr(lhs, n.List.First()) {
 			n = ordercopyexpr(n, n.Type, order, 0)
 		}
 
-	case OSLICE, OSLICEARR, OSLICESTR:
+	case ir.OSLICE, ir.OSLICEARR, ir.OSLICESTR:
 		n.Left = orderexpr(n.Left, order, nil)
 		n.Right.Left = orderexpr(n.Right.Left, order, nil)
 		n.Right.Left = ordercheapexpr(n.Right.Left, order)
 		n.Right.Right = orderexpr(n.Right.Right, order, nil)
 		n.Right.Right = ordercheapexpr(n.Right.Right, order)
-		if lhs == nil || lhs.Op != ONAME && !samesafeexpr(lhs, n.Left) {
+		if lhs == nil || lhs.Op != ir.ONAME && !samesafeexpr(lhs, n.Left) {
 			n = ordercopyexpr(n, n.Type, order, 0)
 		}
 
-	case OSLICE3, OSLICE3ARR:
+	case ir.OSLICE3, ir.OSLICE3ARR:
 		n.Left = orderexpr(n.Left, order, nil)
 		n.Right.Left = orderexpr(n.Right.Left, order, nil)
 		n.Right.Left = ordercheapexpr(n.Right.Left, order)
@@ -1140,34 +1205,34 @@ func orderexpr(n *Node, order *Order, lhs *Node) *Node {
 		n.Right.Right.Left = ordercheapexpr(n.Right.Right.Left, order)
 		n.Right.Right.Right = orderexpr(n.Right.Right.Right, order, nil)
 		n.Right.Right.Right = ordercheapexpr(n.Right.Right.Right, order)
-		if lhs == nil || lhs.Op != ONAME && !samesafeexpr(lhs, n.Left) {
+		if lhs == nil || lhs.Op != ir.ONAME && !samesafeexpr(lhs, n.Left) {
 			n = ordercopyexpr(n, n.Type, order, 0)
 		}
 
-	case OCLOSURE:
+	case ir.OCLOSURE:
 		if n.Noescape && len(n.Func.Cvars.Slice()) > 0 {
-			prealloc[n] = ordertemp(Types[TUINT8], order, false) // walk will fill in correct type
+			orderprealloc(n, ir.Types[ir.TUINT8], order, false) // walk will fill in correct type
 		}
 
-	case OARRAYLIT, OCALLPART:
+	case ir.OARRAYLIT, ir.OCALLPART:
 		n.Left = orderexpr(n.Left, order, nil)
 		n.Right = orderexpr(n.Right, order, nil)
 		orderexprlist(n.List, order)
 		orderexprlist(n.Rlist, order)
 		if n.Noescape {
-			prealloc[n] = ordertemp(Types[TUINT8], order, false) // walk will fill in correct type
+			orderprealloc(n, ir.Types[ir.TUINT8], order, false) // walk will fill in correct type
 		}
 
-	case ODDDARG:
+	case ir.ODDDARG:
 		if n.Noescape {
 			// The ddd argument does not live beyond the call it is created for.
 			// Allocate a temporary that will be cleaned up when this statement
 			// completes. We could be more aggressive and try to arrange for it
 			// to be cleaned up when the call completes.
-			prealloc[n] = ordertemp(n.Type.Type, order, false)
+			orderprealloc(n, n.Type.Type, order, false)
 		}
 
-	case ODOTTYPE, ODOTTYPE2:
+	case ir.ODOTTYPE, ir.ODOTTYPE2:
 		n.Left = orderexpr(n.Left, order, nil)
 		// TODO(rsc): The Isfat is for consistency with componentgen and walkexpr.
 		// It needs to be removed in all three places.
@@ -1176,15 +1241,15 @@ func orderexpr(n *Node, order *Order, lhs *Node) *Node {
 			n = ordercopyexpr(n, n.Type, order, 1)
 		}
 
-	case ORECV:
+	case ir.ORECV:
 		n.Left = orderexpr(n.Left, order, nil)
 		n = ordercopyexpr(n, n.Type, order, 1)
 
-	case OEQ, ONE:
+	case ir.OEQ, ir.ONE:
 		n.Left = orderexpr(n.Left, order, nil)
 		n.Right = orderexpr(n.Right, order, nil)
 		t := n.Left.Type
-		if t.Etype == TSTRUCT || Isfixedarray(t) {
+		if t.Etype == ir.TSTRUCT || Isfixedarray(t) {
 			// for complex comparisons, we need both args to be
 			// addressable so we can pass them to the runtime.
 			n.Left = orderaddrtemp(n.Left, order)
@@ -0,0 +1,52 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gc
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"testing"
+)
+
+// Make sure -c produces the same object file however the functions
+// are scheduled, and that the program built from it runs.
+func TestParallelSSA(t *testing.T) {
+	d := newTestDir(t, "TestParallelSSA")
+	defer d.remove()
+
+	// Enough functions for several batches, each with string
+	// constants and temporaries allocated by the back end.
+	var buf bytes.Buffer
+	buf.WriteString("package main\n\ntype T struct{ a, b, c, d int }\n\n")
+	const n = 100
+	for i := 0; i < n; i++ {
+		fmt.Fprintf(&buf, "func f%d(x T, s string) (T, string) {\n\tx.b = x.a\n\tx.a += %d\n\treturn x, s + \"f%d\"\n}\n\n", i, i, i)
+	}
+	buf.WriteString("func main() {\n\tvar x T\n\tvar s string\n")
+	for i := 0; i < n; i++ {
+		fmt.Fprintf(&buf, "\tx, s = f%d(x, s)\n", i)
+	}
+	buf.WriteString("\tprintln(x.a, x.b, len(s))\n}\n")
+
+	var first []byte
+	var obj string
+	for i := 0; i < 3; i++ {
+		obj = d.compile("main", buf.String(), "-c=4")
+		data, err := ioutil.ReadFile(obj)
+		if err != nil {
+			t.Fatalf("could not read object file: %v", err)
+		}
+		if first == nil {
+			first = data
+		} else if !bytes.Equal(data, first) {
+			t.Fatalf("object files compiled with -c=4 differ")
+		}
+	}
+
+	if out := d.run(d.link(obj)); out != "4950 4851 290\n" {
+		t.Errorf("got output %q, want %q", out, "4950 4851 290\n")
+	}
+}
@@ -14,6 +14,7 @@ package gc
 
 import (
 	"bufio"
+	"cmd/compile/internal/ir"
 	"fmt"
 	"strconv"
 	"strings"
@@ -37,8 +38,10 @@ type parser struct {
 	xnest  int    // expression nesting level (for complit ambiguity resolution)
 	indent []byte // tracing support
 
+	consttoks []token // tokens of lastconst, for repeating it; see constdcl
+
 	// TODO(gri) remove this once we switch to binary export format
-	structpkg *Pkg // for verification in addmethod only
+	structpkg *ir.Pkg // for verification in addmethod only
 }
 
 // newparser returns a new parser ready to parse from src.
@@ -312,7 +315,7 @@ func (p *parser) importdcl() {
 		defer p.trace("importdcl")()
 	}
 
-	var my *Sym
+	var my *ir.Sym
 	switch p.tok {
 	case LNAME, '@', '?':
 		// import with given name
@@ -353,7 +356,7 @@ func (p *parser) importdcl() {
 		my = Lookup(ipkg.Name)
 	}
 
-	pack := Nod(OPACK, nil, nil)
+	pack := Nod(ir.OPACK, nil, nil)
 	pack.Sym = my
 	pack.Name.Pkg = ipkg
 	pack.Lineno = line
@@ -430,12 +433,12 @@ func (p *parser) import_package() {
 // ConstDecl   = "const" ( ConstSpec | "(" { ConstSpec ";" } ")" ) .
 // TypeDecl    = "type" ( TypeSpec | "(" { TypeSpec ";" } ")" ) .
 // VarDecl     = "var" ( VarSpec | "(" { VarSpec ";" } ")" ) .
-func (p *parser) common_dcl() []*Node {
+func (p *parser) common_dcl() []*ir.Node {
 	if trace && Debug['x'] != 0 {
 		defer p.trace("common_dcl")()
 	}
 
-	var dcl func() []*Node
+	var dcl func() []*ir.Node
 	switch p.tok {
 	case LVAR:
 		dcl = p.vardcl
@@ -452,7 +455,7 @@ func (p *parser) common_dcl() []*Node {
 	}
 
 	p.next()
-	var s []*Node
+	var s []*ir.Node
 	if p.got('(') {
 		for p.tok != EOF && p.tok != ')' {
 			s = append(s, dcl()...)
@@ -467,19 +470,20 @@ func (p *parser) common_dcl() []*Node {
 
 	iota_ = -100000
 	lastconst = nil
+	p.consttoks = nil
 
 	return s
 }
 
 // VarSpec = IdentifierList ( Type [ "=" ExpressionList ] | "=" ExpressionList ) .
-func (p *parser) vardcl() []*Node {
+func (p *parser) vardcl() []*ir.Node {
 	if trace && Debug['x'] != 0 {
 		defer p.trace("vardcl")()
 	}
 
 	names := p.dcl_name_list()
-	var typ *Node
-	var exprs []*Node
+	var typ *ir.Node
+	var exprs []*ir.Node
 	if p.got('=') {
 		exprs = p.expr_list()
 	} else {
@@ -493,26 +497,66 @@ func (p *parser) vardcl() []*Node {
 }
 
 // ConstSpec = IdentifierList [ [ Type ] "=" ExpressionList ] .
-func (p *parser) constdcl() []*Node {
+func (p *parser) constdcl() []*ir.Node {
 	if trace && Debug['x'] != 0 {
 		defer p.trace("constdcl")()
 	}
 
 	names := p.dcl_name_list()
-	var typ *Node
-	var exprs []*Node
+	var typ *ir.Node
+	var exprs []*ir.Node
 	if p.tok != EOF && p.tok != ';' && p.tok != ')' {
 		typ = p.try_ntype()
-		if p.got('=') {
+		if p.tok == '=' {
+			// Record the expressions' tokens, in case
+			// a following spec repeats them implicitly.
+			recording := p.recording
+			start := len(p.recorded)
+			p.recording = true
+			p.next()
 			exprs = p.expr_list()
+			// The last token recorded follows the expressions.
+			p.consttoks = append([]token(nil), p.recorded[start:len(p.recorded)-1]...)
+			p.recording = recording
+			if !recording {
+				p.recorded = p.recorded[:0]
+			}
 		}
 	}
 
+	if exprs == nil && typ == nil && listhasclosure(lastconst) {
+		// treecopy can't copy a function literal, which
+		// would be shared by the constants. Parse the
+		// expressions again, at the line of this spec.
+		typ = lasttype
+		exprs = p.reparse(p.consttoks, names[0].Lineno)
+	}
+
 	return constiter(names, typ, exprs)
 }
 
+// reparse parses the expression list recorded in toks again,
+// as if it were written at line lno.
+func (p *parser) reparse(toks []token, lno int32) []*ir.Node {
+	next := p.token()
+	p.replay = make([]token, 0, len(toks)+1)
+	for _, t := range toks {
+		t.lineno = lno
+		p.replay = append(p.replay, t)
+	}
+	p.replay = append(p.replay, next)
+	p.next()
+	exprs := p.expr_list()
+	if len(p.replay) != 0 {
+		// The expressions had a syntax error the first time.
+		p.settoken(next)
+		p.replay = nil
+	}
+	return exprs
+}
+
 // TypeSpec = identifier Type .
-func (p *parser) typedcl() []*Node {
+func (p *parser) typedcl() []*ir.Node {
 	if trace && Debug['x'] != 0 {
 		defer p.trace("typedcl")()
 	}
@@ -526,20 +570,20 @@ func (p *parser) typedcl() []*Node {
 		p.advance(';', ')')
 	}
 
-	return []*Node{typedcl1(name, typ, true)}
+	return []*ir.Node{typedcl1(name, typ, true)}
 }
 
 // SimpleStmt = EmptyStmt | ExpressionStmt | SendStmt | IncDecStmt | Assignment | ShortVarDecl .
 //
 // simple_stmt may return missing_stmt if labelOk is set.
-func (p *parser) simple_stmt(labelOk, rangeOk bool) *Node {
+func (p *parser) simple_stmt(labelOk, rangeOk bool) *ir.Node {
 	if trace && Debug['x'] != 0 {
 		defer p.trace("simple_stmt")()
 	}
 
 	if rangeOk && p.got(LRANGE) {
 		// LRANGE expr
-		r := Nod(ORANGE, nil, p.expr())
+		r := Nod(ir.ORANGE, nil, p.expr())
 		r.Etype = 0 // := flag
 		return r
 	}
@@ -556,17 +600,17 @@ func (p *parser) simple_stmt(labelOk, rangeOk bool) *Node {
 			p.next()
 			rhs := p.expr()
 
-			stmt := Nod(OASOP, lhs, rhs)
-			stmt.Etype = EType(op) // rathole to pass opcode
+			stmt := Nod(ir.OASOP, lhs, rhs)
+			stmt.Etype = ir.EType(op) // rathole to pass opcode
 			return stmt
 
 		case LINCOP:
 			// expr LINCOP
 			p.next()
 
-			stmt := Nod(OASOP, lhs, Nodintconst(1))
+			stmt := Nod(ir.OASOP, lhs, Nodintconst(1))
 			stmt.Implicit = true
-			stmt.Etype = EType(p.op)
+			stmt.Etype = ir.EType(p.op)
 			return stmt
 
 		case ':':
@@ -576,7 +620,7 @@ func (p *parser) simple_stmt(labelOk, rangeOk bool) *Node {
 				// (calling p.name()) and given an ONAME, ONONAME, OTYPE, OPACK, or OLITERAL node.
 				// We only have a labelname if there is a symbol (was issue 14006).
 				switch lhs.Op {
-				case ONAME, ONONAME, OTYPE, OPACK, OLITERAL:
+				case ir.ONAME, ir.ONONAME, ir.OTYPE, ir.OPACK, ir.OLITERAL:
 					if lhs.Sym != nil {
 						lhs = newname(lhs.Sym)
 						break
@@ -586,7 +630,7 @@ func (p *parser) simple_stmt(labelOk, rangeOk bool) *Node {
 					p.syntax_error("expecting semicolon or newline or }")
 					// we already progressed, no need to advance
 				}
-				lhs := Nod(OLABEL, lhs, nil)
+				lhs := Nod(ir.OLABEL, lhs, nil)
 				lhs.Sym = dclstack // context, for goto restrictions
 				p.next()           // consume ':' after making label node for correct lineno
 				return p.labeled_stmt(lhs)
@@ -608,7 +652,7 @@ func (p *parser) simple_stmt(labelOk, rangeOk bool) *Node {
 		p.next()
 		if rangeOk && p.got(LRANGE) {
 			// expr_list '=' LRANGE expr
-			r := Nod(ORANGE, nil, p.expr())
+			r := Nod(ir.ORANGE, nil, p.expr())
 			r.List.Set(lhs)
 			r.Etype = 0 // := flag
 			return r
@@ -619,10 +663,10 @@ func (p *parser) simple_stmt(labelOk, rangeOk bool) *Node {
 
 		if len(lhs) == 1 && len(rhs) == 1 {
 			// simple
-			return Nod(OAS, lhs[0], rhs[0])
+			return Nod(ir.OAS, lhs[0], rhs[0])
 		}
 		// multiple
-		stmt := Nod(OAS2, nil, nil)
+		stmt := Nod(ir.OAS2, nil, nil)
 		stmt.List.Set(lhs)
 		stmt.Rlist.Set(rhs)
 		return stmt
@@ -633,7 +677,7 @@ func (p *parser) simple_stmt(labelOk, rangeOk bool) *Node {
 
 		if rangeOk && p.got(LRANGE) {
 			// expr_list LCOLAS LRANGE expr
-			r := Nod(ORANGE, nil, p.expr())
+			r := Nod(ir.ORANGE, nil, p.expr())
 			r.List.Set(lhs)
 			r.Colas = true
 			colasdefn(r.List, r)
@@ -643,14 +687,14 @@ func (p *parser) simple_stmt(labelOk, rangeOk bool) *Node {
 		// expr_list LCOLAS expr_list
 		rhs := p.expr_list()
 
-		if rhs[0].Op == OTYPESW {
-			ts := Nod(OTYPESW, nil, rhs[0].Right)
+		if rhs[0].Op == ir.OTYPESW {
+			ts := Nod(ir.OTYPESW, nil, rhs[0].Right)
 			if len(rhs) > 1 {
 				Yyerror("expr.(type) must be alone in list")
 			}
 			if len(lhs) > 1 {
 				Yyerror("argument count mismatch: %d = %d", len(lhs), 1)
-			} else if (lhs[0].Op != ONAME && lhs[0].Op != OTYPE && lhs[0].Op != ONONAME && (lhs[0].Op != OLITERAL || lhs[0].Name == nil)) || isblank(lhs[0]) {
+			} else if (lhs[0].Op != ir.ONAME && lhs[0].Op != ir.OTYPE && lhs[0].Op != ir.ONONAME && (lhs[0].Op != ir.OLITERAL || lhs[0].Name == nil)) || isblank(lhs[0]) {
 				Yyerror("invalid variable name %s in type switch", lhs[0])
 			} else {
 				ts.Left = dclname(lhs[0].Sym)
@@ -668,12 +712,12 @@ func (p *parser) simple_stmt(labelOk, rangeOk bool) *Node {
 
 // LabeledStmt = Label ":" Statement .
 // Label       = identifier .
-func (p *parser) labeled_stmt(label *Node) *Node {
+func (p *parser) labeled_stmt(label *ir.Node) *ir.Node {
 	if trace && Debug['x'] != 0 {
 		defer p.trace("labeled_stmt")()
 	}
 
-	var ls *Node // labeled statement
+	var ls *ir.Node // labeled statement
 	if p.tok != '}' && p.tok != EOF {
 		ls = p.stmt()
 		if ls == missing_stmt {
@@ -685,9 +729,9 @@ func (p *parser) labeled_stmt(label *Node) *Node {
 	}
 
 	label.Name.Defn = ls
-	l := []*Node{label}
+	l := []*ir.Node{label}
 	if ls != nil {
-		if ls.Op == OBLOCK && ls.Ninit.Len() == 0 {
+		if ls.Op == ir.OBLOCK && ls.Ninit.Len() == 0 {
 			l = append(l, ls.List.Slice()...)
 		} else {
 			l = append(l, ls)
@@ -705,7 +749,7 @@ func (p *parser) labeled_stmt(label *Node) *Node {
 // CommCase       = "case" ( SendStmt | RecvStmt ) | "default" .
 // RecvStmt       = [ ExpressionList "=" | IdentifierList ":=" ] RecvExpr .
 // RecvExpr       = Expression .
-func (p *parser) case_(tswitch *Node) *Node {
+func (p *parser) case_(tswitch *ir.Node) *ir.Node {
 	if trace && Debug['x'] != 0 {
 		defer p.trace("case_")()
 	}
@@ -722,7 +766,7 @@ func (p *parser) case_(tswitch *Node) *Node {
 			// right will point to next case
 			// done in casebody()
 			markdcl() // matching popdcl in caseblock
-			stmt := Nod(OXCASE, nil, nil)
+			stmt := Nod(ir.OXCASE, nil, nil)
 			stmt.List.Set(cases)
 			if tswitch != nil {
 				if n := tswitch.Left; n != nil {
@@ -748,12 +792,12 @@ func (p *parser) case_(tswitch *Node) *Node {
 			// right will point to next case
 			// done in casebody()
 			markdcl() // matching popdcl in caseblock
-			stmt := Nod(OXCASE, nil, nil)
-			var n *Node
+			stmt := Nod(ir.OXCASE, nil, nil)
+			var n *ir.Node
 			if len(cases) == 1 {
-				n = Nod(OAS, cases[0], rhs)
+				n = Nod(ir.OAS, cases[0], rhs)
 			} else {
-				n = Nod(OAS2, nil, nil)
+				n = Nod(ir.OAS2, nil, nil)
 				n.List.Set(cases)
 				n.Rlist.Set1(rhs)
 			}
@@ -772,15 +816,15 @@ func (p *parser) case_(tswitch *Node) *Node {
 			// right will point to next case
 			// done in casebody()
 			markdcl() // matching popdcl in caseblock
-			stmt := Nod(OXCASE, nil, nil)
-			stmt.List.Set1(colas(cases, []*Node{rhs}, lno))
+			stmt := Nod(ir.OXCASE, nil, nil)
+			stmt.List.Set1(colas(cases, []*ir.Node{rhs}, lno))
 
 			p.want(':') // consume ':' after declaring select cases for correct lineno
 			return stmt
 
 		default:
-			markdcl()                     // for matching popdcl in caseblock
-			stmt := Nod(OXCASE, nil, nil) // don't return nil
+			markdcl()                        // for matching popdcl in caseblock
+			stmt := Nod(ir.OXCASE, nil, nil) // don't return nil
 			p.syntax_error("expecting := or = or : or comma")
 			p.advance(LCASE, LDEFAULT, '}')
 			return stmt
@@ -791,7 +835,7 @@ func (p *parser) case_(tswitch *Node) *Node {
 		p.next()
 
 		markdcl() // matching popdcl in caseblock
-		stmt := Nod(OXCASE, nil, nil)
+		stmt := Nod(ir.OXCASE, nil, nil)
 		if tswitch != nil {
 			if n := tswitch.Left; n != nil {
 				// type switch - declare variable
@@ -808,8 +852,8 @@ func (p *parser) case_(tswitch *Node) *Node {
 		return stmt
 
 	default:
-		markdcl()                     // matching popdcl in caseblock
-		stmt := Nod(OXCASE, nil, nil) // don't return nil
+		markdcl()                        // matching popdcl in caseblock
+		stmt := Nod(ir.OXCASE, nil, nil) // don't return nil
 		p.syntax_error("expecting case or default or }")
 		p.advance(LCASE, LDEFAULT, '}')
 		return stmt
@@ -818,7 +862,7 @@ func (p *parser) case_(tswitch *Node) *Node {
 
 // Block         = "{" StatementList "}" .
 // StatementList = { Statement ";" } .
-func (p *parser) compound_stmt() *Node {
+func (p *parser) compound_stmt() *ir.Node {
 	if trace && Debug['x'] != 0 {
 		defer p.trace("compound_stmt")()
 	}
@@ -830,7 +874,7 @@ func (p *parser) compound_stmt() *Node {
 	popdcl()
 
 	if len(l) == 0 {
-		return Nod(OEMPTY, nil, nil)
+		return Nod(ir.OEMPTY, nil, nil)
 	}
 	return liststmt(l)
 }
@@ -840,7 +884,7 @@ func (p *parser) compound_stmt() *Node {
 // ExprCaseClause = ExprSwitchCase ":" StatementList .
 // TypeCaseClause = TypeSwitchCase ":" StatementList .
 // CommClause     = CommCase ":" StatementList .
-func (p *parser) caseblock(tswitch *Node) *Node {
+func (p *parser) caseblock(tswitch *ir.Node) *ir.Node {
 	if trace && Debug['x'] != 0 {
 		defer p.trace("caseblock")()
 	}
@@ -855,7 +899,7 @@ func (p *parser) caseblock(tswitch *Node) *Node {
 }
 
 // caseblock_list parses a superset of switch and select clause lists.
-func (p *parser) caseblock_list(tswitch *Node) (l []*Node) {
+func (p *parser) caseblock_list(tswitch *ir.Node) (l []*ir.Node) {
 	if trace && Debug['x'] != 0 {
 		defer p.trace("caseblock_list")()
 	}
@@ -873,7 +917,7 @@ func (p *parser) caseblock_list(tswitch *Node) (l []*Node) {
 }
 
 // loop_body parses if and for statement bodies.
-func (p *parser) loop_body(context string) []*Node {
+func (p *parser) loop_body(context string) []*ir.Node {
 	if trace && Debug['x'] != 0 {
 		defer p.trace("loop_body")()
 	}
@@ -895,7 +939,7 @@ func (p *parser) loop_body(context string) []*Node {
 //
 // ForStmt   = "for" [ Condition | ForClause | RangeClause ] Block .
 // Condition = Expression .
-func (p *parser) for_header() *Node {
+func (p *parser) for_header() *ir.Node {
 	if trace && Debug['x'] != 0 {
 		defer p.trace("for_header")()
 	}
@@ -907,7 +951,7 @@ func (p *parser) for_header() *Node {
 		if post != nil && post.Colas {
 			Yyerror("cannot declare in the for-increment")
 		}
-		h := Nod(OFOR, nil, nil)
+		h := Nod(ir.OFOR, nil, nil)
 		if init != nil {
 			h.Ninit.Set1(init)
 		}
@@ -916,18 +960,18 @@ func (p *parser) for_header() *Node {
 		return h
 	}
 
-	if cond != nil && cond.Op == ORANGE {
+	if cond != nil && cond.Op == ir.ORANGE {
 		// range_stmt - handled by pexpr
 		return cond
 	}
 
 	// normal test
-	h := Nod(OFOR, nil, nil)
+	h := Nod(ir.OFOR, nil, nil)
 	h.Left = cond
 	return h
 }
 
-func (p *parser) for_body() *Node {
+func (p *parser) for_body() *ir.Node {
 	if trace && Debug['x'] != 0 {
 		defer p.trace("for_body")()
 	}
@@ -940,7 +984,7 @@ func (p *parser) for_body() *Node {
 }
 
 // ForStmt = "for" [ Condition | ForClause | RangeClause ] Block .
-func (p *parser) for_stmt() *Node {
+func (p *parser) for_stmt() *ir.Node {
 	if trace && Debug['x'] != 0 {
 		defer p.trace("for_stmt")()
 	}
@@ -959,7 +1003,7 @@ func (p *parser) for_stmt() *Node {
 // Header   = [ InitStmt ] ";" [ Condition ] ";" [ PostStmt ] .  // for_stmt only
 // InitStmt = SimpleStmt .
 // PostStmt = SimpleStmt .
-func (p *parser) header(for_stmt bool) (init, cond, post *Node) {
+func (p *parser) header(for_stmt bool) (init, cond, post *ir.Node) {
 	if p.tok == '{' {
 		return
 	}
@@ -976,7 +1020,7 @@ func (p *parser) header(for_stmt bool) (init, cond, post *Node) {
 		}
 		init = p.simple_stmt(false, for_stmt)
 		// If we have a range clause, we are done.
-		if for_stmt && init.Op == ORANGE {
+		if for_stmt && init.Op == ir.ORANGE {
 			cond = init
 			init = nil
 
@@ -1005,13 +1049,13 @@ func (p *parser) header(for_stmt bool) (init, cond, post *Node) {
 	return
 }
 
-func (p *parser) if_header() *Node {
+func (p *parser) if_header() *ir.Node {
 	if trace && Debug['x'] != 0 {
 		defer p.trace("if_header")()
 	}
 
 	init, cond, _ := p.header(false)
-	h := Nod(OIF, nil, nil)
+	h := Nod(ir.OIF, nil, nil)
 	if init != nil {
 		h.Ninit.Set1(init)
 	}
@@ -1020,7 +1064,7 @@ func (p *parser) if_header() *Node {
 }
 
 // IfStmt = "if" [ SimpleStmt ";" ] Expression Block [ "else" ( IfStmt | Block ) ] .
-func (p *parser) if_stmt() *Node {
+func (p *parser) if_stmt() *ir.Node {
 	if trace && Debug['x'] != 0 {
 		defer p.trace("if_stmt")()
 	}
@@ -1042,7 +1086,7 @@ func (p *parser) if_stmt() *Node {
 			stmt.Rlist.Set1(p.if_stmt())
 		case '{':
 			cs := p.compound_stmt()
-			if cs.Op == OBLOCK && cs.Ninit.Len() == 0 {
+			if cs.Op == ir.OBLOCK && cs.Ninit.Len() == 0 {
 				stmt.Rlist.Set(cs.List.Slice())
 			} else {
 				stmt.Rlist.Set1(cs)
@@ -1062,7 +1106,7 @@ func (p *parser) if_stmt() *Node {
 // SwitchStmt     = ExprSwitchStmt | TypeSwitchStmt .
 // ExprSwitchStmt = "switch" [ SimpleStmt ";" ] [ Expression ] "{" { ExprCaseClause } "}" .
 // TypeSwitchStmt = "switch" [ SimpleStmt ";" ] TypeSwitchGuard "{" { TypeCaseClause } "}" .
-func (p *parser) switch_stmt() *Node {
+func (p *parser) switch_stmt() *ir.Node {
 	if trace && Debug['x'] != 0 {
 		defer p.trace("switch_stmt")()
 	}
@@ -1071,10 +1115,10 @@ func (p *parser) switch_stmt() *Node {
 	markdcl()
 
 	hdr := p.if_header()
-	hdr.Op = OSWITCH
+	hdr.Op = ir.OSWITCH
 
 	tswitch := hdr.Left
-	if tswitch != nil && tswitch.Op != OTYPESW {
+	if tswitch != nil && tswitch.Op != ir.OTYPESW {
 		tswitch = nil
 	}
 
@@ -1085,19 +1129,19 @@ func (p *parser) switch_stmt() *Node {
 }
 
 // SelectStmt = "select" "{" { CommClause } "}" .
-func (p *parser) select_stmt() *Node {
+func (p *parser) select_stmt() *ir.Node {
 	if trace && Debug['x'] != 0 {
 		defer p.trace("select_stmt")()
 	}
 
 	p.want(LSELECT)
-	hdr := Nod(OSELECT, nil, nil)
+	hdr := Nod(ir.OSELECT, nil, nil)
 	hdr.List.Set(p.caseblock_list(nil))
 	return hdr
 }
 
 // Expression = UnaryExpr | Expression binary_op Expression .
-func (p *parser) bexpr(prec OpPrec) *Node {
+func (p *parser) bexpr(prec OpPrec) *ir.Node {
 	// don't trace bexpr - only leads to overly nested trace output
 
 	// prec is precedence of the prior/enclosing binary operator (if any),
@@ -1106,13 +1150,15 @@ func (p *parser) bexpr(prec OpPrec) *Node {
 	x := p.uexpr()
 	for p.prec > prec {
 		op, prec1 := p.op, p.prec
+		line := lineno // position the expression at its operator
 		p.next()
 		x = Nod(op, x, p.bexpr(prec1))
+		x.Lineno = line
 	}
 	return x
 }
 
-func (p *parser) expr() *Node {
+func (p *parser) expr() *ir.Node {
 	if trace && Debug['x'] != 0 {
 		defer p.trace("expr")()
 	}
@@ -1120,49 +1166,49 @@ func (p *parser) expr() *Node {
 	return p.bexpr(0)
 }
 
-func unparen(x *Node) *Node {
-	for x.Op == OPAREN {
+func unparen(x *ir.Node) *ir.Node {
+	for x.Op == ir.OPAREN {
 		x = x.Left
 	}
 	return x
 }
 
 // UnaryExpr = PrimaryExpr | unary_op UnaryExpr .
-func (p *parser) uexpr() *Node {
+func (p *parser) uexpr() *ir.Node {
 	if trace && Debug['x'] != 0 {
 		defer p.trace("uexpr")()
 	}
 
-	var op Op
+	var op ir.Op
 	switch p.tok {
 	case '*':
-		op = OIND
+		op = ir.OIND
 
 	case '&':
 		p.next()
 		// uexpr may have returned a parenthesized composite literal
 		// (see comment in operand) - remove parentheses if any
 		x := unparen(p.uexpr())
-		if x.Op == OCOMPLIT {
+		if x.Op == ir.OCOMPLIT {
 			// Special case for &T{...}: turn into (*T){...}.
-			x.Right = Nod(OIND, x.Right, nil)
+			x.Right = Nod(ir.OIND, x.Right, nil)
 			x.Right.Implicit = true
 		} else {
-			x = Nod(OADDR, x, nil)
+			x = Nod(ir.OADDR, x, nil)
 		}
 		return x
 
 	case '+':
-		op = OPLUS
+		op = ir.OPLUS
 
 	case '-':
-		op = OMINUS
+		op = ir.OMINUS
 
 	case '!':
-		op = ONOT
+		op = ir.ONOT
 
 	case '^':
-		op = OCOM
+		op = ir.OCOM
 
 	case LCOMM:
 		// receive op (<-x) or receive-only channel (<-chan E)
@@ -1185,11 +1231,11 @@ func (p *parser) uexpr() *Node {
 		//   <-(chan E)   =>  (<-chan E)
 		//   <-(chan<-E)  =>  (<-chan (<-E))
 
-		if x.Op == OTCHAN {
+		if x.Op == ir.OTCHAN {
 			// x is a channel type => re-associate <-
-			dir := EType(Csend)
+			dir := ir.EType(Csend)
 			t := x
-			for ; t.Op == OTCHAN && dir == Csend; t = t.Left {
+			for ; t.Op == ir.OTCHAN && dir == Csend; t = t.Left {
 				dir = t.Etype
 				if dir == Crecv {
 					// t is type <-chan E but <-<-chan E is not permitted
@@ -1209,7 +1255,7 @@ func (p *parser) uexpr() *Node {
 		}
 
 		// x is not a channel type => we have a receive op
-		return Nod(ORECV, x, nil)
+		return Nod(ir.ORECV, x, nil)
 
 	default:
 		return p.pexpr(false)
@@ -1221,16 +1267,16 @@ func (p *parser) uexpr() *Node {
 }
 
 // pseudocall parses call-like statements that can be preceded by 'defer' and 'go'.
-func (p *parser) pseudocall() *Node {
+func (p *parser) pseudocall() *ir.Node {
 	if trace && Debug['x'] != 0 {
 		defer p.trace("pseudocall")()
 	}
 
 	x := p.pexpr(p.tok == '(') // keep_parens so we can report error below
 	switch x.Op {
-	case OCALL:
+	case ir.OCALL:
 		return x
-	case OPAREN:
+	case ir.OPAREN:
 		Yyerror("expression in go/defer must not be parenthesized")
 		// already progressed, no need to advance
 	default:
@@ -1244,7 +1290,7 @@ func (p *parser) pseudocall() *Node {
 // Literal     = BasicLit | CompositeLit | FunctionLit .
 // BasicLit    = int_lit | float_lit | imaginary_lit | rune_lit | string_lit .
 // OperandName = identifier | QualifiedIdent.
-func (p *parser) operand(keep_parens bool) *Node {
+func (p *parser) operand(keep_parens bool) *ir.Node {
 	if trace && Debug['x'] != 0 {
 		defer p.trace("operand")()
 	}
@@ -1271,7 +1317,7 @@ func (p *parser) operand(keep_parens bool) *Node {
 
 		// Parentheses are not permitted on lhs of := .
 		switch x.Op {
-		case ONAME, ONONAME, OPACK, OTYPE, OLITERAL, OTYPESW:
+		case ir.ONAME, ir.ONONAME, ir.OPACK, ir.OTYPE, ir.OLITERAL, ir.OTYPESW:
 			keep_parens = true
 		}
 
@@ -1287,7 +1333,7 @@ func (p *parser) operand(keep_parens bool) *Node {
 		// in a go/defer statement. In that case, operand is called
 		// with keep_parens set.
 		if keep_parens {
-			x = Nod(OPAREN, x, nil)
+			x = Nod(ir.OPAREN, x, nil)
 		}
 		return x
 
@@ -1345,7 +1391,7 @@ func (p *parser) operand(keep_parens bool) *Node {
 //                  "]" .
 // TypeAssertion  = "." "(" Type ")" .
 // Arguments      = "(" [ ( ExpressionList | Type [ "," ExpressionList ] ) [ "..." ] [ "," ] ] ")" .
-func (p *parser) pexpr(keep_parens bool) *Node {
+func (p *parser) pexpr(keep_parens bool) *ir.Node {
 	if trace && Debug['x'] != 0 {
 		defer p.trace("pexpr")()
 	}
@@ -1369,13 +1415,13 @@ loop:
 					// pexpr '.' '(' expr_or_type ')'
 					t := p.expr() // expr_or_type
 					p.want(')')
-					x = Nod(ODOTTYPE, x, t)
+					x = Nod(ir.ODOTTYPE, x, t)
 
 				case LTYPE:
 					// pexpr '.' '(' LTYPE ')'
 					p.next()
 					p.want(')')
-					x = Nod(OTYPESW, nil, x)
+					x = Nod(ir.OTYPESW, nil, x)
 				}
 
 			default:
@@ -1386,7 +1432,7 @@ loop:
 		case '[':
 			p.next()
 			p.xnest++
-			var index [3]*Node
+			var index [3]*ir.Node
 			if p.tok != ':' {
 				index[0] = p.expr()
 			}
@@ -1406,11 +1452,11 @@ loop:
 				if i == nil {
 					Yyerror("missing index in index expression")
 				}
-				x = Nod(OINDEX, x, i)
+				x = Nod(ir.OINDEX, x, i)
 			case 1:
 				i := index[0]
 				j := index[1]
-				x = Nod(OSLICE, x, Nod(OKEY, i, j))
+				x = Nod(ir.OSLICE, x, Nod(ir.OKEY, i, j))
 			case 2:
 				i := index[0]
 				j := index[1]
@@ -1421,7 +1467,7 @@ loop:
 				if k == nil {
 					Yyerror("final index required in 3-index slice")
 				}
-				x = Nod(OSLICE3, x, Nod(OKEY, i, Nod(OKEY, j, k)))
+				x = Nod(ir.OSLICE3, x, Nod(ir.OKEY, i, Nod(ir.OKEY, j, k)))
 
 			default:
 				panic("unreachable")
@@ -1432,7 +1478,7 @@ loop:
 			args, ddd := p.arg_list()
 
 			// call or conversion
-			x = Nod(OCALL, x, nil)
+			x = Nod(ir.OCALL, x, nil)
 			x.List.Set(args)
 			x.Isddd = ddd
 
@@ -1443,12 +1489,12 @@ loop:
 			// determine if '{' belongs to a complit or a compound_stmt
 			complit_ok := false
 			switch t.Op {
-			case ONAME, ONONAME, OTYPE, OPACK, OXDOT, ODOT:
+			case ir.ONAME, ir.ONONAME, ir.OTYPE, ir.OPACK, ir.OXDOT, ir.ODOT:
 				if p.xnest >= 0 {
 					// x is considered a comptype
 					complit_ok = true
 				}
-			case OTARRAY, OTSTRUCT, OTMAP:
+			case ir.OTARRAY, ir.OTSTRUCT, ir.OTMAP:
 				// x is a comptype
 				complit_ok = true
 			}
@@ -1472,7 +1518,7 @@ loop:
 }
 
 // KeyedElement = [ Key ":" ] Element .
-func (p *parser) keyval() *Node {
+func (p *parser) keyval() *ir.Node {
 	if trace && Debug['x'] != 0 {
 		defer p.trace("keyval")()
 	}
@@ -1482,30 +1528,33 @@ func (p *parser) keyval() *Node {
 	// Wrap values (but not keys!) that don't carry line
 	// numbers.
 
+	line := lineno
 	x := p.bare_complitexpr()
 
 	if p.got(':') {
 		// key ':' value
-		return Nod(OKEY, x, wrapname(p.bare_complitexpr()))
+		n := Nod(ir.OKEY, x, wrapname(p.bare_complitexpr()))
+		n.Lineno = line // errors about the element refer to its key
+		return n
 	}
 
 	// value
 	return wrapname(x)
 }
 
-func wrapname(x *Node) *Node {
+func wrapname(x *ir.Node) *ir.Node {
 	// These nodes do not carry line numbers.
 	// Introduce a wrapper node to give the correct line.
 	switch x.Op {
-	case ONAME, ONONAME, OTYPE, OPACK, OLITERAL:
-		x = Nod(OPAREN, x, nil)
+	case ir.ONAME, ir.ONONAME, ir.OTYPE, ir.OPACK, ir.OLITERAL:
+		x = Nod(ir.OPAREN, x, nil)
 		x.Implicit = true
 	}
 	return x
 }
 
 // Element = Expression | LiteralValue .
-func (p *parser) bare_complitexpr() *Node {
+func (p *parser) bare_complitexpr() *ir.Node {
 	if trace && Debug['x'] != 0 {
 		defer p.trace("bare_complitexpr")()
 	}
@@ -1519,18 +1568,18 @@ func (p *parser) bare_complitexpr() *Node {
 }
 
 // LiteralValue = "{" [ ElementList [ "," ] ] "}" .
-func (p *parser) complitexpr() *Node {
+func (p *parser) complitexpr() *ir.Node {
 	if trace && Debug['x'] != 0 {
 		defer p.trace("complitexpr")()
 	}
 
 	// make node early so we get the right line number
-	n := Nod(OCOMPLIT, nil, nil)
+	n := Nod(ir.OCOMPLIT, nil, nil)
 
 	p.want('{')
 	p.xnest++
 
-	var l []*Node
+	var l []*ir.Node
 	for p.tok != EOF && p.tok != '}' {
 		l = append(l, p.keyval())
 		if !p.ocomma('}') {
@@ -1548,7 +1597,7 @@ func (p *parser) complitexpr() *Node {
 // names and types
 //	newname is used before declared
 //	oldname is used after declared
-func (p *parser) new_name(sym *Sym) *Node {
+func (p *parser) new_name(sym *ir.Sym) *ir.Node {
 	if trace && Debug['x'] != 0 {
 		defer p.trace("new_name")()
 	}
@@ -1559,7 +1608,7 @@ func (p *parser) new_name(sym *Sym) *Node {
 	return nil
 }
 
-func (p *parser) dcl_name() *Node {
+func (p *parser) dcl_name() *ir.Node {
 	if trace && Debug['x'] != 0 {
 		defer p.trace("dcl_name")()
 	}
@@ -1573,7 +1622,7 @@ func (p *parser) dcl_name() *Node {
 	return dclname(sym)
 }
 
-func (p *parser) onew_name() *Node {
+func (p *parser) onew_name() *ir.Node {
 	if trace && Debug['x'] != 0 {
 		defer p.trace("onew_name")()
 	}
@@ -1585,7 +1634,7 @@ func (p *parser) onew_name() *Node {
 	return nil
 }
 
-func (p *parser) sym() *Sym {
+func (p *parser) sym() *ir.Sym {
 	switch p.tok {
 	case LNAME:
 		s := p.sym_
@@ -1606,11 +1655,11 @@ func (p *parser) sym() *Sym {
 	default:
 		p.syntax_error("expecting name")
 		p.advance()
-		return new(Sym)
+		return new(ir.Sym)
 	}
 }
 
-func mkname(sym *Sym) *Node {
+func mkname(sym *ir.Sym) *ir.Node {
 	n := oldname(sym)
 	if n.Name != nil && n.Name.Pack != nil {
 		n.Name.Pack.Used = true
@@ -1618,7 +1667,7 @@ func mkname(sym *Sym) *Node {
 	return n
 }
 
-func (p *parser) name() *Node {
+func (p *parser) name() *ir.Node {
 	if trace && Debug['x'] != 0 {
 		defer p.trace("name")()
 	}
@@ -1627,21 +1676,21 @@ func (p *parser) name() *Node {
 }
 
 // [ "..." ] Type
-func (p *parser) dotdotdot() *Node {
+func (p *parser) dotdotdot() *ir.Node {
 	if trace && Debug['x'] != 0 {
 		defer p.trace("dotdotdot")()
 	}
 
 	p.want(LDDD)
 	if typ := p.try_ntype(); typ != nil {
-		return Nod(ODDD, typ, nil)
+		return Nod(ir.ODDD, typ, nil)
 	}
 
 	Yyerror("final argument in variadic function missing type")
-	return Nod(ODDD, typenod(typ(TINTER)), nil)
+	return Nod(ir.ODDD, typenod(ir.Typ(ir.TINTER)), nil)
 }
 
-func (p *parser) ntype() *Node {
+func (p *parser) ntype() *ir.Node {
 	if trace && Debug['x'] != 0 {
 		defer p.trace("ntype")()
 	}
@@ -1658,21 +1707,21 @@ func (p *parser) ntype() *Node {
 // signature parses a function signature and returns an OTFUNC node.
 //
 // Signature = Parameters [ Result ] .
-func (p *parser) signature(recv *Node) *Node {
+func (p *parser) signature(recv *ir.Node) *ir.Node {
 	if trace && Debug['x'] != 0 {
 		defer p.trace("signature")()
 	}
 
 	params := p.param_list(true)
 
-	var result []*Node
+	var result []*ir.Node
 	if p.tok == '(' {
 		result = p.param_list(false)
 	} else if t := p.try_ntype(); t != nil {
-		result = []*Node{Nod(ODCLFIELD, nil, t)}
+		result = []*ir.Node{Nod(ir.ODCLFIELD, nil, t)}
 	}
 
-	typ := Nod(OTFUNC, recv, nil)
+	typ := Nod(ir.OTFUNC, recv, nil)
 	typ.List.Set(params)
 	typ.Rlist.Set(result)
 
@@ -1686,7 +1735,7 @@ func (p *parser) signature(recv *Node) *Node {
 // TypeName = identifier | QualifiedIdent .
 // TypeLit  = ArrayType | StructType | PointerType | FunctionType | InterfaceType |
 // 	      SliceType | MapType | ChannelType .
-func (p *parser) try_ntype() *Node {
+func (p *parser) try_ntype() *ir.Node {
 	if trace && Debug['x'] != 0 {
 		defer p.trace("try_ntype")()
 	}
@@ -1696,7 +1745,7 @@ func (p *parser) try_ntype() *Node {
 		// recvchantype
 		p.next()
 		p.want(LCHAN)
-		t := Nod(OTCHAN, p.chan_elem(), nil)
+		t := Nod(ir.OTCHAN, p.chan_elem(), nil)
 		t.Etype = Crecv
 		return t
 
@@ -1710,27 +1759,27 @@ func (p *parser) try_ntype() *Node {
 		// '[' LDDD ']' ntype
 		p.next()
 		p.xnest++
-		var len *Node
+		var len *ir.Node
 		if p.tok != ']' {
 			if p.got(LDDD) {
-				len = Nod(ODDD, nil, nil)
+				len = Nod(ir.ODDD, nil, nil)
 			} else {
 				len = p.expr()
 			}
 		}
 		p.xnest--
 		p.want(']')
-		return Nod(OTARRAY, len, p.ntype())
+		return Nod(ir.OTARRAY, len, p.ntype())
 
 	case LCHAN:
 		// LCHAN non_recvchantype
 		// LCHAN LCOMM ntype
 		p.next()
-		var dir EType = Cboth
+		var dir ir.EType = Cboth
 		if p.got(LCOMM) {
 			dir = Csend
 		}
-		t := Nod(OTCHAN, p.chan_elem(), nil)
+		t := Nod(ir.OTCHAN, p.chan_elem(), nil)
 		t.Etype = dir
 		return t
 
@@ -1741,7 +1790,7 @@ func (p *parser) try_ntype() *Node {
 		key := p.ntype()
 		p.want(']')
 		val := p.ntype()
-		return Nod(OTMAP, key, val)
+		return Nod(ir.OTMAP, key, val)
 
 	case LSTRUCT:
 		return p.structtype()
@@ -1752,7 +1801,7 @@ func (p *parser) try_ntype() *Node {
 	case '*':
 		// ptrtype
 		p.next()
-		return Nod(OIND, p.ntype(), nil)
+		return Nod(ir.OIND, p.ntype(), nil)
 
 	case LNAME, '@', '?':
 		return p.dotname()
@@ -1768,7 +1817,7 @@ func (p *parser) try_ntype() *Node {
 	}
 }
 
-func (p *parser) chan_elem() *Node {
+func (p *parser) chan_elem() *ir.Node {
 	if trace && Debug['x'] != 0 {
 		defer p.trace("chan_elem")()
 	}
@@ -1782,21 +1831,21 @@ func (p *parser) chan_elem() *Node {
 	return nil
 }
 
-func (p *parser) new_dotname(obj *Node) *Node {
+func (p *parser) new_dotname(obj *ir.Node) *ir.Node {
 	if trace && Debug['x'] != 0 {
 		defer p.trace("new_dotname")()
 	}
 
 	sel := p.sym()
-	if obj.Op == OPACK {
+	if obj.Op == ir.OPACK {
 		s := restrictlookup(sel.Name, obj.Name.Pkg)
 		obj.Used = true
 		return oldname(s)
 	}
-	return NodSym(OXDOT, obj, sel)
+	return NodSym(ir.OXDOT, obj, sel)
 }
 
-func (p *parser) dotname() *Node {
+func (p *parser) dotname() *ir.Node {
 	if trace && Debug['x'] != 0 {
 		defer p.trace("dotname")()
 	}
@@ -1809,14 +1858,14 @@ func (p *parser) dotname() *Node {
 }
 
 // StructType = "struct" "{" { FieldDecl ";" } "}" .
-func (p *parser) structtype() *Node {
+func (p *parser) structtype() *ir.Node {
 	if trace && Debug['x'] != 0 {
 		defer p.trace("structtype")()
 	}
 
 	p.want(LSTRUCT)
 	p.want('{')
-	var l []*Node
+	var l []*ir.Node
 	for p.tok != EOF && p.tok != '}' {
 		l = append(l, p.structdcl()...)
 		if !p.osemi('}') {
@@ -1825,20 +1874,20 @@ func (p *parser) structtype() *Node {
 	}
 	p.want('}')
 
-	t := Nod(OTSTRUCT, nil, nil)
+	t := Nod(ir.OTSTRUCT, nil, nil)
 	t.List.Set(l)
 	return t
 }
 
 // InterfaceType = "interface" "{" { MethodSpec ";" } "}" .
-func (p *parser) interfacetype() *Node {
+func (p *parser) interfacetype() *ir.Node {
 	if trace && Debug['x'] != 0 {
 		defer p.trace("interfacetype")()
 	}
 
 	p.want(LINTERFACE)
 	p.want('{')
-	var l []*Node
+	var l []*ir.Node
 	for p.tok != EOF && p.tok != '}' {
 		l = append(l, p.interfacedcl())
 		if !p.osemi('}') {
@@ -1847,7 +1896,7 @@ func (p *parser) interfacetype() *Node {
 	}
 	p.want('}')
 
-	t := Nod(OTINTER, nil, nil)
+	t := Nod(ir.OTINTER, nil, nil)
 	t.List.Set(l)
 	return t
 }
@@ -1855,13 +1904,13 @@ func (p *parser) interfacetype() *Node {
 // Function stuff.
 // All in one place to show how crappy it all is.
 
-func (p *parser) xfndcl() *Node {
+func (p *parser) xfndcl() *ir.Node {
 	if trace && Debug['x'] != 0 {
 		defer p.trace("xfndcl")()
 	}
 
 	p.want(LFUNC)
-	f := p.fndcl(p.pragma&Nointerface != 0)
+	f := p.fndcl(p.pragma&ir.Nointerface != 0)
 	body := p.fnbody()
 
 	if f == nil {
@@ -1869,7 +1918,7 @@ func (p *parser) xfndcl() *Node {
 	}
 
 	f.Nbody.Set(body)
-	f.Noescape = p.pragma&Noescape != 0
+	f.Noescape = p.pragma&ir.Noescape != 0
 	if f.Noescape && len(body) != 0 {
 		Yyerror("can only use //go:noescape with external func implementations")
 	}
@@ -1886,7 +1935,7 @@ func (p *parser) xfndcl() *Node {
 // Function     = Signature FunctionBody .
 // MethodDecl   = "func" Receiver MethodName ( Function | Signature ) .
 // Receiver     = Parameters .
-func (p *parser) fndcl(nointerface bool) *Node {
+func (p *parser) fndcl(nointerface bool) *ir.Node {
 	if trace && Debug['x'] != 0 {
 		defer p.trace("fndcl")()
 	}
@@ -1910,11 +1959,11 @@ func (p *parser) fndcl(nointerface bool) *Node {
 			}
 		}
 
-		f := Nod(ODCLFUNC, nil, nil)
+		f := Nod(ir.ODCLFUNC, nil, nil)
 		f.Func.Nname = newfuncname(name)
 		f.Func.Nname.Name.Defn = f
 		f.Func.Nname.Name.Param.Ntype = t // TODO: check if nname already has an ntype
-		declare(f.Func.Nname, PFUNC)
+		declare(f.Func.Nname, ir.PFUNC)
 
 		funchdr(f)
 		return f
@@ -1922,7 +1971,7 @@ func (p *parser) fndcl(nointerface bool) *Node {
 	case '(':
 		// Receiver MethodName Signature
 		rparam := p.param_list(false)
-		var recv *Node
+		var recv *ir.Node
 		if len(rparam) > 0 {
 			recv = rparam[0]
 		}
@@ -1940,18 +1989,18 @@ func (p *parser) fndcl(nointerface bool) *Node {
 			return nil
 		}
 
-		if recv.Op != ODCLFIELD {
+		if recv.Op != ir.ODCLFIELD {
 			Yyerror("bad receiver in method")
 			return nil
 		}
 
-		f := Nod(ODCLFUNC, nil, nil)
+		f := Nod(ir.ODCLFUNC, nil, nil)
 		f.Func.Shortname = newfuncname(name)
 		f.Func.Nname = methodname1(f.Func.Shortname, recv.Right)
 		f.Func.Nname.Name.Defn = f
 		f.Func.Nname.Name.Param.Ntype = t
 		f.Func.Nname.Nointerface = nointerface
-		declare(f.Func.Nname, PFUNC)
+		declare(f.Func.Nname, ir.PFUNC)
 
 		funchdr(f)
 		return f
@@ -1963,7 +2012,7 @@ func (p *parser) fndcl(nointerface bool) *Node {
 	}
 }
 
-func (p *parser) hidden_fndcl() *Node {
+func (p *parser) hidden_fndcl() *ir.Node {
 	if trace && Debug['x'] != 0 {
 		defer p.trace("hidden_fndcl")()
 	}
@@ -1980,10 +2029,10 @@ func (p *parser) hidden_fndcl() *Node {
 		s := s1
 		t := functype(nil, s3, s5)
 
-		importsym(s, ONAME)
-		if s.Def != nil && s.Def.Op == ONAME {
+		importsym(s, ir.ONAME)
+		if s.Def != nil && s.Def.Op == ir.ONAME {
 			if Eqtype(t, s.Def.Type) {
-				dclcontext = PDISCARD // since we skip funchdr below
+				dclcontext = ir.PDISCARD // since we skip funchdr below
 				return nil
 			}
 			Yyerror("inconsistent definition for func %v during import\n\t%v\n\t%v", s, s.Def.Type, t)
@@ -1991,7 +2040,7 @@ func (p *parser) hidden_fndcl() *Node {
 
 		ss := newfuncname(s)
 		ss.Type = t
-		declare(ss, PFUNC)
+		declare(ss, ir.PFUNC)
 
 		funchdr(ss)
 		return ss
@@ -2024,7 +2073,7 @@ func (p *parser) hidden_fndcl() *Node {
 }
 
 // FunctionBody = Block .
-func (p *parser) fnbody() []*Node {
+func (p *parser) fnbody() []*ir.Node {
 	if trace && Debug['x'] != 0 {
 		defer p.trace("fnbody")()
 	}
@@ -2035,7 +2084,7 @@ func (p *parser) fnbody() []*Node {
 		p.fnest--
 		p.want('}')
 		if body == nil {
-			body = []*Node{Nod(OEMPTY, nil, nil)}
+			body = []*ir.Node{Nod(ir.OEMPTY, nil, nil)}
 		}
 		return body
 	}
@@ -2045,7 +2094,7 @@ func (p *parser) fnbody() []*Node {
 
 // Declaration  = ConstDecl | TypeDecl | VarDecl .
 // TopLevelDecl = Declaration | FunctionDecl | MethodDecl .
-func (p *parser) xdcl_list() (l []*Node) {
+func (p *parser) xdcl_list() (l []*ir.Node) {
 	if trace && Debug['x'] != 0 {
 		defer p.trace("xdcl_list")()
 	}
@@ -2059,7 +2108,7 @@ func (p *parser) xdcl_list() (l []*Node) {
 			l = append(l, p.xfndcl())
 
 		default:
-			if p.tok == '{' && len(l) != 0 && l[len(l)-1].Op == ODCLFUNC && l[len(l)-1].Nbody.Len() == 0 {
+			if p.tok == '{' && len(l) != 0 && l[len(l)-1].Op == ir.ODCLFUNC && l[len(l)-1].Nbody.Len() == 0 {
 				// opening { of function declaration on next line
 				p.syntax_error("unexpected semicolon or newline before {")
 			} else {
@@ -2088,12 +2137,12 @@ func (p *parser) xdcl_list() (l []*Node) {
 // FieldDecl      = (IdentifierList Type | AnonymousField) [ Tag ] .
 // AnonymousField = [ "*" ] TypeName .
 // Tag            = string_lit .
-func (p *parser) structdcl() []*Node {
+func (p *parser) structdcl() []*ir.Node {
 	if trace && Debug['x'] != 0 {
 		defer p.trace("structdcl")()
 	}
 
-	var sym *Sym
+	var sym *ir.Sym
 	switch p.tok {
 	case LNAME:
 		sym = p.sym_
@@ -2107,7 +2156,7 @@ func (p *parser) structdcl() []*Node {
 			tag := p.oliteral()
 
 			field.SetVal(tag)
-			return []*Node{field}
+			return []*ir.Node{field}
 		}
 
 		// LNAME belongs to first *Sym of new_name_list
@@ -2130,17 +2179,17 @@ func (p *parser) structdcl() []*Node {
 		if len(fields) == 0 || fields[0].Sym.Name == "?" {
 			// ? symbol, during import
 			n := typ
-			if n.Op == OIND {
+			if n.Op == ir.OIND {
 				n = n.Left
 			}
 			n = embedded(n.Sym, importpkg)
 			n.Right = typ
 			n.SetVal(tag)
-			return []*Node{n}
+			return []*ir.Node{n}
 		}
 
 		for i, n := range fields {
-			fields[i] = Nod(ODCLFIELD, n, typ)
+			fields[i] = Nod(ir.ODCLFIELD, n, typ)
 			fields[i].SetVal(tag)
 		}
 		return fields
@@ -2153,10 +2202,10 @@ func (p *parser) structdcl() []*Node {
 			p.want(')')
 			tag := p.oliteral()
 
-			field.Right = Nod(OIND, field.Right, nil)
+			field.Right = Nod(ir.OIND, field.Right, nil)
 			field.SetVal(tag)
 			Yyerror("cannot parenthesize embedded type")
-			return []*Node{field}
+			return []*ir.Node{field}
 
 		} else {
 			// '(' embed ')' oliteral
@@ -2166,7 +2215,7 @@ func (p *parser) structdcl() []*Node {
 
 			field.SetVal(tag)
 			Yyerror("cannot parenthesize embedded type")
-			return []*Node{field}
+			return []*ir.Node{field}
 		}
 
 	case '*':
@@ -2177,19 +2226,19 @@ func (p *parser) structdcl() []*Node {
 			p.want(')')
 			tag := p.oliteral()
 
-			field.Right = Nod(OIND, field.Right, nil)
+			field.Right = Nod(ir.OIND, field.Right, nil)
 			field.SetVal(tag)
 			Yyerror("cannot parenthesize embedded type")
-			return []*Node{field}
+			return []*ir.Node{field}
 
 		} else {
 			// '*' embed oliteral
 			field := p.embed(nil)
 			tag := p.oliteral()
 
-			field.Right = Nod(OIND, field.Right, nil)
+			field.Right = Nod(ir.OIND, field.Right, nil)
 			field.SetVal(tag)
-			return []*Node{field}
+			return []*ir.Node{field}
 		}
 
 	default:
@@ -2199,7 +2248,7 @@ func (p *parser) structdcl() []*Node {
 	}
 }
 
-func (p *parser) oliteral() (v Val) {
+func (p *parser) oliteral() (v ir.Val) {
 	if p.tok == LLITERAL {
 		v = p.val
 		p.next()
@@ -2207,7 +2256,7 @@ func (p *parser) oliteral() (v Val) {
 	return
 }
 
-func (p *parser) packname(name *Sym) *Sym {
+func (p *parser) packname(name *ir.Sym) *ir.Sym {
 	if trace && Debug['x'] != 0 {
 		defer p.trace("embed")()
 	}
@@ -2220,15 +2269,15 @@ func (p *parser) packname(name *Sym) *Sym {
 	} else {
 		p.syntax_error("expecting name")
 		p.advance('.', ';', '}')
-		name = new(Sym)
+		name = new(ir.Sym)
 	}
 
 	if p.got('.') {
 		// LNAME '.' sym
 		s := p.sym()
 
-		var pkg *Pkg
-		if name.Def == nil || name.Def.Op != OPACK {
+		var pkg *ir.Pkg
+		if name.Def == nil || name.Def.Op != ir.OPACK {
 			Yyerror("%v is not a package", name)
 			pkg = localpkg
 		} else {
@@ -2245,7 +2294,7 @@ func (p *parser) packname(name *Sym) *Sym {
 	return name
 }
 
-func (p *parser) embed(sym *Sym) *Node {
+func (p *parser) embed(sym *ir.Sym) *ir.Node {
 	if trace && Debug['x'] != 0 {
 		defer p.trace("embed")()
 	}
@@ -2257,7 +2306,7 @@ func (p *parser) embed(sym *Sym) *Node {
 // MethodSpec        = MethodName Signature | InterfaceTypeName .
 // MethodName        = identifier .
 // InterfaceTypeName = TypeName .
-func (p *parser) interfacedcl() *Node {
+func (p *parser) interfacedcl() *ir.Node {
 	if trace && Debug['x'] != 0 {
 		defer p.trace("interfacedcl")()
 	}
@@ -2281,14 +2330,14 @@ func (p *parser) interfacedcl() *Node {
 		if p.tok != '(' {
 			// packname
 			pname := p.packname(sym)
-			return Nod(ODCLFIELD, nil, oldname(pname))
+			return Nod(ir.ODCLFIELD, nil, oldname(pname))
 		}
 
 		// MethodName Signature
 		mname := newname(sym)
 		sig := p.signature(fakethis())
 
-		meth := Nod(ODCLFIELD, mname, sig)
+		meth := Nod(ir.ODCLFIELD, mname, sig)
 		ifacedcl(meth)
 		return meth
 
@@ -2307,7 +2356,7 @@ func (p *parser) interfacedcl() *Node {
 		mname := newname(p.sym())
 		sig := p.signature(fakethis())
 
-		meth := Nod(ODCLFIELD, mname, sig)
+		meth := Nod(ir.ODCLFIELD, mname, sig)
 		ifacedcl(meth)
 		return meth
 
@@ -2315,7 +2364,7 @@ func (p *parser) interfacedcl() *Node {
 		p.next()
 		pname := p.packname(nil)
 		p.want(')')
-		n := Nod(ODCLFIELD, nil, oldname(pname))
+		n := Nod(ir.ODCLFIELD, nil, oldname(pname))
 		Yyerror("cannot parenthesize embedded type")
 		return n
 
@@ -2332,7 +2381,7 @@ func (p *parser) interfacedcl() *Node {
 // a type name. The result is (nil, nil) in case of a syntax error.
 //
 // [ParameterName] Type
-func (p *parser) param() (name *Sym, typ *Node) {
+func (p *parser) param() (name *ir.Sym, typ *ir.Node) {
 	if trace && Debug['x'] != 0 {
 		defer p.trace("param")()
 	}
@@ -2377,14 +2426,14 @@ func (p *parser) param() (name *Sym, typ *Node) {
 // Parameters    = "(" [ ParameterList [ "," ] ] ")" .
 // ParameterList = ParameterDecl { "," ParameterDecl } .
 // ParameterDecl = [ IdentifierList ] [ "..." ] Type .
-func (p *parser) param_list(dddOk bool) []*Node {
+func (p *parser) param_list(dddOk bool) []*ir.Node {
 	if trace && Debug['x'] != 0 {
 		defer p.trace("param_list")()
 	}
 
 	type param struct {
-		name *Sym
-		typ  *Node
+		name *ir.Sym
+		typ  *ir.Node
 	}
 	var params []param
 	var named int // number of parameters that have a name and type
@@ -2420,7 +2469,7 @@ func (p *parser) param_list(dddOk bool) []*Node {
 		// Some parameter list entries have both a name and a type:
 		// Distribute types backwards and check that there are no
 		// mixed named and unnamed parameters.
-		var T *Node // type T in a parameter sequence: a, b, c T
+		var T *ir.Node // type T in a parameter sequence: a, b, c T
 		for i := len(params) - 1; i >= 0; i-- {
 			p := &params[i]
 			if t := p.typ; t != nil {
@@ -2449,10 +2498,10 @@ func (p *parser) param_list(dddOk bool) []*Node {
 	}
 
 	// create final parameter list
-	list := make([]*Node, len(params))
+	list := make([]*ir.Node, len(params))
 	for i, p := range params {
 		// create dcl node
-		var name, typ *Node
+		var name, typ *ir.Node
 		if p.typ != nil {
 			typ = p.typ
 			if p.name != nil {
@@ -2463,16 +2512,16 @@ func (p *parser) param_list(dddOk bool) []*Node {
 			// p.name must be a type name (or nil in case of syntax error)
 			typ = mkname(p.name)
 		}
-		n := Nod(ODCLFIELD, name, typ)
+		n := Nod(ir.ODCLFIELD, name, typ)
 
 		// rewrite ...T parameter
-		if typ != nil && typ.Op == ODDD {
+		if typ != nil && typ.Op == ir.ODDD {
 			if !dddOk {
 				Yyerror("cannot use ... in receiver or result parameter list")
 			} else if i+1 < len(params) {
 				Yyerror("can only use ... with final parameter in list")
 			}
-			typ.Op = OTARRAY
+			typ.Op = ir.OTARRAY
 			typ.Right = typ.Left
 			typ.Left = nil
 			n.Isddd = true
@@ -2487,7 +2536,7 @@ func (p *parser) param_list(dddOk bool) []*Node {
 	return list
 }
 
-var missing_stmt = Nod(OXXX, nil, nil)
+var missing_stmt = Nod(ir.OXXX, nil, nil)
 
 // Statement =
 // 	Declaration | LabeledStmt | SimpleStmt |
@@ -2496,7 +2545,7 @@ var missing_stmt = Nod(OXXX, nil, nil)
 // 	DeferStmt .
 //
 // stmt may return missing_stmt.
-func (p *parser) stmt() *Node {
+func (p *parser) stmt() *ir.Node {
 	if trace && Debug['x'] != 0 {
 		defer p.trace("stmt")()
 	}
@@ -2528,47 +2577,47 @@ func (p *parser) stmt() *Node {
 	case LFALL:
 		p.next()
 		// will be converted to OFALL
-		stmt := Nod(OXFALL, nil, nil)
+		stmt := Nod(ir.OXFALL, nil, nil)
 		stmt.Xoffset = int64(block)
 		return stmt
 
 	case LBREAK:
 		p.next()
-		return Nod(OBREAK, p.onew_name(), nil)
+		return Nod(ir.OBREAK, p.onew_name(), nil)
 
 	case LCONTINUE:
 		p.next()
-		return Nod(OCONTINUE, p.onew_name(), nil)
+		return Nod(ir.OCONTINUE, p.onew_name(), nil)
 
 	case LGO:
 		p.next()
-		return Nod(OPROC, p.pseudocall(), nil)
+		return Nod(ir.OPROC, p.pseudocall(), nil)
 
 	case LDEFER:
 		p.next()
-		return Nod(ODEFER, p.pseudocall(), nil)
+		return Nod(ir.ODEFER, p.pseudocall(), nil)
 
 	case LGOTO:
 		p.next()
-		stmt := Nod(OGOTO, p.new_name(p.sym()), nil)
+		stmt := Nod(ir.OGOTO, p.new_name(p.sym()), nil)
 		stmt.Sym = dclstack // context, for goto restrictions
 		return stmt
 
 	case LRETURN:
 		p.next()
-		var results []*Node
+		var results []*ir.Node
 		if p.tok != ';' && p.tok != '}' {
 			results = p.expr_list()
 		}
 
-		stmt := Nod(ORETURN, nil, nil)
+		stmt := Nod(ir.ORETURN, nil, nil)
 		stmt.List.Set(results)
 		if stmt.List.Len() == 0 && Curfn != nil {
 			for _, ln := range Curfn.Func.Dcl {
-				if ln.Class == PPARAM {
+				if ln.Class == ir.PPARAM {
 					continue
 				}
-				if ln.Class != PPARAMOUT {
+				if ln.Class != ir.PPARAMOUT {
 					break
 				}
 				if ln.Sym.Def != ln {
@@ -2588,7 +2637,7 @@ func (p *parser) stmt() *Node {
 }
 
 // StatementList = { Statement ";" } .
-func (p *parser) stmt_list() (l []*Node) {
+func (p *parser) stmt_list() (l []*ir.Node) {
 	if trace && Debug['x'] != 0 {
 		defer p.trace("stmt_list")()
 	}
@@ -2599,7 +2648,7 @@ func (p *parser) stmt_list() (l []*Node) {
 			break
 		}
 		if s == nil {
-		} else if s.Op == OBLOCK && s.Ninit.Len() == 0 {
+		} else if s.Op == ir.OBLOCK && s.Ninit.Len() == 0 {
 			l = append(l, s.List.Slice()...)
 		} else {
 			l = append(l, s)
@@ -2620,7 +2669,7 @@ func (p *parser) stmt_list() (l []*Node) {
 // IdentifierList = identifier { "," identifier } .
 //
 // If first != nil we have the first symbol already.
-func (p *parser) new_name_list(first *Sym) []*Node {
+func (p *parser) new_name_list(first *ir.Sym) []*ir.Node {
 	if trace && Debug['x'] != 0 {
 		defer p.trace("new_name_list")()
 	}
@@ -2628,7 +2677,7 @@ func (p *parser) new_name_list(first *Sym) []*Node {
 	if first == nil {
 		first = p.sym() // may still be nil
 	}
-	var l []*Node
+	var l []*ir.Node
 	n := p.new_name(first)
 	if n != nil {
 		l = append(l, n)
@@ -2643,12 +2692,12 @@ func (p *parser) new_name_list(first *Sym) []*Node {
 }
 
 // IdentifierList = identifier { "," identifier } .
-func (p *parser) dcl_name_list() []*Node {
+func (p *parser) dcl_name_list() []*ir.Node {
 	if trace && Debug['x'] != 0 {
 		defer p.trace("dcl_name_list")()
 	}
 
-	s := []*Node{p.dcl_name()}
+	s := []*ir.Node{p.dcl_name()}
 	for p.got(',') {
 		s = append(s, p.dcl_name())
 	}
@@ -2656,12 +2705,12 @@ func (p *parser) dcl_name_list() []*Node {
 }
 
 // ExpressionList = Expression { "," Expression } .
-func (p *parser) expr_list() []*Node {
+func (p *parser) expr_list() []*ir.Node {
 	if trace && Debug['x'] != 0 {
 		defer p.trace("expr_list")()
 	}
 
-	l := []*Node{p.expr()}
+	l := []*ir.Node{p.expr()}
 	for p.got(',') {
 		l = append(l, p.expr())
 	}
@@ -2669,7 +2718,7 @@ func (p *parser) expr_list() []*Node {
 }
 
 // Arguments = "(" [ ( ExpressionList | Type [ "," ExpressionList ] ) [ "..." ] [ "," ] ] ")" .
-func (p *parser) arg_list() (l []*Node, ddd bool) {
+func (p *parser) arg_list() (l []*ir.Node, ddd bool) {
 	if trace && Debug['x'] != 0 {
 		defer p.trace("arg_list")()
 	}
@@ -2738,13 +2787,13 @@ func (p *parser) import_error() {
 // variable names. However, we will be able to delete them once we enable the
 // new export format by default, so it's not worth the effort (issue 13241).
 
-func (p *parser) hidden_importsym() *Sym {
+func (p *parser) hidden_importsym() *ir.Sym {
 	if trace && Debug['x'] != 0 {
 		defer p.trace("hidden_importsym")()
 	}
 
 	p.want('@')
-	var s2 Val
+	var s2 ir.Val
 	if p.tok == LLITERAL {
 		s2 = p.val
 		p.next()
@@ -2758,7 +2807,7 @@ func (p *parser) hidden_importsym() *Sym {
 		s4 := p.sym_
 		p.next()
 
-		var p *Pkg
+		var p *ir.Pkg
 
 		if s2.U.(string) == "" {
 			p = importpkg
@@ -2773,7 +2822,7 @@ func (p *parser) hidden_importsym() *Sym {
 	case '?':
 		p.next()
 
-		var p *Pkg
+		var p *ir.Pkg
 
 		if s2.U.(string) == "" {
 			p = importpkg
@@ -2791,36 +2840,36 @@ func (p *parser) hidden_importsym() *Sym {
 	}
 }
 
-func (p *parser) ohidden_funarg_list() []*Node {
+func (p *parser) ohidden_funarg_list() []*ir.Node {
 	if trace && Debug['x'] != 0 {
 		defer p.trace("ohidden_funarg_list")()
 	}
 
-	var ss []*Node
+	var ss []*ir.Node
 	if p.tok != ')' {
 		ss = p.hidden_funarg_list()
 	}
 	return ss
 }
 
-func (p *parser) ohidden_structdcl_list() []*Node {
+func (p *parser) ohidden_structdcl_list() []*ir.Node {
 	if trace && Debug['x'] != 0 {
 		defer p.trace("ohidden_structdcl_list")()
 	}
 
-	var ss []*Node
+	var ss []*ir.Node
 	if p.tok != '}' {
 		ss = p.hidden_structdcl_list()
 	}
 	return ss
 }
 
-func (p *parser) ohidden_interfacedcl_list() []*Node {
+func (p *parser) ohidden_interfacedcl_list() []*ir.Node {
 	if trace && Debug['x'] != 0 {
 		defer p.trace("ohidden_interfacedcl_list")()
 	}
 
-	var ss []*Node
+	var ss []*ir.Node
 	if p.tok != '}' {
 		ss = p.hidden_interfacedcl_list()
 	}
@@ -2837,14 +2886,14 @@ func (p *parser) hidden_import() {
 	case LIMPORT:
 		// LIMPORT LNAME LLITERAL ';'
 		p.next()
-		var s2 *Sym
+		var s2 *ir.Sym
 		if p.tok == LNAME {
 			s2 = p.sym_
 			p.next()
 		} else {
 			p.import_error()
 		}
-		var s3 Val
+		var s3 ir.Val
 		if p.tok == LLITERAL {
 			s3 = p.val
 			p.next()
@@ -2869,7 +2918,7 @@ func (p *parser) hidden_import() {
 		// LCONST hidden_pkg_importsym hidden_type '=' hidden_constant ';'
 		p.next()
 		s2 := p.hidden_pkg_importsym()
-		var s3 *Type = Types[TIDEAL]
+		var s3 *ir.Type = ir.Types[ir.TIDEAL]
 		if p.tok != '=' {
 			s3 = p.hidden_type()
 		}
@@ -2896,7 +2945,7 @@ func (p *parser) hidden_import() {
 		p.want(';')
 
 		if s2 == nil {
-			dclcontext = PEXTERN // since we skip the funcbody below
+			dclcontext = ir.PEXTERN // since we skip the funcbody below
 			return
 		}
 
@@ -2917,7 +2966,7 @@ func (p *parser) hidden_import() {
 	}
 }
 
-func (p *parser) hidden_pkg_importsym() *Sym {
+func (p *parser) hidden_pkg_importsym() *ir.Sym {
 	if trace && Debug['x'] != 0 {
 		defer p.trace("hidden_pkg_importsym")()
 	}
@@ -2927,7 +2976,7 @@ func (p *parser) hidden_pkg_importsym() *Sym {
 	return s
 }
 
-func (p *parser) hidden_pkgtype() *Type {
+func (p *parser) hidden_pkgtype() *ir.Type {
 	if trace && Debug['x'] != 0 {
 		defer p.trace("hidden_pkgtype")()
 	}
@@ -2935,7 +2984,7 @@ func (p *parser) hidden_pkgtype() *Type {
 	s1 := p.hidden_pkg_importsym()
 
 	ss := pkgtype(s1)
-	importsym(s1, OTYPE)
+	importsym(s1, ir.OTYPE)
 
 	return ss
 }
@@ -2943,7 +2992,7 @@ func (p *parser) hidden_pkgtype() *Type {
 // ----------------------------------------------------------------------------
 // Importing types
 
-func (p *parser) hidden_type() *Type {
+func (p *parser) hidden_type() *ir.Type {
 	if trace && Debug['x'] != 0 {
 		defer p.trace("hidden_type")()
 	}
@@ -2958,7 +3007,7 @@ func (p *parser) hidden_type() *Type {
 	}
 }
 
-func (p *parser) hidden_type_non_recv_chan() *Type {
+func (p *parser) hidden_type_non_recv_chan() *ir.Type {
 	if trace && Debug['x'] != 0 {
 		defer p.trace("hidden_type_non_recv_chan")()
 	}
@@ -2971,7 +3020,7 @@ func (p *parser) hidden_type_non_recv_chan() *Type {
 	}
 }
 
-func (p *parser) hidden_type_misc() *Type {
+func (p *parser) hidden_type_misc() *ir.Type {
 	if trace && Debug['x'] != 0 {
 		defer p.trace("hidden_type_misc")()
 	}
@@ -2989,7 +3038,7 @@ func (p *parser) hidden_type_misc() *Type {
 
 		// predefined name like uint8
 		s1 = Pkglookup(s1.Name, builtinpkg)
-		if s1.Def == nil || s1.Def.Op != OTYPE {
+		if s1.Def == nil || s1.Def.Op != ir.OTYPE {
 			Yyerror("%s is not a type", s1.Name)
 			return nil
 		} else {
@@ -3000,7 +3049,7 @@ func (p *parser) hidden_type_misc() *Type {
 		// '[' ']' hidden_type
 		// '[' LLITERAL ']' hidden_type
 		p.next()
-		var s2 *Node
+		var s2 *ir.Node
 		if p.tok == LLITERAL {
 			s2 = nodlit(p.val)
 			p.next()
@@ -3050,7 +3099,7 @@ func (p *parser) hidden_type_misc() *Type {
 		default:
 			// LCHAN hidden_type_non_recv_chan
 			s2 := p.hidden_type_non_recv_chan()
-			ss := typ(TCHAN)
+			ss := ir.Typ(ir.TCHAN)
 			ss.Type = s2
 			ss.Chan = Cboth
 			return ss
@@ -3060,7 +3109,7 @@ func (p *parser) hidden_type_misc() *Type {
 			p.next()
 			s3 := p.hidden_type_recv_chan()
 			p.want(')')
-			ss := typ(TCHAN)
+			ss := ir.Typ(ir.TCHAN)
 			ss.Type = s3
 			ss.Chan = Cboth
 			return ss
@@ -3069,7 +3118,7 @@ func (p *parser) hidden_type_misc() *Type {
 			// LCHAN hidden_type
 			p.next()
 			s3 := p.hidden_type()
-			ss := typ(TCHAN)
+			ss := ir.Typ(ir.TCHAN)
 			ss.Type = s3
 			ss.Chan = Csend
 			return ss
@@ -3081,7 +3130,7 @@ func (p *parser) hidden_type_misc() *Type {
 	}
 }
 
-func (p *parser) hidden_type_recv_chan() *Type {
+func (p *parser) hidden_type_recv_chan() *ir.Type {
 	if trace && Debug['x'] != 0 {
 		defer p.trace("hidden_type_recv_chan")()
 	}
@@ -3090,13 +3139,13 @@ func (p *parser) hidden_type_recv_chan() *Type {
 	p.want(LCHAN)
 	s3 := p.hidden_type()
 
-	ss := typ(TCHAN)
+	ss := ir.Typ(ir.TCHAN)
 	ss.Type = s3
 	ss.Chan = Crecv
 	return ss
 }
 
-func (p *parser) hidden_type_func() *Type {
+func (p *parser) hidden_type_func() *ir.Type {
 	if trace && Debug['x'] != 0 {
 		defer p.trace("hidden_type_func")()
 	}
@@ -3110,7 +3159,7 @@ func (p *parser) hidden_type_func() *Type {
 	return functype(nil, s3, s5)
 }
 
-func (p *parser) hidden_funarg() *Node {
+func (p *parser) hidden_funarg() *ir.Node {
 	if trace && Debug['x'] != 0 {
 		defer p.trace("hidden_funarg")()
 	}
@@ -3121,7 +3170,7 @@ func (p *parser) hidden_funarg() *Node {
 		s2 := p.hidden_type()
 		s3 := p.oliteral()
 
-		ss := Nod(ODCLFIELD, nil, typenod(s2))
+		ss := Nod(ir.ODCLFIELD, nil, typenod(s2))
 		if s1 != nil {
 			ss.Left = newname(s1)
 		}
@@ -3133,11 +3182,11 @@ func (p *parser) hidden_funarg() *Node {
 		s3 := p.hidden_type()
 		s4 := p.oliteral()
 
-		t := typ(TARRAY)
+		t := ir.Typ(ir.TARRAY)
 		t.Bound = -1
 		t.Type = s3
 
-		ss := Nod(ODCLFIELD, nil, typenod(t))
+		ss := Nod(ir.ODCLFIELD, nil, typenod(t))
 		if s1 != nil {
 			ss.Left = newname(s1)
 		}
@@ -3148,7 +3197,7 @@ func (p *parser) hidden_funarg() *Node {
 	}
 }
 
-func (p *parser) hidden_structdcl() *Node {
+func (p *parser) hidden_structdcl() *ir.Node {
 	if trace && Debug['x'] != 0 {
 		defer p.trace("hidden_structdcl")()
 	}
@@ -3157,9 +3206,9 @@ func (p *parser) hidden_structdcl() *Node {
 	s2 := p.hidden_type()
 	s3 := p.oliteral()
 
-	var ss *Node
+	var ss *ir.Node
 	if s1 != nil && s1.Name != "?" {
-		ss = Nod(ODCLFIELD, newname(s1), typenod(s2))
+		ss = Nod(ir.ODCLFIELD, newname(s1), typenod(s2))
 		ss.SetVal(s3)
 	} else {
 		s := s2.Sym
@@ -3178,7 +3227,7 @@ func (p *parser) hidden_structdcl() *Node {
 	return ss
 }
 
-func (p *parser) hidden_interfacedcl() *Node {
+func (p *parser) hidden_interfacedcl() *ir.Node {
 	if trace && Debug['x'] != 0 {
 		defer p.trace("hidden_interfacedcl")()
 	}
@@ -3208,10 +3257,10 @@ func (p *parser) hidden_interfacedcl() *Node {
 	p.want(')')
 	s5 := p.ohidden_funres()
 
-	return Nod(ODCLFIELD, newname(s1), typenod(functype(fakethis(), s3, s5)))
+	return Nod(ir.ODCLFIELD, newname(s1), typenod(functype(fakethis(), s3, s5)))
 }
 
-func (p *parser) ohidden_funres() []*Node {
+func (p *parser) ohidden_funres() []*ir.Node {
 	if trace && Debug['x'] != 0 {
 		defer p.trace("ohidden_funres")()
 	}
@@ -3225,7 +3274,7 @@ func (p *parser) ohidden_funres() []*Node {
 	}
 }
 
-func (p *parser) hidden_funres() []*Node {
+func (p *parser) hidden_funres() []*ir.Node {
 	if trace && Debug['x'] != 0 {
 		defer p.trace("hidden_funres")()
 	}
@@ -3239,14 +3288,14 @@ func (p *parser) hidden_funres() []*Node {
 
 	default:
 		s1 := p.hidden_type()
-		return []*Node{Nod(ODCLFIELD, nil, typenod(s1))}
+		return []*ir.Node{Nod(ir.ODCLFIELD, nil, typenod(s1))}
 	}
 }
 
 // ----------------------------------------------------------------------------
 // Importing constants
 
-func (p *parser) hidden_literal() *Node {
+func (p *parser) hidden_literal() *ir.Node {
 	if trace && Debug['x'] != 0 {
 		defer p.trace("hidden_literal")()
 	}
@@ -3263,15 +3312,15 @@ func (p *parser) hidden_literal() *Node {
 			ss := nodlit(p.val)
 			p.next()
 			switch ss.Val().Ctype() {
-			case CTINT, CTRUNE:
-				ss.Val().U.(*Mpint).Neg()
+			case ir.CTINT, ir.CTRUNE:
+				ss.Val().U.(*ir.Mpint).Neg()
 				break
-			case CTFLT:
-				ss.Val().U.(*Mpflt).Neg()
+			case ir.CTFLT:
+				ss.Val().U.(*ir.Mpflt).Neg()
 				break
-			case CTCPLX:
-				ss.Val().U.(*Mpcplx).Real.Neg()
-				ss.Val().U.(*Mpcplx).Imag.Neg()
+			case ir.CTCPLX:
+				ss.Val().U.(*ir.Mpcplx).Real.Neg()
+				ss.Val().U.(*ir.Mpcplx).Imag.Neg()
 				break
 			default:
 				Yyerror("bad negated constant")
@@ -3285,7 +3334,7 @@ func (p *parser) hidden_literal() *Node {
 	case LNAME, '@', '?':
 		s1 := p.sym()
 		ss := oldname(Pkglookup(s1.Name, builtinpkg))
-		if ss.Op != OLITERAL {
+		if ss.Op != ir.OLITERAL {
 			Yyerror("bad constant %v", ss.Sym)
 		}
 		return ss
@@ -3296,7 +3345,7 @@ func (p *parser) hidden_literal() *Node {
 	}
 }
 
-func (p *parser) hidden_constant() *Node {
+func (p *parser) hidden_constant() *ir.Node {
 	if trace && Debug['x'] != 0 {
 		defer p.trace("hidden_constant")()
 	}
@@ -3311,13 +3360,13 @@ func (p *parser) hidden_constant() *Node {
 		s4 := p.hidden_literal()
 		p.want(')')
 
-		if s2.Val().Ctype() == CTRUNE && s4.Val().Ctype() == CTINT {
+		if s2.Val().Ctype() == ir.CTRUNE && s4.Val().Ctype() == ir.CTINT {
 			ss := s2
-			s2.Val().U.(*Mpint).Add(s4.Val().U.(*Mpint))
+			s2.Val().U.(*ir.Mpint).Add(s4.Val().U.(*ir.Mpint))
 			return ss
 		}
-		s4.Val().U.(*Mpcplx).Real = s4.Val().U.(*Mpcplx).Imag
-		s4.Val().U.(*Mpcplx).Imag.SetFloat64(0.0)
+		s4.Val().U.(*ir.Mpcplx).Real = s4.Val().U.(*ir.Mpcplx).Imag
+		s4.Val().U.(*ir.Mpcplx).Imag.SetFloat64(0.0)
 		return nodcplxlit(s2.Val(), s4.Val())
 	}
 }
@@ -3332,13 +3381,13 @@ func (p *parser) hidden_import_list() {
 	}
 }
 
-func (p *parser) hidden_funarg_list() []*Node {
+func (p *parser) hidden_funarg_list() []*ir.Node {
 	if trace && Debug['x'] != 0 {
 		defer p.trace("hidden_funarg_list")()
 	}
 
 	s1 := p.hidden_funarg()
-	ss := []*Node{s1}
+	ss := []*ir.Node{s1}
 	for p.got(',') {
 		s3 := p.hidden_funarg()
 		ss = append(ss, s3)
@@ -3346,13 +3395,13 @@ func (p *parser) hidden_funarg_list() []*Node {
 	return ss
 }
 
-func (p *parser) hidden_structdcl_list() []*Node {
+func (p *parser) hidden_structdcl_list() []*ir.Node {
 	if trace && Debug['x'] != 0 {
 		defer p.trace("hidden_structdcl_list")()
 	}
 
 	s1 := p.hidden_structdcl()
-	ss := []*Node{s1}
+	ss := []*ir.Node{s1}
 	for p.got(';') {
 		s3 := p.hidden_structdcl()
 		ss = append(ss, s3)
@@ -3360,13 +3409,13 @@ func (p *parser) hidden_structdcl_list() []*Node {
 	return ss
 }
 
-func (p *parser) hidden_interfacedcl_list() []*Node {
+func (p *parser) hidden_interfacedcl_list() []*ir.Node {
 	if trace && Debug['x'] != 0 {
 		defer p.trace("hidden_interfacedcl_list")()
 	}
 
 	s1 := p.hidden_interfacedcl()
-	ss := []*Node{s1}
+	ss := []*ir.Node{s1}
 	for p.got(';') {
 		s3 := p.hidden_interfacedcl()
 		ss = append(ss, s3)
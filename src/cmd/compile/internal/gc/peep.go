@@ -0,0 +1,175 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Peephole optimizations shared by the back ends.
+//
+// The back ends run their own peep pass over the Flow graph built by
+// Flowstart, but the generic transformations live here and learn what
+// an instruction does through two Arch hooks:
+//
+//	Copyu(p, v, nil) reports how p uses the address v, as one of the
+//	Copy* constants below. Copyu(p, v, s) replaces reads of v in p
+//	with reads of s and returns 0 on success or non-zero on failure.
+//
+//	Copyas(a, v) reports whether a and v address the same register.
+//
+// Fixing a bug here, or adding a transformation, applies it to every
+// back end at once.
+
+package gc
+
+import (
+	"cmd/internal/obj"
+	"fmt"
+)
+
+// Results of Arch.Copyu when called with s == nil.
+const (
+	CopyNone   = 0 // v is not touched
+	CopyUse    = 1 // v is only used
+	CopyRar    = 2 // v is set and used in one address (read-alter-rewrite; can't substitute)
+	CopySet    = 3 // v is only set
+	CopyUseSet = 4 // v is set in one address and used in another (addresses can be rewritten independently)
+)
+
+// copyactive marks the Flows visited by the current copy1 walk.
+// It is reset by Flowstart for each new graph.
+var copyactive uint32
+
+// Excise removes the instruction at r from the program.
+func Excise(r *Flow) {
+	p := r.Prog
+	if Debug['P'] != 0 && Debug['v'] != 0 {
+		fmt.Printf("%v ===delete===\n", p)
+	}
+	obj.Nopout(p)
+	Ostats.Ndelmov++
+}
+
+// Copyprop tries to eliminate the move at r0 by replacing all later
+// uses of its destination with its source.
+// It reports whether the move can be removed.
+//
+// The idea is to remove redundant copies.
+//	v1->v2	F=0
+//	(use v2	s/v2/v1/)*
+//	set v1	F=1
+//	use v2	return fail (v1->v2 move must remain)
+//	-----------------
+//	v1->v2	F=0
+//	(use v2	s/v2/v1/)*
+//	set v1	F=1
+//	set v2	return success (caller can remove v1->v2 move)
+func Copyprop(r0 *Flow) bool {
+	p := r0.Prog
+	v1 := &p.From
+	v2 := &p.To
+	if Thearch.Copyas(v1, v2) {
+		if Debug['P'] != 0 {
+			fmt.Printf("eliminating self-move: %v\n", r0.Prog)
+		}
+		return true
+	}
+
+	copyactive++
+	if Debug['P'] != 0 {
+		fmt.Printf("trying to eliminate %v->%v move from:\n%v\n", Ctxt.Dconv(v1), Ctxt.Dconv(v2), r0.Prog)
+	}
+	return copy1(v1, v2, r0.S1, false)
+}
+
+// copy1 replaces uses of v2 with v1 starting at r and reports whether
+// all uses were rewritten.
+func copy1(v1 *obj.Addr, v2 *obj.Addr, r *Flow, f bool) bool {
+	if uint32(r.Active) == copyactive {
+		if Debug['P'] != 0 {
+			fmt.Printf("act set; return 1\n")
+		}
+		return true
+	}
+
+	r.Active = int32(copyactive)
+	if Debug['P'] != 0 {
+		fmt.Printf("copy1 replace %v with %v f=%v\n", Ctxt.Dconv(v2), Ctxt.Dconv(v1), f)
+	}
+	for ; r != nil; r = r.S1 {
+		p := r.Prog
+		if Debug['P'] != 0 {
+			fmt.Printf("%v", p)
+		}
+		if !f && Uniqp(r) == nil {
+			// Multiple predecessors; conservatively
+			// assume v1 was set on other path
+			f = true
+
+			if Debug['P'] != 0 {
+				fmt.Printf("; merge; f=%v", f)
+			}
+		}
+
+		switch t := Thearch.Copyu(p, v2, nil); t {
+		case CopyRar:
+			if Debug['P'] != 0 {
+				fmt.Printf("; %v rar; return 0\n", Ctxt.Dconv(v2))
+			}
+			return false
+
+		case CopySet:
+			if Debug['P'] != 0 {
+				fmt.Printf("; %v set; return 1\n", Ctxt.Dconv(v2))
+			}
+			return true
+
+		case CopyUse, CopyUseSet:
+			if f {
+				if Debug['P'] == 0 {
+					return false
+				}
+				if t == CopyUseSet {
+					fmt.Printf("; %v used+set and f=%v; return 0\n", Ctxt.Dconv(v2), f)
+				} else {
+					fmt.Printf("; %v used and f=%v; return 0\n", Ctxt.Dconv(v2), f)
+				}
+				return false
+			}
+
+			if Thearch.Copyu(p, v2, v1) != 0 {
+				if Debug['P'] != 0 {
+					fmt.Printf("; sub fail; return 0\n")
+				}
+				return false
+			}
+
+			if Debug['P'] != 0 {
+				fmt.Printf("; sub %v->%v\n => %v", Ctxt.Dconv(v2), Ctxt.Dconv(v1), p)
+			}
+			if t == CopyUseSet {
+				if Debug['P'] != 0 {
+					fmt.Printf("; %v used+set; return 1\n", Ctxt.Dconv(v2))
+				}
+				return true
+			}
+		}
+
+		if !f {
+			t := Thearch.Copyu(p, v1, nil)
+			if t == CopyRar || t == CopySet || t == CopyUseSet {
+				f = true
+				if Debug['P'] != 0 {
+					fmt.Printf("; %v set and !f; f=%v", Ctxt.Dconv(v1), f)
+				}
+			}
+		}
+
+		if Debug['P'] != 0 {
+			fmt.Printf("\n")
+		}
+		if r.S2 != nil {
+			if !copy1(v1, v2, r.S2, f) {
+				return false
+			}
+		}
+	}
+	return true
+}
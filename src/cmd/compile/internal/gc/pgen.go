@@ -5,26 +5,28 @@
 package gc
 
 import (
+	"cmd/compile/internal/ir"
 	"cmd/compile/internal/ssa"
 	"cmd/internal/obj"
 	"crypto/md5"
 	"fmt"
 	"sort"
 	"strings"
+	"sync"
 )
 
 // "Portable" code generation.
 
 var makefuncdatasym_nsym int
 
-func makefuncdatasym(nameprefix string, funcdatakind int64) *Sym {
-	var nod Node
+func makefuncdatasym(nameprefix string, funcdatakind int64) *ir.Sym {
+	var nod ir.Node
 
 	sym := LookupN(nameprefix, makefuncdatasym_nsym)
 	makefuncdatasym_nsym++
 	pnod := newname(sym)
-	pnod.Class = PEXTERN
-	Nodconst(&nod, Types[TINT32], funcdatakind)
+	pnod.Class = ir.PEXTERN
+	Nodconst(&nod, ir.Types[ir.TINT32], funcdatakind)
 	Thearch.Gins(obj.AFUNCDATA, &nod, pnod)
 	return sym
 }
@@ -85,17 +87,17 @@ func makefuncdatasym(nameprefix string, funcdatakind int64) *Sym {
 // that its argument is certainly dead, for use when the liveness analysis
 // would not otherwise be able to deduce that fact.
 
-func gvardefx(n *Node, as obj.As) {
+func gvardefx(n *ir.Node, as obj.As) {
 	if n == nil {
 		Fatalf("gvardef nil")
 	}
-	if n.Op != ONAME {
-		Yyerror("gvardef %v; %v", Oconv(n.Op, FmtSharp), n)
+	if n.Op != ir.ONAME {
+		Yyerror("gvardef %v; %v", Oconv(n.Op, ir.FmtSharp), n)
 		return
 	}
 
 	switch n.Class {
-	case PAUTO, PPARAM, PPARAMOUT:
+	case ir.PAUTO, ir.PPARAM, ir.PPARAMOUT:
 		if as == obj.AVARLIVE {
 			Thearch.Gins(as, n, nil)
 		} else {
@@ -104,18 +106,117 @@ func gvardefx(n *Node, as obj.As) {
 	}
 }
 
-func Gvardef(n *Node) {
+func Gvardef(n *ir.Node) {
 	gvardefx(n, obj.AVARDEF)
 }
 
-func Gvarkill(n *Node) {
+func Gvarkill(n *ir.Node) {
 	gvardefx(n, obj.AVARKILL)
 }
 
-func Gvarlive(n *Node) {
+func Gvarlive(n *ir.Node) {
 	gvardefx(n, obj.AVARLIVE)
 }
 
+// defframe fills in the argument and frame sizes of the TEXT
+// instruction ptxt and inserts code after it to zero the ambiguously
+// live variables, so that the garbage collector only sees initialized
+// values when it looks for pointers.
+func defframe(ptxt *obj.Prog) {
+	ptxt.To.Type = obj.TYPE_TEXTSIZE
+	ptxt.To.Val = int32(Rnd(Curfn.Type.Argwid, int64(Widthptr)))
+	frame := Rnd(Stksize+Maxarg, int64(Widthreg))
+	if Thearch.PadFrame != nil {
+		frame = Thearch.PadFrame(frame)
+	}
+	ptxt.To.Offset = frame
+
+	p := ptxt
+	var state uint32
+	hi := int64(0)
+	lo := hi
+
+	// iterate through declarations - they are sorted in decreasing xoffset order.
+	for _, n := range Curfn.Func.Dcl {
+		if !n.Name.Needzero {
+			continue
+		}
+		if n.Class != ir.PAUTO {
+			Fatalf("needzero class %d", n.Class)
+		}
+		if n.Type.Width%int64(Widthptr) != 0 || n.Xoffset%int64(Widthptr) != 0 || n.Type.Width == 0 {
+			Fatalf("var %v has size %d offset %d", Nconv(n, ir.FmtLong), int(n.Type.Width), int(n.Xoffset))
+		}
+
+		merge := n.Xoffset+n.Type.Width >= lo-int64(2*Widthreg)
+		if Thearch.Thechar == '8' {
+			// 386 merges only ranges exactly two words apart.
+			merge = n.Xoffset+n.Type.Width == lo-int64(2*Widthptr)
+		}
+		if lo != hi && merge {
+			// merge with range we already have
+			lo = n.Xoffset
+			continue
+		}
+
+		// zero old range
+		p = Thearch.ZeroRange(p, frame, lo, hi, &state)
+
+		// set new range
+		hi = n.Xoffset + n.Type.Width
+		lo = n.Xoffset
+	}
+
+	// zero final range
+	Thearch.ZeroRange(p, frame, lo, hi, &state)
+}
+
+// marknosplit marks the function starting at ptxt NOSPLIT if it is a
+// leaf with a small frame, so that it can run in the stack guard area
+// and needs no stack-growth prologue. It must run after defframe,
+// which fixes the frame size and may insert calls to duffzero.
+// Calls to the bounds and divide check panics do not count: they
+// never return and check for stack space themselves.
+//
+// The assemblers for some architectures insert calls of their own
+// (division and soft float on arm), and wrappers and functions that
+// must run on the system stack need their prologue, so those are
+// left alone.
+func marknosplit(ptxt *obj.Prog) {
+	if Thearch.Thechar == '5' || ptxt.From3.Offset&(obj.NOSPLIT|obj.WRAPPER) != 0 || ptxt.From.Sym == nil || ptxt.From.Sym.Cfunc != 0 {
+		return
+	}
+	// Leave room for a saved return address and frame pointer.
+	if ptxt.To.Offset+2*int64(Widthreg) >= obj.StackSmall {
+		return
+	}
+	for p := ptxt.Link; p != nil; p = p.Link {
+		switch p.As {
+		case obj.ACALL:
+			if !ispanicprog(p) {
+				return
+			}
+		case obj.ADUFFZERO, obj.ADUFFCOPY:
+			return
+		}
+	}
+	ptxt.From3.Offset |= obj.NOSPLIT
+}
+
+// ispanicprog reports whether p calls one of the runtime's
+// bounds or divide check failure functions.
+func ispanicprog(p *obj.Prog) bool {
+	if p.To.Sym == nil {
+		return false
+	}
+	for _, n := range []*ir.Node{Panicindex, panicslice, panicdivide} {
+		if n != nil && p.To.Sym == Linksym(n.Sym) {
+			return true
+		}
+	}
+	return false
+}
+
 func removevardef(firstp *obj.Prog) {
 	for p := firstp; p != nil; p = p.Link {
 		for p.Link != nil && (p.Link.As == obj.AVARDEF || p.Link.As == obj.AVARKILL || p.Link.As == obj.AVARLIVE) {
@@ -129,7 +230,7 @@ func removevardef(firstp *obj.Prog) {
 	}
 }
 
-func gcsymdup(s *Sym) {
+func gcsymdup(s *ir.Sym) {
 	ls := Linksym(s)
 	if len(ls.R) > 0 {
 		Fatalf("cannot rosymdup %s with relocations", ls.Name)
@@ -187,12 +288,12 @@ func emitptrargsmap() {
 // really means, in memory, things with pointers needing zeroing at
 // the top of the stack and increasing in size.
 // Non-autos sort on offset.
-func cmpstackvarlt(a, b *Node) bool {
-	if (a.Class == PAUTO) != (b.Class == PAUTO) {
-		return b.Class == PAUTO
+func cmpstackvarlt(a, b *ir.Node) bool {
+	if (a.Class == ir.PAUTO) != (b.Class == ir.PAUTO) {
+		return b.Class == ir.PAUTO
 	}
 
-	if a.Class != PAUTO {
+	if a.Class != ir.PAUTO {
 		return a.Xoffset < b.Xoffset
 	}
 
@@ -220,7 +321,7 @@ func cmpstackvarlt(a, b *Node) bool {
 }
 
 // byStackvar implements sort.Interface for []*Node using cmpstackvarlt.
-type byStackVar []*Node
+type byStackVar []*ir.Node
 
 func (s byStackVar) Len() int           { return len(s) }
 func (s byStackVar) Less(i, j int) bool { return cmpstackvarlt(s[i], s[j]) }
@@ -229,7 +330,7 @@ func (s byStackVar) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
 // stkdelta records the stack offset delta for a node
 // during the compaction of the stack frame to remove
 // unused stack slots.
-var stkdelta = map[*Node]int64{}
+var stkdelta = map[*ir.Node]int64{}
 
 // TODO(lvd) find out where the PAUTO/OLITERAL nodes come from.
 func allocauto(ptxt *obj.Prog) {
@@ -242,7 +343,7 @@ func allocauto(ptxt *obj.Prog) {
 
 	// Mark the PAUTO's unused.
 	for _, ln := range Curfn.Func.Dcl {
-		if ln.Class == PAUTO {
+		if ln.Class == ir.PAUTO {
 			ln.Used = false
 		}
 	}
@@ -253,7 +354,7 @@ func allocauto(ptxt *obj.Prog) {
 
 	// Unused autos are at the end, chop 'em off.
 	n := Curfn.Func.Dcl[0]
-	if n.Class == PAUTO && n.Op == ONAME && !n.Used {
+	if n.Class == ir.PAUTO && n.Op == ir.ONAME && !n.Used {
 		// No locals used at all
 		Curfn.Func.Dcl = nil
 
@@ -263,16 +364,22 @@ func allocauto(ptxt *obj.Prog) {
 
 	for i := 1; i < len(Curfn.Func.Dcl); i++ {
 		n = Curfn.Func.Dcl[i]
-		if n.Class == PAUTO && n.Op == ONAME && !n.Used {
+		if n.Class == ir.PAUTO && n.Op == ir.ONAME && !n.Used {
 			Curfn.Func.Dcl = Curfn.Func.Dcl[:i]
 			break
 		}
 	}
 
 	// Reassign stack offsets of the locals that are still there.
+	// Locals with disjoint lifetimes may share a slot.
+	merged := mergeautos(ptxt)
 	var w int64
 	for _, n := range Curfn.Func.Dcl {
-		if n.Class != PAUTO || n.Op != ONAME {
+		if n.Class != ir.PAUTO || n.Op != ir.ONAME {
+			continue
+		}
+		if m := merged[n]; m != nil {
+			stkdelta[n] = stkdelta[m] + m.Xoffset - n.Xoffset
 			continue
 		}
 
@@ -290,8 +397,8 @@ func allocauto(ptxt *obj.Prog) {
 			Stksize = Rnd(Stksize, int64(Widthptr))
 		}
 		if Stksize >= 1<<31 {
-			setlineno(Curfn)
-			Yyerror("stack frame too large (>2GB)")
+			tooLarge("stack frame too large (>2GB)")
+			break
 		}
 
 		stkdelta[n] = -Stksize - n.Xoffset
@@ -304,7 +411,7 @@ func allocauto(ptxt *obj.Prog) {
 
 	// The debug information needs accurate offsets on the symbols.
 	for _, ln := range Curfn.Func.Dcl {
-		if ln.Class != PAUTO || ln.Op != ONAME {
+		if ln.Class != ir.PAUTO || ln.Op != ir.ONAME {
 			continue
 		}
 		ln.Xoffset += stkdelta[ln]
@@ -312,20 +419,86 @@ func allocauto(ptxt *obj.Prog) {
 	}
 }
 
-func Cgen_checknil(n *Node) {
+// tooLarge reports that Curfn exceeds an implementation limit of the
+// compiler, described by why. With -d toolarge, it also lists the
+// largest local variables of the function.
+func tooLarge(why string) {
+	lno := setlineno(Curfn)
+	defer func() { lineno = lno }()
+
+	frame := Stksize + Maxarg
+	if Stksize == ir.BADWIDTH {
+		frame = Maxarg
+	}
+	hint := "; recompile with -d toolarge for details"
+	if Debug_toolarge != 0 {
+		hint = ""
+	}
+	Yyerror("function %v is too large (%d nodes / %d byte frame): %s%s", Curfn.Func.Nname.Sym, countnodes(Curfn), frame, why, hint)
+	if Debug_toolarge == 0 {
+		return
+	}
+
+	var autos []*ir.Node
+	for _, n := range Curfn.Func.Dcl {
+		if n.Op == ir.ONAME && n.Class == ir.PAUTO && n.Type != nil {
+			autos = append(autos, n)
+		}
+	}
+	sort.Sort(byWidth(autos))
+	if len(autos) > 10 {
+		autos = autos[:10]
+	}
+	for _, n := range autos {
+		Warnl(n.Lineno, "%v is %d bytes", n, n.Type.Width)
+	}
+}
+
+// byWidth sorts local variables by decreasing width, and then by position.
+type byWidth []*ir.Node
+
+func (s byWidth) Len() int { return len(s) }
+func (s byWidth) Less(i, j int) bool {
+	if s[i].Type.Width != s[j].Type.Width {
+		return s[i].Type.Width > s[j].Type.Width
+	}
+	return s[i].Lineno < s[j].Lineno
+}
+func (s byWidth) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
+
+// countnodes returns the number of nodes in fn's body.
+func countnodes(fn *ir.Node) int {
+	var count func(n *ir.Node) int
+	countlist := func(l ir.Nodes) int {
+		c := 0
+		for _, n := range l.Slice() {
+			c += count(n)
+		}
+		return c
+	}
+	count = func(n *ir.Node) int {
+		if n == nil {
+			return 0
+		}
+		return 1 + count(n.Left) + count(n.Right) + countlist(n.Ninit) + countlist(n.Nbody) + countlist(n.List) + countlist(n.Rlist)
+	}
+	return countlist(fn.Nbody) + countlist(fn.Func.Enter)
+}
+
+func Cgen_checknil(n *ir.Node) {
 	if Disable_checknil != 0 {
 		return
 	}
 
 	// Ideally we wouldn't see any integer types here, but we do.
-	if n.Type == nil || (!Isptr[n.Type.Etype] && !Isint[n.Type.Etype] && n.Type.Etype != TUNSAFEPTR) {
+	if n.Type == nil || (!Isptr[n.Type.Etype] && !Isint[n.Type.Etype] && n.Type.Etype != ir.TUNSAFEPTR) {
 		Dump("checknil", n)
 		Fatalf("bad checknil")
 	}
 
-	if ((Thearch.Thechar == '0' || Thearch.Thechar == '5' || Thearch.Thechar == '7' || Thearch.Thechar == '9') && n.Op != OREGISTER) || !n.Addable || n.Op == OLITERAL {
-		var reg Node
-		Regalloc(&reg, Types[Tptr], n)
+	if ((Thearch.Thechar == '0' || Thearch.Thechar == '5' || Thearch.Thechar == '7' || Thearch.Thechar == '9') && n.Op != ir.OREGISTER) || !n.Addable || n.Op == ir.OLITERAL {
+		var reg ir.Node
+		Regalloc(&reg, ir.Types[Tptr], n)
 		Cgen(n, &reg)
 		Thearch.Gins(obj.ACHECKNIL, &reg, nil)
 		Regfree(&reg)
@@ -335,10 +508,142 @@ func Cgen_checknil(n *Node) {
 	Thearch.Gins(obj.ACHECKNIL, n, nil)
 }
 
-func compile(fn *Node) {
+// nBackendWorkers is the number of goroutines on which the SSA
+// optimization passes run (-c).
+var nBackendWorkers = 1
+
+// backendMu serializes the calls from the SSA optimization passes
+// into the rest of the compiler, which is not safe for concurrent use,
+// while nBackendWorkers goroutines optimize functions.
+var backendMu sync.Mutex
+
+// backendBatch is the number of functions the SSA back end optimizes
+// at once when nBackendWorkers > 1. It does not depend on the number of
+// workers, so that the output does not either.
+const backendBatch = 32
+
+// compileFunctions compiles the top-level functions in xtop.
+//
+// If nBackendWorkers > 1, it builds the SSA form of a batch of
+// functions, optimizes them concurrently, and then generates their
+// code, in order. Only ssa.Compile runs concurrently. Walking a
+// function, building its SSA form and generating its code use global
+// state, such as Curfn, lineno, Pc and the Prog allocator, that is
+// not per worker, so they run on one goroutine.
+//
+// TODO: Compile whole functions concurrently. That needs Curfn,
+// lineno, the Prog allocator and the rest of the state used by walk,
+// buildssa, genssa and liveness analysis to be per worker, and the
+// object file to be written in xtop order once all workers are done.
+func compileFunctions() {
+	if nBackendWorkers == 1 {
+		// Don't use range--walk can add functions to xtop.
+		for i := 0; i < len(xtop); i++ {
+			if xtop[i].Op == ir.ODCLFUNC {
+				funccompile(xtop[i])
+			}
+		}
+		return
+	}
+
+	var fns []*ir.Node
+	var ssafns []*ssa.Func
+	flush := func() {
+		work := make(chan int, len(ssafns))
+		for i := range ssafns {
+			work <- i
+		}
+		close(work)
+		var wg sync.WaitGroup
+		wg.Add(nBackendWorkers)
+		for i := 0; i < nBackendWorkers; i++ {
+			go func() {
+				for i := range work {
+					t := timestart()
+					ssa.Compile(ssafns[i])
+					timeend("compile", fns[i], t)
+				}
+				wg.Done()
+			}()
+		}
+		wg.Wait()
+
+		for i, fn := range fns {
+			funcenter(fn)
+			Curfn = fn
+			lno := setlineno(fn)
+			emitfunc(fn, ssafns[i])
+			lineno = lno
+			funcexit()
+			fncachestore(fn)
+			funcdone(fn)
+			ssaConfigs[i].Frontend().(*ssaExport).curfn = nil
+		}
+		fns, ssafns = fns[:0], ssafns[:0]
+	}
+
+	// Don't use range--walk can add functions to xtop.
+	for i := 0; i < len(xtop); i++ {
+		fn := xtop[i]
+		if fn.Op != ir.ODCLFUNC {
+			continue
+		}
+		if fn.Type == nil || !shouldssa(fn) {
+			flush()
+			funccompile(fn)
+			continue
+		}
+		if !funcenter(fn) {
+			continue
+		}
+		lno := setlineno(fn)
+		ssafn, ok := buildfunc(fn, func() *ssa.Config { return batchssa(len(fns), fn) })
+		if ok && ssafn != nil && fncacheload(fn, ssafn) {
+			ok = false
+		}
+		lineno = lno
+		funcexit()
+		if ok && ssafn != nil {
+			fns = append(fns, fn)
+			ssafns = append(ssafns, ssafn)
+			if len(fns) == backendBatch {
+				flush()
+			}
+		}
+	}
+	flush()
+}
+
+func compile(fn *ir.Node) {
+	defer func(lno int32) {
+		lineno = lno
+	}(setlineno(fn))
+
+	ssafn, ok := buildfunc(fn, initssa)
+	if !ok {
+		return
+	}
+	if ssafn != nil {
+		if fncacheload(fn, ssafn) {
+			return
+		}
+		t := timestart()
+		ssa.Compile(ssafn)
+		timeend("compile", fn, t)
+	}
+	emitfunc(fn, ssafn)
+}
+
+// buildfunc prepares fn for code generation: it orders and walks the
+// function body and, if fn is compiled by the SSA back end, builds its
+// SSA form using the configuration returned by ssaconfig. If ssaconfig
+// is nil, it stops after walk. It reports whether code should be
+// generated for fn.
+func buildfunc(fn *ir.Node, ssaconfig func() *ssa.Config) (ssafn *ssa.Func, ok bool) {
 	if Newproc == nil {
 		Newproc = Sysfunc("newproc")
 		Deferproc = Sysfunc("deferproc")
+		Deferproc0 = Sysfunc("deferproc0")
 		Deferreturn = Sysfunc("deferreturn")
 		Panicindex = Sysfunc("panicindex")
 		panicslice = Sysfunc("panicslice")
@@ -350,24 +655,24 @@ func compile(fn *Node) {
 		panicdottype = Sysfunc("panicdottype")
 	}
 
-	defer func(lno int32) {
-		lineno = lno
-	}(setlineno(fn))
-
 	Curfn = fn
 	dowidth(Curfn.Type)
+	if Curfn.Type.Argwid >= 1<<31 {
+		tooLarge(fmt.Sprintf("arguments too large (%d bytes > 2GB)", Curfn.Type.Argwid))
+		return nil, false
+	}
 
 	if len(fn.Nbody.Slice()) == 0 {
 		if pure_go != 0 || strings.HasPrefix(fn.Func.Nname.Sym.Name, "init.") {
 			Yyerror("missing function body for %q", fn.Func.Nname.Sym.Name)
-			return
+			return nil, false
 		}
 
 		if Debug['A'] != 0 {
-			return
+			return nil, false
 		}
 		emitptrargsmap()
-		return
+		return nil, false
 	}
 
 	saveerrors()
@@ -379,34 +684,53 @@ func compile(fn *Node) {
 		// add clearing of the output parameters
 		for _, t := range Curfn.Type.Results().Fields().Slice() {
 			if t.Nname != nil {
-				n := Nod(OAS, t.Nname, nil)
+				n := Nod(ir.OAS, t.Nname, nil)
 				n = typecheck(n, Etop)
-				Curfn.Nbody.Set(append([]*Node{n}, Curfn.Nbody.Slice()...))
+				Curfn.Nbody.Set(append([]*ir.Node{n}, Curfn.Nbody.Slice()...))
 			}
 		}
 	}
 
+	if dwarflevel > dwarfNone {
+		stmtlines(Curfn)
+	}
+
+	t := timestart()
 	order(Curfn)
 	if nerrors != 0 {
-		return
+		return nil, false
 	}
 
 	hasdefer = false
 	walk(Curfn)
 	if nerrors != 0 {
-		return
+		return nil, false
 	}
 	if instrumenting {
 		instrument(Curfn)
 	}
 	if nerrors != 0 {
-		return
+		return nil, false
 	}
+	writebarrier(Curfn)
+	timeend("walk", Curfn, t)
 
 	// Build an SSA backend function.
-	var ssafn *ssa.Func
-	if shouldssa(Curfn) {
-		ssafn = buildssa(Curfn)
+	if ssaconfig != nil && shouldssa(Curfn) {
+		t := timestart()
+		ssafn = buildssa(Curfn, ssaconfig())
+		timeend("ssa", Curfn, t)
+	}
+	return ssafn, true
+}
+
+// emitfunc generates code for fn, which was prepared by buildfunc,
+// from ssafn if it is not nil, and using the legacy back end otherwise.
+func emitfunc(fn *ir.Node, ssafn *ssa.Func) {
+	defer timeend("gen", fn, timestart())
+
+	if ssafn != nil {
+		ssafn.Config.Frontend().(*ssaExport).commit()
 	}
 
 	continpc = nil
@@ -417,8 +741,8 @@ func compile(fn *Node) {
 
 	setlineno(Curfn)
 
-	var nod1 Node
-	Nodconst(&nod1, Types[TINT32], 0)
+	var nod1 ir.Node
+	Nodconst(&nod1, ir.Types[ir.TINT32], 0)
 	nam := Curfn.Func.Nname
 	if isblank(nam) {
 		nam = nil
@@ -435,13 +759,13 @@ func compile(fn *Node) {
 	if fn.Func.Needctxt {
 		ptxt.From3.Offset |= obj.NEEDCTXT
 	}
-	if fn.Func.Pragma&Nosplit != 0 {
+	if fn.Func.Pragma&ir.Nosplit != 0 {
 		ptxt.From3.Offset |= obj.NOSPLIT
 	}
 	if fn.Func.ReflectMethod {
 		ptxt.From3.Offset |= obj.REFLECTMETHOD
 	}
-	if fn.Func.Pragma&Systemstack != 0 {
+	if fn.Func.Pragma&ir.Systemstack != 0 {
 		ptxt.From.Sym.Cfunc = 1
 	}
 
@@ -460,7 +784,7 @@ func compile(fn *Node) {
 	gclocals := makefuncdatasym("gclocals·", obj.FUNCDATA_LocalsPointerMaps)
 
 	if obj.Fieldtrack_enabled != 0 && len(Curfn.Func.FieldTrack) > 0 {
-		trackSyms := make([]*Sym, 0, len(Curfn.Func.FieldTrack))
+		trackSyms := make([]*ir.Sym, 0, len(Curfn.Func.FieldTrack))
 		for sym := range Curfn.Func.FieldTrack {
 			trackSyms = append(trackSyms, sym)
 		}
@@ -470,15 +794,40 @@ func compile(fn *Node) {
 		}
 	}
 
-	for _, n := range fn.Func.Dcl {
-		if n.Op != ONAME { // might be OTYPE or OLITERAL
-			continue
+	// Tell the linker which methods reflect.Type.Method
+	// and MethodByName can reach from this function.
+	if len(Curfn.Func.ReflectMethods) > 0 {
+		methodSyms := make([]*ir.Sym, 0, len(Curfn.Func.ReflectMethods))
+		for sym := range Curfn.Func.ReflectMethods {
+			methodSyms = append(methodSyms, sym)
 		}
-		switch n.Class {
-		case PAUTO, PPARAM, PPARAMOUT:
-			Nodconst(&nod1, Types[TUINTPTR], n.Type.Width)
-			p := Thearch.Gins(obj.ATYPE, n, &nod1)
-			p.From.Gotype = Linksym(ngotype(n))
+		sort.Sort(symByName(methodSyms))
+		for _, sym := range methodSyms {
+			gtrack(sym)
+		}
+	}
+
+	if dwarflevel == dwarfNone && ptxt.From.Sym != nil {
+		ptxt.From.Sym.NoDWARF = true
+	}
+	if dwarflevel >= dwarfVars {
+		scopes, varscope := funcscopes(fn)
+		if ptxt.From.Sym != nil {
+			ptxt.From.Sym.Scopes = scopes
+		}
+		for _, n := range fn.Func.Dcl {
+			if n.Op != ir.ONAME { // might be OTYPE or OLITERAL
+				continue
+			}
+			switch n.Class {
+			case ir.PAUTO, ir.PPARAM, ir.PPARAMOUT:
+				Nodconst(&nod1, ir.Types[ir.TUINTPTR], n.Type.Width)
+				p := Thearch.Gins(obj.ATYPE, n, &nod1)
+				p.From.Val = Linksym(ngotype(n))
+				if s := varscope[n]; s != 0 {
+					p.From3 = &obj.Addr{Type: obj.TYPE_CONST, Offset: int64(s)}
+				}
+			}
 		}
 	}
 
@@ -488,10 +837,15 @@ func compile(fn *Node) {
 	} else {
 		genlegacy(ptxt, gcargs, gclocals)
 	}
+	markstmts(ptxt)
+
+	if Debug_wb > 1 && Curfn.Func.WBLineno != 0 {
+		Warnl(Curfn.Lineno, "%v: first write barrier at %v", Curfn.Func.Nname.Sym, linestr(Curfn.Func.WBLineno))
+	}
 }
 
 // genlegacy compiles Curfn using the legacy non-SSA code generator.
-func genlegacy(ptxt *obj.Prog, gcargs, gclocals *Sym) {
+func genlegacy(ptxt *obj.Prog, gcargs, gclocals *ir.Sym) {
 	Genlist(Curfn.Func.Enter)
 	Genlist(Curfn.Nbody)
 	gclean()
@@ -540,7 +894,7 @@ func genlegacy(ptxt *obj.Prog, gcargs, gclocals *Sym) {
 
 	setlineno(Curfn)
 	if Stksize+Maxarg > 1<<31 {
-		Yyerror("stack frame too large (>2GB)")
+		tooLarge("stack frame too large (>2GB)")
 		return
 	}
 
@@ -550,12 +904,14 @@ func genlegacy(ptxt *obj.Prog, gcargs, gclocals *Sym) {
 	gcsymdup(gcargs)
 	gcsymdup(gclocals)
 
-	Thearch.Defframe(ptxt)
+	defframe(ptxt)
 
 	if Debug['f'] != 0 {
 		frame(0)
 	}
 
+	marknosplit(ptxt)
+
 	// Remove leftover instrumentation from the instruction stream.
 	removevardef(ptxt)
 }
@@ -5,6 +5,7 @@
 package gc
 
 import (
+	"cmd/compile/internal/ir"
 	"reflect"
 	"sort"
 	"testing"
@@ -13,97 +14,97 @@ import (
 // Test all code paths for cmpstackvarlt.
 func TestCmpstackvar(t *testing.T) {
 	testdata := []struct {
-		a, b Node
+		a, b ir.Node
 		lt   bool
 	}{
 		{
-			Node{Class: PAUTO},
-			Node{Class: PFUNC},
+			ir.Node{Class: ir.PAUTO},
+			ir.Node{Class: ir.PFUNC},
 			false,
 		},
 		{
-			Node{Class: PFUNC},
-			Node{Class: PAUTO},
+			ir.Node{Class: ir.PFUNC},
+			ir.Node{Class: ir.PAUTO},
 			true,
 		},
 		{
-			Node{Class: PFUNC, Xoffset: 0},
-			Node{Class: PFUNC, Xoffset: 10},
+			ir.Node{Class: ir.PFUNC, Xoffset: 0},
+			ir.Node{Class: ir.PFUNC, Xoffset: 10},
 			true,
 		},
 		{
-			Node{Class: PFUNC, Xoffset: 20},
-			Node{Class: PFUNC, Xoffset: 10},
+			ir.Node{Class: ir.PFUNC, Xoffset: 20},
+			ir.Node{Class: ir.PFUNC, Xoffset: 10},
 			false,
 		},
 		{
-			Node{Class: PFUNC, Xoffset: 10},
-			Node{Class: PFUNC, Xoffset: 10},
+			ir.Node{Class: ir.PFUNC, Xoffset: 10},
+			ir.Node{Class: ir.PFUNC, Xoffset: 10},
 			false,
 		},
 		{
-			Node{Class: PPARAM, Xoffset: 10},
-			Node{Class: PPARAMOUT, Xoffset: 20},
+			ir.Node{Class: ir.PPARAM, Xoffset: 10},
+			ir.Node{Class: ir.PPARAMOUT, Xoffset: 20},
 			true,
 		},
 		{
-			Node{Class: PPARAMOUT, Xoffset: 10},
-			Node{Class: PPARAM, Xoffset: 20},
+			ir.Node{Class: ir.PPARAMOUT, Xoffset: 10},
+			ir.Node{Class: ir.PPARAM, Xoffset: 20},
 			true,
 		},
 		{
-			Node{Class: PAUTO, Used: true},
-			Node{Class: PAUTO, Used: false},
+			ir.Node{Class: ir.PAUTO, Used: true},
+			ir.Node{Class: ir.PAUTO, Used: false},
 			true,
 		},
 		{
-			Node{Class: PAUTO, Used: false},
-			Node{Class: PAUTO, Used: true},
+			ir.Node{Class: ir.PAUTO, Used: false},
+			ir.Node{Class: ir.PAUTO, Used: true},
 			false,
 		},
 		{
-			Node{Class: PAUTO, Type: &Type{Haspointers: 1}}, // haspointers -> false
-			Node{Class: PAUTO, Type: &Type{Haspointers: 2}}, // haspointers -> true
+			ir.Node{Class: ir.PAUTO, Type: &ir.Type{Haspointers: 1}}, // haspointers -> false
+			ir.Node{Class: ir.PAUTO, Type: &ir.Type{Haspointers: 2}}, // haspointers -> true
 			false,
 		},
 		{
-			Node{Class: PAUTO, Type: &Type{Haspointers: 2}}, // haspointers -> true
-			Node{Class: PAUTO, Type: &Type{Haspointers: 1}}, // haspointers -> false
+			ir.Node{Class: ir.PAUTO, Type: &ir.Type{Haspointers: 2}}, // haspointers -> true
+			ir.Node{Class: ir.PAUTO, Type: &ir.Type{Haspointers: 1}}, // haspointers -> false
 			true,
 		},
 		{
-			Node{Class: PAUTO, Type: &Type{}, Name: &Name{Needzero: true}},
-			Node{Class: PAUTO, Type: &Type{}, Name: &Name{Needzero: false}},
+			ir.Node{Class: ir.PAUTO, Type: &ir.Type{}, Name: &ir.Name{Needzero: true}},
+			ir.Node{Class: ir.PAUTO, Type: &ir.Type{}, Name: &ir.Name{Needzero: false}},
 			true,
 		},
 		{
-			Node{Class: PAUTO, Type: &Type{}, Name: &Name{Needzero: false}},
-			Node{Class: PAUTO, Type: &Type{}, Name: &Name{Needzero: true}},
+			ir.Node{Class: ir.PAUTO, Type: &ir.Type{}, Name: &ir.Name{Needzero: false}},
+			ir.Node{Class: ir.PAUTO, Type: &ir.Type{}, Name: &ir.Name{Needzero: true}},
 			false,
 		},
 		{
-			Node{Class: PAUTO, Type: &Type{Width: 1}, Name: &Name{}},
-			Node{Class: PAUTO, Type: &Type{Width: 2}, Name: &Name{}},
+			ir.Node{Class: ir.PAUTO, Type: &ir.Type{Width: 1}, Name: &ir.Name{}},
+			ir.Node{Class: ir.PAUTO, Type: &ir.Type{Width: 2}, Name: &ir.Name{}},
 			false,
 		},
 		{
-			Node{Class: PAUTO, Type: &Type{Width: 2}, Name: &Name{}},
-			Node{Class: PAUTO, Type: &Type{Width: 1}, Name: &Name{}},
+			ir.Node{Class: ir.PAUTO, Type: &ir.Type{Width: 2}, Name: &ir.Name{}},
+			ir.Node{Class: ir.PAUTO, Type: &ir.Type{Width: 1}, Name: &ir.Name{}},
 			true,
 		},
 		{
-			Node{Class: PAUTO, Type: &Type{}, Name: &Name{}, Sym: &Sym{Name: "abc"}},
-			Node{Class: PAUTO, Type: &Type{}, Name: &Name{}, Sym: &Sym{Name: "xyz"}},
+			ir.Node{Class: ir.PAUTO, Type: &ir.Type{}, Name: &ir.Name{}, Sym: &ir.Sym{Name: "abc"}},
+			ir.Node{Class: ir.PAUTO, Type: &ir.Type{}, Name: &ir.Name{}, Sym: &ir.Sym{Name: "xyz"}},
 			true,
 		},
 		{
-			Node{Class: PAUTO, Type: &Type{}, Name: &Name{}, Sym: &Sym{Name: "abc"}},
-			Node{Class: PAUTO, Type: &Type{}, Name: &Name{}, Sym: &Sym{Name: "abc"}},
+			ir.Node{Class: ir.PAUTO, Type: &ir.Type{}, Name: &ir.Name{}, Sym: &ir.Sym{Name: "abc"}},
+			ir.Node{Class: ir.PAUTO, Type: &ir.Type{}, Name: &ir.Name{}, Sym: &ir.Sym{Name: "abc"}},
 			false,
 		},
 		{
-			Node{Class: PAUTO, Type: &Type{}, Name: &Name{}, Sym: &Sym{Name: "xyz"}},
-			Node{Class: PAUTO, Type: &Type{}, Name: &Name{}, Sym: &Sym{Name: "abc"}},
+			ir.Node{Class: ir.PAUTO, Type: &ir.Type{}, Name: &ir.Name{}, Sym: &ir.Sym{Name: "xyz"}},
+			ir.Node{Class: ir.PAUTO, Type: &ir.Type{}, Name: &ir.Name{}, Sym: &ir.Sym{Name: "abc"}},
 			false,
 		},
 	}
@@ -120,35 +121,35 @@ func TestCmpstackvar(t *testing.T) {
 }
 
 func TestStackvarSort(t *testing.T) {
-	inp := []*Node{
-		{Class: PFUNC, Type: &Type{}, Name: &Name{}, Sym: &Sym{}},
-		{Class: PAUTO, Type: &Type{}, Name: &Name{}, Sym: &Sym{}},
-		{Class: PFUNC, Xoffset: 0, Type: &Type{}, Name: &Name{}, Sym: &Sym{}},
-		{Class: PFUNC, Xoffset: 10, Type: &Type{}, Name: &Name{}, Sym: &Sym{}},
-		{Class: PFUNC, Xoffset: 20, Type: &Type{}, Name: &Name{}, Sym: &Sym{}},
-		{Class: PAUTO, Used: true, Type: &Type{}, Name: &Name{}, Sym: &Sym{}},
-		{Class: PAUTO, Type: &Type{Haspointers: 1}, Name: &Name{}, Sym: &Sym{}}, // haspointers -> false
-		{Class: PAUTO, Type: &Type{}, Name: &Name{}, Sym: &Sym{}},
-		{Class: PAUTO, Type: &Type{}, Name: &Name{Needzero: true}, Sym: &Sym{}},
-		{Class: PAUTO, Type: &Type{Width: 1}, Name: &Name{}, Sym: &Sym{}},
-		{Class: PAUTO, Type: &Type{Width: 2}, Name: &Name{}, Sym: &Sym{}},
-		{Class: PAUTO, Type: &Type{}, Name: &Name{}, Sym: &Sym{Name: "abc"}},
-		{Class: PAUTO, Type: &Type{}, Name: &Name{}, Sym: &Sym{Name: "xyz"}},
+	inp := []*ir.Node{
+		{Class: ir.PFUNC, Type: &ir.Type{}, Name: &ir.Name{}, Sym: &ir.Sym{}},
+		{Class: ir.PAUTO, Type: &ir.Type{}, Name: &ir.Name{}, Sym: &ir.Sym{}},
+		{Class: ir.PFUNC, Xoffset: 0, Type: &ir.Type{}, Name: &ir.Name{}, Sym: &ir.Sym{}},
+		{Class: ir.PFUNC, Xoffset: 10, Type: &ir.Type{}, Name: &ir.Name{}, Sym: &ir.Sym{}},
+		{Class: ir.PFUNC, Xoffset: 20, Type: &ir.Type{}, Name: &ir.Name{}, Sym: &ir.Sym{}},
+		{Class: ir.PAUTO, Used: true, Type: &ir.Type{}, Name: &ir.Name{}, Sym: &ir.Sym{}},
+		{Class: ir.PAUTO, Type: &ir.Type{Haspointers: 1}, Name: &ir.Name{}, Sym: &ir.Sym{}}, // haspointers -> false
+		{Class: ir.PAUTO, Type: &ir.Type{}, Name: &ir.Name{}, Sym: &ir.Sym{}},
+		{Class: ir.PAUTO, Type: &ir.Type{}, Name: &ir.Name{Needzero: true}, Sym: &ir.Sym{}},
+		{Class: ir.PAUTO, Type: &ir.Type{Width: 1}, Name: &ir.Name{}, Sym: &ir.Sym{}},
+		{Class: ir.PAUTO, Type: &ir.Type{Width: 2}, Name: &ir.Name{}, Sym: &ir.Sym{}},
+		{Class: ir.PAUTO, Type: &ir.Type{}, Name: &ir.Name{}, Sym: &ir.Sym{Name: "abc"}},
+		{Class: ir.PAUTO, Type: &ir.Type{}, Name: &ir.Name{}, Sym: &ir.Sym{Name: "xyz"}},
 	}
-	want := []*Node{
-		{Class: PFUNC, Type: &Type{}, Name: &Name{}, Sym: &Sym{}},
-		{Class: PFUNC, Xoffset: 0, Type: &Type{}, Name: &Name{}, Sym: &Sym{}},
-		{Class: PFUNC, Xoffset: 10, Type: &Type{}, Name: &Name{}, Sym: &Sym{}},
-		{Class: PFUNC, Xoffset: 20, Type: &Type{}, Name: &Name{}, Sym: &Sym{}},
-		{Class: PAUTO, Used: true, Type: &Type{}, Name: &Name{}, Sym: &Sym{}},
-		{Class: PAUTO, Type: &Type{}, Name: &Name{Needzero: true}, Sym: &Sym{}},
-		{Class: PAUTO, Type: &Type{Width: 2}, Name: &Name{}, Sym: &Sym{}},
-		{Class: PAUTO, Type: &Type{Width: 1}, Name: &Name{}, Sym: &Sym{}},
-		{Class: PAUTO, Type: &Type{}, Name: &Name{}, Sym: &Sym{}},
-		{Class: PAUTO, Type: &Type{}, Name: &Name{}, Sym: &Sym{}},
-		{Class: PAUTO, Type: &Type{}, Name: &Name{}, Sym: &Sym{Name: "abc"}},
-		{Class: PAUTO, Type: &Type{}, Name: &Name{}, Sym: &Sym{Name: "xyz"}},
-		{Class: PAUTO, Type: &Type{Haspointers: 1}, Name: &Name{}, Sym: &Sym{}}, // haspointers -> false
+	want := []*ir.Node{
+		{Class: ir.PFUNC, Type: &ir.Type{}, Name: &ir.Name{}, Sym: &ir.Sym{}},
+		{Class: ir.PFUNC, Xoffset: 0, Type: &ir.Type{}, Name: &ir.Name{}, Sym: &ir.Sym{}},
+		{Class: ir.PFUNC, Xoffset: 10, Type: &ir.Type{}, Name: &ir.Name{}, Sym: &ir.Sym{}},
+		{Class: ir.PFUNC, Xoffset: 20, Type: &ir.Type{}, Name: &ir.Name{}, Sym: &ir.Sym{}},
+		{Class: ir.PAUTO, Used: true, Type: &ir.Type{}, Name: &ir.Name{}, Sym: &ir.Sym{}},
+		{Class: ir.PAUTO, Type: &ir.Type{}, Name: &ir.Name{Needzero: true}, Sym: &ir.Sym{}},
+		{Class: ir.PAUTO, Type: &ir.Type{Width: 2}, Name: &ir.Name{}, Sym: &ir.Sym{}},
+		{Class: ir.PAUTO, Type: &ir.Type{Width: 1}, Name: &ir.Name{}, Sym: &ir.Sym{}},
+		{Class: ir.PAUTO, Type: &ir.Type{}, Name: &ir.Name{}, Sym: &ir.Sym{}},
+		{Class: ir.PAUTO, Type: &ir.Type{}, Name: &ir.Name{}, Sym: &ir.Sym{}},
+		{Class: ir.PAUTO, Type: &ir.Type{}, Name: &ir.Name{}, Sym: &ir.Sym{Name: "abc"}},
+		{Class: ir.PAUTO, Type: &ir.Type{}, Name: &ir.Name{}, Sym: &ir.Sym{Name: "xyz"}},
+		{Class: ir.PAUTO, Type: &ir.Type{Haspointers: 1}, Name: &ir.Name{}, Sym: &ir.Sym{}}, // haspointers -> false
 	}
 	// haspointers updates Type.Haspointers as a side effect, so
 	// exercise this function on all inputs so that reflect.DeepEqual
@@ -0,0 +1,304 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gc
+
+import "cmd/compile/internal/ir"
+import "cmd/compile/internal/ssa"
+
+// Large functions place phis as described by Cytron, Ferrante, Rosen,
+// Wegman, and Zadeck, "Efficiently Computing Static Single Assignment
+// Form and the Control Dependence Graph", with dominance frontiers
+// computed as by Cooper, Harvey, and Kennedy, "A Simple, Fast Dominance
+// Algorithm". linkForwardReferences leaves a FwdRef in every join a
+// variable is live through, which for machine-generated functions with
+// thousands of variables and blocks is quadratic in time and memory.
+// Here, phis only go in the iterated dominance frontier of a variable's
+// definitions, and a single walk of the dominator tree links the uses
+// to them.
+
+// smallBlocks is the number of blocks above which buildssa uses
+// insertPhis. Below it, linkForwardReferences is faster.
+const smallBlocks = 500
+
+// insertPhis is linkForwardReferences for large functions.
+// It finds the uses of variables from the FwdRefs in s.fwdRefs, and the
+// definitions from s.defvars, inserts phis for the variables, and turns
+// the FwdRefs into copies of the phis or definitions reaching them.
+func (s *state) insertPhis() {
+	p := phiState{s: s, f: s.f, varnum: map[*ir.Node]int32{}}
+
+	// Number the variables used in a block other than the one
+	// defining them. A use in the block just after the definition
+	// is common enough to resolve right away.
+	for _, v := range s.fwdRefs {
+		name := v.Aux.(*ir.Node)
+		if b := v.Block; len(b.Preds) == 1 {
+			if w := p.defvar(b.Preds[0], name); w != nil {
+				v.Op = ssa.OpCopy
+				v.Aux = nil
+				v.AddArg(w)
+				continue
+			}
+		}
+		if _, ok := p.varnum[name]; ok {
+			continue
+		}
+		p.varnum[name] = int32(len(p.vars))
+		p.vars = append(p.vars, name)
+		p.types = append(p.types, v.Type)
+	}
+	s.fwdRefs = s.fwdRefs[:0]
+	if len(p.vars) == 0 {
+		return
+	}
+
+	// Find the blocks defining each variable.
+	defs := make([][]*ssa.Block, len(p.vars))
+	for _, b := range s.f.Blocks {
+		if int(b.ID) >= len(s.defvars) {
+			continue
+		}
+		for name := range s.defvars[b.ID] {
+			if n, ok := p.varnum[name]; ok {
+				defs[n] = append(defs[n], b)
+			}
+		}
+	}
+
+	// Build the dominator tree, and the dominance frontiers.
+	nb := s.f.NumBlocks()
+	p.idom = s.f.Idom()
+	p.child = make([]*ssa.Block, nb)
+	p.sibling = make([]*ssa.Block, nb)
+	for _, b := range s.f.Blocks {
+		if d := p.idom[b.ID]; d != nil {
+			p.sibling[b.ID] = p.child[d.ID]
+			p.child[d.ID] = b
+		}
+	}
+	p.df = make([][]*ssa.Block, nb)
+	for _, b := range s.f.Blocks {
+		if len(b.Preds) < 2 || p.idom[b.ID] == nil {
+			// Not a join, or unreachable.
+			continue
+		}
+		for _, d := range b.Preds {
+			if !p.reachable(d) {
+				continue
+			}
+			for ; d != p.idom[b.ID]; d = p.idom[d.ID] {
+				if l := p.df[d.ID]; len(l) > 0 && l[len(l)-1] == b {
+					// b has several preds d dominates.
+					break
+				}
+				p.df[d.ID] = append(p.df[d.ID], b)
+			}
+		}
+	}
+
+	p.hasPhi = make([]int32, nb)
+	p.hasDef = make([]int32, nb)
+	p.entry = make([]*ssa.Value, len(p.vars))
+	p.placeholder = s.entryNewValue0(ssa.OpUnknown, ssa.TypeInvalid)
+
+	for n := range p.vars {
+		p.insertVarPhis(int32(n), defs[n])
+	}
+	p.resolveFwdRefs()
+
+	for _, b := range s.f.Blocks {
+		for _, v := range b.Values {
+			switch v.Op {
+			case ssa.OpPhi:
+				// Done with the variable number.
+				v.AuxInt = 0
+			case ssa.OpFwdRef:
+				// In a block the walk did not reach.
+				// Like linkForwardReferences, it doesn't
+				// matter what we use.
+				v.Op = ssa.OpUnknown
+				v.Aux = nil
+			}
+		}
+	}
+}
+
+// A phiState holds the state of insertPhis.
+type phiState struct {
+	s *state
+	f *ssa.Func
+
+	varnum map[*ir.Node]int32 // variable numbering
+	vars   []*ir.Node         // variables by number
+	types  []ssa.Type         // types of the variables
+
+	// The dominator tree, and the dominance frontiers.
+	idom    []*ssa.Block   // parent
+	child   []*ssa.Block   // first child
+	sibling []*ssa.Block   // next sibling
+	df      [][]*ssa.Block // dominance frontier
+
+	// Scratch space. hasPhi and hasDef hold one plus the number
+	// of the variable last processed for each block.
+	q      []*ssa.Block
+	hasPhi []int32
+	hasDef []int32
+
+	entry       []*ssa.Value // values of variables at entry, once needed
+	placeholder *ssa.Value   // phi arg where the variable is undefined
+}
+
+// defvar returns the value of name at the end of b, if b defines it.
+func (p *phiState) defvar(b *ssa.Block, name *ir.Node) *ssa.Value {
+	if int(b.ID) >= len(p.s.defvars) {
+		return nil
+	}
+	return p.s.defvars[b.ID][name]
+}
+
+// reachable reports whether b is reachable from the entry block.
+func (p *phiState) reachable(b *ssa.Block) bool {
+	return b == p.f.Entry || p.idom[b.ID] != nil
+}
+
+// insertVarPhis inserts phis for variable n, defined in defs.
+// A phi is a definition too, so they go in the iterated dominance
+// frontier of defs.
+func (p *phiState) insertVarPhis(n int32, defs []*ssa.Block) {
+	mark := n + 1
+	q := p.q[:0]
+	for _, b := range defs {
+		p.hasDef[b.ID] = mark
+		q = append(q, b)
+	}
+	for len(q) > 0 {
+		b := q[len(q)-1]
+		q = q[:len(q)-1]
+		for _, c := range p.df[b.ID] {
+			if p.hasPhi[c.ID] == mark {
+				continue
+			}
+			p.hasPhi[c.ID] = mark
+			v := c.NewValue0I(b.Line, ssa.OpPhi, p.types[n], int64(n))
+			p.s.addNamedValue(p.vars[n], v)
+			for range c.Preds {
+				// Filled in by resolveFwdRefs.
+				v.AddArg(p.placeholder)
+			}
+			if p.hasDef[c.ID] != mark {
+				p.hasDef[c.ID] = mark
+				q = append(q, c)
+			}
+		}
+	}
+	p.q = q
+}
+
+// resolveFwdRefs walks the dominator tree, keeping track of the value
+// of each variable, and links the FwdRefs and phis to those values.
+func (p *phiState) resolveFwdRefs() {
+	values := make([]*ssa.Value, len(p.vars))
+
+	// A stackEntry is either a block to visit, or the value
+	// of variable n to restore on leaving a block.
+	type stackEntry struct {
+		b *ssa.Block
+		n int32
+		v *ssa.Value
+	}
+	stk := []stackEntry{{b: p.f.Entry}}
+	for len(stk) > 0 {
+		e := stk[len(stk)-1]
+		stk = stk[:len(stk)-1]
+		b := e.b
+		if b == nil {
+			values[e.n] = e.v
+			continue
+		}
+
+		// Phis define their variables at the start of b.
+		for _, v := range b.Values {
+			if v.Op == ssa.OpPhi {
+				n := int32(v.AuxInt)
+				stk = append(stk, stackEntry{n: n, v: values[n]})
+				values[n] = v
+			}
+		}
+
+		// FwdRefs stand for the incoming values.
+		for _, v := range b.Values {
+			if v.Op != ssa.OpFwdRef {
+				continue
+			}
+			n := p.varnum[v.Aux.(*ir.Node)]
+			if b == p.f.Entry {
+				p.s.resolveFwdRef(v)
+				p.entry[n] = v
+				continue
+			}
+			w := values[n]
+			if w == nil {
+				w = p.entryValue(n, v.Line)
+			}
+			v.Op = ssa.OpCopy
+			v.Aux = nil
+			v.AddArg(w)
+		}
+
+		// The values at the end of b.
+		if int(b.ID) < len(p.s.defvars) {
+			for name, v := range p.s.defvars[b.ID] {
+				if n, ok := p.varnum[name]; ok {
+					stk = append(stk, stackEntry{n: n, v: values[n]})
+					values[n] = v
+				}
+			}
+		}
+
+		// Fill in the args of the phis in the successors,
+		// which are at the end of their blocks.
+		for _, c := range b.Succs {
+			for j := len(c.Values) - 1; j >= 0; j-- {
+				v := c.Values[j]
+				if v.Op != ssa.OpPhi {
+					break
+				}
+				n := int32(v.AuxInt)
+				w := values[n]
+				if w == nil {
+					if class := p.vars[n].Class; class == ir.PPARAM || class == ir.PPARAMOUT {
+						w = p.entryValue(n, v.Line)
+					} else {
+						// Phis go wherever definitions
+						// meet, live or not. This one is
+						// dead, and so removed later.
+						w = p.placeholder
+					}
+				}
+				for i, d := range c.Preds {
+					if d == b {
+						v.SetArg(i, w)
+					}
+				}
+			}
+		}
+
+		for c := p.child[b.ID]; c != nil; c = p.sibling[c.ID] {
+			stk = append(stk, stackEntry{b: c})
+		}
+	}
+}
+
+// entryValue returns the value of variable n at the start of the function.
+func (p *phiState) entryValue(n int32, line int32) *ssa.Value {
+	if v := p.entry[n]; v != nil {
+		return v
+	}
+	v := p.f.Entry.NewValue0A(line, ssa.OpFwdRef, p.types[n], p.vars[n])
+	p.s.addNamedValue(p.vars[n], v)
+	p.s.resolveFwdRef(v)
+	p.entry[n] = v
+	return v
+}
@@ -16,6 +16,7 @@
 package gc
 
 import (
+	"cmd/compile/internal/ir"
 	"cmd/internal/obj"
 	"fmt"
 	"sort"
@@ -83,9 +84,9 @@ type BasicBlock struct {
 
 // A collection of global state used by liveness analysis.
 type Liveness struct {
-	fn   *Node
+	fn   *ir.Node
 	ptxt *obj.Prog
-	vars []*Node
+	vars []*ir.Node
 	cfg  []*BasicBlock
 
 	// An array with a bit vector for each safe point tracking live pointers
@@ -197,10 +198,10 @@ func blockany(bb *BasicBlock, f func(*obj.Prog) bool) bool {
 
 // Collects and returns a slice of *Nodes for functions arguments and local
 // variables.
-func getvariables(fn *Node) []*Node {
-	var result []*Node
+func getvariables(fn *ir.Node) []*ir.Node {
+	var result []*ir.Node
 	for _, ln := range fn.Func.Dcl {
-		if ln.Op == ONAME {
+		if ln.Op == ir.ONAME {
 			// In order for GODEBUG=gcdead=1 to work, each bitmap needs
 			// to contain information about all variables covered by the bitmap.
 			// For local variables, the bitmap only covers the stkptrsize
@@ -229,13 +230,13 @@ func getvariables(fn *Node) []*Node {
 
 			ln.Name.Curfn = Curfn
 			switch ln.Class {
-			case PAUTO:
+			case ir.PAUTO:
 				if haspointers(ln.Type) {
 					ln.SetOpt(int32(len(result)))
 					result = append(result, ln)
 				}
 
-			case PPARAM, PPARAMOUT:
+			case ir.PPARAM, ir.PPARAMOUT:
 				ln.SetOpt(int32(len(result)))
 				result = append(result, ln)
 			}
@@ -530,7 +531,7 @@ func freecfg(cfg []*BasicBlock) {
 
 // Returns true if the node names a variable that is otherwise uninteresting to
 // the liveness computation.
-func isfunny(n *Node) bool {
+func isfunny(n *ir.Node) bool {
 	return n.Sym != nil && (n.Sym.Name == ".fp" || n.Sym.Name == ".args")
 }
 
@@ -548,7 +549,7 @@ func isfunny(n *Node) bool {
 // The avarinit output serves as a signal that the data has been
 // initialized, because any use of a variable must come after its
 // initialization.
-func progeffects(prog *obj.Prog, vars []*Node, uevar Bvec, varkill Bvec, avarinit Bvec) {
+func progeffects(prog *obj.Prog, vars []*ir.Node, uevar Bvec, varkill Bvec, avarinit Bvec) {
 	bvresetall(uevar)
 	bvresetall(varkill)
 	bvresetall(avarinit)
@@ -565,8 +566,8 @@ func progeffects(prog *obj.Prog, vars []*Node, uevar Bvec, varkill Bvec, avarini
 		// read the out arguments - they won't be set until the new
 		// function runs.
 		for i, node := range vars {
-			switch node.Class &^ PHEAP {
-			case PPARAM:
+			switch node.Class &^ ir.PHEAP {
+			case ir.PPARAM:
 				bvset(uevar, int32(i))
 
 				// If the result had its address taken, it is being tracked
@@ -577,7 +578,7 @@ func progeffects(prog *obj.Prog, vars []*Node, uevar Bvec, varkill Bvec, avarini
 			// The p.to.type == thearch.D_NONE limits the bvset to
 			// non-tail-call return instructions; see note above
 			// the for loop for details.
-			case PPARAMOUT:
+			case ir.PPARAMOUT:
 				if !node.Addrtaken && prog.To.Type == obj.TYPE_NONE {
 					bvset(uevar, int32(i))
 				}
@@ -591,8 +592,8 @@ func progeffects(prog *obj.Prog, vars []*Node, uevar Bvec, varkill Bvec, avarini
 		// A text instruction marks the entry point to a function and
 		// the definition point of all in arguments.
 		for i, node := range vars {
-			switch node.Class &^ PHEAP {
-			case PPARAM:
+			switch node.Class &^ ir.PHEAP {
+			case ir.PPARAM:
 				if node.Addrtaken {
 					bvset(avarinit, int32(i))
 				}
@@ -605,24 +606,24 @@ func progeffects(prog *obj.Prog, vars []*Node, uevar Bvec, varkill Bvec, avarini
 
 	if prog.Info.Flags&(LeftRead|LeftWrite|LeftAddr) != 0 {
 		from := &prog.From
-		if from.Node != nil && from.Sym != nil && ((from.Node).(*Node)).Name.Curfn == Curfn {
-			switch ((from.Node).(*Node)).Class &^ PHEAP {
-			case PAUTO, PPARAM, PPARAMOUT:
-				pos, ok := from.Node.(*Node).Opt().(int32) // index in vars
+		if from.Node != nil && from.Sym != nil && ((from.Node).(*ir.Node)).Name.Curfn == Curfn {
+			switch ((from.Node).(*ir.Node)).Class &^ ir.PHEAP {
+			case ir.PAUTO, ir.PPARAM, ir.PPARAMOUT:
+				pos, ok := from.Node.(*ir.Node).Opt().(int32) // index in vars
 				if !ok {
 					break
 				}
 				if pos >= int32(len(vars)) || vars[pos] != from.Node {
-					Fatalf("bad bookkeeping in liveness %v %d", Nconv(from.Node.(*Node), 0), pos)
+					Fatalf("bad bookkeeping in liveness %v %d", Nconv(from.Node.(*ir.Node), 0), pos)
 				}
-				if ((from.Node).(*Node)).Addrtaken {
+				if ((from.Node).(*ir.Node)).Addrtaken {
 					bvset(avarinit, pos)
 				} else {
 					if prog.Info.Flags&(LeftRead|LeftAddr) != 0 {
 						bvset(uevar, pos)
 					}
 					if prog.Info.Flags&LeftWrite != 0 {
-						if from.Node != nil && !Isfat(((from.Node).(*Node)).Type) {
+						if from.Node != nil && !Isfat(((from.Node).(*ir.Node)).Type) && !partialstore(prog, (from.Node).(*ir.Node)) {
 							bvset(varkill, pos)
 						}
 					}
@@ -633,17 +634,17 @@ func progeffects(prog *obj.Prog, vars []*Node, uevar Bvec, varkill Bvec, avarini
 
 	if prog.Info.Flags&(RightRead|RightWrite|RightAddr) != 0 {
 		to := &prog.To
-		if to.Node != nil && to.Sym != nil && ((to.Node).(*Node)).Name.Curfn == Curfn {
-			switch ((to.Node).(*Node)).Class &^ PHEAP {
-			case PAUTO, PPARAM, PPARAMOUT:
-				pos, ok := to.Node.(*Node).Opt().(int32) // index in vars
+		if to.Node != nil && to.Sym != nil && ((to.Node).(*ir.Node)).Name.Curfn == Curfn {
+			switch ((to.Node).(*ir.Node)).Class &^ ir.PHEAP {
+			case ir.PAUTO, ir.PPARAM, ir.PPARAMOUT:
+				pos, ok := to.Node.(*ir.Node).Opt().(int32) // index in vars
 				if !ok {
 					return
 				}
 				if pos >= int32(len(vars)) || vars[pos] != to.Node {
-					Fatalf("bad bookkeeping in liveness %v %d", Nconv(to.Node.(*Node), 0), pos)
+					Fatalf("bad bookkeeping in liveness %v %d", Nconv(to.Node.(*ir.Node), 0), pos)
 				}
-				if ((to.Node).(*Node)).Addrtaken {
+				if ((to.Node).(*ir.Node)).Addrtaken {
 					if prog.As != obj.AVARKILL {
 						bvset(avarinit, pos)
 					}
@@ -663,7 +664,7 @@ func progeffects(prog *obj.Prog, vars []*Node, uevar Bvec, varkill Bvec, avarini
 						bvset(uevar, pos)
 					}
 					if prog.Info.Flags&RightWrite != 0 {
-						if to.Node != nil && (!Isfat(((to.Node).(*Node)).Type) || prog.As == obj.AVARDEF) {
+						if to.Node != nil && (!Isfat(((to.Node).(*ir.Node)).Type) && !partialstore(prog, (to.Node).(*ir.Node)) || prog.As == obj.AVARDEF) {
 							bvset(varkill, pos)
 						}
 					}
@@ -673,10 +674,38 @@ func progeffects(prog *obj.Prog, vars []*Node, uevar Bvec, varkill Bvec, avarini
 	}
 }
 
+// partialstore reports whether prog, which writes to the local n,
+// writes only part of it. Locals that are not fat can still take two
+// words, such as a complex128, or an int64 on 386, and are then written
+// one word at a time; such a write must not kill the local. Only
+// mergeautos tracks such locals: those in the stack maps are pointers.
+// Results are left alone, as they are for the stack maps: a result
+// written in parts would otherwise be live on entry.
+func partialstore(prog *obj.Prog, n *ir.Node) bool {
+	if n.Class != ir.PAUTO {
+		return false
+	}
+	var size int64
+	switch {
+	case prog.Info.Flags&SizeB != 0:
+		size = 1
+	case prog.Info.Flags&SizeW != 0:
+		size = 2
+	case prog.Info.Flags&(SizeL|SizeF) != 0:
+		size = 4
+	case prog.Info.Flags&(SizeQ|SizeD) != 0:
+		size = 8
+	default:
+		// Unknown size; assume the whole variable.
+		return false
+	}
+	return size < n.Type.Width
+}
+
 // Constructs a new liveness structure used to hold the global state of the
 // liveness computation. The cfg argument is a slice of *BasicBlocks and the
 // vars argument is a slice of *Nodes.
-func newliveness(fn *Node, ptxt *obj.Prog, cfg []*BasicBlock, vars []*Node) *Liveness {
+func newliveness(fn *ir.Node, ptxt *obj.Prog, cfg []*BasicBlock, vars []*ir.Node) *Liveness {
 	result := Liveness{
 		fn:   fn,
 		ptxt: ptxt,
@@ -713,7 +742,7 @@ func printeffects(p *obj.Prog, uevar Bvec, varkill Bvec, avarinit Bvec) {
 // Pretty print a variable node. Uses Pascal like conventions for pointers and
 // addresses to avoid confusing the C like conventions used in the node variable
 // names.
-func printnode(node *Node) {
+func printnode(node *ir.Node) {
 	p := ""
 	if haspointers(node.Type) {
 		p = "^"
@@ -726,7 +755,7 @@ func printnode(node *Node) {
 }
 
 // Pretty print a list of variables. The vars argument is a slice of *Nodes.
-func printvars(name string, bv Bvec, vars []*Node) {
+func printvars(name string, bv Bvec, vars []*ir.Node) {
 	fmt.Printf("%s:", name)
 	for i, node := range vars {
 		if bvget(bv, int32(i)) != 0 {
@@ -786,9 +815,9 @@ func livenessprintcfg(lv *Liveness) {
 	}
 }
 
-func checkauto(fn *Node, p *obj.Prog, n *Node) {
+func checkauto(fn *ir.Node, p *obj.Prog, n *ir.Node) {
 	for _, ln := range fn.Func.Dcl {
-		if ln.Op == ONAME && ln.Class == PAUTO && ln == n {
+		if ln.Op == ir.ONAME && ln.Class == ir.PAUTO && ln == n {
 			return
 		}
 	}
@@ -805,13 +834,13 @@ func checkauto(fn *Node, p *obj.Prog, n *Node) {
 	Yyerror("checkauto: invariant lost")
 }
 
-func checkparam(fn *Node, p *obj.Prog, n *Node) {
+func checkparam(fn *ir.Node, p *obj.Prog, n *ir.Node) {
 	if isfunny(n) {
 		return
 	}
 	for _, a := range fn.Func.Dcl {
-		class := a.Class &^ PHEAP
-		if a.Op == ONAME && (class == PPARAM || class == PPARAMOUT) && a == n {
+		class := a.Class &^ ir.PHEAP
+		if a.Op == ir.ONAME && (class == ir.PPARAM || class == ir.PPARAMOUT) && a == n {
 			return
 		}
 	}
@@ -823,18 +852,18 @@ func checkparam(fn *Node, p *obj.Prog, n *Node) {
 	Yyerror("checkparam: invariant lost")
 }
 
-func checkprog(fn *Node, p *obj.Prog) {
+func checkprog(fn *ir.Node, p *obj.Prog) {
 	if p.From.Name == obj.NAME_AUTO {
-		checkauto(fn, p, p.From.Node.(*Node))
+		checkauto(fn, p, p.From.Node.(*ir.Node))
 	}
 	if p.From.Name == obj.NAME_PARAM {
-		checkparam(fn, p, p.From.Node.(*Node))
+		checkparam(fn, p, p.From.Node.(*ir.Node))
 	}
 	if p.To.Name == obj.NAME_AUTO {
-		checkauto(fn, p, p.To.Node.(*Node))
+		checkauto(fn, p, p.To.Node.(*ir.Node))
 	}
 	if p.To.Name == obj.NAME_PARAM {
-		checkparam(fn, p, p.To.Node.(*Node))
+		checkparam(fn, p, p.To.Node.(*ir.Node))
 	}
 }
 
@@ -843,7 +872,7 @@ func checkprog(fn *Node, p *obj.Prog) {
 // function. This is not strictly true, as is the case for the so-called funny
 // nodes and there are special cases to skip over that stuff. The analysis will
 // fail if this invariant blindly changes.
-func checkptxt(fn *Node, firstp *obj.Prog) {
+func checkptxt(fn *ir.Node, firstp *obj.Prog) {
 	if debuglive == 0 {
 		return
 	}
@@ -862,43 +891,43 @@ func checkptxt(fn *Node, firstp *obj.Prog) {
 // and then simply copied into bv at the correct offset on future calls with
 // the same type t. On https://rsc.googlecode.com/hg/testdata/slow.go, onebitwalktype1
 // accounts for 40% of the 6g execution time.
-func onebitwalktype1(t *Type, xoffset *int64, bv Bvec) {
+func onebitwalktype1(t *ir.Type, xoffset *int64, bv Bvec) {
 	if t.Align > 0 && *xoffset&int64(t.Align-1) != 0 {
 		Fatalf("onebitwalktype1: invalid initial alignment, %v", t)
 	}
 
 	switch t.Etype {
-	case TINT8,
-		TUINT8,
-		TINT16,
-		TUINT16,
-		TINT32,
-		TUINT32,
-		TINT64,
-		TUINT64,
-		TINT,
-		TUINT,
-		TUINTPTR,
-		TBOOL,
-		TFLOAT32,
-		TFLOAT64,
-		TCOMPLEX64,
-		TCOMPLEX128:
+	case ir.TINT8,
+		ir.TUINT8,
+		ir.TINT16,
+		ir.TUINT16,
+		ir.TINT32,
+		ir.TUINT32,
+		ir.TINT64,
+		ir.TUINT64,
+		ir.TINT,
+		ir.TUINT,
+		ir.TUINTPTR,
+		ir.TBOOL,
+		ir.TFLOAT32,
+		ir.TFLOAT64,
+		ir.TCOMPLEX64,
+		ir.TCOMPLEX128:
 		*xoffset += t.Width
 
-	case TPTR32,
-		TPTR64,
-		TUNSAFEPTR,
-		TFUNC,
-		TCHAN,
-		TMAP:
+	case ir.TPTR32,
+		ir.TPTR64,
+		ir.TUNSAFEPTR,
+		ir.TFUNC,
+		ir.TCHAN,
+		ir.TMAP:
 		if *xoffset&int64(Widthptr-1) != 0 {
 			Fatalf("onebitwalktype1: invalid alignment, %v", t)
 		}
 		bvset(bv, int32(*xoffset/int64(Widthptr))) // pointer
 		*xoffset += t.Width
 
-	case TSTRING:
+	case ir.TSTRING:
 		// struct { byte *str; intgo len; }
 		if *xoffset&int64(Widthptr-1) != 0 {
 			Fatalf("onebitwalktype1: invalid alignment, %v", t)
@@ -906,7 +935,7 @@ func onebitwalktype1(t *Type, xoffset *int64, bv Bvec) {
 		bvset(bv, int32(*xoffset/int64(Widthptr))) //pointer in first slot
 		*xoffset += t.Width
 
-	case TINTER:
+	case ir.TINTER:
 		// struct { Itab *tab;	void *data; }
 		// or, when isnilinter(t)==true:
 		// struct { Type *type; void *data; }
@@ -917,7 +946,7 @@ func onebitwalktype1(t *Type, xoffset *int64, bv Bvec) {
 		bvset(bv, int32(*xoffset/int64(Widthptr)+1)) // pointer in second slot
 		*xoffset += t.Width
 
-	case TARRAY:
+	case ir.TARRAY:
 		// The value of t.bound is -1 for slices types and >=0 for
 		// for fixed array types. All other values are invalid.
 		if t.Bound < -1 {
@@ -936,7 +965,7 @@ func onebitwalktype1(t *Type, xoffset *int64, bv Bvec) {
 			}
 		}
 
-	case TSTRUCT:
+	case ir.TSTRUCT:
 		var o int64
 		for _, t1 := range t.Fields().Slice() {
 			fieldoffset := t1.Width
@@ -965,7 +994,7 @@ func argswords() int32 {
 // Generates live pointer value maps for arguments and local variables. The
 // this argument and the in arguments are always assumed live. The vars
 // argument is a slice of *Nodes.
-func onebitlivepointermap(lv *Liveness, liveout Bvec, vars []*Node, args Bvec, locals Bvec) {
+func onebitlivepointermap(lv *Liveness, liveout Bvec, vars []*ir.Node, args Bvec, locals Bvec) {
 	var xoffset int64
 
 	for i := int32(0); ; i++ {
@@ -975,11 +1004,11 @@ func onebitlivepointermap(lv *Liveness, liveout Bvec, vars []*Node, args Bvec, l
 		}
 		node := vars[i]
 		switch node.Class {
-		case PAUTO:
+		case ir.PAUTO:
 			xoffset = node.Xoffset + stkptrsize
 			onebitwalktype1(node.Type, &xoffset, locals)
 
-		case PPARAM, PPARAMOUT:
+		case ir.PPARAM, ir.PPARAMOUT:
 			xoffset = node.Xoffset
 			onebitwalktype1(node.Type, &xoffset, args)
 		}
@@ -1014,9 +1043,9 @@ func unlinkedprog(as obj.As) *obj.Prog {
 // Construct a new PCDATA instruction associated with and for the purposes of
 // covering an existing instruction.
 func newpcdataprog(prog *obj.Prog, index int32) *obj.Prog {
-	var from, to Node
-	Nodconst(&from, Types[TINT32], obj.PCDATA_StackMapIndex)
-	Nodconst(&to, Types[TINT32], int64(index))
+	var from, to ir.Node
+	Nodconst(&from, ir.Types[ir.TINT32], obj.PCDATA_StackMapIndex)
+	Nodconst(&to, ir.Types[ir.TINT32], int64(index))
 	pcdata := unlinkedprog(obj.APCDATA)
 	pcdata.Lineno = prog.Lineno
 	Naddr(&pcdata.From, &from)
@@ -1026,6 +1055,12 @@ func newpcdataprog(prog *obj.Prog, index int32) *obj.Prog {
 
 // Returns true for instructions that are safe points that must be annotated
 // with liveness information.
+//
+// Safe points are already restricted to call sites, plus the function
+// entry for the call to morestack. The runtime depends on this: it only
+// ever looks up a stack map for a frame's return PC, which follows a
+// call, or for a function that is about to grow its stack, and it never
+// stops a goroutine between two non-call instructions.
 func issafepoint(prog *obj.Prog) bool {
 	return prog.As == obj.ATEXT || prog.As == obj.ACALL
 }
@@ -1094,6 +1129,7 @@ func livenesssolve(lv *Liveness) {
 
 	for change := true; change; {
 		change = false
+		counts.liveness++
 		for _, bb := range lv.cfg {
 			bvresetall(any)
 			bvresetall(all)
@@ -1129,6 +1165,7 @@ func livenesssolve(lv *Liveness) {
 
 	for change := true; change; {
 		change = false
+		counts.liveness++
 
 		// Walk blocks in the general direction of propagation. This
 		// improves convergence.
@@ -1163,16 +1200,16 @@ func livenesssolve(lv *Liveness) {
 
 // This function is slow but it is only used for generating debug prints.
 // Check whether n is marked live in args/locals.
-func islive(n *Node, args Bvec, locals Bvec) bool {
+func islive(n *ir.Node, args Bvec, locals Bvec) bool {
 	switch n.Class {
-	case PPARAM, PPARAMOUT:
+	case ir.PPARAM, ir.PPARAMOUT:
 		for i := 0; int64(i) < n.Type.Width/int64(Widthptr); i++ {
 			if bvget(args, int32(n.Xoffset/int64(Widthptr)+int64(i))) != 0 {
 				return true
 			}
 		}
 
-	case PAUTO:
+	case ir.PAUTO:
 		for i := 0; int64(i) < n.Type.Width/int64(Widthptr); i++ {
 			if bvget(locals, int32((n.Xoffset+stkptrsize)/int64(Widthptr)+int64(i))) != 0 {
 				return true
@@ -1241,7 +1278,7 @@ func livenessepilogue(lv *Liveness) {
 						if !n.Name.Needzero {
 							n.Name.Needzero = true
 							if debuglive >= 1 {
-								Warnl(p.Lineno, "%v: %v is ambiguously live", Curfn.Func.Nname, Nconv(n, FmtLong))
+								Warnl(p.Lineno, "%v: %v is ambiguously live", Curfn.Func.Nname, Nconv(n, ir.FmtLong))
 							}
 
 							// Record in 'ambiguous' bitmap.
@@ -1327,6 +1364,13 @@ func livenessepilogue(lv *Liveness) {
 				// Found an interesting instruction, record the
 				// corresponding liveness information.
 
+				// The function entry has no PCDATA annotation:
+				// the runtime uses the first stack map for it, so
+				// the entry must have index 0 and the calls must not.
+				if (p.As == obj.ATEXT) != (pos == 0) {
+					Fatalf("livenessepilogue: %v has stack map index %d", p, pos)
+				}
+
 				// Useful sanity check: on entry to the function,
 				// the only things that can possibly be live are the
 				// input parameters.
@@ -1336,8 +1380,8 @@ func livenessepilogue(lv *Liveness) {
 							continue
 						}
 						n := lv.vars[j]
-						if n.Class != PPARAM {
-							yyerrorl(p.Lineno, "internal error: %v %v recorded as live on entry, p.Pc=%v", Curfn.Func.Nname, Nconv(n, FmtLong), p.Pc)
+						if n.Class != ir.PPARAM {
+							yyerrorl(p.Lineno, "internal error: %v %v recorded as live on entry, p.Pc=%v", Curfn.Func.Nname, Nconv(n, ir.FmtLong), p.Pc)
 						}
 					}
 				}
@@ -1371,7 +1415,7 @@ func livenessepilogue(lv *Liveness) {
 					} else if p.As == obj.ACALL {
 						fmt_ += "indirect call:"
 					} else {
-						fmt_ += fmt.Sprintf("entry to %s:", ((p.From.Node).(*Node)).Sym.Name)
+						fmt_ += fmt.Sprintf("entry to %s:", ((p.From.Node).(*ir.Node)).Sym.Name)
 					}
 					numlive := 0
 					for j := 0; j < len(lv.vars); j++ {
@@ -1544,7 +1588,7 @@ func livenesscompact(lv *Liveness) {
 	}
 }
 
-func printbitset(printed bool, name string, vars []*Node, bits Bvec) bool {
+func printbitset(printed bool, name string, vars []*ir.Node, bits Bvec) bool {
 	started := false
 	for i, n := range vars {
 		if bvget(bits, int32(i)) == 0 {
@@ -1671,7 +1715,7 @@ func livenessprintdebug(lv *Liveness) {
 // first word dumped is the total number of bitmaps. The second word is the
 // length of the bitmaps. All bitmaps are assumed to be of equal length. The
 // words that are followed are the raw bitmap words.
-func onebitwritesymbol(arr []Bvec, sym *Sym) {
+func onebitwritesymbol(arr []Bvec, sym *ir.Sym) {
 	off := 4                                  // number of bitmaps, to fill in later
 	off = duint32(sym, off, uint32(arr[0].n)) // number of bits in each bitmap
 	var i int
@@ -1708,7 +1752,7 @@ func printprog(p *obj.Prog) {
 // Entry pointer for liveness analysis. Constructs a complete CFG, solves for
 // the liveness of pointer variables in the function, and emits a runtime data
 // structure read by the garbage collector.
-func liveness(fn *Node, firstp *obj.Prog, argssym *Sym, livesym *Sym) {
+func liveness(fn *ir.Node, firstp *obj.Prog, argssym *ir.Sym, livesym *ir.Sym) {
 	// Change name to dump debugging information only for a specific function.
 	debugdelta := 0
 
@@ -1731,6 +1775,18 @@ func liveness(fn *Node, firstp *obj.Prog, argssym *Sym, livesym *Sym) {
 		printcfg(cfg)
 	}
 	vars := getvariables(fn)
+	if words := int64(bvsize(uint32(len(vars)))/4) * int64(len(cfg)) * 7; words > 1<<31-1 {
+		// newliveness allocates 7 bitmaps per block.
+		tooLarge(fmt.Sprintf("liveness bitmaps for %d variables in %d blocks too large", len(vars), len(cfg)))
+		for _, ln := range fn.Func.Dcl {
+			if ln != nil {
+				ln.SetOpt(nil)
+			}
+		}
+		freecfg(cfg)
+		debuglive -= debugdelta
+		return
+	}
 	lv := newliveness(fn, firstp, cfg, vars)
 
 	// Run the dataflow framework.
@@ -33,6 +33,7 @@
 package gc
 
 import (
+	"cmd/compile/internal/ir"
 	"cmd/internal/obj"
 	"fmt"
 	"sort"
@@ -50,7 +51,7 @@ type OptStats struct {
 
 var Ostats OptStats
 
-var noreturn_symlist [10]*Sym
+var noreturn_symlist [10]*ir.Sym
 
 // p is a call instruction. Does the call fail to return?
 func Noreturn(p *obj.Prog) bool {
@@ -68,7 +69,7 @@ func Noreturn(p *obj.Prog) bool {
 	if p.To.Node == nil {
 		return false
 	}
-	s := ((p.To.Node).(*Node)).Sym
+	s := ((p.To.Node).(*ir.Node)).Sym
 	if s == nil {
 		return false
 	}
@@ -256,6 +257,9 @@ func growffcache(n int) {
 }
 
 func Flowstart(firstp *obj.Prog, newData func() interface{}) *Graph {
+	// New graph, new marks for Copyprop.
+	copyactive = 0
+
 	// Count and mark instructions to annotate.
 	nf := 0
 
@@ -533,7 +537,7 @@ func Uniqs(r *Flow) *Flow {
 // ACM TOPLAS 1999.
 
 type TempVar struct {
-	node    *Node
+	node    *ir.Node
 	def     *Flow    // definition of temp var
 	use     *Flow    // use list, chained through Flow.data
 	merge   *TempVar // merge var with this one
@@ -573,8 +577,8 @@ func (x startcmp) Less(i, j int) bool {
 }
 
 // Is n available for merging?
-func canmerge(n *Node) bool {
-	return n.Class == PAUTO && strings.HasPrefix(n.Sym.Name, "autotmp")
+func canmerge(n *ir.Node) bool {
+	return n.Class == ir.PAUTO && strings.HasPrefix(n.Sym.Name, "autotmp")
 }
 
 func mergetemp(firstp *obj.Prog) {
@@ -604,16 +608,16 @@ func mergetemp(firstp *obj.Prog) {
 	// single-use (that's why we have so many!).
 	for f := g.Start; f != nil; f = f.Link {
 		p := f.Prog
-		if p.From.Node != nil && ((p.From.Node).(*Node)).Opt() != nil && p.To.Node != nil && ((p.To.Node).(*Node)).Opt() != nil {
+		if p.From.Node != nil && ((p.From.Node).(*ir.Node)).Opt() != nil && p.To.Node != nil && ((p.To.Node).(*ir.Node)).Opt() != nil {
 			Fatalf("double node %v", p)
 		}
 		var v *TempVar
-		n, _ := p.From.Node.(*Node)
+		n, _ := p.From.Node.(*ir.Node)
 		if n != nil {
 			v, _ = n.Opt().(*TempVar)
 		}
 		if v == nil {
-			n, _ = p.To.Node.(*Node)
+			n, _ = p.To.Node.(*ir.Node)
 			if n != nil {
 				v, _ = n.Opt().(*TempVar)
 			}
@@ -671,7 +675,7 @@ func mergetemp(firstp *obj.Prog) {
 			)
 			if p.From.Node == v.node && p1.To.Node == v.node && (p.Info.Flags&Move != 0) && (p.Info.Flags|p1.Info.Flags)&(LeftAddr|RightAddr) == 0 && p.Info.Flags&SizeAny == p1.Info.Flags&SizeAny {
 				p1.From = p.From
-				Thearch.Excise(f)
+				Excise(f)
 				v.removed = true
 				if debugmerge > 0 && Debug['v'] != 0 {
 					fmt.Printf("drop immediate-use %v\n", v.node.Sym)
@@ -718,7 +722,7 @@ func mergetemp(firstp *obj.Prog) {
 	nfree := len(bystart)
 	for _, v := range bystart {
 		if debugmerge > 0 && Debug['v'] != 0 {
-			fmt.Printf("consider %v: removed=%t\n", Nconv(v.node, FmtSharp), v.removed)
+			fmt.Printf("consider %v: removed=%t\n", Nconv(v.node, ir.FmtSharp), v.removed)
 		}
 
 		if v.removed {
@@ -733,7 +737,7 @@ func mergetemp(firstp *obj.Prog) {
 		}
 
 		if debugmerge > 0 && Debug['v'] != 0 {
-			fmt.Printf("consider %v: removed=%t nfree=%d nvar=%d\n", Nconv(v.node, FmtSharp), v.removed, nfree, len(bystart))
+			fmt.Printf("consider %v: removed=%t nfree=%d nvar=%d\n", Nconv(v.node, ir.FmtSharp), v.removed, nfree, len(bystart))
 		}
 
 		// Find old temp to reuse if possible.
@@ -742,7 +746,7 @@ func mergetemp(firstp *obj.Prog) {
 		for j := nfree; j < len(inuse); j++ {
 			v1 := inuse[j]
 			if debugmerge > 0 && Debug['v'] != 0 {
-				fmt.Printf("consider %v: maybe %v: type=%v,%v addrtaken=%v,%v\n", Nconv(v.node, FmtSharp), Nconv(v1.node, FmtSharp), t, v1.node.Type, v.node.Addrtaken, v1.node.Addrtaken)
+				fmt.Printf("consider %v: maybe %v: type=%v,%v addrtaken=%v,%v\n", Nconv(v.node, ir.FmtSharp), Nconv(v1.node, ir.FmtSharp), t, v1.node.Type, v.node.Addrtaken, v1.node.Addrtaken)
 			}
 
 			// Require the types to match but also require the addrtaken bits to match.
@@ -778,7 +782,7 @@ func mergetemp(firstp *obj.Prog) {
 	if debugmerge > 0 && Debug['v'] != 0 {
 		fmt.Printf("%v [%d - %d]\n", Curfn.Func.Nname.Sym, len(vars), nkill)
 		for _, v := range vars {
-			fmt.Printf("var %v %v %d-%d", Nconv(v.node, FmtSharp), v.node.Type, v.start, v.end)
+			fmt.Printf("var %v %v %d-%d", Nconv(v.node, ir.FmtSharp), v.node.Type, v.start, v.end)
 			if v.addr {
 				fmt.Printf(" addr=true")
 			}
@@ -786,7 +790,7 @@ func mergetemp(firstp *obj.Prog) {
 				fmt.Printf(" removed=true")
 			}
 			if v.merge != nil {
-				fmt.Printf(" merge %v", Nconv(v.merge.node, FmtSharp))
+				fmt.Printf(" merge %v", Nconv(v.merge.node, ir.FmtSharp))
 			}
 			if v.start == v.end && v.def != nil {
 				fmt.Printf(" %v", v.def.Prog)
@@ -802,14 +806,14 @@ func mergetemp(firstp *obj.Prog) {
 	// Update node references to use merged temporaries.
 	for f := g.Start; f != nil; f = f.Link {
 		p := f.Prog
-		n, _ := p.From.Node.(*Node)
+		n, _ := p.From.Node.(*ir.Node)
 		if n != nil {
 			v, _ := n.Opt().(*TempVar)
 			if v != nil && v.merge != nil {
 				p.From.Node = v.merge.node
 			}
 		}
-		n, _ = p.To.Node.(*Node)
+		n, _ = p.To.Node.(*ir.Node)
 		if n != nil {
 			v, _ := n.Opt().(*TempVar)
 			if v != nil && v.merge != nil {
@@ -819,7 +823,7 @@ func mergetemp(firstp *obj.Prog) {
 	}
 
 	// Delete merged nodes from declaration list.
-	dcl := make([]*Node, 0, len(Curfn.Func.Dcl)-nkill)
+	dcl := make([]*ir.Node, 0, len(Curfn.Func.Dcl)-nkill)
 	for _, n := range Curfn.Func.Dcl {
 		v, _ := n.Opt().(*TempVar)
 		if v != nil && (v.merge != nil || v.removed) {
@@ -953,7 +957,7 @@ func nilopt(firstp *obj.Prog) {
 	for f := g.Start; f != nil; f = f.Link {
 		if f.Data != nil {
 			nkill++
-			Thearch.Excise(f)
+			Excise(f)
 		}
 	}
 
@@ -5,6 +5,7 @@
 package gc
 
 import (
+	"cmd/compile/internal/ir"
 	"fmt"
 	"strings"
 )
@@ -20,8 +21,21 @@ import (
 //
 // For flag_msan:
 //
-// 1. It inserts a call to msanread before each memory read.
-// 2. It inserts a call to msanwrite before each memory write.
+// 1. It inserts a call to msanwrite for the argument area at the beginning of each function.
+// 2. It inserts a call to msanread before each memory read.
+// 3. It inserts a call to msanwrite before each memory write.
+//
+// For flag_asan:
+//
+// 1. It inserts calls to asanunpoison for the argument area and each
+//    address-taken local variable at the beginning of each function.
+// 2. It inserts calls to asanpoison for the same variables at the end of each function.
+// 3. It inserts a call to asanread before each memory read.
+// 4. It inserts a call to asanwrite before each memory write.
+//
+// Functions marked //go:norace are not instrumented at all. Functions
+// marked //go:nomsan or //go:noasan are not instrumented for that
+// sanitizer only.
 //
 // The rewriting is not yet complete. Certain nodes are not rewritten
 // but should be.
@@ -31,7 +45,7 @@ import (
 
 // Do not instrument the following packages at all,
 // at best instrumentation would cause infinite recursion.
-var omit_pkgs = []string{"runtime/internal/atomic", "runtime/internal/sys", "runtime", "runtime/race", "runtime/msan"}
+var omit_pkgs = []string{"runtime/internal/atomic", "runtime/internal/sys", "runtime", "runtime/race", "runtime/msan", "runtime/asan"}
 
 // Only insert racefuncenterfp/racefuncexit into the following packages.
 // Memory accesses in the packages are either uninteresting or will cause false positives.
@@ -49,8 +63,22 @@ func ispkgin(pkgs []string) bool {
 	return false
 }
 
-func instrument(fn *Node) {
-	if ispkgin(omit_pkgs) || fn.Func.Pragma&Norace != 0 {
+// noinstrument reports whether fn has opted out of instrumentation
+// for the sanitizer in use.
+func noinstrument(fn *ir.Node) bool {
+	switch {
+	case flag_race != 0:
+		return fn.Func.Pragma&ir.Norace != 0
+	case flag_msan != 0:
+		return fn.Func.Pragma&(ir.Norace|ir.Nomsan) != 0
+	case flag_asan != 0:
+		return fn.Func.Pragma&(ir.Norace|ir.Noasan) != 0
+	}
+	return false
+}
+
+func instrument(fn *ir.Node) {
+	if ispkgin(omit_pkgs) || noinstrument(fn) {
 		return
 	}
 
@@ -66,14 +94,49 @@ func instrument(fn *Node) {
 		// getcallerpc. We use -widthptr(FP) for x86.
 		// BUG: this will not work on arm.
 		nodpc := *nodfp
-		nodpc.Type = Types[TUINTPTR]
+		nodpc.Type = ir.Types[ir.TUINTPTR]
 		nodpc.Xoffset = int64(-Widthptr)
 		nd := mkcall("racefuncenter", nil, nil, &nodpc)
-		fn.Func.Enter.Set(append([]*Node{nd}, fn.Func.Enter.Slice()...))
+		fn.Func.Enter.Set(append([]*ir.Node{nd}, fn.Func.Enter.Slice()...))
 		nd = mkcall("racefuncexit", nil, nil)
 		fn.Func.Exit.Append(nd)
 	}
 
+	if flag_msan != 0 && fn.Type.Argwid > 0 {
+		// The caller's stores to the arguments are not instrumented.
+		// Mark the whole argument area, results included, as written,
+		// so that msan sees initialized memory when the arguments
+		// are read through pointers or by C code.
+		var init ir.Nodes
+		nd := mkcall("msanwrite", nil, &init, argsaddr(), Nodintconst(fn.Type.Argwid))
+		init.Append(nd)
+		fn.Func.Enter.Set(append(init.Slice(), fn.Func.Enter.Slice()...))
+	}
+
+	if flag_asan != 0 {
+		// Pointers to the function's local variables may outlive
+		// the function, for example if they are kept by C code.
+		// Poison the variables on return, so that asan catches
+		// such uses, and unpoison them on entry, because the
+		// same stack memory may have been poisoned by an earlier call.
+		// The argument area is in the caller's frame and may have
+		// been poisoned the same way.
+		var enter, exit ir.Nodes
+		if fn.Type.Argwid > 0 {
+			enter.Append(mkcall("asanunpoison", nil, &enter, argsaddr(), Nodintconst(fn.Type.Argwid)))
+		}
+		for _, n := range fn.Func.Dcl {
+			if n.Op != ir.ONAME || n.Class != ir.PAUTO || !n.Addrtaken || n.Type.Width == 0 {
+				continue
+			}
+			w := Nodintconst(n.Type.Width)
+			enter.Append(mkcall("asanunpoison", nil, &enter, uintptraddr(n), w))
+			exit.Append(mkcall("asanpoison", nil, &exit, uintptraddr(n), w))
+		}
+		fn.Func.Enter.Set(append(enter.Slice(), fn.Func.Enter.Slice()...))
+		fn.Func.Exit.AppendNodes(&exit)
+	}
+
 	if Debug['W'] != 0 {
 		s := fmt.Sprintf("after instrument %v", fn.Func.Nname.Sym)
 		dumplist(s, fn.Nbody)
@@ -84,10 +147,10 @@ func instrument(fn *Node) {
 	}
 }
 
-func instrumentlist(l Nodes, init *Nodes) {
+func instrumentlist(l ir.Nodes, init *ir.Nodes) {
 	s := l.Slice()
 	for i := range s {
-		var instr Nodes
+		var instr ir.Nodes
 		instrumentnode(&s[i], &instr, 0, 0)
 		if init == nil {
 			s[i].Ninit.AppendNodes(&instr)
@@ -100,7 +163,7 @@ func instrumentlist(l Nodes, init *Nodes) {
 // walkexpr and walkstmt combined
 // walks the tree and adds calls to the
 // instrumentation code to top-level (statement) nodes' init
-func instrumentnode(np **Node, init *Nodes, wr int, skip int) {
+func instrumentnode(np **ir.Node, init *ir.Nodes, wr int, skip int) {
 	n := *np
 
 	if n == nil {
@@ -134,21 +197,21 @@ func instrumentnode(np **Node, init *Nodes, wr int, skip int) {
 	default:
 		Fatalf("instrument: unknown node type %v", Oconv(n.Op, 0))
 
-	case OAS, OASWB, OAS2FUNC:
+	case ir.OAS, ir.OASWB, ir.OAS2FUNC:
 		instrumentnode(&n.Left, init, 1, 0)
 		instrumentnode(&n.Right, init, 0, 0)
 		goto ret
 
 		// can't matter
-	case OCFUNC, OVARKILL, OVARLIVE:
+	case ir.OCFUNC, ir.OVARKILL, ir.OVARLIVE:
 		goto ret
 
-	case OBLOCK:
-		var out []*Node
+	case ir.OBLOCK:
+		var out []*ir.Node
 		ls := n.List.Slice()
 		for i := 0; i < len(ls); i++ {
 			switch ls[i].Op {
-			case OCALLFUNC, OCALLMETH, OCALLINTER:
+			case ir.OCALLFUNC, ir.OCALLMETH, ir.OCALLINTER:
 				instrumentnode(&ls[i], &ls[i].Ninit, 0, 0)
 				out = append(out, ls[i])
 				// Scan past OAS nodes copying results off stack.
@@ -156,12 +219,12 @@ func instrumentnode(np **Node, init *Nodes, wr int, skip int) {
 				// instrumentation calls will smash the results.
 				// The assignments are to temporaries, so they cannot
 				// be involved in races and need not be instrumented.
-				for i+1 < len(ls) && ls[i+1].Op == OAS && iscallret(ls[i+1].Right) {
+				for i+1 < len(ls) && ls[i+1].Op == ir.OAS && iscallret(ls[i+1].Right) {
 					i++
 					out = append(out, ls[i])
 				}
 			default:
-				var outn Nodes
+				var outn ir.Nodes
 				outn.Set(out)
 				instrumentnode(&ls[i], &outn, 0, 0)
 				out = append(outn.Slice(), ls[i])
@@ -170,91 +233,91 @@ func instrumentnode(np **Node, init *Nodes, wr int, skip int) {
 		n.List.Set(out)
 		goto ret
 
-	case ODEFER:
+	case ir.ODEFER:
 		instrumentnode(&n.Left, init, 0, 0)
 		goto ret
 
-	case OPROC:
+	case ir.OPROC:
 		instrumentnode(&n.Left, init, 0, 0)
 		goto ret
 
-	case OCALLINTER:
+	case ir.OCALLINTER:
 		instrumentnode(&n.Left, init, 0, 0)
 		goto ret
 
 		// Instrument dst argument of runtime.writebarrier* calls
 	// as we do not instrument runtime code.
 	// typedslicecopy is instrumented in runtime.
-	case OCALLFUNC:
+	case ir.OCALLFUNC:
 		instrumentnode(&n.Left, init, 0, 0)
 		goto ret
 
-	case ONOT,
-		OMINUS,
-		OPLUS,
-		OREAL,
-		OIMAG,
-		OCOM,
-		OSQRT:
+	case ir.ONOT,
+		ir.OMINUS,
+		ir.OPLUS,
+		ir.OREAL,
+		ir.OIMAG,
+		ir.OCOM,
+		ir.OSQRT:
 		instrumentnode(&n.Left, init, wr, 0)
 		goto ret
 
-	case ODOTINTER:
+	case ir.ODOTINTER:
 		instrumentnode(&n.Left, init, 0, 0)
 		goto ret
 
-	case ODOT:
+	case ir.ODOT:
 		instrumentnode(&n.Left, init, 0, 1)
 		callinstr(&n, init, wr, skip)
 		goto ret
 
-	case ODOTPTR: // dst = (*x).f with implicit *; otherwise it's ODOT+OIND
+	case ir.ODOTPTR: // dst = (*x).f with implicit *; otherwise it's ODOT+OIND
 		instrumentnode(&n.Left, init, 0, 0)
 
 		callinstr(&n, init, wr, skip)
 		goto ret
 
-	case OIND: // *p
+	case ir.OIND: // *p
 		instrumentnode(&n.Left, init, 0, 0)
 
 		callinstr(&n, init, wr, skip)
 		goto ret
 
-	case OSPTR, OLEN, OCAP:
+	case ir.OSPTR, ir.OLEN, ir.OCAP:
 		instrumentnode(&n.Left, init, 0, 0)
-		if Istype(n.Left.Type, TMAP) {
-			n1 := Nod(OCONVNOP, n.Left, nil)
-			n1.Type = Ptrto(Types[TUINT8])
-			n1 = Nod(OIND, n1, nil)
+		if Istype(n.Left.Type, ir.TMAP) {
+			n1 := Nod(ir.OCONVNOP, n.Left, nil)
+			n1.Type = Ptrto(ir.Types[ir.TUINT8])
+			n1 = Nod(ir.OIND, n1, nil)
 			n1 = typecheck(n1, Erv)
 			callinstr(&n1, init, 0, skip)
 		}
 
 		goto ret
 
-	case OLSH,
-		ORSH,
-		OLROT,
-		OAND,
-		OANDNOT,
-		OOR,
-		OXOR,
-		OSUB,
-		OMUL,
-		OHMUL,
-		OEQ,
-		ONE,
-		OLT,
-		OLE,
-		OGE,
-		OGT,
-		OADD,
-		OCOMPLEX:
+	case ir.OLSH,
+		ir.ORSH,
+		ir.OLROT,
+		ir.OAND,
+		ir.OANDNOT,
+		ir.OOR,
+		ir.OXOR,
+		ir.OSUB,
+		ir.OMUL,
+		ir.OHMUL,
+		ir.OEQ,
+		ir.ONE,
+		ir.OLT,
+		ir.OLE,
+		ir.OGE,
+		ir.OGT,
+		ir.OADD,
+		ir.OCOMPLEX:
 		instrumentnode(&n.Left, init, wr, 0)
 		instrumentnode(&n.Right, init, wr, 0)
 		goto ret
 
-	case OANDAND, OOROR:
+	case ir.OANDAND, ir.OOROR:
 		instrumentnode(&n.Left, init, wr, 0)
 
 		// walk has ensured the node has moved to a location where
@@ -265,24 +328,24 @@ func instrumentnode(np **Node, init *Nodes, wr int, skip int) {
 
 		goto ret
 
-	case ONAME:
+	case ir.ONAME:
 		callinstr(&n, init, wr, skip)
 		goto ret
 
-	case OCONV:
+	case ir.OCONV:
 		instrumentnode(&n.Left, init, wr, 0)
 		goto ret
 
-	case OCONVNOP:
+	case ir.OCONVNOP:
 		instrumentnode(&n.Left, init, wr, 0)
 		goto ret
 
-	case ODIV, OMOD:
+	case ir.ODIV, ir.OMOD:
 		instrumentnode(&n.Left, init, wr, 0)
 		instrumentnode(&n.Right, init, wr, 0)
 		goto ret
 
-	case OINDEX:
+	case ir.OINDEX:
 		if !Isfixedarray(n.Left.Type) {
 			instrumentnode(&n.Left, init, 0, 0)
 		} else if !islvalue(n.Left) {
@@ -294,90 +357,90 @@ func instrumentnode(np **Node, init *Nodes, wr int, skip int) {
 		}
 
 		instrumentnode(&n.Right, init, 0, 0)
-		if n.Left.Type.Etype != TSTRING {
+		if n.Left.Type.Etype != ir.TSTRING {
 			callinstr(&n, init, wr, skip)
 		}
 		goto ret
 
-	case OSLICE, OSLICEARR, OSLICE3, OSLICE3ARR, OSLICESTR:
+	case ir.OSLICE, ir.OSLICEARR, ir.OSLICE3, ir.OSLICE3ARR, ir.OSLICESTR:
 		instrumentnode(&n.Left, init, 0, 0)
 		instrumentnode(&n.Right, init, 0, 0)
 		goto ret
 
-	case OKEY:
+	case ir.OKEY:
 		instrumentnode(&n.Left, init, 0, 0)
 		instrumentnode(&n.Right, init, 0, 0)
 		goto ret
 
-	case OADDR:
+	case ir.OADDR:
 		instrumentnode(&n.Left, init, 0, 1)
 		goto ret
 
 		// n->left is Type* which is not interesting.
-	case OEFACE:
+	case ir.OEFACE:
 		instrumentnode(&n.Right, init, 0, 0)
 
 		goto ret
 
-	case OITAB:
+	case ir.OITAB:
 		instrumentnode(&n.Left, init, 0, 0)
 		goto ret
 
 		// should not appear in AST by now
-	case OSEND,
-		ORECV,
-		OCLOSE,
-		ONEW,
-		OXCASE,
-		OXFALL,
-		OCASE,
-		OPANIC,
-		ORECOVER,
-		OCONVIFACE,
-		OCMPIFACE,
-		OMAKECHAN,
-		OMAKEMAP,
-		OMAKESLICE,
-		OCALL,
-		OCOPY,
-		OAPPEND,
-		ORUNESTR,
-		OARRAYBYTESTR,
-		OARRAYRUNESTR,
-		OSTRARRAYBYTE,
-		OSTRARRAYRUNE,
-		OINDEXMAP,
+	case ir.OSEND,
+		ir.ORECV,
+		ir.OCLOSE,
+		ir.ONEW,
+		ir.OXCASE,
+		ir.OXFALL,
+		ir.OCASE,
+		ir.OPANIC,
+		ir.ORECOVER,
+		ir.OCONVIFACE,
+		ir.OCMPIFACE,
+		ir.OMAKECHAN,
+		ir.OMAKEMAP,
+		ir.OMAKESLICE,
+		ir.OCALL,
+		ir.OCOPY,
+		ir.OAPPEND,
+		ir.ORUNESTR,
+		ir.OARRAYBYTESTR,
+		ir.OARRAYRUNESTR,
+		ir.OSTRARRAYBYTE,
+		ir.OSTRARRAYRUNE,
+		ir.OINDEXMAP,
 		// lowered to call
-		OCMPSTR,
-		OADDSTR,
-		ODOTTYPE,
-		ODOTTYPE2,
-		OAS2DOTTYPE,
-		OCALLPART,
+		ir.OCMPSTR,
+		ir.OADDSTR,
+		ir.ODOTTYPE,
+		ir.ODOTTYPE2,
+		ir.OAS2DOTTYPE,
+		ir.OCALLPART,
 		// lowered to PTRLIT
-		OCLOSURE,  // lowered to PTRLIT
-		ORANGE,    // lowered to ordinary for loop
-		OARRAYLIT, // lowered to assignments
-		OMAPLIT,
-		OSTRUCTLIT,
-		OAS2,
-		OAS2RECV,
-		OAS2MAPR,
-		OASOP:
+		ir.OCLOSURE,  // lowered to PTRLIT
+		ir.ORANGE,    // lowered to ordinary for loop
+		ir.OARRAYLIT, // lowered to assignments
+		ir.OMAPLIT,
+		ir.OSTRUCTLIT,
+		ir.OAS2,
+		ir.OAS2RECV,
+		ir.OAS2MAPR,
+		ir.OASOP:
 		Yyerror("instrument: %v must be lowered by now", Oconv(n.Op, 0))
 
 		goto ret
 
 		// impossible nodes: only appear in backend.
-	case ORROTC, OEXTEND:
+	case ir.ORROTC, ir.OEXTEND:
 		Yyerror("instrument: %v cannot exist now", Oconv(n.Op, 0))
 		goto ret
 
-	case OGETG:
+	case ir.OGETG:
 		Yyerror("instrument: OGETG can happen only in runtime which we don't instrument")
 		goto ret
 
-	case OFOR:
+	case ir.OFOR:
 		if n.Left != nil {
 			instrumentnode(&n.Left, &n.Left.Ninit, 0, 0)
 		}
@@ -386,45 +449,45 @@ func instrumentnode(np **Node, init *Nodes, wr int, skip int) {
 		}
 		goto ret
 
-	case OIF, OSWITCH:
+	case ir.OIF, ir.OSWITCH:
 		if n.Left != nil {
 			instrumentnode(&n.Left, &n.Left.Ninit, 0, 0)
 		}
 		goto ret
 
 		// just do generic traversal
-	case OCALLMETH,
-		ORETURN,
-		ORETJMP,
-		OSELECT,
-		OEMPTY,
-		OBREAK,
-		OCONTINUE,
-		OFALL,
-		OGOTO,
-		OLABEL:
+	case ir.OCALLMETH,
+		ir.ORETURN,
+		ir.ORETJMP,
+		ir.OSELECT,
+		ir.OEMPTY,
+		ir.OBREAK,
+		ir.OCONTINUE,
+		ir.OFALL,
+		ir.OGOTO,
+		ir.OLABEL:
 		goto ret
 
 		// does not require instrumentation
-	case OPRINT, // don't bother instrumenting it
-		OPRINTN,     // don't bother instrumenting it
-		OCHECKNIL,   // always followed by a read.
-		OPARAM,      // it appears only in fn->exit to copy heap params back
-		OCLOSUREVAR, // immutable pointer to captured variable
-		ODOTMETH,    // either part of CALLMETH or CALLPART (lowered to PTRLIT)
-		OINDREG,     // at this stage, only n(SP) nodes from nodarg
-		ODCL,        // declarations (without value) cannot be races
-		ODCLCONST,
-		ODCLTYPE,
-		OTYPE,
-		ONONAME,
-		OLITERAL,
-		OTYPESW: // ignored by code generation, do not instrument.
+	case ir.OPRINT, // don't bother instrumenting it
+		ir.OPRINTN,     // don't bother instrumenting it
+		ir.OCHECKNIL,   // always followed by a read.
+		ir.OPARAM,      // it appears only in fn->exit to copy heap params back
+		ir.OCLOSUREVAR, // immutable pointer to captured variable
+		ir.ODOTMETH,    // either part of CALLMETH or CALLPART (lowered to PTRLIT)
+		ir.OINDREG,     // at this stage, only n(SP) nodes from nodarg
+		ir.ODCL,        // declarations (without value) cannot be races
+		ir.ODCLCONST,
+		ir.ODCLTYPE,
+		ir.OTYPE,
+		ir.ONONAME,
+		ir.OLITERAL,
+		ir.OTYPESW: // ignored by code generation, do not instrument.
 		goto ret
 	}
 
 ret:
-	if n.Op != OBLOCK { // OBLOCK is handled above in a special way.
+	if n.Op != ir.OBLOCK { // OBLOCK is handled above in a special way.
 		instrumentlist(n.List, init)
 	}
 	instrumentlist(n.Nbody, nil)
@@ -432,11 +495,11 @@ ret:
 	*np = n
 }
 
-func isartificial(n *Node) bool {
+func isartificial(n *ir.Node) bool {
 	// compiler-emitted artificial things that we do not want to instrument,
 	// can't possibly participate in a data race.
-	// can't be seen by C/C++ and therefore irrelevant for msan.
-	if n.Op == ONAME && n.Sym != nil && n.Sym.Name != "" {
+	// can't be seen by C/C++ and therefore irrelevant for msan and asan.
+	if n.Op == ir.ONAME && n.Sym != nil && n.Sym.Name != "" {
 		if n.Sym.Name == "_" {
 			return true
 		}
@@ -451,6 +514,11 @@ func isartificial(n *Node) bool {
 			return true
 		}
 
+		// coverage counters are updated without synchronization by design
+		if n.Sym.Name == "cover·counters" {
+			return true
+		}
+
 		// go.itab is accessed only by the compiler and runtime (assume safe)
 		if n.Sym.Pkg != nil && n.Sym.Pkg.Name != "" && n.Sym.Pkg.Name == "go.itab" {
 			return true
@@ -460,13 +528,13 @@ func isartificial(n *Node) bool {
 	return false
 }
 
-func callinstr(np **Node, init *Nodes, wr int, skip int) bool {
+func callinstr(np **ir.Node, init *ir.Nodes, wr int, skip int) bool {
 	n := *np
 
 	//print("callinstr for %+N [ %O ] etype=%E class=%d\n",
 	//	  n, n->op, n->type ? n->type->etype : -1, n->class);
 
-	if skip != 0 || n.Type == nil || n.Type.Etype >= TIDEAL {
+	if skip != 0 || n.Type == nil || n.Type.Etype >= ir.TIDEAL {
 		return false
 	}
 	t := n.Type
@@ -480,13 +548,19 @@ func callinstr(np **Node, init *Nodes, wr int, skip int) bool {
 	if isartificial(b) {
 		return false
 	}
+
+	// Memory on the goroutine's own stack is not shared
+	// with other goroutines, so accesses to it cannot race.
+	if flag_race != 0 && onstack(b) {
+		return false
+	}
 	class := b.Class
 
 	// BUG: we _may_ want to instrument PAUTO sometimes
 	// e.g. if we've got a local variable/method receiver
 	// that has got a pointer inside. Whether it points to
 	// the heap or not is impossible to know at compile time
-	if (class&PHEAP != 0) || class == PPARAMREF || class == PEXTERN || b.Op == OINDEX || b.Op == ODOTPTR || b.Op == OIND {
+	if (class&ir.PHEAP != 0) || class == ir.PPARAMREF || class == ir.PEXTERN || b.Op == ir.OINDEX || b.Op == ir.ODOTPTR || b.Op == ir.OIND {
 		hascalls := 0
 		foreach(n, hascallspred, &hascalls)
 		if hascalls != 0 {
@@ -496,20 +570,25 @@ func callinstr(np **Node, init *Nodes, wr int, skip int) bool {
 
 		n = treecopy(n, 0)
 		makeaddable(n)
-		var f *Node
-		if flag_msan != 0 {
-			name := "msanread"
+		var f *ir.Node
+		if flag_msan != 0 || flag_asan != 0 {
+			name := "read"
 			if wr != 0 {
-				name = "msanwrite"
+				name = "write"
+			}
+			if flag_msan != 0 {
+				name = "msan" + name
+			} else {
+				name = "asan" + name
 			}
 			// dowidth may not have been called for PEXTERN.
 			dowidth(t)
 			w := t.Width
-			if w == BADWIDTH {
+			if w == ir.BADWIDTH {
 				Fatalf("instrument: %v badwidth", t)
 			}
 			f = mkcall(name, nil, init, uintptraddr(n), Nodintconst(w))
-		} else if flag_race != 0 && (t.Etype == TSTRUCT || Isfixedarray(t)) {
+		} else if flag_race != 0 && (t.Etype == ir.TSTRUCT || Isfixedarray(t)) {
 			name := "racereadrange"
 			if wr != 0 {
 				name = "racewriterange"
@@ -517,7 +596,7 @@ func callinstr(np **Node, init *Nodes, wr int, skip int) bool {
 			// dowidth may not have been called for PEXTERN.
 			dowidth(t)
 			w := t.Width
-			if w == BADWIDTH {
+			if w == ir.BADWIDTH {
 				Fatalf("instrument: %v badwidth", t)
 			}
 			f = mkcall(name, nil, init, uintptraddr(n), Nodintconst(w))
@@ -536,71 +615,103 @@ func callinstr(np **Node, init *Nodes, wr int, skip int) bool {
 	return false
 }
 
+// onstack reports whether the outer value n of a memory access is
+// known to be on the stack of the current goroutine. Besides local
+// variables, which callinstr does not instrument anyway, this covers
+// variables captured by reference by closures that escape analysis
+// left on the stack, and dereferences of addresses of such variables.
+func onstack(n *ir.Node) bool {
+	switch n.Op {
+	case ir.ONAME:
+		for n.Class == ir.PPARAMREF {
+			n = n.Name.Param.Closure
+			if n == nil {
+				return false
+			}
+		}
+		return n.Class == ir.PAUTO || n.Class == ir.PPARAM || n.Class == ir.PPARAMOUT
+
+	case ir.OIND, ir.ODOTPTR:
+		l := n.Left
+		for l.Op == ir.OCONVNOP {
+			l = l.Left
+		}
+		return l.Op == ir.OADDR && onstack(outervalue(l.Left))
+	}
+	return false
+}
+
 // makeaddable returns a node whose memory location is the
 // same as n, but which is addressable in the Go language
 // sense.
 // This is different from functions like cheapexpr that may make
 // a copy of their argument.
-func makeaddable(n *Node) {
+func makeaddable(n *ir.Node) {
 	// The arguments to uintptraddr technically have an address but
 	// may not be addressable in the Go sense: for example, in the case
 	// of T(v).Field where T is a struct type and v is
 	// an addressable value.
 	switch n.Op {
-	case OINDEX:
+	case ir.OINDEX:
 		if Isfixedarray(n.Left.Type) {
 			makeaddable(n.Left)
 		}
 
 		// Turn T(v).Field into v.Field
-	case ODOT, OXDOT:
-		if n.Left.Op == OCONVNOP {
+	case ir.ODOT, ir.OXDOT:
+		if n.Left.Op == ir.OCONVNOP {
 			n.Left = n.Left.Left
 		}
 		makeaddable(n.Left)
 
 		// nothing to do
-	case ODOTPTR:
+	case ir.ODOTPTR:
 		fallthrough
 	default:
 		break
 	}
 }
 
-func uintptraddr(n *Node) *Node {
-	r := Nod(OADDR, n, nil)
+func uintptraddr(n *ir.Node) *ir.Node {
+	r := Nod(ir.OADDR, n, nil)
 	r.Bounded = true
-	r = conv(r, Types[TUNSAFEPTR])
-	r = conv(r, Types[TUINTPTR])
+	r = conv(r, ir.Types[ir.TUNSAFEPTR])
+	r = conv(r, ir.Types[ir.TUINTPTR])
 	return r
 }
 
-func detachexpr(n *Node, init *Nodes) *Node {
-	addr := Nod(OADDR, n, nil)
+// argsaddr returns the address of the current function's
+// argument area as a uintptr.
+func argsaddr() *ir.Node {
+	return conv(conv(Nod(ir.OADDR, nodfp, nil), ir.Types[ir.TUNSAFEPTR]), ir.Types[ir.TUINTPTR])
+}
+
+func detachexpr(n *ir.Node, init *ir.Nodes) *ir.Node {
+	addr := Nod(ir.OADDR, n, nil)
 	l := temp(Ptrto(n.Type))
-	as := Nod(OAS, l, addr)
+	as := Nod(ir.OAS, l, addr)
 	as = typecheck(as, Etop)
 	as = walkexpr(as, init)
 	init.Append(as)
-	ind := Nod(OIND, l, nil)
+	ind := Nod(ir.OIND, l, nil)
 	ind = typecheck(ind, Erv)
 	ind = walkexpr(ind, init)
 	return ind
 }
 
-func foreachnode(n *Node, f func(*Node, interface{}), c interface{}) {
+func foreachnode(n *ir.Node, f func(*ir.Node, interface{}), c interface{}) {
 	if n != nil {
 		f(n, c)
 	}
 }
 
-func foreachlist(l Nodes, f func(*Node, interface{}), c interface{}) {
+func foreachlist(l ir.Nodes, f func(*ir.Node, interface{}), c interface{}) {
 	for _, n := range l.Slice() {
 		foreachnode(n, f, c)
 	}
 }
 
-func foreach(n *Node, f func(*Node, interface{}), c interface{}) {
+func foreach(n *ir.Node, f func(*ir.Node, interface{}), c interface{}) {
 	foreachlist(n.Ninit, f, c)
 	foreachnode(n.Left, f, c)
 	foreachnode(n.Right, f, c)
@@ -609,16 +720,16 @@ func foreach(n *Node, f func(*Node, interface{}), c interface{}) {
 	foreachlist(n.Rlist, f, c)
 }
 
-func hascallspred(n *Node, c interface{}) {
+func hascallspred(n *ir.Node, c interface{}) {
 	switch n.Op {
-	case OCALL, OCALLFUNC, OCALLMETH, OCALLINTER:
+	case ir.OCALL, ir.OCALLFUNC, ir.OCALLMETH, ir.OCALLINTER:
 		(*c.(*int))++
 	}
 }
 
 // appendinit is like addinit in subr.go
 // but appends rather than prepends.
-func appendinit(np **Node, init Nodes) {
+func appendinit(np **ir.Node, init ir.Nodes) {
 	if init.Len() == 0 {
 		return
 	}
@@ -627,8 +738,8 @@ func appendinit(np **Node, init Nodes) {
 	switch n.Op {
 	// There may be multiple refs to this node;
 	// introduce OCONVNOP to hold init list.
-	case ONAME, OLITERAL:
-		n = Nod(OCONVNOP, n, nil)
+	case ir.ONAME, ir.OLITERAL:
+		n = Nod(ir.OCONVNOP, n, nil)
 
 		n.Type = n.Left.Type
 		n.Typecheck = 1
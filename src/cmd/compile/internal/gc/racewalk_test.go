@@ -0,0 +1,147 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gc
+
+import (
+	"internal/testenv"
+	"strings"
+	"testing"
+)
+
+// Make sure -msan marks the argument area of a function as written
+// on entry, even if the function reads no memory.
+func TestMSanArgs(t *testing.T) {
+	testenv.MustHaveGoBuild(t)
+
+	src := "package p\nfunc f(x, y int) int { return x + y }\n"
+	if out := compileS(t, src); strings.Contains(out, "msanwrite") {
+		t.Errorf("msanwrite call without -msan:\n%s", out)
+	}
+	if out := compileS(t, src, "-msan"); !strings.Contains(out, "CALL\truntime.msanwrite(SB)") {
+		t.Errorf("no msanwrite call with -msan:\n%s", out)
+	}
+}
+
+// Make sure -asan poisons address-taken local variables on return,
+// unpoisons them and the argument area on entry, and checks memory
+// accesses through pointers.
+func TestASanFrame(t *testing.T) {
+	testenv.MustHaveGoBuild(t)
+
+	src := `package p
+
+//go:noinline
+func g(p *int) int { return *p }
+
+func f(x int) int {
+	y := x
+	return g(&y)
+}
+`
+	if out := compileS(t, src); strings.Contains(out, "asan") {
+		t.Errorf("asan call without -asan:\n%s", out)
+	}
+	out := compileS(t, src, "-asan")
+	for _, call := range []string{"asanread", "asanunpoison", "asanpoison"} {
+		if !strings.Contains(out, "CALL\truntime."+call+"(SB)") {
+			t.Errorf("no %s call with -asan:\n%s", call, out)
+		}
+	}
+}
+
+// Make sure -race does not instrument accesses to variables
+// captured by closures that stay on the stack, but still does
+// for closures run by another goroutine.
+func TestRaceStackClosure(t *testing.T) {
+	testenv.MustHaveGoBuild(t)
+
+	src := `package p
+
+func stack() int {
+	x := 0
+	func() {
+		x++
+	}()
+	f := func() {
+		x++
+	}
+	f()
+	return x
+}
+
+func shared() int {
+	x := 0
+	done := make(chan bool)
+	go func() {
+		x++
+		done <- true
+	}()
+	<-done
+	return x
+}
+`
+	out := compileS(t, src, "-race")
+	for _, fn := range []string{"stack.func1", "stack.func2"} {
+		text := funcText(out, fn)
+		if text == "" {
+			t.Errorf("%s: not found in assembly listing:\n%s", fn, out)
+		} else if strings.Contains(text, "runtime.racewrite") {
+			t.Errorf("%s: access to stack variable instrumented:\n%s", fn, text)
+		}
+	}
+	if text := funcText(out, "shared.func1"); !strings.Contains(text, "runtime.racewrite") {
+		t.Errorf("shared.func1: access to shared variable not instrumented:\n%s", text)
+	}
+}
+
+// Make sure //go:norace turns off every sanitizer, as it always has,
+// //go:nomsan and //go:noasan each turn off only their own, and all
+// three keep the function from being inlined.
+func TestNoInstrumentPragma(t *testing.T) {
+	testenv.MustHaveGoBuild(t)
+
+	src := `package p
+
+var x int
+
+//go:norace
+func race() { x++ }
+
+//go:nomsan
+func msan() { x++ }
+
+//go:noasan
+func asan() { x++ }
+
+func f() {
+	race()
+	msan()
+	asan()
+}
+`
+	for _, tt := range []struct {
+		flag, call string
+		skip       []string
+	}{
+		{"-race", "runtime.racewrite", []string{"race"}},
+		{"-msan", "runtime.msanwrite", []string{"race", "msan"}},
+		{"-asan", "runtime.asanwrite", []string{"race", "asan"}},
+	} {
+		out := compileS(t, src, tt.flag)
+		skipped := make(map[string]bool)
+		for _, fn := range tt.skip {
+			skipped[fn] = true
+		}
+		for _, fn := range []string{"race", "msan", "asan"} {
+			text := funcText(out, fn)
+			if got, want := strings.Contains(text, tt.call), !skipped[fn]; got != want {
+				t.Errorf("%s: %s: instrumented = %v, want %v:\n%s", tt.flag, fn, got, want, text)
+			}
+			if text := funcText(out, "f"); skipped[fn] && !strings.Contains(text, "CALL\t\"\"."+fn+"(SB)") {
+				t.Errorf("%s: %s inlined into f:\n%s", tt.flag, fn, text)
+			}
+		}
+	}
+}
@@ -4,15 +4,17 @@
 
 package gc
 
+import "cmd/compile/internal/ir"
+
 // range
-func typecheckrange(n *Node) {
+func typecheckrange(n *ir.Node) {
 	var toomany int
 	var why string
-	var t1 *Type
-	var t2 *Type
-	var v1 *Node
-	var v2 *Node
-	var ls []*Node
+	var t1 *ir.Type
+	var t2 *ir.Type
+	var v1 *ir.Node
+	var v2 *ir.Node
+	var ls []*ir.Node
 
 	// Typechecking order is important here:
 	// 0. first typecheck range expression (slice/map/chan),
@@ -20,12 +22,15 @@ func typecheckrange(n *Node) {
 	// 1. typcheck produced values,
 	//	this part can declare new vars and so it must be typechecked before body,
 	//	because body can contain a closure that captures the vars.
+	//	It is assigned on every iteration, so markloop comes first.
 	// 2. decldepth++ to denote loop body.
 	// 3. typecheck body.
 	// 4. decldepth--.
 
 	n.Right = typecheck(n.Right, Erv)
 
+	markloop()
+
 	t := n.Right.Type
 	if t == nil {
 		goto out
@@ -46,18 +51,18 @@ func typecheckrange(n *Node) {
 	toomany = 0
 	switch t.Etype {
 	default:
-		Yyerror("cannot range over %v", Nconv(n.Right, FmtLong))
+		Yyerror("cannot range over %v", Nconv(n.Right, ir.FmtLong))
 		goto out
 
-	case TARRAY:
-		t1 = Types[TINT]
+	case ir.TARRAY:
+		t1 = ir.Types[ir.TINT]
 		t2 = t.Type
 
-	case TMAP:
+	case ir.TMAP:
 		t1 = t.Key()
 		t2 = t.Type
 
-	case TCHAN:
+	case ir.TCHAN:
 		if t.Chan&Crecv == 0 {
 			Yyerror("invalid operation: range %v (receive from send-only type %v)", n.Right, n.Right.Type)
 			goto out
@@ -69,9 +74,9 @@ func typecheckrange(n *Node) {
 			toomany = 1
 		}
 
-	case TSTRING:
-		t1 = Types[TINT]
-		t2 = runetype
+	case ir.TSTRING:
+		t1 = ir.Types[ir.TINT]
+		t2 = ir.Runetype
 	}
 
 	if n.List.Len() > 2 || toomany != 0 {
@@ -102,7 +107,7 @@ func typecheckrange(n *Node) {
 		if v1.Name != nil && v1.Name.Defn == n {
 			v1.Type = t1
 		} else if v1.Type != nil && assignop(t1, v1.Type, &why) == 0 {
-			Yyerror("cannot assign type %v to %v in range%s", t1, Nconv(v1, FmtLong), why)
+			Yyerror("cannot assign type %v to %v in range%s", t1, Nconv(v1, ir.FmtLong), why)
 		}
 		checkassign(n, v1)
 	}
@@ -111,7 +116,7 @@ func typecheckrange(n *Node) {
 		if v2.Name != nil && v2.Name.Defn == n {
 			v2.Type = t2
 		} else if v2.Type != nil && assignop(t2, v2.Type, &why) == 0 {
-			Yyerror("cannot assign type %v to %v in range%s", t2, Nconv(v2, FmtLong), why)
+			Yyerror("cannot assign type %v to %v in range%s", t2, Nconv(v2, ir.FmtLong), why)
 		}
 		checkassign(n, v2)
 	}
@@ -131,7 +136,7 @@ out:
 	decldepth--
 }
 
-func walkrange(n *Node) {
+func walkrange(n *ir.Node) {
 	// variable name conventions:
 	//	ohv1, hv1, hv2: hidden (old) val 1, 2
 	//	ha, hit: hidden aggregate, iterator
@@ -145,11 +150,11 @@ func walkrange(n *Node) {
 	lno := setlineno(a)
 	n.Right = nil
 
-	var v1 *Node
+	var v1 *ir.Node
 	if n.List.Len() != 0 {
 		v1 = n.List.First()
 	}
-	var v2 *Node
+	var v2 *ir.Node
 	if n.List.Len() > 1 && !isblank(n.List.Second()) {
 		v2 = n.List.Second()
 	}
@@ -158,45 +163,49 @@ func walkrange(n *Node) {
 	// to avoid erroneous processing by racewalk.
 	n.List.Set(nil)
 
-	var body []*Node
-	var init []*Node
+	var body []*ir.Node
+	var init []*ir.Node
 	switch t.Etype {
 	default:
 		Fatalf("walkrange")
 
-	case TARRAY:
+	case ir.TARRAY:
 		if memclrrange(n, v1, v2, a) {
 			lineno = lno
 			return
 		}
+		if memmoverange(n, v1, v2, a) {
+			lineno = lno
+			return
+		}
 
 		// orderstmt arranged for a copy of the array/slice variable if needed.
 		ha := a
 
-		hv1 := temp(Types[TINT])
-		hn := temp(Types[TINT])
-		var hp *Node
+		hv1 := temp(ir.Types[ir.TINT])
+		hn := temp(ir.Types[ir.TINT])
+		var hp *ir.Node
 
-		init = append(init, Nod(OAS, hv1, nil))
-		init = append(init, Nod(OAS, hn, Nod(OLEN, ha, nil)))
+		init = append(init, Nod(ir.OAS, hv1, nil))
+		init = append(init, Nod(ir.OAS, hn, Nod(ir.OLEN, ha, nil)))
 		if v2 != nil {
 			hp = temp(Ptrto(n.Type.Type))
-			tmp := Nod(OINDEX, ha, Nodintconst(0))
+			tmp := Nod(ir.OINDEX, ha, Nodintconst(0))
 			tmp.Bounded = true
-			init = append(init, Nod(OAS, hp, Nod(OADDR, tmp, nil)))
+			init = append(init, Nod(ir.OAS, hp, Nod(ir.OADDR, tmp, nil)))
 		}
 
-		n.Left = Nod(OLT, hv1, hn)
-		n.Right = Nod(OAS, hv1, Nod(OADD, hv1, Nodintconst(1)))
+		n.Left = Nod(ir.OLT, hv1, hn)
+		n.Right = Nod(ir.OAS, hv1, Nod(ir.OADD, hv1, Nodintconst(1)))
 		if v1 == nil {
 			body = nil
 		} else if v2 == nil {
-			body = []*Node{Nod(OAS, v1, hv1)}
+			body = []*ir.Node{Nod(ir.OAS, v1, hv1)}
 		} else {
-			a := Nod(OAS2, nil, nil)
-			a.List.Set([]*Node{v1, v2})
-			a.Rlist.Set([]*Node{hv1, Nod(OIND, hp, nil)})
-			body = []*Node{a}
+			a := Nod(ir.OAS2, nil, nil)
+			a.List.Set([]*ir.Node{v1, v2})
+			a.Rlist.Set([]*ir.Node{hv1, Nod(ir.OIND, hp, nil)})
+			body = []*ir.Node{a}
 
 			// Advance pointer as part of increment.
 			// We used to advance the pointer before executing the loop body,
@@ -206,20 +215,20 @@ func walkrange(n *Node) {
 			// Advancing during the increment ensures that the pointer p only points
 			// pass the end of the array during the final "p++; i++; if(i >= len(x)) break;",
 			// after which p is dead, so it cannot confuse the collector.
-			tmp := Nod(OADD, hp, Nodintconst(t.Type.Width))
+			tmp := Nod(ir.OADD, hp, Nodintconst(t.Type.Width))
 
 			tmp.Type = hp.Type
 			tmp.Typecheck = 1
-			tmp.Right.Type = Types[Tptr]
+			tmp.Right.Type = ir.Types[Tptr]
 			tmp.Right.Typecheck = 1
-			a = Nod(OAS, hp, tmp)
+			a = Nod(ir.OAS, hp, tmp)
 			a = typecheck(a, Etop)
 			n.Right.Ninit.Set1(a)
 		}
 
 		// orderstmt allocated the iterator for us.
 	// we only use a once, so no copy needed.
-	case TMAP:
+	case ir.TMAP:
 		ha := a
 
 		th := hiter(t)
@@ -232,30 +241,30 @@ func walkrange(n *Node) {
 		fn := syslook("mapiterinit")
 
 		fn = substArgTypes(fn, t.Key(), t.Type, th)
-		init = append(init, mkcall1(fn, nil, nil, typename(t), ha, Nod(OADDR, hit, nil)))
-		n.Left = Nod(ONE, NodSym(ODOT, hit, keysym), nodnil())
+		init = append(init, mkcall1(fn, nil, nil, typename(t), ha, Nod(ir.OADDR, hit, nil)))
+		n.Left = Nod(ir.ONE, NodSym(ir.ODOT, hit, keysym), nodnil())
 
 		fn = syslook("mapiternext")
 		fn = substArgTypes(fn, th)
-		n.Right = mkcall1(fn, nil, nil, Nod(OADDR, hit, nil))
+		n.Right = mkcall1(fn, nil, nil, Nod(ir.OADDR, hit, nil))
 
-		key := NodSym(ODOT, hit, keysym)
-		key = Nod(OIND, key, nil)
+		key := NodSym(ir.ODOT, hit, keysym)
+		key = Nod(ir.OIND, key, nil)
 		if v1 == nil {
 			body = nil
 		} else if v2 == nil {
-			body = []*Node{Nod(OAS, v1, key)}
+			body = []*ir.Node{Nod(ir.OAS, v1, key)}
 		} else {
-			val := NodSym(ODOT, hit, valsym)
-			val = Nod(OIND, val, nil)
-			a := Nod(OAS2, nil, nil)
-			a.List.Set([]*Node{v1, v2})
-			a.Rlist.Set([]*Node{key, val})
-			body = []*Node{a}
+			val := NodSym(ir.ODOT, hit, valsym)
+			val = Nod(ir.OIND, val, nil)
+			a := Nod(ir.OAS2, nil, nil)
+			a.List.Set([]*ir.Node{v1, v2})
+			a.Rlist.Set([]*ir.Node{key, val})
+			body = []*ir.Node{a}
 		}
 
 		// orderstmt arranged for a copy of the channel variable.
-	case TCHAN:
+	case ir.TCHAN:
 		ha := a
 
 		n.Left = nil
@@ -263,56 +272,56 @@ func walkrange(n *Node) {
 		hv1 := temp(t.Type)
 		hv1.Typecheck = 1
 		if haspointers(t.Type) {
-			init = append(init, Nod(OAS, hv1, nil))
+			init = append(init, Nod(ir.OAS, hv1, nil))
 		}
-		hb := temp(Types[TBOOL])
+		hb := temp(ir.Types[ir.TBOOL])
 
-		n.Left = Nod(ONE, hb, Nodbool(false))
-		a := Nod(OAS2RECV, nil, nil)
+		n.Left = Nod(ir.ONE, hb, Nodbool(false))
+		a := Nod(ir.OAS2RECV, nil, nil)
 		a.Typecheck = 1
-		a.List.Set([]*Node{hv1, hb})
-		a.Rlist.Set1(Nod(ORECV, ha, nil))
+		a.List.Set([]*ir.Node{hv1, hb})
+		a.Rlist.Set1(Nod(ir.ORECV, ha, nil))
 		n.Left.Ninit.Set1(a)
 		if v1 == nil {
 			body = nil
 		} else {
-			body = []*Node{Nod(OAS, v1, hv1)}
+			body = []*ir.Node{Nod(ir.OAS, v1, hv1)}
 		}
 
 		// orderstmt arranged for a copy of the string variable.
-	case TSTRING:
+	case ir.TSTRING:
 		ha := a
 
-		ohv1 := temp(Types[TINT])
+		ohv1 := temp(ir.Types[ir.TINT])
 
-		hv1 := temp(Types[TINT])
-		init = append(init, Nod(OAS, hv1, nil))
+		hv1 := temp(ir.Types[ir.TINT])
+		init = append(init, Nod(ir.OAS, hv1, nil))
 
-		var a *Node
-		var hv2 *Node
+		var a *ir.Node
+		var hv2 *ir.Node
 		if v2 == nil {
-			a = Nod(OAS, hv1, mkcall("stringiter", Types[TINT], nil, ha, hv1))
+			a = Nod(ir.OAS, hv1, mkcall("stringiter", ir.Types[ir.TINT], nil, ha, hv1))
 		} else {
-			hv2 = temp(runetype)
-			a = Nod(OAS2, nil, nil)
-			a.List.Set([]*Node{hv1, hv2})
+			hv2 = temp(ir.Runetype)
+			a = Nod(ir.OAS2, nil, nil)
+			a.List.Set([]*ir.Node{hv1, hv2})
 			fn := syslook("stringiter2")
 			a.Rlist.Set1(mkcall1(fn, fn.Type.Results(), nil, ha, hv1))
 		}
 
-		n.Left = Nod(ONE, hv1, Nodintconst(0))
-		n.Left.Ninit.Set([]*Node{Nod(OAS, ohv1, hv1), a})
+		n.Left = Nod(ir.ONE, hv1, Nodintconst(0))
+		n.Left.Ninit.Set([]*ir.Node{Nod(ir.OAS, ohv1, hv1), a})
 
 		body = nil
 		if v1 != nil {
-			body = []*Node{Nod(OAS, v1, ohv1)}
+			body = []*ir.Node{Nod(ir.OAS, v1, ohv1)}
 		}
 		if v2 != nil {
-			body = append(body, Nod(OAS, v2, hv2))
+			body = append(body, Nod(ir.OAS, v2, hv2))
 		}
 	}
 
-	n.Op = OFOR
+	n.Op = ir.OFOR
 	typecheckslice(init, Etop)
 	n.Ninit.Append(init...)
 	typecheckslice(n.Left.Ninit.Slice(), Etop)
@@ -336,7 +345,7 @@ func walkrange(n *Node) {
 // in which the evaluation of a is side-effect-free.
 //
 // Parameters are as in walkrange: "for v1, v2 = range a".
-func memclrrange(n, v1, v2, a *Node) bool {
+func memclrrange(n, v1, v2, a *ir.Node) bool {
 	if Debug['N'] != 0 || instrumenting {
 		return false
 	}
@@ -347,7 +356,7 @@ func memclrrange(n, v1, v2, a *Node) bool {
 		return false
 	}
 	stmt := n.Nbody.First() // only stmt in body
-	if stmt.Op != OAS || stmt.Left.Op != OINDEX {
+	if stmt.Op != ir.OAS || stmt.Left.Op != ir.OINDEX {
 		return false
 	}
 	if !samesafeexpr(stmt.Left.Left, a) || !samesafeexpr(stmt.Left.Right, v1) {
@@ -365,28 +374,28 @@ func memclrrange(n, v1, v2, a *Node) bool {
 	// 	memclr(hp, hn)
 	// 	i = len(a) - 1
 	// }
-	n.Op = OIF
+	n.Op = ir.OIF
 
 	n.Nbody.Set(nil)
-	n.Left = Nod(ONE, Nod(OLEN, a, nil), Nodintconst(0))
+	n.Left = Nod(ir.ONE, Nod(ir.OLEN, a, nil), Nodintconst(0))
 
 	// hp = &a[0]
-	hp := temp(Ptrto(Types[TUINT8]))
+	hp := temp(Ptrto(ir.Types[ir.TUINT8]))
 
-	tmp := Nod(OINDEX, a, Nodintconst(0))
+	tmp := Nod(ir.OINDEX, a, Nodintconst(0))
 	tmp.Bounded = true
-	tmp = Nod(OADDR, tmp, nil)
-	tmp = Nod(OCONVNOP, tmp, nil)
-	tmp.Type = Ptrto(Types[TUINT8])
-	n.Nbody.Append(Nod(OAS, hp, tmp))
+	tmp = Nod(ir.OADDR, tmp, nil)
+	tmp = Nod(ir.OCONVNOP, tmp, nil)
+	tmp.Type = Ptrto(ir.Types[ir.TUINT8])
+	n.Nbody.Append(Nod(ir.OAS, hp, tmp))
 
 	// hn = len(a) * sizeof(elem(a))
-	hn := temp(Types[TUINTPTR])
+	hn := temp(ir.Types[ir.TUINTPTR])
 
-	tmp = Nod(OLEN, a, nil)
-	tmp = Nod(OMUL, tmp, Nodintconst(elemsize))
-	tmp = conv(tmp, Types[TUINTPTR])
-	n.Nbody.Append(Nod(OAS, hn, tmp))
+	tmp = Nod(ir.OLEN, a, nil)
+	tmp = Nod(ir.OMUL, tmp, Nodintconst(elemsize))
+	tmp = conv(tmp, ir.Types[ir.TUINTPTR])
+	n.Nbody.Append(Nod(ir.OAS, hn, tmp))
 
 	// memclr(hp, hn)
 	fn := mkcall("memclr", nil, nil, hp, hn)
@@ -394,7 +403,7 @@ func memclrrange(n, v1, v2, a *Node) bool {
 	n.Nbody.Append(fn)
 
 	// i = len(a) - 1
-	v1 = Nod(OAS, v1, Nod(OSUB, Nod(OLEN, a, nil), Nodintconst(1)))
+	v1 = Nod(ir.OAS, v1, Nod(ir.OSUB, Nod(ir.OLEN, a, nil), Nodintconst(1)))
 
 	n.Nbody.Append(v1)
 
@@ -403,3 +412,152 @@ func memclrrange(n, v1, v2, a *Node) bool {
 	n = walkstmt(n)
 	return true
 }
+
+// Lower n into runtime·memmove if possible, for
+// fast copying between pointer-free slices and arrays.
+// Look for instances of
+//
+// for i := range a {
+// 	b[i] = a[i]
+// }
+//
+// in which the evaluation of a and b is side-effect-free.
+//
+// The loop copies element by element from low to high indexes,
+// so it only agrees with memmove when b does not start inside
+// the first len(a) elements of a. It also stops with an index
+// panic when b is shorter than a. Both cases are detected at
+// run time and fall back to the original loop.
+//
+// The rewrite evaluates b once, before the loop, so b must be
+// loop-invariant: b[i][i] = a[i] indexes a different row of b on
+// every iteration.
+//
+// Parameters are as in walkrange: "for v1, v2 = range a".
+func memmoverange(n, v1, v2, a *ir.Node) bool {
+	if Debug['N'] != 0 || instrumenting {
+		return false
+	}
+	if v1 == nil || v2 != nil {
+		return false
+	}
+	if n.Nbody.Len() != 1 || n.Nbody.First() == nil {
+		return false
+	}
+	stmt := n.Nbody.First() // only stmt in body
+	if stmt.Op != ir.OAS || stmt.Left.Op != ir.OINDEX || stmt.Right == nil || stmt.Right.Op != ir.OINDEX {
+		return false
+	}
+	b := stmt.Left.Left
+	if !samesafeexpr(stmt.Right.Left, a) || !samesafeexpr(stmt.Right.Right, v1) || !samesafeexpr(stmt.Left.Right, v1) {
+		return false
+	}
+	if !rangeinvariant(b) || !b.Type.IsSlice() && !b.Type.IsArray() {
+		return false
+	}
+	elem := n.Type.Type
+	elemsize := elem.Width
+	if elemsize <= 0 || haspointers(elem) || !Eqtype(elem, b.Type.Type) {
+		return false
+	}
+
+	// Convert to
+	// if len(a) != 0 {
+	// 	hn = len(a)
+	// 	hb = hn <= len(b)
+	// 	if hb {
+	// 		hp = &b[0]
+	// 		hq = &a[0]
+	// 		hs = hn*sizeof(elem(a))
+	// 		hb = uintptr(hp) <= uintptr(hq) || uintptr(hp) >= uintptr(hq)+hs
+	// 		if hb {
+	// 			memmove(hp, hq, hs)
+	// 			i = hn - 1
+	// 		}
+	// 	}
+	// 	if !hb {
+	// 		for hv1 := 0; hv1 < hn; hv1++ {
+	// 			i = hv1
+	// 			b[i] = a[i]
+	// 		}
+	// 	}
+	// }
+	body := stmt
+	n.Op = ir.OIF
+
+	n.Nbody.Set(nil)
+	n.Left = Nod(ir.ONE, Nod(ir.OLEN, a, nil), Nodintconst(0))
+
+	// hn = len(a)
+	hn := temp(ir.Types[ir.TINT])
+	n.Nbody.Append(Nod(ir.OAS, hn, Nod(ir.OLEN, a, nil)))
+
+	// hb = hn <= len(b)
+	hb := temp(ir.Types[ir.TBOOL])
+	n.Nbody.Append(Nod(ir.OAS, hb, Nod(ir.OLE, hn, Nod(ir.OLEN, b, nil))))
+
+	// b is long enough, so both indexes are in range.
+	nif := Nod(ir.OIF, hb, nil)
+
+	// hp = &b[0]; hq = &a[0]
+	hp := temp(Ptrto(ir.Types[ir.TUINT8]))
+	hq := temp(Ptrto(ir.Types[ir.TUINT8]))
+	for _, p := range []struct{ h, x *ir.Node }{{hp, b}, {hq, a}} {
+		tmp := Nod(ir.OINDEX, p.x, Nodintconst(0))
+		tmp.Bounded = true
+		tmp = Nod(ir.OADDR, tmp, nil)
+		tmp = Nod(ir.OCONVNOP, tmp, nil)
+		tmp.Type = Ptrto(ir.Types[ir.TUINT8])
+		nif.Nbody.Append(Nod(ir.OAS, p.h, tmp))
+	}
+
+	// hs = hn * sizeof(elem(a))
+	hs := temp(ir.Types[ir.TUINTPTR])
+	tmp := conv(Nod(ir.OMUL, hn, Nodintconst(elemsize)), ir.Types[ir.TUINTPTR])
+	nif.Nbody.Append(Nod(ir.OAS, hs, tmp))
+
+	// hb = uintptr(hp) <= uintptr(hq) || uintptr(hp) >= uintptr(hq)+hs
+	uintptrOf := func(p *ir.Node) *ir.Node {
+		u := Nod(ir.OCONVNOP, p, nil)
+		u.Type = ir.Types[ir.TUINTPTR]
+		return u
+	}
+	tmp = Nod(ir.OOROR,
+		Nod(ir.OLE, uintptrOf(hp), uintptrOf(hq)),
+		Nod(ir.OGE, uintptrOf(hp), Nod(ir.OADD, uintptrOf(hq), hs)))
+	nif.Nbody.Append(Nod(ir.OAS, hb, tmp))
+
+	// memmove(hp, hq, hs); i = hn - 1
+	fn := syslook("memmove")
+	fn = substArgTypes(fn, ir.Types[ir.TUINT8], ir.Types[ir.TUINT8])
+	move := Nod(ir.OIF, hb, nil)
+	move.Nbody.Append(mkcall1(fn, nil, nil, hp, hq, hs))
+	move.Nbody.Append(Nod(ir.OAS, v1, Nod(ir.OSUB, hn, Nodintconst(1))))
+	nif.Nbody.Append(move)
+
+	n.Nbody.Append(nif)
+
+	// for hv1 := 0; hv1 < hn; hv1++ { i = hv1; b[i] = a[i] }
+	hv1 := temp(ir.Types[ir.TINT])
+	loop := Nod(ir.OFOR, Nod(ir.OLT, hv1, hn), Nod(ir.OAS, hv1, Nod(ir.OADD, hv1, Nodintconst(1))))
+	loop.Ninit.Set1(Nod(ir.OAS, hv1, nil))
+	loop.Nbody.Set([]*ir.Node{Nod(ir.OAS, v1, hv1), body})
+	nif = Nod(ir.OIF, Nod(ir.ONOT, hb, nil), nil)
+	nif.Nbody.Set1(loop)
+	n.Nbody.Append(nif)
+
+	n.Left = typecheck(n.Left, Erv)
+	typecheckslice(n.Nbody.Slice(), Etop)
+	n = walkstmt(n)
+	return true
+}
+
+// rangeinvariant reports whether n is a variable or a field of one,
+// so that its value cannot change while a loop that only assigns
+// to its elements runs.
+func rangeinvariant(n *ir.Node) bool {
+	for n.Op == ir.ODOT {
+		n = n.Left
+	}
+	return n.Op == ir.ONAME
+}
@@ -5,6 +5,7 @@
 package gc
 
 import (
+	"cmd/compile/internal/ir"
 	"cmd/internal/gcprog"
 	"cmd/internal/obj"
 	"fmt"
@@ -14,7 +15,7 @@ import (
 )
 
 // runtime interface and reflection data structures
-var signatlist []*Node
+var signatlist []*ir.Node
 
 // byMethodNameAndPackagePath sorts method signatures by name, then package path.
 type byMethodNameAndPackagePath []*Sig
@@ -53,28 +54,28 @@ const (
 	MAXVALSIZE = 128
 )
 
-func structfieldSize() int       { return 3 * Widthptr } // Sizeof(runtime.structfield{})
-func imethodSize() int           { return 2 * Widthptr } // Sizeof(runtime.imethod{})
-func uncommonSize(t *Type) int { // Sizeof(runtime.uncommontype{})
+func structfieldSize() int          { return 3 * Widthptr } // Sizeof(runtime.structfield{})
+func imethodSize() int              { return 2 * Widthptr } // Sizeof(runtime.imethod{})
+func uncommonSize(t *ir.Type) int { // Sizeof(runtime.uncommontype{})
 	if t.Sym == nil && len(methods(t)) == 0 {
 		return 0
 	}
 	return 2*Widthptr + 2*Widthint
 }
 
-func makefield(name string, t *Type) *Field {
-	f := newField()
+func makefield(name string, t *ir.Type) *ir.Field {
+	f := ir.NewField()
 	f.Type = t
-	f.Sym = nopkg.Lookup(name)
+	f.Sym = ir.Nopkg.Lookup(name)
 	return f
 }
 
-func mapbucket(t *Type) *Type {
+func mapbucket(t *ir.Type) *ir.Type {
 	if t.Bucket != nil {
 		return t.Bucket
 	}
 
-	bucket := typ(TSTRUCT)
+	bucket := ir.Typ(ir.TSTRUCT)
 	keytype := t.Key()
 	valtype := t.Type
 	dowidth(keytype)
@@ -87,17 +88,17 @@ func mapbucket(t *Type) *Type {
 	}
 
 	// The first field is: uint8 topbits[BUCKETSIZE].
-	arr := typ(TARRAY)
+	arr := ir.Typ(ir.TARRAY)
 
-	arr.Type = Types[TUINT8]
+	arr.Type = ir.Types[ir.TUINT8]
 	arr.Bound = BUCKETSIZE
-	field := make([]*Field, 0, 5)
+	field := make([]*ir.Field, 0, 5)
 	field = append(field, makefield("topbits", arr))
-	arr = typ(TARRAY)
+	arr = ir.Typ(ir.TARRAY)
 	arr.Type = keytype
 	arr.Bound = BUCKETSIZE
 	field = append(field, makefield("keys", arr))
-	arr = typ(TARRAY)
+	arr = ir.Typ(ir.TARRAY)
 	arr.Type = valtype
 	arr.Bound = BUCKETSIZE
 	field = append(field, makefield("values", arr))
@@ -120,7 +121,7 @@ func mapbucket(t *Type) *Type {
 	// then it would end with an extra 32-bit padding field.
 	// Preempt that by emitting the padding here.
 	if int(t.Type.Align) > Widthptr || int(t.Key().Align) > Widthptr {
-		field = append(field, makefield("pad", Types[TUINTPTR]))
+		field = append(field, makefield("pad", ir.Types[ir.TUINTPTR]))
 	}
 
 	// If keys and values have no pointers, the map implementation
@@ -131,7 +132,7 @@ func mapbucket(t *Type) *Type {
 	// See comment on hmap.overflow in ../../../../runtime/hashmap.go.
 	otyp := Ptrto(bucket)
 	if !haspointers(t.Type) && !haspointers(t.Key()) && t.Type.Width <= MAXVALSIZE && t.Key().Width <= MAXKEYSIZE {
-		otyp = Types[TUINTPTR]
+		otyp = ir.Types[ir.TUINTPTR]
 	}
 	ovf := makefield("overflow", otyp)
 	field = append(field, ovf)
@@ -156,23 +157,23 @@ func mapbucket(t *Type) *Type {
 
 // Builds a type representing a Hmap structure for the given map type.
 // Make sure this stays in sync with ../../../../runtime/hashmap.go!
-func hmap(t *Type) *Type {
+func hmap(t *ir.Type) *ir.Type {
 	if t.Hmap != nil {
 		return t.Hmap
 	}
 
 	bucket := mapbucket(t)
-	var field [8]*Field
-	field[0] = makefield("count", Types[TINT])
-	field[1] = makefield("flags", Types[TUINT8])
-	field[2] = makefield("B", Types[TUINT8])
-	field[3] = makefield("hash0", Types[TUINT32])
+	var field [8]*ir.Field
+	field[0] = makefield("count", ir.Types[ir.TINT])
+	field[1] = makefield("flags", ir.Types[ir.TUINT8])
+	field[2] = makefield("B", ir.Types[ir.TUINT8])
+	field[3] = makefield("hash0", ir.Types[ir.TUINT32])
 	field[4] = makefield("buckets", Ptrto(bucket))
 	field[5] = makefield("oldbuckets", Ptrto(bucket))
-	field[6] = makefield("nevacuate", Types[TUINTPTR])
-	field[7] = makefield("overflow", Types[TUNSAFEPTR])
+	field[6] = makefield("nevacuate", ir.Types[ir.TUINTPTR])
+	field[7] = makefield("overflow", ir.Types[ir.TUNSAFEPTR])
 
-	h := typ(TSTRUCT)
+	h := ir.Typ(ir.TSTRUCT)
 	h.Noalg = true
 	h.Local = t.Local
 	h.SetFields(field[:])
@@ -182,7 +183,7 @@ func hmap(t *Type) *Type {
 	return h
 }
 
-func hiter(t *Type) *Type {
+func hiter(t *ir.Type) *ir.Type {
 	if t.Hiter != nil {
 		return t.Hiter
 	}
@@ -203,22 +204,22 @@ func hiter(t *Type) *Type {
 	//    checkBucket uintptr
 	// }
 	// must match ../../../../runtime/hashmap.go:hiter.
-	var field [12]*Field
+	var field [12]*ir.Field
 	field[0] = makefield("key", Ptrto(t.Key()))
 	field[1] = makefield("val", Ptrto(t.Type))
-	field[2] = makefield("t", Ptrto(Types[TUINT8]))
+	field[2] = makefield("t", Ptrto(ir.Types[ir.TUINT8]))
 	field[3] = makefield("h", Ptrto(hmap(t)))
 	field[4] = makefield("buckets", Ptrto(mapbucket(t)))
 	field[5] = makefield("bptr", Ptrto(mapbucket(t)))
-	field[6] = makefield("overflow0", Types[TUNSAFEPTR])
-	field[7] = makefield("overflow1", Types[TUNSAFEPTR])
-	field[8] = makefield("startBucket", Types[TUINTPTR])
-	field[9] = makefield("stuff", Types[TUINTPTR]) // offset+wrapped+B+I
-	field[10] = makefield("bucket", Types[TUINTPTR])
-	field[11] = makefield("checkBucket", Types[TUINTPTR])
+	field[6] = makefield("overflow0", ir.Types[ir.TUNSAFEPTR])
+	field[7] = makefield("overflow1", ir.Types[ir.TUNSAFEPTR])
+	field[8] = makefield("startBucket", ir.Types[ir.TUINTPTR])
+	field[9] = makefield("stuff", ir.Types[ir.TUINTPTR]) // offset+wrapped+B+I
+	field[10] = makefield("bucket", ir.Types[ir.TUINTPTR])
+	field[11] = makefield("checkBucket", ir.Types[ir.TUINTPTR])
 
 	// build iterator struct holding the above fields
-	i := typ(TSTRUCT)
+	i := ir.Typ(ir.TSTRUCT)
 	i.Noalg = true
 	i.SetFields(field[:])
 	dowidth(i)
@@ -232,30 +233,29 @@ func hiter(t *Type) *Type {
 
 // f is method type, with receiver.
 // return function type, receiver as first argument (or not).
-func methodfunc(f *Type, receiver *Type) *Type {
-	var in []*Node
+func methodfunc(f *ir.Type, receiver *ir.Type) *ir.Type {
+	var in []*ir.Field
 	if receiver != nil {
-		d := Nod(ODCLFIELD, nil, nil)
+		d := ir.NewField()
 		d.Type = receiver
 		in = append(in, d)
 	}
 
-	var d *Node
 	for _, t := range f.Params().Fields().Slice() {
-		d = Nod(ODCLFIELD, nil, nil)
+		d := ir.NewField()
 		d.Type = t.Type
 		d.Isddd = t.Isddd
 		in = append(in, d)
 	}
 
-	var out []*Node
+	var out []*ir.Field
 	for _, t := range f.Results().Fields().Slice() {
-		d = Nod(ODCLFIELD, nil, nil)
+		d := ir.NewField()
 		d.Type = t.Type
 		out = append(out, d)
 	}
 
-	t := functype(nil, in, out)
+	t := functypefield(nil, in, out)
 	if f.Nname != nil {
 		// Link to name of original method function.
 		t.Nname = f.Nname
@@ -266,7 +266,7 @@ func methodfunc(f *Type, receiver *Type) *Type {
 
 // methods returns the methods of the non-interface type t, sorted by name.
 // Generates stub functions as needed.
-func methods(t *Type) []*Sig {
+func methods(t *ir.Type) []*Sig {
 	// method type
 	mt := methtype(t, 0)
 
@@ -286,7 +286,7 @@ func methods(t *Type) []*Sig {
 	// generating code if necessary.
 	var ms []*Sig
 	for _, f := range mt.AllMethods().Slice() {
-		if f.Type.Etype != TFUNC || f.Type.Recv() == nil {
+		if f.Type.Etype != ir.TFUNC || f.Type.Recv() == nil {
 			Fatalf("non-method on %v method %v %v\n", mt, f.Sym, f)
 		}
 		if f.Type.Recv() == nil {
@@ -330,17 +330,17 @@ func methods(t *Type) []*Sig {
 		sig.type_ = methodfunc(f.Type, t)
 		sig.mtype = methodfunc(f.Type, nil)
 
-		if sig.isym.Flags&SymSiggen == 0 {
-			sig.isym.Flags |= SymSiggen
-			if !Eqtype(this, it) || this.Width < Types[Tptr].Width {
+		if sig.isym.Flags&ir.SymSiggen == 0 {
+			sig.isym.Flags |= ir.SymSiggen
+			if !Eqtype(this, it) || this.Width < ir.Types[Tptr].Width {
 				compiling_wrappers = 1
 				genwrapper(it, f, sig.isym, 1)
 				compiling_wrappers = 0
 			}
 		}
 
-		if sig.tsym.Flags&SymSiggen == 0 {
-			sig.tsym.Flags |= SymSiggen
+		if sig.tsym.Flags&ir.SymSiggen == 0 {
+			sig.tsym.Flags |= ir.SymSiggen
 			if !Eqtype(this, t) {
 				compiling_wrappers = 1
 				genwrapper(t, f, sig.tsym, 0)
@@ -354,10 +354,10 @@ func methods(t *Type) []*Sig {
 }
 
 // imethods returns the methods of the interface type t, sorted by name.
-func imethods(t *Type) []*Sig {
+func imethods(t *ir.Type) []*Sig {
 	var methods []*Sig
 	for _, f := range t.Fields().Slice() {
-		if f.Type.Etype != TFUNC || f.Sym == nil {
+		if f.Type.Etype != ir.TFUNC || f.Sym == nil {
 			continue
 		}
 		method := f.Sym
@@ -382,30 +382,44 @@ func imethods(t *Type) []*Sig {
 			}
 		}
 		methods = append(methods, &sig)
+	}
+
+	return methods
+}
 
+// An imethodexpr is an interface method expression I.M,
+// which refers to a wrapper that calls M on its first argument.
+type imethodexpr struct {
+	t   *ir.Type  // interface type I
+	f   *ir.Field // method M
+	sym *ir.Sym   // wrapper I.M
+}
+
+// imethodexprs lists the interface method expressions seen by
+// typecheck. Their wrappers are generated by dumptypestructs, so
+// only wrappers that are used are compiled.
+var imethodexprs []imethodexpr
+
+// genimethodexprs generates the wrappers for imethodexprs.
+// The wrappers are dupok, so every package using I.M can
+// generate its own copy.
+func genimethodexprs() {
+	for _, m := range imethodexprs {
 		// Compiler can only refer to wrappers for non-blank methods.
-		if isblanksym(method) {
+		if isblanksym(m.f.Sym) {
 			continue
 		}
-
-		// NOTE(rsc): Perhaps an oversight that
-		// IfaceType.Method is not in the reflect data.
-		// Generate the method body, so that compiled
-		// code can refer to it.
-		isym := methodsym(method, t, 0)
-
-		if isym.Flags&SymSiggen == 0 {
-			isym.Flags |= SymSiggen
-			genwrapper(t, f, isym, 0)
+		if m.sym.Flags&ir.SymSiggen == 0 {
+			m.sym.Flags |= ir.SymSiggen
+			genwrapper(m.t, m.f, m.sym, 0)
 		}
 	}
-
-	return methods
+	imethodexprs = nil
 }
 
-var dimportpath_gopkg *Pkg
+var dimportpath_gopkg *ir.Pkg
 
-func dimportpath(p *Pkg) {
+func dimportpath(p *ir.Pkg) {
 	if p.Pathsym != nil {
 		return
 	}
@@ -424,10 +438,10 @@ func dimportpath(p *Pkg) {
 
 	nam := "importpath." + p.Prefix + "."
 
-	n := Nod(ONAME, nil, nil)
+	n := Nod(ir.ONAME, nil, nil)
 	n.Sym = Pkglookup(nam, dimportpath_gopkg)
 
-	n.Class = PEXTERN
+	n.Class = ir.PEXTERN
 	n.Xoffset = 0
 	p.Pathsym = n.Sym
 
@@ -437,14 +451,14 @@ func dimportpath(p *Pkg) {
 	} else {
 		gdatastring(n, p.Path)
 	}
-	ggloblsym(n.Sym, int32(Types[TSTRING].Width), obj.DUPOK|obj.RODATA)
+	ggloblsym(n.Sym, int32(ir.Types[ir.TSTRING].Width), obj.DUPOK|obj.RODATA)
 }
 
-func dgopkgpath(s *Sym, ot int, pkg *Pkg) int {
+func dgopkgpath(s *ir.Sym, ot int, pkg *ir.Pkg) int {
 	return dgopkgpathLSym(Linksym(s), ot, pkg)
 }
 
-func dgopkgpathLSym(s *obj.LSym, ot int, pkg *Pkg) int {
+func dgopkgpathLSym(s *obj.LSym, ot int, pkg *ir.Pkg) int {
 	if pkg == nil {
 		return duintxxLSym(s, ot, 0, Widthptr)
 	}
@@ -464,7 +478,7 @@ func dgopkgpathLSym(s *obj.LSym, ot int, pkg *Pkg) int {
 }
 
 // isExportedField reports whether a struct field is exported.
-func isExportedField(ft *Field) bool {
+func isExportedField(ft *ir.Field) bool {
 	if ft.Sym != nil && ft.Embedded == 0 {
 		return exportname(ft.Sym.Name)
 	} else {
@@ -478,7 +492,7 @@ func isExportedField(ft *Field) bool {
 }
 
 // dnameField dumps a reflect.name for a struct field.
-func dnameField(s *Sym, ot int, ft *Field) int {
+func dnameField(s *ir.Sym, ot int, ft *ir.Field) int {
 	var name, tag string
 	if ft.Sym != nil && ft.Embedded == 0 {
 		name = ft.Sym.Name
@@ -492,7 +506,7 @@ func dnameField(s *Sym, ot int, ft *Field) int {
 var dnameCount int
 
 // dname dumps a reflect.name for a struct field or method.
-func dname(s *Sym, ot int, name, tag string, pkg *Pkg, exported bool) int {
+func dname(s *ir.Sym, ot int, name, tag string, pkg *ir.Pkg, exported bool) int {
 	if len(name) > 1<<16-1 {
 		Fatalf("name too long: %s", name)
 	}
@@ -551,7 +565,7 @@ func dname(s *Sym, ot int, name, tag string, pkg *Pkg, exported bool) int {
 // dextratype dumps the fields of a runtime.uncommontype.
 // dataAdd is the offset in bytes after the header where the
 // backing array of the []method field is written (by dextratypeData).
-func dextratype(s *Sym, ot int, t *Type, dataAdd int) int {
+func dextratype(s *ir.Sym, ot int, t *ir.Type, dataAdd int) int {
 	m := methods(t)
 	if t.Sym == nil && len(m) == 0 {
 		return ot
@@ -577,12 +591,12 @@ func dextratype(s *Sym, ot int, t *Type, dataAdd int) int {
 	return ot
 }
 
-func typePkg(t *Type) *Pkg {
+func typePkg(t *ir.Type) *ir.Pkg {
 	tsym := t.Sym
 	if tsym == nil && t.Type != nil {
 		tsym = t.Type.Sym
 	}
-	if tsym != nil && t != Types[t.Etype] && t != errortype {
+	if tsym != nil && t != ir.Types[t.Etype] && t != ir.Errortype {
 		return tsym.Pkg
 	}
 	return nil
@@ -590,23 +604,23 @@ func typePkg(t *Type) *Pkg {
 
 // dextratypeData dumps the backing array for the []method field of
 // runtime.uncommontype.
-func dextratypeData(s *Sym, ot int, t *Type) int {
+func dextratypeData(s *ir.Sym, ot int, t *ir.Type) int {
 	for _, a := range methods(t) {
 		// ../../../../runtime/type.go:/method
 		exported := exportname(a.name)
-		var pkg *Pkg
+		var pkg *ir.Pkg
 		if !exported && a.pkg != typePkg(t) {
 			pkg = a.pkg
 		}
 		ot = dname(s, ot, a.name, "", pkg, exported)
 		ot = dmethodptr(s, ot, dtypesym(a.mtype))
-		ot = dmethodptr(s, ot, a.isym)
-		ot = dmethodptr(s, ot, a.tsym)
+		ot = dmethodptr(s, ot, methodwrapper(a.isym))
+		ot = dmethodptr(s, ot, methodwrapper(a.tsym))
 	}
 	return ot
 }
 
-func dmethodptr(s *Sym, off int, x *Sym) int {
+func dmethodptr(s *ir.Sym, off int, x *ir.Sym) int {
 	duintptr(s, off, 0)
 	r := obj.Addrel(Linksym(s))
 	r.Off = int32(off)
@@ -617,60 +631,60 @@ func dmethodptr(s *Sym, off int, x *Sym) int {
 }
 
 var kinds = []int{
-	TINT:        obj.KindInt,
-	TUINT:       obj.KindUint,
-	TINT8:       obj.KindInt8,
-	TUINT8:      obj.KindUint8,
-	TINT16:      obj.KindInt16,
-	TUINT16:     obj.KindUint16,
-	TINT32:      obj.KindInt32,
-	TUINT32:     obj.KindUint32,
-	TINT64:      obj.KindInt64,
-	TUINT64:     obj.KindUint64,
-	TUINTPTR:    obj.KindUintptr,
-	TFLOAT32:    obj.KindFloat32,
-	TFLOAT64:    obj.KindFloat64,
-	TBOOL:       obj.KindBool,
-	TSTRING:     obj.KindString,
-	TPTR32:      obj.KindPtr,
-	TPTR64:      obj.KindPtr,
-	TSTRUCT:     obj.KindStruct,
-	TINTER:      obj.KindInterface,
-	TCHAN:       obj.KindChan,
-	TMAP:        obj.KindMap,
-	TARRAY:      obj.KindArray,
-	TFUNC:       obj.KindFunc,
-	TCOMPLEX64:  obj.KindComplex64,
-	TCOMPLEX128: obj.KindComplex128,
-	TUNSAFEPTR:  obj.KindUnsafePointer,
+	ir.TINT:        obj.KindInt,
+	ir.TUINT:       obj.KindUint,
+	ir.TINT8:       obj.KindInt8,
+	ir.TUINT8:      obj.KindUint8,
+	ir.TINT16:      obj.KindInt16,
+	ir.TUINT16:     obj.KindUint16,
+	ir.TINT32:      obj.KindInt32,
+	ir.TUINT32:     obj.KindUint32,
+	ir.TINT64:      obj.KindInt64,
+	ir.TUINT64:     obj.KindUint64,
+	ir.TUINTPTR:    obj.KindUintptr,
+	ir.TFLOAT32:    obj.KindFloat32,
+	ir.TFLOAT64:    obj.KindFloat64,
+	ir.TBOOL:       obj.KindBool,
+	ir.TSTRING:     obj.KindString,
+	ir.TPTR32:      obj.KindPtr,
+	ir.TPTR64:      obj.KindPtr,
+	ir.TSTRUCT:     obj.KindStruct,
+	ir.TINTER:      obj.KindInterface,
+	ir.TCHAN:       obj.KindChan,
+	ir.TMAP:        obj.KindMap,
+	ir.TARRAY:      obj.KindArray,
+	ir.TFUNC:       obj.KindFunc,
+	ir.TCOMPLEX64:  obj.KindComplex64,
+	ir.TCOMPLEX128: obj.KindComplex128,
+	ir.TUNSAFEPTR:  obj.KindUnsafePointer,
 }
 
-func haspointers(t *Type) bool {
+func haspointers(t *ir.Type) bool {
 	if t.Haspointers != 0 {
 		return t.Haspointers-1 != 0
 	}
 
 	var ret bool
 	switch t.Etype {
-	case TINT,
-		TUINT,
-		TINT8,
-		TUINT8,
-		TINT16,
-		TUINT16,
-		TINT32,
-		TUINT32,
-		TINT64,
-		TUINT64,
-		TUINTPTR,
-		TFLOAT32,
-		TFLOAT64,
-		TCOMPLEX64,
-		TCOMPLEX128,
-		TBOOL:
+	case ir.TINT,
+		ir.TUINT,
+		ir.TINT8,
+		ir.TUINT8,
+		ir.TINT16,
+		ir.TUINT16,
+		ir.TINT32,
+		ir.TUINT32,
+		ir.TINT64,
+		ir.TUINT64,
+		ir.TUINTPTR,
+		ir.TFLOAT32,
+		ir.TFLOAT64,
+		ir.TCOMPLEX64,
+		ir.TCOMPLEX128,
+		ir.TBOOL:
 		ret = false
 
-	case TARRAY:
+	case ir.TARRAY:
 		if t.Bound < 0 { // slice
 			ret = true
 			break
@@ -683,7 +697,7 @@ func haspointers(t *Type) bool {
 
 		ret = haspointers(t.Type)
 
-	case TSTRUCT:
+	case ir.TSTRUCT:
 		ret = false
 		for _, t1 := range t.Fields().Slice() {
 			if haspointers(t1.Type) {
@@ -692,14 +706,14 @@ func haspointers(t *Type) bool {
 			}
 		}
 
-	case TSTRING,
-		TPTR32,
-		TPTR64,
-		TUNSAFEPTR,
-		TINTER,
-		TCHAN,
-		TMAP,
-		TFUNC:
+	case ir.TSTRING,
+		ir.TPTR32,
+		ir.TPTR64,
+		ir.TUNSAFEPTR,
+		ir.TINTER,
+		ir.TCHAN,
+		ir.TMAP,
+		ir.TFUNC:
 		fallthrough
 	default:
 		ret = true
@@ -711,30 +725,30 @@ func haspointers(t *Type) bool {
 
 // typeptrdata returns the length in bytes of the prefix of t
 // containing pointer data. Anything after this offset is scalar data.
-func typeptrdata(t *Type) int64 {
+func typeptrdata(t *ir.Type) int64 {
 	if !haspointers(t) {
 		return 0
 	}
 
 	switch t.Etype {
-	case TPTR32,
-		TPTR64,
-		TUNSAFEPTR,
-		TFUNC,
-		TCHAN,
-		TMAP:
+	case ir.TPTR32,
+		ir.TPTR64,
+		ir.TUNSAFEPTR,
+		ir.TFUNC,
+		ir.TCHAN,
+		ir.TMAP:
 		return int64(Widthptr)
 
-	case TSTRING:
+	case ir.TSTRING:
 		// struct { byte *str; intgo len; }
 		return int64(Widthptr)
 
-	case TINTER:
+	case ir.TINTER:
 		// struct { Itab *tab;	void *data; } or
 		// struct { Type *type; void *data; }
 		return 2 * int64(Widthptr)
 
-	case TARRAY:
+	case ir.TARRAY:
 		if Isslice(t) {
 			// struct { byte *array; uintgo len; uintgo cap; }
 			return int64(Widthptr)
@@ -742,9 +756,9 @@ func typeptrdata(t *Type) int64 {
 		// haspointers already eliminated t.Bound == 0.
 		return (t.Bound-1)*t.Type.Width + typeptrdata(t.Type)
 
-	case TSTRUCT:
+	case ir.TSTRUCT:
 		// Find the last field that has pointers.
-		var lastPtrField *Field
+		var lastPtrField *ir.Field
 		for _, t1 := range t.Fields().Slice() {
 			if haspointers(t1.Type) {
 				lastPtrField = t1
@@ -764,9 +778,9 @@ const tflagUncommon = 1
 // commonType
 // ../../../../runtime/type.go:/commonType
 
-var dcommontype_algarray *Sym
+var dcommontype_algarray *ir.Sym
 
-func dcommontype(s *Sym, ot int, t *Type) int {
+func dcommontype(s *ir.Sym, ot int, t *ir.Type) int {
 	if ot != 0 {
 		Fatalf("dcommontype %d", ot)
 	}
@@ -777,7 +791,7 @@ func dcommontype(s *Sym, ot int, t *Type) int {
 	}
 	dowidth(t)
 	alg := algtype(t)
-	var algsym *Sym
+	var algsym *ir.Sym
 	if alg < 0 || alg == AMEM {
 		algsym = dalgsym(t)
 	}
@@ -832,7 +846,7 @@ func dcommontype(s *Sym, ot int, t *Type) int {
 	ot = duint8(s, ot, t.Align) // fieldAlign
 
 	i = kinds[t.Etype]
-	if t.Etype == TARRAY && t.Bound < 0 {
+	if t.Etype == ir.TARRAY && t.Bound < 0 {
 		i = obj.KindSlice
 	}
 	if !haspointers(t) {
@@ -852,7 +866,7 @@ func dcommontype(s *Sym, ot int, t *Type) int {
 	}
 	ot = dsymptr(s, ot, gcsym, 0) // gcdata
 
-	p := Tconv(t, FmtLeft|FmtUnsigned)
+	p := Tconv(t, ir.FmtLeft|ir.FmtUnsigned)
 
 	// If we're writing out type T,
 	// we are very likely to write out type *T as well.
@@ -871,17 +885,17 @@ func dcommontype(s *Sym, ot int, t *Type) int {
 	return ot
 }
 
-func typesym(t *Type) *Sym {
-	return Pkglookup(Tconv(t, FmtLeft), typepkg)
+func typesym(t *ir.Type) *ir.Sym {
+	return Pkglookup(Tconv(t, ir.FmtLeft), typepkg)
 }
 
 // tracksym returns the symbol for tracking use of field/method f, assumed
 // to be a member of struct/interface type t.
-func tracksym(t *Type, f *Field) *Sym {
-	return Pkglookup(Tconv(t, FmtLeft)+"."+f.Sym.Name, trackpkg)
+func tracksym(t *ir.Type, f *ir.Field) *ir.Sym {
+	return Pkglookup(Tconv(t, ir.FmtLeft)+"."+f.Sym.Name, trackpkg)
 }
 
-func typelinksym(t *Type) *Sym {
+func typelinksym(t *ir.Type) *ir.Sym {
 	// %-uT is what the generated Type's string field says.
 	// It uses (ambiguous) package names instead of import paths.
 	// %-T is the complete, unambiguous type name.
@@ -891,7 +905,7 @@ func typelinksym(t *Type) *Sym {
 	// ensure the types appear sorted by their string field. The
 	// names are a little long but they are discarded by the linker
 	// and do not end up in the symbol table of the final binary.
-	p := Tconv(t, FmtLeft|FmtUnsigned) + "\t" + Tconv(t, FmtLeft)
+	p := Tconv(t, ir.FmtLeft|ir.FmtUnsigned) + "\t" + Tconv(t, ir.FmtLeft)
 
 	s := Pkglookup(p, typelinkpkg)
 
@@ -900,8 +914,8 @@ func typelinksym(t *Type) *Sym {
 	return s
 }
 
-func typesymprefix(prefix string, t *Type) *Sym {
-	p := prefix + "." + Tconv(t, FmtLeft)
+func typesymprefix(prefix string, t *ir.Type) *ir.Sym {
+	p := prefix + "." + Tconv(t, ir.FmtLeft)
 	s := Pkglookup(p, typepkg)
 
 	//print("algsym: %s -> %+S\n", p, s);
@@ -909,18 +923,18 @@ func typesymprefix(prefix string, t *Type) *Sym {
 	return s
 }
 
-func typenamesym(t *Type) *Sym {
+func typenamesym(t *ir.Type) *ir.Sym {
 	if t == nil || (Isptr[t.Etype] && t.Type == nil) || isideal(t) {
 		Fatalf("typename %v", t)
 	}
 	s := typesym(t)
 	if s.Def == nil {
-		n := Nod(ONAME, nil, nil)
+		n := Nod(ir.ONAME, nil, nil)
 		n.Sym = s
-		n.Type = Types[TUINT8]
+		n.Type = ir.Types[ir.TUINT8]
 		n.Addable = true
 		n.Ullman = 1
-		n.Class = PEXTERN
+		n.Class = ir.PEXTERN
 		n.Xoffset = 0
 		n.Typecheck = 1
 		s.Def = n
@@ -931,9 +945,9 @@ func typenamesym(t *Type) *Sym {
 	return s.Def.Sym
 }
 
-func typename(t *Type) *Node {
+func typename(t *ir.Type) *ir.Node {
 	s := typenamesym(t)
-	n := Nod(OADDR, s.Def, nil)
+	n := Nod(ir.OADDR, s.Def, nil)
 	n.Type = Ptrto(s.Def.Type)
 	n.Addable = true
 	n.Ullman = 2
@@ -943,41 +957,41 @@ func typename(t *Type) *Node {
 
 // isreflexive reports whether t has a reflexive equality operator.
 // That is, if x==x for all x of type t.
-func isreflexive(t *Type) bool {
+func isreflexive(t *ir.Type) bool {
 	switch t.Etype {
-	case TBOOL,
-		TINT,
-		TUINT,
-		TINT8,
-		TUINT8,
-		TINT16,
-		TUINT16,
-		TINT32,
-		TUINT32,
-		TINT64,
-		TUINT64,
-		TUINTPTR,
-		TPTR32,
-		TPTR64,
-		TUNSAFEPTR,
-		TSTRING,
-		TCHAN:
+	case ir.TBOOL,
+		ir.TINT,
+		ir.TUINT,
+		ir.TINT8,
+		ir.TUINT8,
+		ir.TINT16,
+		ir.TUINT16,
+		ir.TINT32,
+		ir.TUINT32,
+		ir.TINT64,
+		ir.TUINT64,
+		ir.TUINTPTR,
+		ir.TPTR32,
+		ir.TPTR64,
+		ir.TUNSAFEPTR,
+		ir.TSTRING,
+		ir.TCHAN:
 		return true
 
-	case TFLOAT32,
-		TFLOAT64,
-		TCOMPLEX64,
-		TCOMPLEX128,
-		TINTER:
+	case ir.TFLOAT32,
+		ir.TFLOAT64,
+		ir.TCOMPLEX64,
+		ir.TCOMPLEX128,
+		ir.TINTER:
 		return false
 
-	case TARRAY:
+	case ir.TARRAY:
 		if Isslice(t) {
 			Fatalf("slice can't be a map key: %v", t)
 		}
 		return isreflexive(t.Type)
 
-	case TSTRUCT:
+	case ir.TSTRUCT:
 		for _, t1 := range t.Fields().Slice() {
 			if !isreflexive(t1.Type) {
 				return false
@@ -993,41 +1007,41 @@ func isreflexive(t *Type) bool {
 
 // needkeyupdate reports whether map updates with t as a key
 // need the key to be updated.
-func needkeyupdate(t *Type) bool {
+func needkeyupdate(t *ir.Type) bool {
 	switch t.Etype {
-	case TBOOL,
-		TINT,
-		TUINT,
-		TINT8,
-		TUINT8,
-		TINT16,
-		TUINT16,
-		TINT32,
-		TUINT32,
-		TINT64,
-		TUINT64,
-		TUINTPTR,
-		TPTR32,
-		TPTR64,
-		TUNSAFEPTR,
-		TCHAN:
+	case ir.TBOOL,
+		ir.TINT,
+		ir.TUINT,
+		ir.TINT8,
+		ir.TUINT8,
+		ir.TINT16,
+		ir.TUINT16,
+		ir.TINT32,
+		ir.TUINT32,
+		ir.TINT64,
+		ir.TUINT64,
+		ir.TUINTPTR,
+		ir.TPTR32,
+		ir.TPTR64,
+		ir.TUNSAFEPTR,
+		ir.TCHAN:
 		return false
 
-	case TFLOAT32, // floats can be +0/-0
-		TFLOAT64,
-		TCOMPLEX64,
-		TCOMPLEX128,
-		TINTER,
-		TSTRING: // strings might have smaller backing stores
+	case ir.TFLOAT32, // floats can be +0/-0
+		ir.TFLOAT64,
+		ir.TCOMPLEX64,
+		ir.TCOMPLEX128,
+		ir.TINTER,
+		ir.TSTRING: // strings might have smaller backing stores
 		return true
 
-	case TARRAY:
+	case ir.TARRAY:
 		if Isslice(t) {
 			Fatalf("slice can't be a map key: %v", t)
 		}
 		return needkeyupdate(t.Type)
 
-	case TSTRUCT:
+	case ir.TSTRUCT:
 		for _, t1 := range t.Fields().Slice() {
 			if needkeyupdate(t1.Type) {
 				return true
@@ -1041,12 +1055,12 @@ func needkeyupdate(t *Type) bool {
 	}
 }
 
-func dtypesym(t *Type) *Sym {
+func dtypesym(t *ir.Type) *ir.Sym {
 	// Replace byte, rune aliases with real type.
 	// They've been separate internally to make error messages
 	// better, but we have to merge them in the reflect tables.
-	if t == bytetype || t == runetype {
-		t = Types[t.Etype]
+	if t == ir.Bytetype || t == ir.Runetype {
+		t = ir.Types[t.Etype]
 	}
 
 	if isideal(t) {
@@ -1054,10 +1068,10 @@ func dtypesym(t *Type) *Sym {
 	}
 
 	s := typesym(t)
-	if s.Flags&SymSiggen != 0 {
+	if s.Flags&ir.SymSiggen != 0 {
 		return s
 	}
-	s.Flags |= SymSiggen
+	s.Flags |= ir.SymSiggen
 
 	// special case (look for runtime below):
 	// when compiling package runtime,
@@ -1072,7 +1086,7 @@ func dtypesym(t *Type) *Sym {
 		dupok = obj.DUPOK
 	}
 
-	if myimportpath == "runtime" && (tbase == Types[tbase.Etype] || tbase == bytetype || tbase == runetype || tbase == errortype) { // int, float, etc
+	if myimportpath == "runtime" && (tbase == ir.Types[tbase.Etype] || tbase == ir.Bytetype || tbase == ir.Runetype || tbase == ir.Errortype) { // int, float, etc
 		goto ok
 	}
 
@@ -1091,12 +1105,12 @@ ok:
 		ot = dcommontype(s, ot, t)
 		ot = dextratype(s, ot, t, 0)
 
-	case TARRAY:
+	case ir.TARRAY:
 		if t.Bound >= 0 {
 			// ../../../../runtime/type.go:/arrayType
 			s1 := dtypesym(t.Type)
 
-			t2 := typ(TARRAY)
+			t2 := ir.Typ(ir.TARRAY)
 			t2.Type = t.Type
 			t2.Bound = -1 // slice
 			s2 := dtypesym(t2)
@@ -1114,7 +1128,7 @@ ok:
 		ot = dextratype(s, ot, t, 0)
 
 	// ../../../../runtime/type.go:/chanType
-	case TCHAN:
+	case ir.TCHAN:
 		s1 := dtypesym(t.Type)
 
 		ot = dcommontype(s, ot, t)
@@ -1122,7 +1136,7 @@ ok:
 		ot = duintptr(s, ot, uint64(t.Chan))
 		ot = dextratype(s, ot, t, 0)
 
-	case TFUNC:
+	case ir.TFUNC:
 		for _, t1 := range t.Recvs().Fields().Slice() {
 			dtypesym(t1.Type)
 		}
@@ -1161,7 +1175,7 @@ ok:
 			ot = dsymptr(s, ot, dtypesym(t1.Type), 0)
 		}
 
-	case TINTER:
+	case ir.TINTER:
 		m := imethods(t)
 		n := len(m)
 		for _, a := range m {
@@ -1171,8 +1185,8 @@ ok:
 		// ../../../../runtime/type.go:/interfaceType
 		ot = dcommontype(s, ot, t)
 
-		var tpkg *Pkg
-		if t.Sym != nil && t != Types[t.Etype] && t != errortype {
+		var tpkg *ir.Pkg
+		if t.Sym != nil && t != ir.Types[t.Etype] && t != ir.Errortype {
 			tpkg = t.Sym.Pkg
 		}
 		ot = dgopkgpath(s, ot, tpkg)
@@ -1186,7 +1200,7 @@ ok:
 		for _, a := range m {
 			// ../../../../runtime/type.go:/imethod
 			exported := exportname(a.name)
-			var pkg *Pkg
+			var pkg *ir.Pkg
 			if !exported && a.pkg != tpkg {
 				pkg = a.pkg
 			}
@@ -1195,7 +1209,7 @@ ok:
 		}
 
 	// ../../../../runtime/type.go:/mapType
-	case TMAP:
+	case ir.TMAP:
 		s1 := dtypesym(t.Key())
 		s2 := dtypesym(t.Type)
 		s3 := dtypesym(mapbucket(t))
@@ -1226,8 +1240,8 @@ ok:
 		ot = duint8(s, ot, uint8(obj.Bool2int(needkeyupdate(t.Key()))))
 		ot = dextratype(s, ot, t, 0)
 
-	case TPTR32, TPTR64:
-		if t.Type.Etype == TANY {
+	case ir.TPTR32, ir.TPTR64:
+		if t.Type.Etype == ir.TANY {
 			// ../../../../runtime/type.go:/UnsafePointerType
 			ot = dcommontype(s, ot, t)
 			ot = dextratype(s, ot, t, 0)
@@ -1244,7 +1258,7 @@ ok:
 
 	// ../../../../runtime/type.go:/structType
 	// for security, only the exported fields.
-	case TSTRUCT:
+	case ir.TSTRUCT:
 		n := 0
 
 		for _, t1 := range t.Fields().Slice() {
@@ -1253,7 +1267,7 @@ ok:
 		}
 
 		ot = dcommontype(s, ot, t)
-		var pkg *Pkg
+		var pkg *ir.Pkg
 		if t.Sym != nil {
 			pkg = t.Sym.Pkg
 		}
@@ -1283,7 +1297,7 @@ ok:
 	// we want be able to find.
 	if t.Sym == nil {
 		switch t.Etype {
-		case TPTR32, TPTR64, TARRAY, TCHAN, TFUNC, TMAP:
+		case ir.TPTR32, ir.TPTR64, ir.TARRAY, ir.TCHAN, ir.TFUNC, ir.TMAP:
 			slink := typelinksym(t)
 			dsymptr(slink, 0, s, 0)
 			ggloblsym(slink, int32(Widthptr), int16(dupok|obj.RODATA))
@@ -1296,17 +1310,22 @@ ok:
 func dumptypestructs() {
 	// copy types from externdcl list to signatlist
 	for _, n := range externdcl {
-		if n.Op != OTYPE {
+		if n.Op != ir.OTYPE {
 			continue
 		}
 		signatlist = append(signatlist, n)
 	}
 
+	// Generate wrappers for interface method expressions
+	// before processing signatlist, which may grow as they
+	// are compiled.
+	genimethodexprs()
+
 	// Process signatlist.  This can't use range, as entries are
 	// added to the list while it is being processed.
 	for i := 0; i < len(signatlist); i++ {
 		n := signatlist[i]
-		if n.Op != OTYPE {
+		if n.Op != ir.OTYPE {
 			continue
 		}
 		t := n.Type
@@ -1330,17 +1349,17 @@ func dumptypestructs() {
 	// another possible choice would be package main,
 	// but using runtime means fewer copies in .6 files.
 	if myimportpath == "runtime" {
-		for i := EType(1); i <= TBOOL; i++ {
-			dtypesym(Ptrto(Types[i]))
+		for i := ir.EType(1); i <= ir.TBOOL; i++ {
+			dtypesym(Ptrto(ir.Types[i]))
 		}
-		dtypesym(Ptrto(Types[TSTRING]))
-		dtypesym(Ptrto(Types[TUNSAFEPTR]))
+		dtypesym(Ptrto(ir.Types[ir.TSTRING]))
+		dtypesym(Ptrto(ir.Types[ir.TUNSAFEPTR]))
 
 		// emit type structs for error and func(error) string.
 		// The latter is the type of an auto-generated wrapper.
-		dtypesym(Ptrto(errortype))
+		dtypesym(Ptrto(ir.Errortype))
 
-		dtypesym(functype(nil, []*Node{Nod(ODCLFIELD, nil, typenod(errortype))}, []*Node{Nod(ODCLFIELD, nil, typenod(Types[TSTRING]))}))
+		dtypesym(functype(nil, []*ir.Node{Nod(ir.ODCLFIELD, nil, typenod(ir.Errortype))}, []*ir.Node{Nod(ir.ODCLFIELD, nil, typenod(ir.Types[ir.TSTRING]))}))
 
 		// add paths for runtime and main, which 6l imports implicitly.
 		dimportpath(Runtimepkg)
@@ -1351,14 +1370,17 @@ func dumptypestructs() {
 		if flag_msan != 0 {
 			dimportpath(msanpkg)
 		}
+		if flag_asan != 0 {
+			dimportpath(asanpkg)
+		}
 		dimportpath(mkpkg("main"))
 	}
 }
 
-func dalgsym(t *Type) *Sym {
-	var s *Sym
-	var hashfunc *Sym
-	var eqfunc *Sym
+func dalgsym(t *ir.Type) *ir.Sym {
+	var s *ir.Sym
+	var hashfunc *ir.Sym
+	var eqfunc *ir.Sym
 
 	// dalgsym is only called for a type that needs an algorithm table,
 	// which implies that the type is comparable (or else it would use ANOEQ).
@@ -1369,10 +1391,10 @@ func dalgsym(t *Type) *Sym {
 
 		s = Pkglookup(p, typepkg)
 
-		if s.Flags&SymAlgGen != 0 {
+		if s.Flags&ir.SymAlgGen != 0 {
 			return s
 		}
-		s.Flags |= SymAlgGen
+		s.Flags |= ir.SymAlgGen
 
 		// make hash closure
 		p = fmt.Sprintf(".hashfunc%d", t.Width)
@@ -1455,32 +1477,80 @@ func dalgsym(t *Type) *Sym {
 //
 // Also known to reflect/type.go.
 //
+// The cutoff can be raised with -d maxptrmask=n, for example to measure
+// the trade-off with -d gcdata. It cannot be lowered: that would break
+// the chansend guarantee above on 32-bit systems. In the standard
+// library and commands only five types are above the cutoff; their GC
+// programs total 67 bytes where bitmaps would take 55 kB. So 2048, the
+// smallest safe cutoff, is also the best one.
+//
 const maxPtrmaskBytes = 2048
 
+// gcdataSizes records the size of the GC information emitted for
+// each type, for the -d gcdata report.
+var gcdataSizes []gcdataSize
+
+type gcdataSize struct {
+	t         *ir.Type
+	useGCProg bool
+	size      int
+}
+
 // dgcsym emits and returns a data symbol containing GC information for type t,
 // along with a boolean reporting whether the UseGCProg bit should be set in
 // the type kind, and the ptrdata field to record in the reflect type information.
-func dgcsym(t *Type) (sym *Sym, useGCProg bool, ptrdata int64) {
+func dgcsym(t *ir.Type) (sym *ir.Sym, useGCProg bool, ptrdata int64) {
 	ptrdata = typeptrdata(t)
-	if ptrdata/int64(Widthptr) <= maxPtrmaskBytes*8 {
+	limit := int64(maxPtrmaskBytes)
+	if Debug_maxptrmask != 0 {
+		limit = int64(Debug_maxptrmask)
+	}
+	if ptrdata/int64(Widthptr) <= limit*8 {
 		sym = dgcptrmask(t)
-		return
+	} else {
+		useGCProg = true
+		sym, ptrdata = dgcprog(t)
+	}
+	if Debug_gcdata != 0 {
+		gcdataSizes = append(gcdataSizes, gcdataSize{t, useGCProg, len(Linksym(sym).P)})
 	}
-
-	useGCProg = true
-	sym, ptrdata = dgcprog(t)
 	return
 }
 
+// dumpgcdata prints the -d gcdata report: the size of the GC
+// information of every type described by this package, largest first.
+func dumpgcdata() {
+	sort.Sort(byGCDataSize(gcdataSizes))
+	for _, d := range gcdataSizes {
+		kind := "ptrmask"
+		if d.useGCProg {
+			kind = "gcprog"
+		}
+		fmt.Printf("gcdata: %d %s %v (size %d)\n", d.size, kind, Tconv(d.t, ir.FmtLeft), d.t.Width)
+	}
+	gcdataSizes = nil
+}
+
+type byGCDataSize []gcdataSize
+
+func (x byGCDataSize) Len() int      { return len(x) }
+func (x byGCDataSize) Swap(i, j int) { x[i], x[j] = x[j], x[i] }
+func (x byGCDataSize) Less(i, j int) bool {
+	if x[i].size != x[j].size {
+		return x[i].size > x[j].size
+	}
+	return Tconv(x[i].t, ir.FmtLeft) < Tconv(x[j].t, ir.FmtLeft)
+}
+
 // dgcptrmask emits and returns the symbol containing a pointer mask for type t.
-func dgcptrmask(t *Type) *Sym {
+func dgcptrmask(t *ir.Type) *ir.Sym {
 	ptrmask := make([]byte, (typeptrdata(t)/int64(Widthptr)+7)/8)
 	fillptrmask(t, ptrmask)
 	p := fmt.Sprintf("gcbits.%x", ptrmask)
 
 	sym := Pkglookup(p, Runtimepkg)
-	if sym.Flags&SymUniq == 0 {
-		sym.Flags |= SymUniq
+	if sym.Flags&ir.SymUniq == 0 {
+		sym.Flags |= ir.SymUniq
 		for i, x := range ptrmask {
 			duint8(sym, i, x)
 		}
@@ -1492,7 +1562,7 @@ func dgcptrmask(t *Type) *Sym {
 // fillptrmask fills in ptrmask with 1s corresponding to the
 // word offsets in t that hold pointers.
 // ptrmask is assumed to fit at least typeptrdata(t)/Widthptr bits.
-func fillptrmask(t *Type, ptrmask []byte) {
+func fillptrmask(t *ir.Type, ptrmask []byte) {
 	for i := range ptrmask {
 		ptrmask[i] = 0
 	}
@@ -1516,9 +1586,9 @@ func fillptrmask(t *Type, ptrmask []byte) {
 // along with the size of the data described by the program (in the range [typeptrdata(t), t.Width]).
 // In practice, the size is typeptrdata(t) except for non-trivial arrays.
 // For non-trivial arrays, the program describes the full t.Width size.
-func dgcprog(t *Type) (*Sym, int64) {
+func dgcprog(t *ir.Type) (*ir.Sym, int64) {
 	dowidth(t)
-	if t.Width == BADWIDTH {
+	if t.Width == ir.BADWIDTH {
 		Fatalf("dgcprog: %v badwidth", t)
 	}
 	sym := typesymprefix(".gcprog", t)
@@ -1534,14 +1604,14 @@ func dgcprog(t *Type) (*Sym, int64) {
 }
 
 type GCProg struct {
-	sym    *Sym
+	sym    *ir.Sym
 	symoff int
 	w      gcprog.Writer
 }
 
 var Debug_gcprog int // set by -d gcprog
 
-func (p *GCProg) init(sym *Sym) {
+func (p *GCProg) init(sym *ir.Sym) {
 	p.sym = sym
 	p.symoff = 4 // first 4 bytes hold program length
 	p.w.Init(p.writeByte)
@@ -1564,7 +1634,7 @@ func (p *GCProg) end() {
 	}
 }
 
-func (p *GCProg) emit(t *Type, offset int64) {
+func (p *GCProg) emit(t *ir.Type, offset int64) {
 	dowidth(t)
 	if !haspointers(t) {
 		return
@@ -1577,14 +1647,14 @@ func (p *GCProg) emit(t *Type, offset int64) {
 	default:
 		Fatalf("GCProg.emit: unexpected type %v", t)
 
-	case TSTRING:
+	case ir.TSTRING:
 		p.w.Ptr(offset / int64(Widthptr))
 
-	case TINTER:
+	case ir.TINTER:
 		p.w.Ptr(offset / int64(Widthptr))
 		p.w.Ptr(offset/int64(Widthptr) + 1)
 
-	case TARRAY:
+	case ir.TARRAY:
 		if Isslice(t) {
 			p.w.Ptr(offset / int64(Widthptr))
 			return
@@ -1613,7 +1683,7 @@ func (p *GCProg) emit(t *Type, offset int64) {
 		p.w.ZeroUntil((offset + elem.Width) / int64(Widthptr))
 		p.w.Repeat(elem.Width/int64(Widthptr), count-1)
 
-	case TSTRUCT:
+	case ir.TSTRUCT:
 		for _, t1 := range t.Fields().Slice() {
 			p.emit(t1.Type, offset+t1.Width)
 		}
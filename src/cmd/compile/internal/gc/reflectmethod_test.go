@@ -0,0 +1,43 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gc
+
+import (
+	"strings"
+	"testing"
+)
+
+// Make sure constant arguments to reflect.Type.Method and
+// MethodByName let the linker prune the other methods.
+func TestReflectMethodConst(t *testing.T) {
+	d := newTestDir(t, "TestReflectMethodConst")
+	defer d.remove()
+
+	exe := d.build("test", `
+package main
+
+import "reflect"
+
+type M int
+
+func (M) A() {}
+func (M) B() {}
+func (M) C() {}
+
+func main() {
+	reflect.TypeOf(M(0)).MethodByName("C")
+	reflect.TypeOf(M(0)).Method(1)
+}
+`)
+	nm := d.run("go", "tool", "nm", exe)
+	for _, m := range []string{"main.M.B", "main.M.C"} {
+		if !strings.Contains(nm, m) {
+			t.Errorf("missing %s", m)
+		}
+	}
+	if strings.Contains(nm, "main.M.A") {
+		t.Errorf("main.M.A not pruned")
+	}
+}
@@ -32,6 +32,7 @@ package gc
 
 import (
 	"bytes"
+	"cmd/compile/internal/ir"
 	"cmd/internal/obj"
 	"fmt"
 	"sort"
@@ -43,12 +44,12 @@ import (
 // to represent the use of registers in the unoptimized instruction stream.
 type Var struct {
 	offset     int64
-	node       *Node
+	node       *ir.Node
 	nextinnode *Var
 	width      int
 	id         int // index in vars
 	name       int8
-	etype      EType
+	etype      ir.EType
 	addr       int8
 }
 
@@ -166,7 +167,7 @@ func setaddrs(bit Bits) {
 	var i int
 	var n int
 	var v *Var
-	var node *Node
+	var node *ir.Node
 
 	for bany(&bit) {
 		// convert each bit to a variable
@@ -186,9 +187,9 @@ func setaddrs(bit Bits) {
 	}
 }
 
-var regnodes [64]*Node
+var regnodes [64]*ir.Node
 
-func walkvardef(n *Node, f *Flow, active int) {
+func walkvardef(n *ir.Node, f *Flow, active int) {
 	var f1 *Flow
 	var bn int
 	var v *Var
@@ -246,11 +247,11 @@ func addmove(r *Flow, bn int, rn int, f int) {
 	else if(a->sym == nil)
 		a->type = TYPE_CONST;
 	*/
-	p1.As = Thearch.Optoas(OAS, Types[uint8(v.etype)])
+	p1.As = Thearch.Optoas(ir.OAS, ir.Types[uint8(v.etype)])
 
 	// TODO(rsc): Remove special case here.
-	if (Thearch.Thechar == '0' || Thearch.Thechar == '5' || Thearch.Thechar == '7' || Thearch.Thechar == '9') && v.etype == TBOOL {
-		p1.As = Thearch.Optoas(OAS, Types[TUINT8])
+	if (Thearch.Thechar == '0' || Thearch.Thechar == '5' || Thearch.Thechar == '7' || Thearch.Thechar == '9') && v.etype == ir.TBOOL {
+		p1.As = Thearch.Optoas(ir.OAS, ir.Types[ir.TUINT8])
 	}
 	p1.From.Type = obj.TYPE_REG
 	p1.From.Reg = int16(rn)
@@ -341,8 +342,8 @@ func mkvar(f *Flow, a *obj.Addr) Bits {
 		}
 	}
 
-	node, _ := a.Node.(*Node)
-	if node == nil || node.Op != ONAME || node.Orig == nil {
+	node, _ := a.Node.(*ir.Node)
+	if node == nil || node.Op != ir.ONAME || node.Orig == nil {
 		return zbits
 	}
 	node = node.Orig
@@ -352,7 +353,7 @@ func mkvar(f *Flow, a *obj.Addr) Bits {
 	if node.Sym == nil || node.Sym.Name[0] == '.' {
 		return zbits
 	}
-	et := EType(a.Etype)
+	et := ir.EType(a.Etype)
 	o := a.Offset
 	w := a.Width
 	if w < 0 {
@@ -386,16 +387,16 @@ func mkvar(f *Flow, a *obj.Addr) Bits {
 	}
 
 	switch et {
-	case 0, TFUNC:
+	case 0, ir.TFUNC:
 		return zbits
 	}
 
 	if nvar >= NVAR {
 		if Debug['w'] > 1 && node != nil {
-			Fatalf("variable not optimized: %v", Nconv(node, FmtSharp))
+			Fatalf("variable not optimized: %v", Nconv(node, ir.FmtSharp))
 		}
 		if Debug['v'] > 0 {
-			Warn("variable not optimized: %v", Nconv(node, FmtSharp))
+			Warn("variable not optimized: %v", Nconv(node, ir.FmtSharp))
 		}
 
 		// If we're not tracking a word in a variable, mark the rest as
@@ -444,12 +445,12 @@ func mkvar(f *Flow, a *obj.Addr) Bits {
 		}
 	}
 
-	if node.Class == PPARAM {
+	if node.Class == ir.PPARAM {
 		for z := 0; z < BITS; z++ {
 			ivar.b[z] |= bit.b[z]
 		}
 	}
-	if node.Class == PPARAMOUT {
+	if node.Class == ir.PPARAMOUT {
 		for z := 0; z < BITS; z++ {
 			ovar.b[z] |= bit.b[z]
 		}
@@ -482,12 +483,12 @@ func mkvar(f *Flow, a *obj.Addr) Bits {
 	//
 	// Disable registerization for results if using defer, because the deferred func
 	// might recover and return, causing the current values to be used.
-	if node.Class == PEXTERN || (hasdefer && node.Class == PPARAMOUT) {
+	if node.Class == ir.PEXTERN || (hasdefer && node.Class == ir.PPARAMOUT) {
 		v.addr = 1
 	}
 
 	if Debug['R'] != 0 {
-		fmt.Printf("bit=%2d et=%v w=%d+%d %v %v flag=%d\n", i, Econv(et), o, w, Nconv(node, FmtSharp), Ctxt.Dconv(a), v.addr)
+		fmt.Printf("bit=%2d et=%v w=%d+%d %v %v flag=%d\n", i, ir.Econv(et), o, w, Nconv(node, ir.FmtSharp), Ctxt.Dconv(a), v.addr)
 	}
 	Ostats.Nvar++
 
@@ -651,29 +652,29 @@ func allreg(b uint64, r *Rgn) uint64 {
 	r.regno = 0
 	switch v.etype {
 	default:
-		Fatalf("unknown etype %d/%v", Bitno(b), Econv(v.etype))
-
-	case TINT8,
-		TUINT8,
-		TINT16,
-		TUINT16,
-		TINT32,
-		TUINT32,
-		TINT64,
-		TUINT64,
-		TINT,
-		TUINT,
-		TUINTPTR,
-		TBOOL,
-		TPTR32,
-		TPTR64:
+		Fatalf("unknown etype %d/%v", Bitno(b), ir.Econv(v.etype))
+
+	case ir.TINT8,
+		ir.TUINT8,
+		ir.TINT16,
+		ir.TUINT16,
+		ir.TINT32,
+		ir.TUINT32,
+		ir.TINT64,
+		ir.TUINT64,
+		ir.TINT,
+		ir.TUINT,
+		ir.TUINTPTR,
+		ir.TBOOL,
+		ir.TPTR32,
+		ir.TPTR64:
 		i := Thearch.BtoR(^b)
 		if i != 0 && r.cost > 0 {
 			r.regno = int16(i)
 			return Thearch.RtoB(i)
 		}
 
-	case TFLOAT32, TFLOAT64:
+	case ir.TFLOAT32, ir.TFLOAT64:
 		i := Thearch.BtoF(^b)
 		if i != 0 && r.cost > 0 {
 			r.regno = int16(i)
@@ -1146,7 +1147,7 @@ func regopt(firstp *obj.Prog) {
 		}
 
 		if Debug['R'] != 0 && Debug['v'] != 0 {
-			fmt.Printf("bit=%2d addr=%d et=%v w=%-2d s=%v + %d\n", i, v.addr, Econv(v.etype), v.width, v.node, v.offset)
+			fmt.Printf("bit=%2d addr=%d et=%v w=%-2d s=%v + %d\n", i, v.addr, ir.Econv(v.etype), v.width, v.node, v.offset)
 		}
 	}
 
@@ -1177,9 +1178,9 @@ func regopt(firstp *obj.Prog) {
 
 	for f := firstf; f != nil; f = f.Link {
 		p := f.Prog
-		if p.As == obj.AVARDEF && Isfat(((p.To.Node).(*Node)).Type) && ((p.To.Node).(*Node)).Opt() != nil {
+		if p.As == obj.AVARDEF && Isfat(((p.To.Node).(*ir.Node)).Type) && ((p.To.Node).(*ir.Node)).Opt() != nil {
 			active++
-			walkvardef(p.To.Node.(*Node), f, active)
+			walkvardef(p.To.Node.(*ir.Node), f, active)
 		}
 	}
 
@@ -1296,7 +1297,7 @@ loop2:
 				fmt.Printf("%v: set and not used: %v\n", f.Prog.Line(), &bit)
 			}
 			f.Refset = true
-			Thearch.Excise(f)
+			Excise(f)
 		}
 
 		for z := 0; z < BITS; z++ {
@@ -1357,7 +1358,7 @@ loop2:
 		if rgp.regno != 0 {
 			if Debug['R'] != 0 && Debug['v'] != 0 {
 				v := &vars[rgp.varno]
-				fmt.Printf("registerize %v+%d (bit=%2d et=%v) in %v usedreg=%#x vreg=%#x\n", v.node, v.offset, rgp.varno, Econv(v.etype), obj.Rconv(int(rgp.regno)), usedreg, vreg)
+				fmt.Printf("registerize %v+%d (bit=%2d et=%v) in %v usedreg=%#x vreg=%#x\n", v.node, v.offset, rgp.varno, ir.Econv(v.etype), obj.Rconv(int(rgp.regno)), usedreg, vreg)
 			}
 
 			paint3(rgp.enter, int(rgp.varno), vreg, int(rgp.regno))
@@ -1383,7 +1384,7 @@ loop2:
 	// pass 7
 	// peep-hole on basic block
 	if Debug['R'] == 0 || Debug['P'] != 0 {
-		Thearch.Peep(firstp)
+		thebackend.Peep(firstp)
 	}
 
 	// eliminate nops
@@ -0,0 +1,58 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gc
+
+import "cmd/compile/internal/ir"
+import "cmd/internal/obj"
+
+// A blockinfo describes a block opened by markdcl: the block
+// enclosing it and the lines between markdcl and the matching popdcl.
+type blockinfo struct {
+	parent     int32
+	start, end int32
+}
+
+// funcscopes returns the lexical scopes of fn that hold its named
+// local variables, for DWARF, along with the index of each variable's
+// scope. Scope 0 is the function itself: it holds the parameters and
+// top-level locals, as well as temporaries and variables declared
+// after parsing. Blocks without variables of their own are left out.
+func funcscopes(fn *ir.Node) ([]obj.Scope, map[*ir.Node]int32) {
+	if fn.Func.Block == 0 {
+		return nil, nil
+	}
+	scopes := []obj.Scope{{}}
+	index := map[int32]int32{fn.Func.Block: 0}
+	var lookup func(b int32) int32
+	lookup = func(b int32) int32 {
+		if i, ok := index[b]; ok {
+			return i
+		}
+		if b <= 1 {
+			// Not inside fn after all.
+			return 0
+		}
+		bi := blocks[b]
+		parent := lookup(bi.parent)
+		i := int32(len(scopes))
+		scopes = append(scopes, obj.Scope{Parent: parent, Start: bi.start, End: bi.end})
+		index[b] = i
+		return i
+	}
+
+	varscope := make(map[*ir.Node]int32)
+	for _, n := range fn.Func.Dcl {
+		if n.Op != ir.ONAME || n.Class != ir.PAUTO || n.Name.Block == 0 {
+			continue
+		}
+		if s := lookup(n.Name.Block); s != 0 {
+			varscope[n] = s
+		}
+	}
+	if len(scopes) == 1 {
+		return nil, nil
+	}
+	return scopes, varscope
+}
@@ -4,12 +4,14 @@
 
 package gc
 
+import "cmd/compile/internal/ir"
+
 // select
-func typecheckselect(sel *Node) {
-	var ncase *Node
-	var n *Node
+func typecheckselect(sel *ir.Node) {
+	var ncase *ir.Node
+	var n *ir.Node
 
-	var def *Node
+	var def *ir.Node
 	lno := setlineno(sel)
 	count := 0
 	typecheckslice(sel.Ninit.Slice(), Etop)
@@ -17,7 +19,7 @@ func typecheckselect(sel *Node) {
 		count++
 		ncase = n1
 		setlineno(ncase)
-		if ncase.Op != OXCASE {
+		if ncase.Op != ir.OXCASE {
 			Fatalf("typecheckselect %v", Oconv(ncase.Op, 0))
 		}
 
@@ -43,39 +45,39 @@ func typecheckselect(sel *Node) {
 				// convert x = <-c into OSELRECV(x, <-c).
 			// remove implicit conversions; the eventual assignment
 			// will reintroduce them.
-			case OAS:
-				if (n.Right.Op == OCONVNOP || n.Right.Op == OCONVIFACE) && n.Right.Implicit {
+			case ir.OAS:
+				if (n.Right.Op == ir.OCONVNOP || n.Right.Op == ir.OCONVIFACE) && n.Right.Implicit {
 					n.Right = n.Right.Left
 				}
 
-				if n.Right.Op != ORECV {
+				if n.Right.Op != ir.ORECV {
 					Yyerror("select assignment must have receive on right hand side")
 					break
 				}
 
-				n.Op = OSELRECV
+				n.Op = ir.OSELRECV
 
 				// convert x, ok = <-c into OSELRECV2(x, <-c) with ntest=ok
-			case OAS2RECV:
-				if n.Rlist.First().Op != ORECV {
+			case ir.OAS2RECV:
+				if n.Rlist.First().Op != ir.ORECV {
 					Yyerror("select assignment must have receive on right hand side")
 					break
 				}
 
-				n.Op = OSELRECV2
+				n.Op = ir.OSELRECV2
 				n.Left = n.List.First()
 				n.List.Set1(n.List.Second())
 				n.Right = n.Rlist.First()
 				n.Rlist.Set(nil)
 
 				// convert <-c into OSELRECV(N, <-c)
-			case ORECV:
-				n = Nod(OSELRECV, nil, n)
+			case ir.ORECV:
+				n = Nod(ir.OSELRECV, nil, n)
 
 				n.Typecheck = 1
 				ncase.Left = n
 
-			case OSEND:
+			case ir.OSEND:
 				break
 			}
 		}
@@ -87,7 +89,7 @@ func typecheckselect(sel *Node) {
 	lineno = lno
 }
 
-func walkselect(sel *Node) {
+func walkselect(sel *ir.Node) {
 	if sel.List.Len() == 0 && sel.Xoffset != 0 {
 		Fatalf("double walkselect") // already rewrote
 	}
@@ -96,19 +98,17 @@ func walkselect(sel *Node) {
 	i := sel.List.Len()
 
 	// optimization: zero-case select
-	var init []*Node
-	var r *Node
-	var n *Node
-	var var_ *Node
-	var selv *Node
+	var init []*ir.Node
+	var r *ir.Node
+	var n *ir.Node
+	var var_ *ir.Node
+	var selv *ir.Node
 	if i == 0 {
 		sel.Nbody.Set1(mkcall("block", nil, nil))
 		goto out
 	}
 
 	// optimization: one-case select: single op.
-	// TODO(rsc): Reenable optimization once order.go can handle it.
-	// golang.org/issue/7672.
 	if i == 1 {
 		cas := sel.List.First()
 		setlineno(cas)
@@ -117,22 +117,22 @@ func walkselect(sel *Node) {
 			n := cas.Left
 			l = append(l, n.Ninit.Slice()...)
 			n.Ninit.Set(nil)
-			var ch *Node
+			var ch *ir.Node
 			switch n.Op {
 			default:
 				Fatalf("select %v", Oconv(n.Op, 0))
 
 				// ok already
-			case OSEND:
+			case ir.OSEND:
 				ch = n.Left
 
-			case OSELRECV, OSELRECV2:
+			case ir.OSELRECV, ir.OSELRECV2:
 				ch = n.Right.Left
-				if n.Op == OSELRECV || n.List.Len() == 0 {
+				if n.Op == ir.OSELRECV || n.List.Len() == 0 {
 					if n.Left == nil {
 						n = n.Right
 					} else {
-						n.Op = OAS
+						n.Op = ir.OAS
 					}
 					break
 				}
@@ -142,8 +142,8 @@ func walkselect(sel *Node) {
 					n.Left = nblank
 				}
 
-				n.Op = OAS2
-				n.List.Set(append([]*Node{n.Left}, n.List.Slice()...))
+				n.Op = ir.OAS2
+				n.List.Set(append([]*ir.Node{n.Left}, n.List.Slice()...))
 				n.Rlist.Set1(n.Right)
 				n.Right = nil
 				n.Left = nil
@@ -152,10 +152,10 @@ func walkselect(sel *Node) {
 			}
 
 			// if ch == nil { block() }; n;
-			a := Nod(OIF, nil, nil)
+			a := Nod(ir.OIF, nil, nil)
 
-			a.Left = Nod(OEQ, ch, nodnil())
-			var ln Nodes
+			a.Left = Nod(ir.OEQ, ch, nodnil())
+			var ln ir.Nodes
 			ln.Set(l)
 			a.Nbody.Set1(mkcall("block", nil, &ln))
 			l = ln.Slice()
@@ -178,23 +178,23 @@ func walkselect(sel *Node) {
 			continue
 		}
 		switch n.Op {
-		case OSEND:
-			n.Right = Nod(OADDR, n.Right, nil)
+		case ir.OSEND:
+			n.Right = chanelem(n.Right)
 			n.Right = typecheck(n.Right, Erv)
 
-		case OSELRECV, OSELRECV2:
-			if n.Op == OSELRECV2 && n.List.Len() == 0 {
-				n.Op = OSELRECV
+		case ir.OSELRECV, ir.OSELRECV2:
+			if n.Op == ir.OSELRECV2 && n.List.Len() == 0 {
+				n.Op = ir.OSELRECV
 			}
-			if n.Op == OSELRECV2 {
-				n.List.SetIndex(0, Nod(OADDR, n.List.First(), nil))
+			if n.Op == ir.OSELRECV2 {
+				n.List.SetIndex(0, Nod(ir.OADDR, n.List.First(), nil))
 				n.List.SetIndex(0, typecheck(n.List.Index(0), Erv))
 			}
 
 			if n.Left == nil {
 				n.Left = nodnil()
 			} else {
-				n.Left = Nod(OADDR, n.Left, nil)
+				n.Left = chanelem(n.Left)
 				n.Left = typecheck(n.Left, Erv)
 			}
 		}
@@ -202,8 +202,8 @@ func walkselect(sel *Node) {
 
 	// optimization: two-case select but one is default: single non-blocking op.
 	if i == 2 && (sel.List.First().Left == nil || sel.List.Second().Left == nil) {
-		var cas *Node
-		var dflt *Node
+		var cas *ir.Node
+		var dflt *ir.Node
 		if sel.List.First().Left == nil {
 			cas = sel.List.Second()
 			dflt = sel.List.First()
@@ -214,33 +214,33 @@ func walkselect(sel *Node) {
 
 		n := cas.Left
 		setlineno(n)
-		r := Nod(OIF, nil, nil)
+		r := Nod(ir.OIF, nil, nil)
 		r.Ninit.Set(cas.Ninit.Slice())
 		switch n.Op {
 		default:
 			Fatalf("select %v", Oconv(n.Op, 0))
 
 			// if selectnbsend(c, v) { body } else { default body }
-		case OSEND:
+		case ir.OSEND:
 			ch := n.Left
 
-			r.Left = mkcall1(chanfn("selectnbsend", 2, ch.Type), Types[TBOOL], &r.Ninit, typename(ch.Type), ch, n.Right)
+			r.Left = mkcall1(chanfn("selectnbsend", 2, ch.Type), ir.Types[ir.TBOOL], &r.Ninit, typename(ch.Type), ch, n.Right)
 
 			// if c != nil && selectnbrecv(&v, c) { body } else { default body }
-		case OSELRECV:
-			r = Nod(OIF, nil, nil)
+		case ir.OSELRECV:
+			r = Nod(ir.OIF, nil, nil)
 
 			r.Ninit.Set(cas.Ninit.Slice())
 			ch := n.Right.Left
-			r.Left = mkcall1(chanfn("selectnbrecv", 2, ch.Type), Types[TBOOL], &r.Ninit, typename(ch.Type), n.Left, ch)
+			r.Left = mkcall1(chanfn("selectnbrecv", 2, ch.Type), ir.Types[ir.TBOOL], &r.Ninit, typename(ch.Type), n.Left, ch)
 
 			// if c != nil && selectnbrecv2(&v, c) { body } else { default body }
-		case OSELRECV2:
-			r = Nod(OIF, nil, nil)
+		case ir.OSELRECV2:
+			r = Nod(ir.OIF, nil, nil)
 
 			r.Ninit.Set(cas.Ninit.Slice())
 			ch := n.Right.Left
-			r.Left = mkcall1(chanfn("selectnbrecv2", 2, ch.Type), Types[TBOOL], &r.Ninit, typename(ch.Type), n.Left, n.List.First(), ch)
+			r.Left = mkcall1(chanfn("selectnbrecv2", 2, ch.Type), ir.Types[ir.TBOOL], &r.Ninit, typename(ch.Type), n.Left, n.List.First(), ch)
 		}
 
 		r.Left = typecheck(r.Left, Erv)
@@ -257,10 +257,10 @@ func walkselect(sel *Node) {
 	setlineno(sel)
 
 	selv = temp(selecttype(int32(sel.Xoffset)))
-	r = Nod(OAS, selv, nil)
+	r = Nod(ir.OAS, selv, nil)
 	r = typecheck(r, Etop)
 	init = append(init, r)
-	var_ = conv(conv(Nod(OADDR, selv, nil), Types[TUNSAFEPTR]), Ptrto(Types[TUINT8]))
+	var_ = conv(conv(Nod(ir.OADDR, selv, nil), ir.Types[ir.TUNSAFEPTR]), Ptrto(ir.Types[ir.TUINT8]))
 	r = mkcall("newselect", nil, nil, var_, Nodintconst(selv.Type.Width), Nodintconst(sel.Xoffset))
 	r = typecheck(r, Etop)
 	init = append(init, r)
@@ -268,7 +268,7 @@ func walkselect(sel *Node) {
 	for _, cas := range sel.List.Slice() {
 		setlineno(cas)
 		n = cas.Left
-		r = Nod(OIF, nil, nil)
+		r = Nod(ir.OIF, nil, nil)
 		r.Ninit.Set(cas.Ninit.Slice())
 		cas.Ninit.Set(nil)
 		if n != nil {
@@ -278,31 +278,31 @@ func walkselect(sel *Node) {
 
 		if n == nil {
 			// selectdefault(sel *byte);
-			r.Left = mkcall("selectdefault", Types[TBOOL], &r.Ninit, var_)
+			r.Left = mkcall("selectdefault", ir.Types[ir.TBOOL], &r.Ninit, var_)
 		} else {
 			switch n.Op {
 			default:
 				Fatalf("select %v", Oconv(n.Op, 0))
 
 				// selectsend(sel *byte, hchan *chan any, elem *any) (selected bool);
-			case OSEND:
-				r.Left = mkcall1(chanfn("selectsend", 2, n.Left.Type), Types[TBOOL], &r.Ninit, var_, n.Left, n.Right)
+			case ir.OSEND:
+				r.Left = mkcall1(chanfn("selectsend", 2, n.Left.Type), ir.Types[ir.TBOOL], &r.Ninit, var_, n.Left, n.Right)
 
 				// selectrecv(sel *byte, hchan *chan any, elem *any) (selected bool);
-			case OSELRECV:
-				r.Left = mkcall1(chanfn("selectrecv", 2, n.Right.Left.Type), Types[TBOOL], &r.Ninit, var_, n.Right.Left, n.Left)
+			case ir.OSELRECV:
+				r.Left = mkcall1(chanfn("selectrecv", 2, n.Right.Left.Type), ir.Types[ir.TBOOL], &r.Ninit, var_, n.Right.Left, n.Left)
 
 				// selectrecv2(sel *byte, hchan *chan any, elem *any, received *bool) (selected bool);
-			case OSELRECV2:
-				r.Left = mkcall1(chanfn("selectrecv2", 2, n.Right.Left.Type), Types[TBOOL], &r.Ninit, var_, n.Right.Left, n.Left, n.List.First())
+			case ir.OSELRECV2:
+				r.Left = mkcall1(chanfn("selectrecv2", 2, n.Right.Left.Type), ir.Types[ir.TBOOL], &r.Ninit, var_, n.Right.Left, n.Left, n.List.First())
 			}
 		}
 
 		// selv is no longer alive after use.
-		r.Nbody.Append(Nod(OVARKILL, selv, nil))
+		r.Nbody.Append(Nod(ir.OVARKILL, selv, nil))
 
 		r.Nbody.AppendNodes(&cas.Nbody)
-		r.Nbody.Append(Nod(OBREAK, nil, nil))
+		r.Nbody.Append(Nod(ir.OBREAK, nil, nil))
 		init = append(init, r)
 	}
 
@@ -319,33 +319,33 @@ out:
 }
 
 // Keep in sync with src/runtime/select.go.
-func selecttype(size int32) *Type {
+func selecttype(size int32) *ir.Type {
 	// TODO(dvyukov): it's possible to generate Scase only once
 	// and then cache; and also cache Select per size.
 
-	scase := Nod(OTSTRUCT, nil, nil)
-	scase.List.Append(Nod(ODCLFIELD, newname(Lookup("elem")), typenod(Ptrto(Types[TUINT8]))))
-	scase.List.Append(Nod(ODCLFIELD, newname(Lookup("chan")), typenod(Ptrto(Types[TUINT8]))))
-	scase.List.Append(Nod(ODCLFIELD, newname(Lookup("pc")), typenod(Types[TUINTPTR])))
-	scase.List.Append(Nod(ODCLFIELD, newname(Lookup("kind")), typenod(Types[TUINT16])))
-	scase.List.Append(Nod(ODCLFIELD, newname(Lookup("so")), typenod(Types[TUINT16])))
-	scase.List.Append(Nod(ODCLFIELD, newname(Lookup("receivedp")), typenod(Ptrto(Types[TUINT8]))))
-	scase.List.Append(Nod(ODCLFIELD, newname(Lookup("releasetime")), typenod(Types[TUINT64])))
+	scase := Nod(ir.OTSTRUCT, nil, nil)
+	scase.List.Append(Nod(ir.ODCLFIELD, newname(Lookup("elem")), typenod(Ptrto(ir.Types[ir.TUINT8]))))
+	scase.List.Append(Nod(ir.ODCLFIELD, newname(Lookup("chan")), typenod(Ptrto(ir.Types[ir.TUINT8]))))
+	scase.List.Append(Nod(ir.ODCLFIELD, newname(Lookup("pc")), typenod(ir.Types[ir.TUINTPTR])))
+	scase.List.Append(Nod(ir.ODCLFIELD, newname(Lookup("kind")), typenod(ir.Types[ir.TUINT16])))
+	scase.List.Append(Nod(ir.ODCLFIELD, newname(Lookup("so")), typenod(ir.Types[ir.TUINT16])))
+	scase.List.Append(Nod(ir.ODCLFIELD, newname(Lookup("receivedp")), typenod(Ptrto(ir.Types[ir.TUINT8]))))
+	scase.List.Append(Nod(ir.ODCLFIELD, newname(Lookup("releasetime")), typenod(ir.Types[ir.TUINT64])))
 	scase = typecheck(scase, Etype)
 	scase.Type.Noalg = true
 	scase.Type.Local = true
 
-	sel := Nod(OTSTRUCT, nil, nil)
-	sel.List.Append(Nod(ODCLFIELD, newname(Lookup("tcase")), typenod(Types[TUINT16])))
-	sel.List.Append(Nod(ODCLFIELD, newname(Lookup("ncase")), typenod(Types[TUINT16])))
-	sel.List.Append(Nod(ODCLFIELD, newname(Lookup("pollorder")), typenod(Ptrto(Types[TUINT8]))))
-	sel.List.Append(Nod(ODCLFIELD, newname(Lookup("lockorder")), typenod(Ptrto(Types[TUINT8]))))
-	arr := Nod(OTARRAY, Nodintconst(int64(size)), scase)
-	sel.List.Append(Nod(ODCLFIELD, newname(Lookup("scase")), arr))
-	arr = Nod(OTARRAY, Nodintconst(int64(size)), typenod(Types[TUINT16]))
-	sel.List.Append(Nod(ODCLFIELD, newname(Lookup("lockorderarr")), arr))
-	arr = Nod(OTARRAY, Nodintconst(int64(size)), typenod(Types[TUINT16]))
-	sel.List.Append(Nod(ODCLFIELD, newname(Lookup("pollorderarr")), arr))
+	sel := Nod(ir.OTSTRUCT, nil, nil)
+	sel.List.Append(Nod(ir.ODCLFIELD, newname(Lookup("tcase")), typenod(ir.Types[ir.TUINT16])))
+	sel.List.Append(Nod(ir.ODCLFIELD, newname(Lookup("ncase")), typenod(ir.Types[ir.TUINT16])))
+	sel.List.Append(Nod(ir.ODCLFIELD, newname(Lookup("pollorder")), typenod(Ptrto(ir.Types[ir.TUINT8]))))
+	sel.List.Append(Nod(ir.ODCLFIELD, newname(Lookup("lockorder")), typenod(Ptrto(ir.Types[ir.TUINT8]))))
+	arr := Nod(ir.OTARRAY, Nodintconst(int64(size)), scase)
+	sel.List.Append(Nod(ir.ODCLFIELD, newname(Lookup("scase")), arr))
+	arr = Nod(ir.OTARRAY, Nodintconst(int64(size)), typenod(ir.Types[ir.TUINT16]))
+	sel.List.Append(Nod(ir.ODCLFIELD, newname(Lookup("lockorderarr")), arr))
+	arr = Nod(ir.OTARRAY, Nodintconst(int64(size)), typenod(ir.Types[ir.TUINT16]))
+	sel.List.Append(Nod(ir.ODCLFIELD, newname(Lookup("pollorderarr")), arr))
 	sel = typecheck(sel, Etype)
 	sel.Type.Noalg = true
 	sel.Type.Local = true
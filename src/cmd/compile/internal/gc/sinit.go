@@ -5,6 +5,7 @@
 package gc
 
 import (
+	"cmd/compile/internal/ir"
 	"fmt"
 )
 
@@ -16,8 +17,8 @@ const (
 )
 
 type InitEntry struct {
-	Xoffset int64 // struct, array only
-	Expr    *Node // bytes of run-time computed expressions
+	Xoffset int64    // struct, array only
+	Expr    *ir.Node // bytes of run-time computed expressions
 }
 
 type InitPlan struct {
@@ -25,14 +26,14 @@ type InitPlan struct {
 }
 
 var (
-	initlist  []*Node
-	initplans map[*Node]*InitPlan
-	inittemps = make(map[*Node]*Node)
+	initlist  []*ir.Node
+	initplans map[*ir.Node]*InitPlan
+	inittemps = make(map[*ir.Node]*ir.Node)
 )
 
 // init1 walks the AST starting at n, and accumulates in out
 // the list of definitions needing init code in dependency order.
-func init1(n *Node, out *[]*Node) {
+func init1(n *ir.Node, out *[]*ir.Node) {
 	if n == nil {
 		return
 	}
@@ -42,17 +43,17 @@ func init1(n *Node, out *[]*Node) {
 		init1(n1, out)
 	}
 
-	if n.Left != nil && n.Type != nil && n.Left.Op == OTYPE && n.Class == PFUNC {
+	if n.Left != nil && n.Type != nil && n.Left.Op == ir.OTYPE && n.Class == ir.PFUNC {
 		// Methods called as Type.Method(receiver, ...).
 		// Definitions for method expressions are stored in type->nname.
 		init1(n.Type.Nname, out)
 	}
 
-	if n.Op != ONAME {
+	if n.Op != ir.ONAME {
 		return
 	}
 	switch n.Class {
-	case PEXTERN, PFUNC:
+	case ir.PEXTERN, ir.PFUNC:
 	default:
 		if isblank(n) && n.Name.Curfn == nil && n.Name.Defn != nil && n.Name.Defn.Initorder == InitNotStarted {
 			// blank names initialization is part of init() but not
@@ -75,7 +76,7 @@ func init1(n *Node, out *[]*Node) {
 		// Conversely, if there exists an initialization cycle involving
 		// a variable in the program, the tree walk will reach a cycle
 		// involving that variable.
-		if n.Class != PFUNC {
+		if n.Class != ir.PFUNC {
 			foundinitloop(n, n)
 		}
 
@@ -84,7 +85,7 @@ func init1(n *Node, out *[]*Node) {
 			if x == n {
 				break
 			}
-			if x.Class != PFUNC {
+			if x.Class != ir.PFUNC {
 				foundinitloop(n, x)
 			}
 		}
@@ -105,16 +106,16 @@ func init1(n *Node, out *[]*Node) {
 			Dump("defn", defn)
 			Fatalf("init1: bad defn")
 
-		case ODCLFUNC:
+		case ir.ODCLFUNC:
 			init2list(defn.Nbody, out)
 
-		case OAS:
+		case ir.OAS:
 			if defn.Left != n {
 				Dump("defn", defn)
 				Fatalf("init1: bad defn")
 			}
 			if isblank(defn.Left) && candiscard(defn.Right) {
-				defn.Op = OEMPTY
+				defn.Op = ir.OEMPTY
 				defn.Left = nil
 				defn.Right = nil
 				break
@@ -131,7 +132,7 @@ func init1(n *Node, out *[]*Node) {
 				*out = append(*out, defn)
 			}
 
-		case OAS2FUNC, OAS2MAPR, OAS2DOTTYPE, OAS2RECV:
+		case ir.OAS2FUNC, ir.OAS2MAPR, ir.OAS2DOTTYPE, ir.OAS2RECV:
 			if defn.Initorder == InitDone {
 				break
 			}
@@ -159,7 +160,7 @@ func init1(n *Node, out *[]*Node) {
 }
 
 // foundinitloop prints an init loop error and exits.
-func foundinitloop(node, visited *Node) {
+func foundinitloop(node, visited *ir.Node) {
 	// If there have already been errors printed,
 	// those errors probably confused us and
 	// there might not be a loop. Let the user
@@ -195,13 +196,13 @@ func foundinitloop(node, visited *Node) {
 }
 
 // recurse over n, doing init1 everywhere.
-func init2(n *Node, out *[]*Node) {
+func init2(n *ir.Node, out *[]*ir.Node) {
 	if n == nil || n.Initorder == InitDone {
 		return
 	}
 
-	if n.Op == ONAME && n.Ninit.Len() != 0 {
-		Fatalf("name %v with ninit: %v\n", n.Sym, Nconv(n, FmtSign))
+	if n.Op == ir.ONAME && n.Ninit.Len() != 0 {
+		Fatalf("name %v with ninit: %v\n", n.Sym, Nconv(n, ir.FmtSign))
 	}
 
 	init1(n, out)
@@ -212,25 +213,25 @@ func init2(n *Node, out *[]*Node) {
 	init2list(n.Rlist, out)
 	init2list(n.Nbody, out)
 
-	if n.Op == OCLOSURE {
+	if n.Op == ir.OCLOSURE {
 		init2list(n.Func.Closure.Nbody, out)
 	}
-	if n.Op == ODOTMETH || n.Op == OCALLPART {
+	if n.Op == ir.ODOTMETH || n.Op == ir.OCALLPART {
 		init2(n.Type.Nname, out)
 	}
 }
 
-func init2list(l Nodes, out *[]*Node) {
+func init2list(l ir.Nodes, out *[]*ir.Node) {
 	for _, n := range l.Slice() {
 		init2(n, out)
 	}
 }
 
-func initreorder(l []*Node, out *[]*Node) {
-	var n *Node
+func initreorder(l []*ir.Node, out *[]*ir.Node) {
+	var n *ir.Node
 	for _, n = range l {
 		switch n.Op {
-		case ODCLFUNC, ODCLCONST, ODCLTYPE:
+		case ir.ODCLFUNC, ir.ODCLCONST, ir.ODCLTYPE:
 			continue
 		}
 
@@ -243,9 +244,9 @@ func initreorder(l []*Node, out *[]*Node) {
 // initfix computes initialization order for a list l of top-level
 // declarations and outputs the corresponding list of statements
 // to include in the init() function body.
-func initfix(l []*Node) []*Node {
-	var lout []*Node
-	initplans = make(map[*Node]*InitPlan)
+func initfix(l []*ir.Node) []*ir.Node {
+	var lout []*ir.Node
+	initplans = make(map[*ir.Node]*InitPlan)
 	lno := lineno
 	initreorder(l, &lout)
 	lineno = lno
@@ -255,8 +256,8 @@ func initfix(l []*Node) []*Node {
 
 // compilation of top-level (static) assignments
 // into DATA statements if at all possible.
-func staticinit(n *Node, out *[]*Node) bool {
-	if n.Op != ONAME || n.Class != PEXTERN || n.Name.Defn == nil || n.Name.Defn.Op != OAS {
+func staticinit(n *ir.Node, out *[]*ir.Node) bool {
+	if n.Op != ir.ONAME || n.Class != ir.PEXTERN || n.Name.Defn == nil || n.Name.Defn.Op != ir.OAS {
 		Fatalf("staticinit")
 	}
 
@@ -268,73 +269,73 @@ func staticinit(n *Node, out *[]*Node) bool {
 
 // like staticassign but we are copying an already
 // initialized value r.
-func staticcopy(l *Node, r *Node, out *[]*Node) bool {
-	if r.Op != ONAME {
+func staticcopy(l *ir.Node, r *ir.Node, out *[]*ir.Node) bool {
+	if r.Op != ir.ONAME {
 		return false
 	}
-	if r.Class == PFUNC {
+	if r.Class == ir.PFUNC {
 		gdata(l, r, Widthptr)
 		return true
 	}
-	if r.Class != PEXTERN || r.Sym.Pkg != localpkg {
+	if r.Class != ir.PEXTERN || r.Sym.Pkg != localpkg {
 		return false
 	}
 	if r.Name.Defn == nil { // probably zeroed but perhaps supplied externally and of unknown value
 		return false
 	}
-	if r.Name.Defn.Op != OAS {
+	if r.Name.Defn.Op != ir.OAS {
 		return false
 	}
 	orig := r
 	r = r.Name.Defn.Right
 
-	for r.Op == OCONVNOP {
+	for r.Op == ir.OCONVNOP {
 		r = r.Left
 	}
 
 	switch r.Op {
-	case ONAME:
+	case ir.ONAME:
 		if staticcopy(l, r, out) {
 			return true
 		}
-		*out = append(*out, Nod(OAS, l, r))
+		*out = append(*out, Nod(ir.OAS, l, r))
 		return true
 
-	case OLITERAL:
+	case ir.OLITERAL:
 		if iszero(r) {
 			return true
 		}
 		gdata(l, r, int(l.Type.Width))
 		return true
 
-	case OADDR:
+	case ir.OADDR:
 		switch r.Left.Op {
-		case ONAME:
+		case ir.ONAME:
 			gdata(l, r, int(l.Type.Width))
 			return true
 		}
 
-	case OPTRLIT:
+	case ir.OPTRLIT:
 		switch r.Left.Op {
 		//dump("not static addr", r);
 		default:
 			break
 
 			// copy pointer
-		case OARRAYLIT, OSTRUCTLIT, OMAPLIT:
-			gdata(l, Nod(OADDR, inittemps[r], nil), int(l.Type.Width))
+		case ir.OARRAYLIT, ir.OSTRUCTLIT, ir.OMAPLIT:
+			gdata(l, Nod(ir.OADDR, inittemps[r], nil), int(l.Type.Width))
 
 			return true
 		}
 
-	case OARRAYLIT:
+	case ir.OARRAYLIT:
 		if Isslice(r.Type) {
 			// copy slice
 			a := inittemps[r]
 
 			n := *l
 			n.Xoffset = l.Xoffset + int64(Array_array)
-			gdata(&n, Nod(OADDR, a, nil), Widthptr)
+			gdata(&n, Nod(ir.OADDR, a, nil), Widthptr)
 			n.Xoffset = l.Xoffset + int64(Array_nel)
 			gdata(&n, r.Right, Widthint)
 			n.Xoffset = l.Xoffset + int64(Array_cap)
@@ -344,7 +345,7 @@ func staticcopy(l *Node, r *Node, out *[]*Node) bool {
 		fallthrough
 
 		// fall through
-	case OSTRUCTLIT:
+	case ir.OSTRUCTLIT:
 		p := initplans[r]
 
 		n := *l
@@ -352,23 +353,23 @@ func staticcopy(l *Node, r *Node, out *[]*Node) bool {
 			e := &p.E[i]
 			n.Xoffset = l.Xoffset + e.Xoffset
 			n.Type = e.Expr.Type
-			if e.Expr.Op == OLITERAL {
+			if e.Expr.Op == ir.OLITERAL {
 				gdata(&n, e.Expr, int(n.Type.Width))
 			} else {
-				ll := Nod(OXXX, nil, nil)
+				ll := Nod(ir.OXXX, nil, nil)
 				*ll = n
 				ll.Orig = ll // completely separate copy
 				if !staticassign(ll, e.Expr, out) {
 					// Requires computation, but we're
 					// copying someone else's computation.
-					rr := Nod(OXXX, nil, nil)
+					rr := Nod(ir.OXXX, nil, nil)
 
 					*rr = *orig
 					rr.Orig = rr // completely separate copy
 					rr.Type = ll.Type
 					rr.Xoffset += e.Xoffset
 					setlineno(rr)
-					*out = append(*out, Nod(OAS, ll, rr))
+					*out = append(*out, Nod(ir.OAS, ll, rr))
 				}
 			}
 		}
@@ -379,24 +380,24 @@ func staticcopy(l *Node, r *Node, out *[]*Node) bool {
 	return false
 }
 
-func staticassign(l *Node, r *Node, out *[]*Node) bool {
-	for r.Op == OCONVNOP {
+func staticassign(l *ir.Node, r *ir.Node, out *[]*ir.Node) bool {
+	for r.Op == ir.OCONVNOP {
 		r = r.Left
 	}
 
 	switch r.Op {
-	case ONAME:
+	case ir.ONAME:
 		return staticcopy(l, r, out)
 
-	case OLITERAL:
+	case ir.OLITERAL:
 		if iszero(r) {
 			return true
 		}
 		gdata(l, r, int(l.Type.Width))
 		return true
 
-	case OADDR:
-		var nam Node
+	case ir.OADDR:
+		var nam ir.Node
 		if stataddr(&nam, r.Left) {
 			n := *r
 			n.Left = &nam
@@ -405,43 +406,43 @@ func staticassign(l *Node, r *Node, out *[]*Node) bool {
 		}
 		fallthrough
 
-	case OPTRLIT:
+	case ir.OPTRLIT:
 		switch r.Left.Op {
-		case OARRAYLIT, OMAPLIT, OSTRUCTLIT:
+		case ir.OARRAYLIT, ir.OMAPLIT, ir.OSTRUCTLIT:
 			// Init pointer.
 			a := staticname(r.Left.Type, 1)
 
 			inittemps[r] = a
-			gdata(l, Nod(OADDR, a, nil), int(l.Type.Width))
+			gdata(l, Nod(ir.OADDR, a, nil), int(l.Type.Width))
 
 			// Init underlying literal.
 			if !staticassign(a, r.Left, out) {
-				*out = append(*out, Nod(OAS, a, r.Left))
+				*out = append(*out, Nod(ir.OAS, a, r.Left))
 			}
 			return true
 		}
 		//dump("not static ptrlit", r);
 
-	case OSTRARRAYBYTE:
-		if l.Class == PEXTERN && r.Left.Op == OLITERAL {
+	case ir.OSTRARRAYBYTE:
+		if l.Class == ir.PEXTERN && r.Left.Op == ir.OLITERAL {
 			sval := r.Left.Val().U.(string)
 			slicebytes(l, sval, len(sval))
 			return true
 		}
 
-	case OARRAYLIT:
+	case ir.OARRAYLIT:
 		initplan(r)
 		if Isslice(r.Type) {
 			// Init slice.
-			ta := typ(TARRAY)
+			ta := ir.Typ(ir.TARRAY)
 
 			ta.Type = r.Type.Type
-			ta.Bound = r.Right.Val().U.(*Mpint).Int64()
+			ta.Bound = r.Right.Val().U.(*ir.Mpint).Int64()
 			a := staticname(ta, 1)
 			inittemps[r] = a
 			n := *l
 			n.Xoffset = l.Xoffset + int64(Array_array)
-			gdata(&n, Nod(OADDR, a, nil), Widthptr)
+			gdata(&n, Nod(ir.OADDR, a, nil), Widthptr)
 			n.Xoffset = l.Xoffset + int64(Array_nel)
 			gdata(&n, r.Right, Widthint)
 			n.Xoffset = l.Xoffset + int64(Array_cap)
@@ -452,7 +453,7 @@ func staticassign(l *Node, r *Node, out *[]*Node) bool {
 		}
 		fallthrough
 
-	case OSTRUCTLIT:
+	case ir.OSTRUCTLIT:
 		initplan(r)
 
 		p := initplans[r]
@@ -461,26 +462,26 @@ func staticassign(l *Node, r *Node, out *[]*Node) bool {
 			e := &p.E[i]
 			n.Xoffset = l.Xoffset + e.Xoffset
 			n.Type = e.Expr.Type
-			if e.Expr.Op == OLITERAL {
+			if e.Expr.Op == ir.OLITERAL {
 				gdata(&n, e.Expr, int(n.Type.Width))
 			} else {
 				setlineno(e.Expr)
-				a := Nod(OXXX, nil, nil)
+				a := Nod(ir.OXXX, nil, nil)
 				*a = n
 				a.Orig = a // completely separate copy
 				if !staticassign(a, e.Expr, out) {
-					*out = append(*out, Nod(OAS, a, e.Expr))
+					*out = append(*out, Nod(ir.OAS, a, e.Expr))
 				}
 			}
 		}
 
 		return true
 
-	case OMAPLIT:
+	case ir.OMAPLIT:
 		// TODO: Table-driven map insert.
 		break
 
-	case OCLOSURE:
+	case ir.OCLOSURE:
 		if len(r.Func.Cvars.Slice()) == 0 {
 			// Closures with no captured variables are globals,
 			// so the assignment can be done at link time.
@@ -499,27 +500,28 @@ func staticassign(l *Node, r *Node, out *[]*Node) bool {
 // most of the work is to generate
 // data statements for the constant
 // part of the composite literal.
-func staticname(t *Type, ctxt int) *Node {
-	n := newname(LookupN("statictmp_", statuniqgen))
-	statuniqgen++
+func staticname(t *ir.Type, ctxt int) *ir.Node {
+	n := newname(LookupN("statictmp_", statictmpgen))
+	statictmpgen++
 	if ctxt == 0 {
 		n.Name.Readonly = true
+		rodatasyms = append(rodatasyms, n.Sym)
 	}
-	addvar(n, t, PEXTERN)
+	addvar(n, t, ir.PEXTERN)
 	return n
 }
 
-func isliteral(n *Node) bool {
+func isliteral(n *ir.Node) bool {
 	// Treat nils as zeros rather than literals.
-	return n.Op == OLITERAL && n.Val().Ctype() != CTNIL
+	return n.Op == ir.OLITERAL && n.Val().Ctype() != ir.CTNIL
 }
 
-func (n *Node) isSimpleName() bool {
-	return n.Op == ONAME && n.Addable && n.Class&PHEAP == 0 && n.Class != PPARAMREF
+func isSimpleName(n *ir.Node) bool {
+	return n.Op == ir.ONAME && n.Addable && n.Class&ir.PHEAP == 0 && n.Class != ir.PPARAMREF
 }
 
-func litas(l *Node, r *Node, init *Nodes) {
-	a := Nod(OAS, l, r)
+func litas(l *ir.Node, r *ir.Node, init *ir.Nodes) {
+	a := Nod(ir.OAS, l, r)
 	a = typecheck(a, Etop)
 	a = walkexpr(a, init)
 	init.Append(a)
@@ -533,7 +535,7 @@ const (
 	initConst                           // contains some constant values, which may be written into data symbols
 )
 
-func getdyn(n *Node, top int) initGenType {
+func getdyn(n *ir.Node, top int) initGenType {
 	switch n.Op {
 	default:
 		if isliteral(n) {
@@ -541,12 +543,12 @@ func getdyn(n *Node, top int) initGenType {
 		}
 		return initDynamic
 
-	case OARRAYLIT:
+	case ir.OARRAYLIT:
 		if top == 0 && n.Type.Bound < 0 {
 			return initDynamic
 		}
 
-	case OSTRUCTLIT:
+	case ir.OSTRUCTLIT:
 	}
 
 	var mode initGenType
@@ -560,22 +562,22 @@ func getdyn(n *Node, top int) initGenType {
 	return mode
 }
 
-func structlit(ctxt int, pass int, n *Node, var_ *Node, init *Nodes) {
+func structlit(ctxt int, pass int, n *ir.Node, var_ *ir.Node, init *ir.Nodes) {
 	for _, r := range n.List.Slice() {
-		if r.Op != OKEY {
+		if r.Op != ir.OKEY {
 			Fatalf("structlit: rhs not OKEY: %v", r)
 		}
 		index := r.Left
 		value := r.Right
 
 		switch value.Op {
-		case OARRAYLIT:
+		case ir.OARRAYLIT:
 			if value.Type.Bound < 0 {
 				if pass == 1 && ctxt != 0 {
-					a := NodSym(ODOT, var_, index.Sym)
+					a := NodSym(ir.ODOT, var_, index.Sym)
 					slicelit(ctxt, value, a, init)
 				} else if pass == 2 && ctxt == 0 {
-					a := NodSym(ODOT, var_, index.Sym)
+					a := NodSym(ir.ODOT, var_, index.Sym)
 					slicelit(ctxt, value, a, init)
 				} else if pass == 3 {
 					break
@@ -583,12 +585,12 @@ func structlit(ctxt int, pass int, n *Node, var_ *Node, init *Nodes) {
 				continue
 			}
 
-			a := NodSym(ODOT, var_, index.Sym)
+			a := NodSym(ir.ODOT, var_, index.Sym)
 			arraylit(ctxt, pass, value, a, init)
 			continue
 
-		case OSTRUCTLIT:
-			a := NodSym(ODOT, var_, index.Sym)
+		case ir.OSTRUCTLIT:
+			a := NodSym(ir.ODOT, var_, index.Sym)
 			structlit(ctxt, pass, value, a, init)
 			continue
 		}
@@ -603,13 +605,13 @@ func structlit(ctxt int, pass int, n *Node, var_ *Node, init *Nodes) {
 
 		// build list of var.field = expr
 		setlineno(value)
-		a := NodSym(ODOT, var_, index.Sym)
+		a := NodSym(ir.ODOT, var_, index.Sym)
 
-		a = Nod(OAS, a, value)
+		a = Nod(ir.OAS, a, value)
 		a = typecheck(a, Etop)
 		if pass == 1 {
 			a = walkexpr(a, init) // add any assignments in r to top
-			if a.Op != OAS {
+			if a.Op != ir.OAS {
 				Fatalf("structlit: not as")
 			}
 			a.Dodata = 2
@@ -622,22 +624,22 @@ func structlit(ctxt int, pass int, n *Node, var_ *Node, init *Nodes) {
 	}
 }
 
-func arraylit(ctxt int, pass int, n *Node, var_ *Node, init *Nodes) {
+func arraylit(ctxt int, pass int, n *ir.Node, var_ *ir.Node, init *ir.Nodes) {
 	for _, r := range n.List.Slice() {
-		if r.Op != OKEY {
+		if r.Op != ir.OKEY {
 			Fatalf("arraylit: rhs not OKEY: %v", r)
 		}
 		index := r.Left
 		value := r.Right
 
 		switch value.Op {
-		case OARRAYLIT:
+		case ir.OARRAYLIT:
 			if value.Type.Bound < 0 {
 				if pass == 1 && ctxt != 0 {
-					a := Nod(OINDEX, var_, index)
+					a := Nod(ir.OINDEX, var_, index)
 					slicelit(ctxt, value, a, init)
 				} else if pass == 2 && ctxt == 0 {
-					a := Nod(OINDEX, var_, index)
+					a := Nod(ir.OINDEX, var_, index)
 					slicelit(ctxt, value, a, init)
 				} else if pass == 3 {
 					break
@@ -645,12 +647,12 @@ func arraylit(ctxt int, pass int, n *Node, var_ *Node, init *Nodes) {
 				continue
 			}
 
-			a := Nod(OINDEX, var_, index)
+			a := Nod(ir.OINDEX, var_, index)
 			arraylit(ctxt, pass, value, a, init)
 			continue
 
-		case OSTRUCTLIT:
-			a := Nod(OINDEX, var_, index)
+		case ir.OSTRUCTLIT:
+			a := Nod(ir.OINDEX, var_, index)
 			structlit(ctxt, pass, value, a, init)
 			continue
 		}
@@ -665,13 +667,13 @@ func arraylit(ctxt int, pass int, n *Node, var_ *Node, init *Nodes) {
 
 		// build list of var[index] = value
 		setlineno(value)
-		a := Nod(OINDEX, var_, index)
+		a := Nod(ir.OINDEX, var_, index)
 
-		a = Nod(OAS, a, value)
+		a = Nod(ir.OAS, a, value)
 		a = typecheck(a, Etop)
 		if pass == 1 {
 			a = walkexpr(a, init)
-			if a.Op != OAS {
+			if a.Op != ir.OAS {
 				Fatalf("arraylit: not as")
 			}
 			a.Dodata = 2
@@ -684,11 +686,12 @@ func arraylit(ctxt int, pass int, n *Node, var_ *Node, init *Nodes) {
 	}
 }
 
-func slicelit(ctxt int, n *Node, var_ *Node, init *Nodes) {
+func slicelit(ctxt int, n *ir.Node, var_ *ir.Node, init *ir.Nodes) {
 	// make an array type
 	t := n.Type.Copy()
-	t.Bound = n.Right.Val().U.(*Mpint).Int64()
+	t.Bound = n.Right.Val().U.(*ir.Mpint).Int64()
 	t.Width = 0
+	t.Align = 0
 	t.Sym = nil
 	t.Haspointers = 0
 	dowidth(t)
@@ -701,9 +704,9 @@ func slicelit(ctxt int, n *Node, var_ *Node, init *Nodes) {
 		arraylit(ctxt, 2, n, vstat, init)
 
 		// copy static to slice
-		a := Nod(OSLICE, vstat, Nod(OKEY, nil, nil))
+		a := Nod(ir.OSLICE, vstat, Nod(ir.OKEY, nil, nil))
 
-		a = Nod(OAS, var_, a)
+		a = Nod(ir.OAS, var_, a)
 		a = typecheck(a, Etop)
 		a.Dodata = 2
 		init.Append(a)
@@ -731,7 +734,7 @@ func slicelit(ctxt int, n *Node, var_ *Node, init *Nodes) {
 
 	// if the literal contains constants,
 	// make static initialized array (1),(2)
-	var vstat *Node
+	var vstat *ir.Node
 
 	mode := getdyn(n, 1)
 	if mode&initConst != 0 {
@@ -743,50 +746,50 @@ func slicelit(ctxt int, n *Node, var_ *Node, init *Nodes) {
 	vauto := temp(Ptrto(t))
 
 	// set auto to point at new temp or heap (3 assign)
-	var a *Node
+	var a *ir.Node
 	if x := prealloc[n]; x != nil {
 		// temp allocated during order.go for dddarg
 		x.Type = t
 
 		if vstat == nil {
-			a = Nod(OAS, x, nil)
+			a = Nod(ir.OAS, x, nil)
 			a = typecheck(a, Etop)
 			init.Append(a) // zero new temp
 		}
 
-		a = Nod(OADDR, x, nil)
+		a = Nod(ir.OADDR, x, nil)
 	} else if n.Esc == EscNone {
 		a = temp(t)
 		if vstat == nil {
-			a = Nod(OAS, temp(t), nil)
+			a = Nod(ir.OAS, temp(t), nil)
 			a = typecheck(a, Etop)
 			init.Append(a) // zero new temp
 			a = a.Left
 		}
 
-		a = Nod(OADDR, a, nil)
+		a = Nod(ir.OADDR, a, nil)
 	} else {
-		a = Nod(ONEW, nil, nil)
+		a = Nod(ir.ONEW, nil, nil)
 		a.List.Set1(typenod(t))
 	}
 
-	a = Nod(OAS, vauto, a)
+	a = Nod(ir.OAS, vauto, a)
 	a = typecheck(a, Etop)
 	a = walkexpr(a, init)
 	init.Append(a)
 
 	if vstat != nil {
 		// copy static to heap (4)
-		a = Nod(OIND, vauto, nil)
+		a = Nod(ir.OIND, vauto, nil)
 
-		a = Nod(OAS, a, vstat)
+		a = Nod(ir.OAS, a, vstat)
 		a = typecheck(a, Etop)
 		a = walkexpr(a, init)
 		init.Append(a)
 	}
 
 	// make slice out of heap (5)
-	a = Nod(OAS, var_, Nod(OSLICE, vauto, Nod(OKEY, nil, nil)))
+	a = Nod(ir.OAS, var_, Nod(ir.OSLICE, vauto, Nod(ir.OKEY, nil, nil)))
 
 	a = typecheck(a, Etop)
 	a = orderstmtinplace(a)
@@ -794,25 +797,25 @@ func slicelit(ctxt int, n *Node, var_ *Node, init *Nodes) {
 	init.Append(a)
 	// put dynamics into slice (6)
 	for _, r := range n.List.Slice() {
-		if r.Op != OKEY {
+		if r.Op != ir.OKEY {
 			Fatalf("slicelit: rhs not OKEY: %v", r)
 		}
 		index := r.Left
 		value := r.Right
-		a := Nod(OINDEX, var_, index)
+		a := Nod(ir.OINDEX, var_, index)
 		a.Bounded = true
 
 		// TODO need to check bounds?
 
 		switch value.Op {
-		case OARRAYLIT:
+		case ir.OARRAYLIT:
 			if value.Type.Bound < 0 {
 				break
 			}
 			arraylit(ctxt, 2, value, a, init)
 			continue
 
-		case OSTRUCTLIT:
+		case ir.OSTRUCTLIT:
 			structlit(ctxt, 2, value, a, init)
 			continue
 		}
@@ -823,7 +826,7 @@ func slicelit(ctxt int, n *Node, var_ *Node, init *Nodes) {
 
 		// build list of var[c] = expr
 		setlineno(value)
-		a = Nod(OAS, a, value)
+		a = Nod(ir.OAS, a, value)
 
 		a = typecheck(a, Etop)
 		a = orderstmtinplace(a)
@@ -832,20 +835,20 @@ func slicelit(ctxt int, n *Node, var_ *Node, init *Nodes) {
 	}
 }
 
-func maplit(ctxt int, n *Node, var_ *Node, init *Nodes) {
+func maplit(ctxt int, n *ir.Node, var_ *ir.Node, init *ir.Nodes) {
 	ctxt = 0
 
 	// make the map var
 	nerr := nerrors
 
-	a := Nod(OMAKE, nil, nil)
+	a := Nod(ir.OMAKE, nil, nil)
 	a.List.Set1(typenod(n.Type))
 	litas(var_, a, init)
 
 	// count the initializers
 	b := 0
 	for _, r := range n.List.Slice() {
-		if r.Op != OKEY {
+		if r.Op != ir.OKEY {
 			Fatalf("maplit: rhs not OKEY: %v", r)
 		}
 		index := r.Left
@@ -865,18 +868,18 @@ func maplit(ctxt int, n *Node, var_ *Node, init *Nodes) {
 		syma := Lookup("a")
 		symb := Lookup("b")
 
-		var fields [2]*Field
-		fields[0] = newField()
+		var fields [2]*ir.Field
+		fields[0] = ir.NewField()
 		fields[0].Type = tk
 		fields[0].Sym = syma
-		fields[1] = newField()
+		fields[1] = ir.NewField()
 		fields[1].Type = tv
 		fields[1].Sym = symb
 
-		tstruct := typ(TSTRUCT)
+		tstruct := ir.Typ(ir.TSTRUCT)
 		tstruct.SetFields(fields[:])
 
-		tarr := typ(TARRAY)
+		tarr := ir.Typ(ir.TARRAY)
 		tarr.Bound = int64(b)
 		tarr.Type = tstruct
 
@@ -888,7 +891,7 @@ func maplit(ctxt int, n *Node, var_ *Node, init *Nodes) {
 
 		b := int64(0)
 		for _, r := range n.List.Slice() {
-			if r.Op != OKEY {
+			if r.Op != ir.OKEY {
 				Fatalf("maplit: rhs not OKEY: %v", r)
 			}
 			index := r.Left
@@ -899,9 +902,9 @@ func maplit(ctxt int, n *Node, var_ *Node, init *Nodes) {
 				setlineno(index)
 				a = Nodintconst(b)
 
-				a = Nod(OINDEX, vstat, a)
-				a = NodSym(ODOT, a, syma)
-				a = Nod(OAS, a, index)
+				a = Nod(ir.OINDEX, vstat, a)
+				a = NodSym(ir.ODOT, a, syma)
+				a = Nod(ir.OAS, a, index)
 				a = typecheck(a, Etop)
 				a = walkexpr(a, init)
 				a.Dodata = 2
@@ -911,9 +914,9 @@ func maplit(ctxt int, n *Node, var_ *Node, init *Nodes) {
 				setlineno(value)
 				a = Nodintconst(b)
 
-				a = Nod(OINDEX, vstat, a)
-				a = NodSym(ODOT, a, symb)
-				a = Nod(OAS, a, value)
+				a = Nod(ir.OINDEX, vstat, a)
+				a = NodSym(ir.ODOT, a, symb)
+				a = Nod(ir.OAS, a, value)
 				a = typecheck(a, Etop)
 				a = walkexpr(a, init)
 				a.Dodata = 2
@@ -927,25 +930,25 @@ func maplit(ctxt int, n *Node, var_ *Node, init *Nodes) {
 		// for i = 0; i < len(vstat); i++ {
 		//	map[vstat[i].a] = vstat[i].b
 		// }
-		index := temp(Types[TINT])
+		index := temp(ir.Types[ir.TINT])
 
-		a = Nod(OINDEX, vstat, index)
+		a = Nod(ir.OINDEX, vstat, index)
 		a.Bounded = true
-		a = NodSym(ODOT, a, symb)
+		a = NodSym(ir.ODOT, a, symb)
 
-		r := Nod(OINDEX, vstat, index)
+		r := Nod(ir.OINDEX, vstat, index)
 		r.Bounded = true
-		r = NodSym(ODOT, r, syma)
-		r = Nod(OINDEX, var_, r)
+		r = NodSym(ir.ODOT, r, syma)
+		r = Nod(ir.OINDEX, var_, r)
 
-		r = Nod(OAS, r, a)
+		r = Nod(ir.OAS, r, a)
 
-		a = Nod(OFOR, nil, nil)
+		a = Nod(ir.OFOR, nil, nil)
 		a.Nbody.Set1(r)
 
-		a.Ninit.Set1(Nod(OAS, index, Nodintconst(0)))
-		a.Left = Nod(OLT, index, Nodintconst(tarr.Bound))
-		a.Right = Nod(OAS, index, Nod(OADD, index, Nodintconst(1)))
+		a.Ninit.Set1(Nod(ir.OAS, index, Nodintconst(0)))
+		a.Left = Nod(ir.OLT, index, Nodintconst(tarr.Bound))
+		a.Right = Nod(ir.OAS, index, Nod(ir.OADD, index, Nodintconst(1)))
 
 		a = typecheck(a, Etop)
 		a = walkstmt(a)
@@ -953,9 +956,9 @@ func maplit(ctxt int, n *Node, var_ *Node, init *Nodes) {
 	}
 
 	// put in dynamic entries one-at-a-time
-	var key, val *Node
+	var key, val *ir.Node
 	for _, r := range n.List.Slice() {
-		if r.Op != OKEY {
+		if r.Op != ir.OKEY {
 			Fatalf("maplit: rhs not OKEY: %v", r)
 		}
 		index := r.Left
@@ -973,18 +976,18 @@ func maplit(ctxt int, n *Node, var_ *Node, init *Nodes) {
 		}
 
 		setlineno(r.Left)
-		a = Nod(OAS, key, r.Left)
+		a = Nod(ir.OAS, key, r.Left)
 		a = typecheck(a, Etop)
 		a = walkstmt(a)
 		init.Append(a)
 		setlineno(r.Right)
-		a = Nod(OAS, val, r.Right)
+		a = Nod(ir.OAS, val, r.Right)
 		a = typecheck(a, Etop)
 		a = walkstmt(a)
 		init.Append(a)
 
 		setlineno(val)
-		a = Nod(OAS, Nod(OINDEX, var_, key), val)
+		a = Nod(ir.OAS, Nod(ir.OINDEX, var_, key), val)
 		a = typecheck(a, Etop)
 		a = walkstmt(a)
 		init.Append(a)
@@ -995,53 +998,53 @@ func maplit(ctxt int, n *Node, var_ *Node, init *Nodes) {
 	}
 
 	if key != nil {
-		a = Nod(OVARKILL, key, nil)
+		a = Nod(ir.OVARKILL, key, nil)
 		a = typecheck(a, Etop)
 		init.Append(a)
-		a = Nod(OVARKILL, val, nil)
+		a = Nod(ir.OVARKILL, val, nil)
 		a = typecheck(a, Etop)
 		init.Append(a)
 	}
 }
 
-func anylit(ctxt int, n *Node, var_ *Node, init *Nodes) {
+func anylit(ctxt int, n *ir.Node, var_ *ir.Node, init *ir.Nodes) {
 	t := n.Type
 	switch n.Op {
 	default:
 		Fatalf("anylit: not lit")
 
-	case OPTRLIT:
+	case ir.OPTRLIT:
 		if !Isptr[t.Etype] {
 			Fatalf("anylit: not ptr")
 		}
 
-		var r *Node
+		var r *ir.Node
 		if n.Right != nil {
-			r = Nod(OADDR, n.Right, nil)
+			r = Nod(ir.OADDR, n.Right, nil)
 			r = typecheck(r, Erv)
 		} else {
-			r = Nod(ONEW, nil, nil)
+			r = Nod(ir.ONEW, nil, nil)
 			r.Typecheck = 1
 			r.Type = t
 			r.Esc = n.Esc
 		}
 
 		r = walkexpr(r, init)
-		a := Nod(OAS, var_, r)
+		a := Nod(ir.OAS, var_, r)
 
 		a = typecheck(a, Etop)
 		init.Append(a)
 
-		var_ = Nod(OIND, var_, nil)
+		var_ = Nod(ir.OIND, var_, nil)
 		var_ = typecheck(var_, Erv|Easgn)
 		anylit(ctxt, n.Left, var_, init)
 
-	case OSTRUCTLIT:
-		if t.Etype != TSTRUCT {
+	case ir.OSTRUCTLIT:
+		if t.Etype != ir.TSTRUCT {
 			Fatalf("anylit: not struct")
 		}
 
-		if var_.isSimpleName() && n.List.Len() > 4 {
+		if isSimpleName(var_) && n.List.Len() > 4 {
 			if ctxt == 0 {
 				// lay out static data
 				vstat := staticname(t, ctxt)
@@ -1049,7 +1052,7 @@ func anylit(ctxt int, n *Node, var_ *Node, init *Nodes) {
 				structlit(ctxt, 1, n, vstat, init)
 
 				// copy static to var
-				a := Nod(OAS, var_, vstat)
+				a := Nod(ir.OAS, var_, vstat)
 
 				a = typecheck(a, Etop)
 				a = walkexpr(a, init)
@@ -1067,8 +1070,8 @@ func anylit(ctxt int, n *Node, var_ *Node, init *Nodes) {
 		}
 
 		// initialize of not completely specified
-		if var_.isSimpleName() || n.List.Len() < t.NumFields() {
-			a := Nod(OAS, var_, nil)
+		if isSimpleName(var_) || n.List.Len() < t.NumFields() {
+			a := Nod(ir.OAS, var_, nil)
 			a = typecheck(a, Etop)
 			a = walkexpr(a, init)
 			init.Append(a)
@@ -1076,8 +1079,8 @@ func anylit(ctxt int, n *Node, var_ *Node, init *Nodes) {
 
 		structlit(ctxt, 3, n, var_, init)
 
-	case OARRAYLIT:
-		if t.Etype != TARRAY {
+	case ir.OARRAYLIT:
+		if t.Etype != ir.TARRAY {
 			Fatalf("anylit: not array")
 		}
 		if t.Bound < 0 {
@@ -1085,7 +1088,7 @@ func anylit(ctxt int, n *Node, var_ *Node, init *Nodes) {
 			break
 		}
 
-		if var_.isSimpleName() && n.List.Len() > 4 {
+		if isSimpleName(var_) && n.List.Len() > 4 {
 			if ctxt == 0 {
 				// lay out static data
 				vstat := staticname(t, ctxt)
@@ -1093,7 +1096,7 @@ func anylit(ctxt int, n *Node, var_ *Node, init *Nodes) {
 				arraylit(1, 1, n, vstat, init)
 
 				// copy static to automatic
-				a := Nod(OAS, var_, vstat)
+				a := Nod(ir.OAS, var_, vstat)
 
 				a = typecheck(a, Etop)
 				a = walkexpr(a, init)
@@ -1111,8 +1114,8 @@ func anylit(ctxt int, n *Node, var_ *Node, init *Nodes) {
 		}
 
 		// initialize of not completely specified
-		if var_.isSimpleName() || int64(n.List.Len()) < t.Bound {
-			a := Nod(OAS, var_, nil)
+		if isSimpleName(var_) || int64(n.List.Len()) < t.Bound {
+			a := Nod(ir.OAS, var_, nil)
 			a = typecheck(a, Etop)
 			a = walkexpr(a, init)
 			init.Append(a)
@@ -1120,15 +1123,15 @@ func anylit(ctxt int, n *Node, var_ *Node, init *Nodes) {
 
 		arraylit(ctxt, 3, n, var_, init)
 
-	case OMAPLIT:
-		if t.Etype != TMAP {
+	case ir.OMAPLIT:
+		if t.Etype != ir.TMAP {
 			Fatalf("anylit: not map")
 		}
 		maplit(ctxt, n, var_, init)
 	}
 }
 
-func oaslit(n *Node, init *Nodes) bool {
+func oaslit(n *ir.Node, init *ir.Nodes) bool {
 	if n.Left == nil || n.Right == nil {
 		// not a special composit literal assignment
 		return false
@@ -1137,7 +1140,7 @@ func oaslit(n *Node, init *Nodes) bool {
 		// not a special composit literal assignment
 		return false
 	}
-	if !n.Left.isSimpleName() {
+	if !isSimpleName(n.Left) {
 		// not a special composit literal assignment
 		return false
 	}
@@ -1159,7 +1162,7 @@ func oaslit(n *Node, init *Nodes) bool {
 		// not a special composit literal assignment
 		return false
 
-	case OSTRUCTLIT, OARRAYLIT, OMAPLIT:
+	case ir.OSTRUCTLIT, ir.OARRAYLIT, ir.OMAPLIT:
 		if vmatch1(n.Left, n.Right) {
 			// not a special composit literal assignment
 			return false
@@ -1167,30 +1170,30 @@ func oaslit(n *Node, init *Nodes) bool {
 		anylit(ctxt, n.Right, n.Left, init)
 	}
 
-	n.Op = OEMPTY
+	n.Op = ir.OEMPTY
 	n.Right = nil
 	return true
 }
 
-func getlit(lit *Node) int {
+func getlit(lit *ir.Node) int {
 	if Smallintconst(lit) {
-		return int(lit.Val().U.(*Mpint).Int64())
+		return int(lit.Val().U.(*ir.Mpint).Int64())
 	}
 	return -1
 }
 
 // stataddr sets nam to the static address of n and reports whether it succeeeded.
-func stataddr(nam *Node, n *Node) bool {
+func stataddr(nam *ir.Node, n *ir.Node) bool {
 	if n == nil {
 		return false
 	}
 
 	switch n.Op {
-	case ONAME:
+	case ir.ONAME:
 		*nam = *n
 		return n.Addable
 
-	case ODOT:
+	case ir.ODOT:
 		if !stataddr(nam, n.Left) {
 			break
 		}
@@ -1198,7 +1201,7 @@ func stataddr(nam *Node, n *Node) bool {
 		nam.Type = n.Type
 		return true
 
-	case OINDEX:
+	case ir.OINDEX:
 		if n.Left.Type.Bound < 0 {
 			break
 		}
@@ -1222,7 +1225,7 @@ func stataddr(nam *Node, n *Node) bool {
 	return false
 }
 
-func initplan(n *Node) {
+func initplan(n *ir.Node) {
 	if initplans[n] != nil {
 		return
 	}
@@ -1232,25 +1235,25 @@ func initplan(n *Node) {
 	default:
 		Fatalf("initplan")
 
-	case OARRAYLIT:
+	case ir.OARRAYLIT:
 		for _, a := range n.List.Slice() {
-			if a.Op != OKEY || !Smallintconst(a.Left) {
+			if a.Op != ir.OKEY || !Smallintconst(a.Left) {
 				Fatalf("initplan arraylit")
 			}
-			addvalue(p, n.Type.Type.Width*a.Left.Val().U.(*Mpint).Int64(), a.Right)
+			addvalue(p, n.Type.Type.Width*a.Left.Val().U.(*ir.Mpint).Int64(), a.Right)
 		}
 
-	case OSTRUCTLIT:
+	case ir.OSTRUCTLIT:
 		for _, a := range n.List.Slice() {
-			if a.Op != OKEY || a.Left.Type != structkey {
+			if a.Op != ir.OKEY || a.Left.Type != structkey {
 				Fatalf("initplan structlit")
 			}
 			addvalue(p, a.Left.Xoffset, a.Right)
 		}
 
-	case OMAPLIT:
+	case ir.OMAPLIT:
 		for _, a := range n.List.Slice() {
-			if a.Op != OKEY {
+			if a.Op != ir.OKEY {
 				Fatalf("initplan maplit")
 			}
 			addvalue(p, -1, a.Right)
@@ -1258,7 +1261,7 @@ func initplan(n *Node) {
 	}
 }
 
-func addvalue(p *InitPlan, xoffset int64, n *Node) {
+func addvalue(p *InitPlan, xoffset int64, n *ir.Node) {
 	// special case: zero can be dropped entirely
 	if iszero(n) {
 		return
@@ -1280,41 +1283,41 @@ func addvalue(p *InitPlan, xoffset int64, n *Node) {
 	p.E = append(p.E, InitEntry{Xoffset: xoffset, Expr: n})
 }
 
-func iszero(n *Node) bool {
+func iszero(n *ir.Node) bool {
 	switch n.Op {
-	case OLITERAL:
+	case ir.OLITERAL:
 		switch n.Val().Ctype() {
 		default:
 			Dump("unexpected literal", n)
 			Fatalf("iszero")
 
-		case CTNIL:
+		case ir.CTNIL:
 			return true
 
-		case CTSTR:
+		case ir.CTSTR:
 			return n.Val().U.(string) == ""
 
-		case CTBOOL:
+		case ir.CTBOOL:
 			return !n.Val().U.(bool)
 
-		case CTINT, CTRUNE:
-			return n.Val().U.(*Mpint).CmpInt64(0) == 0
+		case ir.CTINT, ir.CTRUNE:
+			return n.Val().U.(*ir.Mpint).CmpInt64(0) == 0
 
-		case CTFLT:
-			return n.Val().U.(*Mpflt).CmpFloat64(0) == 0
+		case ir.CTFLT:
+			return n.Val().U.(*ir.Mpflt).CmpFloat64(0) == 0
 
-		case CTCPLX:
-			return n.Val().U.(*Mpcplx).Real.CmpFloat64(0) == 0 && n.Val().U.(*Mpcplx).Imag.CmpFloat64(0) == 0
+		case ir.CTCPLX:
+			return n.Val().U.(*ir.Mpcplx).Real.CmpFloat64(0) == 0 && n.Val().U.(*ir.Mpcplx).Imag.CmpFloat64(0) == 0
 		}
 
-	case OARRAYLIT:
+	case ir.OARRAYLIT:
 		if Isslice(n.Type) {
 			break
 		}
 		fallthrough
 
 		// fall through
-	case OSTRUCTLIT:
+	case ir.OSTRUCTLIT:
 		for _, n1 := range n.List.Slice() {
 			if !iszero(n1.Right) {
 				return false
@@ -1326,13 +1329,13 @@ func iszero(n *Node) bool {
 	return false
 }
 
-func isvaluelit(n *Node) bool {
-	return (n.Op == OARRAYLIT && Isfixedarray(n.Type)) || n.Op == OSTRUCTLIT
+func isvaluelit(n *ir.Node) bool {
+	return (n.Op == ir.OARRAYLIT && Isfixedarray(n.Type)) || n.Op == ir.OSTRUCTLIT
 }
 
 // gen_as_init attempts to emit static data for n and reports whether it succeeded.
 // If reportOnly is true, it does not emit static data and does not modify the AST.
-func gen_as_init(n *Node, reportOnly bool) bool {
+func gen_as_init(n *ir.Node, reportOnly bool) bool {
 	success := genAsInitNoCheck(n, reportOnly)
 	if !success && n.Dodata == 2 {
 		Dump("\ngen_as_init", n)
@@ -1341,7 +1344,7 @@ func gen_as_init(n *Node, reportOnly bool) bool {
 	return success
 }
 
-func genAsInitNoCheck(n *Node, reportOnly bool) bool {
+func genAsInitNoCheck(n *ir.Node, reportOnly bool) bool {
 	if n.Dodata == 0 {
 		return false
 	}
@@ -1349,16 +1352,16 @@ func genAsInitNoCheck(n *Node, reportOnly bool) bool {
 	nr := n.Right
 	nl := n.Left
 	if nr == nil {
-		var nam Node
-		return stataddr(&nam, nl) && nam.Class == PEXTERN
+		var nam ir.Node
+		return stataddr(&nam, nl) && nam.Class == ir.PEXTERN
 	}
 
 	if nr.Type == nil || !Eqtype(nl.Type, nr.Type) {
 		return false
 	}
 
-	var nam Node
-	if !stataddr(&nam, nl) || nam.Class != PEXTERN {
+	var nam ir.Node
+	if !stataddr(&nam, nl) || nam.Class != ir.PEXTERN {
 		return false
 	}
 
@@ -1366,29 +1369,29 @@ func genAsInitNoCheck(n *Node, reportOnly bool) bool {
 	default:
 		return false
 
-	case OCONVNOP:
+	case ir.OCONVNOP:
 		nr = nr.Left
-		if nr == nil || nr.Op != OSLICEARR {
+		if nr == nil || nr.Op != ir.OSLICEARR {
 			return false
 		}
 		fallthrough
 
-	case OSLICEARR:
-		if nr.Right.Op != OKEY || nr.Right.Left != nil || nr.Right.Right != nil {
+	case ir.OSLICEARR:
+		if nr.Right.Op != ir.OKEY || nr.Right.Left != nil || nr.Right.Right != nil {
 			return false
 		}
 		nr = nr.Left
-		if nr == nil || nr.Op != OADDR {
+		if nr == nil || nr.Op != ir.OADDR {
 			return false
 		}
 		ptr := nr
 		nr = nr.Left
-		if nr == nil || nr.Op != ONAME {
+		if nr == nil || nr.Op != ir.ONAME {
 			return false
 		}
 
 		// nr is the array being converted to a slice
-		if nr.Type == nil || nr.Type.Etype != TARRAY || nr.Type.Bound < 0 {
+		if nr.Type == nil || nr.Type.Etype != ir.TARRAY || nr.Type.Bound < 0 {
 			return false
 		}
 
@@ -1397,8 +1400,8 @@ func genAsInitNoCheck(n *Node, reportOnly bool) bool {
 			gdata(&nam, ptr, Widthptr)
 
 			nam.Xoffset += int64(Array_nel) - int64(Array_array)
-			var nod1 Node
-			Nodconst(&nod1, Types[TINT], nr.Type.Bound)
+			var nod1 ir.Node
+			Nodconst(&nod1, ir.Types[ir.TINT], nr.Type.Bound)
 			gdata(&nam, &nod1, Widthint)
 
 			nam.Xoffset += int64(Array_cap) - int64(Array_nel)
@@ -1407,7 +1410,7 @@ func genAsInitNoCheck(n *Node, reportOnly bool) bool {
 
 		return true
 
-	case OLITERAL:
+	case ir.OLITERAL:
 		break
 	}
 
@@ -1415,21 +1418,21 @@ func genAsInitNoCheck(n *Node, reportOnly bool) bool {
 	default:
 		return false
 
-	case TBOOL, TINT8, TUINT8, TINT16, TUINT16,
-		TINT32, TUINT32, TINT64, TUINT64,
-		TINT, TUINT, TUINTPTR,
-		TPTR32, TPTR64,
-		TFLOAT32, TFLOAT64:
+	case ir.TBOOL, ir.TINT8, ir.TUINT8, ir.TINT16, ir.TUINT16,
+		ir.TINT32, ir.TUINT32, ir.TINT64, ir.TUINT64,
+		ir.TINT, ir.TUINT, ir.TUINTPTR,
+		ir.TPTR32, ir.TPTR64,
+		ir.TFLOAT32, ir.TFLOAT64:
 		if !reportOnly {
 			gdata(&nam, nr, int(nr.Type.Width))
 		}
 
-	case TCOMPLEX64, TCOMPLEX128:
+	case ir.TCOMPLEX64, ir.TCOMPLEX128:
 		if !reportOnly {
-			gdatacomplex(&nam, nr.Val().U.(*Mpcplx))
+			gdatacomplex(&nam, nr.Val().U.(*ir.Mpcplx))
 		}
 
-	case TSTRING:
+	case ir.TSTRING:
 		if !reportOnly {
 			gdatastring(&nam, nr.Val().U.(string))
 		}
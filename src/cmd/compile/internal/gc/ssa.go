@@ -6,6 +6,7 @@ package gc
 
 import (
 	"bytes"
+	"cmd/compile/internal/ir"
 	"fmt"
 	"html"
 	"os"
@@ -24,12 +25,41 @@ func initssa() *ssa.Config {
 	ssaExp.unimplemented = false
 	ssaExp.mustImplement = true
 	if ssaConfig == nil {
-		ssaConfig = ssa.NewConfig(Thearch.Thestring, &ssaExp, Ctxt, Debug['N'] == 0)
+		ssaConfig = newssaconfig(&ssaExp)
 	}
 	return ssaConfig
 }
 
-func shouldssa(fn *Node) bool {
+// newssaconfig returns a new SSA configuration with frontend fe.
+func newssaconfig(fe ssa.Frontend) *ssa.Config {
+	c := ssa.NewConfig(Thearch.Thestring, fe, Ctxt, Debug['N'] == 0)
+	w := int64(Widthreg)
+	if r := Thearch.Duffzero; r != (DuffRange{}) {
+		c.DuffZeroMin, c.DuffZeroMax = r.Min*w, r.Max*w
+	}
+	if r := Thearch.Duffcopy; r != (DuffRange{}) {
+		c.DuffCopyMin, c.DuffCopyMax = r.Min*w, r.Max*w
+	}
+	return c
+}
+
+// ssaConfigs holds the SSA configurations, each with its own frontend,
+// used to build the functions of a batch that the back end optimizes
+// concurrently (see -c).
+var ssaConfigs []*ssa.Config
+
+// batchssa returns the SSA configuration for building fn as the i'th
+// function of a batch.
+func batchssa(i int, fn *ir.Node) *ssa.Config {
+	for len(ssaConfigs) <= i {
+		ssaConfigs = append(ssaConfigs, newssaconfig(new(ssaExport)))
+	}
+	c := ssaConfigs[i]
+	*c.Frontend().(*ssaExport) = ssaExport{mustImplement: true, curfn: fn}
+	return c
+}
+
+func shouldssa(fn *ir.Node) bool {
 	switch Thearch.Thestring {
 	default:
 		// Only available for testing.
@@ -84,8 +114,8 @@ func shouldssa(fn *Node) bool {
 	return initssa().DebugHashMatch("GOSSAHASH", name)
 }
 
-// buildssa builds an SSA function.
-func buildssa(fn *Node) *ssa.Func {
+// buildssa builds an SSA function using config.
+func buildssa(fn *ir.Node, config *ssa.Config) *ssa.Func {
 	name := fn.Func.Nname.Sym.Name
 	printssa := name == os.Getenv("GOSSAFUNC")
 	if printssa {
@@ -99,10 +129,10 @@ func buildssa(fn *Node) *ssa.Func {
 	s.pushLine(fn.Lineno)
 	defer s.popLine()
 
-	if fn.Func.Pragma&CgoUnsafeArgs != 0 {
+	if fn.Func.Pragma&ir.CgoUnsafeArgs != 0 {
 		s.cgoUnsafeArgs = true
 	}
-	if fn.Func.Pragma&Nowritebarrier != 0 {
+	if fn.Func.Pragma&ir.Nowritebarrier != 0 {
 		s.noWB = true
 	}
 	defer func() {
@@ -112,13 +142,13 @@ func buildssa(fn *Node) *ssa.Func {
 	}()
 	// TODO(khr): build config just once at the start of the compiler binary
 
-	ssaExp.log = printssa
-
-	s.config = initssa()
+	s.config = config
+	s.config.Frontend().(*ssaExport).log = printssa
 	s.f = s.config.NewFunc()
 	s.f.Name = name
 	s.exitCode = fn.Func.Exit
 	s.panics = map[funcLine]*ssa.Block{}
+	s.wbreasons = fn.Func.WBReasons
 
 	if name == os.Getenv("GOSSAFUNC") {
 		// TODO: tempfile? it is handy to have the location
@@ -137,46 +167,46 @@ func buildssa(fn *Node) *ssa.Func {
 
 	// Allocate starting values
 	s.labels = map[string]*ssaLabel{}
-	s.labeledNodes = map[*Node]*ssaLabel{}
+	s.labeledNodes = map[*ir.Node]*ssaLabel{}
 	s.startmem = s.entryNewValue0(ssa.OpInitMem, ssa.TypeMem)
-	s.sp = s.entryNewValue0(ssa.OpSP, Types[TUINTPTR]) // TODO: use generic pointer type (unsafe.Pointer?) instead
-	s.sb = s.entryNewValue0(ssa.OpSB, Types[TUINTPTR])
+	s.sp = s.entryNewValue0(ssa.OpSP, ir.Types[ir.TUINTPTR]) // TODO: use generic pointer type (unsafe.Pointer?) instead
+	s.sb = s.entryNewValue0(ssa.OpSB, ir.Types[ir.TUINTPTR])
 
 	s.startBlock(s.f.Entry)
 	s.vars[&memVar] = s.startmem
 
-	s.varsyms = map[*Node]interface{}{}
+	s.varsyms = map[*ir.Node]interface{}{}
 
 	// Generate addresses of local declarations
-	s.decladdrs = map[*Node]*ssa.Value{}
+	s.decladdrs = map[*ir.Node]*ssa.Value{}
 	for _, n := range fn.Func.Dcl {
 		switch n.Class {
-		case PPARAM, PPARAMOUT:
+		case ir.PPARAM, ir.PPARAMOUT:
 			aux := s.lookupSymbol(n, &ssa.ArgSymbol{Typ: n.Type, Node: n})
 			s.decladdrs[n] = s.entryNewValue1A(ssa.OpAddr, Ptrto(n.Type), aux, s.sp)
-			if n.Class == PPARAMOUT && s.canSSA(n) {
+			if n.Class == ir.PPARAMOUT && s.canSSA(n) {
 				// Save ssa-able PPARAMOUT variables so we can
 				// store them back to the stack at the end of
 				// the function.
 				s.returns = append(s.returns, n)
 			}
-		case PAUTO | PHEAP:
+		case ir.PAUTO | ir.PHEAP:
 			// TODO this looks wrong for PAUTO|PHEAP, no vardef, but also no definition
 			aux := s.lookupSymbol(n, &ssa.AutoSymbol{Typ: n.Type, Node: n})
 			s.decladdrs[n] = s.entryNewValue1A(ssa.OpAddr, Ptrto(n.Type), aux, s.sp)
-		case PPARAM | PHEAP, PPARAMOUT | PHEAP:
+		case ir.PPARAM | ir.PHEAP, ir.PPARAMOUT | ir.PHEAP:
 		// This ends up wrong, have to do it at the PARAM node instead.
-		case PAUTO:
+		case ir.PAUTO:
 			// processed at each use, to prevent Addr coming
 			// before the decl.
-		case PFUNC:
+		case ir.PFUNC:
 			// local function - already handled by frontend
 		default:
 			str := ""
-			if n.Class&PHEAP != 0 {
+			if n.Class&ir.PHEAP != 0 {
 				str = ",heap"
 			}
-			s.Unimplementedf("local variable with class %s%s unimplemented", classnames[n.Class&^PHEAP], str)
+			s.Unimplementedf("local variable with class %s%s unimplemented", classnames[n.Class&^ir.PHEAP], str)
 		}
 	}
 
@@ -220,11 +250,14 @@ func buildssa(fn *Node) *ssa.Func {
 	// Link up variable uses to variable definitions
 	s.linkForwardReferences()
 
-	// Don't carry reference this around longer than necessary
-	s.exitCode = Nodes{}
+	// Check the stores against the write barrier decisions, now that
+	// the values they store are known.
+	for _, st := range s.wbstores {
+		checkwbssa(st, s.wbreasons[st.n])
+	}
 
-	// Main call to ssa package to compile function
-	ssa.Compile(s.f)
+	// Don't carry reference this around longer than necessary
+	s.exitCode = ir.Nodes{}
 
 	return s.f
 }
@@ -238,13 +271,13 @@ type state struct {
 
 	// labels and labeled control flow nodes (OFOR, OSWITCH, OSELECT) in f
 	labels       map[string]*ssaLabel
-	labeledNodes map[*Node]*ssaLabel
+	labeledNodes map[*ir.Node]*ssaLabel
 
 	// gotos that jump forward; required for deferred checkgoto calls
-	fwdGotos []*Node
+	fwdGotos []*ir.Node
 	// Code that must precede any return
 	// (e.g., copying value of heap-escaped paramout back to true paramout)
-	exitCode Nodes
+	exitCode ir.Nodes
 
 	// unlabeled break and continue statement tracking
 	breakTo    *ssa.Block // current target for plain break statement
@@ -255,16 +288,16 @@ type state struct {
 
 	// variable assignments in the current block (map from variable symbol to ssa value)
 	// *Node is the unique identifier (an ONAME Node) for the variable.
-	vars map[*Node]*ssa.Value
+	vars map[*ir.Node]*ssa.Value
 
 	// all defined variables at the end of each block. Indexed by block ID.
-	defvars []map[*Node]*ssa.Value
+	defvars []map[*ir.Node]*ssa.Value
 
 	// addresses of PPARAM and PPARAMOUT variables.
-	decladdrs map[*Node]*ssa.Value
+	decladdrs map[*ir.Node]*ssa.Value
 
 	// symbols for PEXTERN, PAUTO and PPARAMOUT variables so they can be reused.
-	varsyms map[*Node]interface{}
+	varsyms map[*ir.Node]interface{}
 
 	// starting values. Memory, stack pointer, and globals pointer
 	startmem *ssa.Value
@@ -282,15 +315,22 @@ type state struct {
 	fwdRefs []*ssa.Value
 
 	// list of PPARAMOUT (return) variables. Does not include PPARAM|PHEAP vars.
-	returns []*Node
+	returns []*ir.Node
 
 	cgoUnsafeArgs bool
 	noWB          bool
 	WBLineno      int32 // line number of first write barrier. 0=no write barriers
+
+	// With -d=wb=2, the reasons recorded by the writebarrier pass,
+	// the assignment being converted, and the stores generated for
+	// the assignments so far; see checkwbssa.
+	wbreasons map[*ir.Node]string
+	wbnode    *ir.Node
+	wbstores  []wbstore
 }
 
 type funcLine struct {
-	f    *Node
+	f    *ir.Node
 	line int32
 }
 
@@ -298,11 +338,11 @@ type ssaLabel struct {
 	target         *ssa.Block // block identified by this label
 	breakTarget    *ssa.Block // block to break to in control flow node identified by this label
 	continueTarget *ssa.Block // block to continue to in control flow node identified by this label
-	defNode        *Node      // label definition Node (OLABEL)
+	defNode        *ir.Node   // label definition Node (OLABEL)
 	// Label use Node (OGOTO, OBREAK, OCONTINUE).
 	// Used only for error detection and reporting.
 	// There might be multiple uses, but we only need to track one.
-	useNode  *Node
+	useNode  *ir.Node
 	reported bool // reported indicates whether an error has already been reported for this label
 }
 
@@ -313,7 +353,7 @@ func (l *ssaLabel) defined() bool { return l.defNode != nil }
 func (l *ssaLabel) used() bool { return l.useNode != nil }
 
 // label returns the label associated with sym, creating it if necessary.
-func (s *state) label(sym *Sym) *ssaLabel {
+func (s *state) label(sym *ir.Sym) *ssaLabel {
 	lab := s.labels[sym.Name]
 	if lab == nil {
 		lab = new(ssaLabel)
@@ -333,15 +373,15 @@ func (s *state) Debug_checknil() bool                              { return s.co
 
 var (
 	// dummy node for the memory variable
-	memVar = Node{Op: ONAME, Class: Pxxx, Sym: &Sym{Name: "mem"}}
+	memVar = ir.Node{Op: ir.ONAME, Class: ir.Pxxx, Sym: &ir.Sym{Name: "mem"}}
 
 	// dummy nodes for temporary variables
-	ptrVar   = Node{Op: ONAME, Class: Pxxx, Sym: &Sym{Name: "ptr"}}
-	capVar   = Node{Op: ONAME, Class: Pxxx, Sym: &Sym{Name: "cap"}}
-	typVar   = Node{Op: ONAME, Class: Pxxx, Sym: &Sym{Name: "typ"}}
-	idataVar = Node{Op: ONAME, Class: Pxxx, Sym: &Sym{Name: "idata"}}
-	okVar    = Node{Op: ONAME, Class: Pxxx, Sym: &Sym{Name: "ok"}}
-	deltaVar = Node{Op: ONAME, Class: Pxxx, Sym: &Sym{Name: "delta"}}
+	ptrVar   = ir.Node{Op: ir.ONAME, Class: ir.Pxxx, Sym: &ir.Sym{Name: "ptr"}}
+	capVar   = ir.Node{Op: ir.ONAME, Class: ir.Pxxx, Sym: &ir.Sym{Name: "cap"}}
+	typVar   = ir.Node{Op: ir.ONAME, Class: ir.Pxxx, Sym: &ir.Sym{Name: "typ"}}
+	idataVar = ir.Node{Op: ir.ONAME, Class: ir.Pxxx, Sym: &ir.Sym{Name: "idata"}}
+	okVar    = ir.Node{Op: ir.ONAME, Class: ir.Pxxx, Sym: &ir.Sym{Name: "ok"}}
+	deltaVar = ir.Node{Op: ir.ONAME, Class: ir.Pxxx, Sym: &ir.Sym{Name: "delta"}}
 )
 
 // startBlock sets the current block we're generating code in to b.
@@ -350,7 +390,7 @@ func (s *state) startBlock(b *ssa.Block) {
 		s.Fatalf("starting block %v when block %v has not ended", b, s.curBlock)
 	}
 	s.curBlock = b
-	s.vars = map[*Node]*ssa.Value{}
+	s.vars = map[*ir.Node]*ssa.Value{}
 }
 
 // endBlock marks the end of generating code for the current block.
@@ -481,7 +521,7 @@ func (s *state) constInterface(t ssa.Type) *ssa.Value   { return s.f.ConstInterf
 func (s *state) constNil(t ssa.Type) *ssa.Value         { return s.f.ConstNil(s.peekLine(), t) }
 func (s *state) constEmptyString(t ssa.Type) *ssa.Value { return s.f.ConstEmptyString(s.peekLine(), t) }
 func (s *state) constBool(c bool) *ssa.Value {
-	return s.f.ConstBool(s.peekLine(), Types[TBOOL], c)
+	return s.f.ConstBool(s.peekLine(), ir.Types[ir.TBOOL], c)
 }
 func (s *state) constInt8(t ssa.Type, c int8) *ssa.Value {
 	return s.f.ConstInt8(s.peekLine(), t, c)
@@ -511,21 +551,21 @@ func (s *state) constInt(t ssa.Type, c int64) *ssa.Value {
 	return s.constInt32(t, int32(c))
 }
 
-func (s *state) stmts(a Nodes) {
+func (s *state) stmts(a ir.Nodes) {
 	for _, x := range a.Slice() {
 		s.stmt(x)
 	}
 }
 
 // ssaStmtList converts the statement n to SSA and adds it to s.
-func (s *state) stmtList(l Nodes) {
+func (s *state) stmtList(l ir.Nodes) {
 	for _, n := range l.Slice() {
 		s.stmt(n)
 	}
 }
 
 // ssaStmt converts the statement n to SSA and adds it to s.
-func (s *state) stmt(n *Node) {
+func (s *state) stmt(n *ir.Node) {
 	s.pushLine(n.Lineno)
 	defer s.popLine()
 
@@ -542,16 +582,16 @@ func (s *state) stmt(n *Node) {
 	s.stmtList(n.Ninit)
 	switch n.Op {
 
-	case OBLOCK:
+	case ir.OBLOCK:
 		s.stmtList(n.List)
 
 	// No-ops
-	case OEMPTY, ODCLCONST, ODCLTYPE, OFALL:
+	case ir.OEMPTY, ir.ODCLCONST, ir.ODCLTYPE, ir.OFALL:
 
 	// Expression statements
-	case OCALLFUNC, OCALLMETH, OCALLINTER:
+	case ir.OCALLFUNC, ir.OCALLMETH, ir.OCALLINTER:
 		s.call(n, callNormal)
-		if n.Op == OCALLFUNC && n.Left.Op == ONAME && n.Left.Class == PFUNC &&
+		if n.Op == ir.OCALLFUNC && n.Left.Op == ir.ONAME && n.Left.Class == ir.PFUNC &&
 			(compiling_runtime != 0 && n.Left.Sym.Name == "throw" ||
 				n.Left.Sym.Pkg == Runtimepkg && (n.Left.Sym.Name == "gopanic" || n.Left.Sym.Name == "selectgo" || n.Left.Sym.Name == "block")) {
 			m := s.mem()
@@ -562,19 +602,19 @@ func (s *state) stmt(n *Node) {
 			// first place. Need to wait until all backends
 			// go through SSA.
 		}
-	case ODEFER:
+	case ir.ODEFER:
 		s.call(n.Left, callDefer)
-	case OPROC:
+	case ir.OPROC:
 		s.call(n.Left, callGo)
 
-	case OAS2DOTTYPE:
+	case ir.OAS2DOTTYPE:
 		res, resok := s.dottype(n.Rlist.First(), true)
 		s.assign(n.List.First(), res, needwritebarrier(n.List.First(), n.Rlist.First()), false, n.Lineno, 0)
 		s.assign(n.List.Second(), resok, false, false, n.Lineno, 0)
 		return
 
-	case ODCL:
-		if n.Left.Class&PHEAP == 0 {
+	case ir.ODCL:
+		if n.Left.Class&ir.PHEAP == 0 {
 			return
 		}
 		if compiling_runtime != 0 {
@@ -593,7 +633,7 @@ func (s *state) stmt(n *Node) {
 		r := s.expr(palloc)
 		s.assign(n.Left.Name.Heapaddr, r, false, false, n.Lineno, 0)
 
-	case OLABEL:
+	case ir.OLABEL:
 		sym := n.Left.Sym
 
 		if isblanksym(sym) {
@@ -607,7 +647,7 @@ func (s *state) stmt(n *Node) {
 		// Associate label with its control flow node, if any
 		if ctl := n.Name.Defn; ctl != nil {
 			switch ctl.Op {
-			case OFOR, OSWITCH, OSELECT:
+			case ir.OFOR, ir.OSWITCH, ir.OSELECT:
 				s.labeledNodes[ctl] = lab
 			}
 		}
@@ -628,7 +668,7 @@ func (s *state) stmt(n *Node) {
 		b.AddEdgeTo(lab.target)
 		s.startBlock(lab.target)
 
-	case OGOTO:
+	case ir.OGOTO:
 		sym := n.Left.Sym
 
 		lab := s.label(sym)
@@ -648,20 +688,20 @@ func (s *state) stmt(n *Node) {
 		b := s.endBlock()
 		b.AddEdgeTo(lab.target)
 
-	case OAS, OASWB:
+	case ir.OAS, ir.OASWB:
 		// Check whether we can generate static data rather than code.
 		// If so, ignore n and defer data generation until codegen.
 		// Failure to do this causes writes to readonly symbols.
 		if gen_as_init(n, true) {
-			var data []*Node
+			var data []*ir.Node
 			if s.f.StaticData != nil {
-				data = s.f.StaticData.([]*Node)
+				data = s.f.StaticData.([]*ir.Node)
 			}
 			s.f.StaticData = append(data, n)
 			return
 		}
 
-		var t *Type
+		var t *ir.Type
 		if n.Right != nil {
 			t = n.Right.Type
 		} else {
@@ -670,7 +710,7 @@ func (s *state) stmt(n *Node) {
 
 		// Evaluate RHS.
 		rhs := n.Right
-		if rhs != nil && (rhs.Op == OSTRUCTLIT || rhs.Op == OARRAYLIT) {
+		if rhs != nil && (rhs.Op == ir.OSTRUCTLIT || rhs.Op == ir.OARRAYLIT) {
 			// All literals with nonzero fields have already been
 			// rewritten during walk. Any that remain are just T{}
 			// or equivalents. Use the zero value.
@@ -680,7 +720,7 @@ func (s *state) stmt(n *Node) {
 			rhs = nil
 		}
 		var r *ssa.Value
-		needwb := n.Op == OASWB && rhs != nil
+		needwb := n.Op == ir.OASWB && rhs != nil
 		deref := !canSSAType(t)
 		if deref {
 			if rhs == nil {
@@ -695,7 +735,7 @@ func (s *state) stmt(n *Node) {
 				r = s.expr(rhs)
 			}
 		}
-		if rhs != nil && rhs.Op == OAPPEND {
+		if rhs != nil && rhs.Op == ir.OAPPEND {
 			// Yuck!  The frontend gets rid of the write barrier, but we need it!
 			// At least, we need it in the case where growslice is called.
 			// TODO: Do the write barrier on just the growslice branch.
@@ -706,18 +746,18 @@ func (s *state) stmt(n *Node) {
 		}
 
 		var skip skipMask
-		if rhs != nil && (rhs.Op == OSLICE || rhs.Op == OSLICE3 || rhs.Op == OSLICESTR) && samesafeexpr(rhs.Left, n.Left) {
+		if rhs != nil && (rhs.Op == ir.OSLICE || rhs.Op == ir.OSLICE3 || rhs.Op == ir.OSLICESTR) && samesafeexpr(rhs.Left, n.Left) {
 			// We're assigning a slicing operation back to its source.
 			// Don't write back fields we aren't changing. See issue #14855.
 			i := rhs.Right.Left
-			var j, k *Node
-			if rhs.Op == OSLICE3 {
+			var j, k *ir.Node
+			if rhs.Op == ir.OSLICE3 {
 				j = rhs.Right.Right.Left
 				k = rhs.Right.Right.Right
 			} else {
 				j = rhs.Right.Right
 			}
-			if i != nil && (i.Op == OLITERAL && i.Val().Ctype() == CTINT && i.Val().U.(*Mpint).Int64() == 0) {
+			if i != nil && (i.Op == ir.OLITERAL && i.Val().Ctype() == ir.CTINT && i.Val().U.(*ir.Mpint).Int64() == 0) {
 				// [0:...] is the same as [:...]
 				i = nil
 			}
@@ -742,9 +782,13 @@ func (s *state) stmt(n *Node) {
 			}
 		}
 
+		if _, ok := s.wbreasons[n]; ok {
+			s.wbnode = n
+		}
 		s.assign(n.Left, r, needwb, deref, n.Lineno, skip)
+		s.wbnode = nil
 
-	case OIF:
+	case ir.OIF:
 		bThen := s.f.NewBlock(ssa.BlockPlain)
 		bEnd := s.f.NewBlock(ssa.BlockPlain)
 		var bElse *ssa.Block
@@ -770,23 +814,23 @@ func (s *state) stmt(n *Node) {
 		}
 		s.startBlock(bEnd)
 
-	case ORETURN:
+	case ir.ORETURN:
 		s.stmtList(n.List)
 		s.exit()
-	case ORETJMP:
+	case ir.ORETJMP:
 		s.stmtList(n.List)
 		b := s.exit()
 		b.Kind = ssa.BlockRetJmp // override BlockRet
 		b.Aux = n.Left.Sym
 
-	case OCONTINUE, OBREAK:
+	case ir.OCONTINUE, ir.OBREAK:
 		var op string
 		var to *ssa.Block
 		switch n.Op {
-		case OCONTINUE:
+		case ir.OCONTINUE:
 			op = "continue"
 			to = s.continueTo
-		case OBREAK:
+		case ir.OBREAK:
 			op = "break"
 			to = s.breakTo
 		}
@@ -810,9 +854,9 @@ func (s *state) stmt(n *Node) {
 				return
 			}
 			switch n.Op {
-			case OCONTINUE:
+			case ir.OCONTINUE:
 				to = lab.continueTarget
-			case OBREAK:
+			case ir.OBREAK:
 				to = lab.breakTarget
 			}
 			if to == nil {
@@ -831,7 +875,7 @@ func (s *state) stmt(n *Node) {
 		b := s.endBlock()
 		b.AddEdgeTo(to)
 
-	case OFOR:
+	case ir.OFOR:
 		// OFOR: for Ninit; Left; Right { Nbody }
 		bCond := s.f.NewBlock(ssa.BlockPlain)
 		bBody := s.f.NewBlock(ssa.BlockPlain)
@@ -891,7 +935,7 @@ func (s *state) stmt(n *Node) {
 		}
 		s.startBlock(bEnd)
 
-	case OSWITCH, OSELECT:
+	case ir.OSWITCH, ir.OSELECT:
 		// These have been mostly rewritten by the front end into their Nbody fields.
 		// Our main task is to correctly hook up any break statements.
 		bEnd := s.f.NewBlock(ssa.BlockPlain)
@@ -921,7 +965,7 @@ func (s *state) stmt(n *Node) {
 		}
 		s.startBlock(bEnd)
 
-	case OVARKILL:
+	case ir.OVARKILL:
 		// Insert a varkill op to record that a variable is no longer live.
 		// We only care about liveness info at call sites, so putting the
 		// varkill in the store chain is enough to keep it correctly ordered
@@ -930,14 +974,14 @@ func (s *state) stmt(n *Node) {
 			s.vars[&memVar] = s.newValue1A(ssa.OpVarKill, ssa.TypeMem, n.Left, s.mem())
 		}
 
-	case OVARLIVE:
+	case ir.OVARLIVE:
 		// Insert a varlive op to record that a variable is still live.
 		if !n.Left.Addrtaken {
 			s.Fatalf("VARLIVE variable %s must have Addrtaken set", n.Left)
 		}
 		s.vars[&memVar] = s.newValue1A(ssa.OpVarLive, ssa.TypeMem, n.Left, s.mem())
 
-	case OCHECKNIL:
+	case ir.OCHECKNIL:
 		p := s.expr(n.Left)
 		s.nilCheck(p)
 
@@ -979,434 +1023,434 @@ func (s *state) exit() *ssa.Block {
 }
 
 type opAndType struct {
-	op    Op
-	etype EType
+	op    ir.Op
+	etype ir.EType
 }
 
 var opToSSA = map[opAndType]ssa.Op{
-	opAndType{OADD, TINT8}:    ssa.OpAdd8,
-	opAndType{OADD, TUINT8}:   ssa.OpAdd8,
-	opAndType{OADD, TINT16}:   ssa.OpAdd16,
-	opAndType{OADD, TUINT16}:  ssa.OpAdd16,
-	opAndType{OADD, TINT32}:   ssa.OpAdd32,
-	opAndType{OADD, TUINT32}:  ssa.OpAdd32,
-	opAndType{OADD, TPTR32}:   ssa.OpAdd32,
-	opAndType{OADD, TINT64}:   ssa.OpAdd64,
-	opAndType{OADD, TUINT64}:  ssa.OpAdd64,
-	opAndType{OADD, TPTR64}:   ssa.OpAdd64,
-	opAndType{OADD, TFLOAT32}: ssa.OpAdd32F,
-	opAndType{OADD, TFLOAT64}: ssa.OpAdd64F,
-
-	opAndType{OSUB, TINT8}:    ssa.OpSub8,
-	opAndType{OSUB, TUINT8}:   ssa.OpSub8,
-	opAndType{OSUB, TINT16}:   ssa.OpSub16,
-	opAndType{OSUB, TUINT16}:  ssa.OpSub16,
-	opAndType{OSUB, TINT32}:   ssa.OpSub32,
-	opAndType{OSUB, TUINT32}:  ssa.OpSub32,
-	opAndType{OSUB, TINT64}:   ssa.OpSub64,
-	opAndType{OSUB, TUINT64}:  ssa.OpSub64,
-	opAndType{OSUB, TFLOAT32}: ssa.OpSub32F,
-	opAndType{OSUB, TFLOAT64}: ssa.OpSub64F,
-
-	opAndType{ONOT, TBOOL}: ssa.OpNot,
-
-	opAndType{OMINUS, TINT8}:    ssa.OpNeg8,
-	opAndType{OMINUS, TUINT8}:   ssa.OpNeg8,
-	opAndType{OMINUS, TINT16}:   ssa.OpNeg16,
-	opAndType{OMINUS, TUINT16}:  ssa.OpNeg16,
-	opAndType{OMINUS, TINT32}:   ssa.OpNeg32,
-	opAndType{OMINUS, TUINT32}:  ssa.OpNeg32,
-	opAndType{OMINUS, TINT64}:   ssa.OpNeg64,
-	opAndType{OMINUS, TUINT64}:  ssa.OpNeg64,
-	opAndType{OMINUS, TFLOAT32}: ssa.OpNeg32F,
-	opAndType{OMINUS, TFLOAT64}: ssa.OpNeg64F,
-
-	opAndType{OCOM, TINT8}:   ssa.OpCom8,
-	opAndType{OCOM, TUINT8}:  ssa.OpCom8,
-	opAndType{OCOM, TINT16}:  ssa.OpCom16,
-	opAndType{OCOM, TUINT16}: ssa.OpCom16,
-	opAndType{OCOM, TINT32}:  ssa.OpCom32,
-	opAndType{OCOM, TUINT32}: ssa.OpCom32,
-	opAndType{OCOM, TINT64}:  ssa.OpCom64,
-	opAndType{OCOM, TUINT64}: ssa.OpCom64,
-
-	opAndType{OIMAG, TCOMPLEX64}:  ssa.OpComplexImag,
-	opAndType{OIMAG, TCOMPLEX128}: ssa.OpComplexImag,
-	opAndType{OREAL, TCOMPLEX64}:  ssa.OpComplexReal,
-	opAndType{OREAL, TCOMPLEX128}: ssa.OpComplexReal,
-
-	opAndType{OMUL, TINT8}:    ssa.OpMul8,
-	opAndType{OMUL, TUINT8}:   ssa.OpMul8,
-	opAndType{OMUL, TINT16}:   ssa.OpMul16,
-	opAndType{OMUL, TUINT16}:  ssa.OpMul16,
-	opAndType{OMUL, TINT32}:   ssa.OpMul32,
-	opAndType{OMUL, TUINT32}:  ssa.OpMul32,
-	opAndType{OMUL, TINT64}:   ssa.OpMul64,
-	opAndType{OMUL, TUINT64}:  ssa.OpMul64,
-	opAndType{OMUL, TFLOAT32}: ssa.OpMul32F,
-	opAndType{OMUL, TFLOAT64}: ssa.OpMul64F,
-
-	opAndType{ODIV, TFLOAT32}: ssa.OpDiv32F,
-	opAndType{ODIV, TFLOAT64}: ssa.OpDiv64F,
-
-	opAndType{OHMUL, TINT8}:   ssa.OpHmul8,
-	opAndType{OHMUL, TUINT8}:  ssa.OpHmul8u,
-	opAndType{OHMUL, TINT16}:  ssa.OpHmul16,
-	opAndType{OHMUL, TUINT16}: ssa.OpHmul16u,
-	opAndType{OHMUL, TINT32}:  ssa.OpHmul32,
-	opAndType{OHMUL, TUINT32}: ssa.OpHmul32u,
-
-	opAndType{ODIV, TINT8}:   ssa.OpDiv8,
-	opAndType{ODIV, TUINT8}:  ssa.OpDiv8u,
-	opAndType{ODIV, TINT16}:  ssa.OpDiv16,
-	opAndType{ODIV, TUINT16}: ssa.OpDiv16u,
-	opAndType{ODIV, TINT32}:  ssa.OpDiv32,
-	opAndType{ODIV, TUINT32}: ssa.OpDiv32u,
-	opAndType{ODIV, TINT64}:  ssa.OpDiv64,
-	opAndType{ODIV, TUINT64}: ssa.OpDiv64u,
-
-	opAndType{OMOD, TINT8}:   ssa.OpMod8,
-	opAndType{OMOD, TUINT8}:  ssa.OpMod8u,
-	opAndType{OMOD, TINT16}:  ssa.OpMod16,
-	opAndType{OMOD, TUINT16}: ssa.OpMod16u,
-	opAndType{OMOD, TINT32}:  ssa.OpMod32,
-	opAndType{OMOD, TUINT32}: ssa.OpMod32u,
-	opAndType{OMOD, TINT64}:  ssa.OpMod64,
-	opAndType{OMOD, TUINT64}: ssa.OpMod64u,
-
-	opAndType{OAND, TINT8}:   ssa.OpAnd8,
-	opAndType{OAND, TUINT8}:  ssa.OpAnd8,
-	opAndType{OAND, TINT16}:  ssa.OpAnd16,
-	opAndType{OAND, TUINT16}: ssa.OpAnd16,
-	opAndType{OAND, TINT32}:  ssa.OpAnd32,
-	opAndType{OAND, TUINT32}: ssa.OpAnd32,
-	opAndType{OAND, TINT64}:  ssa.OpAnd64,
-	opAndType{OAND, TUINT64}: ssa.OpAnd64,
-
-	opAndType{OOR, TINT8}:   ssa.OpOr8,
-	opAndType{OOR, TUINT8}:  ssa.OpOr8,
-	opAndType{OOR, TINT16}:  ssa.OpOr16,
-	opAndType{OOR, TUINT16}: ssa.OpOr16,
-	opAndType{OOR, TINT32}:  ssa.OpOr32,
-	opAndType{OOR, TUINT32}: ssa.OpOr32,
-	opAndType{OOR, TINT64}:  ssa.OpOr64,
-	opAndType{OOR, TUINT64}: ssa.OpOr64,
-
-	opAndType{OXOR, TINT8}:   ssa.OpXor8,
-	opAndType{OXOR, TUINT8}:  ssa.OpXor8,
-	opAndType{OXOR, TINT16}:  ssa.OpXor16,
-	opAndType{OXOR, TUINT16}: ssa.OpXor16,
-	opAndType{OXOR, TINT32}:  ssa.OpXor32,
-	opAndType{OXOR, TUINT32}: ssa.OpXor32,
-	opAndType{OXOR, TINT64}:  ssa.OpXor64,
-	opAndType{OXOR, TUINT64}: ssa.OpXor64,
-
-	opAndType{OEQ, TBOOL}:      ssa.OpEq8,
-	opAndType{OEQ, TINT8}:      ssa.OpEq8,
-	opAndType{OEQ, TUINT8}:     ssa.OpEq8,
-	opAndType{OEQ, TINT16}:     ssa.OpEq16,
-	opAndType{OEQ, TUINT16}:    ssa.OpEq16,
-	opAndType{OEQ, TINT32}:     ssa.OpEq32,
-	opAndType{OEQ, TUINT32}:    ssa.OpEq32,
-	opAndType{OEQ, TINT64}:     ssa.OpEq64,
-	opAndType{OEQ, TUINT64}:    ssa.OpEq64,
-	opAndType{OEQ, TINTER}:     ssa.OpEqInter,
-	opAndType{OEQ, TARRAY}:     ssa.OpEqSlice,
-	opAndType{OEQ, TFUNC}:      ssa.OpEqPtr,
-	opAndType{OEQ, TMAP}:       ssa.OpEqPtr,
-	opAndType{OEQ, TCHAN}:      ssa.OpEqPtr,
-	opAndType{OEQ, TPTR64}:     ssa.OpEqPtr,
-	opAndType{OEQ, TUINTPTR}:   ssa.OpEqPtr,
-	opAndType{OEQ, TUNSAFEPTR}: ssa.OpEqPtr,
-	opAndType{OEQ, TFLOAT64}:   ssa.OpEq64F,
-	opAndType{OEQ, TFLOAT32}:   ssa.OpEq32F,
-
-	opAndType{ONE, TBOOL}:      ssa.OpNeq8,
-	opAndType{ONE, TINT8}:      ssa.OpNeq8,
-	opAndType{ONE, TUINT8}:     ssa.OpNeq8,
-	opAndType{ONE, TINT16}:     ssa.OpNeq16,
-	opAndType{ONE, TUINT16}:    ssa.OpNeq16,
-	opAndType{ONE, TINT32}:     ssa.OpNeq32,
-	opAndType{ONE, TUINT32}:    ssa.OpNeq32,
-	opAndType{ONE, TINT64}:     ssa.OpNeq64,
-	opAndType{ONE, TUINT64}:    ssa.OpNeq64,
-	opAndType{ONE, TINTER}:     ssa.OpNeqInter,
-	opAndType{ONE, TARRAY}:     ssa.OpNeqSlice,
-	opAndType{ONE, TFUNC}:      ssa.OpNeqPtr,
-	opAndType{ONE, TMAP}:       ssa.OpNeqPtr,
-	opAndType{ONE, TCHAN}:      ssa.OpNeqPtr,
-	opAndType{ONE, TPTR64}:     ssa.OpNeqPtr,
-	opAndType{ONE, TUINTPTR}:   ssa.OpNeqPtr,
-	opAndType{ONE, TUNSAFEPTR}: ssa.OpNeqPtr,
-	opAndType{ONE, TFLOAT64}:   ssa.OpNeq64F,
-	opAndType{ONE, TFLOAT32}:   ssa.OpNeq32F,
-
-	opAndType{OLT, TINT8}:    ssa.OpLess8,
-	opAndType{OLT, TUINT8}:   ssa.OpLess8U,
-	opAndType{OLT, TINT16}:   ssa.OpLess16,
-	opAndType{OLT, TUINT16}:  ssa.OpLess16U,
-	opAndType{OLT, TINT32}:   ssa.OpLess32,
-	opAndType{OLT, TUINT32}:  ssa.OpLess32U,
-	opAndType{OLT, TINT64}:   ssa.OpLess64,
-	opAndType{OLT, TUINT64}:  ssa.OpLess64U,
-	opAndType{OLT, TFLOAT64}: ssa.OpLess64F,
-	opAndType{OLT, TFLOAT32}: ssa.OpLess32F,
-
-	opAndType{OGT, TINT8}:    ssa.OpGreater8,
-	opAndType{OGT, TUINT8}:   ssa.OpGreater8U,
-	opAndType{OGT, TINT16}:   ssa.OpGreater16,
-	opAndType{OGT, TUINT16}:  ssa.OpGreater16U,
-	opAndType{OGT, TINT32}:   ssa.OpGreater32,
-	opAndType{OGT, TUINT32}:  ssa.OpGreater32U,
-	opAndType{OGT, TINT64}:   ssa.OpGreater64,
-	opAndType{OGT, TUINT64}:  ssa.OpGreater64U,
-	opAndType{OGT, TFLOAT64}: ssa.OpGreater64F,
-	opAndType{OGT, TFLOAT32}: ssa.OpGreater32F,
-
-	opAndType{OLE, TINT8}:    ssa.OpLeq8,
-	opAndType{OLE, TUINT8}:   ssa.OpLeq8U,
-	opAndType{OLE, TINT16}:   ssa.OpLeq16,
-	opAndType{OLE, TUINT16}:  ssa.OpLeq16U,
-	opAndType{OLE, TINT32}:   ssa.OpLeq32,
-	opAndType{OLE, TUINT32}:  ssa.OpLeq32U,
-	opAndType{OLE, TINT64}:   ssa.OpLeq64,
-	opAndType{OLE, TUINT64}:  ssa.OpLeq64U,
-	opAndType{OLE, TFLOAT64}: ssa.OpLeq64F,
-	opAndType{OLE, TFLOAT32}: ssa.OpLeq32F,
-
-	opAndType{OGE, TINT8}:    ssa.OpGeq8,
-	opAndType{OGE, TUINT8}:   ssa.OpGeq8U,
-	opAndType{OGE, TINT16}:   ssa.OpGeq16,
-	opAndType{OGE, TUINT16}:  ssa.OpGeq16U,
-	opAndType{OGE, TINT32}:   ssa.OpGeq32,
-	opAndType{OGE, TUINT32}:  ssa.OpGeq32U,
-	opAndType{OGE, TINT64}:   ssa.OpGeq64,
-	opAndType{OGE, TUINT64}:  ssa.OpGeq64U,
-	opAndType{OGE, TFLOAT64}: ssa.OpGeq64F,
-	opAndType{OGE, TFLOAT32}: ssa.OpGeq32F,
-
-	opAndType{OLROT, TUINT8}:  ssa.OpLrot8,
-	opAndType{OLROT, TUINT16}: ssa.OpLrot16,
-	opAndType{OLROT, TUINT32}: ssa.OpLrot32,
-	opAndType{OLROT, TUINT64}: ssa.OpLrot64,
-
-	opAndType{OSQRT, TFLOAT64}: ssa.OpSqrt,
-}
-
-func (s *state) concreteEtype(t *Type) EType {
+	opAndType{ir.OADD, ir.TINT8}:    ssa.OpAdd8,
+	opAndType{ir.OADD, ir.TUINT8}:   ssa.OpAdd8,
+	opAndType{ir.OADD, ir.TINT16}:   ssa.OpAdd16,
+	opAndType{ir.OADD, ir.TUINT16}:  ssa.OpAdd16,
+	opAndType{ir.OADD, ir.TINT32}:   ssa.OpAdd32,
+	opAndType{ir.OADD, ir.TUINT32}:  ssa.OpAdd32,
+	opAndType{ir.OADD, ir.TPTR32}:   ssa.OpAdd32,
+	opAndType{ir.OADD, ir.TINT64}:   ssa.OpAdd64,
+	opAndType{ir.OADD, ir.TUINT64}:  ssa.OpAdd64,
+	opAndType{ir.OADD, ir.TPTR64}:   ssa.OpAdd64,
+	opAndType{ir.OADD, ir.TFLOAT32}: ssa.OpAdd32F,
+	opAndType{ir.OADD, ir.TFLOAT64}: ssa.OpAdd64F,
+
+	opAndType{ir.OSUB, ir.TINT8}:    ssa.OpSub8,
+	opAndType{ir.OSUB, ir.TUINT8}:   ssa.OpSub8,
+	opAndType{ir.OSUB, ir.TINT16}:   ssa.OpSub16,
+	opAndType{ir.OSUB, ir.TUINT16}:  ssa.OpSub16,
+	opAndType{ir.OSUB, ir.TINT32}:   ssa.OpSub32,
+	opAndType{ir.OSUB, ir.TUINT32}:  ssa.OpSub32,
+	opAndType{ir.OSUB, ir.TINT64}:   ssa.OpSub64,
+	opAndType{ir.OSUB, ir.TUINT64}:  ssa.OpSub64,
+	opAndType{ir.OSUB, ir.TFLOAT32}: ssa.OpSub32F,
+	opAndType{ir.OSUB, ir.TFLOAT64}: ssa.OpSub64F,
+
+	opAndType{ir.ONOT, ir.TBOOL}: ssa.OpNot,
+
+	opAndType{ir.OMINUS, ir.TINT8}:    ssa.OpNeg8,
+	opAndType{ir.OMINUS, ir.TUINT8}:   ssa.OpNeg8,
+	opAndType{ir.OMINUS, ir.TINT16}:   ssa.OpNeg16,
+	opAndType{ir.OMINUS, ir.TUINT16}:  ssa.OpNeg16,
+	opAndType{ir.OMINUS, ir.TINT32}:   ssa.OpNeg32,
+	opAndType{ir.OMINUS, ir.TUINT32}:  ssa.OpNeg32,
+	opAndType{ir.OMINUS, ir.TINT64}:   ssa.OpNeg64,
+	opAndType{ir.OMINUS, ir.TUINT64}:  ssa.OpNeg64,
+	opAndType{ir.OMINUS, ir.TFLOAT32}: ssa.OpNeg32F,
+	opAndType{ir.OMINUS, ir.TFLOAT64}: ssa.OpNeg64F,
+
+	opAndType{ir.OCOM, ir.TINT8}:   ssa.OpCom8,
+	opAndType{ir.OCOM, ir.TUINT8}:  ssa.OpCom8,
+	opAndType{ir.OCOM, ir.TINT16}:  ssa.OpCom16,
+	opAndType{ir.OCOM, ir.TUINT16}: ssa.OpCom16,
+	opAndType{ir.OCOM, ir.TINT32}:  ssa.OpCom32,
+	opAndType{ir.OCOM, ir.TUINT32}: ssa.OpCom32,
+	opAndType{ir.OCOM, ir.TINT64}:  ssa.OpCom64,
+	opAndType{ir.OCOM, ir.TUINT64}: ssa.OpCom64,
+
+	opAndType{ir.OIMAG, ir.TCOMPLEX64}:  ssa.OpComplexImag,
+	opAndType{ir.OIMAG, ir.TCOMPLEX128}: ssa.OpComplexImag,
+	opAndType{ir.OREAL, ir.TCOMPLEX64}:  ssa.OpComplexReal,
+	opAndType{ir.OREAL, ir.TCOMPLEX128}: ssa.OpComplexReal,
+
+	opAndType{ir.OMUL, ir.TINT8}:    ssa.OpMul8,
+	opAndType{ir.OMUL, ir.TUINT8}:   ssa.OpMul8,
+	opAndType{ir.OMUL, ir.TINT16}:   ssa.OpMul16,
+	opAndType{ir.OMUL, ir.TUINT16}:  ssa.OpMul16,
+	opAndType{ir.OMUL, ir.TINT32}:   ssa.OpMul32,
+	opAndType{ir.OMUL, ir.TUINT32}:  ssa.OpMul32,
+	opAndType{ir.OMUL, ir.TINT64}:   ssa.OpMul64,
+	opAndType{ir.OMUL, ir.TUINT64}:  ssa.OpMul64,
+	opAndType{ir.OMUL, ir.TFLOAT32}: ssa.OpMul32F,
+	opAndType{ir.OMUL, ir.TFLOAT64}: ssa.OpMul64F,
+
+	opAndType{ir.ODIV, ir.TFLOAT32}: ssa.OpDiv32F,
+	opAndType{ir.ODIV, ir.TFLOAT64}: ssa.OpDiv64F,
+
+	opAndType{ir.OHMUL, ir.TINT8}:   ssa.OpHmul8,
+	opAndType{ir.OHMUL, ir.TUINT8}:  ssa.OpHmul8u,
+	opAndType{ir.OHMUL, ir.TINT16}:  ssa.OpHmul16,
+	opAndType{ir.OHMUL, ir.TUINT16}: ssa.OpHmul16u,
+	opAndType{ir.OHMUL, ir.TINT32}:  ssa.OpHmul32,
+	opAndType{ir.OHMUL, ir.TUINT32}: ssa.OpHmul32u,
+
+	opAndType{ir.ODIV, ir.TINT8}:   ssa.OpDiv8,
+	opAndType{ir.ODIV, ir.TUINT8}:  ssa.OpDiv8u,
+	opAndType{ir.ODIV, ir.TINT16}:  ssa.OpDiv16,
+	opAndType{ir.ODIV, ir.TUINT16}: ssa.OpDiv16u,
+	opAndType{ir.ODIV, ir.TINT32}:  ssa.OpDiv32,
+	opAndType{ir.ODIV, ir.TUINT32}: ssa.OpDiv32u,
+	opAndType{ir.ODIV, ir.TINT64}:  ssa.OpDiv64,
+	opAndType{ir.ODIV, ir.TUINT64}: ssa.OpDiv64u,
+
+	opAndType{ir.OMOD, ir.TINT8}:   ssa.OpMod8,
+	opAndType{ir.OMOD, ir.TUINT8}:  ssa.OpMod8u,
+	opAndType{ir.OMOD, ir.TINT16}:  ssa.OpMod16,
+	opAndType{ir.OMOD, ir.TUINT16}: ssa.OpMod16u,
+	opAndType{ir.OMOD, ir.TINT32}:  ssa.OpMod32,
+	opAndType{ir.OMOD, ir.TUINT32}: ssa.OpMod32u,
+	opAndType{ir.OMOD, ir.TINT64}:  ssa.OpMod64,
+	opAndType{ir.OMOD, ir.TUINT64}: ssa.OpMod64u,
+
+	opAndType{ir.OAND, ir.TINT8}:   ssa.OpAnd8,
+	opAndType{ir.OAND, ir.TUINT8}:  ssa.OpAnd8,
+	opAndType{ir.OAND, ir.TINT16}:  ssa.OpAnd16,
+	opAndType{ir.OAND, ir.TUINT16}: ssa.OpAnd16,
+	opAndType{ir.OAND, ir.TINT32}:  ssa.OpAnd32,
+	opAndType{ir.OAND, ir.TUINT32}: ssa.OpAnd32,
+	opAndType{ir.OAND, ir.TINT64}:  ssa.OpAnd64,
+	opAndType{ir.OAND, ir.TUINT64}: ssa.OpAnd64,
+
+	opAndType{ir.OOR, ir.TINT8}:   ssa.OpOr8,
+	opAndType{ir.OOR, ir.TUINT8}:  ssa.OpOr8,
+	opAndType{ir.OOR, ir.TINT16}:  ssa.OpOr16,
+	opAndType{ir.OOR, ir.TUINT16}: ssa.OpOr16,
+	opAndType{ir.OOR, ir.TINT32}:  ssa.OpOr32,
+	opAndType{ir.OOR, ir.TUINT32}: ssa.OpOr32,
+	opAndType{ir.OOR, ir.TINT64}:  ssa.OpOr64,
+	opAndType{ir.OOR, ir.TUINT64}: ssa.OpOr64,
+
+	opAndType{ir.OXOR, ir.TINT8}:   ssa.OpXor8,
+	opAndType{ir.OXOR, ir.TUINT8}:  ssa.OpXor8,
+	opAndType{ir.OXOR, ir.TINT16}:  ssa.OpXor16,
+	opAndType{ir.OXOR, ir.TUINT16}: ssa.OpXor16,
+	opAndType{ir.OXOR, ir.TINT32}:  ssa.OpXor32,
+	opAndType{ir.OXOR, ir.TUINT32}: ssa.OpXor32,
+	opAndType{ir.OXOR, ir.TINT64}:  ssa.OpXor64,
+	opAndType{ir.OXOR, ir.TUINT64}: ssa.OpXor64,
+
+	opAndType{ir.OEQ, ir.TBOOL}:      ssa.OpEq8,
+	opAndType{ir.OEQ, ir.TINT8}:      ssa.OpEq8,
+	opAndType{ir.OEQ, ir.TUINT8}:     ssa.OpEq8,
+	opAndType{ir.OEQ, ir.TINT16}:     ssa.OpEq16,
+	opAndType{ir.OEQ, ir.TUINT16}:    ssa.OpEq16,
+	opAndType{ir.OEQ, ir.TINT32}:     ssa.OpEq32,
+	opAndType{ir.OEQ, ir.TUINT32}:    ssa.OpEq32,
+	opAndType{ir.OEQ, ir.TINT64}:     ssa.OpEq64,
+	opAndType{ir.OEQ, ir.TUINT64}:    ssa.OpEq64,
+	opAndType{ir.OEQ, ir.TINTER}:     ssa.OpEqInter,
+	opAndType{ir.OEQ, ir.TARRAY}:     ssa.OpEqSlice,
+	opAndType{ir.OEQ, ir.TFUNC}:      ssa.OpEqPtr,
+	opAndType{ir.OEQ, ir.TMAP}:       ssa.OpEqPtr,
+	opAndType{ir.OEQ, ir.TCHAN}:      ssa.OpEqPtr,
+	opAndType{ir.OEQ, ir.TPTR64}:     ssa.OpEqPtr,
+	opAndType{ir.OEQ, ir.TUINTPTR}:   ssa.OpEqPtr,
+	opAndType{ir.OEQ, ir.TUNSAFEPTR}: ssa.OpEqPtr,
+	opAndType{ir.OEQ, ir.TFLOAT64}:   ssa.OpEq64F,
+	opAndType{ir.OEQ, ir.TFLOAT32}:   ssa.OpEq32F,
+
+	opAndType{ir.ONE, ir.TBOOL}:      ssa.OpNeq8,
+	opAndType{ir.ONE, ir.TINT8}:      ssa.OpNeq8,
+	opAndType{ir.ONE, ir.TUINT8}:     ssa.OpNeq8,
+	opAndType{ir.ONE, ir.TINT16}:     ssa.OpNeq16,
+	opAndType{ir.ONE, ir.TUINT16}:    ssa.OpNeq16,
+	opAndType{ir.ONE, ir.TINT32}:     ssa.OpNeq32,
+	opAndType{ir.ONE, ir.TUINT32}:    ssa.OpNeq32,
+	opAndType{ir.ONE, ir.TINT64}:     ssa.OpNeq64,
+	opAndType{ir.ONE, ir.TUINT64}:    ssa.OpNeq64,
+	opAndType{ir.ONE, ir.TINTER}:     ssa.OpNeqInter,
+	opAndType{ir.ONE, ir.TARRAY}:     ssa.OpNeqSlice,
+	opAndType{ir.ONE, ir.TFUNC}:      ssa.OpNeqPtr,
+	opAndType{ir.ONE, ir.TMAP}:       ssa.OpNeqPtr,
+	opAndType{ir.ONE, ir.TCHAN}:      ssa.OpNeqPtr,
+	opAndType{ir.ONE, ir.TPTR64}:     ssa.OpNeqPtr,
+	opAndType{ir.ONE, ir.TUINTPTR}:   ssa.OpNeqPtr,
+	opAndType{ir.ONE, ir.TUNSAFEPTR}: ssa.OpNeqPtr,
+	opAndType{ir.ONE, ir.TFLOAT64}:   ssa.OpNeq64F,
+	opAndType{ir.ONE, ir.TFLOAT32}:   ssa.OpNeq32F,
+
+	opAndType{ir.OLT, ir.TINT8}:    ssa.OpLess8,
+	opAndType{ir.OLT, ir.TUINT8}:   ssa.OpLess8U,
+	opAndType{ir.OLT, ir.TINT16}:   ssa.OpLess16,
+	opAndType{ir.OLT, ir.TUINT16}:  ssa.OpLess16U,
+	opAndType{ir.OLT, ir.TINT32}:   ssa.OpLess32,
+	opAndType{ir.OLT, ir.TUINT32}:  ssa.OpLess32U,
+	opAndType{ir.OLT, ir.TINT64}:   ssa.OpLess64,
+	opAndType{ir.OLT, ir.TUINT64}:  ssa.OpLess64U,
+	opAndType{ir.OLT, ir.TFLOAT64}: ssa.OpLess64F,
+	opAndType{ir.OLT, ir.TFLOAT32}: ssa.OpLess32F,
+
+	opAndType{ir.OGT, ir.TINT8}:    ssa.OpGreater8,
+	opAndType{ir.OGT, ir.TUINT8}:   ssa.OpGreater8U,
+	opAndType{ir.OGT, ir.TINT16}:   ssa.OpGreater16,
+	opAndType{ir.OGT, ir.TUINT16}:  ssa.OpGreater16U,
+	opAndType{ir.OGT, ir.TINT32}:   ssa.OpGreater32,
+	opAndType{ir.OGT, ir.TUINT32}:  ssa.OpGreater32U,
+	opAndType{ir.OGT, ir.TINT64}:   ssa.OpGreater64,
+	opAndType{ir.OGT, ir.TUINT64}:  ssa.OpGreater64U,
+	opAndType{ir.OGT, ir.TFLOAT64}: ssa.OpGreater64F,
+	opAndType{ir.OGT, ir.TFLOAT32}: ssa.OpGreater32F,
+
+	opAndType{ir.OLE, ir.TINT8}:    ssa.OpLeq8,
+	opAndType{ir.OLE, ir.TUINT8}:   ssa.OpLeq8U,
+	opAndType{ir.OLE, ir.TINT16}:   ssa.OpLeq16,
+	opAndType{ir.OLE, ir.TUINT16}:  ssa.OpLeq16U,
+	opAndType{ir.OLE, ir.TINT32}:   ssa.OpLeq32,
+	opAndType{ir.OLE, ir.TUINT32}:  ssa.OpLeq32U,
+	opAndType{ir.OLE, ir.TINT64}:   ssa.OpLeq64,
+	opAndType{ir.OLE, ir.TUINT64}:  ssa.OpLeq64U,
+	opAndType{ir.OLE, ir.TFLOAT64}: ssa.OpLeq64F,
+	opAndType{ir.OLE, ir.TFLOAT32}: ssa.OpLeq32F,
+
+	opAndType{ir.OGE, ir.TINT8}:    ssa.OpGeq8,
+	opAndType{ir.OGE, ir.TUINT8}:   ssa.OpGeq8U,
+	opAndType{ir.OGE, ir.TINT16}:   ssa.OpGeq16,
+	opAndType{ir.OGE, ir.TUINT16}:  ssa.OpGeq16U,
+	opAndType{ir.OGE, ir.TINT32}:   ssa.OpGeq32,
+	opAndType{ir.OGE, ir.TUINT32}:  ssa.OpGeq32U,
+	opAndType{ir.OGE, ir.TINT64}:   ssa.OpGeq64,
+	opAndType{ir.OGE, ir.TUINT64}:  ssa.OpGeq64U,
+	opAndType{ir.OGE, ir.TFLOAT64}: ssa.OpGeq64F,
+	opAndType{ir.OGE, ir.TFLOAT32}: ssa.OpGeq32F,
+
+	opAndType{ir.OLROT, ir.TUINT8}:  ssa.OpLrot8,
+	opAndType{ir.OLROT, ir.TUINT16}: ssa.OpLrot16,
+	opAndType{ir.OLROT, ir.TUINT32}: ssa.OpLrot32,
+	opAndType{ir.OLROT, ir.TUINT64}: ssa.OpLrot64,
+
+	opAndType{ir.OSQRT, ir.TFLOAT64}: ssa.OpSqrt,
+}
+
+func (s *state) concreteEtype(t *ir.Type) ir.EType {
 	e := t.Etype
 	switch e {
 	default:
 		return e
-	case TINT:
+	case ir.TINT:
 		if s.config.IntSize == 8 {
-			return TINT64
+			return ir.TINT64
 		}
-		return TINT32
-	case TUINT:
+		return ir.TINT32
+	case ir.TUINT:
 		if s.config.IntSize == 8 {
-			return TUINT64
+			return ir.TUINT64
 		}
-		return TUINT32
-	case TUINTPTR:
+		return ir.TUINT32
+	case ir.TUINTPTR:
 		if s.config.PtrSize == 8 {
-			return TUINT64
+			return ir.TUINT64
 		}
-		return TUINT32
+		return ir.TUINT32
 	}
 }
 
-func (s *state) ssaOp(op Op, t *Type) ssa.Op {
+func (s *state) ssaOp(op ir.Op, t *ir.Type) ssa.Op {
 	etype := s.concreteEtype(t)
 	x, ok := opToSSA[opAndType{op, etype}]
 	if !ok {
-		s.Unimplementedf("unhandled binary op %s %s", opnames[op], Econv(etype))
+		s.Unimplementedf("unhandled binary op %s %s", opnames[op], ir.Econv(etype))
 	}
 	return x
 }
 
-func floatForComplex(t *Type) *Type {
+func floatForComplex(t *ir.Type) *ir.Type {
 	if t.Size() == 8 {
-		return Types[TFLOAT32]
+		return ir.Types[ir.TFLOAT32]
 	} else {
-		return Types[TFLOAT64]
+		return ir.Types[ir.TFLOAT64]
 	}
 }
 
 type opAndTwoTypes struct {
-	op     Op
-	etype1 EType
-	etype2 EType
+	op     ir.Op
+	etype1 ir.EType
+	etype2 ir.EType
 }
 
 type twoTypes struct {
-	etype1 EType
-	etype2 EType
+	etype1 ir.EType
+	etype2 ir.EType
 }
 
 type twoOpsAndType struct {
 	op1              ssa.Op
 	op2              ssa.Op
-	intermediateType EType
+	intermediateType ir.EType
 }
 
 var fpConvOpToSSA = map[twoTypes]twoOpsAndType{
 
-	twoTypes{TINT8, TFLOAT32}:  twoOpsAndType{ssa.OpSignExt8to32, ssa.OpCvt32to32F, TINT32},
-	twoTypes{TINT16, TFLOAT32}: twoOpsAndType{ssa.OpSignExt16to32, ssa.OpCvt32to32F, TINT32},
-	twoTypes{TINT32, TFLOAT32}: twoOpsAndType{ssa.OpCopy, ssa.OpCvt32to32F, TINT32},
-	twoTypes{TINT64, TFLOAT32}: twoOpsAndType{ssa.OpCopy, ssa.OpCvt64to32F, TINT64},
-
-	twoTypes{TINT8, TFLOAT64}:  twoOpsAndType{ssa.OpSignExt8to32, ssa.OpCvt32to64F, TINT32},
-	twoTypes{TINT16, TFLOAT64}: twoOpsAndType{ssa.OpSignExt16to32, ssa.OpCvt32to64F, TINT32},
-	twoTypes{TINT32, TFLOAT64}: twoOpsAndType{ssa.OpCopy, ssa.OpCvt32to64F, TINT32},
-	twoTypes{TINT64, TFLOAT64}: twoOpsAndType{ssa.OpCopy, ssa.OpCvt64to64F, TINT64},
-
-	twoTypes{TFLOAT32, TINT8}:  twoOpsAndType{ssa.OpCvt32Fto32, ssa.OpTrunc32to8, TINT32},
-	twoTypes{TFLOAT32, TINT16}: twoOpsAndType{ssa.OpCvt32Fto32, ssa.OpTrunc32to16, TINT32},
-	twoTypes{TFLOAT32, TINT32}: twoOpsAndType{ssa.OpCvt32Fto32, ssa.OpCopy, TINT32},
-	twoTypes{TFLOAT32, TINT64}: twoOpsAndType{ssa.OpCvt32Fto64, ssa.OpCopy, TINT64},
-
-	twoTypes{TFLOAT64, TINT8}:  twoOpsAndType{ssa.OpCvt64Fto32, ssa.OpTrunc32to8, TINT32},
-	twoTypes{TFLOAT64, TINT16}: twoOpsAndType{ssa.OpCvt64Fto32, ssa.OpTrunc32to16, TINT32},
-	twoTypes{TFLOAT64, TINT32}: twoOpsAndType{ssa.OpCvt64Fto32, ssa.OpCopy, TINT32},
-	twoTypes{TFLOAT64, TINT64}: twoOpsAndType{ssa.OpCvt64Fto64, ssa.OpCopy, TINT64},
+	twoTypes{ir.TINT8, ir.TFLOAT32}:  twoOpsAndType{ssa.OpSignExt8to32, ssa.OpCvt32to32F, ir.TINT32},
+	twoTypes{ir.TINT16, ir.TFLOAT32}: twoOpsAndType{ssa.OpSignExt16to32, ssa.OpCvt32to32F, ir.TINT32},
+	twoTypes{ir.TINT32, ir.TFLOAT32}: twoOpsAndType{ssa.OpCopy, ssa.OpCvt32to32F, ir.TINT32},
+	twoTypes{ir.TINT64, ir.TFLOAT32}: twoOpsAndType{ssa.OpCopy, ssa.OpCvt64to32F, ir.TINT64},
+
+	twoTypes{ir.TINT8, ir.TFLOAT64}:  twoOpsAndType{ssa.OpSignExt8to32, ssa.OpCvt32to64F, ir.TINT32},
+	twoTypes{ir.TINT16, ir.TFLOAT64}: twoOpsAndType{ssa.OpSignExt16to32, ssa.OpCvt32to64F, ir.TINT32},
+	twoTypes{ir.TINT32, ir.TFLOAT64}: twoOpsAndType{ssa.OpCopy, ssa.OpCvt32to64F, ir.TINT32},
+	twoTypes{ir.TINT64, ir.TFLOAT64}: twoOpsAndType{ssa.OpCopy, ssa.OpCvt64to64F, ir.TINT64},
+
+	twoTypes{ir.TFLOAT32, ir.TINT8}:  twoOpsAndType{ssa.OpCvt32Fto32, ssa.OpTrunc32to8, ir.TINT32},
+	twoTypes{ir.TFLOAT32, ir.TINT16}: twoOpsAndType{ssa.OpCvt32Fto32, ssa.OpTrunc32to16, ir.TINT32},
+	twoTypes{ir.TFLOAT32, ir.TINT32}: twoOpsAndType{ssa.OpCvt32Fto32, ssa.OpCopy, ir.TINT32},
+	twoTypes{ir.TFLOAT32, ir.TINT64}: twoOpsAndType{ssa.OpCvt32Fto64, ssa.OpCopy, ir.TINT64},
+
+	twoTypes{ir.TFLOAT64, ir.TINT8}:  twoOpsAndType{ssa.OpCvt64Fto32, ssa.OpTrunc32to8, ir.TINT32},
+	twoTypes{ir.TFLOAT64, ir.TINT16}: twoOpsAndType{ssa.OpCvt64Fto32, ssa.OpTrunc32to16, ir.TINT32},
+	twoTypes{ir.TFLOAT64, ir.TINT32}: twoOpsAndType{ssa.OpCvt64Fto32, ssa.OpCopy, ir.TINT32},
+	twoTypes{ir.TFLOAT64, ir.TINT64}: twoOpsAndType{ssa.OpCvt64Fto64, ssa.OpCopy, ir.TINT64},
 	// unsigned
-	twoTypes{TUINT8, TFLOAT32}:  twoOpsAndType{ssa.OpZeroExt8to32, ssa.OpCvt32to32F, TINT32},
-	twoTypes{TUINT16, TFLOAT32}: twoOpsAndType{ssa.OpZeroExt16to32, ssa.OpCvt32to32F, TINT32},
-	twoTypes{TUINT32, TFLOAT32}: twoOpsAndType{ssa.OpZeroExt32to64, ssa.OpCvt64to32F, TINT64}, // go wide to dodge unsigned
-	twoTypes{TUINT64, TFLOAT32}: twoOpsAndType{ssa.OpCopy, ssa.OpInvalid, TUINT64},            // Cvt64Uto32F, branchy code expansion instead
-
-	twoTypes{TUINT8, TFLOAT64}:  twoOpsAndType{ssa.OpZeroExt8to32, ssa.OpCvt32to64F, TINT32},
-	twoTypes{TUINT16, TFLOAT64}: twoOpsAndType{ssa.OpZeroExt16to32, ssa.OpCvt32to64F, TINT32},
-	twoTypes{TUINT32, TFLOAT64}: twoOpsAndType{ssa.OpZeroExt32to64, ssa.OpCvt64to64F, TINT64}, // go wide to dodge unsigned
-	twoTypes{TUINT64, TFLOAT64}: twoOpsAndType{ssa.OpCopy, ssa.OpInvalid, TUINT64},            // Cvt64Uto64F, branchy code expansion instead
-
-	twoTypes{TFLOAT32, TUINT8}:  twoOpsAndType{ssa.OpCvt32Fto32, ssa.OpTrunc32to8, TINT32},
-	twoTypes{TFLOAT32, TUINT16}: twoOpsAndType{ssa.OpCvt32Fto32, ssa.OpTrunc32to16, TINT32},
-	twoTypes{TFLOAT32, TUINT32}: twoOpsAndType{ssa.OpCvt32Fto64, ssa.OpTrunc64to32, TINT64}, // go wide to dodge unsigned
-	twoTypes{TFLOAT32, TUINT64}: twoOpsAndType{ssa.OpInvalid, ssa.OpCopy, TUINT64},          // Cvt32Fto64U, branchy code expansion instead
-
-	twoTypes{TFLOAT64, TUINT8}:  twoOpsAndType{ssa.OpCvt64Fto32, ssa.OpTrunc32to8, TINT32},
-	twoTypes{TFLOAT64, TUINT16}: twoOpsAndType{ssa.OpCvt64Fto32, ssa.OpTrunc32to16, TINT32},
-	twoTypes{TFLOAT64, TUINT32}: twoOpsAndType{ssa.OpCvt64Fto64, ssa.OpTrunc64to32, TINT64}, // go wide to dodge unsigned
-	twoTypes{TFLOAT64, TUINT64}: twoOpsAndType{ssa.OpInvalid, ssa.OpCopy, TUINT64},          // Cvt64Fto64U, branchy code expansion instead
+	twoTypes{ir.TUINT8, ir.TFLOAT32}:  twoOpsAndType{ssa.OpZeroExt8to32, ssa.OpCvt32to32F, ir.TINT32},
+	twoTypes{ir.TUINT16, ir.TFLOAT32}: twoOpsAndType{ssa.OpZeroExt16to32, ssa.OpCvt32to32F, ir.TINT32},
+	twoTypes{ir.TUINT32, ir.TFLOAT32}: twoOpsAndType{ssa.OpZeroExt32to64, ssa.OpCvt64to32F, ir.TINT64}, // go wide to dodge unsigned
+	twoTypes{ir.TUINT64, ir.TFLOAT32}: twoOpsAndType{ssa.OpCopy, ssa.OpInvalid, ir.TUINT64},            // Cvt64Uto32F, branchy code expansion instead
+
+	twoTypes{ir.TUINT8, ir.TFLOAT64}:  twoOpsAndType{ssa.OpZeroExt8to32, ssa.OpCvt32to64F, ir.TINT32},
+	twoTypes{ir.TUINT16, ir.TFLOAT64}: twoOpsAndType{ssa.OpZeroExt16to32, ssa.OpCvt32to64F, ir.TINT32},
+	twoTypes{ir.TUINT32, ir.TFLOAT64}: twoOpsAndType{ssa.OpZeroExt32to64, ssa.OpCvt64to64F, ir.TINT64}, // go wide to dodge unsigned
+	twoTypes{ir.TUINT64, ir.TFLOAT64}: twoOpsAndType{ssa.OpCopy, ssa.OpInvalid, ir.TUINT64},            // Cvt64Uto64F, branchy code expansion instead
+
+	twoTypes{ir.TFLOAT32, ir.TUINT8}:  twoOpsAndType{ssa.OpCvt32Fto32, ssa.OpTrunc32to8, ir.TINT32},
+	twoTypes{ir.TFLOAT32, ir.TUINT16}: twoOpsAndType{ssa.OpCvt32Fto32, ssa.OpTrunc32to16, ir.TINT32},
+	twoTypes{ir.TFLOAT32, ir.TUINT32}: twoOpsAndType{ssa.OpCvt32Fto64, ssa.OpTrunc64to32, ir.TINT64}, // go wide to dodge unsigned
+	twoTypes{ir.TFLOAT32, ir.TUINT64}: twoOpsAndType{ssa.OpInvalid, ssa.OpCopy, ir.TUINT64},          // Cvt32Fto64U, branchy code expansion instead
+
+	twoTypes{ir.TFLOAT64, ir.TUINT8}:  twoOpsAndType{ssa.OpCvt64Fto32, ssa.OpTrunc32to8, ir.TINT32},
+	twoTypes{ir.TFLOAT64, ir.TUINT16}: twoOpsAndType{ssa.OpCvt64Fto32, ssa.OpTrunc32to16, ir.TINT32},
+	twoTypes{ir.TFLOAT64, ir.TUINT32}: twoOpsAndType{ssa.OpCvt64Fto64, ssa.OpTrunc64to32, ir.TINT64}, // go wide to dodge unsigned
+	twoTypes{ir.TFLOAT64, ir.TUINT64}: twoOpsAndType{ssa.OpInvalid, ssa.OpCopy, ir.TUINT64},          // Cvt64Fto64U, branchy code expansion instead
 
 	// float
-	twoTypes{TFLOAT64, TFLOAT32}: twoOpsAndType{ssa.OpCvt64Fto32F, ssa.OpCopy, TFLOAT32},
-	twoTypes{TFLOAT64, TFLOAT64}: twoOpsAndType{ssa.OpCopy, ssa.OpCopy, TFLOAT64},
-	twoTypes{TFLOAT32, TFLOAT32}: twoOpsAndType{ssa.OpCopy, ssa.OpCopy, TFLOAT32},
-	twoTypes{TFLOAT32, TFLOAT64}: twoOpsAndType{ssa.OpCvt32Fto64F, ssa.OpCopy, TFLOAT64},
+	twoTypes{ir.TFLOAT64, ir.TFLOAT32}: twoOpsAndType{ssa.OpCvt64Fto32F, ssa.OpCopy, ir.TFLOAT32},
+	twoTypes{ir.TFLOAT64, ir.TFLOAT64}: twoOpsAndType{ssa.OpCopy, ssa.OpCopy, ir.TFLOAT64},
+	twoTypes{ir.TFLOAT32, ir.TFLOAT32}: twoOpsAndType{ssa.OpCopy, ssa.OpCopy, ir.TFLOAT32},
+	twoTypes{ir.TFLOAT32, ir.TFLOAT64}: twoOpsAndType{ssa.OpCvt32Fto64F, ssa.OpCopy, ir.TFLOAT64},
 }
 
 var shiftOpToSSA = map[opAndTwoTypes]ssa.Op{
-	opAndTwoTypes{OLSH, TINT8, TUINT8}:   ssa.OpLsh8x8,
-	opAndTwoTypes{OLSH, TUINT8, TUINT8}:  ssa.OpLsh8x8,
-	opAndTwoTypes{OLSH, TINT8, TUINT16}:  ssa.OpLsh8x16,
-	opAndTwoTypes{OLSH, TUINT8, TUINT16}: ssa.OpLsh8x16,
-	opAndTwoTypes{OLSH, TINT8, TUINT32}:  ssa.OpLsh8x32,
-	opAndTwoTypes{OLSH, TUINT8, TUINT32}: ssa.OpLsh8x32,
-	opAndTwoTypes{OLSH, TINT8, TUINT64}:  ssa.OpLsh8x64,
-	opAndTwoTypes{OLSH, TUINT8, TUINT64}: ssa.OpLsh8x64,
-
-	opAndTwoTypes{OLSH, TINT16, TUINT8}:   ssa.OpLsh16x8,
-	opAndTwoTypes{OLSH, TUINT16, TUINT8}:  ssa.OpLsh16x8,
-	opAndTwoTypes{OLSH, TINT16, TUINT16}:  ssa.OpLsh16x16,
-	opAndTwoTypes{OLSH, TUINT16, TUINT16}: ssa.OpLsh16x16,
-	opAndTwoTypes{OLSH, TINT16, TUINT32}:  ssa.OpLsh16x32,
-	opAndTwoTypes{OLSH, TUINT16, TUINT32}: ssa.OpLsh16x32,
-	opAndTwoTypes{OLSH, TINT16, TUINT64}:  ssa.OpLsh16x64,
-	opAndTwoTypes{OLSH, TUINT16, TUINT64}: ssa.OpLsh16x64,
-
-	opAndTwoTypes{OLSH, TINT32, TUINT8}:   ssa.OpLsh32x8,
-	opAndTwoTypes{OLSH, TUINT32, TUINT8}:  ssa.OpLsh32x8,
-	opAndTwoTypes{OLSH, TINT32, TUINT16}:  ssa.OpLsh32x16,
-	opAndTwoTypes{OLSH, TUINT32, TUINT16}: ssa.OpLsh32x16,
-	opAndTwoTypes{OLSH, TINT32, TUINT32}:  ssa.OpLsh32x32,
-	opAndTwoTypes{OLSH, TUINT32, TUINT32}: ssa.OpLsh32x32,
-	opAndTwoTypes{OLSH, TINT32, TUINT64}:  ssa.OpLsh32x64,
-	opAndTwoTypes{OLSH, TUINT32, TUINT64}: ssa.OpLsh32x64,
-
-	opAndTwoTypes{OLSH, TINT64, TUINT8}:   ssa.OpLsh64x8,
-	opAndTwoTypes{OLSH, TUINT64, TUINT8}:  ssa.OpLsh64x8,
-	opAndTwoTypes{OLSH, TINT64, TUINT16}:  ssa.OpLsh64x16,
-	opAndTwoTypes{OLSH, TUINT64, TUINT16}: ssa.OpLsh64x16,
-	opAndTwoTypes{OLSH, TINT64, TUINT32}:  ssa.OpLsh64x32,
-	opAndTwoTypes{OLSH, TUINT64, TUINT32}: ssa.OpLsh64x32,
-	opAndTwoTypes{OLSH, TINT64, TUINT64}:  ssa.OpLsh64x64,
-	opAndTwoTypes{OLSH, TUINT64, TUINT64}: ssa.OpLsh64x64,
-
-	opAndTwoTypes{ORSH, TINT8, TUINT8}:   ssa.OpRsh8x8,
-	opAndTwoTypes{ORSH, TUINT8, TUINT8}:  ssa.OpRsh8Ux8,
-	opAndTwoTypes{ORSH, TINT8, TUINT16}:  ssa.OpRsh8x16,
-	opAndTwoTypes{ORSH, TUINT8, TUINT16}: ssa.OpRsh8Ux16,
-	opAndTwoTypes{ORSH, TINT8, TUINT32}:  ssa.OpRsh8x32,
-	opAndTwoTypes{ORSH, TUINT8, TUINT32}: ssa.OpRsh8Ux32,
-	opAndTwoTypes{ORSH, TINT8, TUINT64}:  ssa.OpRsh8x64,
-	opAndTwoTypes{ORSH, TUINT8, TUINT64}: ssa.OpRsh8Ux64,
-
-	opAndTwoTypes{ORSH, TINT16, TUINT8}:   ssa.OpRsh16x8,
-	opAndTwoTypes{ORSH, TUINT16, TUINT8}:  ssa.OpRsh16Ux8,
-	opAndTwoTypes{ORSH, TINT16, TUINT16}:  ssa.OpRsh16x16,
-	opAndTwoTypes{ORSH, TUINT16, TUINT16}: ssa.OpRsh16Ux16,
-	opAndTwoTypes{ORSH, TINT16, TUINT32}:  ssa.OpRsh16x32,
-	opAndTwoTypes{ORSH, TUINT16, TUINT32}: ssa.OpRsh16Ux32,
-	opAndTwoTypes{ORSH, TINT16, TUINT64}:  ssa.OpRsh16x64,
-	opAndTwoTypes{ORSH, TUINT16, TUINT64}: ssa.OpRsh16Ux64,
-
-	opAndTwoTypes{ORSH, TINT32, TUINT8}:   ssa.OpRsh32x8,
-	opAndTwoTypes{ORSH, TUINT32, TUINT8}:  ssa.OpRsh32Ux8,
-	opAndTwoTypes{ORSH, TINT32, TUINT16}:  ssa.OpRsh32x16,
-	opAndTwoTypes{ORSH, TUINT32, TUINT16}: ssa.OpRsh32Ux16,
-	opAndTwoTypes{ORSH, TINT32, TUINT32}:  ssa.OpRsh32x32,
-	opAndTwoTypes{ORSH, TUINT32, TUINT32}: ssa.OpRsh32Ux32,
-	opAndTwoTypes{ORSH, TINT32, TUINT64}:  ssa.OpRsh32x64,
-	opAndTwoTypes{ORSH, TUINT32, TUINT64}: ssa.OpRsh32Ux64,
-
-	opAndTwoTypes{ORSH, TINT64, TUINT8}:   ssa.OpRsh64x8,
-	opAndTwoTypes{ORSH, TUINT64, TUINT8}:  ssa.OpRsh64Ux8,
-	opAndTwoTypes{ORSH, TINT64, TUINT16}:  ssa.OpRsh64x16,
-	opAndTwoTypes{ORSH, TUINT64, TUINT16}: ssa.OpRsh64Ux16,
-	opAndTwoTypes{ORSH, TINT64, TUINT32}:  ssa.OpRsh64x32,
-	opAndTwoTypes{ORSH, TUINT64, TUINT32}: ssa.OpRsh64Ux32,
-	opAndTwoTypes{ORSH, TINT64, TUINT64}:  ssa.OpRsh64x64,
-	opAndTwoTypes{ORSH, TUINT64, TUINT64}: ssa.OpRsh64Ux64,
-}
-
-func (s *state) ssaShiftOp(op Op, t *Type, u *Type) ssa.Op {
+	opAndTwoTypes{ir.OLSH, ir.TINT8, ir.TUINT8}:   ssa.OpLsh8x8,
+	opAndTwoTypes{ir.OLSH, ir.TUINT8, ir.TUINT8}:  ssa.OpLsh8x8,
+	opAndTwoTypes{ir.OLSH, ir.TINT8, ir.TUINT16}:  ssa.OpLsh8x16,
+	opAndTwoTypes{ir.OLSH, ir.TUINT8, ir.TUINT16}: ssa.OpLsh8x16,
+	opAndTwoTypes{ir.OLSH, ir.TINT8, ir.TUINT32}:  ssa.OpLsh8x32,
+	opAndTwoTypes{ir.OLSH, ir.TUINT8, ir.TUINT32}: ssa.OpLsh8x32,
+	opAndTwoTypes{ir.OLSH, ir.TINT8, ir.TUINT64}:  ssa.OpLsh8x64,
+	opAndTwoTypes{ir.OLSH, ir.TUINT8, ir.TUINT64}: ssa.OpLsh8x64,
+
+	opAndTwoTypes{ir.OLSH, ir.TINT16, ir.TUINT8}:   ssa.OpLsh16x8,
+	opAndTwoTypes{ir.OLSH, ir.TUINT16, ir.TUINT8}:  ssa.OpLsh16x8,
+	opAndTwoTypes{ir.OLSH, ir.TINT16, ir.TUINT16}:  ssa.OpLsh16x16,
+	opAndTwoTypes{ir.OLSH, ir.TUINT16, ir.TUINT16}: ssa.OpLsh16x16,
+	opAndTwoTypes{ir.OLSH, ir.TINT16, ir.TUINT32}:  ssa.OpLsh16x32,
+	opAndTwoTypes{ir.OLSH, ir.TUINT16, ir.TUINT32}: ssa.OpLsh16x32,
+	opAndTwoTypes{ir.OLSH, ir.TINT16, ir.TUINT64}:  ssa.OpLsh16x64,
+	opAndTwoTypes{ir.OLSH, ir.TUINT16, ir.TUINT64}: ssa.OpLsh16x64,
+
+	opAndTwoTypes{ir.OLSH, ir.TINT32, ir.TUINT8}:   ssa.OpLsh32x8,
+	opAndTwoTypes{ir.OLSH, ir.TUINT32, ir.TUINT8}:  ssa.OpLsh32x8,
+	opAndTwoTypes{ir.OLSH, ir.TINT32, ir.TUINT16}:  ssa.OpLsh32x16,
+	opAndTwoTypes{ir.OLSH, ir.TUINT32, ir.TUINT16}: ssa.OpLsh32x16,
+	opAndTwoTypes{ir.OLSH, ir.TINT32, ir.TUINT32}:  ssa.OpLsh32x32,
+	opAndTwoTypes{ir.OLSH, ir.TUINT32, ir.TUINT32}: ssa.OpLsh32x32,
+	opAndTwoTypes{ir.OLSH, ir.TINT32, ir.TUINT64}:  ssa.OpLsh32x64,
+	opAndTwoTypes{ir.OLSH, ir.TUINT32, ir.TUINT64}: ssa.OpLsh32x64,
+
+	opAndTwoTypes{ir.OLSH, ir.TINT64, ir.TUINT8}:   ssa.OpLsh64x8,
+	opAndTwoTypes{ir.OLSH, ir.TUINT64, ir.TUINT8}:  ssa.OpLsh64x8,
+	opAndTwoTypes{ir.OLSH, ir.TINT64, ir.TUINT16}:  ssa.OpLsh64x16,
+	opAndTwoTypes{ir.OLSH, ir.TUINT64, ir.TUINT16}: ssa.OpLsh64x16,
+	opAndTwoTypes{ir.OLSH, ir.TINT64, ir.TUINT32}:  ssa.OpLsh64x32,
+	opAndTwoTypes{ir.OLSH, ir.TUINT64, ir.TUINT32}: ssa.OpLsh64x32,
+	opAndTwoTypes{ir.OLSH, ir.TINT64, ir.TUINT64}:  ssa.OpLsh64x64,
+	opAndTwoTypes{ir.OLSH, ir.TUINT64, ir.TUINT64}: ssa.OpLsh64x64,
+
+	opAndTwoTypes{ir.ORSH, ir.TINT8, ir.TUINT8}:   ssa.OpRsh8x8,
+	opAndTwoTypes{ir.ORSH, ir.TUINT8, ir.TUINT8}:  ssa.OpRsh8Ux8,
+	opAndTwoTypes{ir.ORSH, ir.TINT8, ir.TUINT16}:  ssa.OpRsh8x16,
+	opAndTwoTypes{ir.ORSH, ir.TUINT8, ir.TUINT16}: ssa.OpRsh8Ux16,
+	opAndTwoTypes{ir.ORSH, ir.TINT8, ir.TUINT32}:  ssa.OpRsh8x32,
+	opAndTwoTypes{ir.ORSH, ir.TUINT8, ir.TUINT32}: ssa.OpRsh8Ux32,
+	opAndTwoTypes{ir.ORSH, ir.TINT8, ir.TUINT64}:  ssa.OpRsh8x64,
+	opAndTwoTypes{ir.ORSH, ir.TUINT8, ir.TUINT64}: ssa.OpRsh8Ux64,
+
+	opAndTwoTypes{ir.ORSH, ir.TINT16, ir.TUINT8}:   ssa.OpRsh16x8,
+	opAndTwoTypes{ir.ORSH, ir.TUINT16, ir.TUINT8}:  ssa.OpRsh16Ux8,
+	opAndTwoTypes{ir.ORSH, ir.TINT16, ir.TUINT16}:  ssa.OpRsh16x16,
+	opAndTwoTypes{ir.ORSH, ir.TUINT16, ir.TUINT16}: ssa.OpRsh16Ux16,
+	opAndTwoTypes{ir.ORSH, ir.TINT16, ir.TUINT32}:  ssa.OpRsh16x32,
+	opAndTwoTypes{ir.ORSH, ir.TUINT16, ir.TUINT32}: ssa.OpRsh16Ux32,
+	opAndTwoTypes{ir.ORSH, ir.TINT16, ir.TUINT64}:  ssa.OpRsh16x64,
+	opAndTwoTypes{ir.ORSH, ir.TUINT16, ir.TUINT64}: ssa.OpRsh16Ux64,
+
+	opAndTwoTypes{ir.ORSH, ir.TINT32, ir.TUINT8}:   ssa.OpRsh32x8,
+	opAndTwoTypes{ir.ORSH, ir.TUINT32, ir.TUINT8}:  ssa.OpRsh32Ux8,
+	opAndTwoTypes{ir.ORSH, ir.TINT32, ir.TUINT16}:  ssa.OpRsh32x16,
+	opAndTwoTypes{ir.ORSH, ir.TUINT32, ir.TUINT16}: ssa.OpRsh32Ux16,
+	opAndTwoTypes{ir.ORSH, ir.TINT32, ir.TUINT32}:  ssa.OpRsh32x32,
+	opAndTwoTypes{ir.ORSH, ir.TUINT32, ir.TUINT32}: ssa.OpRsh32Ux32,
+	opAndTwoTypes{ir.ORSH, ir.TINT32, ir.TUINT64}:  ssa.OpRsh32x64,
+	opAndTwoTypes{ir.ORSH, ir.TUINT32, ir.TUINT64}: ssa.OpRsh32Ux64,
+
+	opAndTwoTypes{ir.ORSH, ir.TINT64, ir.TUINT8}:   ssa.OpRsh64x8,
+	opAndTwoTypes{ir.ORSH, ir.TUINT64, ir.TUINT8}:  ssa.OpRsh64Ux8,
+	opAndTwoTypes{ir.ORSH, ir.TINT64, ir.TUINT16}:  ssa.OpRsh64x16,
+	opAndTwoTypes{ir.ORSH, ir.TUINT64, ir.TUINT16}: ssa.OpRsh64Ux16,
+	opAndTwoTypes{ir.ORSH, ir.TINT64, ir.TUINT32}:  ssa.OpRsh64x32,
+	opAndTwoTypes{ir.ORSH, ir.TUINT64, ir.TUINT32}: ssa.OpRsh64Ux32,
+	opAndTwoTypes{ir.ORSH, ir.TINT64, ir.TUINT64}:  ssa.OpRsh64x64,
+	opAndTwoTypes{ir.ORSH, ir.TUINT64, ir.TUINT64}: ssa.OpRsh64Ux64,
+}
+
+func (s *state) ssaShiftOp(op ir.Op, t *ir.Type, u *ir.Type) ssa.Op {
 	etype1 := s.concreteEtype(t)
 	etype2 := s.concreteEtype(u)
 	x, ok := shiftOpToSSA[opAndTwoTypes{op, etype1, etype2}]
 	if !ok {
-		s.Unimplementedf("unhandled shift op %s etype=%s/%s", opnames[op], Econv(etype1), Econv(etype2))
+		s.Unimplementedf("unhandled shift op %s etype=%s/%s", opnames[op], ir.Econv(etype1), ir.Econv(etype2))
 	}
 	return x
 }
 
-func (s *state) ssaRotateOp(op Op, t *Type) ssa.Op {
+func (s *state) ssaRotateOp(op ir.Op, t *ir.Type) ssa.Op {
 	etype1 := s.concreteEtype(t)
 	x, ok := opToSSA[opAndType{op, etype1}]
 	if !ok {
-		s.Unimplementedf("unhandled rotate op %s etype=%s", opnames[op], Econv(etype1))
+		s.Unimplementedf("unhandled rotate op %s etype=%s", opnames[op], ir.Econv(etype1))
 	}
 	return x
 }
 
 // expr converts the expression n to ssa, adds it to s and returns the ssa result.
-func (s *state) expr(n *Node) *ssa.Value {
+func (s *state) expr(n *ir.Node) *ssa.Value {
 	s.pushLine(n.Lineno)
 	defer s.popLine()
 
 	s.stmtList(n.Ninit)
 	switch n.Op {
-	case OCFUNC:
+	case ir.OCFUNC:
 		aux := s.lookupSymbol(n, &ssa.ExternSymbol{n.Type, n.Left.Sym})
 		return s.entryNewValue1A(ssa.OpAddr, n.Type, aux, s.sb)
-	case OPARAM:
+	case ir.OPARAM:
 		addr := s.addr(n, false)
 		return s.newValue2(ssa.OpLoad, n.Left.Type, addr, s.mem())
-	case ONAME:
-		if n.Class == PFUNC {
+	case ir.ONAME:
+		if n.Class == ir.PFUNC {
 			// "value" of a function is the address of the function's closure
 			sym := funcsym(n.Sym)
 			aux := &ssa.ExternSymbol{n.Type, sym}
@@ -1417,13 +1461,13 @@ func (s *state) expr(n *Node) *ssa.Value {
 		}
 		addr := s.addr(n, false)
 		return s.newValue2(ssa.OpLoad, n.Type, addr, s.mem())
-	case OCLOSUREVAR:
+	case ir.OCLOSUREVAR:
 		addr := s.addr(n, false)
 		return s.newValue2(ssa.OpLoad, n.Type, addr, s.mem())
-	case OLITERAL:
+	case ir.OLITERAL:
 		switch n.Val().Ctype() {
-		case CTINT:
-			i := n.Val().U.(*Mpint).Int64()
+		case ir.CTINT:
+			i := n.Val().U.(*ir.Mpint).Int64()
 			switch n.Type.Size() {
 			case 1:
 				return s.constInt8(n.Type, int8(i))
@@ -1437,12 +1481,12 @@ func (s *state) expr(n *Node) *ssa.Value {
 				s.Fatalf("bad integer size %d", n.Type.Size())
 				return nil
 			}
-		case CTSTR:
+		case ir.CTSTR:
 			if n.Val().U == "" {
 				return s.constEmptyString(n.Type)
 			}
 			return s.entryNewValue0A(ssa.OpConstString, n.Type, n.Val().U)
-		case CTBOOL:
+		case ir.CTBOOL:
 			v := s.constBool(n.Val().U.(bool))
 			// For some reason the frontend gets the line numbers of
 			// CTBOOL literals totally wrong. Fix it here by grabbing
@@ -1451,7 +1495,7 @@ func (s *state) expr(n *Node) *ssa.Value {
 				v.Line = s.line[len(s.line)-2]
 			}
 			return v
-		case CTNIL:
+		case ir.CTNIL:
 			t := n.Type
 			switch {
 			case t.IsSlice():
@@ -1461,8 +1505,8 @@ func (s *state) expr(n *Node) *ssa.Value {
 			default:
 				return s.constNil(t)
 			}
-		case CTFLT:
-			f := n.Val().U.(*Mpflt)
+		case ir.CTFLT:
+			f := n.Val().U.(*ir.Mpflt)
 			switch n.Type.Size() {
 			case 4:
 				return s.constFloat32(n.Type, f.Float32())
@@ -1472,21 +1516,21 @@ func (s *state) expr(n *Node) *ssa.Value {
 				s.Fatalf("bad float size %d", n.Type.Size())
 				return nil
 			}
-		case CTCPLX:
-			c := n.Val().U.(*Mpcplx)
+		case ir.CTCPLX:
+			c := n.Val().U.(*ir.Mpcplx)
 			r := &c.Real
 			i := &c.Imag
 			switch n.Type.Size() {
 			case 8:
 				{
-					pt := Types[TFLOAT32]
+					pt := ir.Types[ir.TFLOAT32]
 					return s.newValue2(ssa.OpComplexMake, n.Type,
 						s.constFloat32(pt, r.Float32()),
 						s.constFloat32(pt, i.Float32()))
 				}
 			case 16:
 				{
-					pt := Types[TFLOAT64]
+					pt := ir.Types[ir.TFLOAT64]
 					return s.newValue2(ssa.OpComplexMake, n.Type,
 						s.constFloat64(pt, r.Float64()),
 						s.constFloat64(pt, i.Float64()))
@@ -1500,7 +1544,7 @@ func (s *state) expr(n *Node) *ssa.Value {
 			s.Unimplementedf("unhandled OLITERAL %v", n.Val().Ctype())
 			return nil
 		}
-	case OCONVNOP:
+	case ir.OCONVNOP:
 		to := n.Type
 		from := n.Left.Type
 
@@ -1519,7 +1563,7 @@ func (s *state) expr(n *Node) *ssa.Value {
 		v := s.newValue1(ssa.OpCopy, to, x) // ensure that v has the right type
 
 		// CONVNOP closure
-		if to.Etype == TFUNC && from.IsPtr() {
+		if to.Etype == ir.TFUNC && from.IsPtr() {
 			return v
 		}
 
@@ -1529,7 +1573,7 @@ func (s *state) expr(n *Node) *ssa.Value {
 		}
 
 		// unsafe.Pointer <--> *T
-		if to.Etype == TUNSAFEPTR && from.IsPtr() || from.Etype == TUNSAFEPTR && to.IsPtr() {
+		if to.Etype == ir.TUNSAFEPTR && from.IsPtr() || from.Etype == ir.TUNSAFEPTR && to.IsPtr() {
 			return v
 		}
 
@@ -1540,7 +1584,7 @@ func (s *state) expr(n *Node) *ssa.Value {
 			return nil
 		}
 		if etypesign(from.Etype) != etypesign(to.Etype) {
-			s.Fatalf("CONVNOP sign mismatch %v (%s) -> %v (%s)\n", from, Econv(from.Etype), to, Econv(to.Etype))
+			s.Fatalf("CONVNOP sign mismatch %v (%s) -> %v (%s)\n", from, ir.Econv(from.Etype), to, ir.Econv(to.Etype))
 			return nil
 		}
 
@@ -1559,7 +1603,7 @@ func (s *state) expr(n *Node) *ssa.Value {
 		// integer, same width, same sign
 		return v
 
-	case OCONV:
+	case ir.OCONV:
 		x := s.expr(n.Left)
 		ft := n.Left.Type // from type
 		tt := n.Type      // to type
@@ -1643,7 +1687,7 @@ func (s *state) expr(n *Node) *ssa.Value {
 				if op2 == ssa.OpCopy {
 					return s.newValue1(op1, n.Type, x)
 				}
-				return s.newValue1(op2, n.Type, s.newValue1(op1, Types[it], x))
+				return s.newValue1(op2, n.Type, s.newValue1(op1, ir.Types[it], x))
 			}
 			// Tricky 64-bit unsigned cases.
 			if ft.IsInteger() {
@@ -1685,34 +1729,34 @@ func (s *state) expr(n *Node) *ssa.Value {
 				s.newValue1(op, ttp, s.newValue1(ssa.OpComplexImag, ftp, x)))
 		}
 
-		s.Unimplementedf("unhandled OCONV %s -> %s", Econv(n.Left.Type.Etype), Econv(n.Type.Etype))
+		s.Unimplementedf("unhandled OCONV %s -> %s", ir.Econv(n.Left.Type.Etype), ir.Econv(n.Type.Etype))
 		return nil
 
-	case ODOTTYPE:
+	case ir.ODOTTYPE:
 		res, _ := s.dottype(n, false)
 		return res
 
 	// binary ops
-	case OLT, OEQ, ONE, OLE, OGE, OGT:
+	case ir.OLT, ir.OEQ, ir.ONE, ir.OLE, ir.OGE, ir.OGT:
 		a := s.expr(n.Left)
 		b := s.expr(n.Right)
 		if n.Left.Type.IsComplex() {
 			pt := floatForComplex(n.Left.Type)
-			op := s.ssaOp(OEQ, pt)
-			r := s.newValue2(op, Types[TBOOL], s.newValue1(ssa.OpComplexReal, pt, a), s.newValue1(ssa.OpComplexReal, pt, b))
-			i := s.newValue2(op, Types[TBOOL], s.newValue1(ssa.OpComplexImag, pt, a), s.newValue1(ssa.OpComplexImag, pt, b))
-			c := s.newValue2(ssa.OpAnd8, Types[TBOOL], r, i)
+			op := s.ssaOp(ir.OEQ, pt)
+			r := s.newValue2(op, ir.Types[ir.TBOOL], s.newValue1(ssa.OpComplexReal, pt, a), s.newValue1(ssa.OpComplexReal, pt, b))
+			i := s.newValue2(op, ir.Types[ir.TBOOL], s.newValue1(ssa.OpComplexImag, pt, a), s.newValue1(ssa.OpComplexImag, pt, b))
+			c := s.newValue2(ssa.OpAnd8, ir.Types[ir.TBOOL], r, i)
 			switch n.Op {
-			case OEQ:
+			case ir.OEQ:
 				return c
-			case ONE:
-				return s.newValue1(ssa.OpNot, Types[TBOOL], c)
+			case ir.ONE:
+				return s.newValue1(ssa.OpNot, ir.Types[ir.TBOOL], c)
 			default:
 				s.Fatalf("ordered complex compare %s", opnames[n.Op])
 			}
 		}
-		return s.newValue2(s.ssaOp(n.Op, n.Left.Type), Types[TBOOL], a, b)
-	case OMUL:
+		return s.newValue2(s.ssaOp(n.Op, n.Left.Type), ir.Types[ir.TBOOL], a, b)
+	case ir.OMUL:
 		a := s.expr(n.Left)
 		b := s.expr(n.Right)
 		if n.Type.IsComplex() {
@@ -1720,7 +1764,7 @@ func (s *state) expr(n *Node) *ssa.Value {
 			addop := ssa.OpAdd64F
 			subop := ssa.OpSub64F
 			pt := floatForComplex(n.Type) // Could be Float32 or Float64
-			wt := Types[TFLOAT64]         // Compute in Float64 to minimize cancellation error
+			wt := ir.Types[ir.TFLOAT64]   // Compute in Float64 to minimize cancellation error
 
 			areal := s.newValue1(ssa.OpComplexReal, pt, a)
 			breal := s.newValue1(ssa.OpComplexReal, pt, b)
@@ -1746,7 +1790,7 @@ func (s *state) expr(n *Node) *ssa.Value {
 		}
 		return s.newValue2(s.ssaOp(n.Op, n.Type), a.Type, a, b)
 
-	case ODIV:
+	case ir.ODIV:
 		a := s.expr(n.Left)
 		b := s.expr(n.Right)
 		if n.Type.IsComplex() {
@@ -1758,7 +1802,7 @@ func (s *state) expr(n *Node) *ssa.Value {
 			subop := ssa.OpSub64F
 			divop := ssa.OpDiv64F
 			pt := floatForComplex(n.Type) // Could be Float32 or Float64
-			wt := Types[TFLOAT64]         // Compute in Float64 to minimize cancellation error
+			wt := ir.Types[ir.TFLOAT64]   // Compute in Float64 to minimize cancellation error
 
 			areal := s.newValue1(ssa.OpComplexReal, pt, a)
 			breal := s.newValue1(ssa.OpComplexReal, pt, b)
@@ -1793,18 +1837,18 @@ func (s *state) expr(n *Node) *ssa.Value {
 			return s.newValue2(s.ssaOp(n.Op, n.Type), a.Type, a, b)
 		} else {
 			// do a size-appropriate check for zero
-			cmp := s.newValue2(s.ssaOp(ONE, n.Type), Types[TBOOL], b, s.zeroVal(n.Type))
+			cmp := s.newValue2(s.ssaOp(ir.ONE, n.Type), ir.Types[ir.TBOOL], b, s.zeroVal(n.Type))
 			s.check(cmp, panicdivide)
 			return s.newValue2(s.ssaOp(n.Op, n.Type), a.Type, a, b)
 		}
-	case OMOD:
+	case ir.OMOD:
 		a := s.expr(n.Left)
 		b := s.expr(n.Right)
 		// do a size-appropriate check for zero
-		cmp := s.newValue2(s.ssaOp(ONE, n.Type), Types[TBOOL], b, s.zeroVal(n.Type))
+		cmp := s.newValue2(s.ssaOp(ir.ONE, n.Type), ir.Types[ir.TBOOL], b, s.zeroVal(n.Type))
 		s.check(cmp, panicdivide)
 		return s.newValue2(s.ssaOp(n.Op, n.Type), a.Type, a, b)
-	case OADD, OSUB:
+	case ir.OADD, ir.OSUB:
 		a := s.expr(n.Left)
 		b := s.expr(n.Right)
 		if n.Type.IsComplex() {
@@ -1815,22 +1859,22 @@ func (s *state) expr(n *Node) *ssa.Value {
 				s.newValue2(op, pt, s.newValue1(ssa.OpComplexImag, pt, a), s.newValue1(ssa.OpComplexImag, pt, b)))
 		}
 		return s.newValue2(s.ssaOp(n.Op, n.Type), a.Type, a, b)
-	case OAND, OOR, OHMUL, OXOR:
+	case ir.OAND, ir.OOR, ir.OHMUL, ir.OXOR:
 		a := s.expr(n.Left)
 		b := s.expr(n.Right)
 		return s.newValue2(s.ssaOp(n.Op, n.Type), a.Type, a, b)
-	case OLSH, ORSH:
+	case ir.OLSH, ir.ORSH:
 		a := s.expr(n.Left)
 		b := s.expr(n.Right)
 		return s.newValue2(s.ssaShiftOp(n.Op, n.Type, n.Right.Type), a.Type, a, b)
-	case OLROT:
+	case ir.OLROT:
 		a := s.expr(n.Left)
 		i := n.Right.Int()
 		if i <= 0 || i >= n.Type.Size()*8 {
 			s.Fatalf("Wrong rotate distance for LROT, expected 1 through %d, saw %d", n.Type.Size()*8-1, i)
 		}
 		return s.newValue1I(s.ssaRotateOp(n.Op, n.Type), a.Type, i, a)
-	case OANDAND, OOROR:
+	case ir.OANDAND, ir.OOROR:
 		// To implement OANDAND (and OOROR), we introduce a
 		// new temporary variable to hold the result. The
 		// variable is associated with the OANDAND node in the
@@ -1857,10 +1901,10 @@ func (s *state) expr(n *Node) *ssa.Value {
 
 		bRight := s.f.NewBlock(ssa.BlockPlain)
 		bResult := s.f.NewBlock(ssa.BlockPlain)
-		if n.Op == OANDAND {
+		if n.Op == ir.OANDAND {
 			b.AddEdgeTo(bRight)
 			b.AddEdgeTo(bResult)
-		} else if n.Op == OOROR {
+		} else if n.Op == ir.OOROR {
 			b.AddEdgeTo(bResult)
 			b.AddEdgeTo(bRight)
 		}
@@ -1873,14 +1917,14 @@ func (s *state) expr(n *Node) *ssa.Value {
 		b.AddEdgeTo(bResult)
 
 		s.startBlock(bResult)
-		return s.variable(n, Types[TBOOL])
-	case OCOMPLEX:
+		return s.variable(n, ir.Types[ir.TBOOL])
+	case ir.OCOMPLEX:
 		r := s.expr(n.Left)
 		i := s.expr(n.Right)
 		return s.newValue2(ssa.OpComplexMake, n.Type, r, i)
 
 	// unary ops
-	case OMINUS:
+	case ir.OMINUS:
 		a := s.expr(n.Left)
 		if n.Type.IsComplex() {
 			tp := floatForComplex(n.Type)
@@ -1890,19 +1934,19 @@ func (s *state) expr(n *Node) *ssa.Value {
 				s.newValue1(negop, tp, s.newValue1(ssa.OpComplexImag, tp, a)))
 		}
 		return s.newValue1(s.ssaOp(n.Op, n.Type), a.Type, a)
-	case ONOT, OCOM, OSQRT:
+	case ir.ONOT, ir.OCOM, ir.OSQRT:
 		a := s.expr(n.Left)
 		return s.newValue1(s.ssaOp(n.Op, n.Type), a.Type, a)
-	case OIMAG, OREAL:
+	case ir.OIMAG, ir.OREAL:
 		a := s.expr(n.Left)
 		return s.newValue1(s.ssaOp(n.Op, n.Left.Type), n.Type, a)
-	case OPLUS:
+	case ir.OPLUS:
 		return s.expr(n.Left)
 
-	case OADDR:
+	case ir.OADDR:
 		return s.addr(n.Left, n.Bounded)
 
-	case OINDREG:
+	case ir.OINDREG:
 		if int(n.Reg) != Thearch.REGSP {
 			s.Unimplementedf("OINDREG of non-SP register %s in expr: %v", obj.Rconv(int(n.Reg)), n)
 			return nil
@@ -1910,12 +1954,12 @@ func (s *state) expr(n *Node) *ssa.Value {
 		addr := s.entryNewValue1I(ssa.OpOffPtr, Ptrto(n.Type), n.Xoffset, s.sp)
 		return s.newValue2(ssa.OpLoad, n.Type, addr, s.mem())
 
-	case OIND:
+	case ir.OIND:
 		p := s.expr(n.Left)
 		s.nilCheck(p)
 		return s.newValue2(ssa.OpLoad, n.Type, p, s.mem())
 
-	case ODOT:
+	case ir.ODOT:
 		t := n.Left.Type
 		if canSSAType(t) {
 			v := s.expr(n.Left)
@@ -1924,30 +1968,30 @@ func (s *state) expr(n *Node) *ssa.Value {
 		p := s.addr(n, false)
 		return s.newValue2(ssa.OpLoad, n.Type, p, s.mem())
 
-	case ODOTPTR:
+	case ir.ODOTPTR:
 		p := s.expr(n.Left)
 		s.nilCheck(p)
 		p = s.newValue1I(ssa.OpOffPtr, p.Type, n.Xoffset, p)
 		return s.newValue2(ssa.OpLoad, n.Type, p, s.mem())
 
-	case OINDEX:
+	case ir.OINDEX:
 		switch {
 		case n.Left.Type.IsString():
 			a := s.expr(n.Left)
 			i := s.expr(n.Right)
 			i = s.extendIndex(i)
 			if !n.Bounded {
-				len := s.newValue1(ssa.OpStringLen, Types[TINT], a)
+				len := s.newValue1(ssa.OpStringLen, ir.Types[ir.TINT], a)
 				s.boundsCheck(i, len)
 			}
-			ptrtyp := Ptrto(Types[TUINT8])
+			ptrtyp := Ptrto(ir.Types[ir.TUINT8])
 			ptr := s.newValue1(ssa.OpStringPtr, ptrtyp, a)
-			if Isconst(n.Right, CTINT) {
+			if ir.Isconst(n.Right, ir.CTINT) {
 				ptr = s.newValue1I(ssa.OpOffPtr, ptrtyp, n.Right.Int(), ptr)
 			} else {
 				ptr = s.newValue2(ssa.OpAddPtr, ptrtyp, ptr, i)
 			}
-			return s.newValue2(ssa.OpLoad, Types[TUINT8], ptr, s.mem())
+			return s.newValue2(ssa.OpLoad, ir.Types[ir.TUINT8], ptr, s.mem())
 		case n.Left.Type.IsSlice():
 			p := s.addr(n, false)
 			return s.newValue2(ssa.OpLoad, n.Left.Type.Type, p, s.mem())
@@ -1960,23 +2004,23 @@ func (s *state) expr(n *Node) *ssa.Value {
 			return nil
 		}
 
-	case OLEN, OCAP:
+	case ir.OLEN, ir.OCAP:
 		switch {
 		case n.Left.Type.IsSlice():
 			op := ssa.OpSliceLen
-			if n.Op == OCAP {
+			if n.Op == ir.OCAP {
 				op = ssa.OpSliceCap
 			}
-			return s.newValue1(op, Types[TINT], s.expr(n.Left))
+			return s.newValue1(op, ir.Types[ir.TINT], s.expr(n.Left))
 		case n.Left.Type.IsString(): // string; not reachable for OCAP
-			return s.newValue1(ssa.OpStringLen, Types[TINT], s.expr(n.Left))
+			return s.newValue1(ssa.OpStringLen, ir.Types[ir.TINT], s.expr(n.Left))
 		case n.Left.Type.IsMap(), n.Left.Type.IsChan():
 			return s.referenceTypeBuiltin(n, s.expr(n.Left))
 		default: // array
-			return s.constInt(Types[TINT], n.Left.Type.Bound)
+			return s.constInt(ir.Types[ir.TINT], n.Left.Type.Bound)
 		}
 
-	case OSPTR:
+	case ir.OSPTR:
 		a := s.expr(n.Left)
 		if n.Left.Type.IsSlice() {
 			return s.newValue1(ssa.OpSlicePtr, n.Type, a)
@@ -1984,11 +2028,11 @@ func (s *state) expr(n *Node) *ssa.Value {
 			return s.newValue1(ssa.OpStringPtr, n.Type, a)
 		}
 
-	case OITAB:
+	case ir.OITAB:
 		a := s.expr(n.Left)
 		return s.newValue1(ssa.OpITab, n.Type, a)
 
-	case OEFACE:
+	case ir.OEFACE:
 		tab := s.expr(n.Left)
 		data := s.expr(n.Right)
 		// The frontend allows putting things like struct{*byte} in
@@ -2019,7 +2063,7 @@ func (s *state) expr(n *Node) *ssa.Value {
 		}
 		return s.newValue2(ssa.OpIMake, n.Type, tab, data)
 
-	case OSLICE, OSLICEARR:
+	case ir.OSLICE, ir.OSLICEARR:
 		v := s.expr(n.Left)
 		var i, j *ssa.Value
 		if n.Right.Left != nil {
@@ -2030,7 +2074,7 @@ func (s *state) expr(n *Node) *ssa.Value {
 		}
 		p, l, c := s.slice(n.Left.Type, v, i, j, nil)
 		return s.newValue3(ssa.OpSliceMake, n.Type, p, l, c)
-	case OSLICESTR:
+	case ir.OSLICESTR:
 		v := s.expr(n.Left)
 		var i, j *ssa.Value
 		if n.Right.Left != nil {
@@ -2041,7 +2085,7 @@ func (s *state) expr(n *Node) *ssa.Value {
 		}
 		p, l, _ := s.slice(n.Left.Type, v, i, j, nil)
 		return s.newValue2(ssa.OpStringMake, n.Type, p, l)
-	case OSLICE3, OSLICE3ARR:
+	case ir.OSLICE3, ir.OSLICE3ARR:
 		v := s.expr(n.Left)
 		var i *ssa.Value
 		if n.Right.Left != nil {
@@ -2052,14 +2096,14 @@ func (s *state) expr(n *Node) *ssa.Value {
 		p, l, c := s.slice(n.Left.Type, v, i, j, k)
 		return s.newValue3(ssa.OpSliceMake, n.Type, p, l, c)
 
-	case OCALLFUNC, OCALLINTER, OCALLMETH:
+	case ir.OCALLFUNC, ir.OCALLINTER, ir.OCALLMETH:
 		a := s.call(n, callNormal)
 		return s.newValue2(ssa.OpLoad, n.Type, a, s.mem())
 
-	case OGETG:
+	case ir.OGETG:
 		return s.newValue1(ssa.OpGetG, n.Type, s.mem())
 
-	case OAPPEND:
+	case ir.OAPPEND:
 		// append(s, e1, e2, e3).  Compile like:
 		// ptr,len,cap := s
 		// newlen := len + 3
@@ -2084,10 +2128,10 @@ func (s *state) expr(n *Node) *ssa.Value {
 		// Decide if we need to grow
 		nargs := int64(n.List.Len() - 1)
 		p := s.newValue1(ssa.OpSlicePtr, pt, slice)
-		l := s.newValue1(ssa.OpSliceLen, Types[TINT], slice)
-		c := s.newValue1(ssa.OpSliceCap, Types[TINT], slice)
-		nl := s.newValue2(s.ssaOp(OADD, Types[TINT]), Types[TINT], l, s.constInt(Types[TINT], nargs))
-		cmp := s.newValue2(s.ssaOp(OGT, Types[TINT]), Types[TBOOL], nl, c)
+		l := s.newValue1(ssa.OpSliceLen, ir.Types[ir.TINT], slice)
+		c := s.newValue1(ssa.OpSliceCap, ir.Types[ir.TINT], slice)
+		nl := s.newValue2(s.ssaOp(ir.OADD, ir.Types[ir.TINT]), ir.Types[ir.TINT], l, s.constInt(ir.Types[ir.TINT], nargs))
+		cmp := s.newValue2(s.ssaOp(ir.OGT, ir.Types[ir.TINT]), ir.Types[ir.TBOOL], nl, c)
 		s.vars[&ptrVar] = p
 		s.vars[&capVar] = c
 		b := s.endBlock()
@@ -2099,9 +2143,9 @@ func (s *state) expr(n *Node) *ssa.Value {
 
 		// Call growslice
 		s.startBlock(grow)
-		taddr := s.newValue1A(ssa.OpAddr, Types[TUINTPTR], &ssa.ExternSymbol{Types[TUINTPTR], typenamesym(n.Type)}, s.sb)
+		taddr := s.newValue1A(ssa.OpAddr, ir.Types[ir.TUINTPTR], &ssa.ExternSymbol{ir.Types[ir.TUINTPTR], typenamesym(n.Type)}, s.sb)
 
-		r := s.rtcall(growslice, true, []*Type{pt, Types[TINT], Types[TINT]}, taddr, p, l, c, nl)
+		r := s.rtcall(growslice, true, []*ir.Type{pt, ir.Types[ir.TINT], ir.Types[ir.TINT]}, taddr, p, l, c, nl)
 
 		s.vars[&ptrVar] = r[0]
 		// Note: we don't need to read r[1], the result's length. It will be nl.
@@ -2126,13 +2170,13 @@ func (s *state) expr(n *Node) *ssa.Value {
 			}
 		}
 
-		p = s.variable(&ptrVar, pt)          // generates phi for ptr
-		c = s.variable(&capVar, Types[TINT]) // generates phi for cap
+		p = s.variable(&ptrVar, pt)                // generates phi for ptr
+		c = s.variable(&capVar, ir.Types[ir.TINT]) // generates phi for cap
 		p2 := s.newValue2(ssa.OpPtrIndex, pt, p, l)
 		// TODO: just one write barrier call for all of these writes?
 		// TODO: maybe just one writeBarrier.enabled check?
 		for i, arg := range args {
-			addr := s.newValue2(ssa.OpPtrIndex, pt, p2, s.constInt(Types[TINT], int64(i)))
+			addr := s.newValue2(ssa.OpPtrIndex, pt, p2, s.constInt(ir.Types[ir.TINT], int64(i)))
 			if store[i] {
 				if haspointers(et) {
 					s.insertWBstore(et, addr, arg, n.Lineno, 0)
@@ -2163,8 +2207,8 @@ func (s *state) expr(n *Node) *ssa.Value {
 // if cond is true and no if cond is false.
 // This function is intended to handle && and || better than just calling
 // s.expr(cond) and branching on the result.
-func (s *state) condBranch(cond *Node, yes, no *ssa.Block, likely int8) {
-	if cond.Op == OANDAND {
+func (s *state) condBranch(cond *ir.Node, yes, no *ssa.Block, likely int8) {
+	if cond.Op == ir.OANDAND {
 		mid := s.f.NewBlock(ssa.BlockPlain)
 		s.stmtList(cond.Ninit)
 		s.condBranch(cond.Left, mid, no, max8(likely, 0))
@@ -2178,7 +2222,7 @@ func (s *state) condBranch(cond *Node, yes, no *ssa.Block, likely int8) {
 		// TODO: have the frontend give us branch prediction hints for
 		// OANDAND and OOROR nodes (if it ever has such info).
 	}
-	if cond.Op == OOROR {
+	if cond.Op == ir.OOROR {
 		mid := s.f.NewBlock(ssa.BlockPlain)
 		s.stmtList(cond.Ninit)
 		s.condBranch(cond.Left, yes, mid, min8(likely, 0))
@@ -2189,7 +2233,7 @@ func (s *state) condBranch(cond *Node, yes, no *ssa.Block, likely int8) {
 		// If likely==1, then we don't have enough info to decide
 		// the likelihood of the first branch.
 	}
-	if cond.Op == ONOT {
+	if cond.Op == ir.ONOT {
 		s.stmtList(cond.Ninit)
 		s.condBranch(cond.Left, no, yes, -likely)
 		return
@@ -2217,8 +2261,8 @@ const (
 // If deref is true and right == nil, just do left = 0.
 // Include a write barrier if wb is true.
 // skip indicates assignments (at the top level) that can be avoided.
-func (s *state) assign(left *Node, right *ssa.Value, wb, deref bool, line int32, skip skipMask) {
-	if left.Op == ONAME && isblank(left) {
+func (s *state) assign(left *ir.Node, right *ssa.Value, wb, deref bool, line int32, skip skipMask) {
+	if left.Op == ir.ONAME && isblank(left) {
 		return
 	}
 	t := left.Type
@@ -2227,7 +2271,7 @@ func (s *state) assign(left *Node, right *ssa.Value, wb, deref bool, line int32,
 		if deref {
 			s.Fatalf("can SSA LHS %s but not RHS %s", left, right)
 		}
-		if left.Op == ODOT {
+		if left.Op == ir.ODOT {
 			// We're assigning to a field of an ssa-able value.
 			// We need to build a new structure with the new value for the
 			// field we're assigning and the old values for the other fields.
@@ -2269,7 +2313,10 @@ func (s *state) assign(left *Node, right *ssa.Value, wb, deref bool, line int32,
 	}
 	// Left is not ssa-able. Compute its address.
 	addr := s.addr(left, false)
-	if left.Op == ONAME {
+	if s.wbnode != nil && skip&skipPtr == 0 {
+		s.wbstores = append(s.wbstores, wbstore{s.wbnode, t, addr, right, deref, wb})
+	}
+	if left.Op == ir.ONAME {
 		s.vars[&memVar] = s.newValue1A(ssa.OpVarDef, ssa.TypeMem, left, s.mem())
 	}
 	if deref {
@@ -2307,7 +2354,7 @@ func (s *state) assign(left *Node, right *ssa.Value, wb, deref bool, line int32,
 }
 
 // zeroVal returns the zero value for type t.
-func (s *state) zeroVal(t *Type) *ssa.Value {
+func (s *state) zeroVal(t *ir.Type) *ssa.Value {
 	switch {
 	case t.IsInteger():
 		switch t.Size() {
@@ -2334,10 +2381,10 @@ func (s *state) zeroVal(t *Type) *ssa.Value {
 	case t.IsComplex():
 		switch t.Size() {
 		case 8:
-			z := s.constFloat32(Types[TFLOAT32], 0)
+			z := s.constFloat32(ir.Types[ir.TFLOAT32], 0)
 			return s.entryNewValue2(ssa.OpComplexMake, t, z, z)
 		case 16:
-			z := s.constFloat64(Types[TFLOAT64], 0)
+			z := s.constFloat64(ir.Types[ir.TFLOAT64], 0)
 			return s.entryNewValue2(ssa.OpComplexMake, t, z, z)
 		default:
 			s.Fatalf("bad sized complex type %s", t)
@@ -2357,7 +2404,7 @@ func (s *state) zeroVal(t *Type) *ssa.Value {
 		n := t.NumFields()
 		v := s.entryNewValue0(ssa.StructMakeOp(t.NumFields()), t)
 		for i := 0; i < n; i++ {
-			v.AddArg(s.zeroVal(t.FieldType(i).(*Type)))
+			v.AddArg(s.zeroVal(t.FieldType(i).(*ir.Type)))
 		}
 		return v
 	}
@@ -2375,21 +2422,21 @@ const (
 
 // Calls the function n using the specified call type.
 // Returns the address of the return value (or nil if none).
-func (s *state) call(n *Node, k callKind) *ssa.Value {
-	var sym *Sym           // target symbol (if static)
+func (s *state) call(n *ir.Node, k callKind) *ssa.Value {
+	var sym *ir.Sym        // target symbol (if static)
 	var closure *ssa.Value // ptr to closure to run (if dynamic)
 	var codeptr *ssa.Value // ptr to target code (if dynamic)
 	var rcvr *ssa.Value    // receiver to set
 	fn := n.Left
 	switch n.Op {
-	case OCALLFUNC:
-		if k == callNormal && fn.Op == ONAME && fn.Class == PFUNC {
+	case ir.OCALLFUNC:
+		if k == callNormal && fn.Op == ir.ONAME && fn.Class == ir.PFUNC {
 			sym = fn.Sym
 			break
 		}
 		closure = s.expr(fn)
-	case OCALLMETH:
-		if fn.Op != ODOTMETH {
+	case ir.OCALLMETH:
+		if fn.Op != ir.ODOTMETH {
 			Fatalf("OCALLMETH: n.Left not an ODOTMETH: %v", fn)
 		}
 		if k == callNormal {
@@ -2397,25 +2444,25 @@ func (s *state) call(n *Node, k callKind) *ssa.Value {
 			break
 		}
 		n2 := newname(fn.Sym)
-		n2.Class = PFUNC
+		n2.Class = ir.PFUNC
 		n2.Lineno = fn.Lineno
 		closure = s.expr(n2)
 		// Note: receiver is already assigned in n.List, so we don't
 		// want to set it here.
-	case OCALLINTER:
-		if fn.Op != ODOTINTER {
+	case ir.OCALLINTER:
+		if fn.Op != ir.ODOTINTER {
 			Fatalf("OCALLINTER: n.Left not an ODOTINTER: %v", Oconv(fn.Op, 0))
 		}
 		i := s.expr(fn.Left)
-		itab := s.newValue1(ssa.OpITab, Types[TUINTPTR], i)
+		itab := s.newValue1(ssa.OpITab, ir.Types[ir.TUINTPTR], i)
 		itabidx := fn.Xoffset + 3*int64(Widthptr) + 8 // offset of fun field in runtime.itab
-		itab = s.newValue1I(ssa.OpOffPtr, Types[TUINTPTR], itabidx, itab)
+		itab = s.newValue1I(ssa.OpOffPtr, ir.Types[ir.TUINTPTR], itabidx, itab)
 		if k == callNormal {
-			codeptr = s.newValue2(ssa.OpLoad, Types[TUINTPTR], itab, s.mem())
+			codeptr = s.newValue2(ssa.OpLoad, ir.Types[ir.TUINTPTR], itab, s.mem())
 		} else {
 			closure = itab
 		}
-		rcvr = s.newValue1(ssa.OpIData, Types[TUINTPTR], i)
+		rcvr = s.newValue1(ssa.OpIData, ir.Types[ir.TUINTPTR], i)
 	}
 	dowidth(fn.Type)
 	stksize := fn.Type.Argwid // includes receiver
@@ -2432,16 +2479,22 @@ func (s *state) call(n *Node, k callKind) *ssa.Value {
 		if k != callNormal {
 			argStart += int64(2 * Widthptr)
 		}
-		addr := s.entryNewValue1I(ssa.OpOffPtr, Types[TUINTPTR], argStart, s.sp)
+		addr := s.entryNewValue1I(ssa.OpOffPtr, ir.Types[ir.TUINTPTR], argStart, s.sp)
 		s.vars[&memVar] = s.newValue3I(ssa.OpStore, ssa.TypeMem, int64(Widthptr), addr, rcvr, s.mem())
 	}
 
 	// Defer/go args
-	if k != callNormal {
+	deferproc := Deferproc
+	if k == callDefer && stksize == 0 {
+		// Write just the closure (arg to Deferproc0).
+		deferproc = Deferproc0
+		s.vars[&memVar] = s.newValue3I(ssa.OpStore, ssa.TypeMem, int64(Widthptr), s.sp, closure, s.mem())
+		stksize += int64(Widthptr)
+	} else if k != callNormal {
 		// Write argsize and closure (args to Newproc/Deferproc).
-		argsize := s.constInt32(Types[TUINT32], int32(stksize))
+		argsize := s.constInt32(ir.Types[ir.TUINT32], int32(stksize))
 		s.vars[&memVar] = s.newValue3I(ssa.OpStore, ssa.TypeMem, 4, s.sp, argsize, s.mem())
-		addr := s.entryNewValue1I(ssa.OpOffPtr, Ptrto(Types[TUINTPTR]), int64(Widthptr), s.sp)
+		addr := s.entryNewValue1I(ssa.OpOffPtr, Ptrto(ir.Types[ir.TUINTPTR]), int64(Widthptr), s.sp)
 		s.vars[&memVar] = s.newValue3I(ssa.OpStore, ssa.TypeMem, int64(Widthptr), addr, closure, s.mem())
 		stksize += 2 * int64(Widthptr)
 	}
@@ -2451,11 +2504,11 @@ func (s *state) call(n *Node, k callKind) *ssa.Value {
 	var call *ssa.Value
 	switch {
 	case k == callDefer:
-		call = s.newValue1(ssa.OpDeferCall, ssa.TypeMem, s.mem())
+		call = s.newValue1A(ssa.OpDeferCall, ssa.TypeMem, deferproc.Sym, s.mem())
 	case k == callGo:
 		call = s.newValue1(ssa.OpGoCall, ssa.TypeMem, s.mem())
 	case closure != nil:
-		codeptr = s.newValue2(ssa.OpLoad, Types[TUINTPTR], closure, s.mem())
+		codeptr = s.newValue2(ssa.OpLoad, ir.Types[ir.TUINTPTR], closure, s.mem())
 		call = s.newValue3(ssa.OpClosureCall, ssa.TypeMem, codeptr, closure, s.mem())
 	case codeptr != nil:
 		call = s.newValue2(ssa.OpInterCall, ssa.TypeMem, codeptr, s.mem())
@@ -2495,11 +2548,11 @@ func (s *state) call(n *Node, k callKind) *ssa.Value {
 
 // etypesign returns the signed-ness of e, for integer/pointer etypes.
 // -1 means signed, +1 means unsigned, 0 means non-integer/non-pointer.
-func etypesign(e EType) int8 {
+func etypesign(e ir.EType) int8 {
 	switch e {
-	case TINT8, TINT16, TINT32, TINT64, TINT:
+	case ir.TINT8, ir.TINT16, ir.TINT32, ir.TINT64, ir.TINT:
 		return -1
-	case TUINT8, TUINT16, TUINT32, TUINT64, TUINT, TUINTPTR, TUNSAFEPTR:
+	case ir.TUINT8, ir.TUINT16, ir.TUINT32, ir.TUINT64, ir.TUINT, ir.TUINTPTR, ir.TUNSAFEPTR:
 		return +1
 	}
 	return 0
@@ -2508,7 +2561,7 @@ func etypesign(e EType) int8 {
 // lookupSymbol is used to retrieve the symbol (Extern, Arg or Auto) used for a particular node.
 // This improves the effectiveness of cse by using the same Aux values for the
 // same symbols.
-func (s *state) lookupSymbol(n *Node, sym interface{}) interface{} {
+func (s *state) lookupSymbol(n *ir.Node, sym interface{}) interface{} {
 	switch sym.(type) {
 	default:
 		s.Fatalf("sym %v is of uknown type %T", sym, sym)
@@ -2528,12 +2581,12 @@ func (s *state) lookupSymbol(n *Node, sym interface{}) interface{} {
 // The value that the returned Value represents is guaranteed to be non-nil.
 // If bounded is true then this address does not require a nil check for its operand
 // even if that would otherwise be implied.
-func (s *state) addr(n *Node, bounded bool) *ssa.Value {
+func (s *state) addr(n *ir.Node, bounded bool) *ssa.Value {
 	t := Ptrto(n.Type)
 	switch n.Op {
-	case ONAME:
+	case ir.ONAME:
 		switch n.Class {
-		case PEXTERN:
+		case ir.PEXTERN:
 			// global variable
 			aux := s.lookupSymbol(n, &ssa.ExternSymbol{n.Type, n.Sym})
 			v := s.entryNewValue1A(ssa.OpAddr, t, aux, s.sb)
@@ -2542,7 +2595,7 @@ func (s *state) addr(n *Node, bounded bool) *ssa.Value {
 				v = s.entryNewValue1I(ssa.OpOffPtr, v.Type, n.Xoffset, v)
 			}
 			return v
-		case PPARAM:
+		case ir.PPARAM:
 			// parameter slot
 			v := s.decladdrs[n]
 			if v != nil {
@@ -2556,21 +2609,21 @@ func (s *state) addr(n *Node, bounded bool) *ssa.Value {
 			}
 			s.Fatalf("addr of undeclared ONAME %v. declared: %v", n, s.decladdrs)
 			return nil
-		case PAUTO:
+		case ir.PAUTO:
 			aux := s.lookupSymbol(n, &ssa.AutoSymbol{Typ: n.Type, Node: n})
 			return s.newValue1A(ssa.OpAddr, t, aux, s.sp)
-		case PPARAMOUT: // Same as PAUTO -- cannot generate LEA early.
+		case ir.PPARAMOUT: // Same as PAUTO -- cannot generate LEA early.
 			// ensure that we reuse symbols for out parameters so
 			// that cse works on their addresses
 			aux := s.lookupSymbol(n, &ssa.ArgSymbol{Typ: n.Type, Node: n})
 			return s.newValue1A(ssa.OpAddr, t, aux, s.sp)
-		case PAUTO | PHEAP, PPARAM | PHEAP, PPARAMOUT | PHEAP, PPARAMREF:
+		case ir.PAUTO | ir.PHEAP, ir.PPARAM | ir.PHEAP, ir.PPARAMOUT | ir.PHEAP, ir.PPARAMREF:
 			return s.expr(n.Name.Heapaddr)
 		default:
 			s.Unimplementedf("variable address class %v not implemented", n.Class)
 			return nil
 		}
-	case OINDREG:
+	case ir.OINDREG:
 		// indirect off a register
 		// used for storing/loading arguments/returns to/from callees
 		if int(n.Reg) != Thearch.REGSP {
@@ -2578,12 +2631,12 @@ func (s *state) addr(n *Node, bounded bool) *ssa.Value {
 			return nil
 		}
 		return s.entryNewValue1I(ssa.OpOffPtr, t, n.Xoffset, s.sp)
-	case OINDEX:
+	case ir.OINDEX:
 		if n.Left.Type.IsSlice() {
 			a := s.expr(n.Left)
 			i := s.expr(n.Right)
 			i = s.extendIndex(i)
-			len := s.newValue1(ssa.OpSliceLen, Types[TINT], a)
+			len := s.newValue1(ssa.OpSliceLen, ir.Types[ir.TINT], a)
 			if !n.Bounded {
 				s.boundsCheck(i, len)
 			}
@@ -2593,33 +2646,33 @@ func (s *state) addr(n *Node, bounded bool) *ssa.Value {
 			a := s.addr(n.Left, bounded)
 			i := s.expr(n.Right)
 			i = s.extendIndex(i)
-			len := s.constInt(Types[TINT], n.Left.Type.Bound)
+			len := s.constInt(ir.Types[ir.TINT], n.Left.Type.Bound)
 			if !n.Bounded {
 				s.boundsCheck(i, len)
 			}
 			return s.newValue2(ssa.OpPtrIndex, Ptrto(n.Left.Type.Type), a, i)
 		}
-	case OIND:
+	case ir.OIND:
 		p := s.expr(n.Left)
 		if !bounded {
 			s.nilCheck(p)
 		}
 		return p
-	case ODOT:
+	case ir.ODOT:
 		p := s.addr(n.Left, bounded)
 		return s.newValue1I(ssa.OpOffPtr, t, n.Xoffset, p)
-	case ODOTPTR:
+	case ir.ODOTPTR:
 		p := s.expr(n.Left)
 		if !bounded {
 			s.nilCheck(p)
 		}
 		return s.newValue1I(ssa.OpOffPtr, t, n.Xoffset, p)
-	case OCLOSUREVAR:
+	case ir.OCLOSUREVAR:
 		return s.newValue1I(ssa.OpOffPtr, t, n.Xoffset,
-			s.entryNewValue0(ssa.OpGetClosurePtr, Ptrto(Types[TUINT8])))
-	case OPARAM:
+			s.entryNewValue0(ssa.OpGetClosurePtr, Ptrto(ir.Types[ir.TUINT8])))
+	case ir.OPARAM:
 		p := n.Left
-		if p.Op != ONAME || !(p.Class == PPARAM|PHEAP || p.Class == PPARAMOUT|PHEAP) {
+		if p.Op != ir.ONAME || !(p.Class == ir.PPARAM|ir.PHEAP || p.Class == ir.PPARAMOUT|ir.PHEAP) {
 			s.Fatalf("OPARAM not of ONAME,{PPARAM,PPARAMOUT}|PHEAP, instead %s", nodedump(p, 0))
 		}
 
@@ -2628,10 +2681,10 @@ func (s *state) addr(n *Node, bounded bool) *ssa.Value {
 		original_p.Xoffset = n.Xoffset
 		aux := &ssa.ArgSymbol{Typ: n.Type, Node: &original_p}
 		return s.entryNewValue1A(ssa.OpAddr, t, aux, s.sp)
-	case OCONVNOP:
+	case ir.OCONVNOP:
 		addr := s.addr(n.Left, bounded)
 		return s.newValue1(ssa.OpCopy, t, addr) // ensure that addr has the right type
-	case OCALLFUNC, OCALLINTER, OCALLMETH:
+	case ir.OCALLFUNC, ir.OCALLINTER, ir.OCALLMETH:
 		return s.call(n, callNormal)
 
 	default:
@@ -2642,24 +2695,24 @@ func (s *state) addr(n *Node, bounded bool) *ssa.Value {
 
 // canSSA reports whether n is SSA-able.
 // n must be an ONAME (or an ODOT sequence with an ONAME base).
-func (s *state) canSSA(n *Node) bool {
-	for n.Op == ODOT {
+func (s *state) canSSA(n *ir.Node) bool {
+	for n.Op == ir.ODOT {
 		n = n.Left
 	}
-	if n.Op != ONAME {
+	if n.Op != ir.ONAME {
 		return false
 	}
 	if n.Addrtaken {
 		return false
 	}
-	if n.Class&PHEAP != 0 {
+	if n.Class&ir.PHEAP != 0 {
 		return false
 	}
 	switch n.Class {
-	case PEXTERN, PPARAMREF:
+	case ir.PEXTERN, ir.PPARAMREF:
 		// TODO: maybe treat PPARAMREF with an Arg-like op to read from closure?
 		return false
-	case PPARAMOUT:
+	case ir.PPARAMOUT:
 		if hasdefer {
 			// TODO: handle this case?  Named return values must be
 			// in memory so that the deferred function can see them.
@@ -2672,7 +2725,7 @@ func (s *state) canSSA(n *Node) bool {
 			return false
 		}
 	}
-	if n.Class == PPARAM && n.String() == ".this" {
+	if n.Class == ir.PPARAM && n.String() == ".this" {
 		// wrappers generated by genwrapper need to update
 		// the .this pointer in place.
 		// TODO: treat as a PPARMOUT?
@@ -2683,8 +2736,8 @@ func (s *state) canSSA(n *Node) bool {
 }
 
 // canSSA reports whether variables of type t are SSA-able.
-func canSSAType(t *Type) bool {
-	dowidth(t)
+func canSSAType(t *ir.Type) bool {
+	backendDowidth(t)
 	if t.Width > int64(4*Widthptr) {
 		// 4*Widthptr is an arbitrary constant. We want it
 		// to be at least 3*Widthptr so slices can be registerized.
@@ -2692,7 +2745,7 @@ func canSSAType(t *Type) bool {
 		return false
 	}
 	switch t.Etype {
-	case TARRAY:
+	case ir.TARRAY:
 		if Isslice(t) {
 			return true
 		}
@@ -2702,7 +2755,7 @@ func canSSAType(t *Type) bool {
 		// are constant?  Might be good for the arrays
 		// introduced by the compiler for variadic functions.
 		return false
-	case TSTRUCT:
+	case ir.TSTRUCT:
 		if t.NumFields() > ssa.MaxStruct {
 			return false
 		}
@@ -2744,7 +2797,7 @@ func (s *state) boundsCheck(idx, len *ssa.Value) {
 	// TODO: if index is 64-bit and we're compiling to 32-bit, check that high 32 bits are zero.
 
 	// bounds check
-	cmp := s.newValue2(ssa.OpIsInBounds, Types[TBOOL], idx, len)
+	cmp := s.newValue2(ssa.OpIsInBounds, ir.Types[ir.TBOOL], idx, len)
 	s.check(cmp, Panicindex)
 }
 
@@ -2758,12 +2811,12 @@ func (s *state) sliceBoundsCheck(idx, len *ssa.Value) {
 	// TODO: if index is 64-bit and we're compiling to 32-bit, check that high 32 bits are zero.
 
 	// bounds check
-	cmp := s.newValue2(ssa.OpIsSliceInBounds, Types[TBOOL], idx, len)
+	cmp := s.newValue2(ssa.OpIsSliceInBounds, ir.Types[ir.TBOOL], idx, len)
 	s.check(cmp, panicslice)
 }
 
 // If cmp (a bool) is true, panic using the given function.
-func (s *state) check(cmp *ssa.Value, fn *Node) {
+func (s *state) check(cmp *ssa.Value, fn *ir.Node) {
 	b := s.endBlock()
 	b.Kind = ssa.BlockIf
 	b.SetControl(cmp)
@@ -2790,7 +2843,7 @@ func (s *state) check(cmp *ssa.Value, fn *Node) {
 // If returns is false, the block is marked as an exit block.
 // If returns is true, the block is marked as a call block. A new block
 // is started to load the return values.
-func (s *state) rtcall(fn *Node, returns bool, results []*Type, args ...*ssa.Value) []*ssa.Value {
+func (s *state) rtcall(fn *ir.Node, returns bool, results []*ir.Type, args ...*ssa.Value) []*ssa.Value {
 	// Write args to the stack
 	var off int64 // TODO: arch-dependent starting offset?
 	for _, arg := range args {
@@ -2798,7 +2851,7 @@ func (s *state) rtcall(fn *Node, returns bool, results []*Type, args ...*ssa.Val
 		off = Rnd(off, t.Alignment())
 		ptr := s.sp
 		if off != 0 {
-			ptr = s.newValue1I(ssa.OpOffPtr, Types[TUINTPTR], off, s.sp)
+			ptr = s.newValue1I(ssa.OpOffPtr, ir.Types[ir.TUINTPTR], off, s.sp)
 		}
 		size := t.Size()
 		s.vars[&memVar] = s.newValue3I(ssa.OpStore, ssa.TypeMem, size, ptr, arg, s.mem())
@@ -2833,7 +2886,7 @@ func (s *state) rtcall(fn *Node, returns bool, results []*Type, args ...*ssa.Val
 		off = Rnd(off, t.Alignment())
 		ptr := s.sp
 		if off != 0 {
-			ptr = s.newValue1I(ssa.OpOffPtr, Types[TUINTPTR], off, s.sp)
+			ptr = s.newValue1I(ssa.OpOffPtr, ir.Types[ir.TUINTPTR], off, s.sp)
 		}
 		res[i] = s.newValue2(ssa.OpLoad, t, ptr, s.mem())
 		off += t.Size()
@@ -2848,7 +2901,7 @@ func (s *state) rtcall(fn *Node, returns bool, results []*Type, args ...*ssa.Val
 
 // insertWBmove inserts the assignment *left = *right including a write barrier.
 // t is the type being assigned.
-func (s *state) insertWBmove(t *Type, left, right *ssa.Value, line int32) {
+func (s *state) insertWBmove(t *ir.Type, left, right *ssa.Value, line int32) {
 	// if writeBarrier.enabled {
 	//   typedmemmove(&t, left, right)
 	// } else {
@@ -2865,12 +2918,12 @@ func (s *state) insertWBmove(t *Type, left, right *ssa.Value, line int32) {
 	bElse := s.f.NewBlock(ssa.BlockPlain)
 	bEnd := s.f.NewBlock(ssa.BlockPlain)
 
-	aux := &ssa.ExternSymbol{Types[TBOOL], syslook("writeBarrier").Sym}
-	flagaddr := s.newValue1A(ssa.OpAddr, Ptrto(Types[TUINT32]), aux, s.sb)
+	aux := &ssa.ExternSymbol{ir.Types[ir.TBOOL], syslook("writeBarrier").Sym}
+	flagaddr := s.newValue1A(ssa.OpAddr, Ptrto(ir.Types[ir.TUINT32]), aux, s.sb)
 	// TODO: select the .enabled field. It is currently first, so not needed for now.
 	// Load word, test byte, avoiding partial register write from load byte.
-	flag := s.newValue2(ssa.OpLoad, Types[TUINT32], flagaddr, s.mem())
-	flag = s.newValue1(ssa.OpTrunc64to8, Types[TBOOL], flag)
+	flag := s.newValue2(ssa.OpLoad, ir.Types[ir.TUINT32], flagaddr, s.mem())
+	flag = s.newValue1(ssa.OpTrunc64to8, ir.Types[ir.TBOOL], flag)
 	b := s.endBlock()
 	b.Kind = ssa.BlockIf
 	b.Likely = ssa.BranchUnlikely
@@ -2879,7 +2932,7 @@ func (s *state) insertWBmove(t *Type, left, right *ssa.Value, line int32) {
 	b.AddEdgeTo(bElse)
 
 	s.startBlock(bThen)
-	taddr := s.newValue1A(ssa.OpAddr, Types[TUINTPTR], &ssa.ExternSymbol{Types[TUINTPTR], typenamesym(t)}, s.sb)
+	taddr := s.newValue1A(ssa.OpAddr, ir.Types[ir.TUINTPTR], &ssa.ExternSymbol{ir.Types[ir.TUINTPTR], typenamesym(t)}, s.sb)
 	s.rtcall(typedmemmove, true, nil, taddr, left, right)
 	s.endBlock().AddEdgeTo(bEnd)
 
@@ -2896,7 +2949,7 @@ func (s *state) insertWBmove(t *Type, left, right *ssa.Value, line int32) {
 
 // insertWBstore inserts the assignment *left = right including a write barrier.
 // t is the type being assigned.
-func (s *state) insertWBstore(t *Type, left, right *ssa.Value, line int32, skip skipMask) {
+func (s *state) insertWBstore(t *ir.Type, left, right *ssa.Value, line int32, skip skipMask) {
 	// store scalar fields
 	// if writeBarrier.enabled {
 	//   writebarrierptr for pointer fields
@@ -2916,12 +2969,12 @@ func (s *state) insertWBstore(t *Type, left, right *ssa.Value, line int32, skip
 	bElse := s.f.NewBlock(ssa.BlockPlain)
 	bEnd := s.f.NewBlock(ssa.BlockPlain)
 
-	aux := &ssa.ExternSymbol{Types[TBOOL], syslook("writeBarrier").Sym}
-	flagaddr := s.newValue1A(ssa.OpAddr, Ptrto(Types[TUINT32]), aux, s.sb)
+	aux := &ssa.ExternSymbol{ir.Types[ir.TBOOL], syslook("writeBarrier").Sym}
+	flagaddr := s.newValue1A(ssa.OpAddr, Ptrto(ir.Types[ir.TUINT32]), aux, s.sb)
 	// TODO: select the .enabled field. It is currently first, so not needed for now.
 	// Load word, test byte, avoiding partial register write from load byte.
-	flag := s.newValue2(ssa.OpLoad, Types[TUINT32], flagaddr, s.mem())
-	flag = s.newValue1(ssa.OpTrunc64to8, Types[TBOOL], flag)
+	flag := s.newValue2(ssa.OpLoad, ir.Types[ir.TUINT32], flagaddr, s.mem())
+	flag = s.newValue1(ssa.OpTrunc64to8, ir.Types[ir.TBOOL], flag)
 	b := s.endBlock()
 	b.Kind = ssa.BlockIf
 	b.Likely = ssa.BranchUnlikely
@@ -2947,7 +3000,7 @@ func (s *state) insertWBstore(t *Type, left, right *ssa.Value, line int32, skip
 }
 
 // do *left = right for all scalar (non-pointer) parts of t.
-func (s *state) storeTypeScalars(t *Type, left, right *ssa.Value, skip skipMask) {
+func (s *state) storeTypeScalars(t *ir.Type, left, right *ssa.Value, skip skipMask) {
 	switch {
 	case t.IsBoolean() || t.IsInteger() || t.IsFloat() || t.IsComplex():
 		s.vars[&memVar] = s.newValue3I(ssa.OpStore, ssa.TypeMem, t.Size(), left, right, s.mem())
@@ -2957,23 +3010,23 @@ func (s *state) storeTypeScalars(t *Type, left, right *ssa.Value, skip skipMask)
 		if skip&skipLen != 0 {
 			return
 		}
-		len := s.newValue1(ssa.OpStringLen, Types[TINT], right)
-		lenAddr := s.newValue1I(ssa.OpOffPtr, Ptrto(Types[TINT]), s.config.IntSize, left)
+		len := s.newValue1(ssa.OpStringLen, ir.Types[ir.TINT], right)
+		lenAddr := s.newValue1I(ssa.OpOffPtr, Ptrto(ir.Types[ir.TINT]), s.config.IntSize, left)
 		s.vars[&memVar] = s.newValue3I(ssa.OpStore, ssa.TypeMem, s.config.IntSize, lenAddr, len, s.mem())
 	case t.IsSlice():
 		if skip&skipLen == 0 {
-			len := s.newValue1(ssa.OpSliceLen, Types[TINT], right)
-			lenAddr := s.newValue1I(ssa.OpOffPtr, Ptrto(Types[TINT]), s.config.IntSize, left)
+			len := s.newValue1(ssa.OpSliceLen, ir.Types[ir.TINT], right)
+			lenAddr := s.newValue1I(ssa.OpOffPtr, Ptrto(ir.Types[ir.TINT]), s.config.IntSize, left)
 			s.vars[&memVar] = s.newValue3I(ssa.OpStore, ssa.TypeMem, s.config.IntSize, lenAddr, len, s.mem())
 		}
 		if skip&skipCap == 0 {
-			cap := s.newValue1(ssa.OpSliceCap, Types[TINT], right)
-			capAddr := s.newValue1I(ssa.OpOffPtr, Ptrto(Types[TINT]), 2*s.config.IntSize, left)
+			cap := s.newValue1(ssa.OpSliceCap, ir.Types[ir.TINT], right)
+			capAddr := s.newValue1I(ssa.OpOffPtr, Ptrto(ir.Types[ir.TINT]), 2*s.config.IntSize, left)
 			s.vars[&memVar] = s.newValue3I(ssa.OpStore, ssa.TypeMem, s.config.IntSize, capAddr, cap, s.mem())
 		}
 	case t.IsInterface():
 		// itab field doesn't need a write barrier (even though it is a pointer).
-		itab := s.newValue1(ssa.OpITab, Ptrto(Types[TUINT8]), right)
+		itab := s.newValue1(ssa.OpITab, Ptrto(ir.Types[ir.TUINT8]), right)
 		s.vars[&memVar] = s.newValue3I(ssa.OpStore, ssa.TypeMem, s.config.IntSize, left, itab, s.mem())
 	case t.IsStruct():
 		n := t.NumFields()
@@ -2981,7 +3034,7 @@ func (s *state) storeTypeScalars(t *Type, left, right *ssa.Value, skip skipMask)
 			ft := t.FieldType(i)
 			addr := s.newValue1I(ssa.OpOffPtr, ft.PtrTo(), t.FieldOff(i), left)
 			val := s.newValue1I(ssa.OpStructSelect, ft, int64(i), right)
-			s.storeTypeScalars(ft.(*Type), addr, val, 0)
+			s.storeTypeScalars(ft.(*ir.Type), addr, val, 0)
 		}
 	default:
 		s.Fatalf("bad write barrier type %s", t)
@@ -2989,31 +3042,31 @@ func (s *state) storeTypeScalars(t *Type, left, right *ssa.Value, skip skipMask)
 }
 
 // do *left = right for all pointer parts of t.
-func (s *state) storeTypePtrs(t *Type, left, right *ssa.Value) {
+func (s *state) storeTypePtrs(t *ir.Type, left, right *ssa.Value) {
 	switch {
 	case t.IsPtr() || t.IsMap() || t.IsChan():
 		s.vars[&memVar] = s.newValue3I(ssa.OpStore, ssa.TypeMem, s.config.PtrSize, left, right, s.mem())
 	case t.IsString():
-		ptr := s.newValue1(ssa.OpStringPtr, Ptrto(Types[TUINT8]), right)
+		ptr := s.newValue1(ssa.OpStringPtr, Ptrto(ir.Types[ir.TUINT8]), right)
 		s.vars[&memVar] = s.newValue3I(ssa.OpStore, ssa.TypeMem, s.config.PtrSize, left, ptr, s.mem())
 	case t.IsSlice():
-		ptr := s.newValue1(ssa.OpSlicePtr, Ptrto(Types[TUINT8]), right)
+		ptr := s.newValue1(ssa.OpSlicePtr, Ptrto(ir.Types[ir.TUINT8]), right)
 		s.vars[&memVar] = s.newValue3I(ssa.OpStore, ssa.TypeMem, s.config.PtrSize, left, ptr, s.mem())
 	case t.IsInterface():
 		// itab field is treated as a scalar.
-		idata := s.newValue1(ssa.OpIData, Ptrto(Types[TUINT8]), right)
-		idataAddr := s.newValue1I(ssa.OpOffPtr, Ptrto(Types[TUINT8]), s.config.PtrSize, left)
+		idata := s.newValue1(ssa.OpIData, Ptrto(ir.Types[ir.TUINT8]), right)
+		idataAddr := s.newValue1I(ssa.OpOffPtr, Ptrto(ir.Types[ir.TUINT8]), s.config.PtrSize, left)
 		s.vars[&memVar] = s.newValue3I(ssa.OpStore, ssa.TypeMem, s.config.PtrSize, idataAddr, idata, s.mem())
 	case t.IsStruct():
 		n := t.NumFields()
 		for i := 0; i < n; i++ {
 			ft := t.FieldType(i)
-			if !haspointers(ft.(*Type)) {
+			if !haspointers(ft.(*ir.Type)) {
 				continue
 			}
 			addr := s.newValue1I(ssa.OpOffPtr, ft.PtrTo(), t.FieldOff(i), left)
 			val := s.newValue1I(ssa.OpStructSelect, ft, int64(i), right)
-			s.storeTypePtrs(ft.(*Type), addr, val)
+			s.storeTypePtrs(ft.(*ir.Type), addr, val)
 		}
 	default:
 		s.Fatalf("bad write barrier type %s", t)
@@ -3021,30 +3074,30 @@ func (s *state) storeTypePtrs(t *Type, left, right *ssa.Value) {
 }
 
 // do *left = right with a write barrier for all pointer parts of t.
-func (s *state) storeTypePtrsWB(t *Type, left, right *ssa.Value) {
+func (s *state) storeTypePtrsWB(t *ir.Type, left, right *ssa.Value) {
 	switch {
 	case t.IsPtr() || t.IsMap() || t.IsChan():
 		s.rtcall(writebarrierptr, true, nil, left, right)
 	case t.IsString():
-		ptr := s.newValue1(ssa.OpStringPtr, Ptrto(Types[TUINT8]), right)
+		ptr := s.newValue1(ssa.OpStringPtr, Ptrto(ir.Types[ir.TUINT8]), right)
 		s.rtcall(writebarrierptr, true, nil, left, ptr)
 	case t.IsSlice():
-		ptr := s.newValue1(ssa.OpSlicePtr, Ptrto(Types[TUINT8]), right)
+		ptr := s.newValue1(ssa.OpSlicePtr, Ptrto(ir.Types[ir.TUINT8]), right)
 		s.rtcall(writebarrierptr, true, nil, left, ptr)
 	case t.IsInterface():
-		idata := s.newValue1(ssa.OpIData, Ptrto(Types[TUINT8]), right)
-		idataAddr := s.newValue1I(ssa.OpOffPtr, Ptrto(Types[TUINT8]), s.config.PtrSize, left)
+		idata := s.newValue1(ssa.OpIData, Ptrto(ir.Types[ir.TUINT8]), right)
+		idataAddr := s.newValue1I(ssa.OpOffPtr, Ptrto(ir.Types[ir.TUINT8]), s.config.PtrSize, left)
 		s.rtcall(writebarrierptr, true, nil, idataAddr, idata)
 	case t.IsStruct():
 		n := t.NumFields()
 		for i := 0; i < n; i++ {
 			ft := t.FieldType(i)
-			if !haspointers(ft.(*Type)) {
+			if !haspointers(ft.(*ir.Type)) {
 				continue
 			}
 			addr := s.newValue1I(ssa.OpOffPtr, ft.PtrTo(), t.FieldOff(i), left)
 			val := s.newValue1I(ssa.OpStructSelect, ft, int64(i), right)
-			s.storeTypePtrsWB(ft.(*Type), addr, val)
+			s.storeTypePtrsWB(ft.(*ir.Type), addr, val)
 		}
 	default:
 		s.Fatalf("bad write barrier type %s", t)
@@ -3054,25 +3107,25 @@ func (s *state) storeTypePtrsWB(t *Type, left, right *ssa.Value) {
 // slice computes the slice v[i:j:k] and returns ptr, len, and cap of result.
 // i,j,k may be nil, in which case they are set to their default value.
 // t is a slice, ptr to array, or string type.
-func (s *state) slice(t *Type, v, i, j, k *ssa.Value) (p, l, c *ssa.Value) {
-	var elemtype *Type
-	var ptrtype *Type
+func (s *state) slice(t *ir.Type, v, i, j, k *ssa.Value) (p, l, c *ssa.Value) {
+	var elemtype *ir.Type
+	var ptrtype *ir.Type
 	var ptr *ssa.Value
 	var len *ssa.Value
 	var cap *ssa.Value
-	zero := s.constInt(Types[TINT], 0)
+	zero := s.constInt(ir.Types[ir.TINT], 0)
 	switch {
 	case t.IsSlice():
 		elemtype = t.Type
 		ptrtype = Ptrto(elemtype)
 		ptr = s.newValue1(ssa.OpSlicePtr, ptrtype, v)
-		len = s.newValue1(ssa.OpSliceLen, Types[TINT], v)
-		cap = s.newValue1(ssa.OpSliceCap, Types[TINT], v)
+		len = s.newValue1(ssa.OpSliceLen, ir.Types[ir.TINT], v)
+		cap = s.newValue1(ssa.OpSliceCap, ir.Types[ir.TINT], v)
 	case t.IsString():
-		elemtype = Types[TUINT8]
+		elemtype = ir.Types[ir.TUINT8]
 		ptrtype = Ptrto(elemtype)
 		ptr = s.newValue1(ssa.OpStringPtr, ptrtype, v)
-		len = s.newValue1(ssa.OpStringLen, Types[TINT], v)
+		len = s.newValue1(ssa.OpStringLen, ir.Types[ir.TINT], v)
 		cap = len
 	case t.IsPtr():
 		if !t.Type.IsArray() {
@@ -3082,7 +3135,7 @@ func (s *state) slice(t *Type, v, i, j, k *ssa.Value) (p, l, c *ssa.Value) {
 		ptrtype = Ptrto(elemtype)
 		s.nilCheck(v)
 		ptr = v
-		len = s.constInt(Types[TINT], t.Type.Bound)
+		len = s.constInt(ir.Types[ir.TINT], t.Type.Bound)
 		cap = len
 	default:
 		s.Fatalf("bad type in slice %v\n", t)
@@ -3119,10 +3172,10 @@ func (s *state) slice(t *Type, v, i, j, k *ssa.Value) (p, l, c *ssa.Value) {
 	// }
 	// rptr = p+delta
 	// result = (SliceMake rptr rlen rcap)
-	subOp := s.ssaOp(OSUB, Types[TINT])
-	eqOp := s.ssaOp(OEQ, Types[TINT])
-	mulOp := s.ssaOp(OMUL, Types[TINT])
-	rlen := s.newValue2(subOp, Types[TINT], j, i)
+	subOp := s.ssaOp(ir.OSUB, ir.Types[ir.TINT])
+	eqOp := s.ssaOp(ir.OEQ, ir.Types[ir.TINT])
+	mulOp := s.ssaOp(ir.OMUL, ir.Types[ir.TINT])
+	rlen := s.newValue2(subOp, ir.Types[ir.TINT], j, i)
 	var rcap *ssa.Value
 	switch {
 	case t.IsString():
@@ -3133,7 +3186,7 @@ func (s *state) slice(t *Type, v, i, j, k *ssa.Value) (p, l, c *ssa.Value) {
 	case j == k:
 		rcap = rlen
 	default:
-		rcap = s.newValue2(subOp, Types[TINT], k, i)
+		rcap = s.newValue2(subOp, ir.Types[ir.TINT], k, i)
 	}
 
 	// delta = # of elements to offset pointer by.
@@ -3142,7 +3195,7 @@ func (s *state) slice(t *Type, v, i, j, k *ssa.Value) (p, l, c *ssa.Value) {
 	// Generate code to set delta=0 if the resulting capacity is zero.
 	if !((i.Op == ssa.OpConst64 && i.AuxInt == 0) ||
 		(i.Op == ssa.OpConst32 && int32(i.AuxInt) == 0)) {
-		cmp := s.newValue2(eqOp, Types[TBOOL], rcap, zero)
+		cmp := s.newValue2(eqOp, ir.Types[ir.TBOOL], rcap, zero)
 
 		b := s.endBlock()
 		b.Kind = ssa.BlockIf
@@ -3166,7 +3219,7 @@ func (s *state) slice(t *Type, v, i, j, k *ssa.Value) (p, l, c *ssa.Value) {
 	}
 
 	// Compute rptr = ptr + delta * elemsize
-	rptr := s.newValue2(ssa.OpAddPtr, ptrtype, ptr, s.newValue2(mulOp, Types[TINT], s.variable(&deltaVar, Types[TINT]), s.constInt(Types[TINT], elemtype.Width)))
+	rptr := s.newValue2(ssa.OpAddPtr, ptrtype, ptr, s.newValue2(mulOp, ir.Types[ir.TINT], s.variable(&deltaVar, ir.Types[ir.TINT]), s.constInt(ir.Types[ir.TINT], elemtype.Width)))
 	delete(s.vars, &deltaVar)
 	return rptr, rlen, rcap
 }
@@ -3210,15 +3263,15 @@ var u32_f32 u2fcvtTab = u2fcvtTab{
 	},
 }
 
-func (s *state) uint64Tofloat64(n *Node, x *ssa.Value, ft, tt *Type) *ssa.Value {
+func (s *state) uint64Tofloat64(n *ir.Node, x *ssa.Value, ft, tt *ir.Type) *ssa.Value {
 	return s.uintTofloat(&u64_f64, n, x, ft, tt)
 }
 
-func (s *state) uint64Tofloat32(n *Node, x *ssa.Value, ft, tt *Type) *ssa.Value {
+func (s *state) uint64Tofloat32(n *ir.Node, x *ssa.Value, ft, tt *ir.Type) *ssa.Value {
 	return s.uintTofloat(&u64_f32, n, x, ft, tt)
 }
 
-func (s *state) uintTofloat(cvttab *u2fcvtTab, n *Node, x *ssa.Value, ft, tt *Type) *ssa.Value {
+func (s *state) uintTofloat(cvttab *u2fcvtTab, n *ir.Node, x *ssa.Value, ft, tt *ir.Type) *ssa.Value {
 	// if x >= 0 {
 	//    result = (floatY) x
 	// } else {
@@ -3244,7 +3297,7 @@ func (s *state) uintTofloat(cvttab *u2fcvtTab, n *Node, x *ssa.Value, ft, tt *Ty
 	// equal to 10000000001; that rounds up, and the 1 cannot
 	// be lost else it would round down if the LSB of the
 	// candidate mantissa is 0.
-	cmp := s.newValue2(cvttab.geq, Types[TBOOL], x, s.zeroVal(ft))
+	cmp := s.newValue2(cvttab.geq, ir.Types[ir.TBOOL], x, s.zeroVal(ft))
 	b := s.endBlock()
 	b.Kind = ssa.BlockIf
 	b.SetControl(cmp)
@@ -3278,7 +3331,7 @@ func (s *state) uintTofloat(cvttab *u2fcvtTab, n *Node, x *ssa.Value, ft, tt *Ty
 }
 
 // referenceTypeBuiltin generates code for the len/cap builtins for maps and channels.
-func (s *state) referenceTypeBuiltin(n *Node, x *ssa.Value) *ssa.Value {
+func (s *state) referenceTypeBuiltin(n *ir.Node, x *ssa.Value) *ssa.Value {
 	if !n.Left.Type.IsMap() && !n.Left.Type.IsChan() {
 		s.Fatalf("node must be a map or a channel")
 	}
@@ -3291,8 +3344,8 @@ func (s *state) referenceTypeBuiltin(n *Node, x *ssa.Value) *ssa.Value {
 	//   return *(((*int)n)+1)
 	// }
 	lenType := n.Type
-	nilValue := s.constNil(Types[TUINTPTR])
-	cmp := s.newValue2(ssa.OpEqPtr, Types[TBOOL], x, nilValue)
+	nilValue := s.constNil(ir.Types[ir.TUINTPTR])
+	cmp := s.newValue2(ssa.OpEqPtr, ir.Types[ir.TBOOL], x, nilValue)
 	b := s.endBlock()
 	b.Kind = ssa.BlockIf
 	b.SetControl(cmp)
@@ -3311,10 +3364,10 @@ func (s *state) referenceTypeBuiltin(n *Node, x *ssa.Value) *ssa.Value {
 
 	b.AddEdgeTo(bElse)
 	s.startBlock(bElse)
-	if n.Op == OLEN {
+	if n.Op == ir.OLEN {
 		// length is stored in the first word for map/chan
 		s.vars[n] = s.newValue2(ssa.OpLoad, lenType, x, s.mem())
-	} else if n.Op == OCAP {
+	} else if n.Op == ir.OCAP {
 		// capacity is stored in the second word for chan
 		sw := s.newValue1I(ssa.OpOffPtr, lenType.PtrTo(), lenType.Width, x)
 		s.vars[n] = s.newValue2(ssa.OpLoad, lenType, sw, s.mem())
@@ -3347,14 +3400,14 @@ var f64_u64 f2uCvtTab = f2uCvtTab{
 	value: (*state).constFloat64,
 }
 
-func (s *state) float32ToUint64(n *Node, x *ssa.Value, ft, tt *Type) *ssa.Value {
+func (s *state) float32ToUint64(n *ir.Node, x *ssa.Value, ft, tt *ir.Type) *ssa.Value {
 	return s.floatToUint(&f32_u64, n, x, ft, tt)
 }
-func (s *state) float64ToUint64(n *Node, x *ssa.Value, ft, tt *Type) *ssa.Value {
+func (s *state) float64ToUint64(n *ir.Node, x *ssa.Value, ft, tt *ir.Type) *ssa.Value {
 	return s.floatToUint(&f64_u64, n, x, ft, tt)
 }
 
-func (s *state) floatToUint(cvttab *f2uCvtTab, n *Node, x *ssa.Value, ft, tt *Type) *ssa.Value {
+func (s *state) floatToUint(cvttab *f2uCvtTab, n *ir.Node, x *ssa.Value, ft, tt *ir.Type) *ssa.Value {
 	// if x < 9223372036854775808.0 {
 	// 	result = uintY(x)
 	// } else {
@@ -3363,7 +3416,7 @@ func (s *state) floatToUint(cvttab *f2uCvtTab, n *Node, x *ssa.Value, ft, tt *Ty
 	// 	result = z | -9223372036854775808
 	// }
 	twoToThe63 := cvttab.value(s, ft, 9223372036854775808.0)
-	cmp := s.newValue2(cvttab.ltf, Types[TBOOL], x, twoToThe63)
+	cmp := s.newValue2(cvttab.ltf, ir.Types[ir.TBOOL], x, twoToThe63)
 	b := s.endBlock()
 	b.Kind = ssa.BlockIf
 	b.SetControl(cmp)
@@ -3397,8 +3450,8 @@ func (s *state) floatToUint(cvttab *f2uCvtTab, n *Node, x *ssa.Value, ft, tt *Ty
 // ifaceType returns the value for the word containing the type.
 // n is the node for the interface expression.
 // v is the corresponding value.
-func (s *state) ifaceType(n *Node, v *ssa.Value) *ssa.Value {
-	byteptr := Ptrto(Types[TUINT8]) // type used in runtime prototypes for runtime type (*byte)
+func (s *state) ifaceType(n *ir.Node, v *ssa.Value) *ssa.Value {
+	byteptr := Ptrto(ir.Types[ir.TUINT8]) // type used in runtime prototypes for runtime type (*byte)
 
 	if isnilinter(n.Type) {
 		// Have *eface. The type is the first word in the struct.
@@ -3412,7 +3465,7 @@ func (s *state) ifaceType(n *Node, v *ssa.Value) *ssa.Value {
 
 	tab := s.newValue1(ssa.OpITab, byteptr, v)
 	s.vars[&typVar] = tab
-	isnonnil := s.newValue2(ssa.OpNeqPtr, Types[TBOOL], tab, s.constNil(byteptr))
+	isnonnil := s.newValue2(ssa.OpNeqPtr, ir.Types[ir.TBOOL], tab, s.constNil(byteptr))
 	b := s.endBlock()
 	b.Kind = ssa.BlockIf
 	b.SetControl(isnonnil)
@@ -3439,7 +3492,7 @@ func (s *state) ifaceType(n *Node, v *ssa.Value) *ssa.Value {
 // dottype generates SSA for a type assertion node.
 // commaok indicates whether to panic or return a bool.
 // If commaok is false, resok will be nil.
-func (s *state) dottype(n *Node, commaok bool) (res, resok *ssa.Value) {
+func (s *state) dottype(n *ir.Node, commaok bool) (res, resok *ssa.Value) {
 	iface := s.expr(n.Left)
 	typ := s.ifaceType(n.Left, iface)  // actual concrete type
 	target := s.expr(typename(n.Type)) // target type
@@ -3454,13 +3507,13 @@ func (s *state) dottype(n *Node, commaok bool) (res, resok *ssa.Value) {
 
 	// TODO:  If we have a nonempty interface and its itab field is nil,
 	// then this test is redundant and ifaceType should just branch directly to bFail.
-	cond := s.newValue2(ssa.OpEqPtr, Types[TBOOL], typ, target)
+	cond := s.newValue2(ssa.OpEqPtr, ir.Types[ir.TBOOL], typ, target)
 	b := s.endBlock()
 	b.Kind = ssa.BlockIf
 	b.SetControl(cond)
 	b.Likely = ssa.BranchLikely
 
-	byteptr := Ptrto(Types[TUINT8])
+	byteptr := Ptrto(ir.Types[ir.TUINT8])
 
 	bOk := s.f.NewBlock(ssa.BlockPlain)
 	bFail := s.f.NewBlock(ssa.BlockPlain)
@@ -3499,7 +3552,7 @@ func (s *state) dottype(n *Node, commaok bool) (res, resok *ssa.Value) {
 	// merge point
 	s.startBlock(bEnd)
 	res = s.variable(&idataVar, byteptr)
-	resok = s.variable(&okVar, Types[TBOOL])
+	resok = s.variable(&okVar, ir.Types[ir.TBOOL])
 	delete(s.vars, &idataVar)
 	delete(s.vars, &okVar)
 	return res, resok
@@ -3510,7 +3563,7 @@ func (s *state) dottype(n *Node, commaok bool) (res, resok *ssa.Value) {
 // It is a copy of checkgoto in the pre-SSA backend,
 // modified only for line number handling.
 // TODO: document how this works and why it is designed the way it is.
-func (s *state) checkgoto(from *Node, to *Node) {
+func (s *state) checkgoto(from *ir.Node, to *ir.Node) {
 	if from.Sym == to.Sym {
 		return
 	}
@@ -3531,9 +3584,9 @@ func (s *state) checkgoto(from *Node, to *Node) {
 		// decide what to complain about.
 		// prefer to complain about 'into block' over declarations,
 		// so scan backward to find most recent block or else dcl.
-		var block *Sym
+		var block *ir.Sym
 
-		var dcl *Sym
+		var dcl *ir.Sym
 		ts := to.Sym
 		for ; nt > nf; nt-- {
 			if ts.Pkg == nil {
@@ -3556,15 +3609,15 @@ func (s *state) checkgoto(from *Node, to *Node) {
 
 		lno := from.Left.Lineno
 		if block != nil {
-			yyerrorl(lno, "goto %v jumps into block starting at %v", from.Left.Sym, linestr(block.Lastlineno))
+			yyerrorl(lno, "goto %v jumps into block starting at %v\n\tlabel %v defined at %v", from.Left.Sym, linestr(block.Lastlineno), to.Left.Sym, to.Line())
 		} else {
-			yyerrorl(lno, "goto %v jumps over declaration of %v at %v", from.Left.Sym, dcl, linestr(dcl.Lastlineno))
+			yyerrorl(lno, "goto %v jumps over declaration of %v at %v\n\tlabel %v defined at %v", from.Left.Sym, dcl, linestr(dcl.Lastlineno), to.Left.Sym, to.Line())
 		}
 	}
 }
 
 // variable returns the value of a variable at the current location.
-func (s *state) variable(name *Node, t ssa.Type) *ssa.Value {
+func (s *state) variable(name *ir.Node, t ssa.Type) *ssa.Value {
 	v := s.vars[name]
 	if v == nil {
 		v = s.newValue0A(ssa.OpFwdRef, t, name)
@@ -3591,6 +3644,11 @@ func (s *state) linkForwardReferences() {
 	//     completely built. That way we can avoid the notion of "sealed"
 	//     blocks.
 	//   - Phi optimization is a separate pass (in ../ssa/phielim.go).
+	// Large functions use a different algorithm; see phi.go.
+	if len(s.f.Blocks) > smallBlocks {
+		s.insertPhis()
+		return
+	}
 	for len(s.fwdRefs) > 0 {
 		v := s.fwdRefs[len(s.fwdRefs)-1]
 		s.fwdRefs = s.fwdRefs[:len(s.fwdRefs)-1]
@@ -3602,7 +3660,7 @@ func (s *state) linkForwardReferences() {
 // v must be a FwdRef op.
 func (s *state) resolveFwdRef(v *ssa.Value) {
 	b := v.Block
-	name := v.Aux.(*Node)
+	name := v.Aux.(*ir.Node)
 	v.Aux = nil
 	if b == s.f.Entry {
 		// Live variable at start of function.
@@ -3664,22 +3722,32 @@ func (s *state) resolveFwdRef(v *ssa.Value) {
 }
 
 // lookupVarOutgoing finds the variable's value at the end of block b.
-func (s *state) lookupVarOutgoing(b *ssa.Block, t ssa.Type, name *Node, line int32) *ssa.Value {
-	m := s.defvars[b.ID]
-	if v, ok := m[name]; ok {
-		return v
+func (s *state) lookupVarOutgoing(b *ssa.Block, t ssa.Type, name *ir.Node, line int32) *ssa.Value {
+	for {
+		if v, ok := s.defvars[b.ID][name]; ok {
+			return v
+		}
+		// The variable is not defined by b and we haven't looked it up yet.
+		// If b has exactly one predecessor, loop to look it up there.
+		// Otherwise, give up and insert a new FwdRef and resolve it later.
+		// Leaving a FwdRef in every block of a long chain, as calls
+		// make in straight-line code, is quadratic in the size of the
+		// function.
+		if len(b.Preds) != 1 {
+			break
+		}
+		b = b.Preds[0]
 	}
-	// The variable is not defined by b and we haven't
-	// looked it up yet. Generate a FwdRef for the variable and return that.
+	// Generate a FwdRef for the variable and return that.
 	v := b.NewValue0A(line, ssa.OpFwdRef, t, name)
 	s.fwdRefs = append(s.fwdRefs, v)
-	m[name] = v
+	s.defvars[b.ID][name] = v
 	s.addNamedValue(name, v)
 	return v
 }
 
-func (s *state) addNamedValue(n *Node, v *ssa.Value) {
-	if n.Class == Pxxx {
+func (s *state) addNamedValue(n *ir.Node, v *ssa.Value) {
+	if n.Class == ir.Pxxx {
 		// Don't track our dummy nodes (&memVar etc.).
 		return
 	}
@@ -3687,18 +3755,18 @@ func (s *state) addNamedValue(n *Node, v *ssa.Value) {
 		// Don't track autotmp_ variables.
 		return
 	}
-	if n.Class == PAUTO && (v.Type.IsString() || v.Type.IsSlice() || v.Type.IsInterface()) {
+	if n.Class == ir.PAUTO && (v.Type.IsString() || v.Type.IsSlice() || v.Type.IsInterface()) {
 		// TODO: can't handle auto compound objects with pointers yet.
 		// The live variable analysis barfs because we don't put VARDEF
 		// pseudos in the right place when we spill to these nodes.
 		return
 	}
-	if n.Class == PPARAMOUT {
+	if n.Class == ir.PPARAMOUT {
 		// Don't track named output values.  This prevents return values
 		// from being assigned too early. See #14591 and #14762. TODO: allow this.
 		return
 	}
-	if n.Class == PAUTO && n.Xoffset != 0 {
+	if n.Class == ir.PAUTO && n.Xoffset != 0 {
 		s.Fatalf("AUTO var with offset %s %d", n, n.Xoffset)
 	}
 	loc := ssa.LocalSlot{N: n, Type: n.Type, Off: 0}
@@ -3737,7 +3805,7 @@ func (s *SSAGenState) SetLineno(l int32) {
 
 // genssa appends entries to ptxt for each instruction in f.
 // gcargs and gclocals are filled in with pointer maps for the frame.
-func genssa(f *ssa.Func, ptxt *obj.Prog, gcargs, gclocals *Sym) {
+func genssa(f *ssa.Func, ptxt *obj.Prog, gcargs, gclocals *ir.Sym) {
 	var s SSAGenState
 
 	e := f.Config.Frontend().(*ssaExport)
@@ -3836,7 +3904,7 @@ func genssa(f *ssa.Func, ptxt *obj.Prog, gcargs, gclocals *Sym) {
 
 	// Emit static data
 	if f.StaticData != nil {
-		for _, n := range f.StaticData.([]*Node) {
+		for _, n := range f.StaticData.([]*ir.Node) {
 			if !gen_as_init(n, false) {
 				Fatalf("non-static data marked as static: %v\n\n", n, f)
 			}
@@ -3845,6 +3913,13 @@ func genssa(f *ssa.Func, ptxt *obj.Prog, gcargs, gclocals *Sym) {
 
 	// Allocate stack frame
 	allocauto(ptxt)
+	if nerrors == 0 && Stksize+Maxarg > 1<<31 {
+		tooLarge("stack frame too large (>2GB)")
+	}
+	if nerrors != 0 {
+		f.Config.HTML.Close()
+		return
+	}
 
 	// Generate gc bitmaps.
 	liveness(Curfn, ptxt, gcargs, gclocals)
@@ -3852,11 +3927,13 @@ func genssa(f *ssa.Func, ptxt *obj.Prog, gcargs, gclocals *Sym) {
 	gcsymdup(gclocals)
 
 	// Add frame prologue. Zero ambiguously live variables.
-	Thearch.Defframe(ptxt)
+	defframe(ptxt)
 	if Debug['f'] != 0 {
 		frame(0)
 	}
 
+	marknosplit(ptxt)
+
 	// Remove leftover instrumentation from the instruction stream.
 	removevardef(ptxt)
 
@@ -3943,7 +4020,7 @@ func AddAux2(a *obj.Addr, v *ssa.Value, offset int64) {
 	case *ssa.ExternSymbol:
 		a.Name = obj.NAME_EXTERN
 		switch s := sym.Sym.(type) {
-		case *Sym:
+		case *ir.Sym:
 			a.Sym = Linksym(s)
 		case *obj.LSym:
 			a.Sym = s
@@ -3951,13 +4028,13 @@ func AddAux2(a *obj.Addr, v *ssa.Value, offset int64) {
 			v.Fatalf("ExternSymbol.Sym is %T", s)
 		}
 	case *ssa.ArgSymbol:
-		n := sym.Node.(*Node)
+		n := sym.Node.(*ir.Node)
 		a.Name = obj.NAME_PARAM
 		a.Node = n
 		a.Sym = Linksym(n.Orig.Sym)
 		a.Offset += n.Xoffset // TODO: why do I have to add this here?  I don't for auto variables.
 	case *ssa.AutoSymbol:
-		n := sym.Node.(*Node)
+		n := sym.Node.(*ir.Node)
 		a.Name = obj.NAME_AUTO
 		a.Node = n
 		a.Sym = Linksym(n.Sym)
@@ -4012,7 +4089,7 @@ func (s *state) extendIndex(v *ssa.Value) *ssa.Value {
 			s.Fatalf("bad unsigned index extension %s", v.Type)
 		}
 	}
-	return s.newValue1(op, Types[TINT], v)
+	return s.newValue1(op, ir.Types[ir.TINT], v)
 }
 
 // SSARegNum returns the register (in cmd/internal/obj numbering) to
@@ -4030,19 +4107,19 @@ func SSARegNum(v *ssa.Value) int16 {
 
 // AutoVar returns a *Node and int64 representing the auto variable and offset within it
 // where v should be spilled.
-func AutoVar(v *ssa.Value) (*Node, int64) {
+func AutoVar(v *ssa.Value) (*ir.Node, int64) {
 	loc := v.Block.Func.RegAlloc[v.ID].(ssa.LocalSlot)
 	if v.Type.Size() > loc.Type.Size() {
 		v.Fatalf("spill/restore type %s doesn't fit in slot type %s", v.Type, loc.Type)
 	}
-	return loc.N.(*Node), loc.Off
+	return loc.N.(*ir.Node), loc.Off
 }
 
 // fieldIdx finds the index of the field referred to by the ODOT node n.
-func fieldIdx(n *Node) int {
+func fieldIdx(n *ir.Node) int {
 	t := n.Left.Type
 	f := n.Sym
-	if t.Etype != TSTRUCT {
+	if t.Etype != ir.TSTRUCT {
 		panic("ODOT's LHS is not a struct")
 	}
 
@@ -4068,43 +4145,107 @@ type ssaExport struct {
 	log           bool
 	unimplemented bool
 	mustImplement bool
+
+	// When the SSA passes run concurrently, curfn is the function
+	// being compiled, and the services lock backendMu. The autos
+	// and string data allocated for curfn are named and emitted by
+	// commit, in order, so that the output doesn't depend on
+	// scheduling.
+	curfn   *ir.Node
+	autos   []*ir.Node
+	strings []string
 }
 
-func (s *ssaExport) TypeBool() ssa.Type    { return Types[TBOOL] }
-func (s *ssaExport) TypeInt8() ssa.Type    { return Types[TINT8] }
-func (s *ssaExport) TypeInt16() ssa.Type   { return Types[TINT16] }
-func (s *ssaExport) TypeInt32() ssa.Type   { return Types[TINT32] }
-func (s *ssaExport) TypeInt64() ssa.Type   { return Types[TINT64] }
-func (s *ssaExport) TypeUInt8() ssa.Type   { return Types[TUINT8] }
-func (s *ssaExport) TypeUInt16() ssa.Type  { return Types[TUINT16] }
-func (s *ssaExport) TypeUInt32() ssa.Type  { return Types[TUINT32] }
-func (s *ssaExport) TypeUInt64() ssa.Type  { return Types[TUINT64] }
-func (s *ssaExport) TypeFloat32() ssa.Type { return Types[TFLOAT32] }
-func (s *ssaExport) TypeFloat64() ssa.Type { return Types[TFLOAT64] }
-func (s *ssaExport) TypeInt() ssa.Type     { return Types[TINT] }
-func (s *ssaExport) TypeUintptr() ssa.Type { return Types[TUINTPTR] }
-func (s *ssaExport) TypeString() ssa.Type  { return Types[TSTRING] }
-func (s *ssaExport) TypeBytePtr() ssa.Type { return Ptrto(Types[TUINT8]) }
+func (e *ssaExport) lock() {
+	if e.curfn != nil {
+		backendMu.Lock()
+	}
+}
+
+func (e *ssaExport) unlock() {
+	if e.curfn != nil {
+		backendMu.Unlock()
+	}
+}
+
+// commit names the autos and emits the string data allocated by the
+// concurrent SSA passes.
+func (e *ssaExport) commit() {
+	for _, n := range e.autos {
+		n.Sym = LookupN("autotmp_", statuniqgen)
+		statuniqgen++
+		n.Sym.Def = n
+	}
+	for _, s := range e.strings {
+		stringsym(s)
+	}
+	e.autos = nil
+	e.strings = nil
+}
+
+func (s *ssaExport) TypeBool() ssa.Type    { return ir.Types[ir.TBOOL] }
+func (s *ssaExport) TypeInt8() ssa.Type    { return ir.Types[ir.TINT8] }
+func (s *ssaExport) TypeInt16() ssa.Type   { return ir.Types[ir.TINT16] }
+func (s *ssaExport) TypeInt32() ssa.Type   { return ir.Types[ir.TINT32] }
+func (s *ssaExport) TypeInt64() ssa.Type   { return ir.Types[ir.TINT64] }
+func (s *ssaExport) TypeUInt8() ssa.Type   { return ir.Types[ir.TUINT8] }
+func (s *ssaExport) TypeUInt16() ssa.Type  { return ir.Types[ir.TUINT16] }
+func (s *ssaExport) TypeUInt32() ssa.Type  { return ir.Types[ir.TUINT32] }
+func (s *ssaExport) TypeUInt64() ssa.Type  { return ir.Types[ir.TUINT64] }
+func (s *ssaExport) TypeFloat32() ssa.Type { return ir.Types[ir.TFLOAT32] }
+func (s *ssaExport) TypeFloat64() ssa.Type { return ir.Types[ir.TFLOAT64] }
+func (s *ssaExport) TypeInt() ssa.Type     { return ir.Types[ir.TINT] }
+func (s *ssaExport) TypeUintptr() ssa.Type { return ir.Types[ir.TUINTPTR] }
+func (s *ssaExport) TypeString() ssa.Type  { return ir.Types[ir.TSTRING] }
+func (s *ssaExport) TypeBytePtr() ssa.Type { return ir.Types[ir.TUINT8].PtrTo() }
 
 // StringData returns a symbol (a *Sym wrapped in an interface) which
 // is the data component of a global string constant containing s.
-func (*ssaExport) StringData(s string) interface{} {
+func (e *ssaExport) StringData(s string) interface{} {
+	e.lock()
+	defer e.unlock()
+	var data *obj.LSym
+	if e.curfn == nil {
+		_, data = stringsym(s)
+	} else {
+		// Emitting the data appends to the Prog list; leave it to commit.
+		data = obj.Linklookup(Ctxt, stringdataname(s), 0)
+		e.strings = append(e.strings, s)
+	}
 	// TODO: is idealstring correct?  It might not matter...
-	_, data := stringsym(s)
-	return &ssa.ExternSymbol{Typ: idealstring, Sym: data}
+	return &ssa.ExternSymbol{Typ: ir.Idealstring, Sym: data}
 }
 
 func (e *ssaExport) Auto(t ssa.Type) ssa.GCNode {
-	n := temp(t.(*Type))   // Note: adds new auto to Curfn.Func.Dcl list
 	e.mustImplement = true // This modifies the input to SSA, so we want to make sure we succeed from here!
+	if e.curfn == nil {
+		return temp(t.(*ir.Type)) // Note: adds new auto to Curfn.Func.Dcl list
+	}
+
+	backendMu.Lock()
+	defer backendMu.Unlock()
+	n := Nod(ir.ONAME, nil, nil)
+	n.Type = t.(*ir.Type)
+	n.Class = ir.PAUTO
+	n.Addable = true
+	n.Ullman = 1
+	n.Esc = EscNever
+	n.Used = true
+	n.Name.Curfn = e.curfn
+	e.curfn.Func.Dcl = append(e.curfn.Func.Dcl, n)
+	e.autos = append(e.autos, n)
+	dowidth(n.Type)
+	n.Xoffset = 0
 	return n
 }
 
 func (e *ssaExport) CanSSA(t ssa.Type) bool {
-	return canSSAType(t.(*Type))
+	return canSSAType(t.(*ir.Type))
 }
 
 func (e *ssaExport) Line(line int32) string {
+	e.lock()
+	defer e.unlock()
 	return linestr(line)
 }
 
@@ -4124,6 +4265,7 @@ func (e *ssaExport) Log() bool {
 func (e *ssaExport) Fatalf(line int32, msg string, args ...interface{}) {
 	// If e was marked as unimplemented, anything could happen. Ignore.
 	if !e.unimplemented {
+		e.lock()
 		lineno = line
 		Fatalf(msg, args...)
 	}
@@ -4133,6 +4275,7 @@ func (e *ssaExport) Fatalf(line int32, msg string, args ...interface{}) {
 // It will be removed once SSA work is complete.
 func (e *ssaExport) Unimplementedf(line int32, msg string, args ...interface{}) {
 	if e.mustImplement {
+		e.lock()
 		lineno = line
 		Fatalf(msg, args...)
 	}
@@ -4147,13 +4290,11 @@ func (e *ssaExport) Unimplementedf(line int32, msg string, args ...interface{})
 // Warnl reports a "warning", which is usually flag-triggered
 // logging output for the benefit of tests.
 func (e *ssaExport) Warnl(line int32, fmt_ string, args ...interface{}) {
+	e.lock()
+	defer e.unlock()
 	Warnl(line, fmt_, args...)
 }
 
 func (e *ssaExport) Debug_checknil() bool {
 	return Debug_checknil != 0
 }
-
-func (n *Node) Typ() ssa.Type {
-	return n.Type
-}
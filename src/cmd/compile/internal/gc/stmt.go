@@ -0,0 +1,74 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gc
+
+import "cmd/compile/internal/ir"
+import "cmd/internal/obj"
+
+// stmtlines records in fn.Func.StmtLines the lines on which the
+// statements of fn begin. It must run before order and walk, which
+// introduce statements for the pieces of expressions, so that the
+// continuation lines of a statement are not mistaken for statements.
+func stmtlines(fn *ir.Node) {
+	lines := make(map[int32]bool)
+	lines[fn.Lineno] = true
+	if fn.Func.Endlineno != 0 {
+		lines[fn.Func.Endlineno] = true
+	}
+	addstmtlines(lines, fn.Func.Enter)
+	addstmtlines(lines, fn.Nbody)
+	fn.Func.StmtLines = lines
+}
+
+func addstmtlines(lines map[int32]bool, l ir.Nodes) {
+	for _, n := range l.Slice() {
+		addstmtline(lines, n)
+	}
+}
+
+func addstmtline(lines map[int32]bool, n *ir.Node) {
+	if n == nil {
+		return
+	}
+	addstmtlines(lines, n.Ninit)
+	if n.Op == ir.OBLOCK {
+		addstmtlines(lines, n.List)
+		return
+	}
+	lines[n.Lineno] = true
+	switch n.Op {
+	case ir.OFOR:
+		addstmtline(lines, n.Right)
+	case ir.OIF:
+		addstmtlines(lines, n.Rlist)
+	case ir.OSWITCH, ir.OSELECT:
+		for _, c := range n.List.Slice() {
+			addstmtlines(lines, c.Nbody)
+		}
+	}
+	addstmtlines(lines, n.Nbody)
+}
+
+// markstmts marks the first instruction generated for each line in
+// Curfn.Func.StmtLines as the start of a statement, for the is_stmt
+// column of the DWARF line table.
+func markstmts(ptxt *obj.Prog) {
+	lines := Curfn.Func.StmtLines
+	if lines == nil {
+		return
+	}
+	for p := ptxt; p != nil; p = p.Link {
+		switch p.As {
+		case obj.ATEXT, obj.AEND, obj.ANOP, obj.ATYPE, obj.AFUNCDATA, obj.APCDATA,
+			obj.AVARDEF, obj.AVARKILL, obj.AVARLIVE, obj.AUSEFIELD:
+			continue
+		}
+		if lines[p.Lineno] {
+			p.Stmt = true
+			delete(lines, p.Lineno)
+		}
+	}
+	Curfn.Func.StmtLines = nil
+}
@@ -6,11 +6,13 @@ package gc
 
 import (
 	"bytes"
+	"cmd/compile/internal/ir"
 	"cmd/internal/obj"
 	"crypto/md5"
 	"encoding/binary"
 	"fmt"
 	"os"
+	"runtime/debug"
 	"sort"
 	"strconv"
 	"strings"
@@ -21,32 +23,43 @@ import (
 type Error struct {
 	lineno int32
 	msg    string
+	text   string     // msg without the position, for -json
+	remove *byteRange // for -json; see diag.go
 }
 
 var errors []Error
 
 func errorexit() {
 	Flusherrors()
+	if compileFuncDiags != nil {
+		panic(compileFuncExit{})
+	}
 	if outfile != "" {
 		os.Remove(outfile)
 	}
+	if linkobj != "" {
+		os.Remove(linkobj)
+	}
 	os.Exit(2)
 }
 
-func adderrorname(n *Node) {
-	if n.Op != ODOT {
+func adderrorname(n *ir.Node) {
+	if n.Op != ir.ODOT {
 		return
 	}
 	old := fmt.Sprintf("%v: undefined: %v\n", n.Line(), n.Left)
 	if len(errors) > 0 && errors[len(errors)-1].lineno == n.Lineno && errors[len(errors)-1].msg == old {
-		errors[len(errors)-1].msg = fmt.Sprintf("%v: undefined: %v in %v\n", n.Line(), n.Left, n)
+		errors[len(errors)-1].text = fmt.Sprintf("undefined: %v in %v", n.Left, n)
+		errors[len(errors)-1].msg = fmt.Sprintf("%v: %s\n", n.Line(), errors[len(errors)-1].text)
 	}
 }
 
 func adderr(line int32, format string, args ...interface{}) {
+	text := fmt.Sprintf(format, args...)
 	errors = append(errors, Error{
 		lineno: line,
-		msg:    fmt.Sprintf("%v: %s\n", linestr(line), fmt.Sprintf(format, args...)),
+		msg:    fmt.Sprintf("%v: %s\n", linestr(line), text),
+		text:   text,
 	})
 }
 
@@ -65,6 +78,14 @@ func Flusherrors() {
 	sort.Stable(byLineno(errors))
 	for i := 0; i < len(errors); i++ {
 		if i == 0 || errors[i].msg != errors[i-1].msg {
+			if compileFuncDiags != nil {
+				*compileFuncDiags = append(*compileFuncDiags, strings.TrimSuffix(errors[i].msg, "\n"))
+				continue
+			}
+			if flag_json != 0 {
+				printjson(errors[i])
+				continue
+			}
 			fmt.Printf("%s", errors[i].msg)
 		}
 	}
@@ -77,6 +98,9 @@ func hcrash() {
 		if outfile != "" {
 			os.Remove(outfile)
 		}
+		if linkobj != "" {
+			os.Remove(linkobj)
+		}
 		var x *int
 		*x = 0
 	}
@@ -87,15 +111,32 @@ func linestr(line int32) string {
 }
 
 func yyerrorl(line int32, format string, args ...interface{}) {
+	yyerrorlremove(line, nil, format, args...)
+}
+
+// yyerrorlremove is like yyerrorl, but also records remove,
+// the byte range whose removal fixes the error, for -json.
+func yyerrorlremove(line int32, remove *byteRange, format string, args ...interface{}) {
 	adderr(line, format, args...)
+	errors[len(errors)-1].remove = remove
 
 	hcrash()
 	nerrors++
 	if nsavederrors+nerrors >= 10 && Debug['e'] == 0 {
-		Flusherrors()
+		toomanyerrors(line)
+	}
+}
+
+// toomanyerrors reports that there were too many errors
+// at line, and exits.
+func toomanyerrors(line int32) {
+	Flusherrors()
+	if flag_json != 0 {
+		printjson(Error{lineno: line, text: "too many errors"})
+	} else {
 		fmt.Printf("%v: too many errors\n", linestr(line))
-		errorexit()
 	}
+	errorexit()
 }
 
 var yyerror_lastsyntax int32
@@ -120,9 +161,7 @@ func Yyerror(format string, args ...interface{}) {
 	hcrash()
 	nerrors++
 	if nsavederrors+nerrors >= 10 && Debug['e'] == 0 {
-		Flusherrors()
-		fmt.Printf("%v: too many errors\n", linestr(lineno))
-		errorexit()
+		toomanyerrors(lineno)
 	}
 }
 
@@ -141,6 +180,11 @@ func Warnl(line int32, fmt_ string, args ...interface{}) {
 
 func Fatalf(fmt_ string, args ...interface{}) {
 	Flusherrors()
+	if compileFuncDiags != nil {
+		msg := fmt.Sprintf(fmt_, args...)
+		*compileFuncDiags = append(*compileFuncDiags, fmt.Sprintf("%v: internal compiler error: %s", linestr(lineno), msg))
+		panic(&InternalError{Msg: msg, Stack: debug.Stack()})
+	}
 
 	fmt.Printf("%v: internal compiler error: ", linestr(lineno))
 	fmt.Printf(fmt_, args...)
@@ -185,14 +229,14 @@ func linehistupdate(file string, off int) {
 	Ctxt.LineHist.Update(int(lexlineno), file, off)
 }
 
-func setlineno(n *Node) int32 {
+func setlineno(n *ir.Node) int32 {
 	lno := lineno
 	if n != nil {
 		switch n.Op {
-		case ONAME, OTYPE, OPACK:
+		case ir.ONAME, ir.OTYPE, ir.OPACK:
 			break
 
-		case OLITERAL:
+		case ir.OLITERAL:
 			if n.Sym != nil {
 				break
 			}
@@ -212,82 +256,52 @@ func setlineno(n *Node) int32 {
 	return lno
 }
 
-func Lookup(name string) *Sym {
+func Lookup(name string) *ir.Sym {
 	return localpkg.Lookup(name)
 }
 
-func Lookupf(format string, a ...interface{}) *Sym {
+func Lookupf(format string, a ...interface{}) *ir.Sym {
 	return Lookup(fmt.Sprintf(format, a...))
 }
 
-func LookupBytes(name []byte) *Sym {
+func LookupBytes(name []byte) *ir.Sym {
 	return localpkg.LookupBytes(name)
 }
 
 // LookupN looks up the symbol starting with prefix and ending with
 // the decimal n. If prefix is too long, LookupN panics.
-func LookupN(prefix string, n int) *Sym {
+func LookupN(prefix string, n int) *ir.Sym {
 	var buf [20]byte // plenty long enough for all current users
 	copy(buf[:], prefix)
 	b := strconv.AppendInt(buf[:len(prefix)], int64(n), 10)
 	return LookupBytes(b)
 }
 
-var initSyms []*Sym
-
-var nopkg = &Pkg{
-	Syms: make(map[string]*Sym),
-}
-
-func (pkg *Pkg) Lookup(name string) *Sym {
-	if pkg == nil {
-		pkg = nopkg
-	}
-	if s := pkg.Syms[name]; s != nil {
-		return s
-	}
-
-	s := &Sym{
-		Name: name,
-		Pkg:  pkg,
-	}
-	if name == "init" {
-		initSyms = append(initSyms, s)
-	}
-	pkg.Syms[name] = s
-	return s
-}
-
-func (pkg *Pkg) LookupBytes(name []byte) *Sym {
-	if pkg == nil {
-		pkg = nopkg
-	}
-	if s := pkg.Syms[string(name)]; s != nil {
-		return s
-	}
-	str := internString(name)
-	return pkg.Lookup(str)
-}
-
-func Pkglookup(name string, pkg *Pkg) *Sym {
+func Pkglookup(name string, pkg *ir.Pkg) *ir.Sym {
 	return pkg.Lookup(name)
 }
 
-func restrictlookup(name string, pkg *Pkg) *Sym {
+// restrictlookup returns the symbol for the qualified identifier
+// pkg.name, decoding the declaration of the imported object if needed.
+func restrictlookup(name string, pkg *ir.Pkg) *ir.Sym {
 	if !exportname(name) && pkg != localpkg {
 		Yyerror("cannot refer to unexported name %s.%s", pkg.Name, name)
 	}
-	return Pkglookup(name, pkg)
+	s := Pkglookup(name, pkg)
+	expandDecls(s)
+	return s
 }
 
 // find all the exported symbols in package opkg
 // and make them available in the current package
-func importdot(opkg *Pkg, pack *Node) {
-	var s1 *Sym
+func importdot(opkg *ir.Pkg, pack *ir.Node) {
+	var s1 *ir.Sym
 	var pkgerror string
 
+	expandPkg(opkg)
+
 	n := 0
-	for _, s := range opkg.Syms {
+	for _, s := range opkg.Syms() {
 		if s.Def == nil {
 			continue
 		}
@@ -318,46 +332,72 @@ func importdot(opkg *Pkg, pack *Node) {
 	}
 }
 
-func Nod(op Op, nleft *Node, nright *Node) *Node {
-	n := new(Node)
+func Nod(op ir.Op, nleft *ir.Node, nright *ir.Node) *ir.Node {
+	n := new(ir.Node)
 	n.Op = op
 	n.Left = nleft
 	n.Right = nright
 	n.Lineno = lineno
-	n.Xoffset = BADWIDTH
+	n.Xoffset = ir.BADWIDTH
 	n.Orig = n
 	switch op {
-	case OCLOSURE, ODCLFUNC:
-		n.Func = new(Func)
+	case ir.OCLOSURE, ir.ODCLFUNC:
+		n.Func = new(ir.Func)
 		n.Func.FCurfn = Curfn
-	case ONAME:
-		n.Name = new(Name)
-		n.Name.Param = new(Param)
-	case OLABEL, OPACK:
-		n.Name = new(Name)
-	case ODCLFIELD:
+	case ir.ONAME:
+		n.Name = new(ir.Name)
+		n.Name.Param = new(ir.Param)
+	case ir.OLABEL, ir.OPACK:
+		n.Name = new(ir.Name)
+	case ir.ODCLFIELD:
 		if nleft != nil {
 			n.Name = nleft.Name
 		} else {
-			n.Name = new(Name)
-			n.Name.Param = new(Param)
+			n.Name = new(ir.Name)
+			n.Name.Param = new(ir.Param)
 		}
 	}
 	if n.Name != nil {
 		n.Name.Curfn = Curfn
 	}
+	if Debug_nodealloc != 0 {
+		nodeAllocated(n)
+	}
+	return n
+}
+
+// renod is like Nod, but it reuses n, which the caller is replacing
+// and knows to be referenced nowhere else, instead of allocating a new
+// node. The operands are read before n is cleared, so they may be
+// fields of n, but not n itself.
+func renod(n *ir.Node, op ir.Op, nleft *ir.Node, nright *ir.Node) *ir.Node {
+	if n.Name != nil || n.Func != nil || nleft == n || nright == n {
+		return Nod(op, nleft, nright)
+	}
+	switch op {
+	case ir.OCLOSURE, ir.ODCLFUNC, ir.ONAME, ir.OLABEL, ir.OPACK, ir.ODCLFIELD:
+		return Nod(op, nleft, nright)
+	}
+	*n = ir.Node{}
+	n.Op = op
+	n.Left = nleft
+	n.Right = nright
+	n.Lineno = lineno
+	n.Xoffset = ir.BADWIDTH
+	n.Orig = n
+	nodeReused(n)
 	return n
 }
 
 // NodSym makes a Node with Op op and with the Left field set to left
 // and the Sym field set to sym. This is for ODOT and friends.
-func NodSym(op Op, left *Node, sym *Sym) *Node {
+func NodSym(op ir.Op, left *ir.Node, sym *ir.Sym) *ir.Node {
 	n := Nod(op, left, nil)
 	n.Sym = sym
 	return n
 }
 
-func saveorignode(n *Node) {
+func saveorignode(n *ir.Node) {
 	if n.Orig != nil {
 		return
 	}
@@ -366,11 +406,11 @@ func saveorignode(n *Node) {
 	n.Orig = norig
 }
 
-func maptype(key *Type, val *Type) *Type {
+func maptype(key *ir.Type, val *ir.Type) *ir.Type {
 	if key != nil {
-		var bad *Type
+		var bad *ir.Type
 		atype := algtype1(key, &bad)
-		var mtype EType
+		var mtype ir.EType
 		if bad == nil {
 			mtype = key.Etype
 		} else {
@@ -383,7 +423,7 @@ func maptype(key *Type, val *Type) *Type {
 			}
 
 			// will be resolved later.
-		case TANY:
+		case ir.TANY:
 			break
 
 			// map[key] used during definition of key.
@@ -392,21 +432,21 @@ func maptype(key *Type, val *Type) *Type {
 		// before key is fully defined, the error
 		// will only be printed for the first one.
 		// good enough.
-		case TFORW:
+		case ir.TFORW:
 			if key.Maplineno == 0 {
 				key.Maplineno = lineno
 			}
 		}
 	}
 
-	t := typ(TMAP)
+	t := ir.Typ(ir.TMAP)
 	t.Down = key
 	t.Type = val
-	return t
+	return sharetype(t)
 }
 
 // methcmp sorts by symbol, then by package path for unexported symbols.
-type methcmp []*Field
+type methcmp []*ir.Field
 
 func (x methcmp) Len() int      { return len(x) }
 func (x methcmp) Swap(i, j int) { x[i], x[j] = x[j], x[i] }
@@ -434,33 +474,33 @@ func (x methcmp) Less(i, j int) bool {
 	return false
 }
 
-func Nodintconst(v int64) *Node {
-	c := Nod(OLITERAL, nil, nil)
+func Nodintconst(v int64) *ir.Node {
+	c := Nod(ir.OLITERAL, nil, nil)
 	c.Addable = true
-	c.SetVal(Val{new(Mpint)})
-	c.Val().U.(*Mpint).SetInt64(v)
-	c.Type = Types[TIDEAL]
+	c.SetVal(ir.Val{U: new(ir.Mpint)})
+	c.Val().U.(*ir.Mpint).SetInt64(v)
+	c.Type = ir.Types[ir.TIDEAL]
 	ullmancalc(c)
 	return c
 }
 
-func nodfltconst(v *Mpflt) *Node {
-	c := Nod(OLITERAL, nil, nil)
+func nodfltconst(v *ir.Mpflt) *ir.Node {
+	c := Nod(ir.OLITERAL, nil, nil)
 	c.Addable = true
-	c.SetVal(Val{newMpflt()})
-	c.Val().U.(*Mpflt).Set(v)
-	c.Type = Types[TIDEAL]
+	c.SetVal(ir.Val{U: ir.NewMpflt()})
+	c.Val().U.(*ir.Mpflt).Set(v)
+	c.Type = ir.Types[ir.TIDEAL]
 	ullmancalc(c)
 	return c
 }
 
-func Nodconst(n *Node, t *Type, v int64) {
-	*n = Node{}
-	n.Op = OLITERAL
+func Nodconst(n *ir.Node, t *ir.Type, v int64) {
+	*n = ir.Node{}
+	n.Op = ir.OLITERAL
 	n.Addable = true
 	ullmancalc(n)
-	n.SetVal(Val{new(Mpint)})
-	n.Val().U.(*Mpint).SetInt64(v)
+	n.SetVal(ir.Val{U: new(ir.Mpint)})
+	n.Val().U.(*ir.Mpint).SetInt64(v)
 	n.Type = t
 
 	if Isfloat[t.Etype] {
@@ -468,30 +508,34 @@ func Nodconst(n *Node, t *Type, v int64) {
 	}
 }
 
-func nodnil() *Node {
-	c := Nodintconst(0)
-	c.SetVal(Val{new(NilVal)})
-	c.Type = Types[TNIL]
+// nodconstval returns a new constant with value v and type t.
+func nodconstval(v ir.Val, t *ir.Type) *ir.Node {
+	c := Nod(ir.OLITERAL, nil, nil)
+	c.Addable = true
+	c.SetVal(v)
+	c.Type = t
+	ullmancalc(c)
 	return c
 }
 
-func Nodbool(b bool) *Node {
-	c := Nodintconst(0)
-	c.SetVal(Val{b})
-	c.Type = idealbool
-	return c
+func nodnil() *ir.Node {
+	return nodconstval(ir.Val{U: new(ir.NilVal)}, ir.Types[ir.TNIL])
+}
+
+func Nodbool(b bool) *ir.Node {
+	return nodconstval(ir.Val{U: b}, ir.Idealbool)
 }
 
-func aindex(b *Node, t *Type) *Type {
+func aindex(b *ir.Node, t *ir.Type) *ir.Type {
 	bound := int64(-1) // open bound
 	b = typecheck(b, Erv)
 	if b != nil {
-		switch consttype(b) {
+		switch ir.Consttype(b) {
 		default:
 			Yyerror("array bound must be an integer expression")
 
-		case CTINT, CTRUNE:
-			bound = b.Val().U.(*Mpint).Int64()
+		case ir.CTINT, ir.CTRUNE:
+			bound = b.Val().U.(*ir.Mpint).Int64()
 			if bound < 0 {
 				Yyerror("array bound must be non negative")
 			}
@@ -499,7 +543,7 @@ func aindex(b *Node, t *Type) *Type {
 	}
 
 	// fixed array
-	r := typ(TARRAY)
+	r := ir.Typ(ir.TARRAY)
 
 	r.Type = t
 	r.Bound = bound
@@ -507,11 +551,10 @@ func aindex(b *Node, t *Type) *Type {
 }
 
 // treecopy recursively copies n, with the exception of
-// ONAME, OLITERAL, OTYPE, and non-iota ONONAME leaves.
-// Copies of iota ONONAME nodes are assigned the current
-// value of iota_. If lineno != 0, it sets the line number
+// ONAME, OLITERAL, OTYPE, and ONONAME leaves.
+// If lineno != 0, it sets the line number
 // of newly allocated nodes to lineno.
-func treecopy(n *Node, lineno int32) *Node {
+func treecopy(n *ir.Node, lineno int32) *ir.Node {
 	if n == nil {
 		return nil
 	}
@@ -523,45 +566,29 @@ func treecopy(n *Node, lineno int32) *Node {
 		m.Left = treecopy(n.Left, lineno)
 		m.Right = treecopy(n.Right, lineno)
 		m.List.Set(listtreecopy(n.List.Slice(), lineno))
+		m.Rlist.Set(listtreecopy(n.Rlist.Slice(), lineno))
 		if lineno != 0 {
 			m.Lineno = lineno
 		}
-		if m.Name != nil && n.Op != ODCLFIELD {
+		if m.Name != nil && n.Op != ir.ODCLFIELD {
 			Dump("treecopy", n)
 			Fatalf("treecopy Name")
 		}
 		return &m
 
-	case ONONAME:
-		if n.Sym == Lookup("iota") {
-			// Not sure yet whether this is the real iota,
-			// but make a copy of the Node* just in case,
-			// so that all the copies of this const definition
-			// don't have the same iota value.
-			m := *n
-			if lineno != 0 {
-				m.Lineno = lineno
-			}
-			m.Name = new(Name)
-			*m.Name = *n.Name
-			m.Name.Iota = iota_
-			return &m
-		}
-		return n
-
-	case ONAME, OLITERAL, OTYPE:
+	case ir.ONAME, ir.ONONAME, ir.OLITERAL, ir.OTYPE:
 		return n
 	}
 }
 
 // isnil reports whether n represents the universal untyped zero value "nil".
-func isnil(n *Node) bool {
+func isnil(n *ir.Node) bool {
 	// Check n.Orig because constant propagation may produce typed nil constants,
 	// which don't exist in the Go spec.
-	return Isconst(n.Orig, CTNIL)
+	return ir.Isconst(n.Orig, ir.CTNIL)
 }
 
-func isptrto(t *Type, et EType) bool {
+func isptrto(t *ir.Type, et ir.EType) bool {
 	if t == nil {
 		return false
 	}
@@ -578,46 +605,46 @@ func isptrto(t *Type, et EType) bool {
 	return true
 }
 
-func Istype(t *Type, et EType) bool {
+func Istype(t *ir.Type, et ir.EType) bool {
 	return t != nil && t.Etype == et
 }
 
-func Isfixedarray(t *Type) bool {
-	return t != nil && t.Etype == TARRAY && t.Bound >= 0
+func Isfixedarray(t *ir.Type) bool {
+	return t != nil && t.Etype == ir.TARRAY && t.Bound >= 0
 }
 
-func Isslice(t *Type) bool {
-	return t != nil && t.Etype == TARRAY && t.Bound < 0
+func Isslice(t *ir.Type) bool {
+	return t != nil && t.Etype == ir.TARRAY && t.Bound < 0
 }
 
-func isblank(n *Node) bool {
+func isblank(n *ir.Node) bool {
 	if n == nil {
 		return false
 	}
 	return isblanksym(n.Sym)
 }
 
-func isblanksym(s *Sym) bool {
+func isblanksym(s *ir.Sym) bool {
 	return s != nil && s.Name == "_"
 }
 
-func Isinter(t *Type) bool {
-	return t != nil && t.Etype == TINTER
+func Isinter(t *ir.Type) bool {
+	return t != nil && t.Etype == ir.TINTER
 }
 
-func isnilinter(t *Type) bool {
+func isnilinter(t *ir.Type) bool {
 	return Isinter(t) && t.NumFields() == 0
 }
 
-func isideal(t *Type) bool {
+func isideal(t *ir.Type) bool {
 	if t == nil {
 		return false
 	}
-	if t == idealstring || t == idealbool {
+	if t == ir.Idealstring || t == ir.Idealbool {
 		return true
 	}
 	switch t.Etype {
-	case TNIL, TIDEAL:
+	case ir.TNIL, ir.TIDEAL:
 		return true
 	}
 
@@ -626,7 +653,7 @@ func isideal(t *Type) bool {
 
 // given receiver of type t (t == r or t == *r)
 // return type to hang methods off (r).
-func methtype(t *Type, mustname int) *Type {
+func methtype(t *ir.Type, mustname int) *ir.Type {
 	if t == nil {
 		return nil
 	}
@@ -643,7 +670,7 @@ func methtype(t *Type, mustname int) *Type {
 	}
 
 	// need a type name
-	if t.Sym == nil && (mustname != 0 || t.Etype != TSTRUCT) {
+	if t.Sym == nil && (mustname != 0 || t.Etype != ir.TSTRUCT) {
 		return nil
 	}
 
@@ -653,12 +680,12 @@ func methtype(t *Type, mustname int) *Type {
 		default:
 			return nil
 
-		case TSTRUCT,
-			TARRAY,
-			TMAP,
-			TCHAN,
-			TSTRING,
-			TFUNC:
+		case ir.TSTRUCT,
+			ir.TARRAY,
+			ir.TMAP,
+			ir.TCHAN,
+			ir.TSTRING,
+			ir.TFUNC:
 			break
 		}
 	}
@@ -666,16 +693,16 @@ func methtype(t *Type, mustname int) *Type {
 	return t
 }
 
-func cplxsubtype(et EType) EType {
+func cplxsubtype(et ir.EType) ir.EType {
 	switch et {
-	case TCOMPLEX64:
-		return TFLOAT32
+	case ir.TCOMPLEX64:
+		return ir.TFLOAT32
 
-	case TCOMPLEX128:
-		return TFLOAT64
+	case ir.TCOMPLEX128:
+		return ir.TFLOAT64
 	}
 
-	Fatalf("cplxsubtype: %v\n", Econv(et))
+	Fatalf("cplxsubtype: %v\n", ir.Econv(et))
 	return 0
 }
 
@@ -689,16 +716,22 @@ func eqnote(a, b *string) bool {
 // named, it is only identical to the other if they are the same
 // pointer (t1 == t2), so there's no chance of chasing cycles
 // ad infinitum, so no need for a depth counter.
-func Eqtype(t1, t2 *Type) bool {
-	return eqtype1(t1, t2, nil)
+func Eqtype(t1, t2 *ir.Type) bool {
+	return eqtype1(t1, t2, true, nil)
+}
+
+// eqtypeIgnoreTags is like Eqtype but ignores the tags of struct
+// fields, as the conversion rules do.
+func eqtypeIgnoreTags(t1, t2 *ir.Type) bool {
+	return eqtype1(t1, t2, false, nil)
 }
 
 type typePair struct {
-	t1 *Type
-	t2 *Type
+	t1 *ir.Type
+	t2 *ir.Type
 }
 
-func eqtype1(t1, t2 *Type, assumedEqual map[typePair]struct{}) bool {
+func eqtype1(t1, t2 *ir.Type, cmpTags bool, assumedEqual map[typePair]struct{}) bool {
 	if t1 == t2 {
 		return true
 	}
@@ -709,10 +742,10 @@ func eqtype1(t1, t2 *Type, assumedEqual map[typePair]struct{}) bool {
 		// Special case: we keep byte/uint8 and rune/int32
 		// separate for error messages. Treat them as equal.
 		switch t1.Etype {
-		case TUINT8:
-			return (t1 == Types[TUINT8] || t1 == bytetype) && (t2 == Types[TUINT8] || t2 == bytetype)
-		case TINT32:
-			return (t1 == Types[TINT32] || t1 == runetype) && (t2 == Types[TINT32] || t2 == runetype)
+		case ir.TUINT8:
+			return (t1 == ir.Types[ir.TUINT8] || t1 == ir.Bytetype) && (t2 == ir.Types[ir.TUINT8] || t2 == ir.Bytetype)
+		case ir.TINT32:
+			return (t1 == ir.Types[ir.TINT32] || t1 == ir.Runetype) && (t2 == ir.Types[ir.TINT32] || t2 == ir.Runetype)
 		default:
 			return false
 		}
@@ -726,11 +759,11 @@ func eqtype1(t1, t2 *Type, assumedEqual map[typePair]struct{}) bool {
 	assumedEqual[typePair{t1, t2}] = struct{}{}
 
 	switch t1.Etype {
-	case TINTER, TSTRUCT:
-		t1, i1 := IterFields(t1)
-		t2, i2 := IterFields(t2)
+	case ir.TINTER, ir.TSTRUCT:
+		t1, i1 := ir.IterFields(t1)
+		t2, i2 := ir.IterFields(t2)
 		for ; t1 != nil && t2 != nil; t1, t2 = i1.Next(), i2.Next() {
-			if t1.Sym != t2.Sym || t1.Embedded != t2.Embedded || !eqtype1(t1.Type, t2.Type, assumedEqual) || !eqnote(t1.Note, t2.Note) {
+			if t1.Sym != t2.Sym || t1.Embedded != t2.Embedded || !eqtype1(t1.Type, t2.Type, cmpTags, assumedEqual) || cmpTags && !eqnote(t1.Note, t2.Note) {
 				return false
 			}
 		}
@@ -740,16 +773,16 @@ func eqtype1(t1, t2 *Type, assumedEqual map[typePair]struct{}) bool {
 		}
 		return false
 
-	case TFUNC:
+	case ir.TFUNC:
 		// Check parameters and result parameters for type equality.
 		// We intentionally ignore receiver parameters for type
 		// equality, because they're never relevant.
-		for _, f := range paramsResults {
+		for _, f := range ir.ParamsResults {
 			// Loop over fields in structs, ignoring argument names.
-			ta, ia := IterFields(f(t1))
-			tb, ib := IterFields(f(t2))
+			ta, ia := ir.IterFields(f(t1))
+			tb, ib := ir.IterFields(f(t2))
 			for ; ta != nil && tb != nil; ta, tb = ia.Next(), ib.Next() {
-				if ta.Isddd != tb.Isddd || !eqtype1(ta.Type, tb.Type, assumedEqual) {
+				if ta.Isddd != tb.Isddd || !eqtype1(ta.Type, tb.Type, cmpTags, assumedEqual) {
 					return false
 				}
 			}
@@ -759,35 +792,35 @@ func eqtype1(t1, t2 *Type, assumedEqual map[typePair]struct{}) bool {
 		}
 		return true
 
-	case TARRAY:
+	case ir.TARRAY:
 		if t1.Bound != t2.Bound {
 			return false
 		}
 
-	case TCHAN:
+	case ir.TCHAN:
 		if t1.Chan != t2.Chan {
 			return false
 		}
 
-	case TMAP:
-		if !eqtype1(t1.Key(), t2.Key(), assumedEqual) {
+	case ir.TMAP:
+		if !eqtype1(t1.Key(), t2.Key(), cmpTags, assumedEqual) {
 			return false
 		}
 	}
 
-	return eqtype1(t1.Type, t2.Type, assumedEqual)
+	return eqtype1(t1.Type, t2.Type, cmpTags, assumedEqual)
 }
 
 // Are t1 and t2 equal struct types when field names are ignored?
 // For deciding whether the result struct from g can be copied
 // directly when compiling f(g()).
-func eqtypenoname(t1 *Type, t2 *Type) bool {
-	if t1 == nil || t2 == nil || t1.Etype != TSTRUCT || t2.Etype != TSTRUCT {
+func eqtypenoname(t1 *ir.Type, t2 *ir.Type) bool {
+	if t1 == nil || t2 == nil || t1.Etype != ir.TSTRUCT || t2.Etype != ir.TSTRUCT {
 		return false
 	}
 
-	f1, i1 := IterFields(t1)
-	f2, i2 := IterFields(t2)
+	f1, i1 := ir.IterFields(t1)
+	f2, i2 := ir.IterFields(t2)
 	for {
 		if !Eqtype(f1.Type, f2.Type) {
 			return false
@@ -803,28 +836,28 @@ func eqtypenoname(t1 *Type, t2 *Type) bool {
 // Is type src assignment compatible to type dst?
 // If so, return op code to use in conversion.
 // If not, return 0.
-func assignop(src *Type, dst *Type, why *string) Op {
+func assignop(src *ir.Type, dst *ir.Type, why *string) ir.Op {
 	if why != nil {
 		*why = ""
 	}
 
 	// TODO(rsc,lvd): This behaves poorly in the presence of inlining.
 	// https://golang.org/issue/2795
-	if safemode != 0 && importpkg == nil && src != nil && src.Etype == TUNSAFEPTR {
+	if safemode != 0 && importpkg == nil && src != nil && src.Etype == ir.TUNSAFEPTR {
 		Yyerror("cannot use unsafe.Pointer")
 		errorexit()
 	}
 
 	if src == dst {
-		return OCONVNOP
+		return ir.OCONVNOP
 	}
-	if src == nil || dst == nil || src.Etype == TFORW || dst.Etype == TFORW || src.Orig == nil || dst.Orig == nil {
+	if src == nil || dst == nil || src.Etype == ir.TFORW || dst.Etype == ir.TFORW || src.Orig == nil || dst.Orig == nil {
 		return 0
 	}
 
 	// 1. src type is identical to dst.
 	if Eqtype(src, dst) {
-		return OCONVNOP
+		return ir.OCONVNOP
 	}
 
 	// 2. src and dst have identical underlying types
@@ -833,50 +866,42 @@ func assignop(src *Type, dst *Type, why *string) Op {
 	// For assignable but different non-empty interface types,
 	// we want to recompute the itab.
 	if Eqtype(src.Orig, dst.Orig) && (src.Sym == nil || dst.Sym == nil || isnilinter(src)) {
-		return OCONVNOP
+		return ir.OCONVNOP
 	}
 
 	// 3. dst is an interface type and src implements dst.
-	if dst.Etype == TINTER && src.Etype != TNIL {
-		var missing, have *Field
+	if dst.Etype == ir.TINTER && src.Etype != ir.TNIL {
+		var missing, have *ir.Field
 		var ptr int
 		if implements(src, dst, &missing, &have, &ptr) {
-			return OCONVIFACE
+			return ir.OCONVIFACE
 		}
 
 		// we'll have complained about this method anyway, suppress spurious messages.
 		if have != nil && have.Sym == missing.Sym && (have.Type.Broke || missing.Type.Broke) {
-			return OCONVIFACE
+			return ir.OCONVIFACE
 		}
 
 		if why != nil {
-			if isptrto(src, TINTER) {
+			if isptrto(src, ir.TINTER) {
 				*why = fmt.Sprintf(":\n\t%v is pointer to interface, not interface", src)
-			} else if have != nil && have.Sym == missing.Sym && have.Nointerface {
-				*why = fmt.Sprintf(":\n\t%v does not implement %v (%v method is marked 'nointerface')", src, dst, missing.Sym)
-			} else if have != nil && have.Sym == missing.Sym {
-				*why = fmt.Sprintf(":\n\t%v does not implement %v (wrong type for %v method)\n"+"\t\thave %v%v\n\t\twant %v%v", src, dst, missing.Sym, have.Sym, Tconv(have.Type, FmtShort|FmtByte), missing.Sym, Tconv(missing.Type, FmtShort|FmtByte))
-			} else if ptr != 0 {
-				*why = fmt.Sprintf(":\n\t%v does not implement %v (%v method has pointer receiver)", src, dst, missing.Sym)
-			} else if have != nil {
-				*why = fmt.Sprintf(":\n\t%v does not implement %v (missing %v method)\n"+"\t\thave %v%v\n\t\twant %v%v", src, dst, missing.Sym, have.Sym, Tconv(have.Type, FmtShort|FmtByte), missing.Sym, Tconv(missing.Type, FmtShort|FmtByte))
 			} else {
-				*why = fmt.Sprintf(":\n\t%v does not implement %v (missing %v method)", src, dst, missing.Sym)
+				*why = fmt.Sprintf(":\n\t%v does not implement %v %s", src, dst, missingmethod(missing, have, ptr))
 			}
 		}
 
 		return 0
 	}
 
-	if isptrto(dst, TINTER) {
+	if isptrto(dst, ir.TINTER) {
 		if why != nil {
 			*why = fmt.Sprintf(":\n\t%v is pointer to interface, not interface", dst)
 		}
 		return 0
 	}
 
-	if src.Etype == TINTER && dst.Etype != TBLANK {
-		var missing, have *Field
+	if src.Etype == ir.TINTER && dst.Etype != ir.TBLANK {
+		var missing, have *ir.Field
 		var ptr int
 		if why != nil && implements(dst, src, &missing, &have, &ptr) {
 			*why = ": need type assertion"
@@ -887,51 +912,66 @@ func assignop(src *Type, dst *Type, why *string) Op {
 	// 4. src is a bidirectional channel value, dst is a channel type,
 	// src and dst have identical element types, and
 	// either src or dst is not a named type.
-	if src.Etype == TCHAN && src.Chan == Cboth && dst.Etype == TCHAN {
+	if src.Etype == ir.TCHAN && src.Chan == Cboth && dst.Etype == ir.TCHAN {
 		if Eqtype(src.Type, dst.Type) && (src.Sym == nil || dst.Sym == nil) {
-			return OCONVNOP
+			return ir.OCONVNOP
 		}
 	}
 
 	// 5. src is the predeclared identifier nil and dst is a nillable type.
-	if src.Etype == TNIL {
+	if src.Etype == ir.TNIL {
 		switch dst.Etype {
-		case TARRAY:
+		case ir.TARRAY:
 			if dst.Bound != -100 { // not slice
 				break
 			}
 			fallthrough
 
-		case TPTR32,
-			TPTR64,
-			TFUNC,
-			TMAP,
-			TCHAN,
-			TINTER:
-			return OCONVNOP
+		case ir.TPTR32,
+			ir.TPTR64,
+			ir.TFUNC,
+			ir.TMAP,
+			ir.TCHAN,
+			ir.TINTER:
+			return ir.OCONVNOP
 		}
 	}
 
 	// 6. rule about untyped constants - already converted by defaultlit.
 
 	// 7. Any typed value can be assigned to the blank identifier.
-	if dst.Etype == TBLANK {
-		return OCONVNOP
+	if dst.Etype == ir.TBLANK {
+		return ir.OCONVNOP
+	}
+
+	// Types that differ only in struct tags can be converted,
+	// but not assigned, to each other.
+	if why != nil && tagsDiffer(src, dst) {
+		*why = ": struct tags differ, need conversion"
 	}
 
 	return 0
 }
 
+// tagsDiffer reports whether src and dst, or the types that they point
+// to, have underlying types that differ only in struct tags.
+func tagsDiffer(src, dst *ir.Type) bool {
+	if Isptr[src.Etype] && Isptr[dst.Etype] && src.Sym == nil && dst.Sym == nil {
+		src, dst = src.Type, dst.Type
+	}
+	return !Eqtype(src.Orig, dst.Orig) && eqtypeIgnoreTags(src.Orig, dst.Orig)
+}
+
 // Can we convert a value of type src to a value of type dst?
 // If so, return op code to use in conversion (maybe OCONVNOP).
 // If not, return 0.
-func convertop(src *Type, dst *Type, why *string) Op {
+func convertop(src *ir.Type, dst *ir.Type, why *string) ir.Op {
 	if why != nil {
 		*why = ""
 	}
 
 	if src == dst {
-		return OCONVNOP
+		return ir.OCONVNOP
 	}
 	if src == nil || dst == nil {
 		return 0
@@ -947,109 +987,253 @@ func convertop(src *Type, dst *Type, why *string) Op {
 	// than assignments. If interfaces are involved, stop now
 	// with the good message from assignop.
 	// Otherwise clear the error.
-	if src.Etype == TINTER || dst.Etype == TINTER {
+	if src.Etype == ir.TINTER || dst.Etype == ir.TINTER {
 		return 0
 	}
 	if why != nil {
 		*why = ""
 	}
 
-	// 2. src and dst have identical underlying types.
-	if Eqtype(src.Orig, dst.Orig) {
-		return OCONVNOP
+	// 2. Ignoring struct tags, src and dst have identical underlying types.
+	if eqtypeIgnoreTags(src.Orig, dst.Orig) {
+		return ir.OCONVNOP
 	}
 
-	// 3. src and dst are unnamed pointer types
-	// and their base types have identical underlying types.
+	// 3. src and dst are unnamed pointer types and, ignoring struct tags,
+	// their base types have identical underlying types.
 	if Isptr[src.Etype] && Isptr[dst.Etype] && src.Sym == nil && dst.Sym == nil {
-		if Eqtype(src.Type.Orig, dst.Type.Orig) {
-			return OCONVNOP
+		if eqtypeIgnoreTags(src.Type.Orig, dst.Type.Orig) {
+			return ir.OCONVNOP
 		}
 	}
 
 	// 4. src and dst are both integer or floating point types.
 	if (Isint[src.Etype] || Isfloat[src.Etype]) && (Isint[dst.Etype] || Isfloat[dst.Etype]) {
 		if Simtype[src.Etype] == Simtype[dst.Etype] {
-			return OCONVNOP
+			return ir.OCONVNOP
 		}
-		return OCONV
+		return ir.OCONV
 	}
 
 	// 5. src and dst are both complex types.
 	if Iscomplex[src.Etype] && Iscomplex[dst.Etype] {
 		if Simtype[src.Etype] == Simtype[dst.Etype] {
-			return OCONVNOP
+			return ir.OCONVNOP
 		}
-		return OCONV
+		return ir.OCONV
 	}
 
 	// 6. src is an integer or has type []byte or []rune
 	// and dst is a string type.
-	if Isint[src.Etype] && dst.Etype == TSTRING {
-		return ORUNESTR
+	if Isint[src.Etype] && dst.Etype == ir.TSTRING {
+		return ir.ORUNESTR
 	}
 
-	if Isslice(src) && dst.Etype == TSTRING {
-		if src.Type.Etype == bytetype.Etype {
-			return OARRAYBYTESTR
+	if Isslice(src) && dst.Etype == ir.TSTRING {
+		if src.Type.Etype == ir.Bytetype.Etype {
+			return ir.OARRAYBYTESTR
 		}
-		if src.Type.Etype == runetype.Etype {
-			return OARRAYRUNESTR
+		if src.Type.Etype == ir.Runetype.Etype {
+			return ir.OARRAYRUNESTR
 		}
 	}
 
 	// 7. src is a string and dst is []byte or []rune.
 	// String to slice.
-	if src.Etype == TSTRING && Isslice(dst) {
-		if dst.Type.Etype == bytetype.Etype {
-			return OSTRARRAYBYTE
+	if src.Etype == ir.TSTRING && Isslice(dst) {
+		if dst.Type.Etype == ir.Bytetype.Etype {
+			return ir.OSTRARRAYBYTE
 		}
-		if dst.Type.Etype == runetype.Etype {
-			return OSTRARRAYRUNE
+		if dst.Type.Etype == ir.Runetype.Etype {
+			return ir.OSTRARRAYRUNE
 		}
 	}
 
 	// 8. src is a pointer or uintptr and dst is unsafe.Pointer.
-	if (Isptr[src.Etype] || src.Etype == TUINTPTR) && dst.Etype == TUNSAFEPTR {
-		return OCONVNOP
+	if (Isptr[src.Etype] || src.Etype == ir.TUINTPTR) && dst.Etype == ir.TUNSAFEPTR {
+		return ir.OCONVNOP
 	}
 
 	// 9. src is unsafe.Pointer and dst is a pointer or uintptr.
-	if src.Etype == TUNSAFEPTR && (Isptr[dst.Etype] || dst.Etype == TUINTPTR) {
-		return OCONVNOP
+	if src.Etype == ir.TUNSAFEPTR && (Isptr[dst.Etype] || dst.Etype == ir.TUINTPTR) {
+		return ir.OCONVNOP
 	}
 
+	if why != nil {
+		*why = convertwhy(src, dst)
+	}
 	return 0
 }
 
-func assignconv(n *Node, t *Type, context string) *Node {
+// convertwhy explains why convertop rejected a conversion from src to dst,
+// neither of which is an interface. It shows the underlying types of
+// named types and where the underlying types differ. The explanation
+// is empty if there is nothing to add to the types themselves.
+func convertwhy(src, dst *ir.Type) string {
+	var buf bytes.Buffer
+	for _, t := range []*ir.Type{src, dst} {
+		if u := Tconv(t, ir.FmtLong); u != Tconv(t, 0) {
+			fmt.Fprintf(&buf, "\n\t%v has underlying type %s", t, u)
+		}
+	}
+
+	// If the underlying types are different kinds of type,
+	// the types themselves say it all.
+	if src.Etype == dst.Etype {
+		if path, detail := typediff(src, dst); detail != "" {
+			if len(path) > 0 {
+				detail = strings.Join(path, ", ") + ": " + detail
+			}
+			fmt.Fprintf(&buf, "\n\ttypes differ in %s", detail)
+		}
+	}
+
+	if buf.Len() == 0 {
+		return ""
+	}
+	return ":" + buf.String()
+}
+
+// typediff finds the first difference between the structures of t1 and t2,
+// ignoring their names and any struct tags. It returns the path to the
+// difference, such as "field x" and "element type", and a description of it.
+// The description is empty if the structures are identical.
+// Component types that are named must be identical.
+func typediff(t1, t2 *ir.Type) (path []string, detail string) {
+	if t1.Etype != t2.Etype {
+		return nil, fmt.Sprintf("%v vs %v", t1, t2)
+	}
+
+	switch t1.Etype {
+	case ir.TPTR32, ir.TPTR64:
+		return elemdiff("pointer base type", t1.Type, t2.Type)
+
+	case ir.TARRAY:
+		if t1.Bound != t2.Bound {
+			if t1.Bound < 0 || t2.Bound < 0 {
+				return nil, fmt.Sprintf("slice or array: %v vs %v", Tconv(t1, ir.FmtLong), Tconv(t2, ir.FmtLong))
+			}
+			return nil, fmt.Sprintf("array length: %d vs %d", t1.Bound, t2.Bound)
+		}
+		return elemdiff("element type", t1.Type, t2.Type)
+
+	case ir.TCHAN:
+		if t1.Chan != t2.Chan {
+			return nil, fmt.Sprintf("channel direction: %v vs %v", Tconv(t1, ir.FmtLong), Tconv(t2, ir.FmtLong))
+		}
+		return elemdiff("element type", t1.Type, t2.Type)
+
+	case ir.TMAP:
+		if path, detail := elemdiff("key type", t1.Key(), t2.Key()); detail != "" {
+			return path, detail
+		}
+		return elemdiff("value type", t1.Type, t2.Type)
+
+	case ir.TSTRUCT, ir.TINTER:
+		what := "field"
+		if t1.Etype == ir.TINTER {
+			what = "method"
+		}
+		f1, f2 := t1.Fields().Slice(), t2.Fields().Slice()
+		for i := 0; i < len(f1) && i < len(f2); i++ {
+			a, b := f1[i], f2[i]
+			if a.Sym != b.Sym {
+				if fieldname(a) == fieldname(b) {
+					return nil, fmt.Sprintf("%s %s: unexported names from different packages", what, fieldname(a))
+				}
+				return nil, fmt.Sprintf("name of %s %d: %s vs %s", what, i+1, fieldname(a), fieldname(b))
+			}
+			if a.Embedded != b.Embedded {
+				return nil, fmt.Sprintf("%s %s: embedded in only one", what, fieldname(a))
+			}
+			if path, detail := elemdiff(what+" "+fieldname(a), a.Type, b.Type); detail != "" {
+				return path, detail
+			}
+		}
+		if len(f1) != len(f2) {
+			return nil, fmt.Sprintf("number of %ss: %d vs %d", what, len(f1), len(f2))
+		}
+
+	case ir.TFUNC:
+		for i, f := range ir.ParamsResults {
+			what := [...]string{"parameter", "result"}[i]
+			p1, p2 := f(t1).Fields().Slice(), f(t2).Fields().Slice()
+			if len(p1) != len(p2) {
+				return nil, fmt.Sprintf("number of %ss: %d vs %d", what, len(p1), len(p2))
+			}
+			for j := range p1 {
+				if p1[j].Isddd != p2[j].Isddd {
+					return nil, fmt.Sprintf("%s %d: variadic in only one", what, j+1)
+				}
+				if path, detail := elemdiff(fmt.Sprintf("%s %d", what, j+1), p1[j].Type, p2[j].Type); detail != "" {
+					return path, detail
+				}
+			}
+		}
+	}
+
+	return nil, ""
+}
+
+// elemdiff is like typediff for the component types t1 and t2 described by
+// what. Named component types are different unless they are identical.
+func elemdiff(what string, t1, t2 *ir.Type) (path []string, detail string) {
+	if eqtypeIgnoreTags(t1, t2) {
+		return nil, ""
+	}
+	if t1.Sym == nil && t2.Sym == nil {
+		path, detail = typediff(t1, t2)
+		if detail != "" {
+			return append([]string{what}, path...), detail
+		}
+	}
+	return []string{what}, fmt.Sprintf("%v vs %v", t1, t2)
+}
+
+// fieldname returns the name of the struct field or interface method f
+// for use in error messages.
+func fieldname(f *ir.Field) string {
+	if f.Sym == nil {
+		return "_"
+	}
+	return f.Sym.Name
+}
+
+func assignconv(n *ir.Node, t *ir.Type, context string) *ir.Node {
 	return assignconvfn(n, t, func() string { return context })
 }
 
 // Convert node n for assignment to type t.
-func assignconvfn(n *Node, t *Type, context func() string) *Node {
+func assignconvfn(n *ir.Node, t *ir.Type, context func() string) *ir.Node {
+	return assignconv1(n, t, context, context)
+}
+
+// assignconv1 is like assignconvfn, but describes the assignment
+// in constant overflow errors with overflowContext, which may say
+// more about where t came from than the "cannot use" errors do.
+func assignconv1(n *ir.Node, t *ir.Type, context, overflowContext func() string) *ir.Node {
 	if n == nil || n.Type == nil || n.Type.Broke {
 		return n
 	}
 
-	if t.Etype == TBLANK && n.Type.Etype == TNIL {
+	if t.Etype == ir.TBLANK && n.Type.Etype == ir.TNIL {
 		Yyerror("use of untyped nil")
 	}
 
 	old := n
 	old.Diag++ // silence errors about n; we'll issue one below
-	n = defaultlit(n, t)
+	n = defaultlitfn(n, t, overflowContext)
 	old.Diag--
-	if t.Etype == TBLANK {
+	if t.Etype == ir.TBLANK {
 		return n
 	}
 
 	// Convert ideal bool from comparison to plain bool
 	// if the next step is non-bool (like interface{}).
-	if n.Type == idealbool && t.Etype != TBOOL {
-		if n.Op == ONAME || n.Op == OLITERAL {
-			r := Nod(OCONVNOP, n, nil)
-			r.Type = Types[TBOOL]
+	if n.Type == ir.Idealbool && t.Etype != ir.TBOOL {
+		if n.Op == ir.ONAME || n.Op == ir.OLITERAL {
+			r := Nod(ir.OCONVNOP, n, nil)
+			r.Type = ir.Types[ir.TBOOL]
 			r.Typecheck = 1
 			r.Implicit = true
 			n = r
@@ -1063,8 +1247,8 @@ func assignconvfn(n *Node, t *Type, context func() string) *Node {
 	var why string
 	op := assignop(n.Type, t, &why)
 	if op == 0 {
-		Yyerror("cannot use %v as type %v in %s%s", Nconv(n, FmtLong), t, context(), why)
-		op = OCONV
+		Yyerror("cannot use %v as type %v in %s%s", Nconv(n, ir.FmtLong), t, context(), why)
+		op = ir.OCONV
 	}
 
 	r := Nod(op, n, nil)
@@ -1080,7 +1264,7 @@ func assignconvfn(n *Node, t *Type, context func() string) *Node {
 // type syntax expression n.Type.
 // The result of substArgTypes MUST be assigned back to old, e.g.
 // 	n.Left = substArgTypes(n.Left, t1, t2)
-func substArgTypes(old *Node, types ...*Type) *Node {
+func substArgTypes(old *ir.Node, types ...*ir.Type) *ir.Node {
 	n := *old // make shallow copy
 
 	for _, t := range types {
@@ -1095,7 +1279,7 @@ func substArgTypes(old *Node, types ...*Type) *Node {
 
 // substAny walks t, replacing instances of "any" with successive
 // elements removed from types.  It returns the substituted type.
-func substAny(t *Type, types *[]*Type) *Type {
+func substAny(t *ir.Type, types *[]*ir.Type) *ir.Type {
 	if t == nil {
 		return nil
 	}
@@ -1104,21 +1288,21 @@ func substAny(t *Type, types *[]*Type) *Type {
 	default:
 		// Leave the type unchanged.
 
-	case TANY:
+	case ir.TANY:
 		if len(*types) == 0 {
 			Fatalf("substArgTypes: not enough argument types")
 		}
 		t = (*types)[0]
 		*types = (*types)[1:]
 
-	case TPTR32, TPTR64, TCHAN, TARRAY:
+	case ir.TPTR32, ir.TPTR64, ir.TCHAN, ir.TARRAY:
 		elem := substAny(t.Type, types)
 		if elem != t.Type {
 			t = t.Copy()
 			t.Type = elem
 		}
 
-	case TMAP:
+	case ir.TMAP:
 		key := substAny(t.Down, types)
 		val := substAny(t.Type, types)
 		if key != t.Down || val != t.Type {
@@ -1127,7 +1311,7 @@ func substAny(t *Type, types *[]*Type) *Type {
 			t.Type = val
 		}
 
-	case TFUNC:
+	case ir.TFUNC:
 		recvs := substAny(t.Recvs(), types)
 		params := substAny(t.Params(), types)
 		results := substAny(t.Results(), types)
@@ -1146,9 +1330,9 @@ func substAny(t *Type, types *[]*Type) *Type {
 			*t.ParamsP() = params
 		}
 
-	case TSTRUCT:
+	case ir.TSTRUCT:
 		// nfs only has to be big enough for the builtin functions.
-		var nfs [8]*Field
+		var nfs [8]*ir.Field
 		fields := t.FieldSlice()
 		changed := false
 		for i, f := range fields {
@@ -1186,12 +1370,12 @@ func substAny(t *Type, types *[]*Type) *Type {
 }
 
 // Is this a 64-bit type?
-func Is64(t *Type) bool {
+func Is64(t *ir.Type) bool {
 	if t == nil {
 		return false
 	}
 	switch Simtype[t.Etype] {
-	case TINT64, TUINT64, TPTR64:
+	case ir.TINT64, ir.TUINT64, ir.TPTR64:
 		return true
 	}
 
@@ -1199,34 +1383,34 @@ func Is64(t *Type) bool {
 }
 
 // Is a conversion between t1 and t2 a no-op?
-func Noconv(t1 *Type, t2 *Type) bool {
+func Noconv(t1 *ir.Type, t2 *ir.Type) bool {
 	e1 := Simtype[t1.Etype]
 	e2 := Simtype[t2.Etype]
 
 	switch e1 {
-	case TINT8, TUINT8:
-		return e2 == TINT8 || e2 == TUINT8
+	case ir.TINT8, ir.TUINT8:
+		return e2 == ir.TINT8 || e2 == ir.TUINT8
 
-	case TINT16, TUINT16:
-		return e2 == TINT16 || e2 == TUINT16
+	case ir.TINT16, ir.TUINT16:
+		return e2 == ir.TINT16 || e2 == ir.TUINT16
 
-	case TINT32, TUINT32, TPTR32:
-		return e2 == TINT32 || e2 == TUINT32 || e2 == TPTR32
+	case ir.TINT32, ir.TUINT32, ir.TPTR32:
+		return e2 == ir.TINT32 || e2 == ir.TUINT32 || e2 == ir.TPTR32
 
-	case TINT64, TUINT64, TPTR64:
-		return e2 == TINT64 || e2 == TUINT64 || e2 == TPTR64
+	case ir.TINT64, ir.TUINT64, ir.TPTR64:
+		return e2 == ir.TINT64 || e2 == ir.TUINT64 || e2 == ir.TPTR64
 
-	case TFLOAT32:
-		return e2 == TFLOAT32
+	case ir.TFLOAT32:
+		return e2 == ir.TFLOAT32
 
-	case TFLOAT64:
-		return e2 == TFLOAT64
+	case ir.TFLOAT64:
+		return e2 == ir.TFLOAT64
 	}
 
 	return false
 }
 
-func syslook(name string) *Node {
+func syslook(name string) *ir.Node {
 	s := Pkglookup(name, Runtimepkg)
 	if s == nil || s.Def == nil {
 		Fatalf("syslook: can't find runtime.%s", name)
@@ -1236,11 +1420,11 @@ func syslook(name string) *Node {
 
 // typehash computes a hash value for type t to use in type switch
 // statements.
-func typehash(t *Type) uint32 {
+func typehash(t *ir.Type) uint32 {
 	// Tconv already contains all the necessary logic to generate
 	// a representation that completely describes the type, so using
 	// it here avoids duplicating that code.
-	p := Tconv(t, FmtLeft|FmtUnsigned)
+	p := Tconv(t, ir.FmtLeft|ir.FmtUnsigned)
 
 	// Using MD5 is overkill, but reduces accidental collisions.
 	h := md5.Sum([]byte(p))
@@ -1250,23 +1434,23 @@ func typehash(t *Type) uint32 {
 var initPtrtoDone bool
 
 var (
-	ptrToUint8  *Type
-	ptrToAny    *Type
-	ptrToString *Type
-	ptrToBool   *Type
-	ptrToInt32  *Type
+	ptrToUint8  *ir.Type
+	ptrToAny    *ir.Type
+	ptrToString *ir.Type
+	ptrToBool   *ir.Type
+	ptrToInt32  *ir.Type
 )
 
 func initPtrto() {
-	ptrToUint8 = ptrto1(Types[TUINT8])
-	ptrToAny = ptrto1(Types[TANY])
-	ptrToString = ptrto1(Types[TSTRING])
-	ptrToBool = ptrto1(Types[TBOOL])
-	ptrToInt32 = ptrto1(Types[TINT32])
+	ptrToUint8 = ptrto1(ir.Types[ir.TUINT8])
+	ptrToAny = ptrto1(ir.Types[ir.TANY])
+	ptrToString = ptrto1(ir.Types[ir.TSTRING])
+	ptrToBool = ptrto1(ir.Types[ir.TBOOL])
+	ptrToInt32 = ptrto1(ir.Types[ir.TINT32])
 }
 
-func ptrto1(t *Type) *Type {
-	t1 := typ(Tptr)
+func ptrto1(t *ir.Type) *ir.Type {
+	t1 := ir.Typ(Tptr)
 	t1.Type = t
 	t1.Width = int64(Widthptr)
 	t1.Align = uint8(Widthptr)
@@ -1275,7 +1459,7 @@ func ptrto1(t *Type) *Type {
 
 // Ptrto returns the Type *t.
 // The returned struct must not be modified.
-func Ptrto(t *Type) *Type {
+func Ptrto(t *ir.Type) *ir.Type {
 	if Tptr == 0 {
 		Fatalf("ptrto: no tptr")
 	}
@@ -1285,18 +1469,21 @@ func Ptrto(t *Type) *Type {
 		initPtrtoDone = true
 	}
 	switch t {
-	case Types[TUINT8]:
+	case ir.Types[ir.TUINT8]:
 		return ptrToUint8
-	case Types[TINT32]:
+	case ir.Types[ir.TINT32]:
 		return ptrToInt32
-	case Types[TANY]:
+	case ir.Types[ir.TANY]:
 		return ptrToAny
-	case Types[TSTRING]:
+	case ir.Types[ir.TSTRING]:
 		return ptrToString
-	case Types[TBOOL]:
+	case ir.Types[ir.TBOOL]:
 		return ptrToBool
 	}
-	return ptrto1(t)
+	if p := sharedTypes[typeKey{etype: Tptr, elem: t}]; p != nil {
+		return p
+	}
+	return sharetype(ptrto1(t))
 }
 
 func frame(context int) {
@@ -1316,15 +1503,15 @@ func frame(context int) {
 	}
 }
 
-func printframenode(n *Node) {
+func printframenode(n *ir.Node) {
 	w := int64(-1)
 	if n.Type != nil {
 		w = n.Type.Width
 	}
 	switch n.Op {
-	case ONAME:
+	case ir.ONAME:
 		fmt.Printf("%v %v G%d %v width=%d\n", Oconv(n.Op, 0), n.Sym, n.Name.Vargen, n.Type, w)
-	case OTYPE:
+	case ir.OTYPE:
 		fmt.Printf("%v %v width=%d\n", Oconv(n.Op, 0), n.Type, w)
 	}
 }
@@ -1333,7 +1520,7 @@ func printframenode(n *Node) {
 // roughly how many registers needed to
 // compile a node. used to compile the
 // hardest side first to minimize registers.
-func ullmancalc(n *Node) {
+func ullmancalc(n *ir.Node) {
 	if n == nil {
 		return
 	}
@@ -1346,19 +1533,19 @@ func ullmancalc(n *Node) {
 	}
 
 	switch n.Op {
-	case OREGISTER, OLITERAL, ONAME:
+	case ir.OREGISTER, ir.OLITERAL, ir.ONAME:
 		ul = 1
-		if n.Class == PPARAMREF || (n.Class&PHEAP != 0) {
+		if n.Class == ir.PPARAMREF || (n.Class&ir.PHEAP != 0) {
 			ul++
 		}
 		goto out
 
-	case OCALL, OCALLFUNC, OCALLMETH, OCALLINTER, OASWB:
+	case ir.OCALL, ir.OCALLFUNC, ir.OCALLMETH, ir.OCALLINTER, ir.OASWB:
 		ul = UINF
 		goto out
 
 		// hard with instrumented code
-	case OANDAND, OOROR:
+	case ir.OANDAND, ir.OOROR:
 		if instrumenting {
 			ul = UINF
 			goto out
@@ -1387,7 +1574,7 @@ out:
 	n.Ullman = uint8(ul)
 }
 
-func badtype(op Op, tl *Type, tr *Type) {
+func badtype(op ir.Op, tl *ir.Type, tr *ir.Type) {
 	fmt_ := ""
 	if tl != nil {
 		fmt_ += fmt.Sprintf("\n\t%v", tl)
@@ -1398,9 +1585,9 @@ func badtype(op Op, tl *Type, tr *Type) {
 
 	// common mistake: *struct and *interface.
 	if tl != nil && tr != nil && Isptr[tl.Etype] && Isptr[tr.Etype] {
-		if tl.Type.Etype == TSTRUCT && tr.Type.Etype == TINTER {
+		if tl.Type.Etype == ir.TSTRUCT && tr.Type.Etype == ir.TINTER {
 			fmt_ += "\n\t(*struct vs *interface)"
-		} else if tl.Type.Etype == TINTER && tr.Type.Etype == TSTRUCT {
+		} else if tl.Type.Etype == ir.TINTER && tr.Type.Etype == ir.TSTRUCT {
 			fmt_ += "\n\t(*interface vs *struct)"
 		}
 	}
@@ -1411,20 +1598,20 @@ func badtype(op Op, tl *Type, tr *Type) {
 
 // Brcom returns !(op).
 // For example, Brcom(==) is !=.
-func Brcom(op Op) Op {
+func Brcom(op ir.Op) ir.Op {
 	switch op {
-	case OEQ:
-		return ONE
-	case ONE:
-		return OEQ
-	case OLT:
-		return OGE
-	case OGT:
-		return OLE
-	case OLE:
-		return OGT
-	case OGE:
-		return OLT
+	case ir.OEQ:
+		return ir.ONE
+	case ir.ONE:
+		return ir.OEQ
+	case ir.OLT:
+		return ir.OGE
+	case ir.OGT:
+		return ir.OLE
+	case ir.OLE:
+		return ir.OGT
+	case ir.OGE:
+		return ir.OLT
 	}
 	Fatalf("brcom: no com for %v\n", Oconv(op, 0))
 	return op
@@ -1432,20 +1619,20 @@ func Brcom(op Op) Op {
 
 // Brrev returns reverse(op).
 // For example, Brrev(<) is >.
-func Brrev(op Op) Op {
+func Brrev(op ir.Op) ir.Op {
 	switch op {
-	case OEQ:
-		return OEQ
-	case ONE:
-		return ONE
-	case OLT:
-		return OGT
-	case OGT:
-		return OLT
-	case OLE:
-		return OGE
-	case OGE:
-		return OLE
+	case ir.OEQ:
+		return ir.OEQ
+	case ir.ONE:
+		return ir.ONE
+	case ir.OLT:
+		return ir.OGT
+	case ir.OGT:
+		return ir.OLT
+	case ir.OLE:
+		return ir.OGE
+	case ir.OGE:
+		return ir.OLE
 	}
 	Fatalf("brrev: no rev for %v\n", Oconv(op, 0))
 	return op
@@ -1453,7 +1640,7 @@ func Brrev(op Op) Op {
 
 // return side effect-free n, appending side effects to init.
 // result is assignable if n is.
-func safeexpr(n *Node, init *Nodes) *Node {
+func safeexpr(n *ir.Node, init *ir.Nodes) *ir.Node {
 	if n == nil {
 		return nil
 	}
@@ -1464,39 +1651,39 @@ func safeexpr(n *Node, init *Nodes) *Node {
 	}
 
 	switch n.Op {
-	case ONAME, OLITERAL:
+	case ir.ONAME, ir.OLITERAL:
 		return n
 
-	case ODOT, OLEN, OCAP:
+	case ir.ODOT, ir.OLEN, ir.OCAP:
 		l := safeexpr(n.Left, init)
 		if l == n.Left {
 			return n
 		}
-		r := Nod(OXXX, nil, nil)
+		r := Nod(ir.OXXX, nil, nil)
 		*r = *n
 		r.Left = l
 		r = typecheck(r, Erv)
 		r = walkexpr(r, init)
 		return r
 
-	case ODOTPTR, OIND:
+	case ir.ODOTPTR, ir.OIND:
 		l := safeexpr(n.Left, init)
 		if l == n.Left {
 			return n
 		}
-		a := Nod(OXXX, nil, nil)
+		a := Nod(ir.OXXX, nil, nil)
 		*a = *n
 		a.Left = l
 		a = walkexpr(a, init)
 		return a
 
-	case OINDEX, OINDEXMAP:
+	case ir.OINDEX, ir.OINDEXMAP:
 		l := safeexpr(n.Left, init)
 		r := safeexpr(n.Right, init)
 		if l == n.Left && r == n.Right {
 			return n
 		}
-		a := Nod(OXXX, nil, nil)
+		a := Nod(ir.OXXX, nil, nil)
 		*a = *n
 		a.Left = l
 		a.Right = r
@@ -1511,9 +1698,9 @@ func safeexpr(n *Node, init *Nodes) *Node {
 	return cheapexpr(n, init)
 }
 
-func copyexpr(n *Node, t *Type, init *Nodes) *Node {
+func copyexpr(n *ir.Node, t *ir.Type, init *ir.Nodes) *ir.Node {
 	l := temp(t)
-	a := Nod(OAS, l, n)
+	a := Nod(ir.OAS, l, n)
 	a = typecheck(a, Etop)
 	a = walkexpr(a, init)
 	init.Append(a)
@@ -1522,16 +1709,16 @@ func copyexpr(n *Node, t *Type, init *Nodes) *Node {
 
 // return side-effect free and cheap n, appending side effects to init.
 // result may not be assignable.
-func cheapexpr(n *Node, init *Nodes) *Node {
+func cheapexpr(n *ir.Node, init *ir.Nodes) *ir.Node {
 	switch n.Op {
-	case ONAME, OLITERAL:
+	case ir.ONAME, ir.OLITERAL:
 		return n
 	}
 
 	return copyexpr(n, n.Type, init)
 }
 
-func Setmaxarg(t *Type, extra int32) {
+func Setmaxarg(t *ir.Type, extra int32) {
 	dowidth(t)
 	w := t.Argwid
 	if w >= Thearch.MAXWIDTH {
@@ -1551,7 +1738,7 @@ func Setmaxarg(t *Type, extra int32) {
 // A Dlist stores a pointer to a TFIELD Type embedded within
 // a TSTRUCT or TINTER Type.
 type Dlist struct {
-	field *Field
+	field *ir.Field
 }
 
 // dotlist is used by adddot1 to record the path of embedded fields
@@ -1562,16 +1749,16 @@ var dotlist = make([]Dlist, 10)
 // lookdot0 returns the number of fields or methods named s associated
 // with Type t. If exactly one exists, it will be returned in *save
 // (if save is not nil).
-func lookdot0(s *Sym, t *Type, save **Field, ignorecase bool) int {
+func lookdot0(s *ir.Sym, t *ir.Type, save **ir.Field, ignorecase bool) int {
 	u := t
 	if Isptr[u.Etype] {
 		u = u.Type
 	}
 
 	c := 0
-	if u.Etype == TSTRUCT || u.Etype == TINTER {
+	if u.Etype == ir.TSTRUCT || u.Etype == ir.TINTER {
 		for _, f := range u.Fields().Slice() {
-			if f.Sym == s || (ignorecase && f.Type.Etype == TFUNC && f.Type.Recv() != nil && strings.EqualFold(f.Sym.Name, s.Name)) {
+			if f.Sym == s || (ignorecase && f.Type.Etype == ir.TFUNC && f.Type.Recv() != nil && strings.EqualFold(f.Sym.Name, s.Name)) {
 				if save != nil {
 					*save = f
 				}
@@ -1601,13 +1788,13 @@ func lookdot0(s *Sym, t *Type, save **Field, ignorecase bool) int {
 // in reverse order. If none exist, more will indicate whether t contains any
 // embedded fields at depth d, so callers can decide whether to retry at
 // a greater depth.
-func adddot1(s *Sym, t *Type, d int, save **Field, ignorecase bool) (c int, more bool) {
+func adddot1(s *ir.Sym, t *ir.Type, d int, save **ir.Field, ignorecase bool) (c int, more bool) {
 	if t.Trecur != 0 {
 		return
 	}
 	t.Trecur = 1
 
-	var u *Type
+	var u *ir.Type
 	d--
 	if d < 0 {
 		// We've reached our target depth. If t has any fields/methods
@@ -1623,7 +1810,7 @@ func adddot1(s *Sym, t *Type, d int, save **Field, ignorecase bool) (c int, more
 	if Isptr[u.Etype] {
 		u = u.Type
 	}
-	if u.Etype != TSTRUCT && u.Etype != TINTER {
+	if u.Etype != ir.TSTRUCT && u.Etype != ir.TINTER {
 		goto out
 	}
 
@@ -1655,7 +1842,7 @@ out:
 // a selection expression x.f, where x is of type t and f is the symbol s.
 // If no such path exists, dotpath returns nil.
 // If there are multiple shortest paths to the same depth, ambig is true.
-func dotpath(s *Sym, t *Type, save **Field, ignorecase bool) (path []Dlist, ambig bool) {
+func dotpath(s *ir.Sym, t *ir.Type, save **ir.Field, ignorecase bool) (path []Dlist, ambig bool) {
 	// The embedding of types within structs imposes a tree structure onto
 	// types: structs parent the types they embed, and types parent their
 	// fields or methods. Our goal here is to find the shortest path to
@@ -1680,7 +1867,7 @@ func dotpath(s *Sym, t *Type, save **Field, ignorecase bool) (path []Dlist, ambi
 // find missing fields that
 // will give shortest unique addressing.
 // modify the tree with missing type names.
-func adddot(n *Node) *Node {
+func adddot(n *ir.Node) *ir.Node {
 	n.Left = typecheck(n.Left, Etype|Erv)
 	n.Diag |= n.Left.Diag
 	t := n.Left.Type
@@ -1688,7 +1875,7 @@ func adddot(n *Node) *Node {
 		return n
 	}
 
-	if n.Left.Op == OTYPE {
+	if n.Left.Op == ir.OTYPE {
 		return n
 	}
 
@@ -1701,7 +1888,7 @@ func adddot(n *Node) *Node {
 	case path != nil:
 		// rebuild elided dots
 		for c := len(path) - 1; c >= 0; c-- {
-			n.Left = NodSym(ODOT, n.Left, path[c].field.Sym)
+			n.Left = NodSym(ir.ODOT, n.Left, path[c].field.Sym)
 			n.Left.Implicit = true
 		}
 	case ambig:
@@ -1721,25 +1908,25 @@ func adddot(n *Node) *Node {
 // with unique tasks and they return
 // the actual methods.
 type Symlink struct {
-	field     *Field
+	field     *ir.Field
 	followptr bool
 }
 
 var slist []Symlink
 
-func expand0(t *Type, followptr bool) {
+func expand0(t *ir.Type, followptr bool) {
 	u := t
 	if Isptr[u.Etype] {
 		followptr = true
 		u = u.Type
 	}
 
-	if u.Etype == TINTER {
+	if u.Etype == ir.TINTER {
 		for _, f := range u.Fields().Slice() {
-			if f.Sym.Flags&SymUniq != 0 {
+			if f.Sym.Flags&ir.SymUniq != 0 {
 				continue
 			}
-			f.Sym.Flags |= SymUniq
+			f.Sym.Flags |= ir.SymUniq
 			slist = append(slist, Symlink{field: f, followptr: followptr})
 		}
 
@@ -1749,16 +1936,16 @@ func expand0(t *Type, followptr bool) {
 	u = methtype(t, 0)
 	if u != nil {
 		for _, f := range u.Methods().Slice() {
-			if f.Sym.Flags&SymUniq != 0 {
+			if f.Sym.Flags&ir.SymUniq != 0 {
 				continue
 			}
-			f.Sym.Flags |= SymUniq
+			f.Sym.Flags |= ir.SymUniq
 			slist = append(slist, Symlink{field: f, followptr: followptr})
 		}
 	}
 }
 
-func expand1(t *Type, top, followptr bool) {
+func expand1(t *ir.Type, top, followptr bool) {
 	if t.Trecur != 0 {
 		return
 	}
@@ -1774,7 +1961,7 @@ func expand1(t *Type, top, followptr bool) {
 		u = u.Type
 	}
 
-	if u.Etype != TSTRUCT && u.Etype != TINTER {
+	if u.Etype != ir.TSTRUCT && u.Etype != ir.TINTER {
 		goto out
 	}
 
@@ -1792,7 +1979,7 @@ out:
 	t.Trecur = 0
 }
 
-func expandmeth(t *Type) {
+func expandmeth(t *ir.Type) {
 	if t == nil || t.AllMethods().Len() != 0 {
 		return
 	}
@@ -1800,7 +1987,7 @@ func expandmeth(t *Type) {
 	// mark top-level method symbols
 	// so that expand1 doesn't consider them.
 	for _, f := range t.Methods().Slice() {
-		f.Sym.Flags |= SymUniq
+		f.Sym.Flags |= ir.SymUniq
 	}
 
 	// generate all reachable methods
@@ -1808,18 +1995,18 @@ func expandmeth(t *Type) {
 	expand1(t, true, false)
 
 	// check each method to be uniquely reachable
-	var ms []*Field
+	var ms []*ir.Field
 	for i, sl := range slist {
 		slist[i].field = nil
-		sl.field.Sym.Flags &^= SymUniq
+		sl.field.Sym.Flags &^= ir.SymUniq
 
-		var f *Field
+		var f *ir.Field
 		if path, _ := dotpath(sl.field.Sym, t, &f, false); path == nil {
 			continue
 		}
 
 		// dotpath may have dug out arbitrary fields, we only want methods.
-		if f.Type.Etype != TFUNC || f.Type.Recv() == nil {
+		if f.Type.Etype != ir.TFUNC || f.Type.Recv() == nil {
 			continue
 		}
 
@@ -1833,7 +2020,7 @@ func expandmeth(t *Type) {
 	}
 
 	for _, f := range t.Methods().Slice() {
-		f.Sym.Flags &^= SymUniq
+		f.Sym.Flags &^= ir.SymUniq
 	}
 
 	ms = append(ms, t.Methods().Slice()...)
@@ -1841,11 +2028,11 @@ func expandmeth(t *Type) {
 }
 
 // Given funarg struct list, return list of ODCLFIELD Node fn args.
-func structargs(tl *Type, mustname bool) []*Node {
-	var args []*Node
+func structargs(tl *ir.Type, mustname bool) []*ir.Node {
+	var args []*ir.Node
 	gen := 0
 	for _, t := range tl.Fields().Slice() {
-		var n *Node
+		var n *ir.Node
 		if mustname && (t.Sym == nil || t.Sym.Name == "_") {
 			// invent a name so that we can refer to it in the trampoline
 			buf := fmt.Sprintf(".anon%d", gen)
@@ -1854,7 +2041,7 @@ func structargs(tl *Type, mustname bool) []*Node {
 		} else if t.Sym != nil {
 			n = newname(t.Sym)
 		}
-		a := Nod(ODCLFIELD, n, typenod(t.Type))
+		a := Nod(ir.ODCLFIELD, n, typenod(t.Type))
 		a.Isddd = t.Isddd
 		if n != nil {
 			n.Isddd = t.Isddd
@@ -1889,7 +2076,7 @@ func structargs(tl *Type, mustname bool) []*Node {
 
 var genwrapper_linehistdone int = 0
 
-func genwrapper(rcvr *Type, method *Field, newnam *Sym, iface int) {
+func genwrapper(rcvr *ir.Type, method *ir.Field, newnam *ir.Sym, iface int) {
 	if false && Debug['r'] != 0 {
 		fmt.Printf("genwrapper rcvrtype=%v method=%v newnam=%v\n", rcvr, method, newnam)
 	}
@@ -1903,42 +2090,42 @@ func genwrapper(rcvr *Type, method *Field, newnam *Sym, iface int) {
 		genwrapper_linehistdone = 1
 	}
 
-	dclcontext = PEXTERN
+	dclcontext = ir.PEXTERN
 	markdcl()
 
-	this := Nod(ODCLFIELD, newname(Lookup(".this")), typenod(rcvr))
+	this := Nod(ir.ODCLFIELD, newname(Lookup(".this")), typenod(rcvr))
 	this.Left.Name.Param.Ntype = this.Right
 	in := structargs(method.Type.Params(), true)
 	out := structargs(method.Type.Results(), false)
 
-	t := Nod(OTFUNC, nil, nil)
-	l := []*Node{this}
-	if iface != 0 && rcvr.Width < Types[Tptr].Width {
+	t := Nod(ir.OTFUNC, nil, nil)
+	l := []*ir.Node{this}
+	if iface != 0 && rcvr.Width < ir.Types[Tptr].Width {
 		// Building method for interface table and receiver
 		// is smaller than the single pointer-sized word
 		// that the interface call will pass in.
 		// Add a dummy padding argument after the
 		// receiver to make up the difference.
-		tpad := typ(TARRAY)
+		tpad := ir.Typ(ir.TARRAY)
 
-		tpad.Type = Types[TUINT8]
-		tpad.Bound = Types[Tptr].Width - rcvr.Width
-		pad := Nod(ODCLFIELD, newname(Lookup(".pad")), typenod(tpad))
+		tpad.Type = ir.Types[ir.TUINT8]
+		tpad.Bound = ir.Types[Tptr].Width - rcvr.Width
+		pad := Nod(ir.ODCLFIELD, newname(Lookup(".pad")), typenod(tpad))
 		l = append(l, pad)
 	}
 
 	t.List.Set(append(l, in...))
 	t.Rlist.Set(out)
 
-	fn := Nod(ODCLFUNC, nil, nil)
+	fn := Nod(ir.ODCLFUNC, nil, nil)
 	fn.Func.Nname = newname(newnam)
 	fn.Func.Nname.Name.Defn = fn
 	fn.Func.Nname.Name.Param.Ntype = t
-	declare(fn.Func.Nname, PFUNC)
+	declare(fn.Func.Nname, ir.PFUNC)
 	funchdr(fn)
 
 	// arg list
-	var args []*Node
+	var args []*ir.Node
 
 	isddd := false
 	for _, n := range in {
@@ -1951,50 +2138,51 @@ func genwrapper(rcvr *Type, method *Field, newnam *Sym, iface int) {
 	// generate nil pointer check for better error
 	if Isptr[rcvr.Etype] && rcvr.Type == methodrcvr {
 		// generating wrapper from *T to T.
-		n := Nod(OIF, nil, nil)
+		n := Nod(ir.OIF, nil, nil)
 
-		n.Left = Nod(OEQ, this.Left, nodnil())
+		n.Left = Nod(ir.OEQ, this.Left, nodnil())
 
 		// these strings are already in the reflect tables,
 		// so no space cost to use them here.
-		var l []*Node
+		var l []*ir.Node
 
-		var v Val
+		var v ir.Val
 		v.U = rcvr.Type.Sym.Pkg.Name // package name
 		l = append(l, nodlit(v))
 		v.U = rcvr.Type.Sym.Name // type name
 		l = append(l, nodlit(v))
 		v.U = method.Sym.Name
 		l = append(l, nodlit(v)) // method name
-		call := Nod(OCALL, syslook("panicwrap"), nil)
+		call := Nod(ir.OCALL, syslook("panicwrap"), nil)
 		call.List.Set(l)
 		n.Nbody.Set1(call)
 		fn.Nbody.Append(n)
 	}
 
-	dot := adddot(NodSym(OXDOT, this.Left, method.Sym))
+	dot := adddot(NodSym(ir.OXDOT, this.Left, method.Sym))
 
 	// generate call
 	if !instrumenting && Isptr[rcvr.Etype] && Isptr[methodrcvr.Etype] && method.Embedded != 0 && !isifacemethod(method.Type) {
 		// generate tail call: adjust pointer receiver and jump to embedded method.
+		sharewrapper(rcvr, method, methodrcvr, newnam)
 		dot = dot.Left // skip final .M
 		// TODO(mdempsky): Remove dependency on dotlist.
 		if !Isptr[dotlist[0].field.Type.Etype] {
-			dot = Nod(OADDR, dot, nil)
+			dot = Nod(ir.OADDR, dot, nil)
 		}
-		as := Nod(OAS, this.Left, Nod(OCONVNOP, dot, nil))
+		as := Nod(ir.OAS, this.Left, Nod(ir.OCONVNOP, dot, nil))
 		as.Right.Type = rcvr
 		fn.Nbody.Append(as)
-		n := Nod(ORETJMP, nil, nil)
+		n := Nod(ir.ORETJMP, nil, nil)
 		n.Left = newname(methodsym(method.Sym, methodrcvr, 0))
 		fn.Nbody.Append(n)
 	} else {
 		fn.Func.Wrapper = true // ignore frame for panic+recover matching
-		call := Nod(OCALL, dot, nil)
+		call := Nod(ir.OCALL, dot, nil)
 		call.List.Set(args)
 		call.Isddd = isddd
 		if method.Type.Results().NumFields() > 0 {
-			n := Nod(ORETURN, nil, nil)
+			n := Nod(ir.ORETURN, nil, nil)
 			n.List.Set1(call)
 			call = n
 		}
@@ -2012,42 +2200,87 @@ func genwrapper(rcvr *Type, method *Field, newnam *Sym, iface int) {
 	testdclstack()
 
 	// wrappers where T is anonymous (struct or interface) can be duplicated.
-	if rcvr.Etype == TSTRUCT || rcvr.Etype == TINTER || Isptr[rcvr.Etype] && rcvr.Type.Etype == TSTRUCT {
+	if rcvr.Etype == ir.TSTRUCT || rcvr.Etype == ir.TINTER || Isptr[rcvr.Etype] && rcvr.Type.Etype == ir.TSTRUCT {
 		fn.Func.Dupok = true
 	}
 	fn = typecheck(fn, Etop)
 	typecheckslice(fn.Nbody.Slice(), Etop)
 
 	inlcalls(fn)
-	escAnalyze([]*Node{fn}, false)
+	escAnalyze([]*ir.Node{fn}, false)
 
 	Curfn = nil
 	funccompile(fn)
 }
 
-func hashmem(t *Type) *Node {
+// Tail-call wrappers that add the same constant offset to the
+// receiver and jump to the same method compile to identical code.
+// wrappershapes maps each such shape to the first wrapper generated
+// for it, and sharedwrappers maps later wrappers of that shape to it.
+// The later wrappers are still compiled, since code can refer to them
+// directly, but method tables use the shared one, so the linker
+// drops the others unless something else needs them.
+var (
+	wrappershapes  = make(map[string]*ir.Sym)
+	sharedwrappers = make(map[*ir.Sym]*ir.Sym)
+)
+
+// sharewrapper records the shape of the tail-call wrapper newnam,
+// which adjusts a receiver of type rcvr and jumps to method on
+// methodrcvr. Only wrappers whose embedding path contains no
+// pointers, so that the adjustment is a constant offset, are shared.
+func sharewrapper(rcvr *ir.Type, method *ir.Field, methodrcvr *ir.Type, newnam *ir.Sym) {
+	path, _ := dotpath(method.Sym, rcvr, nil, false)
+	if path == nil {
+		return
+	}
+	var off int64
+	for _, d := range path {
+		if Isptr[d.field.Type.Etype] {
+			return
+		}
+		off += d.field.Width
+	}
+	shape := fmt.Sprintf("%d %v", off, methodsym(method.Sym, methodrcvr, 0))
+	if s := wrappershapes[shape]; s != nil {
+		sharedwrappers[newnam] = s
+		return
+	}
+	wrappershapes[shape] = newnam
+}
+
+// methodwrapper returns the symbol to use for the method
+// wrapper s in method tables.
+func methodwrapper(s *ir.Sym) *ir.Sym {
+	if shared := sharedwrappers[s]; shared != nil {
+		return shared
+	}
+	return s
+}
+
+func hashmem(t *ir.Type) *ir.Node {
 	sym := Pkglookup("memhash", Runtimepkg)
 
 	n := newname(sym)
-	n.Class = PFUNC
-	tfn := Nod(OTFUNC, nil, nil)
-	tfn.List.Append(Nod(ODCLFIELD, nil, typenod(Ptrto(t))))
-	tfn.List.Append(Nod(ODCLFIELD, nil, typenod(Types[TUINTPTR])))
-	tfn.List.Append(Nod(ODCLFIELD, nil, typenod(Types[TUINTPTR])))
-	tfn.Rlist.Append(Nod(ODCLFIELD, nil, typenod(Types[TUINTPTR])))
+	n.Class = ir.PFUNC
+	tfn := Nod(ir.OTFUNC, nil, nil)
+	tfn.List.Append(Nod(ir.ODCLFIELD, nil, typenod(Ptrto(t))))
+	tfn.List.Append(Nod(ir.ODCLFIELD, nil, typenod(ir.Types[ir.TUINTPTR])))
+	tfn.List.Append(Nod(ir.ODCLFIELD, nil, typenod(ir.Types[ir.TUINTPTR])))
+	tfn.Rlist.Append(Nod(ir.ODCLFIELD, nil, typenod(ir.Types[ir.TUINTPTR])))
 	tfn = typecheck(tfn, Etype)
 	n.Type = tfn.Type
 	return n
 }
 
-func ifacelookdot(s *Sym, t *Type, followptr *bool, ignorecase bool) *Field {
+func ifacelookdot(s *ir.Sym, t *ir.Type, followptr *bool, ignorecase bool) *ir.Field {
 	*followptr = false
 
 	if t == nil {
 		return nil
 	}
 
-	var m *Field
+	var m *ir.Field
 	path, ambig := dotpath(s, t, &m, ignorecase)
 	if path == nil {
 		if ambig {
@@ -2063,7 +2296,7 @@ func ifacelookdot(s *Sym, t *Type, followptr *bool, ignorecase bool) *Field {
 		}
 	}
 
-	if m.Type.Etype != TFUNC || m.Type.Recv() == nil {
+	if m.Type.Etype != ir.TFUNC || m.Type.Recv() == nil {
 		Yyerror("%v.%v is a field, not a method", t, s)
 		return nil
 	}
@@ -2071,7 +2304,7 @@ func ifacelookdot(s *Sym, t *Type, followptr *bool, ignorecase bool) *Field {
 	return m
 }
 
-func implements(t, iface *Type, m, samename **Field, ptr *int) bool {
+func implements(t, iface *ir.Type, m, samename **ir.Field, ptr *int) bool {
 	t0 := t
 	if t == nil {
 		return false
@@ -2081,7 +2314,7 @@ func implements(t, iface *Type, m, samename **Field, ptr *int) bool {
 	// could sort these first
 	// and then do one loop.
 
-	if t.Etype == TINTER {
+	if t.Etype == ir.TINTER {
 		for _, im := range iface.Fields().Slice() {
 			for _, tm := range t.Fields().Slice() {
 				if tm.Sym == im.Sym {
@@ -2098,6 +2331,12 @@ func implements(t, iface *Type, m, samename **Field, ptr *int) bool {
 			*m = im
 			*samename = nil
 			*ptr = 0
+			for _, tm := range t.Fields().Slice() {
+				if strings.EqualFold(tm.Sym.Name, im.Sym.Name) {
+					*samename = tm
+					break
+				}
+			}
 			return false
 		found:
 		}
@@ -2144,39 +2383,64 @@ func implements(t, iface *Type, m, samename **Field, ptr *int) bool {
 	return true
 }
 
+// missingmethod explains why a type does not implement an interface,
+// given the m, samename and ptr results of the implements call that
+// failed. The explanation is a parenthesized reason, followed by the
+// method the type has and the method the interface wants when the type
+// has a method of a similar name.
+func missingmethod(missing, have *ir.Field, ptr int) string {
+	var reason string
+	switch {
+	case have != nil && have.Sym == missing.Sym && have.Nointerface:
+		return fmt.Sprintf("(%v method is marked 'nointerface')", missing.Sym)
+	case have != nil && have.Sym == missing.Sym:
+		reason = fmt.Sprintf("(wrong type for %v method)", missing.Sym)
+	case ptr != 0:
+		return fmt.Sprintf("(%v method has pointer receiver)", missing.Sym)
+	case have != nil && have.Sym.Name == missing.Sym.Name:
+		// unexported methods of different packages
+		reason = fmt.Sprintf("(missing %v method; unexported %v is a different method)", missing.Sym, have.Sym)
+	case have != nil:
+		reason = fmt.Sprintf("(missing %v method; %v differs only in case)", missing.Sym, have.Sym)
+	default:
+		return fmt.Sprintf("(missing %v method)", missing.Sym)
+	}
+	return fmt.Sprintf("%s\n\t\thave %v%v\n\t\twant %v%v", reason, have.Sym, Tconv(have.Type, ir.FmtShort|ir.FmtByte), missing.Sym, Tconv(missing.Type, ir.FmtShort|ir.FmtByte))
+}
+
 // even simpler simtype; get rid of ptr, bool.
 // assuming that the front end has rejected
 // all the invalid conversions (like ptr -> bool)
-func Simsimtype(t *Type) EType {
+func Simsimtype(t *ir.Type) ir.EType {
 	if t == nil {
 		return 0
 	}
 
 	et := Simtype[t.Etype]
 	switch et {
-	case TPTR32:
-		et = TUINT32
+	case ir.TPTR32:
+		et = ir.TUINT32
 
-	case TPTR64:
-		et = TUINT64
+	case ir.TPTR64:
+		et = ir.TUINT64
 
-	case TBOOL:
-		et = TUINT8
+	case ir.TBOOL:
+		et = ir.TUINT8
 	}
 
 	return et
 }
 
-func listtreecopy(l []*Node, lineno int32) []*Node {
-	var out []*Node
+func listtreecopy(l []*ir.Node, lineno int32) []*ir.Node {
+	var out []*ir.Node
 	for _, n := range l {
 		out = append(out, treecopy(n, lineno))
 	}
 	return out
 }
 
-func liststmt(l []*Node) *Node {
-	n := Nod(OBLOCK, nil, nil)
+func liststmt(l []*ir.Node) *ir.Node {
+	n := Nod(ir.OBLOCK, nil, nil)
 	n.List.Set(l)
 	if len(l) != 0 {
 		n.Lineno = l[0].Lineno
@@ -2187,15 +2451,15 @@ func liststmt(l []*Node) *Node {
 // return power of 2 of the constant
 // operand. -1 if it is not a power of 2.
 // 1000+ if it is a -(power of 2)
-func powtwo(n *Node) int {
-	if n == nil || n.Op != OLITERAL || n.Type == nil {
+func powtwo(n *ir.Node) int {
+	if n == nil || n.Op != ir.OLITERAL || n.Type == nil {
 		return -1
 	}
 	if !Isint[n.Type.Etype] {
 		return -1
 	}
 
-	v := uint64(n.Val().U.(*Mpint).Int64())
+	v := uint64(n.Val().U.(*ir.Mpint).Int64())
 	b := uint64(1)
 	for i := 0; i < 64; i++ {
 		if b == v {
@@ -2224,7 +2488,7 @@ func powtwo(n *Node) int {
 // a signed integer type.
 // returns T if input is not a
 // signed integer type.
-func tounsigned(t *Type) *Type {
+func tounsigned(t *ir.Type) *ir.Type {
 	// this is types[et+1], but not sure
 	// that this relation is immutable
 	switch t.Etype {
@@ -2232,26 +2496,26 @@ func tounsigned(t *Type) *Type {
 		fmt.Printf("tounsigned: unknown type %v\n", t)
 		t = nil
 
-	case TINT:
-		t = Types[TUINT]
+	case ir.TINT:
+		t = ir.Types[ir.TUINT]
 
-	case TINT8:
-		t = Types[TUINT8]
+	case ir.TINT8:
+		t = ir.Types[ir.TUINT8]
 
-	case TINT16:
-		t = Types[TUINT16]
+	case ir.TINT16:
+		t = ir.Types[ir.TUINT16]
 
-	case TINT32:
-		t = Types[TUINT32]
+	case ir.TINT32:
+		t = ir.Types[ir.TUINT32]
 
-	case TINT64:
-		t = Types[TUINT64]
+	case ir.TINT64:
+		t = ir.Types[ir.TUINT64]
 	}
 
 	return t
 }
 
-func ngotype(n *Node) *Sym {
+func ngotype(n *ir.Node) *ir.Sym {
 	if n.Type != nil {
 		return typenamesym(n.Type)
 	}
@@ -2285,18 +2549,17 @@ func pathtoprefix(s string) string {
 	return s
 }
 
-var pkgMap = make(map[string]*Pkg)
-var pkgs []*Pkg
+var pkgMap = make(map[string]*ir.Pkg)
+var pkgs []*ir.Pkg
 
-func mkpkg(path string) *Pkg {
+func mkpkg(path string) *ir.Pkg {
 	if p := pkgMap[path]; p != nil {
 		return p
 	}
 
-	p := new(Pkg)
-	p.Path = path
+	p := new(ir.Pkg)
+	p.Path = ir.InternStr(path)
 	p.Prefix = pathtoprefix(path)
-	p.Syms = make(map[string]*Sym)
 	pkgMap[path] = p
 	pkgs = append(pkgs, p)
 	return p
@@ -2304,7 +2567,7 @@ func mkpkg(path string) *Pkg {
 
 // The result of addinit MUST be assigned back to n, e.g.
 // 	n.Left = addinit(n.Left, init)
-func addinit(n *Node, init []*Node) *Node {
+func addinit(n *ir.Node, init []*ir.Node) *ir.Node {
 	if len(init) == 0 {
 		return n
 	}
@@ -2312,8 +2575,8 @@ func addinit(n *Node, init []*Node) *Node {
 	switch n.Op {
 	// There may be multiple refs to this node;
 	// introduce OCONVNOP to hold init list.
-	case ONAME, OLITERAL:
-		n = Nod(OCONVNOP, n, nil)
+	case ir.ONAME, ir.OLITERAL:
+		n = Nod(ir.OCONVNOP, n, nil)
 		n.Type = n.Left.Type
 		n.Typecheck = 1
 	}
@@ -2371,34 +2634,34 @@ func isbadimport(path string) bool {
 	return false
 }
 
-func checknil(x *Node, init *Nodes) {
+func checknil(x *ir.Node, init *ir.Nodes) {
 	if Isinter(x.Type) {
-		x = Nod(OITAB, x, nil)
+		x = Nod(ir.OITAB, x, nil)
 		x = typecheck(x, Erv)
 	}
 
-	n := Nod(OCHECKNIL, x, nil)
+	n := Nod(ir.OCHECKNIL, x, nil)
 	n.Typecheck = 1
 	init.Append(n)
 }
 
 // Can this type be stored directly in an interface word?
 // Yes, if the representation is a single pointer.
-func isdirectiface(t *Type) bool {
+func isdirectiface(t *ir.Type) bool {
 	switch t.Etype {
-	case TPTR32,
-		TPTR64,
-		TCHAN,
-		TMAP,
-		TFUNC,
-		TUNSAFEPTR:
+	case ir.TPTR32,
+		ir.TPTR64,
+		ir.TCHAN,
+		ir.TMAP,
+		ir.TFUNC,
+		ir.TUNSAFEPTR:
 		return true
 
-	case TARRAY:
+	case ir.TARRAY:
 		// Array of 1 direct iface type can be direct.
 		return t.Bound == 1 && isdirectiface(t.Type)
 
-	case TSTRUCT:
+	case ir.TSTRUCT:
 		// Struct with 1 field of direct iface type can be direct.
 		return t.NumFields() == 1 && isdirectiface(t.Field(0).Type)
 	}
@@ -2409,7 +2672,7 @@ func isdirectiface(t *Type) bool {
 // iet returns 'T' if t is a concrete type,
 // 'I' if t is an interface type, and 'E' if t is an empty interface type.
 // It is used to build calls to the conv* and assert* runtime routines.
-func (t *Type) iet() byte {
+func iet(t *ir.Type) byte {
 	if isnilinter(t) {
 		return 'E'
 	}
@@ -5,8 +5,10 @@
 package gc
 
 import (
+	"cmd/compile/internal/ir"
 	"sort"
 	"strconv"
+	"strings"
 )
 
 const (
@@ -36,41 +38,41 @@ const binarySearchMin = 4 // minimum number of cases for binary search
 
 // An exprSwitch walks an expression switch.
 type exprSwitch struct {
-	exprname *Node // node for the expression being switched on
-	kind     int   // kind of switch statement (switchKind*)
+	exprname *ir.Node // node for the expression being switched on
+	kind     int      // kind of switch statement (switchKind*)
 }
 
 // A typeSwitch walks a type switch.
 type typeSwitch struct {
-	hashname *Node // node for the hash of the type of the variable being switched on
-	facename *Node // node for the concrete type of the variable being switched on
-	okname   *Node // boolean node used for comma-ok type assertions
+	hashname *ir.Node // node for the hash of the type of the variable being switched on
+	facename *ir.Node // node for the concrete type of the variable being switched on
+	okname   *ir.Node // boolean node used for comma-ok type assertions
 }
 
 // A caseClause is a single case clause in a switch statement.
 type caseClause struct {
-	node    *Node  // points at case statement
-	ordinal int    // position in switch
-	hash    uint32 // hash of a type switch
-	typ     uint8  // type of case
+	node    *ir.Node // points at case statement
+	ordinal int      // position in switch
+	hash    uint32   // hash of a type switch
+	typ     uint8    // type of case
 }
 
 // typecheckswitch typechecks a switch statement.
-func typecheckswitch(n *Node) {
+func typecheckswitch(n *ir.Node) {
 	lno := lineno
 	typecheckslice(n.Ninit.Slice(), Etop)
 
 	var nilonly string
 	var top int
-	var t *Type
+	var t *ir.Type
 
-	if n.Left != nil && n.Left.Op == OTYPESW {
+	if n.Left != nil && n.Left.Op == ir.OTYPESW {
 		// type switch
 		top = Etype
 		n.Left.Right = typecheck(n.Left.Right, Erv)
 		t = n.Left.Right.Type
-		if t != nil && t.Etype != TINTER {
-			Yyerror("cannot type switch on non-interface value %v", Nconv(n.Left.Right, FmtLong))
+		if t != nil && t.Etype != ir.TINTER {
+			Yyerror("cannot type switch on non-interface value %v", Nconv(n.Left.Right, ir.FmtLong))
 		}
 	} else {
 		// expression switch
@@ -80,22 +82,22 @@ func typecheckswitch(n *Node) {
 			n.Left = defaultlit(n.Left, nil)
 			t = n.Left.Type
 		} else {
-			t = Types[TBOOL]
+			t = ir.Types[ir.TBOOL]
 		}
 		if t != nil {
-			var badtype *Type
+			var badtype *ir.Type
 			switch {
 			case !okforeq[t.Etype]:
-				Yyerror("cannot switch on %v", Nconv(n.Left, FmtLong))
-			case t.Etype == TARRAY && !Isfixedarray(t):
+				Yyerror("cannot switch on %v", Nconv(n.Left, ir.FmtLong))
+			case t.Etype == ir.TARRAY && !Isfixedarray(t):
 				nilonly = "slice"
-			case t.Etype == TARRAY && Isfixedarray(t) && algtype1(t, nil) == ANOEQ:
-				Yyerror("cannot switch on %v", Nconv(n.Left, FmtLong))
-			case t.Etype == TSTRUCT && algtype1(t, &badtype) == ANOEQ:
-				Yyerror("cannot switch on %v (struct containing %v cannot be compared)", Nconv(n.Left, FmtLong), badtype)
-			case t.Etype == TFUNC:
+			case t.Etype == ir.TARRAY && Isfixedarray(t) && algtype1(t, nil) == ANOEQ:
+				Yyerror("cannot switch on %v", Nconv(n.Left, ir.FmtLong))
+			case t.Etype == ir.TSTRUCT && algtype1(t, &badtype) == ANOEQ:
+				Yyerror("cannot switch on %v (struct containing %v cannot be compared)", Nconv(n.Left, ir.FmtLong), badtype)
+			case t.Etype == ir.TFUNC:
 				nilonly = "func"
-			case t.Etype == TMAP:
+			case t.Etype == ir.TMAP:
 				nilonly = "map"
 			}
 		}
@@ -103,7 +105,7 @@ func typecheckswitch(n *Node) {
 
 	n.Type = t
 
-	var def *Node
+	var def *ir.Node
 	for _, ncase := range n.List.Slice() {
 		setlineno(n)
 		if ncase.List.Len() == 0 {
@@ -129,7 +131,7 @@ func typecheckswitch(n *Node) {
 					ls[i1] = defaultlit(ls[i1], t)
 					n1 = ls[i1]
 					switch {
-					case n1.Op == OTYPE:
+					case n1.Op == ir.OTYPE:
 						Yyerror("type %v is not an expression", n1.Type)
 					case n1.Type != nil && assignop(n1.Type, t, nil) == 0 && assignop(t, n1.Type, nil) == 0:
 						if n.Left != nil {
@@ -140,25 +142,23 @@ func typecheckswitch(n *Node) {
 					case nilonly != "" && !isnil(n1):
 						Yyerror("invalid case %v in switch (can only compare %s %v to nil)", n1, nilonly, n.Left)
 					case Isinter(t) && !Isinter(n1.Type) && algtype1(n1.Type, nil) == ANOEQ:
-						Yyerror("invalid case %v in switch (incomparable type)", Nconv(n1, FmtLong))
+						Yyerror("invalid case %v in switch (incomparable type)", Nconv(n1, ir.FmtLong))
 					}
 
 				// type switch
 				case Etype:
-					var missing, have *Field
+					var missing, have *ir.Field
 					var ptr int
 					switch {
-					case n1.Op == OLITERAL && Istype(n1.Type, TNIL):
-					case n1.Op != OTYPE && n1.Type != nil: // should this be ||?
-						Yyerror("%v is not a type", Nconv(n1, FmtLong))
+					case n1.Op == ir.OLITERAL && Istype(n1.Type, ir.TNIL):
+					case n1.Op != ir.OTYPE && n1.Type != nil: // should this be ||?
+						Yyerror("%v is not a type", Nconv(n1, ir.FmtLong))
 						// reset to original type
 						n1 = n.Left.Right
 						ls[i1] = n1
-					case n1.Type.Etype != TINTER && t.Etype == TINTER && !implements(n1.Type, t, &missing, &have, &ptr):
-						if have != nil && !missing.Broke && !have.Broke {
-							Yyerror("impossible type switch case: %v cannot have dynamic type %v"+" (wrong type for %v method)\n\thave %v%v\n\twant %v%v", Nconv(n.Left.Right, FmtLong), n1.Type, missing.Sym, have.Sym, Tconv(have.Type, FmtShort), missing.Sym, Tconv(missing.Type, FmtShort))
-						} else if !missing.Broke {
-							Yyerror("impossible type switch case: %v cannot have dynamic type %v"+" (missing %v method)", Nconv(n.Left.Right, FmtLong), n1.Type, missing.Sym)
+					case n1.Type.Etype != ir.TINTER && t.Etype == ir.TINTER && !implements(n1.Type, t, &missing, &have, &ptr):
+						if !missing.Broke && (have == nil || !have.Broke) {
+							Yyerror("impossible type switch case: %v cannot have dynamic type %v %s", Nconv(n.Left.Right, ir.FmtLong), n1.Type, missingmethod(missing, have, ptr))
 						}
 					}
 				}
@@ -169,7 +169,7 @@ func typecheckswitch(n *Node) {
 			ll := ncase.List
 			if ncase.Rlist.Len() != 0 {
 				nvar := ncase.Rlist.First()
-				if ll.Len() == 1 && ll.First().Type != nil && !Istype(ll.First().Type, TNIL) {
+				if ll.Len() == 1 && ll.First().Type != nil && !Istype(ll.First().Type, ir.TNIL) {
 					// single entry type switch
 					nvar.Name.Param.Ntype = typenod(ll.First().Type)
 				} else {
@@ -185,18 +185,161 @@ func typecheckswitch(n *Node) {
 		typecheckslice(ncase.Nbody.Slice(), Etop)
 	}
 
+	if top == Erv && t != nil {
+		checkdupcases(n, t)
+		if Debug_exhaustive != 0 && def == nil {
+			checkexhaustive(n, t)
+		}
+	}
+
 	lineno = lno
 }
 
+// A caseKey identifies the value of a constant case in an expression switch.
+// Equal constants have equal keys. In a switch on an interface value,
+// constants of different types are different cases, so the key also
+// records the type of the constant.
+type caseKey struct {
+	typ *ir.Type
+	val string
+}
+
+// checkdupcases reports constant cases in the expression switch n
+// that repeat an earlier case. t is the type of the switch expression.
+func checkdupcases(n *ir.Node, t *ir.Type) {
+	seen := make(map[caseKey]*ir.Node)
+	for _, ncase := range n.List.Slice() {
+		for _, n1 := range ncase.List.Slice() {
+			if n1.Type == nil || n1.Op != ir.OLITERAL {
+				continue
+			}
+			val, ok := constkey(n1.Val())
+			if !ok {
+				// nil or a boolean. The compiler has never rejected
+				// duplicate boolean cases, and code like
+				//	case GOARCH == "arm" && GOARM == "5":
+				//	case GOARCH == "arm":
+				// relies on that.
+				continue
+			}
+			k := caseKey{val: val}
+			if Isinter(t) {
+				k.typ = n1.Type
+			}
+			prev, dup := seen[k]
+			if !dup {
+				seen[k] = ncase
+				continue
+			}
+			yyerrorl(ncase.Lineno, "duplicate case %v in switch\n\tprevious case at %v", n1, prev.Line())
+		}
+	}
+}
+
+// checkexhaustive warns if the expression switch n, which has no default case,
+// does not handle every package-level constant declared with the type of
+// the switch expression, t, in t's package. Only named integer and string
+// types are checked. A constant is handled if a case has its value.
+func checkexhaustive(n *ir.Node, t *ir.Type) {
+	if n.Left == nil || t.Sym == nil || !Isint[t.Etype] && t.Etype != ir.TSTRING {
+		return
+	}
+	if Curfn != nil && Curfn.Op == ir.ONAME {
+		return // imported function body, typechecked by typecheckinl
+	}
+
+	handled := make(map[string]bool)
+	for _, ncase := range n.List.Slice() {
+		for _, n1 := range ncase.List.Slice() {
+			if n1.Op != ir.OLITERAL {
+				return // can't tell which values the case handles
+			}
+			if k, ok := constkey(n1.Val()); ok {
+				handled[k] = true
+			}
+		}
+	}
+
+	pkg := t.Sym.Pkg
+	expandPkg(pkg) // the constants need not have been referred to
+	var consts []*ir.Node
+	for _, s := range pkg.Syms() {
+		c := s.Def
+		if c == nil || c.Op != ir.OLITERAL || c.Type != t || c.Class != ir.PEXTERN || isblanksym(s) {
+			continue
+		}
+		if pkg != localpkg && !exportname(s.Name) {
+			continue
+		}
+		consts = append(consts, c)
+	}
+	sort.Sort(constsByPos(consts))
+
+	var missing []string
+	for _, c := range consts {
+		k, ok := constkey(c.Val())
+		if !ok || handled[k] {
+			continue
+		}
+		handled[k] = true // report each value once, by its first name
+		missing = append(missing, Sconv(c.Sym, 0))
+	}
+	if len(missing) > 0 {
+		Warnl(n.Lineno, "switch on %v without default does not handle %s", t, strings.Join(missing, ", "))
+	}
+}
+
+// constsByPos sorts constants by declaration position, then by value,
+// then by name. Imported constants all have the same position,
+// so they end up sorted by value: time.January before time.February.
+type constsByPos []*ir.Node
+
+func (x constsByPos) Len() int      { return len(x) }
+func (x constsByPos) Swap(i, j int) { x[i], x[j] = x[j], x[i] }
+func (x constsByPos) Less(i, j int) bool {
+	if x[i].Lineno != x[j].Lineno {
+		return x[i].Lineno < x[j].Lineno
+	}
+	switch u := x[i].Val().U.(type) {
+	case *ir.Mpint:
+		if c := u.Cmp(x[j].Val().U.(*ir.Mpint)); c != 0 {
+			return c < 0
+		}
+	case string:
+		if v := x[j].Val().U.(string); u != v {
+			return u < v
+		}
+	}
+	return x[i].Sym.Name < x[j].Sym.Name
+}
+
+// constkey returns a canonical string for the constant v,
+// so that equal values have equal strings regardless of how they were written.
+// It reports false for kinds of constant that are not checked for duplicates.
+func constkey(v ir.Val) (string, bool) {
+	switch u := v.U.(type) {
+	case *ir.Mpint:
+		return u.Val.String(), true
+	case *ir.Mpflt:
+		// 'p' is exact and independent of the precision of u.
+		return u.Val.Text('p', 0), true
+	case *ir.Mpcplx:
+		return u.Real.Val.Text('p', 0) + "," + u.Imag.Val.Text('p', 0), true
+	case string:
+		return u, true
+	}
+	return "", false
+}
+
 // walkswitch walks a switch statement.
-func walkswitch(sw *Node) {
+func walkswitch(sw *ir.Node) {
 	// convert switch {...} to switch true {...}
 	if sw.Left == nil {
 		sw.Left = Nodbool(true)
 		sw.Left = typecheck(sw.Left, Erv)
 	}
 
-	if sw.Left.Op == OTYPESW {
+	if sw.Left.Op == ir.OTYPESW {
 		var s typeSwitch
 		s.walk(sw)
 	} else {
@@ -210,14 +353,14 @@ func walkswitch(sw *Node) {
 // The AST is generally of the form of a linear
 // search using if..goto, although binary search
 // is used with long runs of constants.
-func (s *exprSwitch) walk(sw *Node) {
+func (s *exprSwitch) walk(sw *ir.Node) {
 	casebody(sw, nil)
 
 	cond := sw.Left
 	sw.Left = nil
 
 	s.kind = switchKindExpr
-	if Isconst(cond, CTBOOL) {
+	if ir.Isconst(cond, ir.CTBOOL) {
 		s.kind = switchKindTrue
 		if !cond.Val().U.(bool) {
 			s.kind = switchKindFalse
@@ -231,27 +374,27 @@ func (s *exprSwitch) walk(sw *Node) {
 	}
 
 	// convert the switch into OIF statements
-	var cas []*Node
+	var cas []*ir.Node
 	if s.kind == switchKindTrue || s.kind == switchKindFalse {
 		s.exprname = Nodbool(s.kind == switchKindTrue)
-	} else if consttype(cond) >= 0 {
+	} else if ir.Consttype(cond) >= 0 {
 		// leave constants to enable dead code elimination (issue 9608)
 		s.exprname = cond
 	} else {
 		s.exprname = temp(cond.Type)
-		cas = []*Node{Nod(OAS, s.exprname, cond)}
+		cas = []*ir.Node{Nod(ir.OAS, s.exprname, cond)}
 		typecheckslice(cas, Etop)
 	}
 
 	// enumerate the cases, and lop off the default case
 	cc := caseClauses(sw, s.kind)
 	sw.List.Set(nil)
-	var def *Node
+	var def *ir.Node
 	if len(cc) > 0 && cc[0].typ == caseKindDefault {
 		def = cc[0].node.Right
 		cc = cc[1:]
 	} else {
-		def = Nod(OBREAK, nil, nil)
+		def = Nod(ir.OBREAK, nil, nil)
 	}
 
 	// handle the cases in order
@@ -285,23 +428,23 @@ func (s *exprSwitch) walk(sw *Node) {
 }
 
 // walkCases generates an AST implementing the cases in cc.
-func (s *exprSwitch) walkCases(cc []*caseClause) *Node {
+func (s *exprSwitch) walkCases(cc []*caseClause) *ir.Node {
 	if len(cc) < binarySearchMin {
 		// linear search
-		var cas []*Node
+		var cas []*ir.Node
 		for _, c := range cc {
 			n := c.node
 			lno := setlineno(n)
 
-			a := Nod(OIF, nil, nil)
-			if (s.kind != switchKindTrue && s.kind != switchKindFalse) || assignop(n.Left.Type, s.exprname.Type, nil) == OCONVIFACE || assignop(s.exprname.Type, n.Left.Type, nil) == OCONVIFACE {
-				a.Left = Nod(OEQ, s.exprname, n.Left) // if name == val
+			a := Nod(ir.OIF, nil, nil)
+			if (s.kind != switchKindTrue && s.kind != switchKindFalse) || assignop(n.Left.Type, s.exprname.Type, nil) == ir.OCONVIFACE || assignop(s.exprname.Type, n.Left.Type, nil) == ir.OCONVIFACE {
+				a.Left = Nod(ir.OEQ, s.exprname, n.Left) // if name == val
 				a.Left = typecheck(a.Left, Erv)
 			} else if s.kind == switchKindTrue {
 				a.Left = n.Left // if val
 			} else {
 				// s.kind == switchKindFalse
-				a.Left = Nod(ONOT, n.Left, nil) // if !val
+				a.Left = Nod(ir.ONOT, n.Left, nil) // if !val
 				a.Left = typecheck(a.Left, Erv)
 			}
 			a.Nbody.Set1(n.Right) // goto l
@@ -314,14 +457,14 @@ func (s *exprSwitch) walkCases(cc []*caseClause) *Node {
 
 	// find the middle and recur
 	half := len(cc) / 2
-	a := Nod(OIF, nil, nil)
+	a := Nod(ir.OIF, nil, nil)
 	mid := cc[half-1].node.Left
-	le := Nod(OLE, s.exprname, mid)
-	if Isconst(mid, CTSTR) {
+	le := Nod(ir.OLE, s.exprname, mid)
+	if ir.Isconst(mid, ir.CTSTR) {
 		// Search by length and then by value; see exprcmp.
-		lenlt := Nod(OLT, Nod(OLEN, s.exprname, nil), Nod(OLEN, mid, nil))
-		leneq := Nod(OEQ, Nod(OLEN, s.exprname, nil), Nod(OLEN, mid, nil))
-		a.Left = Nod(OOROR, lenlt, Nod(OANDAND, leneq, le))
+		lenlt := Nod(ir.OLT, Nod(ir.OLEN, s.exprname, nil), Nod(ir.OLEN, mid, nil))
+		leneq := Nod(ir.OEQ, Nod(ir.OLEN, s.exprname, nil), Nod(ir.OLEN, mid, nil))
+		a.Left = Nod(ir.OOROR, lenlt, Nod(ir.OANDAND, leneq, le))
 	} else {
 		a.Left = le
 	}
@@ -334,27 +477,27 @@ func (s *exprSwitch) walkCases(cc []*caseClause) *Node {
 // casebody builds separate lists of statements and cases.
 // It makes labels between cases and statements
 // and deals with fallthrough, break, and unreachable statements.
-func casebody(sw *Node, typeswvar *Node) {
+func casebody(sw *ir.Node, typeswvar *ir.Node) {
 	if sw.List.Len() == 0 {
 		return
 	}
 
 	lno := setlineno(sw)
 
-	var cas []*Node  // cases
-	var stat []*Node // statements
-	var def *Node    // defaults
-	br := Nod(OBREAK, nil, nil)
+	var cas []*ir.Node  // cases
+	var stat []*ir.Node // statements
+	var def *ir.Node    // defaults
+	br := Nod(ir.OBREAK, nil, nil)
 
 	for i, n := range sw.List.Slice() {
 		setlineno(n)
-		if n.Op != OXCASE {
+		if n.Op != ir.OXCASE {
 			Fatalf("casebody %v", Oconv(n.Op, 0))
 		}
-		n.Op = OCASE
-		needvar := n.List.Len() != 1 || n.List.First().Op == OLITERAL
+		n.Op = ir.OCASE
+		needvar := n.List.Len() != 1 || n.List.First().Op == ir.OLITERAL
 
-		jmp := Nod(OGOTO, newCaseLabel(), nil)
+		jmp := Nod(ir.OGOTO, newCaseLabel(), nil)
 		if n.List.Len() == 0 {
 			if def != nil {
 				Yyerror("more than one default case")
@@ -373,15 +516,15 @@ func casebody(sw *Node, typeswvar *Node) {
 		} else {
 			// expand multi-valued cases
 			for _, n1 := range n.List.Slice() {
-				cas = append(cas, Nod(OCASE, n1, jmp))
+				cas = append(cas, Nod(ir.OCASE, n1, jmp))
 			}
 		}
 
-		stat = append(stat, Nod(OLABEL, jmp.Left, nil))
+		stat = append(stat, Nod(ir.OLABEL, jmp.Left, nil))
 		if typeswvar != nil && needvar && n.Rlist.Len() != 0 {
-			l := []*Node{
-				Nod(ODCL, n.Rlist.First(), nil),
-				Nod(OAS, n.Rlist.First(), typeswvar),
+			l := []*ir.Node{
+				Nod(ir.ODCL, n.Rlist.First(), nil),
+				Nod(ir.OAS, n.Rlist.First(), typeswvar),
 			}
 			typecheckslice(l, Etop)
 			stat = append(stat, l...)
@@ -390,7 +533,7 @@ func casebody(sw *Node, typeswvar *Node) {
 
 		// botch - shouldn't fall thru declaration
 		last := stat[len(stat)-1]
-		if last.Xoffset == n.Xoffset && last.Op == OXFALL {
+		if last.Xoffset == n.Xoffset && last.Op == ir.OXFALL {
 			if typeswvar != nil {
 				setlineno(last)
 				Yyerror("cannot fallthrough in type switch")
@@ -401,7 +544,7 @@ func casebody(sw *Node, typeswvar *Node) {
 				Yyerror("cannot fallthrough final case in switch")
 			}
 
-			last.Op = OFALL
+			last.Op = ir.OFALL
 		} else {
 			stat = append(stat, br)
 		}
@@ -421,7 +564,7 @@ func casebody(sw *Node, typeswvar *Node) {
 // This should be per-function, but it is a global counter for now.
 var nSwitchLabel int
 
-func newCaseLabel() *Node {
+func newCaseLabel() *ir.Node {
 	label := strconv.Itoa(nSwitchLabel)
 	nSwitchLabel++
 	return newname(Lookup(label))
@@ -430,7 +573,7 @@ func newCaseLabel() *Node {
 // caseClauses generates a slice of caseClauses
 // corresponding to the clauses in the switch statement sw.
 // Kind is the kind of switch statement.
-func caseClauses(sw *Node, kind int) []*caseClause {
+func caseClauses(sw *ir.Node, kind int) []*caseClause {
 	var cc []*caseClause
 	for _, n := range sw.List.Slice() {
 		c := new(caseClause)
@@ -446,9 +589,9 @@ func caseClauses(sw *Node, kind int) []*caseClause {
 		if kind == switchKindType {
 			// type switch
 			switch {
-			case n.Left.Op == OLITERAL:
+			case n.Left.Op == ir.OLITERAL:
 				c.typ = caseKindTypeNil
-			case Istype(n.Left.Type, TINTER):
+			case Istype(n.Left.Type, ir.TINTER):
 				c.typ = caseKindTypeVar
 			default:
 				c.typ = caseKindTypeConst
@@ -456,8 +599,8 @@ func caseClauses(sw *Node, kind int) []*caseClause {
 			}
 		} else {
 			// expression switch
-			switch consttype(n.Left) {
-			case CTFLT, CTINT, CTRUNE, CTSTR:
+			switch ir.Consttype(n.Left) {
+			case ir.CTFLT, ir.CTINT, ir.CTRUNE, ir.CTSTR:
 				c.typ = caseKindExprConst
 			default:
 				c.typ = caseKindExprVar
@@ -469,7 +612,8 @@ func caseClauses(sw *Node, kind int) []*caseClause {
 		return nil
 	}
 
-	// sort by value and diagnose duplicate cases
+	// diagnose duplicate cases in type switches
+	// (typecheckswitch diagnoses them in expression switches)
 	if kind == switchKindType {
 		// type switch
 		sort.Sort(caseClauseByType(cc))
@@ -486,20 +630,6 @@ func caseClauses(sw *Node, kind int) []*caseClause {
 				}
 			}
 		}
-	} else {
-		// expression switch
-		sort.Sort(caseClauseByExpr(cc))
-		for i, c1 := range cc {
-			if i+1 == len(cc) {
-				break
-			}
-			c2 := cc[i+1]
-			if exprcmp(c1, c2) != 0 {
-				continue
-			}
-			setlineno(c2.node)
-			Yyerror("duplicate case %v in switch\n\tprevious case at %v", c1.node.Left, c1.node.Line())
-		}
 	}
 
 	// put list back in processing order
@@ -512,7 +642,7 @@ func caseClauses(sw *Node, kind int) []*caseClause {
 // The AST is generally of the form of a linear
 // search using if..goto, although binary search
 // is used with long runs of concrete types.
-func (s *typeSwitch) walk(sw *Node) {
+func (s *typeSwitch) walk(sw *ir.Node) {
 	cond := sw.Left
 	sw.Left = nil
 
@@ -527,24 +657,24 @@ func (s *typeSwitch) walk(sw *Node) {
 	}
 
 	cond.Right = walkexpr(cond.Right, &sw.Ninit)
-	if !Istype(cond.Right.Type, TINTER) {
+	if !Istype(cond.Right.Type, ir.TINTER) {
 		Yyerror("type switch must be on an interface")
 		return
 	}
 
-	var cas []*Node
+	var cas []*ir.Node
 
 	// predeclare temporary variables and the boolean var
 	s.facename = temp(cond.Right.Type)
 
-	a := Nod(OAS, s.facename, cond.Right)
+	a := Nod(ir.OAS, s.facename, cond.Right)
 	a = typecheck(a, Etop)
 	cas = append(cas, a)
 
-	s.okname = temp(Types[TBOOL])
+	s.okname = temp(ir.Types[ir.TBOOL])
 	s.okname = typecheck(s.okname, Erv)
 
-	s.hashname = temp(Types[TUINT32])
+	s.hashname = temp(ir.Types[ir.TUINT32])
 	s.hashname = typecheck(s.hashname, Erv)
 
 	// set up labels and jumps
@@ -552,14 +682,14 @@ func (s *typeSwitch) walk(sw *Node) {
 
 	cc := caseClauses(sw, switchKindType)
 	sw.List.Set(nil)
-	var def *Node
+	var def *ir.Node
 	if len(cc) > 0 && cc[0].typ == caseKindDefault {
 		def = cc[0].node.Right
 		cc = cc[1:]
 	} else {
-		def = Nod(OBREAK, nil, nil)
+		def = Nod(ir.OBREAK, nil, nil)
 	}
-	var typenil *Node
+	var typenil *ir.Node
 	if len(cc) > 0 && cc[0].typ == caseKindTypeNil {
 		typenil = cc[0].node.Right
 		cc = cc[1:]
@@ -573,21 +703,21 @@ func (s *typeSwitch) walk(sw *Node) {
 	// Use a similar strategy for non-empty interfaces.
 
 	// Get interface descriptor word.
-	typ := Nod(OITAB, s.facename, nil)
+	typ := Nod(ir.OITAB, s.facename, nil)
 
 	// Check for nil first.
-	i := Nod(OIF, nil, nil)
-	i.Left = Nod(OEQ, typ, nodnil())
+	i := Nod(ir.OIF, nil, nil)
+	i.Left = Nod(ir.OEQ, typ, nodnil())
 	if typenil != nil {
 		// Do explicit nil case right here.
 		i.Nbody.Set1(typenil)
 	} else {
 		// Jump to default case.
 		lbl := newCaseLabel()
-		i.Nbody.Set1(Nod(OGOTO, lbl, nil))
+		i.Nbody.Set1(Nod(ir.OGOTO, lbl, nil))
 		// Wrap default case with label.
-		blk := Nod(OBLOCK, nil, nil)
-		blk.List.Set([]*Node{Nod(OLABEL, lbl, nil), def})
+		blk := Nod(ir.OBLOCK, nil, nil)
+		blk.List.Set([]*ir.Node{Nod(ir.OLABEL, lbl, nil), def})
 		def = blk
 	}
 	i.Left = typecheck(i.Left, Erv)
@@ -595,19 +725,19 @@ func (s *typeSwitch) walk(sw *Node) {
 
 	if !isnilinter(cond.Right.Type) {
 		// Load type from itab.
-		typ = NodSym(ODOTPTR, typ, nil)
-		typ.Type = Ptrto(Types[TUINT8])
+		typ = NodSym(ir.ODOTPTR, typ, nil)
+		typ.Type = Ptrto(ir.Types[ir.TUINT8])
 		typ.Typecheck = 1
 		typ.Xoffset = int64(Widthptr) // offset of _type in runtime.itab
 		typ.Bounded = true            // guaranteed not to fault
 	}
 	// Load hash from type.
-	h := NodSym(ODOTPTR, typ, nil)
-	h.Type = Types[TUINT32]
+	h := NodSym(ir.ODOTPTR, typ, nil)
+	h.Type = ir.Types[ir.TUINT32]
 	h.Typecheck = 1
 	h.Xoffset = int64(2 * Widthptr) // offset of hash in runtime._type
 	h.Bounded = true                // guaranteed not to fault
-	a = Nod(OAS, s.hashname, h)
+	a = Nod(ir.OAS, s.hashname, h)
 	a = typecheck(a, Etop)
 	cas = append(cas, a)
 
@@ -652,7 +782,7 @@ func (s *typeSwitch) walk(sw *Node) {
 		ncase := 0
 		for i := 0; i < run; i++ {
 			ncase++
-			hash := []*Node{cc[i].node.Right}
+			hash := []*ir.Node{cc[i].node.Right}
 			for j := i + 1; j < run && cc[i].hash == cc[j].hash; j++ {
 				hash = append(hash, cc[j].node.Right)
 			}
@@ -675,29 +805,29 @@ func (s *typeSwitch) walk(sw *Node) {
 
 // typeone generates an AST that jumps to the
 // case body if the variable is of type t.
-func (s *typeSwitch) typeone(t *Node) *Node {
-	var name *Node
-	var init []*Node
+func (s *typeSwitch) typeone(t *ir.Node) *ir.Node {
+	var name *ir.Node
+	var init []*ir.Node
 	if t.Rlist.Len() == 0 {
 		name = nblank
 		nblank = typecheck(nblank, Erv|Easgn)
 	} else {
 		name = t.Rlist.First()
-		init = []*Node{Nod(ODCL, name, nil)}
-		a := Nod(OAS, name, nil)
+		init = []*ir.Node{Nod(ir.ODCL, name, nil)}
+		a := Nod(ir.OAS, name, nil)
 		a = typecheck(a, Etop)
 		init = append(init, a)
 	}
 
-	a := Nod(OAS2, nil, nil)
-	a.List.Set([]*Node{name, s.okname}) // name, ok =
-	b := Nod(ODOTTYPE, s.facename, nil)
+	a := Nod(ir.OAS2, nil, nil)
+	a.List.Set([]*ir.Node{name, s.okname}) // name, ok =
+	b := Nod(ir.ODOTTYPE, s.facename, nil)
 	b.Type = t.Left.Type // interface.(type)
 	a.Rlist.Set1(b)
 	a = typecheck(a, Etop)
 	init = append(init, a)
 
-	c := Nod(OIF, nil, nil)
+	c := Nod(ir.OIF, nil, nil)
 	c.Left = s.okname
 	c.Nbody.Set1(t.Right) // if ok { goto l }
 
@@ -705,16 +835,16 @@ func (s *typeSwitch) typeone(t *Node) *Node {
 }
 
 // walkCases generates an AST implementing the cases in cc.
-func (s *typeSwitch) walkCases(cc []*caseClause) *Node {
+func (s *typeSwitch) walkCases(cc []*caseClause) *ir.Node {
 	if len(cc) < binarySearchMin {
-		var cas []*Node
+		var cas []*ir.Node
 		for _, c := range cc {
 			n := c.node
 			if c.typ != caseKindTypeConst {
 				Fatalf("typeSwitch walkCases")
 			}
-			a := Nod(OIF, nil, nil)
-			a.Left = Nod(OEQ, s.hashname, Nodintconst(int64(c.hash)))
+			a := Nod(ir.OIF, nil, nil)
+			a.Left = Nod(ir.OEQ, s.hashname, Nodintconst(int64(c.hash)))
 			a.Left = typecheck(a.Left, Erv)
 			a.Nbody.Set1(n.Right)
 			cas = append(cas, a)
@@ -724,8 +854,8 @@ func (s *typeSwitch) walkCases(cc []*caseClause) *Node {
 
 	// find the middle and recur
 	half := len(cc) / 2
-	a := Nod(OIF, nil, nil)
-	a.Left = Nod(OLE, s.hashname, Nodintconst(int64(cc[half-1].hash)))
+	a := Nod(ir.OIF, nil, nil)
+	a.Left = Nod(ir.OLE, s.hashname, Nodintconst(int64(cc[half-1].hash)))
 	a.Left = typecheck(a.Left, Erv)
 	a.Nbody.Set1(s.walkCases(cc[:half]))
 	a.Rlist.Set1(s.walkCases(cc[half:]))
@@ -794,11 +924,11 @@ func exprcmp(c1, c2 *caseClause) int {
 
 	// sort by constant value to enable binary search
 	switch ct {
-	case CTFLT:
-		return n1.Val().U.(*Mpflt).Cmp(n2.Val().U.(*Mpflt))
-	case CTINT, CTRUNE:
-		return n1.Val().U.(*Mpint).Cmp(n2.Val().U.(*Mpint))
-	case CTSTR:
+	case ir.CTFLT:
+		return n1.Val().U.(*ir.Mpflt).Cmp(n2.Val().U.(*ir.Mpflt))
+	case ir.CTINT, ir.CTRUNE:
+		return n1.Val().U.(*ir.Mpint).Cmp(n2.Val().U.(*ir.Mpint))
+	case ir.CTSTR:
 		// Sort strings by length and then by value.
 		// It is much cheaper to compare lengths than values,
 		// and all we need here is consistency.
@@ -6,6 +6,7 @@ package gc
 
 import (
 	"cmd/compile/internal/big"
+	"cmd/compile/internal/ir"
 	"testing"
 )
 
@@ -16,120 +17,120 @@ func TestExprcmp(t *testing.T) {
 	}{
 		// Non-constants.
 		{
-			caseClause{node: Nod(OXXX, nil, nil), typ: caseKindExprVar},
-			caseClause{node: Nod(OXXX, nil, nil), typ: caseKindExprConst},
+			caseClause{node: Nod(ir.OXXX, nil, nil), typ: caseKindExprVar},
+			caseClause{node: Nod(ir.OXXX, nil, nil), typ: caseKindExprConst},
 			+1,
 		},
 		{
-			caseClause{node: Nod(OXXX, nil, nil), typ: caseKindExprConst},
-			caseClause{node: Nod(OXXX, nil, nil), typ: caseKindExprVar},
+			caseClause{node: Nod(ir.OXXX, nil, nil), typ: caseKindExprConst},
+			caseClause{node: Nod(ir.OXXX, nil, nil), typ: caseKindExprVar},
 			-1,
 		},
 		// Type switches
 		{
-			caseClause{node: Nod(OXXX, Nodintconst(0), nil), typ: caseKindExprConst},
-			caseClause{node: Nod(OXXX, Nodbool(true), nil), typ: caseKindExprConst},
+			caseClause{node: Nod(ir.OXXX, Nodintconst(0), nil), typ: caseKindExprConst},
+			caseClause{node: Nod(ir.OXXX, Nodbool(true), nil), typ: caseKindExprConst},
 			-1,
 		},
 		{
-			caseClause{node: Nod(OXXX, Nodbool(true), nil), typ: caseKindExprConst},
-			caseClause{node: Nod(OXXX, Nodintconst(1), nil), typ: caseKindExprConst},
+			caseClause{node: Nod(ir.OXXX, Nodbool(true), nil), typ: caseKindExprConst},
+			caseClause{node: Nod(ir.OXXX, Nodintconst(1), nil), typ: caseKindExprConst},
 			+1,
 		},
 		{
-			caseClause{node: Nod(OXXX, &Node{Type: &Type{Etype: TBOOL, Vargen: 1}}, nil), typ: caseKindExprConst},
-			caseClause{node: Nod(OXXX, &Node{Type: &Type{Etype: TINT, Vargen: 0}}, nil), typ: caseKindExprConst},
+			caseClause{node: Nod(ir.OXXX, &ir.Node{Type: &ir.Type{Etype: ir.TBOOL, Vargen: 1}}, nil), typ: caseKindExprConst},
+			caseClause{node: Nod(ir.OXXX, &ir.Node{Type: &ir.Type{Etype: ir.TINT, Vargen: 0}}, nil), typ: caseKindExprConst},
 			+1,
 		},
 		{
-			caseClause{node: Nod(OXXX, &Node{Type: &Type{Etype: TBOOL, Vargen: 1}}, nil), typ: caseKindExprConst},
-			caseClause{node: Nod(OXXX, &Node{Type: &Type{Etype: TINT, Vargen: 1}}, nil), typ: caseKindExprConst},
+			caseClause{node: Nod(ir.OXXX, &ir.Node{Type: &ir.Type{Etype: ir.TBOOL, Vargen: 1}}, nil), typ: caseKindExprConst},
+			caseClause{node: Nod(ir.OXXX, &ir.Node{Type: &ir.Type{Etype: ir.TINT, Vargen: 1}}, nil), typ: caseKindExprConst},
 			-1,
 		},
 		{
-			caseClause{node: Nod(OXXX, &Node{Type: &Type{Etype: TBOOL, Vargen: 0}}, nil), typ: caseKindExprConst},
-			caseClause{node: Nod(OXXX, &Node{Type: &Type{Etype: TINT, Vargen: 1}}, nil), typ: caseKindExprConst},
+			caseClause{node: Nod(ir.OXXX, &ir.Node{Type: &ir.Type{Etype: ir.TBOOL, Vargen: 0}}, nil), typ: caseKindExprConst},
+			caseClause{node: Nod(ir.OXXX, &ir.Node{Type: &ir.Type{Etype: ir.TINT, Vargen: 1}}, nil), typ: caseKindExprConst},
 			-1,
 		},
 		// Constant values.
 		// CTFLT
 		{
-			caseClause{node: Nod(OXXX, nodlit(Val{&Mpflt{Val: *big.NewFloat(0.1)}}), nil), typ: caseKindExprConst},
-			caseClause{node: Nod(OXXX, nodlit(Val{&Mpflt{Val: *big.NewFloat(0.2)}}), nil), typ: caseKindExprConst},
+			caseClause{node: Nod(ir.OXXX, nodlit(ir.Val{U: &ir.Mpflt{Val: *big.NewFloat(0.1)}}), nil), typ: caseKindExprConst},
+			caseClause{node: Nod(ir.OXXX, nodlit(ir.Val{U: &ir.Mpflt{Val: *big.NewFloat(0.2)}}), nil), typ: caseKindExprConst},
 			-1,
 		},
 		{
-			caseClause{node: Nod(OXXX, nodlit(Val{&Mpflt{Val: *big.NewFloat(0.1)}}), nil), typ: caseKindExprConst},
-			caseClause{node: Nod(OXXX, nodlit(Val{&Mpflt{Val: *big.NewFloat(0.1)}}), nil), typ: caseKindExprConst},
+			caseClause{node: Nod(ir.OXXX, nodlit(ir.Val{U: &ir.Mpflt{Val: *big.NewFloat(0.1)}}), nil), typ: caseKindExprConst},
+			caseClause{node: Nod(ir.OXXX, nodlit(ir.Val{U: &ir.Mpflt{Val: *big.NewFloat(0.1)}}), nil), typ: caseKindExprConst},
 			0,
 		},
 		{
-			caseClause{node: Nod(OXXX, nodlit(Val{&Mpflt{Val: *big.NewFloat(0.2)}}), nil), typ: caseKindExprConst},
-			caseClause{node: Nod(OXXX, nodlit(Val{&Mpflt{Val: *big.NewFloat(0.1)}}), nil), typ: caseKindExprConst},
+			caseClause{node: Nod(ir.OXXX, nodlit(ir.Val{U: &ir.Mpflt{Val: *big.NewFloat(0.2)}}), nil), typ: caseKindExprConst},
+			caseClause{node: Nod(ir.OXXX, nodlit(ir.Val{U: &ir.Mpflt{Val: *big.NewFloat(0.1)}}), nil), typ: caseKindExprConst},
 			+1,
 		},
 		// CTINT
 		{
-			caseClause{node: Nod(OXXX, Nodintconst(0), nil), typ: caseKindExprConst},
-			caseClause{node: Nod(OXXX, Nodintconst(1), nil), typ: caseKindExprConst},
+			caseClause{node: Nod(ir.OXXX, Nodintconst(0), nil), typ: caseKindExprConst},
+			caseClause{node: Nod(ir.OXXX, Nodintconst(1), nil), typ: caseKindExprConst},
 			-1,
 		},
 		{
-			caseClause{node: Nod(OXXX, Nodintconst(1), nil), typ: caseKindExprConst},
-			caseClause{node: Nod(OXXX, Nodintconst(1), nil), typ: caseKindExprConst},
+			caseClause{node: Nod(ir.OXXX, Nodintconst(1), nil), typ: caseKindExprConst},
+			caseClause{node: Nod(ir.OXXX, Nodintconst(1), nil), typ: caseKindExprConst},
 			0,
 		},
 		{
-			caseClause{node: Nod(OXXX, Nodintconst(1), nil), typ: caseKindExprConst},
-			caseClause{node: Nod(OXXX, Nodintconst(0), nil), typ: caseKindExprConst},
+			caseClause{node: Nod(ir.OXXX, Nodintconst(1), nil), typ: caseKindExprConst},
+			caseClause{node: Nod(ir.OXXX, Nodintconst(0), nil), typ: caseKindExprConst},
 			+1,
 		},
 		// CTRUNE
 		{
-			caseClause{node: Nod(OXXX, nodlit(Val{&Mpint{Val: *big.NewInt('a'), Rune: true}}), nil), typ: caseKindExprConst},
-			caseClause{node: Nod(OXXX, nodlit(Val{&Mpint{Val: *big.NewInt('b'), Rune: true}}), nil), typ: caseKindExprConst},
+			caseClause{node: Nod(ir.OXXX, nodlit(ir.Val{U: &ir.Mpint{Val: *big.NewInt('a'), Rune: true}}), nil), typ: caseKindExprConst},
+			caseClause{node: Nod(ir.OXXX, nodlit(ir.Val{U: &ir.Mpint{Val: *big.NewInt('b'), Rune: true}}), nil), typ: caseKindExprConst},
 			-1,
 		},
 		{
-			caseClause{node: Nod(OXXX, nodlit(Val{&Mpint{Val: *big.NewInt('b'), Rune: true}}), nil), typ: caseKindExprConst},
-			caseClause{node: Nod(OXXX, nodlit(Val{&Mpint{Val: *big.NewInt('b'), Rune: true}}), nil), typ: caseKindExprConst},
+			caseClause{node: Nod(ir.OXXX, nodlit(ir.Val{U: &ir.Mpint{Val: *big.NewInt('b'), Rune: true}}), nil), typ: caseKindExprConst},
+			caseClause{node: Nod(ir.OXXX, nodlit(ir.Val{U: &ir.Mpint{Val: *big.NewInt('b'), Rune: true}}), nil), typ: caseKindExprConst},
 			0,
 		},
 		{
-			caseClause{node: Nod(OXXX, nodlit(Val{&Mpint{Val: *big.NewInt('b'), Rune: true}}), nil), typ: caseKindExprConst},
-			caseClause{node: Nod(OXXX, nodlit(Val{&Mpint{Val: *big.NewInt('a'), Rune: true}}), nil), typ: caseKindExprConst},
+			caseClause{node: Nod(ir.OXXX, nodlit(ir.Val{U: &ir.Mpint{Val: *big.NewInt('b'), Rune: true}}), nil), typ: caseKindExprConst},
+			caseClause{node: Nod(ir.OXXX, nodlit(ir.Val{U: &ir.Mpint{Val: *big.NewInt('a'), Rune: true}}), nil), typ: caseKindExprConst},
 			+1,
 		},
 		// CTSTR
 		{
-			caseClause{node: Nod(OXXX, nodlit(Val{"ab"}), nil), typ: caseKindExprConst},
-			caseClause{node: Nod(OXXX, nodlit(Val{"abc"}), nil), typ: caseKindExprConst},
+			caseClause{node: Nod(ir.OXXX, nodlit(ir.Val{U: "ab"}), nil), typ: caseKindExprConst},
+			caseClause{node: Nod(ir.OXXX, nodlit(ir.Val{U: "abc"}), nil), typ: caseKindExprConst},
 			-1,
 		},
 		{
-			caseClause{node: Nod(OXXX, nodlit(Val{"abc"}), nil), typ: caseKindExprConst},
-			caseClause{node: Nod(OXXX, nodlit(Val{"xyz"}), nil), typ: caseKindExprConst},
+			caseClause{node: Nod(ir.OXXX, nodlit(ir.Val{U: "abc"}), nil), typ: caseKindExprConst},
+			caseClause{node: Nod(ir.OXXX, nodlit(ir.Val{U: "xyz"}), nil), typ: caseKindExprConst},
 			-1,
 		},
 		{
-			caseClause{node: Nod(OXXX, nodlit(Val{"abc"}), nil), typ: caseKindExprConst},
-			caseClause{node: Nod(OXXX, nodlit(Val{"abc"}), nil), typ: caseKindExprConst},
+			caseClause{node: Nod(ir.OXXX, nodlit(ir.Val{U: "abc"}), nil), typ: caseKindExprConst},
+			caseClause{node: Nod(ir.OXXX, nodlit(ir.Val{U: "abc"}), nil), typ: caseKindExprConst},
 			0,
 		},
 		{
-			caseClause{node: Nod(OXXX, nodlit(Val{"abc"}), nil), typ: caseKindExprConst},
-			caseClause{node: Nod(OXXX, nodlit(Val{"ab"}), nil), typ: caseKindExprConst},
+			caseClause{node: Nod(ir.OXXX, nodlit(ir.Val{U: "abc"}), nil), typ: caseKindExprConst},
+			caseClause{node: Nod(ir.OXXX, nodlit(ir.Val{U: "ab"}), nil), typ: caseKindExprConst},
 			+1,
 		},
 		{
-			caseClause{node: Nod(OXXX, nodlit(Val{"xyz"}), nil), typ: caseKindExprConst},
-			caseClause{node: Nod(OXXX, nodlit(Val{"abc"}), nil), typ: caseKindExprConst},
+			caseClause{node: Nod(ir.OXXX, nodlit(ir.Val{U: "xyz"}), nil), typ: caseKindExprConst},
+			caseClause{node: Nod(ir.OXXX, nodlit(ir.Val{U: "abc"}), nil), typ: caseKindExprConst},
 			+1,
 		},
 		// Everything else should compare equal.
 		{
-			caseClause{node: Nod(OXXX, nodnil(), nil), typ: caseKindExprConst},
-			caseClause{node: Nod(OXXX, nodnil(), nil), typ: caseKindExprConst},
+			caseClause{node: Nod(ir.OXXX, nodnil(), nil), typ: caseKindExprConst},
+			caseClause{node: Nod(ir.OXXX, nodnil(), nil), typ: caseKindExprConst},
 			0,
 		},
 	}
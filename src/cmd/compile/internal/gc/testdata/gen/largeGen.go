@@ -0,0 +1,201 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"io/ioutil"
+	"log"
+	"math/rand"
+	"strings"
+)
+
+// This program generates a test of a function large enough for
+// buildssa to place its phis with insertPhis (see ../../phi.go).
+// The function assigns to many variables, and to its parameter, in
+// branches and loops; the generator works out what it should return.
+
+// run as `go run largeGen.go`.  A file called large_ssa.go
+// will be written into the parent directory containing the tests.
+
+const (
+	nvars = 40  // int variables, the last of which is the parameter
+	nstmt = 600 // top-level statements
+	mod   = 1000003
+)
+
+// A stmt is a statement of the generated function.
+type stmt struct {
+	kind    int // one of the constants below
+	i, j, c int
+	body    []stmt // if, loop
+	els     []stmt // if
+}
+
+const (
+	assign = iota // vi = (vj*c + vi + c) % mod
+	call          // vi = id(vj + c)
+	str           // si = sid(si[len(si)/2:] + "c")
+	swap          // vi, vj = vj, vi
+	cond          // if vi > c { body } else { els }
+	loop          // for k := 0; k < c; k++ { body }
+)
+
+var r = rand.New(rand.NewSource(1))
+
+func gen(depth int) stmt {
+	s := stmt{i: r.Intn(nvars), j: r.Intn(nvars)}
+	switch k := r.Intn(10); {
+	case k < 4:
+		s.kind, s.c = assign, 1+r.Intn(50)
+	case k < 5:
+		s.kind, s.c = call, r.Intn(50)
+	case k < 6:
+		s.kind, s.c = str, r.Intn(10)
+	case k < 8 && depth < 3:
+		s.kind, s.c = cond, r.Intn(mod)
+		s.body = gens(depth+1, 1+r.Intn(4))
+		s.els = gens(depth+1, r.Intn(4))
+	case k < 9 && depth < 2:
+		s.kind, s.c = loop, 1+r.Intn(3)
+		s.body = gens(depth+1, 1+r.Intn(3))
+	default:
+		s.kind = swap
+	}
+	return s
+}
+
+func gens(depth, n int) []stmt {
+	var l []stmt
+	for i := 0; i < n; i++ {
+		l = append(l, gen(depth))
+	}
+	return l
+}
+
+func name(i int) string {
+	if i == nvars-1 {
+		return "a"
+	}
+	return fmt.Sprintf("v%d", i)
+}
+
+func print(w *bytes.Buffer, l []stmt, depth int) {
+	for _, s := range l {
+		vi, vj := name(s.i), name(s.j)
+		switch s.kind {
+		case assign:
+			fmt.Fprintf(w, "%s = (%s*%d + %s + %d) %% %d\n", vi, vj, s.c, vi, s.c, mod)
+		case call:
+			fmt.Fprintf(w, "%s = id(%s + %d)\n", vi, vj, s.c)
+		case str:
+			fmt.Fprintf(w, "s%d = sid(s%d[len(s%d)/2:] + \"%d\")\n", s.i, s.i, s.i, s.c)
+		case swap:
+			fmt.Fprintf(w, "%s, %s = %s, %s\n", vi, vj, vj, vi)
+		case cond:
+			fmt.Fprintf(w, "if %s > %d {\n", vi, s.c)
+			print(w, s.body, depth)
+			fmt.Fprintf(w, "} else {\n")
+			print(w, s.els, depth)
+			fmt.Fprintf(w, "}\n")
+		case loop:
+			fmt.Fprintf(w, "for k%d := 0; k%d < %d; k%d++ {\n", depth, depth, s.c, depth)
+			print(w, s.body, depth+1)
+			fmt.Fprintf(w, "}\n")
+		}
+	}
+}
+
+func run(l []stmt, v []int, s []string) {
+	for _, x := range l {
+		switch x.kind {
+		case assign:
+			v[x.i] = (v[x.j]*x.c + v[x.i] + x.c) % mod
+		case call:
+			v[x.i] = v[x.j] + x.c
+		case str:
+			s[x.i] = s[x.i][len(s[x.i])/2:] + fmt.Sprint(x.c)
+		case swap:
+			v[x.i], v[x.j] = v[x.j], v[x.i]
+		case cond:
+			if v[x.i] > x.c {
+				run(x.body, v, s)
+			} else {
+				run(x.els, v, s)
+			}
+		case loop:
+			for k := 0; k < x.c; k++ {
+				run(x.body, v, s)
+			}
+		}
+	}
+}
+
+func main() {
+	body := gens(0, nstmt)
+	v := make([]int, nvars)
+	s := make([]string, nvars)
+	for i := range v[:nvars-1] {
+		v[i] = i
+	}
+	run(body, v, s)
+
+	w := new(bytes.Buffer)
+	fmt.Fprintf(w, "// run\n")
+	fmt.Fprintf(w, "// autogenerated from gen/largeGen.go - do not edit!\n")
+	fmt.Fprintf(w, "package main\n")
+	fmt.Fprintf(w, "import \"fmt\"\n")
+
+	fmt.Fprintf(w, "//go:noinline\n")
+	fmt.Fprintf(w, "func id(x int) int { return x }\n")
+	fmt.Fprintf(w, "//go:noinline\n")
+	fmt.Fprintf(w, "func sid(s string) string { return s }\n")
+
+	// function being tested
+	fmt.Fprintf(w, "func large_ssa(a int) ([%d]int, [%d]string) {\n", nvars, nvars)
+	for i := 0; i < nvars; i++ {
+		if i < nvars-1 {
+			fmt.Fprintf(w, "v%d := a + %d\n", i, i)
+		}
+		fmt.Fprintf(w, "s%d := \"\"\n", i)
+	}
+	print(w, body, 0)
+	var vs, ss []string
+	for i := 0; i < nvars; i++ {
+		vs = append(vs, name(i))
+		ss = append(ss, fmt.Sprintf("s%d", i))
+	}
+	fmt.Fprintf(w, "return [%d]int{%s}, [%d]string{%s}\n", nvars, strings.Join(vs, ", "), nvars, strings.Join(ss, ", "))
+	fmt.Fprintf(w, "}\n")
+
+	// testing harness
+	fmt.Fprintf(w, "func main() {\n")
+	fmt.Fprintf(w, "v, s := large_ssa(0)\n")
+	fmt.Fprintf(w, "if want := [%d]int%s; v != want {\n", nvars, fmt.Sprintf("%#v", v)[len("[]int"):])
+	fmt.Fprintf(w, "  fmt.Printf(\"large_ssa ints got=%%v, want %%v\\n\", v, want)\n")
+	fmt.Fprintf(w, "  panic(\"failed\")\n")
+	fmt.Fprintf(w, "}\n")
+	fmt.Fprintf(w, "if want := [%d]string%s; s != want {\n", nvars, fmt.Sprintf("%#v", s)[len("[]string"):])
+	fmt.Fprintf(w, "  fmt.Printf(\"large_ssa strings got=%%q, want %%q\\n\", s, want)\n")
+	fmt.Fprintf(w, "  panic(\"failed\")\n")
+	fmt.Fprintf(w, "}\n")
+	fmt.Fprintf(w, "}\n")
+
+	// gofmt result
+	b := w.Bytes()
+	src, err := format.Source(b)
+	if err != nil {
+		fmt.Printf("%s\n", b)
+		panic(err)
+	}
+
+	// write to file
+	err = ioutil.WriteFile("../large_ssa.go", src, 0666)
+	if err != nil {
+		log.Fatalf("can't write output: %v\n", err)
+	}
+}
@@ -0,0 +1,21 @@
+// phases: typecheck closure walk
+
+package p
+
+func counter() func() int {
+	n := 0
+	return func() int {
+		n++
+		return n
+	}
+}
+
+func apply(xs []int) int {
+	sum := 0
+	func() {
+		for _, x := range xs {
+			sum += x
+		}
+	}()
+	return sum
+}
@@ -0,0 +1,15 @@
+// phases: typecheck walk
+
+package p
+
+func concat(a, b string) string {
+	return a + b + "!"
+}
+
+func bytes(s string) int {
+	n := 0
+	for _, c := range []byte(s) {
+		n += int(c)
+	}
+	return n
+}
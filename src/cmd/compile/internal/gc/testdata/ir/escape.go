@@ -0,0 +1,20 @@
+// phases: escape
+
+package p
+
+type T struct{ p *int }
+
+func leak(x int) *int {
+	return &x
+}
+
+func local() int {
+	y := 1
+	t := T{&y}
+	return *t.p
+}
+
+func store(t *T) {
+	z := 2
+	t.p = &z
+}
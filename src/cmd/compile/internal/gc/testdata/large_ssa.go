@@ -0,0 +1,3277 @@
+// run
+// autogenerated from gen/largeGen.go - do not edit!
+package main
+
+import "fmt"
+
+//go:noinline
+func id(x int) int { return x }
+
+//go:noinline
+func sid(s string) string { return s }
+func large_ssa(a int) ([40]int, [40]string) {
+	v0 := a + 0
+	s0 := ""
+	v1 := a + 1
+	s1 := ""
+	v2 := a + 2
+	s2 := ""
+	v3 := a + 3
+	s3 := ""
+	v4 := a + 4
+	s4 := ""
+	v5 := a + 5
+	s5 := ""
+	v6 := a + 6
+	s6 := ""
+	v7 := a + 7
+	s7 := ""
+	v8 := a + 8
+	s8 := ""
+	v9 := a + 9
+	s9 := ""
+	v10 := a + 10
+	s10 := ""
+	v11 := a + 11
+	s11 := ""
+	v12 := a + 12
+	s12 := ""
+	v13 := a + 13
+	s13 := ""
+	v14 := a + 14
+	s14 := ""
+	v15 := a + 15
+	s15 := ""
+	v16 := a + 16
+	s16 := ""
+	v17 := a + 17
+	s17 := ""
+	v18 := a + 18
+	s18 := ""
+	v19 := a + 19
+	s19 := ""
+	v20 := a + 20
+	s20 := ""
+	v21 := a + 21
+	s21 := ""
+	v22 := a + 22
+	s22 := ""
+	v23 := a + 23
+	s23 := ""
+	v24 := a + 24
+	s24 := ""
+	v25 := a + 25
+	s25 := ""
+	v26 := a + 26
+	s26 := ""
+	v27 := a + 27
+	s27 := ""
+	v28 := a + 28
+	s28 := ""
+	v29 := a + 29
+	s29 := ""
+	v30 := a + 30
+	s30 := ""
+	v31 := a + 31
+	s31 := ""
+	v32 := a + 32
+	s32 := ""
+	v33 := a + 33
+	s33 := ""
+	v34 := a + 34
+	s34 := ""
+	v35 := a + 35
+	s35 := ""
+	v36 := a + 36
+	s36 := ""
+	v37 := a + 37
+	s37 := ""
+	v38 := a + 38
+	s38 := ""
+	s39 := ""
+	if v1 > 981242 {
+		v38 = (v25*7 + v38 + 7) % 1000003
+		v20 = (v14*13 + v20 + 13) % 1000003
+	} else {
+		v8 = (v34*46 + v8 + 46) % 1000003
+	}
+	s37 = sid(s37[len(s37)/2:] + "6")
+	if v8 > 978036 {
+		s8 = sid(s8[len(s8)/2:] + "1")
+		v8 = (v27*30 + v8 + 30) % 1000003
+		v36 = (v17*36 + v36 + 36) % 1000003
+		v26 = (v13*45 + v26 + 45) % 1000003
+	} else {
+		for k0 := 0; k0 < 2; k0++ {
+			v33 = (v37*40 + v33 + 40) % 1000003
+			s39 = sid(s39[len(s39)/2:] + "8")
+		}
+		s18 = sid(s18[len(s18)/2:] + "1")
+		if v30 > 827090 {
+			v1 = (v2*47 + v1 + 47) % 1000003
+		} else {
+			v16, v2 = v2, v16
+			if v7 > 703833 {
+				v20 = (v23*38 + v20 + 38) % 1000003
+			} else {
+				v1 = (v19*44 + v1 + 44) % 1000003
+			}
+			v11, v2 = v2, v11
+		}
+	}
+	if v16 > 106734 {
+		s32 = sid(s32[len(s32)/2:] + "8")
+		s25 = sid(s25[len(s25)/2:] + "7")
+		v7 = (v10*36 + v7 + 36) % 1000003
+		for k0 := 0; k0 < 2; k0++ {
+			if v22 > 655469 {
+				v31, v14 = v14, v31
+				v2, v21 = v21, v2
+			} else {
+				v30, v13 = v13, v30
+				v19 = (v21*26 + v19 + 26) % 1000003
+			}
+			if v5 > 371856 {
+				v18 = (v24*25 + v18 + 25) % 1000003
+			} else {
+				v12, v12 = v12, v12
+				a, v20 = v20, a
+				v11 = (v36*2 + v11 + 2) % 1000003
+			}
+		}
+	} else {
+	}
+	v4 = (v25*2 + v4 + 2) % 1000003
+	for k0 := 0; k0 < 2; k0++ {
+		v18 = (v34*24 + v18 + 24) % 1000003
+	}
+	v22 = (v8*19 + v22 + 19) % 1000003
+	s6 = sid(s6[len(s6)/2:] + "0")
+	if v24 > 30200 {
+		a = (v30*1 + a + 1) % 1000003
+		a = (v0*21 + a + 21) % 1000003
+		v24 = (v7*16 + v24 + 16) % 1000003
+		v2 = (v29*49 + v2 + 49) % 1000003
+	} else {
+	}
+	v15 = (v26*7 + v15 + 7) % 1000003
+	v29 = (v12*28 + v29 + 28) % 1000003
+	v6, v0 = v0, v6
+	v2 = (v7*39 + v2 + 39) % 1000003
+	for k0 := 0; k0 < 2; k0++ {
+		if v19 > 852535 {
+			s21 = sid(s21[len(s21)/2:] + "7")
+			if v33 > 507865 {
+				v0 = (v20*30 + v0 + 30) % 1000003
+			} else {
+				v20, v20 = v20, v20
+			}
+			v34 = (v24*2 + v34 + 2) % 1000003
+			if v21 > 345758 {
+				v37 = (v13*5 + v37 + 5) % 1000003
+				s5 = sid(s5[len(s5)/2:] + "9")
+				v14 = (v0*41 + v14 + 41) % 1000003
+				v0 = (v4*36 + v0 + 36) % 1000003
+			} else {
+				v21 = id(v30 + 19)
+				v30, v16 = v16, v30
+				s32 = sid(s32[len(s32)/2:] + "4")
+			}
+		} else {
+			if v4 > 226405 {
+				v20 = (v22*18 + v20 + 18) % 1000003
+			} else {
+				v25, v2 = v2, v25
+				v32 = (v9*22 + v32 + 22) % 1000003
+				v15 = (v31*4 + v15 + 4) % 1000003
+			}
+		}
+	}
+	if v6 > 155744 {
+		v8 = id(v13 + 34)
+	} else {
+		if v34 > 87960 {
+			v34, v18 = v18, v34
+			v26 = id(v3 + 37)
+			v17, v14 = v14, v17
+			v11 = (v26*29 + v11 + 29) % 1000003
+		} else {
+		}
+		v6 = (v37*37 + v6 + 37) % 1000003
+		v11 = (v25*40 + v11 + 40) % 1000003
+	}
+	v18 = (v7*10 + v18 + 10) % 1000003
+	v8 = (a*3 + v8 + 3) % 1000003
+	v18 = (v25*4 + v18 + 4) % 1000003
+	v0 = (v0*6 + v0 + 6) % 1000003
+	if v36 > 817880 {
+		if v20 > 733565 {
+			v15 = (v33*9 + v15 + 9) % 1000003
+			v31, v25 = v25, v31
+			v4 = (v2*13 + v4 + 13) % 1000003
+		} else {
+			v12, v13 = v13, v12
+		}
+		for k0 := 0; k0 < 1; k0++ {
+			v14 = (v26*32 + v14 + 32) % 1000003
+			v12 = (v37*5 + v12 + 5) % 1000003
+			if v3 > 558154 {
+				v4 = (v21*49 + v4 + 49) % 1000003
+				v36 = (v5*46 + v36 + 46) % 1000003
+				v14, v6 = v6, v14
+			} else {
+			}
+		}
+		v20, v4 = v4, v20
+		if v7 > 83921 {
+			v35, v20 = v20, v35
+			v14, v25 = v25, v14
+			v21 = (v11*36 + v21 + 36) % 1000003
+		} else {
+			v3 = (v15*23 + v3 + 23) % 1000003
+		}
+	} else {
+		v17 = (v22*18 + v17 + 18) % 1000003
+		a = (v7*6 + a + 6) % 1000003
+		v6 = id(v34 + 23)
+	}
+	v14 = (v2*25 + v14 + 25) % 1000003
+	s10 = sid(s10[len(s10)/2:] + "4")
+	v3, v10 = v10, v3
+	v37 = (v5*9 + v37 + 9) % 1000003
+	v38, v14 = v14, v38
+	if v34 > 265111 {
+		v1 = (v27*50 + v1 + 50) % 1000003
+		v8 = id(v0 + 1)
+		if v20 > 517306 {
+			v30 = (v32*15 + v30 + 15) % 1000003
+			if a > 407215 {
+				v23, v36 = v36, v23
+			} else {
+			}
+			if v23 > 264956 {
+				v34, v10 = v10, v34
+			} else {
+			}
+			v11, v34 = v34, v11
+		} else {
+			v33 = (v5*43 + v33 + 43) % 1000003
+			a = (v14*25 + a + 25) % 1000003
+		}
+	} else {
+		for k0 := 0; k0 < 2; k0++ {
+			v16 = (a*17 + v16 + 17) % 1000003
+			v13 = (v36*2 + v13 + 2) % 1000003
+		}
+	}
+	v35 = (v37*18 + v35 + 18) % 1000003
+	v38, v32 = v32, v38
+	v35 = (v13*28 + v35 + 28) % 1000003
+	for k0 := 0; k0 < 3; k0++ {
+		if v9 > 488639 {
+			v36, v34 = v34, v36
+		} else {
+			v2, v4 = v4, v2
+			v10 = (v19*11 + v10 + 11) % 1000003
+			v14 = (v25*14 + v14 + 14) % 1000003
+		}
+		if v1 > 215026 {
+			v3 = (v1*16 + v3 + 16) % 1000003
+			v20 = (v12*42 + v20 + 42) % 1000003
+			if v18 > 193967 {
+				v36 = (v12*6 + v36 + 6) % 1000003
+				v9 = id(v20 + 48)
+				v30 = (v17*15 + v30 + 15) % 1000003
+				v10, v22 = v22, v10
+			} else {
+			}
+		} else {
+			v36, v25 = v25, v36
+			v2 = (v3*22 + v2 + 22) % 1000003
+		}
+	}
+	v33 = (v29*21 + v33 + 21) % 1000003
+	v17 = (v6*41 + v17 + 41) % 1000003
+	v38 = (v25*9 + v38 + 9) % 1000003
+	s38 = sid(s38[len(s38)/2:] + "5")
+	if v1 > 502104 {
+		v19 = (v13*12 + v19 + 12) % 1000003
+		v20 = id(v12 + 14)
+		s38 = sid(s38[len(s38)/2:] + "8")
+	} else {
+		v0 = (v12*17 + v0 + 17) % 1000003
+		a = id(v0 + 10)
+	}
+	v31 = (v26*23 + v31 + 23) % 1000003
+	v20 = (v34*13 + v20 + 13) % 1000003
+	v18 = (v17*41 + v18 + 41) % 1000003
+	v13 = (v18*7 + v13 + 7) % 1000003
+	if v28 > 729096 {
+		v7 = (v4*8 + v7 + 8) % 1000003
+		s22 = sid(s22[len(s22)/2:] + "8")
+	} else {
+		v24 = (v2*26 + v24 + 26) % 1000003
+		v34 = (v7*2 + v34 + 2) % 1000003
+	}
+	s13 = sid(s13[len(s13)/2:] + "0")
+	v5, v27 = v27, v5
+	s18 = sid(s18[len(s18)/2:] + "5")
+	v23 = (v20*40 + v23 + 40) % 1000003
+	v33 = (v31*29 + v33 + 29) % 1000003
+	v1, v26 = v26, v1
+	v15 = (v25*49 + v15 + 49) % 1000003
+	if v34 > 994225 {
+		v36 = (v26*29 + v36 + 29) % 1000003
+	} else {
+		v12 = (v19*44 + v12 + 44) % 1000003
+	}
+	v27 = (v12*24 + v27 + 24) % 1000003
+	if v25 > 879680 {
+		if v7 > 681641 {
+			v16 = id(v32 + 38)
+			v37 = (v0*39 + v37 + 39) % 1000003
+			v35 = id(v37 + 28)
+		} else {
+		}
+		v7 = id(v23 + 2)
+	} else {
+		v1 = (v38*39 + v1 + 39) % 1000003
+		v20 = (v21*42 + v20 + 42) % 1000003
+		if v34 > 546752 {
+			v4, v37 = v37, v4
+		} else {
+			v34 = (v35*36 + v34 + 36) % 1000003
+			a, v27 = v27, a
+			if v2 > 518452 {
+				s32 = sid(s32[len(s32)/2:] + "2")
+				v4, v0 = v0, v4
+			} else {
+				v4 = (v20*30 + v4 + 30) % 1000003
+				s7 = sid(s7[len(s7)/2:] + "8")
+				v3 = (v5*2 + v3 + 2) % 1000003
+			}
+		}
+	}
+	v20 = (v12*33 + v20 + 33) % 1000003
+	for k0 := 0; k0 < 1; k0++ {
+		if v9 > 474539 {
+			v8 = (v4*47 + v8 + 47) % 1000003
+		} else {
+			v1 = id(v33 + 5)
+			s0 = sid(s0[len(s0)/2:] + "3")
+			v18 = (v31*41 + v18 + 41) % 1000003
+		}
+		v32 = id(v38 + 36)
+		v32 = (v23*36 + v32 + 36) % 1000003
+	}
+	for k0 := 0; k0 < 2; k0++ {
+		v23 = (v16*47 + v23 + 47) % 1000003
+	}
+	if v36 > 233746 {
+		if v17 > 947699 {
+			v3 = (v29*34 + v3 + 34) % 1000003
+		} else {
+		}
+		v10 = (v2*48 + v10 + 48) % 1000003
+		v30 = id(v17 + 23)
+	} else {
+		for k0 := 0; k0 < 2; k0++ {
+			s36 = sid(s36[len(s36)/2:] + "5")
+			v25, v25 = v25, v25
+		}
+	}
+	v21 = id(v3 + 19)
+	if v27 > 678052 {
+		if v10 > 712488 {
+			s18 = sid(s18[len(s18)/2:] + "7")
+		} else {
+		}
+		v23 = id(v29 + 14)
+		v9 = (v38*9 + v9 + 9) % 1000003
+		for k0 := 0; k0 < 3; k0++ {
+			s13 = sid(s13[len(s13)/2:] + "0")
+		}
+	} else {
+	}
+	v11, v32 = v32, v11
+	for k0 := 0; k0 < 3; k0++ {
+		v13 = (v18*17 + v13 + 17) % 1000003
+		v13 = (v34*24 + v13 + 24) % 1000003
+		v8 = id(v26 + 4)
+	}
+	v3 = (v0*10 + v3 + 10) % 1000003
+	v12, v17 = v17, v12
+	s39 = sid(s39[len(s39)/2:] + "3")
+	if v18 > 910921 {
+		v2 = (v31*29 + v2 + 29) % 1000003
+		for k0 := 0; k0 < 3; k0++ {
+			if v28 > 350350 {
+				v36 = id(v1 + 41)
+				v29, v4 = v4, v29
+				v2, v30 = v30, v2
+				v21 = (v4*14 + v21 + 14) % 1000003
+			} else {
+			}
+			v11 = (v13*33 + v11 + 33) % 1000003
+			v18 = (v26*21 + v18 + 21) % 1000003
+		}
+		v22, v25 = v25, v22
+	} else {
+		if v22 > 173571 {
+			v12 = (v34*48 + v12 + 48) % 1000003
+			v9 = id(v25 + 49)
+			if a > 954975 {
+				v12, v10 = v10, v12
+			} else {
+				v9 = id(v25 + 42)
+			}
+		} else {
+			v15 = (v14*28 + v15 + 28) % 1000003
+		}
+		if v26 > 815191 {
+			v10 = (v35*4 + v10 + 4) % 1000003
+			v17, v4 = v4, v17
+			if v20 > 701000 {
+				s20 = sid(s20[len(s20)/2:] + "6")
+				s3 = sid(s3[len(s3)/2:] + "2")
+			} else {
+				v32, a = a, v32
+				v1, v7 = v7, v1
+			}
+		} else {
+		}
+		if v6 > 108146 {
+			if v26 > 61194 {
+				v11, v27 = v27, v11
+				s15 = sid(s15[len(s15)/2:] + "0")
+				v33 = (v32*26 + v33 + 26) % 1000003
+			} else {
+				v36, v32 = v32, v36
+				v9, v8 = v8, v9
+				v22 = (v3*48 + v22 + 48) % 1000003
+			}
+			v29 = (v24*10 + v29 + 10) % 1000003
+		} else {
+			v34 = (v35*14 + v34 + 14) % 1000003
+		}
+	}
+	if v14 > 472452 {
+		v5 = (v25*32 + v5 + 32) % 1000003
+		v32 = (v14*8 + v32 + 8) % 1000003
+	} else {
+		v31 = (v0*16 + v31 + 16) % 1000003
+		v35 = (v24*31 + v35 + 31) % 1000003
+		if v2 > 550008 {
+			if v19 > 176866 {
+				v12 = id(v15 + 21)
+				v14, v22 = v22, v14
+				v21, v33 = v33, v21
+			} else {
+				v0 = (v8*21 + v0 + 21) % 1000003
+				v31, v36 = v36, v31
+				a = id(v22 + 11)
+			}
+			if v32 > 278205 {
+				v36 = id(v1 + 34)
+			} else {
+			}
+			s31 = sid(s31[len(s31)/2:] + "6")
+			if v9 > 720584 {
+				v7, a = a, v7
+				v1 = id(v1 + 9)
+				v30, v5 = v5, v30
+			} else {
+				v38 = (v19*13 + v38 + 13) % 1000003
+				v25, v32 = v32, v25
+				v21 = (v15*4 + v21 + 4) % 1000003
+			}
+		} else {
+			if v17 > 55860 {
+				v26, v7 = v7, v26
+				v24, v15 = v15, v24
+			} else {
+				v33, v6 = v6, v33
+				v34 = id(v17 + 18)
+			}
+			v3 = id(v9 + 35)
+		}
+	}
+	v1 = (v14*47 + v1 + 47) % 1000003
+	v24 = (v36*7 + v24 + 7) % 1000003
+	if v4 > 874698 {
+		v35 = (v21*42 + v35 + 42) % 1000003
+		v10 = (v25*15 + v10 + 15) % 1000003
+	} else {
+		for k0 := 0; k0 < 1; k0++ {
+			if v31 > 41181 {
+				v34, v6 = v6, v34
+				v22 = (v37*40 + v22 + 40) % 1000003
+				v20, v24 = v24, v20
+				v14 = (v1*6 + v14 + 6) % 1000003
+			} else {
+				v29 = (v7*10 + v29 + 10) % 1000003
+				v32, v30 = v30, v32
+			}
+		}
+		for k0 := 0; k0 < 2; k0++ {
+			if v0 > 961954 {
+				v29, v1 = v1, v29
+				s22 = sid(s22[len(s22)/2:] + "2")
+				v27 = (v16*20 + v27 + 20) % 1000003
+			} else {
+				v36 = (v34*22 + v36 + 22) % 1000003
+			}
+		}
+	}
+	if v17 > 126266 {
+		v22 = (v3*16 + v22 + 16) % 1000003
+	} else {
+		if a > 614405 {
+			if a > 809143 {
+				v10, v23 = v23, v10
+				v8, v22 = v22, v8
+				v28 = id(v2 + 21)
+				v36, v31 = v31, v36
+			} else {
+				s14 = sid(s14[len(s14)/2:] + "1")
+				v34 = (v11*18 + v34 + 18) % 1000003
+			}
+			v11 = (v4*25 + v11 + 25) % 1000003
+			v2 = (v2*12 + v2 + 12) % 1000003
+		} else {
+			v31, v4 = v4, v31
+			if v16 > 771700 {
+				v9, v29 = v29, v9
+				v26 = (v12*48 + v26 + 48) % 1000003
+				v21 = (v30*17 + v21 + 17) % 1000003
+			} else {
+				v26, v15 = v15, v26
+				s10 = sid(s10[len(s10)/2:] + "8")
+				v9 = (v34*48 + v9 + 48) % 1000003
+			}
+		}
+	}
+	if v29 > 934159 {
+		if v34 > 457578 {
+			if v11 > 515704 {
+				v20, v31 = v31, v20
+				v10 = (v14*42 + v10 + 42) % 1000003
+				s34 = sid(s34[len(s34)/2:] + "8")
+			} else {
+				v21 = (v30*19 + v21 + 19) % 1000003
+				s10 = sid(s10[len(s10)/2:] + "4")
+			}
+			v27 = (v20*15 + v27 + 15) % 1000003
+			v17 = id(v14 + 1)
+		} else {
+			v21 = (a*25 + v21 + 25) % 1000003
+			v31 = (v37*49 + v31 + 49) % 1000003
+			v3, v24 = v24, v3
+		}
+		for k0 := 0; k0 < 2; k0++ {
+			v12, v14 = v14, v12
+			v21 = id(v5 + 31)
+			v20 = (v18*12 + v20 + 12) % 1000003
+		}
+	} else {
+		for k0 := 0; k0 < 2; k0++ {
+			v15 = (v10*40 + v15 + 40) % 1000003
+			v35 = (v28*39 + v35 + 39) % 1000003
+			v7 = (v15*31 + v7 + 31) % 1000003
+		}
+	}
+	s3 = sid(s3[len(s3)/2:] + "5")
+	v12 = id(v17 + 18)
+	v14 = (v34*13 + v14 + 13) % 1000003
+	v12, v4 = v4, v12
+	v35, v30 = v30, v35
+	v16 = (v5*5 + v16 + 5) % 1000003
+	v28, v9 = v9, v28
+	for k0 := 0; k0 < 2; k0++ {
+		if v29 > 153267 {
+			v12, v6 = v6, v12
+			v3 = (v6*12 + v3 + 12) % 1000003
+			if v13 > 782154 {
+				v10, v38 = v38, v10
+				v13, v27 = v27, v13
+			} else {
+				v19 = (v17*43 + v19 + 43) % 1000003
+			}
+			v14, v31 = v31, v14
+		} else {
+			v23 = (v22*28 + v23 + 28) % 1000003
+			v26, v31 = v31, v26
+			v30 = id(v33 + 5)
+		}
+		v29 = (v17*16 + v29 + 16) % 1000003
+	}
+	s28 = sid(s28[len(s28)/2:] + "1")
+	if v31 > 615229 {
+		if v0 > 406920 {
+			a = (v16*26 + a + 26) % 1000003
+			if v16 > 611483 {
+				v3, v6 = v6, v3
+			} else {
+			}
+			v15 = (v34*37 + v15 + 37) % 1000003
+		} else {
+		}
+		if v23 > 179739 {
+			v6 = id(v28 + 30)
+			v25 = (v0*34 + v25 + 34) % 1000003
+		} else {
+			v5 = id(v6 + 34)
+		}
+	} else {
+		if v11 > 167017 {
+			v22 = (v28*27 + v22 + 27) % 1000003
+			s30 = sid(s30[len(s30)/2:] + "2")
+			v20 = (v38*31 + v20 + 31) % 1000003
+			v4 = (v1*3 + v4 + 3) % 1000003
+		} else {
+			v4 = id(v4 + 34)
+			v5 = (v27*23 + v5 + 23) % 1000003
+			v31 = (v32*22 + v31 + 22) % 1000003
+		}
+	}
+	v17 = (v22*2 + v17 + 2) % 1000003
+	a = (v11*18 + a + 18) % 1000003
+	v7 = (v29*13 + v7 + 13) % 1000003
+	v16 = (v27*39 + v16 + 39) % 1000003
+	v7 = (v14*38 + v7 + 38) % 1000003
+	v25 = (v15*17 + v25 + 17) % 1000003
+	v21 = id(v7 + 48)
+	s24 = sid(s24[len(s24)/2:] + "1")
+	v1 = (v26*28 + v1 + 28) % 1000003
+	v21, v26 = v26, v21
+	v25 = (v36*48 + v25 + 48) % 1000003
+	v15 = (v38*4 + v15 + 4) % 1000003
+	if v25 > 118645 {
+		v12 = (v23*29 + v12 + 29) % 1000003
+		v20 = (v20*13 + v20 + 13) % 1000003
+		if v25 > 488786 {
+			s25 = sid(s25[len(s25)/2:] + "7")
+			v33 = id(v5 + 19)
+			v32 = (v8*33 + v32 + 33) % 1000003
+			v16 = (v16*27 + v16 + 27) % 1000003
+		} else {
+			v6 = (v27*9 + v6 + 9) % 1000003
+			v25 = (v32*14 + v25 + 14) % 1000003
+			v10, v36 = v36, v10
+		}
+	} else {
+		v16 = (v27*17 + v16 + 17) % 1000003
+	}
+	s20 = sid(s20[len(s20)/2:] + "5")
+	for k0 := 0; k0 < 3; k0++ {
+		v19 = id(v30 + 2)
+		v10 = id(v13 + 29)
+	}
+	v37 = (v35*50 + v37 + 50) % 1000003
+	v14 = (v37*5 + v14 + 5) % 1000003
+	v0 = (v35*38 + v0 + 38) % 1000003
+	for k0 := 0; k0 < 2; k0++ {
+		if v20 > 132872 {
+			v16 = (a*38 + v16 + 38) % 1000003
+			if v17 > 392983 {
+				v34, v12 = v12, v34
+				s2 = sid(s2[len(s2)/2:] + "3")
+			} else {
+				v16 = (v36*11 + v16 + 11) % 1000003
+				v30, v29 = v29, v30
+				v38 = (v31*25 + v38 + 25) % 1000003
+			}
+			v18, a = a, v18
+		} else {
+			if v20 > 124455 {
+				v31, v5 = v5, v31
+				v25, v30 = v30, v25
+				v6 = (v10*40 + v6 + 40) % 1000003
+			} else {
+			}
+			s6 = sid(s6[len(s6)/2:] + "3")
+		}
+	}
+	v0 = (v18*47 + v0 + 47) % 1000003
+	v25 = id(v29 + 49)
+	v28 = (v8*36 + v28 + 36) % 1000003
+	v16, v4 = v4, v16
+	if v18 > 703270 {
+		v7 = id(v2 + 14)
+		v7 = (v26*33 + v7 + 33) % 1000003
+	} else {
+	}
+	v5 = id(v28 + 13)
+	v16 = (v31*18 + v16 + 18) % 1000003
+	v15 = (v32*9 + v15 + 9) % 1000003
+	v30 = id(v26 + 45)
+	v29 = (v15*27 + v29 + 27) % 1000003
+	v29 = (v28*36 + v29 + 36) % 1000003
+	v34 = (v25*34 + v34 + 34) % 1000003
+	if v34 > 815692 {
+		v4 = (v22*4 + v4 + 4) % 1000003
+		v29 = (v8*45 + v29 + 45) % 1000003
+		v36, v6 = v6, v36
+		v20, v9 = v9, v20
+	} else {
+		v4 = id(v36 + 20)
+	}
+	if v21 > 292010 {
+		if v10 > 861403 {
+			if v12 > 373809 {
+				v32, v37 = v37, v32
+				s18 = sid(s18[len(s18)/2:] + "8")
+			} else {
+			}
+			if v32 > 792226 {
+				v22, v11 = v11, v22
+				v26, v32 = v32, v26
+			} else {
+				v24, v5 = v5, v24
+				v11 = (v7*42 + v11 + 42) % 1000003
+			}
+			v20 = id(v7 + 15)
+		} else {
+			if v2 > 767179 {
+				v21 = (v34*24 + v21 + 24) % 1000003
+				v19, v32 = v32, v19
+				v20 = (v6*19 + v20 + 19) % 1000003
+			} else {
+				v16, v26 = v26, v16
+				v16, v20 = v20, v16
+			}
+			if v28 > 767444 {
+				s37 = sid(s37[len(s37)/2:] + "3")
+				v23, v21 = v21, v23
+				v1, v12 = v12, v1
+				v24, v17 = v17, v24
+			} else {
+			}
+			v38 = id(v1 + 39)
+		}
+	} else {
+	}
+	if v3 > 908872 {
+		v4 = (v30*6 + v4 + 6) % 1000003
+		v24 = (v15*31 + v24 + 31) % 1000003
+	} else {
+		if v23 > 176105 {
+			v34 = id(v0 + 24)
+			v21 = (v32*6 + v21 + 6) % 1000003
+		} else {
+			v35, v0 = v0, v35
+			v23 = (v15*25 + v23 + 25) % 1000003
+		}
+	}
+	v33 = (v8*24 + v33 + 24) % 1000003
+	for k0 := 0; k0 < 1; k0++ {
+		s36 = sid(s36[len(s36)/2:] + "8")
+	}
+	v20, v5 = v5, v20
+	v29 = (v1*34 + v29 + 34) % 1000003
+	v8 = id(v18 + 35)
+	for k0 := 0; k0 < 1; k0++ {
+		for k1 := 0; k1 < 1; k1++ {
+			if v15 > 216064 {
+				v38, v4 = v4, v38
+				v21, v35 = v35, v21
+				v17 = (a*50 + v17 + 50) % 1000003
+			} else {
+				v0 = id(v34 + 35)
+				s12 = sid(s12[len(s12)/2:] + "2")
+				v1, v3 = v3, v1
+			}
+			v10, v23 = v23, v10
+		}
+		for k1 := 0; k1 < 2; k1++ {
+			if v26 > 224776 {
+				s25 = sid(s25[len(s25)/2:] + "1")
+				v6 = (v20*16 + v6 + 16) % 1000003
+				a, v29 = v29, a
+				v20, v8 = v8, v20
+			} else {
+				v9 = (v29*50 + v9 + 50) % 1000003
+				a = (v35*13 + a + 13) % 1000003
+				v10 = (v10*45 + v10 + 45) % 1000003
+			}
+			v10 = (v5*16 + v10 + 16) % 1000003
+			s18 = sid(s18[len(s18)/2:] + "4")
+		}
+	}
+	if v38 > 292979 {
+		v4 = (v16*40 + v4 + 40) % 1000003
+		a = (v3*31 + a + 31) % 1000003
+		s2 = sid(s2[len(s2)/2:] + "9")
+		v34 = id(v23 + 47)
+	} else {
+		v29 = (v15*26 + v29 + 26) % 1000003
+		v31 = (v31*7 + v31 + 7) % 1000003
+		v26 = (v2*23 + v26 + 23) % 1000003
+	}
+	for k0 := 0; k0 < 2; k0++ {
+		s18 = sid(s18[len(s18)/2:] + "1")
+		v14, v34 = v34, v14
+	}
+	if v24 > 523237 {
+		v3 = (v18*23 + v3 + 23) % 1000003
+		s20 = sid(s20[len(s20)/2:] + "7")
+		v32 = id(v21 + 28)
+	} else {
+		if v21 > 683322 {
+			if v2 > 637831 {
+				v38, v14 = v14, v38
+			} else {
+				v19, v34 = v34, v19
+				s20 = sid(s20[len(s20)/2:] + "3")
+			}
+			v22, v6 = v6, v22
+		} else {
+			s5 = sid(s5[len(s5)/2:] + "9")
+			v2, v2 = v2, v2
+			v25, a = a, v25
+		}
+		for k0 := 0; k0 < 2; k0++ {
+			if v11 > 853847 {
+				v0, v20 = v20, v0
+				v35 = (v16*22 + v35 + 22) % 1000003
+			} else {
+				v15 = (v9*41 + v15 + 41) % 1000003
+				v10, v26 = v26, v10
+				v38 = (v37*16 + v38 + 16) % 1000003
+			}
+		}
+		if v19 > 196641 {
+			v38 = (v1*41 + v38 + 41) % 1000003
+		} else {
+		}
+	}
+	if v2 > 612453 {
+		v13 = (v3*47 + v13 + 47) % 1000003
+		v37 = id(v23 + 31)
+	} else {
+	}
+	v4 = id(v22 + 46)
+	v30 = (v18*45 + v30 + 45) % 1000003
+	v9 = (v5*19 + v9 + 19) % 1000003
+	if v10 > 689047 {
+		s36 = sid(s36[len(s36)/2:] + "8")
+		v5 = (v10*17 + v5 + 17) % 1000003
+		if v29 > 796046 {
+			if v29 > 359535 {
+				v28, v20 = v20, v28
+				v37, v18 = v18, v37
+				v37, v12 = v12, v37
+			} else {
+				v2, a = a, v2
+			}
+		} else {
+			if v33 > 988582 {
+				v33 = (v26*3 + v33 + 3) % 1000003
+			} else {
+				v25 = (v13*19 + v25 + 19) % 1000003
+			}
+			if v22 > 728699 {
+				v18 = (v28*50 + v18 + 50) % 1000003
+				v2 = (v28*23 + v2 + 23) % 1000003
+				s3 = sid(s3[len(s3)/2:] + "5")
+			} else {
+				v25 = (v22*48 + v25 + 48) % 1000003
+				v2, v10 = v10, v2
+			}
+		}
+	} else {
+		if a > 81413 {
+			v4, v18 = v18, v4
+			v16 = id(v34 + 31)
+			v17 = id(v26 + 10)
+		} else {
+			v16 = (v4*22 + v16 + 22) % 1000003
+		}
+		v13 = (v16*2 + v13 + 2) % 1000003
+	}
+	if v34 > 45742 {
+		v21 = (v26*32 + v21 + 32) % 1000003
+	} else {
+	}
+	v33 = (v1*25 + v33 + 25) % 1000003
+	v30 = (v32*11 + v30 + 11) % 1000003
+	v10, v20 = v20, v10
+	v18 = (v6*38 + v18 + 38) % 1000003
+	for k0 := 0; k0 < 2; k0++ {
+		for k1 := 0; k1 < 3; k1++ {
+			v37 = id(v28 + 35)
+			v31 = (v15*35 + v31 + 35) % 1000003
+			v2 = id(v7 + 35)
+		}
+	}
+	v1 = id(v27 + 1)
+	v32 = (v37*35 + v32 + 35) % 1000003
+	v34 = (v38*4 + v34 + 4) % 1000003
+	v34 = id(v6 + 41)
+	v30 = (v2*48 + v30 + 48) % 1000003
+	v3, v12 = v12, v3
+	for k0 := 0; k0 < 1; k0++ {
+		v11 = (v27*40 + v11 + 40) % 1000003
+		if v5 > 546813 {
+			if v19 > 650749 {
+				v7, v19 = v19, v7
+				v11, v5 = v5, v11
+			} else {
+				v6 = (v37*47 + v6 + 47) % 1000003
+				a = (a*37 + a + 37) % 1000003
+				v19 = id(v5 + 33)
+			}
+			if v8 > 506351 {
+				v1 = (v9*31 + v1 + 31) % 1000003
+				v34 = (v0*29 + v34 + 29) % 1000003
+				v30, v13 = v13, v30
+			} else {
+				v10, v38 = v38, v10
+				v26 = (v23*1 + v26 + 1) % 1000003
+			}
+			v8 = (v12*14 + v8 + 14) % 1000003
+		} else {
+			v27 = (v23*19 + v27 + 19) % 1000003
+		}
+		v5 = id(v11 + 45)
+	}
+	v14 = (v2*48 + v14 + 48) % 1000003
+	v28, v15 = v15, v28
+	if v12 > 375122 {
+		if v33 > 245430 {
+			v6, v37 = v37, v6
+			v3, v19 = v19, v3
+		} else {
+			v28 = (v4*11 + v28 + 11) % 1000003
+			v25 = (v10*41 + v25 + 41) % 1000003
+			v2 = (v33*13 + v2 + 13) % 1000003
+		}
+	} else {
+	}
+	if v13 > 499029 {
+		if v16 > 297664 {
+			v34 = id(v19 + 11)
+			a = (v0*26 + a + 26) % 1000003
+		} else {
+			v26 = (v25*5 + v26 + 5) % 1000003
+			if v15 > 464250 {
+				v0 = (v16*21 + v0 + 21) % 1000003
+				v26, v25 = v25, v26
+			} else {
+				v11 = id(v37 + 42)
+				s6 = sid(s6[len(s6)/2:] + "3")
+			}
+			v34 = (v18*50 + v34 + 50) % 1000003
+		}
+		v21 = (v27*12 + v21 + 12) % 1000003
+		v26 = (v37*28 + v26 + 28) % 1000003
+		v16 = (v18*9 + v16 + 9) % 1000003
+	} else {
+	}
+	v3 = (v0*28 + v3 + 28) % 1000003
+	if v23 > 759346 {
+		v31 = id(v14 + 17)
+		v33 = id(v30 + 42)
+	} else {
+		v7 = (v30*35 + v7 + 35) % 1000003
+		v6 = (v19*49 + v6 + 49) % 1000003
+	}
+	v10 = (v10*26 + v10 + 26) % 1000003
+	v6 = (v20*36 + v6 + 36) % 1000003
+	v10 = (v21*32 + v10 + 32) % 1000003
+	for k0 := 0; k0 < 2; k0++ {
+		for k1 := 0; k1 < 3; k1++ {
+			v6 = (v31*45 + v6 + 45) % 1000003
+			if v36 > 155335 {
+				a, v23 = v23, a
+			} else {
+				v2 = (v31*44 + v2 + 44) % 1000003
+				v22 = (v19*17 + v22 + 17) % 1000003
+			}
+		}
+	}
+	v36 = (v3*34 + v36 + 34) % 1000003
+	a = (v28*27 + a + 27) % 1000003
+	v33 = (v28*26 + v33 + 26) % 1000003
+	s31 = sid(s31[len(s31)/2:] + "7")
+	v17 = (v0*6 + v17 + 6) % 1000003
+	v28 = (v8*47 + v28 + 47) % 1000003
+	if v27 > 770443 {
+		v10 = (v26*42 + v10 + 42) % 1000003
+		v10 = (v25*4 + v10 + 4) % 1000003
+		v18 = (v33*10 + v18 + 10) % 1000003
+		v26 = (v28*39 + v26 + 39) % 1000003
+	} else {
+		v28, v1 = v1, v28
+		v18 = (v26*44 + v18 + 44) % 1000003
+		v24, v20 = v20, v24
+	}
+	v35 = (v8*8 + v35 + 8) % 1000003
+	v10 = (v4*32 + v10 + 32) % 1000003
+	if v13 > 963593 {
+		v23 = (v35*18 + v23 + 18) % 1000003
+		v5 = (v10*43 + v5 + 43) % 1000003
+		v36 = (v34*39 + v36 + 39) % 1000003
+		s16 = sid(s16[len(s16)/2:] + "1")
+	} else {
+		v18 = (v35*29 + v18 + 29) % 1000003
+		v27, v20 = v20, v27
+	}
+	for k0 := 0; k0 < 1; k0++ {
+		for k1 := 0; k1 < 1; k1++ {
+			v1 = id(v21 + 29)
+		}
+		for k1 := 0; k1 < 1; k1++ {
+			v17 = (v5*43 + v17 + 43) % 1000003
+		}
+		for k1 := 0; k1 < 2; k1++ {
+			v26 = (v23*18 + v26 + 18) % 1000003
+		}
+	}
+	v5 = (v35*34 + v5 + 34) % 1000003
+	v32 = (v26*35 + v32 + 35) % 1000003
+	v13, v29 = v29, v13
+	v0 = (v26*15 + v0 + 15) % 1000003
+	v26 = (v8*36 + v26 + 36) % 1000003
+	v34, v30 = v30, v34
+	v29 = id(v29 + 7)
+	s2 = sid(s2[len(s2)/2:] + "8")
+	v20 = (v6*12 + v20 + 12) % 1000003
+	v10 = (v25*17 + v10 + 17) % 1000003
+	if v27 > 852550 {
+		if v33 > 55451 {
+			v8 = id(v2 + 13)
+			a = (v36*37 + a + 37) % 1000003
+		} else {
+		}
+		v5 = (v0*7 + v5 + 7) % 1000003
+	} else {
+	}
+	a = (v3*16 + a + 16) % 1000003
+	if v33 > 25829 {
+		if v20 > 378835 {
+			v22 = (v23*26 + v22 + 26) % 1000003
+			if a > 329422 {
+				v5, v0 = v0, v5
+				v28 = (v10*26 + v28 + 26) % 1000003
+			} else {
+				v29 = (v21*22 + v29 + 22) % 1000003
+			}
+			v36 = (v34*8 + v36 + 8) % 1000003
+		} else {
+		}
+		v11 = (a*14 + v11 + 14) % 1000003
+		for k0 := 0; k0 < 2; k0++ {
+			v31 = (v10*22 + v31 + 22) % 1000003
+			v16, v36 = v36, v16
+			v35 = id(v11 + 32)
+		}
+		v37, v21 = v21, v37
+	} else {
+		v27 = id(v6 + 10)
+	}
+	v37 = (v1*21 + v37 + 21) % 1000003
+	v37 = (v2*29 + v37 + 29) % 1000003
+	v22, v3 = v3, v22
+	v34 = id(v34 + 47)
+	s10 = sid(s10[len(s10)/2:] + "3")
+	v17 = id(v36 + 1)
+	v36 = (v17*8 + v36 + 8) % 1000003
+	v5 = (v22*3 + v5 + 3) % 1000003
+	v19 = (v32*2 + v19 + 2) % 1000003
+	v34 = (v38*34 + v34 + 34) % 1000003
+	s1 = sid(s1[len(s1)/2:] + "7")
+	if v0 > 429135 {
+		s18 = sid(s18[len(s18)/2:] + "2")
+	} else {
+	}
+	v29 = (v14*25 + v29 + 25) % 1000003
+	if v35 > 609663 {
+		for k0 := 0; k0 < 1; k0++ {
+			v8 = (v22*38 + v8 + 38) % 1000003
+			if v5 > 377024 {
+				v31, v34 = v34, v31
+				v28 = id(v38 + 28)
+				v29, v13 = v13, v29
+			} else {
+				v17, v23 = v23, v17
+			}
+			v0, v37 = v37, v0
+		}
+		v14 = (v16*6 + v14 + 6) % 1000003
+		v6 = (v21*34 + v6 + 34) % 1000003
+		v9 = (v33*1 + v9 + 1) % 1000003
+	} else {
+		v20, v3 = v3, v20
+		v29 = id(v25 + 33)
+	}
+	for k0 := 0; k0 < 2; k0++ {
+		v0 = (v6*39 + v0 + 39) % 1000003
+	}
+	v16, v26 = v26, v16
+	v16 = (v3*31 + v16 + 31) % 1000003
+	v12 = (v29*45 + v12 + 45) % 1000003
+	s7 = sid(s7[len(s7)/2:] + "7")
+	v28 = id(v37 + 34)
+	v0 = id(v30 + 44)
+	if v25 > 92789 {
+		v21, v36 = v36, v21
+		v0, v9 = v9, v0
+	} else {
+		v0 = (v27*11 + v0 + 11) % 1000003
+	}
+	v15, v7 = v7, v15
+	v6, v32 = v32, v6
+	if v3 > 134700 {
+		v17 = (v33*15 + v17 + 15) % 1000003
+		for k0 := 0; k0 < 3; k0++ {
+			v25 = (v26*1 + v25 + 1) % 1000003
+			if v19 > 984717 {
+				v36, v18 = v18, v36
+				v2, v11 = v11, v2
+				v17, v38 = v38, v17
+			} else {
+				v12, v13 = v13, v12
+			}
+		}
+		v35 = (v4*6 + v35 + 6) % 1000003
+	} else {
+		v6 = (v14*28 + v6 + 28) % 1000003
+		v32 = (v9*50 + v32 + 50) % 1000003
+		v6, v13 = v13, v6
+	}
+	v32, v36 = v36, v32
+	a = (v11*40 + a + 40) % 1000003
+	v10, v33 = v33, v10
+	a = id(v15 + 27)
+	if v1 > 388251 {
+		v38 = (v8*13 + v38 + 13) % 1000003
+		if v38 > 403025 {
+			v30 = id(v24 + 42)
+			if v6 > 781059 {
+				v0 = (v33*17 + v0 + 17) % 1000003
+			} else {
+				v17, v9 = v9, v17
+				v19, v3 = v3, v19
+			}
+			if v17 > 482481 {
+				v6, v0 = v0, v6
+				v11, v11 = v11, v11
+				v18 = (v12*4 + v18 + 4) % 1000003
+			} else {
+			}
+		} else {
+			v27 = (v17*3 + v27 + 3) % 1000003
+			if v31 > 315093 {
+				v3, v18 = v18, v3
+				v30, v0 = v0, v30
+			} else {
+				v28 = (v21*36 + v28 + 36) % 1000003
+				v25 = (v30*25 + v25 + 25) % 1000003
+				s12 = sid(s12[len(s12)/2:] + "4")
+			}
+			s39 = sid(s39[len(s39)/2:] + "0")
+		}
+	} else {
+		if v2 > 696462 {
+			v2 = (v25*23 + v2 + 23) % 1000003
+			v8 = (v33*31 + v8 + 31) % 1000003
+		} else {
+		}
+		v18 = (v38*46 + v18 + 46) % 1000003
+	}
+	s2 = sid(s2[len(s2)/2:] + "5")
+	if v28 > 910914 {
+		a, v25 = v25, a
+		v31 = (v21*11 + v31 + 11) % 1000003
+	} else {
+		if v25 > 763413 {
+			if v33 > 986852 {
+				v2 = (v6*28 + v2 + 28) % 1000003
+				v22 = (v35*7 + v22 + 7) % 1000003
+				v31 = (v17*3 + v31 + 3) % 1000003
+				v2, v31 = v31, v2
+			} else {
+			}
+			v16 = (v16*3 + v16 + 3) % 1000003
+		} else {
+		}
+		v11 = id(v35 + 5)
+		v14, v22 = v22, v14
+	}
+	if v9 > 118065 {
+		v37 = (v27*17 + v37 + 17) % 1000003
+		v5 = (v24*32 + v5 + 32) % 1000003
+	} else {
+		v28 = (v8*13 + v28 + 13) % 1000003
+		v11 = id(v6 + 37)
+	}
+	v5 = (v11*42 + v5 + 42) % 1000003
+	v18 = id(v34 + 1)
+	v30, v2 = v2, v30
+	if v14 > 165319 {
+		s1 = sid(s1[len(s1)/2:] + "3")
+		v22 = (v19*45 + v22 + 45) % 1000003
+		v1 = (v4*38 + v1 + 38) % 1000003
+	} else {
+		s37 = sid(s37[len(s37)/2:] + "7")
+		v31, v8 = v8, v31
+	}
+	v7 = (v7*12 + v7 + 12) % 1000003
+	v34 = (v16*48 + v34 + 48) % 1000003
+	v16, v34 = v34, v16
+	s18 = sid(s18[len(s18)/2:] + "9")
+	v2 = (v36*16 + v2 + 16) % 1000003
+	v13 = (v19*20 + v13 + 20) % 1000003
+	v18 = (v35*50 + v18 + 50) % 1000003
+	v29 = (v14*18 + v29 + 18) % 1000003
+	v35 = (v27*21 + v35 + 21) % 1000003
+	if v35 > 833720 {
+		for k0 := 0; k0 < 3; k0++ {
+			if v37 > 685037 {
+				v0 = id(v32 + 23)
+				v9, v17 = v17, v9
+				v23 = (v10*26 + v23 + 26) % 1000003
+				a = (v25*24 + a + 24) % 1000003
+			} else {
+				v29, v18 = v18, v29
+			}
+			v24, v11 = v11, v24
+			v23 = (v30*43 + v23 + 43) % 1000003
+		}
+		v27 = (v32*47 + v27 + 47) % 1000003
+		v11 = (v0*23 + v11 + 23) % 1000003
+	} else {
+		v38 = (v15*10 + v38 + 10) % 1000003
+	}
+	if v25 > 797981 {
+		for k0 := 0; k0 < 3; k0++ {
+			v3, v31 = v31, v3
+			v12, v29 = v29, v12
+			v34 = (v16*3 + v34 + 3) % 1000003
+		}
+		v18, v30 = v30, v18
+		v23 = (v0*20 + v23 + 20) % 1000003
+	} else {
+		v30 = (v14*47 + v30 + 47) % 1000003
+	}
+	v36 = (v29*35 + v36 + 35) % 1000003
+	if v1 > 601363 {
+		if v31 > 926029 {
+			v13, v6 = v6, v13
+		} else {
+			if v31 > 440886 {
+				v25, v12 = v12, v25
+				v30 = id(v29 + 7)
+			} else {
+				s7 = sid(s7[len(s7)/2:] + "6")
+				v16 = (v33*16 + v16 + 16) % 1000003
+			}
+			if v36 > 216831 {
+				v2, v37 = v37, v2
+				v5, v31 = v31, v5
+				v5, v16 = v16, v5
+				v13 = (v29*30 + v13 + 30) % 1000003
+			} else {
+				v26 = (v38*31 + v26 + 31) % 1000003
+				v21 = (v30*30 + v21 + 30) % 1000003
+				v25 = (v8*37 + v25 + 37) % 1000003
+			}
+			v6 = (v27*31 + v6 + 31) % 1000003
+		}
+		if v22 > 366938 {
+			s36 = sid(s36[len(s36)/2:] + "8")
+			if v32 > 930701 {
+				v32 = id(v29 + 43)
+				s28 = sid(s28[len(s28)/2:] + "9")
+			} else {
+			}
+		} else {
+			a = (v28*35 + a + 35) % 1000003
+		}
+	} else {
+	}
+	v20, v16 = v16, v20
+	if v6 > 768024 {
+		v38 = id(v32 + 47)
+		v14 = (v33*4 + v14 + 4) % 1000003
+	} else {
+		v38, v23 = v23, v38
+		v30, v19 = v19, v30
+	}
+	if v4 > 370054 {
+		s8 = sid(s8[len(s8)/2:] + "0")
+		v9 = (v35*27 + v9 + 27) % 1000003
+		v24 = id(v26 + 43)
+		v17 = (v19*38 + v17 + 38) % 1000003
+	} else {
+		v15 = (v15*2 + v15 + 2) % 1000003
+	}
+	v6 = id(v37 + 49)
+	v35 = (v23*36 + v35 + 36) % 1000003
+	v33 = (v13*18 + v33 + 18) % 1000003
+	v10 = (v9*38 + v10 + 38) % 1000003
+	v25, v17 = v17, v25
+	if v13 > 903672 {
+		v32 = (v26*41 + v32 + 41) % 1000003
+		v4 = id(v6 + 3)
+		for k0 := 0; k0 < 2; k0++ {
+			v35 = id(v30 + 48)
+			if v34 > 358967 {
+				s7 = sid(s7[len(s7)/2:] + "5")
+				s7 = sid(s7[len(s7)/2:] + "9")
+				v6 = (v3*4 + v6 + 4) % 1000003
+				v20, v36 = v36, v20
+			} else {
+				v5 = id(v32 + 42)
+			}
+		}
+		if v4 > 702217 {
+			v19 = (v11*50 + v19 + 50) % 1000003
+			v34 = (v16*48 + v34 + 48) % 1000003
+		} else {
+			v22 = (v28*4 + v22 + 4) % 1000003
+		}
+	} else {
+		s7 = sid(s7[len(s7)/2:] + "8")
+		v12 = (v32*18 + v12 + 18) % 1000003
+		v18 = (v3*32 + v18 + 32) % 1000003
+	}
+	v22 = (v6*35 + v22 + 35) % 1000003
+	v37 = (v11*39 + v37 + 39) % 1000003
+	v19 = (v5*43 + v19 + 43) % 1000003
+	v30 = (v15*26 + v30 + 26) % 1000003
+	v25, v23 = v23, v25
+	v28 = id(v25 + 13)
+	v16 = (v35*25 + v16 + 25) % 1000003
+	v3 = (v2*50 + v3 + 50) % 1000003
+	for k0 := 0; k0 < 3; k0++ {
+		if v27 > 521341 {
+			v28 = (v27*5 + v28 + 5) % 1000003
+		} else {
+			v7, v6 = v6, v7
+			v5 = (v7*45 + v5 + 45) % 1000003
+			s28 = sid(s28[len(s28)/2:] + "9")
+		}
+		if v37 > 464685 {
+			v15 = (v24*30 + v15 + 30) % 1000003
+			if v21 > 437393 {
+				v1, v18 = v18, v1
+				v23 = (v20*17 + v23 + 17) % 1000003
+				s17 = sid(s17[len(s17)/2:] + "1")
+				v29 = (v17*23 + v29 + 23) % 1000003
+			} else {
+				v7, v11 = v11, v7
+			}
+		} else {
+			v1 = (v1*8 + v1 + 8) % 1000003
+			v23 = id(v8 + 13)
+		}
+	}
+	s29 = sid(s29[len(s29)/2:] + "2")
+	v22 = (a*33 + v22 + 33) % 1000003
+	v22 = (v6*35 + v22 + 35) % 1000003
+	v4, v10 = v10, v4
+	v7 = id(v4 + 15)
+	for k0 := 0; k0 < 1; k0++ {
+		v6 = (v36*23 + v6 + 23) % 1000003
+		v18, v36 = v36, v18
+	}
+	if v3 > 637125 {
+		if v10 > 192464 {
+			if v28 > 232735 {
+				v37 = (v23*28 + v37 + 28) % 1000003
+				v14 = id(v7 + 46)
+				v8 = (v1*6 + v8 + 6) % 1000003
+			} else {
+				v38 = (v33*39 + v38 + 39) % 1000003
+				v30, v18 = v18, v30
+			}
+		} else {
+		}
+		s7 = sid(s7[len(s7)/2:] + "1")
+		if v0 > 935950 {
+			v38 = (v20*16 + v38 + 16) % 1000003
+			v0 = (v30*21 + v0 + 21) % 1000003
+			v24 = (v30*4 + v24 + 4) % 1000003
+		} else {
+			v8 = (v33*19 + v8 + 19) % 1000003
+			v8 = (v6*14 + v8 + 14) % 1000003
+			s34 = sid(s34[len(s34)/2:] + "3")
+		}
+	} else {
+		v23 = id(v7 + 28)
+		if v24 > 242723 {
+			v21, v9 = v9, v21
+		} else {
+			v27 = (v9*16 + v27 + 16) % 1000003
+		}
+		v24 = id(v34 + 35)
+	}
+	v27 = (v26*9 + v27 + 9) % 1000003
+	v3 = (v27*16 + v3 + 16) % 1000003
+	v3 = (v1*2 + v3 + 2) % 1000003
+	for k0 := 0; k0 < 1; k0++ {
+		if v38 > 2222 {
+			if v36 > 374427 {
+				v26 = (v26*11 + v26 + 11) % 1000003
+				v31, v9 = v9, v31
+				v28 = (v0*23 + v28 + 23) % 1000003
+			} else {
+				v35 = id(v10 + 28)
+				v27 = (v26*16 + v27 + 16) % 1000003
+			}
+			v5 = (v34*40 + v5 + 40) % 1000003
+			v34 = (v4*19 + v34 + 19) % 1000003
+		} else {
+			v35 = id(v12 + 30)
+			v3 = (v28*39 + v3 + 39) % 1000003
+			v30, v23 = v23, v30
+		}
+	}
+	v31, v29 = v29, v31
+	v38, v23 = v23, v38
+	v6 = (v19*40 + v6 + 40) % 1000003
+	if v29 > 73228 {
+		s27 = sid(s27[len(s27)/2:] + "5")
+		v20 = (v2*31 + v20 + 31) % 1000003
+	} else {
+	}
+	v23 = (v12*20 + v23 + 20) % 1000003
+	v28 = (v20*32 + v28 + 32) % 1000003
+	s4 = sid(s4[len(s4)/2:] + "3")
+	v4, v6 = v6, v4
+	v15 = (v12*18 + v15 + 18) % 1000003
+	if v2 > 818787 {
+		v15 = (a*12 + v15 + 12) % 1000003
+		for k0 := 0; k0 < 2; k0++ {
+			v17 = (v6*16 + v17 + 16) % 1000003
+			v36, v10 = v10, v36
+			if v0 > 149561 {
+				v0 = (v10*16 + v0 + 16) % 1000003
+				v23 = (v8*10 + v23 + 10) % 1000003
+			} else {
+				s20 = sid(s20[len(s20)/2:] + "1")
+				v28, v2 = v2, v28
+				v24 = (v26*43 + v24 + 43) % 1000003
+			}
+		}
+		s39 = sid(s39[len(s39)/2:] + "4")
+		v15 = id(v24 + 42)
+	} else {
+		v16 = (v2*9 + v16 + 9) % 1000003
+		s15 = sid(s15[len(s15)/2:] + "0")
+	}
+	v37 = (v21*34 + v37 + 34) % 1000003
+	if v0 > 448862 {
+		if v24 > 119308 {
+			s32 = sid(s32[len(s32)/2:] + "5")
+		} else {
+			v6 = id(v20 + 43)
+			s10 = sid(s10[len(s10)/2:] + "8")
+			v15 = (v9*26 + v15 + 26) % 1000003
+		}
+		v4, v17 = v17, v4
+	} else {
+		if v0 > 502345 {
+			if v26 > 16306 {
+				v26 = (v19*10 + v26 + 10) % 1000003
+				v4, v37 = v37, v4
+			} else {
+				v32 = (v16*1 + v32 + 1) % 1000003
+				v25 = (v22*39 + v25 + 39) % 1000003
+				v16 = (v11*40 + v16 + 40) % 1000003
+			}
+			v8 = (v28*32 + v8 + 32) % 1000003
+			v4 = (v2*27 + v4 + 27) % 1000003
+			v14 = (v24*41 + v14 + 41) % 1000003
+		} else {
+			if v26 > 543725 {
+				v22, v14 = v14, v22
+			} else {
+				v19 = id(v32 + 3)
+				v33 = (v6*25 + v33 + 25) % 1000003
+				v36, v2 = v2, v36
+			}
+			v35 = (v0*44 + v35 + 44) % 1000003
+			v36 = (v17*23 + v36 + 23) % 1000003
+		}
+	}
+	v10 = id(v35 + 49)
+	v10, v18 = v18, v10
+	v18 = (v19*26 + v18 + 26) % 1000003
+	v14 = id(v16 + 19)
+	v37 = (v4*22 + v37 + 22) % 1000003
+	if v24 > 871905 {
+		v18, v16 = v16, v18
+		v7 = (v1*42 + v7 + 42) % 1000003
+		if v0 > 218439 {
+			v10 = (v24*37 + v10 + 37) % 1000003
+		} else {
+		}
+		v4 = (v4*1 + v4 + 1) % 1000003
+	} else {
+		s39 = sid(s39[len(s39)/2:] + "0")
+		v29 = (v26*12 + v29 + 12) % 1000003
+		s26 = sid(s26[len(s26)/2:] + "4")
+	}
+	s35 = sid(s35[len(s35)/2:] + "7")
+	v38 = (v27*40 + v38 + 40) % 1000003
+	if v32 > 633271 {
+		v27 = (v29*2 + v27 + 2) % 1000003
+		v26 = (v30*6 + v26 + 6) % 1000003
+		v18 = id(v32 + 23)
+		v32 = (v6*7 + v32 + 7) % 1000003
+	} else {
+		v10 = (v3*15 + v10 + 15) % 1000003
+		v14 = id(v36 + 14)
+		v8, v34 = v34, v8
+	}
+	v14 = (v9*11 + v14 + 11) % 1000003
+	v12 = (v14*19 + v12 + 19) % 1000003
+	v5 = (v33*50 + v5 + 50) % 1000003
+	if v37 > 108174 {
+		v16 = (v4*22 + v16 + 22) % 1000003
+		v24 = (v31*34 + v24 + 34) % 1000003
+	} else {
+	}
+	v29 = (v28*15 + v29 + 15) % 1000003
+	v25 = (v35*25 + v25 + 25) % 1000003
+	v11 = (v25*43 + v11 + 43) % 1000003
+	v28 = (v3*24 + v28 + 24) % 1000003
+	v8 = (v0*23 + v8 + 23) % 1000003
+	if v19 > 768111 {
+		s16 = sid(s16[len(s16)/2:] + "2")
+		v6 = id(v22 + 13)
+		v21 = id(v37 + 39)
+		v29 = (v13*2 + v29 + 2) % 1000003
+	} else {
+		for k0 := 0; k0 < 2; k0++ {
+			if v26 > 842548 {
+				v16, v8 = v8, v16
+				v31, v4 = v4, v31
+				v12, v2 = v2, v12
+				v33 = id(v33 + 11)
+			} else {
+				v10, v36 = v36, v10
+			}
+		}
+		v26, v31 = v31, v26
+	}
+	v28, v5 = v5, v28
+	v18 = (v9*17 + v18 + 17) % 1000003
+	v31 = (v16*34 + v31 + 34) % 1000003
+	v22 = (v29*47 + v22 + 47) % 1000003
+	v28 = (v34*23 + v28 + 23) % 1000003
+	v21 = (v33*7 + v21 + 7) % 1000003
+	if v31 > 49999 {
+		v2 = (v11*29 + v2 + 29) % 1000003
+		v9 = (v38*43 + v9 + 43) % 1000003
+	} else {
+		v38 = (v0*42 + v38 + 42) % 1000003
+		v22 = (v4*16 + v22 + 16) % 1000003
+		v16 = (v8*1 + v16 + 1) % 1000003
+	}
+	if v5 > 194761 {
+		if v12 > 328109 {
+			v22, v37 = v37, v22
+			s14 = sid(s14[len(s14)/2:] + "2")
+			v20, v5 = v5, v20
+			v5 = id(v0 + 25)
+		} else {
+			v5, v10 = v10, v5
+			v22 = (v8*19 + v22 + 19) % 1000003
+			if v38 > 243927 {
+				a, v20 = v20, a
+			} else {
+				v21 = (v20*24 + v21 + 24) % 1000003
+			}
+		}
+		v22 = (v19*26 + v22 + 26) % 1000003
+	} else {
+		if v9 > 563796 {
+			v27 = (v19*8 + v27 + 8) % 1000003
+			v24 = (a*21 + v24 + 21) % 1000003
+			v17, v22 = v22, v17
+			s27 = sid(s27[len(s27)/2:] + "3")
+		} else {
+		}
+	}
+	s30 = sid(s30[len(s30)/2:] + "5")
+	if v5 > 885300 {
+		v8 = id(v32 + 13)
+		if v14 > 174203 {
+			if v6 > 498422 {
+				s22 = sid(s22[len(s22)/2:] + "0")
+				v24, v20 = v20, v24
+				v20, v32 = v32, v20
+				v0, v13 = v13, v0
+			} else {
+				v36 = (v10*43 + v36 + 43) % 1000003
+				v2, v7 = v7, v2
+			}
+			v18 = (v24*9 + v18 + 9) % 1000003
+			v27, v30 = v30, v27
+		} else {
+			v5, v23 = v23, v5
+		}
+	} else {
+		for k0 := 0; k0 < 2; k0++ {
+			v1 = (v25*30 + v1 + 30) % 1000003
+		}
+	}
+	v11 = (v28*50 + v11 + 50) % 1000003
+	v12, v30 = v30, v12
+	v36, v17 = v17, v36
+	v12, v7 = v7, v12
+	v14 = (v22*29 + v14 + 29) % 1000003
+	v12 = (v19*18 + v12 + 18) % 1000003
+	s36 = sid(s36[len(s36)/2:] + "7")
+	v14 = id(v21 + 45)
+	v30 = (v26*30 + v30 + 30) % 1000003
+	s35 = sid(s35[len(s35)/2:] + "1")
+	v28 = (v11*38 + v28 + 38) % 1000003
+	if v37 > 263942 {
+		if v19 > 460430 {
+			v25 = (v36*46 + v25 + 46) % 1000003
+			if v10 > 556108 {
+				v28 = (v0*32 + v28 + 32) % 1000003
+			} else {
+				v15 = (v32*42 + v15 + 42) % 1000003
+			}
+			if a > 639263 {
+				v9, v13 = v13, v9
+				v34 = (v37*13 + v34 + 13) % 1000003
+			} else {
+				v11, v7 = v7, v11
+				v34 = (v15*22 + v34 + 22) % 1000003
+			}
+			v24, v19 = v19, v24
+		} else {
+		}
+		v28 = (v10*11 + v28 + 11) % 1000003
+		for k0 := 0; k0 < 2; k0++ {
+			v28 = (v9*47 + v28 + 47) % 1000003
+			v38, v38 = v38, v38
+		}
+		v24 = (v25*7 + v24 + 7) % 1000003
+	} else {
+		for k0 := 0; k0 < 2; k0++ {
+			v16 = (v23*28 + v16 + 28) % 1000003
+			if v33 > 40801 {
+				v26 = id(v37 + 30)
+				v3, v1 = v1, v3
+				v9, v7 = v7, v9
+			} else {
+				v19 = (a*5 + v19 + 5) % 1000003
+				v0 = (v31*3 + v0 + 3) % 1000003
+				v23 = id(v38 + 49)
+			}
+			v32, v35 = v35, v32
+		}
+	}
+	for k0 := 0; k0 < 1; k0++ {
+		v33 = (v23*12 + v33 + 12) % 1000003
+		v28 = id(v14 + 21)
+	}
+	if v12 > 630345 {
+		v2 = (v0*3 + v2 + 3) % 1000003
+		v34 = (v27*49 + v34 + 49) % 1000003
+		if v37 > 405207 {
+			v26 = (v22*44 + v26 + 44) % 1000003
+			if v11 > 96222 {
+				v5, v8 = v8, v5
+			} else {
+				v21, v7 = v7, v21
+			}
+		} else {
+			v32 = id(v1 + 38)
+			v24 = id(v5 + 36)
+			v38 = (v11*39 + v38 + 39) % 1000003
+		}
+		if v6 > 704468 {
+			v8 = (v7*4 + v8 + 4) % 1000003
+		} else {
+			v25 = (v12*6 + v25 + 6) % 1000003
+			v36 = id(v33 + 13)
+			v22 = (v27*32 + v22 + 32) % 1000003
+		}
+	} else {
+		v24 = (v34*26 + v24 + 26) % 1000003
+		v5, v4 = v4, v5
+	}
+	if v30 > 57394 {
+		v20, v16 = v16, v20
+	} else {
+		v25 = (v25*10 + v25 + 10) % 1000003
+		if v31 > 274547 {
+			v15 = (v26*46 + v15 + 46) % 1000003
+			v13, v11 = v11, v13
+		} else {
+			s37 = sid(s37[len(s37)/2:] + "8")
+			if a > 619082 {
+				v16 = (v24*5 + v16 + 5) % 1000003
+			} else {
+				v30 = (v4*4 + v30 + 4) % 1000003
+				v37 = (v23*44 + v37 + 44) % 1000003
+				v23, v37 = v37, v23
+			}
+		}
+	}
+	v30, v34 = v34, v30
+	v36 = (v14*7 + v36 + 7) % 1000003
+	for k0 := 0; k0 < 2; k0++ {
+		v27 = (v29*11 + v27 + 11) % 1000003
+	}
+	v27, v3 = v3, v27
+	v0 = (v14*21 + v0 + 21) % 1000003
+	a = id(v23 + 17)
+	v12 = (v15*6 + v12 + 6) % 1000003
+	v34 = (v16*2 + v34 + 2) % 1000003
+	if a > 932999 {
+		for k0 := 0; k0 < 1; k0++ {
+			s25 = sid(s25[len(s25)/2:] + "7")
+			if v19 > 903892 {
+				v27, v15 = v15, v27
+				v24 = (v8*40 + v24 + 40) % 1000003
+				v20, v25 = v25, v20
+				v32 = (v12*34 + v32 + 34) % 1000003
+			} else {
+				v21, v33 = v33, v21
+				v10 = (v12*21 + v10 + 21) % 1000003
+			}
+			v35 = (v6*24 + v35 + 24) % 1000003
+		}
+		s5 = sid(s5[len(s5)/2:] + "9")
+		v37 = (v20*38 + v37 + 38) % 1000003
+		v38 = (v37*13 + v38 + 13) % 1000003
+	} else {
+		v13 = (a*44 + v13 + 44) % 1000003
+		v8 = (v12*35 + v8 + 35) % 1000003
+	}
+	for k0 := 0; k0 < 3; k0++ {
+		v30 = (v5*10 + v30 + 10) % 1000003
+		if v34 > 309232 {
+			v25 = (v9*14 + v25 + 14) % 1000003
+			v34 = (v7*1 + v34 + 1) % 1000003
+			v38 = id(v1 + 30)
+		} else {
+			if v24 > 436518 {
+				v5, v19 = v19, v5
+				s27 = sid(s27[len(s27)/2:] + "4")
+			} else {
+				v16, v2 = v2, v16
+				v1, v34 = v34, v1
+				v21 = (v27*35 + v21 + 35) % 1000003
+			}
+			v4, v31 = v31, v4
+			if v19 > 784635 {
+				v26 = (v7*7 + v26 + 7) % 1000003
+			} else {
+				v21, v4 = v4, v21
+			}
+		}
+	}
+	v13 = (v5*5 + v13 + 5) % 1000003
+	for k0 := 0; k0 < 2; k0++ {
+		if v18 > 681115 {
+			s6 = sid(s6[len(s6)/2:] + "4")
+			v4 = id(v18 + 18)
+			if v26 > 963644 {
+				v21 = (v24*22 + v21 + 22) % 1000003
+				v13 = (v18*5 + v13 + 5) % 1000003
+			} else {
+			}
+		} else {
+			v16 = (v3*32 + v16 + 32) % 1000003
+		}
+		v13 = (v9*18 + v13 + 18) % 1000003
+	}
+	if v16 > 156072 {
+		for k0 := 0; k0 < 2; k0++ {
+			v10 = id(a + 30)
+			s21 = sid(s21[len(s21)/2:] + "6")
+			s25 = sid(s25[len(s25)/2:] + "4")
+		}
+	} else {
+		v8 = (v10*49 + v8 + 49) % 1000003
+	}
+	s3 = sid(s3[len(s3)/2:] + "7")
+	if v13 > 696163 {
+		v0 = (v7*47 + v0 + 47) % 1000003
+	} else {
+		s24 = sid(s24[len(s24)/2:] + "2")
+		if v8 > 607897 {
+			v9 = id(v19 + 49)
+			if v28 > 477761 {
+				v1 = (v2*2 + v1 + 2) % 1000003
+				v9 = id(v36 + 15)
+			} else {
+			}
+			if v26 > 272200 {
+				v30 = (v0*23 + v30 + 23) % 1000003
+				v26, v5 = v5, v26
+			} else {
+				v15, v11 = v11, v15
+			}
+		} else {
+			v15 = (v34*28 + v15 + 28) % 1000003
+			v14, v33 = v33, v14
+		}
+		v34, v17 = v17, v34
+	}
+	v7 = (v1*30 + v7 + 30) % 1000003
+	v2, v26 = v26, v2
+	if v34 > 518128 {
+		v6, v13 = v13, v6
+		for k0 := 0; k0 < 1; k0++ {
+			v29 = (v35*4 + v29 + 4) % 1000003
+			if v13 > 194573 {
+				s5 = sid(s5[len(s5)/2:] + "9")
+				v28, v4 = v4, v28
+			} else {
+				v18, v6 = v6, v18
+				v9 = (v1*8 + v9 + 8) % 1000003
+				v16 = id(v22 + 13)
+			}
+			v2, v37 = v37, v2
+		}
+		if v32 > 155299 {
+			v28 = (v38*2 + v28 + 2) % 1000003
+			v10 = (v37*37 + v10 + 37) % 1000003
+		} else {
+			s20 = sid(s20[len(s20)/2:] + "4")
+		}
+		v29 = id(v20 + 44)
+	} else {
+		v2 = (v18*34 + v2 + 34) % 1000003
+		s21 = sid(s21[len(s21)/2:] + "4")
+	}
+	if v3 > 185372 {
+		v15, v19 = v19, v15
+		v27 = (v21*45 + v27 + 45) % 1000003
+		s1 = sid(s1[len(s1)/2:] + "8")
+	} else {
+		v15 = (v30*19 + v15 + 19) % 1000003
+		v29, v5 = v5, v29
+	}
+	v29 = id(v4 + 41)
+	s10 = sid(s10[len(s10)/2:] + "0")
+	if v3 > 281702 {
+		v36, v20 = v20, v36
+	} else {
+	}
+	v8, v25 = v25, v8
+	v30, v22 = v22, v30
+	v29 = (a*32 + v29 + 32) % 1000003
+	for k0 := 0; k0 < 2; k0++ {
+		v7 = (v25*48 + v7 + 48) % 1000003
+	}
+	if v15 > 53467 {
+		v20, v0 = v0, v20
+		v4 = (v18*5 + v4 + 5) % 1000003
+	} else {
+	}
+	v5 = (v10*9 + v5 + 9) % 1000003
+	v38 = (v30*42 + v38 + 42) % 1000003
+	v0 = (v6*48 + v0 + 48) % 1000003
+	if v15 > 124133 {
+		v33 = (v14*48 + v33 + 48) % 1000003
+		for k0 := 0; k0 < 2; k0++ {
+			v27, v19 = v19, v27
+			v23 = id(v16 + 22)
+		}
+	} else {
+		v33 = (v30*27 + v33 + 27) % 1000003
+		if v35 > 641842 {
+			if v1 > 236448 {
+				v13 = (v38*31 + v13 + 31) % 1000003
+			} else {
+			}
+			v11, v10 = v10, v11
+			v33, v27 = v27, v33
+		} else {
+			if v38 > 567854 {
+				v16, v21 = v21, v16
+				s25 = sid(s25[len(s25)/2:] + "3")
+				s14 = sid(s14[len(s14)/2:] + "9")
+			} else {
+				v16, v8 = v8, v16
+			}
+		}
+		s14 = sid(s14[len(s14)/2:] + "9")
+	}
+	if v3 > 656577 {
+		v27 = id(v31 + 31)
+		s27 = sid(s27[len(s27)/2:] + "9")
+	} else {
+		v35 = id(v35 + 46)
+		v36 = (v3*4 + v36 + 4) % 1000003
+		if v11 > 703648 {
+			v13, v29 = v29, v13
+			v21 = (v30*44 + v21 + 44) % 1000003
+		} else {
+			v15 = (v29*21 + v15 + 21) % 1000003
+			v24, v20 = v20, v24
+		}
+	}
+	for k0 := 0; k0 < 2; k0++ {
+		s27 = sid(s27[len(s27)/2:] + "5")
+		for k1 := 0; k1 < 3; k1++ {
+			if v24 > 216123 {
+				v33, v28 = v28, v33
+				v27 = id(v0 + 37)
+				v30 = id(v1 + 44)
+			} else {
+				v36 = id(a + 25)
+				v36 = (v20*26 + v36 + 26) % 1000003
+				v38, v26 = v26, v38
+			}
+			v0 = (v7*35 + v0 + 35) % 1000003
+			v20, v17 = v17, v20
+		}
+		v36, v15 = v15, v36
+	}
+	v32 = (v10*15 + v32 + 15) % 1000003
+	v10 = (v34*39 + v10 + 39) % 1000003
+	v3 = id(v29 + 6)
+	for k0 := 0; k0 < 3; k0++ {
+		if v18 > 146737 {
+			v11 = (v36*47 + v11 + 47) % 1000003
+			v22 = (v15*46 + v22 + 46) % 1000003
+			if v25 > 581963 {
+				v38 = (v27*20 + v38 + 20) % 1000003
+				v18 = (v35*40 + v18 + 40) % 1000003
+				v24 = (v12*5 + v24 + 5) % 1000003
+			} else {
+				v14 = (v37*13 + v14 + 13) % 1000003
+			}
+			v18 = (v25*49 + v18 + 49) % 1000003
+		} else {
+		}
+	}
+	v13 = (v6*36 + v13 + 36) % 1000003
+	v11 = (v6*37 + v11 + 37) % 1000003
+	if v22 > 225403 {
+		v7 = (v25*43 + v7 + 43) % 1000003
+		s0 = sid(s0[len(s0)/2:] + "1")
+		s5 = sid(s5[len(s5)/2:] + "7")
+		v1 = (v10*11 + v1 + 11) % 1000003
+	} else {
+		v19 = (v4*36 + v19 + 36) % 1000003
+		if v19 > 766652 {
+			v15, v30 = v30, v15
+			v12 = (v36*42 + v12 + 42) % 1000003
+			v20, v5 = v5, v20
+		} else {
+		}
+		a = (v32*22 + a + 22) % 1000003
+	}
+	v38 = (v20*44 + v38 + 44) % 1000003
+	if v16 > 563810 {
+		v10 = (v6*37 + v10 + 37) % 1000003
+		v17 = (v25*21 + v17 + 21) % 1000003
+	} else {
+	}
+	if v11 > 290211 {
+		if v10 > 381591 {
+			v9 = (v6*29 + v9 + 29) % 1000003
+			v23 = (v22*17 + v23 + 17) % 1000003
+		} else {
+			if v9 > 983048 {
+				v18, v23 = v23, v18
+			} else {
+			}
+			v33, v2 = v2, v33
+		}
+		v2 = (v34*26 + v2 + 26) % 1000003
+		v33 = id(v18 + 47)
+	} else {
+	}
+	v25 = (v32*30 + v25 + 30) % 1000003
+	v29, v23 = v23, v29
+	for k0 := 0; k0 < 2; k0++ {
+		v2 = (v6*43 + v2 + 43) % 1000003
+		a = (v2*24 + a + 24) % 1000003
+	}
+	v5 = id(v26 + 29)
+	v17 = (v17*14 + v17 + 14) % 1000003
+	for k0 := 0; k0 < 1; k0++ {
+		v14 = (v37*12 + v14 + 12) % 1000003
+		s8 = sid(s8[len(s8)/2:] + "8")
+		v35 = (v32*39 + v35 + 39) % 1000003
+	}
+	v19 = (v22*35 + v19 + 35) % 1000003
+	v21 = (v36*1 + v21 + 1) % 1000003
+	v9 = (v1*37 + v9 + 37) % 1000003
+	v25 = (v16*15 + v25 + 15) % 1000003
+	if v14 > 527896 {
+		v32 = (v7*21 + v32 + 21) % 1000003
+		v17, v36 = v36, v17
+		if v36 > 346211 {
+			v17 = (v9*25 + v17 + 25) % 1000003
+			v37, v19 = v19, v37
+			v7 = (v18*9 + v7 + 9) % 1000003
+			s33 = sid(s33[len(s33)/2:] + "7")
+		} else {
+			v30 = id(v22 + 17)
+			v7 = (v23*6 + v7 + 6) % 1000003
+		}
+		v1 = id(v36 + 48)
+	} else {
+		if v8 > 372839 {
+			v37 = (v28*40 + v37 + 40) % 1000003
+			a = (v16*18 + a + 18) % 1000003
+		} else {
+			s1 = sid(s1[len(s1)/2:] + "0")
+			v33, v7 = v7, v33
+			v7 = (v10*44 + v7 + 44) % 1000003
+		}
+		v35 = (v8*26 + v35 + 26) % 1000003
+		s22 = sid(s22[len(s22)/2:] + "3")
+	}
+	v27, v37 = v37, v27
+	v16 = (v4*14 + v16 + 14) % 1000003
+	v8, v22 = v22, v8
+	v37 = (v36*42 + v37 + 42) % 1000003
+	a = (v23*12 + a + 12) % 1000003
+	for k0 := 0; k0 < 1; k0++ {
+		v3 = (v28*27 + v3 + 27) % 1000003
+		if v25 > 238665 {
+			v31, v5 = v5, v31
+			v6, v37 = v37, v6
+		} else {
+			s35 = sid(s35[len(s35)/2:] + "1")
+			v15 = (v8*46 + v15 + 46) % 1000003
+			if v13 > 544591 {
+				s12 = sid(s12[len(s12)/2:] + "9")
+				v30 = id(v10 + 33)
+			} else {
+				v27, v29 = v29, v27
+				v3 = (v14*10 + v3 + 10) % 1000003
+				v35 = (v36*22 + v35 + 22) % 1000003
+			}
+		}
+	}
+	v29 = (v6*49 + v29 + 49) % 1000003
+	v5 = (v19*3 + v5 + 3) % 1000003
+	s0 = sid(s0[len(s0)/2:] + "2")
+	v6 = (v18*43 + v6 + 43) % 1000003
+	v25 = (v3*43 + v25 + 43) % 1000003
+	if v27 > 164721 {
+		if v35 > 700234 {
+			v3 = id(v17 + 14)
+			v35 = (a*21 + v35 + 21) % 1000003
+		} else {
+			v35 = (v29*28 + v35 + 28) % 1000003
+			v4, v3 = v3, v4
+			v20, v1 = v1, v20
+		}
+		v36, v26 = v26, v36
+		v32 = (v25*19 + v32 + 19) % 1000003
+		for k0 := 0; k0 < 3; k0++ {
+			v34 = id(v31 + 36)
+			v15 = (v30*9 + v15 + 9) % 1000003
+		}
+	} else {
+		v10, v12 = v12, v10
+		v12 = (v26*34 + v12 + 34) % 1000003
+		for k0 := 0; k0 < 2; k0++ {
+			v19 = id(v25 + 5)
+		}
+	}
+	v12 = (v17*30 + v12 + 30) % 1000003
+	v14 = (v31*29 + v14 + 29) % 1000003
+	for k0 := 0; k0 < 1; k0++ {
+		v11 = (v35*46 + v11 + 46) % 1000003
+	}
+	for k0 := 0; k0 < 3; k0++ {
+		v20 = (v17*3 + v20 + 3) % 1000003
+		v24 = (v37*18 + v24 + 18) % 1000003
+		v9 = (v38*42 + v9 + 42) % 1000003
+	}
+	v8 = (v30*40 + v8 + 40) % 1000003
+	v13 = (v15*43 + v13 + 43) % 1000003
+	s14 = sid(s14[len(s14)/2:] + "8")
+	v32 = (v13*31 + v32 + 31) % 1000003
+	if v8 > 953371 {
+		s30 = sid(s30[len(s30)/2:] + "8")
+		s38 = sid(s38[len(s38)/2:] + "8")
+	} else {
+	}
+	if v29 > 258312 {
+		for k0 := 0; k0 < 1; k0++ {
+			if v13 > 851374 {
+				v8 = (v36*41 + v8 + 41) % 1000003
+				v3, v7 = v7, v3
+				v10, v38 = v38, v10
+				v4 = (v17*2 + v4 + 2) % 1000003
+			} else {
+				v32, v35 = v35, v32
+				v35, v9 = v9, v35
+				s12 = sid(s12[len(s12)/2:] + "7")
+			}
+			v38 = (v0*22 + v38 + 22) % 1000003
+			v28 = (v10*15 + v28 + 15) % 1000003
+		}
+		v5 = (v14*22 + v5 + 22) % 1000003
+	} else {
+		v33 = (v16*15 + v33 + 15) % 1000003
+	}
+	v7, v3 = v3, v7
+	v19 = id(v11 + 20)
+	v9 = (v10*48 + v9 + 48) % 1000003
+	if v33 > 236868 {
+		for k0 := 0; k0 < 2; k0++ {
+			v19, v11 = v11, v19
+			v28 = (v0*11 + v28 + 11) % 1000003
+		}
+		if v18 > 196457 {
+			v26 = (v11*31 + v26 + 31) % 1000003
+		} else {
+			v15, v1 = v1, v15
+		}
+		v17, v30 = v30, v17
+	} else {
+	}
+	v4 = (v19*3 + v4 + 3) % 1000003
+	if v25 > 921226 {
+		v21 = (v7*28 + v21 + 28) % 1000003
+		if v22 > 796513 {
+			if v9 > 575502 {
+				v32 = (v10*18 + v32 + 18) % 1000003
+				v1, v15 = v15, v1
+			} else {
+				v28, v28 = v28, v28
+				v36 = (v24*11 + v36 + 11) % 1000003
+			}
+		} else {
+			if v6 > 321113 {
+				v7 = (v29*37 + v7 + 37) % 1000003
+				v37, v10 = v10, v37
+			} else {
+			}
+		}
+		v14, v18 = v18, v14
+	} else {
+		v33 = (v33*24 + v33 + 24) % 1000003
+	}
+	v15, v9 = v9, v15
+	v19 = (v31*29 + v19 + 29) % 1000003
+	v0 = (v22*35 + v0 + 35) % 1000003
+	if v8 > 11283 {
+		v2 = (v6*48 + v2 + 48) % 1000003
+	} else {
+		v27 = id(v27 + 46)
+		v15 = id(v35 + 33)
+	}
+	v5, v11 = v11, v5
+	v17 = id(v37 + 23)
+	v34 = id(v30 + 37)
+	v22 = (v5*14 + v22 + 14) % 1000003
+	for k0 := 0; k0 < 3; k0++ {
+		v17, v26 = v26, v17
+	}
+	if v8 > 739908 {
+		if v2 > 225982 {
+			v11 = (v15*45 + v11 + 45) % 1000003
+			v20, v37 = v37, v20
+			if v29 > 771133 {
+				v31, v9 = v9, v31
+			} else {
+			}
+		} else {
+		}
+		if v1 > 937887 {
+			s23 = sid(s23[len(s23)/2:] + "3")
+			v17 = (v33*32 + v17 + 32) % 1000003
+		} else {
+		}
+		if v4 > 657451 {
+			if v2 > 813128 {
+				v4 = (v12*21 + v4 + 21) % 1000003
+				v11 = (v23*21 + v11 + 21) % 1000003
+				s12 = sid(s12[len(s12)/2:] + "1")
+				v22, v11 = v11, v22
+			} else {
+				v35 = id(v14 + 49)
+				v7 = (v19*50 + v7 + 50) % 1000003
+				v5 = (v30*48 + v5 + 48) % 1000003
+			}
+			v31, v19 = v19, v31
+			v32 = (v9*49 + v32 + 49) % 1000003
+		} else {
+			v38, v17 = v17, v38
+			v20 = (v21*17 + v20 + 17) % 1000003
+			v18 = (v20*19 + v18 + 19) % 1000003
+		}
+	} else {
+		for k0 := 0; k0 < 1; k0++ {
+			v38 = (v10*47 + v38 + 47) % 1000003
+			v29 = (v3*6 + v29 + 6) % 1000003
+		}
+		v30 = (v13*5 + v30 + 5) % 1000003
+		v6 = (v14*10 + v6 + 10) % 1000003
+	}
+	if v4 > 214157 {
+		if v6 > 249382 {
+			v10, v4 = v4, v10
+			v12 = (v5*37 + v12 + 37) % 1000003
+			v14 = (v19*18 + v14 + 18) % 1000003
+		} else {
+			v21, v30 = v30, v21
+		}
+	} else {
+		v26 = id(v20 + 12)
+		v10 = (v21*29 + v10 + 29) % 1000003
+	}
+	v3 = (v31*47 + v3 + 47) % 1000003
+	v9 = id(v37 + 7)
+	v15 = (v8*11 + v15 + 11) % 1000003
+	v3 = id(v28 + 38)
+	v24 = (v26*18 + v24 + 18) % 1000003
+	v38 = (v6*16 + v38 + 16) % 1000003
+	s20 = sid(s20[len(s20)/2:] + "6")
+	if v20 > 99031 {
+		s0 = sid(s0[len(s0)/2:] + "4")
+		if v1 > 732474 {
+			if v38 > 348872 {
+				v13 = (v34*40 + v13 + 40) % 1000003
+				v35, v27 = v27, v35
+				v5, v36 = v36, v5
+			} else {
+				v22 = id(v16 + 8)
+				a, v18 = v18, a
+			}
+			v13 = (v31*41 + v13 + 41) % 1000003
+		} else {
+			v32 = (v16*41 + v32 + 41) % 1000003
+			s10 = sid(s10[len(s10)/2:] + "5")
+			v36 = (v9*41 + v36 + 41) % 1000003
+		}
+		for k0 := 0; k0 < 3; k0++ {
+			if v32 > 783826 {
+				v15 = (v33*20 + v15 + 20) % 1000003
+				s17 = sid(s17[len(s17)/2:] + "2")
+				v11 = id(v21 + 28)
+				v25 = (v2*26 + v25 + 26) % 1000003
+			} else {
+				v29 = (v15*41 + v29 + 41) % 1000003
+				s22 = sid(s22[len(s22)/2:] + "6")
+			}
+			v4 = (v20*42 + v4 + 42) % 1000003
+			v21 = (v0*24 + v21 + 24) % 1000003
+		}
+		v0 = (v19*38 + v0 + 38) % 1000003
+	} else {
+		v10 = (v37*5 + v10 + 5) % 1000003
+	}
+	v9 = (v21*42 + v9 + 42) % 1000003
+	if v2 > 338802 {
+		v23 = (v5*9 + v23 + 9) % 1000003
+		v4 = (v4*24 + v4 + 24) % 1000003
+		v15 = (v19*11 + v15 + 11) % 1000003
+		v8 = (v5*46 + v8 + 46) % 1000003
+	} else {
+		v9 = (v10*44 + v9 + 44) % 1000003
+		v8 = (v6*49 + v8 + 49) % 1000003
+	}
+	v3 = (a*5 + v3 + 5) % 1000003
+	if v20 > 189140 {
+		v4 = (v12*30 + v4 + 30) % 1000003
+	} else {
+		v12 = id(v27 + 11)
+	}
+	s37 = sid(s37[len(s37)/2:] + "5")
+	s1 = sid(s1[len(s1)/2:] + "5")
+	for k0 := 0; k0 < 3; k0++ {
+		a, v0 = v0, a
+	}
+	for k0 := 0; k0 < 3; k0++ {
+		v0 = (v2*24 + v0 + 24) % 1000003
+		v14 = (v15*16 + v14 + 16) % 1000003
+		v17 = (v22*4 + v17 + 4) % 1000003
+	}
+	v8 = (v18*46 + v8 + 46) % 1000003
+	v31 = (v0*7 + v31 + 7) % 1000003
+	if v36 > 191299 {
+		s30 = sid(s30[len(s30)/2:] + "9")
+		v20 = id(v13 + 4)
+		v4 = (v23*45 + v4 + 45) % 1000003
+	} else {
+		for k0 := 0; k0 < 2; k0++ {
+			if v38 > 316660 {
+				v12 = (v25*15 + v12 + 15) % 1000003
+				v25 = id(v27 + 39)
+			} else {
+				s35 = sid(s35[len(s35)/2:] + "5")
+				v13, v3 = v3, v13
+				v12 = (v6*42 + v12 + 42) % 1000003
+			}
+			s34 = sid(s34[len(s34)/2:] + "5")
+			if v13 > 640574 {
+				v10 = (v36*12 + v10 + 12) % 1000003
+				v32, v10 = v10, v32
+			} else {
+				v32 = (v14*47 + v32 + 47) % 1000003
+				v34 = (v16*37 + v34 + 37) % 1000003
+			}
+		}
+		s28 = sid(s28[len(s28)/2:] + "6")
+		if v13 > 477264 {
+			v11 = id(v28 + 0)
+			if v36 > 341671 {
+				v19 = (v24*37 + v19 + 37) % 1000003
+			} else {
+			}
+			v0, v6 = v6, v0
+		} else {
+		}
+	}
+	s26 = sid(s26[len(s26)/2:] + "6")
+	v24 = (v28*6 + v24 + 6) % 1000003
+	for k0 := 0; k0 < 1; k0++ {
+		v35 = (v27*47 + v35 + 47) % 1000003
+		if v1 > 697400 {
+			v38 = (v17*14 + v38 + 14) % 1000003
+			v0 = id(v32 + 14)
+			s18 = sid(s18[len(s18)/2:] + "9")
+			s28 = sid(s28[len(s28)/2:] + "6")
+		} else {
+			v5 = (v13*6 + v5 + 6) % 1000003
+			v22 = (v26*23 + v22 + 23) % 1000003
+		}
+		v3 = (v33*27 + v3 + 27) % 1000003
+	}
+	v2 = id(v22 + 19)
+	if v26 > 883130 {
+		if v12 > 961997 {
+			v29 = (v26*1 + v29 + 1) % 1000003
+			s16 = sid(s16[len(s16)/2:] + "1")
+		} else {
+			v33, v2 = v2, v33
+			v9, v31 = v31, v9
+			s30 = sid(s30[len(s30)/2:] + "3")
+		}
+	} else {
+		v32 = (v0*42 + v32 + 42) % 1000003
+		for k0 := 0; k0 < 1; k0++ {
+			v3 = (v11*30 + v3 + 30) % 1000003
+			if v27 > 270894 {
+				v9, v9 = v9, v9
+				v26, v7 = v7, v26
+				v9, v3 = v3, v9
+				v5, v5 = v5, v5
+			} else {
+				v35, v8 = v8, v35
+			}
+		}
+		a = (v25*34 + a + 34) % 1000003
+	}
+	v13 = (v30*49 + v13 + 49) % 1000003
+	v20 = id(v9 + 31)
+	if v15 > 180884 {
+		if v12 > 720585 {
+			v7, v38 = v38, v7
+			if v22 > 166047 {
+				v36 = id(v6 + 1)
+				v2, v1 = v1, v2
+				v6 = (v4*17 + v6 + 17) % 1000003
+			} else {
+				v22, v0 = v0, v22
+				v6, v22 = v22, v6
+				v7, v23 = v23, v7
+			}
+			v30 = (v16*13 + v30 + 13) % 1000003
+		} else {
+			if v31 > 251583 {
+				v25 = (v13*47 + v25 + 47) % 1000003
+			} else {
+			}
+		}
+		a = (v20*45 + a + 45) % 1000003
+		v9 = (v7*32 + v9 + 32) % 1000003
+		v35 = (v33*48 + v35 + 48) % 1000003
+	} else {
+		v7 = (v28*30 + v7 + 30) % 1000003
+	}
+	v8 = (v15*29 + v8 + 29) % 1000003
+	v7 = (v23*18 + v7 + 18) % 1000003
+	v0 = id(v7 + 34)
+	v23 = (v4*36 + v23 + 36) % 1000003
+	v8 = (v21*47 + v8 + 47) % 1000003
+	v20 = id(v25 + 44)
+	v22, v6 = v6, v22
+	v33, v9 = v9, v33
+	v36 = id(v38 + 13)
+	v31 = (v32*1 + v31 + 1) % 1000003
+	v4 = (v32*1 + v4 + 1) % 1000003
+	v5 = id(v16 + 3)
+	v3 = (v26*38 + v3 + 38) % 1000003
+	if v2 > 71296 {
+		v1 = (v2*12 + v1 + 12) % 1000003
+		v9 = (v12*20 + v9 + 20) % 1000003
+		for k0 := 0; k0 < 3; k0++ {
+			v36 = (v9*49 + v36 + 49) % 1000003
+			if v29 > 689408 {
+				v22 = (v18*23 + v22 + 23) % 1000003
+				v25 = (v37*11 + v25 + 11) % 1000003
+			} else {
+			}
+			v30 = id(v7 + 2)
+		}
+		for k0 := 0; k0 < 3; k0++ {
+			v30, v5 = v5, v30
+		}
+	} else {
+		s12 = sid(s12[len(s12)/2:] + "0")
+		s18 = sid(s18[len(s18)/2:] + "2")
+	}
+	v10 = (v4*21 + v10 + 21) % 1000003
+	v13 = (v15*7 + v13 + 7) % 1000003
+	v19 = (v35*19 + v19 + 19) % 1000003
+	v18 = id(v22 + 40)
+	v31 = (v10*13 + v31 + 13) % 1000003
+	s16 = sid(s16[len(s16)/2:] + "1")
+	v13 = (v29*34 + v13 + 34) % 1000003
+	v12 = id(v11 + 35)
+	s9 = sid(s9[len(s9)/2:] + "4")
+	s37 = sid(s37[len(s37)/2:] + "5")
+	v12 = (v33*35 + v12 + 35) % 1000003
+	v0 = (v23*21 + v0 + 21) % 1000003
+	v34, v28 = v28, v34
+	v15 = (v11*26 + v15 + 26) % 1000003
+	v8, v11 = v11, v8
+	v35, v12 = v12, v35
+	if v2 > 296043 {
+		v1 = (v19*14 + v1 + 14) % 1000003
+	} else {
+		s11 = sid(s11[len(s11)/2:] + "3")
+		if v14 > 668713 {
+			v25 = (v23*1 + v25 + 1) % 1000003
+		} else {
+		}
+	}
+	v37 = (v28*48 + v37 + 48) % 1000003
+	v22 = id(v33 + 2)
+	v14, v1 = v1, v14
+	if v14 > 868676 {
+		v34 = (v5*23 + v34 + 23) % 1000003
+		v9 = (v11*16 + v9 + 16) % 1000003
+		if v1 > 292832 {
+			v22 = id(v29 + 11)
+			v5 = (v23*31 + v5 + 31) % 1000003
+			v6, v38 = v38, v6
+			v3 = (v9*12 + v3 + 12) % 1000003
+		} else {
+			v4 = id(v16 + 11)
+			v5 = (v24*26 + v5 + 26) % 1000003
+			v1 = (v27*41 + v1 + 41) % 1000003
+		}
+	} else {
+		v37 = (v2*12 + v37 + 12) % 1000003
+		v36 = (v21*24 + v36 + 24) % 1000003
+		v31 = (v9*35 + v31 + 35) % 1000003
+	}
+	v8 = (v24*35 + v8 + 35) % 1000003
+	if v27 > 933827 {
+		if v0 > 754246 {
+			if v1 > 628794 {
+				v28 = (v35*34 + v28 + 34) % 1000003
+			} else {
+				v6, v2 = v2, v6
+				v15 = id(v29 + 14)
+				s30 = sid(s30[len(s30)/2:] + "9")
+			}
+			v30 = (v16*40 + v30 + 40) % 1000003
+			v32, v0 = v0, v32
+		} else {
+		}
+		if v11 > 780538 {
+			if v11 > 829581 {
+				v10, v3 = v3, v10
+				v37, v27 = v27, v37
+				v20, v20 = v20, v20
+				v23, v35 = v35, v23
+			} else {
+				v31, v0 = v0, v31
+				s7 = sid(s7[len(s7)/2:] + "9")
+				v2, v21 = v21, v2
+			}
+			v28, v30 = v30, v28
+		} else {
+		}
+		v36 = id(v11 + 5)
+		if v13 > 527573 {
+			if v23 > 140585 {
+				s8 = sid(s8[len(s8)/2:] + "7")
+				v21 = (v8*20 + v21 + 20) % 1000003
+				v33 = (v9*35 + v33 + 35) % 1000003
+				v30 = (v12*10 + v30 + 10) % 1000003
+			} else {
+				v25, v32 = v32, v25
+			}
+		} else {
+		}
+	} else {
+		if v25 > 556730 {
+			v11 = (v23*15 + v11 + 15) % 1000003
+			v37 = (v7*43 + v37 + 43) % 1000003
+			v16, v4 = v4, v16
+		} else {
+		}
+		if v9 > 329408 {
+			v0 = (v16*1 + v0 + 1) % 1000003
+			if v10 > 218536 {
+				v27 = (v22*18 + v27 + 18) % 1000003
+				v7 = (v6*34 + v7 + 34) % 1000003
+				v0 = (v11*8 + v0 + 8) % 1000003
+			} else {
+			}
+			v2, v6 = v6, v2
+			v33, v34 = v34, v33
+		} else {
+		}
+		s24 = sid(s24[len(s24)/2:] + "5")
+	}
+	for k0 := 0; k0 < 2; k0++ {
+		v36 = (v33*9 + v36 + 9) % 1000003
+		if v19 > 306372 {
+			v5 = (v27*24 + v5 + 24) % 1000003
+		} else {
+		}
+	}
+	s22 = sid(s22[len(s22)/2:] + "3")
+	v2 = (v21*50 + v2 + 50) % 1000003
+	v3, a = a, v3
+	v20 = (v4*14 + v20 + 14) % 1000003
+	v16 = (v34*3 + v16 + 3) % 1000003
+	s2 = sid(s2[len(s2)/2:] + "0")
+	v35 = (v0*33 + v35 + 33) % 1000003
+	v38 = (v0*7 + v38 + 7) % 1000003
+	v3, v14 = v14, v3
+	for k0 := 0; k0 < 3; k0++ {
+		s37 = sid(s37[len(s37)/2:] + "2")
+	}
+	s28 = sid(s28[len(s28)/2:] + "1")
+	v18 = (v2*39 + v18 + 39) % 1000003
+	v17 = (v30*3 + v17 + 3) % 1000003
+	v4, v20 = v20, v4
+	v12 = (v34*28 + v12 + 28) % 1000003
+	v14, v19 = v19, v14
+	s20 = sid(s20[len(s20)/2:] + "3")
+	v16 = id(v35 + 2)
+	v38, v26 = v26, v38
+	if v26 > 605018 {
+		v31 = (v9*1 + v31 + 1) % 1000003
+		a = id(v21 + 22)
+		v12 = (v8*13 + v12 + 13) % 1000003
+	} else {
+		v17 = (v36*27 + v17 + 27) % 1000003
+	}
+	v37 = (v13*37 + v37 + 37) % 1000003
+	if v25 > 25980 {
+		if v7 > 313105 {
+			v23 = (v9*40 + v23 + 40) % 1000003
+		} else {
+		}
+		v18 = (v5*32 + v18 + 32) % 1000003
+		v35 = (v3*8 + v35 + 8) % 1000003
+	} else {
+		for k0 := 0; k0 < 3; k0++ {
+			s6 = sid(s6[len(s6)/2:] + "8")
+		}
+		v38 = (v6*31 + v38 + 31) % 1000003
+	}
+	v10 = (v30*2 + v10 + 2) % 1000003
+	v19 = id(v20 + 22)
+	v22 = (v7*7 + v22 + 7) % 1000003
+	s36 = sid(s36[len(s36)/2:] + "6")
+	v37 = id(v9 + 37)
+	v27 = (v5*6 + v27 + 6) % 1000003
+	v37 = (v18*42 + v37 + 42) % 1000003
+	for k0 := 0; k0 < 1; k0++ {
+		s4 = sid(s4[len(s4)/2:] + "5")
+	}
+	v21 = (v4*50 + v21 + 50) % 1000003
+	v15 = id(v12 + 34)
+	v38 = (v21*46 + v38 + 46) % 1000003
+	if v10 > 598911 {
+		s2 = sid(s2[len(s2)/2:] + "9")
+		v17 = (v12*13 + v17 + 13) % 1000003
+		v30 = (v17*21 + v30 + 21) % 1000003
+	} else {
+		s37 = sid(s37[len(s37)/2:] + "7")
+		v25, v34 = v34, v25
+	}
+	v28 = (v28*41 + v28 + 41) % 1000003
+	if v3 > 926426 {
+		v38 = (v20*36 + v38 + 36) % 1000003
+		for k0 := 0; k0 < 2; k0++ {
+			v16, v35 = v35, v16
+		}
+		v31 = (v28*34 + v31 + 34) % 1000003
+	} else {
+		for k0 := 0; k0 < 2; k0++ {
+			a = (v18*44 + a + 44) % 1000003
+			if v38 > 665797 {
+				v18, v2 = v2, v18
+				v23, v3 = v3, v23
+				v13 = id(v38 + 30)
+				v23 = (v19*46 + v23 + 46) % 1000003
+			} else {
+			}
+		}
+		v27 = (v14*41 + v27 + 41) % 1000003
+		v15, v2 = v2, v15
+	}
+	v10 = id(v9 + 12)
+	if v22 > 887532 {
+		if v5 > 863316 {
+			if v8 > 862635 {
+				v16 = (v17*17 + v16 + 17) % 1000003
+				v18 = id(a + 37)
+				v16 = id(v0 + 10)
+				v0, v8 = v8, v0
+			} else {
+				v34, v33 = v33, v34
+				v2, v28 = v28, v2
+				v14, v2 = v2, v14
+			}
+		} else {
+			v14 = (v6*13 + v14 + 13) % 1000003
+			v8 = (v9*1 + v8 + 1) % 1000003
+		}
+		v37 = id(v0 + 27)
+		if v5 > 367956 {
+			v20 = (v13*44 + v20 + 44) % 1000003
+		} else {
+			v11 = id(v26 + 49)
+		}
+		if v15 > 881687 {
+			if v5 > 452106 {
+				v1 = (v13*12 + v1 + 12) % 1000003
+				v37 = (v10*41 + v37 + 41) % 1000003
+				v23, v16 = v16, v23
+			} else {
+				v13, v21 = v21, v13
+				v35 = (v20*40 + v35 + 40) % 1000003
+				v16 = (v8*2 + v16 + 2) % 1000003
+			}
+		} else {
+			v28 = (v18*28 + v28 + 28) % 1000003
+		}
+	} else {
+		v29 = (v16*47 + v29 + 47) % 1000003
+	}
+	v29 = (v33*41 + v29 + 41) % 1000003
+	s29 = sid(s29[len(s29)/2:] + "2")
+	v10 = id(v20 + 7)
+	v7 = (v12*35 + v7 + 35) % 1000003
+	v19 = (v29*46 + v19 + 46) % 1000003
+	v33 = (v13*33 + v33 + 33) % 1000003
+	v11 = id(v3 + 29)
+	if v11 > 848168 {
+		s5 = sid(s5[len(s5)/2:] + "6")
+		if v22 > 890361 {
+			if v26 > 295948 {
+				v6 = id(v24 + 32)
+				v22 = (v13*40 + v22 + 40) % 1000003
+			} else {
+				s2 = sid(s2[len(s2)/2:] + "1")
+			}
+			v29 = (v32*43 + v29 + 43) % 1000003
+			v15, v13 = v13, v15
+			v38, v11 = v11, v38
+		} else {
+			v21, v1 = v1, v21
+			v21 = (v5*48 + v21 + 48) % 1000003
+			v20 = (v4*26 + v20 + 26) % 1000003
+		}
+	} else {
+		s0 = sid(s0[len(s0)/2:] + "3")
+	}
+	v33 = (v14*40 + v33 + 40) % 1000003
+	if v4 > 528923 {
+		v28, v11 = v11, v28
+		if v2 > 300209 {
+			if v29 > 340707 {
+				v32 = (v24*37 + v32 + 37) % 1000003
+			} else {
+				v23 = (v15*49 + v23 + 49) % 1000003
+				v38, v22 = v22, v38
+			}
+		} else {
+			if v29 > 854247 {
+				v34, v18 = v18, v34
+				s23 = sid(s23[len(s23)/2:] + "7")
+				v25, a = a, v25
+			} else {
+				v12 = (v7*11 + v12 + 11) % 1000003
+				v9 = id(v16 + 9)
+				v15 = id(v6 + 49)
+			}
+			v2 = id(v3 + 9)
+			v34 = (a*15 + v34 + 15) % 1000003
+		}
+		v29 = id(v29 + 23)
+	} else {
+		v30 = (v16*4 + v30 + 4) % 1000003
+		v29, v38 = v38, v29
+	}
+	v2 = (v38*17 + v2 + 17) % 1000003
+	v6 = (v12*4 + v6 + 4) % 1000003
+	v24 = (v0*25 + v24 + 25) % 1000003
+	v31 = (v25*5 + v31 + 5) % 1000003
+	if v32 > 935610 {
+		for k0 := 0; k0 < 1; k0++ {
+			v17, v24 = v24, v17
+			v24, v21 = v21, v24
+		}
+		v17 = (v3*16 + v17 + 16) % 1000003
+	} else {
+		v27 = (v6*14 + v27 + 14) % 1000003
+		v34 = (v5*10 + v34 + 10) % 1000003
+	}
+	v20 = (v1*12 + v20 + 12) % 1000003
+	v10 = (v9*17 + v10 + 17) % 1000003
+	v33 = id(v35 + 20)
+	s17 = sid(s17[len(s17)/2:] + "9")
+	if v35 > 883468 {
+		v9, v20 = v20, v9
+		if v4 > 591243 {
+			v35, v2 = v2, v35
+			v27 = (v27*47 + v27 + 47) % 1000003
+			v32 = (v12*29 + v32 + 29) % 1000003
+			v8, v18 = v18, v8
+		} else {
+			if v11 > 142958 {
+				s6 = sid(s6[len(s6)/2:] + "7")
+			} else {
+				s19 = sid(s19[len(s19)/2:] + "2")
+				v10 = (v14*15 + v10 + 15) % 1000003
+				v14 = (v24*35 + v14 + 35) % 1000003
+			}
+			v20 = (v1*15 + v20 + 15) % 1000003
+		}
+		for k0 := 0; k0 < 3; k0++ {
+			s19 = sid(s19[len(s19)/2:] + "1")
+			v31 = id(v3 + 24)
+			v27 = (v15*2 + v27 + 2) % 1000003
+		}
+		v7 = (v1*22 + v7 + 22) % 1000003
+	} else {
+		for k0 := 0; k0 < 2; k0++ {
+			v3, v9 = v9, v3
+			v15 = (v35*29 + v15 + 29) % 1000003
+		}
+	}
+	v3 = (v0*10 + v3 + 10) % 1000003
+	if v22 > 894916 {
+		v14 = (v17*9 + v14 + 9) % 1000003
+		v23 = (v13*38 + v23 + 38) % 1000003
+	} else {
+		v9 = (v8*21 + v9 + 21) % 1000003
+		v1 = id(a + 47)
+		s11 = sid(s11[len(s11)/2:] + "1")
+	}
+	a, v34 = v34, a
+	if v3 > 991060 {
+		v0 = id(v5 + 42)
+		v34 = (v7*18 + v34 + 18) % 1000003
+		v14 = (v1*45 + v14 + 45) % 1000003
+		for k0 := 0; k0 < 2; k0++ {
+			v4 = (v36*18 + v4 + 18) % 1000003
+			if v3 > 96145 {
+				v35 = (v31*36 + v35 + 36) % 1000003
+				s6 = sid(s6[len(s6)/2:] + "6")
+			} else {
+				v8, v0 = v0, v8
+				v33 = (v5*26 + v33 + 26) % 1000003
+			}
+		}
+	} else {
+	}
+	for k0 := 0; k0 < 1; k0++ {
+		v38 = id(v12 + 7)
+		v2 = (v34*4 + v2 + 4) % 1000003
+	}
+	v31 = (v0*27 + v31 + 27) % 1000003
+	v36 = id(v19 + 8)
+	v25 = (v17*6 + v25 + 6) % 1000003
+	v12 = (v8*6 + v12 + 6) % 1000003
+	v20 = (v34*15 + v20 + 15) % 1000003
+	for k0 := 0; k0 < 2; k0++ {
+		a, v22 = v22, a
+		v2 = (v26*8 + v2 + 8) % 1000003
+		v29 = (v33*20 + v29 + 20) % 1000003
+	}
+	if v8 > 53665 {
+		v27 = (v26*23 + v27 + 23) % 1000003
+	} else {
+		for k0 := 0; k0 < 2; k0++ {
+			v17, v31 = v31, v17
+			v25 = id(v35 + 14)
+			if v10 > 813268 {
+				v8 = (v30*5 + v8 + 5) % 1000003
+				v11 = (v26*9 + v11 + 9) % 1000003
+				v6, v24 = v24, v6
+				v30, v31 = v31, v30
+			} else {
+				v20, v21 = v21, v20
+			}
+		}
+		if v30 > 642115 {
+			v10 = (v33*45 + v10 + 45) % 1000003
+			if v16 > 782947 {
+				v5 = (a*17 + v5 + 17) % 1000003
+				v21 = (v30*42 + v21 + 42) % 1000003
+			} else {
+				v9, v12 = v12, v9
+				v19 = (v23*17 + v19 + 17) % 1000003
+				v32 = id(v21 + 3)
+			}
+		} else {
+			if v17 > 11449 {
+				v31, v25 = v25, v31
+				v12 = (v11*31 + v12 + 31) % 1000003
+				a = (v28*47 + a + 47) % 1000003
+				v30, v2 = v2, v30
+			} else {
+			}
+			v9 = (v19*36 + v9 + 36) % 1000003
+		}
+	}
+	v17 = id(v36 + 18)
+	v16 = (v27*40 + v16 + 40) % 1000003
+	for k0 := 0; k0 < 1; k0++ {
+		s18 = sid(s18[len(s18)/2:] + "1")
+	}
+	v32, v34 = v34, v32
+	v15 = (v12*32 + v15 + 32) % 1000003
+	if v33 > 294047 {
+		v4 = id(v25 + 13)
+		v23 = id(v21 + 22)
+		v4 = (v22*18 + v4 + 18) % 1000003
+		v12 = (v33*11 + v12 + 11) % 1000003
+	} else {
+		v10 = (v25*49 + v10 + 49) % 1000003
+		v9 = id(v5 + 0)
+	}
+	s32 = sid(s32[len(s32)/2:] + "9")
+	for k0 := 0; k0 < 2; k0++ {
+		v5, v13 = v13, v5
+		v13 = id(v18 + 4)
+	}
+	s13 = sid(s13[len(s13)/2:] + "1")
+	if v12 > 435606 {
+		if v10 > 876027 {
+			v6, v2 = v2, v6
+			a = id(v7 + 37)
+		} else {
+			v7 = (v14*28 + v7 + 28) % 1000003
+			v2 = (v9*6 + v2 + 6) % 1000003
+		}
+		v15, v13 = v13, v15
+	} else {
+		v3, v37 = v37, v3
+		v19 = (v26*41 + v19 + 41) % 1000003
+	}
+	v16 = (v23*30 + v16 + 30) % 1000003
+	if v25 > 853305 {
+		v18 = (v7*35 + v18 + 35) % 1000003
+		v18 = (v19*25 + v18 + 25) % 1000003
+		if v20 > 499178 {
+			v28, v20 = v20, v28
+			v8 = (v29*30 + v8 + 30) % 1000003
+			v12 = (v18*25 + v12 + 25) % 1000003
+		} else {
+			v8, v23 = v23, v8
+			v13 = (v18*23 + v13 + 23) % 1000003
+		}
+	} else {
+		if a > 925662 {
+			if v20 > 483257 {
+				v25 = id(v2 + 42)
+				v38, v4 = v4, v38
+				v32 = (v16*16 + v32 + 16) % 1000003
+				v6 = (v30*39 + v6 + 39) % 1000003
+			} else {
+				v36 = id(v34 + 47)
+				v2 = id(v32 + 46)
+			}
+		} else {
+			v13 = id(v16 + 22)
+			if v8 > 77625 {
+				v7 = id(v18 + 18)
+				v24, v18 = v18, v24
+				v1, v28 = v28, v1
+			} else {
+				v18 = id(v8 + 37)
+				v11 = id(v0 + 4)
+				v10, v10 = v10, v10
+			}
+			v11 = (v9*6 + v11 + 6) % 1000003
+		}
+		if v17 > 215946 {
+			s12 = sid(s12[len(s12)/2:] + "1")
+		} else {
+			if v22 > 835648 {
+				v34 = id(v12 + 12)
+				v22 = id(v35 + 18)
+				v7 = (v24*33 + v7 + 33) % 1000003
+				v0, v23 = v23, v0
+			} else {
+				v12, v5 = v5, v12
+				v15, v3 = v3, v15
+				v25, v27 = v27, v25
+			}
+			if v16 > 479241 {
+				v1 = (v30*6 + v1 + 6) % 1000003
+			} else {
+				v19, v38 = v38, v19
+				s35 = sid(s35[len(s35)/2:] + "9")
+			}
+		}
+		if v2 > 988716 {
+			v23 = id(v25 + 12)
+			if v11 > 308535 {
+				s1 = sid(s1[len(s1)/2:] + "8")
+			} else {
+				v22 = (v16*34 + v22 + 34) % 1000003
+				v7, v30 = v30, v7
+			}
+		} else {
+			v24 = (v4*13 + v24 + 13) % 1000003
+			v11 = (v30*31 + v11 + 31) % 1000003
+			if v29 > 24362 {
+				v33, v21 = v21, v33
+				v20 = (v25*34 + v20 + 34) % 1000003
+				v35 = (v7*24 + v35 + 24) % 1000003
+				v4 = (v23*8 + v4 + 8) % 1000003
+			} else {
+				a = (v17*37 + a + 37) % 1000003
+				s39 = sid(s39[len(s39)/2:] + "9")
+				v29, v34 = v34, v29
+			}
+		}
+	}
+	v24 = (v29*12 + v24 + 12) % 1000003
+	v3 = (v30*19 + v3 + 19) % 1000003
+	if v23 > 292077 {
+		v17 = (v21*17 + v17 + 17) % 1000003
+		v19 = (v32*32 + v19 + 32) % 1000003
+		if v27 > 773489 {
+			s18 = sid(s18[len(s18)/2:] + "1")
+		} else {
+			v12 = (v25*10 + v12 + 10) % 1000003
+			if v15 > 716971 {
+				v3, v28 = v28, v3
+				v23, v1 = v1, v23
+			} else {
+				v20 = id(v2 + 35)
+				v28, v25 = v25, v28
+				v18, v19 = v19, v18
+			}
+		}
+		v26 = (v35*17 + v26 + 17) % 1000003
+	} else {
+	}
+	v0 = (v0*36 + v0 + 36) % 1000003
+	if v19 > 326846 {
+		v36 = (v29*40 + v36 + 40) % 1000003
+	} else {
+	}
+	v31 = id(v33 + 29)
+	v31 = (v13*9 + v31 + 9) % 1000003
+	for k0 := 0; k0 < 3; k0++ {
+		if v30 > 554305 {
+			v3 = (v5*26 + v3 + 26) % 1000003
+		} else {
+			v35, v38 = v38, v35
+		}
+		if v5 > 580636 {
+			v34 = (v6*30 + v34 + 30) % 1000003
+			if v14 > 235134 {
+				v37 = (v28*19 + v37 + 19) % 1000003
+			} else {
+				v17, v31 = v31, v17
+			}
+			v15 = (v5*44 + v15 + 44) % 1000003
+		} else {
+			s36 = sid(s36[len(s36)/2:] + "8")
+			v31 = id(v38 + 5)
+			if v22 > 170845 {
+				s9 = sid(s9[len(s9)/2:] + "2")
+				v23, v1 = v1, v23
+			} else {
+			}
+		}
+	}
+	v26, v24 = v24, v26
+	v34 = (v27*46 + v34 + 46) % 1000003
+	v23 = (v6*20 + v23 + 20) % 1000003
+	v17 = (v5*29 + v17 + 29) % 1000003
+	for k0 := 0; k0 < 3; k0++ {
+		v12 = (v27*34 + v12 + 34) % 1000003
+		if v29 > 289216 {
+			v31, v9 = v9, v31
+		} else {
+			v16 = (v26*11 + v16 + 11) % 1000003
+			v31, v5 = v5, v31
+			s29 = sid(s29[len(s29)/2:] + "4")
+		}
+		v24, v13 = v13, v24
+	}
+	v29 = (a*30 + v29 + 30) % 1000003
+	v19, v33 = v33, v19
+	v35 = (v32*1 + v35 + 1) % 1000003
+	v29 = (v30*37 + v29 + 37) % 1000003
+	if v1 > 685618 {
+		v19 = (v38*43 + v19 + 43) % 1000003
+	} else {
+		if v37 > 655866 {
+			v30 = (v7*30 + v30 + 30) % 1000003
+		} else {
+		}
+	}
+	v9, v38 = v38, v9
+	v25 = (v32*38 + v25 + 38) % 1000003
+	v10 = (v13*38 + v10 + 38) % 1000003
+	if v7 > 525685 {
+		for k0 := 0; k0 < 3; k0++ {
+			v38 = (v17*6 + v38 + 6) % 1000003
+			v16, v28 = v28, v16
+		}
+	} else {
+		s19 = sid(s19[len(s19)/2:] + "1")
+		s6 = sid(s6[len(s6)/2:] + "5")
+	}
+	v10, v0 = v0, v10
+	if v38 > 549674 {
+		v28 = (v16*45 + v28 + 45) % 1000003
+	} else {
+		v9, v19 = v19, v9
+		for k0 := 0; k0 < 3; k0++ {
+			v4 = (v36*15 + v4 + 15) % 1000003
+			v9, v24 = v24, v9
+			v30 = (v6*27 + v30 + 27) % 1000003
+		}
+		v38 = (v34*3 + v38 + 3) % 1000003
+	}
+	if v0 > 437811 {
+		v38 = (v9*36 + v38 + 36) % 1000003
+	} else {
+	}
+	v9 = (v10*19 + v9 + 19) % 1000003
+	for k0 := 0; k0 < 2; k0++ {
+		v2 = (v34*17 + v2 + 17) % 1000003
+		v24 = (v15*36 + v24 + 36) % 1000003
+		v9 = (v20*39 + v9 + 39) % 1000003
+	}
+	if v9 > 578470 {
+		if v34 > 753259 {
+			v21 = (v38*46 + v21 + 46) % 1000003
+			v21, v7 = v7, v21
+			v37 = (v14*1 + v37 + 1) % 1000003
+			if v9 > 444604 {
+				v12 = (v8*16 + v12 + 16) % 1000003
+			} else {
+				v22 = (v23*17 + v22 + 17) % 1000003
+				v34 = (v11*10 + v34 + 10) % 1000003
+				s23 = sid(s23[len(s23)/2:] + "4")
+			}
+		} else {
+			if v5 > 310336 {
+				v26 = (v32*47 + v26 + 47) % 1000003
+			} else {
+				v35 = id(v22 + 48)
+			}
+		}
+		v37 = id(v20 + 4)
+		s26 = sid(s26[len(s26)/2:] + "1")
+	} else {
+	}
+	v37, v2 = v2, v37
+	if v7 > 296510 {
+		v35 = (v15*29 + v35 + 29) % 1000003
+		v17 = (a*34 + v17 + 34) % 1000003
+	} else {
+	}
+	v0, v12 = v12, v0
+	if v11 > 335885 {
+		for k0 := 0; k0 < 2; k0++ {
+			if v16 > 229175 {
+				v21 = id(v16 + 16)
+				v3 = (v6*6 + v3 + 6) % 1000003
+			} else {
+				v4 = id(v32 + 44)
+				v35, a = a, v35
+			}
+		}
+		v26 = id(v4 + 6)
+	} else {
+		v24, v10 = v10, v24
+	}
+	v7 = id(v1 + 46)
+	v0 = (v16*48 + v0 + 48) % 1000003
+	v6 = id(v27 + 2)
+	v34 = (v0*17 + v34 + 17) % 1000003
+	v22 = (v16*14 + v22 + 14) % 1000003
+	v5 = (v19*29 + v5 + 29) % 1000003
+	v34 = (v7*47 + v34 + 47) % 1000003
+	v4 = (v0*46 + v4 + 46) % 1000003
+	v18 = id(v35 + 6)
+	if v28 > 641041 {
+		if v36 > 123278 {
+			v38 = id(v16 + 30)
+			s0 = sid(s0[len(s0)/2:] + "4")
+		} else {
+		}
+		v33 = (v22*32 + v33 + 32) % 1000003
+	} else {
+		if v28 > 165783 {
+			if v2 > 463751 {
+				v38 = (v6*4 + v38 + 4) % 1000003
+				v21 = (v13*21 + v21 + 21) % 1000003
+				v27, v22 = v22, v27
+				v15 = id(v4 + 0)
+			} else {
+				v9 = (v35*14 + v9 + 14) % 1000003
+				v24, v34 = v34, v24
+				v36, v15 = v15, v36
+			}
+			if v37 > 724524 {
+				v12 = (v19*3 + v12 + 3) % 1000003
+			} else {
+				v20 = id(v3 + 29)
+				v13 = (v37*17 + v13 + 17) % 1000003
+			}
+			v4 = (v1*12 + v4 + 12) % 1000003
+		} else {
+			v15, v37 = v37, v15
+			v27 = (v12*19 + v27 + 19) % 1000003
+			if v16 > 663158 {
+				v33, v28 = v28, v33
+				v32, v19 = v19, v32
+				v9, v27 = v27, v9
+			} else {
+				v12 = id(v35 + 39)
+			}
+		}
+	}
+	v28, v27 = v27, v28
+	v21 = (v30*5 + v21 + 5) % 1000003
+	if v33 > 611574 {
+		v33 = id(v8 + 30)
+		v29 = (v26*9 + v29 + 9) % 1000003
+		v18 = (v1*6 + v18 + 6) % 1000003
+		if v8 > 684782 {
+			if v9 > 807110 {
+				s3 = sid(s3[len(s3)/2:] + "3")
+				v15 = id(v18 + 13)
+			} else {
+				v0 = (v8*29 + v0 + 29) % 1000003
+				v26 = (v21*33 + v26 + 33) % 1000003
+			}
+			v15 = (v17*16 + v15 + 16) % 1000003
+			v24 = (v26*20 + v24 + 20) % 1000003
+		} else {
+			s36 = sid(s36[len(s36)/2:] + "6")
+			if v32 > 438214 {
+				v34, v22 = v22, v34
+				v36, v11 = v11, v36
+				v28 = (v36*22 + v28 + 22) % 1000003
+			} else {
+				v1 = (v31*22 + v1 + 22) % 1000003
+				v32 = (v36*16 + v32 + 16) % 1000003
+			}
+		}
+	} else {
+		v34 = (v17*7 + v34 + 7) % 1000003
+		if v2 > 689415 {
+			s24 = sid(s24[len(s24)/2:] + "7")
+			v29, v3 = v3, v29
+			v30 = (v23*11 + v30 + 11) % 1000003
+		} else {
+		}
+	}
+	if v11 > 997947 {
+		v2 = id(v2 + 42)
+	} else {
+	}
+	if v16 > 639964 {
+		s29 = sid(s29[len(s29)/2:] + "3")
+	} else {
+		v18 = (v1*49 + v18 + 49) % 1000003
+		v22 = (v31*30 + v22 + 30) % 1000003
+	}
+	if v17 > 563403 {
+		v8, v20 = v20, v8
+		s35 = sid(s35[len(s35)/2:] + "5")
+	} else {
+		v25 = (v6*39 + v25 + 39) % 1000003
+		v29 = (v30*10 + v29 + 10) % 1000003
+	}
+	v11 = (a*3 + v11 + 3) % 1000003
+	for k0 := 0; k0 < 2; k0++ {
+		v25, v3 = v3, v25
+		v14, v28 = v28, v14
+	}
+	v27 = (v36*41 + v27 + 41) % 1000003
+	if v33 > 11307 {
+		if v10 > 555955 {
+			v33, v26 = v26, v33
+		} else {
+			v16, v8 = v8, v16
+			s21 = sid(s21[len(s21)/2:] + "8")
+		}
+	} else {
+		if v19 > 337391 {
+			v25 = (v33*42 + v25 + 42) % 1000003
+		} else {
+			v38 = id(v20 + 17)
+		}
+		if v16 > 765355 {
+			v27 = (v13*5 + v27 + 5) % 1000003
+			v26 = id(v6 + 42)
+			v0 = (v35*1 + v0 + 1) % 1000003
+			if v14 > 580497 {
+				v37 = (v3*31 + v37 + 31) % 1000003
+			} else {
+				v29, v33 = v33, v29
+				v31, a = a, v31
+			}
+		} else {
+			if v18 > 716376 {
+				s6 = sid(s6[len(s6)/2:] + "3")
+				v1 = (v6*18 + v1 + 18) % 1000003
+				s29 = sid(s29[len(s29)/2:] + "3")
+			} else {
+				v1, v27 = v27, v1
+				s14 = sid(s14[len(s14)/2:] + "0")
+				v23, v6 = v6, v23
+			}
+			v6 = (v11*39 + v6 + 39) % 1000003
+			if v7 > 116701 {
+				v16 = (v5*48 + v16 + 48) % 1000003
+			} else {
+				v23 = (v19*35 + v23 + 35) % 1000003
+				s3 = sid(s3[len(s3)/2:] + "8")
+			}
+		}
+		v19 = (v10*47 + v19 + 47) % 1000003
+	}
+	return [40]int{v0, v1, v2, v3, v4, v5, v6, v7, v8, v9, v10, v11, v12, v13, v14, v15, v16, v17, v18, v19, v20, v21, v22, v23, v24, v25, v26, v27, v28, v29, v30, v31, v32, v33, v34, v35, v36, v37, v38, a}, [40]string{s0, s1, s2, s3, s4, s5, s6, s7, s8, s9, s10, s11, s12, s13, s14, s15, s16, s17, s18, s19, s20, s21, s22, s23, s24, s25, s26, s27, s28, s29, s30, s31, s32, s33, s34, s35, s36, s37, s38, s39}
+}
+func main() {
+	v, s := large_ssa(0)
+	if want := [40]int{523084, 418496, 371577, 876399, 552165, 892574, 205200, 36396, 779460, 312761, 61783, 843141, 957069, 371174, 241740, 110780, 73104, 547098, 535644, 36328, 978205, 289494, 787807, 416738, 306269, 323331, 490333, 466653, 205198, 457584, 702005, 199188, 791097, 73134, 440536, 317532, 529908, 582495, 779490, 362445}; v != want {
+		fmt.Printf("large_ssa ints got=%v, want %v\n", v, want)
+		panic("failed")
+	}
+	if want := [40]string{"44", "85", "50", "57", "35", "76", "03", "81", "8", "22", "30", "1", "1", "01", "98", "0", "11", "9", "91", "", "63", "48", "63", "", "15", "44", "46", "55", "61", "43", "59", "7", "9", "", "3", "71", "86", "27", "5", "30"}; s != want {
+		fmt.Printf("large_ssa strings got=%q, want %q\n", s, want)
+		panic("failed")
+	}
+}
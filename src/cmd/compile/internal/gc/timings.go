@@ -0,0 +1,197 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gc
+
+import (
+	"bufio"
+	"bytes"
+	"cmd/compile/internal/ir"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+)
+
+// -bench records the time spent in each phase of compiling each
+// function, and writes a report, sorted by time, to the named file
+// when the compiler exits. It is meant to be attached to bug reports
+// about slow compiles: "walk of foo.Generate took 22s".
+//
+// -d timings prints, at exit, the total time of each phase and the
+// counts of the work done in the hot spots of the compiler on one
+// line of name=value pairs, so that a change in compile speed can be
+// traced to the phase and the kind of work that changed:
+//
+//	timings: pkg=p total=... parse=... typecheck=... typechecked=... walked=... progs=... liveness=...
+//
+// Times are in nanoseconds.
+
+var benchfile string
+
+// counts is the work done in the hot spots of the compiler. The
+// counts are kept whether or not -d timings is set.
+var counts struct {
+	typechecked int // nodes type checked by typecheck1
+	walked      int // statements and expressions walked
+	progs       int // Progs generated
+	liveness    int // rounds of the liveness dataflow loops
+}
+
+// A timing is the time spent in one phase for one function. If the
+// phase applies to the whole package, fn is empty.
+type timing struct {
+	phase string
+	fn    string
+	d     time.Duration
+}
+
+var (
+	timingsMu sync.Mutex // ssa.Compile may run concurrently; see -c
+	timings   []timing
+)
+
+// timestart returns the start time of a phase, or the zero time
+// if neither -bench nor -d timings is set.
+func timestart() time.Time {
+	if benchfile == "" && Debug_timings == 0 {
+		return time.Time{}
+	}
+	return time.Now()
+}
+
+// timeend records the time since start as spent in phase for fn,
+// or for the whole package if fn is nil.
+func timeend(phase string, fn *ir.Node, start time.Time) {
+	if benchfile == "" && Debug_timings == 0 {
+		return
+	}
+	d := time.Since(start)
+	name := ""
+	if fn != nil {
+		name = timingname(fn)
+	}
+	timingsMu.Lock()
+	timings = append(timings, timing{phase, name, d})
+	timingsMu.Unlock()
+}
+
+// timingname returns the name of fn in the report.
+func timingname(fn *ir.Node) string {
+	if fn.Func == nil || fn.Func.Nname == nil || fn.Func.Nname.Sym == nil {
+		return localpkg.Name + ".func literal"
+	}
+	return localpkg.Name + "." + fn.Func.Nname.Sym.Name
+}
+
+// startTimings arranges for the report to be written and the
+// timings line to be printed at exit.
+func startTimings() {
+	if benchfile == "" && Debug_timings == 0 {
+		return
+	}
+	start := time.Now()
+	AtExit(func() {
+		total := time.Since(start)
+		if Debug_timings != 0 {
+			printTimings(total)
+		}
+		if benchfile == "" {
+			return
+		}
+		f, err := os.Create(benchfile)
+		if err != nil {
+			Fatalf("%v", err)
+		}
+		w := bufio.NewWriter(f)
+		writeTimings(w, total)
+		if err := w.Flush(); err != nil {
+			Fatalf("%v", err)
+		}
+		if err := f.Close(); err != nil {
+			Fatalf("%v", err)
+		}
+	})
+}
+
+// writeTimings writes the report: the total time of each phase, and
+// then the time of each phase for each function, longest first.
+func writeTimings(w *bufio.Writer, total time.Duration) {
+	timingsMu.Lock()
+	defer timingsMu.Unlock()
+
+	type key struct{ phase, fn string }
+	sums := map[key]time.Duration{}
+	phases := map[string]time.Duration{}
+	for _, t := range timings {
+		sums[key{t.phase, t.fn}] += t.d
+		phases[t.phase] += t.d
+	}
+
+	var l []timing
+	for p, d := range phases {
+		l = append(l, timing{phase: p, d: d})
+	}
+	sort.Sort(byDuration(l))
+	fmt.Fprintf(w, "package %s took %v\n", localpkg.Name, total)
+	for _, t := range l {
+		fmt.Fprintf(w, "%s took %v\n", t.phase, t.d)
+	}
+
+	l = l[:0]
+	for k, d := range sums {
+		if k.fn != "" {
+			l = append(l, timing{k.phase, k.fn, d})
+		}
+	}
+	sort.Sort(byDuration(l))
+	fmt.Fprintf(w, "\n")
+	for _, t := range l {
+		fmt.Fprintf(w, "%s of %s took %v\n", t.phase, t.fn, t.d)
+	}
+}
+
+// printTimings prints the line of -d timings.
+func printTimings(total time.Duration) {
+	timingsMu.Lock()
+	defer timingsMu.Unlock()
+
+	phases := map[string]time.Duration{}
+	var names []string
+	for _, t := range timings {
+		if _, ok := phases[t.phase]; !ok {
+			names = append(names, t.phase)
+		}
+		phases[t.phase] += t.d
+	}
+	sort.Strings(names)
+
+	pkg := myimportpath
+	if pkg == "" {
+		pkg = localpkg.Name
+	}
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "timings: pkg=%s total=%d", pkg, int64(total))
+	for _, p := range names {
+		fmt.Fprintf(&buf, " %s=%d", p, int64(phases[p]))
+	}
+	fmt.Fprintf(&buf, " typechecked=%d walked=%d progs=%d liveness=%d\n", counts.typechecked, counts.walked, counts.progs, counts.liveness)
+	os.Stdout.Write(buf.Bytes())
+}
+
+// byDuration sorts timings longest first, and then by phase and name.
+type byDuration []timing
+
+func (s byDuration) Len() int { return len(s) }
+func (s byDuration) Less(i, j int) bool {
+	if s[i].d != s[j].d {
+		return s[i].d > s[j].d
+	}
+	if s[i].phase != s[j].phase {
+		return s[i].phase < s[j].phase
+	}
+	return s[i].fn < s[j].fn
+}
+func (s byDuration) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
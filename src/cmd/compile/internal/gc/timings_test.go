@@ -0,0 +1,68 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gc
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"testing"
+)
+
+// Make sure -bench reports the phases of compiling each function.
+func TestBench(t *testing.T) {
+	d := newTestDir(t, "TestBench")
+	defer d.remove()
+
+	for _, c := range []string{"-c=1", "-c=2"} {
+		bench := d.path("bench.txt")
+		d.compile("p", "package p\n\nfunc F(x int) int { return x * 3 }\n", c, "-bench", bench)
+		data, err := ioutil.ReadFile(bench)
+		if err != nil {
+			t.Fatalf("could not read report: %v", err)
+		}
+		report := string(data)
+		if !strings.HasPrefix(report, "package p took ") {
+			t.Errorf("%s: report does not start with the total:\n%s", c, report)
+		}
+		for _, phase := range []string{"parse", "typecheck", "walk", "compile", "gen", "dumpobj"} {
+			if !strings.Contains(report, "\n"+phase+" took ") {
+				t.Errorf("%s: report has no total for %s:\n%s", c, phase, report)
+			}
+		}
+		for _, phase := range []string{"typecheck", "walk", "ssa", "compile", "gen", "assemble"} {
+			if !strings.Contains(report, "\n"+phase+" of p.F took ") {
+				t.Errorf("%s: report has no %s of p.F:\n%s", c, phase, report)
+			}
+		}
+	}
+}
+
+// Make sure -d timings prints one line of phase times and counts.
+func TestTimings(t *testing.T) {
+	d := newTestDir(t, "TestTimings")
+	defer d.remove()
+
+	src := d.write("p.go", "package p\n\nfunc F(x []int) (s int) {\n\tfor _, v := range x {\n\t\ts += v\n\t}\n\treturn\n}\n")
+	out := d.run("go", "tool", "compile", "-d", "timings", "-p", "example.com/p", "-o", d.path("p.o"), src)
+	line := strings.TrimSuffix(out, "\n")
+	if strings.Contains(line, "\n") || !strings.HasPrefix(line, "timings: pkg=example.com/p ") {
+		t.Fatalf("output is not one timings line:\n%s", out)
+	}
+	fields := map[string]string{}
+	for _, f := range strings.Fields(strings.TrimPrefix(line, "timings: ")) {
+		i := strings.Index(f, "=")
+		if i < 0 {
+			t.Fatalf("field %q is not name=value in %s", f, line)
+		}
+		fields[f[:i]] = f[i+1:]
+	}
+	for _, name := range []string{"total", "parse", "typecheck", "walk", "gen", "dumpobj", "typechecked", "walked", "progs", "liveness"} {
+		var n int64
+		if _, err := fmt.Sscanf(fields[name], "%d", &n); err != nil || n <= 0 {
+			t.Errorf("%s=%q, want a positive integer, in %s", name, fields[name], line)
+		}
+	}
+}
@@ -5,6 +5,7 @@
 package gc
 
 import (
+	"cmd/compile/internal/ir"
 	"cmd/internal/obj"
 	"fmt"
 	"math"
@@ -16,17 +17,34 @@ import (
 // evaluates compile time constants.
 // marks variables that escape the local frame.
 // rewrites n->op to be more specific in some cases.
-var typecheckdefstack []*Node
+var typecheckdefstack []*ir.Node
+
+// iotastack holds the value of iota for each constant
+// being typechecked by typecheckdef, innermost last.
+// typecheckclosure saves the top for the closure's body.
+var iotastack []int32
+
+// curiota returns the value of iota in the current context,
+// or -1 if iota is not defined there.
+func curiota() int32 {
+	if len(iotastack) == 0 {
+		return -1
+	}
+	return iotastack[len(iotastack)-1]
+}
 
 // resolve ONONAME to definition, if any.
-func resolve(n *Node) *Node {
-	if n != nil && n.Op == ONONAME && n.Sym != nil {
+func resolve(n *ir.Node) *ir.Node {
+	if n != nil && n.Op == ir.ONONAME && n.Sym != nil {
 		r := n.Sym.Def
 		if r != nil {
-			if r.Op != OIOTA {
+			if r.Op != ir.OIOTA {
 				n = r
-			} else if n.Name.Iota >= 0 {
-				n = Nodintconst(int64(n.Name.Iota))
+			} else if v := curiota(); v >= 0 && n.Name.Iota >= 0 {
+				// n is in a constant declaration, perhaps
+				// in a function literal, and so is the
+				// declaration being typechecked.
+				n = Nodintconst(int64(v))
 			}
 		}
 	}
@@ -34,44 +52,44 @@ func resolve(n *Node) *Node {
 	return n
 }
 
-func typecheckslice(l []*Node, top int) {
+func typecheckslice(l []*ir.Node, top int) {
 	for i := range l {
 		l[i] = typecheck(l[i], top)
 	}
 }
 
 var _typekind = []string{
-	TINT:        "int",
-	TUINT:       "uint",
-	TINT8:       "int8",
-	TUINT8:      "uint8",
-	TINT16:      "int16",
-	TUINT16:     "uint16",
-	TINT32:      "int32",
-	TUINT32:     "uint32",
-	TINT64:      "int64",
-	TUINT64:     "uint64",
-	TUINTPTR:    "uintptr",
-	TCOMPLEX64:  "complex64",
-	TCOMPLEX128: "complex128",
-	TFLOAT32:    "float32",
-	TFLOAT64:    "float64",
-	TBOOL:       "bool",
-	TSTRING:     "string",
-	TPTR32:      "pointer",
-	TPTR64:      "pointer",
-	TUNSAFEPTR:  "unsafe.Pointer",
-	TSTRUCT:     "struct",
-	TINTER:      "interface",
-	TCHAN:       "chan",
-	TMAP:        "map",
-	TARRAY:      "array",
-	TFUNC:       "func",
-	TNIL:        "nil",
-	TIDEAL:      "untyped number",
+	ir.TINT:        "int",
+	ir.TUINT:       "uint",
+	ir.TINT8:       "int8",
+	ir.TUINT8:      "uint8",
+	ir.TINT16:      "int16",
+	ir.TUINT16:     "uint16",
+	ir.TINT32:      "int32",
+	ir.TUINT32:     "uint32",
+	ir.TINT64:      "int64",
+	ir.TUINT64:     "uint64",
+	ir.TUINTPTR:    "uintptr",
+	ir.TCOMPLEX64:  "complex64",
+	ir.TCOMPLEX128: "complex128",
+	ir.TFLOAT32:    "float32",
+	ir.TFLOAT64:    "float64",
+	ir.TBOOL:       "bool",
+	ir.TSTRING:     "string",
+	ir.TPTR32:      "pointer",
+	ir.TPTR64:      "pointer",
+	ir.TUNSAFEPTR:  "unsafe.Pointer",
+	ir.TSTRUCT:     "struct",
+	ir.TINTER:      "interface",
+	ir.TCHAN:       "chan",
+	ir.TMAP:        "map",
+	ir.TARRAY:      "array",
+	ir.TFUNC:       "func",
+	ir.TNIL:        "nil",
+	ir.TIDEAL:      "untyped number",
 }
 
-func typekind(t *Type) string {
+func typekind(t *ir.Type) string {
 	if Isslice(t) {
 		return "slice"
 	}
@@ -88,7 +106,7 @@ func typekind(t *Type) string {
 // sprint_depchain prints a dependency chain of nodes into fmt.
 // It is used by typecheck in the case of OLITERAL nodes
 // to print constant definition loops.
-func sprint_depchain(fmt_ *string, stack []*Node, cur *Node, first *Node) {
+func sprint_depchain(fmt_ *string, stack []*ir.Node, cur *ir.Node, first *ir.Node) {
 	for i := len(stack) - 1; i >= 0; i-- {
 		if n := stack[i]; n.Op == cur.Op {
 			if n != first {
@@ -100,12 +118,12 @@ func sprint_depchain(fmt_ *string, stack []*Node, cur *Node, first *Node) {
 	}
 }
 
-var typecheck_tcstack []*Node
+var typecheck_tcstack []*ir.Node
 
 // typecheck type checks node n.
 // The result of typecheck MUST be assigned back to n, e.g.
 // 	n.Left = typecheck(n.Left, top)
-func typecheck(n *Node, top int) *Node {
+func typecheck(n *ir.Node, top int) *ir.Node {
 	// cannot type check until all the source has been parsed
 	if !typecheckok {
 		Fatalf("early typecheck")
@@ -118,7 +136,7 @@ func typecheck(n *Node, top int) *Node {
 	lno := setlineno(n)
 
 	// Skip over parens.
-	for n.Op == OPAREN {
+	for n.Op == ir.OPAREN {
 		n = n.Left
 	}
 
@@ -129,7 +147,7 @@ func typecheck(n *Node, top int) *Node {
 	// But re-typecheck ONAME/OTYPE/OLITERAL/OPACK node in case context has changed.
 	if n.Typecheck == 1 {
 		switch n.Op {
-		case ONAME, OTYPE, OLITERAL, OPACK:
+		case ir.ONAME, ir.OTYPE, ir.OLITERAL, ir.OPACK:
 			break
 
 		default:
@@ -144,12 +162,12 @@ func typecheck(n *Node, top int) *Node {
 		var fmt_ string
 		switch n.Op {
 		// We can already diagnose variables used as types.
-		case ONAME:
+		case ir.ONAME:
 			if top&(Erv|Etype) == Etype {
 				Yyerror("%v is not a type", n)
 			}
 
-		case OLITERAL:
+		case ir.OLITERAL:
 			if top&(Erv|Etype) == Etype {
 				Yyerror("%v is not a type", n)
 				break
@@ -174,6 +192,7 @@ func typecheck(n *Node, top int) *Node {
 	n.Typecheck = 2
 
 	typecheck_tcstack = append(typecheck_tcstack, n)
+	counts.typechecked++
 	n = typecheck1(n, top)
 
 	n.Typecheck = 1
@@ -186,31 +205,85 @@ func typecheck(n *Node, top int) *Node {
 	return n
 }
 
+// isbinary reports whether n is a binary expression
+// whose operands are typechecked by the arithmetic case of typecheck1.
+func isbinary(n *ir.Node) bool {
+	switch n.Op {
+	case ir.OADD, ir.OAND, ir.OANDAND, ir.OANDNOT, ir.ODIV, ir.OEQ, ir.OGE, ir.OGT, ir.OHMUL, ir.OLE,
+		ir.OLT, ir.OLSH, ir.ORSH, ir.OMOD, ir.OMUL, ir.ONE, ir.OOR, ir.OOROR, ir.OSUB, ir.OXOR:
+		return true
+	}
+	return false
+}
+
+// typecheckchain typechecks the binary expressions nested along
+// the left operands of n, innermost first. The parser builds
+// x + y + ... + z as a left-nested tree, so typechecking each
+// operand in turn would recurse once per term; machine-generated
+// code with thousands of terms would exhaust the stack.
+func typecheckchain(n *ir.Node, top int) {
+	var chain []*ir.Node
+	for l := n.Left; l != nil && l.Typecheck == 0 && isbinary(l); l = l.Left {
+		chain = append(chain, l)
+	}
+	if len(chain) < 2 {
+		return
+	}
+	// Each typecheck finds its own left operand already done.
+	for i := len(chain) - 1; i > 0; i-- {
+		chain[i-1].Left = typecheck(chain[i], top)
+	}
+}
+
+// checkshiftcount reports whether the shift n of l by r has a valid
+// count. A constant count, folded by the time r is typechecked, is
+// checked here so that the error is at the shift, not at the count,
+// and so that a negative count isn't reported as an overflow of uint.
+func checkshiftcount(n, l, r *ir.Node) bool {
+	if r.Op != ir.OLITERAL || (r.Val().Ctype() != ir.CTINT && r.Val().Ctype() != ir.CTRUNE) {
+		return true
+	}
+	s := r.Val().U.(*ir.Mpint)
+	if s.Ovf {
+		return true // already reported
+	}
+	if s.CmpInt64(0) < 0 {
+		yyerrorl(n.Lineno, "invalid negative shift count: %v", s)
+		return false
+	}
+	// A constant shifted by a huge count must be folded, and can't be.
+	if n.Op == ir.OLSH && l.Op == ir.OLITERAL && s.CmpInt64(ir.Mpintprec) >= 0 {
+		yyerrorl(n.Lineno, "shift count too large: %v", s)
+		return false
+	}
+	return true
+}
+
 // does n contain a call or receive operation?
-func callrecv(n *Node) bool {
+func callrecv(n *ir.Node) bool {
 	if n == nil {
 		return false
 	}
 
 	switch n.Op {
-	case OCALL,
-		OCALLMETH,
-		OCALLINTER,
-		OCALLFUNC,
-		ORECV,
-		OCAP,
-		OLEN,
-		OCOPY,
-		ONEW,
-		OAPPEND,
-		ODELETE:
+	case ir.OCALL,
+		ir.OCALLMETH,
+		ir.OCALLINTER,
+		ir.OCALLFUNC,
+		ir.ORECV,
+		ir.OCAP,
+		ir.OLEN,
+		ir.OCOPY,
+		ir.ONEW,
+		ir.OAPPEND,
+		ir.ODELETE:
 		return true
 	}
 
 	return callrecv(n.Left) || callrecv(n.Right) || callrecvlist(n.Ninit) || callrecvlist(n.Nbody) || callrecvlist(n.List) || callrecvlist(n.Rlist)
 }
 
-func callrecvlist(l Nodes) bool {
+func callrecvlist(l ir.Nodes) bool {
 	for _, n := range l.Slice() {
 		if callrecv(n) {
 			return true
@@ -225,13 +298,13 @@ func callrecvlist(l Nodes) bool {
 // whenever they can be represented by a value of type int.
 // The result of indexlit MUST be assigned back to n, e.g.
 // 	n.Left = indexlit(n.Left)
-func indexlit(n *Node) *Node {
+func indexlit(n *ir.Node) *ir.Node {
 	if n == nil || !isideal(n.Type) {
 		return n
 	}
-	switch consttype(n) {
-	case CTINT, CTRUNE, CTFLT, CTCPLX:
-		n = defaultlit(n, Types[TINT])
+	switch ir.Consttype(n) {
+	case ir.CTINT, ir.CTRUNE, ir.CTFLT, ir.CTCPLX:
+		n = defaultlit(n, ir.Types[ir.TINT])
 	}
 
 	n = defaultlit(n, nil)
@@ -240,20 +313,20 @@ func indexlit(n *Node) *Node {
 
 // The result of typecheck1 MUST be assigned back to n, e.g.
 // 	n.Left = typecheck1(n.Left, top)
-func typecheck1(n *Node, top int) *Node {
+func typecheck1(n *ir.Node, top int) *ir.Node {
 	switch n.Op {
-	case OXDOT, ODOT, ODOTPTR, ODOTMETH, ODOTINTER:
+	case ir.OXDOT, ir.ODOT, ir.ODOTPTR, ir.ODOTMETH, ir.ODOTINTER:
 		// n.Sym is a field/method name, not a variable.
 	default:
 		if n.Sym != nil {
-			if n.Op == ONAME && n.Etype != 0 && top&Ecall == 0 {
+			if n.Op == ir.ONAME && n.Etype != 0 && top&Ecall == 0 {
 				Yyerror("use of builtin %v not in function call", n.Sym)
 				n.Type = nil
 				return n
 			}
 
 			typecheckdef(n)
-			if n.Op == ONONAME {
+			if n.Op == ir.ONONAME {
 				n.Type = nil
 				return n
 			}
@@ -270,19 +343,19 @@ OpSwitch:
 		Fatalf("typecheck %v", Oconv(n.Op, 0))
 
 	// names
-	case OLITERAL:
+	case ir.OLITERAL:
 		ok |= Erv
 
-		if n.Type == nil && n.Val().Ctype() == CTSTR {
-			n.Type = idealstring
+		if n.Type == nil && n.Val().Ctype() == ir.CTSTR {
+			n.Type = ir.Idealstring
 		}
 		break OpSwitch
 
-	case ONONAME:
+	case ir.ONONAME:
 		ok |= Erv
 		break OpSwitch
 
-	case ONAME:
+	case ir.ONAME:
 		if n.Name.Decldepth == 0 {
 			n.Name.Decldepth = decldepth
 		}
@@ -311,16 +384,16 @@ OpSwitch:
 		ok |= Erv
 		break OpSwitch
 
-	case OPACK:
+	case ir.OPACK:
 		Yyerror("use of package %v without selector", n.Sym)
 		n.Type = nil
 		return n
 
-	case ODDD:
+	case ir.ODDD:
 		break
 
 	// types (OIND is with exprs)
-	case OTYPE:
+	case ir.OTYPE:
 		ok |= Etype
 
 		if n.Type == nil {
@@ -328,14 +401,14 @@ OpSwitch:
 			return n
 		}
 
-	case OTARRAY:
+	case ir.OTARRAY:
 		ok |= Etype
-		t := typ(TARRAY)
+		t := ir.Typ(ir.TARRAY)
 		l := n.Left
 		r := n.Right
 		if l == nil {
 			t.Bound = -1 // slice
-		} else if l.Op == ODDD {
+		} else if l.Op == ir.ODDD {
 			t.Bound = -100 // to be filled in
 			if top&Ecomplit == 0 && n.Diag == 0 {
 				t.Broke = true
@@ -345,16 +418,18 @@ OpSwitch:
 		} else {
 			n.Left = typecheck(n.Left, Erv)
 			l := n.Left
-			var v Val
-			switch consttype(l) {
-			case CTINT, CTRUNE:
+			var v ir.Val
+			switch ir.Consttype(l) {
+			case ir.CTINT, ir.CTRUNE:
 				v = l.Val()
 
-			case CTFLT:
+			case ir.CTFLT:
 				v = toint(l.Val())
 
 			default:
-				if l.Type != nil && Isint[l.Type.Etype] && l.Op != OLITERAL {
+				if l.Diag != 0 && l.Op != ir.ONAME {
+					// error already reported inside the bound expression
+				} else if l.Type != nil && Isint[l.Type.Etype] && l.Op != ir.OLITERAL {
 					Yyerror("non-constant array bound %v", l)
 				} else {
 					Yyerror("invalid array bound %v", l)
@@ -363,8 +438,8 @@ OpSwitch:
 				return n
 			}
 
-			t.Bound = v.U.(*Mpint).Int64()
-			if doesoverflow(v, Types[TINT]) {
+			t.Bound = v.U.(*ir.Mpint).Int64()
+			if doesoverflow(v, ir.Types[ir.TINT]) {
 				Yyerror("array bound is too large")
 				n.Type = nil
 				return n
@@ -381,7 +456,10 @@ OpSwitch:
 			return n
 		}
 		t.Type = r.Type
-		n.Op = OTYPE
+		if t.Bound != -100 {
+			t = sharetype(t)
+		}
+		n.Op = ir.OTYPE
 		n.Type = t
 		n.Left = nil
 		n.Right = nil
@@ -389,7 +467,7 @@ OpSwitch:
 			checkwidth(t)
 		}
 
-	case OTMAP:
+	case ir.OTMAP:
 		ok |= Etype
 		n.Left = typecheck(n.Left, Etype)
 		n.Right = typecheck(n.Right, Etype)
@@ -399,12 +477,12 @@ OpSwitch:
 			n.Type = nil
 			return n
 		}
-		n.Op = OTYPE
+		n.Op = ir.OTYPE
 		n.Type = maptype(l.Type, r.Type)
 		n.Left = nil
 		n.Right = nil
 
-	case OTCHAN:
+	case ir.OTCHAN:
 		ok |= Etype
 		n.Left = typecheck(n.Left, Etype)
 		l := n.Left
@@ -412,18 +490,18 @@ OpSwitch:
 			n.Type = nil
 			return n
 		}
-		t := typ(TCHAN)
+		t := ir.Typ(ir.TCHAN)
 		t.Type = l.Type
 		// TODO(marvin): Fix Node.EType type union.
 		t.Chan = uint8(n.Etype)
-		n.Op = OTYPE
-		n.Type = t
+		n.Op = ir.OTYPE
+		n.Type = sharetype(t)
 		n.Left = nil
 		n.Etype = 0
 
-	case OTSTRUCT:
+	case ir.OTSTRUCT:
 		ok |= Etype
-		n.Op = OTYPE
+		n.Op = ir.OTYPE
 		n.Type = tostruct(n.List.Slice())
 		if n.Type == nil || n.Type.Broke {
 			n.Type = nil
@@ -431,26 +509,26 @@ OpSwitch:
 		}
 		n.List.Set(nil)
 
-	case OTINTER:
+	case ir.OTINTER:
 		ok |= Etype
-		n.Op = OTYPE
+		n.Op = ir.OTYPE
 		n.Type = tointerface(n.List.Slice())
 		if n.Type == nil {
 			n.Type = nil
 			return n
 		}
 
-	case OTFUNC:
+	case ir.OTFUNC:
 		ok |= Etype
-		n.Op = OTYPE
-		n.Type = functype(n.Left, n.List.Slice(), n.Rlist.Slice())
+		n.Op = ir.OTYPE
+		n.Type = sharetype(functype(n.Left, n.List.Slice(), n.Rlist.Slice()))
 		if n.Type == nil {
 			n.Type = nil
 			return n
 		}
 
 	// type or expr
-	case OIND:
+	case ir.OIND:
 		ntop := Erv | Etype
 
 		if top&Eaddr == 0 { // The *x in &*x is not an indirect.
@@ -464,9 +542,9 @@ OpSwitch:
 			n.Type = nil
 			return n
 		}
-		if l.Op == OTYPE {
+		if l.Op == ir.OTYPE {
 			ok |= Etype
-			n.Op = OTYPE
+			n.Op = ir.OTYPE
 			n.Type = Ptrto(l.Type)
 			n.Left = nil
 			break OpSwitch
@@ -474,7 +552,7 @@ OpSwitch:
 
 		if !Isptr[t.Etype] {
 			if top&(Erv|Etop) != 0 {
-				Yyerror("invalid indirect of %v", Nconv(n.Left, FmtLong))
+				Yyerror("invalid indirect of %v", Nconv(n.Left, ir.FmtLong))
 				n.Type = nil
 				return n
 			}
@@ -487,31 +565,31 @@ OpSwitch:
 		break OpSwitch
 
 	// arithmetic exprs
-	case OASOP,
-		OADD,
-		OAND,
-		OANDAND,
-		OANDNOT,
-		ODIV,
-		OEQ,
-		OGE,
-		OGT,
-		OHMUL,
-		OLE,
-		OLT,
-		OLSH,
-		ORSH,
-		OMOD,
-		OMUL,
-		ONE,
-		OOR,
-		OOROR,
-		OSUB,
-		OXOR:
-		var l *Node
-		var op Op
-		var r *Node
-		if n.Op == OASOP {
+	case ir.OASOP,
+		ir.OADD,
+		ir.OAND,
+		ir.OANDAND,
+		ir.OANDNOT,
+		ir.ODIV,
+		ir.OEQ,
+		ir.OGE,
+		ir.OGT,
+		ir.OHMUL,
+		ir.OLE,
+		ir.OLT,
+		ir.OLSH,
+		ir.ORSH,
+		ir.OMOD,
+		ir.OMUL,
+		ir.ONE,
+		ir.OOR,
+		ir.OOROR,
+		ir.OSUB,
+		ir.OXOR:
+		var l *ir.Node
+		var op ir.Op
+		var r *ir.Node
+		if n.Op == ir.OASOP {
 			ok |= Etop
 			n.Left = typecheck(n.Left, Erv)
 			n.Right = typecheck(n.Right, Erv)
@@ -528,9 +606,10 @@ OpSwitch:
 				return n
 			}
 			// TODO(marvin): Fix Node.EType type union.
-			op = Op(n.Etype)
+			op = ir.Op(n.Etype)
 		} else {
 			ok |= Erv
+			typecheckchain(n, Erv|top&Eiota)
 			n.Left = typecheck(n.Left, Erv|top&Eiota)
 			n.Right = typecheck(n.Right, Erv|top&Eiota)
 			l = n.Left
@@ -541,8 +620,13 @@ OpSwitch:
 			}
 			op = n.Op
 		}
-		if op == OLSH || op == ORSH {
-			r = defaultlit(r, Types[TUINT])
+		if op == ir.OLSH || op == ir.ORSH {
+			if !checkshiftcount(n, l, r) {
+				n.Diag = 1
+				n.Type = nil
+				return n
+			}
+			r = defaultlit(r, ir.Types[ir.TUINT])
 			n.Right = r
 			t := r.Type
 			if !Isint[t.Etype] || Issigned[t.Etype] {
@@ -552,7 +636,7 @@ OpSwitch:
 			}
 
 			t = l.Type
-			if t != nil && t.Etype != TIDEAL && !Isint[t.Etype] {
+			if t != nil && t.Etype != ir.TIDEAL && !Isint[t.Etype] {
 				Yyerror("invalid operation: %v (shift of type %v)", n, t)
 				n.Type = nil
 				return n
@@ -575,15 +659,15 @@ OpSwitch:
 			return n
 		}
 		t := l.Type
-		if t.Etype == TIDEAL {
+		if t.Etype == ir.TIDEAL {
 			t = r.Type
 		}
 		et := t.Etype
-		if et == TIDEAL {
-			et = TINT
+		if et == ir.TIDEAL {
+			et = ir.TINT
 		}
-		var aop Op = OXXX
-		if iscmp[n.Op] && t.Etype != TIDEAL && !Eqtype(l.Type, r.Type) {
+		var aop ir.Op = ir.OXXX
+		if iscmp[n.Op] && t.Etype != ir.TIDEAL && !Eqtype(l.Type, r.Type) {
 			// comparison is okay as long as one side is
 			// assignable to the other.  convert so they have
 			// the same type.
@@ -591,7 +675,7 @@ OpSwitch:
 			// the only conversion that isn't a no-op is concrete == interface.
 			// in that case, check comparability of the concrete type.
 			// The conversion allocates, so only do it if the concrete type is huge.
-			if r.Type.Etype != TBLANK {
+			if r.Type.Etype != ir.TBLANK {
 				aop = assignop(l.Type, r.Type, nil)
 				if aop != 0 {
 					if Isinter(r.Type) && !Isinter(l.Type) && algtype1(l.Type, nil) == ANOEQ {
@@ -613,7 +697,7 @@ OpSwitch:
 				}
 			}
 
-			if l.Type.Etype != TBLANK {
+			if l.Type.Etype != ir.TBLANK {
 				aop = assignop(r.Type, l.Type, nil)
 				if aop != 0 {
 					if Isinter(l.Type) && !Isinter(r.Type) && algtype1(r.Type, nil) == ANOEQ {
@@ -638,7 +722,7 @@ OpSwitch:
 			et = t.Etype
 		}
 
-		if t.Etype != TIDEAL && !Eqtype(l.Type, r.Type) {
+		if t.Etype != ir.TIDEAL && !Eqtype(l.Type, r.Type) {
 			l, r = defaultlit2(l, r, true)
 			if Isinter(r.Type) == Isinter(l.Type) || aop == 0 {
 				Yyerror("invalid operation: %v (mismatched types %v and %v)", n, l.Type, r.Type)
@@ -667,20 +751,20 @@ OpSwitch:
 			return n
 		}
 
-		if l.Type.Etype == TMAP && !isnil(l) && !isnil(r) {
+		if l.Type.Etype == ir.TMAP && !isnil(l) && !isnil(r) {
 			Yyerror("invalid operation: %v (map can only be compared to nil)", n)
 			n.Type = nil
 			return n
 		}
 
-		if l.Type.Etype == TFUNC && !isnil(l) && !isnil(r) {
+		if l.Type.Etype == ir.TFUNC && !isnil(l) && !isnil(r) {
 			Yyerror("invalid operation: %v (func can only be compared to nil)", n)
 			n.Type = nil
 			return n
 		}
 
-		var badtype *Type
-		if l.Type.Etype == TSTRUCT && algtype1(l.Type, &badtype) == ANOEQ {
+		var badtype *ir.Type
+		if l.Type.Etype == ir.TSTRUCT && algtype1(l.Type, &badtype) == ANOEQ {
 			Yyerror("invalid operation: %v (struct containing %v cannot be compared)", n, badtype)
 			n.Type = nil
 			return n
@@ -689,29 +773,29 @@ OpSwitch:
 		t = l.Type
 		if iscmp[n.Op] {
 			evconst(n)
-			t = idealbool
-			if n.Op != OLITERAL {
+			t = ir.Idealbool
+			if n.Op != ir.OLITERAL {
 				l, r = defaultlit2(l, r, true)
 				n.Left = l
 				n.Right = r
 			}
 		}
 
-		if et == TSTRING {
+		if et == ir.TSTRING {
 			if iscmp[n.Op] {
 				// TODO(marvin): Fix Node.EType type union.
-				n.Etype = EType(n.Op)
-				n.Op = OCMPSTR
-			} else if n.Op == OADD {
+				n.Etype = ir.EType(n.Op)
+				n.Op = ir.OCMPSTR
+			} else if n.Op == ir.OADD {
 				// create OADDSTR node with list of strings in x + y + z + (w + v) + ...
-				n.Op = OADDSTR
+				n.Op = ir.OADDSTR
 
-				if l.Op == OADDSTR {
+				if l.Op == ir.OADDSTR {
 					n.List.Set(l.List.Slice())
 				} else {
 					n.List.Set1(l)
 				}
-				if r.Op == OADDSTR {
+				if r.Op == ir.OADDSTR {
 					n.List.AppendNodes(&r.List)
 				} else {
 					n.List.Append(r)
@@ -721,24 +805,25 @@ OpSwitch:
 			}
 		}
 
-		if et == TINTER {
-			if l.Op == OLITERAL && l.Val().Ctype() == CTNIL {
+		if et == ir.TINTER {
+			if l.Op == ir.OLITERAL && l.Val().Ctype() == ir.CTNIL {
 				// swap for back end
 				n.Left = r
 
 				n.Right = l
-			} else if r.Op == OLITERAL && r.Val().Ctype() == CTNIL {
+			} else if r.Op == ir.OLITERAL && r.Val().Ctype() == ir.CTNIL {
 			} else // leave alone for back end
 			if Isinter(r.Type) == Isinter(l.Type) {
 				// TODO(marvin): Fix Node.EType type union.
-				n.Etype = EType(n.Op)
-				n.Op = OCMPIFACE
+				n.Etype = ir.EType(n.Op)
+				n.Op = ir.OCMPIFACE
 			}
 		}
 
-		if (op == ODIV || op == OMOD) && Isconst(r, CTINT) {
-			if r.Val().U.(*Mpint).CmpInt64(0) == 0 {
-				Yyerror("division by zero")
+		if (op == ir.ODIV || op == ir.OMOD) && ir.Isconst(r, ir.CTINT) {
+			if r.Val().U.(*ir.Mpint).CmpInt64(0) == 0 {
+				Yyerror("division by zero in %v", n)
+				n.Diag = 1
 				n.Type = nil
 				return n
 			}
@@ -747,7 +832,7 @@ OpSwitch:
 		n.Type = t
 		break OpSwitch
 
-	case OCOM, OMINUS, ONOT, OPLUS:
+	case ir.OCOM, ir.OMINUS, ir.ONOT, ir.OPLUS:
 		ok |= Erv
 		n.Left = typecheck(n.Left, Erv|top&Eiota)
 		l := n.Left
@@ -766,7 +851,7 @@ OpSwitch:
 		break OpSwitch
 
 	// exprs
-	case OADDR:
+	case ir.OADDR:
 		ok |= Erv
 
 		n.Left = typecheck(n.Left, Erv|Eaddr)
@@ -776,21 +861,15 @@ OpSwitch:
 		}
 		checklvalue(n.Left, "take the address of")
 		r := outervalue(n.Left)
-		var l *Node
+		var l *ir.Node
 		for l = n.Left; l != r; l = l.Left {
-			l.Addrtaken = true
-			if l.Name != nil && l.Name.Param != nil && l.Name.Param.Closure != nil {
-				l.Name.Param.Closure.Addrtaken = true
-			}
+			setaddrtaken(l)
 		}
 
-		if l.Orig != l && l.Op == ONAME {
+		if l.Orig != l && l.Op == ir.ONAME {
 			Fatalf("found non-orig name node %v", l)
 		}
-		l.Addrtaken = true
-		if l.Name != nil && l.Name.Param != nil && l.Name.Param.Closure != nil {
-			l.Name.Param.Closure.Addrtaken = true
-		}
+		setaddrtaken(l)
 		n.Left = defaultlit(n.Left, nil)
 		l = n.Left
 		t := l.Type
@@ -801,7 +880,7 @@ OpSwitch:
 		n.Type = Ptrto(t)
 		break OpSwitch
 
-	case OCOMPLIT:
+	case ir.OCOMPLIT:
 		ok |= Erv
 		n = typecheckcomplit(n)
 		if n.Type == nil {
@@ -810,10 +889,10 @@ OpSwitch:
 		}
 		break OpSwitch
 
-	case OXDOT, ODOT:
-		if n.Op == OXDOT {
+	case ir.OXDOT, ir.ODOT:
+		if n.Op == ir.OXDOT {
 			n = adddot(n)
-			n.Op = ODOT
+			n.Op = ir.ODOT
 			if n.Left == nil {
 				n.Type = nil
 				return n
@@ -833,7 +912,7 @@ OpSwitch:
 
 		s := n.Sym
 
-		if n.Left.Op == OTYPE {
+		if n.Left.Op == ir.OTYPE {
 			if !looktypedot(n, t, 0) {
 				if looktypedot(n, t, 1) {
 					Yyerror("%v undefined (cannot refer to unexported method %v)", n, n.Sym)
@@ -844,32 +923,32 @@ OpSwitch:
 				return n
 			}
 
-			if n.Type.Etype != TFUNC || n.Type.Recv() == nil {
-				Yyerror("type %v has no method %v", n.Left.Type, Sconv(n.Right.Sym, FmtShort))
+			if n.Type.Etype != ir.TFUNC || n.Type.Recv() == nil {
+				Yyerror("type %v has no method %v", n.Left.Type, Sconv(n.Right.Sym, ir.FmtShort))
 				n.Type = nil
 				n.Type = nil
 				return n
 			}
 
-			n.Op = ONAME
+			n.Op = ir.ONAME
 			if n.Name == nil {
-				n.Name = new(Name)
+				n.Name = new(ir.Name)
 			}
 			n.Right = newname(n.Sym)
 			n.Type = methodfunc(n.Type, n.Left.Type)
 			n.Xoffset = 0
-			n.Class = PFUNC
+			n.Class = ir.PFUNC
 			ok = Erv
 			break OpSwitch
 		}
 
-		if Isptr[t.Etype] && t.Type.Etype != TINTER {
+		if Isptr[t.Etype] && t.Type.Etype != ir.TINTER {
 			t = t.Type
 			if t == nil {
 				n.Type = nil
 				return n
 			}
-			n.Op = ODOTPTR
+			n.Op = ir.ODOTPTR
 			checkwidth(t)
 		}
 
@@ -905,7 +984,7 @@ OpSwitch:
 		}
 
 		switch n.Op {
-		case ODOTINTER, ODOTMETH:
+		case ir.ODOTINTER, ir.ODOTMETH:
 			if top&Ecall != 0 {
 				ok |= Ecall
 			} else {
@@ -919,7 +998,7 @@ OpSwitch:
 
 		break OpSwitch
 
-	case ODOTTYPE:
+	case ir.ODOTTYPE:
 		ok |= Erv
 		n.Left = typecheck(n.Left, Erv)
 		n.Left = defaultlit(n.Left, nil)
@@ -944,19 +1023,11 @@ OpSwitch:
 			}
 		}
 
-		if n.Type != nil && n.Type.Etype != TINTER {
-			var missing, have *Field
+		if n.Type != nil && n.Type.Etype != ir.TINTER {
+			var missing, have *ir.Field
 			var ptr int
 			if !implements(n.Type, t, &missing, &have, &ptr) {
-				if have != nil && have.Sym == missing.Sym {
-					Yyerror("impossible type assertion:\n\t%v does not implement %v (wrong type for %v method)\n"+"\t\thave %v%v\n\t\twant %v%v", n.Type, t, missing.Sym, have.Sym, Tconv(have.Type, FmtShort|FmtByte), missing.Sym, Tconv(missing.Type, FmtShort|FmtByte))
-				} else if ptr != 0 {
-					Yyerror("impossible type assertion:\n\t%v does not implement %v (%v method has pointer receiver)", n.Type, t, missing.Sym)
-				} else if have != nil {
-					Yyerror("impossible type assertion:\n\t%v does not implement %v (missing %v method)\n"+"\t\thave %v%v\n\t\twant %v%v", n.Type, t, missing.Sym, have.Sym, Tconv(have.Type, FmtShort|FmtByte), missing.Sym, Tconv(missing.Type, FmtShort|FmtByte))
-				} else {
-					Yyerror("impossible type assertion:\n\t%v does not implement %v (missing %v method)", n.Type, t, missing.Sym)
-				}
+				Yyerror("impossible type assertion:\n\t%v does not implement %v %s", n.Type, t, missingmethod(missing, have, ptr))
 				n.Type = nil
 				return n
 			}
@@ -964,7 +1035,7 @@ OpSwitch:
 
 		break OpSwitch
 
-	case OINDEX:
+	case ir.OINDEX:
 		ok |= Erv
 		n.Left = typecheck(n.Left, Erv)
 		n.Left = defaultlit(n.Left, nil)
@@ -983,15 +1054,15 @@ OpSwitch:
 			n.Type = nil
 			return n
 
-		case TSTRING, TARRAY:
+		case ir.TSTRING, ir.TARRAY:
 			n.Right = indexlit(n.Right)
-			if t.Etype == TSTRING {
-				n.Type = bytetype
+			if t.Etype == ir.TSTRING {
+				n.Type = ir.Bytetype
 			} else {
 				n.Type = t.Type
 			}
 			why := "string"
-			if t.Etype == TARRAY {
+			if t.Etype == ir.TARRAY {
 				if Isfixedarray(t) {
 					why = "array"
 				} else {
@@ -1004,32 +1075,32 @@ OpSwitch:
 				break
 			}
 
-			if !n.Bounded && Isconst(n.Right, CTINT) {
-				x := n.Right.Val().U.(*Mpint).Int64()
+			if !n.Bounded && ir.Isconst(n.Right, ir.CTINT) {
+				x := n.Right.Val().U.(*ir.Mpint).Int64()
 				if x < 0 {
 					Yyerror("invalid %s index %v (index must be non-negative)", why, n.Right)
 				} else if Isfixedarray(t) && x >= t.Bound {
 					Yyerror("invalid array index %v (out of bounds for %d-element array)", n.Right, t.Bound)
-				} else if Isconst(n.Left, CTSTR) && x >= int64(len(n.Left.Val().U.(string))) {
+				} else if ir.Isconst(n.Left, ir.CTSTR) && x >= int64(len(n.Left.Val().U.(string))) {
 					Yyerror("invalid string index %v (out of bounds for %d-byte string)", n.Right, len(n.Left.Val().U.(string)))
-				} else if n.Right.Val().U.(*Mpint).Cmp(Maxintval[TINT]) > 0 {
+				} else if n.Right.Val().U.(*ir.Mpint).Cmp(Maxintval[ir.TINT]) > 0 {
 					Yyerror("invalid %s index %v (index too large)", why, n.Right)
 				}
 			}
 
-		case TMAP:
+		case ir.TMAP:
 			n.Etype = 0
 			n.Right = defaultlit(n.Right, t.Key())
 			if n.Right.Type != nil {
 				n.Right = assignconv(n.Right, t.Key(), "map index")
 			}
 			n.Type = t.Type
-			n.Op = OINDEXMAP
+			n.Op = ir.OINDEXMAP
 		}
 
 		break OpSwitch
 
-	case ORECV:
+	case ir.ORECV:
 		ok |= Etop | Erv
 		n.Left = typecheck(n.Left, Erv)
 		n.Left = defaultlit(n.Left, nil)
@@ -1039,7 +1110,7 @@ OpSwitch:
 			n.Type = nil
 			return n
 		}
-		if t.Etype != TCHAN {
+		if t.Etype != ir.TCHAN {
 			Yyerror("invalid operation: %v (receive from non-chan type %v)", n, t)
 			n.Type = nil
 			return n
@@ -1054,7 +1125,7 @@ OpSwitch:
 		n.Type = t.Type
 		break OpSwitch
 
-	case OSEND:
+	case ir.OSEND:
 		ok |= Etop
 		n.Left = typecheck(n.Left, Erv)
 		l := n.Left
@@ -1066,7 +1137,7 @@ OpSwitch:
 			n.Type = nil
 			return n
 		}
-		if t.Etype != TCHAN {
+		if t.Etype != ir.TCHAN {
 			Yyerror("invalid operation: %v (send to non-chan type %v)", n, t)
 			n.Type = nil
 			return n
@@ -1092,7 +1163,7 @@ OpSwitch:
 		n.Type = nil
 		break OpSwitch
 
-	case OSLICE:
+	case ir.OSLICE:
 		ok |= Erv
 		n.Left = typecheck(n.Left, top)
 		n.Right.Left = typecheck(n.Right.Left, Erv)
@@ -1108,7 +1179,7 @@ OpSwitch:
 				return n
 			}
 
-			n.Left = Nod(OADDR, n.Left, nil)
+			n.Left = Nod(ir.OADDR, n.Left, nil)
 			n.Left.Implicit = true
 			n.Left = typecheck(n.Left, Erv)
 			l = n.Left
@@ -1119,17 +1190,17 @@ OpSwitch:
 			n.Type = nil
 			return n
 		}
-		var tp *Type
-		if Istype(t, TSTRING) {
+		var tp *ir.Type
+		if Istype(t, ir.TSTRING) {
 			n.Type = t
-			n.Op = OSLICESTR
+			n.Op = ir.OSLICESTR
 		} else if Isptr[t.Etype] && Isfixedarray(t.Type) {
 			tp = t.Type
-			n.Type = typ(TARRAY)
+			n.Type = ir.Typ(ir.TARRAY)
 			n.Type.Type = tp.Type
 			n.Type.Bound = -1
 			dowidth(n.Type)
-			n.Op = OSLICEARR
+			n.Op = ir.OSLICEARR
 		} else if Isslice(t) {
 			n.Type = t
 		} else {
@@ -1154,7 +1225,7 @@ OpSwitch:
 		}
 		break OpSwitch
 
-	case OSLICE3:
+	case ir.OSLICE3:
 		ok |= Erv
 		n.Left = typecheck(n.Left, top)
 		n.Right.Left = typecheck(n.Right.Left, Erv)
@@ -1172,7 +1243,7 @@ OpSwitch:
 				return n
 			}
 
-			n.Left = Nod(OADDR, n.Left, nil)
+			n.Left = Nod(ir.OADDR, n.Left, nil)
 			n.Left.Implicit = true
 			n.Left = typecheck(n.Left, Erv)
 			l = n.Left
@@ -1183,20 +1254,20 @@ OpSwitch:
 			n.Type = nil
 			return n
 		}
-		if Istype(t, TSTRING) {
+		if Istype(t, ir.TSTRING) {
 			Yyerror("invalid operation %v (3-index slice of string)", n)
 			n.Type = nil
 			return n
 		}
 
-		var tp *Type
+		var tp *ir.Type
 		if Isptr[t.Etype] && Isfixedarray(t.Type) {
 			tp = t.Type
-			n.Type = typ(TARRAY)
+			n.Type = ir.Typ(ir.TARRAY)
 			n.Type.Type = tp.Type
 			n.Type.Bound = -1
 			dowidth(n.Type)
-			n.Op = OSLICE3ARR
+			n.Op = ir.OSLICE3ARR
 		} else if Isslice(t) {
 			n.Type = t
 		} else {
@@ -1227,10 +1298,10 @@ OpSwitch:
 		break OpSwitch
 
 	// call and call like
-	case OCALL:
+	case ir.OCALL:
 		l := n.Left
 
-		if l.Op == ONAME {
+		if l.Op == ir.ONAME {
 			r := unsafenmagic(n)
 			if r != nil {
 				if n.Isddd {
@@ -1245,15 +1316,15 @@ OpSwitch:
 		n.Left = typecheck(n.Left, Erv|Etype|Ecall|top&Eproc)
 		n.Diag |= n.Left.Diag
 		l = n.Left
-		if l.Op == ONAME && l.Etype != 0 {
+		if l.Op == ir.ONAME && l.Etype != 0 {
 			// TODO(marvin): Fix Node.EType type union.
-			if n.Isddd && Op(l.Etype) != OAPPEND {
+			if n.Isddd && ir.Op(l.Etype) != ir.OAPPEND {
 				Yyerror("invalid use of ... with builtin %v", l)
 			}
 
 			// builtin: OLEN, OCAP, etc.
 			// TODO(marvin): Fix Node.EType type union.
-			n.Op = Op(l.Etype)
+			n.Op = ir.Op(l.Etype)
 
 			n.Left = n.Right
 			n.Right = nil
@@ -1263,7 +1334,7 @@ OpSwitch:
 
 		n.Left = defaultlit(n.Left, nil)
 		l = n.Left
-		if l.Op == OTYPE {
+		if l.Op == ir.OTYPE {
 			if n.Isddd || l.Type.Bound == -100 {
 				if !l.Type.Broke {
 					Yyerror("invalid use of ... in type conversion to %v", l.Type)
@@ -1277,7 +1348,7 @@ OpSwitch:
 			// turn CALL(type, arg) into CONV(arg) w/ type
 			n.Left = nil
 
-			n.Op = OCONV
+			n.Op = ir.OCONV
 			n.Type = l.Type
 			if !onearg(n, "conversion to %v", l.Type) {
 				n.Type = nil
@@ -1300,11 +1371,11 @@ OpSwitch:
 		checkwidth(t)
 
 		switch l.Op {
-		case ODOTINTER:
-			n.Op = OCALLINTER
+		case ir.ODOTINTER:
+			n.Op = ir.OCALLINTER
 
-		case ODOTMETH:
-			n.Op = OCALLMETH
+		case ir.ODOTMETH:
+			n.Op = ir.OCALLMETH
 
 			// typecheckaste was used here but there wasn't enough
 			// information further down the call chain to know if we
@@ -1317,15 +1388,15 @@ OpSwitch:
 			}
 
 		default:
-			n.Op = OCALLFUNC
-			if t.Etype != TFUNC {
+			n.Op = ir.OCALLFUNC
+			if t.Etype != ir.TFUNC {
 				Yyerror("cannot call non-function %v (type %v)", l, t)
 				n.Type = nil
 				return n
 			}
 		}
 
-		typecheckaste(OCALL, n.Left, n.Isddd, t.Params(), n.List, func() string { return fmt.Sprintf("argument to %v", n.Left) })
+		typecheckaste(ir.OCALL, n.Left, n.Isddd, t.Params(), n.List, func() string { return fmt.Sprintf("argument to %v", n.Left) })
 		ok |= Etop
 		if t.Results().NumFields() == 0 {
 			break OpSwitch
@@ -1334,14 +1405,14 @@ OpSwitch:
 		if t.Results().NumFields() == 1 {
 			n.Type = l.Type.Results().Field(0).Type
 
-			if n.Op == OCALLFUNC && n.Left.Op == ONAME && (compiling_runtime != 0 || n.Left.Sym.Pkg == Runtimepkg) && n.Left.Sym.Name == "getg" {
+			if n.Op == ir.OCALLFUNC && n.Left.Op == ir.ONAME && (compiling_runtime != 0 || n.Left.Sym.Pkg == Runtimepkg) && n.Left.Sym.Name == "getg" {
 				// Emit code for runtime.getg() directly instead of calling function.
 				// Most such rewrites (for example the similar one for math.Sqrt) should be done in walk,
 				// so that the ordering pass can make sure to preserve the semantics of the original code
 				// (in particular, the exact time of the function call) by introducing temporaries.
 				// In this case, we know getg() always returns the same result within a given function
 				// and we want to avoid the temporaries, so we do the rewrite earlier than is typical.
-				n.Op = OGETG
+				n.Op = ir.OGETG
 			}
 
 			break OpSwitch
@@ -1357,7 +1428,7 @@ OpSwitch:
 
 		break OpSwitch
 
-	case OCAP, OLEN, OREAL, OIMAG:
+	case ir.OCAP, ir.OLEN, ir.OREAL, ir.OIMAG:
 		ok |= Erv
 		if !onearg(n, "%v", Oconv(n.Op, 0)) {
 			n.Type = nil
@@ -1373,72 +1444,76 @@ OpSwitch:
 			return n
 		}
 		switch n.Op {
-		case OCAP:
+		case ir.OCAP:
 			if !okforcap[t.Etype] {
 				goto badcall1
 			}
 
-		case OLEN:
+		case ir.OLEN:
 			if !okforlen[t.Etype] {
 				goto badcall1
 			}
 
-		case OREAL, OIMAG:
+		case ir.OREAL, ir.OIMAG:
 			if !Iscomplex[t.Etype] {
 				goto badcall1
 			}
-			if Isconst(l, CTCPLX) {
+			if ir.Isconst(l, ir.CTCPLX) {
 				r := n
-				if n.Op == OREAL {
-					n = nodfltconst(&l.Val().U.(*Mpcplx).Real)
+				if n.Op == ir.OREAL {
+					n = nodfltconst(&l.Val().U.(*ir.Mpcplx).Real)
 				} else {
-					n = nodfltconst(&l.Val().U.(*Mpcplx).Imag)
+					n = nodfltconst(&l.Val().U.(*ir.Mpcplx).Imag)
 				}
 				n.Orig = r
 			}
 
-			n.Type = Types[cplxsubtype(t.Etype)]
+			n.Type = ir.Types[cplxsubtype(t.Etype)]
 			break OpSwitch
 		}
 
-		// might be constant
+		// len(s) is constant if s is a constant string.
+		// len(a) and cap(a) are constant if a is an array or a pointer
+		// to an array (made an array by implicitstar) and a contains
+		// no function calls or channel receives, even if a is not
+		// itself constant.
 		switch t.Etype {
-		case TSTRING:
-			if Isconst(l, CTSTR) {
-				var r Node
-				Nodconst(&r, Types[TINT], int64(len(l.Val().U.(string))))
+		case ir.TSTRING:
+			if ir.Isconst(l, ir.CTSTR) {
+				var r ir.Node
+				Nodconst(&r, ir.Types[ir.TINT], int64(len(l.Val().U.(string))))
 				r.Orig = n
 				n = &r
 			}
 
-		case TARRAY:
+		case ir.TARRAY:
 			if t.Bound < 0 { // slice
 				break
 			}
 			if callrecv(l) { // has call or receive
 				break
 			}
-			var r Node
-			Nodconst(&r, Types[TINT], t.Bound)
+			var r ir.Node
+			Nodconst(&r, ir.Types[ir.TINT], t.Bound)
 			r.Orig = n
 			n = &r
 		}
 
-		n.Type = Types[TINT]
+		n.Type = ir.Types[ir.TINT]
 		break OpSwitch
 
 	badcall1:
-		Yyerror("invalid argument %v for %v", Nconv(n.Left, FmtLong), Oconv(n.Op, 0))
+		Yyerror("invalid argument %v for %v", Nconv(n.Left, ir.FmtLong), Oconv(n.Op, 0))
 		n.Type = nil
 		return n
 
-	case OCOMPLEX:
+	case ir.OCOMPLEX:
 		ok |= Erv
-		var r *Node
-		var l *Node
+		var r *ir.Node
+		var l *ir.Node
 		if n.List.Len() == 1 {
 			typecheckslice(n.List.Slice(), Efnstruct)
-			if n.List.First().Op != OCALLFUNC && n.List.First().Op != OCALLMETH {
+			if n.List.First().Op != ir.OCALLFUNC && n.List.First().Op != ir.OCALLMETH {
 				Yyerror("invalid operation: complex expects two arguments")
 				n.Type = nil
 				return n
@@ -1482,24 +1557,24 @@ OpSwitch:
 			return n
 		}
 
-		var t *Type
+		var t *ir.Type
 		switch l.Type.Etype {
 		default:
 			Yyerror("invalid operation: %v (arguments have type %v, expected floating-point)", n, l.Type)
 			n.Type = nil
 			return n
 
-		case TIDEAL:
-			t = Types[TIDEAL]
+		case ir.TIDEAL:
+			t = ir.Types[ir.TIDEAL]
 
-		case TFLOAT32:
-			t = Types[TCOMPLEX64]
+		case ir.TFLOAT32:
+			t = ir.Types[ir.TCOMPLEX64]
 
-		case TFLOAT64:
-			t = Types[TCOMPLEX128]
+		case ir.TFLOAT64:
+			t = ir.Types[ir.TCOMPLEX128]
 		}
 
-		if l.Op == OLITERAL && r.Op == OLITERAL {
+		if l.Op == ir.OLITERAL && r.Op == ir.OLITERAL {
 			// make it a complex literal
 			r = nodcplxlit(l.Val(), r.Val())
 
@@ -1510,7 +1585,7 @@ OpSwitch:
 		n.Type = t
 		break OpSwitch
 
-	case OCLOSE:
+	case ir.OCLOSE:
 		if !onearg(n, "%v", Oconv(n.Op, 0)) {
 			n.Type = nil
 			return n
@@ -1523,7 +1598,7 @@ OpSwitch:
 			n.Type = nil
 			return n
 		}
-		if t.Etype != TCHAN {
+		if t.Etype != ir.TCHAN {
 			Yyerror("invalid operation: %v (non-chan type %v)", n, t)
 			n.Type = nil
 			return n
@@ -1538,7 +1613,7 @@ OpSwitch:
 		ok |= Etop
 		break OpSwitch
 
-	case ODELETE:
+	case ir.ODELETE:
 		args := n.List
 		if args.Len() == 0 {
 			Yyerror("missing arguments to delete")
@@ -1562,8 +1637,8 @@ OpSwitch:
 		typecheckslice(args.Slice(), Erv)
 		l := args.First()
 		r := args.Second()
-		if l.Type != nil && l.Type.Etype != TMAP {
-			Yyerror("first argument to delete must be map; have %v", Tconv(l.Type, FmtLong))
+		if l.Type != nil && l.Type.Etype != ir.TMAP {
+			Yyerror("first argument to delete must be map; have %v", Tconv(l.Type, ir.FmtLong))
 			n.Type = nil
 			return n
 		}
@@ -1571,7 +1646,7 @@ OpSwitch:
 		args.SetIndex(1, assignconv(r, l.Type.Key(), "delete"))
 		break OpSwitch
 
-	case OAPPEND:
+	case ir.OAPPEND:
 		ok |= Erv
 		args := n.List
 		if args.Len() == 0 {
@@ -1593,21 +1668,21 @@ OpSwitch:
 		}
 
 		// Unpack multiple-return result before type-checking.
-		var funarg *Type
-		if Istype(t, TSTRUCT) && t.Funarg {
+		var funarg *ir.Type
+		if Istype(t, ir.TSTRUCT) && t.Funarg {
 			funarg = t
 			t = t.Field(0).Type
 		}
 
 		n.Type = t
 		if !Isslice(t) {
-			if Isconst(args.First(), CTNIL) {
+			if ir.Isconst(args.First(), ir.CTNIL) {
 				Yyerror("first argument to append must be typed slice; have untyped nil")
 				n.Type = nil
 				return n
 			}
 
-			Yyerror("first argument to append must be slice; have %v", Tconv(t, FmtLong))
+			Yyerror("first argument to append must be slice; have %v", Tconv(t, ir.FmtLong))
 			n.Type = nil
 			return n
 		}
@@ -1625,8 +1700,8 @@ OpSwitch:
 				return n
 			}
 
-			if Istype(t.Type, TUINT8) && Istype(args.Second().Type, TSTRING) {
-				args.SetIndex(1, defaultlit(args.Index(1), Types[TSTRING]))
+			if Istype(t.Type, ir.TUINT8) && Istype(args.Second().Type, ir.TSTRING) {
+				args.SetIndex(1, defaultlit(args.Index(1), ir.Types[ir.TSTRING]))
 				break OpSwitch
 			}
 
@@ -1635,7 +1710,7 @@ OpSwitch:
 		}
 
 		if funarg != nil {
-			_, it := IterFields(funarg) // Skip first field
+			_, it := ir.IterFields(funarg) // Skip first field
 			for t := it.Next(); t != nil; t = it.Next() {
 				if assignop(t.Type, n.Type.Type, nil) == 0 {
 					Yyerror("cannot append %v value to []%v", t.Type, n.Type.Type)
@@ -1653,7 +1728,7 @@ OpSwitch:
 
 		break OpSwitch
 
-	case OCOPY:
+	case ir.OCOPY:
 		ok |= Etop | Erv
 		args := n.List
 		if args.Len() < 2 {
@@ -1671,7 +1746,7 @@ OpSwitch:
 		n.Left = args.First()
 		n.Right = args.Second()
 		n.List.Set(nil)
-		n.Type = Types[TINT]
+		n.Type = ir.Types[ir.TINT]
 		n.Left = typecheck(n.Left, Erv)
 		n.Right = typecheck(n.Right, Erv)
 		if n.Left.Type == nil || n.Right.Type == nil {
@@ -1686,40 +1761,40 @@ OpSwitch:
 		}
 
 		// copy([]byte, string)
-		if Isslice(n.Left.Type) && n.Right.Type.Etype == TSTRING {
-			if Eqtype(n.Left.Type.Type, bytetype) {
+		if Isslice(n.Left.Type) && n.Right.Type.Etype == ir.TSTRING {
+			if Eqtype(n.Left.Type.Type, ir.Bytetype) {
 				break OpSwitch
 			}
-			Yyerror("arguments to copy have different element types: %v and string", Tconv(n.Left.Type, FmtLong))
+			Yyerror("arguments to copy have different element types: %v and string", Tconv(n.Left.Type, ir.FmtLong))
 			n.Type = nil
 			return n
 		}
 
 		if !Isslice(n.Left.Type) || !Isslice(n.Right.Type) {
 			if !Isslice(n.Left.Type) && !Isslice(n.Right.Type) {
-				Yyerror("arguments to copy must be slices; have %v, %v", Tconv(n.Left.Type, FmtLong), Tconv(n.Right.Type, FmtLong))
+				Yyerror("arguments to copy must be slices; have %v, %v", Tconv(n.Left.Type, ir.FmtLong), Tconv(n.Right.Type, ir.FmtLong))
 			} else if !Isslice(n.Left.Type) {
-				Yyerror("first argument to copy should be slice; have %v", Tconv(n.Left.Type, FmtLong))
+				Yyerror("first argument to copy should be slice; have %v", Tconv(n.Left.Type, ir.FmtLong))
 			} else {
-				Yyerror("second argument to copy should be slice or string; have %v", Tconv(n.Right.Type, FmtLong))
+				Yyerror("second argument to copy should be slice or string; have %v", Tconv(n.Right.Type, ir.FmtLong))
 			}
 			n.Type = nil
 			return n
 		}
 
 		if !Eqtype(n.Left.Type.Type, n.Right.Type.Type) {
-			Yyerror("arguments to copy have different element types: %v and %v", Tconv(n.Left.Type, FmtLong), Tconv(n.Right.Type, FmtLong))
+			Yyerror("arguments to copy have different element types: %v and %v", Tconv(n.Left.Type, ir.FmtLong), Tconv(n.Right.Type, ir.FmtLong))
 			n.Type = nil
 			return n
 		}
 
 		break OpSwitch
 
-	case OCONV:
+	case ir.OCONV:
 		ok |= Erv
 		saveorignode(n)
 		n.Left = typecheck(n.Left, Erv|top&(Eindir|Eiota))
-		n.Left = convlit1(n.Left, n.Type, true)
+		n.Left = convlit1(n.Left, n.Type, true, nil)
 		t := n.Left.Type
 		if t == nil || n.Type == nil {
 			n.Type = nil
@@ -1729,38 +1804,38 @@ OpSwitch:
 		n.Op = convertop(t, n.Type, &why)
 		if n.Op == 0 {
 			if n.Diag == 0 && !n.Type.Broke {
-				Yyerror("cannot convert %v to type %v%s", Nconv(n.Left, FmtLong), n.Type, why)
+				Yyerror("cannot convert %v to type %v%s", Nconv(n.Left, ir.FmtLong), n.Type, why)
 				n.Diag = 1
 			}
 
-			n.Op = OCONV
+			n.Op = ir.OCONV
 		}
 
 		switch n.Op {
-		case OCONVNOP:
-			if n.Left.Op == OLITERAL {
-				r := Nod(OXXX, nil, nil)
-				n.Op = OCONV
+		case ir.OCONVNOP:
+			if n.Left.Op == ir.OLITERAL {
+				r := Nod(ir.OXXX, nil, nil)
+				n.Op = ir.OCONV
 				n.Orig = r
 				*r = *n
-				n.Op = OLITERAL
+				n.Op = ir.OLITERAL
 				n.SetVal(n.Left.Val())
 			}
 
 			// do not use stringtoarraylit.
 		// generated code and compiler memory footprint is better without it.
-		case OSTRARRAYBYTE:
+		case ir.OSTRARRAYBYTE:
 			break
 
-		case OSTRARRAYRUNE:
-			if n.Left.Op == OLITERAL {
+		case ir.OSTRARRAYRUNE:
+			if n.Left.Op == ir.OLITERAL {
 				n = stringtoarraylit(n)
 			}
 		}
 
 		break OpSwitch
 
-	case OMAKE:
+	case ir.OMAKE:
 		ok |= Erv
 		args := n.List.Slice()
 		if len(args) == 0 {
@@ -1785,7 +1860,7 @@ OpSwitch:
 			n.Type = nil
 			return n
 
-		case TARRAY:
+		case ir.TARRAY:
 			if !Isslice(t) {
 				Yyerror("cannot make type %v", t)
 				n.Type = nil
@@ -1801,7 +1876,7 @@ OpSwitch:
 			l = args[i]
 			i++
 			l = typecheck(l, Erv)
-			var r *Node
+			var r *ir.Node
 			if i < len(args) {
 				r = args[i]
 				i++
@@ -1816,7 +1891,7 @@ OpSwitch:
 				n.Type = nil
 				return n
 			}
-			if Isconst(l, CTINT) && r != nil && Isconst(r, CTINT) && l.Val().U.(*Mpint).Cmp(r.Val().U.(*Mpint)) > 0 {
+			if ir.Isconst(l, ir.CTINT) && r != nil && ir.Isconst(r, ir.CTINT) && l.Val().U.(*ir.Mpint).Cmp(r.Val().U.(*ir.Mpint)) > 0 {
 				Yyerror("len larger than cap in make(%v)", t)
 				n.Type = nil
 				return n
@@ -1824,14 +1899,14 @@ OpSwitch:
 
 			n.Left = l
 			n.Right = r
-			n.Op = OMAKESLICE
+			n.Op = ir.OMAKESLICE
 
-		case TMAP:
+		case ir.TMAP:
 			if i < len(args) {
 				l = args[i]
 				i++
 				l = typecheck(l, Erv)
-				l = defaultlit(l, Types[TINT])
+				l = defaultlit(l, ir.Types[ir.TINT])
 				if l.Type == nil {
 					n.Type = nil
 					return n
@@ -1844,15 +1919,15 @@ OpSwitch:
 			} else {
 				n.Left = Nodintconst(0)
 			}
-			n.Op = OMAKEMAP
+			n.Op = ir.OMAKEMAP
 
-		case TCHAN:
+		case ir.TCHAN:
 			l = nil
 			if i < len(args) {
 				l = args[i]
 				i++
 				l = typecheck(l, Erv)
-				l = defaultlit(l, Types[TINT])
+				l = defaultlit(l, ir.Types[ir.TINT])
 				if l.Type == nil {
 					n.Type = nil
 					return n
@@ -1865,12 +1940,12 @@ OpSwitch:
 			} else {
 				n.Left = Nodintconst(0)
 			}
-			n.Op = OMAKECHAN
+			n.Op = ir.OMAKECHAN
 		}
 
 		if i < len(args) {
 			Yyerror("too many arguments to make(%v)", t)
-			n.Op = OMAKE
+			n.Op = ir.OMAKE
 			n.Type = nil
 			return n
 		}
@@ -1878,7 +1953,7 @@ OpSwitch:
 		n.Type = t
 		break OpSwitch
 
-	case ONEW:
+	case ir.ONEW:
 		ok |= Erv
 		args := n.List
 		if args.Len() == 0 {
@@ -1904,14 +1979,14 @@ OpSwitch:
 		n.Type = Ptrto(t)
 		break OpSwitch
 
-	case OPRINT, OPRINTN:
+	case ir.OPRINT, ir.OPRINTN:
 		ok |= Etop
 		typecheckslice(n.List.Slice(), Erv|Eindir) // Eindir: address does not escape
 		ls := n.List.Slice()
 		for i1, n1 := range ls {
 			// Special case for print: int constant is int64, not int.
-			if Isconst(n1, CTINT) {
-				ls[i1] = defaultlit(ls[i1], Types[TINT64])
+			if ir.Isconst(n1, ir.CTINT) {
+				ls[i1] = defaultlit(ls[i1], ir.Types[ir.TINT64])
 			} else {
 				ls[i1] = defaultlit(ls[i1], nil)
 			}
@@ -1919,21 +1994,21 @@ OpSwitch:
 
 		break OpSwitch
 
-	case OPANIC:
+	case ir.OPANIC:
 		ok |= Etop
 		if !onearg(n, "panic") {
 			n.Type = nil
 			return n
 		}
 		n.Left = typecheck(n.Left, Erv)
-		n.Left = defaultlit(n.Left, Types[TINTER])
+		n.Left = defaultlit(n.Left, ir.Types[ir.TINTER])
 		if n.Left.Type == nil {
 			n.Type = nil
 			return n
 		}
 		break OpSwitch
 
-	case ORECOVER:
+	case ir.ORECOVER:
 		ok |= Erv | Etop
 		if n.List.Len() != 0 {
 			Yyerror("too many arguments to recover")
@@ -1941,10 +2016,10 @@ OpSwitch:
 			return n
 		}
 
-		n.Type = Types[TINTER]
+		n.Type = ir.Types[ir.TINTER]
 		break OpSwitch
 
-	case OCLOSURE:
+	case ir.OCLOSURE:
 		ok |= Erv
 		typecheckclosure(n, top)
 		if n.Type == nil {
@@ -1953,7 +2028,7 @@ OpSwitch:
 		}
 		break OpSwitch
 
-	case OITAB:
+	case ir.OITAB:
 		ok |= Erv
 		n.Left = typecheck(n.Left, Erv)
 		t := n.Left.Type
@@ -1961,13 +2036,13 @@ OpSwitch:
 			n.Type = nil
 			return n
 		}
-		if t.Etype != TINTER {
+		if t.Etype != ir.TINTER {
 			Fatalf("OITAB of %v", t)
 		}
-		n.Type = Ptrto(Types[TUINTPTR])
+		n.Type = Ptrto(ir.Types[ir.TUINTPTR])
 		break OpSwitch
 
-	case OSPTR:
+	case ir.OSPTR:
 		ok |= Erv
 		n.Left = typecheck(n.Left, Erv)
 		t := n.Left.Type
@@ -1975,65 +2050,66 @@ OpSwitch:
 			n.Type = nil
 			return n
 		}
-		if !Isslice(t) && t.Etype != TSTRING {
+		if !Isslice(t) && t.Etype != ir.TSTRING {
 			Fatalf("OSPTR of %v", t)
 		}
-		if t.Etype == TSTRING {
-			n.Type = Ptrto(Types[TUINT8])
+		if t.Etype == ir.TSTRING {
+			n.Type = Ptrto(ir.Types[ir.TUINT8])
 		} else {
 			n.Type = Ptrto(t.Type)
 		}
 		break OpSwitch
 
-	case OCLOSUREVAR:
+	case ir.OCLOSUREVAR:
 		ok |= Erv
 		break OpSwitch
 
-	case OCFUNC:
+	case ir.OCFUNC:
 		ok |= Erv
 		n.Left = typecheck(n.Left, Erv)
-		n.Type = Types[TUINTPTR]
+		n.Type = ir.Types[ir.TUINTPTR]
 		break OpSwitch
 
-	case OCONVNOP:
+	case ir.OCONVNOP:
 		ok |= Erv
 		n.Left = typecheck(n.Left, Erv)
 		break OpSwitch
 
 	// statements
-	case OAS:
+	case ir.OAS:
 		ok |= Etop
 
 		typecheckas(n)
 
 		// Code that creates temps does not bother to set defn, so do it here.
-		if n.Left.Op == ONAME && strings.HasPrefix(n.Left.Sym.Name, "autotmp_") {
+		if n.Left.Op == ir.ONAME && strings.HasPrefix(n.Left.Sym.Name, "autotmp_") {
 			n.Left.Name.Defn = n
 		}
 		break OpSwitch
 
-	case OAS2:
+	case ir.OAS2:
 		ok |= Etop
 		typecheckas2(n)
 		break OpSwitch
 
-	case OBREAK,
-		OCONTINUE,
-		ODCL,
-		OEMPTY,
-		OGOTO,
-		OXFALL,
-		OVARKILL,
-		OVARLIVE:
+	case ir.OBREAK,
+		ir.OCONTINUE,
+		ir.ODCL,
+		ir.OEMPTY,
+		ir.OGOTO,
+		ir.OXFALL,
+		ir.OVARKILL,
+		ir.OVARLIVE:
 		ok |= Etop
 		break OpSwitch
 
-	case OLABEL:
+	case ir.OLABEL:
 		ok |= Etop
+		markloop()
 		decldepth++
 		break OpSwitch
 
-	case ODEFER:
+	case ir.ODEFER:
 		ok |= Etop
 		n.Left = typecheck(n.Left, Etop|Erv)
 		if n.Left.Diag == 0 {
@@ -2041,21 +2117,22 @@ OpSwitch:
 		}
 		break OpSwitch
 
-	case OPROC:
+	case ir.OPROC:
 		ok |= Etop
 		n.Left = typecheck(n.Left, Etop|Eproc|Erv)
 		checkdefergo(n)
 		break OpSwitch
 
-	case OFOR:
+	case ir.OFOR:
 		ok |= Etop
 		typecheckslice(n.Ninit.Slice(), Etop)
+		markloop()
 		decldepth++
 		n.Left = typecheck(n.Left, Erv)
 		if n.Left != nil {
 			t := n.Left.Type
-			if t != nil && t.Etype != TBOOL {
-				Yyerror("non-bool %v used as for condition", Nconv(n.Left, FmtLong))
+			if t != nil && t.Etype != ir.TBOOL {
+				Yyerror("non-bool %v used as for condition", Nconv(n.Left, ir.FmtLong))
 			}
 		}
 		n.Right = typecheck(n.Right, Etop)
@@ -2063,21 +2140,21 @@ OpSwitch:
 		decldepth--
 		break OpSwitch
 
-	case OIF:
+	case ir.OIF:
 		ok |= Etop
 		typecheckslice(n.Ninit.Slice(), Etop)
 		n.Left = typecheck(n.Left, Erv)
 		if n.Left != nil {
 			t := n.Left.Type
-			if t != nil && t.Etype != TBOOL {
-				Yyerror("non-bool %v used as if condition", Nconv(n.Left, FmtLong))
+			if t != nil && t.Etype != ir.TBOOL {
+				Yyerror("non-bool %v used as if condition", Nconv(n.Left, ir.FmtLong))
 			}
 		}
 		typecheckslice(n.Nbody.Slice(), Etop)
 		typecheckslice(n.Rlist.Slice(), Etop)
 		break OpSwitch
 
-	case ORETURN:
+	case ir.ORETURN:
 		ok |= Etop
 		if n.List.Len() == 1 {
 			typecheckslice(n.List.Slice(), Erv|Efnstruct)
@@ -2093,50 +2170,50 @@ OpSwitch:
 		if Curfn.Type.Outnamed && n.List.Len() == 0 {
 			break OpSwitch
 		}
-		typecheckaste(ORETURN, nil, false, Curfn.Type.Results(), n.List, func() string { return "return argument" })
+		typecheckaste(ir.ORETURN, nil, false, Curfn.Type.Results(), n.List, func() string { return "return argument" })
 		break OpSwitch
 
-	case ORETJMP:
+	case ir.ORETJMP:
 		ok |= Etop
 		break OpSwitch
 
-	case OSELECT:
+	case ir.OSELECT:
 		ok |= Etop
 		typecheckselect(n)
 		break OpSwitch
 
-	case OSWITCH:
+	case ir.OSWITCH:
 		ok |= Etop
 		typecheckswitch(n)
 		break OpSwitch
 
-	case ORANGE:
+	case ir.ORANGE:
 		ok |= Etop
 		typecheckrange(n)
 		break OpSwitch
 
-	case OTYPESW:
+	case ir.OTYPESW:
 		Yyerror("use of .(type) outside type switch")
 		n.Type = nil
 		return n
 
-	case OXCASE:
+	case ir.OXCASE:
 		ok |= Etop
 		typecheckslice(n.List.Slice(), Erv)
 		typecheckslice(n.Nbody.Slice(), Etop)
 		break OpSwitch
 
-	case ODCLFUNC:
+	case ir.ODCLFUNC:
 		ok |= Etop
 		typecheckfunc(n)
 		break OpSwitch
 
-	case ODCLCONST:
+	case ir.ODCLCONST:
 		ok |= Etop
 		n.Left = typecheck(n.Left, Erv)
 		break OpSwitch
 
-	case ODCLTYPE:
+	case ir.ODCLTYPE:
 		ok |= Etop
 		n.Left = typecheck(n.Left, Etype)
 		if incannedimport == 0 {
@@ -2146,14 +2223,14 @@ OpSwitch:
 	}
 
 	t := n.Type
-	if t != nil && !t.Funarg && n.Op != OTYPE {
+	if t != nil && !t.Funarg && n.Op != ir.OTYPE {
 		switch t.Etype {
-		case TFUNC, // might have TANY; wait until its called
-			TANY,
-			TFORW,
-			TIDEAL,
-			TNIL,
-			TBLANK:
+		case ir.TFUNC, // might have TANY; wait until its called
+			ir.TANY,
+			ir.TFORW,
+			ir.TIDEAL,
+			ir.TNIL,
+			ir.TBLANK:
 			break
 
 		default:
@@ -2161,18 +2238,18 @@ OpSwitch:
 		}
 	}
 
-	if safemode != 0 && incannedimport == 0 && importpkg == nil && compiling_wrappers == 0 && t != nil && t.Etype == TUNSAFEPTR {
+	if safemode != 0 && incannedimport == 0 && importpkg == nil && compiling_wrappers == 0 && t != nil && t.Etype == ir.TUNSAFEPTR {
 		Yyerror("cannot use unsafe.Pointer")
 	}
 
 	evconst(n)
-	if n.Op == OTYPE && top&Etype == 0 {
+	if n.Op == ir.OTYPE && top&Etype == 0 {
 		Yyerror("type %v is not an expression", n.Type)
 		n.Type = nil
 		return n
 	}
 
-	if top&(Erv|Etype) == Etype && n.Op != OTYPE {
+	if top&(Erv|Etype) == Etype && n.Op != ir.OTYPE {
 		Yyerror("%v is not a type", n)
 		n.Type = nil
 		return n
@@ -2202,7 +2279,7 @@ OpSwitch:
 	return n
 }
 
-func checksliceindex(l *Node, r *Node, tp *Type) bool {
+func checksliceindex(l *ir.Node, r *ir.Node, tp *ir.Type) bool {
 	t := r.Type
 	if t == nil {
 		return false
@@ -2212,17 +2289,17 @@ func checksliceindex(l *Node, r *Node, tp *Type) bool {
 		return false
 	}
 
-	if r.Op == OLITERAL {
-		if r.Val().U.(*Mpint).Int64() < 0 {
+	if r.Op == ir.OLITERAL {
+		if r.Val().U.(*ir.Mpint).Int64() < 0 {
 			Yyerror("invalid slice index %v (index must be non-negative)", r)
 			return false
-		} else if tp != nil && tp.Bound > 0 && r.Val().U.(*Mpint).Int64() > tp.Bound {
+		} else if tp != nil && tp.Bound > 0 && r.Val().U.(*ir.Mpint).Int64() > tp.Bound {
 			Yyerror("invalid slice index %v (out of bounds for %d-element array)", r, tp.Bound)
 			return false
-		} else if Isconst(l, CTSTR) && r.Val().U.(*Mpint).Int64() > int64(len(l.Val().U.(string))) {
+		} else if ir.Isconst(l, ir.CTSTR) && r.Val().U.(*ir.Mpint).Int64() > int64(len(l.Val().U.(string))) {
 			Yyerror("invalid slice index %v (out of bounds for %d-byte string)", r, len(l.Val().U.(string)))
 			return false
-		} else if r.Val().U.(*Mpint).Cmp(Maxintval[TINT]) > 0 {
+		} else if r.Val().U.(*ir.Mpint).Cmp(Maxintval[ir.TINT]) > 0 {
 			Yyerror("invalid slice index %v (index too large)", r)
 			return false
 		}
@@ -2231,8 +2308,8 @@ func checksliceindex(l *Node, r *Node, tp *Type) bool {
 	return true
 }
 
-func checksliceconst(lo *Node, hi *Node) bool {
-	if lo != nil && hi != nil && lo.Op == OLITERAL && hi.Op == OLITERAL && lo.Val().U.(*Mpint).Cmp(hi.Val().U.(*Mpint)) > 0 {
+func checksliceconst(lo *ir.Node, hi *ir.Node) bool {
+	if lo != nil && hi != nil && lo.Op == ir.OLITERAL && hi.Op == ir.OLITERAL && lo.Val().U.(*ir.Mpint).Cmp(hi.Val().U.(*ir.Mpint)) > 0 {
 		Yyerror("invalid slice index: %v > %v", lo, hi)
 		return false
 	}
@@ -2240,39 +2317,39 @@ func checksliceconst(lo *Node, hi *Node) bool {
 	return true
 }
 
-func checkdefergo(n *Node) {
+func checkdefergo(n *ir.Node) {
 	what := "defer"
-	if n.Op == OPROC {
+	if n.Op == ir.OPROC {
 		what = "go"
 	}
 
 	switch n.Left.Op {
 	// ok
-	case OCALLINTER,
-		OCALLMETH,
-		OCALLFUNC,
-		OCLOSE,
-		OCOPY,
-		ODELETE,
-		OPANIC,
-		OPRINT,
-		OPRINTN,
-		ORECOVER:
+	case ir.OCALLINTER,
+		ir.OCALLMETH,
+		ir.OCALLFUNC,
+		ir.OCLOSE,
+		ir.OCOPY,
+		ir.ODELETE,
+		ir.OPANIC,
+		ir.OPRINT,
+		ir.OPRINTN,
+		ir.ORECOVER:
 		return
 
-	case OAPPEND,
-		OCAP,
-		OCOMPLEX,
-		OIMAG,
-		OLEN,
-		OMAKE,
-		OMAKESLICE,
-		OMAKECHAN,
-		OMAKEMAP,
-		ONEW,
-		OREAL,
-		OLITERAL: // conversion or unsafe.Alignof, Offsetof, Sizeof
-		if n.Left.Orig != nil && n.Left.Orig.Op == OCONV {
+	case ir.OAPPEND,
+		ir.OCAP,
+		ir.OCOMPLEX,
+		ir.OIMAG,
+		ir.OLEN,
+		ir.OMAKE,
+		ir.OMAKESLICE,
+		ir.OMAKECHAN,
+		ir.OMAKEMAP,
+		ir.ONEW,
+		ir.OREAL,
+		ir.OLITERAL: // conversion or unsafe.Alignof, Offsetof, Sizeof
+		if n.Left.Orig != nil && n.Left.Orig.Op == ir.OCONV {
 			break
 		}
 		Yyerror("%s discards result of %v", what, n.Left)
@@ -2296,7 +2373,7 @@ func checkdefergo(n *Node) {
 
 // The result of implicitstar MUST be assigned back to n, e.g.
 // 	n.Left = implicitstar(n.Left)
-func implicitstar(n *Node) *Node {
+func implicitstar(n *ir.Node) *ir.Node {
 	// insert implicit * if needed for fixed array
 	t := n.Type
 	if t == nil || !Isptr[t.Etype] {
@@ -2309,13 +2386,13 @@ func implicitstar(n *Node) *Node {
 	if !Isfixedarray(t) {
 		return n
 	}
-	n = Nod(OIND, n, nil)
+	n = Nod(ir.OIND, n, nil)
 	n.Implicit = true
 	n = typecheck(n, Erv)
 	return n
 }
 
-func onearg(n *Node, f string, args ...interface{}) bool {
+func onearg(n *ir.Node, f string, args ...interface{}) bool {
 	if n.Left != nil {
 		return true
 	}
@@ -2338,7 +2415,7 @@ func onearg(n *Node, f string, args ...interface{}) bool {
 	return true
 }
 
-func twoarg(n *Node) bool {
+func twoarg(n *ir.Node) bool {
 	if n.Left != nil {
 		return true
 	}
@@ -2365,8 +2442,8 @@ func twoarg(n *Node) bool {
 	return true
 }
 
-func lookdot1(errnode *Node, s *Sym, t *Type, fs *Fields, dostrcmp int) *Field {
-	var r *Field
+func lookdot1(errnode *ir.Node, s *ir.Sym, t *ir.Type, fs *ir.Fields, dostrcmp int) *ir.Field {
+	var r *ir.Field
 	for _, f := range fs.Slice() {
 		if dostrcmp != 0 && f.Sym.Name == s.Name {
 			return f
@@ -2394,10 +2471,10 @@ func lookdot1(errnode *Node, s *Sym, t *Type, fs *Fields, dostrcmp int) *Field {
 	return r
 }
 
-func looktypedot(n *Node, t *Type, dostrcmp int) bool {
+func looktypedot(n *ir.Node, t *ir.Type, dostrcmp int) bool {
 	s := n.Sym
 
-	if t.Etype == TINTER {
+	if t.Etype == ir.TINTER {
 		f1 := lookdot1(n, s, t, t.Fields(), dostrcmp)
 		if f1 == nil {
 			return false
@@ -2406,7 +2483,8 @@ func looktypedot(n *Node, t *Type, dostrcmp int) bool {
 		n.Sym = methodsym(n.Sym, t, 0)
 		n.Xoffset = f1.Width
 		n.Type = f1.Type
-		n.Op = ODOTINTER
+		n.Op = ir.ODOTINTER
+		imethodexprs = append(imethodexprs, imethodexpr{t, f1, n.Sym})
 		return true
 	}
 
@@ -2425,18 +2503,18 @@ func looktypedot(n *Node, t *Type, dostrcmp int) bool {
 
 	// disallow T.m if m requires *T receiver
 	if Isptr[f2.Type.Recv().Type.Etype] && !Isptr[t.Etype] && f2.Embedded != 2 && !isifacemethod(f2.Type) {
-		Yyerror("invalid method expression %v (needs pointer receiver: (*%v).%v)", n, t, Sconv(f2.Sym, FmtShort))
+		Yyerror("invalid method expression %v (needs pointer receiver: (*%v).%v)", n, t, Sconv(f2.Sym, ir.FmtShort))
 		return false
 	}
 
 	n.Sym = methodsym(n.Sym, t, 0)
 	n.Xoffset = f2.Width
 	n.Type = f2.Type
-	n.Op = ODOTMETH
+	n.Op = ir.ODOTMETH
 	return true
 }
 
-func derefall(t *Type) *Type {
+func derefall(t *ir.Type) *ir.Type {
 	for t != nil && t.Etype == Tptr {
 		t = t.Type
 	}
@@ -2444,24 +2522,24 @@ func derefall(t *Type) *Type {
 }
 
 type typeSym struct {
-	t *Type
-	s *Sym
+	t *ir.Type
+	s *ir.Sym
 }
 
 // dotField maps (*Type, *Sym) pairs to the corresponding struct field (*Type with Etype==TFIELD).
 // It is a cache for use during usefield in walk.go, only enabled when field tracking.
-var dotField = map[typeSym]*Field{}
+var dotField = map[typeSym]*ir.Field{}
 
-func lookdot(n *Node, t *Type, dostrcmp int) *Field {
+func lookdot(n *ir.Node, t *ir.Type, dostrcmp int) *ir.Field {
 	s := n.Sym
 
 	dowidth(t)
-	var f1 *Field
-	if t.Etype == TSTRUCT || t.Etype == TINTER {
+	var f1 *ir.Field
+	if t.Etype == ir.TSTRUCT || t.Etype == ir.TINTER {
 		f1 = lookdot1(n, s, t, t.Fields(), dostrcmp)
 	}
 
-	var f2 *Field
+	var f2 *ir.Field
 	if n.Left.Type == t || n.Left.Type.Sym == nil {
 		mt := methtype(t, 0)
 		if mt != nil {
@@ -2479,7 +2557,7 @@ func lookdot(n *Node, t *Type, dostrcmp int) *Field {
 		if f2 != nil {
 			Yyerror("%v is both field and method", n.Sym)
 		}
-		if f1.Width == BADWIDTH {
+		if f1.Width == ir.BADWIDTH {
 			Fatalf("lookdot badwidth %v %p", f1, f1)
 		}
 		n.Xoffset = f1.Width
@@ -2487,14 +2565,14 @@ func lookdot(n *Node, t *Type, dostrcmp int) *Field {
 		if obj.Fieldtrack_enabled > 0 {
 			dotField[typeSym{t.Orig, s}] = f1
 		}
-		if t.Etype == TINTER {
+		if t.Etype == ir.TINTER {
 			if Isptr[n.Left.Type.Etype] {
-				n.Left = Nod(OIND, n.Left, nil) // implicitstar
+				n.Left = Nod(ir.OIND, n.Left, nil) // implicitstar
 				n.Left.Implicit = true
 				n.Left = typecheck(n.Left, Erv)
 			}
 
-			n.Op = ODOTINTER
+			n.Op = ir.ODOTINTER
 		}
 
 		return f1
@@ -2511,21 +2589,21 @@ func lookdot(n *Node, t *Type, dostrcmp int) *Field {
 		if !Eqtype(rcvr, tt) {
 			if rcvr.Etype == Tptr && Eqtype(rcvr.Type, tt) {
 				checklvalue(n.Left, "call pointer method on")
-				n.Left = Nod(OADDR, n.Left, nil)
+				n.Left = Nod(ir.OADDR, n.Left, nil)
 				n.Left.Implicit = true
 				n.Left = typecheck(n.Left, Etype|Erv)
 			} else if tt.Etype == Tptr && rcvr.Etype != Tptr && Eqtype(tt.Type, rcvr) {
-				n.Left = Nod(OIND, n.Left, nil)
+				n.Left = Nod(ir.OIND, n.Left, nil)
 				n.Left.Implicit = true
 				n.Left = typecheck(n.Left, Etype|Erv)
 			} else if tt.Etype == Tptr && tt.Type.Etype == Tptr && Eqtype(derefall(tt), derefall(rcvr)) {
-				Yyerror("calling method %v with receiver %v requires explicit dereference", n.Sym, Nconv(n.Left, FmtLong))
+				Yyerror("calling method %v with receiver %v requires explicit dereference", n.Sym, Nconv(n.Left, ir.FmtLong))
 				for tt.Etype == Tptr {
 					// Stop one level early for method with pointer receiver.
 					if rcvr.Etype == Tptr && tt.Type.Etype != Tptr {
 						break
 					}
-					n.Left = Nod(OIND, n.Left, nil)
+					n.Left = Nod(ir.OIND, n.Left, nil)
 					n.Left.Implicit = true
 					n.Left = typecheck(n.Left, Etype|Erv)
 					tt = tt.Type
@@ -2537,11 +2615,11 @@ func lookdot(n *Node, t *Type, dostrcmp int) *Field {
 
 		pll := n
 		ll := n.Left
-		for ll.Left != nil && (ll.Op == ODOT || ll.Op == ODOTPTR || ll.Op == OIND) {
+		for ll.Left != nil && (ll.Op == ir.ODOT || ll.Op == ir.ODOTPTR || ll.Op == ir.OIND) {
 			pll = ll
 			ll = ll.Left
 		}
-		if pll.Implicit && Isptr[ll.Type.Etype] && ll.Type.Sym != nil && ll.Type.Sym.Def != nil && ll.Type.Sym.Def.Op == OTYPE {
+		if pll.Implicit && Isptr[ll.Type.Etype] && ll.Type.Sym != nil && ll.Type.Sym.Def != nil && ll.Type.Sym.Def.Op == ir.OTYPE {
 			// It is invalid to automatically dereference a named pointer type when selecting a method.
 			// Make n->left == ll to clarify error message.
 			n.Left = ll
@@ -2553,7 +2631,7 @@ func lookdot(n *Node, t *Type, dostrcmp int) *Field {
 		n.Type = f2.Type
 
 		//		print("lookdot found [%p] %T\n", f2->type, f2->type);
-		n.Op = ODOTMETH
+		n.Op = ir.ODOTMETH
 
 		return f2
 	}
@@ -2561,16 +2639,16 @@ func lookdot(n *Node, t *Type, dostrcmp int) *Field {
 	return nil
 }
 
-func nokeys(l Nodes) bool {
+func nokeys(l ir.Nodes) bool {
 	for _, n := range l.Slice() {
-		if n.Op == OKEY {
+		if n.Op == ir.OKEY {
 			return false
 		}
 	}
 	return true
 }
 
-func hasddd(t *Type) bool {
+func hasddd(t *ir.Type) bool {
 	for _, tl := range t.Fields().Slice() {
 		if tl.Isddd {
 			return true
@@ -2581,9 +2659,9 @@ func hasddd(t *Type) bool {
 }
 
 // typecheck assignment: type list = expression list
-func typecheckaste(op Op, call *Node, isddd bool, tstruct *Type, nl Nodes, desc func() string) {
-	var t *Type
-	var n *Node
+func typecheckaste(op ir.Op, call *ir.Node, isddd bool, tstruct *ir.Type, nl ir.Nodes, desc func() string) {
+	var t *ir.Type
+	var n *ir.Node
 	var n1 int
 	var n2 int
 	var i int
@@ -2598,7 +2676,7 @@ func typecheckaste(op Op, call *Node, isddd bool, tstruct *Type, nl Nodes, desc
 	if nl.Len() == 1 {
 		n = nl.First()
 		if n.Type != nil {
-			if n.Type.Etype == TSTRUCT && n.Type.Funarg {
+			if n.Type.Etype == ir.TSTRUCT && n.Type.Funarg {
 				if !hasddd(tstruct) {
 					n1 := tstruct.NumFields()
 					n2 := n.Type.NumFields()
@@ -2610,7 +2688,7 @@ func typecheckaste(op Op, call *Node, isddd bool, tstruct *Type, nl Nodes, desc
 					}
 				}
 
-				tn, it := IterFields(n.Type)
+				tn, it := ir.IterFields(n.Type)
 				var why string
 				for _, tl := range tstruct.Fields().Slice() {
 					if tl.Isddd {
@@ -2735,7 +2813,7 @@ notenough:
 			// call is the expression being called, not the overall call.
 			// Method expressions have the form T.M, and the compiler has
 			// rewritten those to ONAME nodes but left T in Left.
-			if call.Op == ONAME && call.Left != nil && call.Left.Op == OTYPE {
+			if call.Op == ir.ONAME && call.Left != nil && call.Left.Op == ir.OTYPE {
 				Yyerror("not enough arguments in call to method expression %v", call)
 			} else {
 				Yyerror("not enough arguments in call to %v", call)
@@ -2760,8 +2838,8 @@ toomany:
 }
 
 // type check composite
-func fielddup(n *Node, hash map[string]bool) {
-	if n.Op != ONAME {
+func fielddup(n *ir.Node, hash map[string]bool) {
+	if n.Op != ir.ONAME {
 		Fatalf("fielddup: not ONAME")
 	}
 	name := n.Sym.Name
@@ -2772,13 +2850,13 @@ func fielddup(n *Node, hash map[string]bool) {
 	hash[name] = true
 }
 
-func keydup(n *Node, hash map[uint32][]*Node) {
+func keydup(n *ir.Node, hash map[uint32][]*ir.Node) {
 	orign := n
-	if n.Op == OCONVIFACE {
+	if n.Op == ir.OCONVIFACE {
 		n = n.Left
 	}
 	evconst(n)
-	if n.Op != OLITERAL {
+	if n.Op != ir.OLITERAL {
 		return // we don't check variables
 	}
 
@@ -2787,18 +2865,18 @@ func keydup(n *Node, hash map[uint32][]*Node) {
 	default: // unknown, bool, nil
 		h = 23
 
-	case CTINT, CTRUNE:
-		h = uint32(n.Val().U.(*Mpint).Int64())
+	case ir.CTINT, ir.CTRUNE:
+		h = uint32(n.Val().U.(*ir.Mpint).Int64())
 
-	case CTFLT:
-		d := n.Val().U.(*Mpflt).Float64()
+	case ir.CTFLT:
+		d := n.Val().U.(*ir.Mpflt).Float64()
 		x := math.Float64bits(d)
 		for i := 0; i < 8; i++ {
 			h = h*PRIME1 + uint32(x&0xFF)
 			x >>= 8
 		}
 
-	case CTSTR:
+	case ir.CTSTR:
 		h = 0
 		s := n.Val().U.(string)
 		for i := 0; i < len(s); i++ {
@@ -2806,16 +2884,16 @@ func keydup(n *Node, hash map[uint32][]*Node) {
 		}
 	}
 
-	var cmp Node
+	var cmp ir.Node
 	for _, a := range hash[h] {
-		cmp.Op = OEQ
+		cmp.Op = ir.OEQ
 		cmp.Left = n
 		b := false
-		if a.Op == OCONVIFACE && orign.Op == OCONVIFACE {
+		if a.Op == ir.OCONVIFACE && orign.Op == ir.OCONVIFACE {
 			if Eqtype(a.Left.Type, n.Type) {
 				cmp.Right = a.Left
 				evconst(&cmp)
-				if cmp.Op == OLITERAL {
+				if cmp.Op == ir.OLITERAL {
 					// Sometimes evconst fails. See issue 12536.
 					b = cmp.Val().U.(bool)
 				}
@@ -2823,7 +2901,7 @@ func keydup(n *Node, hash map[uint32][]*Node) {
 		} else if Eqtype(a.Type, n.Type) {
 			cmp.Right = a
 			evconst(&cmp)
-			if cmp.Op == OLITERAL {
+			if cmp.Op == ir.OLITERAL {
 				b = cmp.Val().U.(bool)
 			}
 		}
@@ -2837,27 +2915,29 @@ func keydup(n *Node, hash map[uint32][]*Node) {
 	hash[h] = append(hash[h], orign)
 }
 
-func indexdup(n *Node, hash map[int64]*Node) {
-	if n.Op != OLITERAL {
+// indexdup diagnoses an array literal element n, an OKEY,
+// whose index was used by an earlier element recorded in hash.
+func indexdup(n *ir.Node, hash map[int64]*ir.Node) {
+	if n.Left.Op != ir.OLITERAL {
 		Fatalf("indexdup: not OLITERAL")
 	}
 
-	v := n.Val().U.(*Mpint).Int64()
-	if hash[v] != nil {
-		Yyerror("duplicate index in array literal: %d", v)
+	v := n.Left.Val().U.(*ir.Mpint).Int64()
+	if prev := hash[v]; prev != nil {
+		yyerrorl(n.Lineno, "duplicate index %d in array literal\n\tprevious element at %v", v, prev.Line())
 		return
 	}
 	hash[v] = n
 }
 
-func iscomptype(t *Type) bool {
+func iscomptype(t *ir.Type) bool {
 	switch t.Etype {
-	case TARRAY, TSTRUCT, TMAP:
+	case ir.TARRAY, ir.TSTRUCT, ir.TMAP:
 		return true
 
-	case TPTR32, TPTR64:
+	case ir.TPTR32, ir.TPTR64:
 		switch t.Type.Etype {
-		case TARRAY, TSTRUCT, TMAP:
+		case ir.TARRAY, ir.TSTRUCT, ir.TMAP:
 			return true
 		}
 	}
@@ -2865,8 +2945,8 @@ func iscomptype(t *Type) bool {
 	return false
 }
 
-func pushtype(n *Node, t *Type) {
-	if n == nil || n.Op != OCOMPLIT || !iscomptype(t) {
+func pushtype(n *ir.Node, t *ir.Type) {
+	if n == nil || n.Op != ir.OCOMPLIT || !iscomptype(t) {
 		return
 	}
 
@@ -2885,11 +2965,27 @@ func pushtype(n *Node, t *Type) {
 // Marker type so esc, fmt, and sinit can recognize the LHS of an OKEY node
 // in a struct literal.
 // TODO(mdempsky): Find a nicer solution.
-var structkey = typ(Txxx)
+var structkey = ir.Typ(ir.Txxx)
+
+// fieldvalue and assignment are the contexts of "cannot use" errors
+// for struct literal fields and assignments.
+func fieldvalue() string { return "field value" }
+func assignment() string { return "assignment" }
+
+// fieldcontext describes a value for field f in a struct literal,
+// for use as the overflow context of assignconv1.
+func fieldcontext(f *ir.Field) func() string {
+	return func() string {
+		if f.Nname == nil {
+			return fmt.Sprintf("field value %v", f.Sym)
+		}
+		return fmt.Sprintf("field value %v (%v %v declared at %v)", f.Sym, f.Sym, f.Type, f.Nname.Line())
+	}
+}
 
 // The result of typecheckcomplit MUST be assigned back to n, e.g.
 // 	n.Left = typecheckcomplit(n.Left)
-func typecheckcomplit(n *Node) *Node {
+func typecheckcomplit(n *ir.Node) *ir.Node {
 	lno := lineno
 	defer func() {
 		lineno = lno
@@ -2939,29 +3035,30 @@ func typecheckcomplit(n *Node) *Node {
 		t = t.Type
 	}
 
-	var r *Node
+	var r *ir.Node
 	switch t.Etype {
 	default:
 		Yyerror("invalid type for composite literal: %v", t)
 		n.Type = nil
 
-	case TARRAY:
+	case ir.TARRAY:
 		// Only allocate hash if there are some key/value pairs.
-		var hash map[int64]*Node
+		var hash map[int64]*ir.Node
 		for _, n1 := range n.List.Slice() {
-			if n1.Op == OKEY {
-				hash = make(map[int64]*Node)
+			if n1.Op == ir.OKEY {
+				hash = make(map[int64]*ir.Node)
 				break
 			}
 		}
 		length := int64(0)
+		bound := t.Bound // t may be shared; see sharetype
 		i := 0
 		for i2, n2 := range n.List.Slice() {
 			l := n2
 			setlineno(l)
-			if l.Op != OKEY {
-				l = Nod(OKEY, Nodintconst(int64(i)), l)
-				l.Left.Type = Types[TINT]
+			if l.Op != ir.OKEY {
+				l = Nod(ir.OKEY, Nodintconst(int64(i)), l)
+				l.Left.Type = ir.Types[ir.TINT]
 				l.Left.Typecheck = 1
 				n.List.SetIndex(i2, l)
 			}
@@ -2970,28 +3067,34 @@ func typecheckcomplit(n *Node) *Node {
 			evconst(l.Left)
 			i = nonnegconst(l.Left)
 			if i < 0 && l.Left.Diag == 0 {
-				Yyerror("index must be non-negative integer constant")
+				switch {
+				case !ir.Isconst(l.Left, ir.CTINT) || l.Left.Val().U.(*ir.Mpint).CmpInt64(0) < 0:
+					yyerrorl(l.Lineno, "index must be non-negative integer constant")
+				case bound >= 0:
+					yyerrorl(l.Lineno, "array index %s out of bounds [0:%d]", Vconv(l.Left.Val(), 0), bound)
+					bound = -1 // no more errors
+				default:
+					yyerrorl(l.Lineno, "array index %s too large", Vconv(l.Left.Val(), 0))
+				}
 				l.Left.Diag = 1
 				i = -(1 << 30) // stay negative for a while
 			}
 
 			if i >= 0 && hash != nil {
-				indexdup(l.Left, hash)
+				indexdup(l, hash)
 			}
 			i++
 			if int64(i) > length {
 				length = int64(i)
-				if t.Bound >= 0 && length > t.Bound {
-					setlineno(l)
-					Yyerror("array index %d out of bounds [0:%d]", length-1, t.Bound)
-					t.Bound = -1 // no more errors
+				if bound >= 0 && length > bound {
+					yyerrorl(l.Lineno, "array index %d out of bounds [0:%d]", length-1, bound)
+					bound = -1 // no more errors
 				}
 			}
 
 			r = l.Right
 			pushtype(r, t.Type)
 			r = typecheck(r, Erv)
-			r = defaultlit(r, t.Type)
 			l.Right = assignconv(r, t.Type, "array or slice literal")
 		}
 
@@ -3001,15 +3104,15 @@ func typecheckcomplit(n *Node) *Node {
 		if t.Bound < 0 {
 			n.Right = Nodintconst(length)
 		}
-		n.Op = OARRAYLIT
+		n.Op = ir.OARRAYLIT
 
-	case TMAP:
-		hash := make(map[uint32][]*Node)
-		var l *Node
+	case ir.TMAP:
+		hash := make(map[uint32][]*ir.Node)
+		var l *ir.Node
 		for i3, n3 := range n.List.Slice() {
 			l = n3
 			setlineno(l)
-			if l.Op != OKEY {
+			if l.Op != ir.OKEY {
 				n.List.SetIndex(i3, typecheck(n.List.Index(i3), Erv))
 				Yyerror("missing key in map literal")
 				continue
@@ -3018,31 +3121,29 @@ func typecheckcomplit(n *Node) *Node {
 			r = l.Left
 			pushtype(r, t.Key())
 			r = typecheck(r, Erv)
-			r = defaultlit(r, t.Key())
 			l.Left = assignconv(r, t.Key(), "map key")
-			if l.Left.Op != OCONV {
+			if l.Left.Op != ir.OCONV {
 				keydup(l.Left, hash)
 			}
 
 			r = l.Right
 			pushtype(r, t.Type)
 			r = typecheck(r, Erv)
-			r = defaultlit(r, t.Type)
 			l.Right = assignconv(r, t.Type, "map value")
 		}
 
-		n.Op = OMAPLIT
+		n.Op = ir.OMAPLIT
 
-	case TSTRUCT:
+	case ir.TSTRUCT:
 		// Need valid field offsets for Xoffset below.
 		dowidth(t)
 
 		bad := 0
 		if n.List.Len() != 0 && nokeys(n.List) {
 			// simple list of variables
-			f, it := IterFields(t)
+			f, it := ir.IterFields(t)
 
-			var s *Sym
+			var s *ir.Sym
 			ls := n.List.Slice()
 			for i1, n1 := range ls {
 				setlineno(n1)
@@ -3061,8 +3162,8 @@ func typecheckcomplit(n *Node) *Node {
 					Yyerror("implicit assignment of unexported field '%s' in %v literal", s.Name, t)
 				}
 				// No pushtype allowed here. Must name fields for that.
-				n1 = assignconv(n1, f.Type, "field value")
-				n1 = Nod(OKEY, newname(f.Sym), n1)
+				n1 = assignconv1(n1, f.Type, fieldvalue, fieldcontext(f))
+				n1 = Nod(ir.OKEY, newname(f.Sym), n1)
 				n1.Left.Type = structkey
 				n1.Left.Xoffset = f.Width
 				n1.Left.Typecheck = 1
@@ -3080,7 +3181,7 @@ func typecheckcomplit(n *Node) *Node {
 			ls := n.List.Slice()
 			for i, l := range ls {
 				setlineno(l)
-				if l.Op != OKEY {
+				if l.Op != ir.OKEY {
 					if bad == 0 {
 						Yyerror("mixture of field:value and value initializers")
 					}
@@ -3123,11 +3224,11 @@ func typecheckcomplit(n *Node) *Node {
 				// No pushtype allowed here. Tried and rejected.
 				r = typecheck(r, Erv)
 
-				l.Right = assignconv(r, f.Type, "field value")
+				l.Right = assignconv1(r, f.Type, fieldvalue, fieldcontext(f))
 			}
 		}
 
-		n.Op = OSTRUCTLIT
+		n.Op = ir.OSTRUCTLIT
 	}
 
 	if nerr != nerrors {
@@ -3137,7 +3238,7 @@ func typecheckcomplit(n *Node) *Node {
 
 	n.Orig = norig
 	if Isptr[n.Type.Etype] {
-		n = Nod(OPTRLIT, n, nil)
+		n = Nod(ir.OPTRLIT, n, nil)
 		n.Typecheck = 1
 		n.Type = n.Left.Type
 		n.Left.Type = t
@@ -3149,26 +3250,26 @@ func typecheckcomplit(n *Node) *Node {
 }
 
 // lvalue etc
-func islvalue(n *Node) bool {
+func islvalue(n *ir.Node) bool {
 	switch n.Op {
-	case OINDEX:
+	case ir.OINDEX:
 		if Isfixedarray(n.Left.Type) {
 			return islvalue(n.Left)
 		}
-		if n.Left.Type != nil && n.Left.Type.Etype == TSTRING {
+		if n.Left.Type != nil && n.Left.Type.Etype == ir.TSTRING {
 			return false
 		}
 		fallthrough
 
 		// fall through
-	case OIND, ODOTPTR, OCLOSUREVAR, OPARAM:
+	case ir.OIND, ir.ODOTPTR, ir.OCLOSUREVAR, ir.OPARAM:
 		return true
 
-	case ODOT:
+	case ir.ODOT:
 		return islvalue(n.Left)
 
-	case ONAME:
-		if n.Class == PFUNC {
+	case ir.ONAME:
+		if n.Class == ir.PFUNC {
 			return false
 		}
 		return true
@@ -3177,47 +3278,87 @@ func islvalue(n *Node) bool {
 	return false
 }
 
-func checklvalue(n *Node, verb string) {
+func checklvalue(n *ir.Node, verb string) {
 	if !islvalue(n) {
 		Yyerror("cannot %s %v", verb, n)
 	}
 }
 
-func checkassign(stmt *Node, n *Node) {
+// setaddrtaken marks n, and the variable it is linked to
+// through a closure, as having its address taken at lineno.
+func setaddrtaken(n *ir.Node) {
+	n.Addrtaken = true
+	if n.Name == nil || n.Name.Param == nil {
+		return
+	}
+	if n.Name.Param.Addrline == 0 {
+		n.Name.Param.Addrline = lineno
+	}
+	if c := n.Name.Param.Closure; c != nil {
+		c.Addrtaken = true
+		if c.Name.Param.Addrline == 0 {
+			c.Name.Param.Addrline = lineno
+		}
+	}
+}
+
+// setassigned marks n, and the variable it is linked to
+// through a closure, as assigned at lineno.
+func setassigned(n *ir.Node) {
+	n.Assigned = true
+	if n.Name == nil || n.Name.Param == nil {
+		return
+	}
+	n.Name.Param.Assignline = lineno
+	n.Name.Param.Assigngen = loopgen
+	if c := n.Name.Param.Closure; c != nil {
+		c.Assigned = true
+		c.Name.Param.Assignline = lineno
+		c.Name.Param.Assigngen = loopgen
+	}
+}
+
+// markloop records that the code typechecked from now until decldepth
+// drops back to its current value may run repeatedly, as the body of
+// a loop or the code following a label does. The caller increments
+// decldepth. See typecheckclosure.
+func markloop() {
+	loopgen++
+	for int(decldepth+1) >= len(loopstart) {
+		loopstart = append(loopstart, 0)
+	}
+	loopstart[decldepth+1] = loopgen
+}
+
+func checkassign(stmt *ir.Node, n *ir.Node) {
 	// Variables declared in ORANGE are assigned on every iteration.
-	if n.Name == nil || n.Name.Defn != stmt || stmt.Op == ORANGE {
+	if n.Name == nil || n.Name.Defn != stmt || stmt.Op == ir.ORANGE {
 		r := outervalue(n)
-		var l *Node
+		var l *ir.Node
 		for l = n; l != r; l = l.Left {
-			l.Assigned = true
-			if l.Name != nil && l.Name.Param != nil && l.Name.Param.Closure != nil {
-				l.Name.Param.Closure.Assigned = true
-			}
+			setassigned(l)
 		}
 
-		l.Assigned = true
-		if l.Name != nil && l.Name.Param != nil && l.Name.Param.Closure != nil {
-			l.Name.Param.Closure.Assigned = true
-		}
+		setassigned(l)
 	}
 
 	if islvalue(n) {
 		return
 	}
-	if n.Op == OINDEXMAP {
+	if n.Op == ir.OINDEXMAP {
 		n.Etype = 1
 		return
 	}
 
 	// have already complained about n being undefined
-	if n.Op == ONONAME {
+	if n.Op == ir.ONONAME {
 		return
 	}
 
 	Yyerror("cannot assign to %v", n)
 }
 
-func checkassignlist(stmt *Node, l Nodes) {
+func checkassignlist(stmt *ir.Node, l ir.Nodes) {
 	for _, n := range l.Slice() {
 		checkassign(stmt, n)
 	}
@@ -3225,32 +3366,43 @@ func checkassignlist(stmt *Node, l Nodes) {
 
 // Check whether l and r are the same side effect-free expression,
 // so that it is safe to reuse one instead of computing both.
-func samesafeexpr(l *Node, r *Node) bool {
+func samesafeexpr(l *ir.Node, r *ir.Node) bool {
 	if l.Op != r.Op || !Eqtype(l.Type, r.Type) {
 		return false
 	}
 
 	switch l.Op {
-	case ONAME, OCLOSUREVAR:
+	case ir.ONAME, ir.OCLOSUREVAR:
 		return l == r
 
-	case ODOT, ODOTPTR:
+	case ir.ODOT, ir.ODOTPTR:
 		return l.Sym != nil && r.Sym != nil && l.Sym == r.Sym && samesafeexpr(l.Left, r.Left)
 
-	case OIND:
+	case ir.OIND:
 		return samesafeexpr(l.Left, r.Left)
 
-	case OINDEX:
+	case ir.OINDEX:
 		return samesafeexpr(l.Left, r.Left) && samesafeexpr(l.Right, r.Right)
 	}
 
 	return false
 }
 
+// assigncontext describes an assignment to l,
+// for use as the overflow context of assignconv1.
+func assigncontext(l *ir.Node) func() string {
+	return func() string {
+		if l.Op != ir.ONAME || isblank(l) {
+			return "assignment"
+		}
+		return fmt.Sprintf("assignment to %v (%v %v declared at %v)", l, l, l.Type, l.Line())
+	}
+}
+
 // type check assignment.
 // if this assignment is the definition of a var on the left side,
 // fill in the var's type.
-func typecheckas(n *Node) {
+func typecheckas(n *ir.Node) {
 	// delicate little dance.
 	// the definition of n may refer to this assignment
 	// as its definition, in which case it will call typecheckas.
@@ -3268,7 +3420,7 @@ func typecheckas(n *Node) {
 	checkassign(n, n.Left)
 	if n.Right != nil && n.Right.Type != nil {
 		if n.Left.Type != nil {
-			n.Right = assignconv(n.Right, n.Left.Type, "assignment")
+			n.Right = assignconv1(n.Right, n.Left.Type, assignment, assigncontext(n.Left))
 		}
 	}
 
@@ -3287,16 +3439,16 @@ func typecheckas(n *Node) {
 	}
 }
 
-func checkassignto(src *Type, dst *Node) {
+func checkassignto(src *ir.Type, dst *ir.Node) {
 	var why string
 
 	if assignop(src, dst.Type, &why) == 0 {
-		Yyerror("cannot assign %v to %v in multiple assignment%s", src, Nconv(dst, FmtLong), why)
+		Yyerror("cannot assign %v to %v in multiple assignment%s", src, Nconv(dst, ir.FmtLong), why)
 		return
 	}
 }
 
-func typecheckas2(n *Node) {
+func typecheckas2(n *ir.Node) {
 	ls := n.List.Slice()
 	for i1, n1 := range ls {
 		// delicate little dance.
@@ -3317,8 +3469,8 @@ func typecheckas2(n *Node) {
 	}
 	checkassignlist(n, n.List)
 
-	var l *Node
-	var r *Node
+	var l *ir.Node
+	var r *ir.Node
 	if cl == cr {
 		// easy
 		ls := n.List.Slice()
@@ -3326,7 +3478,7 @@ func typecheckas2(n *Node) {
 		for il, nl := range ls {
 			nr := rs[il]
 			if nl.Type != nil && nr.Type != nil {
-				rs[il] = assignconv(nr, nl.Type, "assignment")
+				rs[il] = assignconv1(nr, nl.Type, assignment, assigncontext(nl))
 			}
 			if nl.Name != nil && nl.Name.Defn == n && nl.Name.Param.Ntype == nil {
 				rs[il] = defaultlit(rs[il], nil)
@@ -3346,16 +3498,16 @@ func typecheckas2(n *Node) {
 			goto out
 		}
 		switch r.Op {
-		case OCALLMETH, OCALLINTER, OCALLFUNC:
-			if r.Type.Etype != TSTRUCT || !r.Type.Funarg {
+		case ir.OCALLMETH, ir.OCALLINTER, ir.OCALLFUNC:
+			if r.Type.Etype != ir.TSTRUCT || !r.Type.Funarg {
 				break
 			}
 			cr = r.Type.NumFields()
 			if cr != cl {
 				goto mismatch
 			}
-			n.Op = OAS2FUNC
-			t, s := IterFields(r.Type)
+			n.Op = ir.OAS2FUNC
+			t, s := ir.IterFields(r.Type)
 			for _, n3 := range n.List.Slice() {
 				if t.Type != nil && n3.Type != nil {
 					checkassignto(t.Type, n3)
@@ -3376,17 +3528,17 @@ func typecheckas2(n *Node) {
 			goto out
 		}
 		switch r.Op {
-		case OINDEXMAP, ORECV, ODOTTYPE:
+		case ir.OINDEXMAP, ir.ORECV, ir.ODOTTYPE:
 			switch r.Op {
-			case OINDEXMAP:
-				n.Op = OAS2MAPR
+			case ir.OINDEXMAP:
+				n.Op = ir.OAS2MAPR
 
-			case ORECV:
-				n.Op = OAS2RECV
+			case ir.ORECV:
+				n.Op = ir.OAS2RECV
 
-			case ODOTTYPE:
-				n.Op = OAS2DOTTYPE
-				r.Op = ODOTTYPE2
+			case ir.ODOTTYPE:
+				n.Op = ir.OAS2DOTTYPE
+				r.Op = ir.ODOTTYPE2
 			}
 
 			if l.Type != nil {
@@ -3396,11 +3548,11 @@ func typecheckas2(n *Node) {
 				l.Type = r.Type
 			}
 			l := n.List.Second()
-			if l.Type != nil && l.Type.Etype != TBOOL {
-				checkassignto(Types[TBOOL], l)
+			if l.Type != nil && l.Type.Etype != ir.TBOOL {
+				checkassignto(ir.Types[ir.TBOOL], l)
 			}
 			if l.Name != nil && l.Name.Defn == n && l.Name.Param.Ntype == nil {
-				l.Type = Types[TBOOL]
+				l.Type = ir.Types[ir.TBOOL]
 			}
 			goto out
 		}
@@ -3421,12 +3573,16 @@ out:
 }
 
 // type check function definition
-func typecheckfunc(n *Node) {
+func typecheckfunc(n *ir.Node) {
 	n.Func.Nname = typecheck(n.Func.Nname, Erv|Easgn)
 	t := n.Func.Nname.Type
 	if t == nil {
 		return
 	}
+	if t.Shared {
+		t = unsharefunctype(t)
+		n.Func.Nname.Type = t
+	}
 	n.Type = t
 	t.Nname = n.Func.Nname
 	rcvr := t.Recv()
@@ -3435,7 +3591,7 @@ func typecheckfunc(n *Node) {
 	}
 
 	for _, ln := range n.Func.Dcl {
-		if ln.Op == ONAME && (ln.Class == PPARAM || ln.Class == PPARAMOUT) {
+		if ln.Op == ir.ONAME && (ln.Class == ir.PPARAM || ln.Class == ir.PPARAMOUT) {
 			ln.Name.Decldepth = 1
 		}
 	}
@@ -3443,28 +3599,28 @@ func typecheckfunc(n *Node) {
 
 // The result of stringtoarraylit MUST be assigned back to n, e.g.
 // 	n.Left = stringtoarraylit(n.Left)
-func stringtoarraylit(n *Node) *Node {
-	if n.Left.Op != OLITERAL || n.Left.Val().Ctype() != CTSTR {
+func stringtoarraylit(n *ir.Node) *ir.Node {
+	if n.Left.Op != ir.OLITERAL || n.Left.Val().Ctype() != ir.CTSTR {
 		Fatalf("stringtoarraylit %v", n)
 	}
 
 	s := n.Left.Val().U.(string)
-	var l []*Node
-	if n.Type.Type.Etype == TUINT8 {
+	var l []*ir.Node
+	if n.Type.Type.Etype == ir.TUINT8 {
 		// []byte
 		for i := 0; i < len(s); i++ {
-			l = append(l, Nod(OKEY, Nodintconst(int64(i)), Nodintconst(int64(s[0]))))
+			l = append(l, Nod(ir.OKEY, Nodintconst(int64(i)), Nodintconst(int64(s[0]))))
 		}
 	} else {
 		// []rune
 		i := 0
 		for _, r := range s {
-			l = append(l, Nod(OKEY, Nodintconst(int64(i)), Nodintconst(int64(r))))
+			l = append(l, Nod(ir.OKEY, Nodintconst(int64(i)), Nodintconst(int64(r))))
 			i++
 		}
 	}
 
-	nn := Nod(OCOMPLIT, nil, typenod(n.Type))
+	nn := Nod(ir.OCOMPLIT, nil, typenod(n.Type))
 	nn.List.Set(l)
 	nn = typecheck(nn, Erv)
 	return nn
@@ -3472,15 +3628,15 @@ func stringtoarraylit(n *Node) *Node {
 
 var ntypecheckdeftype int
 
-var methodqueue []*Node
+var methodqueue []*ir.Node
 
-func domethod(n *Node) {
+func domethod(n *ir.Node) {
 	nt := n.Type.Nname
 	nt = typecheck(nt, Etype)
 	if nt.Type == nil {
 		// type check failed; leave empty func
 		// TODO(mdempsky): Fix Type rekinding.
-		n.Type.Etype = TFUNC
+		n.Type.Etype = ir.TFUNC
 		n.Type.Nod = nil
 		return
 	}
@@ -3505,10 +3661,10 @@ func domethod(n *Node) {
 	checkwidth(n.Type)
 }
 
-var mapqueue []*Node
+var mapqueue []*ir.Node
 
-func copytype(n *Node, t *Type) {
-	if t.Etype == TFORW {
+func copytype(n *ir.Node, t *ir.Type) {
+	if t.Etype == ir.TFORW {
 		// This type isn't computed yet; when it is, update n.
 		t.Copyto = append(t.Copyto, n)
 
@@ -3528,11 +3684,12 @@ func copytype(n *Node, t *Type) {
 	if n.Name != nil {
 		t.Vargen = n.Name.Vargen
 	}
-	t.methods = Fields{}
-	t.allMethods = Fields{}
+	*t.Methods() = ir.Fields{}
+	*t.AllMethods() = ir.Fields{}
 	t.Nod = nil
 	t.Printed = false
 	t.Deferwidth = false
+	t.Shared = false
 	t.Copyto = nil
 
 	// Update nodes waiting on this type.
@@ -3559,7 +3716,7 @@ func copytype(n *Node, t *Type) {
 	}
 }
 
-func typecheckdeftype(n *Node) {
+func typecheckdeftype(n *ir.Node) {
 	ntypecheckdeftype++
 	lno := lineno
 	setlineno(n)
@@ -3604,7 +3761,7 @@ ret:
 
 		for _, n := range mapqueue {
 			lineno = n.Type.Maplineno
-			maptype(n.Type, Types[TBOOL])
+			maptype(n.Type, ir.Types[ir.TBOOL])
 		}
 
 		lineno = lno
@@ -3613,7 +3770,7 @@ ret:
 	ntypecheckdeftype--
 }
 
-func queuemethod(n *Node) {
+func queuemethod(n *ir.Node) {
 	if ntypecheckdeftype == 0 {
 		domethod(n)
 		return
@@ -3622,11 +3779,11 @@ func queuemethod(n *Node) {
 	methodqueue = append(methodqueue, n)
 }
 
-func typecheckdef(n *Node) *Node {
+func typecheckdef(n *ir.Node) *ir.Node {
 	lno := lineno
 	setlineno(n)
 
-	if n.Op == ONONAME {
+	if n.Op == ir.ONONAME {
 		if n.Diag == 0 {
 			n.Diag = 1
 			if n.Lineno != 0 {
@@ -3659,6 +3816,10 @@ func typecheckdef(n *Node) *Node {
 
 	n.Walkdef = 2
 
+	if n.Op == ir.OLITERAL && n.Name != nil {
+		iotastack = append(iotastack, n.Name.Iota)
+	}
+
 	if n.Type != nil || n.Sym == nil { // builtin or no name
 		goto ret
 	}
@@ -3668,10 +3829,10 @@ func typecheckdef(n *Node) *Node {
 		Fatalf("typecheckdef %v", Oconv(n.Op, 0))
 
 		// not really syms
-	case OGOTO, OLABEL:
+	case ir.OGOTO, ir.OLABEL:
 		break
 
-	case OLITERAL:
+	case ir.OLITERAL:
 		if n.Name.Param.Ntype != nil {
 			n.Name.Param.Ntype = typecheck(n.Name.Param.Ntype, Etype)
 			n.Type = n.Name.Param.Ntype.Type
@@ -3691,12 +3852,12 @@ func typecheckdef(n *Node) *Node {
 		}
 
 		e = typecheck(e, Erv|Eiota)
-		if Isconst(e, CTNIL) {
+		if ir.Isconst(e, ir.CTNIL) {
 			Yyerror("const initializer cannot be nil")
 			goto ret
 		}
 
-		if e.Type != nil && e.Op != OLITERAL || !isgoconst(e) {
+		if e.Type != nil && e.Op != ir.OLITERAL || !isgoconst(e) {
 			if e.Diag == 0 {
 				Yyerror("const initializer %v is not a constant", e)
 				e.Diag = 1
@@ -3713,17 +3874,17 @@ func typecheckdef(n *Node) *Node {
 			}
 
 			if !isideal(e.Type) && !Eqtype(t, e.Type) {
-				Yyerror("cannot use %v as type %v in const initializer", Nconv(e, FmtLong), t)
+				Yyerror("cannot use %v as type %v in const initializer", Nconv(e, ir.FmtLong), t)
 				goto ret
 			}
 
-			e = convlit(e, t)
+			e = convlit1(e, t, false, func() string { return fmt.Sprintf("const initializer for %v", n) })
 		}
 
 		n.SetVal(e.Val())
 		n.Type = e.Type
 
-	case ONAME:
+	case ir.ONAME:
 		if n.Name.Param.Ntype != nil {
 			n.Name.Param.Ntype = typecheck(n.Name.Param.Ntype, Etype)
 			n.Type = n.Name.Param.Ntype.Type
@@ -3751,7 +3912,7 @@ func typecheckdef(n *Node) *Node {
 			Fatalf("var without type, init: %v", n.Sym)
 		}
 
-		if n.Name.Defn.Op == ONAME {
+		if n.Name.Defn.Op == ir.ONAME {
 			n.Name.Defn = typecheck(n.Name.Defn, Erv)
 			n.Type = n.Name.Defn.Type
 			break
@@ -3759,16 +3920,16 @@ func typecheckdef(n *Node) *Node {
 
 		n.Name.Defn = typecheck(n.Name.Defn, Etop) // fills in n->type
 
-	case OTYPE:
+	case ir.OTYPE:
 		if Curfn != nil {
 			defercheckwidth()
 		}
 		n.Walkdef = 1
-		n.Type = typ(TFORW)
+		n.Type = ir.Typ(ir.TFORW)
 		n.Type.Sym = n.Sym
 		nerrors0 := nerrors
 		typecheckdeftype(n)
-		if n.Type.Etype == TFORW && nerrors > nerrors0 {
+		if n.Type.Etype == ir.TFORW && nerrors > nerrors0 {
 			// Something went wrong during type-checking,
 			// but it was reported. Silence future errors.
 			n.Type.Broke = true
@@ -3779,12 +3940,12 @@ func typecheckdef(n *Node) *Node {
 		}
 
 		// nothing to see here
-	case OPACK:
+	case ir.OPACK:
 		break
 	}
 
 ret:
-	if n.Op != OLITERAL && n.Type != nil && isideal(n.Type) {
+	if n.Op != ir.OLITERAL && n.Type != nil && isideal(n.Type) {
 		Fatalf("got %v for %v", n.Type, n)
 	}
 	last := len(typecheckdefstack) - 1
@@ -3793,30 +3954,33 @@ ret:
 	}
 	typecheckdefstack[last] = nil
 	typecheckdefstack = typecheckdefstack[:last]
+	if n.Op == ir.OLITERAL && n.Name != nil {
+		iotastack = iotastack[:len(iotastack)-1]
+	}
 
 	lineno = lno
 	n.Walkdef = 1
 	return n
 }
 
-func checkmake(t *Type, arg string, n *Node) bool {
-	if n.Op == OLITERAL {
+func checkmake(t *ir.Type, arg string, n *ir.Node) bool {
+	if n.Op == ir.OLITERAL {
 		switch n.Val().Ctype() {
-		case CTINT, CTRUNE, CTFLT, CTCPLX:
+		case ir.CTINT, ir.CTRUNE, ir.CTFLT, ir.CTCPLX:
 			n.SetVal(toint(n.Val()))
-			if n.Val().U.(*Mpint).CmpInt64(0) < 0 {
+			if n.Val().U.(*ir.Mpint).CmpInt64(0) < 0 {
 				Yyerror("negative %s argument in make(%v)", arg, t)
 				return false
 			}
 
-			if n.Val().U.(*Mpint).Cmp(Maxintval[TINT]) > 0 {
+			if n.Val().U.(*ir.Mpint).Cmp(Maxintval[ir.TINT]) > 0 {
 				Yyerror("%s argument too large in make(%v)", arg, t)
 				return false
 			}
 
 			// Delay defaultlit until after we've checked range, to avoid
 			// a redundant "constant NNN overflows int" error.
-			n = defaultlit(n, Types[TINT])
+			n = defaultlit(n, ir.Types[ir.TINT])
 
 			return true
 
@@ -3825,24 +3989,24 @@ func checkmake(t *Type, arg string, n *Node) bool {
 		}
 	}
 
-	if !Isint[n.Type.Etype] && n.Type.Etype != TIDEAL {
+	if !Isint[n.Type.Etype] && n.Type.Etype != ir.TIDEAL {
 		Yyerror("non-integer %s argument in make(%v) - %v", arg, t, n.Type)
 		return false
 	}
 
 	// Defaultlit still necessary for non-constant: n might be 1<<k.
-	n = defaultlit(n, Types[TINT])
+	n = defaultlit(n, ir.Types[ir.TINT])
 
 	return true
 }
 
-func markbreak(n *Node, implicit *Node) {
+func markbreak(n *ir.Node, implicit *ir.Node) {
 	if n == nil {
 		return
 	}
 
 	switch n.Op {
-	case OBREAK:
+	case ir.OBREAK:
 		if n.Left == nil {
 			if implicit != nil {
 				implicit.Hasbreak = true
@@ -3854,11 +4018,11 @@ func markbreak(n *Node, implicit *Node) {
 			}
 		}
 
-	case OFOR,
-		OSWITCH,
-		OTYPESW,
-		OSELECT,
-		ORANGE:
+	case ir.OFOR,
+		ir.OSWITCH,
+		ir.OTYPESW,
+		ir.OSELECT,
+		ir.ORANGE:
 		implicit = n
 		fallthrough
 
@@ -3874,14 +4038,14 @@ func markbreak(n *Node, implicit *Node) {
 	}
 }
 
-func markbreaklist(l Nodes, implicit *Node) {
+func markbreaklist(l ir.Nodes, implicit *ir.Node) {
 	s := l.Slice()
 	for i := 0; i < len(s); i++ {
 		n := s[i]
-		if n.Op == OLABEL && i+1 < len(s) && n.Name.Defn == s[i+1] {
+		if n.Op == ir.OLABEL && i+1 < len(s) && n.Name.Defn == s[i+1] {
 			switch n.Name.Defn.Op {
-			case OFOR, OSWITCH, OTYPESW, OSELECT, ORANGE:
-				lab := new(Label)
+			case ir.OFOR, ir.OSWITCH, ir.OTYPESW, ir.OSELECT, ir.ORANGE:
+				lab := new(ir.Label)
 				lab.Def = n.Name.Defn
 				n.Left.Sym.Label = lab
 				markbreak(n.Name.Defn, n.Name.Defn)
@@ -3895,37 +4059,37 @@ func markbreaklist(l Nodes, implicit *Node) {
 	}
 }
 
-// Isterminating whether the Nodes list ends with a terminating
-// statement.
-func (l Nodes) isterminating() bool {
+// isterminatingList reports whether the Nodes list l ends with a
+// terminating statement.
+func isterminatingList(l ir.Nodes) bool {
 	s := l.Slice()
 	c := len(s)
 	if c == 0 {
 		return false
 	}
-	return s[c-1].isterminating()
+	return isterminating(s[c-1])
 }
 
-// Isterminating returns whether the node n, the last one in a
+// isterminating reports whether the node n, the last one in a
 // statement list, is a terminating statement.
-func (n *Node) isterminating() bool {
+func isterminating(n *ir.Node) bool {
 	switch n.Op {
 	// NOTE: OLABEL is treated as a separate statement,
 	// not a separate prefix, so skipping to the last statement
 	// in the block handles the labeled statement case by
 	// skipping over the label. No case OLABEL here.
 
-	case OBLOCK:
-		return n.List.isterminating()
+	case ir.OBLOCK:
+		return isterminatingList(n.List)
 
-	case OGOTO,
-		ORETURN,
-		ORETJMP,
-		OPANIC,
-		OXFALL:
+	case ir.OGOTO,
+		ir.ORETURN,
+		ir.ORETJMP,
+		ir.OPANIC,
+		ir.OXFALL:
 		return true
 
-	case OFOR:
+	case ir.OFOR:
 		if n.Left != nil {
 			return false
 		}
@@ -3934,16 +4098,16 @@ func (n *Node) isterminating() bool {
 		}
 		return true
 
-	case OIF:
-		return n.Nbody.isterminating() && n.Rlist.isterminating()
+	case ir.OIF:
+		return isterminatingList(n.Nbody) && isterminatingList(n.Rlist)
 
-	case OSWITCH, OTYPESW, OSELECT:
+	case ir.OSWITCH, ir.OTYPESW, ir.OSELECT:
 		if n.Hasbreak {
 			return false
 		}
 		def := 0
 		for _, n1 := range n.List.Slice() {
-			if !n1.Nbody.isterminating() {
+			if !isterminatingList(n1.Nbody) {
 				return false
 			}
 			if n1.List.Len() == 0 { // default
@@ -3951,7 +4115,7 @@ func (n *Node) isterminating() bool {
 			}
 		}
 
-		if n.Op != OSELECT && def == 0 {
+		if n.Op != ir.OSELECT && def == 0 {
 			return false
 		}
 		return true
@@ -3960,10 +4124,10 @@ func (n *Node) isterminating() bool {
 	return false
 }
 
-func checkreturn(fn *Node) {
+func checkreturn(fn *ir.Node) {
 	if fn.Type.Results().NumFields() != 0 && len(fn.Nbody.Slice()) != 0 {
 		markbreaklist(fn.Nbody, nil)
-		if !fn.Nbody.isterminating() {
+		if !isterminatingList(fn.Nbody) {
 			yyerrorl(fn.Func.Endlineno, "missing return at end of function")
 		}
 	}
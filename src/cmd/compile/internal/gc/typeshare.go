@@ -0,0 +1,153 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gc
+
+import "cmd/compile/internal/ir"
+
+// Unnamed pointer, slice, array, channel, map and function types made
+// from the same types are shared: the same type written in many places,
+// like []byte or func(int) error, or imported from many packages, is
+// a single Type. This saves memory, and Eqtype returns at its first
+// comparison for identical types.
+//
+// A shared type must not be changed once it is made, except for the
+// information computed from its components, such as its width. Types
+// that are changed after they are made, such as the arrays of map
+// buckets (Noalg), [...]T arrays (whose bound is set by their literal)
+// and the types of methods (whose Nname is set), are not shared. A
+// function type that is shared but must be given an Nname, such as the
+// type of a declared function without parameter names, is first copied
+// by unsharefunctype.
+
+// A typeKey identifies an unnamed pointer, slice, array, channel or
+// map type.
+type typeKey struct {
+	etype ir.EType
+	elem  *ir.Type // element type; value type of a map
+	key   *ir.Type // key type of a map
+	bound int64    // array bound, -1 for a slice
+	dir   uint8    // channel direction
+}
+
+// A funcKey is the part of a function type that selects its list in
+// sharedFuncs. The lists are short: most of the function types with
+// the same number of parameters and results, and the same first
+// parameter and result, are different only in their other fields.
+type funcKey struct {
+	nparams  int
+	nresults int
+	param    *ir.Type
+	result   *ir.Type
+}
+
+var (
+	sharedTypes = map[typeKey]*ir.Type{}
+	sharedFuncs = map[funcKey][]*ir.Type{}
+)
+
+// sharetype returns the shared type identical to t, a type the caller
+// has just made, or t itself if t is the first such type or is not to
+// be shared.
+func sharetype(t *ir.Type) *ir.Type {
+	if t == nil || t.Sym != nil || t.Broke || t.Nname != nil {
+		return t
+	}
+	switch t.Etype {
+	case ir.TPTR32, ir.TPTR64:
+		return sharetypekey(t, typeKey{etype: t.Etype, elem: t.Type})
+	case ir.TARRAY:
+		if t.Bound < -1 {
+			return t
+		}
+		return sharetypekey(t, typeKey{etype: ir.TARRAY, elem: t.Type, bound: t.Bound})
+	case ir.TCHAN:
+		return sharetypekey(t, typeKey{etype: ir.TCHAN, elem: t.Type, dir: t.Chan})
+	case ir.TMAP:
+		return sharetypekey(t, typeKey{etype: ir.TMAP, elem: t.Type, key: t.Down})
+	case ir.TFUNC:
+		return sharefunctype(t)
+	}
+	return t
+}
+
+func sharetypekey(t *ir.Type, k typeKey) *ir.Type {
+	if k.elem == nil || k.elem.Broke || k.key != nil && k.key.Broke {
+		return t
+	}
+	if s := sharedTypes[k]; s != nil {
+		return s
+	}
+	t.Shared = true
+	sharedTypes[k] = t
+	return t
+}
+
+// sharefunctype is sharetype for a function type. Only the types of
+// functions without a receiver and whose parameters are not declared
+// are shared: the parameters of a declared function are its own.
+func sharefunctype(t *ir.Type) *ir.Type {
+	if t.Recvs().NumFields() != 0 {
+		return t
+	}
+	params, results := t.Params().FieldSlice(), t.Results().FieldSlice()
+	for _, fs := range [2][]*ir.Field{params, results} {
+		for _, f := range fs {
+			if f.Nname != nil || f.Broke || f.Type == nil || f.Type.Broke {
+				return t
+			}
+		}
+	}
+
+	k := funcKey{nparams: len(params), nresults: len(results)}
+	if len(params) > 0 {
+		k.param = params[0].Type
+	}
+	if len(results) > 0 {
+		k.result = results[0].Type
+	}
+	for _, s := range sharedFuncs[k] {
+		if samefields(s.Params().FieldSlice(), params) && samefields(s.Results().FieldSlice(), results) {
+			return s
+		}
+	}
+	t.Shared = true
+	sharedFuncs[k] = append(sharedFuncs[k], t)
+	return t
+}
+
+// samefields reports whether the parameter lists a and b are the same,
+// including the names and the escape analysis notes of the parameters.
+func samefields(a, b []*ir.Field) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i, f := range a {
+		g := b[i]
+		if f.Type != g.Type || f.Sym != g.Sym || f.Isddd != g.Isddd {
+			return false
+		}
+		if (f.Note == nil) != (g.Note == nil) || f.Note != nil && *f.Note != *g.Note {
+			return false
+		}
+	}
+	return true
+}
+
+// unsharefunctype returns a copy of the function type t, with copies of
+// its fields, that is not shared with other functions.
+func unsharefunctype(t *ir.Type) *ir.Type {
+	copyfields := func(fs []*ir.Field) []*ir.Field {
+		var c []*ir.Field
+		for _, f := range fs {
+			c = append(c, f.Copy())
+		}
+		return c
+	}
+	var this *ir.Field
+	if r := t.Recv(); r != nil {
+		this = r.Copy()
+	}
+	return functypefield(this, copyfields(t.Params().FieldSlice()), copyfields(t.Results().FieldSlice()))
+}
@@ -4,63 +4,65 @@
 
 package gc
 
+import "cmd/compile/internal/ir"
+
 // builtinpkg is a fake package that declares the universe block.
-var builtinpkg *Pkg
+var builtinpkg *ir.Pkg
 
-var itable *Type // distinguished *byte
+var itable *ir.Type // distinguished *byte
 
 var basicTypes = [...]struct {
 	name  string
-	etype EType
+	etype ir.EType
 }{
-	{"int8", TINT8},
-	{"int16", TINT16},
-	{"int32", TINT32},
-	{"int64", TINT64},
-	{"uint8", TUINT8},
-	{"uint16", TUINT16},
-	{"uint32", TUINT32},
-	{"uint64", TUINT64},
-	{"float32", TFLOAT32},
-	{"float64", TFLOAT64},
-	{"complex64", TCOMPLEX64},
-	{"complex128", TCOMPLEX128},
-	{"bool", TBOOL},
-	{"string", TSTRING},
-	{"any", TANY},
+	{"int8", ir.TINT8},
+	{"int16", ir.TINT16},
+	{"int32", ir.TINT32},
+	{"int64", ir.TINT64},
+	{"uint8", ir.TUINT8},
+	{"uint16", ir.TUINT16},
+	{"uint32", ir.TUINT32},
+	{"uint64", ir.TUINT64},
+	{"float32", ir.TFLOAT32},
+	{"float64", ir.TFLOAT64},
+	{"complex64", ir.TCOMPLEX64},
+	{"complex128", ir.TCOMPLEX128},
+	{"bool", ir.TBOOL},
+	{"string", ir.TSTRING},
+	{"any", ir.TANY},
 }
 
 var typedefs = [...]struct {
 	name     string
-	etype    EType
+	etype    ir.EType
 	width    *int
-	sameas32 EType
-	sameas64 EType
+	sameas32 ir.EType
+	sameas64 ir.EType
 }{
-	{"int", TINT, &Widthint, TINT32, TINT64},
-	{"uint", TUINT, &Widthint, TUINT32, TUINT64},
-	{"uintptr", TUINTPTR, &Widthptr, TUINT32, TUINT64},
+	{"int", ir.TINT, &Widthint, ir.TINT32, ir.TINT64},
+	{"uint", ir.TUINT, &Widthint, ir.TUINT32, ir.TUINT64},
+	{"uintptr", ir.TUINTPTR, &Widthptr, ir.TUINT32, ir.TUINT64},
 }
 
 var builtinFuncs = [...]struct {
 	name string
-	op   Op
+	op   ir.Op
 }{
-	{"append", OAPPEND},
-	{"cap", OCAP},
-	{"close", OCLOSE},
-	{"complex", OCOMPLEX},
-	{"copy", OCOPY},
-	{"delete", ODELETE},
-	{"imag", OIMAG},
-	{"len", OLEN},
-	{"make", OMAKE},
-	{"new", ONEW},
-	{"panic", OPANIC},
-	{"print", OPRINT},
-	{"println", OPRINTN},
-	{"real", OREAL},
-	{"recover", ORECOVER},
+	{"append", ir.OAPPEND},
+	{"cap", ir.OCAP},
+	{"close", ir.OCLOSE},
+	{"complex", ir.OCOMPLEX},
+	{"copy", ir.OCOPY},
+	{"delete", ir.ODELETE},
+	{"imag", ir.OIMAG},
+	{"len", ir.OLEN},
+	{"make", ir.OMAKE},
+	{"new", ir.ONEW},
+	{"panic", ir.OPANIC},
+	{"print", ir.OPRINT},
+	{"println", ir.OPRINTN},
+	{"real", ir.OREAL},
+	{"recover", ir.ORECOVER},
 }
 
 // initUniverse initializes the universe block.
@@ -74,73 +76,73 @@ func initUniverse() {
 func lexinit() {
 	for _, s := range basicTypes {
 		etype := s.etype
-		if int(etype) >= len(Types) {
+		if int(etype) >= len(ir.Types) {
 			Fatalf("lexinit: %s bad etype", s.name)
 		}
 		s2 := Pkglookup(s.name, builtinpkg)
-		t := Types[etype]
+		t := ir.Types[etype]
 		if t == nil {
-			t = typ(etype)
+			t = ir.Typ(etype)
 			t.Sym = s2
-			if etype != TANY && etype != TSTRING {
+			if etype != ir.TANY && etype != ir.TSTRING {
 				dowidth(t)
 			}
-			Types[etype] = t
+			ir.Types[etype] = t
 		}
 		s2.Def = typenod(t)
-		s2.Def.Name = new(Name)
+		s2.Def.Name = new(ir.Name)
 	}
 
 	for _, s := range builtinFuncs {
 		// TODO(marvin): Fix Node.EType type union.
 		s2 := Pkglookup(s.name, builtinpkg)
-		s2.Def = Nod(ONAME, nil, nil)
+		s2.Def = Nod(ir.ONAME, nil, nil)
 		s2.Def.Sym = s2
-		s2.Def.Etype = EType(s.op)
+		s2.Def.Etype = ir.EType(s.op)
 	}
 
-	idealstring = typ(TSTRING)
-	idealbool = typ(TBOOL)
+	ir.Idealstring = ir.Typ(ir.TSTRING)
+	ir.Idealbool = ir.Typ(ir.TBOOL)
 
 	s := Pkglookup("true", builtinpkg)
 	s.Def = Nodbool(true)
 	s.Def.Sym = Lookup("true")
-	s.Def.Name = new(Name)
-	s.Def.Type = idealbool
+	s.Def.Name = new(ir.Name)
+	s.Def.Type = ir.Idealbool
 
 	s = Pkglookup("false", builtinpkg)
 	s.Def = Nodbool(false)
 	s.Def.Sym = Lookup("false")
-	s.Def.Name = new(Name)
-	s.Def.Type = idealbool
+	s.Def.Name = new(ir.Name)
+	s.Def.Type = ir.Idealbool
 
 	s = Lookup("_")
 	s.Block = -100
-	s.Def = Nod(ONAME, nil, nil)
+	s.Def = Nod(ir.ONAME, nil, nil)
 	s.Def.Sym = s
-	Types[TBLANK] = typ(TBLANK)
-	s.Def.Type = Types[TBLANK]
+	ir.Types[ir.TBLANK] = ir.Typ(ir.TBLANK)
+	s.Def.Type = ir.Types[ir.TBLANK]
 	nblank = s.Def
 
 	s = Pkglookup("_", builtinpkg)
 	s.Block = -100
-	s.Def = Nod(ONAME, nil, nil)
+	s.Def = Nod(ir.ONAME, nil, nil)
 	s.Def.Sym = s
-	Types[TBLANK] = typ(TBLANK)
-	s.Def.Type = Types[TBLANK]
+	ir.Types[ir.TBLANK] = ir.Typ(ir.TBLANK)
+	s.Def.Type = ir.Types[ir.TBLANK]
 
-	Types[TNIL] = typ(TNIL)
+	ir.Types[ir.TNIL] = ir.Typ(ir.TNIL)
 	s = Pkglookup("nil", builtinpkg)
-	var v Val
-	v.U = new(NilVal)
+	var v ir.Val
+	v.U = new(ir.NilVal)
 	s.Def = nodlit(v)
 	s.Def.Sym = s
-	s.Def.Name = new(Name)
+	s.Def.Name = new(ir.Name)
 
 	s = Pkglookup("iota", builtinpkg)
-	s.Def = Nod(OIOTA, nil, nil)
+	s.Def = Nod(ir.OIOTA, nil, nil)
 	s.Def.Sym = s
-	s.Def.Name = new(Name)
+	s.Def.Name = new(ir.Name)
 }
 
 func typeinit() {
@@ -148,56 +150,56 @@ func typeinit() {
 		Fatalf("typeinit before betypeinit")
 	}
 
-	for et := EType(0); et < NTYPE; et++ {
+	for et := ir.EType(0); et < ir.NTYPE; et++ {
 		Simtype[et] = et
 	}
 
-	Types[TPTR32] = typ(TPTR32)
-	dowidth(Types[TPTR32])
+	ir.Types[ir.TPTR32] = ir.Typ(ir.TPTR32)
+	dowidth(ir.Types[ir.TPTR32])
 
-	Types[TPTR64] = typ(TPTR64)
-	dowidth(Types[TPTR64])
+	ir.Types[ir.TPTR64] = ir.Typ(ir.TPTR64)
+	dowidth(ir.Types[ir.TPTR64])
 
-	t := typ(TUNSAFEPTR)
-	Types[TUNSAFEPTR] = t
+	t := ir.Typ(ir.TUNSAFEPTR)
+	ir.Types[ir.TUNSAFEPTR] = t
 	t.Sym = Pkglookup("Pointer", unsafepkg)
 	t.Sym.Def = typenod(t)
-	t.Sym.Def.Name = new(Name)
+	t.Sym.Def.Name = new(ir.Name)
 
-	dowidth(Types[TUNSAFEPTR])
+	dowidth(ir.Types[ir.TUNSAFEPTR])
 
-	Tptr = TPTR32
+	Tptr = ir.TPTR32
 	if Widthptr == 8 {
-		Tptr = TPTR64
+		Tptr = ir.TPTR64
 	}
 
-	for et := TINT8; et <= TUINT64; et++ {
+	for et := ir.TINT8; et <= ir.TUINT64; et++ {
 		Isint[et] = true
 	}
-	Isint[TINT] = true
-	Isint[TUINT] = true
-	Isint[TUINTPTR] = true
+	Isint[ir.TINT] = true
+	Isint[ir.TUINT] = true
+	Isint[ir.TUINTPTR] = true
 
-	Isfloat[TFLOAT32] = true
-	Isfloat[TFLOAT64] = true
+	Isfloat[ir.TFLOAT32] = true
+	Isfloat[ir.TFLOAT64] = true
 
-	Iscomplex[TCOMPLEX64] = true
-	Iscomplex[TCOMPLEX128] = true
+	Iscomplex[ir.TCOMPLEX64] = true
+	Iscomplex[ir.TCOMPLEX128] = true
 
-	Isptr[TPTR32] = true
-	Isptr[TPTR64] = true
+	Isptr[ir.TPTR32] = true
+	Isptr[ir.TPTR64] = true
 
-	isforw[TFORW] = true
+	isforw[ir.TFORW] = true
 
-	Issigned[TINT] = true
-	Issigned[TINT8] = true
-	Issigned[TINT16] = true
-	Issigned[TINT32] = true
-	Issigned[TINT64] = true
+	Issigned[ir.TINT] = true
+	Issigned[ir.TINT8] = true
+	Issigned[ir.TINT16] = true
+	Issigned[ir.TINT32] = true
+	Issigned[ir.TINT64] = true
 
 	// initialize okfor
-	for et := EType(0); et < NTYPE; et++ {
-		if Isint[et] || et == TIDEAL {
+	for et := ir.EType(0); et < ir.NTYPE; et++ {
+		if Isint[et] || et == ir.TIDEAL {
 			okforeq[et] = true
 			okforcmp[et] = true
 			okforarith[et] = true
@@ -205,8 +207,8 @@ func typeinit() {
 			okforand[et] = true
 			okforconst[et] = true
 			issimple[et] = true
-			Minintval[et] = new(Mpint)
-			Maxintval[et] = new(Mpint)
+			Minintval[et] = new(ir.Mpint)
+			Maxintval[et] = new(ir.Mpint)
 		}
 
 		if Isfloat[et] {
@@ -216,8 +218,8 @@ func typeinit() {
 			okforarith[et] = true
 			okforconst[et] = true
 			issimple[et] = true
-			minfltval[et] = newMpflt()
-			maxfltval[et] = newMpflt()
+			minfltval[et] = ir.NewMpflt()
+			maxfltval[et] = ir.NewMpflt()
 		}
 
 		if Iscomplex[et] {
@@ -229,36 +231,36 @@ func typeinit() {
 		}
 	}
 
-	issimple[TBOOL] = true
+	issimple[ir.TBOOL] = true
 
-	okforadd[TSTRING] = true
+	okforadd[ir.TSTRING] = true
 
-	okforbool[TBOOL] = true
+	okforbool[ir.TBOOL] = true
 
-	okforcap[TARRAY] = true
-	okforcap[TCHAN] = true
+	okforcap[ir.TARRAY] = true
+	okforcap[ir.TCHAN] = true
 
-	okforconst[TBOOL] = true
-	okforconst[TSTRING] = true
+	okforconst[ir.TBOOL] = true
+	okforconst[ir.TSTRING] = true
 
-	okforlen[TARRAY] = true
-	okforlen[TCHAN] = true
-	okforlen[TMAP] = true
-	okforlen[TSTRING] = true
+	okforlen[ir.TARRAY] = true
+	okforlen[ir.TCHAN] = true
+	okforlen[ir.TMAP] = true
+	okforlen[ir.TSTRING] = true
 
-	okforeq[TPTR32] = true
-	okforeq[TPTR64] = true
-	okforeq[TUNSAFEPTR] = true
-	okforeq[TINTER] = true
-	okforeq[TCHAN] = true
-	okforeq[TSTRING] = true
-	okforeq[TBOOL] = true
-	okforeq[TMAP] = true    // nil only; refined in typecheck
-	okforeq[TFUNC] = true   // nil only; refined in typecheck
-	okforeq[TARRAY] = true  // nil slice only; refined in typecheck
-	okforeq[TSTRUCT] = true // it's complicated; refined in typecheck
+	okforeq[ir.TPTR32] = true
+	okforeq[ir.TPTR64] = true
+	okforeq[ir.TUNSAFEPTR] = true
+	okforeq[ir.TINTER] = true
+	okforeq[ir.TCHAN] = true
+	okforeq[ir.TSTRING] = true
+	okforeq[ir.TBOOL] = true
+	okforeq[ir.TMAP] = true    // nil only; refined in typecheck
+	okforeq[ir.TFUNC] = true   // nil only; refined in typecheck
+	okforeq[ir.TARRAY] = true  // nil slice only; refined in typecheck
+	okforeq[ir.TSTRUCT] = true // it's complicated; refined in typecheck
 
-	okforcmp[TSTRING] = true
+	okforcmp[ir.TSTRING] = true
 
 	var i int
 	for i = 0; i < len(okfor); i++ {
@@ -266,89 +268,89 @@ func typeinit() {
 	}
 
 	// binary
-	okfor[OADD] = okforadd[:]
-
-	okfor[OAND] = okforand[:]
-	okfor[OANDAND] = okforbool[:]
-	okfor[OANDNOT] = okforand[:]
-	okfor[ODIV] = okforarith[:]
-	okfor[OEQ] = okforeq[:]
-	okfor[OGE] = okforcmp[:]
-	okfor[OGT] = okforcmp[:]
-	okfor[OLE] = okforcmp[:]
-	okfor[OLT] = okforcmp[:]
-	okfor[OMOD] = okforand[:]
-	okfor[OHMUL] = okforarith[:]
-	okfor[OMUL] = okforarith[:]
-	okfor[ONE] = okforeq[:]
-	okfor[OOR] = okforand[:]
-	okfor[OOROR] = okforbool[:]
-	okfor[OSUB] = okforarith[:]
-	okfor[OXOR] = okforand[:]
-	okfor[OLSH] = okforand[:]
-	okfor[ORSH] = okforand[:]
+	okfor[ir.OADD] = okforadd[:]
+
+	okfor[ir.OAND] = okforand[:]
+	okfor[ir.OANDAND] = okforbool[:]
+	okfor[ir.OANDNOT] = okforand[:]
+	okfor[ir.ODIV] = okforarith[:]
+	okfor[ir.OEQ] = okforeq[:]
+	okfor[ir.OGE] = okforcmp[:]
+	okfor[ir.OGT] = okforcmp[:]
+	okfor[ir.OLE] = okforcmp[:]
+	okfor[ir.OLT] = okforcmp[:]
+	okfor[ir.OMOD] = okforand[:]
+	okfor[ir.OHMUL] = okforarith[:]
+	okfor[ir.OMUL] = okforarith[:]
+	okfor[ir.ONE] = okforeq[:]
+	okfor[ir.OOR] = okforand[:]
+	okfor[ir.OOROR] = okforbool[:]
+	okfor[ir.OSUB] = okforarith[:]
+	okfor[ir.OXOR] = okforand[:]
+	okfor[ir.OLSH] = okforand[:]
+	okfor[ir.ORSH] = okforand[:]
 
 	// unary
-	okfor[OCOM] = okforand[:]
+	okfor[ir.OCOM] = okforand[:]
 
-	okfor[OMINUS] = okforarith[:]
-	okfor[ONOT] = okforbool[:]
-	okfor[OPLUS] = okforarith[:]
+	okfor[ir.OMINUS] = okforarith[:]
+	okfor[ir.ONOT] = okforbool[:]
+	okfor[ir.OPLUS] = okforarith[:]
 
 	// special
-	okfor[OCAP] = okforcap[:]
+	okfor[ir.OCAP] = okforcap[:]
 
-	okfor[OLEN] = okforlen[:]
+	okfor[ir.OLEN] = okforlen[:]
 
 	// comparison
-	iscmp[OLT] = true
-
-	iscmp[OGT] = true
-	iscmp[OGE] = true
-	iscmp[OLE] = true
-	iscmp[OEQ] = true
-	iscmp[ONE] = true
-
-	Maxintval[TINT8].SetString("0x7f")
-	Minintval[TINT8].SetString("-0x80")
-	Maxintval[TINT16].SetString("0x7fff")
-	Minintval[TINT16].SetString("-0x8000")
-	Maxintval[TINT32].SetString("0x7fffffff")
-	Minintval[TINT32].SetString("-0x80000000")
-	Maxintval[TINT64].SetString("0x7fffffffffffffff")
-	Minintval[TINT64].SetString("-0x8000000000000000")
-
-	Maxintval[TUINT8].SetString("0xff")
-	Maxintval[TUINT16].SetString("0xffff")
-	Maxintval[TUINT32].SetString("0xffffffff")
-	Maxintval[TUINT64].SetString("0xffffffffffffffff")
+	iscmp[ir.OLT] = true
+
+	iscmp[ir.OGT] = true
+	iscmp[ir.OGE] = true
+	iscmp[ir.OLE] = true
+	iscmp[ir.OEQ] = true
+	iscmp[ir.ONE] = true
+
+	Maxintval[ir.TINT8].SetString("0x7f")
+	Minintval[ir.TINT8].SetString("-0x80")
+	Maxintval[ir.TINT16].SetString("0x7fff")
+	Minintval[ir.TINT16].SetString("-0x8000")
+	Maxintval[ir.TINT32].SetString("0x7fffffff")
+	Minintval[ir.TINT32].SetString("-0x80000000")
+	Maxintval[ir.TINT64].SetString("0x7fffffffffffffff")
+	Minintval[ir.TINT64].SetString("-0x8000000000000000")
+
+	Maxintval[ir.TUINT8].SetString("0xff")
+	Maxintval[ir.TUINT16].SetString("0xffff")
+	Maxintval[ir.TUINT32].SetString("0xffffffff")
+	Maxintval[ir.TUINT64].SetString("0xffffffffffffffff")
 
 	// f is valid float if min < f < max.  (min and max are not themselves valid.)
-	maxfltval[TFLOAT32].SetString("33554431p103") // 2^24-1 p (127-23) + 1/2 ulp
-	minfltval[TFLOAT32].SetString("-33554431p103")
-	maxfltval[TFLOAT64].SetString("18014398509481983p970") // 2^53-1 p (1023-52) + 1/2 ulp
-	minfltval[TFLOAT64].SetString("-18014398509481983p970")
+	maxfltval[ir.TFLOAT32].SetString("33554431p103") // 2^24-1 p (127-23) + 1/2 ulp
+	minfltval[ir.TFLOAT32].SetString("-33554431p103")
+	maxfltval[ir.TFLOAT64].SetString("18014398509481983p970") // 2^53-1 p (1023-52) + 1/2 ulp
+	minfltval[ir.TFLOAT64].SetString("-18014398509481983p970")
 
-	maxfltval[TCOMPLEX64] = maxfltval[TFLOAT32]
-	minfltval[TCOMPLEX64] = minfltval[TFLOAT32]
-	maxfltval[TCOMPLEX128] = maxfltval[TFLOAT64]
-	minfltval[TCOMPLEX128] = minfltval[TFLOAT64]
+	maxfltval[ir.TCOMPLEX64] = maxfltval[ir.TFLOAT32]
+	minfltval[ir.TCOMPLEX64] = minfltval[ir.TFLOAT32]
+	maxfltval[ir.TCOMPLEX128] = maxfltval[ir.TFLOAT64]
+	minfltval[ir.TCOMPLEX128] = minfltval[ir.TFLOAT64]
 
 	// for walk to use in error messages
-	Types[TFUNC] = functype(nil, nil, nil)
+	ir.Types[ir.TFUNC] = functype(nil, nil, nil)
 
 	// types used in front end
 	// types[TNIL] got set early in lexinit
-	Types[TIDEAL] = typ(TIDEAL)
+	ir.Types[ir.TIDEAL] = ir.Typ(ir.TIDEAL)
 
-	Types[TINTER] = typ(TINTER)
+	ir.Types[ir.TINTER] = ir.Typ(ir.TINTER)
 
 	// simple aliases
-	Simtype[TMAP] = Tptr
+	Simtype[ir.TMAP] = Tptr
 
-	Simtype[TCHAN] = Tptr
-	Simtype[TFUNC] = Tptr
-	Simtype[TUNSAFEPTR] = Tptr
+	Simtype[ir.TCHAN] = Tptr
+	Simtype[ir.TFUNC] = Tptr
+	Simtype[ir.TUNSAFEPTR] = Tptr
 
 	Array_array = int(Rnd(0, int64(Widthptr)))
 	Array_nel = int(Rnd(int64(Array_array)+int64(Widthptr), int64(Widthint)))
@@ -358,61 +360,61 @@ func typeinit() {
 	// string is same as slice wo the cap
 	sizeof_String = int(Rnd(int64(Array_nel)+int64(Widthint), int64(Widthptr)))
 
-	dowidth(Types[TSTRING])
-	dowidth(idealstring)
+	dowidth(ir.Types[ir.TSTRING])
+	dowidth(ir.Idealstring)
 
-	itable = typ(Tptr)
-	itable.Type = Types[TUINT8]
+	itable = ir.Typ(Tptr)
+	itable.Type = ir.Types[ir.TUINT8]
 }
 
 func lexinit1() {
 	// t = interface { Error() string }
 
-	rcvr := typ(TSTRUCT)
+	rcvr := ir.Typ(ir.TSTRUCT)
 	rcvr.Funarg = true
-	field := newField()
-	field.Type = Ptrto(typ(TSTRUCT))
-	rcvr.SetFields([]*Field{field})
+	field := ir.NewField()
+	field.Type = Ptrto(ir.Typ(ir.TSTRUCT))
+	rcvr.SetFields([]*ir.Field{field})
 
-	in := typ(TSTRUCT)
+	in := ir.Typ(ir.TSTRUCT)
 	in.Funarg = true
 
-	out := typ(TSTRUCT)
+	out := ir.Typ(ir.TSTRUCT)
 	out.Funarg = true
-	field = newField()
-	field.Type = Types[TSTRING]
-	out.SetFields([]*Field{field})
+	field = ir.NewField()
+	field.Type = ir.Types[ir.TSTRING]
+	out.SetFields([]*ir.Field{field})
 
-	f := typ(TFUNC)
+	f := ir.Typ(ir.TFUNC)
 	*f.RecvsP() = rcvr
 	*f.ResultsP() = out
 	*f.ParamsP() = in
 
-	t := typ(TINTER)
-	field = newField()
+	t := ir.Typ(ir.TINTER)
+	field = ir.NewField()
 	field.Sym = Lookup("Error")
 	field.Type = f
-	t.SetFields([]*Field{field})
+	t.SetFields([]*ir.Field{field})
 
 	// error type
 	s := Pkglookup("error", builtinpkg)
-	errortype = t
-	errortype.Sym = s
-	s.Def = typenod(errortype)
+	ir.Errortype = t
+	ir.Errortype.Sym = s
+	s.Def = typenod(ir.Errortype)
 
 	// byte alias
 	s = Pkglookup("byte", builtinpkg)
-	bytetype = typ(TUINT8)
-	bytetype.Sym = s
-	s.Def = typenod(bytetype)
-	s.Def.Name = new(Name)
+	ir.Bytetype = ir.Typ(ir.TUINT8)
+	ir.Bytetype.Sym = s
+	s.Def = typenod(ir.Bytetype)
+	s.Def.Name = new(ir.Name)
 
 	// rune alias
 	s = Pkglookup("rune", builtinpkg)
-	runetype = typ(TINT32)
-	runetype.Sym = s
-	s.Def = typenod(runetype)
-	s.Def.Name = new(Name)
+	ir.Runetype = ir.Typ(ir.TINT32)
+	ir.Runetype.Sym = s
+	s.Def = typenod(ir.Runetype)
+	s.Def.Name = new(ir.Name)
 
 	// backend-dependent builtin types (e.g. int).
 	for _, s := range typedefs {
@@ -429,11 +431,11 @@ func lexinit1() {
 		Minintval[s.etype] = Minintval[sameas]
 		Maxintval[s.etype] = Maxintval[sameas]
 
-		t := typ(s.etype)
+		t := ir.Typ(s.etype)
 		t.Sym = s1
-		Types[s.etype] = t
+		ir.Types[s.etype] = t
 		s1.Def = typenod(t)
-		s1.Def.Name = new(Name)
+		s1.Def.Name = new(ir.Name)
 		s1.Origpkg = builtinpkg
 
 		dowidth(t)
@@ -446,7 +448,7 @@ func finishUniverse() {
 	// that we silently skip symbols that are already declared in the
 	// package block rather than emitting a redeclared symbol error.
 
-	for _, s := range builtinpkg.Syms {
+	for _, s := range builtinpkg.Syms() {
 		if s.Def == nil || (s.Name == "any" && Debug['A'] == 0) {
 			continue
 		}
@@ -459,9 +461,9 @@ func finishUniverse() {
 		s1.Block = s.Block
 	}
 
-	nodfp = Nod(ONAME, nil, nil)
-	nodfp.Type = Types[TINT32]
+	nodfp = Nod(ir.ONAME, nil, nil)
+	nodfp.Type = ir.Types[ir.TINT32]
 	nodfp.Xoffset = 0
-	nodfp.Class = PPARAM
+	nodfp.Class = ir.PPARAM
 	nodfp.Sym = Lookup(".fp")
 }
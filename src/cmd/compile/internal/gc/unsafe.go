@@ -4,12 +4,14 @@
 
 package gc
 
+import "cmd/compile/internal/ir"
+
 // unsafenmagic rewrites calls to package unsafe's functions into constants.
-func unsafenmagic(nn *Node) *Node {
+func unsafenmagic(nn *ir.Node) *ir.Node {
 	fn := nn.Left
 	args := nn.List
 
-	if safemode != 0 || fn == nil || fn.Op != ONAME {
+	if safemode != 0 || fn == nil || fn.Op != ir.ONAME {
 		return nil
 	}
 	s := fn.Sym
@@ -45,7 +47,7 @@ func unsafenmagic(nn *Node) *Node {
 
 	case "Offsetof":
 		// must be a selector.
-		if r.Op != OXDOT {
+		if r.Op != ir.OXDOT {
 			goto bad
 		}
 
@@ -57,9 +59,9 @@ func unsafenmagic(nn *Node) *Node {
 
 		r = typecheck(r, Erv)
 		switch r.Op {
-		case ODOT, ODOTPTR:
+		case ir.ODOT, ir.ODOTPTR:
 			break
-		case OCALLPART:
+		case ir.OCALLPART:
 			Yyerror("invalid expression %v: argument is a method value", nn)
 			goto ret
 		default:
@@ -69,7 +71,7 @@ func unsafenmagic(nn *Node) *Node {
 		// Sum offsets for dots until we reach base.
 		for r1 := r; r1 != base; r1 = r1.Left {
 			switch r1.Op {
-			case ODOTPTR:
+			case ir.ODOTPTR:
 				// For Offsetof(s.f), s may itself be a pointer,
 				// but accessing f must not otherwise involve
 				// indirection via embedded pointer types.
@@ -78,11 +80,11 @@ func unsafenmagic(nn *Node) *Node {
 					goto ret
 				}
 				fallthrough
-			case ODOT:
+			case ir.ODOT:
 				v += r1.Xoffset
 			default:
 				Dump("unsafenmagic", r)
-				Fatalf("impossible %v node after dot insertion", Oconv(r1.Op, FmtSharp))
+				Fatalf("impossible %v node after dot insertion", Oconv(r1.Op, ir.FmtSharp))
 				goto bad
 			}
 		}
@@ -101,19 +103,19 @@ bad:
 
 ret:
 	// any side effects disappear; ignore init
-	var val Val
-	val.U = new(Mpint)
-	val.U.(*Mpint).SetInt64(v)
-	n := Nod(OLITERAL, nil, nil)
+	var val ir.Val
+	val.U = new(ir.Mpint)
+	val.U.(*ir.Mpint).SetInt64(v)
+	n := Nod(ir.OLITERAL, nil, nil)
 	n.Orig = nn
 	n.SetVal(val)
-	n.Type = Types[TUINTPTR]
-	nn.Type = Types[TUINTPTR]
+	n.Type = ir.Types[ir.TUINTPTR]
+	nn.Type = ir.Types[ir.TUINTPTR]
 	return n
 }
 
-func isunsafebuiltin(n *Node) bool {
-	if n == nil || n.Op != ONAME || n.Sym == nil || n.Sym.Pkg != unsafepkg {
+func isunsafebuiltin(n *ir.Node) bool {
+	if n == nil || n.Op != ir.ONAME || n.Sym == nil || n.Sym.Pkg != unsafepkg {
 		return false
 	}
 	if n.Sym.Name == "Sizeof" {
@@ -10,10 +10,6 @@ import (
 	"runtime/pprof"
 )
 
-func (n *Node) Line() string {
-	return Ctxt.LineHist.LineString(int(n.Lineno))
-}
-
 var atExitFuncs []func()
 
 func AtExit(f func()) {
@@ -5,6 +5,7 @@
 package gc
 
 import (
+	"cmd/compile/internal/ir"
 	"cmd/internal/obj"
 	"fmt"
 	"strings"
@@ -15,7 +16,7 @@ const (
 	tmpstringbufsize = 32
 )
 
-func walk(fn *Node) {
+func walk(fn *ir.Node) {
 	Curfn = fn
 
 	if Debug['W'] != 0 {
@@ -28,7 +29,7 @@ func walk(fn *Node) {
 	// Final typecheck for any unused variables.
 	// It's hard to be on the heap when not-used, but best to be consistent about &~PHEAP here and below.
 	for i, ln := range fn.Func.Dcl {
-		if ln.Op == ONAME && ln.Class&^PHEAP == PAUTO {
+		if ln.Op == ir.ONAME && ln.Class&^ir.PHEAP == ir.PAUTO {
 			ln = typecheck(ln, Erv|Easgn)
 			fn.Func.Dcl[i] = ln
 		}
@@ -36,16 +37,16 @@ func walk(fn *Node) {
 
 	// Propagate the used flag for typeswitch variables up to the NONAME in it's definition.
 	for _, ln := range fn.Func.Dcl {
-		if ln.Op == ONAME && ln.Class&^PHEAP == PAUTO && ln.Name.Defn != nil && ln.Name.Defn.Op == OTYPESW && ln.Used {
+		if ln.Op == ir.ONAME && ln.Class&^ir.PHEAP == ir.PAUTO && ln.Name.Defn != nil && ln.Name.Defn.Op == ir.OTYPESW && ln.Used {
 			ln.Name.Defn.Left.Used = true
 		}
 	}
 
 	for _, ln := range fn.Func.Dcl {
-		if ln.Op != ONAME || ln.Class&^PHEAP != PAUTO || ln.Sym.Name[0] == '&' || ln.Used {
+		if ln.Op != ir.ONAME || ln.Class&^ir.PHEAP != ir.PAUTO || ln.Sym.Name[0] == '&' || ln.Used {
 			continue
 		}
-		if defn := ln.Name.Defn; defn != nil && defn.Op == OTYPESW {
+		if defn := ln.Name.Defn; defn != nil && defn.Op == ir.OTYPESW {
 			if defn.Left.Used {
 				continue
 			}
@@ -75,13 +76,13 @@ func walk(fn *Node) {
 	}
 }
 
-func walkstmtlist(s []*Node) {
+func walkstmtlist(s []*ir.Node) {
 	for i := range s {
 		s[i] = walkstmt(s[i])
 	}
 }
 
-func samelist(a, b []*Node) bool {
+func samelist(a, b []*ir.Node) bool {
 	if len(a) != len(b) {
 		return false
 	}
@@ -93,16 +94,16 @@ func samelist(a, b []*Node) bool {
 	return true
 }
 
-func paramoutheap(fn *Node) bool {
+func paramoutheap(fn *ir.Node) bool {
 	for _, ln := range fn.Func.Dcl {
 		switch ln.Class {
-		case PPARAMOUT,
-			PPARAMOUT | PHEAP:
+		case ir.PPARAMOUT,
+			ir.PPARAMOUT | ir.PHEAP:
 			return ln.Addrtaken
 
 			// stop early - parameters are over
-		case PAUTO,
-			PAUTO | PHEAP:
+		case ir.PAUTO,
+			ir.PAUTO | ir.PHEAP:
 			return false
 		}
 	}
@@ -112,23 +113,23 @@ func paramoutheap(fn *Node) bool {
 
 // adds "adjust" to all the argument locations for the call n.
 // n must be a defer or go node that has already been walked.
-func adjustargs(n *Node, adjust int) {
-	var arg *Node
-	var lhs *Node
+func adjustargs(n *ir.Node, adjust int) {
+	var arg *ir.Node
+	var lhs *ir.Node
 
 	callfunc := n.Left
 	for _, arg = range callfunc.List.Slice() {
-		if arg.Op != OAS {
+		if arg.Op != ir.OAS {
 			Yyerror("call arg not assignment")
 		}
 		lhs = arg.Left
-		if lhs.Op == ONAME {
+		if lhs.Op == ir.ONAME {
 			// This is a temporary introduced by reorder1.
 			// The real store to the stack appears later in the arg list.
 			continue
 		}
 
-		if lhs.Op != OINDREG {
+		if lhs.Op != ir.OINDREG {
 			Yyerror("call argument store does not use OINDREG")
 		}
 
@@ -141,13 +142,14 @@ func adjustargs(n *Node, adjust int) {
 
 // The result of walkstmt MUST be assigned back to n, e.g.
 // 	n.Left = walkstmt(n.Left)
-func walkstmt(n *Node) *Node {
+func walkstmt(n *ir.Node) *ir.Node {
 	if n == nil {
 		return n
 	}
 	if n.Dodata == 2 { // don't walk, generated by anylit.
 		return n
 	}
+	counts.walked++
 
 	setlineno(n)
 
@@ -155,51 +157,51 @@ func walkstmt(n *Node) *Node {
 
 	switch n.Op {
 	default:
-		if n.Op == ONAME {
+		if n.Op == ir.ONAME {
 			Yyerror("%v is not a top level statement", n.Sym)
 		} else {
 			Yyerror("%v is not a top level statement", Oconv(n.Op, 0))
 		}
 		Dump("nottop", n)
 
-	case OAS,
-		OASOP,
-		OAS2,
-		OAS2DOTTYPE,
-		OAS2RECV,
-		OAS2FUNC,
-		OAS2MAPR,
-		OCLOSE,
-		OCOPY,
-		OCALLMETH,
-		OCALLINTER,
-		OCALL,
-		OCALLFUNC,
-		ODELETE,
-		OSEND,
-		OPRINT,
-		OPRINTN,
-		OPANIC,
-		OEMPTY,
-		ORECOVER,
-		OGETG:
+	case ir.OAS,
+		ir.OASOP,
+		ir.OAS2,
+		ir.OAS2DOTTYPE,
+		ir.OAS2RECV,
+		ir.OAS2FUNC,
+		ir.OAS2MAPR,
+		ir.OCLOSE,
+		ir.OCOPY,
+		ir.OCALLMETH,
+		ir.OCALLINTER,
+		ir.OCALL,
+		ir.OCALLFUNC,
+		ir.ODELETE,
+		ir.OSEND,
+		ir.OPRINT,
+		ir.OPRINTN,
+		ir.OPANIC,
+		ir.OEMPTY,
+		ir.ORECOVER,
+		ir.OGETG:
 		if n.Typecheck == 0 {
-			Fatalf("missing typecheck: %v", Nconv(n, FmtSign))
+			Fatalf("missing typecheck: %v", Nconv(n, ir.FmtSign))
 		}
-		wascopy := n.Op == OCOPY
+		wascopy := n.Op == ir.OCOPY
 		init := n.Ninit
 		n.Ninit.Set(nil)
 		n = walkexpr(n, &init)
 		n = addinit(n, init.Slice())
-		if wascopy && n.Op == OCONVNOP {
-			n.Op = OEMPTY // don't leave plain values as statements.
+		if wascopy && n.Op == ir.OCONVNOP {
+			n.Op = ir.OEMPTY // don't leave plain values as statements.
 		}
 
 		// special case for a receive where we throw away
 	// the value received.
-	case ORECV:
+	case ir.ORECV:
 		if n.Typecheck == 0 {
-			Fatalf("missing typecheck: %v", Nconv(n, FmtSign))
+			Fatalf("missing typecheck: %v", Nconv(n, ir.FmtSign))
 		}
 		init := n.Ninit
 		n.Ninit.Set(nil)
@@ -210,37 +212,37 @@ func walkstmt(n *Node) *Node {
 
 		n = addinit(n, init.Slice())
 
-	case OBREAK,
-		ODCL,
-		OCONTINUE,
-		OFALL,
-		OGOTO,
-		OLABEL,
-		ODCLCONST,
-		ODCLTYPE,
-		OCHECKNIL,
-		OVARKILL,
-		OVARLIVE:
+	case ir.OBREAK,
+		ir.ODCL,
+		ir.OCONTINUE,
+		ir.OFALL,
+		ir.OGOTO,
+		ir.OLABEL,
+		ir.ODCLCONST,
+		ir.ODCLTYPE,
+		ir.OCHECKNIL,
+		ir.OVARKILL,
+		ir.OVARLIVE:
 		break
 
-	case OBLOCK:
+	case ir.OBLOCK:
 		walkstmtlist(n.List.Slice())
 
-	case OXCASE:
+	case ir.OXCASE:
 		Yyerror("case statement out of place")
-		n.Op = OCASE
+		n.Op = ir.OCASE
 		fallthrough
 
-	case OCASE:
+	case ir.OCASE:
 		n.Right = walkstmt(n.Right)
 
-	case ODEFER:
+	case ir.ODEFER:
 		hasdefer = true
 		switch n.Left.Op {
-		case OPRINT, OPRINTN:
+		case ir.OPRINT, ir.OPRINTN:
 			n.Left = walkprintfunc(n.Left, &n.Ninit)
 
-		case OCOPY:
+		case ir.OCOPY:
 			n.Left = copyany(n.Left, &n.Ninit, true)
 
 		default:
@@ -250,7 +252,7 @@ func walkstmt(n *Node) *Node {
 		// make room for size & fn arguments.
 		adjustargs(n, 2*Widthptr)
 
-	case OFOR:
+	case ir.OFOR:
 		if n.Left != nil {
 			walkstmtlist(n.Left.Ninit.Slice())
 			init := n.Left.Ninit
@@ -262,17 +264,17 @@ func walkstmt(n *Node) *Node {
 		n.Right = walkstmt(n.Right)
 		walkstmtlist(n.Nbody.Slice())
 
-	case OIF:
+	case ir.OIF:
 		n.Left = walkexpr(n.Left, &n.Ninit)
 		walkstmtlist(n.Nbody.Slice())
 		walkstmtlist(n.Rlist.Slice())
 
-	case OPROC:
+	case ir.OPROC:
 		switch n.Left.Op {
-		case OPRINT, OPRINTN:
+		case ir.OPRINT, ir.OPRINTN:
 			n.Left = walkprintfunc(n.Left, &n.Ninit)
 
-		case OCOPY:
+		case ir.OCOPY:
 			n.Left = copyany(n.Left, &n.Ninit, true)
 
 		default:
@@ -282,7 +284,7 @@ func walkstmt(n *Node) *Node {
 		// make room for size & fn arguments.
 		adjustargs(n, 2*Widthptr)
 
-	case ORETURN:
+	case ir.ORETURN:
 		walkexprlist(n.List.Slice(), &n.Ninit)
 		if n.List.Len() == 0 {
 			break
@@ -290,15 +292,15 @@ func walkstmt(n *Node) *Node {
 		if (Curfn.Type.Outnamed && n.List.Len() > 1) || paramoutheap(Curfn) {
 			// assign to the function out parameters,
 			// so that reorder3 can fix up conflicts
-			var rl []*Node
+			var rl []*ir.Node
 
-			var cl Class
+			var cl ir.Class
 			for _, ln := range Curfn.Func.Dcl {
-				cl = ln.Class &^ PHEAP
-				if cl == PAUTO {
+				cl = ln.Class &^ ir.PHEAP
+				if cl == ir.PAUTO {
 					break
 				}
-				if cl == PPARAMOUT {
+				if cl == ir.PPARAMOUT {
 					rl = append(rl, ln)
 				}
 			}
@@ -321,41 +323,37 @@ func walkstmt(n *Node) *Node {
 
 			ll := ascompatee(n.Op, rl, n.List.Slice(), &n.Ninit)
 			n.List.Set(reorder3(ll))
-			ls := n.List.Slice()
-			for i, n := range ls {
-				ls[i] = applywritebarrier(n)
-			}
 			break
 		}
 
 		ll := ascompatte(n.Op, nil, false, Curfn.Type.Results(), n.List.Slice(), 1, &n.Ninit)
 		n.List.Set(ll)
 
-	case ORETJMP:
+	case ir.ORETJMP:
 		break
 
-	case OSELECT:
+	case ir.OSELECT:
 		walkselect(n)
 
-	case OSWITCH:
+	case ir.OSWITCH:
 		walkswitch(n)
 
-	case ORANGE:
+	case ir.ORANGE:
 		walkrange(n)
 
-	case OXFALL:
+	case ir.OXFALL:
 		Yyerror("fallthrough statement out of place")
-		n.Op = OFALL
+		n.Op = ir.OFALL
 	}
 
-	if n.Op == ONAME {
-		Fatalf("walkstmt ended up with name: %v", Nconv(n, FmtSign))
+	if n.Op == ir.ONAME {
+		Fatalf("walkstmt ended up with name: %v", Nconv(n, ir.FmtSign))
 	}
 	return n
 }
 
-func isSmallMakeSlice(n *Node) bool {
-	if n.Op != OMAKESLICE {
+func isSmallMakeSlice(n *ir.Node) bool {
+	if n.Op != ir.OMAKESLICE {
 		return false
 	}
 	l := n.Left
@@ -365,7 +363,7 @@ func isSmallMakeSlice(n *Node) bool {
 	}
 	t := n.Type
 
-	return Smallintconst(l) && Smallintconst(r) && (t.Type.Width == 0 || r.Val().U.(*Mpint).Int64() < (1<<16)/t.Type.Width)
+	return Smallintconst(l) && Smallintconst(r) && (t.Type.Width == 0 || r.Val().U.(*ir.Mpint).Int64() < (1<<16)/t.Type.Width)
 }
 
 // walk the whole tree of the body of an
@@ -373,20 +371,20 @@ func isSmallMakeSlice(n *Node) bool {
 // the types expressions are calculated.
 // compile-time constants are evaluated.
 // complex side effects like statements are appended to init
-func walkexprlist(s []*Node, init *Nodes) {
+func walkexprlist(s []*ir.Node, init *ir.Nodes) {
 	for i := range s {
 		s[i] = walkexpr(s[i], init)
 	}
 }
 
-func walkexprlistsafe(s []*Node, init *Nodes) {
+func walkexprlistsafe(s []*ir.Node, init *ir.Nodes) {
 	for i, n := range s {
 		s[i] = safeexpr(n, init)
 		s[i] = walkexpr(s[i], init)
 	}
 }
 
-func walkexprlistcheap(s []*Node, init *Nodes) {
+func walkexprlistcheap(s []*ir.Node, init *ir.Nodes) {
 	for i, n := range s {
 		s[i] = cheapexpr(n, init)
 		s[i] = walkexpr(s[i], init)
@@ -396,9 +394,9 @@ func walkexprlistcheap(s []*Node, init *Nodes) {
 // Build name of function: convI2E etc.
 // Not all names are possible
 // (e.g., we'll never generate convE2E or convE2I).
-func convFuncName(from, to *Type) string {
-	tkind := to.iet()
-	switch from.iet() {
+func convFuncName(from, to *ir.Type) string {
+	tkind := iet(to)
+	switch iet(from) {
 	case 'I':
 		switch tkind {
 		case 'E':
@@ -414,19 +412,19 @@ func convFuncName(from, to *Type) string {
 			return "convT2I"
 		}
 	}
-	Fatalf("unknown conv func %c2%c", from.iet(), to.iet())
+	Fatalf("unknown conv func %c2%c", iet(from), iet(to))
 	panic("unreachable")
 }
 
 // Build name of function: assertI2E etc.
 // If with2suffix is true, the form ending in "2" is returned".
-func assertFuncName(from, to *Type, with2suffix bool) string {
+func assertFuncName(from, to *ir.Type, with2suffix bool) string {
 	l := len("assertX2X2")
 	if !with2suffix {
 		l--
 	}
-	tkind := to.iet()
-	switch from.iet() {
+	tkind := iet(to)
+	switch iet(from) {
 	case 'E':
 		switch tkind {
 		case 'I':
@@ -446,13 +444,13 @@ func assertFuncName(from, to *Type, with2suffix bool) string {
 			return "assertI2T2"[:l]
 		}
 	}
-	Fatalf("unknown assert func %c2%c", from.iet(), to.iet())
+	Fatalf("unknown assert func %c2%c", iet(from), iet(to))
 	panic("unreachable")
 }
 
 // The result of walkexpr MUST be assigned back to n, e.g.
 // 	n.Left = walkexpr(n.Left, init)
-func walkexpr(n *Node, init *Nodes) *Node {
+func walkexpr(n *ir.Node, init *ir.Nodes) *ir.Node {
 	if n == nil {
 		return n
 	}
@@ -463,6 +461,7 @@ func walkexpr(n *Node, init *Nodes) *Node {
 		// and would lose the init list.
 		Fatalf("walkexpr init == &n->ninit")
 	}
+	counts.walked++
 
 	if n.Ninit.Len() != 0 {
 		walkstmtlist(n.Ninit.Slice())
@@ -470,7 +469,7 @@ func walkexpr(n *Node, init *Nodes) *Node {
 	}
 
 	// annoying case - not typechecked
-	if n.Op == OKEY {
+	if n.Op == ir.OKEY {
 		n.Left = walkexpr(n.Left, init)
 		n.Right = walkexpr(n.Right, init)
 		return n
@@ -483,42 +482,42 @@ func walkexpr(n *Node, init *Nodes) *Node {
 	}
 
 	if n.Typecheck != 1 {
-		Fatalf("missed typecheck: %v\n", Nconv(n, FmtSign))
+		Fatalf("missed typecheck: %v\n", Nconv(n, ir.FmtSign))
 	}
 
 opswitch:
 	switch n.Op {
 	default:
 		Dump("walk", n)
-		Fatalf("walkexpr: switch 1 unknown op %v", Nconv(n, FmtShort|FmtSign))
-
-	case OTYPE,
-		ONONAME,
-		OINDREG,
-		OEMPTY,
-		OPARAM,
-		OGETG:
-
-	case ONOT,
-		OMINUS,
-		OPLUS,
-		OCOM,
-		OREAL,
-		OIMAG,
-		ODOTMETH,
-		ODOTINTER:
+		Fatalf("walkexpr: switch 1 unknown op %v", Nconv(n, ir.FmtShort|ir.FmtSign))
+
+	case ir.OTYPE,
+		ir.ONONAME,
+		ir.OINDREG,
+		ir.OEMPTY,
+		ir.OPARAM,
+		ir.OGETG:
+
+	case ir.ONOT,
+		ir.OMINUS,
+		ir.OPLUS,
+		ir.OCOM,
+		ir.OREAL,
+		ir.OIMAG,
+		ir.ODOTMETH,
+		ir.ODOTINTER:
 		n.Left = walkexpr(n.Left, init)
 
-	case OIND:
+	case ir.OIND:
 		n.Left = walkexpr(n.Left, init)
 
-	case ODOT:
+	case ir.ODOT:
 		usefield(n)
 		n.Left = walkexpr(n.Left, init)
 
-	case ODOTPTR:
+	case ir.ODOTPTR:
 		usefield(n)
-		if n.Op == ODOTPTR && n.Left.Type.Type.Width == 0 {
+		if n.Op == ir.ODOTPTR && n.Left.Type.Type.Width == 0 {
 			// No actual copy will be generated, so emit an explicit nil check.
 			n.Left = cheapexpr(n.Left, init)
 
@@ -527,14 +526,14 @@ opswitch:
 
 		n.Left = walkexpr(n.Left, init)
 
-	case OEFACE:
+	case ir.OEFACE:
 		n.Left = walkexpr(n.Left, init)
 		n.Right = walkexpr(n.Right, init)
 
-	case OSPTR, OITAB:
+	case ir.OSPTR, ir.OITAB:
 		n.Left = walkexpr(n.Left, init)
 
-	case OLEN, OCAP:
+	case ir.OLEN, ir.OCAP:
 		n.Left = walkexpr(n.Left, init)
 
 		// replace len(*[10]int) with 10.
@@ -550,42 +549,46 @@ opswitch:
 			n.Typecheck = 1
 		}
 
-	case OLSH, ORSH:
+	case ir.OLSH, ir.ORSH:
 		n.Left = walkexpr(n.Left, init)
 		n.Right = walkexpr(n.Right, init)
 		t := n.Left.Type
 		n.Bounded = bounded(n.Right, 8*t.Width)
-		if Debug['m'] != 0 && n.Etype != 0 && !Isconst(n.Right, CTINT) {
+		if Debug['m'] != 0 && n.Etype != 0 && !ir.Isconst(n.Right, ir.CTINT) {
 			Warn("shift bounds check elided")
 		}
 
 		// Use results from call expression as arguments for complex.
-	case OAND,
-		OSUB,
-		OHMUL,
-		OLT,
-		OLE,
-		OGE,
-		OGT,
-		OADD,
-		OCOMPLEX,
-		OLROT:
-		if n.Op == OCOMPLEX && n.Left == nil && n.Right == nil {
+	case ir.OAND,
+		ir.OSUB,
+		ir.OHMUL,
+		ir.OLT,
+		ir.OLE,
+		ir.OGE,
+		ir.OGT,
+		ir.OADD,
+		ir.OCOMPLEX,
+		ir.OLROT:
+		if n.Op == ir.OCOMPLEX && n.Left == nil && n.Right == nil {
 			n.Left = n.List.First()
 			n.Right = n.List.Second()
 		}
 
 		n.Left = walkexpr(n.Left, init)
 		n.Right = walkexpr(n.Right, init)
+		if n.Op == ir.OLT || n.Op == ir.OLE || n.Op == ir.OGE || n.Op == ir.OGT {
+			fuzzcmp(n, init)
+		}
 
-	case OOR, OXOR:
+	case ir.OOR, ir.OXOR:
 		n.Left = walkexpr(n.Left, init)
 		n.Right = walkexpr(n.Right, init)
 		n = walkrotate(n)
 
-	case OEQ, ONE:
+	case ir.OEQ, ir.ONE:
 		n.Left = walkexpr(n.Left, init)
 		n.Right = walkexpr(n.Right, init)
+		fuzzcmp(n, init)
 
 		// Disable safemode while compiling this code: the code we
 		// generate internally can refer to unsafe.Pointer.
@@ -598,58 +601,58 @@ opswitch:
 		n = walkcompare(n, init)
 		safemode = old_safemode
 
-	case OANDAND, OOROR:
+	case ir.OANDAND, ir.OOROR:
 		n.Left = walkexpr(n.Left, init)
 
 		// cannot put side effects from n.Right on init,
 		// because they cannot run before n.Left is checked.
 		// save elsewhere and store on the eventual n.Right.
-		var ll Nodes
+		var ll ir.Nodes
 
 		n.Right = walkexpr(n.Right, &ll)
 		n.Right = addinit(n.Right, ll.Slice())
 
-	case OPRINT, OPRINTN:
+	case ir.OPRINT, ir.OPRINTN:
 		walkexprlist(n.List.Slice(), init)
 		n = walkprint(n, init)
 
-	case OPANIC:
+	case ir.OPANIC:
 		n = mkcall("gopanic", nil, init, n.Left)
 
-	case ORECOVER:
-		n = mkcall("gorecover", n.Type, init, Nod(OADDR, nodfp, nil))
+	case ir.ORECOVER:
+		n = mkcall("gorecover", n.Type, init, Nod(ir.OADDR, nodfp, nil))
 
-	case OLITERAL:
+	case ir.OLITERAL:
 		n.Addable = true
 
-	case OCLOSUREVAR, OCFUNC:
+	case ir.OCLOSUREVAR, ir.OCFUNC:
 		n.Addable = true
 
-	case ONAME:
-		if n.Class&PHEAP == 0 && n.Class != PPARAMREF {
+	case ir.ONAME:
+		if n.Class&ir.PHEAP == 0 && n.Class != ir.PPARAMREF {
 			n.Addable = true
 		}
 
-	case OCALLINTER:
-		usemethod(n)
+	case ir.OCALLINTER:
 		t := n.Left.Type
-		if n.List.Len() != 0 && n.List.First().Op == OAS {
+		if n.List.Len() != 0 && n.List.First().Op == ir.OAS {
 			break
 		}
+		usemethod(n)
 		n.Left = walkexpr(n.Left, init)
 		walkexprlist(n.List.Slice(), init)
 		ll := ascompatte(n.Op, n, n.Isddd, t.Params(), n.List.Slice(), 0, init)
 		n.List.Set(reorder1(ll))
 
-	case OCALLFUNC:
-		if n.Left.Op == OCLOSURE {
+	case ir.OCALLFUNC:
+		if n.Left.Op == ir.OCLOSURE {
 			// Transform direct call of a closure to call of a normal function.
 			// transformclosure already did all preparation work.
 
 			// Prepend captured variables to argument list.
-			n.List.Set(append(n.Left.Func.Enter.Slice(), n.List.Slice()...))
-
-			n.Left.Func.Enter.Set(nil)
+			// The closure may be called from several places
+			// (see directclosures), so copy them.
+			n.List.Set(append(listtreecopy(n.Left.Func.Enter.Slice(), 0), n.List.Slice()...))
 
 			// Replace OCLOSURE with ONAME/PFUNC.
 			n.Left = n.Left.Func.Closure.Func.Nname
@@ -664,17 +667,17 @@ opswitch:
 		}
 
 		t := n.Left.Type
-		if n.List.Len() != 0 && n.List.First().Op == OAS {
+		if n.List.Len() != 0 && n.List.First().Op == ir.OAS {
 			break
 		}
 
 		n.Left = walkexpr(n.Left, init)
 		walkexprlist(n.List.Slice(), init)
 
-		if n.Left.Op == ONAME && n.Left.Sym.Name == "Sqrt" && n.Left.Sym.Pkg.Path == "math" {
+		if n.Left.Op == ir.ONAME && n.Left.Sym.Name == "Sqrt" && n.Left.Sym.Pkg.Path == "math" {
 			switch Thearch.Thechar {
 			case '5', '6', '7', '9':
-				n.Op = OSQRT
+				n.Op = ir.OSQRT
 				n.Left = n.List.First()
 				n.List.Set(nil)
 				break opswitch
@@ -684,21 +687,21 @@ opswitch:
 		ll := ascompatte(n.Op, n, n.Isddd, t.Params(), n.List.Slice(), 0, init)
 		n.List.Set(reorder1(ll))
 
-	case OCALLMETH:
+	case ir.OCALLMETH:
 		t := n.Left.Type
-		if n.List.Len() != 0 && n.List.First().Op == OAS {
+		if n.List.Len() != 0 && n.List.First().Op == ir.OAS {
 			break
 		}
 		n.Left = walkexpr(n.Left, init)
 		walkexprlist(n.List.Slice(), init)
-		ll := ascompatte(n.Op, n, false, t.Recvs(), []*Node{n.Left.Left}, 0, init)
+		ll := ascompatte(n.Op, n, false, t.Recvs(), []*ir.Node{n.Left.Left}, 0, init)
 		lr := ascompatte(n.Op, n, n.Isddd, t.Params(), n.List.Slice(), 0, init)
 		ll = append(ll, lr...)
 		n.Left.Left = nil
 		ullmancalc(n.Left)
 		n.List.Set(reorder1(ll))
 
-	case OAS:
+	case ir.OAS:
 		init.AppendNodes(&n.Ninit)
 
 		n.Left = walkexpr(n.Left, init)
@@ -716,7 +719,7 @@ opswitch:
 		default:
 			n.Right = walkexpr(n.Right, init)
 
-		case ODOTTYPE:
+		case ir.ODOTTYPE:
 			// TODO(rsc): The Isfat is for consistency with componentgen and orderexpr.
 			// It needs to be removed in all three places.
 			// That would allow inlining x.(struct{*int}) the same as x.(*int).
@@ -730,7 +733,7 @@ opswitch:
 			// orderstmt made sure x is addressable.
 			n.Right.Left = walkexpr(n.Right.Left, init)
 
-			n1 := Nod(OADDR, n.Left, nil)
+			n1 := Nod(ir.OADDR, n.Left, nil)
 			r := n.Right // i.(T)
 
 			if Debug_typeassert > 0 {
@@ -744,18 +747,18 @@ opswitch:
 			n = walkexpr(n, init)
 			break opswitch
 
-		case ORECV:
+		case ir.ORECV:
 			// x = <-c; n.Left is x, n.Right.Left is c.
 			// orderstmt made sure x is addressable.
 			n.Right.Left = walkexpr(n.Right.Left, init)
 
-			n1 := Nod(OADDR, n.Left, nil)
+			n1 := chanelem(n.Left)
 			r := n.Right.Left // the channel
 			n = mkcall1(chanfn("chanrecv1", 2, r.Type), nil, init, typename(r.Type), r, n1)
 			n = walkexpr(n, init)
 			break opswitch
 
-		case OAPPEND:
+		case ir.OAPPEND:
 			// x = append(...)
 			r := n.Right
 			if r.Isddd {
@@ -764,7 +767,7 @@ opswitch:
 				r = walkappend(r, init, n)
 			}
 			n.Right = r
-			if r.Op == OAPPEND {
+			if r.Op == ir.OAPPEND {
 				// Left in place for back end.
 				// Do not add a new write barrier.
 				break opswitch
@@ -774,25 +777,21 @@ opswitch:
 		}
 
 		if n.Left != nil && n.Right != nil {
-			r := convas(Nod(OAS, n.Left, n.Right), init)
+			r := convas(Nod(ir.OAS, n.Left, n.Right), init)
 			r.Dodata = n.Dodata
 			n = r
-			n = applywritebarrier(n)
 		}
 
-	case OAS2:
+	case ir.OAS2:
 		init.AppendNodes(&n.Ninit)
 		walkexprlistsafe(n.List.Slice(), init)
 		walkexprlistsafe(n.Rlist.Slice(), init)
-		ll := ascompatee(OAS, n.List.Slice(), n.Rlist.Slice(), init)
+		ll := ascompatee(ir.OAS, n.List.Slice(), n.Rlist.Slice(), init)
 		ll = reorder3(ll)
-		for i, n := range ll {
-			ll[i] = applywritebarrier(n)
-		}
 		n = liststmt(ll)
 
 		// a,b,... = fn()
-	case OAS2FUNC:
+	case ir.OAS2FUNC:
 		init.AppendNodes(&n.Ninit)
 
 		r := n.Rlist.First()
@@ -800,33 +799,30 @@ opswitch:
 		r = walkexpr(r, init)
 
 		ll := ascompatet(n.Op, n.List, r.Type, 0, init)
-		for i, n := range ll {
-			ll[i] = applywritebarrier(n)
-		}
-		n = liststmt(append([]*Node{r}, ll...))
+		n = liststmt(append([]*ir.Node{r}, ll...))
 
 		// x, y = <-c
 	// orderstmt made sure x is addressable.
-	case OAS2RECV:
+	case ir.OAS2RECV:
 		init.AppendNodes(&n.Ninit)
 
 		r := n.Rlist.First()
 		walkexprlistsafe(n.List.Slice(), init)
 		r.Left = walkexpr(r.Left, init)
-		var n1 *Node
+		var n1 *ir.Node
 		if isblank(n.List.First()) {
 			n1 = nodnil()
 		} else {
-			n1 = Nod(OADDR, n.List.First(), nil)
+			n1 = chanelem(n.List.First())
 		}
 		n1.Etype = 1 // addr does not escape
 		fn := chanfn("chanrecv2", 2, r.Left.Type)
 		r = mkcall1(fn, n.List.Second().Type, init, typename(r.Left.Type), r.Left, n1)
-		n = Nod(OAS, n.List.Second(), r)
+		n = renod(n, ir.OAS, n.List.Second(), r)
 		n = typecheck(n, Etop)
 
 		// a,b = m[i];
-	case OAS2MAPR:
+	case ir.OAS2MAPR:
 		init.AppendNodes(&n.Ninit)
 
 		r := n.Rlist.First()
@@ -846,14 +842,14 @@ opswitch:
 			}
 		}
 
-		var key *Node
+		var key *ir.Node
 		if p != "" {
 			// fast versions take key by value
 			key = r.Right
 		} else {
 			// standard version takes key by reference
 			// orderexpr made sure key is addressable.
-			key = Nod(OADDR, r.Right, nil)
+			key = Nod(ir.OADDR, r.Right, nil)
 
 			p = "mapaccess2"
 		}
@@ -875,7 +871,7 @@ opswitch:
 			r.Type.Field(1).Type = n.List.Second().Type
 		}
 		n.Rlist.Set1(r)
-		n.Op = OAS2FUNC
+		n.Op = ir.OAS2FUNC
 
 		// don't generate a = *var if a is _
 		if !isblank(a) {
@@ -884,7 +880,7 @@ opswitch:
 			n.List.SetIndex(0, var_)
 			n = walkexpr(n, init)
 			init.Append(n)
-			n = Nod(OAS, a, Nod(OIND, var_, nil))
+			n = Nod(ir.OAS, a, Nod(ir.OIND, var_, nil))
 		}
 
 		n = typecheck(n, Etop)
@@ -892,7 +888,7 @@ opswitch:
 
 		// TODO: ptr is always non-nil, so disable nil check for this OIND op.
 
-	case ODELETE:
+	case ir.ODELETE:
 		init.AppendNodes(&n.Ninit)
 		map_ := n.List.First()
 		key := n.List.Second()
@@ -900,12 +896,12 @@ opswitch:
 		key = walkexpr(key, init)
 
 		// orderstmt made sure key is addressable.
-		key = Nod(OADDR, key, nil)
+		key = Nod(ir.OADDR, key, nil)
 
 		t := map_.Type
 		n = mkcall1(mapfndel("mapdelete", t), nil, init, typename(t), map_, key)
 
-	case OAS2DOTTYPE:
+	case ir.OAS2DOTTYPE:
 		e := n.Rlist.First() // i.(T)
 		// TODO(rsc): The Isfat is for consistency with componentgen and orderexpr.
 		// It needs to be removed in all three places.
@@ -926,46 +922,46 @@ opswitch:
 		t := e.Type    // T
 		from := e.Left // i
 
-		oktype := Types[TBOOL]
+		oktype := ir.Types[ir.TBOOL]
 		ok := n.List.Second()
 		if !isblank(ok) {
 			oktype = ok.Type
 		}
 
-		fromKind := from.Type.iet()
-		toKind := t.iet()
+		fromKind := iet(from.Type)
+		toKind := iet(t)
 
 		// Avoid runtime calls in a few cases of the form _, ok := i.(T).
 		// This is faster and shorter and allows the corresponding assertX2X2
 		// routines to skip nil checks on their last argument.
 		if isblank(n.List.First()) {
-			var fast *Node
+			var fast *ir.Node
 			switch {
 			case fromKind == 'E' && toKind == 'T':
-				tab := Nod(OITAB, from, nil) // type:eface::tab:iface
-				typ := Nod(OCONVNOP, typename(t), nil)
-				typ.Type = Ptrto(Types[TUINTPTR])
-				fast = Nod(OEQ, tab, typ)
+				tab := Nod(ir.OITAB, from, nil) // type:eface::tab:iface
+				typ := Nod(ir.OCONVNOP, typename(t), nil)
+				typ.Type = Ptrto(ir.Types[ir.TUINTPTR])
+				fast = Nod(ir.OEQ, tab, typ)
 			case fromKind == 'I' && toKind == 'E',
 				fromKind == 'E' && toKind == 'E':
-				tab := Nod(OITAB, from, nil)
-				fast = Nod(ONE, nodnil(), tab)
+				tab := Nod(ir.OITAB, from, nil)
+				fast = Nod(ir.ONE, nodnil(), tab)
 			}
 			if fast != nil {
 				if Debug_typeassert > 0 {
 					Warn("type assertion (ok only) inlined")
 				}
-				n = Nod(OAS, ok, fast)
+				n = renod(n, ir.OAS, ok, fast)
 				n = typecheck(n, Etop)
 				break
 			}
 		}
 
-		var resptr *Node // &res
+		var resptr *ir.Node // &res
 		if isblank(n.List.First()) {
 			resptr = nodnil()
 		} else {
-			resptr = Nod(OADDR, n.List.First(), nil)
+			resptr = Nod(ir.OADDR, n.List.First(), nil)
 		}
 		resptr.Etype = 1 // addr does not escape
 
@@ -975,28 +971,28 @@ opswitch:
 		fn := syslook(assertFuncName(from.Type, t, true))
 		fn = substArgTypes(fn, from.Type, t)
 		call := mkcall1(fn, oktype, init, typename(t), from, resptr)
-		n = Nod(OAS, ok, call)
+		n = renod(n, ir.OAS, ok, call)
 		n = typecheck(n, Etop)
 
-	case ODOTTYPE, ODOTTYPE2:
+	case ir.ODOTTYPE, ir.ODOTTYPE2:
 		if !isdirectiface(n.Type) || Isfat(n.Type) {
 			Fatalf("walkexpr ODOTTYPE") // should see inside OAS only
 		}
 		n.Left = walkexpr(n.Left, init)
 
-	case OCONVIFACE:
+	case ir.OCONVIFACE:
 		n.Left = walkexpr(n.Left, init)
 
 		// Optimize convT2E as a two-word copy when T is pointer-shaped.
 		if isnilinter(n.Type) && isdirectiface(n.Left.Type) {
-			l := Nod(OEFACE, typename(n.Left.Type), n.Left)
+			l := Nod(ir.OEFACE, typename(n.Left.Type), n.Left)
 			l.Type = n.Type
 			l.Typecheck = n.Typecheck
 			n = l
 			break
 		}
 
-		var ll []*Node
+		var ll []*ir.Node
 		if !Isinter(n.Left.Type) {
 			ll = append(ll, typename(n.Left.Type))
 		}
@@ -1004,19 +1000,19 @@ opswitch:
 			ll = append(ll, typename(n.Type))
 		}
 		if !Isinter(n.Left.Type) && !isnilinter(n.Type) {
-			sym := Pkglookup(Tconv(n.Left.Type, FmtLeft)+"."+Tconv(n.Type, FmtLeft), itabpkg)
+			sym := Pkglookup(Tconv(n.Left.Type, ir.FmtLeft)+"."+Tconv(n.Type, ir.FmtLeft), itabpkg)
 			if sym.Def == nil {
-				l := Nod(ONAME, nil, nil)
+				l := Nod(ir.ONAME, nil, nil)
 				l.Sym = sym
-				l.Type = Ptrto(Types[TUINT8])
+				l.Type = Ptrto(ir.Types[ir.TUINT8])
 				l.Addable = true
-				l.Class = PEXTERN
+				l.Class = ir.PEXTERN
 				l.Xoffset = 0
 				sym.Def = l
 				ggloblsym(sym, int32(Widthptr), obj.DUPOK|obj.NOPTR)
 			}
 
-			l := Nod(OADDR, sym.Def, nil)
+			l := Nod(ir.OADDR, sym.Def, nil)
 			l.Addable = true
 			ll = append(ll, l)
 
@@ -1030,26 +1026,26 @@ opswitch:
 				//
 				// The CONVIFACE expression is replaced with this:
 				// 	OEFACE{tab, ptr};
-				l := temp(Ptrto(Types[TUINT8]))
+				l := temp(Ptrto(ir.Types[ir.TUINT8]))
 
-				n1 := Nod(OAS, l, sym.Def)
+				n1 := Nod(ir.OAS, l, sym.Def)
 				n1 = typecheck(n1, Etop)
 				init.Append(n1)
 
 				fn := syslook("typ2Itab")
-				n1 = Nod(OCALL, fn, nil)
+				n1 = Nod(ir.OCALL, fn, nil)
 				n1.List.Set(ll)
 				n1 = typecheck(n1, Erv)
 				n1 = walkexpr(n1, init)
 
-				n2 := Nod(OIF, nil, nil)
-				n2.Left = Nod(OEQ, l, nodnil())
-				n2.Nbody.Set1(Nod(OAS, l, n1))
+				n2 := Nod(ir.OIF, nil, nil)
+				n2.Left = Nod(ir.OEQ, l, nodnil())
+				n2.Nbody.Set1(Nod(ir.OAS, l, n1))
 				n2.Likely = -1
 				n2 = typecheck(n2, Etop)
 				init.Append(n2)
 
-				l = Nod(OEFACE, l, n.Left)
+				l = Nod(ir.OEFACE, l, n.Left)
 				l.Typecheck = n.Typecheck
 				l.Type = n.Type
 				n = l
@@ -1067,19 +1063,19 @@ opswitch:
 			// with non-interface cases, is not visible to orderstmt, so we
 			// have to fall back on allocating a temp here.
 			if islvalue(n.Left) {
-				ll = append(ll, Nod(OADDR, n.Left, nil))
+				ll = append(ll, Nod(ir.OADDR, n.Left, nil))
 			} else {
-				ll = append(ll, Nod(OADDR, copyexpr(n.Left, n.Left.Type, init), nil))
+				ll = append(ll, Nod(ir.OADDR, copyexpr(n.Left, n.Left.Type, init), nil))
 			}
 			dowidth(n.Left.Type)
 			r := nodnil()
 			if n.Esc == EscNone && n.Left.Type.Width <= 1024 {
 				// Allocate stack buffer for value stored in interface.
 				r = temp(n.Left.Type)
-				r = Nod(OAS, r, nil) // zero temp
+				r = Nod(ir.OAS, r, nil) // zero temp
 				r = typecheck(r, Etop)
 				init.Append(r)
-				r = Nod(OADDR, r.Left, nil)
+				r = Nod(ir.OADDR, r.Left, nil)
 				r = typecheck(r, Erv)
 			}
 			ll = append(ll, r)
@@ -1092,62 +1088,73 @@ opswitch:
 			fn = substArgTypes(fn, n.Left.Type, n.Type)
 		}
 		dowidth(fn.Type)
-		n = Nod(OCALL, fn, nil)
+		n = Nod(ir.OCALL, fn, nil)
 		n.List.Set(ll)
 		n = typecheck(n, Erv)
 		n = walkexpr(n, init)
 
-	case OCONV, OCONVNOP:
+	case ir.OCONV, ir.OCONVNOP:
 		if Thearch.Thechar == '5' {
 			if Isfloat[n.Left.Type.Etype] {
-				if n.Type.Etype == TINT64 {
-					n = mkcall("float64toint64", n.Type, init, conv(n.Left, Types[TFLOAT64]))
+				if n.Type.Etype == ir.TINT64 {
+					n = mkcall("float64toint64", n.Type, init, conv(n.Left, ir.Types[ir.TFLOAT64]))
 					break
 				}
 
-				if n.Type.Etype == TUINT64 {
-					n = mkcall("float64touint64", n.Type, init, conv(n.Left, Types[TFLOAT64]))
+				if n.Type.Etype == ir.TUINT64 {
+					n = mkcall("float64touint64", n.Type, init, conv(n.Left, ir.Types[ir.TFLOAT64]))
 					break
 				}
 			}
 
 			if Isfloat[n.Type.Etype] {
-				if n.Left.Type.Etype == TINT64 {
-					n = mkcall("int64tofloat64", n.Type, init, conv(n.Left, Types[TINT64]))
+				if n.Left.Type.Etype == ir.TINT64 {
+					n = mkcall("int64tofloat64", n.Type, init, conv(n.Left, ir.Types[ir.TINT64]))
 					break
 				}
 
-				if n.Left.Type.Etype == TUINT64 {
-					n = mkcall("uint64tofloat64", n.Type, init, conv(n.Left, Types[TUINT64]))
+				if n.Left.Type.Etype == ir.TUINT64 {
+					n = mkcall("uint64tofloat64", n.Type, init, conv(n.Left, ir.Types[ir.TUINT64]))
 					break
 				}
 			}
 		}
 
+		if n.Op == ir.OCONVNOP && Debug_checkptr != 0 && compiling_runtime == 0 {
+			if n.Type.Etype == ir.TUNSAFEPTR && n.Left.Type.Etype == ir.TUINTPTR {
+				n = walkcheckptrarith(n, init)
+				break
+			}
+			if Isptr[n.Type.Etype] && n.Left.Type.Etype == ir.TUNSAFEPTR {
+				n = walkcheckptralign(n, init, nil)
+				break
+			}
+		}
+
 		n.Left = walkexpr(n.Left, init)
 
-	case OANDNOT:
+	case ir.OANDNOT:
 		n.Left = walkexpr(n.Left, init)
-		n.Op = OAND
-		n.Right = Nod(OCOM, n.Right, nil)
+		n.Op = ir.OAND
+		n.Right = Nod(ir.OCOM, n.Right, nil)
 		n.Right = typecheck(n.Right, Erv)
 		n.Right = walkexpr(n.Right, init)
 
-	case OMUL:
+	case ir.OMUL:
 		n.Left = walkexpr(n.Left, init)
 		n.Right = walkexpr(n.Right, init)
 		n = walkmul(n, init)
 
-	case ODIV, OMOD:
+	case ir.ODIV, ir.OMOD:
 		n.Left = walkexpr(n.Left, init)
 		n.Right = walkexpr(n.Right, init)
 
 		// rewrite complex div into function call.
 		et := n.Left.Type.Etype
 
-		if Iscomplex[et] && n.Op == ODIV {
+		if Iscomplex[et] && n.Op == ir.ODIV {
 			t := n.Type
-			n = mkcall("complex128div", Types[TCOMPLEX128], init, conv(n.Left, Types[TCOMPLEX128]), conv(n.Right, Types[TCOMPLEX128]))
+			n = mkcall("complex128div", ir.Types[ir.TCOMPLEX128], init, conv(n.Left, ir.Types[ir.TCOMPLEX128]), conv(n.Right, ir.Types[ir.TCOMPLEX128]))
 			n = conv(n, t)
 			break
 		}
@@ -1163,25 +1170,25 @@ opswitch:
 		// rewrite 64-bit div and mod into function calls
 		// on 32-bit architectures.
 		switch n.Op {
-		case OMOD, ODIV:
-			if Widthreg >= 8 || (et != TUINT64 && et != TINT64) {
+		case ir.OMOD, ir.ODIV:
+			if Widthreg >= 8 || (et != ir.TUINT64 && et != ir.TINT64) {
 				break opswitch
 			}
 			var fn string
-			if et == TINT64 {
+			if et == ir.TINT64 {
 				fn = "int64"
 			} else {
 				fn = "uint64"
 			}
-			if n.Op == ODIV {
+			if n.Op == ir.ODIV {
 				fn += "div"
 			} else {
 				fn += "mod"
 			}
-			n = mkcall(fn, n.Type, init, conv(n.Left, Types[et]), conv(n.Right, Types[et]))
+			n = mkcall(fn, n.Type, init, conv(n.Left, ir.Types[et]), conv(n.Right, ir.Types[et]))
 		}
 
-	case OINDEX:
+	case ir.OINDEX:
 		n.Left = walkexpr(n.Left, init)
 
 		// save the original node for bounds checking elision.
@@ -1201,15 +1208,15 @@ opswitch:
 		}
 		if Isfixedarray(t) {
 			n.Bounded = bounded(r, t.Bound)
-			if Debug['m'] != 0 && n.Bounded && !Isconst(n.Right, CTINT) {
+			if Debug['m'] != 0 && n.Bounded && !ir.Isconst(n.Right, ir.CTINT) {
 				Warn("index bounds check elided")
 			}
 			if Smallintconst(n.Right) && !n.Bounded {
 				Yyerror("index out of bounds")
 			}
-		} else if Isconst(n.Left, CTSTR) {
+		} else if ir.Isconst(n.Left, ir.CTSTR) {
 			n.Bounded = bounded(r, int64(len(n.Left.Val().U.(string))))
-			if Debug['m'] != 0 && n.Bounded && !Isconst(n.Right, CTINT) {
+			if Debug['m'] != 0 && n.Bounded && !ir.Isconst(n.Right, ir.CTINT) {
 				Warn("index bounds check elided")
 			}
 			if Smallintconst(n.Right) {
@@ -1219,7 +1226,7 @@ opswitch:
 					// replace "abc"[1] with 'b'.
 					// delayed until now because "abc"[1] is not
 					// an ideal constant.
-					v := n.Right.Val().U.(*Mpint).Int64()
+					v := n.Right.Val().U.(*ir.Mpint).Int64()
 
 					Nodconst(n, n.Type, int64(n.Left.Val().U.(string)[v]))
 					n.Typecheck = 1
@@ -1227,13 +1234,13 @@ opswitch:
 			}
 		}
 
-		if Isconst(n.Right, CTINT) {
-			if n.Right.Val().U.(*Mpint).CmpInt64(0) < 0 || n.Right.Val().U.(*Mpint).Cmp(Maxintval[TINT]) > 0 {
+		if ir.Isconst(n.Right, ir.CTINT) {
+			if n.Right.Val().U.(*ir.Mpint).CmpInt64(0) < 0 || n.Right.Val().U.(*ir.Mpint).Cmp(Maxintval[ir.TINT]) > 0 {
 				Yyerror("index out of bounds")
 			}
 		}
 
-	case OINDEXMAP:
+	case ir.OINDEXMAP:
 		if n.Etype == 1 {
 			break
 		}
@@ -1253,38 +1260,48 @@ opswitch:
 			}
 		}
 
-		var key *Node
+		var key *ir.Node
 		if p != "" {
 			// fast versions take key by value
 			key = n.Right
 		} else {
 			// standard version takes key by reference.
 			// orderexpr made sure key is addressable.
-			key = Nod(OADDR, n.Right, nil)
+			key = Nod(ir.OADDR, n.Right, nil)
 
 			p = "mapaccess1"
 		}
 
 		n = mkcall1(mapfn(p, t), Ptrto(t.Type), init, typename(t), n.Left, key)
-		n = Nod(OIND, n, nil)
+		n = Nod(ir.OIND, n, nil)
 		n.Type = t.Type
 		n.Typecheck = 1
 
-	case ORECV:
+	case ir.ORECV:
 		Fatalf("walkexpr ORECV") // should see inside OAS only
 
-	case OSLICE, OSLICEARR, OSLICESTR:
-		n.Left = walkexpr(n.Left, init)
+	case ir.OSLICE, ir.OSLICEARR, ir.OSLICESTR:
+		checkptr := n.Op == ir.OSLICEARR && ischeckptrconv(n.Left)
+		if !checkptr {
+			n.Left = walkexpr(n.Left, init)
+		}
 		n.Right.Left = walkexpr(n.Right.Left, init)
 		if n.Right.Left != nil && iszero(n.Right.Left) {
 			// Reduce x[0:j] to x[:j].
 			n.Right.Left = nil
 		}
 		n.Right.Right = walkexpr(n.Right.Right, init)
+		if checkptr {
+			n.Left = walkcheckptralign(n.Left, init, n.Right.Right)
+		}
 		n = reduceSlice(n)
+		n = walkcheckslice(n, init)
 
-	case OSLICE3, OSLICE3ARR:
-		n.Left = walkexpr(n.Left, init)
+	case ir.OSLICE3, ir.OSLICE3ARR:
+		checkptr := n.Op == ir.OSLICE3ARR && ischeckptrconv(n.Left)
+		if !checkptr {
+			n.Left = walkexpr(n.Left, init)
+		}
 		n.Right.Left = walkexpr(n.Right.Left, init)
 		if n.Right.Left != nil && iszero(n.Right.Left) {
 			// Reduce x[0:j:k] to x[:j:k].
@@ -1292,32 +1309,36 @@ opswitch:
 		}
 		n.Right.Right.Left = walkexpr(n.Right.Right.Left, init)
 		n.Right.Right.Right = walkexpr(n.Right.Right.Right, init)
+		if checkptr {
+			n.Left = walkcheckptralign(n.Left, init, n.Right.Right.Right)
+		}
 
 		r := n.Right.Right.Right
-		if r != nil && r.Op == OCAP && samesafeexpr(n.Left, r.Left) {
+		if r != nil && r.Op == ir.OCAP && samesafeexpr(n.Left, r.Left) {
 			// Reduce x[i:j:cap(x)] to x[i:j].
 			n.Right.Right = n.Right.Right.Left
-			if n.Op == OSLICE3 {
-				n.Op = OSLICE
+			if n.Op == ir.OSLICE3 {
+				n.Op = ir.OSLICE
 			} else {
-				n.Op = OSLICEARR
+				n.Op = ir.OSLICEARR
 			}
 			n = reduceSlice(n)
 		}
+		n = walkcheckslice(n, init)
 
-	case OADDR:
+	case ir.OADDR:
 		n.Left = walkexpr(n.Left, init)
 
-	case ONEW:
+	case ir.ONEW:
 		if n.Esc == EscNone {
 			if n.Type.Type.Width >= 1<<16 {
 				Fatalf("large ONEW with EscNone: %v", n)
 			}
 			r := temp(n.Type.Type)
-			r = Nod(OAS, r, nil) // zero temp
+			r = Nod(ir.OAS, r, nil) // zero temp
 			r = typecheck(r, Etop)
 			init.Append(r)
-			r = Nod(OADDR, r.Left, nil)
+			r = Nod(ir.OADDR, r.Left, nil)
 			r = typecheck(r, Erv)
 			n = r
 		} else {
@@ -1327,10 +1348,10 @@ opswitch:
 		// If one argument to the comparison is an empty string,
 	// comparing the lengths instead will yield the same result
 	// without the function call.
-	case OCMPSTR:
-		if (Isconst(n.Left, CTSTR) && len(n.Left.Val().U.(string)) == 0) || (Isconst(n.Right, CTSTR) && len(n.Right.Val().U.(string)) == 0) {
+	case ir.OCMPSTR:
+		if (ir.Isconst(n.Left, ir.CTSTR) && len(n.Left.Val().U.(string)) == 0) || (ir.Isconst(n.Right, ir.CTSTR) && len(n.Right.Val().U.(string)) == 0) {
 			// TODO(marvin): Fix Node.EType type union.
-			r := Nod(Op(n.Etype), Nod(OLEN, n.Left, nil), Nod(OLEN, n.Right, nil))
+			r := Nod(ir.Op(n.Etype), Nod(ir.OLEN, n.Left, nil), Nod(ir.OLEN, n.Right, nil))
 			r = typecheck(r, Erv)
 			r = walkexpr(r, init)
 			r.Type = n.Type
@@ -1339,9 +1360,9 @@ opswitch:
 		}
 
 		// s + "badgerbadgerbadger" == "badgerbadgerbadger"
-		if (Op(n.Etype) == OEQ || Op(n.Etype) == ONE) && Isconst(n.Right, CTSTR) && n.Left.Op == OADDSTR && n.Left.List.Len() == 2 && Isconst(n.Left.List.Second(), CTSTR) && strlit(n.Right) == strlit(n.Left.List.Second()) {
+		if (ir.Op(n.Etype) == ir.OEQ || ir.Op(n.Etype) == ir.ONE) && ir.Isconst(n.Right, ir.CTSTR) && n.Left.Op == ir.OADDSTR && n.Left.List.Len() == 2 && ir.Isconst(n.Left.List.Second(), ir.CTSTR) && strlit(n.Right) == strlit(n.Left.List.Second()) {
 			// TODO(marvin): Fix Node.EType type union.
-			r := Nod(Op(n.Etype), Nod(OLEN, n.Left.List.First(), nil), Nodintconst(0))
+			r := Nod(ir.Op(n.Etype), Nod(ir.OLEN, n.Left.List.First(), nil), Nodintconst(0))
 			r = typecheck(r, Erv)
 			r = walkexpr(r, init)
 			r.Type = n.Type
@@ -1349,68 +1370,71 @@ opswitch:
 			break
 		}
 
-		var r *Node
+		fuzzcmp(n, init)
+
+		var r *ir.Node
 		// TODO(marvin): Fix Node.EType type union.
-		if Op(n.Etype) == OEQ || Op(n.Etype) == ONE {
+		if ir.Op(n.Etype) == ir.OEQ || ir.Op(n.Etype) == ir.ONE {
 			// prepare for rewrite below
 			n.Left = cheapexpr(n.Left, init)
 
 			n.Right = cheapexpr(n.Right, init)
 
-			r = mkcall("eqstring", Types[TBOOL], init, conv(n.Left, Types[TSTRING]), conv(n.Right, Types[TSTRING]))
+			r = mkcall("eqstring", ir.Types[ir.TBOOL], init, conv(n.Left, ir.Types[ir.TSTRING]), conv(n.Right, ir.Types[ir.TSTRING]))
 
 			// quick check of len before full compare for == or !=
 			// eqstring assumes that the lengths are equal
 			// TODO(marvin): Fix Node.EType type union.
-			if Op(n.Etype) == OEQ {
+			if ir.Op(n.Etype) == ir.OEQ {
 				// len(left) == len(right) && eqstring(left, right)
-				r = Nod(OANDAND, Nod(OEQ, Nod(OLEN, n.Left, nil), Nod(OLEN, n.Right, nil)), r)
+				r = Nod(ir.OANDAND, Nod(ir.OEQ, Nod(ir.OLEN, n.Left, nil), Nod(ir.OLEN, n.Right, nil)), r)
 			} else {
 				// len(left) != len(right) || !eqstring(left, right)
-				r = Nod(ONOT, r, nil)
+				r = Nod(ir.ONOT, r, nil)
 
-				r = Nod(OOROR, Nod(ONE, Nod(OLEN, n.Left, nil), Nod(OLEN, n.Right, nil)), r)
+				r = Nod(ir.OOROR, Nod(ir.ONE, Nod(ir.OLEN, n.Left, nil), Nod(ir.OLEN, n.Right, nil)), r)
 			}
 
 			r = typecheck(r, Erv)
 			r = walkexpr(r, nil)
 		} else {
 			// sys_cmpstring(s1, s2) :: 0
-			r = mkcall("cmpstring", Types[TINT], init, conv(n.Left, Types[TSTRING]), conv(n.Right, Types[TSTRING]))
+			r = mkcall("cmpstring", ir.Types[ir.TINT], init, conv(n.Left, ir.Types[ir.TSTRING]), conv(n.Right, ir.Types[ir.TSTRING]))
 
 			// TODO(marvin): Fix Node.EType type union.
-			r = Nod(Op(n.Etype), r, Nodintconst(0))
+			r = Nod(ir.Op(n.Etype), r, Nodintconst(0))
 		}
 
 		r = typecheck(r, Erv)
-		if n.Type.Etype != TBOOL {
+		if n.Type.Etype != ir.TBOOL {
 			Fatalf("cmp %v", n.Type)
 		}
 		r.Type = n.Type
 		n = r
 
-	case OADDSTR:
+	case ir.OADDSTR:
 		n = addstr(n, init)
 
-	case OAPPEND:
+	case ir.OAPPEND:
 		// order should make sure we only see OAS(node, OAPPEND), which we handle above.
 		Fatalf("append outside assignment")
 
-	case OCOPY:
+	case ir.OCOPY:
 		n = copyany(n, init, instrumenting)
 
 		// cannot use chanfn - closechan takes any, not chan any
-	case OCLOSE:
+	case ir.OCLOSE:
 		fn := syslook("closechan")
 
 		fn = substArgTypes(fn, n.Left.Type)
 		n = mkcall1(fn, nil, init, n.Left)
 
-	case OMAKECHAN:
-		n = mkcall1(chanfn("makechan", 1, n.Type), n.Type, init, typename(n.Type), conv(n.Left, Types[TINT64]))
+	case ir.OMAKECHAN:
+		n = mkcall1(chanfn("makechan", 1, n.Type), n.Type, init, typename(n.Type), conv(walkcheckmake(n.Left, init), ir.Types[ir.TINT64]))
 
-	case OMAKEMAP:
+	case ir.OMAKEMAP:
 		t := n.Type
+		hint := walkcheckmake(n.Left, init)
 
 		a := nodnil() // hmap buffer
 		r := nodnil() // bucket buffer
@@ -1418,29 +1442,29 @@ opswitch:
 			// Allocate hmap buffer on stack.
 			var_ := temp(hmap(t))
 
-			a = Nod(OAS, var_, nil) // zero temp
+			a = Nod(ir.OAS, var_, nil) // zero temp
 			a = typecheck(a, Etop)
 			init.Append(a)
-			a = Nod(OADDR, var_, nil)
+			a = Nod(ir.OADDR, var_, nil)
 
 			// Allocate one bucket on stack.
 			// Maximum key/value size is 128 bytes, larger objects
 			// are stored with an indirection. So max bucket size is 2048+eps.
 			var_ = temp(mapbucket(t))
 
-			r = Nod(OAS, var_, nil) // zero temp
+			r = Nod(ir.OAS, var_, nil) // zero temp
 			r = typecheck(r, Etop)
 			init.Append(r)
-			r = Nod(OADDR, var_, nil)
+			r = Nod(ir.OADDR, var_, nil)
 		}
 
 		fn := syslook("makemap")
 		fn = substArgTypes(fn, hmap(t), mapbucket(t), t.Key(), t.Type)
-		n = mkcall1(fn, n.Type, init, typename(n.Type), conv(n.Left, Types[TINT64]), a, r)
+		n = mkcall1(fn, n.Type, init, typename(n.Type), conv(hint, ir.Types[ir.TINT64]), a, r)
 
-	case OMAKESLICE:
-		l := n.Left
-		r := n.Right
+	case ir.OMAKESLICE:
+		l := walkcheckmake(n.Left, init)
+		r := walkcheckmake(n.Right, init)
 		if r == nil {
 			r = safeexpr(l, init)
 			l = r
@@ -1454,11 +1478,11 @@ opswitch:
 			// n = arr[:l]
 			t = aindex(r, t.Type) // [r]T
 			var_ := temp(t)
-			a := Nod(OAS, var_, nil) // zero temp
+			a := Nod(ir.OAS, var_, nil) // zero temp
 			a = typecheck(a, Etop)
 			init.Append(a)
-			r := Nod(OSLICE, var_, Nod(OKEY, nil, l)) // arr[:l]
-			r = conv(r, n.Type)                       // in case n.Type is named.
+			r := Nod(ir.OSLICE, var_, Nod(ir.OKEY, nil, l)) // arr[:l]
+			r = conv(r, n.Type)                             // in case n.Type is named.
 			r = typecheck(r, Erv)
 			r = walkexpr(r, init)
 			n = r
@@ -1467,85 +1491,85 @@ opswitch:
 			fn := syslook("makeslice")
 
 			fn = substArgTypes(fn, t.Type) // any-1
-			n = mkcall1(fn, n.Type, init, typename(n.Type), conv(l, Types[TINT64]), conv(r, Types[TINT64]))
+			n = mkcall1(fn, n.Type, init, typename(n.Type), conv(l, ir.Types[ir.TINT64]), conv(r, ir.Types[ir.TINT64]))
 		}
 
-	case ORUNESTR:
+	case ir.ORUNESTR:
 		a := nodnil()
 		if n.Esc == EscNone {
-			t := aindex(Nodintconst(4), Types[TUINT8])
+			t := aindex(Nodintconst(4), ir.Types[ir.TUINT8])
 			var_ := temp(t)
-			a = Nod(OADDR, var_, nil)
+			a = Nod(ir.OADDR, var_, nil)
 		}
 
 		// intstring(*[4]byte, rune)
-		n = mkcall("intstring", n.Type, init, a, conv(n.Left, Types[TINT64]))
+		n = mkcall("intstring", n.Type, init, a, conv(n.Left, ir.Types[ir.TINT64]))
 
-	case OARRAYBYTESTR:
+	case ir.OARRAYBYTESTR:
 		a := nodnil()
 		if n.Esc == EscNone {
 			// Create temporary buffer for string on stack.
-			t := aindex(Nodintconst(tmpstringbufsize), Types[TUINT8])
+			t := aindex(Nodintconst(tmpstringbufsize), ir.Types[ir.TUINT8])
 
-			a = Nod(OADDR, temp(t), nil)
+			a = Nod(ir.OADDR, temp(t), nil)
 		}
 
 		// slicebytetostring(*[32]byte, []byte) string;
 		n = mkcall("slicebytetostring", n.Type, init, a, n.Left)
 
 		// slicebytetostringtmp([]byte) string;
-	case OARRAYBYTESTRTMP:
+	case ir.OARRAYBYTESTRTMP:
 		n = mkcall("slicebytetostringtmp", n.Type, init, n.Left)
 
 		// slicerunetostring(*[32]byte, []rune) string;
-	case OARRAYRUNESTR:
+	case ir.OARRAYRUNESTR:
 		a := nodnil()
 
 		if n.Esc == EscNone {
 			// Create temporary buffer for string on stack.
-			t := aindex(Nodintconst(tmpstringbufsize), Types[TUINT8])
+			t := aindex(Nodintconst(tmpstringbufsize), ir.Types[ir.TUINT8])
 
-			a = Nod(OADDR, temp(t), nil)
+			a = Nod(ir.OADDR, temp(t), nil)
 		}
 
 		n = mkcall("slicerunetostring", n.Type, init, a, n.Left)
 
 		// stringtoslicebyte(*32[byte], string) []byte;
-	case OSTRARRAYBYTE:
+	case ir.OSTRARRAYBYTE:
 		a := nodnil()
 
 		if n.Esc == EscNone {
 			// Create temporary buffer for slice on stack.
-			t := aindex(Nodintconst(tmpstringbufsize), Types[TUINT8])
+			t := aindex(Nodintconst(tmpstringbufsize), ir.Types[ir.TUINT8])
 
-			a = Nod(OADDR, temp(t), nil)
+			a = Nod(ir.OADDR, temp(t), nil)
 		}
 
-		n = mkcall("stringtoslicebyte", n.Type, init, a, conv(n.Left, Types[TSTRING]))
+		n = mkcall("stringtoslicebyte", n.Type, init, a, conv(n.Left, ir.Types[ir.TSTRING]))
 
 		// stringtoslicebytetmp(string) []byte;
-	case OSTRARRAYBYTETMP:
-		n = mkcall("stringtoslicebytetmp", n.Type, init, conv(n.Left, Types[TSTRING]))
+	case ir.OSTRARRAYBYTETMP:
+		n = mkcall("stringtoslicebytetmp", n.Type, init, conv(n.Left, ir.Types[ir.TSTRING]))
 
 		// stringtoslicerune(*[32]rune, string) []rune
-	case OSTRARRAYRUNE:
+	case ir.OSTRARRAYRUNE:
 		a := nodnil()
 
 		if n.Esc == EscNone {
 			// Create temporary buffer for slice on stack.
-			t := aindex(Nodintconst(tmpstringbufsize), Types[TINT32])
+			t := aindex(Nodintconst(tmpstringbufsize), ir.Types[ir.TINT32])
 
-			a = Nod(OADDR, temp(t), nil)
+			a = Nod(ir.OADDR, temp(t), nil)
 		}
 
 		n = mkcall("stringtoslicerune", n.Type, init, a, n.Left)
 
 		// ifaceeq(i1 any-1, i2 any-2) (ret bool);
-	case OCMPIFACE:
+	case ir.OCMPIFACE:
 		if !Eqtype(n.Left.Type, n.Right.Type) {
 			Fatalf("ifaceeq %v %v %v", Oconv(n.Op, 0), n.Left.Type, n.Right.Type)
 		}
-		var fn *Node
+		var fn *ir.Node
 		if isnilinter(n.Left.Type) {
 			fn = syslook("efaceeq")
 		} else {
@@ -1557,38 +1581,38 @@ opswitch:
 		fn = substArgTypes(fn, n.Right.Type, n.Left.Type)
 		r := mkcall1(fn, n.Type, init, n.Left, n.Right)
 		// TODO(marvin): Fix Node.EType type union.
-		if Op(n.Etype) == ONE {
-			r = Nod(ONOT, r, nil)
+		if ir.Op(n.Etype) == ir.ONE {
+			r = Nod(ir.ONOT, r, nil)
 		}
 
 		// check itable/type before full compare.
 		// TODO(marvin): Fix Node.EType type union.
-		if Op(n.Etype) == OEQ {
-			r = Nod(OANDAND, Nod(OEQ, Nod(OITAB, n.Left, nil), Nod(OITAB, n.Right, nil)), r)
+		if ir.Op(n.Etype) == ir.OEQ {
+			r = Nod(ir.OANDAND, Nod(ir.OEQ, Nod(ir.OITAB, n.Left, nil), Nod(ir.OITAB, n.Right, nil)), r)
 		} else {
-			r = Nod(OOROR, Nod(ONE, Nod(OITAB, n.Left, nil), Nod(OITAB, n.Right, nil)), r)
+			r = Nod(ir.OOROR, Nod(ir.ONE, Nod(ir.OITAB, n.Left, nil), Nod(ir.OITAB, n.Right, nil)), r)
 		}
 		r = typecheck(r, Erv)
 		r = walkexpr(r, init)
 		r.Type = n.Type
 		n = r
 
-	case OARRAYLIT, OMAPLIT, OSTRUCTLIT, OPTRLIT:
+	case ir.OARRAYLIT, ir.OMAPLIT, ir.OSTRUCTLIT, ir.OPTRLIT:
 		var_ := temp(n.Type)
 		anylit(0, n, var_, init)
 		n = var_
 
-	case OSEND:
+	case ir.OSEND:
 		n1 := n.Right
 		n1 = assignconv(n1, n.Left.Type.Type, "chan send")
 		n1 = walkexpr(n1, init)
-		n1 = Nod(OADDR, n1, nil)
+		n1 = chanelem(n1)
 		n = mkcall1(chanfn("chansend1", 2, n.Left.Type), nil, init, typename(n.Left.Type), n.Left, n1)
 
-	case OCLOSURE:
+	case ir.OCLOSURE:
 		n = walkclosure(n, init)
 
-	case OCALLPART:
+	case ir.OCALLPART:
 		n = walkpartialcall(n, init)
 	}
 
@@ -1601,7 +1625,7 @@ opswitch:
 
 	evconst(n)
 	n.Type = t
-	if n.Op == OLITERAL {
+	if n.Op == ir.OLITERAL {
 		n = typecheck(n, Erv)
 	}
 
@@ -1615,13 +1639,13 @@ opswitch:
 	return n
 }
 
-func reduceSlice(n *Node) *Node {
+func reduceSlice(n *ir.Node) *ir.Node {
 	r := n.Right.Right
-	if r != nil && r.Op == OLEN && samesafeexpr(n.Left, r.Left) {
+	if r != nil && r.Op == ir.OLEN && samesafeexpr(n.Left, r.Left) {
 		// Reduce x[i:len(x)] to x[i:].
 		n.Right.Right = nil
 	}
-	if (n.Op == OSLICE || n.Op == OSLICESTR) && n.Right.Left == nil && n.Right.Right == nil {
+	if (n.Op == ir.OSLICE || n.Op == ir.OSLICESTR) && n.Right.Left == nil && n.Right.Right == nil {
 		// Reduce x[:] to x.
 		if Debug_slice > 0 {
 			Warn("slice: omit slice operation")
@@ -1631,19 +1655,19 @@ func reduceSlice(n *Node) *Node {
 	return n
 }
 
-func ascompatee1(op Op, l *Node, r *Node, init *Nodes) *Node {
+func ascompatee1(op ir.Op, l *ir.Node, r *ir.Node, init *ir.Nodes) *ir.Node {
 	// convas will turn map assigns into function calls,
 	// making it impossible for reorder3 to work.
-	n := Nod(OAS, l, r)
+	n := Nod(ir.OAS, l, r)
 
-	if l.Op == OINDEXMAP {
+	if l.Op == ir.OINDEXMAP {
 		return n
 	}
 
 	return convas(n, init)
 }
 
-func ascompatee(op Op, nl, nr []*Node, init *Nodes) []*Node {
+func ascompatee(op ir.Op, nl, nr []*ir.Node, init *ir.Nodes) []*ir.Node {
 	// check assign expression list to
 	// a expression list. called in
 	//	expr-list = expr-list
@@ -1656,14 +1680,14 @@ func ascompatee(op Op, nl, nr []*Node, init *Nodes) []*Node {
 		nr[i1] = safeexpr(nr[i1], init)
 	}
 
-	var nn []*Node
+	var nn []*ir.Node
 	i := 0
 	for ; i < len(nl); i++ {
 		if i >= len(nr) {
 			break
 		}
 		// Do not generate 'x = x' during return. See issue 4014.
-		if op == ORETURN && nl[i] == nr[i] {
+		if op == ir.ORETURN && nl[i] == nr[i] {
 			continue
 		}
 		nn = append(nn, ascompatee1(op, nl[i], nr[i], init))
@@ -1671,10 +1695,10 @@ func ascompatee(op Op, nl, nr []*Node, init *Nodes) []*Node {
 
 	// cannot happen: caller checked that lists had same length
 	if i < len(nl) || i < len(nr) {
-		var nln, nrn Nodes
+		var nln, nrn ir.Nodes
 		nln.Set(nl)
 		nrn.Set(nr)
-		Yyerror("error in shape across %v %v %v / %d %d [%s]", Hconv(nln, FmtSign), Oconv(op, 0), Hconv(nrn, FmtSign), len(nl), len(nr), Curfn.Func.Nname.Sym.Name)
+		Yyerror("error in shape across %v %v %v / %d %d [%s]", Hconv(nln, ir.FmtSign), Oconv(op, 0), Hconv(nrn, ir.FmtSign), len(nl), len(nr), Curfn.Func.Nname.Sym.Name)
 	}
 	return nn
 }
@@ -1683,11 +1707,11 @@ func ascompatee(op Op, nl, nr []*Node, init *Nodes) []*Node {
 // return 1 if this implies a function call
 // evaluating the lv or a function call
 // in the conversion of the types
-func fncall(l *Node, rt *Type) bool {
-	if l.Ullman >= UINF || l.Op == OINDEXMAP {
+func fncall(l *ir.Node, rt *ir.Type) bool {
+	if l.Ullman >= UINF || l.Op == ir.OINDEXMAP {
 		return true
 	}
-	var r Node
+	var r ir.Node
 	if needwritebarrier(l, &r) {
 		return true
 	}
@@ -1700,10 +1724,10 @@ func fncall(l *Node, rt *Type) bool {
 // check assign type list to
 // a expression list. called in
 //	expr-list = func()
-func ascompatet(op Op, nl Nodes, nr *Type, fp int, init *Nodes) []*Node {
-	r, saver := IterFields(nr)
+func ascompatet(op ir.Op, nl ir.Nodes, nr *ir.Type, fp int, init *ir.Nodes) []*ir.Node {
+	r, saver := ir.IterFields(nr)
 
-	var nn, mm []*Node
+	var nn, mm []*ir.Node
 	var ullmanOverflow bool
 	var i int
 	for i = 0; i < nl.Len(); i++ {
@@ -1722,13 +1746,13 @@ func ascompatet(op Op, nl Nodes, nr *Type, fp int, init *Nodes) []*Node {
 		if fncall(l, r.Type) {
 			tmp := temp(r.Type)
 			tmp = typecheck(tmp, Erv)
-			a := Nod(OAS, l, tmp)
+			a := Nod(ir.OAS, l, tmp)
 			a = convas(a, init)
 			mm = append(mm, a)
 			l = tmp
 		}
 
-		a := Nod(OAS, l, nodarg(r, fp))
+		a := Nod(ir.OAS, l, nodarg(r, fp))
 		a = convas(a, init)
 		ullmancalc(a)
 		if a.Ullman >= UINF {
@@ -1751,22 +1775,22 @@ func ascompatet(op Op, nl Nodes, nr *Type, fp int, init *Nodes) []*Node {
 }
 
 // package all the arguments that match a ... T parameter into a []T.
-func mkdotargslice(lr0, nn []*Node, l *Field, fp int, init *Nodes, ddd *Node) []*Node {
+func mkdotargslice(lr0, nn []*ir.Node, l *ir.Field, fp int, init *ir.Nodes, ddd *ir.Node) []*ir.Node {
 	esc := uint16(EscUnknown)
 	if ddd != nil {
 		esc = ddd.Esc
 	}
 
-	tslice := typ(TARRAY)
+	tslice := ir.Typ(ir.TARRAY)
 	tslice.Type = l.Type.Type
 	tslice.Bound = -1
 
-	var n *Node
+	var n *ir.Node
 	if len(lr0) == 0 {
 		n = nodnil()
 		n.Type = tslice
 	} else {
-		n = Nod(OCOMPLIT, nil, typenod(tslice))
+		n = Nod(ir.OCOMPLIT, nil, typenod(tslice))
 		if ddd != nil && prealloc[ddd] != nil {
 			prealloc[n] = prealloc[ddd] // temporary to use
 		}
@@ -1779,13 +1803,13 @@ func mkdotargslice(lr0, nn []*Node, l *Field, fp int, init *Nodes, ddd *Node) []
 		n = walkexpr(n, init)
 	}
 
-	a := Nod(OAS, nodarg(l, fp), n)
+	a := Nod(ir.OAS, nodarg(l, fp), n)
 	nn = append(nn, convas(a, init))
 	return nn
 }
 
 // helpers for shape errors
-func dumptypes(nl *Type, what string) string {
+func dumptypes(nl *ir.Type, what string) string {
 	s := ""
 	for _, l := range nl.Fields().Slice() {
 		if s != "" {
@@ -1799,7 +1823,7 @@ func dumptypes(nl *Type, what string) string {
 	return s
 }
 
-func dumpnodetypes(l []*Node, what string) string {
+func dumpnodetypes(l []*ir.Node, what string) string {
 	s := ""
 	for _, r := range l {
 		if s != "" {
@@ -1817,36 +1841,36 @@ func dumpnodetypes(l []*Node, what string) string {
 // a type list. called in
 //	return expr-list
 //	func(expr-list)
-func ascompatte(op Op, call *Node, isddd bool, nl *Type, lr []*Node, fp int, init *Nodes) []*Node {
+func ascompatte(op ir.Op, call *ir.Node, isddd bool, nl *ir.Type, lr []*ir.Node, fp int, init *ir.Nodes) []*ir.Node {
 	lr0 := lr
-	l, savel := IterFields(nl)
-	var r *Node
+	l, savel := ir.IterFields(nl)
+	var r *ir.Node
 	if len(lr) > 0 {
 		r = lr[0]
 	}
-	var nn []*Node
+	var nn []*ir.Node
 
 	// f(g()) where g has multiple return values
-	if r != nil && len(lr) <= 1 && r.Type.Etype == TSTRUCT && r.Type.Funarg {
+	if r != nil && len(lr) <= 1 && r.Type.Etype == ir.TSTRUCT && r.Type.Funarg {
 		// optimization - can do block copy
 		if eqtypenoname(r.Type, nl) {
 			arg := nodarg(nl, fp)
-			r = Nod(OCONVNOP, r, nil)
+			r = Nod(ir.OCONVNOP, r, nil)
 			r.Type = arg.Type
-			nn = []*Node{convas(Nod(OAS, arg, r), init)}
+			nn = []*ir.Node{convas(Nod(ir.OAS, arg, r), init)}
 			goto ret
 		}
 
 		// conversions involved.
 		// copy into temporaries.
-		var alist []*Node
+		var alist []*ir.Node
 
 		for _, l := range r.Type.Fields().Slice() {
 			tmp := temp(l.Type)
 			alist = append(alist, tmp)
 		}
 
-		a := Nod(OAS2, nil, nil)
+		a := Nod(ir.OAS2, nil, nil)
 		a.List.Set(alist)
 		a.Rlist.Set(lr)
 		a = typecheck(a, Etop)
@@ -1854,7 +1878,7 @@ func ascompatte(op Op, call *Node, isddd bool, nl *Type, lr []*Node, fp int, ini
 		init.Append(a)
 		lr = alist
 		r = lr[0]
-		l, savel = IterFields(nl)
+		l, savel = ir.IterFields(nl)
 	}
 
 	for {
@@ -1871,7 +1895,7 @@ func ascompatte(op Op, call *Node, isddd bool, nl *Type, lr []*Node, fp int, ini
 			// argument to a ddd parameter then it is
 			// passed thru unencapsulated
 			if r != nil && len(lr) <= 1 && isddd && Eqtype(l.Type, r.Type) {
-				a := Nod(OAS, nodarg(l, fp), r)
+				a := Nod(ir.OAS, nodarg(l, fp), r)
 				a = convas(a, init)
 				nn = append(nn, a)
 				break
@@ -1899,7 +1923,7 @@ func ascompatte(op Op, call *Node, isddd bool, nl *Type, lr []*Node, fp int, ini
 			break
 		}
 
-		a := Nod(OAS, nodarg(l, fp), r)
+		a := Nod(ir.OAS, nodarg(l, fp), r)
 		a = convas(a, init)
 		nn = append(nn, a)
 
@@ -1919,16 +1943,16 @@ ret:
 }
 
 // generate code for print
-func walkprint(nn *Node, init *Nodes) *Node {
-	var r *Node
-	var n *Node
-	var on *Node
-	var t *Type
-	var et EType
+func walkprint(nn *ir.Node, init *ir.Nodes) *ir.Node {
+	var r *ir.Node
+	var n *ir.Node
+	var on *ir.Node
+	var t *ir.Type
+	var et ir.EType
 
 	op := nn.Op
 	all := nn.List
-	var calls []*Node
+	var calls []*ir.Node
 	notfirst := false
 
 	// Hoist all the argument evaluation up before the lock.
@@ -1940,28 +1964,28 @@ func walkprint(nn *Node, init *Nodes) *Node {
 			calls = append(calls, mkcall("printsp", nil, init))
 		}
 
-		notfirst = op == OPRINTN
+		notfirst = op == ir.OPRINTN
 
 		n = n1
-		if n.Op == OLITERAL {
+		if n.Op == ir.OLITERAL {
 			switch n.Val().Ctype() {
-			case CTRUNE:
-				n = defaultlit(n, runetype)
+			case ir.CTRUNE:
+				n = defaultlit(n, ir.Runetype)
 
-			case CTINT:
-				n = defaultlit(n, Types[TINT64])
+			case ir.CTINT:
+				n = defaultlit(n, ir.Types[ir.TINT64])
 
-			case CTFLT:
-				n = defaultlit(n, Types[TFLOAT64])
+			case ir.CTFLT:
+				n = defaultlit(n, ir.Types[ir.TFLOAT64])
 			}
 		}
 
-		if n.Op != OLITERAL && n.Type != nil && n.Type.Etype == TIDEAL {
-			n = defaultlit(n, Types[TINT64])
+		if n.Op != ir.OLITERAL && n.Type != nil && n.Type.Etype == ir.TIDEAL {
+			n = defaultlit(n, ir.Types[ir.TINT64])
 		}
 		n = defaultlit(n, nil)
 		all.SetIndex(i1, n)
-		if n.Type == nil || n.Type.Etype == TFORW {
+		if n.Type == nil || n.Type.Etype == ir.TFORW {
 			continue
 		}
 
@@ -1974,14 +1998,14 @@ func walkprint(nn *Node, init *Nodes) *Node {
 				on = syslook("printiface")
 			}
 			on = substArgTypes(on, n.Type) // any-1
-		} else if Isptr[et] || et == TCHAN || et == TMAP || et == TFUNC || et == TUNSAFEPTR {
+		} else if Isptr[et] || et == ir.TCHAN || et == ir.TMAP || et == ir.TFUNC || et == ir.TUNSAFEPTR {
 			on = syslook("printpointer")
 			on = substArgTypes(on, n.Type) // any-1
 		} else if Isslice(n.Type) {
 			on = syslook("printslice")
 			on = substArgTypes(on, n.Type) // any-1
 		} else if Isint[et] {
-			if et == TUINT64 {
+			if et == ir.TUINT64 {
 				if (t.Sym.Pkg == Runtimepkg || compiling_runtime != 0) && t.Sym.Name == "hex" {
 					on = syslook("printhex")
 				} else {
@@ -1994,28 +2018,28 @@ func walkprint(nn *Node, init *Nodes) *Node {
 			on = syslook("printfloat")
 		} else if Iscomplex[et] {
 			on = syslook("printcomplex")
-		} else if et == TBOOL {
+		} else if et == ir.TBOOL {
 			on = syslook("printbool")
-		} else if et == TSTRING {
+		} else if et == ir.TSTRING {
 			on = syslook("printstring")
 		} else {
-			badtype(OPRINT, n.Type, nil)
+			badtype(ir.OPRINT, n.Type, nil)
 			continue
 		}
 
 		t = on.Type.Params().Field(0).Type
 
 		if !Eqtype(t, n.Type) {
-			n = Nod(OCONV, n, nil)
+			n = Nod(ir.OCONV, n, nil)
 			n.Type = t
 		}
 
-		r = Nod(OCALL, on, nil)
+		r = Nod(ir.OCALL, on, nil)
 		r.List.Append(n)
 		calls = append(calls, r)
 	}
 
-	if op == OPRINTN {
+	if op == ir.OPRINTN {
 		calls = append(calls, mkcall("printnl", nil, nil))
 	}
 
@@ -2024,44 +2048,48 @@ func walkprint(nn *Node, init *Nodes) *Node {
 	typecheckslice(calls, Etop)
 	walkexprlist(calls, init)
 
-	r = Nod(OEMPTY, nil, nil)
+	r = Nod(ir.OEMPTY, nil, nil)
 	r = typecheck(r, Etop)
 	r = walkexpr(r, init)
 	r.Ninit.Set(calls)
 	return r
 }
 
-func callnew(t *Type) *Node {
+func callnew(t *ir.Type) *ir.Node {
 	dowidth(t)
 	fn := syslook("newobject")
 	fn = substArgTypes(fn, t)
 	return mkcall1(fn, Ptrto(t), nil, typename(t))
 }
 
-func iscallret(n *Node) bool {
+func iscallret(n *ir.Node) bool {
 	n = outervalue(n)
-	return n.Op == OINDREG && n.Reg == int16(Thearch.REGSP)
+	return n.Op == ir.OINDREG && n.Reg == int16(Thearch.REGSP)
 }
 
-func isstack(n *Node) bool {
+func isstack(n *ir.Node) bool {
 	n = outervalue(n)
 
 	// If n is *autotmp and autotmp = &foo, replace n with foo.
 	// We introduce such temps when initializing struct literals.
-	if n.Op == OIND && n.Left.Op == ONAME && strings.HasPrefix(n.Left.Sym.Name, "autotmp_") {
+	if n.Op == ir.OIND && n.Left.Op == ir.ONAME && strings.HasPrefix(n.Left.Sym.Name, "autotmp_") {
 		defn := n.Left.Name.Defn
-		if defn != nil && defn.Op == OAS && defn.Right.Op == OADDR {
+		if defn != nil && defn.Op == ir.OAS && defn.Right.Op == ir.OADDR {
 			n = defn.Right.Left
 		}
 	}
 
 	switch n.Op {
-	case OINDREG:
+	case ir.OINDREG:
 		return n.Reg == int16(Thearch.REGSP)
 
-	case ONAME:
+	case ir.OPARAM:
+		// Stack copy of a heap-allocated parameter.
+		return true
+
+	case ir.ONAME:
 		switch n.Class {
-		case PAUTO, PPARAM, PPARAMOUT:
+		case ir.PAUTO, ir.PPARAM, ir.PPARAMOUT:
 			return true
 		}
 	}
@@ -2069,13 +2097,13 @@ func isstack(n *Node) bool {
 	return false
 }
 
-func isglobal(n *Node) bool {
+func isglobal(n *ir.Node) bool {
 	n = outervalue(n)
 
 	switch n.Op {
-	case ONAME:
+	case ir.ONAME:
 		switch n.Class {
-		case PEXTERN:
+		case ir.PEXTERN:
 			return true
 		}
 	}
@@ -2083,94 +2111,15 @@ func isglobal(n *Node) bool {
 	return false
 }
 
-// Do we need a write barrier for the assignment l = r?
-func needwritebarrier(l *Node, r *Node) bool {
-	if use_writebarrier == 0 {
-		return false
-	}
-
-	if l == nil || isblank(l) {
-		return false
-	}
-
-	// No write barrier for write of non-pointers.
-	dowidth(l.Type)
-
-	if !haspointers(l.Type) {
-		return false
-	}
-
-	// No write barrier for write to stack.
-	if isstack(l) {
-		return false
-	}
-
-	// No write barrier for implicit zeroing.
-	if r == nil {
-		return false
-	}
-
-	// Ignore no-op conversions when making decision.
-	// Ensures that xp = unsafe.Pointer(&x) is treated
-	// the same as xp = &x.
-	for r.Op == OCONVNOP {
-		r = r.Left
-	}
-
-	// No write barrier for zeroing or initialization to constant.
-	if iszero(r) || r.Op == OLITERAL {
-		return false
-	}
-
-	// No write barrier for storing static (read-only) data.
-	if r.Op == ONAME && strings.HasPrefix(r.Sym.Name, "statictmp_") {
-		return false
-	}
-
-	// No write barrier for storing address of stack values,
-	// which are guaranteed only to be written to the stack.
-	if r.Op == OADDR && isstack(r.Left) {
-		return false
-	}
-
-	// No write barrier for storing address of global, which
-	// is live no matter what.
-	if r.Op == OADDR && isglobal(r.Left) {
-		return false
-	}
-
-	// No write barrier for storing global function, which is live
-	// no matter what.
-	if r.Op == ONAME && r.Class == PFUNC {
-		return false
-	}
-
-	// Otherwise, be conservative and use write barrier.
-	return true
-}
-
-// TODO(rsc): Perhaps componentgen should run before this.
-
-func applywritebarrier(n *Node) *Node {
-	if n.Left != nil && n.Right != nil && needwritebarrier(n.Left, n.Right) {
-		if Debug_wb > 1 {
-			Warnl(n.Lineno, "marking %v for barrier", Nconv(n.Left, 0))
-		}
-		n.Op = OASWB
-		return n
-	}
-	return n
-}
-
-func convas(n *Node, init *Nodes) *Node {
-	if n.Op != OAS {
+func convas(n *ir.Node, init *ir.Nodes) *ir.Node {
+	if n.Op != ir.OAS {
 		Fatalf("convas: not OAS %v", Oconv(n.Op, 0))
 	}
 
 	n.Typecheck = 1
 
-	var lt *Type
-	var rt *Type
+	var lt *ir.Type
+	var rt *ir.Type
 	if n.Left == nil || n.Right == nil {
 		goto out
 	}
@@ -2186,7 +2135,7 @@ func convas(n *Node, init *Nodes) *Node {
 		goto out
 	}
 
-	if n.Left.Op == OINDEXMAP {
+	if n.Left.Op == ir.OINDEXMAP {
 		map_ := n.Left.Left
 		key := n.Left.Right
 		val := n.Right
@@ -2195,9 +2144,9 @@ func convas(n *Node, init *Nodes) *Node {
 		val = walkexpr(val, init)
 
 		// orderexpr made sure key and val are addressable.
-		key = Nod(OADDR, key, nil)
+		key = Nod(ir.OADDR, key, nil)
 
-		val = Nod(OADDR, val, nil)
+		val = Nod(ir.OADDR, val, nil)
 		n = mkcall1(mapfn("mapassign1", map_.Type), nil, init, typename(map_.Type), map_, key, val)
 		goto out
 	}
@@ -2218,7 +2167,7 @@ out:
 // if there is exactly one function expr,
 // then it is done first. otherwise must
 // make temp variables
-func reorder1(all []*Node) []*Node {
+func reorder1(all []*ir.Node) []*ir.Node {
 	c := 0 // function calls
 	t := 0 // total parameters
 
@@ -2234,11 +2183,11 @@ func reorder1(all []*Node) []*Node {
 		return all
 	}
 
-	var g []*Node // fncalls assigned to tempnames
-	var f *Node   // last fncall assigned to stack
-	var r []*Node // non fncalls and tempnames assigned to stack
+	var g []*ir.Node // fncalls assigned to tempnames
+	var f *ir.Node   // last fncall assigned to stack
+	var r []*ir.Node // non fncalls and tempnames assigned to stack
 	d := 0
-	var a *Node
+	var a *ir.Node
 	for _, n := range all {
 		if n.Ullman < UINF {
 			r = append(r, n)
@@ -2254,7 +2203,7 @@ func reorder1(all []*Node) []*Node {
 		// make assignment of fncall to tempname
 		a = temp(n.Right.Type)
 
-		a = Nod(OAS, a, n.Right)
+		a = Nod(ir.OAS, a, n.Right)
 		g = append(g, a)
 
 		// put normal arg assignment on list
@@ -2276,27 +2225,27 @@ func reorder1(all []*Node) []*Node {
 // be later use of an earlier lvalue.
 //
 // function calls have been removed.
-func reorder3(all []*Node) []*Node {
-	var l *Node
+func reorder3(all []*ir.Node) []*ir.Node {
+	var l *ir.Node
 
 	// If a needed expression may be affected by an
 	// earlier assignment, make an early copy of that
 	// expression and use the copy instead.
-	var early []*Node
+	var early []*ir.Node
 
-	var mapinit Nodes
+	var mapinit ir.Nodes
 	for i, n := range all {
 		l = n.Left
 
 		// Save subexpressions needed on left side.
 		// Drill through non-dereferences.
 		for {
-			if l.Op == ODOT || l.Op == OPAREN {
+			if l.Op == ir.ODOT || l.Op == ir.OPAREN {
 				l = l.Left
 				continue
 			}
 
-			if l.Op == OINDEX && Isfixedarray(l.Left.Type) {
+			if l.Op == ir.OINDEX && Isfixedarray(l.Left.Type) {
 				l.Right = reorder3save(l.Right, all, i, &early)
 				l = l.Left
 				continue
@@ -2307,19 +2256,19 @@ func reorder3(all []*Node) []*Node {
 
 		switch l.Op {
 		default:
-			Fatalf("reorder3 unexpected lvalue %v", Oconv(l.Op, FmtSharp))
+			Fatalf("reorder3 unexpected lvalue %v", Oconv(l.Op, ir.FmtSharp))
 
-		case ONAME:
+		case ir.ONAME:
 			break
 
-		case OINDEX, OINDEXMAP:
+		case ir.OINDEX, ir.OINDEXMAP:
 			l.Left = reorder3save(l.Left, all, i, &early)
 			l.Right = reorder3save(l.Right, all, i, &early)
-			if l.Op == OINDEXMAP {
+			if l.Op == ir.OINDEXMAP {
 				all[i] = convas(all[i], &mapinit)
 			}
 
-		case OIND, ODOTPTR:
+		case ir.OIND, ir.ODOTPTR:
 			l.Left = reorder3save(l.Left, all, i, &early)
 		}
 
@@ -2337,13 +2286,13 @@ func reorder3(all []*Node) []*Node {
 // replace *np with that temp.
 // The result of reorder3save MUST be assigned back to n, e.g.
 // 	n.Left = reorder3save(n.Left, all, i, early)
-func reorder3save(n *Node, all []*Node, i int, early *[]*Node) *Node {
+func reorder3save(n *ir.Node, all []*ir.Node, i int, early *[]*ir.Node) *ir.Node {
 	if !aliased(n, all, i) {
 		return n
 	}
 
 	q := temp(n.Type)
-	q = Nod(OAS, q, n)
+	q = Nod(ir.OAS, q, n)
 	q = typecheck(q, Etop)
 	*early = append(*early, q)
 	return q.Left
@@ -2351,17 +2300,17 @@ func reorder3save(n *Node, all []*Node, i int, early *[]*Node) *Node {
 
 // what's the outer value that a write to n affects?
 // outer value means containing struct or array.
-func outervalue(n *Node) *Node {
+func outervalue(n *ir.Node) *ir.Node {
 	for {
-		if n.Op == OXDOT {
+		if n.Op == ir.OXDOT {
 			Fatalf("OXDOT in walk")
 		}
-		if n.Op == ODOT || n.Op == OPAREN || n.Op == OCONVNOP {
+		if n.Op == ir.ODOT || n.Op == ir.OPAREN || n.Op == ir.OCONVNOP {
 			n = n.Left
 			continue
 		}
 
-		if n.Op == OINDEX && Isfixedarray(n.Left.Type) {
+		if n.Op == ir.OINDEX && Isfixedarray(n.Left.Type) {
 			n = n.Left
 			continue
 		}
@@ -2374,7 +2323,7 @@ func outervalue(n *Node) *Node {
 
 // Is it possible that the computation of n might be
 // affected by writes in as up to but not including the ith element?
-func aliased(n *Node, all []*Node, i int) bool {
+func aliased(n *ir.Node, all []*ir.Node, i int) bool {
 	if n == nil {
 		return false
 	}
@@ -2387,10 +2336,10 @@ func aliased(n *Node, all []*Node, i int) bool {
 	memwrite := 0
 
 	varwrite := 0
-	var a *Node
+	var a *ir.Node
 	for _, an := range all[:i] {
 		a = outervalue(an.Left)
-		if a.Op != ONAME {
+		if a.Op != ir.ONAME {
 			memwrite = 1
 			continue
 		}
@@ -2400,7 +2349,7 @@ func aliased(n *Node, all []*Node, i int) bool {
 			varwrite = 1
 			continue
 
-		case PAUTO, PPARAM, PPARAMOUT:
+		case ir.PAUTO, ir.PPARAM, ir.PPARAMOUT:
 			if n.Addrtaken {
 				varwrite = 1
 				continue
@@ -2437,18 +2386,18 @@ func aliased(n *Node, all []*Node, i int) bool {
 // does the evaluation of n only refer to variables
 // whose addresses have not been taken?
 // (and no other memory)
-func varexpr(n *Node) bool {
+func varexpr(n *ir.Node) bool {
 	if n == nil {
 		return true
 	}
 
 	switch n.Op {
-	case OLITERAL:
+	case ir.OLITERAL:
 		return true
 
-	case ONAME:
+	case ir.ONAME:
 		switch n.Class {
-		case PAUTO, PPARAM, PPARAMOUT:
+		case ir.PAUTO, ir.PPARAM, ir.PPARAMOUT:
 			if !n.Addrtaken {
 				return true
 			}
@@ -2456,30 +2405,30 @@ func varexpr(n *Node) bool {
 
 		return false
 
-	case OADD,
-		OSUB,
-		OOR,
-		OXOR,
-		OMUL,
-		ODIV,
-		OMOD,
-		OLSH,
-		ORSH,
-		OAND,
-		OANDNOT,
-		OPLUS,
-		OMINUS,
-		OCOM,
-		OPAREN,
-		OANDAND,
-		OOROR,
-		OCONV,
-		OCONVNOP,
-		OCONVIFACE,
-		ODOTTYPE:
+	case ir.OADD,
+		ir.OSUB,
+		ir.OOR,
+		ir.OXOR,
+		ir.OMUL,
+		ir.ODIV,
+		ir.OMOD,
+		ir.OLSH,
+		ir.ORSH,
+		ir.OAND,
+		ir.OANDNOT,
+		ir.OPLUS,
+		ir.OMINUS,
+		ir.OCOM,
+		ir.OPAREN,
+		ir.OANDAND,
+		ir.OOROR,
+		ir.OCONV,
+		ir.OCONVNOP,
+		ir.OCONVIFACE,
+		ir.ODOTTYPE:
 		return varexpr(n.Left) && varexpr(n.Right)
 
-	case ODOT: // but not ODOTPTR
+	case ir.ODOT: // but not ODOTPTR
 		return varexpr(n.Left)
 	}
 
@@ -2488,16 +2437,16 @@ func varexpr(n *Node) bool {
 }
 
 // is the name l mentioned in r?
-func vmatch2(l *Node, r *Node) bool {
+func vmatch2(l *ir.Node, r *ir.Node) bool {
 	if r == nil {
 		return false
 	}
 	switch r.Op {
 	// match each right given left
-	case ONAME:
+	case ir.ONAME:
 		return l == r
 
-	case OLITERAL:
+	case ir.OLITERAL:
 		return false
 	}
 
@@ -2517,15 +2466,15 @@ func vmatch2(l *Node, r *Node) bool {
 
 // is any name mentioned in l also mentioned in r?
 // called by sinit.go
-func vmatch1(l *Node, r *Node) bool {
+func vmatch1(l *ir.Node, r *ir.Node) bool {
 	// isolate all left sides
 	if l == nil || r == nil {
 		return false
 	}
 	switch l.Op {
-	case ONAME:
+	case ir.ONAME:
 		switch l.Class {
-		case PPARAM, PPARAMREF, PAUTO:
+		case ir.PPARAM, ir.PPARAMREF, ir.PAUTO:
 			break
 
 			// assignment to non-stack variable
@@ -2538,7 +2487,7 @@ func vmatch1(l *Node, r *Node) bool {
 
 		return vmatch2(l, r)
 
-	case OLITERAL:
+	case ir.OLITERAL:
 		return false
 	}
 
@@ -2560,8 +2509,8 @@ func vmatch1(l *Node, r *Node) bool {
 // and to copy non-result prameters' values from the stack.
 // If out is true, then code is also produced to zero-initialize their
 // stack memory addresses.
-func paramstoheap(params *Type, out bool) []*Node {
-	var nn []*Node
+func paramstoheap(params *ir.Type, out bool) []*ir.Node {
+	var nn []*ir.Node
 	for _, t := range params.Fields().Slice() {
 		v := t.Nname
 		if v != nil && v.Sym != nil && strings.HasPrefix(v.Sym.Name, "~r") { // unnamed result
@@ -2574,10 +2523,10 @@ func paramstoheap(params *Type, out bool) []*Node {
 			// Defer might stop a panic and show the
 			// return values as they exist at the time of panic.
 			// Make sure to zero them on entry to the function.
-			nn = append(nn, Nod(OAS, nodarg(t, -1), nil))
+			nn = append(nn, Nod(ir.OAS, nodarg(t, -1), nil))
 		}
 
-		if v == nil || v.Class&PHEAP == 0 {
+		if v == nil || v.Class&ir.PHEAP == 0 {
 			continue
 		}
 
@@ -2588,12 +2537,11 @@ func paramstoheap(params *Type, out bool) []*Node {
 		if prealloc[v] == nil {
 			prealloc[v] = callnew(v.Type)
 		}
-		nn = append(nn, Nod(OAS, v.Name.Heapaddr, prealloc[v]))
-		if v.Class&^PHEAP != PPARAMOUT {
-			as := Nod(OAS, v, v.Name.Param.Stackparam)
+		nn = append(nn, Nod(ir.OAS, v.Name.Heapaddr, prealloc[v]))
+		if v.Class&^ir.PHEAP != ir.PPARAMOUT {
+			as := Nod(ir.OAS, v, v.Name.Param.Stackparam)
 			v.Name.Param.Stackparam.Typecheck = 1
 			as = typecheck(as, Etop)
-			as = applywritebarrier(as)
 			nn = append(nn, as)
 		}
 	}
@@ -2603,14 +2551,14 @@ func paramstoheap(params *Type, out bool) []*Node {
 
 // returnsfromheap returns code to copy values for heap-escaped parameters
 // back to the stack.
-func returnsfromheap(params *Type) []*Node {
-	var nn []*Node
+func returnsfromheap(params *ir.Type) []*ir.Node {
+	var nn []*ir.Node
 	for _, t := range params.Fields().Slice() {
 		v := t.Nname
-		if v == nil || v.Class != PHEAP|PPARAMOUT {
+		if v == nil || v.Class != ir.PHEAP|ir.PPARAMOUT {
 			continue
 		}
-		nn = append(nn, Nod(OAS, v.Name.Param.Stackparam, v))
+		nn = append(nn, Nod(ir.OAS, v.Name.Param.Stackparam, v))
 	}
 
 	return nn
@@ -2631,14 +2579,14 @@ func heapmoves() {
 	lineno = lno
 }
 
-func vmkcall(fn *Node, t *Type, init *Nodes, va []*Node) *Node {
-	if fn.Type == nil || fn.Type.Etype != TFUNC {
+func vmkcall(fn *ir.Node, t *ir.Type, init *ir.Nodes, va []*ir.Node) *ir.Node {
+	if fn.Type == nil || fn.Type.Etype != ir.TFUNC {
 		Fatalf("mkcall %v %v", fn, fn.Type)
 	}
 
 	n := fn.Type.Params().NumFields()
 
-	r := Nod(OCALL, fn, nil)
+	r := Nod(ir.OCALL, fn, nil)
 	r.List.Set(va[:n])
 	if fn.Type.Results().NumFields() > 0 {
 		r = typecheck(r, Erv|Efnstruct)
@@ -2650,26 +2598,114 @@ func vmkcall(fn *Node, t *Type, init *Nodes, va []*Node) *Node {
 	return r
 }
 
-func mkcall(name string, t *Type, init *Nodes, args ...*Node) *Node {
+func mkcall(name string, t *ir.Type, init *ir.Nodes, args ...*ir.Node) *ir.Node {
 	return vmkcall(syslook(name), t, init, args)
 }
 
-func mkcall1(fn *Node, t *Type, init *Nodes, args ...*Node) *Node {
+func mkcall1(fn *ir.Node, t *ir.Type, init *ir.Nodes, args ...*ir.Node) *ir.Node {
 	return vmkcall(fn, t, init, args)
 }
 
-func conv(n *Node, t *Type) *Node {
+// ischeckptrconv reports whether n is a conversion of an unsafe.Pointer
+// to *T that -d=checkptr instruments.
+func ischeckptrconv(n *ir.Node) bool {
+	return Debug_checkptr != 0 && compiling_runtime == 0 && n.Op == ir.OCONVNOP && n.Left.Type.Etype == ir.TUNSAFEPTR
+}
+
+// walkcheckptralign instruments the conversion n of an unsafe.Pointer
+// to *T for -d=checkptr, checking that the result is suitably aligned
+// and does not straddle two allocations. If count is not nil, T is an
+// array type and the conversion is immediately sliced, as in
+// (*[1<<20]E)(p)[:count]; only the first count elements of type E
+// need to lie within one allocation.
+func walkcheckptralign(n *ir.Node, init *ir.Nodes, count *ir.Node) *ir.Node {
+	n.Left = walkexpr(n.Left, init)
+	elem := n.Type.Type
+	if count != nil {
+		elem = elem.Type
+	} else {
+		dowidth(elem)
+		if elem.Width <= 1 && elem.Align <= 1 {
+			return n
+		}
+		count = Nodintconst(1)
+	}
+	n.Left = cheapexpr(n.Left, init)
+	init.Append(mkcall("checkptrAlignment", nil, init, conv(n.Left, Ptrto(ir.Types[ir.TUINT8])), typename(elem), conv(count, ir.Types[ir.TUINTPTR])))
+	return n
+}
+
+// walkcheckptrarith instruments the conversion n of a uintptr to
+// unsafe.Pointer for -d=checkptr. If the uintptr was computed by
+// arithmetic on unsafe.Pointer values, the result must point into
+// the same allocation as one of those values.
+func walkcheckptrarith(n *ir.Node, init *ir.Nodes) *ir.Node {
+	// Results of calls, as in syscall wrappers, are not derived
+	// from any pointer visible here.
+	switch n.Left.Op {
+	case ir.OCALLFUNC, ir.OCALLMETH, ir.OCALLINTER:
+		n.Left = walkexpr(n.Left, init)
+		return n
+	}
+
+	// Collect the unsafe.Pointer operands of the arithmetic.
+	// Only addition contributes on both sides; for subtraction
+	// and masking, the right operand is an offset.
+	var originals []*ir.Node
+	var walk func(n *ir.Node)
+	walk = func(n *ir.Node) {
+		switch n.Op {
+		case ir.OADD:
+			walk(n.Left)
+			walk(n.Right)
+
+		case ir.OSUB, ir.OANDNOT:
+			walk(n.Left)
+
+		case ir.OCONVNOP:
+			if n.Left.Type.Etype == ir.TUNSAFEPTR {
+				n.Left = cheapexpr(n.Left, init)
+				originals = append(originals, conv(n.Left, Ptrto(ir.Types[ir.TUINT8])))
+			}
+		}
+	}
+	walk(n.Left)
+
+	n.Left = walkexpr(n.Left, init)
+	n.Left = cheapexpr(n.Left, init)
+
+	tslice := ir.Typ(ir.TARRAY)
+	tslice.Type = Ptrto(ir.Types[ir.TUINT8])
+	tslice.Bound = -1
+
+	var slice *ir.Node
+	if len(originals) == 0 {
+		slice = nodnil()
+		slice.Type = tslice
+	} else {
+		slice = Nod(ir.OCOMPLIT, nil, typenod(tslice))
+		slice.List.Set(originals)
+		slice.Esc = EscNone
+		slice = typecheck(slice, Erv)
+		slice = walkexpr(slice, init)
+	}
+
+	init.Append(mkcall("checkptrArithmetic", nil, init, n.Left, slice))
+	return n
+}
+
+func conv(n *ir.Node, t *ir.Type) *ir.Node {
 	if Eqtype(n.Type, t) {
 		return n
 	}
-	n = Nod(OCONV, n, nil)
+	n = Nod(ir.OCONV, n, nil)
 	n.Type = t
 	n = typecheck(n, Erv)
 	return n
 }
 
-func chanfn(name string, n int, t *Type) *Node {
-	if t.Etype != TCHAN {
+func chanfn(name string, n int, t *ir.Type) *ir.Node {
+	if t.Etype != ir.TCHAN {
 		Fatalf("chanfn %v", t)
 	}
 	fn := syslook(name)
@@ -2684,8 +2720,20 @@ func chanfn(name string, n int, t *Type) *Node {
 	return fn
 }
 
-func mapfn(name string, t *Type) *Node {
-	if t.Etype != TMAP {
+// chanelem returns the element pointer passed to the channel
+// runtime routines for n. Values of zero-size element types carry
+// no data, so when n is a plain name the runtime is passed nil and
+// copies nothing.
+func chanelem(n *ir.Node) *ir.Node {
+	dowidth(n.Type)
+	if n.Type.Width == 0 && n.Op == ir.ONAME && !instrumenting {
+		return nodnil()
+	}
+	return Nod(ir.OADDR, n, nil)
+}
+
+func mapfn(name string, t *ir.Type) *ir.Node {
+	if t.Etype != ir.TMAP {
 		Fatalf("mapfn %v", t)
 	}
 	fn := syslook(name)
@@ -2693,8 +2741,8 @@ func mapfn(name string, t *Type) *Node {
 	return fn
 }
 
-func mapfndel(name string, t *Type) *Node {
-	if t.Etype != TMAP {
+func mapfndel(name string, t *ir.Type) *ir.Node {
+	if t.Etype != ir.TMAP {
 		Fatalf("mapfn %v", t)
 	}
 	fn := syslook(name)
@@ -2702,13 +2750,13 @@ func mapfndel(name string, t *Type) *Node {
 	return fn
 }
 
-func writebarrierfn(name string, l *Type, r *Type) *Node {
+func writebarrierfn(name string, l *ir.Type, r *ir.Type) *ir.Node {
 	fn := syslook(name)
 	fn = substArgTypes(fn, l, r)
 	return fn
 }
 
-func addstr(n *Node, init *Nodes) *Node {
+func addstr(n *ir.Node, init *ir.Nodes) *ir.Node {
 	// orderexpr rewrote OADDSTR to have a list of strings.
 	c := n.List.Len()
 
@@ -2720,7 +2768,7 @@ func addstr(n *Node, init *Nodes) *Node {
 	if n.Esc == EscNone {
 		sz := int64(0)
 		for _, n1 := range n.List.Slice() {
-			if n1.Op == OLITERAL {
+			if n1.Op == ir.OLITERAL {
 				sz += int64(len(n1.Val().U.(string)))
 			}
 		}
@@ -2728,16 +2776,16 @@ func addstr(n *Node, init *Nodes) *Node {
 		// Don't allocate the buffer if the result won't fit.
 		if sz < tmpstringbufsize {
 			// Create temporary buffer for result string on stack.
-			t := aindex(Nodintconst(tmpstringbufsize), Types[TUINT8])
+			t := aindex(Nodintconst(tmpstringbufsize), ir.Types[ir.TUINT8])
 
-			buf = Nod(OADDR, temp(t), nil)
+			buf = Nod(ir.OADDR, temp(t), nil)
 		}
 	}
 
 	// build list of string arguments
-	args := []*Node{buf}
+	args := []*ir.Node{buf}
 	for _, n2 := range n.List.Slice() {
-		args = append(args, conv(n2, Types[TSTRING]))
+		args = append(args, conv(n2, ir.Types[ir.TSTRING]))
 	}
 
 	var fn string
@@ -2749,21 +2797,21 @@ func addstr(n *Node, init *Nodes) *Node {
 		// large numbers of strings are passed to the runtime as a slice.
 		fn = "concatstrings"
 
-		t := typ(TARRAY)
-		t.Type = Types[TSTRING]
+		t := ir.Typ(ir.TARRAY)
+		t.Type = ir.Types[ir.TSTRING]
 		t.Bound = -1
-		slice := Nod(OCOMPLIT, nil, typenod(t))
+		slice := Nod(ir.OCOMPLIT, nil, typenod(t))
 		if prealloc[n] != nil {
 			prealloc[slice] = prealloc[n]
 		}
 		slice.List.Set(args[1:]) // skip buf arg
-		args = []*Node{buf}
+		args = []*ir.Node{buf}
 		args = append(args, slice)
 		slice.Esc = EscNone
 	}
 
 	cat := syslook(fn)
-	r := Nod(OCALL, cat, nil)
+	r := Nod(ir.OCALL, cat, nil)
 	r.List.Set(args)
 	r = typecheck(r, Erv)
 	r = walkexpr(r, init)
@@ -2786,7 +2834,7 @@ func addstr(n *Node, init *Nodes) *Node {
 //   s
 //
 // l2 is allowed to be a string.
-func appendslice(n *Node, init *Nodes) *Node {
+func appendslice(n *ir.Node, init *ir.Nodes) *ir.Node {
 	walkexprlistsafe(n.List.Slice(), init)
 
 	// walkexprlistsafe will leave OINDEX (s[n]) alone if both s
@@ -2800,82 +2848,85 @@ func appendslice(n *Node, init *Nodes) *Node {
 	l1 := n.List.First()
 	l2 := n.List.Second()
 
-	var l []*Node
+	var l []*ir.Node
 
 	// var s []T
 	s := temp(l1.Type)
-	l = append(l, Nod(OAS, s, l1)) // s = l1
+	l = append(l, Nod(ir.OAS, s, l1)) // s = l1
 
 	// n := len(s) + len(l2)
-	nn := temp(Types[TINT])
-	l = append(l, Nod(OAS, nn, Nod(OADD, Nod(OLEN, s, nil), Nod(OLEN, l2, nil))))
+	nn := temp(ir.Types[ir.TINT])
+	l = append(l, Nod(ir.OAS, nn, Nod(ir.OADD, Nod(ir.OLEN, s, nil), Nod(ir.OLEN, l2, nil))))
 
 	// if uint(n) > uint(cap(s))
-	nif := Nod(OIF, nil, nil)
-	nif.Left = Nod(OGT, Nod(OCONV, nn, nil), Nod(OCONV, Nod(OCAP, s, nil), nil))
-	nif.Left.Left.Type = Types[TUINT]
-	nif.Left.Right.Type = Types[TUINT]
+	nif := Nod(ir.OIF, nil, nil)
+	nif.Left = Nod(ir.OGT, Nod(ir.OCONV, nn, nil), Nod(ir.OCONV, Nod(ir.OCAP, s, nil), nil))
+	nif.Left.Left.Type = ir.Types[ir.TUINT]
+	nif.Left.Right.Type = ir.Types[ir.TUINT]
 
 	// instantiate growslice(Type*, []any, int) []any
 	fn := syslook("growslice")
 	fn = substArgTypes(fn, s.Type.Type, s.Type.Type)
 
 	// s = growslice(T, s, n)
-	nif.Nbody.Set1(Nod(OAS, s, mkcall1(fn, s.Type, &nif.Ninit, typename(s.Type), s, nn)))
+	nif.Nbody.Set1(Nod(ir.OAS, s, mkcall1(fn, s.Type, &nif.Ninit, typename(s.Type), s, nn)))
 	l = append(l, nif)
 
 	// s = s[:n]
-	nt := Nod(OSLICE, s, Nod(OKEY, nil, nn))
+	nt := Nod(ir.OSLICE, s, Nod(ir.OKEY, nil, nn))
 	nt.Etype = 1
-	l = append(l, Nod(OAS, s, nt))
+	l = append(l, Nod(ir.OAS, s, nt))
 
 	if haspointers(l1.Type.Type) {
+		if Debug_wb > 0 {
+			Warnl(n.Lineno, "write barrier")
+		}
 		// copy(s[len(l1):], l2)
-		nptr1 := Nod(OSLICE, s, Nod(OKEY, Nod(OLEN, l1, nil), nil))
+		nptr1 := Nod(ir.OSLICE, s, Nod(ir.OKEY, Nod(ir.OLEN, l1, nil), nil))
 
 		nptr1.Etype = 1
 		nptr2 := l2
 		fn := syslook("typedslicecopy")
 		fn = substArgTypes(fn, l1.Type, l2.Type)
-		var ln Nodes
+		var ln ir.Nodes
 		ln.Set(l)
-		nt := mkcall1(fn, Types[TINT], &ln, typename(l1.Type.Type), nptr1, nptr2)
+		nt := mkcall1(fn, ir.Types[ir.TINT], &ln, typename(l1.Type.Type), nptr1, nptr2)
 		l = append(ln.Slice(), nt)
 	} else if instrumenting {
 		// rely on runtime to instrument copy.
 		// copy(s[len(l1):], l2)
-		nptr1 := Nod(OSLICE, s, Nod(OKEY, Nod(OLEN, l1, nil), nil))
+		nptr1 := Nod(ir.OSLICE, s, Nod(ir.OKEY, Nod(ir.OLEN, l1, nil), nil))
 
 		nptr1.Etype = 1
 		nptr2 := l2
-		var fn *Node
-		if l2.Type.Etype == TSTRING {
+		var fn *ir.Node
+		if l2.Type.Etype == ir.TSTRING {
 			fn = syslook("slicestringcopy")
 		} else {
 			fn = syslook("slicecopy")
 		}
 		fn = substArgTypes(fn, l1.Type, l2.Type)
-		var ln Nodes
+		var ln ir.Nodes
 		ln.Set(l)
-		nt := mkcall1(fn, Types[TINT], &ln, nptr1, nptr2, Nodintconst(s.Type.Type.Width))
+		nt := mkcall1(fn, ir.Types[ir.TINT], &ln, nptr1, nptr2, Nodintconst(s.Type.Type.Width))
 		l = append(ln.Slice(), nt)
 	} else {
 		// memmove(&s[len(l1)], &l2[0], len(l2)*sizeof(T))
-		nptr1 := Nod(OINDEX, s, Nod(OLEN, l1, nil))
+		nptr1 := Nod(ir.OINDEX, s, Nod(ir.OLEN, l1, nil))
 		nptr1.Bounded = true
 
-		nptr1 = Nod(OADDR, nptr1, nil)
+		nptr1 = Nod(ir.OADDR, nptr1, nil)
 
-		nptr2 := Nod(OSPTR, l2, nil)
+		nptr2 := Nod(ir.OSPTR, l2, nil)
 
 		fn := syslook("memmove")
 		fn = substArgTypes(fn, s.Type.Type, s.Type.Type)
 
-		var ln Nodes
+		var ln ir.Nodes
 		ln.Set(l)
-		nwid := cheapexpr(conv(Nod(OLEN, l2, nil), Types[TUINTPTR]), &ln)
+		nwid := cheapexpr(conv(Nod(ir.OLEN, l2, nil), ir.Types[ir.TUINTPTR]), &ln)
 
-		nwid = Nod(OMUL, nwid, Nodintconst(s.Type.Type.Width))
+		nwid = Nod(ir.OMUL, nwid, Nodintconst(s.Type.Type.Width))
 		nt := mkcall1(fn, nil, &ln, nptr1, nptr2, nwid)
 		l = append(ln.Slice(), nt)
 	}
@@ -2907,7 +2958,7 @@ func appendslice(n *Node, init *Nodes) *Node {
 //     ...
 //   }
 //   s
-func walkappend(n *Node, init *Nodes, dst *Node) *Node {
+func walkappend(n *ir.Node, init *ir.Nodes, dst *ir.Node) *ir.Node {
 	if !samesafeexpr(dst, n.List.First()) {
 		n.List.SetIndex(0, safeexpr(n.List.Index(0), init))
 		n.List.SetIndex(0, walkexpr(n.List.Index(0), init))
@@ -2928,7 +2979,7 @@ func walkappend(n *Node, init *Nodes, dst *Node) *Node {
 	nsrc := n.List.First()
 
 	// Resolve slice type of multi-valued return.
-	if Istype(nsrc.Type, TSTRUCT) {
+	if Istype(nsrc.Type, ir.TSTRUCT) {
 		nsrc.Type = nsrc.Type.Type.Type
 	}
 	argc := n.List.Len() - 1
@@ -2942,38 +2993,38 @@ func walkappend(n *Node, init *Nodes, dst *Node) *Node {
 		return n
 	}
 
-	var l []*Node
+	var l []*ir.Node
 
 	ns := temp(nsrc.Type)
-	l = append(l, Nod(OAS, ns, nsrc)) // s = src
+	l = append(l, Nod(ir.OAS, ns, nsrc)) // s = src
 
 	na := Nodintconst(int64(argc)) // const argc
-	nx := Nod(OIF, nil, nil)       // if cap(s) - len(s) < argc
-	nx.Left = Nod(OLT, Nod(OSUB, Nod(OCAP, ns, nil), Nod(OLEN, ns, nil)), na)
+	nx := Nod(ir.OIF, nil, nil)    // if cap(s) - len(s) < argc
+	nx.Left = Nod(ir.OLT, Nod(ir.OSUB, Nod(ir.OCAP, ns, nil), Nod(ir.OLEN, ns, nil)), na)
 
 	fn := syslook("growslice") //   growslice(<type>, old []T, mincap int) (ret []T)
 	fn = substArgTypes(fn, ns.Type.Type, ns.Type.Type)
 
-	nx.Nbody.Set1(Nod(OAS, ns,
+	nx.Nbody.Set1(Nod(ir.OAS, ns,
 		mkcall1(fn, ns.Type, &nx.Ninit, typename(ns.Type), ns,
-			Nod(OADD, Nod(OLEN, ns, nil), na))))
+			Nod(ir.OADD, Nod(ir.OLEN, ns, nil), na))))
 
 	l = append(l, nx)
 
-	nn := temp(Types[TINT])
-	l = append(l, Nod(OAS, nn, Nod(OLEN, ns, nil))) // n = len(s)
+	nn := temp(ir.Types[ir.TINT])
+	l = append(l, Nod(ir.OAS, nn, Nod(ir.OLEN, ns, nil))) // n = len(s)
 
-	nx = Nod(OSLICE, ns, Nod(OKEY, nil, Nod(OADD, nn, na))) // ...s[:n+argc]
+	nx = Nod(ir.OSLICE, ns, Nod(ir.OKEY, nil, Nod(ir.OADD, nn, na))) // ...s[:n+argc]
 	nx.Etype = 1
-	l = append(l, Nod(OAS, ns, nx)) // s = s[:n+argc]
+	l = append(l, Nod(ir.OAS, ns, nx)) // s = s[:n+argc]
 
 	ls = n.List.Slice()[1:]
 	for i, n := range ls {
-		nx = Nod(OINDEX, ns, nn) // s[n] ...
+		nx = Nod(ir.OINDEX, ns, nn) // s[n] ...
 		nx.Bounded = true
-		l = append(l, Nod(OAS, nx, n)) // s[n] = arg
+		l = append(l, Nod(ir.OAS, nx, n)) // s[n] = arg
 		if i+1 < len(ls) {
-			l = append(l, Nod(OAS, nn, Nod(OADD, nn, Nodintconst(1)))) // n = n + 1
+			l = append(l, Nod(ir.OAS, nn, Nod(ir.OADD, nn, Nodintconst(1)))) // n = n + 1
 		}
 	}
 
@@ -2994,15 +3045,18 @@ func walkappend(n *Node, init *Nodes, dst *Node) *Node {
 //
 // Also works if b is a string.
 //
-func copyany(n *Node, init *Nodes, runtimecall bool) *Node {
+func copyany(n *ir.Node, init *ir.Nodes, runtimecall bool) *ir.Node {
 	if haspointers(n.Left.Type.Type) {
+		if Debug_wb > 0 {
+			Warnl(n.Lineno, "write barrier")
+		}
 		fn := writebarrierfn("typedslicecopy", n.Left.Type, n.Right.Type)
 		return mkcall1(fn, n.Type, init, typename(n.Left.Type.Type), n.Left, n.Right)
 	}
 
 	if runtimecall {
-		var fn *Node
-		if n.Right.Type.Etype == TSTRING {
+		var fn *ir.Node
+		if n.Right.Type.Etype == ir.TSTRING {
 			fn = syslook("slicestringcopy")
 		} else {
 			fn = syslook("slicecopy")
@@ -3015,32 +3069,32 @@ func copyany(n *Node, init *Nodes, runtimecall bool) *Node {
 	n.Right = walkexpr(n.Right, init)
 	nl := temp(n.Left.Type)
 	nr := temp(n.Right.Type)
-	var l []*Node
-	l = append(l, Nod(OAS, nl, n.Left))
-	l = append(l, Nod(OAS, nr, n.Right))
+	var l []*ir.Node
+	l = append(l, Nod(ir.OAS, nl, n.Left))
+	l = append(l, Nod(ir.OAS, nr, n.Right))
 
-	nfrm := Nod(OSPTR, nr, nil)
-	nto := Nod(OSPTR, nl, nil)
+	nfrm := Nod(ir.OSPTR, nr, nil)
+	nto := Nod(ir.OSPTR, nl, nil)
 
-	nlen := temp(Types[TINT])
+	nlen := temp(ir.Types[ir.TINT])
 
 	// n = len(to)
-	l = append(l, Nod(OAS, nlen, Nod(OLEN, nl, nil)))
+	l = append(l, Nod(ir.OAS, nlen, Nod(ir.OLEN, nl, nil)))
 
 	// if n > len(frm) { n = len(frm) }
-	nif := Nod(OIF, nil, nil)
+	nif := Nod(ir.OIF, nil, nil)
 
-	nif.Left = Nod(OGT, nlen, Nod(OLEN, nr, nil))
-	nif.Nbody.Append(Nod(OAS, nlen, Nod(OLEN, nr, nil)))
+	nif.Left = Nod(ir.OGT, nlen, Nod(ir.OLEN, nr, nil))
+	nif.Nbody.Append(Nod(ir.OAS, nlen, Nod(ir.OLEN, nr, nil)))
 	l = append(l, nif)
 
 	// Call memmove.
 	fn := syslook("memmove")
 
 	fn = substArgTypes(fn, nl.Type.Type, nl.Type.Type)
-	nwid := temp(Types[TUINTPTR])
-	l = append(l, Nod(OAS, nwid, conv(nlen, Types[TUINTPTR])))
-	nwid = Nod(OMUL, nwid, Nodintconst(nl.Type.Type.Width))
+	nwid := temp(ir.Types[ir.TUINTPTR])
+	l = append(l, Nod(ir.OAS, nwid, conv(nlen, ir.Types[ir.TUINTPTR])))
+	nwid = Nod(ir.OMUL, nwid, Nodintconst(nl.Type.Type.Width))
 	l = append(l, mkcall1(fn, nil, init, nto, nfrm, nwid))
 
 	typecheckslice(l, Etop)
@@ -3049,7 +3103,7 @@ func copyany(n *Node, init *Nodes, runtimecall bool) *Node {
 	return nlen
 }
 
-func eqfor(t *Type, needsize *int) *Node {
+func eqfor(t *ir.Type, needsize *int) *ir.Node {
 	// Should only arrive here with large memory or
 	// a struct/array containing a non-memory field/element.
 	// Small memory is handled inline, and single non-memory
@@ -3069,11 +3123,11 @@ func eqfor(t *Type, needsize *int) *Node {
 
 	sym := typesymprefix(".eq", t)
 	n := newname(sym)
-	n.Class = PFUNC
-	ntype := Nod(OTFUNC, nil, nil)
-	ntype.List.Append(Nod(ODCLFIELD, nil, typenod(Ptrto(t))))
-	ntype.List.Append(Nod(ODCLFIELD, nil, typenod(Ptrto(t))))
-	ntype.Rlist.Append(Nod(ODCLFIELD, nil, typenod(Types[TBOOL])))
+	n.Class = ir.PFUNC
+	ntype := Nod(ir.OTFUNC, nil, nil)
+	ntype.List.Append(Nod(ir.ODCLFIELD, nil, typenod(Ptrto(t))))
+	ntype.List.Append(Nod(ir.ODCLFIELD, nil, typenod(Ptrto(t))))
+	ntype.Rlist.Append(Nod(ir.ODCLFIELD, nil, typenod(ir.Types[ir.TBOOL])))
 	ntype = typecheck(ntype, Etype)
 	n.Type = ntype.Type
 	*needsize = 0
@@ -3082,7 +3136,7 @@ func eqfor(t *Type, needsize *int) *Node {
 
 // The result of walkcompare MUST be assigned back to n, e.g.
 // 	n.Left = walkcompare(n.Left, init)
-func walkcompare(n *Node, init *Nodes) *Node {
+func walkcompare(n *ir.Node, init *ir.Nodes) *ir.Node {
 	// Given interface value l and concrete value r, rewrite
 	//   l == r
 	// to
@@ -3090,9 +3144,9 @@ func walkcompare(n *Node, init *Nodes) *Node {
 	// Handle != similarly.
 	// This avoids the allocation that would be required
 	// to convert r to l for comparison.
-	var l *Node
+	var l *ir.Node
 
-	var r *Node
+	var r *ir.Node
 	if Isinter(n.Left.Type) && !Isinter(n.Right.Type) {
 		l = n.Left
 		r = n.Right
@@ -3104,19 +3158,19 @@ func walkcompare(n *Node, init *Nodes) *Node {
 	if l != nil {
 		x := temp(r.Type)
 		if haspointers(r.Type) {
-			a := Nod(OAS, x, nil)
+			a := Nod(ir.OAS, x, nil)
 			a = typecheck(a, Etop)
 			init.Append(a)
 		}
-		ok := temp(Types[TBOOL])
+		ok := temp(ir.Types[ir.TBOOL])
 
 		// l.(type(r))
-		a := Nod(ODOTTYPE, l, nil)
+		a := Nod(ir.ODOTTYPE, l, nil)
 
 		a.Type = r.Type
 
 		// x, ok := l.(type(r))
-		expr := Nod(OAS2, nil, nil)
+		expr := Nod(ir.OAS2, nil, nil)
 
 		expr.List.Append(x)
 		expr.List.Append(ok)
@@ -3124,10 +3178,10 @@ func walkcompare(n *Node, init *Nodes) *Node {
 		expr = typecheck(expr, Etop)
 		expr = walkexpr(expr, init)
 
-		if n.Op == OEQ {
-			r = Nod(OANDAND, ok, Nod(OEQ, x, r))
+		if n.Op == ir.OEQ {
+			r = Nod(ir.OANDAND, ok, Nod(ir.OEQ, x, r))
 		} else {
-			r = Nod(OOROR, Nod(ONOT, ok, nil), Nod(ONE, x, r))
+			r = Nod(ir.OOROR, Nod(ir.ONOT, ok, nil), Nod(ir.ONE, x, r))
 		}
 		init.Append(expr)
 		n = finishcompare(n, r, init)
@@ -3142,21 +3196,21 @@ func walkcompare(n *Node, init *Nodes) *Node {
 	default:
 		return n
 
-	case TARRAY:
+	case ir.TARRAY:
 		if Isslice(t) {
 			return n
 		}
 
-	case TSTRUCT:
+	case ir.TSTRUCT:
 		break
 	}
 
 	cmpl := n.Left
-	for cmpl != nil && cmpl.Op == OCONVNOP {
+	for cmpl != nil && cmpl.Op == ir.OCONVNOP {
 		cmpl = cmpl.Left
 	}
 	cmpr := n.Right
-	for cmpr != nil && cmpr.Op == OCONVNOP {
+	for cmpr != nil && cmpr.Op == ir.OCONVNOP {
 		cmpr = cmpr.Left
 	}
 
@@ -3165,31 +3219,31 @@ func walkcompare(n *Node, init *Nodes) *Node {
 	}
 
 	l = temp(Ptrto(t))
-	a := Nod(OAS, l, Nod(OADDR, cmpl, nil))
+	a := Nod(ir.OAS, l, Nod(ir.OADDR, cmpl, nil))
 	a.Right.Etype = 1 // addr does not escape
 	a = typecheck(a, Etop)
 	init.Append(a)
 
 	r = temp(Ptrto(t))
-	a = Nod(OAS, r, Nod(OADDR, cmpr, nil))
+	a = Nod(ir.OAS, r, Nod(ir.OADDR, cmpr, nil))
 	a.Right.Etype = 1 // addr does not escape
 	a = typecheck(a, Etop)
 	init.Append(a)
 
-	var andor Op = OANDAND
-	if n.Op == ONE {
-		andor = OOROR
+	var andor ir.Op = ir.OANDAND
+	if n.Op == ir.ONE {
+		andor = ir.OOROR
 	}
 
-	var expr *Node
-	if t.Etype == TARRAY && t.Bound <= 4 && issimple[t.Type.Etype] {
+	var expr *ir.Node
+	if t.Etype == ir.TARRAY && t.Bound <= 4 && issimple[t.Type.Etype] {
 		// Four or fewer elements of a basic type.
 		// Unroll comparisons.
-		var li *Node
-		var ri *Node
+		var li *ir.Node
+		var ri *ir.Node
 		for i := 0; int64(i) < t.Bound; i++ {
-			li = Nod(OINDEX, l, Nodintconst(int64(i)))
-			ri = Nod(OINDEX, r, Nodintconst(int64(i)))
+			li = Nod(ir.OINDEX, l, Nodintconst(int64(i)))
+			ri = Nod(ir.OINDEX, r, Nodintconst(int64(i)))
 			a = Nod(n.Op, li, ri)
 			if expr == nil {
 				expr = a
@@ -3199,38 +3253,38 @@ func walkcompare(n *Node, init *Nodes) *Node {
 		}
 
 		if expr == nil {
-			expr = Nodbool(n.Op == OEQ)
+			expr = Nodbool(n.Op == ir.OEQ)
 		}
 		n = finishcompare(n, expr, init)
 		return n
 	}
 
-	if t.Etype == TARRAY {
+	if t.Etype == ir.TARRAY {
 		// Zero- or single-element array, of any type.
 		switch t.Bound {
 		case 0:
-			n = finishcompare(n, Nodbool(n.Op == OEQ), init)
+			n = finishcompare(n, Nodbool(n.Op == ir.OEQ), init)
 			return n
 		case 1:
-			l0 := Nod(OINDEX, l, Nodintconst(0))
-			r0 := Nod(OINDEX, r, Nodintconst(0))
+			l0 := Nod(ir.OINDEX, l, Nodintconst(0))
+			r0 := Nod(ir.OINDEX, r, Nodintconst(0))
 			a := Nod(n.Op, l0, r0)
 			n = finishcompare(n, a, init)
 			return n
 		}
 	}
 
-	if t.Etype == TSTRUCT && t.NumFields() <= 4 {
+	if t.Etype == ir.TSTRUCT && t.NumFields() <= 4 {
 		// Struct of four or fewer fields.
 		// Inline comparisons.
-		var li *Node
-		var ri *Node
+		var li *ir.Node
+		var ri *ir.Node
 		for _, t1 := range t.Fields().Slice() {
 			if isblanksym(t1.Sym) {
 				continue
 			}
-			li = NodSym(OXDOT, l, t1.Sym)
-			ri = NodSym(OXDOT, r, t1.Sym)
+			li = NodSym(ir.OXDOT, l, t1.Sym)
+			ri = NodSym(ir.OXDOT, r, t1.Sym)
 			a = Nod(n.Op, li, ri)
 			if expr == nil {
 				expr = a
@@ -3240,7 +3294,7 @@ func walkcompare(n *Node, init *Nodes) *Node {
 		}
 
 		if expr == nil {
-			expr = Nodbool(n.Op == OEQ)
+			expr = Nodbool(n.Op == ir.OEQ)
 		}
 		n = finishcompare(n, expr, init)
 		return n
@@ -3248,7 +3302,7 @@ func walkcompare(n *Node, init *Nodes) *Node {
 
 	// Chose not to inline. Call equality function directly.
 	var needsize int
-	call := Nod(OCALL, eqfor(t, &needsize), nil)
+	call := Nod(ir.OCALL, eqfor(t, &needsize), nil)
 
 	call.List.Append(l)
 	call.List.Append(r)
@@ -3256,8 +3310,8 @@ func walkcompare(n *Node, init *Nodes) *Node {
 		call.List.Append(Nodintconst(t.Width))
 	}
 	r = call
-	if n.Op != OEQ {
-		r = Nod(ONOT, r, nil)
+	if n.Op != ir.OEQ {
+		r = Nod(ir.ONOT, r, nil)
 	}
 
 	n = finishcompare(n, r, init)
@@ -3266,14 +3320,14 @@ func walkcompare(n *Node, init *Nodes) *Node {
 
 // The result of finishcompare MUST be assigned back to n, e.g.
 // 	n.Left = finishcompare(n.Left, x, r, init)
-func finishcompare(n, r *Node, init *Nodes) *Node {
+func finishcompare(n, r *ir.Node, init *ir.Nodes) *ir.Node {
 	// Use nn here to avoid passing r to typecheck.
 	nn := r
 	nn = typecheck(nn, Erv)
 	nn = walkexpr(nn, init)
 	r = nn
 	if r.Type != n.Type {
-		r = Nod(OCONVNOP, r, nil)
+		r = Nod(ir.OCONVNOP, r, nil)
 		r.Type = n.Type
 		r.Typecheck = 1
 		nn = r
@@ -3281,26 +3335,26 @@ func finishcompare(n, r *Node, init *Nodes) *Node {
 	return nn
 }
 
-func samecheap(a *Node, b *Node) bool {
-	var ar *Node
-	var br *Node
+func samecheap(a *ir.Node, b *ir.Node) bool {
+	var ar *ir.Node
+	var br *ir.Node
 	for a != nil && b != nil && a.Op == b.Op {
 		switch a.Op {
 		default:
 			return false
 
-		case ONAME:
+		case ir.ONAME:
 			return a == b
 
-		case ODOT, ODOTPTR:
+		case ir.ODOT, ir.ODOTPTR:
 			if a.Sym != b.Sym {
 				return false
 			}
 
-		case OINDEX:
+		case ir.OINDEX:
 			ar = a.Right
 			br = b.Right
-			if !Isconst(ar, CTINT) || !Isconst(br, CTINT) || ar.Val().U.(*Mpint).Cmp(br.Val().U.(*Mpint)) != 0 {
+			if !ir.Isconst(ar, ir.CTINT) || !ir.Isconst(br, ir.CTINT) || ar.Val().U.(*ir.Mpint).Cmp(br.Val().U.(*ir.Mpint)) != 0 {
 				return false
 			}
 		}
@@ -3314,7 +3368,7 @@ func samecheap(a *Node, b *Node) bool {
 
 // The result of walkrotate MUST be assigned back to n, e.g.
 // 	n.Left = walkrotate(n.Left)
-func walkrotate(n *Node) *Node {
+func walkrotate(n *ir.Node) *ir.Node {
 	if Thearch.Thechar == '0' || Thearch.Thechar == '7' || Thearch.Thechar == '9' {
 		return n
 	}
@@ -3323,7 +3377,7 @@ func walkrotate(n *Node) *Node {
 	l := n.Left
 
 	r := n.Right
-	if (n.Op != OOR && n.Op != OXOR) || (l.Op != OLSH && l.Op != ORSH) || (r.Op != OLSH && r.Op != ORSH) || n.Type == nil || Issigned[n.Type.Etype] || l.Op == r.Op {
+	if (n.Op != ir.OOR && n.Op != ir.OXOR) || (l.Op != ir.OLSH && l.Op != ir.ORSH) || (r.Op != ir.OLSH && r.Op != ir.ORSH) || n.Type == nil || Issigned[n.Type.Etype] || l.Op == r.Op {
 		return n
 	}
 
@@ -3336,20 +3390,20 @@ func walkrotate(n *Node) *Node {
 	w := int(l.Type.Width * 8)
 
 	if Smallintconst(l.Right) && Smallintconst(r.Right) {
-		sl := int(l.Right.Val().U.(*Mpint).Int64())
+		sl := int(l.Right.Val().U.(*ir.Mpint).Int64())
 		if sl >= 0 {
-			sr := int(r.Right.Val().U.(*Mpint).Int64())
+			sr := int(r.Right.Val().U.(*ir.Mpint).Int64())
 			if sr >= 0 && sl+sr == w {
 				// Rewrite left shift half to left rotate.
-				if l.Op == OLSH {
+				if l.Op == ir.OLSH {
 					n = l
 				} else {
 					n = r
 				}
-				n.Op = OLROT
+				n.Op = ir.OLROT
 
 				// Remove rotate 0 and rotate w.
-				s := int(n.Right.Val().U.(*Mpint).Int64())
+				s := int(n.Right.Val().U.(*ir.Mpint).Int64())
 
 				if s == 0 || s == w {
 					n = n.Left
@@ -3367,17 +3421,17 @@ func walkrotate(n *Node) *Node {
 // walkmul rewrites integer multiplication by powers of two as shifts.
 // The result of walkmul MUST be assigned back to n, e.g.
 // 	n.Left = walkmul(n.Left, init)
-func walkmul(n *Node, init *Nodes) *Node {
+func walkmul(n *ir.Node, init *ir.Nodes) *ir.Node {
 	if !Isint[n.Type.Etype] {
 		return n
 	}
 
-	var nr *Node
-	var nl *Node
-	if n.Right.Op == OLITERAL {
+	var nr *ir.Node
+	var nl *ir.Node
+	if n.Right.Op == ir.OLITERAL {
 		nl = n.Left
 		nr = n.Right
-	} else if n.Left.Op == OLITERAL {
+	} else if n.Left.Op == ir.OLITERAL {
 		nl = n.Right
 		nr = n.Left
 	} else {
@@ -3389,7 +3443,7 @@ func walkmul(n *Node, init *Nodes) *Node {
 	// x*0 is 0 (and side effects of x).
 	var pow int
 	var w int
-	if nr.Val().U.(*Mpint).Int64() == 0 {
+	if nr.Val().U.(*ir.Mpint).Int64() == 0 {
 		cheapexpr(nl, init)
 		Nodconst(n, n.Type, 0)
 		goto ret
@@ -3422,11 +3476,11 @@ func walkmul(n *Node, init *Nodes) *Node {
 		goto ret
 	}
 
-	n = Nod(OLSH, nl, Nodintconst(int64(pow)))
+	n = Nod(ir.OLSH, nl, Nodintconst(int64(pow)))
 
 ret:
 	if neg != 0 {
-		n = Nod(OMINUS, n, nil)
+		n = Nod(ir.OMINUS, n, nil)
 	}
 
 	n = typecheck(n, Erv)
@@ -3438,7 +3492,7 @@ ret:
 // operations.
 // The result of walkdiv MUST be assigned back to n, e.g.
 // 	n.Left = walkdiv(n.Left, init)
-func walkdiv(n *Node, init *Nodes) *Node {
+func walkdiv(n *ir.Node, init *ir.Nodes) *ir.Node {
 	// if >= 0, nr is 1<<pow // 1 if nr is negative.
 
 	// TODO(minux)
@@ -3446,7 +3500,7 @@ func walkdiv(n *Node, init *Nodes) *Node {
 		return n
 	}
 
-	if n.Right.Op != OLITERAL {
+	if n.Right.Op != ir.OLITERAL {
 		return n
 	}
 
@@ -3481,10 +3535,10 @@ func walkdiv(n *Node, init *Nodes) *Node {
 		m.W = w
 
 		if Issigned[nl.Type.Etype] {
-			m.Sd = nr.Val().U.(*Mpint).Int64()
+			m.Sd = nr.Val().U.(*ir.Mpint).Int64()
 			Smagic(&m)
 		} else {
-			m.Ud = uint64(nr.Val().U.(*Mpint).Int64())
+			m.Ud = uint64(nr.Val().U.(*ir.Mpint).Int64())
 			Umagic(&m)
 		}
 
@@ -3494,12 +3548,12 @@ func walkdiv(n *Node, init *Nodes) *Node {
 
 		// We have a quick division method so use it
 		// for modulo too.
-		if n.Op == OMOD {
+		if n.Op == ir.OMOD {
 			// rewrite as A%B = A - (A/B*B).
-			n1 := Nod(ODIV, nl, nr)
+			n1 := Nod(ir.ODIV, nl, nr)
 
-			n2 := Nod(OMUL, n1, nr)
-			n = Nod(OSUB, nl, n2)
+			n2 := Nod(ir.OMUL, n1, nr)
+			n = Nod(ir.OSUB, nl, n2)
 			goto ret
 		}
 
@@ -3508,77 +3562,77 @@ func walkdiv(n *Node, init *Nodes) *Node {
 			return n
 
 			// n1 = nl * magic >> w (HMUL)
-		case TUINT8, TUINT16, TUINT32:
-			var nc Node
+		case ir.TUINT8, ir.TUINT16, ir.TUINT32:
+			var nc ir.Node
 
 			Nodconst(&nc, nl.Type, int64(m.Um))
-			n1 := Nod(OHMUL, nl, &nc)
+			n1 := Nod(ir.OHMUL, nl, &nc)
 			n1 = typecheck(n1, Erv)
 			if m.Ua != 0 {
 				// Select a Go type with (at least) twice the width.
-				var twide *Type
+				var twide *ir.Type
 				switch Simtype[nl.Type.Etype] {
 				default:
 					return n
 
-				case TUINT8, TUINT16:
-					twide = Types[TUINT32]
+				case ir.TUINT8, ir.TUINT16:
+					twide = ir.Types[ir.TUINT32]
 
-				case TUINT32:
-					twide = Types[TUINT64]
+				case ir.TUINT32:
+					twide = ir.Types[ir.TUINT64]
 
-				case TINT8, TINT16:
-					twide = Types[TINT32]
+				case ir.TINT8, ir.TINT16:
+					twide = ir.Types[ir.TINT32]
 
-				case TINT32:
-					twide = Types[TINT64]
+				case ir.TINT32:
+					twide = ir.Types[ir.TINT64]
 				}
 
 				// add numerator (might overflow).
 				// n2 = (n1 + nl)
-				n2 := Nod(OADD, conv(n1, twide), conv(nl, twide))
+				n2 := Nod(ir.OADD, conv(n1, twide), conv(nl, twide))
 
 				// shift by m.s
-				var nc Node
+				var nc ir.Node
 
-				Nodconst(&nc, Types[TUINT], int64(m.S))
-				n = conv(Nod(ORSH, n2, &nc), nl.Type)
+				Nodconst(&nc, ir.Types[ir.TUINT], int64(m.S))
+				n = conv(Nod(ir.ORSH, n2, &nc), nl.Type)
 			} else {
 				// n = n1 >> m.s
-				var nc Node
+				var nc ir.Node
 
-				Nodconst(&nc, Types[TUINT], int64(m.S))
-				n = Nod(ORSH, n1, &nc)
+				Nodconst(&nc, ir.Types[ir.TUINT], int64(m.S))
+				n = Nod(ir.ORSH, n1, &nc)
 			}
 
 			// n1 = nl * magic >> w
-		case TINT8, TINT16, TINT32:
-			var nc Node
+		case ir.TINT8, ir.TINT16, ir.TINT32:
+			var nc ir.Node
 
 			Nodconst(&nc, nl.Type, m.Sm)
-			n1 := Nod(OHMUL, nl, &nc)
+			n1 := Nod(ir.OHMUL, nl, &nc)
 			n1 = typecheck(n1, Erv)
 			if m.Sm < 0 {
 				// add the numerator.
-				n1 = Nod(OADD, n1, nl)
+				n1 = Nod(ir.OADD, n1, nl)
 			}
 
 			// shift by m.s
-			var ns Node
+			var ns ir.Node
 
-			Nodconst(&ns, Types[TUINT], int64(m.S))
-			n2 := conv(Nod(ORSH, n1, &ns), nl.Type)
+			Nodconst(&ns, ir.Types[ir.TUINT], int64(m.S))
+			n2 := conv(Nod(ir.ORSH, n1, &ns), nl.Type)
 
 			// add 1 iff n1 is negative.
-			var nneg Node
+			var nneg ir.Node
 
-			Nodconst(&nneg, Types[TUINT], int64(w)-1)
-			n3 := Nod(ORSH, nl, &nneg) // n4 = -1 iff n1 is negative.
-			n = Nod(OSUB, n2, n3)
+			Nodconst(&nneg, ir.Types[ir.TUINT], int64(w)-1)
+			n3 := Nod(ir.ORSH, nl, &nneg) // n4 = -1 iff n1 is negative.
+			n = Nod(ir.OSUB, n2, n3)
 
 			// apply sign.
 			if m.Sd < 0 {
-				n = Nod(OMINUS, n, nil)
+				n = Nod(ir.OMINUS, n, nil)
 			}
 		}
 
@@ -3587,12 +3641,12 @@ func walkdiv(n *Node, init *Nodes) *Node {
 
 	switch pow {
 	case 0:
-		if n.Op == OMOD {
+		if n.Op == ir.OMOD {
 			// nl % 1 is zero.
 			Nodconst(n, n.Type, 0)
 		} else if s != 0 {
 			// divide by -1
-			n.Op = OMINUS
+			n.Op = ir.OMINUS
 
 			n.Right = nil
 		} else {
@@ -3602,37 +3656,37 @@ func walkdiv(n *Node, init *Nodes) *Node {
 
 	default:
 		if Issigned[n.Type.Etype] {
-			if n.Op == OMOD {
+			if n.Op == ir.OMOD {
 				// signed modulo 2^pow is like ANDing
 				// with the last pow bits, but if nl < 0,
 				// nl & (2^pow-1) is (nl+1)%2^pow - 1.
-				var nc Node
+				var nc ir.Node
 
-				Nodconst(&nc, Types[Simtype[TUINT]], int64(w)-1)
-				n1 := Nod(ORSH, nl, &nc) // n1 = -1 iff nl < 0.
+				Nodconst(&nc, ir.Types[Simtype[ir.TUINT]], int64(w)-1)
+				n1 := Nod(ir.ORSH, nl, &nc) // n1 = -1 iff nl < 0.
 				if pow == 1 {
 					n1 = typecheck(n1, Erv)
 					n1 = cheapexpr(n1, init)
 
 					// n = (nl+ε)&1 -ε where ε=1 iff nl<0.
-					n2 := Nod(OSUB, nl, n1)
+					n2 := Nod(ir.OSUB, nl, n1)
 
-					var nc Node
+					var nc ir.Node
 					Nodconst(&nc, nl.Type, 1)
-					n3 := Nod(OAND, n2, &nc)
-					n = Nod(OADD, n3, n1)
+					n3 := Nod(ir.OAND, n2, &nc)
+					n = Nod(ir.OADD, n3, n1)
 				} else {
 					// n = (nl+ε)&(nr-1) - ε where ε=2^pow-1 iff nl<0.
-					var nc Node
+					var nc ir.Node
 
 					Nodconst(&nc, nl.Type, (1<<uint(pow))-1)
-					n2 := Nod(OAND, n1, &nc) // n2 = 2^pow-1 iff nl<0.
+					n2 := Nod(ir.OAND, n1, &nc) // n2 = 2^pow-1 iff nl<0.
 					n2 = typecheck(n2, Erv)
 					n2 = cheapexpr(n2, init)
 
-					n3 := Nod(OADD, nl, n2)
-					n4 := Nod(OAND, n3, &nc)
-					n = Nod(OSUB, n4, n2)
+					n3 := Nod(ir.OADD, nl, n2)
+					n4 := Nod(ir.OAND, n3, &nc)
+					n = Nod(ir.OSUB, n4, n2)
 				}
 
 				break
@@ -3640,48 +3694,48 @@ func walkdiv(n *Node, init *Nodes) *Node {
 				// arithmetic right shift does not give the correct rounding.
 				// if nl >= 0, nl >> n == nl / nr
 				// if nl < 0, we want to add 2^n-1 first.
-				var nc Node
+				var nc ir.Node
 
-				Nodconst(&nc, Types[Simtype[TUINT]], int64(w)-1)
-				n1 := Nod(ORSH, nl, &nc) // n1 = -1 iff nl < 0.
+				Nodconst(&nc, ir.Types[Simtype[ir.TUINT]], int64(w)-1)
+				n1 := Nod(ir.ORSH, nl, &nc) // n1 = -1 iff nl < 0.
 				if pow == 1 {
 					// nl+1 is nl-(-1)
-					n.Left = Nod(OSUB, nl, n1)
+					n.Left = Nod(ir.OSUB, nl, n1)
 				} else {
 					// Do a logical right right on -1 to keep pow bits.
-					var nc Node
+					var nc ir.Node
 
-					Nodconst(&nc, Types[Simtype[TUINT]], int64(w)-int64(pow))
-					n2 := Nod(ORSH, conv(n1, tounsigned(nl.Type)), &nc)
-					n.Left = Nod(OADD, nl, conv(n2, nl.Type))
+					Nodconst(&nc, ir.Types[Simtype[ir.TUINT]], int64(w)-int64(pow))
+					n2 := Nod(ir.ORSH, conv(n1, tounsigned(nl.Type)), &nc)
+					n.Left = Nod(ir.OADD, nl, conv(n2, nl.Type))
 				}
 
 				// n = (nl + 2^pow-1) >> pow
-				n.Op = ORSH
+				n.Op = ir.ORSH
 
-				var n2 Node
-				Nodconst(&n2, Types[Simtype[TUINT]], int64(pow))
+				var n2 ir.Node
+				Nodconst(&n2, ir.Types[Simtype[ir.TUINT]], int64(pow))
 				n.Right = &n2
 				n.Typecheck = 0
 			}
 
 			if s != 0 {
-				n = Nod(OMINUS, n, nil)
+				n = Nod(ir.OMINUS, n, nil)
 			}
 			break
 		}
 
-		var nc Node
-		if n.Op == OMOD {
+		var nc ir.Node
+		if n.Op == ir.OMOD {
 			// n = nl & (nr-1)
-			n.Op = OAND
+			n.Op = ir.OAND
 
-			Nodconst(&nc, nl.Type, nr.Val().U.(*Mpint).Int64()-1)
+			Nodconst(&nc, nl.Type, nr.Val().U.(*ir.Mpint).Int64()-1)
 		} else {
 			// n = nl >> pow
-			n.Op = ORSH
+			n.Op = ir.ORSH
 
-			Nodconst(&nc, Types[Simtype[TUINT]], int64(pow))
+			Nodconst(&nc, ir.Types[Simtype[ir.TUINT]], int64(pow))
 		}
 
 		n.Typecheck = 0
@@ -3697,7 +3751,7 @@ ret:
 }
 
 // return 1 if integer n must be in range [0, max), 0 otherwise
-func bounded(n *Node, max int64) bool {
+func bounded(n *ir.Node, max int64) bool {
 	if n.Type == nil || !Isint[n.Type.Etype] {
 		return false
 	}
@@ -3706,43 +3760,43 @@ func bounded(n *Node, max int64) bool {
 	bits := int32(8 * n.Type.Width)
 
 	if Smallintconst(n) {
-		v := n.Val().U.(*Mpint).Int64()
+		v := n.Val().U.(*ir.Mpint).Int64()
 		return 0 <= v && v < max
 	}
 
 	switch n.Op {
-	case OAND:
+	case ir.OAND:
 		v := int64(-1)
 		if Smallintconst(n.Left) {
-			v = n.Left.Val().U.(*Mpint).Int64()
+			v = n.Left.Val().U.(*ir.Mpint).Int64()
 		} else if Smallintconst(n.Right) {
-			v = n.Right.Val().U.(*Mpint).Int64()
+			v = n.Right.Val().U.(*ir.Mpint).Int64()
 		}
 
 		if 0 <= v && v < max {
 			return true
 		}
 
-	case OMOD:
+	case ir.OMOD:
 		if !sign && Smallintconst(n.Right) {
-			v := n.Right.Val().U.(*Mpint).Int64()
+			v := n.Right.Val().U.(*ir.Mpint).Int64()
 			if 0 <= v && v <= max {
 				return true
 			}
 		}
 
-	case ODIV:
+	case ir.ODIV:
 		if !sign && Smallintconst(n.Right) {
-			v := n.Right.Val().U.(*Mpint).Int64()
+			v := n.Right.Val().U.(*ir.Mpint).Int64()
 			for bits > 0 && v >= 2 {
 				bits--
 				v >>= 1
 			}
 		}
 
-	case ORSH:
+	case ir.ORSH:
 		if !sign && Smallintconst(n.Right) {
-			v := n.Right.Val().U.(*Mpint).Int64()
+			v := n.Right.Val().U.(*ir.Mpint).Int64()
 			if v > int64(bits) {
 				return true
 			}
@@ -3758,15 +3812,12 @@ func bounded(n *Node, max int64) bool {
 }
 
 // usemethod check interface method calls for uses of reflect.Type.Method.
-func usemethod(n *Node) {
+func usemethod(n *ir.Node) {
 	t := n.Left.Type
 
 	// Looking for either of:
 	//	Method(int) reflect.Method
 	//	MethodByName(string) (reflect.Method, bool)
-	//
-	// TODO(crawshaw): improve precision of match by working out
-	//                 how to check the method name.
 	if n := t.Params().NumFields(); n != 1 {
 		return
 	}
@@ -3775,20 +3826,20 @@ func usemethod(n *Node) {
 	}
 	p0 := t.Params().Field(0)
 	res0 := t.Results().Field(0)
-	var res1 *Field
+	var res1 *ir.Field
 	if t.Results().NumFields() == 2 {
 		res1 = t.Results().Field(1)
 	}
 
 	if res1 == nil {
-		if p0.Type.Etype != TINT {
+		if p0.Type.Etype != ir.TINT {
 			return
 		}
 	} else {
-		if p0.Type.Etype != TSTRING {
+		if p0.Type.Etype != ir.TSTRING {
 			return
 		}
-		if res1.Type.Etype != TBOOL {
+		if res1.Type.Etype != ir.TBOOL {
 			return
 		}
 	}
@@ -3796,10 +3847,26 @@ func usemethod(n *Node) {
 		return
 	}
 
-	Curfn.Func.ReflectMethod = true
+	// A constant method name or index tells the linker exactly which
+	// method may be needed. Otherwise, any exported method of any
+	// reachable type may be.
+	var name string
+	switch arg := n.List.First(); {
+	case ir.Isconst(arg, ir.CTSTR):
+		name = arg.Val().U.(string)
+	case ir.Isconst(arg, ir.CTINT):
+		name = fmt.Sprint(arg.Int())
+	default:
+		Curfn.Func.ReflectMethod = true
+		return
+	}
+	if Curfn.Func.ReflectMethods == nil {
+		Curfn.Func.ReflectMethods = make(map[*ir.Sym]struct{})
+	}
+	Curfn.Func.ReflectMethods[Pkglookup(name, reflectmethodpkg)] = struct{}{}
 }
 
-func usefield(n *Node) {
+func usefield(n *ir.Node) {
 	if obj.Fieldtrack_enabled == 0 {
 		return
 	}
@@ -3808,7 +3875,7 @@ func usefield(n *Node) {
 	default:
 		Fatalf("usefield %v", Oconv(n.Op, 0))
 
-	case ODOT, ODOTPTR:
+	case ir.ODOT, ir.ODOTPTR:
 		break
 	}
 	if n.Sym == nil {
@@ -3842,12 +3909,12 @@ func usefield(n *Node) {
 
 	sym := tracksym(outer, field)
 	if Curfn.Func.FieldTrack == nil {
-		Curfn.Func.FieldTrack = make(map[*Sym]struct{})
+		Curfn.Func.FieldTrack = make(map[*ir.Sym]struct{})
 	}
 	Curfn.Func.FieldTrack[sym] = struct{}{}
 }
 
-func candiscardlist(l Nodes) bool {
+func candiscardlist(l ir.Nodes) bool {
 	for _, n := range l.Slice() {
 		if !candiscard(n) {
 			return false
@@ -3856,7 +3923,7 @@ func candiscardlist(l Nodes) bool {
 	return true
 }
 
-func candiscard(n *Node) bool {
+func candiscard(n *ir.Node) bool {
 	if n == nil {
 		return true
 	}
@@ -3866,79 +3933,79 @@ func candiscard(n *Node) bool {
 		return false
 
 		// Discardable as long as the subpieces are.
-	case ONAME,
-		ONONAME,
-		OTYPE,
-		OPACK,
-		OLITERAL,
-		OADD,
-		OSUB,
-		OOR,
-		OXOR,
-		OADDSTR,
-		OADDR,
-		OANDAND,
-		OARRAYBYTESTR,
-		OARRAYRUNESTR,
-		OSTRARRAYBYTE,
-		OSTRARRAYRUNE,
-		OCAP,
-		OCMPIFACE,
-		OCMPSTR,
-		OCOMPLIT,
-		OMAPLIT,
-		OSTRUCTLIT,
-		OARRAYLIT,
-		OPTRLIT,
-		OCONV,
-		OCONVIFACE,
-		OCONVNOP,
-		ODOT,
-		OEQ,
-		ONE,
-		OLT,
-		OLE,
-		OGT,
-		OGE,
-		OKEY,
-		OLEN,
-		OMUL,
-		OLSH,
-		ORSH,
-		OAND,
-		OANDNOT,
-		ONEW,
-		ONOT,
-		OCOM,
-		OPLUS,
-		OMINUS,
-		OOROR,
-		OPAREN,
-		ORUNESTR,
-		OREAL,
-		OIMAG,
-		OCOMPLEX:
+	case ir.ONAME,
+		ir.ONONAME,
+		ir.OTYPE,
+		ir.OPACK,
+		ir.OLITERAL,
+		ir.OADD,
+		ir.OSUB,
+		ir.OOR,
+		ir.OXOR,
+		ir.OADDSTR,
+		ir.OADDR,
+		ir.OANDAND,
+		ir.OARRAYBYTESTR,
+		ir.OARRAYRUNESTR,
+		ir.OSTRARRAYBYTE,
+		ir.OSTRARRAYRUNE,
+		ir.OCAP,
+		ir.OCMPIFACE,
+		ir.OCMPSTR,
+		ir.OCOMPLIT,
+		ir.OMAPLIT,
+		ir.OSTRUCTLIT,
+		ir.OARRAYLIT,
+		ir.OPTRLIT,
+		ir.OCONV,
+		ir.OCONVIFACE,
+		ir.OCONVNOP,
+		ir.ODOT,
+		ir.OEQ,
+		ir.ONE,
+		ir.OLT,
+		ir.OLE,
+		ir.OGT,
+		ir.OGE,
+		ir.OKEY,
+		ir.OLEN,
+		ir.OMUL,
+		ir.OLSH,
+		ir.ORSH,
+		ir.OAND,
+		ir.OANDNOT,
+		ir.ONEW,
+		ir.ONOT,
+		ir.OCOM,
+		ir.OPLUS,
+		ir.OMINUS,
+		ir.OOROR,
+		ir.OPAREN,
+		ir.ORUNESTR,
+		ir.OREAL,
+		ir.OIMAG,
+		ir.OCOMPLEX:
 		break
 
 		// Discardable as long as we know it's not division by zero.
-	case ODIV, OMOD:
-		if Isconst(n.Right, CTINT) && n.Right.Val().U.(*Mpint).CmpInt64(0) != 0 {
+	case ir.ODIV, ir.OMOD:
+		if ir.Isconst(n.Right, ir.CTINT) && n.Right.Val().U.(*ir.Mpint).CmpInt64(0) != 0 {
 			break
 		}
-		if Isconst(n.Right, CTFLT) && n.Right.Val().U.(*Mpflt).CmpFloat64(0) != 0 {
+		if ir.Isconst(n.Right, ir.CTFLT) && n.Right.Val().U.(*ir.Mpflt).CmpFloat64(0) != 0 {
 			break
 		}
 		return false
 
 		// Discardable as long as we know it won't fail because of a bad size.
-	case OMAKECHAN, OMAKEMAP:
-		if Isconst(n.Left, CTINT) && n.Left.Val().U.(*Mpint).CmpInt64(0) == 0 {
+	case ir.OMAKECHAN, ir.OMAKEMAP:
+		if ir.Isconst(n.Left, ir.CTINT) && n.Left.Val().U.(*ir.Mpint).CmpInt64(0) == 0 {
 			break
 		}
 		return false
 
 		// Difficult to tell what sizes are okay.
-	case OMAKESLICE:
+	case ir.OMAKESLICE:
 		return false
 	}
 
@@ -3959,34 +4026,74 @@ func candiscard(n *Node) bool {
 
 var walkprintfunc_prgen int
 
+// A printfunc is a wrapper generated by walkprintfunc.
+type printfunc struct {
+	op    ir.Op      // OPRINT or OPRINTN
+	types []*ir.Type // argument types
+	nname *ir.Node
+}
+
+// printfuncs lists the wrappers generated so far. Calls with
+// the same op and argument types share a wrapper.
+var printfuncs []printfunc
+
+// lookprintfunc returns the wrapper for n, or nil if there is none yet.
+func lookprintfunc(n *ir.Node) *ir.Node {
+	args := n.List.Slice()
+Outer:
+	for _, p := range printfuncs {
+		if p.op != n.Op || len(p.types) != len(args) {
+			continue
+		}
+		for i, n1 := range args {
+			if !Eqtype(p.types[i], n1.Type) {
+				continue Outer
+			}
+		}
+		return p.nname
+	}
+	return nil
+}
+
 // The result of walkprintfunc MUST be assigned back to n, e.g.
 // 	n.Left = walkprintfunc(n.Left, init)
-func walkprintfunc(n *Node, init *Nodes) *Node {
+func walkprintfunc(n *ir.Node, init *ir.Nodes) *ir.Node {
 	if n.Ninit.Len() != 0 {
 		walkstmtlist(n.Ninit.Slice())
 		init.AppendNodes(&n.Ninit)
 	}
 
-	t := Nod(OTFUNC, nil, nil)
+	if nname := lookprintfunc(n); nname != nil {
+		a := Nod(ir.OCALL, nil, nil)
+		a.Left = nname
+		a.List.Set(n.List.Slice())
+		a = typecheck(a, Etop)
+		a = walkexpr(a, init)
+		return a
+	}
+
+	t := Nod(ir.OTFUNC, nil, nil)
+	var types []*ir.Type
 	num := 0
-	var printargs []*Node
-	var a *Node
+	var printargs []*ir.Node
+	var a *ir.Node
 	var buf string
 	for _, n1 := range n.List.Slice() {
 		buf = fmt.Sprintf("a%d", num)
 		num++
-		a = Nod(ODCLFIELD, newname(Lookup(buf)), typenod(n1.Type))
+		a = Nod(ir.ODCLFIELD, newname(Lookup(buf)), typenod(n1.Type))
 		t.List.Append(a)
 		printargs = append(printargs, a.Left)
+		types = append(types, n1.Type)
 	}
 
-	fn := Nod(ODCLFUNC, nil, nil)
+	fn := Nod(ir.ODCLFUNC, nil, nil)
 	walkprintfunc_prgen++
 	buf = fmt.Sprintf("print·%d", walkprintfunc_prgen)
 	fn.Func.Nname = newname(Lookup(buf))
 	fn.Func.Nname.Name.Defn = fn
 	fn.Func.Nname.Name.Param.Ntype = t
-	declare(fn.Func.Nname, PFUNC)
+	declare(fn.Func.Nname, ir.PFUNC)
 
 	oldfn := Curfn
 	Curfn = nil
@@ -4005,8 +4112,9 @@ func walkprintfunc(n *Node, init *Nodes) *Node {
 	typecheckslice(fn.Nbody.Slice(), Etop)
 	xtop = append(xtop, fn)
 	Curfn = oldfn
+	printfuncs = append(printfuncs, printfunc{n.Op, types, fn.Func.Nname})
 
-	a = Nod(OCALL, nil, nil)
+	a = Nod(ir.OCALL, nil, nil)
 	a.Left = fn.Func.Nname
 	a.List.Set(n.List.Slice())
 	a = typecheck(a, Etop)
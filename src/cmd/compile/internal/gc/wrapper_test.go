@@ -0,0 +1,63 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gc
+
+import (
+	"strings"
+	"testing"
+)
+
+// Make sure wrappers for interface method expressions are
+// generated only for the method expressions that are used.
+func TestIfaceMethodExprWrappers(t *testing.T) {
+	d := newTestDir(t, "TestIfaceMethodExprWrappers")
+	defer d.remove()
+
+	obj := d.compile("x", `
+package x
+
+type I interface {
+	M() int
+	N() int
+}
+
+var F = I.M
+`)
+	nm := d.run("go", "tool", "nm", obj)
+	if !strings.Contains(nm, "T %22%22.I.M") {
+		t.Errorf("missing wrapper I.M:\n%s", nm)
+	}
+	if strings.Contains(nm, ".I.N") {
+		t.Errorf("unexpected wrapper I.N:\n%s", nm)
+	}
+}
+
+// Make sure go and defer of print calls with the same argument
+// types share a wrapper.
+func TestSharedPrintWrappers(t *testing.T) {
+	d := newTestDir(t, "TestSharedPrintWrappers")
+	defer d.remove()
+
+	obj := d.compile("x", `
+package x
+
+func f(x int, s string) {
+	defer println(x, s)
+	defer println(x+1, "b")
+	go println(x)
+}
+
+func g(y int) {
+	defer println(y, "c")
+	go println(y)
+	defer print(y, "d")
+}
+`)
+	nm := d.run("go", "tool", "nm", obj)
+	// One wrapper each for println(int, string), println(int) and print(int, string).
+	if !strings.Contains(nm, "T %22%22.print·3") || strings.Contains(nm, "T %22%22.print·4") {
+		t.Errorf("want exactly 3 print wrappers:\n%s", nm)
+	}
+}
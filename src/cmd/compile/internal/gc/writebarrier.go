@@ -0,0 +1,314 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gc
+
+import (
+	"cmd/compile/internal/ir"
+	"cmd/compile/internal/ssa"
+	"strings"
+)
+
+// Write barrier insertion.
+//
+// Walk lowers assignments to OAS but does not decide which of them need
+// write barriers. Once walk is done, writebarrier visits every assignment
+// in the function and turns those that need a barrier into OASWB. The
+// decision for each store is made by wbreason alone, so -d=wb=2 can
+// audit it: every pointer store to memory that may be in the heap is
+// reported together with either its barrier or the reason it has none.
+//
+// With -d=wb=2 the pass also records the reason for each assignment in
+// Func.WBReasons, and the back ends check the stores they generate
+// against it without asking wbreason again: the SSA back end checks the
+// barrier and the reason against the address and value stored (see
+// checkwbssa), and the legacy back end checks the barrier (checkwbgen).
+// -d=wbbreak=1 drops the first barrier marked in each function, and
+// -d=wbbreak=2 also records that the store is to the stack, so that the
+// checks can be tested.
+
+// wbreason reports why the assignment l = r needs no write barrier.
+// It returns the empty string if a write barrier is required.
+func wbreason(l *ir.Node, r *ir.Node) string {
+	if use_writebarrier == 0 {
+		return "write barriers disabled"
+	}
+
+	if l == nil || isblank(l) {
+		return "assignment to blank"
+	}
+
+	// No write barrier for write of non-pointers.
+	dowidth(l.Type)
+
+	if !haspointers(l.Type) {
+		return "no pointers"
+	}
+
+	// No write barrier for write to stack.
+	if isstack(l) {
+		return "store to stack"
+	}
+
+	// No write barrier for implicit zeroing.
+	if r == nil {
+		return "implicit zeroing"
+	}
+
+	// Ignore no-op conversions when making decision.
+	// Ensures that xp = unsafe.Pointer(&x) is treated
+	// the same as xp = &x.
+	for r.Op == ir.OCONVNOP {
+		r = r.Left
+	}
+
+	// No write barrier for zeroing or initialization to constant.
+	if iszero(r) || r.Op == ir.OLITERAL {
+		return "zero or constant value"
+	}
+
+	// No write barrier for storing static (read-only) data.
+	if r.Op == ir.ONAME && strings.HasPrefix(r.Sym.Name, "statictmp_") {
+		return "static data"
+	}
+
+	// No write barrier for storing address of stack values,
+	// which are guaranteed only to be written to the stack.
+	if r.Op == ir.OADDR && isstack(r.Left) {
+		return "address of stack value"
+	}
+
+	// No write barrier for storing address of global, which
+	// is live no matter what.
+	if r.Op == ir.OADDR && isglobal(r.Left) {
+		return "address of global"
+	}
+
+	// No write barrier for storing global function, which is live
+	// no matter what.
+	if r.Op == ir.ONAME && r.Class == ir.PFUNC {
+		return "global function"
+	}
+
+	// Otherwise, be conservative and use write barrier.
+	return ""
+}
+
+// Do we need a write barrier for the assignment l = r?
+func needwritebarrier(l *ir.Node, r *ir.Node) bool {
+	return wbreason(l, r) == ""
+}
+
+// writebarrier marks the assignments in fn that need write barriers.
+func writebarrier(fn *ir.Node) {
+	wbbroken = false
+	wbvisitlist(fn.Func.Enter, markwritebarrier)
+	wbvisitlist(fn.Nbody, markwritebarrier)
+	wbvisitlist(fn.Func.Exit, markwritebarrier)
+}
+
+// wbbroken records whether -d=wbbreak has broken a marking in the
+// current function.
+var wbbroken bool
+
+func markwritebarrier(n *ir.Node) {
+	if n.Op != ir.OAS || n.Left == nil {
+		return
+	}
+	why := wbreason(n.Left, n.Right)
+	if why == "" {
+		n.Op = ir.OASWB
+	}
+	if Debug_wb > 1 {
+		recordwritebarrier(n, why)
+	}
+}
+
+// recordwritebarrier records the reason why for the assignment n
+// in Curfn, for the back ends to check, and reports n if it stores
+// pointers to memory that may be in the heap.
+func recordwritebarrier(n *ir.Node, why string) {
+	if n.Op == ir.OASWB && Debug_wbbreak != 0 && !wbbroken {
+		// Break the marking on purpose.
+		wbbroken = true
+		n.Op = ir.OAS
+		if Debug_wbbreak > 1 {
+			why = "store to stack"
+		}
+	}
+	if Curfn.Func.WBReasons == nil {
+		Curfn.Func.WBReasons = make(map[*ir.Node]string)
+	}
+	Curfn.Func.WBReasons[n] = why
+
+	if isblank(n.Left) || !haspointers(n.Left.Type) || isstack(n.Left) {
+		return
+	}
+	if n.Op == ir.OASWB {
+		Warnl(n.Lineno, "write barrier for %v", n.Left)
+	} else if why != "" {
+		Warnl(n.Lineno, "no write barrier for %v: %s", n.Left, why)
+	}
+}
+
+// A wbstore is a store to memory generated by the SSA back end for
+// an assignment recorded by the writebarrier pass.
+type wbstore struct {
+	n     *ir.Node   // the assignment
+	t     *ir.Type   // the type stored
+	addr  *ssa.Value // the address stored to
+	val   *ssa.Value // the value stored, its address if deref, or nil for zeroing
+	deref bool
+	wb    bool // whether the store has a write barrier
+}
+
+// checkwbssa checks the SSA store st against the reason why recorded
+// for its assignment. A store without a write barrier must have a
+// reason, and the reason must hold for the address and value stored.
+// The forward references in the function must have been resolved.
+func checkwbssa(st wbstore, why string) {
+	n := st.n
+	if st.wb {
+		if why != "" && !wbappend(n) {
+			lineno = n.Lineno
+			Fatalf("writebarrier: store to %v has a write barrier it does not need: %s", n.Left, why)
+		}
+		return
+	}
+	if why == "" {
+		lineno = n.Lineno
+		Fatalf("writebarrier: store to %v has no write barrier", n.Left)
+	}
+
+	var ok bool
+	switch why {
+	case "write barriers disabled":
+		ok = use_writebarrier == 0
+	case "no pointers":
+		ok = !haspointers(st.t)
+	case "store to stack":
+		ok = wbaddr(st.addr, ssa.OpSP, nil)
+	case "implicit zeroing", "zero or constant value":
+		ok = st.val == nil || !st.deref && wbconst(st.val)
+	case "static data":
+		v := st.val
+		if !st.deref && v.Op == ssa.OpLoad {
+			v = v.Args[0]
+		}
+		ok = wbaddr(v, ssa.OpSB, func(sym *ir.Sym) bool { return strings.HasPrefix(sym.Name, "statictmp_") })
+	case "address of stack value":
+		ok = !st.deref && wbaddr(st.val, ssa.OpSP, nil)
+	case "address of global", "global function":
+		ok = !st.deref && wbaddr(st.val, ssa.OpSB, nil)
+	}
+	if !ok {
+		lineno = n.Lineno
+		Fatalf("writebarrier: store to %v has no write barrier, but not because of %s", n.Left, why)
+	}
+}
+
+// wbaddr reports whether the value v is always an address relative to
+// the SP or SB value, as op says. If sym is not nil, the address must
+// also be that of a global symbol for which sym returns true.
+func wbaddr(v *ssa.Value, op ssa.Op, sym func(*ir.Sym) bool) bool {
+	seen := make(map[*ssa.Value]bool)
+	var addr func(v *ssa.Value) bool
+	addr = func(v *ssa.Value) bool {
+		switch v.Op {
+		case ssa.OpSP, ssa.OpSB:
+			return v.Op == op && sym == nil
+		case ssa.OpAddr:
+			if sym != nil {
+				aux, ok := v.Aux.(*ssa.ExternSymbol)
+				if !ok || !sym(aux.Sym.(*ir.Sym)) {
+					return false
+				}
+				return v.Args[0].Op == op
+			}
+			return addr(v.Args[0])
+		case ssa.OpOffPtr, ssa.OpAddPtr, ssa.OpPtrIndex, ssa.OpCopy, ssa.OpConvert:
+			return addr(v.Args[0])
+		case ssa.OpPhi:
+			if seen[v] {
+				return true
+			}
+			seen[v] = true
+			for _, a := range v.Args {
+				if !addr(a) {
+					return false
+				}
+			}
+			return true
+		}
+		return false
+	}
+	return addr(v)
+}
+
+// wbconst reports whether the value v is a constant.
+func wbconst(v *ssa.Value) bool {
+	switch v.Op {
+	case ssa.OpConstBool, ssa.OpConstString, ssa.OpConstNil, ssa.OpConst8, ssa.OpConst16,
+		ssa.OpConst32, ssa.OpConst64, ssa.OpConst32F, ssa.OpConst64F, ssa.OpConstInterface,
+		ssa.OpConstSlice:
+		return true
+	case ssa.OpStructMake0, ssa.OpStructMake1, ssa.OpStructMake2, ssa.OpStructMake3, ssa.OpStructMake4,
+		ssa.OpStringMake, ssa.OpSliceMake, ssa.OpIMake, ssa.OpComplexMake, ssa.OpCopy:
+		for _, a := range v.Args {
+			if !wbconst(a) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// wbappend reports whether n assigns the result of append, which the
+// back ends store with a write barrier even if it needs none, because
+// the growslice branch needs one.
+func wbappend(n *ir.Node) bool {
+	return n.Right != nil && n.Right.Op == ir.OAPPEND
+}
+
+// checkwbgen checks the assignment n, just generated by the legacy back
+// end, against the reason recorded for it: unless the back end skipped
+// storing the pointers, n must have a write barrier if and only if there
+// is no reason. The legacy back end has no values to check the reasons
+// themselves against.
+func checkwbgen(n *ir.Node, wb, skipped bool) {
+	why, ok := Curfn.Func.WBReasons[n]
+	if !ok || skipped {
+		return
+	}
+	if wb && why != "" && !wbappend(n) {
+		lineno = n.Lineno
+		Fatalf("writebarrier: store to %v has a write barrier it does not need: %s", n.Left, why)
+	}
+	if !wb && why == "" {
+		lineno = n.Lineno
+		Fatalf("writebarrier: store to %v has no write barrier", n.Left)
+	}
+}
+
+// wbvisit calls f for n and every node reachable from it,
+// not including the bodies of closures.
+func wbvisit(n *ir.Node, f func(*ir.Node)) {
+	if n == nil || n.Op == ir.OCLOSURE || n.Op == ir.ODCLFUNC {
+		return
+	}
+	f(n)
+	wbvisitlist(n.Ninit, f)
+	wbvisit(n.Left, f)
+	wbvisit(n.Right, f)
+	wbvisitlist(n.List, f)
+	wbvisitlist(n.Nbody, f)
+	wbvisitlist(n.Rlist, f)
+}
+
+func wbvisitlist(l ir.Nodes, f func(*ir.Node)) {
+	for _, n := range l.Slice() {
+		wbvisit(n, f)
+	}
+}
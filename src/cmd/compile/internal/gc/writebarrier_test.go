@@ -0,0 +1,64 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gc
+
+import (
+	"internal/testenv"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+// Make sure the stores generated by the back ends agree with the
+// write barrier decisions of the writebarrier pass across a few
+// packages with many pointer stores. A disagreement is a compiler
+// error.
+func TestWriteBarrierCheck(t *testing.T) {
+	testenv.MustHaveGoBuild(t)
+	if testing.Short() {
+		t.Skip("skipping in short mode")
+	}
+
+	// -a, because the packages are up to date without the flag.
+	cmd := exec.Command("go", "build", "-a", "-gcflags", "-d=wb=2", "reflect", "fmt", "go/types")
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		// The output reports every store; show only the end.
+		if len(out) > 2000 {
+			out = out[len(out)-2000:]
+		}
+		t.Errorf("compiling with -d=wb=2: %v\n...%s", err, out)
+	}
+}
+
+// Make sure the back ends catch write barrier markings that were
+// broken on purpose with -d=wbbreak.
+func TestWriteBarrierBreak(t *testing.T) {
+	d := newTestDir(t, "TestWriteBarrierBreak")
+	defer d.remove()
+
+	file := d.write("p.go", "package p\nfunc f(x **int, y *int) {\n\t*x = y\n}\n")
+	for _, test := range []struct {
+		arch, flags, want string
+	}{
+		{"amd64", "-d=wb=2", ""},
+		{"amd64", "-d=wb=2,wbbreak=1", "store to *x has no write barrier\n"},
+		{"amd64", "-d=wb=2,wbbreak=2", "store to *x has no write barrier, but not because of store to stack\n"},
+		// The legacy back end can only check that the barriers
+		// it generates match the marking.
+		{"386", "-d=wb=2", ""},
+		{"386", "-d=wb=2,wbbreak=1", "store to *x has no write barrier\n"},
+	} {
+		d.env = []string{"GOARCH=" + test.arch}
+		out, err := d.try("go", "tool", "compile", "-o", d.path("p.o"), test.flags, file)
+		if test.want == "" {
+			if err != nil {
+				t.Errorf("%s %s: %v\n%s", test.arch, test.flags, err, out)
+			}
+		} else if err == nil || !strings.Contains(out, test.want) {
+			t.Errorf("%s %s: got %v, want failure with %q:\n%s", test.arch, test.flags, err, test.want, out)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package ir defines the compiler's intermediate representation:
+// the syntax tree, types, symbols and constant values that the
+// passes in package gc build and rewrite.
+//
+// The IR relies on a few services it does not implement itself,
+// such as error reporting and formatting. Those are supplied by a
+// Frontend, which must be installed in Fe before the IR is used.
+package ir
+
+// A Frontend provides the services of the compiler that the IR uses.
+type Frontend interface {
+	// Fatalf reports an internal compiler error and exits.
+	Fatalf(format string, args ...interface{})
+
+	// Yyerror reports an error in the program being compiled.
+	Yyerror(format string, args ...interface{})
+
+	// Nerrors returns the number of errors reported so far.
+	Nerrors() int
+
+	// DumpFatal dumps n under the heading s and then reports the
+	// internal compiler error s, with a stack trace.
+	DumpFatal(s string, n *Node)
+
+	// Lineno returns the line number being processed.
+	Lineno() int32
+
+	// Line returns a string describing the given line number.
+	Line(int32) string
+
+	// Nconv, Hconv, Sconv and Tconv format nodes, node lists,
+	// symbols and types. See package gc for the flags they accept.
+	Nconv(n *Node, flag FmtFlag) string
+	Hconv(l Nodes, flag FmtFlag) string
+	Sconv(s *Sym, flag FmtFlag) string
+	Tconv(t *Type, flag FmtFlag) string
+
+	// Eqtype reports whether t1 and t2 are identical.
+	Eqtype(t1, t2 *Type) bool
+
+	// The SSA back end may call the following methods from several
+	// goroutines at once.
+
+	// Dowidth computes the width and alignment of t.
+	Dowidth(t *Type)
+
+	// Ptrto returns the type *t.
+	Ptrto(t *Type) *Type
+}
+
+// Fe is the Frontend in use.
+var Fe Frontend
+
+// lineno returns the line number for a new Type. Fe is not installed
+// yet when this package is initialized, so the types it creates then
+// have none.
+func lineno() int32 {
+	if Fe == nil {
+		return 0
+	}
+	return Fe.Lineno()
+}
@@ -0,0 +1,206 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ir
+
+// The intern table holds a single copy of each symbol name, package
+// path, and string literal, whether it came from the source or from
+// export data. The same names appear in the export data of many
+// packages (String, Error, Len), and without interning each import
+// would keep its own copy.
+//
+// The table is open-addressed and stores the hash of each string
+// with it, so that growing the table does not rehash the strings
+// and a lookup compares strings only when the hashes match.
+
+type internEntry struct {
+	s string
+	h uint32
+}
+
+// The table is a power of two long. An empty slot has s == "" and
+// h == 0; the empty string hashes to another value.
+var intern struct {
+	tab []internEntry
+	n   int
+}
+
+// Strhash returns the hash of b used by the intern table.
+func Strhash(b []byte) uint32 {
+	// FNV-1a
+	h := uint32(2166136261)
+	for _, c := range b {
+		h ^= uint32(c)
+		h *= 16777619
+	}
+	return h
+}
+
+// StrhashString is like Strhash, for a string.
+func StrhashString(s string) uint32 {
+	h := uint32(2166136261)
+	for i := 0; i < len(s); i++ {
+		h ^= uint32(s[i])
+		h *= 16777619
+	}
+	return h
+}
+
+// InternString returns the interned copy of b, adding one if needed.
+func InternString(b []byte) string {
+	return InternStringHash(b, Strhash(b))
+}
+
+// InternStringHash is like InternString, for b with hash h.
+func InternStringHash(b []byte, h uint32) string {
+	i := internFindBytes(h, b)
+	if e := &intern.tab[i]; e.s != "" || e.h != 0 {
+		return e.s
+	}
+	s := string(b)
+	internAdd(i, s, h)
+	return s
+}
+
+// InternStr is like InternString, for a string that the caller
+// may have allocated.
+func InternStr(s string) string {
+	return internStrHash(s, StrhashString(s))
+}
+
+// internStrHash is like InternStr, for s with hash h.
+func internStrHash(s string, h uint32) string {
+	i := internFind(h, s)
+	if e := &intern.tab[i]; e.s != "" || e.h != 0 {
+		return e.s
+	}
+	internAdd(i, s, h)
+	return s
+}
+
+// internFind returns the slot for the string s with hash h: either
+// the slot that holds it or the empty slot where it belongs.
+func internFind(h uint32, s string) int {
+	if intern.tab == nil {
+		intern.tab = make([]internEntry, 1024)
+	}
+	mask := len(intern.tab) - 1
+	for i := int(h) & mask; ; i = (i + 1) & mask {
+		e := &intern.tab[i]
+		if e.h == h && e.s == s || e.s == "" && e.h == 0 {
+			return i
+		}
+	}
+}
+
+// internFindBytes is like internFind, for a byte slice.
+func internFindBytes(h uint32, b []byte) int {
+	if intern.tab == nil {
+		intern.tab = make([]internEntry, 1024)
+	}
+	mask := len(intern.tab) - 1
+	for i := int(h) & mask; ; i = (i + 1) & mask {
+		e := &intern.tab[i]
+		if e.h == h && e.s == string(b) || e.s == "" && e.h == 0 { // string(b) here doesn't allocate
+			return i
+		}
+	}
+}
+
+// internAdd adds s, with hash h, in the empty slot i.
+func internAdd(i int, s string, h uint32) {
+	intern.tab[i] = internEntry{s, h}
+	intern.n++
+	if 4*intern.n < 3*len(intern.tab) {
+		return
+	}
+
+	// Grow the table, reusing the stored hashes.
+	tab := make([]internEntry, 2*len(intern.tab))
+	mask := len(tab) - 1
+	for _, e := range intern.tab {
+		if e.s == "" && e.h == 0 {
+			continue
+		}
+		i := int(e.h) & mask
+		for tab[i].s != "" || tab[i].h != 0 {
+			i = (i + 1) & mask
+		}
+		tab[i] = e
+	}
+	intern.tab = tab
+}
+
+// A symTable holds the symbols of a package, by name. Like the intern
+// table, it is open-addressed and a power of two long, and it uses the
+// hashes stored in the symbols: looking up a name whose hash is known,
+// such as one read from export data or just interned, hashes nothing.
+type symTable struct {
+	tab []*Sym // nil slots are empty
+	n   int
+}
+
+// find returns the slot for the name with hash h: either the slot
+// that holds its symbol or the empty slot where it belongs.
+func (t *symTable) find(h uint32, name string) int {
+	if t.tab == nil {
+		t.tab = make([]*Sym, 16) // most packages have few symbols
+	}
+	mask := len(t.tab) - 1
+	for i := int(h) & mask; ; i = (i + 1) & mask {
+		s := t.tab[i]
+		if s == nil || s.Hash == h && s.Name == name {
+			return i
+		}
+	}
+}
+
+// findBytes is like find, for a byte slice.
+func (t *symTable) findBytes(h uint32, name []byte) int {
+	if t.tab == nil {
+		t.tab = make([]*Sym, 16)
+	}
+	mask := len(t.tab) - 1
+	for i := int(h) & mask; ; i = (i + 1) & mask {
+		s := t.tab[i]
+		if s == nil || s.Hash == h && s.Name == string(name) { // string(name) here doesn't allocate
+			return i
+		}
+	}
+}
+
+// add adds s in the empty slot i.
+func (t *symTable) add(i int, s *Sym) {
+	t.tab[i] = s
+	t.n++
+	if 4*t.n < 3*len(t.tab) {
+		return
+	}
+
+	// Grow the table, reusing the stored hashes.
+	tab := make([]*Sym, 2*len(t.tab))
+	mask := len(tab) - 1
+	for _, s := range t.tab {
+		if s == nil {
+			continue
+		}
+		i := int(s.Hash) & mask
+		for tab[i] != nil {
+			i = (i + 1) & mask
+		}
+		tab[i] = s
+	}
+	t.tab = tab
+}
+
+// list returns the symbols in t, in the order of their slots.
+func (t *symTable) list() []*Sym {
+	l := make([]*Sym, 0, t.n)
+	for _, s := range t.tab {
+		if s != nil {
+			l = append(l, s)
+		}
+	}
+	return l
+}
@@ -0,0 +1,75 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ir
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"unsafe"
+)
+
+// sameString reports whether a and b share their bytes.
+func sameString(a, b string) bool {
+	pa := (*reflect.StringHeader)(unsafe.Pointer(&a))
+	pb := (*reflect.StringHeader)(unsafe.Pointer(&b))
+	return pa.Data == pb.Data && pa.Len == pb.Len
+}
+
+func TestIntern(t *testing.T) {
+	// Enough strings to grow the table a few times.
+	var want []string
+	for i := 0; i < 5000; i++ {
+		want = append(want, InternString([]byte(fmt.Sprintf("intern%d", i))))
+	}
+	for i, s := range want {
+		name := fmt.Sprintf("intern%d", i)
+		if got := InternString([]byte(name)); !sameString(got, s) {
+			t.Fatalf("InternString(%q) returned a new copy", name)
+		}
+		if got := InternStr(name); !sameString(got, s) {
+			t.Fatalf("InternStr(%q) returned a new copy", name)
+		}
+	}
+	if s := InternString(nil); s != "" {
+		t.Errorf("InternString(nil) = %q, want \"\"", s)
+	}
+}
+
+func TestSymTable(t *testing.T) {
+	pkg := new(Pkg)
+	if s := pkg.Find("x0"); s != nil {
+		t.Fatalf("Find(%q) in an empty package = %v", "x0", s)
+	}
+
+	// Enough symbols to grow the table a few times.
+	var want []*Sym
+	for i := 0; i < 1000; i++ {
+		name := fmt.Sprintf("x%d", i)
+		s := pkg.Lookup(name)
+		if s.Name != name || s.Pkg != pkg || s.Hash != StrhashString(name) {
+			t.Fatalf("Lookup(%q) = %v in %p with hash %#x", name, s, s.Pkg, s.Hash)
+		}
+		want = append(want, s)
+	}
+	for i, s := range want {
+		name := fmt.Sprintf("x%d", i)
+		if got := pkg.Lookup(name); got != s {
+			t.Fatalf("Lookup(%q) returned a new symbol", name)
+		}
+		if got := pkg.LookupBytes([]byte(name)); got != s {
+			t.Fatalf("LookupBytes(%q) returned a new symbol", name)
+		}
+		if got := pkg.LookupHash(name, StrhashString(name)); got != s {
+			t.Fatalf("LookupHash(%q) returned a new symbol", name)
+		}
+		if got := pkg.Find(name); got != s {
+			t.Fatalf("Find(%q) = %v, want %v", name, got, s)
+		}
+	}
+	if n := len(pkg.Syms()); n != len(want) {
+		t.Errorf("len(Syms()) = %d, want %d", n, len(want))
+	}
+}
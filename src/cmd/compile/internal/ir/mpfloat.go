@@ -2,7 +2,7 @@
 // Use of this source code is governed by a BSD-style
 // license that can be found in the LICENSE file.
 
-package gc
+package ir
 
 import (
 	"cmd/compile/internal/big"
@@ -13,9 +13,12 @@ import (
 // implements float arithmetic
 
 const (
-	// Maximum size in bits for Mpints before signalling
-	// overflow and also mantissa precision for Mpflts.
+	// Mantissa precision for Mpflts.
 	Mpprec = 512
+	// Maximum size in bits for Mpints before signalling overflow.
+	// The spec requires at least 256; generated code, such as
+	// bit sets and tables packed into constants, needs more.
+	Mpintprec = 2048
 	// Turn on for constant arithmetic debugging output.
 	Mpdebug = false
 )
@@ -31,7 +34,7 @@ type Mpcplx struct {
 	Imag Mpflt
 }
 
-func newMpflt() *Mpflt {
+func NewMpflt() *Mpflt {
 	var a Mpflt
 	a.Val.SetPrec(Mpprec)
 	return &a
@@ -127,8 +130,8 @@ func (a *Mpflt) Float64() float64 {
 	x, _ := a.Val.Float64()
 
 	// check for overflow
-	if math.IsInf(x, 0) && nsavederrors+nerrors == 0 {
-		Yyerror("mpgetflt ovf")
+	if math.IsInf(x, 0) && Fe.Nerrors() == 0 {
+		Fe.Yyerror("mpgetflt ovf")
 	}
 
 	return x + 0 // avoid -0 (should not be needed, but be conservative)
@@ -139,8 +142,8 @@ func (a *Mpflt) Float32() float64 {
 	x := float64(x32)
 
 	// check for overflow
-	if math.IsInf(x, 0) && nsavederrors+nerrors == 0 {
-		Yyerror("mpgetflt32 ovf")
+	if math.IsInf(x, 0) && Fe.Nerrors() == 0 {
+		Fe.Yyerror("mpgetflt32 ovf")
 	}
 
 	return x + 0 // avoid -0 (should not be needed, but be conservative)
@@ -187,13 +190,13 @@ func (a *Mpflt) SetString(as string) {
 		// - constant exponent out of range
 		// - decimal point and binary point in constant
 		// TODO(gri) use different conversion function or check separately
-		Yyerror("malformed constant: %s", as)
+		Fe.Yyerror("malformed constant: %s", as)
 		a.Val.SetFloat64(0)
 		return
 	}
 
 	if f.IsInf() {
-		Yyerror("constant too large: %s", as)
+		Fe.Yyerror("constant too large: %s", as)
 		a.Val.SetFloat64(0)
 		return
 	}
@@ -2,7 +2,7 @@
 // Use of this source code is governed by a BSD-style
 // license that can be found in the LICENSE file.
 
-package gc
+package ir
 
 import (
 	"cmd/compile/internal/big"
@@ -16,6 +16,10 @@ type Mpint struct {
 	Val  big.Int
 	Ovf  bool // set if Val overflowed compiler limit (sticky)
 	Rune bool // set if syntax indicates default type rune
+
+	// OvfBits is the bit length of the result that set Ovf,
+	// or 0 if it isn't known. The caller reports the overflow.
+	OvfBits int
 }
 
 func (a *Mpint) SetOverflow() {
@@ -25,9 +29,9 @@ func (a *Mpint) SetOverflow() {
 
 func (a *Mpint) checkOverflow(extra int) bool {
 	// We don't need to be precise here, any reasonable upper limit would do.
-	// For now, use existing limit so we pass all the tests unchanged.
-	if a.Val.BitLen()+extra > Mpprec {
+	if bits := a.Val.BitLen() + extra; bits > Mpintprec {
 		a.SetOverflow()
+		a.OvfBits = bits
 	}
 	return a.Ovf
 }
@@ -38,8 +42,7 @@ func (a *Mpint) Set(b *Mpint) {
 
 func (a *Mpint) SetFloat(b *Mpflt) int {
 	// avoid converting huge floating-point numbers to integers
-	// (2*Mpprec is large enough to permit all tests to pass)
-	if b.Val.MantExp(nil) > 2*Mpprec {
+	if b.Val.MantExp(nil) > Mpintprec {
 		return -1
 	}
 
@@ -70,8 +73,8 @@ func (a *Mpint) SetFloat(b *Mpflt) int {
 
 func (a *Mpint) Add(b *Mpint) {
 	if a.Ovf || b.Ovf {
-		if nsavederrors+nerrors == 0 {
-			Yyerror("ovf in mpaddfixfix")
+		if Fe.Nerrors() == 0 {
+			Fe.Yyerror("ovf in mpaddfixfix")
 		}
 		a.SetOverflow()
 		return
@@ -79,15 +82,13 @@ func (a *Mpint) Add(b *Mpint) {
 
 	a.Val.Add(&a.Val, &b.Val)
 
-	if a.checkOverflow(0) {
-		Yyerror("constant addition overflow")
-	}
+	a.checkOverflow(0)
 }
 
 func (a *Mpint) Sub(b *Mpint) {
 	if a.Ovf || b.Ovf {
-		if nsavederrors+nerrors == 0 {
-			Yyerror("ovf in mpsubfixfix")
+		if Fe.Nerrors() == 0 {
+			Fe.Yyerror("ovf in mpsubfixfix")
 		}
 		a.SetOverflow()
 		return
@@ -95,15 +96,13 @@ func (a *Mpint) Sub(b *Mpint) {
 
 	a.Val.Sub(&a.Val, &b.Val)
 
-	if a.checkOverflow(0) {
-		Yyerror("constant subtraction overflow")
-	}
+	a.checkOverflow(0)
 }
 
 func (a *Mpint) Mul(b *Mpint) {
 	if a.Ovf || b.Ovf {
-		if nsavederrors+nerrors == 0 {
-			Yyerror("ovf in mpmulfixfix")
+		if Fe.Nerrors() == 0 {
+			Fe.Yyerror("ovf in mpmulfixfix")
 		}
 		a.SetOverflow()
 		return
@@ -111,15 +110,13 @@ func (a *Mpint) Mul(b *Mpint) {
 
 	a.Val.Mul(&a.Val, &b.Val)
 
-	if a.checkOverflow(0) {
-		Yyerror("constant multiplication overflow")
-	}
+	a.checkOverflow(0)
 }
 
 func (a *Mpint) Quo(b *Mpint) {
 	if a.Ovf || b.Ovf {
-		if nsavederrors+nerrors == 0 {
-			Yyerror("ovf in mpdivfixfix")
+		if Fe.Nerrors() == 0 {
+			Fe.Yyerror("ovf in mpdivfixfix")
 		}
 		a.SetOverflow()
 		return
@@ -129,14 +126,14 @@ func (a *Mpint) Quo(b *Mpint) {
 
 	if a.checkOverflow(0) {
 		// can only happen for div-0 which should be checked elsewhere
-		Yyerror("constant division overflow")
+		Fe.Yyerror("constant division overflow")
 	}
 }
 
 func (a *Mpint) Rem(b *Mpint) {
 	if a.Ovf || b.Ovf {
-		if nsavederrors+nerrors == 0 {
-			Yyerror("ovf in mpmodfixfix")
+		if Fe.Nerrors() == 0 {
+			Fe.Yyerror("ovf in mpmodfixfix")
 		}
 		a.SetOverflow()
 		return
@@ -146,14 +143,14 @@ func (a *Mpint) Rem(b *Mpint) {
 
 	if a.checkOverflow(0) {
 		// should never happen
-		Yyerror("constant modulo overflow")
+		Fe.Yyerror("constant modulo overflow")
 	}
 }
 
 func (a *Mpint) Or(b *Mpint) {
 	if a.Ovf || b.Ovf {
-		if nsavederrors+nerrors == 0 {
-			Yyerror("ovf in mporfixfix")
+		if Fe.Nerrors() == 0 {
+			Fe.Yyerror("ovf in mporfixfix")
 		}
 		a.SetOverflow()
 		return
@@ -164,8 +161,8 @@ func (a *Mpint) Or(b *Mpint) {
 
 func (a *Mpint) And(b *Mpint) {
 	if a.Ovf || b.Ovf {
-		if nsavederrors+nerrors == 0 {
-			Yyerror("ovf in mpandfixfix")
+		if Fe.Nerrors() == 0 {
+			Fe.Yyerror("ovf in mpandfixfix")
 		}
 		a.SetOverflow()
 		return
@@ -176,8 +173,8 @@ func (a *Mpint) And(b *Mpint) {
 
 func (a *Mpint) AndNot(b *Mpint) {
 	if a.Ovf || b.Ovf {
-		if nsavederrors+nerrors == 0 {
-			Yyerror("ovf in mpandnotfixfix")
+		if Fe.Nerrors() == 0 {
+			Fe.Yyerror("ovf in mpandnotfixfix")
 		}
 		a.SetOverflow()
 		return
@@ -188,8 +185,8 @@ func (a *Mpint) AndNot(b *Mpint) {
 
 func (a *Mpint) Xor(b *Mpint) {
 	if a.Ovf || b.Ovf {
-		if nsavederrors+nerrors == 0 {
-			Yyerror("ovf in mpxorfixfix")
+		if Fe.Nerrors() == 0 {
+			Fe.Yyerror("ovf in mpxorfixfix")
 		}
 		a.SetOverflow()
 		return
@@ -200,26 +197,25 @@ func (a *Mpint) Xor(b *Mpint) {
 
 func (a *Mpint) Lsh(b *Mpint) {
 	if a.Ovf || b.Ovf {
-		if nsavederrors+nerrors == 0 {
-			Yyerror("ovf in mplshfixfix")
+		if Fe.Nerrors() == 0 {
+			Fe.Yyerror("ovf in mplshfixfix")
 		}
 		a.SetOverflow()
 		return
 	}
 
 	s := b.Int64()
-	if s < 0 || s >= Mpprec {
+	if s < 0 || s >= Mpintprec {
 		msg := "shift count too large"
 		if s < 0 {
 			msg = "invalid negative shift count"
 		}
-		Yyerror("%s: %d", msg, s)
+		Fe.Yyerror("%s: %d", msg, s)
 		a.SetInt64(0)
 		return
 	}
 
 	if a.checkOverflow(int(s)) {
-		Yyerror("constant shift overflow")
 		return
 	}
 	a.Val.Lsh(&a.Val, uint(s))
@@ -227,8 +223,8 @@ func (a *Mpint) Lsh(b *Mpint) {
 
 func (a *Mpint) Rsh(b *Mpint) {
 	if a.Ovf || b.Ovf {
-		if nsavederrors+nerrors == 0 {
-			Yyerror("ovf in mprshfixfix")
+		if Fe.Nerrors() == 0 {
+			Fe.Yyerror("ovf in mprshfixfix")
 		}
 		a.SetOverflow()
 		return
@@ -236,7 +232,7 @@ func (a *Mpint) Rsh(b *Mpint) {
 
 	s := b.Int64()
 	if s < 0 {
-		Yyerror("invalid negative shift count: %d", s)
+		Fe.Yyerror("invalid negative shift count: %d", s)
 		if a.Val.Sign() < 0 {
 			a.SetInt64(-1)
 		} else {
@@ -265,8 +261,8 @@ func (a *Mpint) Neg() {
 
 func (a *Mpint) Int64() int64 {
 	if a.Ovf {
-		if nsavederrors+nerrors == 0 {
-			Yyerror("constant overflow")
+		if Fe.Nerrors() == 0 {
+			Fe.Yyerror("constant overflow")
 		}
 		return 0
 	}
@@ -288,12 +284,12 @@ func (a *Mpint) SetString(as string) {
 		// - malformed octal constant
 		// - malformed decimal constant
 		// TODO(gri) use different conversion function
-		Yyerror("malformed integer constant: %s", as)
+		Fe.Yyerror("malformed integer constant: %s", as)
 		a.Val.SetUint64(0)
 		return
 	}
 	if a.checkOverflow(0) {
-		Yyerror("constant too large: %s", as)
+		Fe.Yyerror("constant too large: %s (%d bits, limit is %d)", as, a.OvfBits, Mpintprec)
 	}
 }
 
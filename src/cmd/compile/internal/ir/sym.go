@@ -0,0 +1,113 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ir
+
+import "cmd/internal/obj"
+
+type Pkg struct {
+	Name     string // package name
+	Path     string // string literal used in import statement
+	Pathsym  *Sym
+	Prefix   string // escaped path for use in symbol table
+	Imported bool   // export data of this package was parsed
+	Exported bool   // import line written in export data
+	Direct   bool   // imported directly
+	Safe     bool   // whether the package is marked as safe
+	syms     symTable
+}
+
+type Sym struct {
+	Flags     SymFlags
+	Hash      uint32 // hash of Name; see Strhash
+	Link      *Sym
+	Importdef *Pkg   // where imported definition was found
+	Linkname  string // link name
+
+	// saved and restored by dcopy
+	Pkg        *Pkg
+	Name       string // variable name
+	Def        *Node  // definition: ONAME OTYPE OPACK or OLITERAL
+	Block      int32  // blocknumber to catch redeclaration
+	Lastlineno int32  // last declaration for diagnostic
+
+	Label   *Label // corresponding label (ephemeral)
+	Origpkg *Pkg   // original package for . import
+	Lsym    *obj.LSym
+	Fsym    *Sym // funcsym
+}
+
+type SymFlags uint8
+
+const (
+	SymExport SymFlags = 1 << iota // to be exported
+	SymPackage
+	SymExported // already written out by export
+	SymUniq
+	SymSiggen
+	SymAsm
+	SymAlgGen
+)
+
+var InitSyms []*Sym
+
+var Nopkg = new(Pkg)
+
+func (pkg *Pkg) Lookup(name string) *Sym {
+	return pkg.LookupHash(name, StrhashString(name))
+}
+
+// LookupHash is like Lookup, for a name whose hash (see Strhash) is h.
+func (pkg *Pkg) LookupHash(name string, h uint32) *Sym {
+	if pkg == nil {
+		pkg = Nopkg
+	}
+	i := pkg.syms.find(h, name)
+	if s := pkg.syms.tab[i]; s != nil {
+		return s
+	}
+	return pkg.newSym(i, internStrHash(name, h), h)
+}
+
+func (pkg *Pkg) LookupBytes(name []byte) *Sym {
+	if pkg == nil {
+		pkg = Nopkg
+	}
+	h := Strhash(name)
+	i := pkg.syms.findBytes(h, name)
+	if s := pkg.syms.tab[i]; s != nil {
+		return s
+	}
+	return pkg.newSym(i, InternStringHash(name, h), h)
+}
+
+// Find returns the symbol with the given name in pkg, or nil if
+// there is none. Unlike Lookup, it doesn't add one.
+func (pkg *Pkg) Find(name string) *Sym {
+	return pkg.syms.tab[pkg.syms.find(StrhashString(name), name)]
+}
+
+// Syms returns the symbols in pkg, in no particular order.
+func (pkg *Pkg) Syms() []*Sym {
+	return pkg.syms.list()
+}
+
+// newSym adds a symbol with the interned name, whose hash is h, to pkg
+// in the empty slot i of its table.
+func (pkg *Pkg) newSym(i int, name string, h uint32) *Sym {
+	s := &Sym{
+		Name: name,
+		Hash: h,
+		Pkg:  pkg,
+	}
+	if name == "init" {
+		InitSyms = append(InitSyms, s)
+	}
+	pkg.syms.add(i, s)
+	return s
+}
+
+func (s *Sym) String() string {
+	return Fe.Sconv(s, 0)
+}
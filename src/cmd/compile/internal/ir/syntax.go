@@ -4,7 +4,12 @@
 
 // “Abstract” syntax representation.
 
-package gc
+package ir
+
+import (
+	"cmd/compile/internal/ssa"
+	"cmd/internal/obj"
+)
 
 // A Node is a single node in the syntax tree.
 // Actually the syntax tree is a syntax DAG, because there is only one
@@ -83,12 +88,15 @@ func (n *Node) Val() Val {
 	return Val{n.E}
 }
 
+// HasVal reports whether the node's Val has been set.
+func (n *Node) HasVal() bool {
+	return n.hasVal == +1
+}
+
 // SetVal sets the Val for the node, which must not have been used with SetOpt.
 func (n *Node) SetVal(v Val) {
 	if n.hasVal == -1 {
-		Debug['h'] = 1
-		Dump("have Opt", n)
-		Fatalf("have Opt")
+		Fe.DumpFatal("have Opt", n)
 	}
 	n.hasVal = +1
 	n.E = v.U
@@ -109,9 +117,7 @@ func (n *Node) SetOpt(x interface{}) {
 		return
 	}
 	if n.hasVal == +1 {
-		Debug['h'] = 1
-		Dump("have Val", n)
-		Fatalf("have Val")
+		Fe.DumpFatal("have Val", n)
 	}
 	n.hasVal = -1
 	n.E = x
@@ -128,9 +134,10 @@ type Name struct {
 	Param     *Param
 	Decldepth int32 // declaration loop depth, increased for every loop or label
 	Vargen    int32 // unique name for ONAME within a function.  Function outputs are numbered starting at one.
-	Iota      int32 // value if this name is iota
+	Iota      int32 // iota of the const spec declaring this name; for iota, < 0 if not in a const declaration
 	Funcdepth int32
-	Method    bool // OCALLMETH name
+	Block     int32 // block number of the declaration; see blocks
+	Method    bool  // OCALLMETH name
 	Readonly  bool
 	Captured  bool // is the variable captured by a closure
 	Byval     bool // is the variable captured by value or by reference
@@ -151,6 +158,11 @@ type Param struct {
 	// ONAME closure param with PPARAMREF
 	Outer   *Node // outer PPARAMREF in nested closure
 	Closure *Node // ONAME/PHEAP <-> ONAME/PPARAMREF
+
+	// ONAME captured by a closure; see typecheckclosure and capturewhy
+	Assignline int32 // line of the last assignment
+	Assigngen  int32 // loopgen at the last assignment
+	Addrline   int32 // line where the address is taken
 }
 
 // Func holds Node fields used only with function-like nodes.
@@ -167,22 +179,37 @@ type Func struct {
 	Outer      *Node // outer func for closure
 	Ntype      *Node // signature
 	Top        int   // top context (Ecall, Eproc, etc)
+	Iota       int32 // value of iota where a closure appears, or -1
 	Closure    *Node // OCLOSURE <-> ODCLFUNC
 	FCurfn     *Node
 	Nname      *Node
 
-	Inl     Nodes // copy of the body for use in inlining
+	Inl     Nodes   // copy of the body for use in inlining
+	Inlsrc  *Inlsrc // imported body not yet decoded into Inl (see gc.loadinl)
 	InlCost int32
 	Depth   int32
 
 	Endlineno int32
 	WBLineno  int32 // line number of first write barrier
+	Block     int32 // block number of the parameters and top-level locals
+
+	// ReflectMethods holds the constant method names and indexes
+	// passed to reflect.Type.Method and MethodByName, as symbols
+	// in reflectmethodpkg.
+	ReflectMethods map[*Sym]struct{}
+
+	StmtLines map[int32]bool // lines that start statements, for DWARF; see stmtlines
+
+	// WBReasons holds, with -d=wb=2, the reason each assignment
+	// needs no write barrier, or "" if it needs one.
+	WBReasons map[*Node]string
 
 	Pragma        Pragma // go:xxx function annotations
 	Dupok         bool   // duplicate definitions ok
 	Wrapper       bool   // is method wrapper
 	Needctxt      bool   // function uses context register (has closure variables)
 	ReflectMethod bool   // function calls reflect.Type.Method or MethodByName
+	Released      bool   // body released after code generation; see funcdone
 }
 
 type Op uint8
@@ -465,3 +492,68 @@ func (n *Nodes) AppendNodes(n2 *Nodes) {
 	}
 	n2.slice = nil
 }
+
+// The Class of a variable/function describes the "storage class"
+// of a variable or function. During parsing, storage classes are
+// called declaration contexts.
+type Class uint8
+
+const (
+	Pxxx      Class = iota
+	PEXTERN         // global variable
+	PAUTO           // local variables
+	PPARAM          // input arguments
+	PPARAMOUT       // output results
+	PPARAMREF       // closure variable reference
+	PFUNC           // global function
+
+	PDISCARD // discard during parse of duplicate import
+
+	PHEAP = 1 << 7 // an extra bit to identify an escaped variable
+)
+
+type Label struct {
+	Sym *Sym
+	Def *Node
+	Use []*Node
+
+	// for use during gen
+	Gotopc   *obj.Prog // pointer to unresolved gotos
+	Labelpc  *obj.Prog // pointer to code
+	Breakpc  *obj.Prog // pointer to code
+	Continpc *obj.Prog // pointer to code
+
+	Used bool
+}
+
+type Pragma uint16
+
+const (
+	Nointerface       Pragma = 1 << iota
+	Noescape                 // func parameters don't escape
+	Norace                   // func must not have race detector annotations
+	Nomsan                   // func must not have memory sanitizer annotations
+	Noasan                   // func must not have address sanitizer annotations
+	Nosplit                  // func should not execute on separate stack
+	Noinline                 // func should not be inlined
+	Systemstack              // func must run on system stack
+	Nowritebarrier           // emit compiler error instead of write barrier
+	Nowritebarrierrec        // error on write barrier in this or recursive callees
+	CgoUnsafeArgs            // treat a pointer to one arg as a pointer to them all
+)
+
+// An Inlsrc locates an imported inlined function body, encoded at
+// offset Off of the data section of the export data.
+type Inlsrc struct {
+	Pkg  *Pkg   // package whose export data contained the body
+	Data []byte // the data section
+	Off  int
+}
+
+func (n *Node) Line() string {
+	return Fe.Line(n.Lineno)
+}
+
+func (n *Node) Typ() ssa.Type {
+	return n.Type
+}
@@ -7,7 +7,7 @@
 // to an import cycle with this package and ../ssa.
 // TODO: move Type to its own package, then we don't need to dance around import cycles.
 
-package gc
+package ir
 
 import (
 	"cmd/compile/internal/ssa"
@@ -80,23 +80,23 @@ var Types [NTYPE]*Type
 
 var (
 	// Predeclared alias types. Kept separate for better error messages.
-	bytetype *Type
-	runetype *Type
+	Bytetype *Type
+	Runetype *Type
 
 	// Predeclared error interface type.
-	errortype *Type
+	Errortype *Type
 
 	// Types to represent untyped string and boolean constants.
-	idealstring *Type
-	idealbool   *Type
+	Idealstring *Type
+	Idealbool   *Type
 
 	// Types to represent untyped numeric constants.
 	// Note: Currently these are only used within the binary export
 	// data format. The rest of the compiler only uses Types[TIDEAL].
-	idealint     = typ(TIDEAL)
-	idealrune    = typ(TIDEAL)
-	idealfloat   = typ(TIDEAL)
-	idealcomplex = typ(TIDEAL)
+	Idealint     = Typ(TIDEAL)
+	Idealrune    = Typ(TIDEAL)
+	Idealfloat   = Typ(TIDEAL)
+	Idealcomplex = Typ(TIDEAL)
 )
 
 // A Type represents a Go type.
@@ -113,6 +113,7 @@ type Type struct {
 	Align       uint8
 	Haspointers uint8 // 0 unknown, 1 no, 2 yes
 	Outnamed    bool  // on TFUNC
+	Shared      bool  // shared with identical types; see sharetype
 
 	Nod  *Node // canonical OTYPE node
 	Orig *Type // original type (type literal or predefined type)
@@ -211,18 +212,21 @@ func (f *Fields) Append(s ...*Field) {
 	*f.s = append(*f.s, s...)
 }
 
-// typ returns a new Type of the specified kind.
-func typ(et EType) *Type {
+// BADWIDTH is the Width of a type or field whose width is not yet known.
+const BADWIDTH = -1000000000
+
+// Typ returns a new Type of the specified kind.
+func Typ(et EType) *Type {
 	t := &Type{
 		Etype:  et,
 		Width:  BADWIDTH,
-		Lineno: lineno,
+		Lineno: lineno(),
 	}
 	t.Orig = t
 	return t
 }
 
-func newField() *Field {
+func NewField() *Field {
 	return &Field{
 		Width: BADWIDTH,
 	}
@@ -234,6 +238,7 @@ func (t *Type) Copy() *Type {
 		return nil
 	}
 	nt := *t
+	nt.Shared = false
 	// TODO(mdempsky): Find out why this is necessary and explain.
 	if t.Orig == t {
 		nt.Orig = &nt
@@ -279,7 +284,7 @@ func (i *Iter) Next() *Field {
 
 func (t *Type) wantEtype(et EType) {
 	if t.Etype != et {
-		Fatalf("want %v, but have %v", et, t)
+		Fe.Fatalf("want %v, but have %v", et, t)
 	}
 }
 
@@ -311,15 +316,15 @@ func (t *Type) Recv() *Field {
 	return s.Field(0)
 }
 
-// recvsParamsResults stores the accessor functions for a function Type's
+// RecvsParamsResults stores the accessor functions for a function Type's
 // receiver, parameters, and result parameters, in that order.
 // It can be used to iterate over all of a function's parameter lists.
-var recvsParamsResults = [3]func(*Type) *Type{
+var RecvsParamsResults = [3]func(*Type) *Type{
 	(*Type).Recvs, (*Type).Params, (*Type).Results,
 }
 
-// paramsResults is like recvsParamsResults, but omits receiver parameters.
-var paramsResults = [2]func(*Type) *Type{
+// ParamsResults is like RecvsParamsResults, but omits receiver parameters.
+var ParamsResults = [2]func(*Type) *Type{
 	(*Type).Params, (*Type).Results,
 }
 
@@ -341,7 +346,7 @@ func (t *Type) AllMethods() *Fields {
 
 func (t *Type) Fields() *Fields {
 	if t.Etype != TSTRUCT && t.Etype != TINTER {
-		Fatalf("Fields: type %v does not have fields", t)
+		Fe.Fatalf("Fields: type %v does not have fields", t)
 	}
 	return &t.fields
 }
@@ -363,12 +368,12 @@ func (t *Type) SetFields(fields []*Field) {
 }
 
 func (t *Type) Size() int64 {
-	dowidth(t)
+	Fe.Dowidth(t)
 	return t.Width
 }
 
 func (t *Type) Alignment() int64 {
-	dowidth(t)
+	Fe.Dowidth(t)
 	return int64(t.Align)
 }
 
@@ -378,7 +383,7 @@ func (t *Type) SimpleString() string {
 
 func (t *Type) Equal(u ssa.Type) bool {
 	x, ok := u.(*Type)
-	return ok && Eqtype(t, x)
+	return ok && Fe.Eqtype(t, x)
 }
 
 // Compare compares types for purposes of the SSA back
@@ -441,7 +446,7 @@ func (r *Sym) cmpsym(s *Sym) ssa.Cmp {
 // ssa.CMPeq, ssa.CMPgt as t<x, t==x, t>x, for an arbitrary
 // and optimizer-centric notion of comparison.
 func (t *Type) cmp(x *Type) ssa.Cmp {
-	// This follows the structure of Eqtype in subr.go
+	// This follows the structure of Eqtype in gc/subr.go
 	// with two exceptions.
 	// 1. Symbols are compared more carefully because a <,=,> result is desired.
 	// 2. Maps are treated specially to avoid endless recursion -- maps
@@ -465,12 +470,12 @@ func (t *Type) cmp(x *Type) ssa.Cmp {
 		// for error messages. Treat them as equal.
 		switch t.Etype {
 		case TUINT8:
-			if (t == Types[TUINT8] || t == bytetype) && (x == Types[TUINT8] || x == bytetype) {
+			if (t == Types[TUINT8] || t == Bytetype) && (x == Types[TUINT8] || x == Bytetype) {
 				return ssa.CMPeq
 			}
 
 		case TINT32:
-			if (t == Types[runetype.Etype] || t == runetype) && (x == Types[runetype.Etype] || x == runetype) {
+			if (t == Types[Runetype.Etype] || t == Runetype) && (x == Types[Runetype.Etype] || x == Runetype) {
 				return ssa.CMPeq
 			}
 		}
@@ -554,7 +559,7 @@ func (t *Type) cmp(x *Type) ssa.Cmp {
 		return ssa.CMPeq
 
 	case TFUNC:
-		for _, f := range recvsParamsResults {
+		for _, f := range RecvsParamsResults {
 			// Loop over fields in structs, ignoring argument names.
 			ta, ia := IterFields(f(t))
 			tb, ib := IterFields(f(x))
@@ -660,7 +665,7 @@ func (t *Type) ElemType() ssa.Type {
 	panic(fmt.Sprintf("ElemType on invalid type %v", t))
 }
 func (t *Type) PtrTo() ssa.Type {
-	return Ptrto(t)
+	return Fe.Ptrto(t)
 }
 
 func (t *Type) NumFields() int {
@@ -6,11 +6,12 @@ package mips64
 
 import (
 	"cmd/compile/internal/gc"
+	"cmd/compile/internal/ir"
 	"cmd/internal/obj"
 	"cmd/internal/obj/mips"
 )
 
-func blockcopy(n, res *gc.Node, osrc, odst, w int64) {
+func blockcopy(n, res *ir.Node, osrc, odst, w int64) {
 	// determine alignment.
 	// want to avoid unaligned access, so have to use
 	// smaller operations for less aligned types.
@@ -48,34 +49,34 @@ func blockcopy(n, res *gc.Node, osrc, odst, w int64) {
 		dir = -dir
 	}
 
-	var dst gc.Node
-	var src gc.Node
+	var dst ir.Node
+	var src ir.Node
 	if n.Ullman >= res.Ullman {
 		gc.Agenr(n, &dst, res) // temporarily use dst
-		gc.Regalloc(&src, gc.Types[gc.Tptr], nil)
+		gc.Regalloc(&src, ir.Types[gc.Tptr], nil)
 		gins(mips.AMOVV, &dst, &src)
-		if res.Op == gc.ONAME {
+		if res.Op == ir.ONAME {
 			gc.Gvardef(res)
 		}
 		gc.Agen(res, &dst)
 	} else {
-		if res.Op == gc.ONAME {
+		if res.Op == ir.ONAME {
 			gc.Gvardef(res)
 		}
 		gc.Agenr(res, &dst, res)
 		gc.Agenr(n, &src, nil)
 	}
 
-	var tmp gc.Node
-	gc.Regalloc(&tmp, gc.Types[gc.Tptr], nil)
+	var tmp ir.Node
+	gc.Regalloc(&tmp, ir.Types[gc.Tptr], nil)
 
 	// set up end marker
-	var nend gc.Node
+	var nend ir.Node
 
 	// move src and dest to the end of block if necessary
 	if dir < 0 {
 		if c >= 4 {
-			gc.Regalloc(&nend, gc.Types[gc.Tptr], nil)
+			gc.Regalloc(&nend, ir.Types[gc.Tptr], nil)
 			gins(mips.AMOVV, &src, &nend)
 		}
 
@@ -96,7 +97,7 @@ func blockcopy(n, res *gc.Node, osrc, odst, w int64) {
 		p.From.Offset = int64(-dir)
 
 		if c >= 4 {
-			gc.Regalloc(&nend, gc.Types[gc.Tptr], nil)
+			gc.Regalloc(&nend, ir.Types[gc.Tptr], nil)
 			p := gins(mips.AMOVV, &src, &nend)
 			p.From.Type = obj.TYPE_ADDR
 			p.From.Offset = w
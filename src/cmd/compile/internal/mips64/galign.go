@@ -10,64 +10,72 @@ import (
 	"cmd/internal/obj/mips"
 )
 
-func betypeinit() {
+func init() {
+	gc.RegisterBackend("mips64", backend{})
+	gc.RegisterBackend("mips64le", backend{})
+}
+
+// backend is the mips64 back end; see gc.Backend.
+type backend struct{}
+
+func (backend) TypeInit() {
 	gc.Widthptr = 8
 	gc.Widthint = 8
 	gc.Widthreg = 8
 }
 
-func Main() {
-	gc.Thearch.Thechar = '0'
-	gc.Thearch.Thestring = "mips64"
-	gc.Thearch.Thelinkarch = &mips.Linkmips64
+func (backend) Init(arch *gc.Arch) {
+	arch.Thechar = '0'
+	arch.Thestring = "mips64"
+	arch.Thelinkarch = &mips.Linkmips64
 	if obj.Getgoarch() == "mips64le" {
-		gc.Thearch.Thestring = "mips64le"
-		gc.Thearch.Thelinkarch = &mips.Linkmips64le
+		arch.Thestring = "mips64le"
+		arch.Thelinkarch = &mips.Linkmips64le
 	}
-	gc.Thearch.REGSP = mips.REGSP
-	gc.Thearch.REGCTXT = mips.REGCTXT
-	gc.Thearch.REGCALLX = mips.REG_R1
-	gc.Thearch.REGCALLX2 = mips.REG_R2
-	gc.Thearch.REGRETURN = mips.REGRET
-	gc.Thearch.REGMIN = mips.REG_R0
-	gc.Thearch.REGMAX = mips.REG_R31
-	gc.Thearch.FREGMIN = mips.REG_F0
-	gc.Thearch.FREGMAX = mips.REG_F31
-	gc.Thearch.MAXWIDTH = 1 << 50
-	gc.Thearch.ReservedRegs = resvd
+	arch.REGSP = mips.REGSP
+	arch.REGCTXT = mips.REGCTXT
+	arch.REGCALLX = mips.REG_R1
+	arch.REGCALLX2 = mips.REG_R2
+	arch.REGRETURN = mips.REGRET
+	arch.REGMIN = mips.REG_R0
+	arch.REGMAX = mips.REG_R31
+	arch.FREGMIN = mips.REG_F0
+	arch.FREGMAX = mips.REG_F31
+	arch.MAXWIDTH = 1 << 50
+	arch.ReservedRegs = resvd
 
-	gc.Thearch.Betypeinit = betypeinit
-	gc.Thearch.Cgen_hmul = cgen_hmul
-	gc.Thearch.Cgen_shift = cgen_shift
-	gc.Thearch.Clearfat = clearfat
-	gc.Thearch.Defframe = defframe
-	gc.Thearch.Dodiv = dodiv
-	gc.Thearch.Excise = excise
-	gc.Thearch.Expandchecks = expandchecks
-	gc.Thearch.Getg = getg
-	gc.Thearch.Gins = gins
-	gc.Thearch.Ginscmp = ginscmp
-	gc.Thearch.Ginscon = ginscon
-	gc.Thearch.Ginsnop = ginsnop
-	gc.Thearch.Gmove = gmove
-	gc.Thearch.Peep = peep
-	gc.Thearch.Proginfo = proginfo
-	gc.Thearch.Regtyp = regtyp
-	gc.Thearch.Sameaddr = sameaddr
-	gc.Thearch.Smallindir = smallindir
-	gc.Thearch.Stackaddr = stackaddr
-	gc.Thearch.Blockcopy = blockcopy
-	gc.Thearch.Sudoaddable = sudoaddable
-	gc.Thearch.Sudoclean = sudoclean
-	gc.Thearch.Excludedregs = excludedregs
-	gc.Thearch.RtoB = RtoB
-	gc.Thearch.FtoB = RtoB
-	gc.Thearch.BtoR = BtoR
-	gc.Thearch.BtoF = BtoF
-	gc.Thearch.Optoas = optoas
-	gc.Thearch.Doregbits = doregbits
-	gc.Thearch.Regnames = regnames
+	arch.Cgen_hmul = cgen_hmul
+	arch.Cgen_shift = cgen_shift
+	arch.Copyas = copyas
+	arch.Copyu = copyu
+	arch.Clearfat = clearfat
+	arch.Dodiv = dodiv
+	arch.Expandchecks = expandchecks
+	arch.Getg = getg
+	arch.Gins = gins
+	arch.Ginscmp = ginscmp
+	arch.Ginscon = ginscon
+	arch.Ginsnop = ginsnop
+	arch.Gmove = gmove
+	arch.Proginfo = proginfo
+	arch.Regtyp = regtyp
+	arch.Sameaddr = sameaddr
+	arch.Smallindir = smallindir
+	arch.Stackaddr = stackaddr
+	arch.Blockcopy = blockcopy
+	arch.Sudoaddable = sudoaddable
+	arch.Sudoclean = sudoclean
+	arch.Excludedregs = excludedregs
+	arch.RtoB = RtoB
+	arch.FtoB = RtoB
+	arch.BtoR = BtoR
+	arch.BtoF = BtoF
+	arch.Optoas = optoas
+	arch.Doregbits = doregbits
+	arch.Regnames = regnames
+	arch.ZeroRange = zerorange
+}
 
-	gc.Main()
-	gc.Exit(0)
+func (backend) Peep(firstp *obj.Prog) {
+	peep(firstp)
 }
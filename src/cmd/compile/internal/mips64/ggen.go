@@ -6,75 +6,28 @@ package mips64
 
 import (
 	"cmd/compile/internal/gc"
+	"cmd/compile/internal/ir"
 	"cmd/internal/obj"
 	"cmd/internal/obj/mips"
 	"fmt"
 )
 
-func defframe(ptxt *obj.Prog) {
-	// fill in argument size, stack size
-	ptxt.To.Type = obj.TYPE_TEXTSIZE
-
-	ptxt.To.Val = int32(gc.Rnd(gc.Curfn.Type.Argwid, int64(gc.Widthptr)))
-	frame := uint32(gc.Rnd(gc.Stksize+gc.Maxarg, int64(gc.Widthreg)))
-	ptxt.To.Offset = int64(frame)
-
-	// insert code to zero ambiguously live variables
-	// so that the garbage collector only sees initialized values
-	// when it looks for pointers.
-	p := ptxt
-
-	hi := int64(0)
-	lo := hi
-
-	// iterate through declarations - they are sorted in decreasing xoffset order.
-	for _, n := range gc.Curfn.Func.Dcl {
-		if !n.Name.Needzero {
-			continue
-		}
-		if n.Class != gc.PAUTO {
-			gc.Fatalf("needzero class %d", n.Class)
-		}
-		if n.Type.Width%int64(gc.Widthptr) != 0 || n.Xoffset%int64(gc.Widthptr) != 0 || n.Type.Width == 0 {
-			gc.Fatalf("var %v has size %d offset %d", gc.Nconv(n, gc.FmtLong), int(n.Type.Width), int(n.Xoffset))
-		}
-
-		if lo != hi && n.Xoffset+n.Type.Width >= lo-int64(2*gc.Widthreg) {
-			// merge with range we already have
-			lo = n.Xoffset
-
-			continue
-		}
-
-		// zero old range
-		p = zerorange(p, int64(frame), lo, hi)
-
-		// set new range
-		hi = n.Xoffset + n.Type.Width
-
-		lo = n.Xoffset
-	}
-
-	// zero final range
-	zerorange(p, int64(frame), lo, hi)
-}
-
-func zerorange(p *obj.Prog, frame int64, lo int64, hi int64) *obj.Prog {
+func zerorange(p *obj.Prog, frame int64, lo int64, hi int64, state *uint32) *obj.Prog {
 	cnt := hi - lo
 	if cnt == 0 {
 		return p
 	}
 	if cnt < int64(4*gc.Widthptr) {
 		for i := int64(0); i < cnt; i += int64(gc.Widthptr) {
-			p = appendpp(p, mips.AMOVV, obj.TYPE_REG, mips.REGZERO, 0, obj.TYPE_MEM, mips.REGSP, 8+frame+lo+i)
+			p = gc.Appendpp(p, mips.AMOVV, obj.TYPE_REG, mips.REGZERO, 0, obj.TYPE_MEM, mips.REGSP, 8+frame+lo+i)
 		}
 		// TODO(dfc): https://golang.org/issue/12108
 		// If DUFFZERO is used inside a tail call (see genwrapper) it will
 		// overwrite the link register.
 	} else if false && cnt <= int64(128*gc.Widthptr) {
-		p = appendpp(p, mips.AADDV, obj.TYPE_CONST, 0, 8+frame+lo-8, obj.TYPE_REG, mips.REGRT1, 0)
+		p = gc.Appendpp(p, mips.AADDV, obj.TYPE_CONST, 0, 8+frame+lo-8, obj.TYPE_REG, mips.REGRT1, 0)
 		p.Reg = mips.REGSP
-		p = appendpp(p, obj.ADUFFZERO, obj.TYPE_NONE, 0, 0, obj.TYPE_MEM, 0, 0)
+		p = gc.Appendpp(p, obj.ADUFFZERO, obj.TYPE_NONE, 0, 0, obj.TYPE_MEM, 0, 0)
 		f := gc.Sysfunc("duffzero")
 		gc.Naddr(&p.To, f)
 		gc.Afunclit(&p.To, f)
@@ -86,14 +39,14 @@ func zerorange(p *obj.Prog, frame int64, lo int64, hi int64) *obj.Prog {
 		//	MOVV	R0, (Widthptr)r1
 		//	ADDV	$Widthptr, r1
 		//	BNE		r1, r2, loop
-		p = appendpp(p, mips.AADDV, obj.TYPE_CONST, 0, 8+frame+lo-8, obj.TYPE_REG, mips.REGRT1, 0)
+		p = gc.Appendpp(p, mips.AADDV, obj.TYPE_CONST, 0, 8+frame+lo-8, obj.TYPE_REG, mips.REGRT1, 0)
 		p.Reg = mips.REGSP
-		p = appendpp(p, mips.AADDV, obj.TYPE_CONST, 0, cnt, obj.TYPE_REG, mips.REGRT2, 0)
+		p = gc.Appendpp(p, mips.AADDV, obj.TYPE_CONST, 0, cnt, obj.TYPE_REG, mips.REGRT2, 0)
 		p.Reg = mips.REGRT1
-		p = appendpp(p, mips.AMOVV, obj.TYPE_REG, mips.REGZERO, 0, obj.TYPE_MEM, mips.REGRT1, int64(gc.Widthptr))
+		p = gc.Appendpp(p, mips.AMOVV, obj.TYPE_REG, mips.REGZERO, 0, obj.TYPE_MEM, mips.REGRT1, int64(gc.Widthptr))
 		p1 := p
-		p = appendpp(p, mips.AADDV, obj.TYPE_CONST, 0, int64(gc.Widthptr), obj.TYPE_REG, mips.REGRT1, 0)
-		p = appendpp(p, mips.ABNE, obj.TYPE_REG, mips.REGRT1, 0, obj.TYPE_BRANCH, 0, 0)
+		p = gc.Appendpp(p, mips.AADDV, obj.TYPE_CONST, 0, int64(gc.Widthptr), obj.TYPE_REG, mips.REGRT1, 0)
+		p = gc.Appendpp(p, mips.ABNE, obj.TYPE_REG, mips.REGRT1, 0, obj.TYPE_BRANCH, 0, 0)
 		p.Reg = mips.REGRT2
 		gc.Patch(p, p1)
 	}
@@ -101,29 +54,13 @@ func zerorange(p *obj.Prog, frame int64, lo int64, hi int64) *obj.Prog {
 	return p
 }
 
-func appendpp(p *obj.Prog, as obj.As, ftype obj.AddrType, freg int, foffset int64, ttype obj.AddrType, treg int, toffset int64) *obj.Prog {
-	q := gc.Ctxt.NewProg()
-	gc.Clearp(q)
-	q.As = as
-	q.Lineno = p.Lineno
-	q.From.Type = ftype
-	q.From.Reg = int16(freg)
-	q.From.Offset = foffset
-	q.To.Type = ttype
-	q.To.Reg = int16(treg)
-	q.To.Offset = toffset
-	q.Link = p.Link
-	p.Link = q
-	return q
-}
-
 func ginsnop() {
-	var reg gc.Node
-	gc.Nodreg(&reg, gc.Types[gc.TINT], mips.REG_R0)
+	var reg ir.Node
+	gc.Nodreg(&reg, ir.Types[ir.TINT], mips.REG_R0)
 	gins(mips.ANOR, &reg, &reg)
 }
 
-var panicdiv *gc.Node
+var panicdiv *ir.Node
 
 /*
  * generate division.
@@ -132,24 +69,24 @@ var panicdiv *gc.Node
  *	res = nl % nr
  * according to op.
  */
-func dodiv(op gc.Op, nl *gc.Node, nr *gc.Node, res *gc.Node) {
+func dodiv(op ir.Op, nl *ir.Node, nr *ir.Node, res *ir.Node) {
 	t := nl.Type
 
 	t0 := t
 
 	if t.Width < 8 {
 		if gc.Issigned[t.Etype] {
-			t = gc.Types[gc.TINT64]
+			t = ir.Types[ir.TINT64]
 		} else {
-			t = gc.Types[gc.TUINT64]
+			t = ir.Types[ir.TUINT64]
 		}
 	}
 
-	a := optoas(gc.ODIV, t)
+	a := optoas(ir.ODIV, t)
 
-	var tl gc.Node
+	var tl ir.Node
 	gc.Regalloc(&tl, t0, nil)
-	var tr gc.Node
+	var tr ir.Node
 	gc.Regalloc(&tr, t0, nil)
 	if nl.Ullman >= nr.Ullman {
 		gc.Cgen(nl, &tl)
@@ -180,13 +117,13 @@ func dodiv(op gc.Op, nl *gc.Node, nr *gc.Node, res *gc.Node) {
 
 	gins3(a, &tr, &tl, nil)
 	gc.Regfree(&tr)
-	if op == gc.ODIV {
-		var lo gc.Node
-		gc.Nodreg(&lo, gc.Types[gc.TUINT64], mips.REG_LO)
+	if op == ir.ODIV {
+		var lo ir.Node
+		gc.Nodreg(&lo, ir.Types[ir.TUINT64], mips.REG_LO)
 		gins(mips.AMOVV, &lo, &tl)
 	} else { // remainder in REG_HI
-		var hi gc.Node
-		gc.Nodreg(&hi, gc.Types[gc.TUINT64], mips.REG_HI)
+		var hi ir.Node
+		gc.Nodreg(&hi, ir.Types[ir.TUINT64], mips.REG_HI)
 		gins(mips.AMOVV, &hi, &tl)
 	}
 	gmove(&tl, res)
@@ -197,7 +134,7 @@ func dodiv(op gc.Op, nl *gc.Node, nr *gc.Node, res *gc.Node) {
  * generate high multiply:
  *   res = (nl*nr) >> width
  */
-func cgen_hmul(nl *gc.Node, nr *gc.Node, res *gc.Node) {
+func cgen_hmul(nl *ir.Node, nr *ir.Node, res *ir.Node) {
 	// largest ullman on left.
 	if nl.Ullman < nr.Ullman {
 		nl, nr = nr, nl
@@ -205,42 +142,42 @@ func cgen_hmul(nl *gc.Node, nr *gc.Node, res *gc.Node) {
 
 	t := nl.Type
 	w := t.Width * 8
-	var n1 gc.Node
+	var n1 ir.Node
 	gc.Cgenr(nl, &n1, res)
-	var n2 gc.Node
+	var n2 ir.Node
 	gc.Cgenr(nr, &n2, nil)
 	switch gc.Simtype[t.Etype] {
-	case gc.TINT8,
-		gc.TINT16,
-		gc.TINT32:
-		gins3(optoas(gc.OMUL, t), &n2, &n1, nil)
-		var lo gc.Node
-		gc.Nodreg(&lo, gc.Types[gc.TUINT64], mips.REG_LO)
+	case ir.TINT8,
+		ir.TINT16,
+		ir.TINT32:
+		gins3(optoas(ir.OMUL, t), &n2, &n1, nil)
+		var lo ir.Node
+		gc.Nodreg(&lo, ir.Types[ir.TUINT64], mips.REG_LO)
 		gins(mips.AMOVV, &lo, &n1)
 		p := gins(mips.ASRAV, nil, &n1)
 		p.From.Type = obj.TYPE_CONST
 		p.From.Offset = w
 
-	case gc.TUINT8,
-		gc.TUINT16,
-		gc.TUINT32:
-		gins3(optoas(gc.OMUL, t), &n2, &n1, nil)
-		var lo gc.Node
-		gc.Nodreg(&lo, gc.Types[gc.TUINT64], mips.REG_LO)
+	case ir.TUINT8,
+		ir.TUINT16,
+		ir.TUINT32:
+		gins3(optoas(ir.OMUL, t), &n2, &n1, nil)
+		var lo ir.Node
+		gc.Nodreg(&lo, ir.Types[ir.TUINT64], mips.REG_LO)
 		gins(mips.AMOVV, &lo, &n1)
 		p := gins(mips.ASRLV, nil, &n1)
 		p.From.Type = obj.TYPE_CONST
 		p.From.Offset = w
 
-	case gc.TINT64,
-		gc.TUINT64:
+	case ir.TINT64,
+		ir.TUINT64:
 		if gc.Issigned[t.Etype] {
 			gins3(mips.AMULV, &n2, &n1, nil)
 		} else {
 			gins3(mips.AMULVU, &n2, &n1, nil)
 		}
-		var hi gc.Node
-		gc.Nodreg(&hi, gc.Types[gc.TUINT64], mips.REG_HI)
+		var hi ir.Node
+		gc.Nodreg(&hi, ir.Types[ir.TUINT64], mips.REG_HI)
 		gins(mips.AMOVV, &hi, &n1)
 
 	default:
@@ -257,18 +194,18 @@ func cgen_hmul(nl *gc.Node, nr *gc.Node, res *gc.Node) {
  *	res = nl << nr
  *	res = nl >> nr
  */
-func cgen_shift(op gc.Op, bounded bool, nl *gc.Node, nr *gc.Node, res *gc.Node) {
+func cgen_shift(op ir.Op, bounded bool, nl *ir.Node, nr *ir.Node, res *ir.Node) {
 	a := optoas(op, nl.Type)
 
-	if nr.Op == gc.OLITERAL {
-		var n1 gc.Node
+	if nr.Op == ir.OLITERAL {
+		var n1 ir.Node
 		gc.Regalloc(&n1, nl.Type, res)
 		gc.Cgen(nl, &n1)
 		sc := uint64(nr.Int())
 		if sc >= uint64(nl.Type.Width*8) {
 			// large shift gets 2 shifts by width-1
-			var n3 gc.Node
-			gc.Nodconst(&n3, gc.Types[gc.TUINT32], nl.Type.Width*8-1)
+			var n3 ir.Node
+			gc.Nodconst(&n3, ir.Types[ir.TUINT32], nl.Type.Width*8-1)
 
 			gins(a, &n3, &n1)
 			gins(a, &n3, &n1)
@@ -281,14 +218,14 @@ func cgen_shift(op gc.Op, bounded bool, nl *gc.Node, nr *gc.Node, res *gc.Node)
 	}
 
 	if nl.Ullman >= gc.UINF {
-		var n4 gc.Node
+		var n4 ir.Node
 		gc.Tempname(&n4, nl.Type)
 		gc.Cgen(nl, &n4)
 		nl = &n4
 	}
 
 	if nr.Ullman >= gc.UINF {
-		var n5 gc.Node
+		var n5 ir.Node
 		gc.Tempname(&n5, nr.Type)
 		gc.Cgen(nr, &n5)
 		nr = &n5
@@ -297,18 +234,18 @@ func cgen_shift(op gc.Op, bounded bool, nl *gc.Node, nr *gc.Node, res *gc.Node)
 	// Allow either uint32 or uint64 as shift type,
 	// to avoid unnecessary conversion from uint32 to uint64
 	// just to do the comparison.
-	tcount := gc.Types[gc.Simtype[nr.Type.Etype]]
+	tcount := ir.Types[gc.Simtype[nr.Type.Etype]]
 
-	if tcount.Etype < gc.TUINT32 {
-		tcount = gc.Types[gc.TUINT32]
+	if tcount.Etype < ir.TUINT32 {
+		tcount = ir.Types[ir.TUINT32]
 	}
 
-	var n1 gc.Node
+	var n1 ir.Node
 	gc.Regalloc(&n1, nr.Type, nil) // to hold the shift type in CX
-	var n3 gc.Node
+	var n3 ir.Node
 	gc.Regalloc(&n3, tcount, &n1) // to clear high bits of CX
 
-	var n2 gc.Node
+	var n2 ir.Node
 	gc.Regalloc(&n2, nl.Type, res)
 
 	if nl.Ullman >= nr.Ullman {
@@ -325,13 +262,13 @@ func cgen_shift(op gc.Op, bounded bool, nl *gc.Node, nr *gc.Node, res *gc.Node)
 
 	// test and fix up large shifts
 	if !bounded {
-		var rtmp gc.Node
+		var rtmp ir.Node
 		gc.Nodreg(&rtmp, tcount, mips.REGTMP)
 		gc.Nodconst(&n3, tcount, nl.Type.Width*8)
 		gins3(mips.ASGTU, &n3, &n1, &rtmp)
 		p1 := ginsbranch(mips.ABNE, nil, &rtmp, nil, 0)
-		if op == gc.ORSH && gc.Issigned[nl.Type.Etype] {
-			gc.Nodconst(&n3, gc.Types[gc.TUINT32], nl.Type.Width*8-1)
+		if op == ir.ORSH && gc.Issigned[nl.Type.Etype] {
+			gc.Nodconst(&n3, ir.Types[ir.TUINT32], nl.Type.Width*8-1)
 			gins(a, &n3, &n2)
 		} else {
 			gc.Nodconst(&n3, nl.Type, 0)
@@ -349,7 +286,7 @@ func cgen_shift(op gc.Op, bounded bool, nl *gc.Node, nr *gc.Node, res *gc.Node)
 	gc.Regfree(&n2)
 }
 
-func clearfat(nl *gc.Node) {
+func clearfat(nl *ir.Node) {
 	/* clear a fat object */
 	if gc.Debug['g'] != 0 {
 		fmt.Printf("clearfat %v (%v, size: %d)\n", nl, nl.Type, nl.Type.Width)
@@ -369,10 +306,10 @@ func clearfat(nl *gc.Node) {
 		gc.Fatalf("%v in use during clearfat", obj.Rconv(mips.REGRT1))
 	}
 
-	var r0 gc.Node
-	gc.Nodreg(&r0, gc.Types[gc.TUINT64], mips.REGZERO)
-	var dst gc.Node
-	gc.Nodreg(&dst, gc.Types[gc.Tptr], mips.REGRT1)
+	var r0 ir.Node
+	gc.Nodreg(&r0, ir.Types[ir.TUINT64], mips.REGZERO)
+	var dst ir.Node
+	gc.Nodreg(&dst, ir.Types[gc.Tptr], mips.REGRT1)
 	gc.Regrealloc(&dst)
 	gc.Agen(nl, &dst)
 
@@ -382,8 +319,8 @@ func clearfat(nl *gc.Node) {
 		p.From.Type = obj.TYPE_CONST
 		p.From.Offset = 8
 
-		var end gc.Node
-		gc.Regalloc(&end, gc.Types[gc.Tptr], nil)
+		var end ir.Node
+		gc.Regalloc(&end, ir.Types[gc.Tptr], nil)
 		p = gins(mips.AMOVV, &dst, &end)
 		p.From.Type = obj.TYPE_ADDR
 		p.From.Offset = int64(q * 8)
@@ -484,8 +421,8 @@ func expandchecks(firstp *obj.Prog) {
 }
 
 // res = runtime.getg()
-func getg(res *gc.Node) {
-	var n1 gc.Node
+func getg(res *ir.Node) {
+	var n1 ir.Node
 	gc.Nodreg(&n1, res.Type, mips.REGG)
 	gmove(&n1, res)
 }
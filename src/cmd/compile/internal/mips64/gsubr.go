@@ -33,6 +33,7 @@ package mips64
 import (
 	"cmd/compile/internal/big"
 	"cmd/compile/internal/gc"
+	"cmd/compile/internal/ir"
 	"cmd/internal/obj"
 	"cmd/internal/obj/mips"
 	"fmt"
@@ -56,16 +57,16 @@ var resvd = []int{
  * generate
  *	as $c, n
  */
-func ginscon(as obj.As, c int64, n2 *gc.Node) {
-	var n1 gc.Node
+func ginscon(as obj.As, c int64, n2 *ir.Node) {
+	var n1 ir.Node
 
-	gc.Nodconst(&n1, gc.Types[gc.TINT64], c)
+	gc.Nodconst(&n1, ir.Types[ir.TINT64], c)
 
-	if as != mips.AMOVV && (c < -mips.BIG || c > mips.BIG) || n2.Op != gc.OREGISTER || as == mips.AMUL || as == mips.AMULU || as == mips.AMULV || as == mips.AMULVU {
+	if as != mips.AMOVV && (c < -mips.BIG || c > mips.BIG) || n2.Op != ir.OREGISTER || as == mips.AMUL || as == mips.AMULU || as == mips.AMULV || as == mips.AMULVU {
 		// cannot have more than 16-bit of immediate in ADD, etc.
 		// instead, MOV into register first.
-		var ntmp gc.Node
-		gc.Regalloc(&ntmp, gc.Types[gc.TINT64], nil)
+		var ntmp ir.Node
+		gc.Regalloc(&ntmp, ir.Types[ir.TINT64], nil)
 
 		rawgins(mips.AMOVV, &n1, &ntmp)
 		rawgins(as, &ntmp, n2)
@@ -78,7 +79,7 @@ func ginscon(as obj.As, c int64, n2 *gc.Node) {
 
 // generate branch
 // n1, n2 are registers
-func ginsbranch(as obj.As, t *gc.Type, n1, n2 *gc.Node, likely int) *obj.Prog {
+func ginsbranch(as obj.As, t *ir.Type, n1, n2 *ir.Node, likely int) *obj.Prog {
 	p := gc.Gbranch(as, t, likely)
 	gc.Naddr(&p.From, n1)
 	if n2 != nil {
@@ -87,22 +88,22 @@ func ginsbranch(as obj.As, t *gc.Type, n1, n2 *gc.Node, likely int) *obj.Prog {
 	return p
 }
 
-func ginscmp(op gc.Op, t *gc.Type, n1, n2 *gc.Node, likely int) *obj.Prog {
-	if !gc.Isfloat[t.Etype] && (op == gc.OLT || op == gc.OGE) {
+func ginscmp(op ir.Op, t *ir.Type, n1, n2 *ir.Node, likely int) *obj.Prog {
+	if !gc.Isfloat[t.Etype] && (op == ir.OLT || op == ir.OGE) {
 		// swap nodes to fit SGT instruction
 		n1, n2 = n2, n1
 	}
-	if gc.Isfloat[t.Etype] && (op == gc.OLT || op == gc.OLE) {
+	if gc.Isfloat[t.Etype] && (op == ir.OLT || op == ir.OLE) {
 		// swap nodes to fit CMPGT, CMPGE instructions and reverse relation
 		n1, n2 = n2, n1
-		if op == gc.OLT {
-			op = gc.OGT
+		if op == ir.OLT {
+			op = ir.OGT
 		} else {
-			op = gc.OGE
+			op = ir.OGE
 		}
 	}
 
-	var r1, r2, g1, g2 gc.Node
+	var r1, r2, g1, g2 ir.Node
 	gc.Regalloc(&r1, t, n1)
 	gc.Regalloc(&g1, n1.Type, &r1)
 	gc.Cgen(n1, &g1)
@@ -114,15 +115,15 @@ func ginscmp(op gc.Op, t *gc.Type, n1, n2 *gc.Node, likely int) *obj.Prog {
 	gmove(&g2, &r2)
 
 	var p *obj.Prog
-	var ntmp gc.Node
-	gc.Nodreg(&ntmp, gc.Types[gc.TINT], mips.REGTMP)
+	var ntmp ir.Node
+	gc.Nodreg(&ntmp, ir.Types[ir.TINT], mips.REGTMP)
 
 	switch gc.Simtype[t.Etype] {
-	case gc.TINT8,
-		gc.TINT16,
-		gc.TINT32,
-		gc.TINT64:
-		if op == gc.OEQ || op == gc.ONE {
+	case ir.TINT8,
+		ir.TINT16,
+		ir.TINT32,
+		ir.TINT64:
+		if op == ir.OEQ || op == ir.ONE {
 			p = ginsbranch(optoas(op, t), nil, &r1, &r2, likely)
 		} else {
 			gins3(mips.ASGT, &r1, &r2, &ntmp)
@@ -130,14 +131,14 @@ func ginscmp(op gc.Op, t *gc.Type, n1, n2 *gc.Node, likely int) *obj.Prog {
 			p = ginsbranch(optoas(op, t), nil, &ntmp, nil, likely)
 		}
 
-	case gc.TBOOL,
-		gc.TUINT8,
-		gc.TUINT16,
-		gc.TUINT32,
-		gc.TUINT64,
-		gc.TPTR32,
-		gc.TPTR64:
-		if op == gc.OEQ || op == gc.ONE {
+	case ir.TBOOL,
+		ir.TUINT8,
+		ir.TUINT16,
+		ir.TUINT32,
+		ir.TUINT64,
+		ir.TPTR32,
+		ir.TPTR64:
+		if op == ir.OEQ || op == ir.ONE {
 			p = ginsbranch(optoas(op, t), nil, &r1, &r2, likely)
 		} else {
 			gins3(mips.ASGTU, &r1, &r2, &ntmp)
@@ -145,36 +146,36 @@ func ginscmp(op gc.Op, t *gc.Type, n1, n2 *gc.Node, likely int) *obj.Prog {
 			p = ginsbranch(optoas(op, t), nil, &ntmp, nil, likely)
 		}
 
-	case gc.TFLOAT32:
+	case ir.TFLOAT32:
 		switch op {
 		default:
 			gc.Fatalf("ginscmp: no entry for op=%v type=%v", gc.Oconv(op, 0), t)
 
-		case gc.OEQ,
-			gc.ONE:
+		case ir.OEQ,
+			ir.ONE:
 			gins3(mips.ACMPEQF, &r1, &r2, nil)
 
-		case gc.OGE:
+		case ir.OGE:
 			gins3(mips.ACMPGEF, &r1, &r2, nil)
 
-		case gc.OGT:
+		case ir.OGT:
 			gins3(mips.ACMPGTF, &r1, &r2, nil)
 		}
 		p = gc.Gbranch(optoas(op, t), nil, likely)
 
-	case gc.TFLOAT64:
+	case ir.TFLOAT64:
 		switch op {
 		default:
 			gc.Fatalf("ginscmp: no entry for op=%v type=%v", gc.Oconv(op, 0), t)
 
-		case gc.OEQ,
-			gc.ONE:
+		case ir.OEQ,
+			ir.ONE:
 			gins3(mips.ACMPEQD, &r1, &r2, nil)
 
-		case gc.OGE:
+		case ir.OGE:
 			gins3(mips.ACMPGED, &r1, &r2, nil)
 
-		case gc.OGT:
+		case ir.OGT:
 			gins3(mips.ACMPGTD, &r1, &r2, nil)
 		}
 		p = gc.Gbranch(optoas(op, t), nil, likely)
@@ -190,8 +191,8 @@ func ginscmp(op gc.Op, t *gc.Type, n1, n2 *gc.Node, likely int) *obj.Prog {
 
 // set up nodes representing 2^63
 var (
-	bigi         gc.Node
-	bigf         gc.Node
+	bigi         ir.Node
+	bigf         ir.Node
 	bignodes_did bool
 )
 
@@ -205,10 +206,10 @@ func bignodes() {
 	i.SetInt64(1)
 	i.Lsh(&i, 63)
 
-	gc.Nodconst(&bigi, gc.Types[gc.TUINT64], 0)
+	gc.Nodconst(&bigi, ir.Types[ir.TUINT64], 0)
 	bigi.SetBigInt(&i)
 
-	bigi.Convconst(&bigf, gc.Types[gc.TFLOAT64])
+	gc.Convconst(&bigi, &bigf, ir.Types[ir.TFLOAT64])
 }
 
 /*
@@ -216,9 +217,9 @@ func bignodes() {
  *	t = f
  * hard part is conversions.
  */
-func gmove(f *gc.Node, t *gc.Node) {
+func gmove(f *ir.Node, t *ir.Node) {
 	if gc.Debug['M'] != 0 {
-		fmt.Printf("gmove %v -> %v\n", gc.Nconv(f, gc.FmtLong), gc.Nconv(t, gc.FmtLong))
+		fmt.Printf("gmove %v -> %v\n", gc.Nconv(f, ir.FmtLong), gc.Nconv(t, ir.FmtLong))
 	}
 
 	ft := int(gc.Simsimtype(f.Type))
@@ -231,38 +232,38 @@ func gmove(f *gc.Node, t *gc.Node) {
 	}
 
 	// cannot have two memory operands
-	var r2 gc.Node
-	var r1 gc.Node
+	var r2 ir.Node
+	var r1 ir.Node
 	var a obj.As
 	if gc.Ismem(f) && gc.Ismem(t) {
 		goto hard
 	}
 
 	// convert constant to desired type
-	if f.Op == gc.OLITERAL {
-		var con gc.Node
+	if f.Op == ir.OLITERAL {
+		var con ir.Node
 		switch tt {
 		default:
-			f.Convconst(&con, t.Type)
-
-		case gc.TINT32,
-			gc.TINT16,
-			gc.TINT8:
-			var con gc.Node
-			f.Convconst(&con, gc.Types[gc.TINT64])
-			var r1 gc.Node
+			gc.Convconst(f, &con, t.Type)
+
+		case ir.TINT32,
+			ir.TINT16,
+			ir.TINT8:
+			var con ir.Node
+			gc.Convconst(f, &con, ir.Types[ir.TINT64])
+			var r1 ir.Node
 			gc.Regalloc(&r1, con.Type, t)
 			gins(mips.AMOVV, &con, &r1)
 			gmove(&r1, t)
 			gc.Regfree(&r1)
 			return
 
-		case gc.TUINT32,
-			gc.TUINT16,
-			gc.TUINT8:
-			var con gc.Node
-			f.Convconst(&con, gc.Types[gc.TUINT64])
-			var r1 gc.Node
+		case ir.TUINT32,
+			ir.TUINT16,
+			ir.TUINT8:
+			var con ir.Node
+			gc.Convconst(f, &con, ir.Types[ir.TUINT64])
+			var r1 ir.Node
 			gc.Regalloc(&r1, con.Type, t)
 			gins(mips.AMOVV, &con, &r1)
 			gmove(&r1, t)
@@ -283,7 +284,7 @@ func gmove(f *gc.Node, t *gc.Node) {
 	// any floating point operand requires register
 	// src, so goto hard to copy to register first.
 	if gc.Ismem(f) && ft != tt && (gc.Isfloat[ft] || gc.Isfloat[tt]) {
-		cvt = gc.Types[ft]
+		cvt = ir.Types[ft]
 		goto hard
 	}
 
@@ -296,112 +297,112 @@ func gmove(f *gc.Node, t *gc.Node) {
 
 	switch uint32(ft)<<16 | uint32(tt) {
 	default:
-		gc.Fatalf("gmove %v -> %v", gc.Tconv(f.Type, gc.FmtLong), gc.Tconv(t.Type, gc.FmtLong))
+		gc.Fatalf("gmove %v -> %v", gc.Tconv(f.Type, ir.FmtLong), gc.Tconv(t.Type, ir.FmtLong))
 
 		/*
 		 * integer copy and truncate
 		 */
-	case gc.TINT8<<16 | gc.TINT8, // same size
-		gc.TUINT8<<16 | gc.TINT8,
-		gc.TINT16<<16 | gc.TINT8, // truncate
-		gc.TUINT16<<16 | gc.TINT8,
-		gc.TINT32<<16 | gc.TINT8,
-		gc.TUINT32<<16 | gc.TINT8,
-		gc.TINT64<<16 | gc.TINT8,
-		gc.TUINT64<<16 | gc.TINT8:
+	case ir.TINT8<<16 | ir.TINT8, // same size
+		ir.TUINT8<<16 | ir.TINT8,
+		ir.TINT16<<16 | ir.TINT8, // truncate
+		ir.TUINT16<<16 | ir.TINT8,
+		ir.TINT32<<16 | ir.TINT8,
+		ir.TUINT32<<16 | ir.TINT8,
+		ir.TINT64<<16 | ir.TINT8,
+		ir.TUINT64<<16 | ir.TINT8:
 		a = mips.AMOVB
 
-	case gc.TINT8<<16 | gc.TUINT8, // same size
-		gc.TUINT8<<16 | gc.TUINT8,
-		gc.TINT16<<16 | gc.TUINT8, // truncate
-		gc.TUINT16<<16 | gc.TUINT8,
-		gc.TINT32<<16 | gc.TUINT8,
-		gc.TUINT32<<16 | gc.TUINT8,
-		gc.TINT64<<16 | gc.TUINT8,
-		gc.TUINT64<<16 | gc.TUINT8:
+	case ir.TINT8<<16 | ir.TUINT8, // same size
+		ir.TUINT8<<16 | ir.TUINT8,
+		ir.TINT16<<16 | ir.TUINT8, // truncate
+		ir.TUINT16<<16 | ir.TUINT8,
+		ir.TINT32<<16 | ir.TUINT8,
+		ir.TUINT32<<16 | ir.TUINT8,
+		ir.TINT64<<16 | ir.TUINT8,
+		ir.TUINT64<<16 | ir.TUINT8:
 		a = mips.AMOVBU
 
-	case gc.TINT16<<16 | gc.TINT16, // same size
-		gc.TUINT16<<16 | gc.TINT16,
-		gc.TINT32<<16 | gc.TINT16, // truncate
-		gc.TUINT32<<16 | gc.TINT16,
-		gc.TINT64<<16 | gc.TINT16,
-		gc.TUINT64<<16 | gc.TINT16:
+	case ir.TINT16<<16 | ir.TINT16, // same size
+		ir.TUINT16<<16 | ir.TINT16,
+		ir.TINT32<<16 | ir.TINT16, // truncate
+		ir.TUINT32<<16 | ir.TINT16,
+		ir.TINT64<<16 | ir.TINT16,
+		ir.TUINT64<<16 | ir.TINT16:
 		a = mips.AMOVH
 
-	case gc.TINT16<<16 | gc.TUINT16, // same size
-		gc.TUINT16<<16 | gc.TUINT16,
-		gc.TINT32<<16 | gc.TUINT16, // truncate
-		gc.TUINT32<<16 | gc.TUINT16,
-		gc.TINT64<<16 | gc.TUINT16,
-		gc.TUINT64<<16 | gc.TUINT16:
+	case ir.TINT16<<16 | ir.TUINT16, // same size
+		ir.TUINT16<<16 | ir.TUINT16,
+		ir.TINT32<<16 | ir.TUINT16, // truncate
+		ir.TUINT32<<16 | ir.TUINT16,
+		ir.TINT64<<16 | ir.TUINT16,
+		ir.TUINT64<<16 | ir.TUINT16:
 		a = mips.AMOVHU
 
-	case gc.TINT32<<16 | gc.TINT32, // same size
-		gc.TUINT32<<16 | gc.TINT32,
-		gc.TINT64<<16 | gc.TINT32, // truncate
-		gc.TUINT64<<16 | gc.TINT32:
+	case ir.TINT32<<16 | ir.TINT32, // same size
+		ir.TUINT32<<16 | ir.TINT32,
+		ir.TINT64<<16 | ir.TINT32, // truncate
+		ir.TUINT64<<16 | ir.TINT32:
 		a = mips.AMOVW
 
-	case gc.TINT32<<16 | gc.TUINT32, // same size
-		gc.TUINT32<<16 | gc.TUINT32,
-		gc.TINT64<<16 | gc.TUINT32, // truncate
-		gc.TUINT64<<16 | gc.TUINT32:
+	case ir.TINT32<<16 | ir.TUINT32, // same size
+		ir.TUINT32<<16 | ir.TUINT32,
+		ir.TINT64<<16 | ir.TUINT32, // truncate
+		ir.TUINT64<<16 | ir.TUINT32:
 		a = mips.AMOVWU
 
-	case gc.TINT64<<16 | gc.TINT64, // same size
-		gc.TINT64<<16 | gc.TUINT64,
-		gc.TUINT64<<16 | gc.TINT64,
-		gc.TUINT64<<16 | gc.TUINT64:
+	case ir.TINT64<<16 | ir.TINT64, // same size
+		ir.TINT64<<16 | ir.TUINT64,
+		ir.TUINT64<<16 | ir.TINT64,
+		ir.TUINT64<<16 | ir.TUINT64:
 		a = mips.AMOVV
 
 		/*
 		 * integer up-conversions
 		 */
-	case gc.TINT8<<16 | gc.TINT16, // sign extend int8
-		gc.TINT8<<16 | gc.TUINT16,
-		gc.TINT8<<16 | gc.TINT32,
-		gc.TINT8<<16 | gc.TUINT32,
-		gc.TINT8<<16 | gc.TINT64,
-		gc.TINT8<<16 | gc.TUINT64:
+	case ir.TINT8<<16 | ir.TINT16, // sign extend int8
+		ir.TINT8<<16 | ir.TUINT16,
+		ir.TINT8<<16 | ir.TINT32,
+		ir.TINT8<<16 | ir.TUINT32,
+		ir.TINT8<<16 | ir.TINT64,
+		ir.TINT8<<16 | ir.TUINT64:
 		a = mips.AMOVB
 
 		goto rdst
 
-	case gc.TUINT8<<16 | gc.TINT16, // zero extend uint8
-		gc.TUINT8<<16 | gc.TUINT16,
-		gc.TUINT8<<16 | gc.TINT32,
-		gc.TUINT8<<16 | gc.TUINT32,
-		gc.TUINT8<<16 | gc.TINT64,
-		gc.TUINT8<<16 | gc.TUINT64:
+	case ir.TUINT8<<16 | ir.TINT16, // zero extend uint8
+		ir.TUINT8<<16 | ir.TUINT16,
+		ir.TUINT8<<16 | ir.TINT32,
+		ir.TUINT8<<16 | ir.TUINT32,
+		ir.TUINT8<<16 | ir.TINT64,
+		ir.TUINT8<<16 | ir.TUINT64:
 		a = mips.AMOVBU
 
 		goto rdst
 
-	case gc.TINT16<<16 | gc.TINT32, // sign extend int16
-		gc.TINT16<<16 | gc.TUINT32,
-		gc.TINT16<<16 | gc.TINT64,
-		gc.TINT16<<16 | gc.TUINT64:
+	case ir.TINT16<<16 | ir.TINT32, // sign extend int16
+		ir.TINT16<<16 | ir.TUINT32,
+		ir.TINT16<<16 | ir.TINT64,
+		ir.TINT16<<16 | ir.TUINT64:
 		a = mips.AMOVH
 
 		goto rdst
 
-	case gc.TUINT16<<16 | gc.TINT32, // zero extend uint16
-		gc.TUINT16<<16 | gc.TUINT32,
-		gc.TUINT16<<16 | gc.TINT64,
-		gc.TUINT16<<16 | gc.TUINT64:
+	case ir.TUINT16<<16 | ir.TINT32, // zero extend uint16
+		ir.TUINT16<<16 | ir.TUINT32,
+		ir.TUINT16<<16 | ir.TINT64,
+		ir.TUINT16<<16 | ir.TUINT64:
 		a = mips.AMOVHU
 
 		goto rdst
 
-	case gc.TINT32<<16 | gc.TINT64, // sign extend int32
-		gc.TINT32<<16 | gc.TUINT64:
+	case ir.TINT32<<16 | ir.TINT64, // sign extend int32
+		ir.TINT32<<16 | ir.TUINT64:
 		a = mips.AMOVW
 
 		goto rdst
 
-	case gc.TUINT32<<16 | gc.TINT64, // zero extend uint32
-		gc.TUINT32<<16 | gc.TUINT64:
+	case ir.TUINT32<<16 | ir.TINT64, // zero extend uint32
+		ir.TUINT32<<16 | ir.TUINT64:
 		a = mips.AMOVWU
 
 		goto rdst
@@ -414,28 +415,28 @@ func gmove(f *gc.Node, t *gc.Node) {
 	/*
 	* float to integer
 	 */
-	case gc.TFLOAT32<<16 | gc.TINT32,
-		gc.TFLOAT64<<16 | gc.TINT32,
-		gc.TFLOAT32<<16 | gc.TINT64,
-		gc.TFLOAT64<<16 | gc.TINT64,
-		gc.TFLOAT32<<16 | gc.TINT16,
-		gc.TFLOAT32<<16 | gc.TINT8,
-		gc.TFLOAT32<<16 | gc.TUINT16,
-		gc.TFLOAT32<<16 | gc.TUINT8,
-		gc.TFLOAT64<<16 | gc.TINT16,
-		gc.TFLOAT64<<16 | gc.TINT8,
-		gc.TFLOAT64<<16 | gc.TUINT16,
-		gc.TFLOAT64<<16 | gc.TUINT8,
-		gc.TFLOAT32<<16 | gc.TUINT32,
-		gc.TFLOAT64<<16 | gc.TUINT32,
-		gc.TFLOAT32<<16 | gc.TUINT64,
-		gc.TFLOAT64<<16 | gc.TUINT64:
+	case ir.TFLOAT32<<16 | ir.TINT32,
+		ir.TFLOAT64<<16 | ir.TINT32,
+		ir.TFLOAT32<<16 | ir.TINT64,
+		ir.TFLOAT64<<16 | ir.TINT64,
+		ir.TFLOAT32<<16 | ir.TINT16,
+		ir.TFLOAT32<<16 | ir.TINT8,
+		ir.TFLOAT32<<16 | ir.TUINT16,
+		ir.TFLOAT32<<16 | ir.TUINT8,
+		ir.TFLOAT64<<16 | ir.TINT16,
+		ir.TFLOAT64<<16 | ir.TINT8,
+		ir.TFLOAT64<<16 | ir.TUINT16,
+		ir.TFLOAT64<<16 | ir.TUINT8,
+		ir.TFLOAT32<<16 | ir.TUINT32,
+		ir.TFLOAT64<<16 | ir.TUINT32,
+		ir.TFLOAT32<<16 | ir.TUINT64,
+		ir.TFLOAT64<<16 | ir.TUINT64:
 		bignodes()
 
-		gc.Regalloc(&r1, gc.Types[gc.TFLOAT64], nil)
+		gc.Regalloc(&r1, ir.Types[ir.TFLOAT64], nil)
 		gmove(f, &r1)
-		if tt == gc.TUINT64 {
-			gc.Regalloc(&r2, gc.Types[gc.TFLOAT64], nil)
+		if tt == ir.TUINT64 {
+			gc.Regalloc(&r2, ir.Types[ir.TFLOAT64], nil)
 			gmove(&bigf, &r2)
 			gins3(mips.ACMPGED, &r1, &r2, nil)
 			p1 := gc.Gbranch(mips.ABFPF, nil, 0)
@@ -444,14 +445,14 @@ func gmove(f *gc.Node, t *gc.Node) {
 			gc.Regfree(&r2)
 		}
 
-		gc.Regalloc(&r2, gc.Types[gc.TINT64], t)
+		gc.Regalloc(&r2, ir.Types[ir.TINT64], t)
 		gins(mips.ATRUNCDV, &r1, &r1)
 		gins(mips.AMOVV, &r1, &r2)
 		gc.Regfree(&r1)
 
-		if tt == gc.TUINT64 {
+		if tt == ir.TUINT64 {
 			p1 := gc.Gbranch(mips.ABFPF, nil, 0) // use FCR0 here again
-			gc.Nodreg(&r1, gc.Types[gc.TINT64], mips.REGTMP)
+			gc.Nodreg(&r1, ir.Types[ir.TINT64], mips.REGTMP)
 			gmove(&bigi, &r1)
 			gins(mips.AADDVU, &r1, &r2)
 			gc.Patch(p1, gc.Pc)
@@ -469,29 +470,29 @@ func gmove(f *gc.Node, t *gc.Node) {
 	/*
 	 * integer to float
 	 */
-	case gc.TINT32<<16 | gc.TFLOAT32,
-		gc.TINT32<<16 | gc.TFLOAT64,
-		gc.TINT64<<16 | gc.TFLOAT32,
-		gc.TINT64<<16 | gc.TFLOAT64,
-		gc.TINT16<<16 | gc.TFLOAT32,
-		gc.TINT16<<16 | gc.TFLOAT64,
-		gc.TINT8<<16 | gc.TFLOAT32,
-		gc.TINT8<<16 | gc.TFLOAT64,
-		gc.TUINT16<<16 | gc.TFLOAT32,
-		gc.TUINT16<<16 | gc.TFLOAT64,
-		gc.TUINT8<<16 | gc.TFLOAT32,
-		gc.TUINT8<<16 | gc.TFLOAT64,
-		gc.TUINT32<<16 | gc.TFLOAT32,
-		gc.TUINT32<<16 | gc.TFLOAT64,
-		gc.TUINT64<<16 | gc.TFLOAT32,
-		gc.TUINT64<<16 | gc.TFLOAT64:
+	case ir.TINT32<<16 | ir.TFLOAT32,
+		ir.TINT32<<16 | ir.TFLOAT64,
+		ir.TINT64<<16 | ir.TFLOAT32,
+		ir.TINT64<<16 | ir.TFLOAT64,
+		ir.TINT16<<16 | ir.TFLOAT32,
+		ir.TINT16<<16 | ir.TFLOAT64,
+		ir.TINT8<<16 | ir.TFLOAT32,
+		ir.TINT8<<16 | ir.TFLOAT64,
+		ir.TUINT16<<16 | ir.TFLOAT32,
+		ir.TUINT16<<16 | ir.TFLOAT64,
+		ir.TUINT8<<16 | ir.TFLOAT32,
+		ir.TUINT8<<16 | ir.TFLOAT64,
+		ir.TUINT32<<16 | ir.TFLOAT32,
+		ir.TUINT32<<16 | ir.TFLOAT64,
+		ir.TUINT64<<16 | ir.TFLOAT32,
+		ir.TUINT64<<16 | ir.TFLOAT64:
 		bignodes()
 
-		var rtmp gc.Node
-		gc.Regalloc(&r1, gc.Types[gc.TINT64], nil)
+		var rtmp ir.Node
+		gc.Regalloc(&r1, ir.Types[ir.TINT64], nil)
 		gmove(f, &r1)
-		if ft == gc.TUINT64 {
-			gc.Nodreg(&rtmp, gc.Types[gc.TUINT64], mips.REGTMP)
+		if ft == ir.TUINT64 {
+			gc.Nodreg(&rtmp, ir.Types[ir.TUINT64], mips.REGTMP)
 			gmove(&bigi, &rtmp)
 			gins(mips.AAND, &r1, &rtmp)
 			p1 := ginsbranch(mips.ABEQ, nil, &rtmp, nil, 0)
@@ -501,14 +502,14 @@ func gmove(f *gc.Node, t *gc.Node) {
 			gc.Patch(p1, gc.Pc)
 		}
 
-		gc.Regalloc(&r2, gc.Types[gc.TFLOAT64], t)
+		gc.Regalloc(&r2, ir.Types[ir.TFLOAT64], t)
 		gins(mips.AMOVV, &r1, &r2)
 		gins(mips.AMOVVD, &r2, &r2)
 		gc.Regfree(&r1)
 
-		if ft == gc.TUINT64 {
+		if ft == ir.TUINT64 {
 			p1 := ginsbranch(mips.ABEQ, nil, &rtmp, nil, 0)
-			gc.Nodreg(&r1, gc.Types[gc.TFLOAT64], mips.FREGTWO)
+			gc.Nodreg(&r1, ir.Types[ir.TFLOAT64], mips.FREGTWO)
 			gins(mips.AMULD, &r1, &r2)
 			gc.Patch(p1, gc.Pc)
 		}
@@ -520,17 +521,17 @@ func gmove(f *gc.Node, t *gc.Node) {
 		/*
 		 * float to float
 		 */
-	case gc.TFLOAT32<<16 | gc.TFLOAT32:
+	case ir.TFLOAT32<<16 | ir.TFLOAT32:
 		a = mips.AMOVF
 
-	case gc.TFLOAT64<<16 | gc.TFLOAT64:
+	case ir.TFLOAT64<<16 | ir.TFLOAT64:
 		a = mips.AMOVD
 
-	case gc.TFLOAT32<<16 | gc.TFLOAT64:
+	case ir.TFLOAT32<<16 | ir.TFLOAT64:
 		a = mips.AMOVFD
 		goto rdst
 
-	case gc.TFLOAT64<<16 | gc.TFLOAT32:
+	case ir.TFLOAT64<<16 | ir.TFLOAT32:
 		a = mips.AMOVDF
 		goto rdst
 	}
@@ -562,7 +563,7 @@ hard:
 // gins is called by the front end.
 // It synthesizes some multiple-instruction sequences
 // so the front end can stay simpler.
-func gins(as obj.As, f, t *gc.Node) *obj.Prog {
+func gins(as obj.As, f, t *ir.Node) *obj.Prog {
 	if as >= obj.A_ARCHSPECIFIC {
 		if x, ok := f.IntLiteral(); ok {
 			ginscon(as, x, t)
@@ -577,7 +578,7 @@ func gins(as obj.As, f, t *gc.Node) *obj.Prog {
  *	as f, r, t
  * r must be register, if not nil
  */
-func gins3(as obj.As, f, r, t *gc.Node) *obj.Prog {
+func gins3(as obj.As, f, r, t *ir.Node) *obj.Prog {
 	p := rawgins(as, f, t)
 	if r != nil {
 		p.Reg = r.Reg
@@ -589,7 +590,7 @@ func gins3(as obj.As, f, r, t *gc.Node) *obj.Prog {
  * generate one instruction:
  *	as f, t
  */
-func rawgins(as obj.As, f *gc.Node, t *gc.Node) *obj.Prog {
+func rawgins(as obj.As, f *ir.Node, t *ir.Node) *obj.Prog {
 	// TODO(austin): Add self-move test like in 6g (but be careful
 	// of truncation moves)
 
@@ -684,32 +685,32 @@ func rawgins(as obj.As, f *gc.Node, t *gc.Node) *obj.Prog {
 /*
  * return Axxx for Oxxx on type t.
  */
-func optoas(op gc.Op, t *gc.Type) obj.As {
+func optoas(op ir.Op, t *ir.Type) obj.As {
 	if t == nil {
 		gc.Fatalf("optoas: t is nil")
 	}
 
 	// avoid constant conversions in switches below
 	const (
-		OMINUS_ = uint32(gc.OMINUS) << 16
-		OLSH_   = uint32(gc.OLSH) << 16
-		ORSH_   = uint32(gc.ORSH) << 16
-		OADD_   = uint32(gc.OADD) << 16
-		OSUB_   = uint32(gc.OSUB) << 16
-		OMUL_   = uint32(gc.OMUL) << 16
-		ODIV_   = uint32(gc.ODIV) << 16
-		OOR_    = uint32(gc.OOR) << 16
-		OAND_   = uint32(gc.OAND) << 16
-		OXOR_   = uint32(gc.OXOR) << 16
-		OEQ_    = uint32(gc.OEQ) << 16
-		ONE_    = uint32(gc.ONE) << 16
-		OLT_    = uint32(gc.OLT) << 16
-		OLE_    = uint32(gc.OLE) << 16
-		OGE_    = uint32(gc.OGE) << 16
-		OGT_    = uint32(gc.OGT) << 16
-		OCMP_   = uint32(gc.OCMP) << 16
-		OAS_    = uint32(gc.OAS) << 16
-		OHMUL_  = uint32(gc.OHMUL) << 16
+		OMINUS_ = uint32(ir.OMINUS) << 16
+		OLSH_   = uint32(ir.OLSH) << 16
+		ORSH_   = uint32(ir.ORSH) << 16
+		OADD_   = uint32(ir.OADD) << 16
+		OSUB_   = uint32(ir.OSUB) << 16
+		OMUL_   = uint32(ir.OMUL) << 16
+		ODIV_   = uint32(ir.ODIV) << 16
+		OOR_    = uint32(ir.OOR) << 16
+		OAND_   = uint32(ir.OAND) << 16
+		OXOR_   = uint32(ir.OXOR) << 16
+		OEQ_    = uint32(ir.OEQ) << 16
+		ONE_    = uint32(ir.ONE) << 16
+		OLT_    = uint32(ir.OLT) << 16
+		OLE_    = uint32(ir.OLE) << 16
+		OGE_    = uint32(ir.OGE) << 16
+		OGT_    = uint32(ir.OGT) << 16
+		OCMP_   = uint32(ir.OCMP) << 16
+		OAS_    = uint32(ir.OAS) << 16
+		OHMUL_  = uint32(ir.OHMUL) << 16
 	)
 
 	a := obj.AXXX
@@ -717,213 +718,213 @@ func optoas(op gc.Op, t *gc.Type) obj.As {
 	default:
 		gc.Fatalf("optoas: no entry for op=%v type=%v", gc.Oconv(op, 0), t)
 
-	case OEQ_ | gc.TBOOL,
-		OEQ_ | gc.TINT8,
-		OEQ_ | gc.TUINT8,
-		OEQ_ | gc.TINT16,
-		OEQ_ | gc.TUINT16,
-		OEQ_ | gc.TINT32,
-		OEQ_ | gc.TUINT32,
-		OEQ_ | gc.TINT64,
-		OEQ_ | gc.TUINT64,
-		OEQ_ | gc.TPTR32,
-		OEQ_ | gc.TPTR64:
+	case OEQ_ | ir.TBOOL,
+		OEQ_ | ir.TINT8,
+		OEQ_ | ir.TUINT8,
+		OEQ_ | ir.TINT16,
+		OEQ_ | ir.TUINT16,
+		OEQ_ | ir.TINT32,
+		OEQ_ | ir.TUINT32,
+		OEQ_ | ir.TINT64,
+		OEQ_ | ir.TUINT64,
+		OEQ_ | ir.TPTR32,
+		OEQ_ | ir.TPTR64:
 		a = mips.ABEQ
 
-	case OEQ_ | gc.TFLOAT32, // ACMPEQF
-		OEQ_ | gc.TFLOAT64: // ACMPEQD
+	case OEQ_ | ir.TFLOAT32, // ACMPEQF
+		OEQ_ | ir.TFLOAT64: // ACMPEQD
 		a = mips.ABFPT
 
-	case ONE_ | gc.TBOOL,
-		ONE_ | gc.TINT8,
-		ONE_ | gc.TUINT8,
-		ONE_ | gc.TINT16,
-		ONE_ | gc.TUINT16,
-		ONE_ | gc.TINT32,
-		ONE_ | gc.TUINT32,
-		ONE_ | gc.TINT64,
-		ONE_ | gc.TUINT64,
-		ONE_ | gc.TPTR32,
-		ONE_ | gc.TPTR64:
+	case ONE_ | ir.TBOOL,
+		ONE_ | ir.TINT8,
+		ONE_ | ir.TUINT8,
+		ONE_ | ir.TINT16,
+		ONE_ | ir.TUINT16,
+		ONE_ | ir.TINT32,
+		ONE_ | ir.TUINT32,
+		ONE_ | ir.TINT64,
+		ONE_ | ir.TUINT64,
+		ONE_ | ir.TPTR32,
+		ONE_ | ir.TPTR64:
 		a = mips.ABNE
 
-	case ONE_ | gc.TFLOAT32, // ACMPEQF
-		ONE_ | gc.TFLOAT64: // ACMPEQD
+	case ONE_ | ir.TFLOAT32, // ACMPEQF
+		ONE_ | ir.TFLOAT64: // ACMPEQD
 		a = mips.ABFPF
 
-	case OLT_ | gc.TINT8, // ASGT
-		OLT_ | gc.TINT16,
-		OLT_ | gc.TINT32,
-		OLT_ | gc.TINT64,
-		OLT_ | gc.TUINT8, // ASGTU
-		OLT_ | gc.TUINT16,
-		OLT_ | gc.TUINT32,
-		OLT_ | gc.TUINT64:
+	case OLT_ | ir.TINT8, // ASGT
+		OLT_ | ir.TINT16,
+		OLT_ | ir.TINT32,
+		OLT_ | ir.TINT64,
+		OLT_ | ir.TUINT8, // ASGTU
+		OLT_ | ir.TUINT16,
+		OLT_ | ir.TUINT32,
+		OLT_ | ir.TUINT64:
 		a = mips.ABNE
 
-	case OLT_ | gc.TFLOAT32, // ACMPGEF
-		OLT_ | gc.TFLOAT64: // ACMPGED
+	case OLT_ | ir.TFLOAT32, // ACMPGEF
+		OLT_ | ir.TFLOAT64: // ACMPGED
 		a = mips.ABFPT
 
-	case OLE_ | gc.TINT8, // ASGT
-		OLE_ | gc.TINT16,
-		OLE_ | gc.TINT32,
-		OLE_ | gc.TINT64,
-		OLE_ | gc.TUINT8, // ASGTU
-		OLE_ | gc.TUINT16,
-		OLE_ | gc.TUINT32,
-		OLE_ | gc.TUINT64:
+	case OLE_ | ir.TINT8, // ASGT
+		OLE_ | ir.TINT16,
+		OLE_ | ir.TINT32,
+		OLE_ | ir.TINT64,
+		OLE_ | ir.TUINT8, // ASGTU
+		OLE_ | ir.TUINT16,
+		OLE_ | ir.TUINT32,
+		OLE_ | ir.TUINT64:
 		a = mips.ABEQ
 
-	case OLE_ | gc.TFLOAT32, // ACMPGTF
-		OLE_ | gc.TFLOAT64: // ACMPGTD
+	case OLE_ | ir.TFLOAT32, // ACMPGTF
+		OLE_ | ir.TFLOAT64: // ACMPGTD
 		a = mips.ABFPT
 
-	case OGT_ | gc.TINT8, // ASGT
-		OGT_ | gc.TINT16,
-		OGT_ | gc.TINT32,
-		OGT_ | gc.TINT64,
-		OGT_ | gc.TUINT8, // ASGTU
-		OGT_ | gc.TUINT16,
-		OGT_ | gc.TUINT32,
-		OGT_ | gc.TUINT64:
+	case OGT_ | ir.TINT8, // ASGT
+		OGT_ | ir.TINT16,
+		OGT_ | ir.TINT32,
+		OGT_ | ir.TINT64,
+		OGT_ | ir.TUINT8, // ASGTU
+		OGT_ | ir.TUINT16,
+		OGT_ | ir.TUINT32,
+		OGT_ | ir.TUINT64:
 		a = mips.ABNE
 
-	case OGT_ | gc.TFLOAT32, // ACMPGTF
-		OGT_ | gc.TFLOAT64: // ACMPGTD
+	case OGT_ | ir.TFLOAT32, // ACMPGTF
+		OGT_ | ir.TFLOAT64: // ACMPGTD
 		a = mips.ABFPT
 
-	case OGE_ | gc.TINT8, // ASGT
-		OGE_ | gc.TINT16,
-		OGE_ | gc.TINT32,
-		OGE_ | gc.TINT64,
-		OGE_ | gc.TUINT8, // ASGTU
-		OGE_ | gc.TUINT16,
-		OGE_ | gc.TUINT32,
-		OGE_ | gc.TUINT64:
+	case OGE_ | ir.TINT8, // ASGT
+		OGE_ | ir.TINT16,
+		OGE_ | ir.TINT32,
+		OGE_ | ir.TINT64,
+		OGE_ | ir.TUINT8, // ASGTU
+		OGE_ | ir.TUINT16,
+		OGE_ | ir.TUINT32,
+		OGE_ | ir.TUINT64:
 		a = mips.ABEQ
 
-	case OGE_ | gc.TFLOAT32, // ACMPGEF
-		OGE_ | gc.TFLOAT64: // ACMPGED
+	case OGE_ | ir.TFLOAT32, // ACMPGEF
+		OGE_ | ir.TFLOAT64: // ACMPGED
 		a = mips.ABFPT
 
-	case OAS_ | gc.TBOOL,
-		OAS_ | gc.TINT8:
+	case OAS_ | ir.TBOOL,
+		OAS_ | ir.TINT8:
 		a = mips.AMOVB
 
-	case OAS_ | gc.TUINT8:
+	case OAS_ | ir.TUINT8:
 		a = mips.AMOVBU
 
-	case OAS_ | gc.TINT16:
+	case OAS_ | ir.TINT16:
 		a = mips.AMOVH
 
-	case OAS_ | gc.TUINT16:
+	case OAS_ | ir.TUINT16:
 		a = mips.AMOVHU
 
-	case OAS_ | gc.TINT32:
+	case OAS_ | ir.TINT32:
 		a = mips.AMOVW
 
-	case OAS_ | gc.TUINT32,
-		OAS_ | gc.TPTR32:
+	case OAS_ | ir.TUINT32,
+		OAS_ | ir.TPTR32:
 		a = mips.AMOVWU
 
-	case OAS_ | gc.TINT64,
-		OAS_ | gc.TUINT64,
-		OAS_ | gc.TPTR64:
+	case OAS_ | ir.TINT64,
+		OAS_ | ir.TUINT64,
+		OAS_ | ir.TPTR64:
 		a = mips.AMOVV
 
-	case OAS_ | gc.TFLOAT32:
+	case OAS_ | ir.TFLOAT32:
 		a = mips.AMOVF
 
-	case OAS_ | gc.TFLOAT64:
+	case OAS_ | ir.TFLOAT64:
 		a = mips.AMOVD
 
-	case OADD_ | gc.TINT8,
-		OADD_ | gc.TUINT8,
-		OADD_ | gc.TINT16,
-		OADD_ | gc.TUINT16,
-		OADD_ | gc.TINT32,
-		OADD_ | gc.TUINT32,
-		OADD_ | gc.TPTR32:
+	case OADD_ | ir.TINT8,
+		OADD_ | ir.TUINT8,
+		OADD_ | ir.TINT16,
+		OADD_ | ir.TUINT16,
+		OADD_ | ir.TINT32,
+		OADD_ | ir.TUINT32,
+		OADD_ | ir.TPTR32:
 		a = mips.AADDU
 
-	case OADD_ | gc.TINT64,
-		OADD_ | gc.TUINT64,
-		OADD_ | gc.TPTR64:
+	case OADD_ | ir.TINT64,
+		OADD_ | ir.TUINT64,
+		OADD_ | ir.TPTR64:
 		a = mips.AADDVU
 
-	case OADD_ | gc.TFLOAT32:
+	case OADD_ | ir.TFLOAT32:
 		a = mips.AADDF
 
-	case OADD_ | gc.TFLOAT64:
+	case OADD_ | ir.TFLOAT64:
 		a = mips.AADDD
 
-	case OSUB_ | gc.TINT8,
-		OSUB_ | gc.TUINT8,
-		OSUB_ | gc.TINT16,
-		OSUB_ | gc.TUINT16,
-		OSUB_ | gc.TINT32,
-		OSUB_ | gc.TUINT32,
-		OSUB_ | gc.TPTR32:
+	case OSUB_ | ir.TINT8,
+		OSUB_ | ir.TUINT8,
+		OSUB_ | ir.TINT16,
+		OSUB_ | ir.TUINT16,
+		OSUB_ | ir.TINT32,
+		OSUB_ | ir.TUINT32,
+		OSUB_ | ir.TPTR32:
 		a = mips.ASUBU
 
-	case OSUB_ | gc.TINT64,
-		OSUB_ | gc.TUINT64,
-		OSUB_ | gc.TPTR64:
+	case OSUB_ | ir.TINT64,
+		OSUB_ | ir.TUINT64,
+		OSUB_ | ir.TPTR64:
 		a = mips.ASUBVU
 
-	case OSUB_ | gc.TFLOAT32:
+	case OSUB_ | ir.TFLOAT32:
 		a = mips.ASUBF
 
-	case OSUB_ | gc.TFLOAT64:
+	case OSUB_ | ir.TFLOAT64:
 		a = mips.ASUBD
 
-	case OMINUS_ | gc.TINT8,
-		OMINUS_ | gc.TUINT8,
-		OMINUS_ | gc.TINT16,
-		OMINUS_ | gc.TUINT16,
-		OMINUS_ | gc.TINT32,
-		OMINUS_ | gc.TUINT32,
-		OMINUS_ | gc.TPTR32,
-		OMINUS_ | gc.TINT64,
-		OMINUS_ | gc.TUINT64,
-		OMINUS_ | gc.TPTR64:
+	case OMINUS_ | ir.TINT8,
+		OMINUS_ | ir.TUINT8,
+		OMINUS_ | ir.TINT16,
+		OMINUS_ | ir.TUINT16,
+		OMINUS_ | ir.TINT32,
+		OMINUS_ | ir.TUINT32,
+		OMINUS_ | ir.TPTR32,
+		OMINUS_ | ir.TINT64,
+		OMINUS_ | ir.TUINT64,
+		OMINUS_ | ir.TPTR64:
 		a = mips.ASUBVU
 
-	case OAND_ | gc.TINT8,
-		OAND_ | gc.TUINT8,
-		OAND_ | gc.TINT16,
-		OAND_ | gc.TUINT16,
-		OAND_ | gc.TINT32,
-		OAND_ | gc.TUINT32,
-		OAND_ | gc.TPTR32,
-		OAND_ | gc.TINT64,
-		OAND_ | gc.TUINT64,
-		OAND_ | gc.TPTR64:
+	case OAND_ | ir.TINT8,
+		OAND_ | ir.TUINT8,
+		OAND_ | ir.TINT16,
+		OAND_ | ir.TUINT16,
+		OAND_ | ir.TINT32,
+		OAND_ | ir.TUINT32,
+		OAND_ | ir.TPTR32,
+		OAND_ | ir.TINT64,
+		OAND_ | ir.TUINT64,
+		OAND_ | ir.TPTR64:
 		a = mips.AAND
 
-	case OOR_ | gc.TINT8,
-		OOR_ | gc.TUINT8,
-		OOR_ | gc.TINT16,
-		OOR_ | gc.TUINT16,
-		OOR_ | gc.TINT32,
-		OOR_ | gc.TUINT32,
-		OOR_ | gc.TPTR32,
-		OOR_ | gc.TINT64,
-		OOR_ | gc.TUINT64,
-		OOR_ | gc.TPTR64:
+	case OOR_ | ir.TINT8,
+		OOR_ | ir.TUINT8,
+		OOR_ | ir.TINT16,
+		OOR_ | ir.TUINT16,
+		OOR_ | ir.TINT32,
+		OOR_ | ir.TUINT32,
+		OOR_ | ir.TPTR32,
+		OOR_ | ir.TINT64,
+		OOR_ | ir.TUINT64,
+		OOR_ | ir.TPTR64:
 		a = mips.AOR
 
-	case OXOR_ | gc.TINT8,
-		OXOR_ | gc.TUINT8,
-		OXOR_ | gc.TINT16,
-		OXOR_ | gc.TUINT16,
-		OXOR_ | gc.TINT32,
-		OXOR_ | gc.TUINT32,
-		OXOR_ | gc.TPTR32,
-		OXOR_ | gc.TINT64,
-		OXOR_ | gc.TUINT64,
-		OXOR_ | gc.TPTR64:
+	case OXOR_ | ir.TINT8,
+		OXOR_ | ir.TUINT8,
+		OXOR_ | ir.TINT16,
+		OXOR_ | ir.TUINT16,
+		OXOR_ | ir.TINT32,
+		OXOR_ | ir.TUINT32,
+		OXOR_ | ir.TPTR32,
+		OXOR_ | ir.TINT64,
+		OXOR_ | ir.TUINT64,
+		OXOR_ | ir.TPTR64:
 		a = mips.AXOR
 
 		// TODO(minux): handle rotates
@@ -940,30 +941,30 @@ func optoas(op gc.Op, t *gc.Type) obj.As {
 	//	a = 0//???; RLDC?
 	//	break;
 
-	case OLSH_ | gc.TINT8,
-		OLSH_ | gc.TUINT8,
-		OLSH_ | gc.TINT16,
-		OLSH_ | gc.TUINT16,
-		OLSH_ | gc.TINT32,
-		OLSH_ | gc.TUINT32,
-		OLSH_ | gc.TPTR32,
-		OLSH_ | gc.TINT64,
-		OLSH_ | gc.TUINT64,
-		OLSH_ | gc.TPTR64:
+	case OLSH_ | ir.TINT8,
+		OLSH_ | ir.TUINT8,
+		OLSH_ | ir.TINT16,
+		OLSH_ | ir.TUINT16,
+		OLSH_ | ir.TINT32,
+		OLSH_ | ir.TUINT32,
+		OLSH_ | ir.TPTR32,
+		OLSH_ | ir.TINT64,
+		OLSH_ | ir.TUINT64,
+		OLSH_ | ir.TPTR64:
 		a = mips.ASLLV
 
-	case ORSH_ | gc.TUINT8,
-		ORSH_ | gc.TUINT16,
-		ORSH_ | gc.TUINT32,
-		ORSH_ | gc.TPTR32,
-		ORSH_ | gc.TUINT64,
-		ORSH_ | gc.TPTR64:
+	case ORSH_ | ir.TUINT8,
+		ORSH_ | ir.TUINT16,
+		ORSH_ | ir.TUINT32,
+		ORSH_ | ir.TPTR32,
+		ORSH_ | ir.TUINT64,
+		ORSH_ | ir.TPTR64:
 		a = mips.ASRLV
 
-	case ORSH_ | gc.TINT8,
-		ORSH_ | gc.TINT16,
-		ORSH_ | gc.TINT32,
-		ORSH_ | gc.TINT64:
+	case ORSH_ | ir.TINT8,
+		ORSH_ | ir.TINT16,
+		ORSH_ | ir.TINT32,
+		ORSH_ | ir.TINT64:
 		a = mips.ASRAV
 
 		// TODO(minux): handle rotates
@@ -978,51 +979,51 @@ func optoas(op gc.Op, t *gc.Type) obj.As {
 	//	a = 0//??? RLDC??
 	//	break;
 
-	case OHMUL_ | gc.TINT64:
+	case OHMUL_ | ir.TINT64:
 		a = mips.AMULV
 
-	case OHMUL_ | gc.TUINT64,
-		OHMUL_ | gc.TPTR64:
+	case OHMUL_ | ir.TUINT64,
+		OHMUL_ | ir.TPTR64:
 		a = mips.AMULVU
 
-	case OMUL_ | gc.TINT8,
-		OMUL_ | gc.TINT16,
-		OMUL_ | gc.TINT32,
-		OMUL_ | gc.TINT64:
+	case OMUL_ | ir.TINT8,
+		OMUL_ | ir.TINT16,
+		OMUL_ | ir.TINT32,
+		OMUL_ | ir.TINT64:
 		a = mips.AMULV
 
-	case OMUL_ | gc.TUINT8,
-		OMUL_ | gc.TUINT16,
-		OMUL_ | gc.TUINT32,
-		OMUL_ | gc.TPTR32,
-		OMUL_ | gc.TUINT64,
-		OMUL_ | gc.TPTR64:
+	case OMUL_ | ir.TUINT8,
+		OMUL_ | ir.TUINT16,
+		OMUL_ | ir.TUINT32,
+		OMUL_ | ir.TPTR32,
+		OMUL_ | ir.TUINT64,
+		OMUL_ | ir.TPTR64:
 		a = mips.AMULVU
 
-	case OMUL_ | gc.TFLOAT32:
+	case OMUL_ | ir.TFLOAT32:
 		a = mips.AMULF
 
-	case OMUL_ | gc.TFLOAT64:
+	case OMUL_ | ir.TFLOAT64:
 		a = mips.AMULD
 
-	case ODIV_ | gc.TINT8,
-		ODIV_ | gc.TINT16,
-		ODIV_ | gc.TINT32,
-		ODIV_ | gc.TINT64:
+	case ODIV_ | ir.TINT8,
+		ODIV_ | ir.TINT16,
+		ODIV_ | ir.TINT32,
+		ODIV_ | ir.TINT64:
 		a = mips.ADIVV
 
-	case ODIV_ | gc.TUINT8,
-		ODIV_ | gc.TUINT16,
-		ODIV_ | gc.TUINT32,
-		ODIV_ | gc.TPTR32,
-		ODIV_ | gc.TUINT64,
-		ODIV_ | gc.TPTR64:
+	case ODIV_ | ir.TUINT8,
+		ODIV_ | ir.TUINT16,
+		ODIV_ | ir.TUINT32,
+		ODIV_ | ir.TPTR32,
+		ODIV_ | ir.TUINT64,
+		ODIV_ | ir.TPTR64:
 		a = mips.ADIVVU
 
-	case ODIV_ | gc.TFLOAT32:
+	case ODIV_ | ir.TFLOAT32:
 		a = mips.ADIVF
 
-	case ODIV_ | gc.TFLOAT64:
+	case ODIV_ | ir.TFLOAT64:
 		a = mips.ADIVD
 	}
 
@@ -1034,7 +1035,7 @@ const (
 	OAddable = 1 << 1
 )
 
-func xgen(n *gc.Node, a *gc.Node, o int) bool {
+func xgen(n *ir.Node, a *ir.Node, o int) bool {
 	// TODO(minux)
 
 	return -1 != 0 /*TypeKind(100016)*/
@@ -1055,7 +1056,7 @@ func sudoclean() {
  * after successful sudoaddable,
  * to release the register used for a.
  */
-func sudoaddable(as obj.As, n *gc.Node, a *obj.Addr) bool {
+func sudoaddable(as obj.As, n *ir.Node, a *obj.Addr) bool {
 	// TODO(minux)
 
 	*a = obj.Addr{}
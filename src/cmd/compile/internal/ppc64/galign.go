@@ -10,7 +10,15 @@ import (
 	"cmd/internal/obj/ppc64"
 )
 
-func betypeinit() {
+func init() {
+	gc.RegisterBackend("ppc64", backend{})
+	gc.RegisterBackend("ppc64le", backend{})
+}
+
+// backend is the ppc64 back end; see gc.Backend.
+type backend struct{}
+
+func (backend) TypeInit() {
 	gc.Widthptr = 8
 	gc.Widthint = 8
 	gc.Widthreg = 8
@@ -21,61 +29,62 @@ func betypeinit() {
 	}
 }
 
-func Main() {
-	gc.Thearch.Thechar = '9'
-	gc.Thearch.Thestring = "ppc64"
-	gc.Thearch.Thelinkarch = &ppc64.Linkppc64
+func (backend) Init(arch *gc.Arch) {
+	arch.Thechar = '9'
+	arch.Thestring = "ppc64"
+	arch.Thelinkarch = &ppc64.Linkppc64
 	if obj.Getgoarch() == "ppc64le" {
-		gc.Thearch.Thestring = "ppc64le"
-		gc.Thearch.Thelinkarch = &ppc64.Linkppc64le
+		arch.Thestring = "ppc64le"
+		arch.Thelinkarch = &ppc64.Linkppc64le
 	}
-	gc.Thearch.REGSP = ppc64.REGSP
-	gc.Thearch.REGCTXT = ppc64.REGCTXT
-	gc.Thearch.REGCALLX = ppc64.REG_R3
-	gc.Thearch.REGCALLX2 = ppc64.REG_R4
-	gc.Thearch.REGRETURN = ppc64.REG_R3
-	gc.Thearch.REGMIN = ppc64.REG_R0
-	gc.Thearch.REGMAX = ppc64.REG_R31
-	gc.Thearch.FREGMIN = ppc64.REG_F0
-	gc.Thearch.FREGMAX = ppc64.REG_F31
-	gc.Thearch.MAXWIDTH = 1 << 50
-	gc.Thearch.ReservedRegs = resvd
+	arch.REGSP = ppc64.REGSP
+	arch.REGCTXT = ppc64.REGCTXT
+	arch.REGCALLX = ppc64.REG_R3
+	arch.REGCALLX2 = ppc64.REG_R4
+	arch.REGRETURN = ppc64.REG_R3
+	arch.REGMIN = ppc64.REG_R0
+	arch.REGMAX = ppc64.REG_R31
+	arch.FREGMIN = ppc64.REG_F0
+	arch.FREGMAX = ppc64.REG_F31
+	arch.MAXWIDTH = 1 << 50
+	arch.ReservedRegs = resvd
+	arch.Duffzero = gc.DuffRange{Min: 4, Max: 128}
 
-	gc.Thearch.Betypeinit = betypeinit
-	gc.Thearch.Cgen_hmul = cgen_hmul
-	gc.Thearch.Cgen_shift = cgen_shift
-	gc.Thearch.Clearfat = clearfat
-	gc.Thearch.Defframe = defframe
-	gc.Thearch.Dodiv = dodiv
-	gc.Thearch.Excise = excise
-	gc.Thearch.Expandchecks = expandchecks
-	gc.Thearch.Getg = getg
-	gc.Thearch.Gins = gins
-	gc.Thearch.Ginscmp = ginscmp
-	gc.Thearch.Ginscon = ginscon
-	gc.Thearch.Ginsnop = ginsnop
-	gc.Thearch.Gmove = gmove
-	gc.Thearch.Peep = peep
-	gc.Thearch.Proginfo = proginfo
-	gc.Thearch.Regtyp = regtyp
-	gc.Thearch.Sameaddr = sameaddr
-	gc.Thearch.Smallindir = smallindir
-	gc.Thearch.Stackaddr = stackaddr
-	gc.Thearch.Blockcopy = blockcopy
-	gc.Thearch.Sudoaddable = sudoaddable
-	gc.Thearch.Sudoclean = sudoclean
-	gc.Thearch.Excludedregs = excludedregs
-	gc.Thearch.RtoB = RtoB
-	gc.Thearch.FtoB = RtoB
-	gc.Thearch.BtoR = BtoR
-	gc.Thearch.BtoF = BtoF
-	gc.Thearch.Optoas = optoas
-	gc.Thearch.Doregbits = doregbits
-	gc.Thearch.Regnames = regnames
+	arch.Cgen_hmul = cgen_hmul
+	arch.Cgen_shift = cgen_shift
+	arch.Copyas = copyas
+	arch.Copyu = copyu
+	arch.Clearfat = clearfat
+	arch.Dodiv = dodiv
+	arch.Expandchecks = expandchecks
+	arch.Getg = getg
+	arch.Gins = gins
+	arch.Ginscmp = ginscmp
+	arch.Ginscon = ginscon
+	arch.Ginsnop = ginsnop
+	arch.Gmove = gmove
+	arch.Proginfo = proginfo
+	arch.Regtyp = regtyp
+	arch.Sameaddr = sameaddr
+	arch.Smallindir = smallindir
+	arch.Stackaddr = stackaddr
+	arch.Blockcopy = blockcopy
+	arch.Sudoaddable = sudoaddable
+	arch.Sudoclean = sudoclean
+	arch.Excludedregs = excludedregs
+	arch.RtoB = RtoB
+	arch.FtoB = RtoB
+	arch.BtoR = BtoR
+	arch.BtoF = BtoF
+	arch.Optoas = optoas
+	arch.Doregbits = doregbits
+	arch.Regnames = regnames
+	arch.ZeroRange = zerorange
 
 	initvariants()
 	initproginfo()
+}
 
-	gc.Main()
-	gc.Exit(0)
+func (backend) Peep(firstp *obj.Prog) {
+	peep(firstp)
 }
@@ -6,116 +6,53 @@ package ppc64
 
 import (
 	"cmd/compile/internal/gc"
+	"cmd/compile/internal/ir"
 	"cmd/internal/obj"
 	"cmd/internal/obj/ppc64"
 	"fmt"
 )
 
-func defframe(ptxt *obj.Prog) {
-	// fill in argument size, stack size
-	ptxt.To.Type = obj.TYPE_TEXTSIZE
-
-	ptxt.To.Val = int32(gc.Rnd(gc.Curfn.Type.Argwid, int64(gc.Widthptr)))
-	frame := uint32(gc.Rnd(gc.Stksize+gc.Maxarg, int64(gc.Widthreg)))
-	ptxt.To.Offset = int64(frame)
-
-	// insert code to zero ambiguously live variables
-	// so that the garbage collector only sees initialized values
-	// when it looks for pointers.
-	p := ptxt
-
-	hi := int64(0)
-	lo := hi
-
-	// iterate through declarations - they are sorted in decreasing xoffset order.
-	for _, n := range gc.Curfn.Func.Dcl {
-		if !n.Name.Needzero {
-			continue
-		}
-		if n.Class != gc.PAUTO {
-			gc.Fatalf("needzero class %d", n.Class)
-		}
-		if n.Type.Width%int64(gc.Widthptr) != 0 || n.Xoffset%int64(gc.Widthptr) != 0 || n.Type.Width == 0 {
-			gc.Fatalf("var %v has size %d offset %d", gc.Nconv(n, gc.FmtLong), int(n.Type.Width), int(n.Xoffset))
-		}
-
-		if lo != hi && n.Xoffset+n.Type.Width >= lo-int64(2*gc.Widthreg) {
-			// merge with range we already have
-			lo = n.Xoffset
-
-			continue
-		}
-
-		// zero old range
-		p = zerorange(p, int64(frame), lo, hi)
-
-		// set new range
-		hi = n.Xoffset + n.Type.Width
-
-		lo = n.Xoffset
-	}
-
-	// zero final range
-	zerorange(p, int64(frame), lo, hi)
-}
-
-func zerorange(p *obj.Prog, frame int64, lo int64, hi int64) *obj.Prog {
+func zerorange(p *obj.Prog, frame int64, lo int64, hi int64, state *uint32) *obj.Prog {
 	cnt := hi - lo
 	if cnt == 0 {
 		return p
 	}
 	if cnt < int64(4*gc.Widthptr) {
 		for i := int64(0); i < cnt; i += int64(gc.Widthptr) {
-			p = appendpp(p, ppc64.AMOVD, obj.TYPE_REG, ppc64.REGZERO, 0, obj.TYPE_MEM, ppc64.REGSP, gc.Ctxt.FixedFrameSize()+frame+lo+i)
+			p = gc.Appendpp(p, ppc64.AMOVD, obj.TYPE_REG, ppc64.REGZERO, 0, obj.TYPE_MEM, ppc64.REGSP, gc.Ctxt.FixedFrameSize()+frame+lo+i)
 		}
 	} else if cnt <= int64(128*gc.Widthptr) {
-		p = appendpp(p, ppc64.AADD, obj.TYPE_CONST, 0, gc.Ctxt.FixedFrameSize()+frame+lo-8, obj.TYPE_REG, ppc64.REGRT1, 0)
+		p = gc.Appendpp(p, ppc64.AADD, obj.TYPE_CONST, 0, gc.Ctxt.FixedFrameSize()+frame+lo-8, obj.TYPE_REG, ppc64.REGRT1, 0)
 		p.Reg = ppc64.REGSP
-		p = appendpp(p, obj.ADUFFZERO, obj.TYPE_NONE, 0, 0, obj.TYPE_MEM, 0, 0)
+		p = gc.Appendpp(p, obj.ADUFFZERO, obj.TYPE_NONE, 0, 0, obj.TYPE_MEM, 0, 0)
 		f := gc.Sysfunc("duffzero")
 		gc.Naddr(&p.To, f)
 		gc.Afunclit(&p.To, f)
 		p.To.Offset = 4 * (128 - cnt/int64(gc.Widthptr))
 	} else {
-		p = appendpp(p, ppc64.AMOVD, obj.TYPE_CONST, 0, gc.Ctxt.FixedFrameSize()+frame+lo-8, obj.TYPE_REG, ppc64.REGTMP, 0)
-		p = appendpp(p, ppc64.AADD, obj.TYPE_REG, ppc64.REGTMP, 0, obj.TYPE_REG, ppc64.REGRT1, 0)
+		p = gc.Appendpp(p, ppc64.AMOVD, obj.TYPE_CONST, 0, gc.Ctxt.FixedFrameSize()+frame+lo-8, obj.TYPE_REG, ppc64.REGTMP, 0)
+		p = gc.Appendpp(p, ppc64.AADD, obj.TYPE_REG, ppc64.REGTMP, 0, obj.TYPE_REG, ppc64.REGRT1, 0)
 		p.Reg = ppc64.REGSP
-		p = appendpp(p, ppc64.AMOVD, obj.TYPE_CONST, 0, cnt, obj.TYPE_REG, ppc64.REGTMP, 0)
-		p = appendpp(p, ppc64.AADD, obj.TYPE_REG, ppc64.REGTMP, 0, obj.TYPE_REG, ppc64.REGRT2, 0)
+		p = gc.Appendpp(p, ppc64.AMOVD, obj.TYPE_CONST, 0, cnt, obj.TYPE_REG, ppc64.REGTMP, 0)
+		p = gc.Appendpp(p, ppc64.AADD, obj.TYPE_REG, ppc64.REGTMP, 0, obj.TYPE_REG, ppc64.REGRT2, 0)
 		p.Reg = ppc64.REGRT1
-		p = appendpp(p, ppc64.AMOVDU, obj.TYPE_REG, ppc64.REGZERO, 0, obj.TYPE_MEM, ppc64.REGRT1, int64(gc.Widthptr))
+		p = gc.Appendpp(p, ppc64.AMOVDU, obj.TYPE_REG, ppc64.REGZERO, 0, obj.TYPE_MEM, ppc64.REGRT1, int64(gc.Widthptr))
 		p1 := p
-		p = appendpp(p, ppc64.ACMP, obj.TYPE_REG, ppc64.REGRT1, 0, obj.TYPE_REG, ppc64.REGRT2, 0)
-		p = appendpp(p, ppc64.ABNE, obj.TYPE_NONE, 0, 0, obj.TYPE_BRANCH, 0, 0)
+		p = gc.Appendpp(p, ppc64.ACMP, obj.TYPE_REG, ppc64.REGRT1, 0, obj.TYPE_REG, ppc64.REGRT2, 0)
+		p = gc.Appendpp(p, ppc64.ABNE, obj.TYPE_NONE, 0, 0, obj.TYPE_BRANCH, 0, 0)
 		gc.Patch(p, p1)
 	}
 
 	return p
 }
 
-func appendpp(p *obj.Prog, as obj.As, ftype obj.AddrType, freg int, foffset int64, ttype obj.AddrType, treg int, toffset int64) *obj.Prog {
-	q := gc.Ctxt.NewProg()
-	gc.Clearp(q)
-	q.As = as
-	q.Lineno = p.Lineno
-	q.From.Type = ftype
-	q.From.Reg = int16(freg)
-	q.From.Offset = foffset
-	q.To.Type = ttype
-	q.To.Reg = int16(treg)
-	q.To.Offset = toffset
-	q.Link = p.Link
-	p.Link = q
-	return q
-}
-
 func ginsnop() {
-	var reg gc.Node
-	gc.Nodreg(&reg, gc.Types[gc.TINT], ppc64.REG_R0)
+	var reg ir.Node
+	gc.Nodreg(&reg, ir.Types[ir.TINT], ppc64.REG_R0)
 	gins(ppc64.AOR, &reg, &reg)
 }
 
-var panicdiv *gc.Node
+var panicdiv *ir.Node
 
 /*
  * generate division.
@@ -124,7 +61,7 @@ var panicdiv *gc.Node
  *	res = nl % nr
  * according to op.
  */
-func dodiv(op gc.Op, nl *gc.Node, nr *gc.Node, res *gc.Node) {
+func dodiv(op ir.Op, nl *ir.Node, nr *ir.Node, res *ir.Node) {
 	// Have to be careful about handling
 	// most negative int divided by -1 correctly.
 	// The hardware will generate undefined result.
@@ -138,27 +75,27 @@ func dodiv(op gc.Op, nl *gc.Node, nr *gc.Node, res *gc.Node) {
 	check := false
 	if gc.Issigned[t.Etype] {
 		check = true
-		if gc.Isconst(nl, gc.CTINT) && nl.Int() != -(1<<uint64(t.Width*8-1)) {
+		if ir.Isconst(nl, ir.CTINT) && nl.Int() != -(1<<uint64(t.Width*8-1)) {
 			check = false
-		} else if gc.Isconst(nr, gc.CTINT) && nr.Int() != -1 {
+		} else if ir.Isconst(nr, ir.CTINT) && nr.Int() != -1 {
 			check = false
 		}
 	}
 
 	if t.Width < 8 {
 		if gc.Issigned[t.Etype] {
-			t = gc.Types[gc.TINT64]
+			t = ir.Types[ir.TINT64]
 		} else {
-			t = gc.Types[gc.TUINT64]
+			t = ir.Types[ir.TUINT64]
 		}
 		check = false
 	}
 
-	a := optoas(gc.ODIV, t)
+	a := optoas(ir.ODIV, t)
 
-	var tl gc.Node
+	var tl ir.Node
 	gc.Regalloc(&tl, t0, nil)
-	var tr gc.Node
+	var tr ir.Node
 	gc.Regalloc(&tr, t0, nil)
 	if nl.Ullman >= nr.Ullman {
 		gc.Cgen(nl, &tl)
@@ -180,11 +117,11 @@ func dodiv(op gc.Op, nl *gc.Node, nr *gc.Node, res *gc.Node) {
 	}
 
 	// Handle divide-by-zero panic.
-	p1 := gins(optoas(gc.OCMP, t), &tr, nil)
+	p1 := gins(optoas(ir.OCMP, t), &tr, nil)
 
 	p1.To.Type = obj.TYPE_REG
 	p1.To.Reg = ppc64.REGZERO
-	p1 = gc.Gbranch(optoas(gc.ONE, t), nil, +1)
+	p1 = gc.Gbranch(optoas(ir.ONE, t), nil, +1)
 	if panicdiv == nil {
 		panicdiv = gc.Sysfunc("panicdivide")
 	}
@@ -193,18 +130,18 @@ func dodiv(op gc.Op, nl *gc.Node, nr *gc.Node, res *gc.Node) {
 
 	var p2 *obj.Prog
 	if check {
-		var nm1 gc.Node
+		var nm1 ir.Node
 		gc.Nodconst(&nm1, t, -1)
-		gins(optoas(gc.OCMP, t), &tr, &nm1)
-		p1 := gc.Gbranch(optoas(gc.ONE, t), nil, +1)
-		if op == gc.ODIV {
+		gins(optoas(ir.OCMP, t), &tr, &nm1)
+		p1 := gc.Gbranch(optoas(ir.ONE, t), nil, +1)
+		if op == ir.ODIV {
 			// a / (-1) is -a.
-			gins(optoas(gc.OMINUS, t), nil, &tl)
+			gins(optoas(ir.OMINUS, t), nil, &tl)
 
 			gmove(&tl, res)
 		} else {
 			// a % (-1) is 0.
-			var nz gc.Node
+			var nz ir.Node
 			gc.Nodconst(&nz, t, 0)
 
 			gmove(&nz, res)
@@ -215,12 +152,12 @@ func dodiv(op gc.Op, nl *gc.Node, nr *gc.Node, res *gc.Node) {
 	}
 
 	p1 = gins(a, &tr, &tl)
-	if op == gc.ODIV {
+	if op == ir.ODIV {
 		gc.Regfree(&tr)
 		gmove(&tl, res)
 	} else {
 		// A%B = A-(A/B*B)
-		var tm gc.Node
+		var tm ir.Node
 		gc.Regalloc(&tm, t, nil)
 
 		// patch div to use the 3 register form
@@ -228,9 +165,9 @@ func dodiv(op gc.Op, nl *gc.Node, nr *gc.Node, res *gc.Node) {
 		p1.Reg = p1.To.Reg
 
 		p1.To.Reg = tm.Reg
-		gins(optoas(gc.OMUL, t), &tr, &tm)
+		gins(optoas(ir.OMUL, t), &tr, &tm)
 		gc.Regfree(&tr)
-		gins(optoas(gc.OSUB, t), &tm, &tl)
+		gins(optoas(ir.OSUB, t), &tm, &tl)
 		gc.Regfree(&tm)
 		gmove(&tl, res)
 	}
@@ -245,7 +182,7 @@ func dodiv(op gc.Op, nl *gc.Node, nr *gc.Node, res *gc.Node) {
  * generate high multiply:
  *   res = (nl*nr) >> width
  */
-func cgen_hmul(nl *gc.Node, nr *gc.Node, res *gc.Node) {
+func cgen_hmul(nl *ir.Node, nr *ir.Node, res *ir.Node) {
 	// largest ullman on left.
 	if nl.Ullman < nr.Ullman {
 		nl, nr = nr, nl
@@ -253,29 +190,29 @@ func cgen_hmul(nl *gc.Node, nr *gc.Node, res *gc.Node) {
 
 	t := nl.Type
 	w := t.Width * 8
-	var n1 gc.Node
+	var n1 ir.Node
 	gc.Cgenr(nl, &n1, res)
-	var n2 gc.Node
+	var n2 ir.Node
 	gc.Cgenr(nr, &n2, nil)
 	switch gc.Simtype[t.Etype] {
-	case gc.TINT8,
-		gc.TINT16,
-		gc.TINT32:
-		gins(optoas(gc.OMUL, t), &n2, &n1)
+	case ir.TINT8,
+		ir.TINT16,
+		ir.TINT32:
+		gins(optoas(ir.OMUL, t), &n2, &n1)
 		p := gins(ppc64.ASRAD, nil, &n1)
 		p.From.Type = obj.TYPE_CONST
 		p.From.Offset = w
 
-	case gc.TUINT8,
-		gc.TUINT16,
-		gc.TUINT32:
-		gins(optoas(gc.OMUL, t), &n2, &n1)
+	case ir.TUINT8,
+		ir.TUINT16,
+		ir.TUINT32:
+		gins(optoas(ir.OMUL, t), &n2, &n1)
 		p := gins(ppc64.ASRD, nil, &n1)
 		p.From.Type = obj.TYPE_CONST
 		p.From.Offset = w
 
-	case gc.TINT64,
-		gc.TUINT64:
+	case ir.TINT64,
+		ir.TUINT64:
 		if gc.Issigned[t.Etype] {
 			gins(ppc64.AMULHD, &n2, &n1)
 		} else {
@@ -296,18 +233,18 @@ func cgen_hmul(nl *gc.Node, nr *gc.Node, res *gc.Node) {
  *	res = nl << nr
  *	res = nl >> nr
  */
-func cgen_shift(op gc.Op, bounded bool, nl *gc.Node, nr *gc.Node, res *gc.Node) {
+func cgen_shift(op ir.Op, bounded bool, nl *ir.Node, nr *ir.Node, res *ir.Node) {
 	a := optoas(op, nl.Type)
 
-	if nr.Op == gc.OLITERAL {
-		var n1 gc.Node
+	if nr.Op == ir.OLITERAL {
+		var n1 ir.Node
 		gc.Regalloc(&n1, nl.Type, res)
 		gc.Cgen(nl, &n1)
 		sc := uint64(nr.Int())
 		if sc >= uint64(nl.Type.Width*8) {
 			// large shift gets 2 shifts by width-1
-			var n3 gc.Node
-			gc.Nodconst(&n3, gc.Types[gc.TUINT32], nl.Type.Width*8-1)
+			var n3 ir.Node
+			gc.Nodconst(&n3, ir.Types[ir.TUINT32], nl.Type.Width*8-1)
 
 			gins(a, &n3, &n1)
 			gins(a, &n3, &n1)
@@ -320,14 +257,14 @@ func cgen_shift(op gc.Op, bounded bool, nl *gc.Node, nr *gc.Node, res *gc.Node)
 	}
 
 	if nl.Ullman >= gc.UINF {
-		var n4 gc.Node
+		var n4 ir.Node
 		gc.Tempname(&n4, nl.Type)
 		gc.Cgen(nl, &n4)
 		nl = &n4
 	}
 
 	if nr.Ullman >= gc.UINF {
-		var n5 gc.Node
+		var n5 ir.Node
 		gc.Tempname(&n5, nr.Type)
 		gc.Cgen(nr, &n5)
 		nr = &n5
@@ -336,18 +273,18 @@ func cgen_shift(op gc.Op, bounded bool, nl *gc.Node, nr *gc.Node, res *gc.Node)
 	// Allow either uint32 or uint64 as shift type,
 	// to avoid unnecessary conversion from uint32 to uint64
 	// just to do the comparison.
-	tcount := gc.Types[gc.Simtype[nr.Type.Etype]]
+	tcount := ir.Types[gc.Simtype[nr.Type.Etype]]
 
-	if tcount.Etype < gc.TUINT32 {
-		tcount = gc.Types[gc.TUINT32]
+	if tcount.Etype < ir.TUINT32 {
+		tcount = ir.Types[ir.TUINT32]
 	}
 
-	var n1 gc.Node
+	var n1 ir.Node
 	gc.Regalloc(&n1, nr.Type, nil) // to hold the shift type in CX
-	var n3 gc.Node
+	var n3 ir.Node
 	gc.Regalloc(&n3, tcount, &n1) // to clear high bits of CX
 
-	var n2 gc.Node
+	var n2 ir.Node
 	gc.Regalloc(&n2, nl.Type, res)
 
 	if nl.Ullman >= nr.Ullman {
@@ -365,10 +302,10 @@ func cgen_shift(op gc.Op, bounded bool, nl *gc.Node, nr *gc.Node, res *gc.Node)
 	// test and fix up large shifts
 	if !bounded {
 		gc.Nodconst(&n3, tcount, nl.Type.Width*8)
-		gins(optoas(gc.OCMP, tcount), &n1, &n3)
-		p1 := gc.Gbranch(optoas(gc.OLT, tcount), nil, +1)
-		if op == gc.ORSH && gc.Issigned[nl.Type.Etype] {
-			gc.Nodconst(&n3, gc.Types[gc.TUINT32], nl.Type.Width*8-1)
+		gins(optoas(ir.OCMP, tcount), &n1, &n3)
+		p1 := gc.Gbranch(optoas(ir.OLT, tcount), nil, +1)
+		if op == ir.ORSH && gc.Issigned[nl.Type.Etype] {
+			gc.Nodconst(&n3, ir.Types[ir.TUINT32], nl.Type.Width*8-1)
 			gins(a, &n3, &n2)
 		} else {
 			gc.Nodconst(&n3, nl.Type, 0)
@@ -386,7 +323,7 @@ func cgen_shift(op gc.Op, bounded bool, nl *gc.Node, nr *gc.Node, res *gc.Node)
 	gc.Regfree(&n2)
 }
 
-func clearfat(nl *gc.Node) {
+func clearfat(nl *ir.Node) {
 	/* clear a fat object */
 	if gc.Debug['g'] != 0 {
 		fmt.Printf("clearfat %v (%v, size: %d)\n", nl, nl.Type, nl.Type.Width)
@@ -406,21 +343,21 @@ func clearfat(nl *gc.Node) {
 		gc.Fatalf("%v in use during clearfat", obj.Rconv(ppc64.REGRT1))
 	}
 
-	var r0 gc.Node
-	gc.Nodreg(&r0, gc.Types[gc.TUINT64], ppc64.REGZERO)
-	var dst gc.Node
-	gc.Nodreg(&dst, gc.Types[gc.Tptr], ppc64.REGRT1)
+	var r0 ir.Node
+	gc.Nodreg(&r0, ir.Types[ir.TUINT64], ppc64.REGZERO)
+	var dst ir.Node
+	gc.Nodreg(&dst, ir.Types[gc.Tptr], ppc64.REGRT1)
 	gc.Regrealloc(&dst)
 	gc.Agen(nl, &dst)
 
 	var boff uint64
-	if q > 128 {
+	if q > uint64(gc.Thearch.Duffzero.Max) {
 		p := gins(ppc64.ASUB, nil, &dst)
 		p.From.Type = obj.TYPE_CONST
 		p.From.Offset = 8
 
-		var end gc.Node
-		gc.Regalloc(&end, gc.Types[gc.Tptr], nil)
+		var end ir.Node
+		gc.Regalloc(&end, ir.Types[gc.Tptr], nil)
 		p = gins(ppc64.AMOVD, &dst, &end)
 		p.From.Type = obj.TYPE_ADDR
 		p.From.Offset = int64(q * 8)
@@ -437,7 +374,7 @@ func clearfat(nl *gc.Node) {
 
 		// The loop leaves R3 on the last zeroed dword
 		boff = 8
-	} else if q >= 4 {
+	} else if q >= uint64(gc.Thearch.Duffzero.Min) {
 		p := gins(ppc64.ASUB, nil, &dst)
 		p.From.Type = obj.TYPE_CONST
 		p.From.Offset = 8
@@ -546,8 +483,8 @@ func expandchecks(firstp *obj.Prog) {
 }
 
 // res = runtime.getg()
-func getg(res *gc.Node) {
-	var n1 gc.Node
+func getg(res *ir.Node) {
+	var n1 ir.Node
 	gc.Nodreg(&n1, res.Type, ppc64.REGG)
 	gmove(&n1, res)
 }
@@ -37,14 +37,11 @@ import (
 	"fmt"
 )
 
-var gactive uint32
-
 func peep(firstp *obj.Prog) {
 	g := gc.Flowstart(firstp, nil)
 	if g == nil {
 		return
 	}
-	gactive = 0
 
 	var p *obj.Prog
 	var r *gc.Flow
@@ -69,11 +66,11 @@ loop1:
 				// Try to eliminate reg->reg moves
 				if regtyp(&p.From) {
 					if p.From.Type == p.To.Type {
-						if copyprop(r) {
-							excise(r)
+						if gc.Copyprop(r) {
+							gc.Excise(r)
 							t++
-						} else if subprop(r) && copyprop(r) {
-							excise(r)
+						} else if subprop(r) && gc.Copyprop(r) {
+							gc.Excise(r)
 							t++
 						}
 					}
@@ -85,11 +82,11 @@ loop1:
 					if p.To.Type == obj.TYPE_REG {
 						p.From.Type = obj.TYPE_REG
 						p.From.Reg = ppc64.REGZERO
-						if copyprop(r) {
-							excise(r)
+						if gc.Copyprop(r) {
+							gc.Excise(r)
 							t++
-						} else if subprop(r) && copyprop(r) {
-							excise(r)
+						} else if subprop(r) && gc.Copyprop(r) {
+							gc.Excise(r)
 							t++
 						}
 					}
@@ -138,7 +135,7 @@ loop1:
 		if p1.To.Type != obj.TYPE_REG || p1.To.Reg != p.To.Reg {
 			continue
 		}
-		excise(r1)
+		gc.Excise(r1)
 	}
 
 	if gc.Debug['D'] > 1 {
@@ -338,7 +335,7 @@ loop1:
 			if gc.Debug['D'] != 0 {
 				fmt.Printf("%v\n", p1)
 			}
-			excise(r)
+			gc.Excise(r)
 			continue
 		}
 	}
@@ -347,15 +344,6 @@ ret:
 	gc.Flowend(g)
 }
 
-func excise(r *gc.Flow) {
-	p := r.Prog
-	if gc.Debug['P'] != 0 && gc.Debug['v'] != 0 {
-		fmt.Printf("%v ===delete===\n", p)
-	}
-	obj.Nopout(p)
-	gc.Ostats.Ndelmov++
-}
-
 // regzer returns true if a's value is 0 (a is R0 or $0)
 func regzer(a *obj.Addr) bool {
 	if a.Type == obj.TYPE_CONST || a.Type == obj.TYPE_ADDR {
@@ -454,133 +442,6 @@ func subprop(r0 *gc.Flow) bool {
 	return false
 }
 
-/*
- * The idea is to remove redundant copies.
- *	v1->v2	F=0
- *	(use v2	s/v2/v1/)*
- *	set v1	F=1
- *	use v2	return fail (v1->v2 move must remain)
- *	-----------------
- *	v1->v2	F=0
- *	(use v2	s/v2/v1/)*
- *	set v1	F=1
- *	set v2	return success (caller can remove v1->v2 move)
- */
-func copyprop(r0 *gc.Flow) bool {
-	p := r0.Prog
-	v1 := &p.From
-	v2 := &p.To
-	if copyas(v1, v2) {
-		if gc.Debug['P'] != 0 {
-			fmt.Printf("eliminating self-move: %v\n", r0.Prog)
-		}
-		return true
-	}
-
-	gactive++
-	if gc.Debug['P'] != 0 {
-		fmt.Printf("trying to eliminate %v->%v move from:\n%v\n", gc.Ctxt.Dconv(v1), gc.Ctxt.Dconv(v2), r0.Prog)
-	}
-	return copy1(v1, v2, r0.S1, false)
-}
-
-// copy1 replaces uses of v2 with v1 starting at r and returns 1 if
-// all uses were rewritten.
-func copy1(v1 *obj.Addr, v2 *obj.Addr, r *gc.Flow, f bool) bool {
-	if uint32(r.Active) == gactive {
-		if gc.Debug['P'] != 0 {
-			fmt.Printf("act set; return 1\n")
-		}
-		return true
-	}
-
-	r.Active = int32(gactive)
-	if gc.Debug['P'] != 0 {
-		fmt.Printf("copy1 replace %v with %v f=%v\n", gc.Ctxt.Dconv(v2), gc.Ctxt.Dconv(v1), f)
-	}
-	for ; r != nil; r = r.S1 {
-		p := r.Prog
-		if gc.Debug['P'] != 0 {
-			fmt.Printf("%v", p)
-		}
-		if !f && gc.Uniqp(r) == nil {
-			// Multiple predecessors; conservatively
-			// assume v1 was set on other path
-			f = true
-
-			if gc.Debug['P'] != 0 {
-				fmt.Printf("; merge; f=%v", f)
-			}
-		}
-
-		switch t := copyu(p, v2, nil); t {
-		case 2: /* rar, can't split */
-			if gc.Debug['P'] != 0 {
-				fmt.Printf("; %v rar; return 0\n", gc.Ctxt.Dconv(v2))
-			}
-			return false
-
-		case 3: /* set */
-			if gc.Debug['P'] != 0 {
-				fmt.Printf("; %v set; return 1\n", gc.Ctxt.Dconv(v2))
-			}
-			return true
-
-		case 1, /* used, substitute */
-			4: /* use and set */
-			if f {
-				if gc.Debug['P'] == 0 {
-					return false
-				}
-				if t == 4 {
-					fmt.Printf("; %v used+set and f=%v; return 0\n", gc.Ctxt.Dconv(v2), f)
-				} else {
-					fmt.Printf("; %v used and f=%v; return 0\n", gc.Ctxt.Dconv(v2), f)
-				}
-				return false
-			}
-
-			if copyu(p, v2, v1) != 0 {
-				if gc.Debug['P'] != 0 {
-					fmt.Printf("; sub fail; return 0\n")
-				}
-				return false
-			}
-
-			if gc.Debug['P'] != 0 {
-				fmt.Printf("; sub %v->%v\n => %v", gc.Ctxt.Dconv(v2), gc.Ctxt.Dconv(v1), p)
-			}
-			if t == 4 {
-				if gc.Debug['P'] != 0 {
-					fmt.Printf("; %v used+set; return 1\n", gc.Ctxt.Dconv(v2))
-				}
-				return true
-			}
-		}
-
-		if !f {
-			t := copyu(p, v1, nil)
-			if t == 2 || t == 3 || t == 4 {
-				f = true
-				if gc.Debug['P'] != 0 {
-					fmt.Printf("; %v set and !f; f=%v", gc.Ctxt.Dconv(v1), f)
-				}
-			}
-		}
-
-		if gc.Debug['P'] != 0 {
-			fmt.Printf("\n")
-		}
-		if r.S2 != nil {
-			if !copy1(v1, v2, r.S2, f) {
-				return false
-			}
-		}
-	}
-
-	return true
-}
-
 // If s==nil, copyu returns the set/use of v in p; otherwise, it
 // modifies p to replace reads of v with reads of s and returns 0 for
 // success or non-zero for failure.
@@ -26,6 +26,12 @@ type Config struct {
 	noDuffDevice bool                       // Don't use Duff's device
 	curFunc      *Func
 
+	// The smallest and largest blocks, in bytes, that lowering
+	// zeroes and copies with Duff's device. Smaller blocks are
+	// unrolled, larger ones use a loop.
+	DuffZeroMin, DuffZeroMax int64
+	DuffCopyMin, DuffCopyMax int64
+
 	// TODO: more stuff. Compiler flags of interest, ...
 
 	// Given an environment variable used for debug hash match,
@@ -118,6 +124,8 @@ func NewConfig(arch string, fe Frontend, ctxt *obj.Link, optimize bool) *Config
 		c.lowerBlock = rewriteBlockAMD64
 		c.lowerValue = rewriteValueAMD64
 		c.registers = registersAMD64[:]
+		c.DuffZeroMin, c.DuffZeroMax = 64, 1024
+		c.DuffCopyMin, c.DuffCopyMax = 128, 1024
 	case "386":
 		c.IntSize = 4
 		c.PtrSize = 4
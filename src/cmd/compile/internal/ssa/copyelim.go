@@ -44,11 +44,12 @@ func copyelimValue(v *Value) bool {
 		// Rewriting can generate OpCopy loops.
 		// They are harmless (see removePredecessor),
 		// but take care to stop if we find a cycle.
-		slow := a // advances every other iteration
+		w := a
+		slow := w // advances every other iteration
 		var advance bool
-		for a.Op == OpCopy {
-			a = a.Args[0]
-			if slow == a {
+		for w.Op == OpCopy {
+			w = w.Args[0]
+			if slow == w {
 				break
 			}
 			if advance {
@@ -56,8 +57,17 @@ func copyelimValue(v *Value) bool {
 			}
 			advance = !advance
 		}
-		v.SetArg(i, a)
+		v.SetArg(i, w)
 		changed = true
+
+		// Point the copies on the way at w too, so that
+		// the uses of a chain of n copies take O(n) time,
+		// not O(n²).
+		for a != w && a.Op == OpCopy {
+			x := a.Args[0]
+			a.SetArg(0, w)
+			a = x
+		}
 	}
 	return changed
 }
@@ -54,6 +54,13 @@ func postorder(f *Func) []*Block {
 
 type linkedBlocks func(*Block) []*Block
 
+// blockAndIndex is a block on the dfs stack, and the index of
+// its next successor to visit.
+type blockAndIndex struct {
+	b     *Block
+	index int
+}
+
 const nscratchslices = 8
 
 // experimentally, functions with 512 or fewer blocks account
@@ -112,28 +119,40 @@ func (f *Func) dfs(entries []*Block, succFn linkedBlocks, dfnum, order, parent [
 	}
 
 	n := ID(0)
-	s := make([]*Block, 0, 256)
+	s := make([]blockAndIndex, 0, 256)
 	for _, entry := range entries {
 		if dfnum[entry.ID] != notFound {
 			continue // already found from a previous entry
 		}
-		s = append(s, entry)
+		n++
+		dfnum[entry.ID] = n
+		order[n] = entry.ID
 		parent[entry.ID] = entry.ID
+		s = append(s, blockAndIndex{b: entry})
 		for len(s) > 0 {
-			node := s[len(s)-1]
-			s = s[:len(s)-1]
-
-			n++
-			for _, w := range succFn(node) {
-				// if it has a dfnum, we've already visited it
-				if dfnum[w.ID] == notFound {
-					s = append(s, w)
-					parent[w.ID] = node.ID
-					dfnum[w.ID] = notExplored
-				}
+			// Number each block when it is first reached, and
+			// make its parent the block it was reached from.
+			// Lengauer-Tarjan needs a true depth first search:
+			// numbering blocks as they are popped off a stack
+			// of all the successors gives a numbering that can
+			// produce wrong dominators.
+			tos := len(s) - 1
+			node := s[tos].b
+			succs := succFn(node)
+			i := s[tos].index
+			if i == len(succs) {
+				s = s[:tos]
+				continue
+			}
+			s[tos].index++
+			w := succs[i]
+			if dfnum[w.ID] == notFound {
+				n++
+				dfnum[w.ID] = n
+				order[n] = w.ID
+				parent[w.ID] = node.ID
+				s = append(s, blockAndIndex{b: w})
 			}
-			dfnum[node.ID] = n
-			order[n] = node.ID
 		}
 	}
 
@@ -152,6 +171,12 @@ func dominators(f *Func) []*Block {
 	return f.dominatorsLT([]*Block{f.Entry}, preds, succs)
 }
 
+// Idom returns a slice which maps block ID to the immediate dominator
+// of that block, like dominators. It is for building the SSA form of f.
+func (f *Func) Idom() []*Block {
+	return dominators(f)
+}
+
 // postDominators computes the post-dominator tree for f.
 func postDominators(f *Func) []*Block {
 	preds := func(b *Block) []*Block { return b.Preds }
@@ -271,7 +296,7 @@ func (f *Func) dominatorsLT(entries []*Block, predFn linkedBlocks, succFn linked
 	// Step 4. Explictly define the immediate dominator of each vertex,
 	// carrying out the computation vertex by vertex in increasing order by
 	// number.
-	for i := 1; i < maxBlockID-1; i++ {
+	for i := 1; i < maxBlockID; i++ {
 		w := vertex[i]
 		if w == 0 {
 			continue
@@ -372,6 +372,39 @@ func TestDominatorsMultPred(t *testing.T) {
 	verifyDominators(t, fun, dominatorsSimple, doms)
 }
 
+// Test that the dominator of d is entry, which depends on
+// dominatorsLT visiting the blocks in depth first order.
+func TestDominatorsDFS(t *testing.T) {
+	c := testConfig(t)
+	fun := Fun(c, "entry",
+		Bloc("entry",
+			Valu("mem", OpInitMem, TypeMem, 0, nil),
+			Valu("p", OpConstBool, TypeBool, 1, nil),
+			If("p", "b", "a")),
+		Bloc("a",
+			If("p", "d", "c")),
+		Bloc("b",
+			Goto("c")),
+		Bloc("c",
+			If("p", "d", "exit")),
+		Bloc("d",
+			Goto("d")),
+		Bloc("exit",
+			Exit("mem")))
+
+	doms := map[string]string{
+		"a":    "entry",
+		"b":    "entry",
+		"c":    "entry",
+		"d":    "entry",
+		"exit": "c",
+	}
+
+	CheckFunc(fun.f)
+	verifyDominators(t, fun, dominators, doms)
+	verifyDominators(t, fun, dominatorsSimple, doms)
+}
+
 func TestPostDominators(t *testing.T) {
 	c := testConfig(t)
 	fun := Fun(c, "entry",
@@ -115,23 +115,39 @@ func fuseBlockIf(b *Block) bool {
 	return true
 }
 
+// fuseBlockPlain handles a chain of plain blocks b -> ... -> c, each
+// the only predecessor of the next, by moving all their values to c,
+// which takes b's place. Fusing the chain one block at a time would
+// move the values of the first block once per block after it, which
+// is quadratic in the length of the chain.
 func fuseBlockPlain(b *Block) bool {
 	if b.Kind != BlockPlain {
 		return false
 	}
 
 	c := b.Succs[0]
-	if len(c.Preds) != 1 {
+	if len(c.Preds) != 1 || c == b {
 		return false
 	}
+	chain := []*Block{b}
+	for c.Kind == BlockPlain {
+		d := c.Succs[0]
+		if len(d.Preds) != 1 || d == b {
+			break
+		}
+		chain = append(chain, c)
+		c = d
+	}
 
-	// move all of b'c values to c.
-	for _, v := range b.Values {
-		v.Block = c
-		c.Values = append(c.Values, v)
+	// move all of the chain's values to c.
+	for _, d := range chain {
+		for _, v := range d.Values {
+			v.Block = c
+			c.Values = append(c.Values, v)
+		}
 	}
 
-	// replace b->c edge with preds(b) -> c
+	// replace b->c path with preds(b) -> c
 	c.predstorage[0] = nil
 	if len(b.Preds) > len(b.predstorage) {
 		c.Preds = b.Preds
@@ -149,10 +165,12 @@ func fuseBlockPlain(b *Block) bool {
 		f.Entry = c
 	}
 
-	// trash b, just in case
-	b.Kind = BlockInvalid
-	b.Values = nil
-	b.Preds = nil
-	b.Succs = nil
+	// trash the chain, just in case
+	for _, d := range chain {
+		d.Kind = BlockInvalid
+		d.Values = nil
+		d.Preds = nil
+		d.Succs = nil
+	}
 	return true
 }
@@ -127,3 +127,25 @@ func TestFuseEliminatesEmptyBlocks(t *testing.T) {
 		}
 	}
 }
+
+func TestFuseChain(t *testing.T) {
+	c := NewConfig("amd64", DummyFrontend{t}, nil, true)
+	fun := Fun(c, "entry", genFunction(1000)...)
+
+	CheckFunc(fun.f)
+	fuse(fun.f)
+	CheckFunc(fun.f)
+
+	entry := fun.blocks["exit"]
+	if fun.f.Entry != entry {
+		t.Errorf("entry is %s, want the chain fused into %s", fun.f.Entry, entry)
+	}
+	for _, v := range fun.values {
+		if v.Block != entry {
+			t.Errorf("%s is in %s, want %s", v, v.Block, entry)
+		}
+	}
+	if len(entry.Values) != len(fun.values) {
+		t.Errorf("entry has %d values, want %d", len(entry.Values), len(fun.values))
+	}
+}
@@ -7,7 +7,12 @@ package ssa
 // layout orders basic blocks in f with the goal of minimizing control flow instructions.
 // After this phase returns, the order of f.Blocks matters and is the order
 // in which those blocks will appear in the assembly output.
+//
+// Blocks that can only end in a panic (see coldBlocks) are placed
+// after all other blocks, keeping the hot paths dense.
 func layout(f *Func) {
+	cold := coldBlocks(f)
+	nhot := 0
 	order := make([]*Block, 0, f.NumBlocks())
 	scheduled := make([]bool, f.NumBlocks())
 	idToBlock := make([]*Block, f.NumBlocks())
@@ -21,6 +26,10 @@ func layout(f *Func) {
 	for _, b := range f.Blocks {
 		idToBlock[b.ID] = b
 		indegree[b.ID] = len(b.Preds)
+		if cold[b.ID] {
+			continue
+		}
+		nhot++
 		if len(b.Preds) == 0 {
 			zerodegree.add(b.ID)
 		} else {
@@ -35,11 +44,14 @@ blockloop:
 		b := idToBlock[bid]
 		order = append(order, b)
 		scheduled[bid] = true
-		if len(order) == len(f.Blocks) {
+		if len(order) == nhot {
 			break
 		}
 
 		for _, c := range b.Succs {
+			if cold[c.ID] {
+				continue
+			}
 			indegree[c.ID]--
 			if indegree[c.ID] == 0 {
 				posdegree.remove(c.ID)
@@ -58,7 +70,7 @@ blockloop:
 		case BranchUnlikely:
 			likely = b.Succs[1]
 		}
-		if likely != nil && !scheduled[likely.ID] {
+		if likely != nil && !scheduled[likely.ID] && !cold[likely.ID] {
 			bid = likely.ID
 			continue
 		}
@@ -67,7 +79,7 @@ blockloop:
 		bid = 0
 		mindegree := f.NumBlocks()
 		for _, c := range order[len(order)-1].Succs {
-			if scheduled[c.ID] {
+			if scheduled[c.ID] || cold[c.ID] {
 				continue
 			}
 			if indegree[c.ID] < mindegree {
@@ -97,5 +109,73 @@ blockloop:
 			}
 		}
 	}
+
+	// Lay out the cold blocks. Each one follows a scheduled
+	// predecessor, preferring the block just placed so that
+	// straight-line panic paths still fall through.
+	var coldq []*Block // cold blocks with a scheduled predecessor
+	for _, b := range order {
+		for _, c := range b.Succs {
+			if cold[c.ID] {
+				coldq = append(coldq, c)
+			}
+		}
+	}
+	var last *Block
+	for len(order) < len(f.Blocks) {
+		var b *Block
+		if last != nil {
+			for _, c := range last.Succs {
+				if !scheduled[c.ID] {
+					b = c
+					break
+				}
+			}
+		}
+		for b == nil {
+			c := coldq[0]
+			coldq = coldq[1:]
+			if !scheduled[c.ID] {
+				b = c
+			}
+		}
+		order = append(order, b)
+		scheduled[b.ID] = true
+		coldq = append(coldq, b.Succs...)
+		last = b
+	}
 	f.Blocks = order
 }
+
+// coldBlocks reports which blocks of f are unlikely to execute:
+// those from which every path ends in a BlockExit (a panic or other
+// non-returning call). The entry block is never cold.
+// Branches into cold blocks are already predicted not taken by
+// likelyadjust; layout uses the result to move them out of line.
+func coldBlocks(f *Func) []bool {
+	cold := make([]bool, f.NumBlocks())
+	po := postorder(f)
+	for changed := true; changed; {
+		changed = false
+		for _, b := range po {
+			if cold[b.ID] || b == f.Entry {
+				continue
+			}
+			c := b.Kind == BlockExit
+			if !c && len(b.Succs) > 0 {
+				c = true
+				for _, s := range b.Succs {
+					if !cold[s.ID] {
+						c = false
+						break
+					}
+				}
+			}
+			if c {
+				cold[b.ID] = true
+				changed = true
+			}
+		}
+	}
+	return cold
+}
@@ -0,0 +1,37 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ssa
+
+import "testing"
+
+func TestLayoutColdBlocksLast(t *testing.T) {
+	c := NewConfig("amd64", DummyFrontend{t}, nil, true)
+	fun := Fun(c, "entry",
+		Bloc("entry",
+			Valu("mem", OpInitMem, TypeMem, 0, nil),
+			Valu("sb", OpSB, TypeInvalid, 0, nil),
+			Valu("cond", OpLoad, TypeBool, 0, nil, "sb", "mem"),
+			If("cond", "panic", "body")),
+		Bloc("panic",
+			Goto("die")),
+		Bloc("die",
+			Exit("mem")),
+		Bloc("body",
+			Goto("ret")),
+		Bloc("ret",
+			Exit("mem")))
+	fun.blocks["ret"].Kind = BlockRet
+
+	CheckFunc(fun.f)
+	layout(fun.f)
+	CheckFunc(fun.f)
+
+	want := []string{"entry", "body", "ret", "panic", "die"}
+	for i, b := range fun.f.Blocks {
+		if b != fun.blocks[want[i]] {
+			t.Fatalf("block %d is %s, want %s", i, b, fun.blocks[want[i]])
+		}
+	}
+}
@@ -3826,7 +3826,7 @@ var opcodeTable = [...]opInfo{
 	},
 	{
 		name:    "CALLdefer",
-		auxType: auxInt64,
+		auxType: auxSymOff,
 		argLen:  1,
 		reg: regInfo{
 			clobbers: 12884901871, // AX CX DX BX BP SI DI R8 R9 R10 R11 R12 R13 R14 R15 X0 X1 X2 X3 X4 X5 X6 X7 X8 X9 X10 X11 X12 X13 X14 X15 FLAGS
@@ -5134,7 +5134,7 @@ var opcodeTable = [...]opInfo{
 	},
 	{
 		name:    "DeferCall",
-		auxType: auxInt64,
+		auxType: auxSymOff,
 		argLen:  1,
 		generic: true,
 	},
@@ -3169,14 +3169,16 @@ func rewriteValueAMD64_OpCvt64to64F(v *Value, config *Config) bool {
 func rewriteValueAMD64_OpDeferCall(v *Value, config *Config) bool {
 	b := v.Block
 	_ = b
-	// match: (DeferCall [argwid] mem)
+	// match: (DeferCall [argwid] {target} mem)
 	// cond:
-	// result: (CALLdefer [argwid] mem)
+	// result: (CALLdefer [argwid] {target} mem)
 	for {
 		argwid := v.AuxInt
+		target := v.Aux
 		mem := v.Args[0]
 		v.reset(OpAMD64CALLdefer)
 		v.AuxInt = argwid
+		v.Aux = target
 		v.AddArg(mem)
 		return true
 	}
@@ -9521,14 +9523,45 @@ func rewriteValueAMD64_OpMove(v *Value, config *Config) bool {
 		return true
 	}
 	// match: (Move [size] dst src mem)
-	// cond: size >= 32 && size <= 16*64 && size%16 == 0 && !config.noDuffDevice
+	// cond: size > 16 && size%16 == 0 && size < config.DuffCopyMin && !config.noDuffDevice
+	// result: (Move [size-16] (ADDQconst <dst.Type> dst [16]) (ADDQconst <src.Type> src [16]) 		(MOVOstore dst (MOVOload src mem) mem))
+	for {
+		size := v.AuxInt
+		dst := v.Args[0]
+		src := v.Args[1]
+		mem := v.Args[2]
+		if !(size > 16 && size%16 == 0 && size < config.DuffCopyMin && !config.noDuffDevice) {
+			break
+		}
+		v.reset(OpMove)
+		v.AuxInt = size - 16
+		v0 := b.NewValue0(v.Line, OpAMD64ADDQconst, dst.Type)
+		v0.AddArg(dst)
+		v0.AuxInt = 16
+		v.AddArg(v0)
+		v1 := b.NewValue0(v.Line, OpAMD64ADDQconst, src.Type)
+		v1.AddArg(src)
+		v1.AuxInt = 16
+		v.AddArg(v1)
+		v2 := b.NewValue0(v.Line, OpAMD64MOVOstore, TypeMem)
+		v2.AddArg(dst)
+		v3 := b.NewValue0(v.Line, OpAMD64MOVOload, TypeInt128)
+		v3.AddArg(src)
+		v3.AddArg(mem)
+		v2.AddArg(v3)
+		v2.AddArg(mem)
+		v.AddArg(v2)
+		return true
+	}
+	// match: (Move [size] dst src mem)
+	// cond: size >= 32 && size <= config.DuffCopyMax && size%16 == 0 && !config.noDuffDevice
 	// result: (DUFFCOPY [14*(64-size/16)] dst src mem)
 	for {
 		size := v.AuxInt
 		dst := v.Args[0]
 		src := v.Args[1]
 		mem := v.Args[2]
-		if !(size >= 32 && size <= 16*64 && size%16 == 0 && !config.noDuffDevice) {
+		if !(size >= 32 && size <= config.DuffCopyMax && size%16 == 0 && !config.noDuffDevice) {
 			break
 		}
 		v.reset(OpAMD64DUFFCOPY)
@@ -9539,14 +9572,14 @@ func rewriteValueAMD64_OpMove(v *Value, config *Config) bool {
 		return true
 	}
 	// match: (Move [size] dst src mem)
-	// cond: (size > 16*64 || config.noDuffDevice) && size%8 == 0
+	// cond: (size > config.DuffCopyMax || config.noDuffDevice) && size%8 == 0
 	// result: (REPMOVSQ dst src (MOVQconst [size/8]) mem)
 	for {
 		size := v.AuxInt
 		dst := v.Args[0]
 		src := v.Args[1]
 		mem := v.Args[2]
-		if !((size > 16*64 || config.noDuffDevice) && size%8 == 0) {
+		if !((size > config.DuffCopyMax || config.noDuffDevice) && size%8 == 0) {
 			break
 		}
 		v.reset(OpAMD64REPMOVSQ)
@@ -15159,13 +15192,36 @@ func rewriteValueAMD64_OpZero(v *Value, config *Config) bool {
 		return true
 	}
 	// match: (Zero [size] destptr mem)
-	// cond: size <= 1024 && size%8 == 0 && size%16 != 0 && !config.noDuffDevice
+	// cond: size > 32 && size%8 == 0 && size < config.DuffZeroMin && !config.noDuffDevice
+	// result: (Zero [size-8] (ADDQconst [8] destptr) (MOVQstoreconst [0] destptr mem))
+	for {
+		size := v.AuxInt
+		destptr := v.Args[0]
+		mem := v.Args[1]
+		if !(size > 32 && size%8 == 0 && size < config.DuffZeroMin && !config.noDuffDevice) {
+			break
+		}
+		v.reset(OpZero)
+		v.AuxInt = size - 8
+		v0 := b.NewValue0(v.Line, OpAMD64ADDQconst, config.fe.TypeUInt64())
+		v0.AuxInt = 8
+		v0.AddArg(destptr)
+		v.AddArg(v0)
+		v1 := b.NewValue0(v.Line, OpAMD64MOVQstoreconst, TypeMem)
+		v1.AuxInt = 0
+		v1.AddArg(destptr)
+		v1.AddArg(mem)
+		v.AddArg(v1)
+		return true
+	}
+	// match: (Zero [size] destptr mem)
+	// cond: size <= config.DuffZeroMax && size%8 == 0 && size%16 != 0 && !config.noDuffDevice
 	// result: (Zero [size-8] (ADDQconst [8] destptr) (MOVQstore destptr (MOVQconst [0]) mem))
 	for {
 		size := v.AuxInt
 		destptr := v.Args[0]
 		mem := v.Args[1]
-		if !(size <= 1024 && size%8 == 0 && size%16 != 0 && !config.noDuffDevice) {
+		if !(size <= config.DuffZeroMax && size%8 == 0 && size%16 != 0 && !config.noDuffDevice) {
 			break
 		}
 		v.reset(OpZero)
@@ -15184,13 +15240,13 @@ func rewriteValueAMD64_OpZero(v *Value, config *Config) bool {
 		return true
 	}
 	// match: (Zero [size] destptr mem)
-	// cond: size <= 1024 && size%16 == 0 && !config.noDuffDevice
+	// cond: size <= config.DuffZeroMax && size%16 == 0 && !config.noDuffDevice
 	// result: (DUFFZERO [duffStart(size)] (ADDQconst [duffAdj(size)] destptr) (MOVOconst [0]) mem)
 	for {
 		size := v.AuxInt
 		destptr := v.Args[0]
 		mem := v.Args[1]
-		if !(size <= 1024 && size%16 == 0 && !config.noDuffDevice) {
+		if !(size <= config.DuffZeroMax && size%16 == 0 && !config.noDuffDevice) {
 			break
 		}
 		v.reset(OpAMD64DUFFZERO)
@@ -15206,13 +15262,13 @@ func rewriteValueAMD64_OpZero(v *Value, config *Config) bool {
 		return true
 	}
 	// match: (Zero [size] destptr mem)
-	// cond: (size > 1024 || (config.noDuffDevice && size > 32)) && size%8 == 0
+	// cond: (size > config.DuffZeroMax || (config.noDuffDevice && size > 32)) && size%8 == 0
 	// result: (REPSTOSQ destptr (MOVQconst [size/8]) (MOVQconst [0]) mem)
 	for {
 		size := v.AuxInt
 		destptr := v.Args[0]
 		mem := v.Args[1]
-		if !((size > 1024 || (config.noDuffDevice && size > 32)) && size%8 == 0) {
+		if !((size > config.DuffZeroMax || (config.noDuffDevice && size > 32)) && size%8 == 0) {
 			break
 		}
 		v.reset(OpAMD64REPSTOSQ)
@@ -6,6 +6,7 @@ package x86
 
 import (
 	"cmd/compile/internal/gc"
+	"cmd/compile/internal/ir"
 	"cmd/internal/obj"
 	"cmd/internal/obj/x86"
 )
@@ -15,49 +16,49 @@ import (
  * n is an array index, and might be any size; res width is <= 32-bit.
  * returns Prog* to patch to panic call.
  */
-func igenindex(n *gc.Node, res *gc.Node, bounded bool) *obj.Prog {
+func igenindex(n *ir.Node, res *ir.Node, bounded bool) *obj.Prog {
 	if !gc.Is64(n.Type) {
-		if n.Addable && (gc.Simtype[n.Etype] == gc.TUINT32 || gc.Simtype[n.Etype] == gc.TINT32) {
+		if n.Addable && (gc.Simtype[n.Etype] == ir.TUINT32 || gc.Simtype[n.Etype] == ir.TINT32) {
 			// nothing to do.
 			*res = *n
 		} else {
-			gc.Tempname(res, gc.Types[gc.TUINT32])
+			gc.Tempname(res, ir.Types[ir.TUINT32])
 			gc.Cgen(n, res)
 		}
 
 		return nil
 	}
 
-	var tmp gc.Node
-	gc.Tempname(&tmp, gc.Types[gc.TINT64])
+	var tmp ir.Node
+	gc.Tempname(&tmp, ir.Types[ir.TINT64])
 	gc.Cgen(n, &tmp)
-	var lo gc.Node
-	var hi gc.Node
+	var lo ir.Node
+	var hi ir.Node
 	split64(&tmp, &lo, &hi)
-	gc.Tempname(res, gc.Types[gc.TUINT32])
+	gc.Tempname(res, ir.Types[ir.TUINT32])
 	gmove(&lo, res)
 	if bounded {
 		splitclean()
 		return nil
 	}
 
-	var zero gc.Node
-	gc.Nodconst(&zero, gc.Types[gc.TINT32], 0)
+	var zero ir.Node
+	gc.Nodconst(&zero, ir.Types[ir.TINT32], 0)
 	gins(x86.ACMPL, &hi, &zero)
 	splitclean()
 	return gc.Gbranch(x86.AJNE, nil, +1)
 }
 
-func blockcopy(n, res *gc.Node, osrc, odst, w int64) {
-	var dst gc.Node
-	gc.Nodreg(&dst, gc.Types[gc.Tptr], x86.REG_DI)
-	var src gc.Node
-	gc.Nodreg(&src, gc.Types[gc.Tptr], x86.REG_SI)
+func blockcopy(n, res *ir.Node, osrc, odst, w int64) {
+	var dst ir.Node
+	gc.Nodreg(&dst, ir.Types[gc.Tptr], x86.REG_DI)
+	var src ir.Node
+	gc.Nodreg(&src, ir.Types[gc.Tptr], x86.REG_SI)
 
-	var tsrc gc.Node
-	gc.Tempname(&tsrc, gc.Types[gc.Tptr])
-	var tdst gc.Node
-	gc.Tempname(&tdst, gc.Types[gc.Tptr])
+	var tsrc ir.Node
+	gc.Tempname(&tsrc, ir.Types[gc.Tptr])
+	var tdst ir.Node
+	gc.Tempname(&tdst, ir.Types[gc.Tptr])
 	if !n.Addable {
 		gc.Agen(n, &tsrc)
 	}
@@ -70,7 +71,7 @@ func blockcopy(n, res *gc.Node, osrc, odst, w int64) {
 		gmove(&tsrc, &src)
 	}
 
-	if res.Op == gc.ONAME {
+	if res.Op == ir.ONAME {
 		gc.Gvardef(res)
 	}
 
@@ -117,11 +118,11 @@ func blockcopy(n, res *gc.Node, osrc, odst, w int64) {
 		gins(x86.ACLD, nil, nil) // paranoia.  TODO(rsc): remove?
 
 		// normal direction
-		if q > 128 || (q >= 4 && gc.Nacl) {
+		if q > int32(gc.Thearch.Duffcopy.Max) || (q >= int32(gc.Thearch.Duffcopy.Min) && gc.Nacl) {
 			gconreg(x86.AMOVL, int64(q), x86.REG_CX)
 			gins(x86.AREP, nil, nil)   // repeat
 			gins(x86.AMOVSL, nil, nil) // MOVL *(SI)+,*(DI)+
-		} else if q >= 4 {
+		} else if q >= int32(gc.Thearch.Duffcopy.Min) {
 			p := gins(obj.ADUFFCOPY, nil, nil)
 			p.To.Type = obj.TYPE_ADDR
 			p.To.Sym = gc.Linksym(gc.Pkglookup("duffcopy", gc.Runtimepkg))
@@ -129,14 +130,14 @@ func blockcopy(n, res *gc.Node, osrc, odst, w int64) {
 			// 10 and 128 = magic constants: see ../../runtime/asm_386.s
 			p.To.Offset = 10 * (128 - int64(q))
 		} else if !gc.Nacl && c == 0 {
-			var cx gc.Node
-			gc.Nodreg(&cx, gc.Types[gc.TINT32], x86.REG_CX)
+			var cx ir.Node
+			gc.Nodreg(&cx, ir.Types[ir.TINT32], x86.REG_CX)
 
 			// We don't need the MOVSL side-effect of updating SI and DI,
 			// and issuing a sequence of MOVLs directly is faster.
-			src.Op = gc.OINDREG
+			src.Op = ir.OINDREG
 
-			dst.Op = gc.OINDREG
+			dst.Op = ir.OINDREG
 			for q > 0 {
 				gmove(&src, &cx) // MOVL x+(SI),CX
 				gmove(&cx, &dst) // MOVL CX,x+(DI)
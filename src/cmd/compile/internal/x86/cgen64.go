@@ -6,6 +6,7 @@ package x86
 
 import (
 	"cmd/compile/internal/gc"
+	"cmd/compile/internal/ir"
 	"cmd/internal/obj"
 	"cmd/internal/obj/x86"
 )
@@ -15,8 +16,8 @@ import (
  *	res = n
  * return 1 on success, 0 if op not handled.
  */
-func cgen64(n *gc.Node, res *gc.Node) {
-	if res.Op != gc.OINDREG && res.Op != gc.ONAME {
+func cgen64(n *ir.Node, res *ir.Node) {
+	if res.Op != ir.OINDREG && res.Op != ir.ONAME {
 		gc.Dump("n", n)
 		gc.Dump("res", res)
 		gc.Fatalf("cgen64 %v of %v", gc.Oconv(n.Op, 0), gc.Oconv(res.Op, 0))
@@ -26,10 +27,10 @@ func cgen64(n *gc.Node, res *gc.Node) {
 	default:
 		gc.Fatalf("cgen64 %v", gc.Oconv(n.Op, 0))
 
-	case gc.OMINUS:
+	case ir.OMINUS:
 		gc.Cgen(n.Left, res)
-		var hi1 gc.Node
-		var lo1 gc.Node
+		var hi1 ir.Node
+		var lo1 ir.Node
 		split64(res, &lo1, &hi1)
 		gins(x86.ANEGL, nil, &lo1)
 		gins(x86.AADCL, ncon(0), &hi1)
@@ -37,10 +38,10 @@ func cgen64(n *gc.Node, res *gc.Node) {
 		splitclean()
 		return
 
-	case gc.OCOM:
+	case ir.OCOM:
 		gc.Cgen(n.Left, res)
-		var lo1 gc.Node
-		var hi1 gc.Node
+		var lo1 ir.Node
+		var hi1 ir.Node
 		split64(res, &lo1, &hi1)
 		gins(x86.ANOTL, nil, &lo1)
 		gins(x86.ANOTL, nil, &hi1)
@@ -49,48 +50,48 @@ func cgen64(n *gc.Node, res *gc.Node) {
 
 		// binary operators.
 	// common setup below.
-	case gc.OADD,
-		gc.OSUB,
-		gc.OMUL,
-		gc.OLROT,
-		gc.OLSH,
-		gc.ORSH,
-		gc.OAND,
-		gc.OOR,
-		gc.OXOR:
+	case ir.OADD,
+		ir.OSUB,
+		ir.OMUL,
+		ir.OLROT,
+		ir.OLSH,
+		ir.ORSH,
+		ir.OAND,
+		ir.OOR,
+		ir.OXOR:
 		break
 	}
 
 	l := n.Left
 	r := n.Right
 	if !l.Addable {
-		var t1 gc.Node
+		var t1 ir.Node
 		gc.Tempname(&t1, l.Type)
 		gc.Cgen(l, &t1)
 		l = &t1
 	}
 
 	if r != nil && !r.Addable {
-		var t2 gc.Node
+		var t2 ir.Node
 		gc.Tempname(&t2, r.Type)
 		gc.Cgen(r, &t2)
 		r = &t2
 	}
 
-	var ax gc.Node
-	gc.Nodreg(&ax, gc.Types[gc.TINT32], x86.REG_AX)
-	var cx gc.Node
-	gc.Nodreg(&cx, gc.Types[gc.TINT32], x86.REG_CX)
-	var dx gc.Node
-	gc.Nodreg(&dx, gc.Types[gc.TINT32], x86.REG_DX)
+	var ax ir.Node
+	gc.Nodreg(&ax, ir.Types[ir.TINT32], x86.REG_AX)
+	var cx ir.Node
+	gc.Nodreg(&cx, ir.Types[ir.TINT32], x86.REG_CX)
+	var dx ir.Node
+	gc.Nodreg(&dx, ir.Types[ir.TINT32], x86.REG_DX)
 
 	// Setup for binary operation.
-	var hi1 gc.Node
-	var lo1 gc.Node
+	var hi1 ir.Node
+	var lo1 ir.Node
 	split64(l, &lo1, &hi1)
 
-	var lo2 gc.Node
-	var hi2 gc.Node
+	var lo2 ir.Node
+	var hi2 ir.Node
 	if gc.Is64(r.Type) {
 		split64(r, &lo2, &hi2)
 	}
@@ -98,7 +99,7 @@ func cgen64(n *gc.Node, res *gc.Node) {
 	// Do op. Leave result in DX:AX.
 	switch n.Op {
 	// TODO: Constants
-	case gc.OADD:
+	case ir.OADD:
 		gins(x86.AMOVL, &lo1, &ax)
 
 		gins(x86.AMOVL, &hi1, &dx)
@@ -106,19 +107,19 @@ func cgen64(n *gc.Node, res *gc.Node) {
 		gins(x86.AADCL, &hi2, &dx)
 
 		// TODO: Constants.
-	case gc.OSUB:
+	case ir.OSUB:
 		gins(x86.AMOVL, &lo1, &ax)
 
 		gins(x86.AMOVL, &hi1, &dx)
 		gins(x86.ASUBL, &lo2, &ax)
 		gins(x86.ASBBL, &hi2, &dx)
 
-	case gc.OMUL:
+	case ir.OMUL:
 		// let's call the next three EX, FX and GX
-		var ex, fx, gx gc.Node
-		gc.Regalloc(&ex, gc.Types[gc.TPTR32], nil)
-		gc.Regalloc(&fx, gc.Types[gc.TPTR32], nil)
-		gc.Regalloc(&gx, gc.Types[gc.TPTR32], nil)
+		var ex, fx, gx ir.Node
+		gc.Regalloc(&ex, ir.Types[ir.TPTR32], nil)
+		gc.Regalloc(&fx, ir.Types[ir.TPTR32], nil)
+		gc.Regalloc(&gx, ir.Types[ir.TPTR32], nil)
 
 		// load args into DX:AX and EX:GX.
 		gins(x86.AMOVL, &lo1, &ax)
@@ -161,7 +162,7 @@ func cgen64(n *gc.Node, res *gc.Node) {
 	//	t = hi
 	//	shld hi:lo, c
 	//	shld lo:t, c
-	case gc.OLROT:
+	case ir.OLROT:
 		v := uint64(r.Int())
 
 		if v >= 32 {
@@ -187,8 +188,8 @@ func cgen64(n *gc.Node, res *gc.Node) {
 			p1.From.Scale = 0
 		}
 
-	case gc.OLSH:
-		if r.Op == gc.OLITERAL {
+	case ir.OLSH:
+		if r.Op == ir.OLITERAL {
 			v := uint64(r.Int())
 			if v >= 64 {
 				if gc.Is64(r.Type) {
@@ -243,14 +244,14 @@ func cgen64(n *gc.Node, res *gc.Node) {
 			p1 = gc.Gbranch(x86.AJNE, nil, +1)
 			gins(x86.AMOVL, &lo2, &cx)
 		} else {
-			cx.Type = gc.Types[gc.TUINT32]
+			cx.Type = ir.Types[ir.TUINT32]
 			gmove(r, &cx)
 		}
 
 		// if shift count is >=64, zero value
 		gins(x86.ACMPL, &cx, ncon(64))
 
-		p2 := gc.Gbranch(optoas(gc.OLT, gc.Types[gc.TUINT32]), nil, +1)
+		p2 := gc.Gbranch(optoas(ir.OLT, ir.Types[ir.TUINT32]), nil, +1)
 		if p1 != nil {
 			gc.Patch(p1, gc.Pc)
 		}
@@ -261,7 +262,7 @@ func cgen64(n *gc.Node, res *gc.Node) {
 		// if shift count is >= 32, zero low.
 		gins(x86.ACMPL, &cx, ncon(32))
 
-		p1 = gc.Gbranch(optoas(gc.OLT, gc.Types[gc.TUINT32]), nil, +1)
+		p1 = gc.Gbranch(optoas(ir.OLT, ir.Types[ir.TUINT32]), nil, +1)
 		gins(x86.AMOVL, &ax, &dx)
 		gins(x86.ASHLL, &cx, &dx) // SHLL only uses bottom 5 bits of count
 		gins(x86.AXORL, &ax, &ax)
@@ -276,8 +277,8 @@ func cgen64(n *gc.Node, res *gc.Node) {
 		gins(x86.ASHLL, &cx, &ax)
 		gc.Patch(p2, gc.Pc)
 
-	case gc.ORSH:
-		if r.Op == gc.OLITERAL {
+	case ir.ORSH:
+		if r.Op == ir.OLITERAL {
 			v := uint64(r.Int())
 			if v >= 64 {
 				if gc.Is64(r.Type) {
@@ -285,7 +286,7 @@ func cgen64(n *gc.Node, res *gc.Node) {
 				}
 				splitclean()
 				split64(res, &lo2, &hi2)
-				if hi1.Type.Etype == gc.TINT32 {
+				if hi1.Type.Etype == ir.TINT32 {
 					gmove(&hi1, &lo2)
 					gins(x86.ASARL, ncon(31), &lo2)
 					gmove(&hi1, &hi2)
@@ -306,9 +307,9 @@ func cgen64(n *gc.Node, res *gc.Node) {
 				split64(res, &lo2, &hi2)
 				gmove(&hi1, &lo2)
 				if v > 32 {
-					gins(optoas(gc.ORSH, hi1.Type), ncon(uint32(v-32)), &lo2)
+					gins(optoas(ir.ORSH, hi1.Type), ncon(uint32(v-32)), &lo2)
 				}
-				if hi1.Type.Etype == gc.TINT32 {
+				if hi1.Type.Etype == ir.TINT32 {
 					gmove(&hi1, &hi2)
 					gins(x86.ASARL, ncon(31), &hi2)
 				} else {
@@ -326,7 +327,7 @@ func cgen64(n *gc.Node, res *gc.Node) {
 			p1 := gins(x86.ASHRL, ncon(uint32(v)), &ax)
 			p1.From.Index = x86.REG_DX // double-width shift
 			p1.From.Scale = 0
-			gins(optoas(gc.ORSH, hi1.Type), ncon(uint32(v)), &dx)
+			gins(optoas(ir.ORSH, hi1.Type), ncon(uint32(v)), &dx)
 			break
 		}
 
@@ -344,18 +345,18 @@ func cgen64(n *gc.Node, res *gc.Node) {
 			p1 = gc.Gbranch(x86.AJNE, nil, +1)
 			gins(x86.AMOVL, &lo2, &cx)
 		} else {
-			cx.Type = gc.Types[gc.TUINT32]
+			cx.Type = ir.Types[ir.TUINT32]
 			gmove(r, &cx)
 		}
 
 		// if shift count is >=64, zero or sign-extend value
 		gins(x86.ACMPL, &cx, ncon(64))
 
-		p2 := gc.Gbranch(optoas(gc.OLT, gc.Types[gc.TUINT32]), nil, +1)
+		p2 := gc.Gbranch(optoas(ir.OLT, ir.Types[ir.TUINT32]), nil, +1)
 		if p1 != nil {
 			gc.Patch(p1, gc.Pc)
 		}
-		if hi1.Type.Etype == gc.TINT32 {
+		if hi1.Type.Etype == ir.TINT32 {
 			gins(x86.ASARL, ncon(31), &dx)
 			gins(x86.AMOVL, &dx, &ax)
 		} else {
@@ -368,9 +369,9 @@ func cgen64(n *gc.Node, res *gc.Node) {
 		// if shift count is >= 32, sign-extend hi.
 		gins(x86.ACMPL, &cx, ncon(32))
 
-		p1 = gc.Gbranch(optoas(gc.OLT, gc.Types[gc.TUINT32]), nil, +1)
+		p1 = gc.Gbranch(optoas(ir.OLT, ir.Types[ir.TUINT32]), nil, +1)
 		gins(x86.AMOVL, &dx, &ax)
-		if hi1.Type.Etype == gc.TINT32 {
+		if hi1.Type.Etype == ir.TINT32 {
 			gins(x86.ASARL, &cx, &ax) // SARL only uses bottom 5 bits of count
 			gins(x86.ASARL, ncon(31), &dx)
 		} else {
@@ -386,26 +387,26 @@ func cgen64(n *gc.Node, res *gc.Node) {
 
 		p1.From.Index = x86.REG_DX // double-width shift
 		p1.From.Scale = 0
-		gins(optoas(gc.ORSH, hi1.Type), &cx, &dx)
+		gins(optoas(ir.ORSH, hi1.Type), &cx, &dx)
 		gc.Patch(p2, gc.Pc)
 
 		// make constant the right side (it usually is anyway).
-	case gc.OXOR,
-		gc.OAND,
-		gc.OOR:
-		if lo1.Op == gc.OLITERAL {
+	case ir.OXOR,
+		ir.OAND,
+		ir.OOR:
+		if lo1.Op == ir.OLITERAL {
 			nswap(&lo1, &lo2)
 			nswap(&hi1, &hi2)
 		}
 
-		if lo2.Op == gc.OLITERAL {
+		if lo2.Op == ir.OLITERAL {
 			// special cases for constants.
 			lv := uint32(lo2.Int())
 			hv := uint32(hi2.Int())
 			splitclean() // right side
 			split64(res, &lo2, &hi2)
 			switch n.Op {
-			case gc.OXOR:
+			case ir.OXOR:
 				gmove(&lo1, &lo2)
 				gmove(&hi1, &hi2)
 				switch lv {
@@ -430,7 +431,7 @@ func cgen64(n *gc.Node, res *gc.Node) {
 					gins(x86.AXORL, ncon(hv), &hi2)
 				}
 
-			case gc.OAND:
+			case ir.OAND:
 				switch lv {
 				case 0:
 					gins(x86.AMOVL, ncon(0), &lo2)
@@ -453,7 +454,7 @@ func cgen64(n *gc.Node, res *gc.Node) {
 					}
 				}
 
-			case gc.OOR:
+			case ir.OOR:
 				switch lv {
 				case 0:
 					gmove(&lo1, &lo2)
@@ -505,12 +506,12 @@ func cgen64(n *gc.Node, res *gc.Node) {
  * generate comparison of nl, nr, both 64-bit.
  * nl is memory; nr is constant or memory.
  */
-func cmp64(nl *gc.Node, nr *gc.Node, op gc.Op, likely int, to *obj.Prog) {
-	var lo1 gc.Node
-	var hi1 gc.Node
-	var lo2 gc.Node
-	var hi2 gc.Node
-	var rr gc.Node
+func cmp64(nl *ir.Node, nr *ir.Node, op ir.Op, likely int, to *obj.Prog) {
+	var lo1 ir.Node
+	var hi1 ir.Node
+	var lo2 ir.Node
+	var hi2 ir.Node
+	var rr ir.Node
 
 	split64(nl, &lo1, &hi1)
 	split64(nr, &lo2, &hi2)
@@ -519,10 +520,10 @@ func cmp64(nl *gc.Node, nr *gc.Node, op gc.Op, likely int, to *obj.Prog) {
 	// if they differ, we're done.
 	t := hi1.Type
 
-	if nl.Op == gc.OLITERAL || nr.Op == gc.OLITERAL {
+	if nl.Op == ir.OLITERAL || nr.Op == ir.OLITERAL {
 		gins(x86.ACMPL, &hi1, &hi2)
 	} else {
-		gc.Regalloc(&rr, gc.Types[gc.TINT32], nil)
+		gc.Regalloc(&rr, ir.Types[ir.TINT32], nil)
 		gins(x86.AMOVL, &hi1, &rr)
 		gins(x86.ACMPL, &rr, &hi2)
 		gc.Regfree(&rr)
@@ -538,14 +539,14 @@ func cmp64(nl *gc.Node, nr *gc.Node, op gc.Op, likely int, to *obj.Prog) {
 	// cmp lo
 	// jeq to
 	// L:
-	case gc.OEQ:
+	case ir.OEQ:
 		br = gc.Gbranch(x86.AJNE, nil, -likely)
 
 		// cmp hi
 	// jne to
 	// cmp lo
 	// jne to
-	case gc.ONE:
+	case ir.ONE:
 		gc.Patch(gc.Gbranch(x86.AJNE, nil, likely), to)
 
 		// cmp hi
@@ -554,11 +555,11 @@ func cmp64(nl *gc.Node, nr *gc.Node, op gc.Op, likely int, to *obj.Prog) {
 	// cmp lo
 	// jge to (or jgt to)
 	// L:
-	case gc.OGE,
-		gc.OGT:
-		gc.Patch(gc.Gbranch(optoas(gc.OGT, t), nil, likely), to)
+	case ir.OGE,
+		ir.OGT:
+		gc.Patch(gc.Gbranch(optoas(ir.OGT, t), nil, likely), to)
 
-		br = gc.Gbranch(optoas(gc.OLT, t), nil, -likely)
+		br = gc.Gbranch(optoas(ir.OLT, t), nil, -likely)
 
 		// cmp hi
 	// jlt to
@@ -566,20 +567,20 @@ func cmp64(nl *gc.Node, nr *gc.Node, op gc.Op, likely int, to *obj.Prog) {
 	// cmp lo
 	// jle to (or jlt to)
 	// L:
-	case gc.OLE,
-		gc.OLT:
-		gc.Patch(gc.Gbranch(optoas(gc.OLT, t), nil, likely), to)
+	case ir.OLE,
+		ir.OLT:
+		gc.Patch(gc.Gbranch(optoas(ir.OLT, t), nil, likely), to)
 
-		br = gc.Gbranch(optoas(gc.OGT, t), nil, -likely)
+		br = gc.Gbranch(optoas(ir.OGT, t), nil, -likely)
 	}
 
 	// compare least significant word
 	t = lo1.Type
 
-	if nl.Op == gc.OLITERAL || nr.Op == gc.OLITERAL {
+	if nl.Op == ir.OLITERAL || nr.Op == ir.OLITERAL {
 		gins(x86.ACMPL, &lo1, &lo2)
 	} else {
-		gc.Regalloc(&rr, gc.Types[gc.TINT32], nil)
+		gc.Regalloc(&rr, ir.Types[ir.TINT32], nil)
 		gins(x86.AMOVL, &lo1, &rr)
 		gins(x86.ACMPL, &rr, &lo2)
 		gc.Regfree(&rr)
@@ -6,101 +6,40 @@ package x86
 
 import (
 	"cmd/compile/internal/gc"
+	"cmd/compile/internal/ir"
 	"cmd/internal/obj"
 	"cmd/internal/obj/x86"
 )
 
-func defframe(ptxt *obj.Prog) {
-	// fill in argument size, stack size
-	ptxt.To.Type = obj.TYPE_TEXTSIZE
-
-	ptxt.To.Val = int32(gc.Rnd(gc.Curfn.Type.Argwid, int64(gc.Widthptr)))
-	frame := uint32(gc.Rnd(gc.Stksize+gc.Maxarg, int64(gc.Widthreg)))
-	ptxt.To.Offset = int64(frame)
-
-	// insert code to zero ambiguously live variables
-	// so that the garbage collector only sees initialized values
-	// when it looks for pointers.
-	p := ptxt
-
-	hi := int64(0)
-	lo := hi
-	ax := uint32(0)
-	for _, n := range gc.Curfn.Func.Dcl {
-		if !n.Name.Needzero {
-			continue
-		}
-		if n.Class != gc.PAUTO {
-			gc.Fatalf("needzero class %d", n.Class)
-		}
-		if n.Type.Width%int64(gc.Widthptr) != 0 || n.Xoffset%int64(gc.Widthptr) != 0 || n.Type.Width == 0 {
-			gc.Fatalf("var %v has size %d offset %d", gc.Nconv(n, gc.FmtLong), int(n.Type.Width), int(n.Xoffset))
-		}
-		if lo != hi && n.Xoffset+n.Type.Width == lo-int64(2*gc.Widthptr) {
-			// merge with range we already have
-			lo = n.Xoffset
-
-			continue
-		}
-
-		// zero old range
-		p = zerorange(p, int64(frame), lo, hi, &ax)
-
-		// set new range
-		hi = n.Xoffset + n.Type.Width
-
-		lo = n.Xoffset
-	}
-
-	// zero final range
-	zerorange(p, int64(frame), lo, hi, &ax)
-}
-
-func zerorange(p *obj.Prog, frame int64, lo int64, hi int64, ax *uint32) *obj.Prog {
+func zerorange(p *obj.Prog, frame int64, lo int64, hi int64, state *uint32) *obj.Prog {
 	cnt := hi - lo
 	if cnt == 0 {
 		return p
 	}
-	if *ax == 0 {
-		p = appendpp(p, x86.AMOVL, obj.TYPE_CONST, 0, 0, obj.TYPE_REG, x86.REG_AX, 0)
-		*ax = 1
+	if *state == 0 {
+		p = gc.Appendpp(p, x86.AMOVL, obj.TYPE_CONST, 0, 0, obj.TYPE_REG, x86.REG_AX, 0)
+		*state = 1
 	}
 
 	if cnt <= int64(4*gc.Widthreg) {
 		for i := int64(0); i < cnt; i += int64(gc.Widthreg) {
-			p = appendpp(p, x86.AMOVL, obj.TYPE_REG, x86.REG_AX, 0, obj.TYPE_MEM, x86.REG_SP, frame+lo+i)
+			p = gc.Appendpp(p, x86.AMOVL, obj.TYPE_REG, x86.REG_AX, 0, obj.TYPE_MEM, x86.REG_SP, frame+lo+i)
 		}
 	} else if !gc.Nacl && cnt <= int64(128*gc.Widthreg) {
-		p = appendpp(p, x86.ALEAL, obj.TYPE_MEM, x86.REG_SP, frame+lo, obj.TYPE_REG, x86.REG_DI, 0)
-		p = appendpp(p, obj.ADUFFZERO, obj.TYPE_NONE, 0, 0, obj.TYPE_ADDR, 0, 1*(128-cnt/int64(gc.Widthreg)))
+		p = gc.Appendpp(p, x86.ALEAL, obj.TYPE_MEM, x86.REG_SP, frame+lo, obj.TYPE_REG, x86.REG_DI, 0)
+		p = gc.Appendpp(p, obj.ADUFFZERO, obj.TYPE_NONE, 0, 0, obj.TYPE_ADDR, 0, 1*(128-cnt/int64(gc.Widthreg)))
 		p.To.Sym = gc.Linksym(gc.Pkglookup("duffzero", gc.Runtimepkg))
 	} else {
-		p = appendpp(p, x86.AMOVL, obj.TYPE_CONST, 0, cnt/int64(gc.Widthreg), obj.TYPE_REG, x86.REG_CX, 0)
-		p = appendpp(p, x86.ALEAL, obj.TYPE_MEM, x86.REG_SP, frame+lo, obj.TYPE_REG, x86.REG_DI, 0)
-		p = appendpp(p, x86.AREP, obj.TYPE_NONE, 0, 0, obj.TYPE_NONE, 0, 0)
-		p = appendpp(p, x86.ASTOSL, obj.TYPE_NONE, 0, 0, obj.TYPE_NONE, 0, 0)
+		p = gc.Appendpp(p, x86.AMOVL, obj.TYPE_CONST, 0, cnt/int64(gc.Widthreg), obj.TYPE_REG, x86.REG_CX, 0)
+		p = gc.Appendpp(p, x86.ALEAL, obj.TYPE_MEM, x86.REG_SP, frame+lo, obj.TYPE_REG, x86.REG_DI, 0)
+		p = gc.Appendpp(p, x86.AREP, obj.TYPE_NONE, 0, 0, obj.TYPE_NONE, 0, 0)
+		p = gc.Appendpp(p, x86.ASTOSL, obj.TYPE_NONE, 0, 0, obj.TYPE_NONE, 0, 0)
 	}
 
 	return p
 }
 
-func appendpp(p *obj.Prog, as obj.As, ftype obj.AddrType, freg int, foffset int64, ttype obj.AddrType, treg int, toffset int64) *obj.Prog {
-	q := gc.Ctxt.NewProg()
-	gc.Clearp(q)
-	q.As = as
-	q.Lineno = p.Lineno
-	q.From.Type = ftype
-	q.From.Reg = int16(freg)
-	q.From.Offset = foffset
-	q.To.Type = ttype
-	q.To.Reg = int16(treg)
-	q.To.Offset = toffset
-	q.Link = p.Link
-	p.Link = q
-	return q
-}
-
-func clearfat(nl *gc.Node) {
+func clearfat(nl *ir.Node) {
 	/* clear a fat object */
 	if gc.Debug['g'] != 0 {
 		gc.Dump("\nclearfat", nl)
@@ -123,20 +62,20 @@ func clearfat(nl *gc.Node) {
 		// than the unrolled STOSL loop.
 		// NOTE: Must use agen, not igen, so that optimizer sees address
 		// being taken. We are not writing on field boundaries.
-		var n1 gc.Node
-		gc.Regalloc(&n1, gc.Types[gc.Tptr], nil)
+		var n1 ir.Node
+		gc.Regalloc(&n1, ir.Types[gc.Tptr], nil)
 
 		gc.Agen(nl, &n1)
-		n1.Op = gc.OINDREG
-		var z gc.Node
-		gc.Nodconst(&z, gc.Types[gc.TUINT64], 0)
+		n1.Op = ir.OINDREG
+		var z ir.Node
+		gc.Nodconst(&z, ir.Types[ir.TUINT64], 0)
 		for ; q > 0; q-- {
 			n1.Type = z.Type
 			gins(x86.AMOVL, &z, &n1)
 			n1.Xoffset += 4
 		}
 
-		gc.Nodconst(&z, gc.Types[gc.TUINT8], 0)
+		gc.Nodconst(&z, ir.Types[ir.TUINT8], 0)
 		for ; c > 0; c-- {
 			n1.Type = z.Type
 			gins(x86.AMOVB, &z, &n1)
@@ -147,16 +86,16 @@ func clearfat(nl *gc.Node) {
 		return
 	}
 
-	var n1 gc.Node
-	gc.Nodreg(&n1, gc.Types[gc.Tptr], x86.REG_DI)
+	var n1 ir.Node
+	gc.Nodreg(&n1, ir.Types[gc.Tptr], x86.REG_DI)
 	gc.Agen(nl, &n1)
 	gconreg(x86.AMOVL, 0, x86.REG_AX)
 
-	if q > 128 || (q >= 4 && gc.Nacl) {
+	if q > uint32(gc.Thearch.Duffzero.Max) || (q >= uint32(gc.Thearch.Duffzero.Min) && gc.Nacl) {
 		gconreg(x86.AMOVL, int64(q), x86.REG_CX)
 		gins(x86.AREP, nil, nil)   // repeat
 		gins(x86.ASTOSL, nil, nil) // STOL AL,*(DI)+
-	} else if q >= 4 {
+	} else if q >= uint32(gc.Thearch.Duffzero.Min) {
 		p := gins(obj.ADUFFZERO, nil, nil)
 		p.To.Type = obj.TYPE_ADDR
 		p.To.Sym = gc.Linksym(gc.Pkglookup("duffzero", gc.Runtimepkg))
@@ -176,7 +115,7 @@ func clearfat(nl *gc.Node) {
 	}
 }
 
-var panicdiv *gc.Node
+var panicdiv *ir.Node
 
 /*
  * generate division.
@@ -188,7 +127,7 @@ var panicdiv *gc.Node
  *	res = nl % nr
  * according to op.
  */
-func dodiv(op gc.Op, nl *gc.Node, nr *gc.Node, res *gc.Node, ax *gc.Node, dx *gc.Node) {
+func dodiv(op ir.Op, nl *ir.Node, nr *ir.Node, res *ir.Node, ax *ir.Node, dx *ir.Node) {
 	// Have to be careful about handling
 	// most negative int divided by -1 correctly.
 	// The hardware will trap.
@@ -203,30 +142,30 @@ func dodiv(op gc.Op, nl *gc.Node, nr *gc.Node, res *gc.Node, ax *gc.Node, dx *gc
 	check := false
 	if gc.Issigned[t.Etype] {
 		check = true
-		if gc.Isconst(nl, gc.CTINT) && nl.Int() != -1<<uint64(t.Width*8-1) {
+		if ir.Isconst(nl, ir.CTINT) && nl.Int() != -1<<uint64(t.Width*8-1) {
 			check = false
-		} else if gc.Isconst(nr, gc.CTINT) && nr.Int() != -1 {
+		} else if ir.Isconst(nr, ir.CTINT) && nr.Int() != -1 {
 			check = false
 		}
 	}
 
 	if t.Width < 4 {
 		if gc.Issigned[t.Etype] {
-			t = gc.Types[gc.TINT32]
+			t = ir.Types[ir.TINT32]
 		} else {
-			t = gc.Types[gc.TUINT32]
+			t = ir.Types[ir.TUINT32]
 		}
 		check = false
 	}
 
-	var t1 gc.Node
+	var t1 ir.Node
 	gc.Tempname(&t1, t)
-	var t2 gc.Node
+	var t2 ir.Node
 	gc.Tempname(&t2, t)
 	if t0 != t {
-		var t3 gc.Node
+		var t3 ir.Node
 		gc.Tempname(&t3, t0)
-		var t4 gc.Node
+		var t4 ir.Node
 		gc.Tempname(&t4, t0)
 		gc.Cgen(nl, &t3)
 		gc.Cgen(nr, &t4)
@@ -240,7 +179,7 @@ func dodiv(op gc.Op, nl *gc.Node, nr *gc.Node, res *gc.Node, ax *gc.Node, dx *gc
 		gc.Cgen(nr, &t2)
 	}
 
-	var n1 gc.Node
+	var n1 ir.Node
 	if !gc.Samereg(ax, res) && !gc.Samereg(dx, res) {
 		gc.Regalloc(&n1, t, res)
 	} else {
@@ -249,15 +188,15 @@ func dodiv(op gc.Op, nl *gc.Node, nr *gc.Node, res *gc.Node, ax *gc.Node, dx *gc
 	gmove(&t2, &n1)
 	gmove(&t1, ax)
 	var p2 *obj.Prog
-	var n4 gc.Node
+	var n4 ir.Node
 	if gc.Nacl {
 		// Native Client does not relay the divide-by-zero trap
 		// to the executing program, so we must insert a check
 		// for ourselves.
 		gc.Nodconst(&n4, t, 0)
 
-		gins(optoas(gc.OCMP, t), &n1, &n4)
-		p1 := gc.Gbranch(optoas(gc.ONE, t), nil, +1)
+		gins(optoas(ir.OCMP, t), &n1, &n4)
+		p1 := gc.Gbranch(optoas(ir.ONE, t), nil, +1)
 		if panicdiv == nil {
 			panicdiv = gc.Sysfunc("panicdivide")
 		}
@@ -267,11 +206,11 @@ func dodiv(op gc.Op, nl *gc.Node, nr *gc.Node, res *gc.Node, ax *gc.Node, dx *gc
 
 	if check {
 		gc.Nodconst(&n4, t, -1)
-		gins(optoas(gc.OCMP, t), &n1, &n4)
-		p1 := gc.Gbranch(optoas(gc.ONE, t), nil, +1)
-		if op == gc.ODIV {
+		gins(optoas(ir.OCMP, t), &n1, &n4)
+		p1 := gc.Gbranch(optoas(ir.ONE, t), nil, +1)
+		if op == ir.ODIV {
 			// a / (-1) is -a.
-			gins(optoas(gc.OMINUS, t), nil, ax)
+			gins(optoas(ir.OMINUS, t), nil, ax)
 
 			gmove(ax, res)
 		} else {
@@ -286,16 +225,16 @@ func dodiv(op gc.Op, nl *gc.Node, nr *gc.Node, res *gc.Node, ax *gc.Node, dx *gc
 	}
 
 	if !gc.Issigned[t.Etype] {
-		var nz gc.Node
+		var nz ir.Node
 		gc.Nodconst(&nz, t, 0)
 		gmove(&nz, dx)
 	} else {
-		gins(optoas(gc.OEXTEND, t), nil, nil)
+		gins(optoas(ir.OEXTEND, t), nil, nil)
 	}
 	gins(optoas(op, t), &n1, nil)
 	gc.Regfree(&n1)
 
-	if op == gc.ODIV {
+	if op == ir.ODIV {
 		gmove(ax, res)
 	} else {
 		gmove(dx, res)
@@ -305,27 +244,27 @@ func dodiv(op gc.Op, nl *gc.Node, nr *gc.Node, res *gc.Node, ax *gc.Node, dx *gc
 	}
 }
 
-func savex(dr int, x *gc.Node, oldx *gc.Node, res *gc.Node, t *gc.Type) {
+func savex(dr int, x *ir.Node, oldx *ir.Node, res *ir.Node, t *ir.Type) {
 	r := gc.GetReg(dr)
-	gc.Nodreg(x, gc.Types[gc.TINT32], dr)
+	gc.Nodreg(x, ir.Types[ir.TINT32], dr)
 
 	// save current ax and dx if they are live
 	// and not the destination
-	*oldx = gc.Node{}
+	*oldx = ir.Node{}
 
 	if r > 0 && !gc.Samereg(x, res) {
-		gc.Tempname(oldx, gc.Types[gc.TINT32])
+		gc.Tempname(oldx, ir.Types[ir.TINT32])
 		gmove(x, oldx)
 	}
 
 	gc.Regalloc(x, t, x)
 }
 
-func restx(x *gc.Node, oldx *gc.Node) {
+func restx(x *ir.Node, oldx *ir.Node) {
 	gc.Regfree(x)
 
 	if oldx.Op != 0 {
-		x.Type = gc.Types[gc.TINT32]
+		x.Type = ir.Types[ir.TINT32]
 		gmove(oldx, x)
 	}
 }
@@ -335,22 +274,22 @@ func restx(x *gc.Node, oldx *gc.Node) {
  *	res = nl / nr
  *	res = nl % nr
  */
-func cgen_div(op gc.Op, nl *gc.Node, nr *gc.Node, res *gc.Node) {
+func cgen_div(op ir.Op, nl *ir.Node, nr *ir.Node, res *ir.Node) {
 	if gc.Is64(nl.Type) {
 		gc.Fatalf("cgen_div %v", nl.Type)
 	}
 
-	var t *gc.Type
+	var t *ir.Type
 	if gc.Issigned[nl.Type.Etype] {
-		t = gc.Types[gc.TINT32]
+		t = ir.Types[ir.TINT32]
 	} else {
-		t = gc.Types[gc.TUINT32]
+		t = ir.Types[ir.TUINT32]
 	}
-	var ax gc.Node
-	var oldax gc.Node
+	var ax ir.Node
+	var oldax ir.Node
 	savex(x86.REG_AX, &ax, &oldax, res, t)
-	var olddx gc.Node
-	var dx gc.Node
+	var olddx ir.Node
+	var dx ir.Node
 	savex(x86.REG_DX, &dx, &olddx, res, t)
 	dodiv(op, nl, nr, res, &ax, &dx)
 	restx(&dx, &olddx)
@@ -362,7 +301,7 @@ func cgen_div(op gc.Op, nl *gc.Node, nr *gc.Node, res *gc.Node) {
  *	res = nl << nr
  *	res = nl >> nr
  */
-func cgen_shift(op gc.Op, bounded bool, nl *gc.Node, nr *gc.Node, res *gc.Node) {
+func cgen_shift(op ir.Op, bounded bool, nl *ir.Node, nr *ir.Node, res *ir.Node) {
 	if nl.Type.Width > 4 {
 		gc.Fatalf("cgen_shift %v", nl.Type)
 	}
@@ -371,11 +310,11 @@ func cgen_shift(op gc.Op, bounded bool, nl *gc.Node, nr *gc.Node, res *gc.Node)
 
 	a := optoas(op, nl.Type)
 
-	if nr.Op == gc.OLITERAL {
-		var n2 gc.Node
+	if nr.Op == ir.OLITERAL {
+		var n2 ir.Node
 		gc.Tempname(&n2, nl.Type)
 		gc.Cgen(nl, &n2)
-		var n1 gc.Node
+		var n1 ir.Node
 		gc.Regalloc(&n1, nl.Type, res)
 		gmove(&n2, &n1)
 		sc := uint64(nr.Int())
@@ -392,25 +331,25 @@ func cgen_shift(op gc.Op, bounded bool, nl *gc.Node, nr *gc.Node, res *gc.Node)
 		return
 	}
 
-	var oldcx gc.Node
-	var cx gc.Node
-	gc.Nodreg(&cx, gc.Types[gc.TUINT32], x86.REG_CX)
+	var oldcx ir.Node
+	var cx ir.Node
+	gc.Nodreg(&cx, ir.Types[ir.TUINT32], x86.REG_CX)
 	if gc.GetReg(x86.REG_CX) > 1 && !gc.Samereg(&cx, res) {
-		gc.Tempname(&oldcx, gc.Types[gc.TUINT32])
+		gc.Tempname(&oldcx, ir.Types[ir.TUINT32])
 		gmove(&cx, &oldcx)
 	}
 
-	var n1 gc.Node
-	var nt gc.Node
+	var n1 ir.Node
+	var nt ir.Node
 	if nr.Type.Width > 4 {
 		gc.Tempname(&nt, nr.Type)
 		n1 = nt
 	} else {
-		gc.Nodreg(&n1, gc.Types[gc.TUINT32], x86.REG_CX)
+		gc.Nodreg(&n1, ir.Types[ir.TUINT32], x86.REG_CX)
 		gc.Regalloc(&n1, nr.Type, &n1) // to hold the shift type in CX
 	}
 
-	var n2 gc.Node
+	var n2 ir.Node
 	if gc.Samereg(&cx, res) {
 		gc.Regalloc(&n2, nl.Type, nil)
 	} else {
@@ -428,11 +367,11 @@ func cgen_shift(op gc.Op, bounded bool, nl *gc.Node, nr *gc.Node, res *gc.Node)
 	if bounded {
 		if nr.Type.Width > 4 {
 			// delayed reg alloc
-			gc.Nodreg(&n1, gc.Types[gc.TUINT32], x86.REG_CX)
+			gc.Nodreg(&n1, ir.Types[ir.TUINT32], x86.REG_CX)
 
-			gc.Regalloc(&n1, gc.Types[gc.TUINT32], &n1) // to hold the shift type in CX
-			var lo gc.Node
-			var hi gc.Node
+			gc.Regalloc(&n1, ir.Types[ir.TUINT32], &n1) // to hold the shift type in CX
+			var lo ir.Node
+			var hi ir.Node
 			split64(&nt, &lo, &hi)
 			gmove(&lo, &n1)
 			splitclean()
@@ -441,25 +380,25 @@ func cgen_shift(op gc.Op, bounded bool, nl *gc.Node, nr *gc.Node, res *gc.Node)
 		var p1 *obj.Prog
 		if nr.Type.Width > 4 {
 			// delayed reg alloc
-			gc.Nodreg(&n1, gc.Types[gc.TUINT32], x86.REG_CX)
+			gc.Nodreg(&n1, ir.Types[ir.TUINT32], x86.REG_CX)
 
-			gc.Regalloc(&n1, gc.Types[gc.TUINT32], &n1) // to hold the shift type in CX
-			var lo gc.Node
-			var hi gc.Node
+			gc.Regalloc(&n1, ir.Types[ir.TUINT32], &n1) // to hold the shift type in CX
+			var lo ir.Node
+			var hi ir.Node
 			split64(&nt, &lo, &hi)
 			gmove(&lo, &n1)
-			gins(optoas(gc.OCMP, gc.Types[gc.TUINT32]), &hi, ncon(0))
-			p2 := gc.Gbranch(optoas(gc.ONE, gc.Types[gc.TUINT32]), nil, +1)
-			gins(optoas(gc.OCMP, gc.Types[gc.TUINT32]), &n1, ncon(uint32(w)))
-			p1 = gc.Gbranch(optoas(gc.OLT, gc.Types[gc.TUINT32]), nil, +1)
+			gins(optoas(ir.OCMP, ir.Types[ir.TUINT32]), &hi, ncon(0))
+			p2 := gc.Gbranch(optoas(ir.ONE, ir.Types[ir.TUINT32]), nil, +1)
+			gins(optoas(ir.OCMP, ir.Types[ir.TUINT32]), &n1, ncon(uint32(w)))
+			p1 = gc.Gbranch(optoas(ir.OLT, ir.Types[ir.TUINT32]), nil, +1)
 			splitclean()
 			gc.Patch(p2, gc.Pc)
 		} else {
-			gins(optoas(gc.OCMP, nr.Type), &n1, ncon(uint32(w)))
-			p1 = gc.Gbranch(optoas(gc.OLT, gc.Types[gc.TUINT32]), nil, +1)
+			gins(optoas(ir.OCMP, nr.Type), &n1, ncon(uint32(w)))
+			p1 = gc.Gbranch(optoas(ir.OLT, ir.Types[ir.TUINT32]), nil, +1)
 		}
 
-		if op == gc.ORSH && gc.Issigned[nl.Type.Etype] {
+		if op == ir.ORSH && gc.Issigned[nl.Type.Etype] {
 			gins(a, ncon(uint32(w)-1), &n2)
 		} else {
 			gmove(ncon(0), &n2)
@@ -486,16 +425,16 @@ func cgen_shift(op gc.Op, bounded bool, nl *gc.Node, nr *gc.Node, res *gc.Node)
  * there is no 2-operand byte multiply instruction so
  * we do a full-width multiplication and truncate afterwards.
  */
-func cgen_bmul(op gc.Op, nl *gc.Node, nr *gc.Node, res *gc.Node) bool {
+func cgen_bmul(op ir.Op, nl *ir.Node, nr *ir.Node, res *ir.Node) bool {
 	if optoas(op, nl.Type) != x86.AIMULB {
 		return false
 	}
 
 	// copy from byte to full registers
-	t := gc.Types[gc.TUINT32]
+	t := ir.Types[ir.TUINT32]
 
 	if gc.Issigned[nl.Type.Etype] {
-		t = gc.Types[gc.TINT32]
+		t = ir.Types[ir.TINT32]
 	}
 
 	// largest ullman on left.
@@ -503,13 +442,13 @@ func cgen_bmul(op gc.Op, nl *gc.Node, nr *gc.Node, res *gc.Node) bool {
 		nl, nr = nr, nl
 	}
 
-	var nt gc.Node
+	var nt ir.Node
 	gc.Tempname(&nt, nl.Type)
 	gc.Cgen(nl, &nt)
-	var n1 gc.Node
+	var n1 ir.Node
 	gc.Regalloc(&n1, t, res)
 	gc.Cgen(nr, &n1)
-	var n2 gc.Node
+	var n2 ir.Node
 	gc.Regalloc(&n2, t, nil)
 	gmove(&nt, &n2)
 	a := optoas(op, t)
@@ -525,12 +464,12 @@ func cgen_bmul(op gc.Op, nl *gc.Node, nr *gc.Node, res *gc.Node) bool {
  * generate high multiply:
  *   res = (nl*nr) >> width
  */
-func cgen_hmul(nl *gc.Node, nr *gc.Node, res *gc.Node) {
-	var n1 gc.Node
-	var n2 gc.Node
+func cgen_hmul(nl *ir.Node, nr *ir.Node, res *ir.Node) {
+	var n1 ir.Node
+	var n2 ir.Node
 
 	t := nl.Type
-	a := optoas(gc.OHMUL, t)
+	a := optoas(ir.OHMUL, t)
 
 	// gen nl in n1.
 	gc.Tempname(&n1, t)
@@ -540,9 +479,9 @@ func cgen_hmul(nl *gc.Node, nr *gc.Node, res *gc.Node) {
 	gc.Regalloc(&n2, t, res)
 	gc.Cgen(nr, &n2)
 
-	var ax, oldax, dx, olddx gc.Node
-	savex(x86.REG_AX, &ax, &oldax, res, gc.Types[gc.TUINT32])
-	savex(x86.REG_DX, &dx, &olddx, res, gc.Types[gc.TUINT32])
+	var ax, oldax, dx, olddx ir.Node
+	savex(x86.REG_AX, &ax, &oldax, res, ir.Types[ir.TUINT32])
+	savex(x86.REG_DX, &dx, &olddx, res, ir.Types[ir.TUINT32])
 
 	gmove(&n2, &ax)
 	gins(a, &n1, nil)
@@ -550,7 +489,7 @@ func cgen_hmul(nl *gc.Node, nr *gc.Node, res *gc.Node) {
 
 	if t.Width == 1 {
 		// byte multiply behaves differently.
-		var byteAH, byteDX gc.Node
+		var byteAH, byteDX ir.Node
 		gc.Nodreg(&byteAH, t, x86.REG_AH)
 		gc.Nodreg(&byteDX, t, x86.REG_DX)
 		gmove(&byteAH, &byteDX)
@@ -565,14 +504,14 @@ func cgen_hmul(nl *gc.Node, nr *gc.Node, res *gc.Node) {
 /*
  * generate floating-point operation.
  */
-func cgen_float(n *gc.Node, res *gc.Node) {
+func cgen_float(n *ir.Node, res *ir.Node) {
 	nl := n.Left
 	switch n.Op {
-	case gc.OEQ,
-		gc.ONE,
-		gc.OLT,
-		gc.OLE,
-		gc.OGE:
+	case ir.OEQ,
+		ir.ONE,
+		ir.OLT,
+		ir.OLE,
+		ir.OGE:
 		p1 := gc.Gbranch(obj.AJMP, nil, 0)
 		p2 := gc.Pc
 		gmove(gc.Nodbool(true), res)
@@ -583,19 +522,19 @@ func cgen_float(n *gc.Node, res *gc.Node) {
 		gc.Patch(p3, gc.Pc)
 		return
 
-	case gc.OPLUS:
+	case ir.OPLUS:
 		gc.Cgen(nl, res)
 		return
 
-	case gc.OCONV:
+	case ir.OCONV:
 		if gc.Eqtype(n.Type, nl.Type) || gc.Noconv(n.Type, nl.Type) {
 			gc.Cgen(nl, res)
 			return
 		}
 
-		var n2 gc.Node
+		var n2 ir.Node
 		gc.Tempname(&n2, n.Type)
-		var n1 gc.Node
+		var n1 ir.Node
 		gc.Mgen(nl, &n1, res)
 		gmove(&n1, &n2)
 		gmove(&n2, res)
@@ -611,9 +550,9 @@ func cgen_float(n *gc.Node, res *gc.Node) {
 }
 
 // floating-point.  387 (not SSE2)
-func cgen_float387(n *gc.Node, res *gc.Node) {
-	var f0 gc.Node
-	var f1 gc.Node
+func cgen_float387(n *ir.Node, res *ir.Node) {
+	var f0 ir.Node
+	var f1 ir.Node
 
 	nl := n.Left
 	nr := n.Right
@@ -646,14 +585,14 @@ func cgen_float387(n *gc.Node, res *gc.Node) {
 	// unary
 	gc.Cgen(nl, &f0)
 
-	if n.Op != gc.OCONV && n.Op != gc.OPLUS {
+	if n.Op != ir.OCONV && n.Op != ir.OPLUS {
 		gins(foptoas(n.Op, n.Type, 0), nil, nil)
 	}
 	gmove(&f0, res)
 	return
 }
 
-func cgen_floatsse(n *gc.Node, res *gc.Node) {
+func cgen_floatsse(n *ir.Node, res *ir.Node) {
 	var a obj.As
 
 	nl := n.Left
@@ -664,41 +603,41 @@ func cgen_floatsse(n *gc.Node, res *gc.Node) {
 		gc.Fatalf("cgen_floatsse %v", gc.Oconv(n.Op, 0))
 		return
 
-	case gc.OMINUS,
-		gc.OCOM:
+	case ir.OMINUS,
+		ir.OCOM:
 		nr = gc.NegOne(n.Type)
-		a = foptoas(gc.OMUL, nl.Type, 0)
+		a = foptoas(ir.OMUL, nl.Type, 0)
 		goto sbop
 
 		// symmetric binary
-	case gc.OADD,
-		gc.OMUL:
+	case ir.OADD,
+		ir.OMUL:
 		a = foptoas(n.Op, nl.Type, 0)
 
 		goto sbop
 
 		// asymmetric binary
-	case gc.OSUB,
-		gc.OMOD,
-		gc.ODIV:
+	case ir.OSUB,
+		ir.OMOD,
+		ir.ODIV:
 		a = foptoas(n.Op, nl.Type, 0)
 
 		goto abop
 	}
 
 sbop: // symmetric binary
-	if nl.Ullman < nr.Ullman || nl.Op == gc.OLITERAL {
+	if nl.Ullman < nr.Ullman || nl.Op == ir.OLITERAL {
 		nl, nr = nr, nl
 	}
 
 abop: // asymmetric binary
 	if nl.Ullman >= nr.Ullman {
-		var nt gc.Node
+		var nt ir.Node
 		gc.Tempname(&nt, nl.Type)
 		gc.Cgen(nl, &nt)
-		var n2 gc.Node
+		var n2 ir.Node
 		gc.Mgen(nr, &n2, nil)
-		var n1 gc.Node
+		var n1 ir.Node
 		gc.Regalloc(&n1, nl.Type, res)
 		gmove(&nt, &n1)
 		gins(a, &n2, &n1)
@@ -706,10 +645,10 @@ abop: // asymmetric binary
 		gc.Regfree(&n1)
 		gc.Mfree(&n2)
 	} else {
-		var n2 gc.Node
+		var n2 ir.Node
 		gc.Regalloc(&n2, nr.Type, res)
 		gc.Cgen(nr, &n2)
-		var n1 gc.Node
+		var n1 ir.Node
 		gc.Regalloc(&n1, nl.Type, nil)
 		gc.Cgen(nl, &n1)
 		gins(a, &n2, &n1)
@@ -721,7 +660,7 @@ abop: // asymmetric binary
 	return
 }
 
-func bgen_float(n *gc.Node, wantTrue bool, likely int, to *obj.Prog) {
+func bgen_float(n *ir.Node, wantTrue bool, likely int, to *obj.Prog) {
 	nl := n.Left
 	nr := n.Right
 	op := n.Op
@@ -741,17 +680,17 @@ func bgen_float(n *gc.Node, wantTrue bool, likely int, to *obj.Prog) {
 
 	if gc.Thearch.Use387 {
 		op = gc.Brrev(op) // because the args are stacked
-		if op == gc.OGE || op == gc.OGT {
+		if op == ir.OGE || op == ir.OGT {
 			// only < and <= work right with NaN; reverse if needed
 			nl, nr = nr, nl
 			op = gc.Brrev(op)
 		}
 
-		var ax, n2, tmp gc.Node
+		var ax, n2, tmp ir.Node
 		gc.Nodreg(&tmp, nr.Type, x86.REG_F0)
 		gc.Nodreg(&n2, nr.Type, x86.REG_F0+1)
-		gc.Nodreg(&ax, gc.Types[gc.TUINT16], x86.REG_AX)
-		if gc.Simsimtype(nr.Type) == gc.TFLOAT64 {
+		gc.Nodreg(&ax, ir.Types[ir.TUINT16], x86.REG_AX)
+		if gc.Simsimtype(nr.Type) == ir.TFLOAT64 {
 			if nl.Ullman > nr.Ullman {
 				gc.Cgen(nl, &tmp)
 				gc.Cgen(nr, &tmp)
@@ -768,11 +707,11 @@ func bgen_float(n *gc.Node, wantTrue bool, likely int, to *obj.Prog) {
 			// all the other ops have the same problem.
 			// We need to figure out what the right general
 			// solution is, besides telling people to use float64.
-			var t1 gc.Node
-			gc.Tempname(&t1, gc.Types[gc.TFLOAT32])
+			var t1 ir.Node
+			gc.Tempname(&t1, ir.Types[ir.TFLOAT32])
 
-			var t2 gc.Node
-			gc.Tempname(&t2, gc.Types[gc.TFLOAT32])
+			var t2 ir.Node
+			gc.Tempname(&t2, ir.Types[ir.TFLOAT32])
 			gc.Cgen(nr, &t1)
 			gc.Cgen(nl, &t2)
 			gmove(&t2, &tmp)
@@ -789,40 +728,40 @@ func bgen_float(n *gc.Node, wantTrue bool, likely int, to *obj.Prog) {
 			nr = gc.CgenTemp(nr)
 		}
 
-		var n2 gc.Node
+		var n2 ir.Node
 		gc.Regalloc(&n2, nr.Type, nil)
 		gmove(nr, &n2)
 		nr = &n2
 
-		if nl.Op != gc.OREGISTER {
-			var n3 gc.Node
+		if nl.Op != ir.OREGISTER {
+			var n3 ir.Node
 			gc.Regalloc(&n3, nl.Type, nil)
 			gmove(nl, &n3)
 			nl = &n3
 		}
 
-		if op == gc.OGE || op == gc.OGT {
+		if op == ir.OGE || op == ir.OGT {
 			// only < and <= work right with NopN; reverse if needed
 			nl, nr = nr, nl
 			op = gc.Brrev(op)
 		}
 
-		gins(foptoas(gc.OCMP, nr.Type, 0), nl, nr)
-		if nl.Op == gc.OREGISTER {
+		gins(foptoas(ir.OCMP, nr.Type, 0), nl, nr)
+		if nl.Op == ir.OREGISTER {
 			gc.Regfree(nl)
 		}
 		gc.Regfree(nr)
 	}
 
 	switch op {
-	case gc.OEQ:
+	case ir.OEQ:
 		// neither NE nor P
 		p1 := gc.Gbranch(x86.AJNE, nil, -likely)
 		p2 := gc.Gbranch(x86.AJPS, nil, -likely)
 		gc.Patch(gc.Gbranch(obj.AJMP, nil, 0), to)
 		gc.Patch(p1, gc.Pc)
 		gc.Patch(p2, gc.Pc)
-	case gc.ONE:
+	case ir.ONE:
 		// either NE or P
 		gc.Patch(gc.Gbranch(x86.AJNE, nil, likely), to)
 		gc.Patch(gc.Gbranch(x86.AJPS, nil, likely), to)
@@ -888,7 +827,7 @@ func expandchecks(firstp *obj.Prog) {
 }
 
 // addr += index*width if possible.
-func addindex(index *gc.Node, width int64, addr *gc.Node) bool {
+func addindex(index *ir.Node, width int64, addr *ir.Node) bool {
 	switch width {
 	case 1, 2, 4, 8:
 		p1 := gins(x86.ALEAL, index, addr)
@@ -902,10 +841,10 @@ func addindex(index *gc.Node, width int64, addr *gc.Node) bool {
 }
 
 // res = runtime.getg()
-func getg(res *gc.Node) {
-	var n1 gc.Node
+func getg(res *ir.Node) {
+	var n1 ir.Node
 	gc.Regalloc(&n1, res.Type, res)
-	mov := optoas(gc.OAS, gc.Types[gc.Tptr])
+	mov := optoas(ir.OAS, ir.Types[gc.Tptr])
 	p := gins(mov, nil, &n1)
 	p.From.Type = obj.TYPE_REG
 	p.From.Reg = x86.REG_TLS
@@ -33,6 +33,7 @@ package x86
 import (
 	"cmd/compile/internal/big"
 	"cmd/compile/internal/gc"
+	"cmd/compile/internal/ir"
 	"cmd/internal/obj"
 	"cmd/internal/obj/x86"
 	"fmt"
@@ -53,39 +54,39 @@ const (
 /*
  * return Axxx for Oxxx on type t.
  */
-func optoas(op gc.Op, t *gc.Type) obj.As {
+func optoas(op ir.Op, t *ir.Type) obj.As {
 	if t == nil {
 		gc.Fatalf("optoas: t is nil")
 	}
 
 	// avoid constant conversions in switches below
 	const (
-		OMINUS_  = uint32(gc.OMINUS) << 16
-		OLSH_    = uint32(gc.OLSH) << 16
-		ORSH_    = uint32(gc.ORSH) << 16
-		OADD_    = uint32(gc.OADD) << 16
-		OSUB_    = uint32(gc.OSUB) << 16
-		OMUL_    = uint32(gc.OMUL) << 16
-		ODIV_    = uint32(gc.ODIV) << 16
-		OMOD_    = uint32(gc.OMOD) << 16
-		OOR_     = uint32(gc.OOR) << 16
-		OAND_    = uint32(gc.OAND) << 16
-		OXOR_    = uint32(gc.OXOR) << 16
-		OEQ_     = uint32(gc.OEQ) << 16
-		ONE_     = uint32(gc.ONE) << 16
-		OLT_     = uint32(gc.OLT) << 16
-		OLE_     = uint32(gc.OLE) << 16
-		OGE_     = uint32(gc.OGE) << 16
-		OGT_     = uint32(gc.OGT) << 16
-		OCMP_    = uint32(gc.OCMP) << 16
-		OAS_     = uint32(gc.OAS) << 16
-		OHMUL_   = uint32(gc.OHMUL) << 16
-		OADDR_   = uint32(gc.OADDR) << 16
-		OINC_    = uint32(gc.OINC) << 16
-		ODEC_    = uint32(gc.ODEC) << 16
-		OLROT_   = uint32(gc.OLROT) << 16
-		OEXTEND_ = uint32(gc.OEXTEND) << 16
-		OCOM_    = uint32(gc.OCOM) << 16
+		OMINUS_  = uint32(ir.OMINUS) << 16
+		OLSH_    = uint32(ir.OLSH) << 16
+		ORSH_    = uint32(ir.ORSH) << 16
+		OADD_    = uint32(ir.OADD) << 16
+		OSUB_    = uint32(ir.OSUB) << 16
+		OMUL_    = uint32(ir.OMUL) << 16
+		ODIV_    = uint32(ir.ODIV) << 16
+		OMOD_    = uint32(ir.OMOD) << 16
+		OOR_     = uint32(ir.OOR) << 16
+		OAND_    = uint32(ir.OAND) << 16
+		OXOR_    = uint32(ir.OXOR) << 16
+		OEQ_     = uint32(ir.OEQ) << 16
+		ONE_     = uint32(ir.ONE) << 16
+		OLT_     = uint32(ir.OLT) << 16
+		OLE_     = uint32(ir.OLE) << 16
+		OGE_     = uint32(ir.OGE) << 16
+		OGT_     = uint32(ir.OGT) << 16
+		OCMP_    = uint32(ir.OCMP) << 16
+		OAS_     = uint32(ir.OAS) << 16
+		OHMUL_   = uint32(ir.OHMUL) << 16
+		OADDR_   = uint32(ir.OADDR) << 16
+		OINC_    = uint32(ir.OINC) << 16
+		ODEC_    = uint32(ir.ODEC) << 16
+		OLROT_   = uint32(ir.OLROT) << 16
+		OEXTEND_ = uint32(ir.OEXTEND) << 16
+		OCOM_    = uint32(ir.OCOM) << 16
 	)
 
 	a := obj.AXXX
@@ -93,362 +94,362 @@ func optoas(op gc.Op, t *gc.Type) obj.As {
 	default:
 		gc.Fatalf("optoas: no entry %v-%v", gc.Oconv(op, 0), t)
 
-	case OADDR_ | gc.TPTR32:
+	case OADDR_ | ir.TPTR32:
 		a = x86.ALEAL
 
-	case OEQ_ | gc.TBOOL,
-		OEQ_ | gc.TINT8,
-		OEQ_ | gc.TUINT8,
-		OEQ_ | gc.TINT16,
-		OEQ_ | gc.TUINT16,
-		OEQ_ | gc.TINT32,
-		OEQ_ | gc.TUINT32,
-		OEQ_ | gc.TINT64,
-		OEQ_ | gc.TUINT64,
-		OEQ_ | gc.TPTR32,
-		OEQ_ | gc.TPTR64,
-		OEQ_ | gc.TFLOAT32,
-		OEQ_ | gc.TFLOAT64:
+	case OEQ_ | ir.TBOOL,
+		OEQ_ | ir.TINT8,
+		OEQ_ | ir.TUINT8,
+		OEQ_ | ir.TINT16,
+		OEQ_ | ir.TUINT16,
+		OEQ_ | ir.TINT32,
+		OEQ_ | ir.TUINT32,
+		OEQ_ | ir.TINT64,
+		OEQ_ | ir.TUINT64,
+		OEQ_ | ir.TPTR32,
+		OEQ_ | ir.TPTR64,
+		OEQ_ | ir.TFLOAT32,
+		OEQ_ | ir.TFLOAT64:
 		a = x86.AJEQ
 
-	case ONE_ | gc.TBOOL,
-		ONE_ | gc.TINT8,
-		ONE_ | gc.TUINT8,
-		ONE_ | gc.TINT16,
-		ONE_ | gc.TUINT16,
-		ONE_ | gc.TINT32,
-		ONE_ | gc.TUINT32,
-		ONE_ | gc.TINT64,
-		ONE_ | gc.TUINT64,
-		ONE_ | gc.TPTR32,
-		ONE_ | gc.TPTR64,
-		ONE_ | gc.TFLOAT32,
-		ONE_ | gc.TFLOAT64:
+	case ONE_ | ir.TBOOL,
+		ONE_ | ir.TINT8,
+		ONE_ | ir.TUINT8,
+		ONE_ | ir.TINT16,
+		ONE_ | ir.TUINT16,
+		ONE_ | ir.TINT32,
+		ONE_ | ir.TUINT32,
+		ONE_ | ir.TINT64,
+		ONE_ | ir.TUINT64,
+		ONE_ | ir.TPTR32,
+		ONE_ | ir.TPTR64,
+		ONE_ | ir.TFLOAT32,
+		ONE_ | ir.TFLOAT64:
 		a = x86.AJNE
 
-	case OLT_ | gc.TINT8,
-		OLT_ | gc.TINT16,
-		OLT_ | gc.TINT32,
-		OLT_ | gc.TINT64:
+	case OLT_ | ir.TINT8,
+		OLT_ | ir.TINT16,
+		OLT_ | ir.TINT32,
+		OLT_ | ir.TINT64:
 		a = x86.AJLT
 
-	case OLT_ | gc.TUINT8,
-		OLT_ | gc.TUINT16,
-		OLT_ | gc.TUINT32,
-		OLT_ | gc.TUINT64:
+	case OLT_ | ir.TUINT8,
+		OLT_ | ir.TUINT16,
+		OLT_ | ir.TUINT32,
+		OLT_ | ir.TUINT64:
 		a = x86.AJCS
 
-	case OLE_ | gc.TINT8,
-		OLE_ | gc.TINT16,
-		OLE_ | gc.TINT32,
-		OLE_ | gc.TINT64:
+	case OLE_ | ir.TINT8,
+		OLE_ | ir.TINT16,
+		OLE_ | ir.TINT32,
+		OLE_ | ir.TINT64:
 		a = x86.AJLE
 
-	case OLE_ | gc.TUINT8,
-		OLE_ | gc.TUINT16,
-		OLE_ | gc.TUINT32,
-		OLE_ | gc.TUINT64:
+	case OLE_ | ir.TUINT8,
+		OLE_ | ir.TUINT16,
+		OLE_ | ir.TUINT32,
+		OLE_ | ir.TUINT64:
 		a = x86.AJLS
 
-	case OGT_ | gc.TINT8,
-		OGT_ | gc.TINT16,
-		OGT_ | gc.TINT32,
-		OGT_ | gc.TINT64:
+	case OGT_ | ir.TINT8,
+		OGT_ | ir.TINT16,
+		OGT_ | ir.TINT32,
+		OGT_ | ir.TINT64:
 		a = x86.AJGT
 
-	case OGT_ | gc.TUINT8,
-		OGT_ | gc.TUINT16,
-		OGT_ | gc.TUINT32,
-		OGT_ | gc.TUINT64,
-		OLT_ | gc.TFLOAT32,
-		OLT_ | gc.TFLOAT64:
+	case OGT_ | ir.TUINT8,
+		OGT_ | ir.TUINT16,
+		OGT_ | ir.TUINT32,
+		OGT_ | ir.TUINT64,
+		OLT_ | ir.TFLOAT32,
+		OLT_ | ir.TFLOAT64:
 		a = x86.AJHI
 
-	case OGE_ | gc.TINT8,
-		OGE_ | gc.TINT16,
-		OGE_ | gc.TINT32,
-		OGE_ | gc.TINT64:
+	case OGE_ | ir.TINT8,
+		OGE_ | ir.TINT16,
+		OGE_ | ir.TINT32,
+		OGE_ | ir.TINT64:
 		a = x86.AJGE
 
-	case OGE_ | gc.TUINT8,
-		OGE_ | gc.TUINT16,
-		OGE_ | gc.TUINT32,
-		OGE_ | gc.TUINT64,
-		OLE_ | gc.TFLOAT32,
-		OLE_ | gc.TFLOAT64:
+	case OGE_ | ir.TUINT8,
+		OGE_ | ir.TUINT16,
+		OGE_ | ir.TUINT32,
+		OGE_ | ir.TUINT64,
+		OLE_ | ir.TFLOAT32,
+		OLE_ | ir.TFLOAT64:
 		a = x86.AJCC
 
-	case OCMP_ | gc.TBOOL,
-		OCMP_ | gc.TINT8,
-		OCMP_ | gc.TUINT8:
+	case OCMP_ | ir.TBOOL,
+		OCMP_ | ir.TINT8,
+		OCMP_ | ir.TUINT8:
 		a = x86.ACMPB
 
-	case OCMP_ | gc.TINT16,
-		OCMP_ | gc.TUINT16:
+	case OCMP_ | ir.TINT16,
+		OCMP_ | ir.TUINT16:
 		a = x86.ACMPW
 
-	case OCMP_ | gc.TINT32,
-		OCMP_ | gc.TUINT32,
-		OCMP_ | gc.TPTR32:
+	case OCMP_ | ir.TINT32,
+		OCMP_ | ir.TUINT32,
+		OCMP_ | ir.TPTR32:
 		a = x86.ACMPL
 
-	case OAS_ | gc.TBOOL,
-		OAS_ | gc.TINT8,
-		OAS_ | gc.TUINT8:
+	case OAS_ | ir.TBOOL,
+		OAS_ | ir.TINT8,
+		OAS_ | ir.TUINT8:
 		a = x86.AMOVB
 
-	case OAS_ | gc.TINT16,
-		OAS_ | gc.TUINT16:
+	case OAS_ | ir.TINT16,
+		OAS_ | ir.TUINT16:
 		a = x86.AMOVW
 
-	case OAS_ | gc.TINT32,
-		OAS_ | gc.TUINT32,
-		OAS_ | gc.TPTR32:
+	case OAS_ | ir.TINT32,
+		OAS_ | ir.TUINT32,
+		OAS_ | ir.TPTR32:
 		a = x86.AMOVL
 
-	case OAS_ | gc.TFLOAT32:
+	case OAS_ | ir.TFLOAT32:
 		a = x86.AMOVSS
 
-	case OAS_ | gc.TFLOAT64:
+	case OAS_ | ir.TFLOAT64:
 		a = x86.AMOVSD
 
-	case OADD_ | gc.TINT8,
-		OADD_ | gc.TUINT8:
+	case OADD_ | ir.TINT8,
+		OADD_ | ir.TUINT8:
 		a = x86.AADDB
 
-	case OADD_ | gc.TINT16,
-		OADD_ | gc.TUINT16:
+	case OADD_ | ir.TINT16,
+		OADD_ | ir.TUINT16:
 		a = x86.AADDW
 
-	case OADD_ | gc.TINT32,
-		OADD_ | gc.TUINT32,
-		OADD_ | gc.TPTR32:
+	case OADD_ | ir.TINT32,
+		OADD_ | ir.TUINT32,
+		OADD_ | ir.TPTR32:
 		a = x86.AADDL
 
-	case OSUB_ | gc.TINT8,
-		OSUB_ | gc.TUINT8:
+	case OSUB_ | ir.TINT8,
+		OSUB_ | ir.TUINT8:
 		a = x86.ASUBB
 
-	case OSUB_ | gc.TINT16,
-		OSUB_ | gc.TUINT16:
+	case OSUB_ | ir.TINT16,
+		OSUB_ | ir.TUINT16:
 		a = x86.ASUBW
 
-	case OSUB_ | gc.TINT32,
-		OSUB_ | gc.TUINT32,
-		OSUB_ | gc.TPTR32:
+	case OSUB_ | ir.TINT32,
+		OSUB_ | ir.TUINT32,
+		OSUB_ | ir.TPTR32:
 		a = x86.ASUBL
 
-	case OINC_ | gc.TINT8,
-		OINC_ | gc.TUINT8:
+	case OINC_ | ir.TINT8,
+		OINC_ | ir.TUINT8:
 		a = x86.AINCB
 
-	case OINC_ | gc.TINT16,
-		OINC_ | gc.TUINT16:
+	case OINC_ | ir.TINT16,
+		OINC_ | ir.TUINT16:
 		a = x86.AINCW
 
-	case OINC_ | gc.TINT32,
-		OINC_ | gc.TUINT32,
-		OINC_ | gc.TPTR32:
+	case OINC_ | ir.TINT32,
+		OINC_ | ir.TUINT32,
+		OINC_ | ir.TPTR32:
 		a = x86.AINCL
 
-	case ODEC_ | gc.TINT8,
-		ODEC_ | gc.TUINT8:
+	case ODEC_ | ir.TINT8,
+		ODEC_ | ir.TUINT8:
 		a = x86.ADECB
 
-	case ODEC_ | gc.TINT16,
-		ODEC_ | gc.TUINT16:
+	case ODEC_ | ir.TINT16,
+		ODEC_ | ir.TUINT16:
 		a = x86.ADECW
 
-	case ODEC_ | gc.TINT32,
-		ODEC_ | gc.TUINT32,
-		ODEC_ | gc.TPTR32:
+	case ODEC_ | ir.TINT32,
+		ODEC_ | ir.TUINT32,
+		ODEC_ | ir.TPTR32:
 		a = x86.ADECL
 
-	case OCOM_ | gc.TINT8,
-		OCOM_ | gc.TUINT8:
+	case OCOM_ | ir.TINT8,
+		OCOM_ | ir.TUINT8:
 		a = x86.ANOTB
 
-	case OCOM_ | gc.TINT16,
-		OCOM_ | gc.TUINT16:
+	case OCOM_ | ir.TINT16,
+		OCOM_ | ir.TUINT16:
 		a = x86.ANOTW
 
-	case OCOM_ | gc.TINT32,
-		OCOM_ | gc.TUINT32,
-		OCOM_ | gc.TPTR32:
+	case OCOM_ | ir.TINT32,
+		OCOM_ | ir.TUINT32,
+		OCOM_ | ir.TPTR32:
 		a = x86.ANOTL
 
-	case OMINUS_ | gc.TINT8,
-		OMINUS_ | gc.TUINT8:
+	case OMINUS_ | ir.TINT8,
+		OMINUS_ | ir.TUINT8:
 		a = x86.ANEGB
 
-	case OMINUS_ | gc.TINT16,
-		OMINUS_ | gc.TUINT16:
+	case OMINUS_ | ir.TINT16,
+		OMINUS_ | ir.TUINT16:
 		a = x86.ANEGW
 
-	case OMINUS_ | gc.TINT32,
-		OMINUS_ | gc.TUINT32,
-		OMINUS_ | gc.TPTR32:
+	case OMINUS_ | ir.TINT32,
+		OMINUS_ | ir.TUINT32,
+		OMINUS_ | ir.TPTR32:
 		a = x86.ANEGL
 
-	case OAND_ | gc.TINT8,
-		OAND_ | gc.TUINT8:
+	case OAND_ | ir.TINT8,
+		OAND_ | ir.TUINT8:
 		a = x86.AANDB
 
-	case OAND_ | gc.TINT16,
-		OAND_ | gc.TUINT16:
+	case OAND_ | ir.TINT16,
+		OAND_ | ir.TUINT16:
 		a = x86.AANDW
 
-	case OAND_ | gc.TINT32,
-		OAND_ | gc.TUINT32,
-		OAND_ | gc.TPTR32:
+	case OAND_ | ir.TINT32,
+		OAND_ | ir.TUINT32,
+		OAND_ | ir.TPTR32:
 		a = x86.AANDL
 
-	case OOR_ | gc.TINT8,
-		OOR_ | gc.TUINT8:
+	case OOR_ | ir.TINT8,
+		OOR_ | ir.TUINT8:
 		a = x86.AORB
 
-	case OOR_ | gc.TINT16,
-		OOR_ | gc.TUINT16:
+	case OOR_ | ir.TINT16,
+		OOR_ | ir.TUINT16:
 		a = x86.AORW
 
-	case OOR_ | gc.TINT32,
-		OOR_ | gc.TUINT32,
-		OOR_ | gc.TPTR32:
+	case OOR_ | ir.TINT32,
+		OOR_ | ir.TUINT32,
+		OOR_ | ir.TPTR32:
 		a = x86.AORL
 
-	case OXOR_ | gc.TINT8,
-		OXOR_ | gc.TUINT8:
+	case OXOR_ | ir.TINT8,
+		OXOR_ | ir.TUINT8:
 		a = x86.AXORB
 
-	case OXOR_ | gc.TINT16,
-		OXOR_ | gc.TUINT16:
+	case OXOR_ | ir.TINT16,
+		OXOR_ | ir.TUINT16:
 		a = x86.AXORW
 
-	case OXOR_ | gc.TINT32,
-		OXOR_ | gc.TUINT32,
-		OXOR_ | gc.TPTR32:
+	case OXOR_ | ir.TINT32,
+		OXOR_ | ir.TUINT32,
+		OXOR_ | ir.TPTR32:
 		a = x86.AXORL
 
-	case OLROT_ | gc.TINT8,
-		OLROT_ | gc.TUINT8:
+	case OLROT_ | ir.TINT8,
+		OLROT_ | ir.TUINT8:
 		a = x86.AROLB
 
-	case OLROT_ | gc.TINT16,
-		OLROT_ | gc.TUINT16:
+	case OLROT_ | ir.TINT16,
+		OLROT_ | ir.TUINT16:
 		a = x86.AROLW
 
-	case OLROT_ | gc.TINT32,
-		OLROT_ | gc.TUINT32,
-		OLROT_ | gc.TPTR32:
+	case OLROT_ | ir.TINT32,
+		OLROT_ | ir.TUINT32,
+		OLROT_ | ir.TPTR32:
 		a = x86.AROLL
 
-	case OLSH_ | gc.TINT8,
-		OLSH_ | gc.TUINT8:
+	case OLSH_ | ir.TINT8,
+		OLSH_ | ir.TUINT8:
 		a = x86.ASHLB
 
-	case OLSH_ | gc.TINT16,
-		OLSH_ | gc.TUINT16:
+	case OLSH_ | ir.TINT16,
+		OLSH_ | ir.TUINT16:
 		a = x86.ASHLW
 
-	case OLSH_ | gc.TINT32,
-		OLSH_ | gc.TUINT32,
-		OLSH_ | gc.TPTR32:
+	case OLSH_ | ir.TINT32,
+		OLSH_ | ir.TUINT32,
+		OLSH_ | ir.TPTR32:
 		a = x86.ASHLL
 
-	case ORSH_ | gc.TUINT8:
+	case ORSH_ | ir.TUINT8:
 		a = x86.ASHRB
 
-	case ORSH_ | gc.TUINT16:
+	case ORSH_ | ir.TUINT16:
 		a = x86.ASHRW
 
-	case ORSH_ | gc.TUINT32,
-		ORSH_ | gc.TPTR32:
+	case ORSH_ | ir.TUINT32,
+		ORSH_ | ir.TPTR32:
 		a = x86.ASHRL
 
-	case ORSH_ | gc.TINT8:
+	case ORSH_ | ir.TINT8:
 		a = x86.ASARB
 
-	case ORSH_ | gc.TINT16:
+	case ORSH_ | ir.TINT16:
 		a = x86.ASARW
 
-	case ORSH_ | gc.TINT32:
+	case ORSH_ | ir.TINT32:
 		a = x86.ASARL
 
-	case OHMUL_ | gc.TINT8,
-		OMUL_ | gc.TINT8,
-		OMUL_ | gc.TUINT8:
+	case OHMUL_ | ir.TINT8,
+		OMUL_ | ir.TINT8,
+		OMUL_ | ir.TUINT8:
 		a = x86.AIMULB
 
-	case OHMUL_ | gc.TINT16,
-		OMUL_ | gc.TINT16,
-		OMUL_ | gc.TUINT16:
+	case OHMUL_ | ir.TINT16,
+		OMUL_ | ir.TINT16,
+		OMUL_ | ir.TUINT16:
 		a = x86.AIMULW
 
-	case OHMUL_ | gc.TINT32,
-		OMUL_ | gc.TINT32,
-		OMUL_ | gc.TUINT32,
-		OMUL_ | gc.TPTR32:
+	case OHMUL_ | ir.TINT32,
+		OMUL_ | ir.TINT32,
+		OMUL_ | ir.TUINT32,
+		OMUL_ | ir.TPTR32:
 		a = x86.AIMULL
 
-	case OHMUL_ | gc.TUINT8:
+	case OHMUL_ | ir.TUINT8:
 		a = x86.AMULB
 
-	case OHMUL_ | gc.TUINT16:
+	case OHMUL_ | ir.TUINT16:
 		a = x86.AMULW
 
-	case OHMUL_ | gc.TUINT32,
-		OHMUL_ | gc.TPTR32:
+	case OHMUL_ | ir.TUINT32,
+		OHMUL_ | ir.TPTR32:
 		a = x86.AMULL
 
-	case ODIV_ | gc.TINT8,
-		OMOD_ | gc.TINT8:
+	case ODIV_ | ir.TINT8,
+		OMOD_ | ir.TINT8:
 		a = x86.AIDIVB
 
-	case ODIV_ | gc.TUINT8,
-		OMOD_ | gc.TUINT8:
+	case ODIV_ | ir.TUINT8,
+		OMOD_ | ir.TUINT8:
 		a = x86.ADIVB
 
-	case ODIV_ | gc.TINT16,
-		OMOD_ | gc.TINT16:
+	case ODIV_ | ir.TINT16,
+		OMOD_ | ir.TINT16:
 		a = x86.AIDIVW
 
-	case ODIV_ | gc.TUINT16,
-		OMOD_ | gc.TUINT16:
+	case ODIV_ | ir.TUINT16,
+		OMOD_ | ir.TUINT16:
 		a = x86.ADIVW
 
-	case ODIV_ | gc.TINT32,
-		OMOD_ | gc.TINT32:
+	case ODIV_ | ir.TINT32,
+		OMOD_ | ir.TINT32:
 		a = x86.AIDIVL
 
-	case ODIV_ | gc.TUINT32,
-		ODIV_ | gc.TPTR32,
-		OMOD_ | gc.TUINT32,
-		OMOD_ | gc.TPTR32:
+	case ODIV_ | ir.TUINT32,
+		ODIV_ | ir.TPTR32,
+		OMOD_ | ir.TUINT32,
+		OMOD_ | ir.TPTR32:
 		a = x86.ADIVL
 
-	case OEXTEND_ | gc.TINT16:
+	case OEXTEND_ | ir.TINT16:
 		a = x86.ACWD
 
-	case OEXTEND_ | gc.TINT32:
+	case OEXTEND_ | ir.TINT32:
 		a = x86.ACDQ
 	}
 
 	return a
 }
 
-func foptoas(op gc.Op, t *gc.Type, flg int) obj.As {
+func foptoas(op ir.Op, t *ir.Type, flg int) obj.As {
 	a := obj.AXXX
 	et := gc.Simtype[t.Etype]
 
 	// avoid constant conversions in switches below
 	const (
-		OCMP_   = uint32(gc.OCMP) << 16
-		OAS_    = uint32(gc.OAS) << 16
-		OADD_   = uint32(gc.OADD) << 16
-		OSUB_   = uint32(gc.OSUB) << 16
-		OMUL_   = uint32(gc.OMUL) << 16
-		ODIV_   = uint32(gc.ODIV) << 16
-		OMINUS_ = uint32(gc.OMINUS) << 16
+		OCMP_   = uint32(ir.OCMP) << 16
+		OAS_    = uint32(ir.OAS) << 16
+		OADD_   = uint32(ir.OADD) << 16
+		OSUB_   = uint32(ir.OSUB) << 16
+		OMUL_   = uint32(ir.OMUL) << 16
+		ODIV_   = uint32(ir.ODIV) << 16
+		OMINUS_ = uint32(ir.OMINUS) << 16
 	)
 
 	if !gc.Thearch.Use387 {
@@ -456,40 +457,40 @@ func foptoas(op gc.Op, t *gc.Type, flg int) obj.As {
 		default:
 			gc.Fatalf("foptoas-sse: no entry %v-%v", gc.Oconv(op, 0), t)
 
-		case OCMP_ | gc.TFLOAT32:
+		case OCMP_ | ir.TFLOAT32:
 			a = x86.AUCOMISS
 
-		case OCMP_ | gc.TFLOAT64:
+		case OCMP_ | ir.TFLOAT64:
 			a = x86.AUCOMISD
 
-		case OAS_ | gc.TFLOAT32:
+		case OAS_ | ir.TFLOAT32:
 			a = x86.AMOVSS
 
-		case OAS_ | gc.TFLOAT64:
+		case OAS_ | ir.TFLOAT64:
 			a = x86.AMOVSD
 
-		case OADD_ | gc.TFLOAT32:
+		case OADD_ | ir.TFLOAT32:
 			a = x86.AADDSS
 
-		case OADD_ | gc.TFLOAT64:
+		case OADD_ | ir.TFLOAT64:
 			a = x86.AADDSD
 
-		case OSUB_ | gc.TFLOAT32:
+		case OSUB_ | ir.TFLOAT32:
 			a = x86.ASUBSS
 
-		case OSUB_ | gc.TFLOAT64:
+		case OSUB_ | ir.TFLOAT64:
 			a = x86.ASUBSD
 
-		case OMUL_ | gc.TFLOAT32:
+		case OMUL_ | ir.TFLOAT32:
 			a = x86.AMULSS
 
-		case OMUL_ | gc.TFLOAT64:
+		case OMUL_ | ir.TFLOAT64:
 			a = x86.AMULSD
 
-		case ODIV_ | gc.TFLOAT32:
+		case ODIV_ | ir.TFLOAT32:
 			a = x86.ADIVSS
 
-		case ODIV_ | gc.TFLOAT64:
+		case ODIV_ | ir.TFLOAT64:
 			a = x86.ADIVSD
 		}
 
@@ -500,90 +501,90 @@ func foptoas(op gc.Op, t *gc.Type, flg int) obj.As {
 	// two different floating-point registers, not memory.
 	// There the instruction only has a float64 form.
 	if flg&Fpop != 0 {
-		et = gc.TFLOAT64
+		et = ir.TFLOAT64
 	}
 
 	// clear Frev if unneeded
 	switch op {
-	case gc.OADD,
-		gc.OMUL:
+	case ir.OADD,
+		ir.OMUL:
 		flg &^= Frev
 	}
 
 	switch uint32(op)<<16 | (uint32(et)<<8 | uint32(flg)) {
-	case OADD_ | (gc.TFLOAT32<<8 | 0):
+	case OADD_ | (ir.TFLOAT32<<8 | 0):
 		return x86.AFADDF
 
-	case OADD_ | (gc.TFLOAT64<<8 | 0):
+	case OADD_ | (ir.TFLOAT64<<8 | 0):
 		return x86.AFADDD
 
-	case OADD_ | (gc.TFLOAT64<<8 | Fpop):
+	case OADD_ | (ir.TFLOAT64<<8 | Fpop):
 		return x86.AFADDDP
 
-	case OSUB_ | (gc.TFLOAT32<<8 | 0):
+	case OSUB_ | (ir.TFLOAT32<<8 | 0):
 		return x86.AFSUBF
 
-	case OSUB_ | (gc.TFLOAT32<<8 | Frev):
+	case OSUB_ | (ir.TFLOAT32<<8 | Frev):
 		return x86.AFSUBRF
 
-	case OSUB_ | (gc.TFLOAT64<<8 | 0):
+	case OSUB_ | (ir.TFLOAT64<<8 | 0):
 		return x86.AFSUBD
 
-	case OSUB_ | (gc.TFLOAT64<<8 | Frev):
+	case OSUB_ | (ir.TFLOAT64<<8 | Frev):
 		return x86.AFSUBRD
 
-	case OSUB_ | (gc.TFLOAT64<<8 | Fpop):
+	case OSUB_ | (ir.TFLOAT64<<8 | Fpop):
 		return x86.AFSUBDP
 
-	case OSUB_ | (gc.TFLOAT64<<8 | (Fpop | Frev)):
+	case OSUB_ | (ir.TFLOAT64<<8 | (Fpop | Frev)):
 		return x86.AFSUBRDP
 
-	case OMUL_ | (gc.TFLOAT32<<8 | 0):
+	case OMUL_ | (ir.TFLOAT32<<8 | 0):
 		return x86.AFMULF
 
-	case OMUL_ | (gc.TFLOAT64<<8 | 0):
+	case OMUL_ | (ir.TFLOAT64<<8 | 0):
 		return x86.AFMULD
 
-	case OMUL_ | (gc.TFLOAT64<<8 | Fpop):
+	case OMUL_ | (ir.TFLOAT64<<8 | Fpop):
 		return x86.AFMULDP
 
-	case ODIV_ | (gc.TFLOAT32<<8 | 0):
+	case ODIV_ | (ir.TFLOAT32<<8 | 0):
 		return x86.AFDIVF
 
-	case ODIV_ | (gc.TFLOAT32<<8 | Frev):
+	case ODIV_ | (ir.TFLOAT32<<8 | Frev):
 		return x86.AFDIVRF
 
-	case ODIV_ | (gc.TFLOAT64<<8 | 0):
+	case ODIV_ | (ir.TFLOAT64<<8 | 0):
 		return x86.AFDIVD
 
-	case ODIV_ | (gc.TFLOAT64<<8 | Frev):
+	case ODIV_ | (ir.TFLOAT64<<8 | Frev):
 		return x86.AFDIVRD
 
-	case ODIV_ | (gc.TFLOAT64<<8 | Fpop):
+	case ODIV_ | (ir.TFLOAT64<<8 | Fpop):
 		return x86.AFDIVDP
 
-	case ODIV_ | (gc.TFLOAT64<<8 | (Fpop | Frev)):
+	case ODIV_ | (ir.TFLOAT64<<8 | (Fpop | Frev)):
 		return x86.AFDIVRDP
 
-	case OCMP_ | (gc.TFLOAT32<<8 | 0):
+	case OCMP_ | (ir.TFLOAT32<<8 | 0):
 		return x86.AFCOMF
 
-	case OCMP_ | (gc.TFLOAT32<<8 | Fpop):
+	case OCMP_ | (ir.TFLOAT32<<8 | Fpop):
 		return x86.AFCOMFP
 
-	case OCMP_ | (gc.TFLOAT64<<8 | 0):
+	case OCMP_ | (ir.TFLOAT64<<8 | 0):
 		return x86.AFCOMD
 
-	case OCMP_ | (gc.TFLOAT64<<8 | Fpop):
+	case OCMP_ | (ir.TFLOAT64<<8 | Fpop):
 		return x86.AFCOMDP
 
-	case OCMP_ | (gc.TFLOAT64<<8 | Fpop2):
+	case OCMP_ | (ir.TFLOAT64<<8 | Fpop2):
 		return x86.AFCOMDPP
 
-	case OMINUS_ | (gc.TFLOAT32<<8 | 0):
+	case OMINUS_ | (ir.TFLOAT32<<8 | 0):
 		return x86.AFCHS
 
-	case OMINUS_ | (gc.TFLOAT64<<8 | 0):
+	case OMINUS_ | (ir.TFLOAT64<<8 | 0):
 		return x86.AFCHS
 	}
 
@@ -606,11 +607,11 @@ var resvd = []int{
  *	as $c, reg
  */
 func gconreg(as obj.As, c int64, reg int) {
-	var n1 gc.Node
-	var n2 gc.Node
+	var n1 ir.Node
+	var n2 ir.Node
 
-	gc.Nodconst(&n1, gc.Types[gc.TINT64], c)
-	gc.Nodreg(&n2, gc.Types[gc.TINT64], reg)
+	gc.Nodconst(&n1, ir.Types[ir.TINT64], c)
+	gc.Nodreg(&n2, ir.Types[ir.TINT64], reg)
 	gins(as, &n1, &n2)
 }
 
@@ -618,15 +619,15 @@ func gconreg(as obj.As, c int64, reg int) {
  * generate
  *	as $c, n
  */
-func ginscon(as obj.As, c int64, n2 *gc.Node) {
-	var n1 gc.Node
-	gc.Nodconst(&n1, gc.Types[gc.TINT32], c)
+func ginscon(as obj.As, c int64, n2 *ir.Node) {
+	var n1 ir.Node
+	gc.Nodconst(&n1, ir.Types[ir.TINT32], c)
 	gins(as, &n1, n2)
 }
 
-func ginscmp(op gc.Op, t *gc.Type, n1, n2 *gc.Node, likely int) *obj.Prog {
+func ginscmp(op ir.Op, t *ir.Type, n1, n2 *ir.Node, likely int) *obj.Prog {
 	if gc.Isint[t.Etype] || t.Etype == gc.Tptr {
-		if (n1.Op == gc.OLITERAL || n1.Op == gc.OADDR && n1.Left.Op == gc.ONAME) && n2.Op != gc.OLITERAL {
+		if (n1.Op == ir.OLITERAL || n1.Op == ir.OADDR && n1.Left.Op == ir.ONAME) && n2.Op != ir.OLITERAL {
 			// Reverse comparison to place constant (including address constant) last.
 			op = gc.Brrev(op)
 			n1, n2 = n2, n1
@@ -634,16 +635,16 @@ func ginscmp(op gc.Op, t *gc.Type, n1, n2 *gc.Node, likely int) *obj.Prog {
 	}
 
 	// General case.
-	var r1, r2, g1, g2 gc.Node
+	var r1, r2, g1, g2 ir.Node
 
 	// A special case to make write barriers more efficient.
 	// Comparing the first field of a named struct can be done directly.
 	base := n1
-	if n1.Op == gc.ODOT && n1.Left.Type.Etype == gc.TSTRUCT && n1.Left.Type.Field(0).Sym == n1.Sym {
+	if n1.Op == ir.ODOT && n1.Left.Type.Etype == ir.TSTRUCT && n1.Left.Type.Field(0).Sym == n1.Sym {
 		base = n1.Left
 	}
 
-	if base.Op == gc.ONAME && base.Class&gc.PHEAP == 0 || n1.Op == gc.OINDREG {
+	if base.Op == ir.ONAME && base.Class&ir.PHEAP == 0 || n1.Op == ir.OINDREG {
 		r1 = *n1
 	} else {
 		gc.Regalloc(&r1, t, n1)
@@ -651,7 +652,7 @@ func ginscmp(op gc.Op, t *gc.Type, n1, n2 *gc.Node, likely int) *obj.Prog {
 		gc.Cgen(n1, &g1)
 		gmove(&g1, &r1)
 	}
-	if n2.Op == gc.OLITERAL && gc.Isint[t.Etype] || n2.Op == gc.OADDR && n2.Left.Op == gc.ONAME && n2.Left.Class == gc.PEXTERN {
+	if n2.Op == ir.OLITERAL && gc.Isint[t.Etype] || n2.Op == ir.OADDR && n2.Left.Op == ir.ONAME && n2.Left.Class == ir.PEXTERN {
 		r2 = *n2
 	} else {
 		gc.Regalloc(&r2, t, n2)
@@ -659,12 +660,12 @@ func ginscmp(op gc.Op, t *gc.Type, n1, n2 *gc.Node, likely int) *obj.Prog {
 		gc.Cgen(n2, &g2)
 		gmove(&g2, &r2)
 	}
-	gins(optoas(gc.OCMP, t), &r1, &r2)
-	if r1.Op == gc.OREGISTER {
+	gins(optoas(ir.OCMP, t), &r1, &r2)
+	if r1.Op == ir.OREGISTER {
 		gc.Regfree(&g1)
 		gc.Regfree(&r1)
 	}
-	if r2.Op == gc.OREGISTER {
+	if r2.Op == ir.OREGISTER {
 		gc.Regfree(&g2)
 		gc.Regfree(&r2)
 	}
@@ -674,7 +675,7 @@ func ginscmp(op gc.Op, t *gc.Type, n1, n2 *gc.Node, likely int) *obj.Prog {
 /*
  * swap node contents
  */
-func nswap(a *gc.Node, b *gc.Node) {
+func nswap(a *ir.Node, b *ir.Node) {
 	t := *a
 	*a = *b
 	*b = t
@@ -685,24 +686,24 @@ func nswap(a *gc.Node, b *gc.Node) {
  * overwritten by next call, but useful in calls to gins.
  */
 
-var ncon_n gc.Node
+var ncon_n ir.Node
 
-func ncon(i uint32) *gc.Node {
+func ncon(i uint32) *ir.Node {
 	if ncon_n.Type == nil {
-		gc.Nodconst(&ncon_n, gc.Types[gc.TUINT32], 0)
+		gc.Nodconst(&ncon_n, ir.Types[ir.TUINT32], 0)
 	}
 	ncon_n.SetInt(int64(i))
 	return &ncon_n
 }
 
-var sclean [10]gc.Node
+var sclean [10]ir.Node
 
 var nsclean int
 
 /*
  * n is a 64-bit value.  fill in lo and hi to refer to its 32-bit halves.
  */
-func split64(n *gc.Node, lo *gc.Node, hi *gc.Node) {
+func split64(n *ir.Node, lo *ir.Node, hi *ir.Node) {
 	if !gc.Is64(n.Type) {
 		gc.Fatalf("split64 %v", n.Type)
 	}
@@ -710,13 +711,13 @@ func split64(n *gc.Node, lo *gc.Node, hi *gc.Node) {
 	if nsclean >= len(sclean) {
 		gc.Fatalf("split64 clean")
 	}
-	sclean[nsclean].Op = gc.OEMPTY
+	sclean[nsclean].Op = ir.OEMPTY
 	nsclean++
 	switch n.Op {
 	default:
 		switch n.Op {
 		default:
-			var n1 gc.Node
+			var n1 ir.Node
 			if !dotaddable(n, &n1) {
 				gc.Igen(n, &n1, nil)
 				sclean[nsclean-1] = n1
@@ -724,39 +725,39 @@ func split64(n *gc.Node, lo *gc.Node, hi *gc.Node) {
 
 			n = &n1
 
-		case gc.ONAME:
-			if n.Class == gc.PPARAMREF {
-				var n1 gc.Node
+		case ir.ONAME:
+			if n.Class == ir.PPARAMREF {
+				var n1 ir.Node
 				gc.Cgen(n.Name.Heapaddr, &n1)
 				sclean[nsclean-1] = n1
 				n = &n1
 			}
 
 			// nothing
-		case gc.OINDREG:
+		case ir.OINDREG:
 			break
 		}
 
 		*lo = *n
 		*hi = *n
-		lo.Type = gc.Types[gc.TUINT32]
-		if n.Type.Etype == gc.TINT64 {
-			hi.Type = gc.Types[gc.TINT32]
+		lo.Type = ir.Types[ir.TUINT32]
+		if n.Type.Etype == ir.TINT64 {
+			hi.Type = ir.Types[ir.TINT32]
 		} else {
-			hi.Type = gc.Types[gc.TUINT32]
+			hi.Type = ir.Types[ir.TUINT32]
 		}
 		hi.Xoffset += 4
 
-	case gc.OLITERAL:
-		var n1 gc.Node
-		n.Convconst(&n1, n.Type)
+	case ir.OLITERAL:
+		var n1 ir.Node
+		gc.Convconst(n, &n1, n.Type)
 		i := n1.Int()
-		gc.Nodconst(lo, gc.Types[gc.TUINT32], int64(uint32(i)))
+		gc.Nodconst(lo, ir.Types[ir.TUINT32], int64(uint32(i)))
 		i >>= 32
-		if n.Type.Etype == gc.TINT64 {
-			gc.Nodconst(hi, gc.Types[gc.TINT32], int64(int32(i)))
+		if n.Type.Etype == ir.TINT64 {
+			gc.Nodconst(hi, ir.Types[ir.TINT32], int64(int32(i)))
 		} else {
-			gc.Nodconst(hi, gc.Types[gc.TUINT32], int64(uint32(i)))
+			gc.Nodconst(hi, ir.Types[ir.TUINT32], int64(uint32(i)))
 		}
 	}
 }
@@ -766,16 +767,16 @@ func splitclean() {
 		gc.Fatalf("splitclean")
 	}
 	nsclean--
-	if sclean[nsclean].Op != gc.OEMPTY {
+	if sclean[nsclean].Op != ir.OEMPTY {
 		gc.Regfree(&sclean[nsclean])
 	}
 }
 
 // set up nodes representing fp constants
 var (
-	zerof        gc.Node
-	two63f       gc.Node
-	two64f       gc.Node
+	zerof        ir.Node
+	two63f       ir.Node
+	two64f       ir.Node
 	bignodes_did bool
 )
 
@@ -785,31 +786,31 @@ func bignodes() {
 	}
 	bignodes_did = true
 
-	gc.Nodconst(&zerof, gc.Types[gc.TINT64], 0)
-	zerof.Convconst(&zerof, gc.Types[gc.TFLOAT64])
+	gc.Nodconst(&zerof, ir.Types[ir.TINT64], 0)
+	gc.Convconst(&zerof, &zerof, ir.Types[ir.TFLOAT64])
 
 	var i big.Int
 	i.SetInt64(1)
 	i.Lsh(&i, 63)
-	var bigi gc.Node
+	var bigi ir.Node
 
-	gc.Nodconst(&bigi, gc.Types[gc.TUINT64], 0)
+	gc.Nodconst(&bigi, ir.Types[ir.TUINT64], 0)
 	bigi.SetBigInt(&i)
-	bigi.Convconst(&two63f, gc.Types[gc.TFLOAT64])
+	gc.Convconst(&bigi, &two63f, ir.Types[ir.TFLOAT64])
 
-	gc.Nodconst(&bigi, gc.Types[gc.TUINT64], 0)
+	gc.Nodconst(&bigi, ir.Types[ir.TUINT64], 0)
 	i.Lsh(&i, 1)
 	bigi.SetBigInt(&i)
-	bigi.Convconst(&two64f, gc.Types[gc.TFLOAT64])
+	gc.Convconst(&bigi, &two64f, ir.Types[ir.TFLOAT64])
 }
 
-func memname(n *gc.Node, t *gc.Type) {
+func memname(n *ir.Node, t *ir.Type) {
 	gc.Tempname(n, t)
 	n.Sym = gc.Lookup("." + n.Sym.Name[1:]) // keep optimizer from registerizing
 	n.Orig.Sym = n.Sym
 }
 
-func gmove(f *gc.Node, t *gc.Node) {
+func gmove(f *ir.Node, t *ir.Node) {
 	if gc.Debug['M'] != 0 {
 		fmt.Printf("gmove %v -> %v\n", f, t)
 	}
@@ -830,16 +831,16 @@ func gmove(f *gc.Node, t *gc.Node) {
 
 	// cannot have two integer memory operands;
 	// except 64-bit, which always copies via registers anyway.
-	var r1 gc.Node
+	var r1 ir.Node
 	var a obj.As
 	if gc.Isint[ft] && gc.Isint[tt] && !gc.Is64(f.Type) && !gc.Is64(t.Type) && gc.Ismem(f) && gc.Ismem(t) {
 		goto hard
 	}
 
 	// convert constant to desired type
-	if f.Op == gc.OLITERAL {
-		var con gc.Node
-		f.Convconst(&con, t.Type)
+	if f.Op == ir.OLITERAL {
+		var con ir.Node
+		gc.Convconst(f, &con, t.Type)
 		f = &con
 		ft = gc.Simsimtype(con.Type)
 	}
@@ -860,109 +861,109 @@ func gmove(f *gc.Node, t *gc.Node) {
 		/*
 		 * integer copy and truncate
 		 */
-	case gc.TINT8<<16 | gc.TINT8, // same size
-		gc.TINT8<<16 | gc.TUINT8,
-		gc.TUINT8<<16 | gc.TINT8,
-		gc.TUINT8<<16 | gc.TUINT8:
+	case ir.TINT8<<16 | ir.TINT8, // same size
+		ir.TINT8<<16 | ir.TUINT8,
+		ir.TUINT8<<16 | ir.TINT8,
+		ir.TUINT8<<16 | ir.TUINT8:
 		a = x86.AMOVB
 
-	case gc.TINT16<<16 | gc.TINT8, // truncate
-		gc.TUINT16<<16 | gc.TINT8,
-		gc.TINT32<<16 | gc.TINT8,
-		gc.TUINT32<<16 | gc.TINT8,
-		gc.TINT16<<16 | gc.TUINT8,
-		gc.TUINT16<<16 | gc.TUINT8,
-		gc.TINT32<<16 | gc.TUINT8,
-		gc.TUINT32<<16 | gc.TUINT8:
+	case ir.TINT16<<16 | ir.TINT8, // truncate
+		ir.TUINT16<<16 | ir.TINT8,
+		ir.TINT32<<16 | ir.TINT8,
+		ir.TUINT32<<16 | ir.TINT8,
+		ir.TINT16<<16 | ir.TUINT8,
+		ir.TUINT16<<16 | ir.TUINT8,
+		ir.TINT32<<16 | ir.TUINT8,
+		ir.TUINT32<<16 | ir.TUINT8:
 		a = x86.AMOVB
 
 		goto rsrc
 
-	case gc.TINT64<<16 | gc.TINT8, // truncate low word
-		gc.TUINT64<<16 | gc.TINT8,
-		gc.TINT64<<16 | gc.TUINT8,
-		gc.TUINT64<<16 | gc.TUINT8:
-		var flo gc.Node
-		var fhi gc.Node
+	case ir.TINT64<<16 | ir.TINT8, // truncate low word
+		ir.TUINT64<<16 | ir.TINT8,
+		ir.TINT64<<16 | ir.TUINT8,
+		ir.TUINT64<<16 | ir.TUINT8:
+		var flo ir.Node
+		var fhi ir.Node
 		split64(f, &flo, &fhi)
 
-		var r1 gc.Node
+		var r1 ir.Node
 		gc.Nodreg(&r1, t.Type, x86.REG_AX)
 		gmove(&flo, &r1)
 		gins(x86.AMOVB, &r1, t)
 		splitclean()
 		return
 
-	case gc.TINT16<<16 | gc.TINT16, // same size
-		gc.TINT16<<16 | gc.TUINT16,
-		gc.TUINT16<<16 | gc.TINT16,
-		gc.TUINT16<<16 | gc.TUINT16:
+	case ir.TINT16<<16 | ir.TINT16, // same size
+		ir.TINT16<<16 | ir.TUINT16,
+		ir.TUINT16<<16 | ir.TINT16,
+		ir.TUINT16<<16 | ir.TUINT16:
 		a = x86.AMOVW
 
-	case gc.TINT32<<16 | gc.TINT16, // truncate
-		gc.TUINT32<<16 | gc.TINT16,
-		gc.TINT32<<16 | gc.TUINT16,
-		gc.TUINT32<<16 | gc.TUINT16:
+	case ir.TINT32<<16 | ir.TINT16, // truncate
+		ir.TUINT32<<16 | ir.TINT16,
+		ir.TINT32<<16 | ir.TUINT16,
+		ir.TUINT32<<16 | ir.TUINT16:
 		a = x86.AMOVW
 
 		goto rsrc
 
-	case gc.TINT64<<16 | gc.TINT16, // truncate low word
-		gc.TUINT64<<16 | gc.TINT16,
-		gc.TINT64<<16 | gc.TUINT16,
-		gc.TUINT64<<16 | gc.TUINT16:
-		var flo gc.Node
-		var fhi gc.Node
+	case ir.TINT64<<16 | ir.TINT16, // truncate low word
+		ir.TUINT64<<16 | ir.TINT16,
+		ir.TINT64<<16 | ir.TUINT16,
+		ir.TUINT64<<16 | ir.TUINT16:
+		var flo ir.Node
+		var fhi ir.Node
 		split64(f, &flo, &fhi)
 
-		var r1 gc.Node
+		var r1 ir.Node
 		gc.Nodreg(&r1, t.Type, x86.REG_AX)
 		gmove(&flo, &r1)
 		gins(x86.AMOVW, &r1, t)
 		splitclean()
 		return
 
-	case gc.TINT32<<16 | gc.TINT32, // same size
-		gc.TINT32<<16 | gc.TUINT32,
-		gc.TUINT32<<16 | gc.TINT32,
-		gc.TUINT32<<16 | gc.TUINT32:
+	case ir.TINT32<<16 | ir.TINT32, // same size
+		ir.TINT32<<16 | ir.TUINT32,
+		ir.TUINT32<<16 | ir.TINT32,
+		ir.TUINT32<<16 | ir.TUINT32:
 		a = x86.AMOVL
 
-	case gc.TINT64<<16 | gc.TINT32, // truncate
-		gc.TUINT64<<16 | gc.TINT32,
-		gc.TINT64<<16 | gc.TUINT32,
-		gc.TUINT64<<16 | gc.TUINT32:
-		var fhi gc.Node
-		var flo gc.Node
+	case ir.TINT64<<16 | ir.TINT32, // truncate
+		ir.TUINT64<<16 | ir.TINT32,
+		ir.TINT64<<16 | ir.TUINT32,
+		ir.TUINT64<<16 | ir.TUINT32:
+		var fhi ir.Node
+		var flo ir.Node
 		split64(f, &flo, &fhi)
 
-		var r1 gc.Node
+		var r1 ir.Node
 		gc.Nodreg(&r1, t.Type, x86.REG_AX)
 		gmove(&flo, &r1)
 		gins(x86.AMOVL, &r1, t)
 		splitclean()
 		return
 
-	case gc.TINT64<<16 | gc.TINT64, // same size
-		gc.TINT64<<16 | gc.TUINT64,
-		gc.TUINT64<<16 | gc.TINT64,
-		gc.TUINT64<<16 | gc.TUINT64:
-		var fhi gc.Node
-		var flo gc.Node
+	case ir.TINT64<<16 | ir.TINT64, // same size
+		ir.TINT64<<16 | ir.TUINT64,
+		ir.TUINT64<<16 | ir.TINT64,
+		ir.TUINT64<<16 | ir.TUINT64:
+		var fhi ir.Node
+		var flo ir.Node
 		split64(f, &flo, &fhi)
 
-		var tlo gc.Node
-		var thi gc.Node
+		var tlo ir.Node
+		var thi ir.Node
 		split64(t, &tlo, &thi)
-		if f.Op == gc.OLITERAL {
+		if f.Op == ir.OLITERAL {
 			gins(x86.AMOVL, &flo, &tlo)
 			gins(x86.AMOVL, &fhi, &thi)
 		} else {
 			// Implementation of conversion-free x = y for int64 or uint64 x.
 			// This is generated by the code that copies small values out of closures,
 			// and that code has DX live, so avoid DX and just use AX twice.
-			var r1 gc.Node
-			gc.Nodreg(&r1, gc.Types[gc.TUINT32], x86.REG_AX)
+			var r1 ir.Node
+			gc.Nodreg(&r1, ir.Types[ir.TUINT32], x86.REG_AX)
 			gins(x86.AMOVL, &flo, &r1)
 			gins(x86.AMOVL, &r1, &tlo)
 			gins(x86.AMOVL, &fhi, &r1)
@@ -976,73 +977,73 @@ func gmove(f *gc.Node, t *gc.Node) {
 		/*
 		 * integer up-conversions
 		 */
-	case gc.TINT8<<16 | gc.TINT16, // sign extend int8
-		gc.TINT8<<16 | gc.TUINT16:
+	case ir.TINT8<<16 | ir.TINT16, // sign extend int8
+		ir.TINT8<<16 | ir.TUINT16:
 		a = x86.AMOVBWSX
 
 		goto rdst
 
-	case gc.TINT8<<16 | gc.TINT32,
-		gc.TINT8<<16 | gc.TUINT32:
+	case ir.TINT8<<16 | ir.TINT32,
+		ir.TINT8<<16 | ir.TUINT32:
 		a = x86.AMOVBLSX
 		goto rdst
 
-	case gc.TINT8<<16 | gc.TINT64, // convert via int32
-		gc.TINT8<<16 | gc.TUINT64:
-		cvt = gc.Types[gc.TINT32]
+	case ir.TINT8<<16 | ir.TINT64, // convert via int32
+		ir.TINT8<<16 | ir.TUINT64:
+		cvt = ir.Types[ir.TINT32]
 
 		goto hard
 
-	case gc.TUINT8<<16 | gc.TINT16, // zero extend uint8
-		gc.TUINT8<<16 | gc.TUINT16:
+	case ir.TUINT8<<16 | ir.TINT16, // zero extend uint8
+		ir.TUINT8<<16 | ir.TUINT16:
 		a = x86.AMOVBWZX
 
 		goto rdst
 
-	case gc.TUINT8<<16 | gc.TINT32,
-		gc.TUINT8<<16 | gc.TUINT32:
+	case ir.TUINT8<<16 | ir.TINT32,
+		ir.TUINT8<<16 | ir.TUINT32:
 		a = x86.AMOVBLZX
 		goto rdst
 
-	case gc.TUINT8<<16 | gc.TINT64, // convert via uint32
-		gc.TUINT8<<16 | gc.TUINT64:
-		cvt = gc.Types[gc.TUINT32]
+	case ir.TUINT8<<16 | ir.TINT64, // convert via uint32
+		ir.TUINT8<<16 | ir.TUINT64:
+		cvt = ir.Types[ir.TUINT32]
 
 		goto hard
 
-	case gc.TINT16<<16 | gc.TINT32, // sign extend int16
-		gc.TINT16<<16 | gc.TUINT32:
+	case ir.TINT16<<16 | ir.TINT32, // sign extend int16
+		ir.TINT16<<16 | ir.TUINT32:
 		a = x86.AMOVWLSX
 
 		goto rdst
 
-	case gc.TINT16<<16 | gc.TINT64, // convert via int32
-		gc.TINT16<<16 | gc.TUINT64:
-		cvt = gc.Types[gc.TINT32]
+	case ir.TINT16<<16 | ir.TINT64, // convert via int32
+		ir.TINT16<<16 | ir.TUINT64:
+		cvt = ir.Types[ir.TINT32]
 
 		goto hard
 
-	case gc.TUINT16<<16 | gc.TINT32, // zero extend uint16
-		gc.TUINT16<<16 | gc.TUINT32:
+	case ir.TUINT16<<16 | ir.TINT32, // zero extend uint16
+		ir.TUINT16<<16 | ir.TUINT32:
 		a = x86.AMOVWLZX
 
 		goto rdst
 
-	case gc.TUINT16<<16 | gc.TINT64, // convert via uint32
-		gc.TUINT16<<16 | gc.TUINT64:
-		cvt = gc.Types[gc.TUINT32]
+	case ir.TUINT16<<16 | ir.TINT64, // convert via uint32
+		ir.TUINT16<<16 | ir.TUINT64:
+		cvt = ir.Types[ir.TUINT32]
 
 		goto hard
 
-	case gc.TINT32<<16 | gc.TINT64, // sign extend int32
-		gc.TINT32<<16 | gc.TUINT64:
-		var thi gc.Node
-		var tlo gc.Node
+	case ir.TINT32<<16 | ir.TINT64, // sign extend int32
+		ir.TINT32<<16 | ir.TUINT64:
+		var thi ir.Node
+		var tlo ir.Node
 		split64(t, &tlo, &thi)
 
-		var flo gc.Node
+		var flo ir.Node
 		gc.Nodreg(&flo, tlo.Type, x86.REG_AX)
-		var fhi gc.Node
+		var fhi ir.Node
 		gc.Nodreg(&fhi, thi.Type, x86.REG_DX)
 		gmove(f, &flo)
 		gins(x86.ACDQ, nil, nil)
@@ -1051,10 +1052,10 @@ func gmove(f *gc.Node, t *gc.Node) {
 		splitclean()
 		return
 
-	case gc.TUINT32<<16 | gc.TINT64, // zero extend uint32
-		gc.TUINT32<<16 | gc.TUINT64:
-		var tlo gc.Node
-		var thi gc.Node
+	case ir.TUINT32<<16 | ir.TINT64, // zero extend uint32
+		ir.TUINT32<<16 | ir.TUINT64:
+		var tlo ir.Node
+		var thi ir.Node
 		split64(t, &tlo, &thi)
 
 		gmove(f, &tlo)
@@ -1096,8 +1097,8 @@ hard:
 	return
 }
 
-func floatmove(f *gc.Node, t *gc.Node) {
-	var r1 gc.Node
+func floatmove(f *ir.Node, t *ir.Node) {
+	var r1 ir.Node
 
 	ft := gc.Simsimtype(f.Type)
 	tt := gc.Simsimtype(t.Type)
@@ -1109,9 +1110,9 @@ func floatmove(f *gc.Node, t *gc.Node) {
 	}
 
 	// convert constant to desired type
-	if f.Op == gc.OLITERAL {
-		var con gc.Node
-		f.Convconst(&con, t.Type)
+	if f.Op == ir.OLITERAL {
+		var con ir.Node
+		gc.Convconst(f, &con, t.Type)
 		f = &con
 		ft = gc.Simsimtype(con.Type)
 
@@ -1141,33 +1142,33 @@ func floatmove(f *gc.Node, t *gc.Node) {
 		return
 
 		// float to very long integer.
-	case gc.TFLOAT32<<16 | gc.TINT64,
-		gc.TFLOAT64<<16 | gc.TINT64:
-		if f.Op == gc.OREGISTER {
+	case ir.TFLOAT32<<16 | ir.TINT64,
+		ir.TFLOAT64<<16 | ir.TINT64:
+		if f.Op == ir.OREGISTER {
 			cvt = f.Type
 			goto hardmem
 		}
 
-		var r1 gc.Node
-		gc.Nodreg(&r1, gc.Types[ft], x86.REG_F0)
-		if ft == gc.TFLOAT32 {
+		var r1 ir.Node
+		gc.Nodreg(&r1, ir.Types[ft], x86.REG_F0)
+		if ft == ir.TFLOAT32 {
 			gins(x86.AFMOVF, f, &r1)
 		} else {
 			gins(x86.AFMOVD, f, &r1)
 		}
 
 		// set round to zero mode during conversion
-		var t1 gc.Node
-		memname(&t1, gc.Types[gc.TUINT16])
+		var t1 ir.Node
+		memname(&t1, ir.Types[ir.TUINT16])
 
-		var t2 gc.Node
-		memname(&t2, gc.Types[gc.TUINT16])
+		var t2 ir.Node
+		memname(&t2, ir.Types[ir.TUINT16])
 		gins(x86.AFSTCW, nil, &t1)
 		gins(x86.AMOVW, ncon(0xf7f), &t2)
 		gins(x86.AFLDCW, &t2, nil)
-		if tt == gc.TINT16 {
+		if tt == ir.TINT16 {
 			gins(x86.AFMOVWP, &r1, t)
-		} else if tt == gc.TINT32 {
+		} else if tt == ir.TINT32 {
 			gins(x86.AFMOVLP, &r1, t)
 		} else {
 			gins(x86.AFMOVVP, &r1, t)
@@ -1175,22 +1176,22 @@ func floatmove(f *gc.Node, t *gc.Node) {
 		gins(x86.AFLDCW, &t1, nil)
 		return
 
-	case gc.TFLOAT32<<16 | gc.TUINT64,
-		gc.TFLOAT64<<16 | gc.TUINT64:
+	case ir.TFLOAT32<<16 | ir.TUINT64,
+		ir.TFLOAT64<<16 | ir.TUINT64:
 		if !gc.Ismem(f) {
 			cvt = f.Type
 			goto hardmem
 		}
 
 		bignodes()
-		var f0 gc.Node
-		gc.Nodreg(&f0, gc.Types[ft], x86.REG_F0)
-		var f1 gc.Node
-		gc.Nodreg(&f1, gc.Types[ft], x86.REG_F0+1)
-		var ax gc.Node
-		gc.Nodreg(&ax, gc.Types[gc.TUINT16], x86.REG_AX)
-
-		if ft == gc.TFLOAT32 {
+		var f0 ir.Node
+		gc.Nodreg(&f0, ir.Types[ft], x86.REG_F0)
+		var f1 ir.Node
+		gc.Nodreg(&f1, ir.Types[ft], x86.REG_F0+1)
+		var ax ir.Node
+		gc.Nodreg(&ax, ir.Types[ir.TUINT16], x86.REG_AX)
+
+		if ft == ir.TFLOAT32 {
 			gins(x86.AFMOVF, f, &f0)
 		} else {
 			gins(x86.AFMOVD, f, &f0)
@@ -1201,7 +1202,7 @@ func floatmove(f *gc.Node, t *gc.Node) {
 		gins(x86.AFUCOMP, &f0, &f1)
 		gins(x86.AFSTSW, nil, &ax)
 		gins(x86.ASAHF, nil, nil)
-		p1 := gc.Gbranch(optoas(gc.OGT, gc.Types[tt]), nil, 0)
+		p1 := gc.Gbranch(optoas(ir.OGT, ir.Types[tt]), nil, 0)
 
 		// if 1<<64 <= v { answer = 0 too }
 		gins(x86.AFMOVD, &two64f, &f0)
@@ -1209,11 +1210,11 @@ func floatmove(f *gc.Node, t *gc.Node) {
 		gins(x86.AFUCOMP, &f0, &f1)
 		gins(x86.AFSTSW, nil, &ax)
 		gins(x86.ASAHF, nil, nil)
-		p2 := gc.Gbranch(optoas(gc.OGT, gc.Types[tt]), nil, 0)
+		p2 := gc.Gbranch(optoas(ir.OGT, ir.Types[tt]), nil, 0)
 		gc.Patch(p1, gc.Pc)
 		gins(x86.AFMOVVP, &f0, t) // don't care about t, but will pop the stack
-		var thi gc.Node
-		var tlo gc.Node
+		var thi ir.Node
+		var tlo ir.Node
 		split64(t, &tlo, &thi)
 		gins(x86.AMOVL, ncon(0), &tlo)
 		gins(x86.AMOVL, ncon(0), &thi)
@@ -1226,11 +1227,11 @@ func floatmove(f *gc.Node, t *gc.Node) {
 		//	otherwise, subtract 2^63, convert, and add it back.
 
 		// set round to zero mode during conversion
-		var t1 gc.Node
-		memname(&t1, gc.Types[gc.TUINT16])
+		var t1 ir.Node
+		memname(&t1, ir.Types[ir.TUINT16])
 
-		var t2 gc.Node
-		memname(&t2, gc.Types[gc.TUINT16])
+		var t2 ir.Node
+		memname(&t2, ir.Types[ir.TUINT16])
 		gins(x86.AFSTCW, nil, &t1)
 		gins(x86.AMOVW, ncon(0xf7f), &t2)
 		gins(x86.AFLDCW, &t2, nil)
@@ -1241,7 +1242,7 @@ func floatmove(f *gc.Node, t *gc.Node) {
 		gins(x86.AFUCOMP, &f0, &f1)
 		gins(x86.AFSTSW, nil, &ax)
 		gins(x86.ASAHF, nil, nil)
-		p2 = gc.Gbranch(optoas(gc.OLE, gc.Types[tt]), nil, 0)
+		p2 = gc.Gbranch(optoas(ir.OLE, ir.Types[tt]), nil, 0)
 		gins(x86.AFMOVVP, &f0, t)
 		p3 := gc.Gbranch(obj.AJMP, nil, 0)
 		gc.Patch(p2, gc.Pc)
@@ -1262,15 +1263,15 @@ func floatmove(f *gc.Node, t *gc.Node) {
 		/*
 		 * integer to float
 		 */
-	case gc.TINT64<<16 | gc.TFLOAT32,
-		gc.TINT64<<16 | gc.TFLOAT64:
-		if t.Op == gc.OREGISTER {
+	case ir.TINT64<<16 | ir.TFLOAT32,
+		ir.TINT64<<16 | ir.TFLOAT64:
+		if t.Op == ir.OREGISTER {
 			goto hardmem
 		}
-		var f0 gc.Node
+		var f0 ir.Node
 		gc.Nodreg(&f0, t.Type, x86.REG_F0)
 		gins(x86.AFMOVV, f, &f0)
-		if tt == gc.TFLOAT32 {
+		if tt == ir.TFLOAT32 {
 			gins(x86.AFMOVFP, &f0, t)
 		} else {
 			gins(x86.AFMOVDP, &f0, t)
@@ -1280,30 +1281,30 @@ func floatmove(f *gc.Node, t *gc.Node) {
 		// algorithm is:
 	//	if small enough, use native int64 -> float64 conversion.
 	//	otherwise, halve (rounding to odd?), convert, and double.
-	case gc.TUINT64<<16 | gc.TFLOAT32,
-		gc.TUINT64<<16 | gc.TFLOAT64:
-		var ax gc.Node
-		gc.Nodreg(&ax, gc.Types[gc.TUINT32], x86.REG_AX)
-
-		var dx gc.Node
-		gc.Nodreg(&dx, gc.Types[gc.TUINT32], x86.REG_DX)
-		var cx gc.Node
-		gc.Nodreg(&cx, gc.Types[gc.TUINT32], x86.REG_CX)
-		var t1 gc.Node
+	case ir.TUINT64<<16 | ir.TFLOAT32,
+		ir.TUINT64<<16 | ir.TFLOAT64:
+		var ax ir.Node
+		gc.Nodreg(&ax, ir.Types[ir.TUINT32], x86.REG_AX)
+
+		var dx ir.Node
+		gc.Nodreg(&dx, ir.Types[ir.TUINT32], x86.REG_DX)
+		var cx ir.Node
+		gc.Nodreg(&cx, ir.Types[ir.TUINT32], x86.REG_CX)
+		var t1 ir.Node
 		gc.Tempname(&t1, f.Type)
-		var tlo gc.Node
-		var thi gc.Node
+		var tlo ir.Node
+		var thi ir.Node
 		split64(&t1, &tlo, &thi)
 		gmove(f, &t1)
 		gins(x86.ACMPL, &thi, ncon(0))
 		p1 := gc.Gbranch(x86.AJLT, nil, 0)
 
 		// native
-		var r1 gc.Node
-		gc.Nodreg(&r1, gc.Types[tt], x86.REG_F0)
+		var r1 ir.Node
+		gc.Nodreg(&r1, ir.Types[tt], x86.REG_F0)
 
 		gins(x86.AFMOVV, &t1, &r1)
-		if tt == gc.TFLOAT32 {
+		if tt == ir.TFLOAT32 {
 			gins(x86.AFMOVFP, &r1, t)
 		} else {
 			gins(x86.AFMOVDP, &r1, t)
@@ -1324,13 +1325,13 @@ func floatmove(f *gc.Node, t *gc.Node) {
 		gins(x86.ASHRL, ncon(1), &dx)
 		gmove(&dx, &thi)
 		gmove(&ax, &tlo)
-		gc.Nodreg(&r1, gc.Types[tt], x86.REG_F0)
-		var r2 gc.Node
-		gc.Nodreg(&r2, gc.Types[tt], x86.REG_F0+1)
+		gc.Nodreg(&r1, ir.Types[tt], x86.REG_F0)
+		var r2 ir.Node
+		gc.Nodreg(&r2, ir.Types[tt], x86.REG_F0+1)
 		gins(x86.AFMOVV, &t1, &r1)
 		gins(x86.AFMOVD, &r1, &r1)
 		gins(x86.AFADDDP, &r1, &r2)
-		if tt == gc.TFLOAT32 {
+		if tt == ir.TFLOAT32 {
 			gins(x86.AFMOVFP, &r1, t)
 		} else {
 			gins(x86.AFMOVDP, &r1, t)
@@ -1358,8 +1359,8 @@ hardmem:
 	return
 }
 
-func floatmove_387(f *gc.Node, t *gc.Node) {
-	var r1 gc.Node
+func floatmove_387(f *ir.Node, t *ir.Node) {
+	var r1 ir.Node
 	var a obj.As
 
 	ft := gc.Simsimtype(f.Type)
@@ -1373,19 +1374,19 @@ func floatmove_387(f *gc.Node, t *gc.Node) {
 		/*
 		* float to integer
 		 */
-	case gc.TFLOAT32<<16 | gc.TINT16,
-		gc.TFLOAT32<<16 | gc.TINT32,
-		gc.TFLOAT32<<16 | gc.TINT64,
-		gc.TFLOAT64<<16 | gc.TINT16,
-		gc.TFLOAT64<<16 | gc.TINT32,
-		gc.TFLOAT64<<16 | gc.TINT64:
-		if t.Op == gc.OREGISTER {
+	case ir.TFLOAT32<<16 | ir.TINT16,
+		ir.TFLOAT32<<16 | ir.TINT32,
+		ir.TFLOAT32<<16 | ir.TINT64,
+		ir.TFLOAT64<<16 | ir.TINT16,
+		ir.TFLOAT64<<16 | ir.TINT32,
+		ir.TFLOAT64<<16 | ir.TINT64:
+		if t.Op == ir.OREGISTER {
 			goto hardmem
 		}
-		var r1 gc.Node
-		gc.Nodreg(&r1, gc.Types[ft], x86.REG_F0)
-		if f.Op != gc.OREGISTER {
-			if ft == gc.TFLOAT32 {
+		var r1 ir.Node
+		gc.Nodreg(&r1, ir.Types[ft], x86.REG_F0)
+		if f.Op != ir.OREGISTER {
+			if ft == ir.TFLOAT32 {
 				gins(x86.AFMOVF, f, &r1)
 			} else {
 				gins(x86.AFMOVD, f, &r1)
@@ -1393,17 +1394,17 @@ func floatmove_387(f *gc.Node, t *gc.Node) {
 		}
 
 		// set round to zero mode during conversion
-		var t1 gc.Node
-		memname(&t1, gc.Types[gc.TUINT16])
+		var t1 ir.Node
+		memname(&t1, ir.Types[ir.TUINT16])
 
-		var t2 gc.Node
-		memname(&t2, gc.Types[gc.TUINT16])
+		var t2 ir.Node
+		memname(&t2, ir.Types[ir.TUINT16])
 		gins(x86.AFSTCW, nil, &t1)
 		gins(x86.AMOVW, ncon(0xf7f), &t2)
 		gins(x86.AFLDCW, &t2, nil)
-		if tt == gc.TINT16 {
+		if tt == ir.TINT16 {
 			gins(x86.AFMOVWP, &r1, t)
-		} else if tt == gc.TINT32 {
+		} else if tt == ir.TINT32 {
 			gins(x86.AFMOVLP, &r1, t)
 		} else {
 			gins(x86.AFMOVVP, &r1, t)
@@ -1412,25 +1413,25 @@ func floatmove_387(f *gc.Node, t *gc.Node) {
 		return
 
 		// convert via int32.
-	case gc.TFLOAT32<<16 | gc.TINT8,
-		gc.TFLOAT32<<16 | gc.TUINT16,
-		gc.TFLOAT32<<16 | gc.TUINT8,
-		gc.TFLOAT64<<16 | gc.TINT8,
-		gc.TFLOAT64<<16 | gc.TUINT16,
-		gc.TFLOAT64<<16 | gc.TUINT8:
-		var t1 gc.Node
-		gc.Tempname(&t1, gc.Types[gc.TINT32])
+	case ir.TFLOAT32<<16 | ir.TINT8,
+		ir.TFLOAT32<<16 | ir.TUINT16,
+		ir.TFLOAT32<<16 | ir.TUINT8,
+		ir.TFLOAT64<<16 | ir.TINT8,
+		ir.TFLOAT64<<16 | ir.TUINT16,
+		ir.TFLOAT64<<16 | ir.TUINT8:
+		var t1 ir.Node
+		gc.Tempname(&t1, ir.Types[ir.TINT32])
 
 		gmove(f, &t1)
 		switch tt {
 		default:
 			gc.Fatalf("gmove %v", t)
 
-		case gc.TINT8:
+		case ir.TINT8:
 			gins(x86.ACMPL, &t1, ncon(-0x80&(1<<32-1)))
-			p1 := gc.Gbranch(optoas(gc.OLT, gc.Types[gc.TINT32]), nil, -1)
+			p1 := gc.Gbranch(optoas(ir.OLT, ir.Types[ir.TINT32]), nil, -1)
 			gins(x86.ACMPL, &t1, ncon(0x7f))
-			p2 := gc.Gbranch(optoas(gc.OGT, gc.Types[gc.TINT32]), nil, -1)
+			p2 := gc.Gbranch(optoas(ir.OGT, ir.Types[ir.TINT32]), nil, -1)
 			p3 := gc.Gbranch(obj.AJMP, nil, 0)
 			gc.Patch(p1, gc.Pc)
 			gc.Patch(p2, gc.Pc)
@@ -1438,14 +1439,14 @@ func floatmove_387(f *gc.Node, t *gc.Node) {
 			gc.Patch(p3, gc.Pc)
 			gmove(&t1, t)
 
-		case gc.TUINT8:
+		case ir.TUINT8:
 			gins(x86.ATESTL, ncon(0xffffff00), &t1)
 			p1 := gc.Gbranch(x86.AJEQ, nil, +1)
 			gins(x86.AMOVL, ncon(0), &t1)
 			gc.Patch(p1, gc.Pc)
 			gmove(&t1, t)
 
-		case gc.TUINT16:
+		case ir.TUINT16:
 			gins(x86.ATESTL, ncon(0xffff0000), &t1)
 			p1 := gc.Gbranch(x86.AJEQ, nil, +1)
 			gins(x86.AMOVL, ncon(0), &t1)
@@ -1456,34 +1457,34 @@ func floatmove_387(f *gc.Node, t *gc.Node) {
 		return
 
 		// convert via int64.
-	case gc.TFLOAT32<<16 | gc.TUINT32,
-		gc.TFLOAT64<<16 | gc.TUINT32:
-		cvt = gc.Types[gc.TINT64]
+	case ir.TFLOAT32<<16 | ir.TUINT32,
+		ir.TFLOAT64<<16 | ir.TUINT32:
+		cvt = ir.Types[ir.TINT64]
 
 		goto hardmem
 
 		/*
 		 * integer to float
 		 */
-	case gc.TINT16<<16 | gc.TFLOAT32,
-		gc.TINT16<<16 | gc.TFLOAT64,
-		gc.TINT32<<16 | gc.TFLOAT32,
-		gc.TINT32<<16 | gc.TFLOAT64,
-		gc.TINT64<<16 | gc.TFLOAT32,
-		gc.TINT64<<16 | gc.TFLOAT64:
-		if t.Op != gc.OREGISTER {
+	case ir.TINT16<<16 | ir.TFLOAT32,
+		ir.TINT16<<16 | ir.TFLOAT64,
+		ir.TINT32<<16 | ir.TFLOAT32,
+		ir.TINT32<<16 | ir.TFLOAT64,
+		ir.TINT64<<16 | ir.TFLOAT32,
+		ir.TINT64<<16 | ir.TFLOAT64:
+		if t.Op != ir.OREGISTER {
 			goto hard
 		}
-		if f.Op == gc.OREGISTER {
+		if f.Op == ir.OREGISTER {
 			cvt = f.Type
 			goto hardmem
 		}
 
 		switch ft {
-		case gc.TINT16:
+		case ir.TINT16:
 			a = x86.AFMOVW
 
-		case gc.TINT32:
+		case ir.TINT32:
 			a = x86.AFMOVL
 
 		default:
@@ -1491,20 +1492,20 @@ func floatmove_387(f *gc.Node, t *gc.Node) {
 		}
 
 		// convert via int32 memory
-	case gc.TINT8<<16 | gc.TFLOAT32,
-		gc.TINT8<<16 | gc.TFLOAT64,
-		gc.TUINT16<<16 | gc.TFLOAT32,
-		gc.TUINT16<<16 | gc.TFLOAT64,
-		gc.TUINT8<<16 | gc.TFLOAT32,
-		gc.TUINT8<<16 | gc.TFLOAT64:
-		cvt = gc.Types[gc.TINT32]
+	case ir.TINT8<<16 | ir.TFLOAT32,
+		ir.TINT8<<16 | ir.TFLOAT64,
+		ir.TUINT16<<16 | ir.TFLOAT32,
+		ir.TUINT16<<16 | ir.TFLOAT64,
+		ir.TUINT8<<16 | ir.TFLOAT32,
+		ir.TUINT8<<16 | ir.TFLOAT64:
+		cvt = ir.Types[ir.TINT32]
 
 		goto hardmem
 
 		// convert via int64 memory
-	case gc.TUINT32<<16 | gc.TFLOAT32,
-		gc.TUINT32<<16 | gc.TFLOAT64:
-		cvt = gc.Types[gc.TINT64]
+	case ir.TUINT32<<16 | ir.TFLOAT32,
+		ir.TUINT32<<16 | ir.TFLOAT64:
+		cvt = ir.Types[ir.TINT64]
 
 		goto hardmem
 
@@ -1517,12 +1518,12 @@ func floatmove_387(f *gc.Node, t *gc.Node) {
 	/*
 	 * float to float
 	 */
-	case gc.TFLOAT32<<16 | gc.TFLOAT32,
-		gc.TFLOAT64<<16 | gc.TFLOAT64:
+	case ir.TFLOAT32<<16 | ir.TFLOAT32,
+		ir.TFLOAT64<<16 | ir.TFLOAT64:
 		if gc.Ismem(f) && gc.Ismem(t) {
 			goto hard
 		}
-		if f.Op == gc.OREGISTER && t.Op == gc.OREGISTER {
+		if f.Op == ir.OREGISTER && t.Op == ir.OREGISTER {
 			if f.Reg != x86.REG_F0 || t.Reg != x86.REG_F0 {
 				goto fatal
 			}
@@ -1530,50 +1531,50 @@ func floatmove_387(f *gc.Node, t *gc.Node) {
 		}
 
 		a = x86.AFMOVF
-		if ft == gc.TFLOAT64 {
+		if ft == ir.TFLOAT64 {
 			a = x86.AFMOVD
 		}
 		if gc.Ismem(t) {
-			if f.Op != gc.OREGISTER || f.Reg != x86.REG_F0 {
+			if f.Op != ir.OREGISTER || f.Reg != x86.REG_F0 {
 				gc.Fatalf("gmove %v", f)
 			}
 			a = x86.AFMOVFP
-			if ft == gc.TFLOAT64 {
+			if ft == ir.TFLOAT64 {
 				a = x86.AFMOVDP
 			}
 		}
 
-	case gc.TFLOAT32<<16 | gc.TFLOAT64:
+	case ir.TFLOAT32<<16 | ir.TFLOAT64:
 		if gc.Ismem(f) && gc.Ismem(t) {
 			goto hard
 		}
-		if f.Op == gc.OREGISTER && t.Op == gc.OREGISTER {
+		if f.Op == ir.OREGISTER && t.Op == ir.OREGISTER {
 			if f.Reg != x86.REG_F0 || t.Reg != x86.REG_F0 {
 				goto fatal
 			}
 			return
 		}
 
-		if f.Op == gc.OREGISTER {
+		if f.Op == ir.OREGISTER {
 			gins(x86.AFMOVDP, f, t)
 		} else {
 			gins(x86.AFMOVF, f, t)
 		}
 		return
 
-	case gc.TFLOAT64<<16 | gc.TFLOAT32:
+	case ir.TFLOAT64<<16 | ir.TFLOAT32:
 		if gc.Ismem(f) && gc.Ismem(t) {
 			goto hard
 		}
-		if f.Op == gc.OREGISTER && t.Op == gc.OREGISTER {
-			var r1 gc.Node
-			gc.Tempname(&r1, gc.Types[gc.TFLOAT32])
+		if f.Op == ir.OREGISTER && t.Op == ir.OREGISTER {
+			var r1 ir.Node
+			gc.Tempname(&r1, ir.Types[ir.TFLOAT32])
 			gins(x86.AFMOVFP, f, &r1)
 			gins(x86.AFMOVF, &r1, t)
 			return
 		}
 
-		if f.Op == gc.OREGISTER {
+		if f.Op == ir.OREGISTER {
 			gins(x86.AFMOVFP, f, t)
 		} else {
 			gins(x86.AFMOVD, f, t)
@@ -1603,14 +1604,14 @@ hardmem:
 
 	// should not happen
 fatal:
-	gc.Fatalf("gmove %v -> %v", gc.Nconv(f, gc.FmtLong), gc.Nconv(t, gc.FmtLong))
+	gc.Fatalf("gmove %v -> %v", gc.Nconv(f, ir.FmtLong), gc.Nconv(t, ir.FmtLong))
 
 	return
 }
 
-func floatmove_sse(f *gc.Node, t *gc.Node) {
-	var r1 gc.Node
-	var cvt *gc.Type
+func floatmove_sse(f *ir.Node, t *ir.Node) {
+	var r1 ir.Node
+	var cvt *ir.Type
 	var a obj.As
 
 	ft := gc.Simsimtype(f.Type)
@@ -1627,30 +1628,30 @@ func floatmove_sse(f *gc.Node, t *gc.Node) {
 	/*
 	* float to integer
 	 */
-	case gc.TFLOAT32<<16 | gc.TINT16,
-		gc.TFLOAT32<<16 | gc.TINT8,
-		gc.TFLOAT32<<16 | gc.TUINT16,
-		gc.TFLOAT32<<16 | gc.TUINT8,
-		gc.TFLOAT64<<16 | gc.TINT16,
-		gc.TFLOAT64<<16 | gc.TINT8,
-		gc.TFLOAT64<<16 | gc.TUINT16,
-		gc.TFLOAT64<<16 | gc.TUINT8:
-		cvt = gc.Types[gc.TINT32]
+	case ir.TFLOAT32<<16 | ir.TINT16,
+		ir.TFLOAT32<<16 | ir.TINT8,
+		ir.TFLOAT32<<16 | ir.TUINT16,
+		ir.TFLOAT32<<16 | ir.TUINT8,
+		ir.TFLOAT64<<16 | ir.TINT16,
+		ir.TFLOAT64<<16 | ir.TINT8,
+		ir.TFLOAT64<<16 | ir.TUINT16,
+		ir.TFLOAT64<<16 | ir.TUINT8:
+		cvt = ir.Types[ir.TINT32]
 
 		goto hard
 
 		// convert via int64.
-	case gc.TFLOAT32<<16 | gc.TUINT32,
-		gc.TFLOAT64<<16 | gc.TUINT32:
-		cvt = gc.Types[gc.TINT64]
+	case ir.TFLOAT32<<16 | ir.TUINT32,
+		ir.TFLOAT64<<16 | ir.TUINT32:
+		cvt = ir.Types[ir.TINT64]
 
 		goto hardmem
 
-	case gc.TFLOAT32<<16 | gc.TINT32:
+	case ir.TFLOAT32<<16 | ir.TINT32:
 		a = x86.ACVTTSS2SL
 		goto rdst
 
-	case gc.TFLOAT64<<16 | gc.TINT32:
+	case ir.TFLOAT64<<16 | ir.TINT32:
 		a = x86.ACVTTSD2SL
 		goto rdst
 
@@ -1658,47 +1659,47 @@ func floatmove_sse(f *gc.Node, t *gc.Node) {
 	/*
 	 * integer to float
 	 */
-	case gc.TINT8<<16 | gc.TFLOAT32,
-		gc.TINT8<<16 | gc.TFLOAT64,
-		gc.TINT16<<16 | gc.TFLOAT32,
-		gc.TINT16<<16 | gc.TFLOAT64,
-		gc.TUINT16<<16 | gc.TFLOAT32,
-		gc.TUINT16<<16 | gc.TFLOAT64,
-		gc.TUINT8<<16 | gc.TFLOAT32,
-		gc.TUINT8<<16 | gc.TFLOAT64:
-		cvt = gc.Types[gc.TINT32]
+	case ir.TINT8<<16 | ir.TFLOAT32,
+		ir.TINT8<<16 | ir.TFLOAT64,
+		ir.TINT16<<16 | ir.TFLOAT32,
+		ir.TINT16<<16 | ir.TFLOAT64,
+		ir.TUINT16<<16 | ir.TFLOAT32,
+		ir.TUINT16<<16 | ir.TFLOAT64,
+		ir.TUINT8<<16 | ir.TFLOAT32,
+		ir.TUINT8<<16 | ir.TFLOAT64:
+		cvt = ir.Types[ir.TINT32]
 
 		goto hard
 
 		// convert via int64 memory
-	case gc.TUINT32<<16 | gc.TFLOAT32,
-		gc.TUINT32<<16 | gc.TFLOAT64:
-		cvt = gc.Types[gc.TINT64]
+	case ir.TUINT32<<16 | ir.TFLOAT32,
+		ir.TUINT32<<16 | ir.TFLOAT64:
+		cvt = ir.Types[ir.TINT64]
 
 		goto hardmem
 
-	case gc.TINT32<<16 | gc.TFLOAT32:
+	case ir.TINT32<<16 | ir.TFLOAT32:
 		a = x86.ACVTSL2SS
 		goto rdst
 
-	case gc.TINT32<<16 | gc.TFLOAT64:
+	case ir.TINT32<<16 | ir.TFLOAT64:
 		a = x86.ACVTSL2SD
 		goto rdst
 
 		/*
 		 * float to float
 		 */
-	case gc.TFLOAT32<<16 | gc.TFLOAT32:
+	case ir.TFLOAT32<<16 | ir.TFLOAT32:
 		a = x86.AMOVSS
 
-	case gc.TFLOAT64<<16 | gc.TFLOAT64:
+	case ir.TFLOAT64<<16 | ir.TFLOAT64:
 		a = x86.AMOVSD
 
-	case gc.TFLOAT32<<16 | gc.TFLOAT64:
+	case ir.TFLOAT32<<16 | ir.TFLOAT64:
 		a = x86.ACVTSS2SD
 		goto rdst
 
-	case gc.TFLOAT64<<16 | gc.TFLOAT32:
+	case ir.TFLOAT64<<16 | ir.TFLOAT32:
 		a = x86.ACVTSD2SS
 		goto rdst
 	}
@@ -1733,13 +1734,13 @@ rdst:
 	return
 }
 
-func samaddr(f *gc.Node, t *gc.Node) bool {
+func samaddr(f *ir.Node, t *ir.Node) bool {
 	if f.Op != t.Op {
 		return false
 	}
 
 	switch f.Op {
-	case gc.OREGISTER:
+	case ir.OREGISTER:
 		if f.Reg != t.Reg {
 			break
 		}
@@ -1753,18 +1754,18 @@ func samaddr(f *gc.Node, t *gc.Node) bool {
  * generate one instruction:
  *	as f, t
  */
-func gins(as obj.As, f *gc.Node, t *gc.Node) *obj.Prog {
-	if as == x86.AFMOVF && f != nil && f.Op == gc.OREGISTER && t != nil && t.Op == gc.OREGISTER {
+func gins(as obj.As, f *ir.Node, t *ir.Node) *obj.Prog {
+	if as == x86.AFMOVF && f != nil && f.Op == ir.OREGISTER && t != nil && t.Op == ir.OREGISTER {
 		gc.Fatalf("gins MOVF reg, reg")
 	}
-	if as == x86.ACVTSD2SS && f != nil && f.Op == gc.OLITERAL {
+	if as == x86.ACVTSD2SS && f != nil && f.Op == ir.OLITERAL {
 		gc.Fatalf("gins CVTSD2SS const")
 	}
-	if as == x86.AMOVSD && t != nil && t.Op == gc.OREGISTER && t.Reg == x86.REG_F0 {
+	if as == x86.AMOVSD && t != nil && t.Op == ir.OREGISTER && t.Reg == x86.REG_F0 {
 		gc.Fatalf("gins MOVSD into F0")
 	}
 
-	if as == x86.AMOVL && f != nil && f.Op == gc.OADDR && f.Left.Op == gc.ONAME && f.Left.Class != gc.PEXTERN && f.Left.Class != gc.PFUNC {
+	if as == x86.AMOVL && f != nil && f.Op == ir.OADDR && f.Left.Op == ir.ONAME && f.Left.Class != ir.PEXTERN && f.Left.Class != ir.PFUNC {
 		// Turn MOVL $xxx(FP/SP) into LEAL xxx.
 		// These should be equivalent but most of the backend
 		// only expects to see LEAL, because that's what we had
@@ -1782,7 +1783,7 @@ func gins(as obj.As, f *gc.Node, t *gc.Node) *obj.Prog {
 		}
 
 	case x86.ALEAL:
-		if f != nil && gc.Isconst(f, gc.CTNIL) {
+		if f != nil && ir.Isconst(f, ir.CTNIL) {
 			gc.Fatalf("gins LEAL nil %v", f.Type)
 		}
 	}
@@ -1821,18 +1822,18 @@ func gins(as obj.As, f *gc.Node, t *gc.Node) *obj.Prog {
 }
 
 func ginsnop() {
-	var reg gc.Node
-	gc.Nodreg(&reg, gc.Types[gc.TINT], x86.REG_AX)
+	var reg ir.Node
+	gc.Nodreg(&reg, ir.Types[ir.TINT], x86.REG_AX)
 	gins(x86.AXCHGL, &reg, &reg)
 }
 
-func dotaddable(n *gc.Node, n1 *gc.Node) bool {
-	if n.Op != gc.ODOT {
+func dotaddable(n *ir.Node, n1 *ir.Node) bool {
+	if n.Op != ir.ODOT {
 		return false
 	}
 
 	var oary [10]int64
-	var nn *gc.Node
+	var nn *ir.Node
 	o := gc.Dotoffset(n, oary[:], &nn)
 	if nn != nil && nn.Addable && o == 1 && oary[0] >= 0 {
 		*n1 = *nn
@@ -1847,7 +1848,7 @@ func dotaddable(n *gc.Node, n1 *gc.Node) bool {
 func sudoclean() {
 }
 
-func sudoaddable(as obj.As, n *gc.Node, a *obj.Addr) bool {
+func sudoaddable(as obj.As, n *ir.Node, a *obj.Addr) bool {
 	*a = obj.Addr{}
 	return false
 }
@@ -5,16 +5,17 @@
 package main
 
 import (
-	"cmd/compile/internal/amd64"
-	"cmd/compile/internal/arm"
-	"cmd/compile/internal/arm64"
-	"cmd/compile/internal/mips64"
-	"cmd/compile/internal/ppc64"
-	"cmd/compile/internal/x86"
-	"cmd/internal/obj"
-	"fmt"
+	"cmd/compile/internal/gc"
 	"log"
-	"os"
+
+	// The back ends register themselves with gc.RegisterBackend.
+	// A new back end is added by importing it here.
+	_ "cmd/compile/internal/amd64"
+	_ "cmd/compile/internal/arm"
+	_ "cmd/compile/internal/arm64"
+	_ "cmd/compile/internal/mips64"
+	_ "cmd/compile/internal/ppc64"
+	_ "cmd/compile/internal/x86"
 )
 
 func main() {
@@ -22,21 +23,6 @@ func main() {
 	log.SetFlags(0)
 	log.SetPrefix("compile: ")
 
-	switch obj.Getgoarch() {
-	default:
-		fmt.Fprintf(os.Stderr, "compile: unknown architecture %q\n", obj.Getgoarch())
-		os.Exit(2)
-	case "386":
-		x86.Main()
-	case "amd64", "amd64p32":
-		amd64.Main()
-	case "arm":
-		arm.Main()
-	case "arm64":
-		arm64.Main()
-	case "mips64", "mips64le":
-		mips64.Main()
-	case "ppc64", "ppc64le":
-		ppc64.Main()
-	}
+	gc.Main()
+	gc.Exit(0)
 }
@@ -34,6 +34,7 @@ var bootstrapDirs = []string{
 	"compile/internal/arm64",
 	"compile/internal/big",
 	"compile/internal/gc",
+	"compile/internal/ir",
 	"compile/internal/mips64",
 	"compile/internal/ppc64",
 	"compile/internal/ssa",
@@ -94,6 +94,10 @@ and test commands:
 		enable interoperation with memory sanitizer.
 		Supported only on linux/amd64,
 		and only with Clang/LLVM as the host C compiler.
+	-asan
+		enable interoperation with address sanitizer.
+		Supported only on linux/amd64,
+		and only with Clang/LLVM as the host C compiler.
 	-v
 		print the names of packages as they are compiled.
 	-work
@@ -117,8 +121,8 @@ and test commands:
 		in order to keep output separate from default builds.
 		If using the -race flag, the install suffix is automatically set to race
 		or, if set explicitly, has _race appended to it.  Likewise for the -msan
-		flag.  Using a -buildmode option that requires non-default compile flags
-		has a similar effect.
+		and -asan flags.  Using a -buildmode option that requires non-default
+		compile flags has a similar effect.
 	-ldflags 'flag list'
 		arguments to pass on each go tool link invocation.
 	-linkshared
@@ -73,6 +73,10 @@ and test commands:
 		enable interoperation with memory sanitizer.
 		Supported only on linux/amd64,
 		and only with Clang/LLVM as the host C compiler.
+	-asan
+		enable interoperation with address sanitizer.
+		Supported only on linux/amd64,
+		and only with Clang/LLVM as the host C compiler.
 	-v
 		print the names of packages as they are compiled.
 	-work
@@ -96,8 +100,8 @@ and test commands:
 		in order to keep output separate from default builds.
 		If using the -race flag, the install suffix is automatically set to race
 		or, if set explicitly, has _race appended to it.  Likewise for the -msan
-		flag.  Using a -buildmode option that requires non-default compile flags
-		has a similar effect.
+		and -asan flags.  Using a -buildmode option that requires non-default
+		compile flags has a similar effect.
 	-ldflags 'flag list'
 		arguments to pass on each go tool link invocation.
 	-linkshared
@@ -161,6 +165,7 @@ var buildLdflags []string    // -ldflags flag
 var buildGccgoflags []string // -gccgoflags flag
 var buildRace bool           // -race flag
 var buildMSan bool           // -msan flag
+var buildASan bool           // -asan flag
 var buildToolExec []string   // -toolexec flag
 var buildBuildmode string    // -buildmode flag
 var buildLinkshared bool     // -linkshared flag
@@ -221,6 +226,7 @@ func addBuildFlags(cmd *Command) {
 	cmd.Flag.StringVar(&buildPkgdir, "pkgdir", "", "")
 	cmd.Flag.BoolVar(&buildRace, "race", false, "")
 	cmd.Flag.BoolVar(&buildMSan, "msan", false, "")
+	cmd.Flag.BoolVar(&buildASan, "asan", false, "")
 	cmd.Flag.Var((*stringsFlag)(&buildContext.BuildTags), "tags", "")
 	cmd.Flag.Var((*stringsFlag)(&buildToolExec), "toolexec", "")
 	cmd.Flag.BoolVar(&buildWork, "work", false, "")
@@ -2268,7 +2274,7 @@ func (gcToolchain) gc(b *builder, p *Package, archive, obj string, asmhdr bool,
 	extFiles := len(p.CgoFiles) + len(p.CFiles) + len(p.CXXFiles) + len(p.MFiles) + len(p.FFiles) + len(p.SFiles) + len(p.SysoFiles) + len(p.SwigFiles) + len(p.SwigCXXFiles)
 	if p.Standard {
 		switch p.ImportPath {
-		case "bytes", "net", "os", "runtime/pprof", "sync", "time":
+		case "bytes", "net", "os", "runtime/pprof", "sync", "testing", "time":
 			extFiles++
 		}
 	}
@@ -3094,7 +3100,7 @@ func (b *builder) cgo(p *Package, cgoExe, obj string, pcCFLAGS, pcLDFLAGS, cgofi
 	if p.Standard && p.ImportPath == "runtime/cgo" {
 		cgoflags = append(cgoflags, "-import_runtime_cgo=false")
 	}
-	if p.Standard && (p.ImportPath == "runtime/race" || p.ImportPath == "runtime/msan" || p.ImportPath == "runtime/cgo") {
+	if p.Standard && (p.ImportPath == "runtime/race" || p.ImportPath == "runtime/msan" || p.ImportPath == "runtime/asan" || p.ImportPath == "runtime/cgo") {
 		cgoflags = append(cgoflags, "-import_syscall=false")
 	}
 
@@ -3564,37 +3570,41 @@ func (q *actionQueue) pop() *action {
 }
 
 func instrumentInit() {
-	if !buildRace && !buildMSan {
+	var modes []string
+	if buildRace {
+		modes = append(modes, "race")
+	}
+	if buildMSan {
+		modes = append(modes, "msan")
+	}
+	if buildASan {
+		modes = append(modes, "asan")
+	}
+	if len(modes) == 0 {
 		return
 	}
-	if buildRace && buildMSan {
-		fmt.Fprintf(os.Stderr, "go %s: may not use -race and -msan simultaneously", flag.Args()[0])
+	if len(modes) > 1 {
+		fmt.Fprintf(os.Stderr, "go %s: may not use -%s and -%s simultaneously", flag.Args()[0], modes[0], modes[1])
 		os.Exit(2)
 	}
 	if goarch != "amd64" || goos != "linux" && goos != "freebsd" && goos != "darwin" && goos != "windows" {
 		fmt.Fprintf(os.Stderr, "go %s: -race and -msan are only supported on linux/amd64, freebsd/amd64, darwin/amd64 and windows/amd64\n", flag.Args()[0])
 		os.Exit(2)
 	}
-	if !buildContext.CgoEnabled {
-		fmt.Fprintf(os.Stderr, "go %s: -race requires cgo; enable cgo by setting CGO_ENABLED=1\n", flag.Args()[0])
+	if buildASan && goos != "linux" {
+		fmt.Fprintf(os.Stderr, "go %s: -asan is only supported on linux/amd64\n", flag.Args()[0])
 		os.Exit(2)
 	}
-	if buildRace {
-		buildGcflags = append(buildGcflags, "-race")
-		buildLdflags = append(buildLdflags, "-race")
-	} else {
-		buildGcflags = append(buildGcflags, "-msan")
-		buildLdflags = append(buildLdflags, "-msan")
+	if !buildContext.CgoEnabled {
+		fmt.Fprintf(os.Stderr, "go %s: -%s requires cgo; enable cgo by setting CGO_ENABLED=1\n", flag.Args()[0], modes[0])
+		os.Exit(2)
 	}
+	mode := modes[0]
+	buildGcflags = append(buildGcflags, "-"+mode)
+	buildLdflags = append(buildLdflags, "-"+mode)
 	if buildContext.InstallSuffix != "" {
 		buildContext.InstallSuffix += "_"
 	}
-
-	if buildRace {
-		buildContext.InstallSuffix += "race"
-		buildContext.BuildTags = append(buildContext.BuildTags, "race")
-	} else {
-		buildContext.InstallSuffix += "msan"
-		buildContext.BuildTags = append(buildContext.BuildTags, "msan")
-	}
+	buildContext.InstallSuffix += mode
+	buildContext.BuildTags = append(buildContext.BuildTags, mode)
 }
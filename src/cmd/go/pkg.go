@@ -731,6 +731,7 @@ func expandScanner(err error) error {
 var raceExclude = map[string]bool{
 	"runtime/race": true,
 	"runtime/msan": true,
+	"runtime/asan": true,
 	"runtime/cgo":  true,
 	"cmd/cgo":      true,
 	"syscall":      true,
@@ -745,6 +746,7 @@ var cgoSyscallExclude = map[string]bool{
 	"runtime/cgo":  true,
 	"runtime/race": true,
 	"runtime/msan": true,
+	"runtime/asan": true,
 }
 
 // load populates p using information from bp, err, which should
@@ -865,6 +867,10 @@ func (p *Package) load(stk *importStack, bp *build.Package, err error) *Package
 		if buildMSan && (!p.Standard || !raceExclude[p.ImportPath]) {
 			importPaths = append(importPaths, "runtime/msan")
 		}
+		// ASan uses runtime/asan.
+		if buildASan && (!p.Standard || !raceExclude[p.ImportPath]) {
+			importPaths = append(importPaths, "runtime/asan")
+		}
 		// On ARM with GOARM=5, everything depends on math for the link.
 		if p.Name == "main" && goarch == "arm" {
 			importPaths = append(importPaths, "math")
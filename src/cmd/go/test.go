@@ -604,6 +604,9 @@ func runTest(cmd *Command, args []string) {
 		if buildMSan {
 			extraOpts = "-msan "
 		}
+		if buildASan {
+			extraOpts = "-asan "
+		}
 		fmt.Fprintf(os.Stderr, "installing these packages with 'go test %s-i%s' will speed future tests.\n\n", extraOpts, args)
 	}
 
@@ -185,7 +185,20 @@ type Var struct {
 	Kind   int    // TODO(rsc): Define meaning.
 	Offset int    // Frame offset. TODO(rsc): Define meaning.
 
-	Type SymID // Go type for variable.
+	Type  SymID // Go type for variable.
+	Scope int   // Index of the enclosing lexical scope in Func.Scope.
+}
+
+// A Scope is a lexical block of a function. Scope 0 is the function
+// itself; each of the others is nested in a preceding Parent.
+type Scope struct {
+	Parent int
+	Ranges []Range // PCs covered, relative to the start of the function
+}
+
+// A Range is the half-open interval [Start, End).
+type Range struct {
+	Start, End int64
 }
 
 // Func contains additional per-symbol information specific to functions.
@@ -195,9 +208,11 @@ type Func struct {
 	Leaf     bool       // function omits save of link register (ARM)
 	NoSplit  bool       // function omits stack split prologue
 	Var      []Var      // detail about local variables
+	Scope    []Scope    // lexical scopes of local variables
 	PCSP     Data       // PC → SP offset map
 	PCFile   Data       // PC → file number map (index into File)
 	PCLine   Data       // PC → line number map
+	PCStmt   Data       // PC → statement start flag map, if known
 	PCData   []Data     // PC → runtime support data map
 	FuncData []FuncData // non-PC-specific runtime support data
 	File     []string   // paths indexed by PCFile
@@ -581,12 +596,12 @@ func (r *objReader) parseObject(prefix []byte) error {
 	}
 
 	r.readFull(r.tmp[:8])
-	if !bytes.Equal(r.tmp[:8], []byte("\x00\x00go13ld")) {
+	if !bytes.Equal(r.tmp[:8], []byte("\x00\x00go17ld")) {
 		return r.error(errCorruptObject)
 	}
 
-	b := r.readByte()
-	if b != 1 {
+	version := r.readByte()
+	if version != 1 && version != 2 {
 		return r.error(errCorruptObject)
 	}
 
@@ -615,6 +630,17 @@ func (r *objReader) parseObject(prefix []byte) error {
 	r.dataOffset = r.offset
 	r.skip(int64(dataLength))
 
+	if version == 2 {
+		// Skip the symbol index and the length of the symbols.
+		for n := r.readInt(); n > 0; n-- {
+			r.readSymID()
+			for i := 0; i < 5; i++ {
+				r.readInt()
+			}
+		}
+		r.readInt()
+	}
+
 	// Symbols.
 	for {
 		if b := r.readByte(); b != 0xfe {
@@ -658,11 +684,23 @@ func (r *objReader) parseObject(prefix []byte) error {
 				v.Offset = r.readInt()
 				v.Kind = r.readInt()
 				v.Type = r.readSymID()
+				v.Scope = r.readInt()
+			}
+			f.Scope = make([]Scope, r.readInt())
+			for i := range f.Scope {
+				sc := &f.Scope[i]
+				sc.Parent = r.readInt()
+				sc.Ranges = make([]Range, r.readInt())
+				for j := range sc.Ranges {
+					sc.Ranges[j].Start = int64(r.readInt())
+					sc.Ranges[j].End = int64(r.readInt())
+				}
 			}
 
 			f.PCSP = r.readData()
 			f.PCFile = r.readData()
 			f.PCLine = r.readData()
+			f.PCStmt = r.readData()
 			f.PCData = make([]Data, r.readInt())
 			for i := range f.PCData {
 				f.PCData[i] = r.readData()
@@ -682,7 +720,7 @@ func (r *objReader) parseObject(prefix []byte) error {
 	}
 
 	r.readFull(r.tmp[:7])
-	if !bytes.Equal(r.tmp[:7], []byte("\xffgo13ld")) {
+	if !bytes.Equal(r.tmp[:7], []byte("\xffgo17ld")) {
 		return r.error(errCorruptObject)
 	}
 
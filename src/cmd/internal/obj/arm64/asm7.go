@@ -3639,8 +3639,7 @@ func brdist(ctxt *obj.Link, p *obj.Prog, preshift int, flen int, shift int) int6
 		v >>= uint(shift)
 		t = int64(1) << uint(flen-1)
 		if v < -t || v >= t {
-			ctxt.Diag("branch too far %#x vs %#x [%p]\n%v\n%v", v, t, ctxt.Blitrl, p, p.Pcond)
-			panic("branch too far")
+			ctxt.Diag("branch too far in %s: %#x vs %#x [%p]\n%v\n%v", ctxt.Cursym.Name, v, t, ctxt.Blitrl, p, p.Pcond)
 		}
 	}
 
@@ -0,0 +1,110 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package obj
+
+import (
+	"encoding/json"
+	"log"
+	"sort"
+)
+
+// With Debugasmjson, the assembly listing has one line for each
+// function, holding a JSON object of type asmFunc, for tools that
+// would otherwise parse the text listing.
+
+// asmFunc is the listing of a function.
+type asmFunc struct {
+	Name         string    `json:"name"`
+	Size         int64     `json:"size"`
+	Args         int32     `json:"args"`
+	Locals       int32     `json:"locals"`
+	Leaf         bool      `json:"leaf,omitempty"`
+	Nosplit      bool      `json:"nosplit,omitempty"`
+	Instructions []asmInst `json:"instructions"`
+	Totals       asmTotals `json:"totals"`
+}
+
+// asmInst is an instruction in a function. Pseudo-instructions,
+// such as TEXT and PCDATA, have size 0.
+type asmInst struct {
+	Pc     int64      `json:"pc"`
+	Size   int64      `json:"size"`
+	File   string     `json:"file"`
+	Line   int        `json:"line"`
+	Text   string     `json:"text"`
+	Relocs []asmReloc `json:"relocs,omitempty"`
+}
+
+// asmReloc is a relocation in the code of an instruction.
+type asmReloc struct {
+	Off  int32  `json:"off"` // from the start of the function
+	Size uint8  `json:"size"`
+	Type int32  `json:"type"`
+	Sym  string `json:"sym"`
+	Add  int64  `json:"add"`
+}
+
+// asmTotals sums up a function.
+type asmTotals struct {
+	Instructions int   `json:"instructions"` // not counting pseudo-instructions
+	Bytes        int64 `json:"bytes"`
+	Relocs       int   `json:"relocs"`
+}
+
+// writesymjson writes the JSON listing of the function s to ctxt.Bso.
+func writesymjson(ctxt *Link, s *LSym) {
+	f := asmFunc{
+		Name:         s.Name,
+		Size:         s.Size,
+		Args:         s.Args,
+		Locals:       s.Locals,
+		Leaf:         s.Leaf != 0,
+		Nosplit:      s.Nosplit != 0,
+		Instructions: []asmInst{},
+	}
+	for p := s.Text; p != nil; p = p.Link {
+		end := s.Size
+		if p.Link != nil {
+			end = p.Link.Pc
+		}
+		file, line := ctxt.LineHist.FileLine(int(p.Lineno))
+		f.Instructions = append(f.Instructions, asmInst{
+			Pc:   p.Pc,
+			Size: end - p.Pc,
+			File: file,
+			Line: line,
+			Text: p.InstructionString(),
+		})
+		if end > p.Pc {
+			f.Totals.Instructions++
+		}
+	}
+	f.Totals.Bytes = s.Size
+
+	// Give each relocation to the last instruction that starts at
+	// or before it.
+	sort.Sort(relocByOff(s.R))
+	for _, r := range s.R {
+		i := sort.Search(len(f.Instructions), func(i int) bool {
+			return f.Instructions[i].Pc > int64(r.Off)
+		})
+		if i == 0 {
+			continue
+		}
+		name := ""
+		if r.Sym != nil {
+			name = r.Sym.Name
+		}
+		in := &f.Instructions[i-1]
+		in.Relocs = append(in.Relocs, asmReloc{r.Off, r.Siz, r.Type, name, r.Add})
+		f.Totals.Relocs++
+	}
+
+	b, err := json.Marshal(f)
+	if err != nil {
+		log.Fatalf("%s: %v", s.Name, err)
+	}
+	ctxt.Bso.Write(append(b, '\n'))
+}
@@ -20,20 +20,29 @@ var (
 // Toolchain experiments.
 // These are controlled by the GOEXPERIMENT environment
 // variable recorded when the toolchain is built.
+// An experiment that is on by default is turned off
+// by prefixing its name with "no", as in GOEXPERIMENT=noframepointer.
 // This list is also known to cmd/gc.
 var exper = []struct {
 	name string
 	val  *int
+	def  int // default value
 }{
-	{"fieldtrack", &Fieldtrack_enabled},
-	{"framepointer", &Framepointer_enabled},
+	{"fieldtrack", &Fieldtrack_enabled, 0},
+	{"framepointer", &Framepointer_enabled, 0},
 }
 
 func addexp(s string) {
+	v := 1
+	name := s
+	if strings.HasPrefix(name, "no") {
+		v = 0
+		name = name[2:]
+	}
 	for i := 0; i < len(exper); i++ {
-		if exper[i].name == s {
+		if exper[i].name == name {
 			if exper[i].val != nil {
-				*exper[i].val = 1
+				*exper[i].val = v
 			}
 			return
 		}
@@ -44,6 +53,18 @@ func addexp(s string) {
 }
 
 func init() {
+	// Frame pointers are maintained by default on amd64,
+	// so that external profilers and debuggers can walk the stack.
+	// This reserves BP and adds a save and restore to every function
+	// with a frame, which grows the text of cmd/go by about 3%.
+	// The runtime makes the same decision; see runtime/proc.go.
+	for i := range exper {
+		if exper[i].name == "framepointer" && Getgoarch() == "amd64" && Getgoos() != "nacl" {
+			exper[i].def = 1
+		}
+		*exper[i].val = exper[i].def
+	}
+
 	for _, f := range strings.Split(goexperiment, ",") {
 		if f != "" {
 			addexp(f)
@@ -60,11 +81,17 @@ func Nopout(p *Prog) {
 	p.To = Addr{}
 }
 
+// Expstring returns the experiments that differ from the defaults,
+// in the form recorded in object file headers.
 func Expstring() string {
 	buf := "X"
 	for i := range exper {
-		if *exper[i].val != 0 {
-			buf += "," + exper[i].name
+		if *exper[i].val != exper[i].def {
+			if *exper[i].val == 0 {
+				buf += ",no" + exper[i].name
+			} else {
+				buf += "," + exper[i].name
+			}
 		}
 	}
 	if buf == "X" {
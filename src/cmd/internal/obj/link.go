@@ -154,13 +154,13 @@ type Addr struct {
 	Offset int64
 	Width  int64
 	Sym    *LSym
-	Gotype *LSym
 
 	// argument value:
 	//	for TYPE_SCONST, a string
 	//	for TYPE_FCONST, a float64
 	//	for TYPE_BRANCH, a *Prog (optional)
 	//	for TYPE_TEXTSIZE, an int32 (optional)
+	//	for the From of an ATYPE, the *LSym of the Go type (optional)
 	Val interface{}
 
 	Node interface{} // for use by compiler
@@ -223,6 +223,7 @@ type Prog struct {
 	Tt     uint8
 	Isize  uint8 // size of the instruction in bytes (x86 only)
 	Mode   int8
+	Stmt   bool // first instruction of a source statement
 
 	Info ProgInfo
 }
@@ -324,6 +325,10 @@ type LSym struct {
 	// Used by the linker to determine what methods can be pruned.
 	ReflectMethod bool
 
+	// NoDWARF means the linker should leave the function out of the
+	// DWARF debugging information.
+	NoDWARF bool
+
 	// Local means make the symbol local even when compiling Go code to reference Go
 	// symbols in other shared libraries, as in this mode symbols are global by
 	// default. "local" here means in the sense of the dynamic linker, i.e. not
@@ -338,6 +343,7 @@ type LSym struct {
 	Size   int64
 	Gotype *LSym
 	Autom  *Auto
+	Scopes []Scope // lexical scopes of a function, for DWARF; see Scope
 	Text   *Prog
 	Pcln   *Pcln
 	P      []byte
@@ -354,6 +360,7 @@ type Pcln struct {
 	Pcsp        Pcdata
 	Pcfile      Pcdata
 	Pcline      Pcdata
+	Pcstmt      Pcdata // statement starts, for DWARF; empty if not known
 	Pcdata      []Pcdata
 	Funcdata    []*LSym
 	Funcdataoff []int64
@@ -494,6 +501,11 @@ const (
 	// of a JMP instruction, by encoding the address into the instruction.
 	// The stack nosplit check ignores this since it is not a function call.
 	R_JMPMIPS
+	// R_INITVAR resolves to the address of the function value V·init·f
+	// that initializes the package-level variable V. It is used in the
+	// table of outlined variable initializers called by the package init
+	// function, and is set to zero by the linker if V is unreachable.
+	R_INITVAR
 
 	// Platform dependent relocations. Architectures with fixed width instructions
 	// have the inherent issue that a 32-bit (or 64-bit!) displacement cannot be
@@ -577,6 +589,26 @@ type Auto struct {
 	Aoffset int32
 	Name    int16
 	Gotype  *LSym
+	Scope   int32 // index of the enclosing scope in LSym.Scopes
+}
+
+// A Scope is a lexical block of a function. Scope 0 is the
+// function itself; every other scope is nested in its Parent,
+// which precedes it in LSym.Scopes.
+//
+// The compiler fills in the lines a block spans. After assembly,
+// each instruction belongs to the innermost scope whose lines
+// contain it, and Ranges lists the PCs, relative to the start of
+// the function, covered by a scope and the scopes nested in it.
+type Scope struct {
+	Parent     int32
+	Start, End int32 // line numbers, as in Prog.Lineno
+	Ranges     []ScopeRange
+}
+
+// A ScopeRange is the half-open interval [Start, End) of PCs.
+type ScopeRange struct {
+	Start, End int64
 }
 
 // Auto.name
@@ -615,12 +647,15 @@ type Link struct {
 	Headtype      int
 	Arch          *LinkArch
 	Debugasm      int32
+	Debugasmjson  bool // with Debugasm, list the functions in JSON
+	Debugasmsrc   bool // with Debugasm, show the source lines of the instructions
 	Debugvlog     int32
 	Debugdivmod   int32
 	Debugpcln     int32
 	Flag_shared   int32
 	Flag_dynlink  bool
 	Flag_optimize bool
+	Flag_objindex bool
 	Bso           *Biobuf
 	Pathname      string
 	Goroot        string
@@ -663,9 +698,15 @@ type Link struct {
 	Text []*LSym
 	Data []*LSym
 
-	// Cache of Progs
-	allocIdx int
-	progs    [10000]Prog
+	// Cache of Progs. NewProg hands out Progs from progs, the block
+	// progBlocks[nblocks-1]; flushplist returns all of them at once.
+	progBlocks [][]Prog
+	nblocks    int
+	progs      []Prog
+	allocIdx   int
+
+	// Lines of the source files the assembly listing has shown, by file.
+	sourceLines map[string][]string
 }
 
 func (ctxt *Link) Diag(format string, args ...interface{}) {
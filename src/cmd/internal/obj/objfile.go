@@ -15,7 +15,7 @@
 //
 // The file format is:
 //
-//	- magic header: "\x00\x00go13ld"
+//	- magic header: "\x00\x00go17ld"
 //	- byte 1 - version number
 //	- sequence of strings giving dependencies (imported packages)
 //	- empty string (marks end of sequence)
@@ -25,7 +25,24 @@
 //	- data, the content of the defined symbols
 //	- sequence of defined symbols
 //	- byte 0xff (marks end of sequence)
-//	- magic footer: "\xff\xffgo13ld"
+//	- magic footer: "\xff\xffgo17ld"
+//
+// If Link.Flag_objindex is set, the version number is 2, and a symbol
+// index precedes the defined symbols, so that the linker can find each
+// definition without decoding the ones before it:
+//
+//	- nsym [int]
+//	- nsym index entries
+//	- integer (length of the sequence of defined symbols)
+//
+// Each index entry describes the corresponding defined symbol:
+//
+//	- name & version [symref index]
+//	- type [int]
+//	- flags [int], as in the symbol
+//	- offset [int] of the symbol from the start of the sequence
+//	- offset [int] of the symbol's content in data
+//	- nr [int]
 //
 // All integers are stored in a zigzag varint format.
 // See golang.org/s/go12symtab for a definition.
@@ -62,8 +79,11 @@
 //		1<<0 leaf
 //		1<<1 C function
 //		1<<2 function may call reflect.Type.Method
+//		1<<3 function is left out of DWARF
 //	- nlocal [int]
 //	- local [nlocal automatics]
+//	- nscope [int]
+//	- scope [nscope lexical scopes]
 //	- pcln [pcln table]
 //
 // Each relocation has the encoding:
@@ -80,12 +100,20 @@
 //	- offset [int]
 //	- type [int]
 //	- gotype [symref index]
+//	- scope [int]
+//
+// Each lexical scope has the encoding:
+//
+//	- parent [int]
+//	- nrange [int]
+//	- ranges [nrange pairs of start and end pc ints]
 //
 // The pcln table has the encoding:
 //
 //	- pcsp [data block]
 //	- pcfile [data block]
 //	- pcline [data block]
+//	- pcstmt [data block]
 //	- npcdata [int]
 //	- pcdata [npcdata data blocks]
 //	- nfuncdata [int]
@@ -102,7 +130,9 @@
 package obj
 
 import (
+	"bytes"
 	"fmt"
+	"io/ioutil"
 	"log"
 	"path/filepath"
 	"sort"
@@ -163,7 +193,8 @@ func flushplist(ctxt *Link, freeProgs bool) {
 				a.Asym = p.From.Sym
 				a.Aoffset = int32(p.From.Offset)
 				a.Name = int16(p.From.Name)
-				a.Gotype = p.From.Gotype
+				a.Gotype, _ = p.From.Val.(*LSym)
+				a.Scope = int32(p.From3Offset())
 				a.Link = curtext.Autom
 				curtext.Autom = a
 				continue
@@ -289,6 +320,7 @@ func flushplist(ctxt *Link, freeProgs bool) {
 		ctxt.Arch.Assemble(ctxt, s)
 		fieldtrack(ctxt, s)
 		linkpcln(ctxt, s)
+		scopepcs(s)
 		if freeProgs {
 			s.Text = nil
 		}
@@ -309,8 +341,12 @@ func Writeobjfile(ctxt *Link, b *Biobuf) {
 	Bputc(b, 0)
 
 	Bputc(b, 0)
-	fmt.Fprintf(b, "go13ld")
-	Bputc(b, 1) // version
+	fmt.Fprintf(b, "go17ld")
+	if ctxt.Flag_objindex {
+		Bputc(b, 2) // version
+	} else {
+		Bputc(b, 1) // version
+	}
 
 	// Emit autolib.
 	for _, pkg := range ctxt.Imports {
@@ -322,15 +358,7 @@ func Writeobjfile(ctxt *Link, b *Biobuf) {
 	// Emit symbol references.
 	for _, s := range ctxt.Text {
 		writerefs(ctxt, b, s)
-		dataLength += int64(len(s.P))
-
-		pc := s.Pcln
-		dataLength += int64(len(pc.Pcsp.P))
-		dataLength += int64(len(pc.Pcfile.P))
-		dataLength += int64(len(pc.Pcline.P))
-		for i := 0; i < len(pc.Pcdata); i++ {
-			dataLength += int64(len(pc.Pcdata[i].P))
-		}
+		dataLength += symDataLength(s)
 	}
 	for _, s := range ctxt.Data {
 		writerefs(ctxt, b, s)
@@ -346,6 +374,7 @@ func Writeobjfile(ctxt *Link, b *Biobuf) {
 		b.w.Write(pc.Pcsp.P)
 		b.w.Write(pc.Pcfile.P)
 		b.w.Write(pc.Pcline.P)
+		b.w.Write(pc.Pcstmt.P)
 		for i := 0; i < len(pc.Pcdata); i++ {
 			b.w.Write(pc.Pcdata[i].P)
 		}
@@ -355,18 +384,85 @@ func Writeobjfile(ctxt *Link, b *Biobuf) {
 	}
 
 	// Emit symbols.
-	for _, s := range ctxt.Text {
-		writesym(ctxt, b, s)
-	}
-	for _, s := range ctxt.Data {
-		writesym(ctxt, b, s)
+	if ctxt.Flag_objindex {
+		writeindexed(ctxt, b)
+	} else {
+		for _, s := range ctxt.Text {
+			writesym(ctxt, b, s)
+		}
+		for _, s := range ctxt.Data {
+			writesym(ctxt, b, s)
+		}
 	}
 
 	// Emit footer.
 	Bputc(b, 0xff)
 
 	Bputc(b, 0xff)
-	fmt.Fprintf(b, "go13ld")
+	fmt.Fprintf(b, "go17ld")
+}
+
+// writeindexed writes the symbol index followed by the defined symbols.
+func writeindexed(ctxt *Link, b *Biobuf) {
+	var syms bytes.Buffer
+	sb := Binitw(&syms)
+	var dataOff int64
+	type entry struct {
+		s       *LSym
+		off     int
+		dataOff int64
+	}
+	var index []entry
+	add := func(s *LSym) {
+		sb.w.Flush()
+		index = append(index, entry{s, syms.Len(), dataOff})
+		writesym(ctxt, sb, s)
+		dataOff += symDataLength(s)
+	}
+	for _, s := range ctxt.Text {
+		add(s)
+	}
+	for _, s := range ctxt.Data {
+		add(s)
+	}
+	sb.w.Flush()
+
+	wrint(b, int64(len(index)))
+	for _, e := range index {
+		wrsym(b, e.s)
+		wrint(b, int64(e.s.Type))
+		wrint(b, symFlags(e.s))
+		wrint(b, int64(e.off))
+		wrint(b, e.dataOff)
+		wrint(b, int64(len(e.s.R)))
+	}
+	wrint(b, int64(syms.Len()))
+	b.Write(syms.Bytes())
+}
+
+// symDataLength returns the length of the content of s in the data block.
+func symDataLength(s *LSym) int64 {
+	n := int64(len(s.P))
+	if s.Type == STEXT {
+		pc := s.Pcln
+		n += int64(len(pc.Pcsp.P))
+		n += int64(len(pc.Pcfile.P))
+		n += int64(len(pc.Pcline.P))
+		n += int64(len(pc.Pcstmt.P))
+		for i := 0; i < len(pc.Pcdata); i++ {
+			n += int64(len(pc.Pcdata[i].P))
+		}
+	}
+	return n
+}
+
+// symFlags returns the flags of s as written in the object file.
+func symFlags(s *LSym) int64 {
+	flags := int64(s.Dupok)
+	if s.Local {
+		flags |= 2
+	}
+	return flags
 }
 
 // Provide the the index of a symbol reference by symbol name.
@@ -428,8 +524,33 @@ func writerefs(ctxt *Link, b *Biobuf, s *LSym) {
 	}
 }
 
+// sourceLine returns line number line of file, without its leading
+// white space, for the assembly listing. It reports false if the
+// file cannot be read or has no such line.
+func (ctxt *Link) sourceLine(file string, line int) (string, bool) {
+	lines, ok := ctxt.sourceLines[file]
+	if !ok {
+		data, err := ioutil.ReadFile(file)
+		if err == nil {
+			lines = strings.Split(string(data), "\n")
+		}
+		if ctxt.sourceLines == nil {
+			ctxt.sourceLines = make(map[string][]string)
+		}
+		ctxt.sourceLines[file] = lines
+	}
+	if line < 1 || line > len(lines) {
+		return "", false
+	}
+	return strings.TrimSpace(lines[line-1]), true
+}
+
 func writesym(ctxt *Link, b *Biobuf, s *LSym) {
-	if ctxt.Debugasm != 0 {
+	if ctxt.Debugasm != 0 && ctxt.Debugasmjson {
+		if s.Type == STEXT {
+			writesymjson(ctxt, s)
+		}
+	} else if ctxt.Debugasm != 0 {
 		fmt.Fprintf(ctxt.Bso, "%s ", s.Name)
 		if s.Version != 0 {
 			fmt.Fprintf(ctxt.Bso, "v=%d ", s.Version)
@@ -455,7 +576,18 @@ func writesym(ctxt *Link, b *Biobuf, s *LSym) {
 		}
 
 		fmt.Fprintf(ctxt.Bso, "\n")
+		var file string
+		var line int
 		for p := s.Text; p != nil; p = p.Link {
+			if ctxt.Debugasmsrc {
+				f, l := ctxt.LineHist.FileLine(int(p.Lineno))
+				if f != file || l != line {
+					file, line = f, l
+					if src, ok := ctxt.sourceLine(f, l); ok {
+						fmt.Fprintf(ctxt.Bso, "\t// %s:%d\t%s\n", filepath.Base(f), l, src)
+					}
+				}
+			}
 			fmt.Fprintf(ctxt.Bso, "\t%#04x %v\n", uint(int(p.Pc)), p)
 		}
 		var c int
@@ -499,11 +631,7 @@ func writesym(ctxt *Link, b *Biobuf, s *LSym) {
 	Bputc(b, 0xfe)
 	wrint(b, int64(s.Type))
 	wrsym(b, s)
-	flags := int64(s.Dupok)
-	if s.Local {
-		flags |= 2
-	}
-	wrint(b, flags)
+	wrint(b, symFlags(s))
 	wrint(b, s.Size)
 	wrsym(b, s.Gotype)
 	wrint(b, int64(len(s.P)))
@@ -527,6 +655,9 @@ func writesym(ctxt *Link, b *Biobuf, s *LSym) {
 		if s.ReflectMethod {
 			flags |= 1 << 2
 		}
+		if s.NoDWARF {
+			flags |= 1 << 3
+		}
 		wrint(b, flags)
 		n := 0
 		for a := s.Autom; a != nil; a = a.Link {
@@ -544,12 +675,23 @@ func writesym(ctxt *Link, b *Biobuf, s *LSym) {
 				log.Fatalf("%s: invalid local variable type %d", s.Name, a.Name)
 			}
 			wrsym(b, a.Gotype)
+			wrint(b, int64(a.Scope))
+		}
+		wrint(b, int64(len(s.Scopes)))
+		for _, sc := range s.Scopes {
+			wrint(b, int64(sc.Parent))
+			wrint(b, int64(len(sc.Ranges)))
+			for _, r := range sc.Ranges {
+				wrint(b, r.Start)
+				wrint(b, r.End)
+			}
 		}
 
 		pc := s.Pcln
 		wrint(b, int64(len(pc.Pcsp.P)))
 		wrint(b, int64(len(pc.Pcfile.P)))
 		wrint(b, int64(len(pc.Pcline.P)))
+		wrint(b, int64(len(pc.Pcstmt.P)))
 		wrint(b, int64(len(pc.Pcdata)))
 		for i := 0; i < len(pc.Pcdata); i++ {
 			wrint(b, int64(len(pc.Pcdata[i].P)))
@@ -209,6 +209,18 @@ func pctopcdata(ctxt *Link, sym *LSym, oldval int32, p *Prog, phase int32, arg i
 	return int32(p.To.Offset)
 }
 
+// pctostmt is the valfunc for the table of statement starts:
+// 1 for the instructions marked Stmt, 0 for the others.
+func pctostmt(ctxt *Link, sym *LSym, oldval int32, p *Prog, phase int32, arg interface{}) int32 {
+	if phase == 1 {
+		return oldval
+	}
+	if p.Stmt {
+		return 1
+	}
+	return 0
+}
+
 func linkpcln(ctxt *Link, cursym *LSym) {
 	ctxt.Cursym = cursym
 
@@ -235,6 +247,12 @@ func linkpcln(ctxt *Link, cursym *LSym) {
 	funcpctab(ctxt, &pcln.Pcsp, cursym, "pctospadj", pctospadj, nil)
 	funcpctab(ctxt, &pcln.Pcfile, cursym, "pctofile", pctofileline, pcln)
 	funcpctab(ctxt, &pcln.Pcline, cursym, "pctoline", pctofileline, nil)
+	for p := cursym.Text; p != nil; p = p.Link {
+		if p.Stmt {
+			funcpctab(ctxt, &pcln.Pcstmt, cursym, "pctostmt", pctostmt, nil)
+			break
+		}
+	}
 
 	// tabulate which pc and func data we have.
 	havepc := make([]uint32, (npcdata+31)/32)
@@ -276,3 +294,51 @@ func linkpcln(ctxt *Link, cursym *LSym) {
 		}
 	}
 }
+
+// scopepcs computes the PC ranges of cursym's lexical scopes.
+// Each instruction belongs to the innermost scope whose lines
+// contain it, or to the function scope if there is none.
+func scopepcs(cursym *LSym) {
+	scopes := cursym.Scopes
+	if len(scopes) < 2 {
+		return
+	}
+	depth := make([]int, len(scopes))
+	for i := 1; i < len(scopes); i++ {
+		depth[i] = depth[scopes[i].Parent] + 1
+	}
+
+	line, scope := int32(-1), int32(0)
+	for p := cursym.Text; p != nil; p = p.Link {
+		end := cursym.Size
+		if p.Link != nil {
+			end = p.Link.Pc
+		}
+		if end <= p.Pc {
+			continue
+		}
+		if p.Lineno != line {
+			// Blocks sharing a line, such as a case clause ending
+			// where the next one starts, go to the deepest and then
+			// the last to start.
+			line, scope = p.Lineno, 0
+			for i := 1; i < len(scopes); i++ {
+				s := &scopes[i]
+				if s.Start > line || line > s.End {
+					continue
+				}
+				if depth[i] > depth[scope] || depth[i] == depth[scope] && s.Start >= scopes[scope].Start {
+					scope = int32(i)
+				}
+			}
+		}
+		for s := scope; s != 0; s = scopes[s].Parent {
+			r := scopes[s].Ranges
+			if n := len(r); n > 0 && r[n-1].End == p.Pc {
+				r[n-1].End = end
+			} else {
+				scopes[s].Ranges = append(r, ScopeRange{p.Pc, end})
+			}
+		}
+	}
+}
@@ -1728,7 +1728,7 @@ func asmout(ctxt *obj.Link, p *obj.Prog, o *Optab, out []uint32) {
 			}
 
 			if v < -(1<<25) || v >= 1<<24 {
-				ctxt.Diag("branch too far\n%v", p)
+				ctxt.Diag("branch too far in %s\n%v", ctxt.Cursym.Name, p)
 			}
 		}
 
@@ -1834,7 +1834,7 @@ func asmout(ctxt *obj.Link, p *obj.Prog, o *Optab, out []uint32) {
 		}
 
 		if v < -(1<<16) || v >= 1<<15 {
-			ctxt.Diag("branch too far\n%v", p)
+			ctxt.Diag("branch too far in %s\n%v", ctxt.Cursym.Name, p)
 		}
 		o1 = OP_BC(opirr(ctxt, p.As), uint32(a), uint32(r), uint32(v), 0)
 
@@ -22,9 +22,9 @@ func TestSizeof(t *testing.T) {
 		_32bit uintptr     // size on 32bit platforms
 		_64bit uintptr     // size on 64bit platforms
 	}{
-		{Addr{}, 52, 80},
-		{LSym{}, 80, 136},
-		{Prog{}, 196, 288},
+		{Addr{}, 48, 72},
+		{LSym{}, 96, 160},
+		{Prog{}, 192, 280},
 	}
 
 	for _, tt := range tests {
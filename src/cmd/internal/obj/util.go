@@ -274,11 +274,25 @@ func (p *Prog) String() string {
 		return "<Prog without ctxt>"
 	}
 
+	return fmt.Sprintf("%.5d (%v)\t%s", p.Pc, p.Line(), p.InstructionString())
+}
+
+// InstructionString returns a string representation of the instruction
+// without the preceding program counter or file and line number.
+func (p *Prog) InstructionString() string {
+	if p == nil {
+		return "<nil Prog>"
+	}
+
+	if p.Ctxt == nil {
+		return "<Prog without ctxt>"
+	}
+
 	sc := CConv(p.Scond)
 
 	var buf bytes.Buffer
 
-	fmt.Fprintf(&buf, "%.5d (%v)\t%v%s", p.Pc, p.Line(), Aconv(p.As), sc)
+	fmt.Fprintf(&buf, "%v%s", Aconv(p.As), sc)
 	sep := "\t"
 	if p.From.Type != TYPE_NONE {
 		fmt.Fprintf(&buf, "%s%v", sep, Dconv(p, &p.From))
@@ -307,22 +321,55 @@ func (p *Prog) String() string {
 	return buf.String()
 }
 
+const (
+	progBlockSize = 1024 // Progs per allocation block
+	maxProgBlocks = 16   // blocks kept for reuse after a flush
+)
+
+// NewProg returns a new, zeroed Prog. Progs are carved out of blocks
+// that are recycled once the function they belong to has been
+// assembled, so a function's instructions cost no garbage.
 func (ctxt *Link) NewProg() *Prog {
-	var p *Prog
-	if i := ctxt.allocIdx; i < len(ctxt.progs) {
-		p = &ctxt.progs[i]
-		ctxt.allocIdx = i + 1
-	} else {
-		p = new(Prog) // should be the only call to this; all others should use ctxt.NewProg
+	if ctxt.allocIdx == len(ctxt.progs) {
+		if ctxt.nblocks == len(ctxt.progBlocks) {
+			// should be the only allocation of Progs; all others should use ctxt.NewProg
+			ctxt.progBlocks = append(ctxt.progBlocks, make([]Prog, progBlockSize))
+		}
+		ctxt.progs = ctxt.progBlocks[ctxt.nblocks]
+		ctxt.nblocks++
+		ctxt.allocIdx = 0
 	}
+	p := &ctxt.progs[ctxt.allocIdx]
+	ctxt.allocIdx++
 	p.Ctxt = ctxt
 	return p
 }
+
+// freeProgs makes all Progs handed out by NewProg available again.
+// The caller must no longer refer to any of them.
+// Blocks beyond the first maxProgBlocks are dropped, so that one
+// huge function does not pin its instructions for the rest of the
+// compilation.
 func (ctxt *Link) freeProgs() {
-	s := ctxt.progs[:ctxt.allocIdx]
-	for i := range s {
-		s[i] = Prog{}
+	if ctxt.nblocks > maxProgBlocks {
+		for i := maxProgBlocks; i < len(ctxt.progBlocks); i++ {
+			ctxt.progBlocks[i] = nil
+		}
+		ctxt.progBlocks = ctxt.progBlocks[:maxProgBlocks]
+		ctxt.nblocks = maxProgBlocks
+		ctxt.allocIdx = progBlockSize
+	}
+	for i := 0; i < ctxt.nblocks; i++ {
+		s := ctxt.progBlocks[i]
+		if i == ctxt.nblocks-1 {
+			s = s[:ctxt.allocIdx]
+		}
+		for j := range s {
+			s[j] = Prog{}
+		}
 	}
+	ctxt.nblocks = 0
+	ctxt.progs = nil
 	ctxt.allocIdx = 0
 }
 
@@ -385,8 +432,10 @@ func Dconv(p *Prog, a *Addr) string {
 		if a.Index != REG_NONE {
 			str += fmt.Sprintf("(%v*%d)", Rconv(int(a.Index)), int(a.Scale))
 		}
-		if p != nil && p.As == ATYPE && a.Gotype != nil {
-			str += fmt.Sprintf("%s", a.Gotype.Name)
+		if p != nil && p.As == ATYPE {
+			if gotype, ok := a.Val.(*LSym); ok {
+				str += fmt.Sprintf("%s", gotype.Name)
+			}
 		}
 
 	case TYPE_CONST:
@@ -41,6 +41,8 @@ Flags:
 		Set the value of the string variable in importpath named name to value.
 		Note that before Go 1.5 this option took two separate arguments.
 		Now it takes one argument split on the first = sign.
+	-asan
+		Link with C/C++ address sanitizer support.
 	-buildmode mode
 		Set build mode (default exe).
 	-cpuprofile file
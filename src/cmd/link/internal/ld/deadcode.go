@@ -7,6 +7,7 @@ package ld
 import (
 	"cmd/internal/obj"
 	"fmt"
+	"strconv"
 	"strings"
 	"unicode"
 )
@@ -40,7 +41,22 @@ import (
 //	- reflect.Value.Method is reachable
 // 	- reflect.Type.Method or MethodByName is called.
 // If any of these happen, all bets are off and all exported methods
-// of reachable types are marked reachable.
+// of reachable types are marked reachable. The exception is a call
+// to reflect.Type.Method or MethodByName with a constant argument:
+// the compiler records the name or index in a go.reflectmethod.*
+// symbol referenced by the caller, and only methods with that name,
+// or exported methods at that index, are marked reachable.
+//
+// The compiler moves side-effect-free initializers of package-level
+// variables into functions of their own, referred to from the package
+// init function only through R_INITVAR relocations. The function for
+// variable V is reached through its function value V·init·f, and is
+// marked reachable only once V is. Otherwise the relocation is zeroed
+// and the init function skips the initializer.
+//
+// The declarations the compiler emits for DWARF with -dwarfdecls,
+// in go.dwarfdecls.* symbols, and the source files it records with
+// -embedsrc, in go.srcfile.* symbols, are kept unless DWARF is disabled.
 //
 // Any unreached text symbols are removed from ctxt.Textp.
 func deadcode(ctxt *Link) {
@@ -49,8 +65,9 @@ func deadcode(ctxt *Link) {
 	}
 
 	d := &deadcodepass{
-		ctxt:        ctxt,
-		ifaceMethod: make(map[methodsig]bool),
+		ctxt:          ctxt,
+		ifaceMethod:   make(map[methodsig]bool),
+		reflectMethod: make(map[string]bool),
 	}
 
 	// First, flood fill any symbols directly reachable in the call
@@ -70,7 +87,7 @@ func deadcode(ctxt *Link) {
 
 	for {
 		if !reflectSeen {
-			if d.reflectMethod || (callSym != nil && callSym.Attr.Reachable()) || (methSym != nil && methSym.Attr.Reachable()) {
+			if d.reflectMethodAny || (callSym != nil && callSym.Attr.Reachable()) || (methSym != nil && methSym.Attr.Reachable()) {
 				// Methods might be called via reflection. Give up on
 				// static analysis, mark all exported methods of
 				// all reachable types as reachable.
@@ -84,7 +101,7 @@ func deadcode(ctxt *Link) {
 		// in the last pass.
 		var rem []methodref
 		for _, m := range d.markableMethods {
-			if (reflectSeen && m.isExported()) || d.ifaceMethod[m.m] {
+			if (reflectSeen && m.isExported()) || d.ifaceMethod[m.m] || d.reflectMethodUsed(m) {
 				d.markMethod(m)
 			} else {
 				rem = append(rem, m)
@@ -92,6 +109,18 @@ func deadcode(ctxt *Link) {
 		}
 		d.markableMethods = rem
 
+		// Mark the initializers of reached variables.
+		var remvars []initvarref
+		for _, v := range d.markableInitvars {
+			if v.v.Attr.Reachable() {
+				d.mark(v.r.Sym, v.src)
+				v.r.Type = obj.R_ADDR
+			} else {
+				remvars = append(remvars, v)
+			}
+		}
+		d.markableInitvars = remvars
+
 		if len(d.markQueue) == 0 {
 			// No new work was discovered. Done.
 			break
@@ -106,6 +135,11 @@ func deadcode(ctxt *Link) {
 		}
 	}
 
+	// Remove the initializers of unreached variables.
+	for _, v := range d.markableInitvars {
+		d.cleanupReloc(v.r)
+	}
+
 	if Buildmode != BuildmodeShared {
 		// Keep a typelink if the symbol it points at is being kept.
 		// (When BuildmodeShared, always keep typelinks.)
@@ -164,10 +198,19 @@ var markextra = []string{
 // the reflect.method struct: mtyp, ifn, and tfn.
 type methodref struct {
 	m   methodsig
+	idx int       // index in the receiver's method table
 	src *LSym     // receiver type symbol
 	r   [3]*Reloc // R_METHOD relocations to fields of runtime.method
 }
 
+// initvarref holds an R_INITVAR relocation from a package's table of
+// variable initializers to the initializer of variable v.
+type initvarref struct {
+	src *LSym  // table of initializers
+	r   *Reloc // R_INITVAR relocation
+	v   *LSym  // initialized variable
+}
+
 func (m methodref) mtyp() *LSym { return m.r[0].Sym }
 func (m methodref) ifn() *LSym  { return m.r[1].Sym }
 func (m methodref) tfn() *LSym  { return m.r[2].Sym }
@@ -179,13 +222,34 @@ func (m methodref) isExported() bool {
 	panic("methodref has no signature")
 }
 
+func (m methodref) name() string {
+	s := string(m.m)
+	return s[:strings.Index(s, "(")]
+}
+
 // deadcodepass holds state for the deadcode flood fill.
 type deadcodepass struct {
 	ctxt            *Link
 	markQueue       []*LSym            // symbols to flood fill in next pass
 	ifaceMethod     map[methodsig]bool // methods declared in reached interfaces
 	markableMethods []methodref        // methods of reached types
-	reflectMethod   bool
+
+	// markableInitvars holds the R_INITVAR relocations to the
+	// initializers of variables not yet reached.
+	markableInitvars []initvarref
+
+	// reflectMethodAny is set when a reached function calls
+	// reflect.Type.Method or MethodByName with a non-constant
+	// argument. Otherwise reflectMethod holds the constant
+	// names and indexes passed to them.
+	reflectMethodAny bool
+	reflectMethod    map[string]bool
+}
+
+// reflectMethodUsed reports whether m may be reached through a call
+// to reflect.Type.Method or MethodByName with a constant argument.
+func (d *deadcodepass) reflectMethodUsed(m methodref) bool {
+	return d.reflectMethod[m.name()] || m.isExported() && d.reflectMethod[strconv.Itoa(m.idx)]
 }
 
 func (d *deadcodepass) cleanupReloc(r *Reloc) {
@@ -193,7 +257,7 @@ func (d *deadcodepass) cleanupReloc(r *Reloc) {
 		r.Type = obj.R_ADDR
 	} else {
 		if Debug['v'] > 1 {
-			fmt.Fprintf(d.ctxt.Bso, "removing method %s\n", r.Sym.Name)
+			fmt.Fprintf(d.ctxt.Bso, "removing %s\n", r.Sym.Name)
 		}
 		r.Sym = nil
 		r.Siz = 0
@@ -206,11 +270,23 @@ func (d *deadcodepass) mark(s, parent *LSym) {
 		return
 	}
 	if s.Attr.ReflectMethod() {
-		d.reflectMethod = true
+		d.reflectMethodAny = true
+	}
+	if strings.HasPrefix(s.Name, "go.reflectmethod.") {
+		d.reflectMethod[s.Name[len("go.reflectmethod."):]] = true
+		s.Type = obj.SCONST
+		s.Attr |= AttrSpecial | AttrHidden
 	}
 	s.Attr |= AttrReachable
 	s.Reachparent = parent
 	d.markQueue = append(d.markQueue, s)
+
+	// A package compiled with -cover describes its counters
+	// in a block table that nothing refers to.
+	if strings.HasSuffix(s.Name, ".cover·counters") {
+		prefix := s.Name[:len(s.Name)-len("counters")]
+		d.mark(Linkrlookup(d.ctxt, prefix+"blocks", 0), s)
+	}
 }
 
 // markMethod marks a method as reachable.
@@ -255,6 +331,16 @@ func (d *deadcodepass) init() {
 		for _, s := range dynexp {
 			d.mark(s, nil)
 		}
+		if Debug['w'] == 0 {
+			// Keep the declarations the compiler emitted for DWARF
+			// with -dwarfdecls, along with the types they refer to,
+			// and the source files it recorded with -embedsrc.
+			for _, s := range d.ctxt.Allsym {
+				if strings.HasPrefix(s.Name, "go.dwarfdecls.") || strings.HasPrefix(s.Name, "go.srcfile.") {
+					d.mark(s, nil)
+				}
+			}
+		}
 	}
 
 	for _, name := range names {
@@ -295,6 +381,17 @@ func (d *deadcodepass) flood() {
 			if r.Sym == nil {
 				continue
 			}
+			if r.Type == obj.R_INITVAR {
+				v := Linkrlookup(d.ctxt, strings.TrimSuffix(r.Sym.Name, "·init·f"), 0)
+				if v == nil || DynlinkingGo() {
+					// Other modules may refer to the variable.
+					d.mark(r.Sym, s)
+					r.Type = obj.R_ADDR
+				} else {
+					d.markableInitvars = append(d.markableInitvars, initvarref{src: s, r: r, v: v})
+				}
+				continue
+			}
 			if r.Type != obj.R_METHOD {
 				d.mark(r.Sym, s)
 				continue
@@ -322,12 +419,12 @@ func (d *deadcodepass) flood() {
 				panic(fmt.Sprintf("%q has %d method relocations for %d methods", s.Name, len(methods), len(methodsigs)))
 			}
 			for i, m := range methodsigs {
-				name := string(m)
-				name = name[:strings.Index(name, "(")]
+				methods[i].m = m
+				methods[i].idx = i
+				name := methods[i].name()
 				if !strings.HasSuffix(methods[i].ifn().Name, name) {
 					panic(fmt.Sprintf("%q relocation for %q does not match method %q", s.Name, methods[i].ifn().Name, name))
 				}
-				methods[i].m = m
 			}
 			d.markableMethods = append(d.markableMethods, methods...)
 		}
@@ -171,6 +171,10 @@ const (
 	DW_AT_go_key  = 0x2901
 	DW_AT_go_elem = 0x2902
 
+	// DW_AT_go_runtime_type is the address of the type's
+	// runtime._type, or 0 if the type has none in the binary.
+	DW_AT_go_runtime_type = 0x2903
+
 	DW_AT_internal_location = 253 // params and locals; not emitted
 )
 
@@ -182,8 +186,11 @@ const (
 	DW_ABRV_COMPUNIT
 	DW_ABRV_FUNCTION
 	DW_ABRV_VARIABLE
+	DW_ABRV_CONSTANT
+	DW_ABRV_CONSTANT_UNSIGNED
 	DW_ABRV_AUTO
 	DW_ABRV_PARAM
+	DW_ABRV_LEXICAL_BLOCK
 	DW_ABRV_STRUCTFIELD
 	DW_ABRV_FUNCTYPEPARAM
 	DW_ABRV_DOTDOTDOT
@@ -252,6 +259,28 @@ var abbrevs = [DW_NABRV]DWAbbrev{
 		},
 	},
 
+	/* CONSTANT */
+	{
+		DW_TAG_constant,
+		DW_CHILDREN_no,
+		[]DWAttrForm{
+			{DW_AT_name, DW_FORM_string},
+			{DW_AT_type, DW_FORM_ref_addr},
+			{DW_AT_const_value, DW_FORM_sdata},
+		},
+	},
+
+	/* CONSTANT_UNSIGNED */
+	{
+		DW_TAG_constant,
+		DW_CHILDREN_no,
+		[]DWAttrForm{
+			{DW_AT_name, DW_FORM_string},
+			{DW_AT_type, DW_FORM_ref_addr},
+			{DW_AT_const_value, DW_FORM_udata},
+		},
+	},
+
 	/* AUTO */
 	{
 		DW_TAG_variable,
@@ -274,6 +303,16 @@ var abbrevs = [DW_NABRV]DWAbbrev{
 		},
 	},
 
+	/* LEXICAL_BLOCK */
+	{
+		DW_TAG_lexical_block,
+		DW_CHILDREN_yes,
+		[]DWAttrForm{
+			{DW_AT_low_pc, DW_FORM_addr},
+			{DW_AT_high_pc, DW_FORM_addr},
+		},
+	},
+
 	/* STRUCTFIELD */
 	{
 		DW_TAG_member,
@@ -334,6 +373,7 @@ var abbrevs = [DW_NABRV]DWAbbrev{
 			{DW_AT_encoding, DW_FORM_data1},
 			{DW_AT_byte_size, DW_FORM_data1},
 			{DW_AT_go_kind, DW_FORM_data1},
+			{DW_AT_go_runtime_type, DW_FORM_addr},
 		},
 	},
 
@@ -347,6 +387,7 @@ var abbrevs = [DW_NABRV]DWAbbrev{
 			{DW_AT_type, DW_FORM_ref_addr},
 			{DW_AT_byte_size, DW_FORM_udata},
 			{DW_AT_go_kind, DW_FORM_data1},
+			{DW_AT_go_runtime_type, DW_FORM_addr},
 		},
 	},
 
@@ -359,6 +400,7 @@ var abbrevs = [DW_NABRV]DWAbbrev{
 			{DW_AT_type, DW_FORM_ref_addr},
 			{DW_AT_go_kind, DW_FORM_data1},
 			{DW_AT_go_elem, DW_FORM_ref_addr},
+			{DW_AT_go_runtime_type, DW_FORM_addr},
 		},
 	},
 
@@ -370,6 +412,7 @@ var abbrevs = [DW_NABRV]DWAbbrev{
 			{DW_AT_name, DW_FORM_string},
 			// {DW_AT_type,	DW_FORM_ref_addr},
 			{DW_AT_go_kind, DW_FORM_data1},
+			{DW_AT_go_runtime_type, DW_FORM_addr},
 		},
 	},
 
@@ -381,6 +424,7 @@ var abbrevs = [DW_NABRV]DWAbbrev{
 			{DW_AT_name, DW_FORM_string},
 			{DW_AT_type, DW_FORM_ref_addr},
 			{DW_AT_go_kind, DW_FORM_data1},
+			{DW_AT_go_runtime_type, DW_FORM_addr},
 		},
 	},
 
@@ -394,6 +438,7 @@ var abbrevs = [DW_NABRV]DWAbbrev{
 			{DW_AT_go_kind, DW_FORM_data1},
 			{DW_AT_go_key, DW_FORM_ref_addr},
 			{DW_AT_go_elem, DW_FORM_ref_addr},
+			{DW_AT_go_runtime_type, DW_FORM_addr},
 		},
 	},
 
@@ -405,6 +450,7 @@ var abbrevs = [DW_NABRV]DWAbbrev{
 			{DW_AT_name, DW_FORM_string},
 			{DW_AT_type, DW_FORM_ref_addr},
 			{DW_AT_go_kind, DW_FORM_data1},
+			{DW_AT_go_runtime_type, DW_FORM_addr},
 		},
 	},
 
@@ -414,6 +460,8 @@ var abbrevs = [DW_NABRV]DWAbbrev{
 		DW_CHILDREN_no,
 		[]DWAttrForm{
 			{DW_AT_name, DW_FORM_string},
+			{DW_AT_go_kind, DW_FORM_data1},
+			{DW_AT_go_runtime_type, DW_FORM_addr},
 		},
 	},
 
@@ -426,6 +474,7 @@ var abbrevs = [DW_NABRV]DWAbbrev{
 			{DW_AT_byte_size, DW_FORM_udata},
 			{DW_AT_go_kind, DW_FORM_data1},
 			{DW_AT_go_elem, DW_FORM_ref_addr},
+			{DW_AT_go_runtime_type, DW_FORM_addr},
 		},
 	},
 
@@ -437,6 +486,7 @@ var abbrevs = [DW_NABRV]DWAbbrev{
 			{DW_AT_name, DW_FORM_string},
 			{DW_AT_byte_size, DW_FORM_udata},
 			{DW_AT_go_kind, DW_FORM_data1},
+			{DW_AT_go_runtime_type, DW_FORM_addr},
 		},
 	},
 
@@ -448,6 +498,7 @@ var abbrevs = [DW_NABRV]DWAbbrev{
 			{DW_AT_name, DW_FORM_string},
 			{DW_AT_byte_size, DW_FORM_udata},
 			{DW_AT_go_kind, DW_FORM_data1},
+			{DW_AT_go_runtime_type, DW_FORM_addr},
 		},
 	},
 
@@ -657,7 +708,7 @@ var fwdcount int
 func putattr(abbrev int, form int, cls int, value int64, data interface{}) {
 	switch form {
 	case DW_FORM_addr: // address
-		if Linkmode == LinkExternal {
+		if Linkmode == LinkExternal && data != nil {
 			value -= (data.(*LSym)).Value
 			adddwarfrel(infosec, data.(*LSym), infoo, Thearch.Ptrsize, value)
 			break
@@ -845,6 +896,15 @@ func newmemberoffsetattr(die *DWDie, offs int32) {
 	newattr(die, DW_AT_data_member_location, DW_CLS_BLOCK, int64(len(b)), b)
 }
 
+// newruntimetypeattr records the address of gotype, the runtime
+// type descriptor of die, if gotype made it into the binary.
+func newruntimetypeattr(die *DWDie, gotype *LSym) {
+	if gotype == nil || !gotype.Attr.Reachable() {
+		return
+	}
+	newattr(die, DW_AT_go_runtime_type, DW_CLS_ADDRESS, Symaddr(gotype), gotype)
+}
+
 // GDB doesn't like DW_FORM_addr for DW_AT_location, so emit a
 // location expression that evals to a const.
 func newabslocexprattr(die *DWDie, addr int64, sym *LSym) {
@@ -1060,6 +1120,7 @@ func defgotype(gotype *LSym) *DWDie {
 	}
 
 	newattr(die, DW_AT_go_kind, DW_CLS_CONSTANT, int64(kind), 0)
+	newruntimetypeattr(die, gotype)
 
 	return die
 }
@@ -1329,6 +1390,34 @@ func defdwsymb(sym *LSym, s string, t int, v int64, size int64, ver int, gotype
 		return
 	}
 
+	// Symbols emitted by the compiler's -dwarfdecls flag.
+	// go.dwarfdecls.pkg. refers to the types and constants of pkg
+	// to describe; type symbols are not visited on their own, so
+	// its types are defined here. go.dwarfdecls.pkg.C holds the
+	// value of the constant C.
+	if strings.HasPrefix(s, "go.dwarfdecls.") {
+		if strings.HasSuffix(s, ".") {
+			for _, r := range sym.R {
+				if strings.HasPrefix(r.Sym.Name, "type.") {
+					defgotype(r.Sym)
+				}
+			}
+		} else if len(sym.P) == 8 {
+			// Values of unsigned types may not fit in an int64.
+			abbrev := DW_ABRV_CONSTANT
+			if gotype != nil {
+				switch decodetype_kind(gotype) {
+				case obj.KindUint, obj.KindUint8, obj.KindUint16, obj.KindUint32, obj.KindUint64, obj.KindUintptr:
+					abbrev = DW_ABRV_CONSTANT_UNSIGNED
+				}
+			}
+			dc := newdie(&dwglobals, abbrev, strings.TrimPrefix(s, "go.dwarfdecls."))
+			newrefattr(dc, DW_AT_type, defgotype(gotype))
+			newattr(dc, DW_AT_const_value, DW_CLS_CONSTANT, int64(decode_inuxi(sym.P, 8)), nil)
+		}
+		return
+	}
+
 	var dv *DWDie
 
 	var dt *DWDie
@@ -1424,6 +1513,66 @@ func mkvarname(name string, da int) string {
 	return n
 }
 
+// scopedies creates the DW_TAG_lexical_block entries for the
+// lexical scopes of s and returns, for each scope, the DIEs that
+// hold its variables: the function itself for scope 0, and a block
+// per PC range for the others. A scope that covers more than one
+// range has its variables repeated in each block. A scope with no
+// code or no variables left shares the DIEs of its parent.
+func scopedies(dwfunc *DWDie, s *LSym) [][]*DWDie {
+	if len(s.Scopes) == 0 {
+		return nil
+	}
+	for i := 1; i < len(s.Scopes); i++ {
+		if p := s.Scopes[i].Parent; p < 0 || int(p) >= i {
+			Diag("%s: bad lexical scope parent %d", s.Name, p)
+			return nil
+		}
+	}
+
+	// Find the scopes that hold variables, directly or in
+	// nested scopes. Parents precede the scopes nested in them.
+	used := make([]bool, len(s.Scopes))
+	for _, a := range s.Autom {
+		if int(a.Scope) < len(used) && !strings.Contains(a.Asym.Name, ".autotmp_") {
+			used[a.Scope] = true
+		}
+	}
+	for i := len(s.Scopes) - 1; i > 0; i-- {
+		if used[i] {
+			used[s.Scopes[i].Parent] = true
+		}
+	}
+
+	dies := make([][]*DWDie, len(s.Scopes))
+	ranges := make([][]ScopeRange, len(s.Scopes))
+	dies[0] = []*DWDie{dwfunc}
+	ranges[0] = []ScopeRange{{0, s.Size}}
+	for i := 1; i < len(s.Scopes); i++ {
+		sc := &s.Scopes[i]
+		if !used[i] || len(sc.Ranges) == 0 {
+			dies[i], ranges[i] = dies[sc.Parent], ranges[sc.Parent]
+			continue
+		}
+		for _, r := range sc.Ranges {
+			// Nest the block in the parent's range that contains it.
+			parent := dies[sc.Parent][0]
+			for j, pr := range ranges[sc.Parent] {
+				if pr.Start <= r.Start && r.End <= pr.End {
+					parent = dies[sc.Parent][j]
+					break
+				}
+			}
+			die := newdie(parent, DW_ABRV_LEXICAL_BLOCK, "")
+			newattr(die, DW_AT_low_pc, DW_CLS_ADDRESS, s.Value+r.Start, s)
+			newattr(die, DW_AT_high_pc, DW_CLS_ADDRESS, s.Value+r.End, s)
+			dies[i] = append(dies[i], die)
+			ranges[i] = append(ranges[i], r)
+		}
+	}
+	return dies
+}
+
 /*
  * Walk prog table, emit line program and build DIE tree.
  */
@@ -1527,6 +1676,7 @@ func writelines() {
 	pc := s.Value
 	line := 1
 	file := 1
+	stmt := true // default_is_stmt
 	if Linkmode == LinkExternal {
 		adddwarfrel(linesec, s, lineo, Thearch.Ptrsize, 0)
 	} else {
@@ -1535,9 +1685,21 @@ func writelines() {
 
 	var pcfile Pciter
 	var pcline Pciter
+	var pcstmt Pciter
 	for Ctxt.Cursym = Ctxt.Textp; Ctxt.Cursym != nil; Ctxt.Cursym = Ctxt.Cursym.Next {
 		s = Ctxt.Cursym
 
+		if s.Attr.NoDWARF() {
+			// Leave the function out of the DIE tree and
+			// attribute its code to no source line.
+			epc = s.Value + s.Size
+			epcs = s
+			putpclcdelta(s.Value-pc, int64(-line))
+			pc = s.Value
+			line = 0
+			continue
+		}
+
 		dwfunc := newdie(dwinfo, DW_ABRV_FUNCTION, s.Name)
 		newattr(dwfunc, DW_AT_low_pc, DW_CLS_ADDRESS, s.Value, s)
 		epc = s.Value + s.Size
@@ -1555,6 +1717,10 @@ func writelines() {
 
 		pciterinit(Ctxt, &pcfile, &s.Pcln.Pcfile)
 		pciterinit(Ctxt, &pcline, &s.Pcln.Pcline)
+		pciterinit(Ctxt, &pcstmt, &s.Pcln.Pcstmt)
+		// Without a table of statement starts, as in assembly
+		// functions, every line change starts a statement.
+		hasstmt := pcstmt.done == 0
 		epc = pc
 		for pcfile.done == 0 && pcline.done == 0 {
 			if epc-s.Value >= int64(pcfile.nextpc) {
@@ -1567,24 +1733,50 @@ func writelines() {
 				continue
 			}
 
+			if hasstmt && pcstmt.done == 0 && epc-s.Value >= int64(pcstmt.nextpc) {
+				pciternext(&pcstmt)
+				continue
+			}
+
 			if int32(file) != pcfile.value {
 				Cput(DW_LNS_set_file)
 				uleb128put(int64(pcfile.value))
 				file = int(pcfile.value)
 			}
 
-			putpclcdelta(s.Value+int64(pcline.pc)-pc, int64(pcline.value)-int64(line))
+			// Emit a row where the file, line or statement
+			// flag last changed, lasting until the next change.
+			rowpc, nextpc := pcline.pc, pcline.nextpc
+			if pcfile.pc > rowpc {
+				rowpc = pcfile.pc
+			}
+			if pcfile.nextpc < nextpc {
+				nextpc = pcfile.nextpc
+			}
+			isstmt := true
+			if hasstmt && pcstmt.done == 0 {
+				isstmt = pcstmt.value != 0
+				if pcstmt.pc > rowpc {
+					rowpc = pcstmt.pc
+				}
+				if pcstmt.nextpc < nextpc {
+					nextpc = pcstmt.nextpc
+				}
+			}
+			if isstmt != stmt {
+				Cput(DW_LNS_negate_stmt)
+				stmt = isstmt
+			}
 
-			pc = s.Value + int64(pcline.pc)
+			putpclcdelta(s.Value+int64(rowpc)-pc, int64(pcline.value)-int64(line))
+
+			pc = s.Value + int64(rowpc)
 			line = int(pcline.value)
-			if pcfile.nextpc < pcline.nextpc {
-				epc = int64(pcfile.nextpc)
-			} else {
-				epc = int64(pcline.nextpc)
-			}
-			epc += s.Value
+			epc = s.Value + int64(nextpc)
 		}
 
+		scopes := scopedies(dwfunc, s)
+
 		var (
 			dt, da int
 			offs   int64
@@ -1609,34 +1801,40 @@ func writelines() {
 			if strings.Contains(a.Asym.Name, ".autotmp_") {
 				continue
 			}
-			var n string
-			if find(dwfunc, a.Asym.Name) != nil {
-				n = mkvarname(a.Asym.Name, da)
-			} else {
-				n = a.Asym.Name
+			parents := []*DWDie{dwfunc}
+			if int(a.Scope) < len(scopes) {
+				parents = scopes[a.Scope]
 			}
+			for _, parent := range parents {
+				var n string
+				if find(parent, a.Asym.Name) != nil {
+					n = mkvarname(a.Asym.Name, da)
+				} else {
+					n = a.Asym.Name
+				}
 
-			// Drop the package prefix from locals and arguments.
-			if i := strings.LastIndex(n, "."); i >= 0 {
-				n = n[i+1:]
-			}
+				// Drop the package prefix from locals and arguments.
+				if i := strings.LastIndex(n, "."); i >= 0 {
+					n = n[i+1:]
+				}
 
-			dwvar := newdie(dwfunc, dt, n)
-			newcfaoffsetattr(dwvar, int32(offs))
-			newrefattr(dwvar, DW_AT_type, defgotype(a.Gotype))
+				dwvar := newdie(parent, dt, n)
+				newcfaoffsetattr(dwvar, int32(offs))
+				newrefattr(dwvar, DW_AT_type, defgotype(a.Gotype))
 
-			// push dwvar down dwfunc->child to preserve order
-			newattr(dwvar, DW_AT_internal_location, DW_CLS_CONSTANT, offs, nil)
+				// push dwvar down parent->child to preserve order
+				newattr(dwvar, DW_AT_internal_location, DW_CLS_CONSTANT, offs, nil)
 
-			dwfunc.child = dwvar.link // take dwvar out from the top of the list
-			dws := &dwfunc.child
-			for ; *dws != nil; dws = &(*dws).link {
-				if offs > getattr(*dws, DW_AT_internal_location).value {
-					break
+				parent.child = dwvar.link // take dwvar out from the top of the list
+				dws := &parent.child
+				for ; *dws != nil; dws = &(*dws).link {
+					if loc := getattr(*dws, DW_AT_internal_location); loc != nil && offs > loc.value {
+						break
+					}
 				}
+				dwvar.link = *dws
+				*dws = dwvar
 			}
-			dwvar.link = *dws
-			*dws = dwvar
 
 			da++
 		}
@@ -2021,12 +2219,15 @@ func Dwarfemitdebugsections() {
 	newdie(&dwtypes, DW_ABRV_NULLTYPE, "<unspecified>")
 
 	newdie(&dwtypes, DW_ABRV_NULLTYPE, "void")
-	newdie(&dwtypes, DW_ABRV_BARE_PTRTYPE, "unsafe.Pointer")
+	die := newdie(&dwtypes, DW_ABRV_BARE_PTRTYPE, "unsafe.Pointer")
+	newattr(die, DW_AT_go_kind, DW_CLS_CONSTANT, obj.KindUnsafePointer, 0)
+	newruntimetypeattr(die, Linkrlookup(Ctxt, "type.unsafe.Pointer", 0))
 
-	die := newdie(&dwtypes, DW_ABRV_BASETYPE, "uintptr") // needed for array size
+	die = newdie(&dwtypes, DW_ABRV_BASETYPE, "uintptr") // needed for array size
 	newattr(die, DW_AT_encoding, DW_CLS_CONSTANT, DW_ATE_unsigned, 0)
 	newattr(die, DW_AT_byte_size, DW_CLS_CONSTANT, int64(Thearch.Ptrsize), 0)
 	newattr(die, DW_AT_go_kind, DW_CLS_CONSTANT, obj.KindUintptr, 0)
+	newruntimetypeattr(die, Linkrlookup(Ctxt, "type.uintptr", 0))
 
 	// Needed by the prettyprinter code for interface inspection.
 	defgotype(lookup_or_diag("type.runtime._type"))
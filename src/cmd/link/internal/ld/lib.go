@@ -204,6 +204,7 @@ var (
 	flag_installsuffix string
 	flag_race          int
 	flag_msan          int
+	flag_asan          int
 	Buildmode          BuildMode
 	Linkshared         bool
 	tracksym           string
@@ -399,6 +400,9 @@ func libinit() {
 	} else if flag_msan != 0 {
 		suffixsep = "_"
 		suffix = "msan"
+	} else if flag_asan != 0 {
+		suffixsep = "_"
+		suffix = "asan"
 	}
 
 	Lflag(fmt.Sprintf("%s/pkg/%s_%s%s%s", goroot, goos, goarch, suffixsep, suffix))
@@ -512,6 +516,9 @@ func loadlib() {
 	if flag_msan != 0 {
 		loadinternal("runtime/msan")
 	}
+	if flag_asan != 0 {
+		loadinternal("runtime/asan")
+	}
 
 	var i int
 	for i = 0; i < len(Ctxt.Library); i++ {
@@ -560,8 +567,8 @@ func loadlib() {
 			Linkmode = LinkExternal
 		}
 
-		// Force external linking for msan.
-		if flag_msan != 0 {
+		// Force external linking for msan and asan.
+		if flag_msan != 0 || flag_asan != 0 {
 			Linkmode = LinkExternal
 		}
 	}
@@ -870,6 +877,7 @@ var internalpkg = []string{
 	"runtime/cgo",
 	"runtime/race",
 	"runtime/msan",
+	"runtime/asan",
 }
 
 func ldhostobj(ld func(*obj.Biobuf, string, int64, string), f *obj.Biobuf, pkg string, length int64, pn string, file string) *Hostobj {
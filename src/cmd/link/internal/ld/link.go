@@ -68,6 +68,7 @@ type LSym struct {
 	Dynimpvers  string
 	Sect        *Section
 	Autom       []Auto
+	Scopes      []Scope
 	Pcln        *Pcln
 	P           []byte
 	R           []Reloc
@@ -106,6 +107,7 @@ const (
 	AttrOnList
 	AttrLocal
 	AttrReflectMethod
+	AttrNoDWARF
 )
 
 func (a Attribute) DuplicateOK() bool      { return a&AttrDuplicateOK != 0 }
@@ -120,6 +122,7 @@ func (a Attribute) Hidden() bool           { return a&AttrHidden != 0 }
 func (a Attribute) OnList() bool           { return a&AttrOnList != 0 }
 func (a Attribute) Local() bool            { return a&AttrLocal != 0 }
 func (a Attribute) ReflectMethod() bool    { return a&AttrReflectMethod != 0 }
+func (a Attribute) NoDWARF() bool          { return a&AttrNoDWARF != 0 }
 
 func (a Attribute) CgoExport() bool {
 	return a.CgoExportDynamic() || a.CgoExportStatic()
@@ -150,6 +153,18 @@ type Auto struct {
 	Gotype  *LSym
 	Aoffset int32
 	Name    int16
+	Scope   int32 // index into LSym.Scopes
+}
+
+// A Scope is a lexical block of a function. Scope 0 is the function
+// itself; the others are nested in a Parent that precedes them.
+type Scope struct {
+	Parent int32
+	Ranges []ScopeRange // PCs relative to the function
+}
+
+type ScopeRange struct {
+	Start, End int64
 }
 
 type Shlib struct {
@@ -234,6 +249,7 @@ type Pcln struct {
 	Pcsp        Pcdata
 	Pcfile      Pcdata
 	Pcline      Pcdata
+	Pcstmt      Pcdata
 	Pcdata      []Pcdata
 	Funcdata    []*LSym
 	Funcdataoff []int64
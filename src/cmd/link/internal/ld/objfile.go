@@ -17,7 +17,7 @@ package ld
 //
 // The file format is:
 //
-//	- magic header: "\x00\x00go13ld"
+//	- magic header: "\x00\x00go17ld"
 //	- byte 1 - version number
 //	- sequence of strings giving dependencies (imported packages)
 //	- empty string (marks end of sequence)
@@ -27,7 +27,27 @@ package ld
 //	- data, the content of the defined symbols
 //	- sequence of defined symbols
 //	- byte 0xff (marks end of sequence)
-//	- magic footer: "\xff\xffgo13ld"
+//	- magic footer: "\xff\xffgo17ld"
+//
+// In version 2, written by the compiler with -objindex, a symbol index
+// precedes the defined symbols:
+//
+//	- nsym [int]
+//	- nsym index entries
+//	- integer (length of the sequence of defined symbols)
+//
+// Each index entry describes the corresponding defined symbol:
+//
+//	- name & version [symref index]
+//	- type [int]
+//	- flags [int], as in the symbol
+//	- offset [int] of the symbol from the start of the sequence
+//	- offset [int] of the symbol's content in data
+//	- nr [int]
+//
+// The linker uses the index to skip duplicate definitions of DUPOK
+// symbols without decoding them, and to allocate the relocations of
+// all the symbols in the file at once.
 //
 // All integers are stored in a zigzag varint format.
 // See golang.org/s/go12symtab for a definition.
@@ -110,8 +130,8 @@ import (
 )
 
 const (
-	startmagic = "\x00\x00go13ld"
-	endmagic   = "\xff\xffgo13ld"
+	startmagic = "\x00\x00go17ld"
+	endmagic   = "\xff\xffgo17ld"
 )
 
 func ldobjfile(ctxt *Link, f *obj.Biobuf, pkg string, length int64, pn string) {
@@ -122,9 +142,9 @@ func ldobjfile(ctxt *Link, f *obj.Biobuf, pkg string, length int64, pn string) {
 	if string(buf[:]) != startmagic {
 		log.Fatalf("%s: invalid file start %x %x %x %x %x %x %x %x", pn, buf[0], buf[1], buf[2], buf[3], buf[4], buf[5], buf[6], buf[7])
 	}
-	c := obj.Bgetc(f)
-	if c != 1 {
-		log.Fatalf("%s: invalid file version number %d", pn, c)
+	version := obj.Bgetc(f)
+	if version != 1 && version != 2 {
+		log.Fatalf("%s: invalid file version number %d", pn, version)
 	}
 
 	var lib string
@@ -153,15 +173,19 @@ func ldobjfile(ctxt *Link, f *obj.Biobuf, pkg string, length int64, pn string) {
 	data := make([]byte, dataLength)
 	obj.Bread(f, data)
 
-	for {
-		c, err := f.Peek(1)
-		if err != nil {
-			log.Fatalf("%s: peeking: %v", pn, err)
-		}
-		if c[0] == 0xff {
-			break
+	if version == 2 {
+		readindexed(ctxt, f, data, pkg, pn)
+	} else {
+		for {
+			c, err := f.Peek(1)
+			if err != nil {
+				log.Fatalf("%s: peeking: %v", pn, err)
+			}
+			if c[0] == 0xff {
+				break
+			}
+			readsym(ctxt, f, &data, pkg, pn, nil)
 		}
-		readsym(ctxt, f, &data, pkg, pn)
 	}
 
 	buf = [8]uint8{}
@@ -175,9 +199,79 @@ func ldobjfile(ctxt *Link, f *obj.Biobuf, pkg string, length int64, pn string) {
 	}
 }
 
+// An indexEntry is an entry of the symbol index of an object file.
+type indexEntry struct {
+	s       *LSym
+	typ     int
+	flags   int
+	off     int64
+	dataOff int64
+	nreloc  int
+}
+
+// readindexed reads the symbol index and the defined symbols
+// of a version 2 object file.
+func readindexed(ctxt *Link, f *obj.Biobuf, data []byte, pkg string, pn string) {
+	index := make([]indexEntry, rdint(f))
+	nreloc := 0
+	for i := range index {
+		x := &index[i]
+		x.s = rdsym(ctxt, f, pkg)
+		x.typ = rdint(f)
+		x.flags = rdint(f)
+		x.off = rdint64(f)
+		x.dataOff = rdint64(f)
+		x.nreloc = rdint(f)
+		nreloc += x.nreloc
+	}
+	length := rdint64(f)
+
+	relocs := make([]Reloc, nreloc)
+	start := obj.Boffset(f)
+	for i, x := range index {
+		next := length
+		if i+1 < len(index) {
+			next = index[i+1].off
+		}
+		if off := obj.Boffset(f) - start; off != x.off {
+			log.Fatalf("%s: symbol %s at offset %d, index says %d", pn, x.s.Name, off, x.off)
+		}
+		if isdup(x) {
+			if _, err := f.Reader().Discard(int(next - x.off)); err != nil {
+				log.Fatalf("%s: skipping symbol %s: %v", pn, x.s.Name, err)
+			}
+			continue
+		}
+		buf := data[x.dataOff:]
+		readsym(ctxt, f, &buf, pkg, pn, relocs[:x.nreloc:x.nreloc])
+		relocs = relocs[x.nreloc:]
+	}
+}
+
+// isdup reports whether the symbol described by the index entry x
+// is a duplicate definition that readsym would discard. The type
+// of a discarded definition is not recorded either, unlike when
+// readsym decodes it; the definitions of DUPOK symbols are identical.
+func isdup(x indexEntry) bool {
+	s := x.s
+	if s.Type == 0 || s.Type == obj.SXREF || len(s.P) == 0 {
+		return false
+	}
+	if x.flags&1 == 0 && !s.Attr.DuplicateOK() {
+		return false // readsym reports the duplicate
+	}
+	switch x.typ {
+	case obj.SDATA, obj.SBSS, obj.SNOPTRBSS:
+		return false // may update the size
+	}
+	return true
+}
+
 var dupSym = &LSym{Name: ".dup"}
 
-func readsym(ctxt *Link, f *obj.Biobuf, buf *[]byte, pkg string, pn string) {
+// readsym reads the next symbol definition.
+// If relocs is not nil, it is used to hold the symbol's relocations.
+func readsym(ctxt *Link, f *obj.Biobuf, buf *[]byte, pkg string, pn string, relocs []Reloc) {
 	if obj.Bgetc(f) != 0xfe {
 		log.Fatalf("readsym out of sync")
 	}
@@ -242,7 +336,10 @@ overwrite:
 	}
 	s.P = data
 	if nreloc > 0 {
-		s.R = make([]Reloc, nreloc)
+		if len(relocs) != nreloc {
+			relocs = make([]Reloc, nreloc)
+		}
+		s.R = relocs
 		var r *Reloc
 		for i := 0; i < nreloc; i++ {
 			r = &s.R[i]
@@ -264,6 +361,9 @@ overwrite:
 		if flags&(1<<2) != 0 {
 			s.Attr |= AttrReflectMethod
 		}
+		if flags&(1<<3) != 0 {
+			s.Attr |= AttrNoDWARF
+		}
 		n := rdint(f)
 		s.Autom = make([]Auto, n)
 		for i := 0; i < n; i++ {
@@ -272,6 +372,17 @@ overwrite:
 				Aoffset: rdint32(f),
 				Name:    rdint16(f),
 				Gotype:  rdsym(ctxt, f, pkg),
+				Scope:   int32(rdint(f)),
+			}
+		}
+		n = rdint(f)
+		s.Scopes = make([]Scope, n)
+		for i := range s.Scopes {
+			sc := &s.Scopes[i]
+			sc.Parent = int32(rdint(f))
+			sc.Ranges = make([]ScopeRange, rdint(f))
+			for j := range sc.Ranges {
+				sc.Ranges[j] = ScopeRange{rdint64(f), rdint64(f)}
 			}
 		}
 
@@ -280,6 +391,7 @@ overwrite:
 		pc.Pcsp.P = rddata(f, buf)
 		pc.Pcfile.P = rddata(f, buf)
 		pc.Pcline.P = rddata(f, buf)
+		pc.Pcstmt.P = rddata(f, buf)
 		n = rdint(f)
 		pc.Pcdata = make([]Pcdata, n)
 		for i := 0; i < n; i++ {
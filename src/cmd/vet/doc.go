@@ -92,6 +92,14 @@ Flag: -tests
 Mistakes involving tests including functions with incorrect names or signatures
 and example tests that document identifiers not in the package.
 
+Impossible interface assertions
+
+Flag: -ifaceassert
+
+Type assertions and type switch cases from one interface type to another
+that always fail, because the two interfaces have a method with the same
+name but different types, so that no type can implement both.
+
 Methods
 
 Flag: -methods
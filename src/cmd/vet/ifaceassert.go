@@ -0,0 +1,82 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+/*
+This file contains the code to check for impossible interface-to-interface
+type assertions. An assertion x.(T) of an interface x to an interface T
+always fails if the two interfaces have a method with the same name but
+different types, as no type can implement both.
+*/
+
+package main
+
+import (
+	"go/ast"
+	"go/types"
+)
+
+func init() {
+	register("ifaceassert",
+		"check for impossible interface-to-interface type assertions",
+		checkIfaceAssert,
+		typeAssertExpr, typeSwitchStmt)
+}
+
+func checkIfaceAssert(f *File, node ast.Node) {
+	switch n := node.(type) {
+	case *ast.TypeAssertExpr:
+		if n.Type == nil {
+			return // x.(type), checked with the type switch
+		}
+		if m := f.ifaceConflict(n.X, n.Type); m != nil {
+			f.Badf(n.Pos(), "impossible type assertion: no type can implement both %v and %v (conflicting types for %v method)", f.pkg.types[n.X].Type, f.pkg.types[n.Type].Type, m.Name())
+		}
+
+	case *ast.TypeSwitchStmt:
+		var x ast.Expr
+		switch a := n.Assign.(type) {
+		case *ast.ExprStmt:
+			x = a.X.(*ast.TypeAssertExpr).X
+		case *ast.AssignStmt:
+			x = a.Rhs[0].(*ast.TypeAssertExpr).X
+		default:
+			return
+		}
+		for _, c := range n.Body.List {
+			for _, typ := range c.(*ast.CaseClause).List {
+				if m := f.ifaceConflict(x, typ); m != nil {
+					f.Badf(typ.Pos(), "impossible type switch case: %s (type %v) cannot have dynamic type %v (conflicting types for %v method)", f.gofmt(x), f.pkg.types[x].Type, f.pkg.types[typ].Type, m.Name())
+				}
+			}
+		}
+	}
+}
+
+// ifaceConflict returns a method of the interface type of x that the
+// interface type typ also has, with a different type. It returns nil
+// if there is none, or if x or typ is not of interface type.
+func (f *File) ifaceConflict(x, typ ast.Expr) *types.Func {
+	v, t := f.pkg.types[x].Type, f.pkg.types[typ].Type
+	if v == nil || t == nil {
+		return nil
+	}
+	vi, ok := v.Underlying().(*types.Interface)
+	if !ok {
+		return nil
+	}
+	ti, ok := t.Underlying().(*types.Interface)
+	if !ok {
+		return nil
+	}
+	for i := 0; i < vi.NumMethods(); i++ {
+		vm := vi.Method(i)
+		for j := 0; j < ti.NumMethods(); j++ {
+			tm := ti.Method(j)
+			if vm.Id() == tm.Id() && !types.Identical(vm.Type(), tm.Type()) {
+				return vm
+			}
+		}
+	}
+	return nil
+}
@@ -128,18 +128,20 @@ func setExit(err int) {
 
 var (
 	// Each of these vars has a corresponding case in (*File).Visit.
-	assignStmt    *ast.AssignStmt
-	binaryExpr    *ast.BinaryExpr
-	callExpr      *ast.CallExpr
-	compositeLit  *ast.CompositeLit
-	exprStmt      *ast.ExprStmt
-	field         *ast.Field
-	funcDecl      *ast.FuncDecl
-	funcLit       *ast.FuncLit
-	genDecl       *ast.GenDecl
-	interfaceType *ast.InterfaceType
-	rangeStmt     *ast.RangeStmt
-	returnStmt    *ast.ReturnStmt
+	assignStmt     *ast.AssignStmt
+	binaryExpr     *ast.BinaryExpr
+	callExpr       *ast.CallExpr
+	compositeLit   *ast.CompositeLit
+	exprStmt       *ast.ExprStmt
+	field          *ast.Field
+	funcDecl       *ast.FuncDecl
+	funcLit        *ast.FuncLit
+	genDecl        *ast.GenDecl
+	interfaceType  *ast.InterfaceType
+	rangeStmt      *ast.RangeStmt
+	returnStmt     *ast.ReturnStmt
+	typeAssertExpr *ast.TypeAssertExpr
+	typeSwitchStmt *ast.TypeSwitchStmt
 
 	// checkers is a two-level map.
 	// The outer level is keyed by a nil pointer, one of the AST vars above.
@@ -479,6 +481,10 @@ func (f *File) Visit(node ast.Node) ast.Visitor {
 		key = rangeStmt
 	case *ast.ReturnStmt:
 		key = returnStmt
+	case *ast.TypeAssertExpr:
+		key = typeAssertExpr
+	case *ast.TypeSwitchStmt:
+		key = typeSwitchStmt
 	}
 	for _, fn := range f.checkers[key] {
 		fn(f, node)
@@ -0,0 +1,55 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// This file contains tests for the ifaceassert checker.
+
+package testdata
+
+type I1 interface {
+	M(int)
+	N()
+}
+
+type I2 interface {
+	M(string)
+}
+
+type I3 interface {
+	M(int)
+	O()
+}
+
+type I4 interface {
+	I2
+	P()
+}
+
+type I5 interface {
+	M(int) bool
+}
+
+type I6 interface {
+	N()
+	M(int)
+}
+
+func IfaceAssert(i I1, e interface{}) {
+	_ = i.(I2) // ERROR "impossible type assertion: no type can implement both testdata.I1 and testdata.I2 \(conflicting types for M method\)"
+	_ = i.(I3)
+	_ = i.(I4) // ERROR "no type can implement both testdata.I1 and testdata.I4 \(conflicting types for M method\)"
+	_ = i.(I5) // ERROR "conflicting types for M method"
+	_ = i.(I6)
+	_ = e.(I2)
+	_, _ = i.(I2) // ERROR "impossible type assertion"
+
+	switch i.(type) {
+	case I2: // ERROR "impossible type switch case: i \(type testdata.I1\) cannot have dynamic type testdata.I2 \(conflicting types for M method\)"
+	case I3, I6:
+	}
+
+	switch x := i.(type) {
+	case nil, I4: // ERROR "impossible type switch case: i \(type testdata.I1\) cannot have dynamic type testdata.I4"
+		_ = x
+	}
+}
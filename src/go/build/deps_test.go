@@ -202,7 +202,7 @@ var pkgDeps = map[string][]string{
 	// Go type checking.
 	"go/constant":               {"L4", "go/token", "math/big"},
 	"go/importer":               {"L4", "go/internal/gcimporter", "go/internal/gccgoimporter", "go/types"},
-	"go/internal/gcimporter":    {"L4", "OS", "go/build", "go/constant", "go/token", "go/types", "text/scanner"},
+	"go/internal/gcimporter":    {"L4", "OS", "compress/flate", "go/build", "go/constant", "go/token", "go/types", "text/scanner"},
 	"go/internal/gccgoimporter": {"L4", "OS", "debug/elf", "go/constant", "go/token", "go/types", "text/scanner"},
 	"go/types":                  {"L4", "GOPARSER", "container/heap", "go/constant"},
 
@@ -269,9 +269,10 @@ var pkgDeps = map[string][]string{
 	// that shows up in programs that use cgo.
 	"C": {},
 
-	// Race detector/MSan uses cgo.
+	// Race detector/MSan/ASan uses cgo.
 	"runtime/race": {"C"},
 	"runtime/msan": {"C"},
+	"runtime/asan": {"C"},
 
 	// Plan 9 alone needs io/ioutil and os.
 	"os/user": {"L4", "CGO", "io/ioutil", "os", "syscall"},
@@ -5,11 +5,16 @@
 package gcimporter
 
 import (
+	"bufio"
+	"bytes"
+	"compress/flate"
 	"encoding/binary"
+	"errors"
 	"fmt"
 	"go/constant"
 	"go/token"
 	"go/types"
+	"io"
 	"sort"
 	"unicode"
 	"unicode/utf8"
@@ -20,30 +25,36 @@ import (
 // If data is obviously malformed, an error is returned but in
 // general it is not recommended to call BImportData on untrusted data.
 func BImportData(imports map[string]*types.Package, data []byte, path string) (int, *types.Package, error) {
-	p := importer{
-		imports: imports,
-		data:    data,
-	}
-	p.buf = p.bufarray[:]
+	r := bytes.NewReader(data)
+	consumed := func() int { return len(data) - r.Len() }
+
+	x := &exportData{imports: imports, pkgs: make(map[*types.Package]bool)}
+	p := x.newImporter(unescaper{r})
 
 	// read low-level encoding format
-	switch format := p.byte(); format {
+	format := p.byte()
+	if format == 'z' {
+		// compressed - read the uncompressed export data,
+		// starting with its own encoding format
+		p.in = unescaper{bufio.NewReader(flate.NewReader(unescaper{r}))}
+		p.read = 0
+		format = p.byte()
+	}
+	switch format {
 	case 'c':
 		// compact format - nothing to do
 	case 'd':
 		p.debugFormat = true
 	default:
-		return p.read, nil, fmt.Errorf("invalid encoding format in export data: got %q; want 'c' or 'd'", format)
+		return consumed(), nil, fmt.Errorf("invalid encoding format in export data: got %q; want 'c' or 'd'", format)
 	}
 
 	// --- generic export data ---
 
-	if v := p.string(); v != "v0" {
-		return p.read, nil, fmt.Errorf("unknown version: %s", v)
+	if v := p.string(); v != "v4" {
+		return consumed(), nil, fmt.Errorf("package %s was compiled with an incompatible compiler version: export data version %s, want v4", path, v)
 	}
-
-	// populate typList with predeclared "known" types
-	p.typList = append(p.typList, predeclared...)
+	p.string() // discard compiler version
 
 	// read package data
 	// TODO(gri) clean this up
@@ -61,70 +72,134 @@ func BImportData(imports map[string]*types.Package, data []byte, path string) (i
 		p.imports[path] = pkg
 	}
 	p.pkgList = append(p.pkgList, pkg)
-
-	if debug && p.pkgList[0] != pkg {
-		panic("imported packaged not found in pkgList[0]")
-	}
+	x.pkg = pkg
 
 	// read compiler-specific flags
 	p.string() // discard
 
-	// read consts
-	for i := p.int(); i > 0; i-- {
-		name := p.string()
-		typ := p.typ(nil)
-		val := p.value()
-		p.declare(types.NewConst(token.NoPos, pkg, name, typ, val))
+	// read data section
+	x.data = make([]byte, p.int())
+	for i := range x.data {
+		x.data[i] = p.byte()
 	}
-
-	// read vars
-	for i := p.int(); i > 0; i-- {
-		name := p.string()
-		typ := p.typ(nil)
-		p.declare(types.NewVar(token.NoPos, pkg, name, typ))
+	if len(x.data) == 0 || x.data[0] != format {
+		panic("invalid data section in export data")
 	}
 
-	// read funcs
+	// read index
+	x.index = make(map[*types.Package]map[string]int)
+	var names []string // of the objects of the imported package
 	for i := p.int(); i > 0; i-- {
-		name := p.string()
-		params, isddd := p.paramList()
-		result, _ := p.paramList()
-		sig := types.NewSignature(nil, params, result, isddd)
-		p.int() // read and discard index of inlined function body
-		p.declare(types.NewFunc(token.NoPos, pkg, name, sig))
+		pkg := p.pkg()
+		offsets := make(map[string]int)
+		for i := p.int(); i > 0; i-- {
+			name := p.string()
+			offsets[name] = p.int()
+			if pkg == x.pkg {
+				names = append(names, name)
+			}
+		}
+		x.index[pkg] = offsets
 	}
 
-	// read types
-	for i := p.int(); i > 0; i-- {
-		// name is parsed as part of named type and the
-		// type object is added to scope via respective
-		// named type
-		_ = p.typ(nil).(*types.Named)
+	// read the declarations of the imported package, and those
+	// of the named types of other packages they refer to
+	for _, name := range names {
+		x.decl(x.pkg, name)
+	}
+	// (x.queue grows during iteration - cannot use range)
+	for i := 0; i < len(x.queue); i++ {
+		obj := x.queue[i]
+		x.decl(obj.Pkg(), obj.Name())
 	}
 
 	// ignore compiler-specific import data
 
 	// complete interfaces
-	for _, typ := range p.typList {
-		if it, ok := typ.(*types.Interface); ok {
-			it.Complete()
-		}
+	for _, t := range x.ifaces {
+		t.Complete()
 	}
 
 	// record all referenced packages as imports
-	list := append(([]*types.Package)(nil), p.pkgList[1:]...)
+	var list []*types.Package
+	for p := range x.pkgs {
+		list = append(list, p)
+	}
 	sort.Sort(byPath(list))
 	pkg.SetImports(list)
 
 	// package was imported completely and without errors
 	pkg.MarkComplete()
 
-	return p.read, pkg, nil
+	return consumed(), pkg, nil
+}
+
+// An exportData holds the data section and the index of the export
+// data being imported, and the state shared by the importers of the
+// declarations in the data section.
+type exportData struct {
+	imports map[string]*types.Package
+	pkg     *types.Package                    // package being imported
+	data    []byte                            // data section
+	index   map[*types.Package]map[string]int // offsets of the declarations by package and name
+	queue   []*types.TypeName                 // named types to declare, in order
+	pkgs    map[*types.Package]bool           // packages referred to, except pkg
+	ifaces  []*types.Interface                // interfaces to complete
+}
+
+// newImporter returns an importer reading from in,
+// with its type list populated with the predeclared types.
+func (x *exportData) newImporter(in io.ByteReader) *importer {
+	p := &importer{
+		imports: x.imports,
+		x:       x,
+		in:      in,
+		strList: []string{""}, // empty string is mapped to 0
+	}
+	p.buf = p.bufarray[:]
+
+	// populate typList with predeclared "known" types
+	p.typList = append(p.typList, predeclared...)
+	return p
+}
+
+// decl reads the declaration of the object name of pkg.
+// Other than named types, only the objects of the imported
+// package are declared.
+func (x *exportData) decl(pkg *types.Package, name string) {
+	p := x.newImporter(bytes.NewReader(x.data[x.index[pkg][name]:]))
+	p.debugFormat = x.data[0] == 'd'
+
+	switch tag := p.tagOrIndex(); tag {
+	case constTag:
+		typ := p.typ(nil)
+		val := p.value()
+		p.declare(types.NewConst(token.NoPos, pkg, name, typ, val))
+
+	case varTag:
+		typ := p.typ(nil)
+		p.declare(types.NewVar(token.NoPos, pkg, name, typ))
+
+	case funcTag:
+		params, isddd := p.paramList()
+		result, _ := p.paramList()
+		sig := types.NewSignature(nil, params, result, isddd)
+		p.int() // read and discard offset of inlined function body
+		p.declare(types.NewFunc(token.NoPos, pkg, name, sig))
+
+	case typeTag:
+		p.typeDecl(p.named(pkg, name))
+
+	default:
+		panic(fmt.Sprintf("unexpected declaration tag %d", tag))
+	}
 }
 
 type importer struct {
 	imports  map[string]*types.Package
-	data     []byte
+	x        *exportData
+	in       io.ByteReader
+	strList  []string
 	buf      []byte   // for reading strings
 	bufarray [64]byte // initial underlying array for buf, large enough to avoid allocation when compiling std lib
 	pkgList  []*types.Package
@@ -135,7 +210,7 @@ type importer struct {
 }
 
 func (p *importer) declare(obj types.Object) {
-	if alt := p.pkgList[0].Scope().Insert(obj); alt != nil {
+	if alt := p.x.pkg.Scope().Insert(obj); alt != nil {
 		// This can only happen if we import a package a second time.
 		panic(fmt.Sprintf("%s already declared", alt.Name()))
 	}
@@ -162,9 +237,10 @@ func (p *importer) pkg() *types.Package {
 		panic("empty package name in import")
 	}
 
-	// we should never see an empty import path
+	// the empty import path stands for the imported package
 	if path == "" {
-		panic("empty import path")
+		p.pkgList = append(p.pkgList, p.x.pkg)
+		return p.x.pkg
 	}
 
 	// if the package was imported before, use that one; otherwise create a new one
@@ -174,10 +250,69 @@ func (p *importer) pkg() *types.Package {
 		p.imports[path] = pkg
 	}
 	p.pkgList = append(p.pkgList, pkg)
+	if pkg != p.x.pkg {
+		p.x.pkgs[pkg] = true
+	}
 
 	return pkg
 }
 
+// named returns the type name for the named type name of pkg,
+// creating it if it does not exist yet. A type name created for
+// another package than the imported one is queued to be declared.
+func (p *importer) named(pkg *types.Package, name string) *types.TypeName {
+	scope := pkg.Scope()
+	obj := scope.Lookup(name)
+
+	// if the object doesn't exist yet, create and insert it
+	if obj == nil {
+		obj = types.NewTypeName(token.NoPos, pkg, name, nil)
+		types.NewNamed(obj.(*types.TypeName), nil, nil)
+		scope.Insert(obj)
+		if pkg != p.x.pkg {
+			if _, ok := p.x.index[pkg][name]; !ok {
+				panic(fmt.Sprintf("no declaration of %s.%s", pkg.Path(), name))
+			}
+			p.x.queue = append(p.x.queue, obj.(*types.TypeName))
+		}
+	}
+
+	tname, ok := obj.(*types.TypeName)
+	if !ok {
+		panic(fmt.Sprintf("pkg = %s, name = %s => %s", pkg, name, obj))
+	}
+	return tname
+}
+
+// typeDecl reads the underlying type and the methods of the named
+// type of obj, unless it was declared before.
+func (p *importer) typeDecl(obj *types.TypeName) {
+	t := obj.Type().(*types.Named)
+	if t.Underlying() != nil {
+		return // declared by an earlier import
+	}
+	parent := obj.Pkg()
+
+	// read underlying type
+	t.SetUnderlying(p.typ(parent))
+
+	// interfaces don't have associated methods
+	if _, ok := t.Underlying().(*types.Interface); ok {
+		return
+	}
+
+	// read associated methods
+	for i := p.int(); i > 0; i-- {
+		name := p.string()
+		recv, _ := p.paramList() // TODO(gri) do we need a full param list for the receiver?
+		params, isddd := p.paramList()
+		result, _ := p.paramList()
+		p.int() // read and discard offset of inlined function body
+		sig := types.NewSignature(recv.At(0), params, result, isddd)
+		t.AddMethod(types.NewFunc(token.NoPos, parent, name, sig))
+	}
+}
+
 func (p *importer) record(t types.Type) {
 	p.typList = append(p.typList, t)
 }
@@ -206,48 +341,10 @@ func (p *importer) typ(parent *types.Package) types.Type {
 	// otherwise, i is the type tag (< 0)
 	switch i {
 	case namedTag:
-		// read type object
+		// the underlying type and methods are part of the declaration
 		name := p.string()
-		parent = p.pkg()
-		scope := parent.Scope()
-		obj := scope.Lookup(name)
-
-		// if the object doesn't exist yet, create and insert it
-		if obj == nil {
-			obj = types.NewTypeName(token.NoPos, parent, name, nil)
-			scope.Insert(obj)
-		}
-
-		if _, ok := obj.(*types.TypeName); !ok {
-			panic(fmt.Sprintf("pkg = %s, name = %s => %s", parent, name, obj))
-		}
-
-		// associate new named type with obj if it doesn't exist yet
-		t0 := types.NewNamed(obj.(*types.TypeName), nil, nil)
-
-		// but record the existing type, if any
-		t := obj.Type().(*types.Named)
+		t := p.named(p.pkg(), name).Type()
 		p.record(t)
-
-		// read underlying type
-		t0.SetUnderlying(p.typ(parent))
-
-		// interfaces don't have associated methods
-		if _, ok := t0.Underlying().(*types.Interface); ok {
-			return t
-		}
-
-		// read associated methods
-		for i := p.int(); i > 0; i-- {
-			name := p.string()
-			recv, _ := p.paramList() // TODO(gri) do we need a full param list for the receiver?
-			params, isddd := p.paramList()
-			result, _ := p.paramList()
-			p.int() // read and discard index of inlined function body
-			sig := types.NewSignature(recv.At(0), params, result, isddd)
-			t0.AddMethod(types.NewFunc(token.NoPos, parent, name, sig))
-		}
-
 		return t
 
 	case arrayTag:
@@ -327,6 +424,7 @@ func (p *importer) typ(parent *types.Package) types.Type {
 
 		t := types.NewInterface(methods, nil)
 		p.typList[n] = t
+		p.x.ifaces = append(p.x.ifaces, t)
 		return t
 
 	case mapTag:
@@ -389,7 +487,7 @@ func (p *importer) fieldName(parent *types.Package) (*types.Package, string) {
 	pkg := parent
 	if pkg == nil {
 		// use the imported package instead
-		pkg = p.pkgList[0]
+		pkg = p.x.pkg
 	}
 	name := p.string()
 	if name == "" {
@@ -549,19 +647,28 @@ func (p *importer) string() string {
 		p.marker('s')
 	}
 
-	if n := int(p.rawInt64()); n > 0 {
-		if cap(p.buf) < n {
-			p.buf = make([]byte, n)
-		} else {
-			p.buf = p.buf[:n]
-		}
-		for i := 0; i < n; i++ {
-			p.buf[i] = p.byte()
+	// if the string was seen before, i is its index (>= 0)
+	// (the empty string is at index 0)
+	i := p.rawInt64()
+	if i >= 0 {
+		if i >= int64(len(p.strList)) {
+			panic(fmt.Sprintf("invalid string index %d", i))
 		}
-		return string(p.buf)
+		return p.strList[i]
 	}
 
-	return ""
+	// otherwise, i is the negative string length (< 0)
+	if n := int(-i); n <= cap(p.buf) {
+		p.buf = p.buf[:n]
+	} else {
+		p.buf = make([]byte, n)
+	}
+	for i := range p.buf {
+		p.buf[i] = p.byte()
+	}
+	s := string(p.buf)
+	p.strList = append(p.strList, s)
+	return s
 }
 
 func (p *importer) marker(want byte) {
@@ -589,27 +696,51 @@ func (p *importer) ReadByte() (byte, error) {
 	return p.byte(), nil
 }
 
-// byte is the bottleneck interface for reading p.data.
-// It unescapes '|' 'S' to '$' and '|' '|' to '|'.
-func (p *importer) byte() byte {
-	b := p.data[0]
-	r := 1
-	if b == '|' {
-		b = p.data[1]
-		r = 2
-		switch b {
-		case 'S':
-			b = '$'
-		case '|':
-			// nothing to do
-		default:
-			panic("unexpected escape sequence in export data")
+// unescaper reads the bytes escaped by the exporter from in,
+// without reading beyond them.
+type unescaper struct {
+	in io.ByteReader
+}
+
+func (u unescaper) ReadByte() (byte, error) {
+	c, err := u.in.ReadByte()
+	if c != '|' || err != nil {
+		return c, err
+	}
+	c, err = u.in.ReadByte()
+	if err != nil {
+		return c, err
+	}
+	switch c {
+	case 'S':
+		c = '$'
+	case '|':
+		// nothing to do
+	default:
+		return c, errors.New("unexpected escape sequence in export data")
+	}
+	return c, nil
+}
+
+func (u unescaper) Read(b []byte) (int, error) {
+	for i := range b {
+		c, err := u.ReadByte()
+		if err != nil {
+			return i, err
 		}
+		b[i] = c
 	}
-	p.data = p.data[r:]
-	p.read += r
-	return b
+	return len(b), nil
+}
 
+// byte is the bottleneck interface for reading p.in.
+func (p *importer) byte() byte {
+	c, err := p.in.ReadByte()
+	if err != nil {
+		panic(fmt.Sprintf("read error: %v", err))
+	}
+	p.read++
+	return c
 }
 
 // ----------------------------------------------------------------------------
@@ -620,6 +751,12 @@ const (
 	// Packages
 	packageTag = -(iota + 1)
 
+	// Declarations
+	constTag
+	typeTag
+	varTag
+	funcTag
+
 	// Types
 	namedTag
 	arrayTag
@@ -640,6 +777,8 @@ const (
 	fractionTag // not used by gc
 	complexTag
 	stringTag
+	nilTag
+	unknownTag // not used by gc (only appears in packages with errors)
 )
 
 var predeclared = []types.Type{
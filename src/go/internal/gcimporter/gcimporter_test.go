@@ -33,9 +33,10 @@ func skipSpecialPlatforms(t *testing.T) {
 	}
 }
 
+// compile compiles filename using the old textual export format.
 func compile(t *testing.T, dirname, filename string) string {
 	testenv.MustHaveGoBuild(t)
-	cmd := exec.Command("go", "tool", "compile", filename)
+	cmd := exec.Command("go", "tool", "compile", "-newexport=0", filename)
 	cmd.Dir = dirname
 	out, err := cmd.CombinedOutput()
 	if err != nil {
@@ -46,10 +47,11 @@ func compile(t *testing.T, dirname, filename string) string {
 	return filepath.Join(dirname, filename[:len(filename)-2]+"o")
 }
 
-// TODO(gri) Remove this function once we switched to new export format by default.
-func compileNewExport(t *testing.T, dirname, filename string) string {
+// TODO(gri) Remove this function once the textual export format is gone.
+func compileNewExport(t *testing.T, dirname, filename string, flags ...string) string {
 	testenv.MustHaveGoBuild(t)
-	cmd := exec.Command("go", "tool", "compile", "-newexport", filename)
+	args := append([]string{"tool", "compile", "-newexport"}, flags...)
+	cmd := exec.Command("go", append(args, filename)...)
 	cmd.Dir = dirname
 	out, err := cmd.CombinedOutput()
 	if err != nil {
@@ -155,6 +157,26 @@ func TestImportTestdataNewExport(t *testing.T) {
 	}
 }
 
+func TestImportTestdataCompressed(t *testing.T) {
+	// This package only handles gc export data.
+	if runtime.Compiler != "gc" {
+		t.Skipf("gc-built packages not available (compiler = %s)", runtime.Compiler)
+		return
+	}
+
+	if outFn := compileNewExport(t, "testdata", "exports.go", "-compressexport"); outFn != "" {
+		defer os.Remove(outFn)
+	}
+
+	if pkg := testPath(t, "./testdata/exports", "."); pkg != nil {
+		want := `[package ast ("go/ast") package token ("go/token")]`
+		got := fmt.Sprint(pkg.Imports())
+		if got != want {
+			t.Errorf(`Package("exports").Imports() = %s, want %s`, got, want)
+		}
+	}
+}
+
 func TestImportStdLib(t *testing.T) {
 	skipSpecialPlatforms(t)
 
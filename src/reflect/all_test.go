@@ -3107,6 +3107,10 @@ type MyRunes []int32
 type MyFunc func()
 type MyByte byte
 
+type MyStruct struct {
+	x int `some:"bar"`
+}
+
 var convertTests = []struct {
 	in  Value
 	out Value
@@ -3454,6 +3458,40 @@ var convertTests = []struct {
 	{V(new(io.Reader)), V(new(io.Reader))},
 	{V(new(io.Writer)), V(new(io.Writer))},
 
+	// structs that differ only in field tags
+	{V(struct {
+		x int `some:"foo"`
+	}{}), V(struct {
+		x int `some:"bar"`
+	}{})},
+	{V(struct {
+		x int `some:"bar"`
+	}{}), V(struct {
+		x int `some:"foo"`
+	}{})},
+	{V(MyStruct{}), V(struct {
+		x int `some:"foo"`
+	}{})},
+	{V(struct {
+		x int `some:"foo"`
+	}{}), V(MyStruct{})},
+	{V(MyStruct{}), V(struct {
+		x int `some:"bar"`
+	}{})},
+	{V(struct {
+		x int `some:"bar"`
+	}{}), V(MyStruct{})},
+	{V(&struct {
+		x int `some:"foo"`
+	}{}), V(&struct {
+		x int `some:"bar"`
+	}{})},
+	{V(&struct {
+		x int `some:"bar"`
+	}{}), V(&struct {
+		x int `some:"foo"`
+	}{})},
+
 	// interfaces
 	{V(int(1)), EmptyInterfaceV(int(1))},
 	{V(string("hello")), EmptyInterfaceV(string("hello"))},
@@ -0,0 +1,61 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build asan
+
+package runtime
+
+import (
+	"unsafe"
+)
+
+// Public address sanitizer API.
+
+func ASanRead(addr unsafe.Pointer, len int) {
+	sp := getcallersp(unsafe.Pointer(&addr))
+	pc := getcallerpc(unsafe.Pointer(&addr))
+	doasanread(addr, uintptr(len), sp, pc)
+}
+
+func ASanWrite(addr unsafe.Pointer, len int) {
+	sp := getcallersp(unsafe.Pointer(&addr))
+	pc := getcallerpc(unsafe.Pointer(&addr))
+	doasanwrite(addr, uintptr(len), sp, pc)
+}
+
+// Private interface for the runtime.
+const asanenabled = true
+
+// asanread and asanwrite are called from instrumented code.
+// They pass the caller's stack pointer and PC on to asan,
+// so that its reports point at the faulting Go code.
+func asanread(addr unsafe.Pointer, sz uintptr) {
+	sp := getcallersp(unsafe.Pointer(&addr))
+	pc := getcallerpc(unsafe.Pointer(&addr))
+	doasanread(addr, sz, sp, pc)
+}
+
+func asanwrite(addr unsafe.Pointer, sz uintptr) {
+	sp := getcallersp(unsafe.Pointer(&addr))
+	pc := getcallerpc(unsafe.Pointer(&addr))
+	doasanwrite(addr, sz, sp, pc)
+}
+
+//go:noescape
+func doasanread(addr unsafe.Pointer, sz, sp, pc uintptr)
+
+//go:noescape
+func doasanwrite(addr unsafe.Pointer, sz, sp, pc uintptr)
+
+//go:noescape
+func asanunpoison(addr unsafe.Pointer, sz uintptr)
+
+//go:noescape
+func asanpoison(addr unsafe.Pointer, sz uintptr)
+
+// These are called from asan_amd64.s
+//go:cgo_import_static __asan_read_go
+//go:cgo_import_static __asan_write_go
+//go:cgo_import_static __asan_unpoison_go
+//go:cgo_import_static __asan_poison_go
@@ -188,7 +188,10 @@ func chansend(t *chantype, c *hchan, ep unsafe.Pointer, block bool, callerpc uin
 			raceacquire(qp)
 			racerelease(qp)
 		}
-		typedmemmove(c.elemtype, qp, ep)
+		// Elements of zero size are only counted.
+		if c.elemsize != 0 {
+			typedmemmove(c.elemtype, qp, ep)
+		}
 		c.sendx++
 		if c.sendx == c.dataqsiz {
 			c.sendx = 0
@@ -247,7 +250,8 @@ func chansend(t *chantype, c *hchan, ep unsafe.Pointer, block bool, callerpc uin
 // The receiver is then woken up to go on its merry way.
 // Channel c must be empty and locked.  send unlocks c with unlockf.
 // sg must already be dequeued from c.
-// ep must be non-nil and point to the heap or the caller's stack.
+// ep must point to the heap or the caller's stack. It may be nil
+// only if the element type has zero size.
 func send(c *hchan, sg *sudog, ep unsafe.Pointer, unlockf func()) {
 	if raceenabled {
 		if c.dataqsiz == 0 {
@@ -458,10 +462,12 @@ func chanrecv(t *chantype, c *hchan, ep unsafe.Pointer, block bool) (selected, r
 			raceacquire(qp)
 			racerelease(qp)
 		}
-		if ep != nil {
-			typedmemmove(c.elemtype, ep, qp)
+		if c.elemsize != 0 {
+			if ep != nil {
+				typedmemmove(c.elemtype, ep, qp)
+			}
+			memclr(qp, uintptr(c.elemsize))
 		}
-		memclr(qp, uintptr(c.elemsize))
 		c.recvx++
 		if c.recvx == c.dataqsiz {
 			c.recvx = 0
@@ -546,12 +552,14 @@ func recv(c *hchan, sg *sudog, ep unsafe.Pointer, unlockf func()) {
 			raceacquireg(sg.g, qp)
 			racereleaseg(sg.g, qp)
 		}
-		// copy data from queue to receiver
-		if ep != nil {
-			typedmemmove(c.elemtype, ep, qp)
+		if c.elemsize != 0 {
+			// copy data from queue to receiver
+			if ep != nil {
+				typedmemmove(c.elemtype, ep, qp)
+			}
+			// copy data from sender to queue
+			typedmemmove(c.elemtype, qp, sg.elem)
 		}
-		// copy data from sender to queue
-		typedmemmove(c.elemtype, qp, sg.elem)
 		c.recvx++
 		if c.recvx == c.dataqsiz {
 			c.recvx = 0
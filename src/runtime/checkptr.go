@@ -0,0 +1,65 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package runtime
+
+import "unsafe"
+
+// The functions in this file are called by code compiled with
+// -d=checkptr, which instruments conversions involving unsafe.Pointer.
+
+// checkptrAlignment is called for each conversion of an unsafe.Pointer
+// p to *T, with elem set to T and n to 1. If T contains pointers, p must
+// be suitably aligned for it, and the n values of type T that p points
+// to must not straddle two allocations. A conversion to *[N]E that is
+// immediately sliced to length n passes E and n instead.
+func checkptrAlignment(p unsafe.Pointer, elem *_type, n uintptr) {
+	if elem.kind&kindNoPointers == 0 && uintptr(p)&(uintptr(elem.align)-1) != 0 {
+		throw("checkptr: misaligned pointer conversion")
+	}
+	if size := n * elem.size; size > 1 && checkptrBase(p) != checkptrBase(add(p, size-1)) {
+		throw("checkptr: converted pointer straddles multiple allocations")
+	}
+}
+
+// checkptrArithmetic is called for each conversion of a uintptr
+// p computed by arithmetic to an unsafe.Pointer. Originals holds the
+// unsafe.Pointer values the arithmetic started from. If p points into
+// an allocation, one of the originals must point into the same one.
+func checkptrArithmetic(p uintptr, originals []unsafe.Pointer) {
+	if 0 < p && p < _PageSize {
+		throw("checkptr: pointer arithmetic computed bad pointer value")
+	}
+	base := checkptrBase(unsafe.Pointer(p))
+	if base == 0 {
+		return
+	}
+	for _, original := range originals {
+		if base == checkptrBase(original) {
+			return
+		}
+	}
+	throw("checkptr: pointer arithmetic result points to invalid allocation")
+}
+
+// checkptrBase returns the base address of the allocation that p points
+// into: a heap object, or the data or bss section of a module. It returns
+// 0 for pointers into the stack and for pointers it knows nothing about.
+func checkptrBase(p unsafe.Pointer) uintptr {
+	if gp := getg(); gp.stack.lo <= uintptr(p) && uintptr(p) < gp.stack.hi {
+		return 0
+	}
+	if base, _, _ := heapBitsForObject(uintptr(p), 0, 0); base != 0 {
+		return base
+	}
+	for datap := &firstmoduledata; datap != nil; datap = datap.next {
+		if datap.data <= uintptr(p) && uintptr(p) < datap.edata {
+			return datap.data
+		}
+		if datap.bss <= uintptr(p) && uintptr(p) < datap.ebss {
+			return datap.bss
+		}
+	}
+	return 0
+}
@@ -0,0 +1,25 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package runtime
+
+import "unsafe"
+
+// checkfailed is called by code compiled with -d=checks when one of
+// the assertions inserted by the compiler does not hold.
+func checkfailed(msg string) {
+	throw(msg)
+}
+
+// checkwarn is called by code compiled with -d=checks=2 when a property
+// that correct programs may violate does not hold. It prints a warning
+// with the position of the check and returns.
+func checkwarn(msg string) {
+	pc := getcallerpc(unsafe.Pointer(&msg))
+	if f := findfunc(pc); f != nil {
+		file, line := funcline(f, pc-1)
+		print(file, ":", line, ": ")
+	}
+	print("warning: ", msg, "\n")
+}
@@ -0,0 +1,48 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package runtime
+
+import "unsafe"
+
+// Native coverage.
+//
+// The init function of each package compiled with -cover registers
+// the package's block counters and block table here; see
+// cmd/compile/internal/gc/cover.go for their format. Package testing
+// reads them back to write its coverage profile.
+
+type coverTable struct {
+	counters *uint32
+	n        uintptr
+	blocks   *uint8
+	size     uintptr
+}
+
+var coverTables []coverTable
+
+// coverRegister records the n counters starting at counters and
+// the size-byte block table starting at blocks. Package init
+// functions run one at a time, so no locking is needed.
+func coverRegister(counters *uint32, n uintptr, blocks *uint8, size uintptr) {
+	coverTables = append(coverTables, coverTable{counters, n, blocks, size})
+}
+
+//go:linkname testing_coverTables testing.runtime_coverTables
+func testing_coverTables() (counters [][]uint32, blocks []string) {
+	for _, t := range coverTables {
+		var c []uint32
+		s := (*slice)(unsafe.Pointer(&c))
+		s.array = unsafe.Pointer(t.counters)
+		s.len = int(t.n)
+		s.cap = int(t.n)
+		var b string
+		ss := (*stringStruct)(unsafe.Pointer(&b))
+		ss.str = unsafe.Pointer(t.blocks)
+		ss.len = int(t.size)
+		counters = append(counters, c)
+		blocks = append(blocks, b)
+	}
+	return
+}
@@ -0,0 +1,70 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build libfuzzer,linux,amd64
+
+package runtime
+
+import (
+	"unsafe"
+)
+
+// The functions in this file are called by code compiled with
+// -d=libfuzzer. They forward coverage counters and comparison
+// operands to libFuzzer, which is linked into the program from C.
+
+// libfuzzerHookStrCmp reports the comparison of s1 and s2.
+func libfuzzerHookStrCmp(s1, s2 string) {
+	n := len(s1)
+	if len(s2) < n {
+		n = len(s2)
+	}
+	if n == 0 {
+		return
+	}
+	result := uintptr(0)
+	if s1 != s2 {
+		result = 1
+	}
+	pc := getcallerpc(unsafe.Pointer(&s1))
+	p1 := (*stringStruct)(unsafe.Pointer(&s1)).str
+	p2 := (*stringStruct)(unsafe.Pointer(&s2)).str
+	libfuzzerCallMemcmp(pc, p1, p2, uintptr(n), result)
+}
+
+// The remaining functions are implemented in libfuzzer_amd64.s.
+
+// libfuzzerInitCounters registers the n 8-bit block counters
+// starting at start. It is called by the init function of each
+// instrumented package.
+//
+//go:noescape
+func libfuzzerInitCounters(start *uint8, n uintptr)
+
+// The comparison hooks report their operands. libFuzzer identifies
+// comparisons by the return address, which is the same for all calls
+// from Go, so it only learns from the values being compared.
+func libfuzzerTraceCmp1(arg0, arg1 uint8)
+func libfuzzerTraceCmp2(arg0, arg1 uint16)
+func libfuzzerTraceCmp4(arg0, arg1 uint32)
+func libfuzzerTraceCmp8(arg0, arg1 uint64)
+func libfuzzerTraceConstCmp1(arg0, arg1 uint8)
+func libfuzzerTraceConstCmp2(arg0, arg1 uint16)
+func libfuzzerTraceConstCmp4(arg0, arg1 uint32)
+func libfuzzerTraceConstCmp8(arg0, arg1 uint64)
+
+//go:noescape
+func libfuzzerCallMemcmp(pc uintptr, s1, s2 unsafe.Pointer, n, result uintptr)
+
+// These are called from libfuzzer_amd64.s
+//go:cgo_import_static __sanitizer_cov_8bit_counters_init
+//go:cgo_import_static __sanitizer_cov_trace_cmp1
+//go:cgo_import_static __sanitizer_cov_trace_cmp2
+//go:cgo_import_static __sanitizer_cov_trace_cmp4
+//go:cgo_import_static __sanitizer_cov_trace_cmp8
+//go:cgo_import_static __sanitizer_cov_trace_const_cmp1
+//go:cgo_import_static __sanitizer_cov_trace_const_cmp2
+//go:cgo_import_static __sanitizer_cov_trace_const_cmp4
+//go:cgo_import_static __sanitizer_cov_trace_const_cmp8
+//go:cgo_import_static __sanitizer_weak_hook_memcmp
@@ -0,0 +1,23 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build !libfuzzer !linux !amd64
+
+// Dummy libFuzzer support API, used when not built with the libfuzzer
+// tag. Code compiled with -d=libfuzzer still links and runs, but its
+// counters and comparisons go nowhere.
+
+package runtime
+
+func libfuzzerInitCounters(start *uint8, n uintptr) {}
+
+func libfuzzerTraceCmp1(arg0, arg1 uint8)       {}
+func libfuzzerTraceCmp2(arg0, arg1 uint16)      {}
+func libfuzzerTraceCmp4(arg0, arg1 uint32)      {}
+func libfuzzerTraceCmp8(arg0, arg1 uint64)      {}
+func libfuzzerTraceConstCmp1(arg0, arg1 uint8)  {}
+func libfuzzerTraceConstCmp2(arg0, arg1 uint16) {}
+func libfuzzerTraceConstCmp4(arg0, arg1 uint32) {}
+func libfuzzerTraceConstCmp8(arg0, arg1 uint64) {}
+func libfuzzerHookStrCmp(s1, s2 string)         {}
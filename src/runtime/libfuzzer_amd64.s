@@ -0,0 +1,120 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build libfuzzer,linux
+
+#include "go_asm.h"
+#include "go_tls.h"
+#include "funcdata.h"
+#include "textflag.h"
+
+// This is like msan_amd64.s, but for the libFuzzer calls.
+// See race_amd64.s for detailed comments.
+
+#define RARG0 DI
+#define RARG1 SI
+#define RARG2 DX
+#define RARG3 CX
+#define RARG4 R8
+
+// func runtime·libfuzzerInitCounters(start *uint8, n uintptr)
+TEXT	runtime·libfuzzerInitCounters(SB), NOSPLIT, $0-16
+	MOVQ	start+0(FP), RARG0
+	MOVQ	n+8(FP), RARG1
+	ADDQ	RARG0, RARG1
+	// void __sanitizer_cov_8bit_counters_init(uint8_t *start, uint8_t *stop);
+	MOVQ	$__sanitizer_cov_8bit_counters_init(SB), AX
+	JMP	libfuzzercall<>(SB)
+
+// func runtime·libfuzzerTraceCmp1(arg0, arg1 uint8)
+TEXT	runtime·libfuzzerTraceCmp1(SB), NOSPLIT, $0-2
+	MOVBQZX	arg0+0(FP), RARG0
+	MOVBQZX	arg1+1(FP), RARG1
+	// void __sanitizer_cov_trace_cmp1(uint8_t arg0, uint8_t arg1);
+	MOVQ	$__sanitizer_cov_trace_cmp1(SB), AX
+	JMP	libfuzzercall<>(SB)
+
+// func runtime·libfuzzerTraceCmp2(arg0, arg1 uint16)
+TEXT	runtime·libfuzzerTraceCmp2(SB), NOSPLIT, $0-4
+	MOVWQZX	arg0+0(FP), RARG0
+	MOVWQZX	arg1+2(FP), RARG1
+	// void __sanitizer_cov_trace_cmp2(uint16_t arg0, uint16_t arg1);
+	MOVQ	$__sanitizer_cov_trace_cmp2(SB), AX
+	JMP	libfuzzercall<>(SB)
+
+// func runtime·libfuzzerTraceCmp4(arg0, arg1 uint32)
+TEXT	runtime·libfuzzerTraceCmp4(SB), NOSPLIT, $0-8
+	MOVLQZX	arg0+0(FP), RARG0
+	MOVLQZX	arg1+4(FP), RARG1
+	// void __sanitizer_cov_trace_cmp4(uint32_t arg0, uint32_t arg1);
+	MOVQ	$__sanitizer_cov_trace_cmp4(SB), AX
+	JMP	libfuzzercall<>(SB)
+
+// func runtime·libfuzzerTraceCmp8(arg0, arg1 uint64)
+TEXT	runtime·libfuzzerTraceCmp8(SB), NOSPLIT, $0-16
+	MOVQ	arg0+0(FP), RARG0
+	MOVQ	arg1+8(FP), RARG1
+	// void __sanitizer_cov_trace_cmp8(uint64_t arg0, uint64_t arg1);
+	MOVQ	$__sanitizer_cov_trace_cmp8(SB), AX
+	JMP	libfuzzercall<>(SB)
+
+// func runtime·libfuzzerTraceConstCmp1(arg0, arg1 uint8)
+TEXT	runtime·libfuzzerTraceConstCmp1(SB), NOSPLIT, $0-2
+	MOVBQZX	arg0+0(FP), RARG0
+	MOVBQZX	arg1+1(FP), RARG1
+	// void __sanitizer_cov_trace_const_cmp1(uint8_t arg0, uint8_t arg1);
+	MOVQ	$__sanitizer_cov_trace_const_cmp1(SB), AX
+	JMP	libfuzzercall<>(SB)
+
+// func runtime·libfuzzerTraceConstCmp2(arg0, arg1 uint16)
+TEXT	runtime·libfuzzerTraceConstCmp2(SB), NOSPLIT, $0-4
+	MOVWQZX	arg0+0(FP), RARG0
+	MOVWQZX	arg1+2(FP), RARG1
+	// void __sanitizer_cov_trace_const_cmp2(uint16_t arg0, uint16_t arg1);
+	MOVQ	$__sanitizer_cov_trace_const_cmp2(SB), AX
+	JMP	libfuzzercall<>(SB)
+
+// func runtime·libfuzzerTraceConstCmp4(arg0, arg1 uint32)
+TEXT	runtime·libfuzzerTraceConstCmp4(SB), NOSPLIT, $0-8
+	MOVLQZX	arg0+0(FP), RARG0
+	MOVLQZX	arg1+4(FP), RARG1
+	// void __sanitizer_cov_trace_const_cmp4(uint32_t arg0, uint32_t arg1);
+	MOVQ	$__sanitizer_cov_trace_const_cmp4(SB), AX
+	JMP	libfuzzercall<>(SB)
+
+// func runtime·libfuzzerTraceConstCmp8(arg0, arg1 uint64)
+TEXT	runtime·libfuzzerTraceConstCmp8(SB), NOSPLIT, $0-16
+	MOVQ	arg0+0(FP), RARG0
+	MOVQ	arg1+8(FP), RARG1
+	// void __sanitizer_cov_trace_const_cmp8(uint64_t arg0, uint64_t arg1);
+	MOVQ	$__sanitizer_cov_trace_const_cmp8(SB), AX
+	JMP	libfuzzercall<>(SB)
+
+// func runtime·libfuzzerCallMemcmp(pc uintptr, s1, s2 unsafe.Pointer, n, result uintptr)
+TEXT	runtime·libfuzzerCallMemcmp(SB), NOSPLIT, $0-40
+	MOVQ	pc+0(FP), RARG0
+	MOVQ	s1+8(FP), RARG1
+	MOVQ	s2+16(FP), RARG2
+	MOVQ	n+24(FP), RARG3
+	MOVQ	result+32(FP), RARG4
+	// void __sanitizer_weak_hook_memcmp(void *caller_pc, const void *s1, const void *s2, size_t n, int result);
+	MOVQ	$__sanitizer_weak_hook_memcmp(SB), AX
+	JMP	libfuzzercall<>(SB)
+
+// Switches SP to g0 stack and calls (AX). Arguments already set.
+TEXT	libfuzzercall<>(SB), NOSPLIT, $0-0
+	get_tls(R12)
+	MOVQ	g(R12), R14
+	MOVQ	g_m(R14), R13
+	// Switch to g0 stack.
+	MOVQ	SP, R12		// callee-saved, preserved across the CALL
+	MOVQ	m_g0(R13), R10
+	CMPQ	R10, R14
+	JE	call	// already on g0
+	MOVQ	(g_sched+gobuf_sp)(R10), SP
+call:
+	ANDQ	$~15, SP	// alignment for gcc ABI
+	CALL	AX
+	MOVQ	R12, SP
+	RET
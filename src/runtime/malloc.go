@@ -716,6 +716,9 @@ func mallocgc(size uintptr, typ *_type, flags uint32) unsafe.Pointer {
 	if msanenabled {
 		msanmalloc(x, size)
 	}
+	if asanenabled {
+		asanunpoison(x, size)
+	}
 
 	mp.mallocing = 0
 	releasem(mp)
@@ -269,6 +269,9 @@ func (s *mspan) sweep(preserve bool) bool {
 		if msanenabled {
 			msanfree(unsafe.Pointer(p), size)
 		}
+		if asanenabled {
+			asanpoison(unsafe.Pointer(p), size)
+		}
 
 		// Reset to allocated+noscan.
 		if cl == 0 {
@@ -110,6 +110,34 @@ func deferproc(siz int32, fn *funcval) { // arguments of fn follow fn
 	// been set and must not be clobbered.
 }
 
+// Create a new deferred function fn that takes no arguments.
+// The compiler turns a defer statement into a call to this instead of
+// deferproc when the deferred call has no arguments and no receiver,
+// as in "defer f()". There are no arguments to copy out of the
+// caller's frame, so unlike deferproc this needs neither nosplit
+// nor the switch to the system stack.
+func deferproc0(fn *funcval) {
+	if getg().m.curg != getg() {
+		// go code on the system stack can't defer
+		throw("defer on system stack")
+	}
+
+	d := newdefer(0)
+	if d._panic != nil {
+		throw("deferproc0: d.panic != nil after newdefer")
+	}
+	// newdefer may have grown the stack, so find the
+	// caller's sp only now.
+	d.fn = fn
+	d.pc = getcallerpc(unsafe.Pointer(&fn))
+	d.sp = getcallersp(unsafe.Pointer(&fn))
+
+	// See deferproc.
+	return0()
+	// No code can go here - the C return register has
+	// been set and must not be clobbered.
+}
+
 // Small malloc size classes >= 16 are the multiples of 16: 16, 32, 48, 64, 80, 96, 112, 128, 144, ...
 // Each P holds a pool for defers with small arg sizes.
 // Assign defer allocations to pools by rounding to 16, to match malloc size classes.
@@ -179,7 +207,7 @@ func init() {
 
 // Allocate a Defer, usually using per-P pool.
 // Each defer must be released with freedefer.
-// Note: runs on g0 stack
+// Note: runs on g0 stack, except when called from deferproc0.
 func newdefer(siz int32) *_defer {
 	var d *_defer
 	sc := deferclass(uintptr(siz))
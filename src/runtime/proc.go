@@ -429,8 +429,10 @@ func schedinit() {
 
 	sched.maxmcount = 10000
 
-	// Cache the framepointer experiment. This affects stack unwinding.
-	framepointer_enabled = haveexperiment("framepointer")
+	// Cache the framepointer setting. This affects stack unwinding.
+	// Frame pointers are on by default on amd64 and can be turned off
+	// with GOEXPERIMENT=noframepointer; see cmd/internal/obj/go.go.
+	framepointer_enabled = GOARCH == "amd64" && GOOS != "nacl" && !haveexperiment("noframepointer")
 
 	tracebackinit()
 	moduledataverify()
@@ -2810,6 +2812,9 @@ retry:
 			if msanenabled {
 				msanmalloc(unsafe.Pointer(gp.stack.lo), gp.stackAlloc)
 			}
+			if asanenabled {
+				asanunpoison(unsafe.Pointer(gp.stack.lo), gp.stackAlloc)
+			}
 		}
 	}
 	return gp
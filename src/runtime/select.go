@@ -514,11 +514,13 @@ bufrecv:
 	if cas.receivedp != nil {
 		*cas.receivedp = true
 	}
-	qp = chanbuf(c, c.recvx)
-	if cas.elem != nil {
-		typedmemmove(c.elemtype, cas.elem, qp)
+	if c.elemsize != 0 {
+		qp = chanbuf(c, c.recvx)
+		if cas.elem != nil {
+			typedmemmove(c.elemtype, cas.elem, qp)
+		}
+		memclr(qp, uintptr(c.elemsize))
 	}
-	memclr(qp, uintptr(c.elemsize))
 	c.recvx++
 	if c.recvx == c.dataqsiz {
 		c.recvx = 0
@@ -537,7 +539,9 @@ bufsend:
 	if msanenabled {
 		msanread(cas.elem, c.elemtype.size)
 	}
-	typedmemmove(c.elemtype, chanbuf(c, c.sendx), cas.elem)
+	if c.elemsize != 0 {
+		typedmemmove(c.elemtype, chanbuf(c, c.sendx), cas.elem)
+	}
 	c.sendx++
 	if c.sendx == c.dataqsiz {
 		c.sendx = 0
@@ -155,7 +155,7 @@ var stackLarge struct {
 	free [_MHeapMap_Bits]mSpanList // free lists by log_2(s.npages)
 }
 
-// Cached value of haveexperiment("framepointer")
+// Whether frame pointers are maintained; set in schedinit.
 var framepointer_enabled bool
 
 func stackinit() {
@@ -400,6 +400,9 @@ func stackalloc(n uint32) (stack, []stkbar) {
 	if msanenabled {
 		msanmalloc(v, uintptr(n))
 	}
+	if asanenabled {
+		asanunpoison(v, uintptr(n))
+	}
 	if stackDebug >= 1 {
 		print("  allocated ", v, "\n")
 	}
@@ -432,6 +435,9 @@ func stackfree(stk stack, n uintptr) {
 	if msanenabled {
 		msanfree(v, n)
 	}
+	if asanenabled {
+		asanpoison(v, n)
+	}
 	if stackCache != 0 && n < _FixedStack<<_NumStackOrders && n < _StackCacheSize {
 		order := uint8(0)
 		n2 := n
@@ -664,7 +670,7 @@ func adjustframe(frame *stkframe, arg unsafe.Pointer) bool {
 	// Adjust saved base pointer if there is one.
 	if sys.TheChar == '6' && frame.argp-frame.varp == 2*sys.RegSize {
 		if !framepointer_enabled {
-			print("runtime: found space for saved base pointer, but no framepointer experiment\n")
+			print("runtime: found space for saved base pointer, but frame pointers are disabled\n")
 			print("argp=", hex(frame.argp), " varp=", hex(frame.varp), "\n")
 			throw("bad frame layout")
 		}
@@ -9,6 +9,7 @@ package testing
 import (
 	"fmt"
 	"os"
+	"strings"
 	"sync/atomic"
 )
 
@@ -65,6 +66,55 @@ func RegisterCover(c Cover) {
 	cover = c
 }
 
+// runtime_coverTables returns the block counters and block tables
+// of the packages compiled with the compiler's -cover flag.
+// Provided by package runtime.
+func runtime_coverTables() (counters [][]uint32, blocks []string)
+
+// registerNativeCover registers the coverage data of the packages
+// compiled with -cover, unless the test binary was built by cmd/cover.
+// Each block table has one line per counter, in the form
+//	file:line0.col0,line1.col1 stmts
+// with the blocks of each file next to each other.
+func registerNativeCover() {
+	if cover.Mode != "" {
+		return
+	}
+	counters, tables := runtime_coverTables()
+	if len(counters) == 0 {
+		return
+	}
+	c := Cover{
+		Mode:     "count",
+		Counters: make(map[string][]uint32),
+		Blocks:   make(map[string][]CoverBlock),
+	}
+	for i, table := range tables {
+		lines := strings.SplitAfter(table, "\n")
+		lines = lines[:len(lines)-1]
+		if len(lines) != len(counters[i]) {
+			mustBeNil(fmt.Errorf("coverage table has %d blocks for %d counters", len(lines), len(counters[i])))
+		}
+		start := 0
+		for k, line := range lines {
+			colon := strings.LastIndex(line, ":")
+			if colon < 0 {
+				mustBeNil(fmt.Errorf("bad coverage block %q", line))
+			}
+			name := line[:colon]
+			var b CoverBlock
+			_, err := fmt.Sscanf(line[colon+1:], "%d.%d,%d.%d %d\n", &b.Line0, &b.Col0, &b.Line1, &b.Col1, &b.Stmts)
+			mustBeNil(err)
+			c.Blocks[name] = append(c.Blocks[name], b)
+			if k+1 == len(lines) || !strings.HasPrefix(lines[k+1], line[:colon+1]) {
+				c.Counters[name] = counters[i][start : k+1]
+				start = k + 1
+			}
+		}
+	}
+	RegisterCover(c)
+}
+
 // mustBeNil checks the error and, if present, reports it and exits.
 func mustBeNil(err error) {
 	if err != nil {
@@ -0,0 +1,111 @@
+// run
+
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Test evaluation order and side effects of select statements
+// with one communication case and a default, which the compiler
+// lowers to non-blocking runtime calls.
+
+package main
+
+var trace string
+
+func ch(c chan int) chan int {
+	trace += "c"
+	return c
+}
+
+func val() int {
+	trace += "v"
+	return 1
+}
+
+func idx() int {
+	trace += "i"
+	return 0
+}
+
+func check(want string) {
+	if trace != want {
+		panic("trace " + trace + ", want " + want)
+	}
+	trace = ""
+}
+
+func main() {
+	var a [1]int
+	var ok bool
+	c := make(chan int)
+	b := make(chan int, 1)
+	var nilc chan int
+
+	// The channel and the value sent are evaluated exactly once,
+	// even when the default case is chosen; the receive targets
+	// are evaluated only when the receive happens.
+	select {
+	case a[idx()] = <-ch(c):
+		panic("received from empty channel")
+	default:
+	}
+	check("c")
+
+	select {
+	case a[idx()], ok = <-ch(c):
+		panic("received from empty channel")
+	default:
+	}
+	check("c")
+
+	select {
+	case ch(c) <- val():
+		panic("sent on channel without receiver")
+	default:
+	}
+	check("cv")
+
+	select {
+	case ch(b) <- val():
+	default:
+		panic("send on buffered channel blocked")
+	}
+	check("cv")
+
+	select {
+	case a[idx()], ok = <-ch(b):
+	default:
+		panic("receive on full channel blocked")
+	}
+	check("ci")
+	if a[0] != 1 || !ok {
+		panic("wrong value received")
+	}
+
+	// Operations on nil channels are never ready.
+	select {
+	case ch(nilc) <- val():
+		panic("sent on nil channel")
+	default:
+	}
+	check("cv")
+
+	select {
+	case <-ch(nilc):
+		panic("received from nil channel")
+	default:
+	}
+	check("c")
+
+	// A closed channel is always ready to receive.
+	close(b)
+	select {
+	case a[idx()], ok = <-ch(b):
+	default:
+		panic("receive on closed channel blocked")
+	}
+	check("ci")
+	if a[0] != 0 || ok {
+		panic("wrong value received from closed channel")
+	}
+}
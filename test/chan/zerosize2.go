@@ -0,0 +1,138 @@
+// run
+
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Test sending and receiving on channels of zero-sized types,
+// which the compiler lowers without passing element pointers.
+
+package main
+
+type empty struct{}
+
+var calls int
+
+func f() empty {
+	calls++
+	return empty{}
+}
+
+func unbuffered() {
+	c := make(chan empty)
+	done := make(chan [0]byte)
+	go func() {
+		for i := 0; i < 10; i++ {
+			c <- f()
+		}
+		close(c)
+	}()
+	go func() {
+		var a [0]byte
+		n := 0
+		for {
+			var e empty
+			e, ok := <-c
+			_ = e
+			if !ok {
+				break
+			}
+			n++
+		}
+		if n != 10 {
+			panic("unbuffered: wrong count")
+		}
+		done <- a
+	}()
+	<-done
+	if calls != 10 {
+		panic("unbuffered: send operand not evaluated")
+	}
+}
+
+func buffered() {
+	c := make(chan struct{}, 3)
+	for i := 0; i < 3; i++ {
+		c <- struct{}{}
+	}
+	if len(c) != 3 {
+		panic("buffered: wrong len")
+	}
+	select {
+	case c <- struct{}{}:
+		panic("buffered: send on full channel")
+	default:
+	}
+	var s struct{}
+	s = <-c
+	_ = s
+	s, ok := <-c
+	if !ok {
+		panic("buffered: receive failed")
+	}
+	select {
+	case s = <-c:
+	default:
+		panic("buffered: select receive failed")
+	}
+	if len(c) != 0 {
+		panic("buffered: channel not drained")
+	}
+	close(c)
+	if _, ok := <-c; ok {
+		panic("buffered: receive from closed channel succeeded")
+	}
+}
+
+func selects() {
+	c := make(chan empty)
+	d := make(chan int)
+	go func() {
+		for i := 0; i < 5; i++ {
+			var e empty
+			select {
+			case c <- e:
+			case d <- i:
+			}
+		}
+		close(c)
+	}()
+	n := 0
+	for {
+		var e empty
+		var ok bool
+		select {
+		case e, ok = <-c:
+		case <-d:
+			ok = true
+		}
+		_ = e
+		if !ok {
+			break
+		}
+		n++
+	}
+	if n != 5 {
+		panic("selects: wrong count")
+	}
+}
+
+func sideEffects() {
+	c := make(chan empty, 1)
+	var a [2]empty
+	i := 2
+	c <- f()
+	defer func() {
+		if recover() == nil {
+			panic("sideEffects: index not evaluated")
+		}
+	}()
+	a[i] = <-c
+}
+
+func main() {
+	unbuffered()
+	buffered()
+	selects()
+	sideEffects()
+}
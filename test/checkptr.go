@@ -0,0 +1,80 @@
+// run -gcflags=-d=checkptr
+
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Test that -d=checkptr catches unsafe.Pointer misuse at run time
+// and leaves valid conversions alone.
+// Each case runs in a child process, since a failed check throws.
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"unsafe"
+)
+
+var sink interface{}
+
+func child(name string) {
+	switch name {
+	case "ok":
+		b := make([]byte, 64)
+		sink = b
+		_ = (*int64)(unsafe.Pointer(&b[8]))
+		_ = (*[56]byte)(unsafe.Pointer(&b[8]))
+		_ = (*[1 << 20]*int)(unsafe.Pointer(&b[0]))[:8:8]
+		_ = unsafe.Pointer(uintptr(unsafe.Pointer(&b[0])) + 32)
+	case "misaligned":
+		b := make([]byte, 64)
+		sink = b
+		_ = (**int)(unsafe.Pointer(&b[1]))
+	case "straddle":
+		x := new([16]byte)
+		sink = x
+		_ = (*[32]byte)(unsafe.Pointer(x))
+	case "arith":
+		x := new([16]byte)
+		sink = x
+		_ = unsafe.Pointer(uintptr(unsafe.Pointer(x)) + 16)
+	}
+}
+
+func main() {
+	if len(os.Args) > 1 {
+		child(os.Args[1])
+		return
+	}
+
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"ok", ""},
+		{"misaligned", "checkptr: misaligned pointer conversion"},
+		{"straddle", "checkptr: converted pointer straddles multiple allocations"},
+		{"arith", "checkptr: pointer arithmetic result points to invalid allocation"},
+	}
+	failed := false
+	for _, test := range tests {
+		out, err := exec.Command(os.Args[0], test.name).CombinedOutput()
+		if test.want == "" {
+			if err != nil {
+				fmt.Printf("%s: unexpected failure: %v\n%s", test.name, err, out)
+				failed = true
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(string(out), test.want) {
+			fmt.Printf("%s: want %q, got %v\n%s", test.name, test.want, err, out)
+			failed = true
+		}
+	}
+	if failed {
+		panic("failed")
+	}
+}
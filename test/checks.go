@@ -0,0 +1,73 @@
+// run -gcflags=-d=checks=2
+
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Test that -d=checks accepts correct code, reports negative make
+// lengths, and warns once about a map grown during its own range.
+// Each case runs in a child process, since a failed check throws.
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+var sink []byte
+
+func grow(m map[int]int) {
+	for k := range m {
+		m[k+100] = k
+	}
+}
+
+func update(m map[int]int) {
+	for k := range m {
+		m[k] = 0
+	}
+}
+
+func child(name string) {
+	m := map[int]int{1: 1, 2: 2}
+	update(m)
+
+	n := 1 - len(os.Args)
+	switch name {
+	case "make":
+		sink = make([]byte, n)
+	case "map":
+		grow(m)
+	}
+}
+
+func main() {
+	if len(os.Args) > 1 {
+		child(os.Args[1])
+		return
+	}
+
+	out, err := exec.Command(os.Args[0], "none").CombinedOutput()
+	if err != nil || len(out) != 0 {
+		fmt.Printf("none: got %v, want success and no output:\n%s", err, out)
+		panic("failed")
+	}
+
+	const msg = "checks: negative length or capacity passed to make"
+	out, err = exec.Command(os.Args[0], "make").CombinedOutput()
+	if err == nil || !strings.Contains(string(out), msg) {
+		fmt.Printf("make: got %v, want failure with %q:\n%s", err, msg, out)
+		panic("failed")
+	}
+
+	// Growing a map while ranging over it is legal.
+	const warning = "checks.go:23: warning: checks: map grown during its own range loop\n"
+	out, err = exec.Command(os.Args[0], "map").CombinedOutput()
+	if err != nil || strings.Count(string(out), warning) != 1 {
+		fmt.Printf("map: got %v, want success with one %q:\n%s", err, warning, out)
+		panic("failed")
+	}
+}
@@ -0,0 +1,38 @@
+// skip
+
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Test the -d=checks slice checks, which only fail under -B.
+// This test is run by checkslice_run.go.
+
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+var (
+	s     = make([]int, 5, 10)
+	str   = "hello"
+	sink  []int
+	ssink string
+)
+
+func main() {
+	i := len(os.Args[1])
+	switch os.Args[1] {
+	case "none":
+		sink = s[1:3][:4:5]
+		ssink = str[1:3]
+	case "cap":
+		sink = s[1:3][:i+1 : 3]
+	case "len":
+		sink = s[i:1]
+	case "string":
+		ssink = str[i:2]
+	}
+	fmt.Println(len(sink), cap(sink), len(ssink))
+}
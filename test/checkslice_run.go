@@ -0,0 +1,42 @@
+// +build !nacl
+// run
+
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Run the checkslice test, which needs -B.
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+func run(arg string) (string, error) {
+	out, err := exec.Command("go", "run", "-gcflags=-B -d=checks=1", "checkslice.go", arg).CombinedOutput()
+	return string(out), err
+}
+
+func main() {
+	out, err := run("none")
+	if err != nil || out != "4 5 2\n" {
+		fmt.Printf("none: got %v, want success with %q:\n%s", err, "4 5 2\n", out)
+		os.Exit(1)
+	}
+
+	for _, c := range []struct{ arg, msg string }{
+		{"cap", "checks: slice length exceeds capacity or is negative"},
+		{"len", "checks: slice length exceeds capacity or is negative"},
+		{"string", "checks: negative string length"},
+	} {
+		out, err := run(c.arg)
+		if err == nil || !strings.Contains(out, c.msg) {
+			fmt.Printf("%s: got %v, want failure with %q:\n%s", c.arg, err, c.msg, out)
+			os.Exit(1)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+// errorcheck -0 -d=capture
+
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Test the -d=capture explanations of how closures
+// capture variables.
+
+package p
+
+func f(n int) (r int) {
+	a := 0
+	b := 0
+	c := 0
+	var big [200]byte
+	for i := 0; i < n; i++ {
+		go func() {
+			println(i, a, b, c, big[0]) // ERROR "i captured by ref \(assigned at closure3.go:17\)" "a captured by value" "b captured by ref \(assigned after capture at closure3.go:20\)" "c captured by ref \(address taken at closure3.go:24\)" "big captured by ref \(200 bytes is too large to copy\)"
+			b++
+			r = 1 // ERROR "r captured by ref \(result parameter\)"
+		}()
+	}
+	p := &c
+	_ = p
+	return
+}
+
+func g() {
+	x := 0
+	x = 1 // assigned before the capture: still by value
+	func() {
+		println(x) // ERROR "x captured by value"
+	}()
+}
+
+// Assignments that cannot happen after the capture
+// do not force capture by reference.
+func h(xs []int, n int) {
+	x := 0
+	if n > 0 {
+		x = n
+	}
+	for i := 0; i < n; i++ {
+		go func() {
+			println(x) // ERROR "x captured by value"
+		}()
+	}
+
+	y := 0
+	for i := 0; i < n; i++ {
+		y = i
+		go func() {
+			println(y) // ERROR "y captured by ref \(assigned at closure3.go:52\)"
+		}()
+	}
+
+	z := 0
+	for range xs {
+		z++
+	}
+	for k, v := range xs {
+		go func() {
+			println(k, v, z) // ERROR "k captured by ref" "v captured by ref" "z captured by value"
+		}()
+	}
+
+	u := 0
+	u = 2
+L:
+	go func() {
+		println(u) // ERROR "u captured by value"
+	}()
+	w := 0
+M:
+	w++
+	go func() {
+		println(w) // ERROR "w captured by ref \(assigned at closure3.go:76\)"
+	}()
+	if n > 1 {
+		goto L
+	}
+	if n > 2 {
+		goto M
+	}
+}
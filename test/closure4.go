@@ -0,0 +1,108 @@
+// run
+
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Test closures that are only called through a local variable,
+// which the compiler calls directly, passing captured variables
+// as hidden arguments.
+
+package main
+
+func sum(xs []int) int {
+	total := 0
+	scale := 2
+	add := func(x int) {
+		total += x * scale
+	}
+	for _, x := range xs {
+		add(x)
+	}
+	add(1)
+	return total
+}
+
+func nested() int {
+	n := 0
+	inc := func(d int) int {
+		n += d
+		get := func() int { return n }
+		return get()
+	}
+	inc(1)
+	return inc(inc(2))
+}
+
+func loop() (s string) {
+	for i := 0; i < 3; i++ {
+		c := byte('a' + i)
+		app := func(k int) {
+			for j := 0; j < k; j++ {
+				s += string(c)
+			}
+		}
+		app(i)
+		app(1)
+	}
+	return
+}
+
+// x is assigned before the closure only, so it is captured by value.
+func assignedBefore() int {
+	x := 1
+	x = 10
+	get := func() int { return x }
+	return get() + get()
+}
+
+// v is captured by value; i and x, assigned again on the next
+// iteration, by reference.
+func capturedInLoop() (sum int) {
+	x := 0
+	for i := 1; i <= 3; i++ {
+		v := i * i
+		x = i
+		get := func() int { return v + i*x }
+		sum += get()
+	}
+	return
+}
+
+func deferred() (n int) {
+	f := func() { n++ }
+	defer f()
+	f()
+	return
+}
+
+func escapes() func() int {
+	n := 0
+	f := func() int { n++; return n }
+	f()
+	return f
+}
+
+func main() {
+	if got := sum([]int{1, 2, 3}); got != 14 {
+		panic(got)
+	}
+	if got := nested(); got != 6 {
+		panic(got)
+	}
+	if got := loop(); got != "abbccc" {
+		panic(got)
+	}
+	if got := assignedBefore(); got != 20 {
+		panic(got)
+	}
+	if got := capturedInLoop(); got != 28 {
+		panic(got)
+	}
+	if got := deferred(); got != 2 {
+		panic(got)
+	}
+	if got := escapes()(); got != 2 {
+		panic(got)
+	}
+}
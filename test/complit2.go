@@ -0,0 +1,40 @@
+// errorcheck
+
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Verify that errors about array literal indexes
+// are reported at the offending element.
+
+package main
+
+const big = 1 << 40
+
+var _ = [3]int{
+	1,
+	2,
+	0: 3, // ERROR "duplicate index 0 in array literal|previous element at LINE-3"
+	4,    // ERROR "duplicate index 1 in array literal|previous element at LINE-3"
+	5,
+	6, // ERROR "array index 3 out of bounds \[0:3\]"
+	7,
+}
+
+var _ = []int{
+	5: 1,
+	2,
+	6: // ERROR "duplicate index 6 in array literal|previous element at LINE-1"
+	3,
+}
+
+var _ = [...]int{
+	1:
+	0,
+	big: 0, // ERROR "array index 1099511627776 too large"
+	-1: 0,  // ERROR "index must be non-negative integer constant"
+}
+
+var _ = [10]int{
+	1 << 40: 0, // ERROR "array index 1099511627776 out of bounds \[0:10\]"
+}
@@ -16,6 +16,7 @@ const (
 
 const LargeA = 1000000000000000000
 const LargeB = LargeA * LargeA * LargeA
-const LargeC = LargeB * LargeB * LargeB // GC_ERROR "constant multiplication overflow"
+const LargeC = LargeB * LargeB * LargeB
+const LargeD = LargeC * LargeC * LargeC * LargeC // GC_ERROR "constant multiplication overflow: LargeC \* LargeC \* LargeC \* LargeC needs 2153 bits"
 
-const AlsoLargeA = LargeA << 400 << 400 >> 400 >> 400 // GC_ERROR "constant shift overflow"
+const AlsoLargeA = LargeA << 1000 << 1000 >> 1000 >> 1000 // GC_ERROR "constant shift overflow: LargeA << 1000 << 1000 needs 2060 bits"
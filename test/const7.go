@@ -0,0 +1,87 @@
+// run
+
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Test that len and cap fold to constants in all the cases
+// the spec allows, so that they can size arrays.
+
+package main
+
+type S struct {
+	a [4]int
+	p *[5]int
+	s struct{ b [6]byte }
+}
+
+type T string
+
+var (
+	s   S
+	ps  *S
+	arr [3][7]int
+	pa  *[8]int
+	m   map[string][9]int
+	sl  [][10]int
+)
+
+const str = "hello"
+
+const (
+	c1  = len(str)
+	c2  = len("a" + "bc")
+	c3  = len(T("abcd"))
+	c4  = len(s.a)
+	c5  = len(s.p)
+	c6  = cap(s.p)
+	c7  = len(ps.s.b)
+	c8  = len(arr[0])
+	c9  = len(pa)
+	c10 = cap(*pa)
+	c11 = len(m[str])
+	c12 = len(sl[len(str)])
+	c13 = len(arr[len(s.a)-2])
+	c14 = len([len(str)]int{})
+	c15 = len(([4]int)(s.a))
+	c16 = len(S{}.a)
+)
+
+// The constants must be usable as array sizes.
+var (
+	a1  [c1]int
+	a2  [c2]int
+	a3  [c3]int
+	a4  [c4]int
+	a5  [c5]int
+	a6  [c6]int
+	a7  [c7]int
+	a8  [c8]int
+	a9  [c9]int
+	a10 [c10]int
+	a11 [c11]int
+	a12 [c12]int
+	a13 [c13]int
+	a14 [c14]int
+	a15 [c15]int
+	a16 [c16]int
+)
+
+func local() int {
+	var a [11]int
+	p := &a
+	const n = len(a) + cap(p)
+	var b [n]int
+	return len(b)
+}
+
+func main() {
+	got := []int{len(a1), len(a2), len(a3), len(a4), len(a5), len(a6), len(a7), len(a8), len(a9), len(a10), len(a11), len(a12), len(a13), len(a14), len(a15), len(a16), local()}
+	want := []int{5, 3, 4, 4, 5, 5, 6, 7, 8, 8, 9, 10, 7, 5, 4, 4, 22}
+	for i := range want {
+		if got[i] != want[i] {
+			println("BUG: case", i+1, "got", got[i], "want", want[i])
+			panic("fail")
+		}
+	}
+}
@@ -0,0 +1,37 @@
+// errorcheck
+
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Verify that errors about untyped constants that overflow
+// their implied type say where the type came from.
+// Does not compile.
+
+package main
+
+type T struct {
+	X int8
+	Y float32
+}
+
+var (
+	t1 = T{X: 300}                // ERROR "constant 300 overflows int8 in field value X \(X int8 declared at .*constoverflow.go:14\)"
+	t2 = T{1, 1e100}              // ERROR "constant 1e\+100 overflows float32 in field value Y \(Y float32 declared at .*constoverflow.go:15\)"
+	t3 = []int8{1000}             // ERROR "constant 1000 overflows int8 in array or slice literal"
+	t4 = map[uint8]bool{-1: true} // ERROR "constant -1 overflows uint8 in map key"
+)
+
+var y int8
+
+const c uint16 = 1 << 16 // ERROR "constant 65536 overflows uint16 in const initializer for c"
+
+func f(uint8)
+
+func g() int8 {
+	y = 200                 // ERROR "constant 200 overflows int8 in assignment to y \(y int8 declared at .*constoverflow.go:25\)"
+	var z, w uint8 = 1, 256 // ERROR "constant 256 overflows uint8 in assignment to w \(w uint8 declared at .*constoverflow.go:33\)"
+	_, _ = z, w
+	f(-2)      // ERROR "constant -2 overflows uint8 in argument to f"
+	return 128 // ERROR "constant 128 overflows int8 in return argument"
+}
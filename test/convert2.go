@@ -0,0 +1,62 @@
+// errorcheck
+
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Verify that struct types differing only in their tags
+// are convertible but not assignable.
+// Does not compile.
+
+package main
+
+type S1 struct {
+	x int
+}
+
+type S2 struct {
+	x int `tag:"x"`
+}
+
+type S3 struct {
+	y int
+}
+
+func f() {
+	var s1 S1
+	var s2 S2
+	var s3 S3
+	s1 = S1(s2)
+	s2 = S2(s1)
+	s1 = S1(s3) // ERROR "cannot convert"
+	_ = (*S1)(&s2)
+	_ = (*S2)(&s3) // ERROR "cannot convert"
+
+	var n1 struct{ x int }
+	var n2 struct {
+		x int `tag:"x"`
+	}
+	n1 = n2 // ERROR "struct tags differ, need conversion"
+	n1 = struct{ x int }(n2)
+	n2 = struct {
+		x int `tag:"x"`
+	}(n1)
+
+	var p1 *struct{ x int }
+	var p2 *struct {
+		x int `tag:"x"`
+	}
+	p1 = p2 // ERROR "struct tags differ, need conversion"
+	p1 = (*struct{ x int })(p2)
+	_, _, _, _ = s1, s2, n1, p1
+
+	_ = []struct{ x int }([]struct {
+		x int `tag:"x"`
+	}(nil))
+	_ = []struct{ x int }([]struct{ y int }(nil)) // ERROR "cannot convert"
+
+	// Only the tags are ignored: a named element type
+	// is still not identical to an unnamed one.
+	_ = []struct{ x int }([]S2(nil)) // ERROR "cannot convert"
+	_ = []S1([]S2(nil))              // ERROR "cannot convert"
+}
@@ -0,0 +1,48 @@
+// errorcheck
+
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Verify that invalid conversion errors show the underlying
+// types and where they differ.
+// Does not compile.
+
+package main
+
+type A struct {
+	x int
+	y []struct{ z string }
+}
+
+type B struct {
+	x int
+	y []struct{ z int }
+}
+
+type C struct{ x, w int }
+
+type E [3]int
+
+type G chan<- int
+
+type H func(int, ...string) error
+
+type K func(int, string) error
+
+type L map[string]int
+
+type P *A
+
+func f(a A, c C, e E, g G, h H, l L, p P, s string) {
+	_ = B(a)                  // ERROR "A has underlying type struct { x int; y \[\]struct { z string } }\n\tB has underlying type struct { x int; y \[\]struct { z int } }\n\ttypes differ in field y, element type, field z: string vs int$"
+	_ = struct{ x int }(c)    // ERROR "types differ in number of fields: 2 vs 1$"
+	_ = struct{ x, W int }(c) // ERROR "types differ in name of field 2: w vs W$"
+	_ = []int(e)              // ERROR "types differ in slice or array: \[3\]int vs \[\]int$"
+	_ = [4]int(e)             // ERROR "E has underlying type \[3\]int\n\ttypes differ in array length: 3 vs 4$"
+	_ = (chan int)(g)         // ERROR "types differ in channel direction: chan<- int vs chan int$"
+	_ = K(h)                  // ERROR "types differ in parameter 2: variadic in only one$"
+	_ = map[int]int(l)        // ERROR "types differ in key type: string vs int$"
+	_ = (*B)(p)               // ERROR "P has underlying type \*A\n\ttypes differ in pointer base type: A vs B$"
+	_ = []int64(s)            // ERROR "cannot convert s \(type string\) to type \[\]int64$"
+}
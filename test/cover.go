@@ -0,0 +1,54 @@
+// run -gcflags=-cover
+
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Test that -cover adds a block table to the binary
+// and keeps the original line numbers.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"runtime"
+)
+
+func f() int {
+	for i := 0; i < 3; i++ {
+		if i == 1 {
+			continue
+		}
+	}
+	_, _, line, _ := runtime.Caller(0)
+	return line
+}
+
+func main() {
+	if line := f(); line != 26 {
+		fmt.Printf("runtime.Caller reports line %d, want 26\n", line)
+		panic("failed")
+	}
+
+	data, err := ioutil.ReadFile(os.Args[0])
+	if err != nil {
+		panic(err)
+	}
+	// Build the blocks at run time, so that they
+	// are not in the binary as string constants.
+	for _, b := range [][3]int{
+		{21, 22, 1},
+		{22, 23, 1},
+		{23, 24, 1},
+		{26, 28, 2},
+	} {
+		block := fmt.Sprintf("cover.go:%d.1,%d.1 %d\n", b[0], b[1], b[2])
+		if !bytes.Contains(data, []byte(block)) {
+			fmt.Printf("block %q missing from binary\n", block)
+			panic("failed")
+		}
+	}
+}
@@ -0,0 +1,70 @@
+// run -gcflags=-cover
+
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Test that package testing writes a profile
+// from the tables registered by -cover.
+// The tests run in a child process, since testing.Main exits.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func f(x int) int {
+	if x > 0 {
+		return 1
+	}
+	return 0
+}
+
+func TestF(t *testing.T) { f(1) }
+
+func main() {
+	if len(os.Args) > 1 {
+		match := func(pat, str string) (bool, error) { return true, nil }
+		testing.Main(match, []testing.InternalTest{{"TestF", TestF}}, nil, nil)
+	}
+
+	dir, err := ioutil.TempDir("", "coverprofile")
+	if err != nil {
+		panic(err)
+	}
+	defer os.RemoveAll(dir)
+	profile := filepath.Join(dir, "cover.out")
+
+	out, err := exec.Command(os.Args[0], "-test.coverprofile", profile).CombinedOutput()
+	if err != nil || !strings.Contains(string(out), "coverage: ") {
+		fmt.Printf("got %v, want success with a coverage summary:\n%s", err, out)
+		panic("failed")
+	}
+
+	data, err := ioutil.ReadFile(profile)
+	if err != nil {
+		panic(err)
+	}
+	if !bytes.HasPrefix(data, []byte("mode: count\n")) {
+		fmt.Printf("profile does not start with mode line:\n%s", data)
+		panic("failed")
+	}
+	for _, block := range []string{
+		"coverprofile.go:25.1,26.1 1 1\n",
+		"coverprofile.go:26.1,27.1 1 1\n",
+		"coverprofile.go:28.1,29.1 1 0\n",
+	} {
+		if !bytes.Contains(data, []byte(block)) {
+			fmt.Printf("block %q missing from profile:\n%s", block, data)
+			panic("failed")
+		}
+	}
+}
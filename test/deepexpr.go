@@ -0,0 +1,59 @@
+// runoutput
+
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Check that the compiler handles binary expressions
+// nested many thousands deep, as generated code writes them.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const nterm = 10000
+
+func main() {
+	var str, sum, and bytes.Buffer
+
+	str.WriteString("s")
+	sum.WriteString("x")
+	and.WriteString("b")
+	for i := 1; i < nterm; i++ {
+		str.WriteString(" + s")
+		sum.WriteString(" + x")
+		and.WriteString(" && b")
+	}
+
+	program = strings.Replace(program, "$N", strconv.Itoa(nterm), -1)
+	program = strings.Replace(program, "$STR", str.String(), 1)
+	program = strings.Replace(program, "$SUM", sum.String(), 1)
+	program = strings.Replace(program, "$AND", and.String(), 1)
+	fmt.Print(program)
+}
+
+var program = `package main
+
+var (
+	s = "a"
+	x = 1
+	b = true
+)
+
+func main() {
+	if str := $STR; len(str) != $N {
+		println("len(str) =", len(str), "want $N")
+	}
+	if sum := $SUM; sum != $N {
+		println("sum =", sum, "want $N")
+	}
+	if !($AND) {
+		println("and = false, want true")
+	}
+}
+`
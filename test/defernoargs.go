@@ -0,0 +1,79 @@
+// run
+
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Test deferred calls without arguments or receiver,
+// which use a cheaper runtime entry point than other defers.
+
+package main
+
+var log string
+
+func a() { log += "a" }
+func b() { log += "b" }
+
+func order() {
+	f := b
+	defer a()
+	defer f()
+	defer func() { log += "c" }()
+}
+
+func recovers() (ok bool) {
+	defer func() {
+		ok = recover() == "boom"
+	}()
+	defer a()
+	panic("boom")
+}
+
+func repanic() (ok bool) {
+	defer func() {
+		ok = recover() == "again"
+	}()
+	defer func() {
+		if recover() == "first" {
+			panic("again")
+		}
+	}()
+	panic("first")
+}
+
+func loop(n int) {
+	for i := 0; i < n; i++ {
+		defer b()
+	}
+}
+
+func nilfunc() (ok bool) {
+	defer func() {
+		ok = recover() != nil
+	}()
+	var f func()
+	defer f()
+	return false
+}
+
+func main() {
+	order()
+	if log != "cba" {
+		panic("order: " + log)
+	}
+	log = ""
+	if !recovers() || log != "a" {
+		panic("recovers: " + log)
+	}
+	if !repanic() {
+		panic("repanic")
+	}
+	log = ""
+	loop(3)
+	if log != "bbb" {
+		panic("loop: " + log)
+	}
+	if !nilfunc() {
+		panic("nilfunc")
+	}
+}
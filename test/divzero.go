@@ -0,0 +1,34 @@
+// errorcheck
+
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Verify that constant division by zero and oversized shifts are
+// diagnosed wherever they appear, naming the offending expression,
+// and without follow-on errors.
+// Does not compile.
+
+package p
+
+const zero = 0
+
+var a1 [4 / zero]int   // ERROR "division by zero in 4 / zero"
+var a2 [4 % zero]int   // ERROR "division by zero in 4 % zero"
+var a3 [1 << 10000]int // ERROR "shift count too large"
+
+var (
+	b1 = [...]int{1 / zero: 1}           // ERROR "division by zero in 1 / zero"
+	b2 = [3]int{(1 + 2) / (zero * 3): 1} // ERROR "division by zero in \(1 \+ 2\) / \(zero \* 3\)"
+	b3 = []int{2, 4 / zero}              // ERROR "division by zero in 4 / zero"
+	b4 = struct{ x int }{x: 1 / zero}    // ERROR "division by zero in 1 / zero"
+	b5 = map[int]int{1 / zero: 2}        // ERROR "division by zero in 1 / zero"
+	b6 = 1.5 / 0.0                       // ERROR "division by zero in 1.5 / 0"
+	b7 = 1i / 0                          // ERROR "division by zero in 1i / 0"
+)
+
+func f(x int) int { return x / zero } // ERROR "division by zero in x / zero"
+
+func g() [2 / zero]int { // ERROR "division by zero in 2 / zero"
+	return g()
+}
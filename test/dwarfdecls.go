@@ -0,0 +1,108 @@
+// +build !darwin,!windows,!plan9
+// run -gcflags=-dwarfdecls=exported -ldflags=-w=0
+
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Test that -dwarfdecls describes unused types and constants,
+// by reading the DWARF of the test's own binary.
+
+package main
+
+import (
+	"debug/dwarf"
+	"debug/elf"
+	"fmt"
+	"os"
+	"strconv"
+)
+
+type Unused struct{ X, Y int }
+type unused struct{ x int }
+
+type Color int
+
+const (
+	Red Color = iota
+	Green
+)
+
+const Big = 1 << 40
+const Max uint64 = 1<<64 - 1
+const small = -7
+const Name = "not an integer"
+
+var failed bool
+
+func errorf(format string, args ...interface{}) {
+	fmt.Printf(format+"\n", args...)
+	failed = true
+}
+
+func main() {
+	f, err := elf.Open(os.Args[0])
+	if err != nil {
+		panic(err)
+	}
+	defer f.Close()
+	dw, err := f.DWARF()
+	if err != nil {
+		panic(err)
+	}
+
+	consts := make(map[string]int64)
+	types := make(map[string]bool)
+	r := dw.Reader()
+	for {
+		e, err := r.Next()
+		if err != nil {
+			panic(err)
+		}
+		if e == nil {
+			break
+		}
+		name, _ := e.Val(dwarf.AttrName).(string)
+		switch e.Tag {
+		case dwarf.TagConstant:
+			consts[name], _ = e.Val(dwarf.AttrConstValue).(int64)
+		case dwarf.TagTypedef, dwarf.TagBaseType:
+			types[name] = true
+		}
+	}
+
+	want := map[string]int64{"main.Red": 0, "main.Green": 1}
+	absent := []string{"main.small", "main.Name"}
+	// Untyped constants get type int, which Big overflows
+	// on 32-bit systems.
+	if strconv.IntSize == 64 {
+		want["main.Big"] = 1 << 40
+	} else {
+		absent = append(absent, "main.Big")
+	}
+	for name, v := range want {
+		if got, ok := consts[name]; !ok || got != v {
+			errorf("constant %s = %d, %v; want %d", name, got, ok, v)
+		}
+	}
+	// debug/dwarf reads unsigned values as int64s too.
+	if got, ok := consts["main.Max"]; !ok || uint64(got) != 1<<64-1 {
+		errorf("constant main.Max = %d, %v; want %d", uint64(got), ok, uint64(1<<64-1))
+	}
+	for _, name := range absent {
+		if _, ok := consts[name]; ok {
+			errorf("unexpected constant %s", name)
+		}
+	}
+	for _, name := range []string{"main.Unused", "main.Color"} {
+		if !types[name] {
+			errorf("missing type %s", name)
+		}
+	}
+	if types["main.unused"] {
+		errorf("unexpected type main.unused")
+	}
+	if failed {
+		panic("failed")
+	}
+}
@@ -0,0 +1,98 @@
+// +build !darwin,!windows,!plan9
+// run -ldflags=-w=0
+
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Test that the DWARF entries of Go types point to runtime type
+// descriptors of the same kind, by reading the test's own binary.
+
+package main
+
+import (
+	"debug/dwarf"
+	"debug/elf"
+	"fmt"
+	"os"
+)
+
+type T struct {
+	n int
+	s string
+}
+
+var (
+	t T
+	m map[string][]int
+	c chan *T
+)
+
+var sink []interface{}
+
+func main() {
+	sink = []interface{}{&t, m, c}
+
+	f, err := elf.Open(os.Args[0])
+	if err != nil {
+		panic(err)
+	}
+	defer f.Close()
+	dw, err := f.DWARF()
+	if err != nil {
+		panic(err)
+	}
+	ptrsize := uint64(4)
+	if f.Class == elf.ELFCLASS64 {
+		ptrsize = 8
+	}
+
+	// The kind recorded for each type must match the kind
+	// in the runtime type descriptor it points to.
+	const (
+		attrGoKind        = dwarf.Attr(0x2900)
+		attrGoRuntimeType = dwarf.Attr(0x2903)
+	)
+	failed := false
+	want := map[string]bool{"main.T": true, "map[string][]int": true, "chan *main.T": true, "[]int": true}
+	r := dw.Reader()
+	for {
+		e, err := r.Next()
+		if err != nil {
+			panic(err)
+		}
+		if e == nil {
+			break
+		}
+		name, _ := e.Val(dwarf.AttrName).(string)
+		addr, ok := e.Val(attrGoRuntimeType).(uint64)
+		if !want[name] || !ok {
+			continue
+		}
+		delete(want, name)
+		kind, _ := e.Val(attrGoKind).(int64)
+		var b [1]byte
+		found := false
+		for _, s := range f.Sections {
+			if s.Type == elf.SHT_PROGBITS && addr >= s.Addr && addr < s.Addr+s.Size {
+				_, err := s.ReadAt(b[:], int64(addr-s.Addr+2*ptrsize+7))
+				found = err == nil
+				break
+			}
+		}
+		if !found {
+			fmt.Printf("%s: runtime type %#x not found in the binary\n", name, addr)
+			failed = true
+		} else if int64(b[0]&0x1f) != kind {
+			fmt.Printf("%s: runtime type has kind %d, DWARF says %d\n", name, b[0]&0x1f, kind)
+			failed = true
+		}
+	}
+	for name := range want {
+		fmt.Printf("no runtime type recorded for %s\n", name)
+		failed = true
+	}
+	if failed {
+		panic("failed")
+	}
+}
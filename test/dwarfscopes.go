@@ -0,0 +1,129 @@
+// +build !darwin,!windows,!plan9
+// run -ldflags=-w=0
+
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Test that shadowed and block-scoped locals are described in nested
+// DW_TAG_lexical_block entries, by reading the DWARF of the test's
+// own binary.
+
+package main
+
+import (
+	"debug/dwarf"
+	"debug/elf"
+	"fmt"
+	"os"
+)
+
+//go:noinline
+func g(p *int) { *p++ }
+
+func f(n int) int {
+	x := 1
+	g(&x)
+	for i := 0; i < n; i++ {
+		x := i
+		g(&x)
+		if y := x; y > 2 {
+			z := y
+			g(&z)
+		}
+	}
+	{
+		w := n
+		g(&w)
+	}
+	return x
+}
+
+var failed bool
+
+func errorf(format string, args ...interface{}) {
+	fmt.Printf(format+"\n", args...)
+	failed = true
+}
+
+func main() {
+	f(5)
+
+	ef, err := elf.Open(os.Args[0])
+	if err != nil {
+		panic(err)
+	}
+	defer ef.Close()
+	dw, err := ef.DWARF()
+	if err != nil {
+		panic(err)
+	}
+
+	// Record the lexical block depth of each variable in main.f.
+	depths := make(map[string]map[int]bool)
+	r := dw.Reader()
+	var lowpc, highpc uint64
+	depth, infunc := 0, false
+	for {
+		e, err := r.Next()
+		if err != nil {
+			panic(err)
+		}
+		if e == nil {
+			break
+		}
+		if e.Tag == 0 {
+			depth--
+			continue
+		}
+		name, _ := e.Val(dwarf.AttrName).(string)
+		switch e.Tag {
+		case dwarf.TagSubprogram:
+			infunc = name == "main.f"
+			lowpc, _ = e.Val(dwarf.AttrLowpc).(uint64)
+			highpc, _ = e.Val(dwarf.AttrHighpc).(uint64)
+			depth = -1
+		case dwarf.TagLexDwarfBlock:
+			if !infunc {
+				break
+			}
+			lo, _ := e.Val(dwarf.AttrLowpc).(uint64)
+			hi, _ := e.Val(dwarf.AttrHighpc).(uint64)
+			if lo < lowpc || hi > highpc || lo >= hi {
+				errorf("lexical block [%#x, %#x) outside main.f [%#x, %#x)", lo, hi, lowpc, highpc)
+			}
+		case dwarf.TagVariable:
+			if infunc {
+				if depths[name] == nil {
+					depths[name] = make(map[int]bool)
+				}
+				depths[name][depth] = true
+			}
+		}
+		if e.Children {
+			depth++
+		}
+	}
+
+	want := map[string][]int{
+		"x": {0, 2},
+		"i": {1},
+		"z": {4},
+		"w": {1},
+	}
+	for name, ds := range want {
+		if len(depths[name]) != len(ds) {
+			errorf("%s at depths %v, want %v", name, depths[name], ds)
+			continue
+		}
+		for _, d := range ds {
+			if !depths[name][d] {
+				errorf("%s at depths %v, want %v", name, depths[name], ds)
+				break
+			}
+		}
+	}
+	if failed {
+		panic("failed")
+	}
+}
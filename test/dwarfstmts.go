@@ -0,0 +1,111 @@
+// +build !darwin,!windows,!plan9
+// run -ldflags=-w=0
+
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Test that the line table marks the first instruction of each
+// statement in main.f, once per line, and only those, by reading
+// the DWARF of the test's own binary.
+
+package main
+
+import (
+	"debug/dwarf"
+	"debug/elf"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+//go:noinline
+func g(a, b int) int { return a + b }
+
+func f(n int) int {
+	s := 0
+	for i := 0; i < n; i++ {
+		s += g(i,
+			n)
+	}
+	return s
+}
+
+func main() {
+	f(3)
+
+	ef, err := elf.Open(os.Args[0])
+	if err != nil {
+		panic(err)
+	}
+	defer ef.Close()
+	dw, err := ef.DWARF()
+	if err != nil {
+		panic(err)
+	}
+
+	// Find main.f and its compilation unit.
+	var cu *dwarf.Entry
+	var lowpc, highpc uint64
+	r := dw.Reader()
+	for {
+		e, err := r.Next()
+		if err != nil {
+			panic(err)
+		}
+		if e == nil {
+			break
+		}
+		if e.Tag == dwarf.TagCompileUnit {
+			cu = e
+			continue
+		}
+		if e.Tag == dwarf.TagSubprogram && e.Val(dwarf.AttrName) == "main.f" {
+			lowpc, _ = e.Val(dwarf.AttrLowpc).(uint64)
+			highpc, _ = e.Val(dwarf.AttrHighpc).(uint64)
+			break
+		}
+	}
+	if cu == nil || lowpc == 0 {
+		panic("main.f not found")
+	}
+
+	// Count the statement starts on each line of main.f.
+	lr, err := dw.LineReader(cu)
+	if err != nil {
+		panic(err)
+	}
+	stmts := make(map[int]int)
+	others := 0
+	var le dwarf.LineEntry
+	for lr.Next(&le) == nil {
+		if le.Address < lowpc || le.Address >= highpc || filepath.Base(le.File.Name) != "dwarfstmts.go" {
+			continue
+		}
+		if le.IsStmt {
+			stmts[le.Line]++
+		} else {
+			others++
+		}
+	}
+	failed := false
+	for _, line := range []int{26, 27, 31} {
+		if stmts[line] != 1 {
+			fmt.Printf("line %d has %d statement starts, want 1\n", line, stmts[line])
+			failed = true
+		}
+	}
+	for line, n := range stmts {
+		if n > 1 {
+			fmt.Printf("line %d has %d statement starts, want at most 1\n", line, n)
+			failed = true
+		}
+	}
+	if others == 0 {
+		fmt.Printf("every row of main.f is a statement start\n")
+		failed = true
+	}
+	if failed {
+		panic("failed")
+	}
+}
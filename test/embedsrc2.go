@@ -0,0 +1,71 @@
+// +build !darwin,!windows,!plan9
+// run -gcflags=-embedsrc=content -ldflags=-w=0
+
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Test that -embedsrc=content records the hash of the source file
+// followed by its compressed text in the test's own binary.
+
+package main
+
+import (
+	"bytes"
+	"compress/zlib"
+	"crypto/sha256"
+	"debug/elf"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+func main() {
+	f, err := elf.Open(os.Args[0])
+	if err != nil {
+		panic(err)
+	}
+	defer f.Close()
+	syms, err := f.Symbols()
+	if err != nil {
+		panic(err)
+	}
+	var src string
+	var data []byte
+	for _, s := range syms {
+		if strings.HasPrefix(s.Name, "go.srcfile.") && filepath.Base(s.Name) == "embedsrc2.go" && int(s.Section) < len(f.Sections) {
+			src = strings.TrimPrefix(s.Name, "go.srcfile.")
+			sect := f.Sections[s.Section]
+			data = make([]byte, s.Size)
+			if _, err := sect.ReadAt(data, int64(s.Value-sect.Addr)); err != nil {
+				panic(err)
+			}
+		}
+	}
+	if src == "" {
+		panic("no go.srcfile symbol for embedsrc2.go")
+	}
+
+	// The linker writes paths in $GOROOT relative to it.
+	text, err := ioutil.ReadFile(strings.Replace(src, "$GOROOT", runtime.GOROOT(), 1))
+	if err != nil {
+		panic(err)
+	}
+	hash := sha256.Sum256(text)
+	if len(data) < len(hash) || !bytes.Equal(data[:len(hash)], hash[:]) {
+		fmt.Printf("no hash of %s recorded\n", src)
+		panic("failed")
+	}
+	zr, err := zlib.NewReader(bytes.NewReader(data[len(hash):]))
+	if err != nil {
+		panic(err)
+	}
+	got, err := ioutil.ReadAll(zr)
+	if err != nil || !bytes.Equal(got, text) {
+		fmt.Printf("recorded %q, %v; want the text of %s\n", got, err, src)
+		panic("failed")
+	}
+}
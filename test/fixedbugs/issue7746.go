@@ -10,9 +10,9 @@ const (
 	c0   = 1 << 100
 	c1   = c0 * c0
 	c2   = c1 * c1
-	c3   = c2 * c2 // ERROR "overflow"
+	c3   = c2 * c2
 	c4   = c3 * c3
-	c5   = c4 * c4
+	c5   = c4 * c4 // ERROR "overflow"
 	c6   = c5 * c5
 	c7   = c6 * c6
 	c8   = c7 * c7
@@ -39,7 +39,7 @@ L:
 
 // goto across declaration not okay
 func _() {
-	goto L // ERROR "goto L jumps over declaration of x at LINE+1|goto jumps over declaration"
+	goto L // ERROR "goto L jumps over declaration of x at LINE+1\n\tlabel L defined at LINE+3|goto jumps over declaration"
 	x := 1 // GCCGO_ERROR "defined here"
 	_ = x
 L:
@@ -114,7 +114,7 @@ L:
 
 // goto into inner block not okay
 func _() {
-	goto L // ERROR "goto L jumps into block starting at LINE+1|goto jumps into block"
+	goto L // ERROR "goto L jumps into block starting at LINE+1\n\tlabel L defined at LINE+2|goto jumps into block"
 	{      // GCCGO_ERROR "block starts here"
 	L:
 	}
@@ -125,7 +125,7 @@ func _() {
 	{ // GCCGO_ERROR "block starts here"
 	L:
 	}
-	goto L // ERROR "goto L jumps into block starting at LINE-3|goto jumps into block"
+	goto L // ERROR "goto L jumps into block starting at LINE-3\n\tlabel L defined at LINE-2|goto jumps into block"
 }
 
 // error shows first (outermost) offending block
@@ -0,0 +1,52 @@
+// errorcheck
+
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Verify that errors about types that don't implement
+// an interface say why the method is missing.
+// Does not compile.
+
+package main
+
+type I interface {
+	M(int) string
+}
+
+type Ptr int
+
+func (*Ptr) M(int) string { return "" }
+
+type Sig int
+
+func (Sig) M(string) string { return "" }
+
+type Case int
+
+func (Case) m(int) string { return "" }
+
+type None int
+
+type J interface {
+	m(int) string
+}
+
+var (
+	_ I = Ptr(0)  // ERROR "M method has pointer receiver"
+	_ I = Sig(0)  // ERROR "wrong type for M method\)\n\t\thave M\(string\) string\n\t\twant M\(int\) string"
+	_ I = Case(0) // ERROR "missing M method; m differs only in case\)\n\t\thave m\(int\) string\n\t\twant M\(int\) string"
+	_ I = None(0) // ERROR "missing M method\)$"
+	_ I = J(nil)  // ERROR "missing M method; m differs only in case"
+)
+
+func f(i I, e interface{}) {
+	_ = e.(I)
+	_ = i.(Ptr)  // ERROR "impossible type assertion:\n\tPtr does not implement I \(M method has pointer receiver\)"
+	_ = i.(Case) // ERROR "impossible type assertion:\n\tCase does not implement I \(missing M method; m differs only in case\)"
+
+	switch i.(type) {
+	case Sig: // ERROR "impossible type switch case: i \(type I\) cannot have dynamic type Sig \(wrong type for M method\)"
+	case Ptr: // ERROR "impossible type switch case: i \(type I\) cannot have dynamic type Ptr \(M method has pointer receiver\)"
+	}
+}
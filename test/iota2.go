@@ -0,0 +1,94 @@
+// run
+
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Test iota in parenthesized expressions, conversions,
+// composite literal types and function literals.
+
+package main
+
+import "unsafe"
+
+func assert(cond bool, msg string) {
+	if !cond {
+		print("assertion fail: ", msg, "\n")
+		panic(1)
+	}
+}
+
+type T int
+
+const (
+	a0 = (iota)
+	a1
+	a2
+)
+
+const (
+	b0 = T((iota)) * 2
+	b1
+	b2 = T(iota) + T((iota))
+)
+
+const (
+	c0 = len([iota]int{})
+	c1
+	c2 = uintptr(len([iota]int{})) + unsafe.Sizeof(struct{ x [iota]byte }{})
+	c3 = unsafe.Sizeof([iota][iota + 1]byte{})
+)
+
+const (
+	d0, d1 = iota, (iota + 10)
+	d2, d3
+)
+
+const (
+	e0 = unsafe.Sizeof(func() [iota]byte { return [iota]byte{} }())
+	e1 = unsafe.Sizeof(func() [iota]byte { return [iota]byte{} }())
+	e2 = unsafe.Sizeof(func() [iota]byte {
+		return func() [iota]byte { return [iota]byte{} }()
+	}())
+)
+
+const (
+	g0 = unsafe.Sizeof((func() [iota]int64)(nil)())
+	g1
+	g2
+)
+
+const (
+	h0 = unsafe.Sizeof(func() {})
+	h1
+	h2 = unsafe.Sizeof(func() [iota]byte { return [iota]byte{} }())
+	h3
+	h4
+)
+
+func k() int {
+	x := 3
+	const (
+		k0 = unsafe.Sizeof(func() [iota]int { _ = x; return [iota]int{} }())
+		k1
+	)
+	return int(k0 + k1)
+}
+
+func main() {
+	assert(a0 == 0 && a1 == 1 && a2 == 2, "a")
+	assert(b0 == 0 && b1 == 2 && b2 == 4, "b")
+	assert(c0 == 0 && c1 == 1 && c2 == 4 && c3 == 12, "c")
+	assert(d0 == 0 && d1 == 10 && d2 == 1 && d3 == 11, "d")
+	assert(e0 == 0 && e1 == 1 && e2 == 2, "e")
+	assert(g0 == 0 && g1 == 8 && g2 == 16, "g")
+	assert(h0 == h1 && h2 == 2 && h3 == 3 && h4 == 4, "h")
+	assert(k() == 8, "k")
+
+	const (
+		f0 = T(iota) * 100
+		f1
+		f2 = len([iota]int{})
+	)
+	assert(f0 == 0 && f1 == 100 && f2 == 2, "f")
+}
@@ -0,0 +1,17 @@
+// errorcheck
+
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Verify that iota is defined only in constant declarations.
+// Does not compile.
+
+package p
+
+var v = func() int { return iota } // ERROR "undefined: iota"
+
+func f() {
+	const c = iota
+	var _ [iota]int // ERROR "undefined: iota"
+}
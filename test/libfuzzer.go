@@ -0,0 +1,17 @@
+// run -gcflags=-d=libfuzzer
+
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Test that a program compiled with -d=libfuzzer links and runs
+// against a runtime built without the libfuzzer tag.
+
+package main
+
+func main() {
+	s := "fuzz"
+	if len(s) == 4 && s == "fuzz" {
+		println("ok")
+	}
+}
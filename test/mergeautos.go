@@ -0,0 +1,128 @@
+// run
+
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Test that locals the compiler places in a shared stack slot
+// never clobber each other.
+
+package main
+
+import "fmt"
+
+type quad struct {
+	a, b, c, d int
+}
+
+//go:noinline
+func sum(q quad) int {
+	return q.a + q.b + q.c + q.d
+}
+
+//go:noinline
+func disjoint(x, y int) int {
+	s := 0
+	{
+		t := quad{x, y, x, y}
+		s += sum(t)
+	}
+	{
+		u := quad{y, x, 2 * y, 2 * x}
+		s += sum(u)
+	}
+	return s
+}
+
+//go:noinline
+func overlap(x, y int) int {
+	t := quad{x, y, x, y}
+	u := quad{y, x, 2 * y, 2 * x}
+	return sum(u) - sum(t)
+}
+
+//go:noinline
+func call(f func()) {
+	f()
+}
+
+//go:noinline
+func use(a, b, c uintptr) uintptr {
+	return a*100 + b*10 + c
+}
+
+//go:noinline
+func captured(x, y, z uintptr) (r uintptr) {
+	p, q, s := x+1, y+1, z+1
+	call(func() {
+		use(0, 0, 0)
+		r = use(p, q, s)
+	})
+	return
+}
+
+//go:noinline
+func loop(n int) int {
+	s := 0
+	for i := 0; i < n; i++ {
+		a := i * 3
+		s += a
+		b := i * 5
+		s += b
+		if i%2 == 0 {
+			c := float64(i) / 2
+			s += int(c)
+		}
+	}
+	return s
+}
+
+// Locals of two words that are not fat, such as complex128 and, on
+// 32-bit systems, int64, are written one word at a time. Writing one
+// word must not end the lifetime of the other.
+
+//go:noinline
+func halves(x, y int64, f, g float64) (int64, complex128) {
+	a := x<<33 | y
+	b := -a
+	a = b*3 + a
+	c := complex(f, g)
+	d := c * c
+	c = d + complex(real(c)*2, imag(d))
+	for i := int64(0); i < 3; i++ {
+		e := a*i - x
+		a += e >> 1
+		h := complex(float64(i), f) * d
+		c += h
+	}
+	return a, c
+}
+
+func main() {
+	if got, want := disjoint(1, 2), 15; got != want {
+		panic(fmt.Sprintf("disjoint = %d, want %d", got, want))
+	}
+	if got, want := overlap(1, 2), 3; got != want {
+		panic(fmt.Sprintf("overlap = %d, want %d", got, want))
+	}
+	if got, want := captured(1, 2, 3), uintptr(234); got != want {
+		panic(fmt.Sprintf("captured = %d, want %d", got, want))
+	}
+	want := 0
+	for i := 0; i < 10; i++ {
+		want += 8 * i
+		if i%2 == 0 {
+			want += i / 2
+		}
+	}
+	if got := loop(10); got != want {
+		panic(fmt.Sprintf("loop = %d, want %d", got, want))
+	}
+	a, c := halves(5, 7, 1, 2)
+	if want := int64(-257698037819); a != want {
+		panic(fmt.Sprintf("halves = %d, want %d", a, want))
+	}
+	if want := complex(-22, 11); c != want {
+		panic(fmt.Sprintf("halves = %v, want %v", c, want))
+	}
+}
@@ -0,0 +1,98 @@
+// run
+
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Test method values whose receiver is the address of a global
+// or a zero-width value, which the compiler builds from static
+// data instead of allocating.
+
+package main
+
+import "runtime"
+
+type T struct {
+	n int
+}
+
+func (t *T) Inc() int {
+	t.n++
+	return t.n
+}
+
+func (t T) Get() int { return t.n }
+
+type Z struct{}
+
+func (Z) Name() string { return "Z" }
+
+func (*Z) PtrName() string { return "*Z" }
+
+type A [0]int
+
+func (A) Len() int { return 0 }
+
+var (
+	g T
+	z Z
+)
+
+func allocs(f func()) uint64 {
+	var m1, m2 runtime.MemStats
+	f()
+	runtime.ReadMemStats(&m1)
+	for i := 0; i < 100; i++ {
+		f()
+	}
+	runtime.ReadMemStats(&m2)
+	return m2.Mallocs - m1.Mallocs
+}
+
+var sink interface{}
+
+func main() {
+	f := g.Inc
+	if f() != 1 || f() != 2 || g.n != 2 {
+		panic("g.Inc")
+	}
+	h := (&g).Inc
+	if h() != 3 || g.Inc() != 4 {
+		panic("(&g).Inc")
+	}
+
+	// A value receiver is copied when the method value is evaluated.
+	get := g.Get
+	g.n = 10
+	if get() != 4 {
+		panic("g.Get")
+	}
+
+	var local Z
+	if z.Name() != "Z" || local.Name() != "Z" || (Z{}).Name() != "Z" {
+		panic("Z.Name")
+	}
+	n1, n2, n3 := z.Name, local.Name, (Z{}).Name
+	if n1() != "Z" || n2() != "Z" || n3() != "Z" {
+		panic("Z.Name method value")
+	}
+	if p := z.PtrName; p() != "*Z" {
+		panic("z.PtrName")
+	}
+	if l := (A{}).Len; l() != 0 {
+		panic("A.Len")
+	}
+
+	if n := allocs(func() { sink = g.Inc }); n != 0 {
+		println("g.Inc allocated", n, "times")
+		panic("fail")
+	}
+	if n := allocs(func() { sink = z.Name }); n != 0 {
+		println("z.Name allocated", n, "times")
+		panic("fail")
+	}
+	if n := allocs(func() { sink = (Z{}).Name }); n != 0 {
+		println("Z{}.Name allocated", n, "times")
+		panic("fail")
+	}
+}
@@ -0,0 +1,89 @@
+// run
+
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Test that the temporaries order reuses from one statement to the
+// next, including those of the same type as the placeholders for
+// closures and map iterators, are not confused.
+
+package main
+
+import "fmt"
+
+type T struct {
+	a, b, c int
+	s       string
+}
+
+var m = map[T]uint8{{1, 2, 3, "x1"}: 4, {5, 6, 7, "x5"}: 8}
+
+//go:noinline
+func key(i int) T {
+	return T{i, i + 1, i + 2, fmt.Sprint("x", i)}
+}
+
+func f(c chan T, cu chan uint8) (sum int) {
+	// Map keys copied to temporaries in successive statements.
+	sum += int(m[key(1)])
+	sum += int(m[key(5)])
+	if v, ok := m[key(1)]; ok {
+		sum += int(v)
+	}
+
+	// A closure whose context is a temporary, next to uint8 temporaries.
+	x, y := 10, 20
+	g := func() int { return x + y }
+	sum += g()
+	sum += int(m[T{1, 2, 3, "x1"}]) + int(m[T{5, 6, 7, "x5"}])
+	h := func() int { return x * y }
+	sum += h() + g()
+	cu <- 3
+	sum += int(<-cu)
+
+	// Map iteration, whose iterator is a temporary.
+	for k, v := range m {
+		sum += k.a + int(v)
+	}
+	for k := range m {
+		sum += k.b
+	}
+	cu <- 1
+	cu <- 2
+	sum += int(<-cu) + int(<-cu)
+
+	// Channel receives into temporaries.
+	c <- key(2)
+	c <- key(3)
+	sum += (<-c).c + (<-c).c
+	select {
+	case c <- key(4):
+	}
+	select {
+	case v, ok := <-c:
+		if ok {
+			sum += v.a
+		}
+	}
+
+	// Results of ... calls.
+	sum += len(fmt.Sprint(key(7), key(8)))
+	sum += len(fmt.Sprint(key(9)))
+	return sum
+}
+
+func main() {
+	c := make(chan T, 2)
+	cu := make(chan uint8, 2)
+	want := 0
+	want += 4 + 8 + 4
+	want += 30 + 4 + 8 + 200 + 30 + 3
+	want += 1 + 4 + 5 + 8
+	want += 2 + 6 + 1 + 2
+	want += 4 + 5 + 4
+	want += len(fmt.Sprint(key(7), key(8))) + len(fmt.Sprint(key(9)))
+	if got := f(c, cu); got != want {
+		panic(fmt.Sprintf("got %d, want %d", got, want))
+	}
+}
@@ -0,0 +1,92 @@
+// run
+
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Test that element-by-element copy loops, which the compiler
+// lowers to memmove, keep their loop semantics.
+
+package main
+
+import "fmt"
+
+type point struct {
+	x, y int32
+}
+
+func copyInts(dst, src []int) (i int) {
+	i = -1
+	for i = range src {
+		dst[i] = src[i]
+	}
+	return
+}
+
+func copyPoints(dst, src []point) {
+	for i := range src {
+		dst[i] = src[i]
+	}
+}
+
+func check(what string, got, want interface{}) {
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		panic(fmt.Sprintf("%s: got %v, want %v", what, got, want))
+	}
+}
+
+func main() {
+	// Disjoint slices.
+	src := []int{1, 2, 3, 4}
+	dst := make([]int, 5)
+	i := copyInts(dst, src)
+	check("disjoint", dst, []int{1, 2, 3, 4, 0})
+	check("disjoint index", i, 3)
+
+	// Empty source leaves the index variable alone.
+	i = copyInts(dst, nil)
+	check("empty index", i, -1)
+
+	// Destination before source: same result as memmove.
+	s := []int{0, 1, 2, 3, 4}
+	copyInts(s[:4], s[1:])
+	check("overlap down", s, []int{1, 2, 3, 4, 4})
+
+	// Destination after source: the loop smears the first element.
+	s = []int{0, 1, 2, 3, 4}
+	i = copyInts(s[1:], s[:4])
+	check("overlap up", s, []int{0, 0, 0, 0, 0})
+	check("overlap up index", i, 3)
+
+	// Structs and arrays.
+	var a [3]point
+	copyPoints(a[:], []point{{1, 2}, {3, 4}, {5, 6}})
+	check("points", a, [3]point{{1, 2}, {3, 4}, {5, 6}})
+
+	var b [4]byte
+	c := [4]byte{'g', 'o', 'p', 'h'}
+	for j := range c {
+		b[j] = c[j]
+	}
+	check("array", string(b[:]), "goph")
+
+	// A destination that depends on the index is not one slice.
+	var m [3][3]int
+	d := [3]int{1, 2, 3}
+	for j := range d {
+		m[j][j] = d[j]
+	}
+	check("diagonal", m, [3][3]int{{1, 0, 0}, {0, 2, 0}, {0, 0, 3}})
+
+	// Short destination copies what fits and then panics.
+	dst = make([]int, 2)
+	func() {
+		defer func() {
+			if recover() == nil {
+				panic("short destination did not panic")
+			}
+		}()
+		copyInts(dst, []int{7, 8, 9})
+	}()
+	check("short", dst, []int{7, 8})
+}
@@ -0,0 +1,42 @@
+// run
+
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// The linker prunes methods that are not directly called or
+// assigned to interfaces. When reflect.Type.Method and MethodByName
+// are only called with constant arguments, it keeps exactly the
+// methods they can reach. Test that those are kept.
+
+package main
+
+import "reflect"
+
+var called string
+
+type M int
+
+func (m M) A() { called += "A" }
+func (m M) B() { called += "B" }
+func (m M) C() { called += "C" }
+
+type N struct{}
+
+func (N) B() { called += "NB" }
+func (N) D() { called += "ND" }
+
+func main() {
+	m, ok := reflect.TypeOf(M(0)).MethodByName("C")
+	if !ok {
+		panic("MethodByName(\"C\") failed")
+	}
+	m.Func.Interface().(func(M))(0)
+
+	reflect.TypeOf(M(0)).Method(1).Func.Interface().(func(M))(0)
+	reflect.TypeOf(N{}).Method(1).Func.Interface().(func(N))(N{})
+
+	if called != "CBND" {
+		panic("called " + called + ", want CBND")
+	}
+}
@@ -475,8 +475,16 @@ func (t *test) run() {
 	case "cmpout":
 		action = "run" // the run case already looks for <dir>/<test>.out files
 		fallthrough
-	case "compile", "compiledir", "build", "run", "runoutput", "rundir":
+	case "compile", "compiledir", "build", "runoutput", "rundir":
 		t.action = action
+	case "run":
+		t.action = action
+		// Leading flags, such as -gcflags=-d=checkptr,
+		// are for the go command.
+		for len(args) > 0 && strings.HasPrefix(args[0], "-") {
+			flags = append(flags, args[0])
+			args = args[1:]
+		}
 	case "errorcheck", "errorcheckdir", "errorcheckoutput":
 		t.action = action
 		wantError = true
@@ -665,6 +673,7 @@ func (t *test) run() {
 		if *linkshared {
 			cmd = append(cmd, "-linkshared")
 		}
+		cmd = append(cmd, flags...)
 		cmd = append(cmd, t.goFileName())
 		out, err := runcmd(append(cmd, args...)...)
 		if err != nil {
@@ -0,0 +1,36 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package a
+
+// *File is written to the export data before File, whose methods
+// refer to it again.
+type File struct {
+	name string
+	set  *FileSet
+}
+
+type FileSet struct {
+	files []*File
+}
+
+func (f *File) Name() string      { return f.name }
+func (f *File) Set() *FileSet     { return f.set }
+func (s *FileSet) Files() []*File { return s.files }
+
+func (s *FileSet) Add(name string) *File {
+	f := &File{name, s}
+	s.files = append(s.files, f)
+	return f
+}
+
+func NewFile(name string) *File {
+	return new(FileSet).Add(name)
+}
+
+// Inc and Dec have the same type, func(int) int, and can be inlined.
+func Inc(x int) int { return x + 1 }
+func Dec(y int) int { return y - 1 }
+
+func Apply(f func(int) int, x int) int { return f(x) }
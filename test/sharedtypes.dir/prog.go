@@ -0,0 +1,42 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"./a"
+	"unicode"
+)
+
+// double and twice have the same type, func(int) int, but no
+// parameter names.
+func double(int) int  { return 0 }
+func twice(x int) int { return 2 * x }
+
+func main() {
+	f := a.NewFile("x.go")
+	if f.Name() != "x.go" || len(f.Set().Files()) != 1 || f.Set().Files()[0] != f {
+		panic("bad file set")
+	}
+
+	if a.Inc(1) != 2 || a.Dec(1) != 0 || a.Apply(a.Inc, 2) != 3 || a.Apply(twice, 2) != 4 {
+		panic("bad func(int) int")
+	}
+	fs := []func(int) int{a.Inc, a.Dec, double, twice}
+	if fs[0](5)+fs[1](5)+fs[2](5)+fs[3](5) != 20 {
+		panic("bad func values")
+	}
+
+	if !unicode.IsUpper('A') || unicode.IsLower('A') || !unicode.IsLower('a') {
+		panic("bad unicode")
+	}
+
+	// [8]int64 is also the type of the keys in the buckets of m.
+	m := map[int64]bool{1: true}
+	var x, y [8]int64
+	x[7] = 1
+	if x == y || !m[x[7]] {
+		panic("bad [8]int64")
+	}
+}
@@ -0,0 +1,32 @@
+// errorcheck
+
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Verify that invalid constant shift counts are reported
+// once, at the shift, even when the count is on a later line.
+// Does not compile.
+
+package p
+
+var x int
+
+const g = 2
+
+var (
+	a = 1 << // ERROR "invalid negative shift count: -1"
+		-1
+	b = x << -1      // ERROR "invalid negative shift count: -1"
+	c = x >> (g - 3) // ERROR "invalid negative shift count: -1"
+	d = 1 >> -3      // ERROR "invalid negative shift count: -3"
+	e = 1 << 5000    // ERROR "shift count too large: 5000"
+	f = 1 <<         // ERROR "shift count too large: 5000"
+		(4000 + 1000)
+	h = x << 5000
+)
+
+func F() {
+	println(1<< // ERROR "invalid negative shift count: -1"
+		-1, x)
+}
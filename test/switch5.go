@@ -0,0 +1,87 @@
+// errorcheck
+
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Verify that the compiler complains about duplicate cases.
+
+package main
+
+const (
+	one      = 1
+	oneI int = 1
+)
+
+type S string
+
+func f0(x int) {
+	switch x {
+	case 0:
+	case 0: // ERROR "duplicate case 0 in switch|previous case at LINE-1"
+	}
+
+	switch x {
+	case 1, one: // ERROR "duplicate case one in switch"
+	case oneI: // ERROR "duplicate case oneI in switch"
+	case 2:
+	}
+}
+
+func f1(x float32) {
+	switch x {
+	case 5:
+	case 5: // ERROR "duplicate case 5 in switch"
+	case 5.0: // ERROR "duplicate case 5 in switch"
+	}
+
+	switch x {
+	case 0.5:
+	case 1.0 / 2: // ERROR "duplicate case"
+	case 1e-100:
+	case 0: // ERROR "duplicate case 0 in switch"
+	}
+}
+
+func f2(c complex128) {
+	switch c {
+	case 1 + 2i:
+	case 2i + 1: // ERROR "duplicate case"
+	case 1:
+	case 1 + 0i: // ERROR "duplicate case"
+	}
+}
+
+func f3(s string, r rune) {
+	switch s {
+	case "abc":
+	case "a" + "bc": // ERROR "duplicate case"
+	case "abc" + "": // ERROR "duplicate case"
+	}
+
+	switch r {
+	case 'a':
+	case 97: // ERROR "duplicate case 97 in switch"
+	}
+}
+
+func f4(e interface{}, ss S) {
+	switch e {
+	case 1:
+	case 1.0:
+	case int(1): // ERROR "duplicate case int\(1\) in switch"
+	case "x", S("x"):
+	case S("x"): // ERROR "duplicate case"
+	case ss, ss:
+	}
+}
+
+func f5(b bool, x int) {
+	// Duplicate boolean cases are allowed.
+	switch b {
+	case true, true:
+	}
+	switch {
+	case x > 0, x > 0:
+	}
+}
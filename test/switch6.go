@@ -0,0 +1,59 @@
+// errorcheck -0 -d=exhaustive
+
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Test -d=exhaustive warnings about switches that
+// do not handle every constant of the switched type.
+
+package p
+
+import "time"
+
+type Color int
+
+const (
+	Red Color = iota
+	Green
+	Blue
+	Default = Red
+)
+
+type Name string
+
+const (
+	Alice Name = "alice"
+	Bob   Name = "bob"
+)
+
+func f(c Color, n Name, m time.Month, x int) {
+	switch c { // ERROR "switch on Color without default does not handle Green, Blue$"
+	case Red:
+	}
+
+	switch c {
+	case Default, Green, Blue:
+	}
+
+	switch c {
+	case Red:
+	default:
+	}
+
+	switch c {
+	case Color(x):
+	}
+
+	switch n { // ERROR "switch on Name without default does not handle Bob$"
+	case "alice":
+	}
+
+	switch m { // ERROR "does not handle time.February, time.March, .*, time.December$"
+	case time.January:
+	}
+
+	switch x {
+	case 1:
+	}
+}
@@ -0,0 +1,20 @@
+// +build amd64
+// errorcheck
+
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Test that functions exceeding the limits of the compiler
+// are reported, rather than compiled incorrectly.
+
+package p
+
+//go:noinline
+func g(x [3 << 30]byte) { // ERROR "function g is too large \(1 nodes / 0 byte frame\): arguments too large"
+}
+
+func f() { // ERROR "function f is too large \(9 nodes / [0-9]+ byte frame\): stack frame too large"
+	var a [3 << 30]byte
+	g(a)
+}
@@ -0,0 +1,50 @@
+// run
+
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Test that method tables share identical embedding wrappers,
+// so that the linker can drop the duplicates.
+
+package main
+
+import "reflect"
+
+type T struct{ x int }
+
+func (t *T) M() int { return t.x }
+
+type A struct{ T }
+type B struct{ T }
+type C struct {
+	y int
+	T
+}
+
+type I interface {
+	M() int
+}
+
+func wrapper(x interface{}) uintptr {
+	return reflect.TypeOf(x).Method(0).Func.Pointer()
+}
+
+func main() {
+	is := []I{&A{T{1}}, &B{T{2}}, &C{0, T{3}}}
+	n := 0
+	for _, i := range is {
+		n += i.M()
+	}
+	if n != 6 {
+		panic(n)
+	}
+
+	// (*A).M and (*B).M are identical; (*C).M adjusts the receiver.
+	if wrapper(&A{}) != wrapper(&B{}) {
+		panic("(*A).M and (*B).M not shared")
+	}
+	if wrapper(&A{}) == wrapper(&C{}) {
+		panic("(*A).M and (*C).M shared")
+	}
+}
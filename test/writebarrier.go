@@ -182,3 +182,25 @@ func f18(p *T18, x *[]int) {
 	p.s = p.s[8:9]   // ERROR "write barrier"
 	*x = (*x)[3:5]   // ERROR "write barrier"
 }
+
+// Pointer-free values are copied without write barriers,
+// however they are stored.
+
+type T19 struct {
+	a, b, c, d, e int
+}
+
+func f19(p *T19, q *[4]T19, s []T19, t []T19, x T19, i int) {
+	*p = x              // no barrier
+	q[i] = x            // no barrier
+	s[i] = x            // no barrier
+	*q = [4]T19{}       // no barrier
+	copy(s, t)          // no barrier
+	s = append(s, t...) // no barrier
+	_ = s
+}
+
+func f20(p *[]*int, q []*int) {
+	copy(*p, q)           // ERROR "write barrier"
+	*p = append(*p, q...) // ERROR "write barrier"
+}
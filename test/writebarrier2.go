@@ -0,0 +1,58 @@
+// errorcheck -0 -l -d=wb=2
+
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Test that -d=wb=2 accounts for every pointer store to
+// memory that may be in the heap.
+
+package p
+
+type T struct {
+	p *int
+	n int
+}
+
+var g int
+var gp *int
+var gf func()
+
+func h()
+
+func f(x **int, y *int, t *T) { // ERROR "f: first write barrier"
+	*x = y        // ERROR "write barrier for \*x" "write barrier$"
+	*x = nil      // ERROR "no write barrier for \*x: zero or constant value"
+	*x = &g       // ERROR "no write barrier for \*x: address of global"
+	gf = h        // ERROR "no write barrier for gf: global function"
+	t.n = 1       // no pointers, not reported
+	*t = T{}      // ERROR "no write barrier for \*t: zero or constant value"
+	gp = new(int) // ERROR "write barrier for gp" "write barrier$"
+
+	var z *int
+	z = y // stack store, not reported
+	_ = z
+}
+
+func f2(x **int) {
+	var y int
+	*x = nil // ERROR "no write barrier for \*x: zero or constant value"
+	_ = y
+}
+
+var sink interface{}
+
+// The copies of parameters moved to the heap are in Enter and Exit.
+func f3(x *int) (r *int) { // ERROR "write barrier for x" "no write barrier for r: implicit zeroing" "write barrier$" "f3: first write barrier"
+	sink = &x // ERROR "write barrier for sink" "write barrier$"
+	sink = &r // ERROR "write barrier for sink" "write barrier$"
+	r = x     // ERROR "write barrier for r" "write barrier$"
+	return
+}
+
+// Closure bodies are checked as functions of their own.
+func f4(s []*int, y *int) {
+	func() { // ERROR "f4.func1: first write barrier"
+		s[0] = y // ERROR "write barrier for s\[0\]" "write barrier$"
+	}()
+}